@@ -0,0 +1,256 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mqtt is a minimal publish-only client of the MQTT 3.1.1 protocol,
+// it supports QoS 0 and 1, QoS 2 is downgraded to 1
+package mqtt
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	defaultTimeout   = 5 * time.Second
+	defaultKeepAlive = 30 * time.Second
+
+	QoSAtMostOnce  byte = 0
+	QoSAtLeastOnce byte = 1
+)
+
+var ErrClosed = errors.New("mqtt connection is closed")
+
+var connAckErrors = map[byte]string{
+	1: "unacceptable protocol version",
+	2: "identifier rejected",
+	3: "server unavailable",
+	4: "bad user name or password",
+	5: "not authorized",
+}
+
+type Config struct {
+	Addr      string
+	ClientId  string
+	User      string
+	Password  string
+	Timeout   time.Duration
+	KeepAlive time.Duration
+	TLS       *tls.Config
+}
+
+type Client struct {
+	Cfg Config
+
+	conn     net.Conn
+	reader   *bufio.Reader
+	packetId uint16
+	inflight map[uint16]chan struct{}
+	done     chan struct{}
+
+	lock   sync.Mutex
+	err    error
+	closed bool
+}
+
+func (c *Client) connect() error {
+	conn, err := net.DialTimeout("tcp", c.Cfg.Addr, c.Cfg.Timeout)
+	if err != nil {
+		return err
+	}
+	if c.Cfg.TLS != nil {
+		conn = tls.Client(conn, c.Cfg.TLS)
+	}
+	c.conn = conn
+	c.reader = bufio.NewReader(conn)
+
+	conn.SetDeadline(time.Now().Add(c.Cfg.Timeout))
+	_, err = conn.Write(encodeConnect(c.Cfg.ClientId, c.Cfg.User, c.Cfg.Password,
+		uint16(c.Cfg.KeepAlive/time.Second)))
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	p, err := readPacket(c.reader)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	if p.Type() != packetConnAck || len(p.body) != 2 {
+		conn.Close()
+		return fmt.Errorf("unexpected mqtt packet type %x, want CONNACK", p.Type())
+	}
+	if code := p.body[1]; code != 0 {
+		conn.Close()
+		return fmt.Errorf("mqtt connection refused: %s", connAckErrors[code])
+	}
+	conn.SetDeadline(time.Time{})
+
+	go c.readLoop()
+	go c.keepAlive()
+	return nil
+}
+
+func (c *Client) readLoop() {
+	for {
+		p, err := readPacket(c.reader)
+		if err != nil {
+			c.setError(err)
+			return
+		}
+		switch p.Type() {
+		case packetPubAck:
+			if len(p.body) < 2 {
+				continue
+			}
+			id := binary.BigEndian.Uint16(p.body)
+			c.lock.Lock()
+			if ch, ok := c.inflight[id]; ok {
+				delete(c.inflight, id)
+				close(ch)
+			}
+			c.lock.Unlock()
+		case packetPingResp:
+		}
+	}
+}
+
+func (c *Client) keepAlive() {
+	ticker := time.NewTicker(c.Cfg.KeepAlive / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			if err := c.write(encode(packetPingReq, nil)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (c *Client) setError(err error) {
+	c.lock.Lock()
+	if c.err == nil {
+		c.err = err
+	}
+	c.lock.Unlock()
+}
+
+// Err returns the first error the connection caught, the client
+// should be discarded if it is not nil
+func (c *Client) Err() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.closed {
+		return ErrClosed
+	}
+	return c.err
+}
+
+func (c *Client) write(b []byte) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.writeLocked(b)
+}
+
+func (c *Client) writeLocked(b []byte) error {
+	if c.closed {
+		return ErrClosed
+	}
+	if c.err != nil {
+		return c.err
+	}
+	c.conn.SetWriteDeadline(time.Now().Add(c.Cfg.Timeout))
+	if _, err := c.conn.Write(b); err != nil {
+		c.err = err
+		return err
+	}
+	return nil
+}
+
+// Publish sends the payload to the topic, if qos is greater than 0,
+// it blocks until the broker acknowledges or timed out
+func (c *Client) Publish(topic string, payload []byte, qos byte) error {
+	if qos == QoSAtMostOnce {
+		return c.write(encodePublish(topic, payload, qos, 0))
+	}
+	qos = QoSAtLeastOnce
+
+	c.lock.Lock()
+	c.packetId++
+	if c.packetId == 0 {
+		c.packetId = 1
+	}
+	id := c.packetId
+	ack := make(chan struct{})
+	c.inflight[id] = ack
+	err := c.writeLocked(encodePublish(topic, payload, qos, id))
+	if err != nil {
+		delete(c.inflight, id)
+	}
+	c.lock.Unlock()
+	if err != nil {
+		return err
+	}
+
+	timer := time.NewTimer(c.Cfg.Timeout)
+	defer timer.Stop()
+	select {
+	case <-ack:
+		return nil
+	case <-timer.C:
+		c.lock.Lock()
+		delete(c.inflight, id)
+		c.lock.Unlock()
+		return fmt.Errorf("wait for mqtt PUBACK[%d] timed out(%s)", id, c.Cfg.Timeout)
+	}
+}
+
+func (c *Client) Close() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.closed {
+		return
+	}
+	c.writeLocked(encode(packetDisconnect, nil))
+	c.closed = true
+	close(c.done)
+	c.conn.Close()
+}
+
+func NewMQTTClient(cfg Config) (*Client, error) {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	if cfg.KeepAlive <= 0 {
+		cfg.KeepAlive = defaultKeepAlive
+	}
+	c := &Client{
+		Cfg:      cfg,
+		inflight: make(map[uint16]chan struct{}),
+		done:     make(chan struct{}),
+	}
+	if err := c.connect(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
@@ -0,0 +1,146 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mqtt
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// MQTT 3.1.1 control packet types
+const (
+	packetConnect    byte = 0x10
+	packetConnAck    byte = 0x20
+	packetPublish    byte = 0x30
+	packetPubAck     byte = 0x40
+	packetPingReq    byte = 0xC0
+	packetPingResp   byte = 0xD0
+	packetDisconnect byte = 0xE0
+)
+
+const (
+	protocolName  = "MQTT"
+	protocolLevel = 4
+
+	flagCleanSession = 0x02
+	flagPassword     = 0x40
+	flagUsername     = 0x80
+
+	maxRemainingLength = 268435455
+)
+
+var errMalformedLength = errors.New("malformed mqtt remaining length")
+
+type packet struct {
+	header byte
+	body   []byte
+}
+
+func (p *packet) Type() byte {
+	return p.header & 0xF0
+}
+
+func writeString(b *bytes.Buffer, s string) {
+	binary.Write(b, binary.BigEndian, uint16(len(s)))
+	b.WriteString(s)
+}
+
+func encode(header byte, body []byte) []byte {
+	b := bytes.NewBuffer(make([]byte, 0, len(body)+5))
+	b.WriteByte(header)
+	l := len(body)
+	for {
+		d := byte(l % 128)
+		l /= 128
+		if l > 0 {
+			d |= 0x80
+		}
+		b.WriteByte(d)
+		if l == 0 {
+			break
+		}
+	}
+	b.Write(body)
+	return b.Bytes()
+}
+
+func encodeConnect(clientId, user, password string, keepAlive uint16) []byte {
+	var (
+		b     bytes.Buffer
+		flags byte = flagCleanSession
+	)
+	if len(user) > 0 {
+		flags |= flagUsername
+	}
+	if len(password) > 0 {
+		flags |= flagPassword
+	}
+	writeString(&b, protocolName)
+	b.WriteByte(protocolLevel)
+	b.WriteByte(flags)
+	binary.Write(&b, binary.BigEndian, keepAlive)
+	writeString(&b, clientId)
+	if len(user) > 0 {
+		writeString(&b, user)
+	}
+	if len(password) > 0 {
+		writeString(&b, password)
+	}
+	return encode(packetConnect, b.Bytes())
+}
+
+func encodePublish(topic string, payload []byte, qos byte, packetId uint16) []byte {
+	var b bytes.Buffer
+	writeString(&b, topic)
+	if qos > 0 {
+		binary.Write(&b, binary.BigEndian, packetId)
+	}
+	b.Write(payload)
+	return encode(packetPublish|qos<<1, b.Bytes())
+}
+
+func readPacket(r *bufio.Reader) (*packet, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	var (
+		l          int
+		multiplier = 1
+	)
+	for {
+		d, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		l += int(d&0x7F) * multiplier
+		if l > maxRemainingLength {
+			return nil, errMalformedLength
+		}
+		if d&0x80 == 0 {
+			break
+		}
+		multiplier *= 128
+	}
+	body := make([]byte, l)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, err
+	}
+	return &packet{header: header, body: body}, nil
+}
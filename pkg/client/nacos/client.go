@@ -0,0 +1,249 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nacos
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"golang.org/x/net/context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DEFAULT_GROUP = "DEFAULT_GROUP"
+
+	defaultTimeout  = 5 * time.Second
+	defaultPageSize = 100
+
+	apiLogin     = "/nacos/v1/auth/login"
+	apiServices  = "/nacos/v1/ns/service/list"
+	apiInstances = "/nacos/v1/ns/instance/list"
+	apiInstance  = "/nacos/v1/ns/instance"
+)
+
+type Config struct {
+	// Addr is the nacos server address, e.g. http://127.0.0.1:8848
+	Addr      string
+	Namespace string
+	Group     string
+	User      string
+	Password  string
+	Timeout   time.Duration
+	TLS       *tls.Config
+}
+
+// Instance is the instance of nacos naming service
+type Instance struct {
+	InstanceId string            `json:"instanceId,omitempty"`
+	Ip         string            `json:"ip"`
+	Port       int               `json:"port"`
+	Weight     float64           `json:"weight"`
+	Healthy    bool              `json:"healthy"`
+	Enabled    bool              `json:"enabled"`
+	Ephemeral  bool              `json:"ephemeral"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+}
+
+// Addr returns the 'ip:port' of the instance
+func (i *Instance) Addr() string {
+	return i.Ip + ":" + strconv.Itoa(i.Port)
+}
+
+type servicesResponse struct {
+	Count int      `json:"count"`
+	Doms  []string `json:"doms"`
+}
+
+type instancesResponse struct {
+	Hosts []*Instance `json:"hosts"`
+}
+
+type loginResponse struct {
+	AccessToken string `json:"accessToken"`
+	TokenTtl    int64  `json:"tokenTtl"`
+}
+
+// Client is a client of the nacos open api v1
+type Client struct {
+	Cfg Config
+
+	client      *http.Client
+	token       string
+	tokenExpire time.Time
+	lock        sync.Mutex
+}
+
+// accessToken logins if the user is configured and the token expires
+func (c *Client) accessToken(ctx context.Context) (string, error) {
+	if len(c.Cfg.User) == 0 {
+		return "", nil
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if len(c.token) > 0 && time.Now().Before(c.tokenExpire) {
+		return c.token, nil
+	}
+	form := url.Values{"username": {c.Cfg.User}, "password": {c.Cfg.Password}}
+	body, err := c.do(ctx, http.MethodPost, apiLogin, form, "")
+	if err != nil {
+		return "", err
+	}
+	resp := &loginResponse{}
+	if err := json.Unmarshal(body, resp); err != nil {
+		return "", err
+	}
+	c.token = resp.AccessToken
+	// refresh the token before it expires
+	c.tokenExpire = time.Now().Add(time.Duration(resp.TokenTtl) * time.Second * 9 / 10)
+	return c.token, nil
+}
+
+func (c *Client) do(ctx context.Context, method, api string, params url.Values, token string) ([]byte, error) {
+	if len(token) > 0 {
+		params.Set("accessToken", token)
+	}
+	var (
+		req *http.Request
+		err error
+	)
+	if method == http.MethodPost {
+		req, err = http.NewRequest(method, c.Cfg.Addr+api, strings.NewReader(params.Encode()))
+		if req != nil {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+	} else {
+		req, err = http.NewRequest(method, c.Cfg.Addr+api+"?"+params.Encode(), nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("nacos %s %s failed, status: %d, %s", method, api, resp.StatusCode, body)
+	}
+	return body, nil
+}
+
+func (c *Client) call(ctx context.Context, method, api string, params url.Values) ([]byte, error) {
+	token, err := c.accessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	params.Set("namespaceId", c.Cfg.Namespace)
+	params.Set("groupName", c.Cfg.Group)
+	return c.do(ctx, method, api, params, token)
+}
+
+// ListServices returns the service names of the group
+func (c *Client) ListServices(ctx context.Context) (services []string, err error) {
+	for page := 1; ; page++ {
+		body, err := c.call(ctx, http.MethodGet, apiServices, url.Values{
+			"pageNo":   {strconv.Itoa(page)},
+			"pageSize": {strconv.Itoa(defaultPageSize)},
+		})
+		if err != nil {
+			return nil, err
+		}
+		resp := &servicesResponse{}
+		if err := json.Unmarshal(body, resp); err != nil {
+			return nil, err
+		}
+		services = append(services, resp.Doms...)
+		if len(resp.Doms) < defaultPageSize || len(services) >= resp.Count {
+			return services, nil
+		}
+	}
+}
+
+// ListInstances returns all the instances of the service including
+// the unhealthy ones
+func (c *Client) ListInstances(ctx context.Context, service string) ([]*Instance, error) {
+	body, err := c.call(ctx, http.MethodGet, apiInstances, url.Values{
+		"serviceName": {service},
+		"healthyOnly": {"false"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := &instancesResponse{}
+	if err := json.Unmarshal(body, resp); err != nil {
+		return nil, err
+	}
+	return resp.Hosts, nil
+}
+
+// RegisterInstance registers or updates the instance
+func (c *Client) RegisterInstance(ctx context.Context, service string, instance *Instance) error {
+	params := instanceParams(service, instance)
+	params.Set("weight", strconv.FormatFloat(instance.Weight, 'f', -1, 64))
+	params.Set("healthy", strconv.FormatBool(instance.Healthy))
+	params.Set("enabled", strconv.FormatBool(instance.Enabled))
+	if len(instance.Metadata) > 0 {
+		metadata, err := json.Marshal(instance.Metadata)
+		if err != nil {
+			return err
+		}
+		params.Set("metadata", string(metadata))
+	}
+	_, err := c.call(ctx, http.MethodPost, apiInstance, params)
+	return err
+}
+
+func (c *Client) DeregisterInstance(ctx context.Context, service string, instance *Instance) error {
+	_, err := c.call(ctx, http.MethodDelete, apiInstance, instanceParams(service, instance))
+	return err
+}
+
+func instanceParams(service string, instance *Instance) url.Values {
+	return url.Values{
+		"serviceName": {service},
+		"ip":          {instance.Ip},
+		"port":        {strconv.Itoa(instance.Port)},
+		"ephemeral":   {strconv.FormatBool(instance.Ephemeral)},
+	}
+}
+
+func NewClient(cfg Config) *Client {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	if len(cfg.Group) == 0 {
+		cfg.Group = DEFAULT_GROUP
+	}
+	cfg.Addr = strings.TrimSuffix(cfg.Addr, "/")
+	return &Client{
+		Cfg: cfg,
+		client: &http.Client{
+			Timeout:   cfg.Timeout,
+			Transport: &http.Transport{TLSClientConfig: cfg.TLS},
+		},
+	}
+}
@@ -0,0 +1,226 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package nats is a minimal publish-only client of the NATS text protocol
+package nats
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultTimeout = 5 * time.Second
+
+	opPing = "PING"
+	opPong = "PONG"
+	opErr  = "-ERR"
+	crlf   = "\r\n"
+)
+
+var ErrClosed = errors.New("nats connection is closed")
+
+type Config struct {
+	Addr     string
+	Name     string
+	User     string
+	Password string
+	Timeout  time.Duration
+	TLS      *tls.Config
+}
+
+type connectOptions struct {
+	Verbose  bool   `json:"verbose"`
+	Pedantic bool   `json:"pedantic"`
+	Name     string `json:"name,omitempty"`
+	User     string `json:"user,omitempty"`
+	Password string `json:"pass,omitempty"`
+	Lang     string `json:"lang"`
+}
+
+type Client struct {
+	Cfg Config
+
+	conn   net.Conn
+	writer *bufio.Writer
+	pongs  chan struct{}
+
+	lock   sync.Mutex
+	err    error
+	closed bool
+}
+
+func (c *Client) connect() error {
+	conn, err := net.DialTimeout("tcp", c.Cfg.Addr, c.Cfg.Timeout)
+	if err != nil {
+		return err
+	}
+	if c.Cfg.TLS != nil {
+		conn = tls.Client(conn, c.Cfg.TLS)
+	}
+	c.conn = conn
+	c.writer = bufio.NewWriter(conn)
+
+	reader := bufio.NewReader(conn)
+	conn.SetReadDeadline(time.Now().Add(c.Cfg.Timeout))
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	if !strings.HasPrefix(line, "INFO") {
+		conn.Close()
+		return fmt.Errorf("unexpected nats server greeting: %s", strings.TrimSpace(line))
+	}
+	conn.SetReadDeadline(time.Time{})
+
+	opts, _ := json.Marshal(connectOptions{
+		Name:     c.Cfg.Name,
+		User:     c.Cfg.User,
+		Password: c.Cfg.Password,
+		Lang:     "go",
+	})
+	c.writer.WriteString("CONNECT ")
+	c.writer.Write(opts)
+	c.writer.WriteString(crlf)
+	if err := c.writer.Flush(); err != nil {
+		conn.Close()
+		return err
+	}
+
+	go c.readLoop(reader)
+
+	if err := c.Flush(c.Cfg.Timeout); err != nil {
+		c.Close()
+		return err
+	}
+	return nil
+}
+
+func (c *Client) readLoop(reader *bufio.Reader) {
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			c.setError(err)
+			return
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case line == opPing:
+			c.write(opPong + crlf)
+		case line == opPong:
+			select {
+			case c.pongs <- struct{}{}:
+			default:
+			}
+		case strings.HasPrefix(line, opErr):
+			c.setError(fmt.Errorf("nats server error: %s", strings.TrimSpace(line[len(opErr):])))
+		}
+	}
+}
+
+func (c *Client) setError(err error) {
+	c.lock.Lock()
+	if c.err == nil {
+		c.err = err
+	}
+	c.lock.Unlock()
+}
+
+// Err returns the first error the connection caught, the client
+// should be discarded if it is not nil
+func (c *Client) Err() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.closed {
+		return ErrClosed
+	}
+	return c.err
+}
+
+func (c *Client) write(s string, payload ...[]byte) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.closed {
+		return ErrClosed
+	}
+	if c.err != nil {
+		return c.err
+	}
+	c.conn.SetWriteDeadline(time.Now().Add(c.Cfg.Timeout))
+	c.writer.WriteString(s)
+	for _, p := range payload {
+		c.writer.Write(p)
+		c.writer.WriteString(crlf)
+	}
+	if err := c.writer.Flush(); err != nil {
+		c.err = err
+		return err
+	}
+	return nil
+}
+
+// Publish sends the data to the subject, it does not wait for the
+// server acknowledgement, call Flush to make sure it is processed
+func (c *Client) Publish(subject string, data []byte) error {
+	return c.write(fmt.Sprintf("PUB %s %d%s", subject, len(data), crlf), data)
+}
+
+// Flush sends a PING and waits for the PONG, all the messages published
+// before are processed by the server if it returns nil
+func (c *Client) Flush(timeout time.Duration) error {
+	if err := c.write(opPing + crlf); err != nil {
+		return err
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-c.pongs:
+		return c.Err()
+	case <-timer.C:
+		return fmt.Errorf("wait for nats server pong timed out(%s)", timeout)
+	}
+}
+
+func (c *Client) Close() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.closed {
+		return
+	}
+	c.closed = true
+	c.conn.Close()
+}
+
+func NewNATSClient(cfg Config) (*Client, error) {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	c := &Client{
+		Cfg:   cfg,
+		pongs: make(chan struct{}, 1),
+	}
+	if err := c.connect(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
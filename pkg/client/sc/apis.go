@@ -28,21 +28,56 @@ import (
 	"golang.org/x/net/context"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 )
 
 const (
-	apiVersionURL   = "/version"
-	apiDumpURL      = "/v4/default/admin/dump"
-	apiClustersURL  = "/v4/default/admin/clusters"
-	apiHealthURL    = "/v4/default/registry/health"
-	apiSchemasURL   = "/v4/%s/registry/microservices/%s/schemas"
-	apiSchemaURL    = "/v4/%s/registry/microservices/%s/schemas/%s"
-	apiInstancesURL = "/v4/%s/registry/microservices/%s/instances"
-	apiInstanceURL  = "/v4/%s/registry/microservices/%s/instances/%s"
+	apiVersionURL     = "/version"
+	apiDumpURL        = "/v4/default/admin/dump"
+	apiClustersURL    = "/v4/default/admin/clusters"
+	apiHealthURL      = "/v4/default/registry/health"
+	apiSchemasURL     = "/v4/%s/registry/microservices/%s/schemas"
+	apiSchemaURL      = "/v4/%s/registry/microservices/%s/schemas/%s"
+	apiInstancesURL   = "/v4/%s/registry/microservices/%s/instances"
+	apiInstanceURL    = "/v4/%s/registry/microservices/%s/instances/%s"
+	apiPeerEventsURL  = "/v4/default/admin/peer/events"
+	apiSyncChangesURL = "/v4/default/admin/syncer/changes?epoch=%s&since=%d"
+	apiMemberSelfURL  = "/v4/default/admin/cluster/self"
+	apiMembersURL     = "/v4/default/admin/cluster/members"
+	apiEventsURL      = "/v4/%s/registry/events?since=%d"
+	apiDomainsURL     = "/v4/default/admin/domains"
+	apiDomainURL      = "/v4/default/admin/domains/%s"
+	apiExportsURL     = "/v4/default/admin/dump/jobs"
+	apiExportURL      = "/v4/default/admin/dump/jobs/%s"
+	apiDownloadURL    = "/v4/default/admin/dump/jobs/%s/download"
+	apiImportURL      = "/v4/default/admin/dump/import"
+	apiExistenceURL   = "/v4/%s/registry/existence"
+	apiTagsURL        = "/v4/%s/registry/microservices/%s/tags"
+	apiTagURL         = "/v4/%s/registry/microservices/%s/tags/%s"
+	apiRulesURL       = "/v4/%s/registry/microservices/%s/rules"
+	apiRuleURL        = "/v4/%s/registry/microservices/%s/rules/%s"
+	apiFeedURL        = "/v4/default/admin/feed?prefix=%s&rev=%d"
+	apiServicesURL    = "/v4/%s/registry/microservices"
+	apiServiceURL     = "/v4/%s/registry/microservices/%s?force=%t"
+	apiHeartbeatURL   = "/v4/%s/registry/microservices/%s/instances/%s/heartbeat"
+	apiFindURL        = "/v4/%s/registry/instances"
+	apiRegisterURL    = "/v4/%s/registry/registration"
+	apiLockURL        = "/v4/%s/registry/locks/%s"
 
 	QueryGlobal = "global"
 )
 
+// DryRunResponse is the response of the API called with '?dryRun=true',
+// the changes are the keys would be written or deleted
+type DryRunResponse struct {
+	DryRun  bool `json:"dryRun"`
+	Changes []struct {
+		Action string `json:"action"`
+		Key    string `json:"key"`
+	} `json:"changes"`
+	Result json.RawMessage `json:"result,omitempty"`
+}
+
 func (c *SCClient) toError(body []byte) *scerr.Error {
 	message := new(scerr.Error)
 	err := json.Unmarshal(body, message)
@@ -88,10 +123,20 @@ func (c *SCClient) GetScVersion(ctx context.Context) (*version.VersionSet, *scer
 }
 
 func (c *SCClient) GetScCache(ctx context.Context) (*model.Cache, *scerr.Error) {
+	return c.dump(ctx, apiDumpURL)
+}
+
+// GetBackendCache returns the same resources as GetScCache, but they are
+// read from the backend by service center
+func (c *SCClient) GetBackendCache(ctx context.Context) (*model.Cache, *scerr.Error) {
+	return c.dump(ctx, apiDumpURL+"?source="+model.DUMP_SOURCE_BACKEND)
+}
+
+func (c *SCClient) dump(ctx context.Context, api string) (*model.Cache, *scerr.Error) {
 	headers := c.CommonHeaders(ctx)
 	// only default domain has admin permission
 	headers.Set("X-Domain-Name", "default")
-	resp, err := c.RestDoWithContext(ctx, http.MethodGet, apiDumpURL, headers, nil)
+	resp, err := c.RestDoWithContext(ctx, http.MethodGet, api, headers, nil)
 	if err != nil {
 		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
 	}
@@ -145,6 +190,73 @@ func (c *SCClient) GetSchemasByServiceId(ctx context.Context, domainProject, ser
 	return schemas.Schemas, nil
 }
 
+// GetServiceIdByKey returns the id of the microservice with the app,
+// name, version and environment
+func (c *SCClient) GetServiceIdByKey(ctx context.Context, domainProject string, key *pb.MicroServiceKey) (string, *scerr.Error) {
+	domain, project := core.FromDomainProject(domainProject)
+	headers := c.CommonHeaders(ctx)
+	headers.Set("X-Domain-Name", domain)
+	query := url.Values{}
+	query.Set("type", pb.EXISTENCE_MS)
+	query.Set("env", key.Environment)
+	query.Set("appId", key.AppId)
+	query.Set("serviceName", key.ServiceName)
+	query.Set("version", key.Version)
+	resp, err := c.RestDoWithContext(ctx, http.MethodGet,
+		fmt.Sprintf(apiExistenceURL, project)+"?"+query.Encode(),
+		headers, nil)
+	if err != nil {
+		return "", scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", c.toError(body)
+	}
+
+	existence := &pb.GetExistenceResponse{}
+	err = json.Unmarshal(body, existence)
+	if err != nil {
+		return "", scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+
+	return existence.ServiceId, nil
+}
+
+// PutSchemas replaces all the schemas of the microservice, the schemas
+// not in the list are deleted
+func (c *SCClient) PutSchemas(ctx context.Context, domainProject, serviceId string, schemas []*pb.Schema) *scerr.Error {
+	reqBody, err := json.Marshal(&pb.ModifySchemasRequest{Schemas: schemas})
+	if err != nil {
+		return scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+
+	domain, project := core.FromDomainProject(domainProject)
+	headers := c.CommonHeaders(ctx)
+	headers.Set("X-Domain-Name", domain)
+	resp, err := c.RestDoWithContext(ctx, http.MethodPost,
+		fmt.Sprintf(apiSchemasURL, project, serviceId), headers, reqBody)
+	if err != nil {
+		return scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return c.toError(body)
+	}
+	return nil
+}
+
 func (c *SCClient) GetSchemaBySchemaId(ctx context.Context, domainProject, serviceId, schemaId string) (*pb.Schema, *scerr.Error) {
 	domain, project := core.FromDomainProject(domainProject)
 	headers := c.CommonHeaders(ctx)
@@ -208,24 +320,36 @@ func (c *SCClient) GetClusters(ctx context.Context) (registry.Clusters, *scerr.E
 }
 
 func (c *SCClient) HealthCheck(ctx context.Context) *scerr.Error {
+	_, scErr := c.ClusterHealth(ctx)
+	return scErr
+}
+
+// ClusterHealth returns the instances of the service center cluster
+func (c *SCClient) ClusterHealth(ctx context.Context) ([]*pb.MicroServiceInstance, *scerr.Error) {
 	headers := c.CommonHeaders(ctx)
 	// only default domain has admin permission
 	headers.Set("X-Domain-Name", "default")
 	resp, err := c.RestDoWithContext(ctx, http.MethodGet, apiHealthURL, headers, nil)
 	if err != nil {
-		return scerr.NewError(scerr.ErrUnavailableBackend, err.Error())
+		return nil, scerr.NewError(scerr.ErrUnavailableBackend, err.Error())
 	}
 	defer resp.Body.Close()
 
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return scerr.NewError(scerr.ErrInternal, err.Error())
+		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return c.toError(body)
+		return nil, c.toError(body)
 	}
-	return nil
+
+	instances := &pb.GetInstancesResponse{}
+	err = json.Unmarshal(body, instances)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+	return instances.Instances, nil
 }
 
 func (c *SCClient) GetInstancesByServiceId(ctx context.Context, domainProject, providerId, consumerId string) ([]*pb.MicroServiceInstance, *scerr.Error) {
@@ -289,3 +413,826 @@ func (c *SCClient) GetInstanceByInstanceId(ctx context.Context, domainProject, p
 
 	return instanceResp.Instance, nil
 }
+
+func (c *SCClient) PostPeerEvents(ctx context.Context, events []*pb.PeerEvent) *scerr.Error {
+	reqBody, err := json.Marshal(&pb.PeerEventsRequest{Events: events})
+	if err != nil {
+		return scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+
+	headers := c.CommonHeaders(ctx)
+	// only default domain has admin permission
+	headers.Set("X-Domain-Name", "default")
+	resp, err := c.RestDoWithContext(ctx, http.MethodPost, apiPeerEventsURL, headers, reqBody)
+	if err != nil {
+		return scerr.NewError(scerr.ErrUnavailableBackend, err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return c.toError(body)
+	}
+	return nil
+}
+
+func (c *SCClient) GetSyncChanges(ctx context.Context, epoch string, since int64) (*pb.SyncChangesResponse, *scerr.Error) {
+	headers := c.CommonHeaders(ctx)
+	// only default domain has admin permission
+	headers.Set("X-Domain-Name", "default")
+	resp, err := c.RestDoWithContext(ctx, http.MethodGet,
+		fmt.Sprintf(apiSyncChangesURL, url.QueryEscape(epoch), since), headers, nil)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrUnavailableBackend, err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.toError(body)
+	}
+
+	changes := &pb.SyncChangesResponse{}
+	err = json.Unmarshal(body, changes)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+	return changes, nil
+}
+
+func (c *SCClient) GetMemberStatus(ctx context.Context) (*model.MemberStatus, *scerr.Error) {
+	headers := c.CommonHeaders(ctx)
+	// only default domain has admin permission
+	headers.Set("X-Domain-Name", "default")
+	resp, err := c.RestDoWithContext(ctx, http.MethodGet, apiMemberSelfURL, headers, nil)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrUnavailableBackend, err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.toError(body)
+	}
+
+	member := &model.MemberResponse{}
+	err = json.Unmarshal(body, member)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+	return member.Member, nil
+}
+
+// GetWatchEvents returns the instance events of the domain project after
+// the since revision
+func (c *SCClient) GetWatchEvents(ctx context.Context, domainProject string, since int64) (*pb.GetWatchEventsResponse, *scerr.Error) {
+	domain, project := core.FromDomainProject(domainProject)
+	headers := c.CommonHeaders(ctx)
+	headers.Set("X-Domain-Name", domain)
+	resp, err := c.RestDoWithContext(ctx, http.MethodGet, fmt.Sprintf(apiEventsURL, project, since), headers, nil)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrUnavailableBackend, err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.toError(body)
+	}
+
+	events := &pb.GetWatchEventsResponse{}
+	err = json.Unmarshal(body, events)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+	return events, nil
+}
+
+// GetMembers returns the status of all the service center instances
+func (c *SCClient) GetMembers(ctx context.Context) (*model.MembersResponse, *scerr.Error) {
+	headers := c.CommonHeaders(ctx)
+	// only default domain has admin permission
+	headers.Set("X-Domain-Name", "default")
+	resp, err := c.RestDoWithContext(ctx, http.MethodGet, apiMembersURL, headers, nil)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrUnavailableBackend, err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.toError(body)
+	}
+
+	members := &model.MembersResponse{}
+	err = json.Unmarshal(body, members)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+	return members, nil
+}
+
+func (c *SCClient) ListDomains(ctx context.Context) ([]*model.Domain, *scerr.Error) {
+	headers := c.CommonHeaders(ctx)
+	// only default domain has admin permission
+	headers.Set("X-Domain-Name", "default")
+	resp, err := c.RestDoWithContext(ctx, http.MethodGet, apiDomainsURL, headers, nil)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrUnavailableBackend, err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.toError(body)
+	}
+
+	domains := &model.DomainsResponse{}
+	err = json.Unmarshal(body, domains)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+	return domains.Domains, nil
+}
+
+func (c *SCClient) GetDomain(ctx context.Context, name string) (*model.Domain, *scerr.Error) {
+	headers := c.CommonHeaders(ctx)
+	// only default domain has admin permission
+	headers.Set("X-Domain-Name", "default")
+	resp, err := c.RestDoWithContext(ctx, http.MethodGet, fmt.Sprintf(apiDomainURL, name), headers, nil)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrUnavailableBackend, err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.toError(body)
+	}
+
+	domain := &model.DomainResponse{}
+	err = json.Unmarshal(body, domain)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+	return domain.Domain, nil
+}
+
+// UpdateDomainQuotas replaces the quotas of the domain, the zero quota
+// means only the global quota is checked
+func (c *SCClient) UpdateDomainQuotas(ctx context.Context, name string, quotas map[string]int64) (*model.Domain, *scerr.Error) {
+	reqBody, err := json.Marshal(&model.DomainRequest{Quotas: quotas})
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+
+	headers := c.CommonHeaders(ctx)
+	// only default domain has admin permission
+	headers.Set("X-Domain-Name", "default")
+	resp, err := c.RestDoWithContext(ctx, http.MethodPut, fmt.Sprintf(apiDomainURL, name), headers, reqBody)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrUnavailableBackend, err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.toError(body)
+	}
+
+	domain := &model.DomainResponse{}
+	err = json.Unmarshal(body, domain)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+	return domain.Domain, nil
+}
+
+func (c *SCClient) GetTags(ctx context.Context, domainProject, serviceId string) (map[string]string, *scerr.Error) {
+	domain, project := core.FromDomainProject(domainProject)
+	headers := c.CommonHeaders(ctx)
+	headers.Set("X-Domain-Name", domain)
+	resp, err := c.RestDoWithContext(ctx, http.MethodGet,
+		fmt.Sprintf(apiTagsURL, project, serviceId), headers, nil)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.toError(body)
+	}
+
+	tags := &pb.GetServiceTagsResponse{}
+	err = json.Unmarshal(body, tags)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+	return tags.Tags, nil
+}
+
+// AddTags adds the tags to the microservice, the existing tags with the
+// same keys are overwritten
+func (c *SCClient) AddTags(ctx context.Context, domainProject, serviceId string, tags map[string]string) *scerr.Error {
+	reqBody, err := json.Marshal(map[string]map[string]string{"tags": tags})
+	if err != nil {
+		return scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+
+	domain, project := core.FromDomainProject(domainProject)
+	headers := c.CommonHeaders(ctx)
+	headers.Set("X-Domain-Name", domain)
+	resp, err := c.RestDoWithContext(ctx, http.MethodPost,
+		fmt.Sprintf(apiTagsURL, project, serviceId), headers, reqBody)
+	if err != nil {
+		return scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return c.toError(body)
+	}
+	return nil
+}
+
+func (c *SCClient) DeleteTags(ctx context.Context, domainProject, serviceId string, keys []string) *scerr.Error {
+	domain, project := core.FromDomainProject(domainProject)
+	headers := c.CommonHeaders(ctx)
+	headers.Set("X-Domain-Name", domain)
+	resp, err := c.RestDoWithContext(ctx, http.MethodDelete,
+		fmt.Sprintf(apiTagURL, project, serviceId, util.StringJoin(keys, ",")), headers, nil)
+	if err != nil {
+		return scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return c.toError(body)
+	}
+	return nil
+}
+
+func (c *SCClient) GetRules(ctx context.Context, domainProject, serviceId string) ([]*pb.ServiceRule, *scerr.Error) {
+	domain, project := core.FromDomainProject(domainProject)
+	headers := c.CommonHeaders(ctx)
+	headers.Set("X-Domain-Name", domain)
+	resp, err := c.RestDoWithContext(ctx, http.MethodGet,
+		fmt.Sprintf(apiRulesURL, project, serviceId), headers, nil)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.toError(body)
+	}
+
+	rules := &pb.GetServiceRulesResponse{}
+	err = json.Unmarshal(body, rules)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+	return rules.Rules, nil
+}
+
+// AddRules adds the rules to the microservice and returns the ids of the
+// rules added, the existing rules are skipped
+func (c *SCClient) AddRules(ctx context.Context, domainProject, serviceId string, rules []*pb.AddOrUpdateServiceRule) ([]string, *scerr.Error) {
+	body, scErr := c.addRules(ctx, domainProject, serviceId, rules, "")
+	if scErr != nil {
+		return nil, scErr
+	}
+
+	added := &pb.AddServiceRulesResponse{}
+	err := json.Unmarshal(body, added)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+	return added.RuleIds, nil
+}
+
+// DryRunAddRules checks the rules by adding them in dry run, nothing is
+// changed in service center
+func (c *SCClient) DryRunAddRules(ctx context.Context, domainProject, serviceId string, rules []*pb.AddOrUpdateServiceRule) (*DryRunResponse, *scerr.Error) {
+	body, scErr := c.addRules(ctx, domainProject, serviceId, rules, "?dryRun=true")
+	if scErr != nil {
+		return nil, scErr
+	}
+
+	dryRun := &DryRunResponse{}
+	err := json.Unmarshal(body, dryRun)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+	return dryRun, nil
+}
+
+func (c *SCClient) addRules(ctx context.Context, domainProject, serviceId string, rules []*pb.AddOrUpdateServiceRule, query string) ([]byte, *scerr.Error) {
+	reqBody, err := json.Marshal(&pb.AddServiceRulesRequest{Rules: rules})
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+
+	domain, project := core.FromDomainProject(domainProject)
+	headers := c.CommonHeaders(ctx)
+	headers.Set("X-Domain-Name", domain)
+	resp, err := c.RestDoWithContext(ctx, http.MethodPost,
+		fmt.Sprintf(apiRulesURL, project, serviceId)+query, headers, reqBody)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.toError(body)
+	}
+	return body, nil
+}
+
+func (c *SCClient) DeleteRules(ctx context.Context, domainProject, serviceId string, ruleIds []string) *scerr.Error {
+	domain, project := core.FromDomainProject(domainProject)
+	headers := c.CommonHeaders(ctx)
+	headers.Set("X-Domain-Name", domain)
+	resp, err := c.RestDoWithContext(ctx, http.MethodDelete,
+		fmt.Sprintf(apiRuleURL, project, serviceId, util.StringJoin(ruleIds, ",")), headers, nil)
+	if err != nil {
+		return scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return c.toError(body)
+	}
+	return nil
+}
+
+func (c *SCClient) CreateService(ctx context.Context, domainProject string, service *pb.MicroService) (string, *scerr.Error) {
+	reqBody, err := json.Marshal(&pb.CreateServiceRequest{Service: service})
+	if err != nil {
+		return "", scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+
+	domain, project := core.FromDomainProject(domainProject)
+	headers := c.CommonHeaders(ctx)
+	headers.Set("X-Domain-Name", domain)
+	resp, err := c.RestDoWithContext(ctx, http.MethodPost,
+		fmt.Sprintf(apiServicesURL, project), headers, reqBody)
+	if err != nil {
+		return "", scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", c.toError(body)
+	}
+
+	serviceResp := &pb.CreateServiceResponse{}
+	err = json.Unmarshal(body, serviceResp)
+	if err != nil {
+		return "", scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+	return serviceResp.ServiceId, nil
+}
+
+// DeleteService deletes the microservice, the instances are deleted
+// together if force is true
+func (c *SCClient) DeleteService(ctx context.Context, domainProject, serviceId string, force bool) *scerr.Error {
+	domain, project := core.FromDomainProject(domainProject)
+	headers := c.CommonHeaders(ctx)
+	headers.Set("X-Domain-Name", domain)
+	resp, err := c.RestDoWithContext(ctx, http.MethodDelete,
+		fmt.Sprintf(apiServiceURL, project, serviceId, force), headers, nil)
+	if err != nil {
+		return scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return c.toError(body)
+	}
+	return nil
+}
+
+func (c *SCClient) RegisterInstance(ctx context.Context, domainProject, serviceId string, instance *pb.MicroServiceInstance) (string, *scerr.Error) {
+	reqBody, err := json.Marshal(&pb.RegisterInstanceRequest{Instance: instance})
+	if err != nil {
+		return "", scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+
+	domain, project := core.FromDomainProject(domainProject)
+	headers := c.CommonHeaders(ctx)
+	headers.Set("X-Domain-Name", domain)
+	resp, err := c.RestDoWithContext(ctx, http.MethodPost,
+		fmt.Sprintf(apiInstancesURL, project, serviceId), headers, reqBody)
+	if err != nil {
+		return "", scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", c.toError(body)
+	}
+
+	instanceResp := &pb.RegisterInstanceResponse{}
+	err = json.Unmarshal(body, instanceResp)
+	if err != nil {
+		return "", scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+	return instanceResp.InstanceId, nil
+}
+
+// RegisterServiceAndInstance creates the service if it is absent and
+// registers its instance in one request, returns the service id and the
+// instance id
+func (c *SCClient) RegisterServiceAndInstance(ctx context.Context, domainProject string,
+	service *pb.MicroService, instance *pb.MicroServiceInstance) (string, string, *scerr.Error) {
+	reqBody, err := json.Marshal(&pb.RegisterServiceAndInstanceRequest{Service: service, Instance: instance})
+	if err != nil {
+		return "", "", scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+
+	domain, project := core.FromDomainProject(domainProject)
+	headers := c.CommonHeaders(ctx)
+	headers.Set("X-Domain-Name", domain)
+	resp, err := c.RestDoWithContext(ctx, http.MethodPost,
+		fmt.Sprintf(apiRegisterURL, project), headers, reqBody)
+	if err != nil {
+		return "", "", scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", "", scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", "", c.toError(body)
+	}
+
+	registrationResp := &pb.RegisterServiceAndInstanceResponse{}
+	err = json.Unmarshal(body, registrationResp)
+	if err != nil {
+		return "", "", scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+	return registrationResp.ServiceId, registrationResp.InstanceId, nil
+}
+
+// AcquireLock takes the named lock for the holder, the lock is released
+// if it is not renewed in the ttl seconds, keep the session of the
+// returned lock to renew and release it
+func (c *SCClient) AcquireLock(ctx context.Context, domainProject, name, holder string, ttl int64) (*pb.Lock, *scerr.Error) {
+	reqBody, err := json.Marshal(&pb.AcquireLockRequest{Holder: holder, TTL: ttl})
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+	return c.doLock(ctx, http.MethodPost, domainProject, name, "", reqBody)
+}
+
+func (c *SCClient) RenewLock(ctx context.Context, domainProject, name, session string) (*pb.Lock, *scerr.Error) {
+	reqBody, err := json.Marshal(&pb.RenewLockRequest{Session: session})
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+	return c.doLock(ctx, http.MethodPut, domainProject, name, "", reqBody)
+}
+
+func (c *SCClient) ReleaseLock(ctx context.Context, domainProject, name, session string) *scerr.Error {
+	_, scErr := c.doLock(ctx, http.MethodDelete, domainProject, name, "?session="+url.QueryEscape(session), nil)
+	return scErr
+}
+
+// GetLock returns the holder of the lock, the holder is empty if the lock
+// is free
+func (c *SCClient) GetLock(ctx context.Context, domainProject, name string) (*pb.Lock, *scerr.Error) {
+	return c.doLock(ctx, http.MethodGet, domainProject, name, "", nil)
+}
+
+func (c *SCClient) doLock(ctx context.Context, method, domainProject, name, query string,
+	reqBody []byte) (*pb.Lock, *scerr.Error) {
+	domain, project := core.FromDomainProject(domainProject)
+	headers := c.CommonHeaders(ctx)
+	headers.Set("X-Domain-Name", domain)
+	resp, err := c.RestDoWithContext(ctx, method,
+		fmt.Sprintf(apiLockURL, project, url.PathEscape(name))+query, headers, reqBody)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.toError(body)
+	}
+
+	if len(body) == 0 {
+		return nil, nil
+	}
+	lock := &pb.Lock{}
+	err = json.Unmarshal(body, lock)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+	return lock, nil
+}
+
+func (c *SCClient) Heartbeat(ctx context.Context, domainProject, serviceId, instanceId string) *scerr.Error {
+	domain, project := core.FromDomainProject(domainProject)
+	headers := c.CommonHeaders(ctx)
+	headers.Set("X-Domain-Name", domain)
+	resp, err := c.RestDoWithContext(ctx, http.MethodPut,
+		fmt.Sprintf(apiHeartbeatURL, project, serviceId, instanceId), headers, nil)
+	if err != nil {
+		return scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return c.toError(body)
+	}
+	return nil
+}
+
+// FindInstances returns the instances of the provider matched the app,
+// name and version rule, the consumer is the service id of the caller
+func (c *SCClient) FindInstances(ctx context.Context, domainProject, consumerId string, provider *pb.MicroServiceKey) ([]*pb.MicroServiceInstance, *scerr.Error) {
+	domain, project := core.FromDomainProject(domainProject)
+	headers := c.CommonHeaders(ctx)
+	headers.Set("X-Domain-Name", domain)
+	headers.Set("X-ConsumerId", consumerId)
+	query := url.Values{}
+	query.Set("appId", provider.AppId)
+	query.Set("serviceName", provider.ServiceName)
+	query.Set("version", provider.Version)
+	resp, err := c.RestDoWithContext(ctx, http.MethodGet,
+		fmt.Sprintf(apiFindURL, project)+"?"+query.Encode(),
+		headers, nil)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.toError(body)
+	}
+
+	instancesResp := &pb.FindInstancesResponse{}
+	err = json.Unmarshal(body, instancesResp)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+	return instancesResp.Instances, nil
+}
+
+// CreateExport starts an export job, the job and its file are kept by the
+// service center instance handling the request, so the client should
+// have only one endpoint to get and download it
+func (c *SCClient) CreateExport(ctx context.Context, in *model.ExportRequest) (*model.ExportJob, *scerr.Error) {
+	reqBody, err := json.Marshal(in)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+
+	headers := c.CommonHeaders(ctx)
+	// only default domain has admin permission
+	headers.Set("X-Domain-Name", "default")
+	resp, err := c.RestDoWithContext(ctx, http.MethodPost, apiExportsURL, headers, reqBody)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrUnavailableBackend, err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.toError(body)
+	}
+
+	export := &model.ExportResponse{}
+	err = json.Unmarshal(body, export)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+	return export.Job, nil
+}
+
+func (c *SCClient) GetExport(ctx context.Context, id string) (*model.ExportJob, *scerr.Error) {
+	headers := c.CommonHeaders(ctx)
+	// only default domain has admin permission
+	headers.Set("X-Domain-Name", "default")
+	resp, err := c.RestDoWithContext(ctx, http.MethodGet, fmt.Sprintf(apiExportURL, id), headers, nil)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrUnavailableBackend, err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.toError(body)
+	}
+
+	export := &model.ExportResponse{}
+	err = json.Unmarshal(body, export)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+	return export.Job, nil
+}
+
+// DownloadExport returns the file of the succeeded export job
+func (c *SCClient) DownloadExport(ctx context.Context, id string) ([]byte, *scerr.Error) {
+	headers := c.CommonHeaders(ctx)
+	// only default domain has admin permission
+	headers.Set("X-Domain-Name", "default")
+	resp, err := c.RestDoWithContext(ctx, http.MethodGet, fmt.Sprintf(apiDownloadURL, id), headers, nil)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrUnavailableBackend, err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.toError(body)
+	}
+	return body, nil
+}
+
+// Import loads the dump created by the export
+func (c *SCClient) Import(ctx context.Context, in *model.ImportRequest, dump []byte) (*model.ImportResult, *scerr.Error) {
+	query := url.Values{}
+	if len(in.Policy) > 0 {
+		query.Set("policy", in.Policy)
+	}
+	if len(in.Format) > 0 {
+		query.Set("format", in.Format)
+	}
+	if in.DryRun {
+		query.Set("dryRun", "true")
+	}
+
+	headers := c.CommonHeaders(ctx)
+	// only default domain has admin permission
+	headers.Set("X-Domain-Name", "default")
+	resp, err := c.RestDoWithContext(ctx, http.MethodPost, apiImportURL+"?"+query.Encode(), headers, dump)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrUnavailableBackend, err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.toError(body)
+	}
+
+	if in.DryRun {
+		// the import response is wrapped as the result of the dry run
+		dryRun := &DryRunResponse{}
+		if err := json.Unmarshal(body, dryRun); err != nil {
+			return nil, scerr.NewError(scerr.ErrInternal, err.Error())
+		}
+		body = dryRun.Result
+	}
+
+	imported := &model.ImportResponse{}
+	err = json.Unmarshal(body, imported)
+	if err != nil {
+		return nil, scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+	return imported.Result, nil
+}
+
+// SubscribeFeed calls f with the changes of the prefix after rev streamed
+// by the leader of the delta feed, until ctx is done or the stream ends
+func (c *SCClient) SubscribeFeed(ctx context.Context, prefix string, rev int64, f func(*registry.PluginResponse)) *scerr.Error {
+	headers := c.CommonHeaders(ctx)
+	// the stream must be flushed line by line
+	headers.Set("Accept-Encoding", "identity")
+	resp, err := c.RestDoWithContext(ctx, http.MethodGet, fmt.Sprintf(apiFeedURL, url.QueryEscape(prefix), rev), headers, nil)
+	if err != nil {
+		return scerr.NewError(scerr.ErrUnavailableBackend, err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return scerr.NewError(scerr.ErrInternal, err.Error())
+		}
+		return c.toError(body)
+	}
+
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		changes := &registry.PluginResponse{}
+		if err := decoder.Decode(changes); err != nil {
+			return scerr.NewError(scerr.ErrUnavailableBackend, err.Error())
+		}
+		f(changes)
+	}
+}
@@ -16,10 +16,25 @@
 package sc
 
 import (
+	"github.com/apache/servicecomb-service-center/pkg/util"
 	"golang.org/x/net/context"
 	"net/http"
+	"strconv"
 )
 
+const ctxFence = "_sc_client_fence"
+
+type fence struct {
+	lockName string
+	token    int64
+}
+
+// WithFence makes the writes called with the context conditional on the
+// lock, they are rejected once the lock acquired with the token is lost
+func WithFence(ctx context.Context, lockName string, token int64) context.Context {
+	return util.SetContext(util.CloneContext(ctx), ctxFence, &fence{lockName: lockName, token: token})
+}
+
 func NewSCClient(cfg Config) (*SCClient, error) {
 	client, err := NewLBClient(cfg.Endpoints, cfg.Merge())
 	if err != nil {
@@ -39,5 +54,9 @@ func (c *SCClient) CommonHeaders(ctx context.Context) http.Header {
 	if len(c.Cfg.Token) > 0 {
 		headers.Set("X-Auth-Token", c.Cfg.Token)
 	}
+	if f, ok := ctx.Value(ctxFence).(*fence); ok {
+		headers.Set("X-Lock-Name", f.lockName)
+		headers.Set("X-Fencing-Token", strconv.FormatInt(f.token, 10))
+	}
 	return headers
 }
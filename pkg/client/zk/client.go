@@ -0,0 +1,403 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package zk is a minimal client of the ZooKeeper protocol, supports
+// the node operations without watches
+package zk
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultTimeout        = 5 * time.Second
+	defaultSessionTimeout = 30 * time.Second
+	maxPacketSize         = 16 * 1024 * 1024
+)
+
+var ErrClosed = errors.New("zk connection is closed")
+
+type Config struct {
+	// Addrs are the 'host:port' of the servers, the client connects to
+	// the first available one
+	Addrs          []string
+	SessionTimeout time.Duration
+	Timeout        time.Duration
+	// Digest is the 'user:password' of the digest auth, optional
+	Digest string
+}
+
+type response struct {
+	err  error
+	body []byte
+}
+
+type Client struct {
+	Cfg Config
+
+	conn      net.Conn
+	sessionId int64
+	xid       int32
+	pending   map[int32]chan *response
+	stopCh    chan struct{}
+
+	lock   sync.Mutex
+	err    error
+	closed bool
+}
+
+// SessionId returns the id of the session, it is the owner of the
+// ephemeral nodes created by the client
+func (c *Client) SessionId() int64 {
+	return c.sessionId
+}
+
+func (c *Client) connect() (err error) {
+	for _, addr := range c.Cfg.Addrs {
+		if err = c.dial(addr); err == nil {
+			return nil
+		}
+	}
+	if err == nil {
+		err = fmt.Errorf("no zk server address")
+	}
+	return err
+}
+
+func (c *Client) dial(addr string) error {
+	conn, err := net.DialTimeout("tcp", addr, c.Cfg.Timeout)
+	if err != nil {
+		return err
+	}
+	var e encoder
+	e.int32(0) // protocol version
+	e.int64(0) // last zxid seen
+	e.int32(int32(c.Cfg.SessionTimeout / time.Millisecond))
+	e.int64(0) // session id
+	e.buffer(make([]byte, 16))
+	conn.SetDeadline(time.Now().Add(c.Cfg.Timeout))
+	if _, err := conn.Write(e.packet()); err != nil {
+		conn.Close()
+		return err
+	}
+	reader := bufio.NewReader(conn)
+	b, err := readPacket(reader)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	d := decoder{b: b}
+	d.int32()
+	timeout := d.int32()
+	sessionId := d.int64()
+	if d.err != nil {
+		conn.Close()
+		return d.err
+	}
+	if timeout <= 0 {
+		conn.Close()
+		return fmt.Errorf("zk session expired")
+	}
+	conn.SetDeadline(time.Time{})
+
+	c.conn = conn
+	c.sessionId = sessionId
+	c.Cfg.SessionTimeout = time.Duration(timeout) * time.Millisecond
+	go c.readLoop(reader)
+	go c.pingLoop()
+	return nil
+}
+
+func (c *Client) auth() error {
+	var e encoder
+	e.int32(0) // auth type
+	e.string("digest")
+	e.buffer([]byte(c.Cfg.Digest))
+	_, err := c.request(xidAuth, opAuth, &e)
+	return err
+}
+
+func readPacket(reader io.Reader) ([]byte, error) {
+	var l [4]byte
+	if _, err := io.ReadFull(reader, l[:]); err != nil {
+		return nil, err
+	}
+	n := binary.BigEndian.Uint32(l[:])
+	if n > maxPacketSize {
+		return nil, fmt.Errorf("zk packet too large(%d)", n)
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(reader, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (c *Client) readLoop(reader io.Reader) {
+	for {
+		b, err := readPacket(reader)
+		if err != nil {
+			c.setError(err)
+			return
+		}
+		d := decoder{b: b}
+		xid := d.int32()
+		d.int64() // zxid
+		code := d.int32()
+		if d.err != nil {
+			c.setError(d.err)
+			return
+		}
+		if xid == xidWatcherEvent {
+			// no watches are set
+			continue
+		}
+
+		c.lock.Lock()
+		ch, ok := c.pending[xid]
+		delete(c.pending, xid)
+		c.lock.Unlock()
+		if ok {
+			ch <- &response{err: codeToError(code), body: d.b}
+		}
+	}
+}
+
+func (c *Client) pingLoop() {
+	ticker := time.NewTicker(c.Cfg.SessionTimeout / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.stopCh:
+			return
+		case <-ticker.C:
+			if _, err := c.request(xidPing, opPing, nil); err != nil {
+				c.setError(err)
+				return
+			}
+		}
+	}
+}
+
+func (c *Client) setError(err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.err == nil {
+		c.err = err
+	}
+	for xid, ch := range c.pending {
+		ch <- &response{err: c.err}
+		delete(c.pending, xid)
+	}
+}
+
+// Err returns the first error the connection caught, the client
+// should be discarded if it is not nil, the ephemeral nodes are removed
+// by the server after the session timed out
+func (c *Client) Err() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.closed {
+		return ErrClosed
+	}
+	return c.err
+}
+
+// request sends the request and waits for the response, the xid is
+// generated if it is 0
+func (c *Client) request(xid, op int32, body *encoder) ([]byte, error) {
+	ch := make(chan *response, 1)
+
+	c.lock.Lock()
+	if c.closed {
+		c.lock.Unlock()
+		return nil, ErrClosed
+	}
+	if c.err != nil {
+		err := c.err
+		c.lock.Unlock()
+		return nil, err
+	}
+	if xid == 0 {
+		c.xid++
+		if c.xid <= 0 {
+			c.xid = 1
+		}
+		xid = c.xid
+	}
+	var e encoder
+	e.int32(xid)
+	e.int32(op)
+	if body != nil {
+		e.Write(body.Bytes())
+	}
+	c.pending[xid] = ch
+	c.conn.SetWriteDeadline(time.Now().Add(c.Cfg.Timeout))
+	_, err := c.conn.Write(e.packet())
+	c.lock.Unlock()
+	if err != nil {
+		c.setError(err)
+	}
+
+	timer := time.NewTimer(c.Cfg.Timeout)
+	defer timer.Stop()
+	select {
+	case resp := <-ch:
+		return resp.body, resp.err
+	case <-timer.C:
+		err := fmt.Errorf("wait for zk server response timed out(%s)", c.Cfg.Timeout)
+		c.setError(err)
+		return nil, err
+	}
+}
+
+// Create returns the path of the node created, the path is suffixed by
+// a sequence number if the flags contain FlagSequence
+func (c *Client) Create(path string, data []byte, flags int32, acls []ACL) (string, error) {
+	var e encoder
+	e.string(path)
+	e.buffer(data)
+	e.acls(acls)
+	e.int32(flags)
+	b, err := c.request(0, opCreate, &e)
+	if err != nil {
+		return "", err
+	}
+	d := decoder{b: b}
+	created := d.string()
+	return created, d.err
+}
+
+// CreateAll creates the persistent node and all its parents if they do
+// not exist
+func (c *Client) CreateAll(path string, acls []ACL) error {
+	var p string
+	for _, name := range strings.Split(strings.Trim(path, "/"), "/") {
+		p += "/" + name
+		if _, err := c.Create(p, nil, 0, acls); err != nil && err != ErrNodeExists {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete removes the node if its version matches, -1 matches any
+func (c *Client) Delete(path string, version int32) error {
+	var e encoder
+	e.string(path)
+	e.int32(version)
+	_, err := c.request(0, opDelete, &e)
+	return err
+}
+
+// Exists returns nil Stat if the node does not exist
+func (c *Client) Exists(path string) (*Stat, error) {
+	var e encoder
+	e.string(path)
+	e.bool(false)
+	b, err := c.request(0, opExists, &e)
+	if err == ErrNoNode {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	d := decoder{b: b}
+	stat := d.stat()
+	return stat, d.err
+}
+
+func (c *Client) Get(path string) ([]byte, *Stat, error) {
+	var e encoder
+	e.string(path)
+	e.bool(false)
+	b, err := c.request(0, opGetData, &e)
+	if err != nil {
+		return nil, nil, err
+	}
+	d := decoder{b: b}
+	data := d.buffer()
+	stat := d.stat()
+	return data, stat, d.err
+}
+
+// Children returns the names of the children of the node
+func (c *Client) Children(path string) ([]string, error) {
+	var e encoder
+	e.string(path)
+	e.bool(false)
+	b, err := c.request(0, opGetChildren, &e)
+	if err != nil {
+		return nil, err
+	}
+	d := decoder{b: b}
+	children := d.strings()
+	return children, d.err
+}
+
+// Close ends the session, the ephemeral nodes of the client are removed
+// by the server immediately
+func (c *Client) Close() {
+	c.lock.Lock()
+	closed, broken := c.closed, c.err != nil
+	c.lock.Unlock()
+	if closed {
+		return
+	}
+	if !broken {
+		c.request(0, opCloseSession, nil)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.closed {
+		return
+	}
+	c.closed = true
+	close(c.stopCh)
+	c.conn.Close()
+}
+
+func NewClient(cfg Config) (*Client, error) {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	if cfg.SessionTimeout <= 0 {
+		cfg.SessionTimeout = defaultSessionTimeout
+	}
+	c := &Client{
+		Cfg:     cfg,
+		pending: make(map[int32]chan *response),
+		stopCh:  make(chan struct{}),
+	}
+	if err := c.connect(); err != nil {
+		return nil, err
+	}
+	if len(cfg.Digest) > 0 {
+		if err := c.auth(); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
@@ -0,0 +1,225 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zk
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+const (
+	opCreate       int32 = 1
+	opDelete       int32 = 2
+	opExists       int32 = 3
+	opGetData      int32 = 4
+	opGetChildren  int32 = 8
+	opPing         int32 = 11
+	opCloseSession int32 = -11
+	opAuth         int32 = 100
+
+	xidWatcherEvent int32 = -1
+	xidPing         int32 = -2
+	xidAuth         int32 = -4
+
+	FlagEphemeral int32 = 1
+	FlagSequence  int32 = 2
+
+	PermAll int32 = 0x1f
+)
+
+var (
+	ErrNoNode     = errors.New("zk: node does not exist")
+	ErrNodeExists = errors.New("zk: node already exists")
+	ErrNotEmpty   = errors.New("zk: node has children")
+	ErrBadVersion = errors.New("zk: version conflict")
+	ErrNoAuth     = errors.New("zk: not authenticated")
+	ErrAuthFailed = errors.New("zk: authentication failed")
+)
+
+var errCodes = map[int32]error{
+	-101: ErrNoNode,
+	-102: ErrNoAuth,
+	-103: ErrBadVersion,
+	-110: ErrNodeExists,
+	-111: ErrNotEmpty,
+	-115: ErrAuthFailed,
+}
+
+func codeToError(code int32) error {
+	if code == 0 {
+		return nil
+	}
+	if err, ok := errCodes[code]; ok {
+		return err
+	}
+	return fmt.Errorf("zk: server error code %d", code)
+}
+
+type ACL struct {
+	Perms  int32
+	Scheme string
+	Id     string
+}
+
+// WorldACL returns the acl allows anyone the perms
+func WorldACL(perms int32) []ACL {
+	return []ACL{{Perms: perms, Scheme: "world", Id: "anyone"}}
+}
+
+// Stat is the metadata of the node
+type Stat struct {
+	Czxid          int64
+	Mzxid          int64
+	Ctime          int64
+	Mtime          int64
+	Version        int32
+	Cversion       int32
+	Aversion       int32
+	EphemeralOwner int64
+	DataLength     int32
+	NumChildren    int32
+	Pzxid          int64
+}
+
+// encoder writes the jute serialization of the zookeeper protocol
+type encoder struct {
+	bytes.Buffer
+}
+
+func (e *encoder) int32(v int32) {
+	binary.Write(&e.Buffer, binary.BigEndian, v)
+}
+
+func (e *encoder) int64(v int64) {
+	binary.Write(&e.Buffer, binary.BigEndian, v)
+}
+
+func (e *encoder) bool(v bool) {
+	if v {
+		e.WriteByte(1)
+		return
+	}
+	e.WriteByte(0)
+}
+
+func (e *encoder) buffer(b []byte) {
+	if b == nil {
+		e.int32(-1)
+		return
+	}
+	e.int32(int32(len(b)))
+	e.Write(b)
+}
+
+func (e *encoder) string(s string) {
+	e.int32(int32(len(s)))
+	e.WriteString(s)
+}
+
+func (e *encoder) acls(acls []ACL) {
+	e.int32(int32(len(acls)))
+	for _, acl := range acls {
+		e.int32(acl.Perms)
+		e.string(acl.Scheme)
+		e.string(acl.Id)
+	}
+}
+
+// packet returns the length prefixed frame
+func (e *encoder) packet() []byte {
+	b := make([]byte, 4+e.Len())
+	binary.BigEndian.PutUint32(b, uint32(e.Len()))
+	copy(b[4:], e.Bytes())
+	return b
+}
+
+var errShortBuffer = errors.New("zk: short response")
+
+type decoder struct {
+	b   []byte
+	err error
+}
+
+func (d *decoder) next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || len(d.b) < n {
+		d.err = errShortBuffer
+		return nil
+	}
+	b := d.b[:n]
+	d.b = d.b[n:]
+	return b
+}
+
+func (d *decoder) int32() int32 {
+	b := d.next(4)
+	if b == nil {
+		return 0
+	}
+	return int32(binary.BigEndian.Uint32(b))
+}
+
+func (d *decoder) int64() int64 {
+	b := d.next(8)
+	if b == nil {
+		return 0
+	}
+	return int64(binary.BigEndian.Uint64(b))
+}
+
+func (d *decoder) buffer() []byte {
+	n := d.int32()
+	if n < 0 {
+		return nil
+	}
+	return d.next(int(n))
+}
+
+func (d *decoder) string() string {
+	return string(d.buffer())
+}
+
+func (d *decoder) strings() []string {
+	n := d.int32()
+	if n <= 0 {
+		return nil
+	}
+	s := make([]string, 0, n)
+	for i := int32(0); i < n && d.err == nil; i++ {
+		s = append(s, d.string())
+	}
+	return s
+}
+
+func (d *decoder) stat() *Stat {
+	return &Stat{
+		Czxid:          d.int64(),
+		Mzxid:          d.int64(),
+		Ctime:          d.int64(),
+		Mtime:          d.int64(),
+		Version:        d.int32(),
+		Cversion:       d.int32(),
+		Aversion:       d.int32(),
+		EphemeralOwner: d.int64(),
+		DataLength:     d.int32(),
+		NumChildren:    d.int32(),
+		Pzxid:          d.int64(),
+	}
+}
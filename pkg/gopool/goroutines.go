@@ -21,20 +21,31 @@ import (
 	"github.com/apache/servicecomb-service-center/pkg/util"
 	"golang.org/x/net/context"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
+const DefaultPoolName = "default"
+
 var GlobalConfig = Configure()
 
 var defaultGo *Pool
 
 func init() {
-	defaultGo = New(context.Background())
+	defaultGo = New(context.Background(), Configure().Named(DefaultPoolName))
 }
 
 type Config struct {
 	Concurrent  int
 	IdleTimeout time.Duration
+	// Name is the pool name in the stats, the unnamed pools are not listed
+	Name string
+	// QueueSize is the max number of the tasks waiting for the free
+	// workers, Pool.Do() blocks if the queue is full
+	QueueSize int
+	// PanicHandler is called with the recovered value after the panic of
+	// a task is logged
+	PanicHandler func(r interface{})
 }
 
 func (c *Config) Workers(max int) *Config {
@@ -47,6 +58,21 @@ func (c *Config) Idle(time time.Duration) *Config {
 	return c
 }
 
+func (c *Config) Named(name string) *Config {
+	c.Name = name
+	return c
+}
+
+func (c *Config) Queue(size int) *Config {
+	c.QueueSize = size
+	return c
+}
+
+func (c *Config) OnPanic(f func(r interface{})) *Config {
+	c.PanicHandler = f
+	return c
+}
+
 func Configure() *Config {
 	return &Config{
 		Concurrent:  1000,
@@ -54,6 +80,12 @@ func Configure() *Config {
 	}
 }
 
+type task struct {
+	f func(ctx context.Context)
+	// queued is the time of Pool.Do() called
+	queued time.Time
+}
+
 type Pool struct {
 	Cfg *Config
 
@@ -61,50 +93,196 @@ type Pool struct {
 	ctx    context.Context
 	cancel context.CancelFunc
 	// pending is the chan to block Pool.Do() when go pool is full
-	pending chan func(ctx context.Context)
-	// workers is the counter of the worker
-	workers chan struct{}
+	pending chan *task
+	// released is notified when a worker exits or the pool is resized,
+	// then the blocked Pool.Do() tries to start a new worker
+	released chan struct{}
+
+	mux     sync.RWMutex
+	wg      sync.WaitGroup
+	closed  bool
+	max     int
+	workers int
+	idle    int32
 
-	mux    sync.RWMutex
-	wg     sync.WaitGroup
-	closed bool
+	completed int64
+	panics    int64
+	waitNanos int64
+	execNanos int64
+}
+
+func (g *Pool) execute(t *task) {
+	start := time.Now()
+	wait := start.Sub(t.queued)
+	defer func() {
+		exec := time.Since(start)
+		atomic.AddInt64(&g.waitNanos, int64(wait))
+		atomic.AddInt64(&g.execNanos, int64(exec))
+		atomic.AddInt64(&g.completed, 1)
+		if r := recover(); r != nil {
+			atomic.AddInt64(&g.panics, 1)
+			log.LogPanic(r)
+			g.onPanic(r)
+		}
+		if len(g.Cfg.Name) > 0 {
+			observe(g.Cfg.Name, wait, exec)
+		}
+	}()
+	t.f(g.ctx)
 }
 
-func (g *Pool) execute(f func(ctx context.Context)) {
+func (g *Pool) onPanic(r interface{}) {
+	if g.Cfg.PanicHandler == nil {
+		return
+	}
 	defer log.Recover()
-	f(g.ctx)
+	g.Cfg.PanicHandler(r)
 }
 
 func (g *Pool) Do(f func(context.Context)) *Pool {
 	defer log.Recover()
+	t := &task{f: f, queued: time.Now()}
+	for {
+		if g.handoff(t) {
+			return g
+		}
+		start, closed := g.acquire()
+		if closed {
+			return g
+		}
+		if start {
+			go g.loop(t)
+			return g
+		}
+		select {
+		case g.pending <- t: // block if workers are busy and the queue is full
+			g.wake()
+			return g
+		case <-g.released:
+		}
+	}
+}
+
+// handoff passes the task to an idle worker without blocking, the pool
+// with a queue starts new workers until the max instead, then queues
+// the tasks
+func (g *Pool) handoff(t *task) bool {
+	g.mux.RLock()
+	closed := g.closed
+	g.mux.RUnlock()
+	if closed || cap(g.pending) > 0 {
+		return false
+	}
 	select {
-	case g.pending <- f: // block if workers are busy
-	case g.workers <- struct{}{}:
-		g.wg.Add(1)
-		go g.loop(f)
+	case g.pending <- t:
+		return true
+	default:
+		return false
 	}
-	return g
 }
 
-func (g *Pool) loop(f func(context.Context)) {
+// acquire counts a new worker if the pool is not full, otherwise the
+// task should be queued
+func (g *Pool) acquire() (start bool, closed bool) {
+	g.mux.Lock()
+	defer g.mux.Unlock()
+	if g.closed {
+		return false, true
+	}
+	if g.workers >= g.max {
+		return false, false
+	}
+	g.workers++
+	g.wg.Add(1)
+	return true, false
+}
+
+// wake starts a worker to consume the queue if no worker is running,
+// e.g. the last worker exited before the task was queued
+func (g *Pool) wake() {
+	g.mux.Lock()
+	if g.closed || g.workers > 0 {
+		g.mux.Unlock()
+		return
+	}
+	g.workers++
+	g.wg.Add(1)
+	g.mux.Unlock()
+	go g.loop(nil)
+}
+
+// release returns true if the idle worker can exit, the last worker
+// keeps running until the queue is empty
+func (g *Pool) release() bool {
+	g.mux.Lock()
+	defer g.mux.Unlock()
+	if !g.closed && g.workers == 1 && len(g.pending) > 0 {
+		return false
+	}
+	g.workers--
+	g.notify()
+	return true
+}
+
+// retire returns true if the worker should exit as the pool is shrunk
+func (g *Pool) retire() bool {
+	g.mux.Lock()
+	defer g.mux.Unlock()
+	if g.workers <= g.max {
+		return false
+	}
+	g.workers--
+	g.notify()
+	return true
+}
+
+func (g *Pool) notify() {
+	select {
+	case g.released <- struct{}{}:
+	default:
+	}
+}
+
+func (g *Pool) loop(t *task) {
 	defer g.wg.Done()
-	defer func() { <-g.workers }()
 
 	timer := time.NewTimer(g.Cfg.IdleTimeout)
 	defer timer.Stop()
 	for {
-		g.execute(f)
+		if t != nil {
+			g.execute(t)
+			if g.retire() {
+				return
+			}
+		}
 
+		atomic.AddInt32(&g.idle, 1)
 		select {
 		case <-timer.C:
-			return
-		case f = <-g.pending:
-			if f == nil {
+			t = nil
+		case t = <-g.pending:
+		}
+		atomic.AddInt32(&g.idle, -1)
+		if t == nil {
+			// idle timeout or the pool is closed
+			if g.release() {
 				return
 			}
-			util.ResetTimer(timer, g.Cfg.IdleTimeout)
 		}
+		util.ResetTimer(timer, g.Cfg.IdleTimeout)
+	}
+}
+
+// Resize changes the max number of the workers, the extra workers exit
+// after the running tasks are done
+func (g *Pool) Resize(max int) {
+	if max <= 0 {
+		return
 	}
+	g.mux.Lock()
+	g.max = max
+	g.notify()
+	g.mux.Unlock()
 }
 
 // Close will call context.Cancel(), so all goroutines maybe exit when job does not complete
@@ -117,8 +295,8 @@ func (g *Pool) Close(grace bool) {
 	g.closed = true
 	g.mux.Unlock()
 
+	unregister(g)
 	close(g.pending)
-	close(g.workers)
 	g.cancel()
 	if grace {
 		g.wg.Wait()
@@ -135,8 +313,8 @@ func (g *Pool) Done() {
 	g.closed = true
 	g.mux.Unlock()
 
+	unregister(g)
 	close(g.pending)
-	close(g.workers)
 	g.wg.Wait()
 }
 
@@ -147,15 +325,24 @@ func New(ctx context.Context, cfgs ...*Config) *Pool {
 	}
 	cfg := cfgs[0]
 	gr := &Pool{
-		Cfg:     cfg,
-		ctx:     ctx,
-		cancel:  cancel,
-		pending: make(chan func(context.Context)),
-		workers: make(chan struct{}, cfg.Concurrent),
+		Cfg:      cfg,
+		ctx:      ctx,
+		cancel:   cancel,
+		pending:  make(chan *task, cfg.QueueSize),
+		released: make(chan struct{}, 1),
+		max:      cfg.Concurrent,
+	}
+	if len(cfg.Name) > 0 {
+		register(gr)
 	}
 	return gr
 }
 
+// Default returns the pool used by Go()
+func Default() *Pool {
+	return defaultGo
+}
+
 func Go(f func(context.Context)) {
 	defaultGo.Do(f)
 }
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"golang.org/x/net/context"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -116,3 +117,123 @@ func TestGoRoutine_Exception2(t *testing.T) {
 	})
 	CloseAndWait()
 }
+
+func TestPool_Resize(t *testing.T) {
+	var (
+		running int32
+		peak    int32
+		wg      sync.WaitGroup
+	)
+	test := New(context.Background(), Configure().Workers(2))
+	defer test.Close(true)
+	do := func() {
+		wg.Add(1)
+		test.Do(func(ctx context.Context) {
+			defer wg.Done()
+			n := atomic.AddInt32(&running, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			<-time.After(50 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+		})
+	}
+	for i := 0; i < 6; i++ {
+		do()
+	}
+	wg.Wait()
+	if peak != 2 {
+		t.Fatalf("TestPool_Resize failed, peak %d", peak)
+	}
+
+	test.Resize(4)
+	atomic.StoreInt32(&peak, 0)
+	for i := 0; i < 8; i++ {
+		do()
+	}
+	wg.Wait()
+	if peak != 4 {
+		t.Fatalf("TestPool_Resize failed, peak %d", peak)
+	}
+
+	test.Resize(1)
+	atomic.StoreInt32(&peak, 0)
+	for i := 0; i < 4; i++ {
+		do()
+	}
+	wg.Wait()
+	if s := test.Stats(); s.MaxWorkers != 1 || s.Workers > 1 || s.Completed != 18 {
+		t.Fatalf("TestPool_Resize failed, %v", s)
+	}
+}
+
+func TestPool_Queue(t *testing.T) {
+	block := make(chan struct{})
+	test := New(context.Background(), Configure().Workers(1).Queue(2))
+	defer test.Close(true)
+	test.Do(func(ctx context.Context) { <-block })
+
+	// queued without blocking
+	done := make(chan struct{})
+	go func() {
+		test.Do(func(ctx context.Context) {})
+		test.Do(func(ctx context.Context) {})
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("TestPool_Queue failed, the queue is not used")
+	}
+	if s := test.Stats(); s.Queued != 2 || s.QueueSize != 2 || s.Utilization != 1 {
+		t.Fatalf("TestPool_Queue failed, %v", s)
+	}
+	close(block)
+	test.Done()
+	if s := test.Stats(); s.Completed != 3 || s.Queued != 0 || s.Workers != 0 {
+		t.Fatalf("TestPool_Queue failed, %v", s)
+	}
+}
+
+func TestPool_Stats(t *testing.T) {
+	var (
+		recovered interface{}
+		observed  int32
+	)
+	SetTaskObserver(func(pool string, wait, exec time.Duration) {
+		if pool == "test" {
+			atomic.AddInt32(&observed, 1)
+		}
+	})
+	defer SetTaskObserver(nil)
+
+	test := New(context.Background(), Configure().Named("test").OnPanic(func(r interface{}) {
+		recovered = r
+	}))
+	test.Do(func(ctx context.Context) { panic("oops") })
+	test.Do(func(ctx context.Context) {})
+
+	found := false
+	for _, s := range Stats() {
+		if s.Name == "test" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("TestPool_Stats failed, the named pool is not listed")
+	}
+
+	test.Done()
+	s := test.Stats()
+	if s.Completed != 2 || s.Panics != 1 || recovered != "oops" || atomic.LoadInt32(&observed) != 2 {
+		t.Fatalf("TestPool_Stats failed, %v, %v", s, recovered)
+	}
+	for _, s := range Stats() {
+		if s.Name == "test" {
+			t.Fatalf("TestPool_Stats failed, the closed pool is listed")
+		}
+	}
+}
@@ -0,0 +1,112 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package gopool
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	pools     = make(map[*Pool]struct{})
+	poolsLock sync.RWMutex
+
+	observer atomic.Value
+)
+
+// TaskObserver is called after each task of the named pools is done, the
+// wait is the time from Pool.Do() called to the task started
+type TaskObserver func(pool string, wait, exec time.Duration)
+
+// PoolStats is the utilization of a named pool
+type PoolStats struct {
+	Name       string `json:"name"`
+	MaxWorkers int    `json:"maxWorkers"`
+	Workers    int    `json:"workers"`
+	Idle       int    `json:"idle"`
+	QueueSize  int    `json:"queueSize"`
+	Queued     int    `json:"queued"`
+	Completed  int64  `json:"completed"`
+	Panics     int64  `json:"panics"`
+	// WaitSeconds and ExecSeconds are the total time of the completed
+	// tasks waiting for the workers and executing
+	WaitSeconds float64 `json:"waitSeconds"`
+	ExecSeconds float64 `json:"execSeconds"`
+	// Utilization is the busy workers divided by the max workers
+	Utilization float64 `json:"utilization"`
+}
+
+func (g *Pool) Stats() *PoolStats {
+	g.mux.RLock()
+	max, workers := g.max, g.workers
+	g.mux.RUnlock()
+	idle := int(atomic.LoadInt32(&g.idle))
+	s := &PoolStats{
+		Name:        g.Cfg.Name,
+		MaxWorkers:  max,
+		Workers:     workers,
+		Idle:        idle,
+		QueueSize:   cap(g.pending),
+		Queued:      len(g.pending),
+		Completed:   atomic.LoadInt64(&g.completed),
+		Panics:      atomic.LoadInt64(&g.panics),
+		WaitSeconds: time.Duration(atomic.LoadInt64(&g.waitNanos)).Seconds(),
+		ExecSeconds: time.Duration(atomic.LoadInt64(&g.execNanos)).Seconds(),
+	}
+	if busy := workers - idle; busy > 0 && max > 0 {
+		s.Utilization = float64(busy) / float64(max)
+	}
+	return s
+}
+
+// Stats returns the stats of the named pools sorted by the name
+func Stats() []*PoolStats {
+	poolsLock.RLock()
+	l := make([]*PoolStats, 0, len(pools))
+	for g := range pools {
+		l = append(l, g.Stats())
+	}
+	poolsLock.RUnlock()
+	sort.Slice(l, func(i, j int) bool {
+		return l[i].Name < l[j].Name
+	})
+	return l
+}
+
+func SetTaskObserver(f TaskObserver) {
+	observer.Store(f)
+}
+
+func observe(pool string, wait, exec time.Duration) {
+	if f, ok := observer.Load().(TaskObserver); ok && f != nil {
+		f(pool, wait, exec)
+	}
+}
+
+func register(g *Pool) {
+	poolsLock.Lock()
+	pools[g] = struct{}{}
+	poolsLock.Unlock()
+}
+
+func unregister(g *Pool) {
+	poolsLock.Lock()
+	delete(pools, g)
+	poolsLock.Unlock()
+}
@@ -43,6 +43,7 @@ var (
 	SignalHooks     map[int]map[os.Signal][]func()
 	graceMux        sync.Mutex
 	forked          bool
+	reloadHooks     []func()
 )
 
 func init() {
@@ -82,6 +83,14 @@ func After(f func()) {
 	RegisterSignalHook(PostSignal, f, registerSignals[1:]...)
 }
 
+// OnReload registers f to be called on SIGHUP, the SIGHUP no longer
+// forks a new process once any f is registered
+func OnReload(f func()) {
+	graceMux.Lock()
+	reloadHooks = append(reloadHooks, f)
+	graceMux.Unlock()
+}
+
 func RegisterSignalHook(phase int, f func(), sigs ...os.Signal) {
 	for s := range SignalHooks[phase] {
 		for _, sig := range sigs {
@@ -120,6 +129,9 @@ func handleSignals() {
 	for {
 		select {
 		case sig = <-sigCh:
+			if sig == syscall.SIGHUP && reload() {
+				continue
+			}
 			fireSignalHook(PreSignal, sig)
 			switch sig {
 			case syscall.SIGHUP:
@@ -134,6 +146,16 @@ func handleSignals() {
 	}
 }
 
+func reload() bool {
+	graceMux.Lock()
+	hooks := reloadHooks
+	graceMux.Unlock()
+	for _, f := range hooks {
+		f()
+	}
+	return len(hooks) > 0
+}
+
 func fork() (err error) {
 	graceMux.Lock()
 	defer graceMux.Unlock()
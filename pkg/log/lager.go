@@ -100,3 +100,15 @@ func (l *Logger) Recover(r interface{}, callerSkip int) {
 
 func (l *Logger) Sync() {
 }
+
+// With returns a child logger printing the key value pairs in every line
+func (l *Logger) With(kv ...string) *Logger {
+	data := make(lager.Data, len(kv)/2)
+	for i := 0; i+1 < len(kv); i += 2 {
+		data[kv[i]] = kv[i+1]
+	}
+	return &Logger{
+		Config: l.Config,
+		Logger: l.Logger.WithData(data),
+	}
+}
@@ -0,0 +1,187 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build go1.9
+
+package log
+
+import (
+	"errors"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"strings"
+	"sync"
+)
+
+var ErrInvalidLevel = errors.New("invalid log level")
+
+// Level is the runtime level of the logger, the modules are the package
+// path patterns, like 'notification' or 'server/service', whose logs are
+// printed in their own level regardless of the global level
+type Level struct {
+	global zap.AtomicLevel
+	// min is the lowest level of the global and modules
+	min     zap.AtomicLevel
+	mux     sync.RWMutex
+	modules map[string]zapcore.Level
+}
+
+func (l *Level) Enabled(lvl zapcore.Level) bool {
+	return l.min.Enabled(lvl)
+}
+
+// enabledFor checks the entry level by the module of the caller
+func (l *Level) enabledFor(lvl zapcore.Level, caller zapcore.EntryCaller) bool {
+	if l.global.Enabled(lvl) {
+		return true
+	}
+	if !caller.Defined {
+		return false
+	}
+	l.mux.RLock()
+	defer l.mux.RUnlock()
+	for m, ml := range l.modules {
+		if lvl >= ml && strings.Contains(caller.File, "/"+m+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *Level) SetLevel(level string) error {
+	lvl, ok := zapLevelMap[strings.ToUpper(level)]
+	if !ok {
+		return ErrInvalidLevel
+	}
+	l.mux.Lock()
+	l.global.SetLevel(lvl)
+	l.resetMin()
+	l.mux.Unlock()
+	return nil
+}
+
+func (l *Level) Level() string {
+	return l.global.Level().CapitalString()
+}
+
+// SetModuleLevel sets the level of the module, the empty level removes it
+func (l *Level) SetModuleLevel(module, level string) error {
+	module = strings.Trim(module, "/")
+	if len(module) == 0 {
+		return errors.New("invalid module")
+	}
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	if len(level) == 0 {
+		delete(l.modules, module)
+		l.resetMin()
+		return nil
+	}
+	lvl, ok := zapLevelMap[strings.ToUpper(level)]
+	if !ok {
+		return ErrInvalidLevel
+	}
+	l.modules[module] = lvl
+	l.resetMin()
+	return nil
+}
+
+func (l *Level) ModuleLevels() map[string]string {
+	l.mux.RLock()
+	m := make(map[string]string, len(l.modules))
+	for module, lvl := range l.modules {
+		m[module] = lvl.CapitalString()
+	}
+	l.mux.RUnlock()
+	return m
+}
+
+func (l *Level) resetMin() {
+	min := l.global.Level()
+	for _, lvl := range l.modules {
+		if lvl < min {
+			min = lvl
+		}
+	}
+	l.min.SetLevel(min)
+}
+
+func NewLevel(level string) *Level {
+	lvl, ok := zapLevelMap[strings.ToUpper(level)]
+	if !ok {
+		lvl = zap.DebugLevel
+	}
+	return &Level{
+		global:  zap.NewAtomicLevelAt(lvl),
+		min:     zap.NewAtomicLevelAt(lvl),
+		modules: make(map[string]zapcore.Level),
+	}
+}
+
+// levelCore filters the entries by the runtime level
+type levelCore struct {
+	zapcore.Core
+	level *Level
+}
+
+func (c *levelCore) Enabled(lvl zapcore.Level) bool {
+	return c.level.Enabled(lvl)
+}
+
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), level: c.level}
+}
+
+func (c *levelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write drops the entries of the modules not in the level, the caller
+// is unknown until the entry is checked
+func (c *levelCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if !c.level.enabledFor(ent.Level, ent.Caller) {
+		return nil
+	}
+	return c.Core.Write(ent, fields)
+}
+
+// CheckLevel returns ErrInvalidLevel if the level can not be set
+func CheckLevel(level string) error {
+	if _, ok := zapLevelMap[strings.ToUpper(level)]; !ok {
+		return ErrInvalidLevel
+	}
+	return nil
+}
+
+// SetLevel changes the level of the global logger at runtime
+func SetLevel(level string) error {
+	return logger.level.SetLevel(level)
+}
+
+func GetLevel() string {
+	return logger.level.Level()
+}
+
+// SetModuleLevel changes the level of the module logs at runtime
+func SetModuleLevel(module, level string) error {
+	return logger.level.SetModuleLevel(module, level)
+}
+
+func ModuleLevels() map[string]string {
+	return logger.level.ModuleLevels()
+}
@@ -0,0 +1,69 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build go1.9
+
+package log
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLevel(t *testing.T) {
+	f, err := ioutil.TempFile("", "level")
+	if err != nil {
+		t.Fatalf("TestLevel failed, %v", err)
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+
+	l := NewLogger(Configure().WithCallerSkip(1).WithFile(f.Name()))
+	if err := l.level.SetLevel("x"); err != ErrInvalidLevel {
+		t.Fatalf("TestLevel failed, %v", err)
+	}
+	l.level.SetLevel("warn")
+	l.Info("global-info")
+	l.level.SetModuleLevel("notification", "debug")
+	l.Info("other-module-info")
+	l.level.SetModuleLevel("pkg/log", "info")
+	l.Debug("module-debug")
+	l.Info("module-info")
+	if m := l.level.ModuleLevels(); len(m) != 2 || m["pkg/log"] != "INFO" {
+		t.Fatalf("TestLevel failed, %v", m)
+	}
+	l.level.SetModuleLevel("pkg/log", "")
+	l.Info("removed-module-info")
+	l.Warn("global-warn")
+	l.Sync()
+
+	b, _ := ioutil.ReadFile(f.Name())
+	s := string(b)
+	for _, expect := range []string{"module-info", "global-warn"} {
+		if !strings.Contains(s, expect) {
+			t.Fatalf("TestLevel failed, %s not found in %s", expect, s)
+		}
+	}
+	for _, unexpect := range []string{"global-info", "other-module-info", "module-debug", "removed-module-info"} {
+		if strings.Contains(s, unexpect) {
+			t.Fatalf("TestLevel failed, %s found in %s", unexpect, s)
+		}
+	}
+	if l.level.Level() != "WARN" {
+		t.Fatalf("TestLevel failed, %s", l.level.Level())
+	}
+}
@@ -17,6 +17,8 @@ package log
 
 import (
 	"errors"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"golang.org/x/net/context"
 	"os"
 	"testing"
 	"time"
@@ -84,6 +86,15 @@ func TestNewLogger(t *testing.T) {
 	l.Fatal("a", nil)
 }
 
+func TestWithContext(t *testing.T) {
+	l := WithContext(context.Background())
+	l.Infof("%s", "a")
+	l = WithContext(util.SetRequestId(context.Background(), "x"))
+	l.Infof("%s", "b")
+	l.Errorf(errors.New("error"), "%s", "c")
+	l.With(FIELD_DOMAIN, "default", FIELD_API, "Register").Warn("d")
+}
+
 func TestLogPanic(t *testing.T) {
 	defer func() {
 		defer func() {
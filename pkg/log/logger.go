@@ -18,13 +18,39 @@ package log
 
 import (
 	"fmt"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"golang.org/x/net/context"
 	"time"
 )
 
+const (
+	FIELD_REQUEST_ID = "requestId"
+	FIELD_DOMAIN     = "domain"
+	FIELD_API        = "api"
+)
+
 func Default() *Logger {
 	return logger
 }
 
+// WithContext returns the logger printing the request id, domain and api
+// of ctx, use it like 'log.WithContext(ctx).Errorf(err, ...)' in the
+// request scope
+func WithContext(ctx context.Context) *Logger {
+	id := util.ParseRequestId(ctx)
+	if len(id) == 0 {
+		id = "-"
+	}
+	kv := []string{FIELD_REQUEST_ID, id}
+	if domain := util.ParseDomain(ctx); len(domain) > 0 {
+		kv = append(kv, FIELD_DOMAIN, domain)
+	}
+	if api := util.ParseApi(ctx); len(api) > 0 {
+		kv = append(kv, FIELD_API, api)
+	}
+	return logger.With(kv...)
+}
+
 func Debug(msg string) {
 	logger.Debug(msg)
 }
@@ -26,7 +26,6 @@ import (
 	"os"
 	"runtime"
 	"runtime/debug"
-	"strings"
 	"time"
 )
 
@@ -79,16 +78,7 @@ func Configure() Config {
 	}
 }
 
-func toZapConfig(c Config) zapcore.Core {
-	// level config
-	l, ok := zapLevelMap[strings.ToUpper(c.LoggerLevel)]
-	if !ok {
-		l = zap.DebugLevel
-	}
-	var levelEnabler zap.LevelEnablerFunc = func(level zapcore.Level) bool {
-		return level >= l
-	}
-
+func toZapConfig(c Config, level *Level) zapcore.Core {
 	// log format
 	format := zapcore.EncoderConfig{
 		MessageKey:     "message",
@@ -108,6 +98,9 @@ func toZapConfig(c Config) zapcore.Core {
 	if c.LogFormatText {
 		enc = zapcore.NewConsoleEncoder(format)
 	} else {
+		// the structured fields for the log collectors like ELK or Loki:
+		// level, ts, caller, message, error, requestId, domain and api
+		format.TimeKey = "ts"
 		enc = zapcore.NewJSONEncoder(format)
 	}
 
@@ -127,12 +120,14 @@ func toZapConfig(c Config) zapcore.Core {
 	}
 
 	zap.NewDevelopment()
-	return zapcore.NewCore(enc, syncer, levelEnabler)
+	// the level is checked by the levelCore which can be changed at runtime
+	return &levelCore{Core: zapcore.NewCore(enc, syncer, zap.DebugLevel), level: level}
 }
 
 type Logger struct {
 	Config Config
 
+	level     *Level
 	zapLogger *zap.Logger
 	zapSugar  *zap.SugaredLogger
 }
@@ -213,14 +208,33 @@ func (l *Logger) Sync() {
 	l.zapLogger.Sync()
 }
 
+// With returns a child logger printing the key value pairs in every
+// line, the child is called directly, not by the package functions, so
+// it skips one less caller than the parent
+func (l *Logger) With(kv ...string) *Logger {
+	fields := make([]zap.Field, 0, len(kv)/2)
+	for i := 0; i+1 < len(kv); i += 2 {
+		fields = append(fields, zap.String(kv[i], kv[i+1]))
+	}
+	zl := l.zapLogger.WithOptions(zap.AddCallerSkip(-1)).With(fields...)
+	return &Logger{
+		Config:    l.Config.WithCallerSkip(l.Config.CallerSkip - 1),
+		level:     l.level,
+		zapLogger: zl,
+		zapSugar:  zl.Sugar(),
+	}
+}
+
 func NewLogger(cfg Config) *Logger {
-	l := zap.New(toZapConfig(cfg),
+	level := NewLevel(cfg.LoggerLevel)
+	l := zap.New(toZapConfig(cfg, level),
 		zap.ErrorOutput(StderrSyncer),
 		zap.AddCaller(),
 		zap.AddCallerSkip(cfg.CallerSkip),
 	)
 	return &Logger{
 		Config:    cfg,
+		level:     level,
 		zapLogger: l,
 		zapSugar:  l.Sugar(),
 	}
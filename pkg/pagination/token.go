@@ -0,0 +1,95 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package pagination
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"sync"
+)
+
+const SIGNATURE_SIZE = 16
+
+var ErrInvalidToken = errors.New("continuation token is invalid")
+
+// Token is the position of the last item of the page, the list continues
+// after it however the items before it are changed. Scope identifies the
+// list the token is issued for, it is only valid for the same one
+type Token struct {
+	Scope string `json:"c,omitempty"`
+	Sort  string `json:"s,omitempty"`
+	Value string `json:"v,omitempty"`
+	Id    string `json:"i"`
+}
+
+var (
+	lock sync.RWMutex
+	key  = randomKey()
+)
+
+func randomKey() []byte {
+	b := make([]byte, sha256.Size)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// SetKey sets the secret to sign the tokens, the replicas behind the same
+// endpoint must share it, otherwise the tokens are only valid on the
+// replica issued them
+func SetKey(secret []byte) {
+	if len(secret) == 0 {
+		return
+	}
+	lock.Lock()
+	key = secret
+	lock.Unlock()
+}
+
+func sign(payload []byte) []byte {
+	lock.RLock()
+	mac := hmac.New(sha256.New, key)
+	lock.RUnlock()
+	mac.Write(payload)
+	return mac.Sum(nil)[:SIGNATURE_SIZE]
+}
+
+// Encode returns the opaque token, the clients can not forge the position
+func Encode(t Token) string {
+	payload, _ := json.Marshal(t)
+	return base64.RawURLEncoding.EncodeToString(append(payload, sign(payload)...))
+}
+
+func Decode(s string) (t Token, err error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) <= SIGNATURE_SIZE {
+		return t, ErrInvalidToken
+	}
+	payload, signature := b[:len(b)-SIGNATURE_SIZE], b[len(b)-SIGNATURE_SIZE:]
+	if !hmac.Equal(signature, sign(payload)) {
+		return t, ErrInvalidToken
+	}
+	if err := json.Unmarshal(payload, &t); err != nil {
+		return t, ErrInvalidToken
+	}
+	return t, nil
+}
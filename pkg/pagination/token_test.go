@@ -0,0 +1,44 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package pagination
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	token := Token{Scope: "abc", Sort: "-timestamp", Value: "100", Id: "a\nb"}
+	s := Encode(token)
+	decoded, err := Decode(s)
+	if err != nil || decoded != token {
+		t.Fatalf("TestEncode failed, %v, %v", decoded, err)
+	}
+
+	b, _ := base64.RawURLEncoding.DecodeString(s)
+	b[0] ^= 1
+	for _, s := range []string{"", "!", "abc", base64.RawURLEncoding.EncodeToString(b)} {
+		if _, err := Decode(s); err != ErrInvalidToken {
+			t.Fatalf("TestEncode failed, %s", s)
+		}
+	}
+
+	SetKey([]byte("another"))
+	if _, err := Decode(s); err != ErrInvalidToken {
+		t.Fatalf("TestEncode failed")
+	}
+}
@@ -17,6 +17,7 @@
 package rest
 
 import (
+	"github.com/apache/servicecomb-service-center/pkg/util"
 	"net/http"
 )
 
@@ -29,11 +30,13 @@ const (
 	CTX_RESPONSE      = "_server_response"
 	CTX_REQUEST       = "_server_request"
 	CTX_MATCH_PATTERN = "_server_match_pattern"
-	CTX_MATCH_FUNC    = "_server_match_func"
+	CTX_MATCH_FUNC    = util.CtxApi
 
 	SERVER_CHAIN_NAME = "_server_chain"
 
 	HEADER_RESPONSE_STATUS = "X-Response-Status"
+	HEADER_ERROR_CODE      = "X-Error-Code"
+	HEADER_REQUEST_ID      = "X-Request-Id"
 
 	HEADER_ALLOW            = "Allow"
 	HEADER_HOST             = "Host"
@@ -42,12 +45,21 @@ const (
 	HEADER_CONTENT_ENCODING = "Content-Encoding"
 	HEADER_ACCEPT           = "Accept"
 	HEADER_ACCEPT_ENCODING  = "Accept-Encoding"
+	HEADER_ETAG             = "ETag"
+	HEADER_IF_NONE_MATCH    = "If-None-Match"
+	HEADER_IF_MATCH         = "If-Match"
+	HEADER_RETRY_AFTER      = "Retry-After"
 
 	ACCEPT_ANY  = "*/*"
 	ACCEPT_JSON = "application/json"
 
 	CONTENT_TYPE_JSON = "application/json; charset=UTF-8"
 	CONTENT_TYPE_TEXT = "text/plain; charset=UTF-8"
+	// the newline delimited JSON, one value a line
+	CONTENT_TYPE_NDJSON = "application/x-ndjson"
+	// the RFC 7807 error payload
+	CONTENT_TYPE_PROBLEM = "application/problem+json"
+	CONTENT_TYPE_YAML    = "application/yaml"
 
 	ENCODING_GZIP = "gzip"
 
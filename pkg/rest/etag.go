@@ -0,0 +1,76 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package rest
+
+import (
+	"strings"
+)
+
+const ETAG_ANY = "*"
+
+// ETag returns the strong entity tag of the resource revision
+func ETag(rev string) string {
+	return `"` + rev + `"`
+}
+
+// ParseIfNoneMatch returns the revisions listed in the If-None-Match
+// header, the weak tags are compared as the strong ones
+func ParseIfNoneMatch(header string) (revs []string) {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == ETAG_ANY {
+			revs = append(revs, tag)
+			continue
+		}
+		tag = strings.TrimPrefix(tag, "W/")
+		if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+			continue
+		}
+		if tag = tag[1 : len(tag)-1]; len(tag) > 0 {
+			revs = append(revs, tag)
+		}
+	}
+	return
+}
+
+// MatchIfNoneMatch reports whether the client already holds the revision
+func MatchIfNoneMatch(header, rev string) bool {
+	if len(header) == 0 || len(rev) == 0 {
+		return false
+	}
+	for _, r := range ParseIfNoneMatch(header) {
+		if r == ETAG_ANY || r == rev {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseIfMatch returns the revision of the If-Match header, it is empty
+// if the header is absent or '*', the update is conditional on the first
+// strong tag only
+func ParseIfMatch(header string) (rev string, ok bool) {
+	header = strings.TrimSpace(header)
+	if len(header) == 0 || header == ETAG_ANY {
+		return "", true
+	}
+	tag := strings.TrimSpace(strings.Split(header, ",")[0])
+	if len(tag) < 3 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return "", false
+	}
+	return tag[1 : len(tag)-1], true
+}
@@ -17,6 +17,7 @@
 package rest
 
 import (
+	"github.com/apache/servicecomb-service-center/pkg/log"
 	"net"
 	"os"
 	"syscall"
@@ -43,9 +44,18 @@ func NewTcpListener(l net.Listener, srv *Server) (el *TcpListener) {
 }
 
 func (rl *TcpListener) Accept() (c net.Conn, err error) {
-	tc, err := rl.Listener.(*net.TCPListener).AcceptTCP()
-	if err != nil {
-		return
+	var tc *net.TCPConn
+	for {
+		tc, err = rl.Listener.(*net.TCPListener).AcceptTCP()
+		if err != nil {
+			return
+		}
+		if !rl.server.Full() {
+			break
+		}
+		log.Warnf("too many connections(%d), close the connection from %s",
+			rl.server.MaxConnections, tc.RemoteAddr())
+		tc.Close()
 	}
 
 	if rl.server.KeepaliveTimeout > 0 {
@@ -82,6 +82,11 @@ func RegisterServant(servant interface{}) {
 	}
 }
 
+// Routes returns the routes of all the registered servants
+func Routes() []URLPattern {
+	return serverHandler.Patterns()
+}
+
 //GetRouter return the router fo REST service
 func GetRouter() http.Handler {
 	return serverHandler
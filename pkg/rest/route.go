@@ -25,17 +25,21 @@ import (
 	"github.com/apache/servicecomb-service-center/pkg/util"
 	"net/http"
 	"net/url"
+	"sort"
 	"strings"
 )
 
 type URLPattern struct {
 	Method string
 	Path   string
+	// Func is the full name of the callback function
+	Func string
 }
 
 type urlPatternHandler struct {
 	Name string
 	Path string
+	Func string
 	http.Handler
 }
 
@@ -70,13 +74,30 @@ func (this *ROAServerHandler) addRoute(route *Route) (err error) {
 		return errors.New(message)
 	}
 
+	name := util.FuncName(route.Func)
 	this.handlers[method] = append(this.handlers[method], &urlPatternHandler{
-		util.FormatFuncName(util.FuncName(route.Func)), route.Path, http.HandlerFunc(route.Func)})
+		util.FormatFuncName(name), route.Path, name, http.HandlerFunc(route.Func)})
 	log.Infof("register route %s(%s)", route.Path, method)
 
 	return nil
 }
 
+// Patterns returns the registered routes sorted by the path and method
+func (this *ROAServerHandler) Patterns() (patterns []URLPattern) {
+	for method, handlers := range this.handlers {
+		for _, ph := range handlers {
+			patterns = append(patterns, URLPattern{Method: method, Path: ph.Path, Func: ph.Func})
+		}
+	}
+	sort.Slice(patterns, func(i, j int) bool {
+		if patterns[i].Path != patterns[j].Path {
+			return patterns[i].Path < patterns[j].Path
+		}
+		return patterns[i].Method < patterns[j].Method
+	})
+	return
+}
+
 func (this *ROAServerHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	for _, ph := range this.handlers[r.Method] {
 		if params, ok := ph.try(r.URL.Path); ok {
@@ -143,7 +164,12 @@ func (this *ROAServerHandler) serve(ph *urlPatternHandler, w http.ResponseWriter
 					}
 				}()
 				if ret.OK {
-					ph.ServeHTTP(w, r)
+					// the handlers may replace the response writer
+					rw, _ := inv.Context().Value(CTX_RESPONSE).(http.ResponseWriter)
+					if rw == nil {
+						rw = w
+					}
+					ph.ServeHTTP(rw, r)
 				}
 			})
 }
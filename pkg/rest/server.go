@@ -22,6 +22,8 @@ import (
 	"github.com/NYTimes/gziphandler"
 	"github.com/apache/servicecomb-service-center/pkg/grace"
 	"github.com/apache/servicecomb-service-center/pkg/log"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 	"net"
 	"net/http"
 	"os"
@@ -50,6 +52,14 @@ type ServerConfig struct {
 	TLSConfig         *tls.Config
 	Compressed        bool
 	CompressMinBytes  int
+	// EnableHTTP2 serves HTTP/2 over TLS, and over cleartext with the
+	// prior knowledge or the upgrade if EnableH2C is set and TLS is disabled
+	EnableHTTP2          bool
+	EnableH2C            bool
+	MaxConcurrentStreams uint32
+	// MaxConnections closes the new connections if the number of the
+	// connections reaches it, 0 is unlimited
+	MaxConnections int
 }
 
 func DefaultServerConfig() *ServerConfig {
@@ -63,6 +73,7 @@ func DefaultServerConfig() *ServerConfig {
 		MaxHeaderBytes:    16384,
 		Compressed:        true,
 		CompressMinBytes:  1400, // 1.4KB
+		EnableHTTP2:       true,
 	}
 }
 
@@ -83,6 +94,7 @@ func NewServer(srvCfg *ServerConfig) *Server {
 		},
 		KeepaliveTimeout: srvCfg.KeepAliveTimeout,
 		GraceTimeout:     srvCfg.GraceTimeout,
+		MaxConnections:   srvCfg.MaxConnections,
 		state:            serverStateInit,
 		Network:          "tcp",
 	}
@@ -90,15 +102,56 @@ func NewServer(srvCfg *ServerConfig) *Server {
 		wrapper, _ := gziphandler.NewGzipLevelAndMinSize(gzip.DefaultCompression, srvCfg.CompressMinBytes)
 		s.Handler = wrapper(srvCfg.Handler)
 	}
+	configureHTTP2(s, srvCfg)
 	return s
 }
 
+func configureHTTP2(s *Server, srvCfg *ServerConfig) {
+	if !srvCfg.EnableHTTP2 {
+		disableHTTP2(s)
+		return
+	}
+	h2s := &http2.Server{
+		MaxConcurrentStreams: srvCfg.MaxConcurrentStreams,
+		IdleTimeout:          srvCfg.IdleTimeout,
+	}
+	if s.TLSConfig == nil {
+		if srvCfg.EnableH2C && s.Handler != nil {
+			s.Handler = h2c.NewHandler(s.Handler, h2s)
+		}
+		return
+	}
+	if err := http2.ConfigureServer(s.Server, h2s); err != nil {
+		log.Errorf(err, "configure HTTP/2 failed, serve HTTP/1.1 only")
+		disableHTTP2(s)
+	}
+}
+
+func disableHTTP2(s *Server) {
+	// the empty map disables the automatic HTTP/2 of net/http
+	s.TLSNextProto = make(map[string]func(*http.Server, *tls.Conn, http.Handler))
+	if s.TLSConfig != nil {
+		s.TLSConfig.NextProtos = withoutProto(s.TLSConfig.NextProtos, http2.NextProtoTLS)
+	}
+}
+
+func withoutProto(protos []string, proto string) []string {
+	var l []string
+	for _, p := range protos {
+		if p != proto {
+			l = append(l, p)
+		}
+	}
+	return l
+}
+
 type Server struct {
 	*http.Server
 
 	Network          string
 	KeepaliveTimeout time.Duration
 	GraceTimeout     time.Duration
+	MaxConnections   int
 
 	Listener    net.Listener
 	netListener net.Listener
@@ -121,6 +174,10 @@ func (srv *Server) Serve() (err error) {
 	return
 }
 
+func (srv *Server) Full() bool {
+	return srv.MaxConnections > 0 && atomic.LoadInt64(&srv.conns) >= int64(srv.MaxConnections)
+}
+
 func (srv *Server) AcceptOne() {
 	defer log.Recover()
 	srv.wg.Add(1)
@@ -255,7 +312,17 @@ func (srv *Server) gracefulStop(d time.Duration) {
 		return
 	}
 
-	<-time.After(d)
+	// close the idle connections and wait for the in-flight requests
+	srv.SetKeepAlivesEnabled(false)
+	drained := make(chan struct{})
+	go func() {
+		srv.wg.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+	case <-time.After(d):
+	}
 
 	n := 0
 	for {
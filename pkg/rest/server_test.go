@@ -0,0 +1,186 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package rest
+
+import (
+	"bufio"
+	"golang.org/x/net/http2"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func startTestServer(t *testing.T, cfg *ServerConfig) *Server {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen failed, %v", err)
+	}
+	cfg.Addr = l.Addr().String()
+	cfg.GraceTimeout = 0
+	cfg.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strconv.Itoa(r.ProtoMajor)))
+	})
+	srv := NewServer(cfg)
+	srv.RegisterListener(l)
+	if err := srv.Listen(); err != nil {
+		t.Fatalf("listen failed, %v", err)
+	}
+	go srv.Serve()
+	return srv
+}
+
+// readServerSettings sends the client preface and returns the first
+// settings of the server
+func readServerSettings(conn net.Conn) (*http2.SettingsFrame, error) {
+	if _, err := conn.Write([]byte(http2.ClientPreface)); err != nil {
+		return nil, err
+	}
+	framer := http2.NewFramer(conn, conn)
+	if err := framer.WriteSettings(); err != nil {
+		return nil, err
+	}
+	for {
+		f, err := framer.ReadFrame()
+		if err != nil {
+			return nil, err
+		}
+		if sf, ok := f.(*http2.SettingsFrame); ok && !sf.IsAck() {
+			return sf, nil
+		}
+	}
+}
+
+func TestServer_H2C(t *testing.T) {
+	cfg := DefaultServerConfig()
+	cfg.EnableH2C = true
+	cfg.MaxConcurrentStreams = 7
+	srv := startTestServer(t, cfg)
+	defer srv.Shutdown()
+
+	// the upgrade from HTTP/1.1
+	conn, err := net.Dial("tcp", cfg.Addr)
+	if err != nil {
+		t.Fatalf("TestServer_H2C failed, %v", err)
+	}
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	_, err = conn.Write([]byte("GET / HTTP/1.1\r\nHost: " + cfg.Addr + "\r\n" +
+		"Connection: Upgrade, HTTP2-Settings\r\nUpgrade: h2c\r\nHTTP2-Settings: \r\n\r\n"))
+	if err != nil {
+		t.Fatalf("TestServer_H2C failed, %v", err)
+	}
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil || !strings.Contains(line, "101") {
+		t.Fatalf("TestServer_H2C upgrade failed, %s, %v", line, err)
+	}
+	conn.Close()
+
+	// the prior knowledge with the configured max concurrent streams
+	conn, err = net.Dial("tcp", cfg.Addr)
+	if err != nil {
+		t.Fatalf("TestServer_H2C failed, %v", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	settings, err := readServerSettings(conn)
+	if err != nil {
+		t.Fatalf("TestServer_H2C prior knowledge failed, %v", err)
+	}
+	if v, ok := settings.Value(http2.SettingMaxConcurrentStreams); !ok || v != 7 {
+		t.Fatalf("TestServer_H2C max concurrent streams failed, %d", v)
+	}
+}
+
+func TestServer_H2CDisabled(t *testing.T) {
+	cfg := DefaultServerConfig()
+	srv := startTestServer(t, cfg)
+	defer srv.Shutdown()
+
+	resp, err := http.Get("http://" + cfg.Addr)
+	if err != nil {
+		t.Fatalf("TestServer_H2CDisabled failed, %v", err)
+	}
+	resp.Body.Close()
+
+	conn, err := net.Dial("tcp", cfg.Addr)
+	if err != nil {
+		t.Fatalf("TestServer_H2CDisabled failed, %v", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	if _, err := readServerSettings(conn); err == nil {
+		t.Fatalf("TestServer_H2CDisabled failed, served HTTP/2 in cleartext")
+	}
+}
+
+func TestServer_MaxConnections(t *testing.T) {
+	cfg := DefaultServerConfig()
+	cfg.MaxConnections = 1
+	srv := startTestServer(t, cfg)
+	defer srv.Shutdown()
+
+	get := func(conn net.Conn) error {
+		conn.SetDeadline(time.Now().Add(5 * time.Second))
+		_, err := conn.Write([]byte("GET / HTTP/1.1\r\nHost: " + cfg.Addr + "\r\n\r\n"))
+		if err != nil {
+			return err
+		}
+		resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+
+	first, err := net.Dial("tcp", cfg.Addr)
+	if err != nil {
+		t.Fatalf("TestServer_MaxConnections failed, %v", err)
+	}
+	if err := get(first); err != nil {
+		t.Fatalf("TestServer_MaxConnections failed, %v", err)
+	}
+
+	// the connection over the cap is closed
+	second, err := net.Dial("tcp", cfg.Addr)
+	if err != nil {
+		t.Fatalf("TestServer_MaxConnections failed, %v", err)
+	}
+	if err := get(second); err == nil {
+		t.Fatalf("TestServer_MaxConnections failed, the connection over the cap is served")
+	}
+	second.Close()
+
+	// and accepted again after the first one is closed
+	first.Close()
+	for i := 0; ; i++ {
+		third, err := net.Dial("tcp", cfg.Addr)
+		if err != nil {
+			t.Fatalf("TestServer_MaxConnections failed, %v", err)
+		}
+		err = get(third)
+		third.Close()
+		if err == nil {
+			break
+		}
+		if i >= 50 {
+			t.Fatalf("TestServer_MaxConnections failed, the slot is not released, %v", err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
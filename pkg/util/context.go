@@ -27,8 +27,20 @@ const (
 	CtxProject       = "project"
 	CtxTargetDomain  = "target-domain"
 	CtxTargetProject = "target-project"
+	// CtxSpiffeId is the SPIFFE ID of the caller authenticated by mTLS
+	CtxSpiffeId = "spiffe-id"
+	// CtxServiceIdentity is the service name mapped from the caller identity,
+	// the caller may only register or update the service of the name
+	CtxServiceIdentity = "service-identity"
+	// CtxRequestId is the id to correlate the logs of one request, it is
+	// also the grpc metadata key so it must be lower case
+	CtxRequestId = "x-request-id"
+	// CtxApi is the name of the handler serving the request
+	CtxApi = "_server_match_func"
 )
 
+const maxRequestIdLength = 128
+
 type StringContext struct {
 	parentCtx context.Context
 	kv        *ConcurrentMap
@@ -157,6 +169,30 @@ func ParseTargetProject(ctx context.Context) string {
 	return v
 }
 
+func ParseApi(ctx context.Context) string {
+	v, _ := FromContext(ctx, CtxApi).(string)
+	return v
+}
+
+func ParseRequestId(ctx context.Context) string {
+	v, _ := FromContext(ctx, CtxRequestId).(string)
+	return v
+}
+
+// IsValidRequestId accepts the printable ascii id from the client which
+// can be written in the log lines and headers safely
+func IsValidRequestId(id string) bool {
+	if len(id) == 0 || len(id) > maxRequestIdLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
 func SetDomain(ctx context.Context, domain string) context.Context {
 	return SetContext(ctx, CtxDomain, domain)
 }
@@ -177,6 +213,10 @@ func SetDomainProject(ctx context.Context, domain string, project string) contex
 	return SetProject(SetDomain(ctx, domain), project)
 }
 
+func SetRequestId(ctx context.Context, id string) context.Context {
+	return SetContext(ctx, CtxRequestId, id)
+}
+
 func SetTargetDomainProject(ctx context.Context, domain string, project string) context.Context {
 	return SetTargetProject(SetTargetDomain(ctx, domain), project)
 }
@@ -19,6 +19,7 @@ package util
 import (
 	"context"
 	"net/http"
+	"strings"
 	"testing"
 )
 
@@ -75,3 +76,17 @@ func TestSetContext(t *testing.T) {
 		t.Fatalf("TestSetContext failed")
 	}
 }
+
+func TestIsValidRequestId(t *testing.T) {
+	if IsValidRequestId("") || IsValidRequestId("a b") || IsValidRequestId("a\nb") ||
+		IsValidRequestId(strings.Repeat("a", 129)) {
+		t.Fatalf("TestIsValidRequestId failed")
+	}
+	if !IsValidRequestId("0b2f3c1e-5a4d-4e8f-9c7b-1a2b3c4d5e6f") {
+		t.Fatalf("TestIsValidRequestId failed")
+	}
+	ctx := SetRequestId(context.Background(), "x")
+	if ParseRequestId(ctx) != "x" {
+		t.Fatalf("TestIsValidRequestId failed")
+	}
+}
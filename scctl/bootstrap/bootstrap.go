@@ -22,3 +22,12 @@ import _ "github.com/apache/servicecomb-service-center/scctl/pkg/plugin/get/inst
 import _ "github.com/apache/servicecomb-service-center/scctl/pkg/plugin/get/schema"
 import _ "github.com/apache/servicecomb-service-center/scctl/pkg/plugin/get/cluster"
 import _ "github.com/apache/servicecomb-service-center/scctl/pkg/plugin/health"
+import _ "github.com/apache/servicecomb-service-center/scctl/pkg/plugin/watch"
+import _ "github.com/apache/servicecomb-service-center/scctl/pkg/plugin/quota"
+import _ "github.com/apache/servicecomb-service-center/scctl/pkg/plugin/schema"
+import _ "github.com/apache/servicecomb-service-center/scctl/pkg/plugin/tag"
+import _ "github.com/apache/servicecomb-service-center/scctl/pkg/plugin/rule"
+import _ "github.com/apache/servicecomb-service-center/scctl/pkg/plugin/top"
+import _ "github.com/apache/servicecomb-service-center/scctl/pkg/plugin/migrate"
+import _ "github.com/apache/servicecomb-service-center/scctl/pkg/plugin/completion"
+import _ "github.com/apache/servicecomb-service-center/scctl/pkg/plugin/bench"
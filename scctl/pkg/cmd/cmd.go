@@ -20,6 +20,7 @@ import (
 	"github.com/apache/servicecomb-service-center/pkg/client/sc"
 	"github.com/apache/servicecomb-service-center/pkg/util"
 	"github.com/apache/servicecomb-service-center/scctl/pkg/version"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/writer"
 	"github.com/spf13/cobra"
 	"os"
 	"time"
@@ -36,6 +37,9 @@ var rootCmd = &cobra.Command{
 }
 var ScClientConfig sc.Config
 
+// Output is the output format shared by all commands, see writer.Formats
+var Output string
+
 func init() {
 	var timeout string
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "make the operation more talkative")
@@ -46,6 +50,9 @@ func init() {
 		if d, err := time.ParseDuration(timeout); err == nil && d > 0 {
 			ScClientConfig.RequestTimeout = d
 		}
+		if err := writer.CheckFormat(Output); err != nil {
+			StopAndExit(ExitError, err)
+		}
 	}
 
 	rootCmd.PersistentFlags().StringSliceVar(&ScClientConfig.Endpoints, "addr",
@@ -70,6 +77,9 @@ func init() {
 
 	rootCmd.PersistentFlags().StringVarP(&timeout, "timeout", "t", "10s",
 		"the maximum time allowed for the request.")
+
+	rootCmd.PersistentFlags().StringVarP(&Output, "output", "o", "",
+		"the output format, one of json, yaml or wide(the complete table).")
 }
 
 func RootCmd() *cobra.Command {
@@ -0,0 +1,82 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cmd
+
+import (
+	"errors"
+	"github.com/apache/servicecomb-service-center/pkg/client/sc"
+	"github.com/apache/servicecomb-service-center/server/core"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"github.com/spf13/pflag"
+	"golang.org/x/net/context"
+	"strings"
+)
+
+// ServiceFlags are the flags to specify a microservice, by the id or by
+// the app, name, version and environment
+type ServiceFlags struct {
+	Domain      string
+	ServiceId   string
+	AppId       string
+	ServiceName string
+	Version     string
+	Environment string
+}
+
+func (f *ServiceFlags) Bind(flags *pflag.FlagSet) {
+	flags.StringVarP(&f.Domain, "domain", "d", "default",
+		"the microservice under the specified domain, or the domain/project")
+	flags.StringVar(&f.ServiceId, "id", "", "the id of microservice, the other flags of microservice are ignored if set")
+	flags.StringVar(&f.AppId, "app", "default", "the application name of microservice")
+	flags.StringVar(&f.ServiceName, "name", "", "the name of microservice")
+	flags.StringVar(&f.Version, "version", "", "the semantic version of microservice")
+	flags.StringVar(&f.Environment, "env", "", "the environment of microservice")
+}
+
+func (f *ServiceFlags) DomainProject() string {
+	if strings.Contains(f.Domain, core.SPLIT) {
+		return f.Domain
+	}
+	return core.ToDomainProject(f.Domain, core.REGISTRY_PROJECT)
+}
+
+// Lookup returns the client and the id of the microservice, it exits if
+// the microservice does not exist
+func (f *ServiceFlags) Lookup(ctx context.Context) (scClient *sc.SCClient, domainProject, serviceId string) {
+	if len(f.ServiceId) == 0 && (len(f.ServiceName) == 0 || len(f.Version) == 0) {
+		StopAndExit(ExitError, errors.New("the microservice is required, use --id or --name and --version"))
+	}
+	domainProject = f.DomainProject()
+
+	scClient, err := sc.NewSCClient(ScClientConfig)
+	if err != nil {
+		StopAndExit(ExitError, err)
+	}
+	if len(f.ServiceId) > 0 {
+		return scClient, domainProject, f.ServiceId
+	}
+	serviceId, scErr := scClient.GetServiceIdByKey(ctx, domainProject, &pb.MicroServiceKey{
+		Environment: f.Environment,
+		AppId:       f.AppId,
+		ServiceName: f.ServiceName,
+		Version:     f.Version,
+	})
+	if scErr != nil {
+		StopAndExit(ExitError, scErr)
+	}
+	return
+}
@@ -37,14 +37,14 @@ func GetDomainProject(resouce interface{}) (domainProject string) {
 }
 
 type Service struct {
-	DomainProject string
-	Environment   string
-	AppId         string
-	ServiceName   string
-	Versions      []string
-	Frameworks    []*proto.FrameWorkProperty
-	Endpoints     []string
-	Timestamp     int64 // the seconds from 0 to now
+	DomainProject string                     `json:"domainProject"`
+	Environment   string                     `json:"environment,omitempty"`
+	AppId         string                     `json:"appId"`
+	ServiceName   string                     `json:"serviceName"`
+	Versions      []string                   `json:"versions"`
+	Frameworks    []*proto.FrameWorkProperty `json:"frameworks,omitempty"`
+	Endpoints     []string                   `json:"endpoints,omitempty"`
+	Timestamp     int64                      `json:"timestamp"` // the seconds from 0 to now
 }
 
 func (s *Service) AppendVersion(v string) {
@@ -82,16 +82,18 @@ func (s *Service) Age() time.Duration {
 }
 
 type Instance struct {
-	DomainProject string
-	Host          string
-	Endpoints     []string
-	Environment   string
-	AppId         string
-	ServiceName   string
-	Version       string
-	Framework     *proto.FrameWorkProperty
-	Lease         int64 // seconds
-	Timestamp     int64 // the seconds from 0 to now
+	DomainProject string                   `json:"domainProject"`
+	InstanceId    string                   `json:"instanceId"`
+	Host          string                   `json:"hostName"`
+	Endpoints     []string                 `json:"endpoints"`
+	Status        string                   `json:"status"`
+	Environment   string                   `json:"environment,omitempty"`
+	AppId         string                   `json:"appId"`
+	ServiceName   string                   `json:"serviceName"`
+	Version       string                   `json:"version"`
+	Framework     *proto.FrameWorkProperty `json:"framework,omitempty"`
+	Lease         int64                    `json:"lease"`     // seconds
+	Timestamp     int64                    `json:"timestamp"` // the seconds from 0 to now
 }
 
 func (s *Instance) SetLease(hc *proto.HealthCheck) {
@@ -0,0 +1,217 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package bench
+
+import (
+	"errors"
+	"fmt"
+	"github.com/apache/servicecomb-service-center/pkg/client/sc"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"golang.org/x/net/context"
+	"math/rand"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const benchAppId = "scctl-bench"
+
+type benchInstance struct {
+	ServiceId  string
+	InstanceId string
+}
+
+// Bencher registers the microservices and instances used by the workers,
+// the workers request the operations picked from the mix until the
+// duration is reached
+type Bencher struct {
+	Client        *sc.SCClient
+	DomainProject string
+	Mix           *Mix
+
+	// runId makes the microservices of each bench different
+	runId     string
+	services  []*pb.MicroService
+	lock      sync.RWMutex
+	instances []benchInstance
+	endpoints int64
+	stats     map[string]*Stats
+	elapsed   time.Duration
+}
+
+// Prepare creates the microservices and registers the instances of them
+func (b *Bencher) Prepare(ctx context.Context, services, instances int) *scerr.Error {
+	for i := 0; i < services; i++ {
+		service := &pb.MicroService{
+			AppId:       benchAppId,
+			ServiceName: "bench-" + b.runId + "-" + strconv.Itoa(i),
+			Version:     "1.0.0",
+		}
+		serviceId, scErr := b.Client.CreateService(ctx, b.DomainProject, service)
+		if scErr != nil {
+			return scErr
+		}
+		service.ServiceId = serviceId
+		b.services = append(b.services, service)
+	}
+
+	var (
+		wg    sync.WaitGroup
+		once  sync.Once
+		first *scerr.Error
+	)
+	for _, service := range b.services {
+		wg.Add(1)
+		go func(serviceId string) {
+			defer wg.Done()
+			for i := 0; i < instances; i++ {
+				if scErr := b.register(ctx, serviceId); scErr != nil {
+					once.Do(func() { first = scErr })
+					return
+				}
+			}
+		}(service.ServiceId)
+	}
+	wg.Wait()
+	return first
+}
+
+func (b *Bencher) register(ctx context.Context, serviceId string) *scerr.Error {
+	instance := &pb.MicroServiceInstance{
+		HostName: "bench-" + b.runId,
+		// the instances with the same endpoints are the same one
+		Endpoints: []string{fmt.Sprintf("rest://127.0.0.1:%d/", atomic.AddInt64(&b.endpoints, 1))},
+	}
+	instanceId, scErr := b.Client.RegisterInstance(ctx, b.DomainProject, serviceId, instance)
+	if scErr != nil {
+		return scErr
+	}
+	b.lock.Lock()
+	b.instances = append(b.instances, benchInstance{ServiceId: serviceId, InstanceId: instanceId})
+	b.lock.Unlock()
+	return nil
+}
+
+// Run starts the workers and blocks until the duration is reached
+func (b *Bencher) Run(ctx context.Context, concurrency int, d time.Duration) {
+	ctx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			b.work(ctx, rand.New(rand.NewSource(seed)))
+		}(start.UnixNano() + int64(i))
+	}
+	wg.Wait()
+	b.elapsed = time.Since(start)
+}
+
+func (b *Bencher) work(ctx context.Context, r *rand.Rand) {
+	var rev int64
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+		op := b.Mix.Pick(r)
+		start := time.Now()
+		err := b.do(ctx, r, op, &rev)
+		if ctx.Err() != nil {
+			// the request is cancelled by the end of the bench
+			return
+		}
+		b.stats[op].Add(time.Since(start), err)
+	}
+}
+
+func (b *Bencher) do(ctx context.Context, r *rand.Rand, op string, rev *int64) error {
+	var scErr *scerr.Error
+	switch op {
+	case OpRegister:
+		scErr = b.register(ctx, b.services[r.Intn(len(b.services))].ServiceId)
+	case OpHeartbeat:
+		b.lock.RLock()
+		instance := b.instances[r.Intn(len(b.instances))]
+		b.lock.RUnlock()
+		scErr = b.Client.Heartbeat(ctx, b.DomainProject, instance.ServiceId, instance.InstanceId)
+	case OpFind:
+		consumer := b.services[r.Intn(len(b.services))]
+		provider := b.services[r.Intn(len(b.services))]
+		_, scErr = b.Client.FindInstances(ctx, b.DomainProject, consumer.ServiceId, &pb.MicroServiceKey{
+			AppId:       provider.AppId,
+			ServiceName: provider.ServiceName,
+			Version:     "latest",
+		})
+	case OpWatch:
+		var resp *pb.GetWatchEventsResponse
+		resp, scErr = b.Client.GetWatchEvents(ctx, b.DomainProject, *rev)
+		switch {
+		case scErr == nil:
+			*rev = resp.Revision
+		case scErr.Code == scerr.ErrRevisionExpired:
+			// too slow to poll, start from the latest revision again
+			*rev, scErr = 0, nil
+		}
+	default:
+		return errors.New("unknown operation " + op)
+	}
+	if scErr != nil {
+		return scErr
+	}
+	return nil
+}
+
+// Clean deletes the microservices together with their instances
+func (b *Bencher) Clean(ctx context.Context) {
+	for _, service := range b.services {
+		if scErr := b.Client.DeleteService(ctx, b.DomainProject, service.ServiceId, true); scErr != nil {
+			fmt.Fprintf(os.Stderr, "warning: delete microservice %s failed, %s\n", service.ServiceId, scErr.Detail)
+		}
+	}
+}
+
+// Reports returns the reports of the operations in the mix
+func (b *Bencher) Reports() (reports []*Report) {
+	for _, op := range Operations {
+		if s, ok := b.stats[op]; ok {
+			reports = append(reports, s.Report(op, b.elapsed))
+		}
+	}
+	return
+}
+
+func NewBencher(client *sc.SCClient, domainProject string, mix *Mix) *Bencher {
+	stats := make(map[string]*Stats, len(mix.ops))
+	for _, op := range mix.ops {
+		stats[op] = &Stats{}
+	}
+	return &Bencher{
+		Client:        client,
+		DomainProject: domainProject,
+		Mix:           mix,
+		runId:         strconv.FormatInt(time.Now().Unix(), 36),
+		stats:         stats,
+	}
+}
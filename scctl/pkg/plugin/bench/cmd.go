@@ -0,0 +1,105 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package bench
+
+import (
+	"fmt"
+	"github.com/apache/servicecomb-service-center/pkg/client/sc"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/cmd"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/writer"
+	"github.com/apache/servicecomb-service-center/server/core"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+	"os"
+	"strings"
+	"time"
+)
+
+const defaultMix = "register=1,heartbeat=80,find=18,watch=1"
+
+var (
+	Domain      string
+	Services    int
+	Instances   int
+	Concurrency int
+	Duration    time.Duration
+	MixFlag     string
+	Keep        bool
+)
+
+func init() {
+	NewBenchCommand(cmd.RootCmd())
+}
+
+func NewBenchCommand(parent *cobra.Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bench [options]",
+		Short: "Drive the mix of register, heartbeat, find and watch requests and output the latencies",
+		Run:   BenchCommandFunc,
+	}
+
+	parent.AddCommand(cmd)
+	cmd.Flags().StringVarP(&Domain, "domain", "d", "default",
+		"register the microservices under the specified domain, or the domain/project")
+	cmd.Flags().IntVar(&Services, "services", 10, "the number of the microservices registered before the bench")
+	cmd.Flags().IntVar(&Instances, "instances", 10, "the number of the instances of each microservice registered before the bench")
+	cmd.Flags().IntVarP(&Concurrency, "concurrency", "c", 10, "the number of the workers sending requests")
+	cmd.Flags().DurationVar(&Duration, "duration", 30*time.Second, "the duration of the bench")
+	cmd.Flags().StringVar(&MixFlag, "mix", defaultMix,
+		"the weights of the operations in format 'op=weight,...', op is one of register, heartbeat, find and watch")
+	cmd.Flags().BoolVar(&Keep, "keep", false, "keep the microservices and instances after the bench")
+	return cmd
+}
+
+func BenchCommandFunc(_ *cobra.Command, args []string) {
+	mix, err := ParseMix(MixFlag)
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+	if Services < 1 || Instances < 1 || Concurrency < 1 || Duration <= 0 {
+		cmd.StopAndExit(cmd.ExitError, fmt.Errorf("services, instances, concurrency and duration must be positive"))
+	}
+	domainProject := Domain
+	if !strings.Contains(domainProject, core.SPLIT) {
+		domainProject = core.ToDomainProject(Domain, core.REGISTRY_PROJECT)
+	}
+
+	scClient, err := sc.NewSCClient(cmd.ScClientConfig)
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+
+	b := NewBencher(scClient, domainProject, mix)
+	ctx := context.Background()
+	fmt.Fprintf(os.Stderr, "registering %d microservices and %d instances ...\n", Services, Services*Instances)
+	scErr := b.Prepare(ctx, Services, Instances)
+	if scErr == nil {
+		fmt.Fprintf(os.Stderr, "running %d workers for %s ...\n", Concurrency, Duration)
+		b.Run(ctx, Concurrency, Duration)
+	}
+	if !Keep {
+		b.Clean(ctx)
+	}
+	if scErr != nil {
+		cmd.StopAndExit(cmd.ExitError, scErr)
+	}
+
+	reports := b.Reports()
+	if err := writer.Print(cmd.Output, reports, &BenchPrinter{Reports: reports}); err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+}
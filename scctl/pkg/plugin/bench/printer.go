@@ -0,0 +1,57 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package bench
+
+import (
+	"github.com/apache/servicecomb-service-center/scctl/pkg/writer"
+	"strconv"
+)
+
+var benchTableHeader = []string{"OPERATION", "REQUESTS", "ERRORS", "QPS", "P50(MS)", "P90(MS)", "P99(MS)", "MAX(MS)"}
+
+// BenchPrinter prints the latency percentiles of each operation
+type BenchPrinter struct {
+	Reports []*Report
+	flags   []interface{}
+}
+
+func (bp *BenchPrinter) Flags(flags ...interface{}) []interface{} {
+	if len(flags) > 0 {
+		bp.flags = flags
+	}
+	return bp.flags
+}
+
+func (bp *BenchPrinter) PrintBody() (slice [][]string) {
+	for _, r := range bp.Reports {
+		slice = append(slice, []string{r.Operation, strconv.Itoa(r.Requests), strconv.Itoa(r.Errors),
+			formatFloat(r.QPS), formatFloat(r.P50), formatFloat(r.P90), formatFloat(r.P99), formatFloat(r.Max)})
+	}
+	return
+}
+
+func (bp *BenchPrinter) PrintTitle() []string {
+	return benchTableHeader
+}
+
+func (bp *BenchPrinter) Sorter() *writer.RecordsSorter {
+	return nil
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', 1, 64)
+}
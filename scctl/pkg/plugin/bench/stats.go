@@ -0,0 +1,155 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package bench
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	OpRegister  = "register"
+	OpHeartbeat = "heartbeat"
+	OpFind      = "find"
+	OpWatch     = "watch"
+)
+
+// Operations is the order of the operations in the report
+var Operations = []string{OpRegister, OpHeartbeat, OpFind, OpWatch}
+
+// Mix is the weighted operations the workers pick from
+type Mix struct {
+	ops     []string
+	weights []int
+	total   int
+}
+
+// Pick returns an operation by the weights
+func (m *Mix) Pick(r *rand.Rand) string {
+	n := r.Intn(m.total)
+	for i, w := range m.weights {
+		if n < w {
+			return m.ops[i]
+		}
+		n -= w
+	}
+	return m.ops[len(m.ops)-1]
+}
+
+// ParseMix parses the mix in format 'op=weight,...', the operations not in
+// the mix are not requested
+func ParseMix(s string) (*Mix, error) {
+	m := &Mix{}
+	for _, kv := range strings.Split(s, ",") {
+		arr := strings.SplitN(strings.TrimSpace(kv), "=", 2)
+		if len(arr) != 2 || !isOperation(arr[0]) {
+			return nil, fmt.Errorf("invalid mix '%s', the format is 'op=weight' and op is one of %v", kv, Operations)
+		}
+		w, err := strconv.Atoi(arr[1])
+		if err != nil || w < 0 {
+			return nil, fmt.Errorf("invalid weight of '%s', it must be a non-negative integer", arr[0])
+		}
+		if w == 0 {
+			continue
+		}
+		m.ops = append(m.ops, arr[0])
+		m.weights = append(m.weights, w)
+		m.total += w
+	}
+	if m.total == 0 {
+		return nil, fmt.Errorf("invalid mix '%s', no operation to request", s)
+	}
+	return m, nil
+}
+
+func isOperation(op string) bool {
+	for _, o := range Operations {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+// Stats collects the latencies of an operation
+type Stats struct {
+	lock      sync.Mutex
+	latencies []time.Duration
+	errors    int
+}
+
+func (s *Stats) Add(latency time.Duration, err error) {
+	s.lock.Lock()
+	s.latencies = append(s.latencies, latency)
+	if err != nil {
+		s.errors++
+	}
+	s.lock.Unlock()
+}
+
+// Report summarizes the stats, the latencies are in milliseconds
+func (s *Stats) Report(op string, elapsed time.Duration) *Report {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	sort.Slice(s.latencies, func(i, j int) bool { return s.latencies[i] < s.latencies[j] })
+	r := &Report{
+		Operation: op,
+		Requests:  len(s.latencies),
+		Errors:    s.errors,
+		P50:       toMillis(percentile(s.latencies, 0.5)),
+		P90:       toMillis(percentile(s.latencies, 0.9)),
+		P99:       toMillis(percentile(s.latencies, 0.99)),
+		Max:       toMillis(percentile(s.latencies, 1)),
+	}
+	if elapsed > 0 {
+		r.QPS = float64(r.Requests) / elapsed.Seconds()
+	}
+	return r
+}
+
+type Report struct {
+	Operation string  `json:"operation"`
+	Requests  int     `json:"requests"`
+	Errors    int     `json:"errors"`
+	QPS       float64 `json:"qps"`
+	P50       float64 `json:"p50"`
+	P90       float64 `json:"p90"`
+	P99       float64 `json:"p99"`
+	Max       float64 `json:"max"`
+}
+
+// percentile returns the nearest rank of the sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if i < 0 {
+		i = 0
+	}
+	return sorted[i]
+}
+
+func toMillis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,64 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package bench
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestParseMix(t *testing.T) {
+	m, err := ParseMix("register=1, heartbeat=3,find=0")
+	if err != nil || m.total != 4 || len(m.ops) != 2 {
+		t.Fatalf("TestParseMix failed, %v %v", m, err)
+	}
+	counts := map[string]int{}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		counts[m.Pick(r)]++
+	}
+	if counts[OpFind] != 0 || counts[OpHeartbeat] < counts[OpRegister]*2 {
+		t.Fatalf("TestParseMix failed, %v", counts)
+	}
+
+	for _, s := range []string{"", "find=0", "get=1", "find", "find=-1", "find=x"} {
+		if _, err := ParseMix(s); err == nil {
+			t.Fatalf("TestParseMix failed, %s", s)
+		}
+	}
+}
+
+func TestStats_Report(t *testing.T) {
+	s := &Stats{}
+	if r := s.Report(OpFind, time.Second); r.Requests != 0 || r.P99 != 0 {
+		t.Fatalf("TestStats_Report failed, %v", r)
+	}
+	for i := 100; i > 0; i-- {
+		var err error
+		if i%10 == 0 {
+			err = errors.New("error")
+		}
+		s.Add(time.Duration(i)*time.Millisecond, err)
+	}
+	r := s.Report(OpFind, 2*time.Second)
+	if r.Requests != 100 || r.Errors != 10 || r.QPS != 50 ||
+		r.P50 != 50 || r.P90 != 90 || r.P99 != 99 || r.Max != 100 {
+		t.Fatalf("TestStats_Report failed, %v", r)
+	}
+}
@@ -0,0 +1,67 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package completion
+
+import (
+	"fmt"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/cmd"
+	"github.com/spf13/cobra"
+	"os"
+)
+
+const (
+	ShellBash = "bash"
+	ShellZsh  = "zsh"
+)
+
+func init() {
+	NewCompletionCommand(cmd.RootCmd())
+}
+
+func NewCompletionCommand(parent *cobra.Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "completion <bash|zsh>",
+		Short: "Output the shell completion script of the tool",
+		Long: "Output the shell completion script of the tool, e.g.\n" +
+			"  source <(scctl completion bash)\n" +
+			"  scctl completion zsh > \"${fpath[1]}/_scctl\"",
+		ValidArgs: []string{ShellBash, ShellZsh},
+		Run:       CompletionCommandFunc,
+	}
+
+	parent.AddCommand(cmd)
+	return cmd
+}
+
+func CompletionCommandFunc(c *cobra.Command, args []string) {
+	if len(args) != 1 {
+		cmd.StopAndExit(cmd.ExitError, fmt.Errorf("the shell is required, %s or %s", ShellBash, ShellZsh))
+	}
+
+	var err error
+	switch args[0] {
+	case ShellBash:
+		err = c.Root().GenBashCompletion(os.Stdout)
+	case ShellZsh:
+		err = c.Root().GenZshCompletion(os.Stdout)
+	default:
+		err = fmt.Errorf("unsupported shell '%s', expect %s or %s", args[0], ShellBash, ShellZsh)
+	}
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+}
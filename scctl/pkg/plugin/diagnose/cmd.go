@@ -23,7 +23,15 @@ import (
 	"path/filepath"
 )
 
-var EtcdClientConfig etcd.Config
+const (
+	SourceEtcd = "etcd"
+	SourceSC   = "sc"
+)
+
+var (
+	EtcdClientConfig etcd.Config
+	Source           string
+)
 
 func init() {
 	root.RootCmd().AddCommand(NewDiagnoseCommand(root.RootCmd()))
@@ -32,11 +40,15 @@ func init() {
 func NewDiagnoseCommand(parent *cobra.Command) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "diagnose [options]",
+		Aliases: []string{"diag"},
 		Short:   "Output the service center diagnostic report",
 		Run:     DiagnoseCommandFunc,
 		Example: parent.CommandPath() + ` diagnose --addr "http://127.0.0.1:30100" --etcd-addr "http://127.0.0.1:2379";`,
 	}
 
+	cmd.Flags().StringVar(&Source, "source", SourceEtcd,
+		"where the backend data is read, 'etcd' reads from the etcd-addr directly, "+
+			"'sc' reads by the admin API of service center")
 	cmd.Flags().StringVar(&EtcdClientConfig.Addrs, "etcd-addr",
 		util.GetEnvString("CSE_REGISTRY_ADDRESS", "http://127.0.0.1:2379"),
 		"the http addr and port of etcd endpoints")
@@ -49,7 +49,7 @@ type CompareResult struct {
 
 type abstractCompareHolder struct {
 	Cache        model.Getter
-	DataStore    *DataStore
+	DataStore    model.Getter
 	MismatchFunc func(v *model.KV) string
 }
 
@@ -129,11 +129,17 @@ type ServiceCompareHolder struct {
 	*abstractCompareHolder
 	Cache model.MicroserviceSlice
 	Kvs   []*mvccpb.KeyValue
+	// Backend is compared instead of the Kvs if it is not nil
+	Backend *model.MicroserviceSlice
 }
 
 func (h *ServiceCompareHolder) Compare() *CompareResult {
+	var store model.Getter = &DataStore{Data: h.Kvs, DataParser: pb.ServiceParser}
+	if h.Backend != nil {
+		store = h.Backend
+	}
 	h.abstractCompareHolder = &abstractCompareHolder{
-		Cache: &h.Cache, DataStore: &DataStore{Data: h.Kvs, DataParser: pb.ServiceParser}, MismatchFunc: h.toName,
+		Cache: &h.Cache, DataStore: store, MismatchFunc: h.toName,
 	}
 	r := h.abstractCompareHolder.Compare()
 	r.Name = service
@@ -151,11 +157,17 @@ type InstanceCompareHolder struct {
 	*abstractCompareHolder
 	Cache model.InstanceSlice
 	Kvs   []*mvccpb.KeyValue
+	// Backend is compared instead of the Kvs if it is not nil
+	Backend *model.InstanceSlice
 }
 
 func (h *InstanceCompareHolder) Compare() *CompareResult {
+	var store model.Getter = &DataStore{Data: h.Kvs, DataParser: pb.InstanceParser}
+	if h.Backend != nil {
+		store = h.Backend
+	}
 	h.abstractCompareHolder = &abstractCompareHolder{
-		Cache: &h.Cache, DataStore: &DataStore{Data: h.Kvs, DataParser: pb.InstanceParser}, MismatchFunc: h.toName,
+		Cache: &h.Cache, DataStore: store, MismatchFunc: h.toName,
 	}
 	r := h.abstractCompareHolder.Compare()
 	r.Name = instance
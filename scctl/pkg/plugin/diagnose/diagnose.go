@@ -21,6 +21,7 @@ import (
 	"github.com/apache/servicecomb-service-center/pkg/client/etcd"
 	"github.com/apache/servicecomb-service-center/pkg/client/sc"
 	"github.com/apache/servicecomb-service-center/scctl/pkg/cmd"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/writer"
 	"github.com/apache/servicecomb-service-center/server/admin/model"
 	"github.com/coreos/etcd/clientv3"
 	"github.com/coreos/etcd/mvcc/mvccpb"
@@ -52,6 +53,10 @@ func DiagnoseCommandFunc(_ *cobra.Command, args []string) {
 	if err != nil {
 		cmd.StopAndExit(cmd.ExitError, err)
 	}
+	if Source == SourceSC {
+		diagnoseBySC(scClient)
+		return
+	}
 	etcdClient, err := etcd.NewEtcdClient(EtcdClientConfig)
 	if err != nil {
 		cmd.StopAndExit(cmd.ExitError, err)
@@ -71,7 +76,39 @@ func DiagnoseCommandFunc(_ *cobra.Command, args []string) {
 	}
 
 	// diagnose go...
-	err, details := diagnose(cache, etcdResp)
+	output(etcdHolders(cache, etcdResp)...)
+}
+
+// diagnoseBySC compares the cache with the backend both dumped by service
+// center, the etcd is not accessed directly
+func diagnoseBySC(scClient *sc.SCClient) {
+	cache, scErr := scClient.GetScCache(context.Background())
+	if scErr != nil {
+		cmd.StopAndExit(cmd.ExitError, scErr)
+	}
+	backendCache, scErr := scClient.GetBackendCache(context.Background())
+	if scErr != nil {
+		cmd.StopAndExit(cmd.ExitError, scErr)
+	}
+
+	output(backendHolders(cache, backendCache)...)
+}
+
+// output prints the report in json or yaml if the output format is
+// structured, otherwise prints the details of the differences
+func output(holders ...CompareHolder) {
+	if writer.IsStructured(cmd.Output) {
+		report := NewReport(compareAll(holders...)...)
+		if err := writer.PrintResult(cmd.Output, report, ""); err != nil {
+			cmd.StopAndExit(cmd.ExitError, err)
+		}
+		if !report.Passed {
+			cmd.StopAndExit(cmd.ExitError)
+		}
+		return
+	}
+
+	err, details := compare(holders...)
 	if err != nil {
 		fmt.Println(details)                // stdout
 		cmd.StopAndExit(cmd.ExitError, err) // stderr
@@ -98,19 +135,43 @@ func setResponse(ctx context.Context, etcdClient *clientv3.Client, key, prefix s
 }
 
 func diagnose(cache *model.Cache, etcdResp etcdResponse) (err error, details string) {
-	var (
-		service  = ServiceCompareHolder{Cache: cache.Microservices, Kvs: etcdResp[service]}
-		instance = InstanceCompareHolder{Cache: cache.Instances, Kvs: etcdResp[instance]}
-	)
+	return compare(etcdHolders(cache, etcdResp)...)
+}
+
+func diagnoseBackend(cache, backendCache *model.Cache) (err error, details string) {
+	return compare(backendHolders(cache, backendCache)...)
+}
+
+func etcdHolders(cache *model.Cache, etcdResp etcdResponse) []CompareHolder {
+	return []CompareHolder{
+		&ServiceCompareHolder{Cache: cache.Microservices, Kvs: etcdResp[service]},
+		&InstanceCompareHolder{Cache: cache.Instances, Kvs: etcdResp[instance]},
+	}
+}
+
+func backendHolders(cache, backendCache *model.Cache) []CompareHolder {
+	return []CompareHolder{
+		&ServiceCompareHolder{Cache: cache.Microservices, Backend: &backendCache.Microservices},
+		&InstanceCompareHolder{Cache: cache.Instances, Backend: &backendCache.Instances},
+	}
+}
+
+func compareAll(holders ...CompareHolder) []*CompareResult {
+	results := make([]*CompareResult, 0, len(holders))
+	for _, h := range holders {
+		results = append(results, h.Compare())
+	}
+	return results
+}
 
-	sr := service.Compare()
-	ir := instance.Compare()
+func compare(holders ...CompareHolder) (err error, details string) {
+	results := compareAll(holders...)
 
 	var (
 		b    bytes.Buffer
 		full bytes.Buffer
 	)
-	writeResult(&b, &full, sr, ir)
+	writeResult(&b, &full, results...)
 	if b.Len() > 0 {
 		return fmt.Errorf("error: %s", b.String()), full.String()
 	}
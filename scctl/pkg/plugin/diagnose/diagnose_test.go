@@ -63,4 +63,30 @@ func TestNewDiagnoseCommand(t *testing.T) {
 	fmt.Println(err)
 	fmt.Println(details)
 	//}
+
+	backend := model.MicroserviceSlice{
+		model.NewMicroservice(&model.KV{Key: "2", Rev: 2, Value: &proto.MicroService{ServiceId: "22"}}),
+		model.NewMicroservice(&model.KV{Key: "4", Rev: 2, Value: &proto.MicroService{ServiceId: "4"}}),
+	}
+	err, details = diagnoseBackend(&model.Cache{Microservices: services, Instances: instances},
+		&model.Cache{Microservices: backend})
+	if err == nil || len(details) == 0 {
+		t.Fatalf("TestNewDiagnoseCommand failed")
+	}
+
+	err, details = diagnoseBackend(&model.Cache{Microservices: backend}, &model.Cache{Microservices: backend})
+	if err != nil || len(details) != 0 {
+		t.Fatalf("TestNewDiagnoseCommand failed, %v", err)
+	}
+
+	report := NewReport(compareAll(etcdHolders(&model.Cache{Microservices: services, Instances: instances},
+		etcdResponse{service: kvs})...)...)
+	if report.Passed || len(report.OnlyInCache[instance]) == 0 || len(report.OnlyInBackend[service]) == 0 {
+		t.Fatalf("TestNewDiagnoseCommand failed, %v", report)
+	}
+	report = NewReport(compareAll(backendHolders(&model.Cache{Microservices: backend},
+		&model.Cache{Microservices: backend})...)...)
+	if !report.Passed {
+		t.Fatalf("TestNewDiagnoseCommand failed, %v", report)
+	}
 }
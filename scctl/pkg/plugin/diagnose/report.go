@@ -0,0 +1,48 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package diagnose
+
+// Report is the structured output of diagnose command, the differences
+// are grouped by the resource type, e.g. service or instance
+type Report struct {
+	Passed        bool                `json:"passed"`
+	OnlyInCache   map[string][]string `json:"onlyInCache,omitempty"`
+	Different     map[string][]string `json:"different,omitempty"`
+	OnlyInBackend map[string][]string `json:"onlyInBackend,omitempty"`
+}
+
+func NewReport(rss ...*CompareResult) *Report {
+	report := &Report{
+		OnlyInCache:   make(map[string][]string),
+		Different:     make(map[string][]string),
+		OnlyInBackend: make(map[string][]string),
+	}
+	for _, rs := range rss {
+		for t, arr := range rs.Results {
+			switch t {
+			case greater:
+				report.OnlyInCache[rs.Name] = arr
+			case mismatch:
+				report.Different[rs.Name] = arr
+			case less:
+				report.OnlyInBackend[rs.Name] = arr
+			}
+		}
+	}
+	report.Passed = len(report.OnlyInCache) == 0 && len(report.Different) == 0 && len(report.OnlyInBackend) == 0
+	return report
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/apache/servicecomb-service-center/scctl/pkg/writer"
 	"github.com/spf13/cobra"
 	"golang.org/x/net/context"
+	"sort"
 )
 
 func init() {
@@ -49,12 +50,19 @@ func ClusterCommandFunc(_ *cobra.Command, args []string) {
 		cmd.StopAndExit(cmd.ExitError, scErr)
 	}
 	records := make(map[string]*ClusterRecord)
+	list := make([]*ClusterRecord, 0, len(clusters))
 	for name, endpoints := range clusters {
 		records[name] = &ClusterRecord{
 			Name: name, Endpoints: endpoints,
 		}
+		list = append(list, records[name])
 	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
 	sp := &ClustersPrinter{Records: records}
-	sp.SetOutputFormat(get.Output, get.AllDomains)
-	writer.PrintTable(sp)
+	sp.SetOutputFormat(cmd.Output, get.AllDomains)
+	if err := writer.Print(cmd.Output, list, sp); err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
 }
@@ -25,8 +25,8 @@ var (
 )
 
 type ClusterRecord struct {
-	Name      string
-	Endpoints []string
+	Name      string   `json:"name"`
+	Endpoints []string `json:"endpoints"`
 }
 
 func (s *ClusterRecord) EndpointsString() string {
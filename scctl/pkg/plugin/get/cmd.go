@@ -22,7 +22,6 @@ import (
 
 var (
 	Domain     string
-	Output     string
 	AllDomains bool
 	RootCmd    *cobra.Command
 )
@@ -38,7 +37,6 @@ func NewGetCommand(parent *cobra.Command) *cobra.Command {
 	}
 	parent.AddCommand(cmd)
 	cmd.PersistentFlags().StringVarP(&Domain, "domain", "d", "default", "print the information under the specified domain in service center")
-	cmd.PersistentFlags().StringVarP(&Output, "output", "o", "", "output the complete microservice information(e.g., framework, endpoints)")
 	cmd.PersistentFlags().BoolVar(&AllDomains, "all-domains", false, "print the information under all domains in service center")
 
 	return cmd
@@ -25,9 +25,12 @@ import (
 	"github.com/apache/servicecomb-service-center/server/core"
 	"github.com/spf13/cobra"
 	"golang.org/x/net/context"
+	"sort"
 	"strings"
 )
 
+var ServiceName string
+
 func init() {
 	NewInstanceCommand(get.RootCmd)
 }
@@ -35,11 +38,12 @@ func init() {
 func NewInstanceCommand(parent *cobra.Command) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "instance [options]",
-		Aliases: []string{"inst"},
+		Aliases: []string{"inst", "instances"},
 		Short:   "Output the instance information of the service center ",
 		Run:     InstanceCommandFunc,
 	}
 	parent.AddCommand(cmd)
+	cmd.Flags().StringVar(&ServiceName, "service", "", "print the instances of the specified microservice name")
 	return cmd
 }
 
@@ -66,7 +70,7 @@ func InstanceCommandFunc(_ *cobra.Command, args []string) {
 		}
 
 		svc, ok := svcMap[inst.Value.ServiceId]
-		if !ok {
+		if !ok || (len(ServiceName) > 0 && svc.Value.ServiceName != ServiceName) {
 			continue
 		}
 
@@ -75,8 +79,10 @@ func InstanceCommandFunc(_ *cobra.Command, args []string) {
 			instance = &InstanceRecord{
 				Instance: model.Instance{
 					DomainProject: domainProject,
+					InstanceId:    inst.Value.InstanceId,
 					Host:          inst.Value.HostName,
 					Endpoints:     inst.Value.Endpoints,
+					Status:        inst.Value.Status,
 					Environment:   svc.Value.Environment,
 					AppId:         svc.Value.AppId,
 					ServiceName:   svc.Value.ServiceName,
@@ -90,7 +96,20 @@ func InstanceCommandFunc(_ *cobra.Command, args []string) {
 		instance.UpdateTimestamp(inst.Value.Timestamp)
 	}
 
+	instances := make([]model.Instance, 0, len(records))
+	for _, record := range records {
+		instances = append(instances, record.Instance)
+	}
+	sort.Slice(instances, func(i, j int) bool {
+		if instances[i].ServiceName != instances[j].ServiceName {
+			return instances[i].ServiceName < instances[j].ServiceName
+		}
+		return instances[i].InstanceId < instances[j].InstanceId
+	})
+
 	sp := &InstancePrinter{Records: records}
-	sp.SetOutputFormat(get.Output, get.AllDomains)
-	writer.PrintTable(sp)
+	sp.SetOutputFormat(cmd.Output, get.AllDomains)
+	if err := writer.Print(cmd.Output, instances, sp); err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
 }
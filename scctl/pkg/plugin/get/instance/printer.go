@@ -67,7 +67,7 @@ func (s *InstanceRecord) Domain() string {
 
 func (s *InstanceRecord) PrintBody(fmt string, all bool) []string {
 	switch {
-	case fmt == "wide":
+	case fmt == writer.OutputWide:
 		return []string{s.Domain(), s.Host, s.EndpointsString(), s.Version, s.ServiceName, s.AppId, s.Environment,
 			s.FrameworksString(), s.LeaseString(), s.AgeString()}
 	case all:
@@ -104,7 +104,7 @@ func (sp *InstancePrinter) PrintBody() (slice [][]string) {
 
 func (sp *InstancePrinter) PrintTitle() []string {
 	switch {
-	case sp.flags[0] == "wide":
+	case sp.flags[0] == writer.OutputWide:
 		return longInstanceTableHeader
 	case sp.flags[1].(bool):
 		return domainInstanceTableHeader
@@ -22,8 +22,10 @@ import (
 	"github.com/apache/servicecomb-service-center/scctl/pkg/model"
 	"github.com/apache/servicecomb-service-center/scctl/pkg/plugin/get"
 	"github.com/apache/servicecomb-service-center/scctl/pkg/progress-bar"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/writer"
 	adminModel "github.com/apache/servicecomb-service-center/server/admin/model"
 	"github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/core/proto"
 	"github.com/spf13/cobra"
 	"golang.org/x/net/context"
 	"io"
@@ -33,6 +35,16 @@ import (
 	"strings"
 )
 
+// SchemaRecord is the structured output of the schemas of a microservice
+type SchemaRecord struct {
+	DomainProject string          `json:"domainProject"`
+	ServiceId     string          `json:"serviceId"`
+	AppId         string          `json:"appId"`
+	ServiceName   string          `json:"serviceName"`
+	Version       string          `json:"version"`
+	Schemas       []*proto.Schema `json:"schemas"`
+}
+
 var (
 	AppId       string
 	ServiceName string
@@ -86,13 +98,13 @@ func SchemaCommandFunc(_ *cobra.Command, args []string) {
 		cmd.StopAndExit(cmd.ExitError, scErr)
 	}
 
+	structured := writer.IsStructured(cmd.Output)
 	var progressBarWriter io.Writer = os.Stdout
-	if len(SaveDir) == 0 {
+	if len(SaveDir) == 0 || structured {
 		progressBarWriter = ioutil.Discard
 	}
 	progressBar := pb.NewProgressBar(len(cache.Microservices), progressBarWriter)
-	defer progressBar.FinishPrint("Finished.")
-
+	records := make([]*SchemaRecord, 0)
 	for _, ms := range cache.Microservices {
 		progressBar.Increment()
 
@@ -118,9 +130,31 @@ func SchemaCommandFunc(_ *cobra.Command, args []string) {
 			continue
 		}
 
-		writer := NewSchemaWriter(Config{SaveDir: saveDirectory(SaveDir, ms)})
-		if err := writer.Write(schemas); err != nil {
+		if structured {
+			records = append(records, &SchemaRecord{
+				DomainProject: domainProject,
+				ServiceId:     ms.Value.ServiceId,
+				AppId:         ms.Value.AppId,
+				ServiceName:   ms.Value.ServiceName,
+				Version:       ms.Value.Version,
+				Schemas:       schemas,
+			})
+			if len(SaveDir) == 0 {
+				continue
+			}
+		}
+
+		w := NewSchemaWriter(Config{SaveDir: saveDirectory(SaveDir, ms)})
+		if err := w.Write(schemas); err != nil {
 			fmt.Fprintln(os.Stderr, "output schema data failed", err.Error())
 		}
 	}
+
+	if structured {
+		if err := writer.PrintResult(cmd.Output, records, ""); err != nil {
+			cmd.StopAndExit(cmd.ExitError, err)
+		}
+		return
+	}
+	progressBar.FinishPrint("Finished.")
 }
@@ -25,6 +25,7 @@ import (
 	"github.com/apache/servicecomb-service-center/server/core"
 	"github.com/spf13/cobra"
 	"golang.org/x/net/context"
+	"sort"
 	"strings"
 )
 
@@ -90,7 +91,20 @@ func ServiceCommandFunc(_ *cobra.Command, args []string) {
 		svc.UpdateTimestamp(ms.Value.Timestamp)
 	}
 
+	services := make([]model.Service, 0, len(records))
+	for _, record := range records {
+		services = append(services, record.Service)
+	}
+	sort.Slice(services, func(i, j int) bool {
+		if services[i].DomainProject != services[j].DomainProject {
+			return services[i].DomainProject < services[j].DomainProject
+		}
+		return services[i].ServiceName < services[j].ServiceName
+	})
+
 	sp := &ServicePrinter{Records: records}
-	sp.SetOutputFormat(get.Output, get.AllDomains)
-	writer.PrintTable(sp)
+	sp.SetOutputFormat(cmd.Output, get.AllDomains)
+	if err := writer.Print(cmd.Output, services, sp); err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
 }
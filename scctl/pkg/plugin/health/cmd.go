@@ -18,6 +18,7 @@ package health
 import (
 	"github.com/apache/servicecomb-service-center/pkg/client/sc"
 	"github.com/apache/servicecomb-service-center/scctl/pkg/cmd"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/writer"
 	scerr "github.com/apache/servicecomb-service-center/server/error"
 	"github.com/spf13/cobra"
 	"golang.org/x/net/context"
@@ -49,7 +50,7 @@ func HealthCommandFunc(_ *cobra.Command, args []string) {
 	if err != nil {
 		cmd.StopAndExit(ExistInternal, err)
 	}
-	scErr := scClient.HealthCheck(context.Background())
+	instances, scErr := scClient.ClusterHealth(context.Background())
 	if scErr != nil {
 		switch scErr.Code {
 		case scerr.ErrUnavailableBackend:
@@ -58,4 +59,8 @@ func HealthCommandFunc(_ *cobra.Command, args []string) {
 			cmd.StopAndExit(ExistAbnormal, scErr)
 		}
 	}
+
+	if err := writer.Print(cmd.Output, instances, &HealthPrinter{Instances: instances}); err != nil {
+		cmd.StopAndExit(ExistInternal, err)
+	}
 }
@@ -0,0 +1,57 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package health
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/model"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/writer"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+)
+
+var healthTableHeader = []string{"HOST", "ENDPOINTS", "VERSION", "STATUS", "AGE"}
+
+// HealthPrinter prints the instances of the service center cluster
+type HealthPrinter struct {
+	Instances []*pb.MicroServiceInstance
+	flags     []interface{}
+}
+
+func (hp *HealthPrinter) Flags(flags ...interface{}) []interface{} {
+	if len(flags) > 0 {
+		hp.flags = flags
+	}
+	return hp.flags
+}
+
+func (hp *HealthPrinter) PrintBody() (slice [][]string) {
+	for _, inst := range hp.Instances {
+		record := &model.Instance{}
+		record.UpdateTimestamp(inst.Timestamp)
+		slice = append(slice, []string{inst.HostName, util.StringJoin(inst.Endpoints, "\n"),
+			inst.Version, inst.Status, writer.TimeFormat(record.Age())})
+	}
+	return
+}
+
+func (hp *HealthPrinter) PrintTitle() []string {
+	return healthTableHeader
+}
+
+func (hp *HealthPrinter) Sorter() *writer.RecordsSorter {
+	return nil
+}
@@ -0,0 +1,70 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package migrate
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/apache/servicecomb-service-center/server/admin/model"
+	"io/ioutil"
+	"os"
+)
+
+// Checkpoint records the domains migrated from the source to the target,
+// the migration continues from it after a failure
+type Checkpoint struct {
+	From    string                         `json:"from"`
+	To      string                         `json:"to"`
+	Domains map[string]*model.ImportResult `json:"domains"`
+}
+
+// LoadCheckpoint reads the checkpoint file, a new checkpoint is returned
+// if the file does not exist
+func LoadCheckpoint(file, from, to string) (*Checkpoint, error) {
+	cp := &Checkpoint{From: from, To: to, Domains: make(map[string]*model.ImportResult)}
+	data, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return cp, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, cp); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint file %s, %s", file, err)
+	}
+	if cp.From != from || cp.To != to {
+		return nil, fmt.Errorf("checkpoint file %s belongs to the migration from %s to %s", file, cp.From, cp.To)
+	}
+	if cp.Domains == nil {
+		cp.Domains = make(map[string]*model.ImportResult)
+	}
+	return cp, nil
+}
+
+// Save writes the checkpoint to a temporary file and renames it, so the
+// file is complete if the command is interrupted
+func (cp *Checkpoint) Save(file string) error {
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := file + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0640); err != nil {
+		return err
+	}
+	return os.Rename(tmp, file)
+}
@@ -0,0 +1,223 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"github.com/apache/servicecomb-service-center/pkg/client/sc"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/cmd"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/writer"
+	"github.com/apache/servicecomb-service-center/server/admin/model"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+	"net/url"
+	"os"
+	"time"
+)
+
+var (
+	From           string
+	To             string
+	Domains        []string
+	Policy         string
+	CheckpointFile string
+	DryRun         bool
+)
+
+func init() {
+	NewMigrateCommand(cmd.RootCmd())
+}
+
+func NewMigrateCommand(parent *cobra.Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate [options]",
+		Short: "Migrate the resources from a service center cluster to another one",
+		Long: "Migrate the resources from a service center cluster to another one domain by domain, " +
+			"the migrated domains are saved to the checkpoint file and skipped when the command runs again",
+		Run: MigrateCommandFunc,
+	}
+
+	parent.AddCommand(cmd)
+	cmd.Flags().StringVar(&From, "from", "", "the http addr of the source service center")
+	cmd.Flags().StringVar(&To, "to", "", "the http addr of the target service center")
+	cmd.Flags().StringSliceVar(&Domains, "domains", nil, "the domains to migrate, all the domains by default")
+	cmd.Flags().StringVar(&Policy, "policy", model.IMPORT_POLICY_SKIP,
+		"the way to handle the existing resources in the target, skip, overwrite or fail")
+	cmd.Flags().StringVar(&CheckpointFile, "checkpoint", "migrate.checkpoint.json",
+		"the file to save the migrated domains")
+	cmd.Flags().BoolVar(&DryRun, "dry-run", false, "report the changes in the target without migrating")
+	return cmd
+}
+
+func MigrateCommandFunc(_ *cobra.Command, args []string) {
+	source, err := newClient(From)
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, "invalid --from:", err)
+	}
+	target, err := newClient(To)
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, "invalid --to:", err)
+	}
+
+	ctx := context.Background()
+	domains := Domains
+	if len(domains) == 0 {
+		all, scErr := source.ListDomains(ctx)
+		if scErr != nil {
+			cmd.StopAndExit(cmd.ExitError, scErr)
+		}
+		for _, d := range all {
+			domains = append(domains, d.Name)
+		}
+	}
+
+	cp := &Checkpoint{From: From, To: To, Domains: make(map[string]*model.ImportResult)}
+	if !DryRun {
+		if cp, err = LoadCheckpoint(CheckpointFile, From, To); err != nil {
+			cmd.StopAndExit(cmd.ExitError, err)
+		}
+	}
+
+	m := &Migrator{Source: source, Target: target}
+	for _, domain := range domains {
+		if _, ok := cp.Domains[domain]; ok {
+			fmt.Fprintf(os.Stderr, "domain '%s' is skipped, it is migrated according to %s\n", domain, CheckpointFile)
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "migrating domain '%s' ...\n", domain)
+		result, err := m.Migrate(ctx, domain, &model.ImportRequest{Policy: Policy, DryRun: DryRun})
+		if err != nil {
+			cmd.StopAndExit(cmd.ExitError, fmt.Sprintf("migrate domain '%s' failed:", domain), err)
+		}
+		cp.Domains[domain] = result
+		if DryRun {
+			continue
+		}
+		if err := cp.Save(CheckpointFile); err != nil {
+			cmd.StopAndExit(cmd.ExitError, err)
+		}
+	}
+
+	if DryRun {
+		printReport(&ReportPrinter{Results: cp.Domains, Domains: domains})
+		return
+	}
+
+	// verify by importing the latest resources of the source in dry run,
+	// the target is complete if nothing would be created
+	fmt.Fprintln(os.Stderr, "verifying ...")
+	missing := make(map[string]*model.ImportResult, len(domains))
+	for _, domain := range domains {
+		result, err := m.Migrate(ctx, domain, &model.ImportRequest{Policy: model.IMPORT_POLICY_SKIP, DryRun: true})
+		if err != nil {
+			cmd.StopAndExit(cmd.ExitError, fmt.Sprintf("verify domain '%s' failed:", domain), err)
+		}
+		missing[domain] = result
+	}
+	report := &ReportPrinter{Results: cp.Domains, Domains: domains, Missing: missing}
+	printReport(report)
+	if !report.Passed() {
+		cmd.StopAndExit(cmd.ExitError, errors.New("verification failed, some resources are missing in the target"))
+	}
+}
+
+func printReport(rp *ReportPrinter) {
+	if err := writer.Print(cmd.Output, rp.Report(), rp); err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+}
+
+// newClient returns the client of the service center, only one endpoint
+// is used as the export job is kept by the instance creating it
+func newClient(addr string) (*sc.SCClient, error) {
+	u, err := url.Parse(addr)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "http", "https":
+	case "":
+		return nil, errors.New("the http addr of service center is required")
+	default:
+		return nil, fmt.Errorf("migrating the '%s' backend directly is not supported, "+
+			"migrate between the service centers running on the backends instead", u.Scheme)
+	}
+	cfg := cmd.ScClientConfig
+	cfg.Endpoints = []string{addr}
+	return sc.NewSCClient(cfg)
+}
+
+// Migrator exports a domain from the source and imports it to the target
+type Migrator struct {
+	Source *sc.SCClient
+	Target *sc.SCClient
+	// Interval is the interval to check whether the export finishes
+	Interval time.Duration
+}
+
+func (m *Migrator) Migrate(ctx context.Context, domain string, in *model.ImportRequest) (*model.ImportResult, error) {
+	interval := m.Interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	job, scErr := m.Source.CreateExport(ctx, &model.ExportRequest{Domains: []string{domain}})
+	if scErr != nil {
+		return nil, scErr
+	}
+	for job.Status == model.EXPORT_STATUS_RUNNING {
+		<-time.After(interval)
+		if job, scErr = m.Source.GetExport(ctx, job.Id); scErr != nil {
+			return nil, scErr
+		}
+	}
+	if job.Status != model.EXPORT_STATUS_SUCCEEDED {
+		return nil, fmt.Errorf("export[%s] %s, %s", job.Id, job.Status, job.Error)
+	}
+
+	dump, scErr := m.Source.DownloadExport(ctx, job.Id)
+	if scErr != nil {
+		return nil, scErr
+	}
+	in.Format = job.Format
+	result, scErr := m.Target.Import(ctx, in, dump)
+	if scErr != nil {
+		return nil, scErr
+	}
+	for _, e := range result.Errors {
+		fmt.Fprintln(os.Stderr, "warning:", e)
+	}
+	if in.DryRun {
+		return result, nil
+	}
+	return result, m.migrateQuotas(ctx, domain)
+}
+
+// migrateQuotas copies the quotas of the domain, they are not in the dump
+func (m *Migrator) migrateQuotas(ctx context.Context, domain string) error {
+	d, scErr := m.Source.GetDomain(ctx, domain)
+	if scErr != nil {
+		return scErr
+	}
+	if len(d.Quotas) == 0 {
+		return nil
+	}
+	if _, scErr := m.Target.UpdateDomainQuotas(ctx, domain, d.Quotas); scErr != nil {
+		return scErr
+	}
+	return nil
+}
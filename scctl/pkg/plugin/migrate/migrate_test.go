@@ -0,0 +1,72 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package migrate
+
+import (
+	"github.com/apache/servicecomb-service-center/server/admin/model"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckpoint(t *testing.T) {
+	dir, err := ioutil.TempDir("", "migrate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "checkpoint.json")
+
+	cp, err := LoadCheckpoint(file, "http://a", "http://b")
+	if err != nil || len(cp.Domains) != 0 {
+		t.Fatalf("TestCheckpoint failed, %v", err)
+	}
+	cp.Domains["d1"] = &model.ImportResult{Counts: map[string]*model.ImportCount{
+		model.EXPORT_TYPE_SERVICE:  {Created: 1},
+		model.EXPORT_TYPE_INSTANCE: {Skipped: 2},
+	}}
+	if err := cp.Save(file); err != nil {
+		t.Fatalf("TestCheckpoint failed, %v", err)
+	}
+
+	cp, err = LoadCheckpoint(file, "http://a", "http://b")
+	if err != nil || cp.Domains["d1"] == nil || sum(cp.Domains["d1"]).Created != 1 ||
+		sum(cp.Domains["d1"]).Skipped != 0 {
+		t.Fatalf("TestCheckpoint failed, %v", err)
+	}
+	if _, err := LoadCheckpoint(file, "http://a", "http://c"); err == nil {
+		t.Fatalf("TestCheckpoint failed")
+	}
+}
+
+func TestReportPrinter_Passed(t *testing.T) {
+	rp := &ReportPrinter{Domains: []string{"d1"}, Missing: map[string]*model.ImportResult{
+		"d1": {Counts: map[string]*model.ImportCount{model.EXPORT_TYPE_SERVICE: {Skipped: 1}}},
+	}}
+	if !rp.Passed() || len(rp.PrintBody()[0]) != len(rp.PrintTitle()) {
+		t.Fatalf("TestReportPrinter_Passed failed")
+	}
+	rp.Missing["d1"].Counts[model.EXPORT_TYPE_SCHEMA] = &model.ImportCount{Created: 1}
+	if rp.Passed() {
+		t.Fatalf("TestReportPrinter_Passed failed")
+	}
+	report := rp.Report()
+	if report.Passed || report.DryRun || len(report.Domains) != 1 || *report.Domains[0].Missing != 1 {
+		t.Fatalf("TestReportPrinter_Passed failed")
+	}
+}
@@ -0,0 +1,135 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package migrate
+
+import (
+	"github.com/apache/servicecomb-service-center/scctl/pkg/writer"
+	"github.com/apache/servicecomb-service-center/server/admin/model"
+	"strconv"
+)
+
+const (
+	resultPassed = "PASSED"
+	resultFailed = "FAILED"
+)
+
+var reportTableHeader = []string{"DOMAIN", "CREATED", "OVERWRITTEN", "SKIPPED", "CONFLICTS"}
+
+// ReportPrinter prints the import counts of the domains, the instances are
+// not counted as they register again, the missing are the resources would
+// be created in the verification
+type ReportPrinter struct {
+	Domains []string
+	Results map[string]*model.ImportResult
+	Missing map[string]*model.ImportResult
+	flags   []interface{}
+}
+
+func (rp *ReportPrinter) Flags(flags ...interface{}) []interface{} {
+	if len(flags) > 0 {
+		rp.flags = flags
+	}
+	return rp.flags
+}
+
+// Report is the structured output of migrate command
+type Report struct {
+	DryRun  bool            `json:"dryRun,omitempty"`
+	Passed  bool            `json:"passed"`
+	Domains []*DomainReport `json:"domains"`
+}
+
+type DomainReport struct {
+	Name   string              `json:"name"`
+	Result *model.ImportResult `json:"result,omitempty"`
+	// Missing is the number of the resources not found in the target
+	// after the migration, it is omitted in dry run
+	Missing *int64 `json:"missing,omitempty"`
+}
+
+// Report converts the results to the structured output
+func (rp *ReportPrinter) Report() *Report {
+	report := &Report{DryRun: rp.Missing == nil, Passed: rp.Passed(),
+		Domains: make([]*DomainReport, 0, len(rp.Domains))}
+	for _, domain := range rp.Domains {
+		dr := &DomainReport{Name: domain, Result: rp.Results[domain]}
+		if rp.Missing != nil {
+			missing := sum(rp.Missing[domain]).Created
+			dr.Missing = &missing
+		}
+		report.Domains = append(report.Domains, dr)
+	}
+	return report
+}
+
+func (rp *ReportPrinter) PrintBody() (slice [][]string) {
+	for _, domain := range rp.Domains {
+		var c model.ImportCount
+		if result, ok := rp.Results[domain]; ok {
+			c = sum(result)
+		}
+		record := []string{domain, strconv.FormatInt(c.Created, 10), strconv.FormatInt(c.Overwritten, 10),
+			strconv.FormatInt(c.Skipped, 10), strconv.FormatInt(c.Conflicts, 10)}
+		if rp.Missing != nil {
+			missing := sum(rp.Missing[domain]).Created
+			result := resultPassed
+			if missing > 0 {
+				result = resultFailed
+			}
+			record = append(record, strconv.FormatInt(missing, 10), result)
+		}
+		slice = append(slice, record)
+	}
+	return
+}
+
+func (rp *ReportPrinter) PrintTitle() []string {
+	if rp.Missing != nil {
+		return append(reportTableHeader, "MISSING", "RESULT")
+	}
+	return reportTableHeader
+}
+
+func (rp *ReportPrinter) Sorter() *writer.RecordsSorter {
+	return nil
+}
+
+// Passed returns true if no resource is missing in the target
+func (rp *ReportPrinter) Passed() bool {
+	for _, result := range rp.Missing {
+		if sum(result).Created > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func sum(result *model.ImportResult) (c model.ImportCount) {
+	if result == nil {
+		return
+	}
+	for t, count := range result.Counts {
+		if t == model.EXPORT_TYPE_INSTANCE {
+			continue
+		}
+		c.Created += count.Created
+		c.Overwritten += count.Overwritten
+		c.Skipped += count.Skipped
+		c.Conflicts += count.Conflicts
+	}
+	return
+}
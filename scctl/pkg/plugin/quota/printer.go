@@ -0,0 +1,78 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package quota
+
+import (
+	"github.com/apache/servicecomb-service-center/scctl/pkg/writer"
+	"github.com/apache/servicecomb-service-center/server/admin/model"
+	"strconv"
+)
+
+var quotaTableHeader = []string{"RESOURCE", "QUOTA", "DOMAIN USED"}
+
+// QuotaPrinter prints the quotas and the usage of the domain, a quota
+// of '-' means only the global quota is checked
+type QuotaPrinter struct {
+	Domain  *model.Domain
+	Project *model.Project
+	flags   []interface{}
+}
+
+func (qp *QuotaPrinter) Flags(flags ...interface{}) []interface{} {
+	if len(flags) > 0 {
+		qp.flags = flags
+	}
+	return qp.flags
+}
+
+func (qp *QuotaPrinter) PrintBody() (slice [][]string) {
+	resources := []struct {
+		Name    string
+		Domain  int64
+		Project int64
+	}{
+		{model.DOMAIN_QUOTA_SERVICE, qp.Domain.Counts.Services, 0},
+		{model.DOMAIN_QUOTA_INSTANCE, qp.Domain.Counts.Instances, 0},
+	}
+	if qp.Project != nil {
+		resources[0].Project = qp.Project.Counts.Services
+		resources[1].Project = qp.Project.Counts.Instances
+	}
+	for _, res := range resources {
+		quota := "-"
+		if q := qp.Domain.Quotas[res.Name]; q > 0 {
+			quota = strconv.FormatInt(q, 10)
+		}
+		record := []string{res.Name, quota, strconv.FormatInt(res.Domain, 10)}
+		if qp.Project != nil {
+			record = append(record, strconv.FormatInt(res.Project, 10))
+		}
+		slice = append(slice, record)
+	}
+	return
+}
+
+func (qp *QuotaPrinter) PrintTitle() []string {
+	if qp.Project != nil {
+		return append(quotaTableHeader, "PROJECT USED")
+	}
+	return quotaTableHeader
+}
+
+func (qp *QuotaPrinter) Sorter() *writer.RecordsSorter {
+	return nil
+}
@@ -0,0 +1,95 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package quota
+
+import (
+	"errors"
+	"fmt"
+	"github.com/apache/servicecomb-service-center/pkg/client/sc"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/cmd"
+	"github.com/apache/servicecomb-service-center/server/admin/model"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+	"os"
+)
+
+var (
+	ServiceQuota  int64
+	InstanceQuota int64
+)
+
+func NewSetCommand(parent *cobra.Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set [options]",
+		Short: "Change the quotas of the domain, it takes effect immediately",
+		Run:   SetCommandFunc,
+	}
+
+	parent.AddCommand(cmd)
+	cmd.Flags().Int64Var(&ServiceQuota, "service", 0,
+		"the max number of the microservices in the domain, 0 means only the global quota is checked")
+	cmd.Flags().Int64Var(&InstanceQuota, "instance", 0,
+		"the max number of the instances in the domain, 0 means only the global quota is checked")
+	return cmd
+}
+
+func SetCommandFunc(c *cobra.Command, args []string) {
+	flags := map[string]*int64{
+		model.DOMAIN_QUOTA_SERVICE:  &ServiceQuota,
+		model.DOMAIN_QUOTA_INSTANCE: &InstanceQuota,
+	}
+	changed := make(map[string]int64)
+	for name, value := range flags {
+		if c.Flags().Changed(name) {
+			changed[name] = *value
+		}
+	}
+	if len(changed) == 0 {
+		cmd.StopAndExit(cmd.ExitError, errors.New("no quota to set, use --service or --instance"))
+	}
+
+	scClient, err := sc.NewSCClient(cmd.ScClientConfig)
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+	ctx := context.Background()
+	domain, scErr := scClient.GetDomain(ctx, Domain)
+	if scErr != nil {
+		cmd.StopAndExit(cmd.ExitError, scErr)
+	}
+
+	// the quotas not specified are kept
+	quotas := make(map[string]int64, len(domain.Quotas)+len(changed))
+	for name, value := range domain.Quotas {
+		quotas[name] = value
+	}
+	for name, value := range changed {
+		if value == 0 {
+			delete(quotas, name)
+			continue
+		}
+		quotas[name] = value
+	}
+	if _, scErr := scClient.UpdateDomainQuotas(ctx, Domain, quotas); scErr != nil {
+		cmd.StopAndExit(cmd.ExitError, scErr)
+	}
+	fmt.Fprintf(os.Stderr, "quotas of domain '%s' are changed from %v to %v\n", Domain, domain.Quotas, quotas)
+
+	// print the usage with the new quotas
+	domain.Quotas = quotas
+	printDomain(domain, nil)
+}
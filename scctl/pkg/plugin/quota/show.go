@@ -0,0 +1,73 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package quota
+
+import (
+	"fmt"
+	"github.com/apache/servicecomb-service-center/pkg/client/sc"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/cmd"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/writer"
+	"github.com/apache/servicecomb-service-center/server/admin/model"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+)
+
+var Project string
+
+func NewShowCommand(parent *cobra.Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show [options]",
+		Short: "Output the quotas and the usage of the domain",
+		Run:   ShowCommandFunc,
+	}
+
+	parent.AddCommand(cmd)
+	cmd.Flags().StringVar(&Project, "project", "", "output the usage of the specified project as well")
+	return cmd
+}
+
+func ShowCommandFunc(_ *cobra.Command, args []string) {
+	scClient, err := sc.NewSCClient(cmd.ScClientConfig)
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+	domain, scErr := scClient.GetDomain(context.Background(), Domain)
+	if scErr != nil {
+		cmd.StopAndExit(cmd.ExitError, scErr)
+	}
+
+	var project *model.Project
+	if len(Project) > 0 {
+		for _, p := range domain.Projects {
+			if p.Name == Project {
+				project = p
+				break
+			}
+		}
+		if project == nil {
+			cmd.StopAndExit(cmd.ExitError, fmt.Errorf("project '%s' does not exist in domain '%s'", Project, Domain))
+		}
+		domain.Projects = []*model.Project{project}
+	}
+	printDomain(domain, project)
+}
+
+func printDomain(domain *model.Domain, project *model.Project) {
+	if err := writer.Print(cmd.Output, domain, &QuotaPrinter{Domain: domain, Project: project}); err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+}
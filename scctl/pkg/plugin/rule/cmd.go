@@ -0,0 +1,181 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package rule
+
+import (
+	"errors"
+	"fmt"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/cmd"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/writer"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/net/context"
+	"strings"
+)
+
+var (
+	Service cmd.ServiceFlags
+	Rule    pb.AddOrUpdateServiceRule
+)
+
+// Result is the structured output of add and rm commands
+type Result struct {
+	ServiceId string   `json:"serviceId"`
+	RuleIds   []string `json:"ruleIds"`
+}
+
+func init() {
+	NewRuleCommand(cmd.RootCmd())
+}
+
+func NewRuleCommand(parent *cobra.Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rule <command> [options]",
+		Short: "Manage the black or white list rules of the microservice",
+	}
+	parent.AddCommand(cmd)
+	Service.Bind(cmd.PersistentFlags())
+
+	list := &cobra.Command{
+		Use:   "list [options]",
+		Short: "Output the rules of the microservice",
+		Run:   ListCommandFunc,
+	}
+	cmd.AddCommand(list)
+
+	add := &cobra.Command{
+		Use:   "add [options]",
+		Short: "Add the rule to the microservice",
+		Run:   AddCommandFunc,
+	}
+	bindRuleFlags(add.Flags())
+	cmd.AddCommand(add)
+
+	test := &cobra.Command{
+		Use:   "test [options]",
+		Short: "Check the rule by adding it in dry run, nothing is changed",
+		Run:   TestCommandFunc,
+	}
+	bindRuleFlags(test.Flags())
+	cmd.AddCommand(test)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:     "rm <ruleId>... [options]",
+		Aliases: []string{"remove"},
+		Short:   "Remove the rules from the microservice",
+		Run:     RemoveCommandFunc,
+	})
+	return cmd
+}
+
+func bindRuleFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&Rule.RuleType, "type", "", "the rule type, WHITE or BLACK")
+	flags.StringVar(&Rule.Attribute, "attribute", "",
+		"the attribute of the consumer to match, 'serviceName' or 'tag_{key}'")
+	flags.StringVar(&Rule.Pattern, "pattern", "", "the regular expression to match the attribute")
+	flags.StringVar(&Rule.Description, "description", "", "the description of the rule")
+}
+
+// checkRule checks the required fields only, the rule is validated by
+// service center
+func checkRule() *pb.AddOrUpdateServiceRule {
+	Rule.RuleType = strings.ToUpper(Rule.RuleType)
+	if len(Rule.RuleType) == 0 || len(Rule.Attribute) == 0 || len(Rule.Pattern) == 0 {
+		cmd.StopAndExit(cmd.ExitError, errors.New("the rule is required, use --type, --attribute and --pattern"))
+	}
+	return &Rule
+}
+
+func ListCommandFunc(_ *cobra.Command, args []string) {
+	ctx := context.Background()
+	scClient, domainProject, serviceId := Service.Lookup(ctx)
+	rules, scErr := scClient.GetRules(ctx, domainProject, serviceId)
+	if scErr != nil {
+		cmd.StopAndExit(cmd.ExitError, scErr)
+	}
+
+	if rules == nil {
+		rules = []*pb.ServiceRule{}
+	}
+	if err := writer.Print(cmd.Output, rules, &RulePrinter{Rules: rules}); err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+}
+
+func AddCommandFunc(_ *cobra.Command, args []string) {
+	rule := checkRule()
+
+	ctx := context.Background()
+	scClient, domainProject, serviceId := Service.Lookup(ctx)
+	ruleIds, scErr := scClient.AddRules(ctx, domainProject, serviceId, []*pb.AddOrUpdateServiceRule{rule})
+	if scErr != nil {
+		cmd.StopAndExit(cmd.ExitError, scErr)
+	}
+	message := fmt.Sprintf("the rule already exists in microservice %s", serviceId)
+	if len(ruleIds) > 0 {
+		message = fmt.Sprintf("rule %s is added to microservice %s", ruleIds[0], serviceId)
+	}
+	if ruleIds == nil {
+		ruleIds = []string{}
+	}
+	if err := writer.PrintResult(cmd.Output, &Result{ServiceId: serviceId, RuleIds: ruleIds}, message); err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+}
+
+func TestCommandFunc(_ *cobra.Command, args []string) {
+	rule := checkRule()
+
+	ctx := context.Background()
+	scClient, domainProject, serviceId := Service.Lookup(ctx)
+	resp, scErr := scClient.DryRunAddRules(ctx, domainProject, serviceId, []*pb.AddOrUpdateServiceRule{rule})
+	if scErr != nil {
+		cmd.StopAndExit(cmd.ExitError, scErr)
+	}
+	if writer.IsStructured(cmd.Output) {
+		if err := writer.PrintResult(cmd.Output, resp, ""); err != nil {
+			cmd.StopAndExit(cmd.ExitError, err)
+		}
+		return
+	}
+	if len(resp.Changes) == 0 {
+		fmt.Printf("the rule already exists in microservice %s\n", serviceId)
+		return
+	}
+	fmt.Printf("the rule can be added to microservice %s, the changes are:\n", serviceId)
+	for _, change := range resp.Changes {
+		fmt.Printf("  %-6s %s\n", change.Action, change.Key)
+	}
+}
+
+func RemoveCommandFunc(_ *cobra.Command, args []string) {
+	if len(args) == 0 {
+		cmd.StopAndExit(cmd.ExitError, "the ids of the rules are required")
+	}
+
+	ctx := context.Background()
+	scClient, domainProject, serviceId := Service.Lookup(ctx)
+	if scErr := scClient.DeleteRules(ctx, domainProject, serviceId, args); scErr != nil {
+		cmd.StopAndExit(cmd.ExitError, scErr)
+	}
+	err := writer.PrintResult(cmd.Output, &Result{ServiceId: serviceId, RuleIds: args},
+		fmt.Sprintf("%d rules are removed from microservice %s", len(args), serviceId))
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+}
@@ -0,0 +1,55 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package rule
+
+import (
+	"github.com/apache/servicecomb-service-center/scctl/pkg/model"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/writer"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+)
+
+var ruleTableHeader = []string{"ID", "TYPE", "ATTRIBUTE", "PATTERN", "DESCRIPTION", "AGE"}
+
+type RulePrinter struct {
+	Rules []*pb.ServiceRule
+	flags []interface{}
+}
+
+func (rp *RulePrinter) Flags(flags ...interface{}) []interface{} {
+	if len(flags) > 0 {
+		rp.flags = flags
+	}
+	return rp.flags
+}
+
+func (rp *RulePrinter) PrintBody() (slice [][]string) {
+	for _, rule := range rp.Rules {
+		record := &model.Service{}
+		record.UpdateTimestamp(rule.Timestamp)
+		slice = append(slice, []string{rule.RuleId, rule.RuleType, rule.Attribute, rule.Pattern,
+			rule.Description, writer.TimeFormat(record.Age())})
+	}
+	return
+}
+
+func (rp *RulePrinter) PrintTitle() []string {
+	return ruleTableHeader
+}
+
+func (rp *RulePrinter) Sorter() *writer.RecordsSorter {
+	return nil
+}
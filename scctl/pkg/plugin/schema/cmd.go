@@ -0,0 +1,61 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package schema
+
+import (
+	"github.com/apache/servicecomb-service-center/scctl/pkg/cmd"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"github.com/spf13/cobra"
+)
+
+var (
+	Service cmd.ServiceFlags
+	Dir     string
+)
+
+// Result is the structured output of pull and push commands
+type Result struct {
+	ServiceId string   `json:"serviceId"`
+	Dir       string   `json:"dir"`
+	SchemaIds []string `json:"schemaIds"`
+}
+
+func NewResult(serviceId string, schemas []*pb.Schema) *Result {
+	r := &Result{ServiceId: serviceId, Dir: Dir, SchemaIds: make([]string, 0, len(schemas))}
+	for _, schema := range schemas {
+		r.SchemaIds = append(r.SchemaIds, schema.SchemaId)
+	}
+	return r
+}
+
+func init() {
+	NewSchemaCommand(cmd.RootCmd())
+}
+
+func NewSchemaCommand(parent *cobra.Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schema <command> [options]",
+		Short: "Download or upload the schemas of the microservice",
+	}
+	parent.AddCommand(cmd)
+	Service.Bind(cmd.PersistentFlags())
+	cmd.PersistentFlags().StringVar(&Dir, "dir", ".", "the local directory of the schema files")
+
+	NewPullCommand(cmd)
+	NewPushCommand(cmd)
+	return cmd
+}
@@ -0,0 +1,57 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package schema
+
+import (
+	"fmt"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/cmd"
+	getschema "github.com/apache/servicecomb-service-center/scctl/pkg/plugin/get/schema"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/writer"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+)
+
+func NewPullCommand(parent *cobra.Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pull [options]",
+		Short: "Download all the schemas of the microservice to the local directory",
+		Run:   PullCommandFunc,
+	}
+
+	parent.AddCommand(cmd)
+	return cmd
+}
+
+func PullCommandFunc(_ *cobra.Command, args []string) {
+	ctx := context.Background()
+	scClient, domainProject, serviceId := Service.Lookup(ctx)
+
+	schemas, scErr := scClient.GetSchemasByServiceId(ctx, domainProject, serviceId)
+	if scErr != nil {
+		cmd.StopAndExit(cmd.ExitError, scErr)
+	}
+	// the schema files are named ${schemaId}.yaml
+	w := getschema.NewSchemaWriter(getschema.Config{SaveDir: Dir})
+	if err := w.Write(schemas); err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+	err := writer.PrintResult(cmd.Output, NewResult(serviceId, schemas),
+		fmt.Sprintf("%d schemas of microservice %s are saved to %s", len(schemas), serviceId, Dir))
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+}
@@ -0,0 +1,104 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package schema
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/cmd"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/writer"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// schemaExts are the extensions of the OpenAPI files to upload
+var schemaExts = map[string]bool{".yaml": true, ".yml": true, ".json": true}
+
+func NewPushCommand(parent *cobra.Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "push [options]",
+		Short: "Upload the OpenAPI files in the local directory as the schemas of the microservice",
+		Long: "Upload the OpenAPI files in the local directory as the schemas of the microservice, " +
+			"the file name without extension is the schema id, and the schemas not in the directory are deleted",
+		Run: PushCommandFunc,
+	}
+
+	parent.AddCommand(cmd)
+	return cmd
+}
+
+func PushCommandFunc(_ *cobra.Command, args []string) {
+	schemas, err := ReadSchemas(Dir)
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+	if len(schemas) == 0 {
+		cmd.StopAndExit(cmd.ExitError, fmt.Errorf("no schema file found in %s", Dir))
+	}
+
+	ctx := context.Background()
+	scClient, domainProject, serviceId := Service.Lookup(ctx)
+	if scErr := scClient.PutSchemas(ctx, domainProject, serviceId, schemas); scErr != nil {
+		cmd.StopAndExit(cmd.ExitError, scErr)
+	}
+	err = writer.PrintResult(cmd.Output, NewResult(serviceId, schemas),
+		fmt.Sprintf("%d schemas are uploaded to microservice %s", len(schemas), serviceId))
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+}
+
+// ReadSchemas reads the schema files in the directory, the summary of
+// each schema is the sha256 of the content
+func ReadSchemas(dir string) ([]*pb.Schema, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var schemas []*pb.Schema
+	exists := make(map[string]string, len(files))
+	for _, file := range files {
+		ext := filepath.Ext(file.Name())
+		if file.IsDir() || !schemaExts[strings.ToLower(ext)] {
+			continue
+		}
+		schemaId := strings.TrimSuffix(file.Name(), ext)
+		if name, ok := exists[schemaId]; ok {
+			return nil, errors.New("duplicate schema id '" + schemaId + "' of " + name + " and " + file.Name())
+		}
+		exists[schemaId] = file.Name()
+
+		content, err := ioutil.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(content)
+		schemas = append(schemas, &pb.Schema{
+			SchemaId: schemaId,
+			Schema:   util.BytesToStringWithNoCopy(content),
+			Summary:  hex.EncodeToString(sum[:]),
+		})
+	}
+	return schemas, nil
+}
@@ -0,0 +1,54 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package schema
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadSchemas(t *testing.T) {
+	dir, err := ioutil.TempDir("", "schemas")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ioutil.WriteFile(filepath.Join(dir, "hello.yaml"), []byte("swagger: '2.0'"), 0640)
+	ioutil.WriteFile(filepath.Join(dir, "world.json"), []byte(`{"swagger":"2.0"}`), 0640)
+	ioutil.WriteFile(filepath.Join(dir, "README.md"), []byte("ignored"), 0640)
+	os.Mkdir(filepath.Join(dir, "sub.yaml"), 0750)
+
+	schemas, err := ReadSchemas(dir)
+	if err != nil || len(schemas) != 2 {
+		t.Fatalf("TestReadSchemas failed, %v", err)
+	}
+	if schemas[0].SchemaId != "hello" || schemas[0].Schema != "swagger: '2.0'" ||
+		schemas[0].Summary != "4c0a434d94e66e232872b544cbd23f7e78f1c0a8b70444f86a814a022c8e1ab9" {
+		t.Fatalf("TestReadSchemas failed, %v", schemas[0])
+	}
+	if schemas[1].SchemaId != "world" || schemas[0].Summary == schemas[1].Summary {
+		t.Fatalf("TestReadSchemas failed, %v", schemas[1])
+	}
+
+	ioutil.WriteFile(filepath.Join(dir, "hello.yml"), []byte("swagger: '2.0'"), 0640)
+	if _, err := ReadSchemas(dir); err == nil {
+		t.Fatalf("TestReadSchemas failed")
+	}
+}
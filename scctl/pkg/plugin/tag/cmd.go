@@ -0,0 +1,134 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package tag
+
+import (
+	"fmt"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/cmd"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/writer"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+	"strings"
+)
+
+var Service cmd.ServiceFlags
+
+// Result is the structured output of add and rm commands
+type Result struct {
+	ServiceId string            `json:"serviceId"`
+	Tags      map[string]string `json:"tags,omitempty"`
+	Keys      []string          `json:"keys,omitempty"`
+}
+
+func init() {
+	NewTagCommand(cmd.RootCmd())
+}
+
+func NewTagCommand(parent *cobra.Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tag <command> [options]",
+		Short: "Manage the tags of the microservice",
+	}
+	parent.AddCommand(cmd)
+	Service.Bind(cmd.PersistentFlags())
+
+	list := &cobra.Command{
+		Use:   "list [options]",
+		Short: "Output the tags of the microservice",
+		Run:   ListCommandFunc,
+	}
+	cmd.AddCommand(list)
+	cmd.AddCommand(&cobra.Command{
+		Use:   "add <key>=<value>... [options]",
+		Short: "Add the tags to the microservice, the tags with the same keys are overwritten",
+		Run:   AddCommandFunc,
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:     "rm <key>... [options]",
+		Aliases: []string{"remove"},
+		Short:   "Remove the tags from the microservice",
+		Run:     RemoveCommandFunc,
+	})
+	return cmd
+}
+
+func ListCommandFunc(_ *cobra.Command, args []string) {
+	ctx := context.Background()
+	scClient, domainProject, serviceId := Service.Lookup(ctx)
+	tags, scErr := scClient.GetTags(ctx, domainProject, serviceId)
+	if scErr != nil {
+		cmd.StopAndExit(cmd.ExitError, scErr)
+	}
+
+	if tags == nil {
+		tags = map[string]string{}
+	}
+	if err := writer.Print(cmd.Output, tags, &TagPrinter{Tags: tags}); err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+}
+
+func AddCommandFunc(_ *cobra.Command, args []string) {
+	tags, err := ParseTags(args)
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+
+	ctx := context.Background()
+	scClient, domainProject, serviceId := Service.Lookup(ctx)
+	if scErr := scClient.AddTags(ctx, domainProject, serviceId, tags); scErr != nil {
+		cmd.StopAndExit(cmd.ExitError, scErr)
+	}
+	err = writer.PrintResult(cmd.Output, &Result{ServiceId: serviceId, Tags: tags},
+		fmt.Sprintf("%d tags are added to microservice %s", len(tags), serviceId))
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+}
+
+func RemoveCommandFunc(_ *cobra.Command, args []string) {
+	if len(args) == 0 {
+		cmd.StopAndExit(cmd.ExitError, "the keys of the tags are required")
+	}
+
+	ctx := context.Background()
+	scClient, domainProject, serviceId := Service.Lookup(ctx)
+	if scErr := scClient.DeleteTags(ctx, domainProject, serviceId, args); scErr != nil {
+		cmd.StopAndExit(cmd.ExitError, scErr)
+	}
+	err := writer.PrintResult(cmd.Output, &Result{ServiceId: serviceId, Keys: args},
+		fmt.Sprintf("%d tags are removed from microservice %s", len(args), serviceId))
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+}
+
+// ParseTags parses the arguments in the form of key=value
+func ParseTags(args []string) (map[string]string, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("the tags are required, e.g. key=value")
+	}
+	tags := make(map[string]string, len(args))
+	for _, arg := range args {
+		i := strings.Index(arg, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf("invalid tag '%s', it must be key=value", arg)
+		}
+		tags[arg[:i]] = arg[i+1:]
+	}
+	return tags, nil
+}
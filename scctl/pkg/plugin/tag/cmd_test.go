@@ -0,0 +1,37 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package tag
+
+import (
+	"testing"
+)
+
+func TestParseTags(t *testing.T) {
+	tags, err := ParseTags([]string{"a=1", "b=", "c=x=y"})
+	if err != nil || len(tags) != 3 || tags["a"] != "1" || tags["b"] != "" || tags["c"] != "x=y" {
+		t.Fatalf("TestParseTags failed, %v %v", tags, err)
+	}
+	if _, err := ParseTags(nil); err == nil {
+		t.Fatalf("TestParseTags failed")
+	}
+	if _, err := ParseTags([]string{"=1"}); err == nil {
+		t.Fatalf("TestParseTags failed")
+	}
+	if _, err := ParseTags([]string{"a"}); err == nil {
+		t.Fatalf("TestParseTags failed")
+	}
+}
@@ -0,0 +1,50 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package tag
+
+import (
+	"github.com/apache/servicecomb-service-center/scctl/pkg/writer"
+)
+
+var tagTableHeader = []string{"KEY", "VALUE"}
+
+type TagPrinter struct {
+	Tags  map[string]string
+	flags []interface{}
+}
+
+func (tp *TagPrinter) Flags(flags ...interface{}) []interface{} {
+	if len(flags) > 0 {
+		tp.flags = flags
+	}
+	return tp.flags
+}
+
+func (tp *TagPrinter) PrintBody() (slice [][]string) {
+	for key, value := range tp.Tags {
+		slice = append(slice, []string{key, value})
+	}
+	return
+}
+
+func (tp *TagPrinter) PrintTitle() []string {
+	return tagTableHeader
+}
+
+func (tp *TagPrinter) Sorter() *writer.RecordsSorter {
+	return nil
+}
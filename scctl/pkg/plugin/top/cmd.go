@@ -0,0 +1,89 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package top
+
+import (
+	"fmt"
+	"github.com/apache/servicecomb-service-center/pkg/client/sc"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/cmd"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/writer"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+	"time"
+)
+
+// clearScreen moves the cursor to the top left and clears the terminal
+const clearScreen = "\033[H\033[2J"
+
+var (
+	Interval time.Duration
+	Once     bool
+)
+
+func init() {
+	NewTopCommand(cmd.RootCmd())
+}
+
+func NewTopCommand(parent *cobra.Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "top [options]",
+		Short: "Output the live metrics of each service center instance",
+		Run:   TopCommandFunc,
+	}
+
+	parent.AddCommand(cmd)
+	cmd.Flags().DurationVar(&Interval, "interval", 5*time.Second, "the interval to refresh the metrics")
+	cmd.Flags().BoolVar(&Once, "once", false, "output the metrics once without refreshing")
+	return cmd
+}
+
+func TopCommandFunc(_ *cobra.Command, args []string) {
+	scClient, err := sc.NewSCClient(cmd.ScClientConfig)
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+
+	// the structured output is printed once
+	if writer.IsStructured(cmd.Output) {
+		resp, scErr := scClient.GetMembers(context.Background())
+		if scErr != nil {
+			cmd.StopAndExit(cmd.ExitError, scErr)
+		}
+		if err := writer.Print(cmd.Output, resp.Members, nil); err != nil {
+			cmd.StopAndExit(cmd.ExitError, err)
+		}
+		return
+	}
+
+	for {
+		resp, scErr := scClient.GetMembers(context.Background())
+		if scErr != nil {
+			cmd.StopAndExit(cmd.ExitError, scErr)
+		}
+		if !Once {
+			fmt.Print(clearScreen)
+			fmt.Printf("%s  members: %d, healthy: %d, revision spread: %d\n\n",
+				time.Now().Format("2006-01-02 15:04:05"),
+				resp.Summary.Total, resp.Summary.Healthy, resp.Summary.RevisionSpread)
+		}
+		writer.PrintTable(&TopPrinter{Members: resp.Members})
+		if Once {
+			return
+		}
+		<-time.After(Interval)
+	}
+}
@@ -0,0 +1,70 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package top
+
+import (
+	"github.com/apache/servicecomb-service-center/scctl/pkg/writer"
+	"github.com/apache/servicecomb-service-center/server/admin/model"
+	"strconv"
+)
+
+var topTableHeader = []string{"HOST", "VERSION", "HEALTH", "REQUEST/S", "HEARTBEAT/S", "INSTANCES", "REVISION", "LAG"}
+
+// TopPrinter prints the metrics of the service center instances, the lag
+// is the revision behind the latest cache of the instances
+type TopPrinter struct {
+	Members []*model.MemberStatus
+	flags   []interface{}
+}
+
+func (tp *TopPrinter) Flags(flags ...interface{}) []interface{} {
+	if len(flags) > 0 {
+		tp.flags = flags
+	}
+	return tp.flags
+}
+
+func (tp *TopPrinter) PrintBody() (slice [][]string) {
+	var latest int64
+	for _, m := range tp.Members {
+		if m.CacheRevision > latest {
+			latest = m.CacheRevision
+		}
+	}
+	for _, m := range tp.Members {
+		record := []string{m.HostName, m.Version, m.Health, "-", "-", "-", "-", "-"}
+		if m.Metrics != nil {
+			record[3] = strconv.FormatFloat(m.Metrics.RequestRate, 'f', 1, 64)
+			record[4] = strconv.FormatFloat(m.Metrics.HeartbeatRate, 'f', 1, 64)
+			record[5] = strconv.FormatInt(m.Metrics.Instances, 10)
+		}
+		if len(m.Error) == 0 {
+			record[6] = strconv.FormatInt(m.CacheRevision, 10)
+			record[7] = strconv.FormatInt(latest-m.CacheRevision, 10)
+		}
+		slice = append(slice, record)
+	}
+	return
+}
+
+func (tp *TopPrinter) PrintTitle() []string {
+	return topTableHeader
+}
+
+func (tp *TopPrinter) Sorter() *writer.RecordsSorter {
+	return nil
+}
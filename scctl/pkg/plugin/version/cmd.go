@@ -20,6 +20,8 @@ import (
 	"github.com/apache/servicecomb-service-center/pkg/client/sc"
 	"github.com/apache/servicecomb-service-center/scctl/pkg/cmd"
 	"github.com/apache/servicecomb-service-center/scctl/pkg/version"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/writer"
+	scversion "github.com/apache/servicecomb-service-center/version"
 	"github.com/spf13/cobra"
 	"golang.org/x/net/context"
 )
@@ -28,6 +30,13 @@ var (
 	RootCmd *cobra.Command
 )
 
+// Versions is the structured output of version command, ServiceCenter is
+// omitted if service center is unreachable
+type Versions struct {
+	Tool          *scversion.VersionSet `json:"tool"`
+	ServiceCenter *scversion.VersionSet `json:"serviceCenter,omitempty"`
+}
+
 func init() {
 	RootCmd = NewGetCommand(cmd.RootCmd())
 }
@@ -44,6 +53,17 @@ func NewGetCommand(parent *cobra.Command) *cobra.Command {
 }
 
 func VersionCommandFunc(_ *cobra.Command, _ []string) {
+	if writer.IsStructured(cmd.Output) {
+		versions := &Versions{Tool: version.Ver()}
+		if scClient, err := sc.NewSCClient(cmd.ScClientConfig); err == nil {
+			versions.ServiceCenter, _ = scClient.GetScVersion(context.Background())
+		}
+		if err := writer.PrintResult(cmd.Output, versions, ""); err != nil {
+			cmd.StopAndExit(cmd.ExitError, err)
+		}
+		return
+	}
+
 	defer cmd.StopAndExit(cmd.ExitSuccess)
 	fmt.Print(version.TOOL_NAME, " ")
 	version.Ver().Print()
@@ -0,0 +1,138 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package watch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/apache/servicecomb-service-center/pkg/client/sc"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/cmd"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/writer"
+	"github.com/apache/servicecomb-service-center/server/core"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const eventLineFormat = "%-20s %-8s %-10s %-34s %-20s %-8s %s\n"
+
+var (
+	ServiceName string
+	Domain      string
+	Interval    time.Duration
+)
+
+func init() {
+	NewWatchCommand(cmd.RootCmd())
+}
+
+func NewWatchCommand(parent *cobra.Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "watch [options]",
+		Short: "Output the instance events of the microservice live",
+		Run:   WatchCommandFunc,
+	}
+
+	parent.AddCommand(cmd)
+	cmd.Flags().StringVar(&ServiceName, "service", "", "the microservice name to watch")
+	cmd.Flags().StringVarP(&Domain, "domain", "d", "default",
+		"watch the microservice under the specified domain, or the domain/project")
+	cmd.Flags().DurationVar(&Interval, "interval", time.Second, "the interval to poll the events")
+	return cmd
+}
+
+func WatchCommandFunc(_ *cobra.Command, args []string) {
+	if len(ServiceName) == 0 {
+		cmd.StopAndExit(cmd.ExitError, errors.New("the microservice name is required, use --service"))
+	}
+	domainProject := Domain
+	if !strings.Contains(domainProject, core.SPLIT) {
+		domainProject = core.ToDomainProject(Domain, core.REGISTRY_PROJECT)
+	}
+
+	scClient, err := sc.NewSCClient(cmd.ScClientConfig)
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+	ctx := context.Background()
+	// start from the latest revision, the retained events are skipped
+	rev, scErr := latest(ctx, scClient, domainProject)
+	if scErr != nil {
+		cmd.StopAndExit(cmd.ExitError, scErr)
+	}
+
+	if !writer.IsStructured(cmd.Output) {
+		fmt.Printf(eventLineFormat, "TIME", "ACTION", "VERSION", "INSTANCE", "HOST", "STATUS", "ENDPOINTS")
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	for {
+		<-time.After(Interval)
+		resp, scErr := scClient.GetWatchEvents(ctx, domainProject, rev)
+		if scErr != nil {
+			if scErr.Code != scerr.ErrRevisionExpired {
+				cmd.StopAndExit(cmd.ExitError, scErr)
+			}
+			// too slow to poll, some events are lost
+			fmt.Fprintln(os.Stderr, "warning:", scErr.Detail)
+			if rev, scErr = latest(ctx, scClient, domainProject); scErr != nil {
+				cmd.StopAndExit(cmd.ExitError, scErr)
+			}
+			continue
+		}
+		for _, evt := range resp.Events {
+			if evt.Key == nil || evt.Key.ServiceName != ServiceName || evt.Instance == nil {
+				continue
+			}
+			switch cmd.Output {
+			case writer.OutputJSON:
+				// an event per line
+				encoder.Encode(evt)
+			case writer.OutputYAML:
+				// an event per document
+				fmt.Println("---")
+				writer.PrintYAML(evt)
+			default:
+				printEvent(evt)
+			}
+		}
+		rev = resp.Revision
+	}
+}
+
+func latest(ctx context.Context, scClient *sc.SCClient, domainProject string) (int64, *scerr.Error) {
+	resp, scErr := scClient.GetWatchEvents(ctx, domainProject, 0)
+	if scErr != nil {
+		return 0, scErr
+	}
+	return resp.Revision, nil
+}
+
+func printEvent(evt *pb.WatchEvent) {
+	t := evt.Timestamp
+	if sec, err := strconv.ParseInt(t, 10, 64); err == nil {
+		t = time.Unix(sec, 0).Format("2006-01-02 15:04:05")
+	}
+	fmt.Printf(eventLineFormat, t, evt.Action, evt.Key.Version, evt.Instance.InstanceId,
+		evt.Instance.HostName, evt.Instance.Status, util.StringJoin(evt.Instance.Endpoints, ","))
+}
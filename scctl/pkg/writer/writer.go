@@ -16,6 +16,9 @@
 package writer
 
 import (
+	"encoding/json"
+	"fmt"
+	"github.com/ghodss/yaml"
 	"github.com/olekukonko/tablewriter"
 	"os"
 	"sort"
@@ -25,6 +28,33 @@ import (
 
 const Day = time.Hour * 24
 
+const (
+	OutputWide = "wide"
+	OutputJSON = "json"
+	OutputYAML = "yaml"
+)
+
+// Formats are the values accepted by the output option
+var Formats = []string{OutputJSON, OutputYAML, OutputWide}
+
+// CheckFormat returns an error if f is not one of the Formats
+func CheckFormat(f string) error {
+	if len(f) == 0 {
+		return nil
+	}
+	for _, format := range Formats {
+		if f == format {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown output format %q, expect one of %v", f, Formats)
+}
+
+// IsStructured returns true if f prints the machine-readable documents
+func IsStructured(f string) bool {
+	return f == OutputJSON || f == OutputYAML
+}
+
 type Printer interface {
 	Flags(flags ...interface{}) []interface{}
 	PrintBody() [][]string
@@ -74,3 +104,46 @@ func PrintTable(p Printer) {
 	sort.Sort(sorter)
 	MakeTable(p.PrintTitle(), body)
 }
+
+func PrintJSON(v interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+func PrintYAML(v interface{}) error {
+	b, err := yaml.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(b)
+	return err
+}
+
+// Print outputs v in json or yaml if the format is structured,
+// otherwise outputs the table of p
+func Print(format string, v interface{}, p Printer) error {
+	switch format {
+	case OutputJSON:
+		return PrintJSON(v)
+	case OutputYAML:
+		return PrintYAML(v)
+	default:
+		PrintTable(p)
+		return nil
+	}
+}
+
+// PrintResult outputs v in json or yaml if the format is structured,
+// otherwise outputs the message line
+func PrintResult(format string, v interface{}, message string) error {
+	switch format {
+	case OutputJSON:
+		return PrintJSON(v)
+	case OutputYAML:
+		return PrintYAML(v)
+	default:
+		_, err := fmt.Println(message)
+		return err
+	}
+}
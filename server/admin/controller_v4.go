@@ -17,10 +17,20 @@
 package admin
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"net/http/pprof"
+	"strconv"
+	"strings"
 
+	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/pkg/rest"
 	"github.com/apache/servicecomb-service-center/server/admin/model"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
 	"github.com/apache/servicecomb-service-center/server/rest/controller"
 )
 
@@ -32,12 +42,42 @@ type AdminServiceControllerV4 struct {
 func (ctrl *AdminServiceControllerV4) URLPatterns() []rest.Route {
 	return []rest.Route{
 		{rest.HTTP_METHOD_GET, "/v4/:project/admin/dump", ctrl.Dump},
+		{rest.HTTP_METHOD_POST, "/v4/:project/admin/dump/jobs", ctrl.CreateExport},
+		{rest.HTTP_METHOD_GET, "/v4/:project/admin/dump/jobs", ctrl.ListExports},
+		{rest.HTTP_METHOD_GET, "/v4/:project/admin/dump/jobs/:id", ctrl.GetExport},
+		{rest.HTTP_METHOD_GET, "/v4/:project/admin/dump/jobs/:id/download", ctrl.DownloadExport},
+		{rest.HTTP_METHOD_POST, "/v4/:project/admin/dump/import", ctrl.Import},
+		{rest.HTTP_METHOD_GET, "/v4/:project/admin/domains", ctrl.ListDomains},
+		{rest.HTTP_METHOD_POST, "/v4/:project/admin/domains", ctrl.CreateDomain},
+		{rest.HTTP_METHOD_GET, "/v4/:project/admin/domains/:domain", ctrl.GetDomain},
+		{rest.HTTP_METHOD_PUT, "/v4/:project/admin/domains/:domain", ctrl.UpdateDomain},
+		{rest.HTTP_METHOD_DELETE, "/v4/:project/admin/domains/:domain", ctrl.DeleteDomain},
+		{rest.HTTP_METHOD_POST, "/v4/:project/admin/domains/:domain/projects", ctrl.CreateProject},
+		{rest.HTTP_METHOD_DELETE, "/v4/:project/admin/domains/:domain/projects/:name", ctrl.DeleteProject},
 		{rest.HTTP_METHOD_GET, "/v4/:project/admin/clusters", ctrl.Clusters},
+		{rest.HTTP_METHOD_GET, "/v4/:project/admin/cluster/members", ctrl.Members},
+		{rest.HTTP_METHOD_GET, "/v4/:project/admin/cluster/self", ctrl.SelfStatus},
+		{rest.HTTP_METHOD_GET, "/v4/:project/admin/laggards", ctrl.Laggards},
+		{rest.HTTP_METHOD_GET, "/v4/:project/admin/pools", ctrl.Pools},
+		{rest.HTTP_METHOD_GET, "/v4/:project/admin/sources", ctrl.Sources},
+		{rest.HTTP_METHOD_POST, "/v4/:project/admin/peer/events", ctrl.PeerEvents},
+		{rest.HTTP_METHOD_GET, "/v4/:project/admin/subscriptions", ctrl.Subscriptions},
+		{rest.HTTP_METHOD_DELETE, "/v4/:project/admin/subscriptions/:id", ctrl.TerminateSubscription},
+		{rest.HTTP_METHOD_GET, "/v4/:project/admin/debug", ctrl.GetDebug},
+		{rest.HTTP_METHOD_PUT, "/v4/:project/admin/debug", ctrl.SetDebug},
+		{rest.HTTP_METHOD_GET, "/v4/:project/admin/debug/runtime", ctrl.Runtime},
+		{rest.HTTP_METHOD_GET, "/v4/:project/admin/debug/pprof/:name", ctrl.Profile},
+		{rest.HTTP_METHOD_GET, "/v4/:project/admin/log", ctrl.GetLogLevel},
+		{rest.HTTP_METHOD_PUT, "/v4/:project/admin/log", ctrl.SetLogLevel},
+		{rest.HTTP_METHOD_POST, "/v4/:project/admin/config/reload", ctrl.ReloadConfig},
+		{rest.HTTP_METHOD_GET, "/v4/:project/admin/config/changes", ctrl.GetConfigChanges},
 	}
 }
 
 func (ctrl *AdminServiceControllerV4) Dump(w http.ResponseWriter, r *http.Request) {
-	request := &model.DumpRequest{}
+	request := &model.DumpRequest{
+		Source: r.URL.Query().Get("source"),
+	}
 	ctx := r.Context()
 	resp, _ := AdminServiceAPI.Dump(ctx, request)
 
@@ -46,6 +86,200 @@ func (ctrl *AdminServiceControllerV4) Dump(w http.ResponseWriter, r *http.Reques
 	controller.WriteResponse(w, respInternal, resp)
 }
 
+// CreateExport starts a job exporting the registry data of the domains,
+// query the job until it succeeds and then download the file
+func (ctrl *AdminServiceControllerV4) CreateExport(w http.ResponseWriter, r *http.Request) {
+	request := &model.ExportRequest{}
+	message, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Error("read body failed", err)
+		controller.WriteError(w, scerr.ErrInvalidParams, err.Error())
+		return
+	}
+	if len(message) > 0 {
+		if err := json.Unmarshal(message, request); err != nil {
+			log.Error("Unmarshal error", err)
+			controller.WriteError(w, scerr.ErrInvalidParams, err.Error())
+			return
+		}
+	}
+	resp, _ := AdminServiceAPI.CreateExport(r.Context(), request)
+
+	respInternal := resp.Response
+	resp.Response = nil
+	controller.WriteResponse(w, respInternal, resp)
+}
+
+func (ctrl *AdminServiceControllerV4) ListExports(w http.ResponseWriter, r *http.Request) {
+	resp, _ := AdminServiceAPI.ListExports(r.Context())
+
+	respInternal := resp.Response
+	resp.Response = nil
+	controller.WriteResponse(w, respInternal, resp)
+}
+
+func (ctrl *AdminServiceControllerV4) GetExport(w http.ResponseWriter, r *http.Request) {
+	resp, _ := AdminServiceAPI.GetExport(r.Context(), r.URL.Query().Get(":id"))
+
+	respInternal := resp.Response
+	resp.Response = nil
+	controller.WriteResponse(w, respInternal, resp)
+}
+
+// DownloadExport streams the export file from the disk
+func (ctrl *AdminServiceControllerV4) DownloadExport(w http.ResponseWriter, r *http.Request) {
+	f, job, resp := AdminServiceAPI.OpenExport(r.Context(), r.URL.Query().Get(":id"))
+	if resp != nil {
+		controller.WriteResponse(w, resp, nil)
+		return
+	}
+	defer f.Close()
+
+	contentType := "application/x-ndjson"
+	if job.Format == model.EXPORT_FORMAT_JSON {
+		contentType = rest.CONTENT_TYPE_JSON
+	}
+	w.Header().Set(rest.HEADER_CONTENT_TYPE, contentType)
+	w.Header().Set("Content-Disposition",
+		fmt.Sprintf(`attachment; filename="%s%s.%s"`, exportFilePrefix, job.Id, job.Format))
+	w.Header().Set("Content-Length", strconv.FormatInt(job.Size, 10))
+	if _, err := io.Copy(w, f); err != nil {
+		log.Errorf(err, "download export[%s] failed", job.Id)
+	}
+}
+
+// Import loads the dump in the body, the options are in the query, like
+// ?policy=skip&format=ndjson&dryRun=true&mapping=from:to,from2:to2
+func (ctrl *AdminServiceControllerV4) Import(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	request := &model.ImportRequest{
+		Policy: query.Get("policy"),
+		Format: query.Get("format"),
+		DryRun: query.Get("dryRun") == "true",
+	}
+	if mapping := query.Get("mapping"); len(mapping) > 0 {
+		request.Mapping = make(map[string]string)
+		for _, pair := range strings.Split(mapping, ",") {
+			kv := strings.SplitN(pair, ":", 2)
+			if len(kv) != 2 {
+				controller.WriteError(w, scerr.ErrInvalidParams, "Invalid mapping '"+pair+"'")
+				return
+			}
+			request.Mapping[kv[0]] = kv[1]
+		}
+	}
+	resp, _ := AdminServiceAPI.Import(r.Context(), request, r.Body)
+
+	respInternal := resp.Response
+	resp.Response = nil
+	controller.WriteResponse(w, respInternal, resp)
+}
+
+func (ctrl *AdminServiceControllerV4) ListDomains(w http.ResponseWriter, r *http.Request) {
+	resp, _ := AdminServiceAPI.ListDomains(r.Context())
+
+	respInternal := resp.Response
+	resp.Response = nil
+	controller.WriteResponse(w, respInternal, resp)
+}
+
+func (ctrl *AdminServiceControllerV4) GetDomain(w http.ResponseWriter, r *http.Request) {
+	resp, _ := AdminServiceAPI.GetDomain(r.Context(), r.URL.Query().Get(":domain"))
+
+	respInternal := resp.Response
+	resp.Response = nil
+	controller.WriteResponse(w, respInternal, resp)
+}
+
+func (ctrl *AdminServiceControllerV4) CreateDomain(w http.ResponseWriter, r *http.Request) {
+	message, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Error("read body failed", err)
+		controller.WriteError(w, scerr.ErrInvalidParams, err.Error())
+		return
+	}
+	request := &model.DomainRequest{}
+	if err := json.Unmarshal(message, request); err != nil {
+		log.Error("Unmarshal error", err)
+		controller.WriteError(w, scerr.ErrInvalidParams, err.Error())
+		return
+	}
+	resp, _ := AdminServiceAPI.CreateDomain(r.Context(), request)
+
+	respInternal := resp.Response
+	resp.Response = nil
+	controller.WriteResponse(w, respInternal, resp)
+}
+
+func (ctrl *AdminServiceControllerV4) UpdateDomain(w http.ResponseWriter, r *http.Request) {
+	message, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Error("read body failed", err)
+		controller.WriteError(w, scerr.ErrInvalidParams, err.Error())
+		return
+	}
+	request := &model.DomainRequest{}
+	if err := json.Unmarshal(message, request); err != nil {
+		log.Error("Unmarshal error", err)
+		controller.WriteError(w, scerr.ErrInvalidParams, err.Error())
+		return
+	}
+	resp, _ := AdminServiceAPI.UpdateDomain(r.Context(), r.URL.Query().Get(":domain"), request)
+
+	respInternal := resp.Response
+	resp.Response = nil
+	controller.WriteResponse(w, respInternal, resp)
+}
+
+// DeleteDomain deletes the empty domain, the domain having resources is
+// deleted by ?cascade=true&confirm={domain}
+func (ctrl *AdminServiceControllerV4) DeleteDomain(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	resp, _ := AdminServiceAPI.DeleteDomain(r.Context(), query.Get(":domain"), &model.DeleteRequest{
+		Cascade: query.Get("cascade") == "true",
+		Confirm: query.Get("confirm"),
+	})
+
+	respInternal := resp.Response
+	resp.Response = nil
+	controller.WriteResponse(w, respInternal, resp)
+}
+
+func (ctrl *AdminServiceControllerV4) CreateProject(w http.ResponseWriter, r *http.Request) {
+	message, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Error("read body failed", err)
+		controller.WriteError(w, scerr.ErrInvalidParams, err.Error())
+		return
+	}
+	request := &model.ProjectRequest{}
+	if err := json.Unmarshal(message, request); err != nil {
+		log.Error("Unmarshal error", err)
+		controller.WriteError(w, scerr.ErrInvalidParams, err.Error())
+		return
+	}
+	resp, _ := AdminServiceAPI.CreateProject(r.Context(), r.URL.Query().Get(":domain"), request)
+
+	respInternal := resp.Response
+	resp.Response = nil
+	controller.WriteResponse(w, respInternal, resp)
+}
+
+// DeleteProject deletes the empty project, the project having resources
+// is deleted by ?cascade=true&confirm={project}
+func (ctrl *AdminServiceControllerV4) DeleteProject(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	resp, _ := AdminServiceAPI.DeleteProject(r.Context(), query.Get(":domain"), query.Get(":name"),
+		&model.DeleteRequest{
+			Cascade: query.Get("cascade") == "true",
+			Confirm: query.Get("confirm"),
+		})
+
+	respInternal := resp.Response
+	resp.Response = nil
+	controller.WriteResponse(w, respInternal, resp)
+}
+
 func (ctrl *AdminServiceControllerV4) Clusters(w http.ResponseWriter, r *http.Request) {
 	request := &model.ClustersRequest{}
 	ctx := r.Context()
@@ -55,3 +289,192 @@ func (ctrl *AdminServiceControllerV4) Clusters(w http.ResponseWriter, r *http.Re
 	resp.Response = nil
 	controller.WriteResponse(w, respInternal, resp)
 }
+
+func (ctrl *AdminServiceControllerV4) Members(w http.ResponseWriter, r *http.Request) {
+	resp, _ := AdminServiceAPI.Members(r.Context())
+
+	respInternal := resp.Response
+	resp.Response = nil
+	controller.WriteResponse(w, respInternal, resp)
+}
+
+func (ctrl *AdminServiceControllerV4) SelfStatus(w http.ResponseWriter, r *http.Request) {
+	resp, _ := AdminServiceAPI.SelfStatus(r.Context())
+
+	respInternal := resp.Response
+	resp.Response = nil
+	controller.WriteResponse(w, respInternal, resp)
+}
+
+func (ctrl *AdminServiceControllerV4) Laggards(w http.ResponseWriter, r *http.Request) {
+	request := &model.LaggardsRequest{}
+	ctx := r.Context()
+	resp, _ := AdminServiceAPI.Laggards(ctx, request)
+
+	respInternal := resp.Response
+	resp.Response = nil
+	controller.WriteResponse(w, respInternal, resp)
+}
+
+// Pools lists the utilization of the goroutine pools shared by the
+// subsystems, e.g. the notifications and the heartbeats
+func (ctrl *AdminServiceControllerV4) Pools(w http.ResponseWriter, r *http.Request) {
+	resp, _ := AdminServiceAPI.Pools(r.Context(), &model.PoolsRequest{})
+
+	respInternal := resp.Response
+	resp.Response = nil
+	controller.WriteResponse(w, respInternal, resp)
+}
+
+func (ctrl *AdminServiceControllerV4) Sources(w http.ResponseWriter, r *http.Request) {
+	request := &model.SourcesRequest{}
+	resp, _ := AdminServiceAPI.Sources(r.Context(), request)
+
+	respInternal := resp.Response
+	resp.Response = nil
+	controller.WriteResponse(w, respInternal, resp)
+}
+
+func (ctrl *AdminServiceControllerV4) Subscriptions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	request := &model.SubscriptionsRequest{
+		Type:  query.Get("type"),
+		Group: query.Get("group"),
+	}
+	resp, _ := AdminServiceAPI.Subscriptions(r.Context(), request)
+
+	respInternal := resp.Response
+	resp.Response = nil
+	controller.WriteResponse(w, respInternal, resp)
+}
+
+func (ctrl *AdminServiceControllerV4) TerminateSubscription(w http.ResponseWriter, r *http.Request) {
+	request := &model.TerminateSubscriptionRequest{
+		Id: r.URL.Query().Get(":id"),
+	}
+	resp, _ := AdminServiceAPI.TerminateSubscription(r.Context(), request)
+	controller.WriteResponse(w, resp.Response, nil)
+}
+
+func (ctrl *AdminServiceControllerV4) PeerEvents(w http.ResponseWriter, r *http.Request) {
+	message, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Error("read body failed", err)
+		controller.WriteError(w, scerr.ErrInvalidParams, err.Error())
+		return
+	}
+	request := &pb.PeerEventsRequest{}
+	err = json.Unmarshal(message, request)
+	if err != nil {
+		log.Error("Unmarshal error", err)
+		controller.WriteError(w, scerr.ErrInvalidParams, err.Error())
+		return
+	}
+	resp, _ := AdminServiceAPI.ReceivePeerEvents(r.Context(), request)
+	controller.WriteResponse(w, resp.Response, nil)
+}
+
+func (ctrl *AdminServiceControllerV4) GetDebug(w http.ResponseWriter, r *http.Request) {
+	resp, _ := AdminServiceAPI.GetDebug(r.Context())
+
+	respInternal := resp.Response
+	resp.Response = nil
+	controller.WriteResponse(w, respInternal, resp)
+}
+
+func (ctrl *AdminServiceControllerV4) SetDebug(w http.ResponseWriter, r *http.Request) {
+	message, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Error("read body failed", err)
+		controller.WriteError(w, scerr.ErrInvalidParams, err.Error())
+		return
+	}
+	request := &model.DebugRequest{}
+	err = json.Unmarshal(message, request)
+	if err != nil {
+		log.Error("Unmarshal error", err)
+		controller.WriteError(w, scerr.ErrInvalidParams, err.Error())
+		return
+	}
+	resp, _ := AdminServiceAPI.SetDebug(r.Context(), request)
+
+	respInternal := resp.Response
+	resp.Response = nil
+	controller.WriteResponse(w, respInternal, resp)
+}
+
+func (ctrl *AdminServiceControllerV4) Runtime(w http.ResponseWriter, r *http.Request) {
+	resp, _ := AdminServiceAPI.Runtime(r.Context(), &model.RuntimeRequest{})
+
+	respInternal := resp.Response
+	resp.Response = nil
+	controller.WriteResponse(w, respInternal, resp)
+}
+
+// Profile serves the net/http/pprof handlers behind the admin permission,
+// the name is one of profile, trace, symbol, cmdline or the runtime
+// profiles like goroutine, heap, block and mutex
+func (ctrl *AdminServiceControllerV4) Profile(w http.ResponseWriter, r *http.Request) {
+	if resp := AdminServiceAPI.CheckDebug(r.Context()); resp != nil {
+		controller.WriteResponse(w, resp, nil)
+		return
+	}
+
+	switch name := r.URL.Query().Get(":name"); name {
+	case "profile":
+		pprof.Profile(w, r)
+	case "trace":
+		pprof.Trace(w, r)
+	case "symbol":
+		pprof.Symbol(w, r)
+	case "cmdline":
+		pprof.Cmdline(w, r)
+	default:
+		pprof.Handler(name).ServeHTTP(w, r)
+	}
+}
+
+func (ctrl *AdminServiceControllerV4) GetLogLevel(w http.ResponseWriter, r *http.Request) {
+	resp, _ := AdminServiceAPI.GetLogLevel(r.Context())
+
+	respInternal := resp.Response
+	resp.Response = nil
+	controller.WriteResponse(w, respInternal, resp)
+}
+
+func (ctrl *AdminServiceControllerV4) SetLogLevel(w http.ResponseWriter, r *http.Request) {
+	message, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Error("read body failed", err)
+		controller.WriteError(w, scerr.ErrInvalidParams, err.Error())
+		return
+	}
+	request := &model.LogLevelRequest{}
+	err = json.Unmarshal(message, request)
+	if err != nil {
+		log.Error("Unmarshal error", err)
+		controller.WriteError(w, scerr.ErrInvalidParams, err.Error())
+		return
+	}
+	resp, _ := AdminServiceAPI.SetLogLevel(r.Context(), request)
+
+	respInternal := resp.Response
+	resp.Response = nil
+	controller.WriteResponse(w, respInternal, resp)
+}
+
+func (ctrl *AdminServiceControllerV4) ReloadConfig(w http.ResponseWriter, r *http.Request) {
+	resp, _ := AdminServiceAPI.ReloadConfig(r.Context())
+
+	respInternal := resp.Response
+	resp.Response = nil
+	controller.WriteResponse(w, respInternal, resp)
+}
+
+func (ctrl *AdminServiceControllerV4) GetConfigChanges(w http.ResponseWriter, r *http.Request) {
+	resp, _ := AdminServiceAPI.GetConfigChanges(r.Context())
+
+	respInternal := resp.Response
+	resp.Response = nil
+	controller.WriteResponse(w, respInternal, resp)
+}
@@ -0,0 +1,161 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package admin
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/admin/model"
+	"github.com/apache/servicecomb-service-center/server/core"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/astaxie/beego"
+	"golang.org/x/net/context"
+	"runtime"
+	"runtime/debug"
+	"runtime/pprof"
+	"sync/atomic"
+	"time"
+)
+
+// maxGCPauses is the max recent gc pauses returned
+const maxGCPauses = 16
+
+// debugEnabled is 1 when the authenticated debug endpoints under
+// '/v4/:project/admin/debug' are allowed, it can be switched at runtime
+var debugEnabled int32
+
+func init() {
+	if beego.AppConfig.DefaultInt("admin_debug", 0) != 0 {
+		debugEnabled = 1
+	}
+}
+
+func DebugEnabled() bool {
+	return atomic.LoadInt32(&debugEnabled) == 1
+}
+
+// CheckDebug returns the error response if the caller is not admin or
+// the debug endpoints are disabled
+func (service *AdminService) CheckDebug(ctx context.Context) *pb.Response {
+	if !core.IsDefaultDomainProject(util.ParseDomainProject(ctx)) {
+		return pb.CreateResponse(scerr.ErrForbidden, "Required admin permission")
+	}
+	if !DebugEnabled() {
+		return pb.CreateResponse(scerr.ErrForbidden, "Debug endpoints are disabled")
+	}
+	return nil
+}
+
+func (service *AdminService) GetDebug(ctx context.Context) (*model.DebugResponse, error) {
+	if !core.IsDefaultDomainProject(util.ParseDomainProject(ctx)) {
+		return &model.DebugResponse{
+			Response: pb.CreateResponse(scerr.ErrForbidden, "Required admin permission"),
+		}, nil
+	}
+	return &model.DebugResponse{
+		Response: pb.CreateResponse(pb.Response_SUCCESS, "Get debug status successfully"),
+		Enabled:  DebugEnabled(),
+	}, nil
+}
+
+func (service *AdminService) SetDebug(ctx context.Context, in *model.DebugRequest) (*model.DebugResponse, error) {
+	if !core.IsDefaultDomainProject(util.ParseDomainProject(ctx)) {
+		return &model.DebugResponse{
+			Response: pb.CreateResponse(scerr.ErrForbidden, "Required admin permission"),
+		}, nil
+	}
+	if in.BlockProfileRate < 0 || in.MutexProfileFraction < 0 {
+		return &model.DebugResponse{
+			Response: pb.CreateResponse(scerr.ErrInvalidParams, "Invalid profile rate"),
+		}, nil
+	}
+
+	if in.Enabled {
+		if in.BlockProfileRate > 0 {
+			runtime.SetBlockProfileRate(in.BlockProfileRate)
+		}
+		if in.MutexProfileFraction > 0 {
+			runtime.SetMutexProfileFraction(in.MutexProfileFraction)
+		}
+		atomic.StoreInt32(&debugEnabled, 1)
+	} else {
+		// stop the sampling which costs at runtime
+		runtime.SetBlockProfileRate(0)
+		runtime.SetMutexProfileFraction(0)
+		atomic.StoreInt32(&debugEnabled, 0)
+	}
+	log.Warnf("debug endpoints are switched to %v by %s", in.Enabled, util.GetIPFromContext(ctx))
+
+	return &model.DebugResponse{
+		Response: pb.CreateResponse(pb.Response_SUCCESS, "Set debug status successfully"),
+		Enabled:  in.Enabled,
+	}, nil
+}
+
+func (service *AdminService) Runtime(ctx context.Context, in *model.RuntimeRequest) (*model.RuntimeResponse, error) {
+	if resp := service.CheckDebug(ctx); resp != nil {
+		return &model.RuntimeResponse{Response: resp}, nil
+	}
+
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+
+	gc := debug.GCStats{Pause: make([]time.Duration, maxGCPauses)}
+	debug.ReadGCStats(&gc)
+	gcStats := &model.GCStats{
+		NumGC:      gc.NumGC,
+		PauseTotal: gc.PauseTotal.String(),
+	}
+	if !gc.LastGC.IsZero() {
+		gcStats.LastGC = gc.LastGC.Format(time.RFC3339)
+	}
+	for i, p := range gc.Pause {
+		if i >= maxGCPauses {
+			break
+		}
+		gcStats.Pauses = append(gcStats.Pauses, p.String())
+	}
+
+	var profiles []string
+	for _, p := range pprof.Profiles() {
+		profiles = append(profiles, p.Name())
+	}
+
+	return &model.RuntimeResponse{
+		Response:     pb.CreateResponse(pb.Response_SUCCESS, "Get runtime successfully"),
+		GoVersion:    runtime.Version(),
+		NumCPU:       runtime.NumCPU(),
+		GoMaxProcs:   runtime.GOMAXPROCS(0),
+		NumGoroutine: runtime.NumGoroutine(),
+		NumCgoCall:   runtime.NumCgoCall(),
+		Memory: &model.MemStats{
+			Alloc:        ms.Alloc,
+			TotalAlloc:   ms.TotalAlloc,
+			Sys:          ms.Sys,
+			HeapAlloc:    ms.HeapAlloc,
+			HeapInuse:    ms.HeapInuse,
+			HeapIdle:     ms.HeapIdle,
+			HeapReleased: ms.HeapReleased,
+			HeapObjects:  ms.HeapObjects,
+			StackInuse:   ms.StackInuse,
+			NextGC:       ms.NextGC,
+		},
+		GC:       gcStats,
+		Profiles: profiles,
+	}, nil
+}
@@ -0,0 +1,560 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package admin
+
+import (
+	"encoding/json"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/admin/model"
+	"github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/core/backend"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/mux"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	"github.com/apache/servicecomb-service-center/server/service/lease"
+	serviceUtil "github.com/apache/servicecomb-service-center/server/service/util"
+	"golang.org/x/net/context"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+var nameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]{0,63}$`)
+
+// projectRootKeys are the prefixes of all the resources in the scope,
+// the domain or the domain project, the instances are deleted first
+func projectRootKeys(scope string) []string {
+	roots := []string{
+		core.GetInstanceRootKey(scope),
+		core.GetInstanceLeaseRootKey(scope),
+		core.GetServiceSchemaRootKey(scope),
+		core.GetServiceSchemaSummaryRootKey(scope),
+		core.GetServiceTagRootKey(scope),
+		core.GetServiceRuleRootKey(scope),
+		core.GetServiceRuleIndexRootKey(scope),
+		core.GetServiceDependencyRuleRootKey(scope),
+		core.GetServiceDependencyQueueRootKey(scope),
+		core.GetServiceDependencyRootKey(scope),
+		core.GetServiceAliasRootKey(scope),
+		core.GetServiceIndexRootKey(scope),
+		core.GetServiceRootKey(scope),
+		core.GetClientLockRootKey(scope),
+	}
+	for i := range roots {
+		roots[i] += core.SPLIT
+	}
+	return roots
+}
+
+func countResources(ctx context.Context, scope string) (counts model.ResourceCounts, err error) {
+	resp, err := backend.Store().Service().Search(ctx, append(serviceUtil.FromContext(ctx),
+		registry.WithStrKey(core.GetServiceRootKey(scope)+core.SPLIT),
+		registry.WithPrefix(), registry.WithCountOnly())...)
+	if err != nil {
+		return
+	}
+	counts.Services = resp.Count
+	resp, err = backend.Store().Instance().Search(ctx, append(serviceUtil.FromContext(ctx),
+		registry.WithStrKey(core.GetInstanceRootKey(scope)+core.SPLIT),
+		registry.WithPrefix(), registry.WithCountOnly())...)
+	if err != nil {
+		return
+	}
+	counts.Instances = resp.Count
+	return
+}
+
+// listResources counts the resources in the scope and returns the keys
+// of the instances
+func listResources(ctx context.Context, scope string) (counts model.ResourceCounts, instances [][]byte, err error) {
+	resp, err := backend.Store().Service().Search(ctx, append(serviceUtil.FromContext(ctx),
+		registry.WithStrKey(core.GetServiceRootKey(scope)+core.SPLIT),
+		registry.WithPrefix(), registry.WithCountOnly())...)
+	if err != nil {
+		return
+	}
+	counts.Services = resp.Count
+	resp, err = backend.Store().Instance().Search(ctx, append(serviceUtil.FromContext(ctx),
+		registry.WithStrKey(core.GetInstanceRootKey(scope)+core.SPLIT),
+		registry.WithPrefix(), registry.WithKeyOnly())...)
+	if err != nil {
+		return
+	}
+	counts.Instances = resp.Count
+	instances = make([][]byte, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		instances = append(instances, kv.Key)
+	}
+	return
+}
+
+// listProjects returns the projects of the domains, the empty domain
+// means all the domains
+func listProjects(ctx context.Context, domain string) (map[string][]string, error) {
+	root := core.GetProjectRootKey("")
+	prefix := root
+	if len(domain) > 0 {
+		prefix = core.GetProjectRootKey(domain) + core.SPLIT
+	}
+	resp, err := backend.Store().Project().Search(ctx,
+		registry.WithStrKey(prefix), registry.WithPrefix(), registry.WithKeyOnly())
+	if err != nil {
+		return nil, err
+	}
+	projects := make(map[string][]string)
+	for _, kv := range resp.Kvs {
+		arr := strings.Split(strings.TrimPrefix(util.BytesToStringWithNoCopy(kv.Key), root), core.SPLIT)
+		if len(arr) != 2 {
+			continue
+		}
+		projects[arr[0]] = append(projects[arr[0]], arr[1])
+	}
+	return projects, nil
+}
+
+func getDomain(ctx context.Context, name string, projects []string) (*model.Domain, error) {
+	info, err := serviceUtil.GetDomainInfo(ctx, name)
+	if err != nil || info == nil {
+		return nil, err
+	}
+	domain := &model.Domain{
+		Name:     name,
+		Quotas:   info.Quotas,
+		CreateAt: info.CreateAt,
+		Projects: make([]*model.Project, 0, len(projects)),
+	}
+	sort.Strings(projects)
+	for _, name := range projects {
+		counts, err := countResources(ctx, domain.Name+core.SPLIT+name)
+		if err != nil {
+			return nil, err
+		}
+		domain.Counts.Services += counts.Services
+		domain.Counts.Instances += counts.Instances
+		domain.Projects = append(domain.Projects, &model.Project{Name: name, Counts: counts})
+	}
+	return domain, nil
+}
+
+func noCache(ctx context.Context) context.Context {
+	return util.SetContext(util.CloneContext(ctx), serviceUtil.CTX_NOCACHE, "1")
+}
+
+func checkQuotas(quotas map[string]int64) string {
+	for name, q := range quotas {
+		if name != model.DOMAIN_QUOTA_SERVICE && name != model.DOMAIN_QUOTA_INSTANCE {
+			return "Unsupported quota '" + name + "'"
+		}
+		if q < 0 {
+			return "Quota '" + name + "' must not be negative"
+		}
+	}
+	return ""
+}
+
+func (service *AdminService) ListDomains(ctx context.Context) (*model.DomainsResponse, error) {
+	if !core.IsDefaultDomainProject(util.ParseDomainProject(ctx)) {
+		return &model.DomainsResponse{
+			Response: pb.CreateResponse(scerr.ErrForbidden, "Required admin permission"),
+		}, nil
+	}
+
+	resp, err := backend.Store().Domain().Search(ctx,
+		registry.WithStrKey(core.GetDomainRootKey()+core.SPLIT), registry.WithPrefix(), registry.WithKeyOnly())
+	if err != nil {
+		log.Errorf(err, "list domains failed")
+		return &model.DomainsResponse{
+			Response: pb.CreateResponse(scerr.ErrUnavailableBackend, err.Error()),
+		}, err
+	}
+	projects, err := listProjects(ctx, "")
+	if err != nil {
+		log.Errorf(err, "list projects failed")
+		return &model.DomainsResponse{
+			Response: pb.CreateResponse(scerr.ErrUnavailableBackend, err.Error()),
+		}, err
+	}
+	domains := make([]*model.Domain, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		name := strings.TrimPrefix(util.BytesToStringWithNoCopy(kv.Key), core.GetDomainRootKey()+core.SPLIT)
+		domain, err := getDomain(ctx, name, projects[name])
+		if err != nil {
+			log.Errorf(err, "get domain '%s' failed", name)
+			return &model.DomainsResponse{
+				Response: pb.CreateResponse(scerr.ErrUnavailableBackend, err.Error()),
+			}, err
+		}
+		if domain != nil {
+			domains = append(domains, domain)
+		}
+	}
+	sort.Slice(domains, func(i, j int) bool { return domains[i].Name < domains[j].Name })
+	return &model.DomainsResponse{
+		Response: pb.CreateResponse(pb.Response_SUCCESS, "List domains successfully"),
+		Domains:  domains,
+	}, nil
+}
+
+func (service *AdminService) GetDomain(ctx context.Context, name string) (*model.DomainResponse, error) {
+	if !core.IsDefaultDomainProject(util.ParseDomainProject(ctx)) {
+		return &model.DomainResponse{
+			Response: pb.CreateResponse(scerr.ErrForbidden, "Required admin permission"),
+		}, nil
+	}
+
+	projects, err := listProjects(ctx, name)
+	if err != nil {
+		log.Errorf(err, "list projects of domain '%s' failed", name)
+		return &model.DomainResponse{
+			Response: pb.CreateResponse(scerr.ErrUnavailableBackend, err.Error()),
+		}, err
+	}
+	domain, err := getDomain(ctx, name, projects[name])
+	if err != nil {
+		log.Errorf(err, "get domain '%s' failed", name)
+		return &model.DomainResponse{
+			Response: pb.CreateResponse(scerr.ErrUnavailableBackend, err.Error()),
+		}, err
+	}
+	if domain == nil {
+		return &model.DomainResponse{
+			Response: pb.CreateResponse(scerr.ErrInvalidParams, "Domain '"+name+"' does not exist"),
+		}, nil
+	}
+	return &model.DomainResponse{
+		Response: pb.CreateResponse(pb.Response_SUCCESS, "Get domain successfully"),
+		Domain:   domain,
+	}, nil
+}
+
+// CreateDomain creates the domain and its projects explicitly, it fails
+// if the domain exists, including the one created on first use
+func (service *AdminService) CreateDomain(ctx context.Context, in *model.DomainRequest) (*model.DomainResponse, error) {
+	if !core.IsDefaultDomainProject(util.ParseDomainProject(ctx)) {
+		return &model.DomainResponse{
+			Response: pb.CreateResponse(scerr.ErrForbidden, "Required admin permission"),
+		}, nil
+	}
+
+	if !nameRegex.MatchString(in.Name) {
+		return &model.DomainResponse{
+			Response: pb.CreateResponse(scerr.ErrInvalidParams, "Invalid domain name '"+in.Name+"'"),
+		}, nil
+	}
+	if msg := checkQuotas(in.Quotas); len(msg) > 0 {
+		return &model.DomainResponse{
+			Response: pb.CreateResponse(scerr.ErrInvalidParams, msg),
+		}, nil
+	}
+	if len(in.Projects) == 0 {
+		in.Projects = []string{core.REGISTRY_PROJECT}
+	}
+	for _, project := range in.Projects {
+		if !nameRegex.MatchString(project) {
+			return &model.DomainResponse{
+				Response: pb.CreateResponse(scerr.ErrInvalidParams, "Invalid project name '"+project+"'"),
+			}, nil
+		}
+	}
+
+	data, err := json.Marshal(&serviceUtil.DomainInfo{
+		Quotas:   in.Quotas,
+		CreateAt: time.Now().UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return &model.DomainResponse{
+			Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
+		}, err
+	}
+	ok, err := backend.Registry().PutNoOverride(ctx,
+		registry.WithStrKey(core.GenerateDomainKey(in.Name)), registry.WithValue(data))
+	if err != nil {
+		log.Errorf(err, "create domain '%s' failed", in.Name)
+		return &model.DomainResponse{
+			Response: pb.CreateResponse(scerr.ErrUnavailableBackend, err.Error()),
+		}, err
+	}
+	if !ok {
+		return &model.DomainResponse{
+			Response: pb.CreateResponse(scerr.ErrInvalidParams, "Domain '"+in.Name+"' already exists"),
+		}, nil
+	}
+	for _, project := range in.Projects {
+		if _, err := serviceUtil.NewProject(ctx, in.Name, project); err != nil {
+			log.Errorf(err, "create project '%s/%s' failed", in.Name, project)
+			return &model.DomainResponse{
+				Response: pb.CreateResponse(scerr.ErrUnavailableBackend, err.Error()),
+			}, err
+		}
+	}
+	log.Warnf("audit: domain '%s' is created with quotas %v, projects %v by %s",
+		in.Name, in.Quotas, in.Projects, util.GetIPFromContext(ctx))
+
+	return &model.DomainResponse{
+		Response: pb.CreateResponse(pb.Response_SUCCESS, "Create domain successfully"),
+		Domain: &model.Domain{
+			Name:     in.Name,
+			Quotas:   in.Quotas,
+			Projects: []*model.Project{},
+		},
+	}, nil
+}
+
+// UpdateDomain replaces the quotas of the domain, the zero quota means
+// only the global quota is checked
+func (service *AdminService) UpdateDomain(ctx context.Context, name string, in *model.DomainRequest) (*model.DomainResponse, error) {
+	if !core.IsDefaultDomainProject(util.ParseDomainProject(ctx)) {
+		return &model.DomainResponse{
+			Response: pb.CreateResponse(scerr.ErrForbidden, "Required admin permission"),
+		}, nil
+	}
+
+	if msg := checkQuotas(in.Quotas); len(msg) > 0 {
+		return &model.DomainResponse{
+			Response: pb.CreateResponse(scerr.ErrInvalidParams, msg),
+		}, nil
+	}
+	info, err := serviceUtil.GetDomainInfo(noCache(ctx), name)
+	if err != nil {
+		log.Errorf(err, "get domain '%s' failed", name)
+		return &model.DomainResponse{
+			Response: pb.CreateResponse(scerr.ErrUnavailableBackend, err.Error()),
+		}, err
+	}
+	if info == nil {
+		return &model.DomainResponse{
+			Response: pb.CreateResponse(scerr.ErrInvalidParams, "Domain '"+name+"' does not exist"),
+		}, nil
+	}
+	old := info.Quotas
+	info.Quotas = in.Quotas
+	if err := serviceUtil.PutDomainInfo(ctx, name, info); err != nil {
+		log.Errorf(err, "update domain '%s' failed", name)
+		return &model.DomainResponse{
+			Response: pb.CreateResponse(scerr.ErrUnavailableBackend, err.Error()),
+		}, err
+	}
+	log.Warnf("audit: quotas of domain '%s' are changed from %v to %v by %s",
+		name, old, in.Quotas, util.GetIPFromContext(ctx))
+
+	return &model.DomainResponse{
+		Response: pb.CreateResponse(pb.Response_SUCCESS, "Update domain successfully"),
+		Domain: &model.Domain{
+			Name:     name,
+			Quotas:   info.Quotas,
+			CreateAt: info.CreateAt,
+			Projects: []*model.Project{},
+		},
+	}, nil
+}
+
+// DeleteDomain deletes the domain and its projects, the domain having
+// services or instances is deleted only in cascade, which removes all
+// the resources in it
+func (service *AdminService) DeleteDomain(ctx context.Context, name string, in *model.DeleteRequest) (*model.DeleteDomainResponse, error) {
+	if !core.IsDefaultDomainProject(util.ParseDomainProject(ctx)) {
+		return &model.DeleteDomainResponse{
+			Response: pb.CreateResponse(scerr.ErrForbidden, "Required admin permission"),
+		}, nil
+	}
+	if name == core.REGISTRY_DOMAIN {
+		return &model.DeleteDomainResponse{
+			Response: pb.CreateResponse(scerr.ErrInvalidParams, "Can not delete the default domain"),
+		}, nil
+	}
+
+	revKey := core.GenerateDomainRevisionKey(name)
+	resp := service.deleteProjects(ctx, name, name, revKey, in,
+		registry.OpDel(registry.WithStrKey(core.GetProjectRootKey(name)+core.SPLIT), registry.WithPrefix()),
+		registry.OpDel(registry.WithStrKey(core.GenerateDomainKey(name))),
+		registry.OpDel(registry.WithStrKey(revKey+core.SPLIT), registry.WithPrefix()),
+		registry.OpDel(registry.WithStrKey(revKey)))
+	if resp.Response.Code == pb.Response_SUCCESS && !backend.IsDryRun(ctx) {
+		log.Warnf("audit: domain '%s' is deleted, cascade: %v, deleted %d services and %d instances by %s",
+			name, in.Cascade, resp.Deleted.Services, resp.Deleted.Instances, util.GetIPFromContext(ctx))
+	}
+	return resp, nil
+}
+
+// deleteProjects deletes the resources in the scope, the domain or the
+// domain project, and the extra keys in one transaction. The resources
+// are deleted if they exist and the request is a confirmed cascade, the
+// deletions are serialized and aborted if any resource is created after
+// the confirmation, which modifies the revision key of the scope
+func (service *AdminService) deleteProjects(ctx context.Context, name, scope, revKey string,
+	in *model.DeleteRequest, extra ...registry.PluginOp) *model.DeleteDomainResponse {
+	lock, err := mux.Lock(mux.DOMAIN_LOCK)
+	if err != nil {
+		log.Errorf(err, "lock %s failed", mux.DOMAIN_LOCK)
+		return &model.DeleteDomainResponse{
+			Response: pb.CreateResponse(scerr.ErrUnavailableBackend, err.Error()),
+		}
+	}
+	defer lock.Unlock()
+
+	// read before listing, the key not existing has the mod revision 0
+	revResp, err := backend.Registry().Do(ctx, registry.GET, registry.WithStrKey(revKey))
+	if err != nil {
+		log.Errorf(err, "get the revision of '%s' failed", scope)
+		return &model.DeleteDomainResponse{
+			Response: pb.CreateResponse(scerr.ErrUnavailableBackend, err.Error()),
+		}
+	}
+	total, instances, err := listResources(noCache(ctx), scope)
+	if err != nil {
+		log.Errorf(err, "list resources of '%s' failed", scope)
+		return &model.DeleteDomainResponse{
+			Response: pb.CreateResponse(scerr.ErrUnavailableBackend, err.Error()),
+		}
+	}
+	if total.Services+total.Instances > 0 {
+		switch {
+		case !in.Cascade:
+			return &model.DeleteDomainResponse{
+				Response: pb.CreateResponse(scerr.ErrInvalidParams,
+					"'"+name+"' is not empty, delete it in cascade"),
+				Deleted: total,
+			}
+		case in.Confirm != name:
+			return &model.DeleteDomainResponse{
+				Response: pb.CreateResponse(scerr.ErrInvalidParams,
+					"Confirm must be '"+name+"' to delete in cascade"),
+				Deleted: total,
+			}
+		}
+	}
+
+	var ops []registry.PluginOp
+	for _, root := range projectRootKeys(scope) {
+		ops = append(ops, registry.OpDel(registry.WithStrKey(root), registry.WithPrefix()))
+	}
+	ops = append(ops, extra...)
+	resp, err := backend.Registry().TxnWithCmp(ctx, ops, []registry.CompareOp{
+		registry.OpCmp(registry.CmpModRev(util.StringToBytesWithNoCopy(revKey)),
+			registry.CMP_EQUAL, revResp.MaxModRevision()),
+	}, nil)
+	if err != nil {
+		log.Errorf(err, "delete '%s' failed", name)
+		return &model.DeleteDomainResponse{
+			Response: pb.CreateResponse(scerr.ErrUnavailableBackend, err.Error()),
+		}
+	}
+	if !resp.Succeeded {
+		return &model.DeleteDomainResponse{
+			Response: pb.CreateResponse(scerr.ErrRevisionConflict,
+				"'"+name+"' is changed during the deletion, retry it"),
+			Deleted: total,
+		}
+	}
+
+	// the instances are deleted with their lease keys rather than expired,
+	// the tracker waits for the markers put after the deletion
+	if !backend.IsDryRun(ctx) {
+		lease.MarkRevokedAll(ctx, instances)
+	}
+	return &model.DeleteDomainResponse{
+		Response: pb.CreateResponse(pb.Response_SUCCESS, "Delete successfully"),
+		Deleted:  total,
+	}
+}
+
+func (service *AdminService) CreateProject(ctx context.Context, domain string, in *model.ProjectRequest) (*model.DomainResponse, error) {
+	if !core.IsDefaultDomainProject(util.ParseDomainProject(ctx)) {
+		return &model.DomainResponse{
+			Response: pb.CreateResponse(scerr.ErrForbidden, "Required admin permission"),
+		}, nil
+	}
+	if !nameRegex.MatchString(in.Name) {
+		return &model.DomainResponse{
+			Response: pb.CreateResponse(scerr.ErrInvalidParams, "Invalid project name '"+in.Name+"'"),
+		}, nil
+	}
+
+	info, err := serviceUtil.GetDomainInfo(ctx, domain)
+	if err != nil {
+		log.Errorf(err, "get domain '%s' failed", domain)
+		return &model.DomainResponse{
+			Response: pb.CreateResponse(scerr.ErrUnavailableBackend, err.Error()),
+		}, err
+	}
+	if info == nil {
+		return &model.DomainResponse{
+			Response: pb.CreateResponse(scerr.ErrInvalidParams, "Domain '"+domain+"' does not exist"),
+		}, nil
+	}
+	ok, err := serviceUtil.NewProject(ctx, domain, in.Name)
+	if err != nil {
+		log.Errorf(err, "create project '%s/%s' failed", domain, in.Name)
+		return &model.DomainResponse{
+			Response: pb.CreateResponse(scerr.ErrUnavailableBackend, err.Error()),
+		}, err
+	}
+	if !ok {
+		return &model.DomainResponse{
+			Response: pb.CreateResponse(scerr.ErrInvalidParams, "Project '"+in.Name+"' already exists"),
+		}, nil
+	}
+	log.Warnf("audit: project '%s/%s' is created by %s", domain, in.Name, util.GetIPFromContext(ctx))
+
+	return &model.DomainResponse{
+		Response: pb.CreateResponse(pb.Response_SUCCESS, "Create project successfully"),
+		Domain: &model.Domain{
+			Name:     domain,
+			Quotas:   info.Quotas,
+			CreateAt: info.CreateAt,
+			Projects: []*model.Project{{Name: in.Name}},
+		},
+	}, nil
+}
+
+func (service *AdminService) DeleteProject(ctx context.Context, domain, project string, in *model.DeleteRequest) (*model.DeleteDomainResponse, error) {
+	if !core.IsDefaultDomainProject(util.ParseDomainProject(ctx)) {
+		return &model.DeleteDomainResponse{
+			Response: pb.CreateResponse(scerr.ErrForbidden, "Required admin permission"),
+		}, nil
+	}
+	if domain == core.REGISTRY_DOMAIN && project == core.REGISTRY_PROJECT {
+		return &model.DeleteDomainResponse{
+			Response: pb.CreateResponse(scerr.ErrInvalidParams, "Can not delete the default project"),
+		}, nil
+	}
+
+	ok, err := serviceUtil.ProjectExist(noCache(ctx), domain, project)
+	if err != nil {
+		log.Errorf(err, "get project '%s/%s' failed", domain, project)
+		return &model.DeleteDomainResponse{
+			Response: pb.CreateResponse(scerr.ErrUnavailableBackend, err.Error()),
+		}, err
+	}
+	if !ok {
+		return &model.DeleteDomainResponse{
+			Response: pb.CreateResponse(scerr.ErrInvalidParams, "Project '"+project+"' does not exist"),
+		}, nil
+	}
+	revKey := core.GenerateProjectRevisionKey(domain, project)
+	resp := service.deleteProjects(ctx, project, domain+core.SPLIT+project, revKey, in,
+		registry.OpDel(registry.WithStrKey(core.GenerateProjectKey(domain, project))),
+		registry.OpDel(registry.WithStrKey(revKey)))
+	if resp.Response.Code == pb.Response_SUCCESS && !backend.IsDryRun(ctx) {
+		log.Warnf("audit: project '%s/%s' is deleted, cascade: %v, deleted %d services and %d instances by %s",
+			domain, project, in.Cascade, resp.Deleted.Services, resp.Deleted.Instances, util.GetIPFromContext(ctx))
+	}
+	return resp, nil
+}
@@ -0,0 +1,518 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package admin
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/admin/model"
+	"github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/core/backend"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/discovery"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	"github.com/apache/servicecomb-service-center/version"
+	"golang.org/x/net/context"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// MAX_RUNNING_EXPORTS limits the exports reading the whole registry
+	// at the same time
+	MAX_RUNNING_EXPORTS      = 1
+	DEFAULT_EXPORT_RETENTION = time.Hour
+	exportFilePrefix         = "sc-export-"
+)
+
+var (
+	ErrExportBusy        = errors.New("too many exports are running")
+	ErrExportNotFound    = errors.New("export does not exist")
+	ErrExportNotFinished = errors.New("export is not finished")
+
+	exporter = NewExporter(os.TempDir())
+)
+
+type exportResource struct {
+	Type    string
+	Adaptor func() discovery.Adaptor
+	RootKey func(domainProject string) string
+}
+
+// exportResources are exported in order, the services come before their
+// resources to make the imports easy
+var exportResources = []exportResource{
+	{model.EXPORT_TYPE_SERVICE, func() discovery.Adaptor { return backend.Store().Service() },
+		core.GetServiceRootKey},
+	{model.EXPORT_TYPE_SCHEMA, func() discovery.Adaptor { return backend.Store().Schema() },
+		core.GetServiceSchemaRootKey},
+	{model.EXPORT_TYPE_TAG, func() discovery.Adaptor { return backend.Store().ServiceTag() },
+		core.GetServiceTagRootKey},
+	{model.EXPORT_TYPE_RULE, func() discovery.Adaptor { return backend.Store().Rule() },
+		core.GetServiceRuleRootKey},
+	{model.EXPORT_TYPE_DEPENDENCY, func() discovery.Adaptor { return backend.Store().DependencyRule() },
+		core.GetServiceDependencyRuleRootKey},
+	{model.EXPORT_TYPE_INSTANCE, func() discovery.Adaptor { return backend.Store().Instance() },
+		core.GetInstanceRootKey},
+}
+
+type exportTask struct {
+	job  *model.ExportJob
+	file string
+}
+
+// Exporter runs the exports in background and keeps the files in Dir
+// for Retention after they finish
+type Exporter struct {
+	Dir       string
+	Retention time.Duration
+
+	lock    sync.RWMutex
+	tasks   map[string]*exportTask
+	running int
+}
+
+func (e *Exporter) Start(in *model.ExportRequest) (*model.ExportJob, error) {
+	format := in.Format
+	if len(format) == 0 {
+		format = model.EXPORT_FORMAT_NDJSON
+	}
+	if format != model.EXPORT_FORMAT_JSON && format != model.EXPORT_FORMAT_NDJSON {
+		return nil, fmt.Errorf("unsupported format '%s'", format)
+	}
+
+	e.clean()
+
+	e.lock.Lock()
+	if e.running >= MAX_RUNNING_EXPORTS {
+		e.lock.Unlock()
+		return nil, ErrExportBusy
+	}
+	id := util.GenerateUuid()
+	t := &exportTask{
+		job: &model.ExportJob{
+			Id:       id,
+			Status:   model.EXPORT_STATUS_RUNNING,
+			Format:   format,
+			Domains:  in.Domains,
+			Counts:   make(map[string]int64, len(exportResources)),
+			CreateAt: time.Now().UTC().Format(time.RFC3339),
+		},
+		file: filepath.Join(e.Dir, exportFilePrefix+id+"."+format),
+	}
+	e.tasks[id] = t
+	e.running++
+	job := copyJob(t.job)
+	e.lock.Unlock()
+
+	gopool.Go(func(ctx context.Context) {
+		e.run(ctx, t)
+	})
+	return job, nil
+}
+
+func (e *Exporter) run(ctx context.Context, t *exportTask) {
+	start := time.Now()
+	counts, err := e.export(ctx, t)
+
+	var size int64
+	if err == nil {
+		var fi os.FileInfo
+		if fi, err = os.Stat(t.file); err == nil {
+			size = fi.Size()
+		}
+	}
+	if err != nil {
+		log.Errorf(err, "export[%s] failed", t.job.Id)
+		os.Remove(t.file)
+	} else {
+		log.Infof("export[%s] finished, %d bytes, spend %s", t.job.Id, size, time.Since(start))
+	}
+
+	e.lock.Lock()
+	e.running--
+	t.job.Counts = counts
+	t.job.Size = size
+	t.job.FinishAt = time.Now().UTC().Format(time.RFC3339)
+	t.job.Status = model.EXPORT_STATUS_SUCCEEDED
+	if err != nil {
+		t.job.Status = model.EXPORT_STATUS_FAILED
+		t.job.Error = err.Error()
+	}
+	e.lock.Unlock()
+}
+
+// export writes the records to the file one by one, all the resources are
+// read at the same revision so the file is a consistent snapshot
+func (e *Exporter) export(ctx context.Context, t *exportTask) (map[string]int64, error) {
+	rev, err := registryRevision(ctx)
+	if err != nil {
+		return nil, err
+	}
+	domains := t.job.Domains
+	if len(domains) == 0 {
+		if domains, err = allDomains(ctx, rev); err != nil {
+			return nil, err
+		}
+	}
+
+	f, err := os.Create(t.file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	buf := bufio.NewWriter(f)
+	w := newExportWriter(t.job.Format, buf)
+	err = w.WriteHeader(&model.ExportHeader{
+		Version:       model.EXPORT_VERSION,
+		ServerVersion: version.Ver().Version,
+		Revision:      rev,
+		Domains:       domains,
+		CreateAt:      t.job.CreateAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(exportResources))
+	for _, domain := range domains {
+		for _, res := range exportResources {
+			n, err := exportResourceOf(ctx, w, res, domain, rev)
+			if err != nil {
+				return nil, err
+			}
+			counts[res.Type] += n
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	if err := buf.Flush(); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+func exportResourceOf(ctx context.Context, w exportWriter, res exportResource,
+	domain string, rev int64) (int64, error) {
+	prefix := res.RootKey(domain) + core.SPLIT
+	resp, err := res.Adaptor().Search(ctx,
+		registry.WithStrKey(prefix),
+		registry.WithPrefix(),
+		registry.WithRev(rev),
+		registry.WithNoCache())
+	if err != nil {
+		return 0, err
+	}
+	for _, kv := range resp.Kvs {
+		key := util.BytesToStringWithNoCopy(kv.Key)
+		value := kv.Value
+		if b, ok := value.([]byte); ok {
+			// the schema content is readable text
+			value = string(b)
+		}
+		err := w.WriteRecord(&model.ExportRecord{
+			Type:          res.Type,
+			DomainProject: domainProjectOf(domain, key[len(prefix):]),
+			Key:           key,
+			Rev:           kv.ModRevision,
+			Value:         value,
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+	return int64(len(resp.Kvs)), nil
+}
+
+func domainProjectOf(domain, subKey string) string {
+	if i := strings.Index(subKey, core.SPLIT); i >= 0 {
+		subKey = subKey[:i]
+	}
+	return domain + core.SPLIT + subKey
+}
+
+func registryRevision(ctx context.Context) (int64, error) {
+	resp, err := backend.Registry().Do(ctx, registry.GET,
+		registry.WithStrKey(core.GetRootKey()),
+		registry.WithCountOnly())
+	if err != nil {
+		return 0, err
+	}
+	return resp.Revision, nil
+}
+
+func allDomains(ctx context.Context, rev int64) ([]string, error) {
+	prefix := core.GetDomainRootKey() + core.SPLIT
+	resp, err := backend.Store().Domain().Search(ctx,
+		registry.WithStrKey(prefix),
+		registry.WithPrefix(),
+		registry.WithRev(rev),
+		registry.WithNoCache())
+	if err != nil {
+		return nil, err
+	}
+	domains := make([]string, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		domains = append(domains, util.BytesToStringWithNoCopy(kv.Key)[len(prefix):])
+	}
+	sort.Strings(domains)
+	return domains, nil
+}
+
+func (e *Exporter) Get(id string) (*model.ExportJob, bool) {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+	t, ok := e.tasks[id]
+	if !ok {
+		return nil, false
+	}
+	return copyJob(t.job), true
+}
+
+func (e *Exporter) List() []*model.ExportJob {
+	e.clean()
+
+	e.lock.RLock()
+	jobs := make([]*model.ExportJob, 0, len(e.tasks))
+	for _, t := range e.tasks {
+		jobs = append(jobs, copyJob(t.job))
+	}
+	e.lock.RUnlock()
+
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreateAt < jobs[j].CreateAt
+	})
+	return jobs
+}
+
+// Open returns the file of the succeeded export, the caller must close it
+func (e *Exporter) Open(id string) (io.ReadCloser, *model.ExportJob, error) {
+	e.lock.RLock()
+	t, ok := e.tasks[id]
+	if !ok {
+		e.lock.RUnlock()
+		return nil, nil, ErrExportNotFound
+	}
+	job, file := copyJob(t.job), t.file
+	e.lock.RUnlock()
+
+	if job.Status != model.EXPORT_STATUS_SUCCEEDED {
+		return nil, job, ErrExportNotFinished
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, job, err
+	}
+	return f, job, nil
+}
+
+// clean removes the finished exports and their files older than the
+// retention
+func (e *Exporter) clean() {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	for id, t := range e.tasks {
+		if t.job.Status == model.EXPORT_STATUS_RUNNING {
+			continue
+		}
+		finishAt, err := time.Parse(time.RFC3339, t.job.FinishAt)
+		if err != nil || time.Since(finishAt) < e.Retention {
+			continue
+		}
+		os.Remove(t.file)
+		delete(e.tasks, id)
+	}
+}
+
+func copyJob(job *model.ExportJob) *model.ExportJob {
+	c := *job
+	c.Counts = make(map[string]int64, len(job.Counts))
+	for k, v := range job.Counts {
+		c.Counts[k] = v
+	}
+	return &c
+}
+
+func NewExporter(dir string) *Exporter {
+	return &Exporter{
+		Dir:       dir,
+		Retention: DEFAULT_EXPORT_RETENTION,
+		tasks:     make(map[string]*exportTask),
+	}
+}
+
+type exportWriter interface {
+	WriteHeader(h *model.ExportHeader) error
+	WriteRecord(r *model.ExportRecord) error
+	Close() error
+}
+
+func newExportWriter(format string, w io.Writer) exportWriter {
+	if format == model.EXPORT_FORMAT_JSON {
+		return &jsonExportWriter{w: w}
+	}
+	return &ndjsonExportWriter{enc: json.NewEncoder(w)}
+}
+
+// ndjsonExportWriter writes the header and the records line by line
+type ndjsonExportWriter struct {
+	enc *json.Encoder
+}
+
+func (w *ndjsonExportWriter) WriteHeader(h *model.ExportHeader) error { return w.enc.Encode(h) }
+func (w *ndjsonExportWriter) WriteRecord(r *model.ExportRecord) error { return w.enc.Encode(r) }
+func (w *ndjsonExportWriter) Close() error                            { return nil }
+
+// jsonExportWriter writes a document like {"header":{},"records":[]}
+// without buffering the records in memory
+type jsonExportWriter struct {
+	w     io.Writer
+	count int
+}
+
+func (w *jsonExportWriter) WriteHeader(h *model.ExportHeader) error {
+	b, err := json.Marshal(h)
+	if err != nil {
+		return err
+	}
+	if _, err = io.WriteString(w.w, `{"header":`); err != nil {
+		return err
+	}
+	if _, err = w.w.Write(b); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w.w, `,"records":[`)
+	return err
+}
+
+func (w *jsonExportWriter) WriteRecord(r *model.ExportRecord) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	if w.count > 0 {
+		if _, err = io.WriteString(w.w, ","); err != nil {
+			return err
+		}
+	}
+	w.count++
+	_, err = w.w.Write(b)
+	return err
+}
+
+func (w *jsonExportWriter) Close() error {
+	_, err := io.WriteString(w.w, "]}")
+	return err
+}
+
+func (service *AdminService) CreateExport(ctx context.Context, in *model.ExportRequest) (*model.ExportResponse, error) {
+	if !core.IsDefaultDomainProject(util.ParseDomainProject(ctx)) {
+		return &model.ExportResponse{
+			Response: pb.CreateResponse(scerr.ErrForbidden, "Required admin permission"),
+		}, nil
+	}
+
+	for _, domain := range in.Domains {
+		if len(domain) == 0 || strings.Contains(domain, core.SPLIT) {
+			return &model.ExportResponse{
+				Response: pb.CreateResponse(scerr.ErrInvalidParams, "Invalid domain '"+domain+"'"),
+			}, nil
+		}
+	}
+
+	job, err := exporter.Start(in)
+	switch err {
+	case nil:
+	case ErrExportBusy:
+		return &model.ExportResponse{
+			Response: pb.CreateResponse(scerr.ErrForbidden, err.Error()),
+		}, nil
+	default:
+		return &model.ExportResponse{
+			Response: pb.CreateResponse(scerr.ErrInvalidParams, err.Error()),
+		}, nil
+	}
+	log.Infof("export[%s] of domains %v is started by %s", job.Id, job.Domains, util.GetIPFromContext(ctx))
+
+	return &model.ExportResponse{
+		Response: pb.CreateResponse(pb.Response_SUCCESS, "Create export successfully"),
+		Job:      job,
+	}, nil
+}
+
+func (service *AdminService) ListExports(ctx context.Context) (*model.ExportsResponse, error) {
+	if !core.IsDefaultDomainProject(util.ParseDomainProject(ctx)) {
+		return &model.ExportsResponse{
+			Response: pb.CreateResponse(scerr.ErrForbidden, "Required admin permission"),
+		}, nil
+	}
+
+	return &model.ExportsResponse{
+		Response: pb.CreateResponse(pb.Response_SUCCESS, "List exports successfully"),
+		Jobs:     exporter.List(),
+	}, nil
+}
+
+func (service *AdminService) GetExport(ctx context.Context, id string) (*model.ExportResponse, error) {
+	if !core.IsDefaultDomainProject(util.ParseDomainProject(ctx)) {
+		return &model.ExportResponse{
+			Response: pb.CreateResponse(scerr.ErrForbidden, "Required admin permission"),
+		}, nil
+	}
+
+	job, ok := exporter.Get(id)
+	if !ok {
+		return &model.ExportResponse{
+			Response: pb.CreateResponse(scerr.ErrInvalidParams, ErrExportNotFound.Error()),
+		}, nil
+	}
+	return &model.ExportResponse{
+		Response: pb.CreateResponse(pb.Response_SUCCESS, "Get export successfully"),
+		Job:      job,
+	}, nil
+}
+
+// OpenExport returns the file of the succeeded export to download
+func (service *AdminService) OpenExport(ctx context.Context, id string) (io.ReadCloser, *model.ExportJob, *pb.Response) {
+	if !core.IsDefaultDomainProject(util.ParseDomainProject(ctx)) {
+		return nil, nil, pb.CreateResponse(scerr.ErrForbidden, "Required admin permission")
+	}
+
+	r, job, err := exporter.Open(id)
+	switch err {
+	case nil:
+		return r, job, nil
+	case ErrExportNotFound, ErrExportNotFinished:
+		return nil, nil, pb.CreateResponse(scerr.ErrInvalidParams, err.Error())
+	default:
+		return nil, nil, pb.CreateResponse(scerr.ErrInternal, err.Error())
+	}
+}
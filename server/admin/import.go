@@ -0,0 +1,506 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package admin
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/admin/model"
+	"github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/core/backend"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	serviceUtil "github.com/apache/servicecomb-service-center/server/service/util"
+	"golang.org/x/net/context"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// MAX_IMPORT_ERRORS limits the error messages in the import result
+const MAX_IMPORT_ERRORS = 100
+
+var (
+	errKeyConflict   = errors.New("key already exists")
+	errIndexConflict = errors.New("service name and version belong to another service")
+)
+
+type importAction int
+
+const (
+	importCreate importAction = iota
+	importOverwrite
+	importSkip
+	importConflict
+)
+
+// importChange is the plan of a record, the ops are committed in a
+// transaction
+type importChange struct {
+	action        importAction
+	domainProject string
+	ops           []registry.PluginOp
+	// reason is the conflict reason
+	reason error
+}
+
+// importer scans the dump twice, the first scan validates all the records
+// and the second one writes them, so an invalid dump or a conflict with
+// the fail policy changes nothing
+type importer struct {
+	in     *model.ImportRequest
+	result *model.ImportResult
+	// skipped are the services not imported, their resources are
+	// skipped too
+	skipped        map[string]struct{}
+	domainProjects map[string]struct{}
+	invalid        int
+	conflicts      int
+}
+
+func newImporter(in *model.ImportRequest) *importer {
+	return &importer{
+		in: in,
+		result: &model.ImportResult{
+			DryRun: in.DryRun,
+			Policy: in.Policy,
+		},
+	}
+}
+
+func (im *importer) scan(ctx context.Context, file string, apply bool) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	im.result.Counts = make(map[string]*model.ImportCount, len(exportResources))
+	im.result.Errors = nil
+	im.skipped = make(map[string]struct{})
+	im.domainProjects = make(map[string]struct{})
+	im.invalid, im.conflicts = 0, 0
+
+	r := newImportReader(im.in.Format, bufio.NewReader(f))
+	header, err := r.Header()
+	if err != nil {
+		return fmt.Errorf("invalid header, %s", err.Error())
+	}
+	if header.Version != model.EXPORT_VERSION {
+		return fmt.Errorf("unsupported version '%s'", header.Version)
+	}
+	im.result.Header = header
+
+	for i := 1; ; i++ {
+		rec, err := r.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("invalid record %d, %s", i, err.Error())
+		}
+
+		c, err := im.plan(ctx, rec)
+		if err != nil {
+			im.invalid++
+			im.addError("record %d(%s): %s", i, rec.Key, err.Error())
+			continue
+		}
+		im.count(rec.Type, c.action)
+		if c.action == importConflict {
+			im.conflicts++
+			im.addError("record %d(%s): %s", i, rec.Key, c.reason.Error())
+		}
+
+		if !apply || (c.action != importCreate && c.action != importOverwrite) {
+			continue
+		}
+		if err := im.ensureDomainProject(ctx, c.domainProject); err != nil {
+			return err
+		}
+		ops := append(c.ops, serviceUtil.TouchRevisionOps(c.domainProject)...)
+		if err := backend.BatchCommit(ctx, ops); err != nil {
+			return fmt.Errorf("write record %d(%s) failed, %s", i, rec.Key, err.Error())
+		}
+	}
+}
+
+func (im *importer) plan(ctx context.Context, rec *model.ImportRecord) (*importChange, error) {
+	var res *exportResource
+	for i := range exportResources {
+		if exportResources[i].Type == rec.Type {
+			res = &exportResources[i]
+			break
+		}
+	}
+	if res == nil {
+		return nil, fmt.Errorf("unknown type '%s'", rec.Type)
+	}
+
+	domain, project := core.FromDomainProject(rec.DomainProject)
+	if len(domain) == 0 || len(project) == 0 {
+		return nil, fmt.Errorf("invalid domain project '%s'", rec.DomainProject)
+	}
+	oldRoot := res.RootKey(rec.DomainProject) + core.SPLIT
+	if !strings.HasPrefix(rec.Key, oldRoot) {
+		return nil, fmt.Errorf("key does not belong to '%s'", rec.DomainProject)
+	}
+	if d, ok := im.in.Mapping[domain]; ok {
+		domain = d
+	}
+	domainProject := core.ToDomainProject(domain, project)
+	sub := rec.Key[len(oldRoot):]
+	key := res.RootKey(domainProject) + core.SPLIT + sub
+	c := &importChange{domainProject: domainProject}
+
+	// the instances are runtime data, they register again after the
+	// environment is cloned
+	if rec.Type == model.EXPORT_TYPE_INSTANCE {
+		c.action = importSkip
+		return c, nil
+	}
+
+	serviceId := sub
+	if i := strings.Index(sub, core.SPLIT); i >= 0 {
+		serviceId = sub[:i]
+	}
+	serviceKey := domainProject + core.SPLIT + serviceId
+	if _, ok := im.skipped[serviceKey]; ok && rec.Type != model.EXPORT_TYPE_DEPENDENCY {
+		c.action = importSkip
+		return c, nil
+	}
+
+	value, extra, err := im.decode(ctx, rec, domainProject, serviceId)
+	if err == errIndexConflict {
+		c.action, c.reason = importConflict, err
+		im.skipped[serviceKey] = struct{}{}
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := backend.Registry().Do(ctx, registry.GET,
+		registry.WithStrKey(key), registry.WithCountOnly())
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case resp.Count == 0:
+		c.action = importCreate
+	case im.in.Policy == model.IMPORT_POLICY_OVERWRITE:
+		c.action = importOverwrite
+	case im.in.Policy == model.IMPORT_POLICY_FAIL:
+		c.action, c.reason = importConflict, errKeyConflict
+	default:
+		c.action = importSkip
+	}
+	if rec.Type == model.EXPORT_TYPE_SERVICE && (c.action == importSkip || c.action == importConflict) {
+		im.skipped[serviceKey] = struct{}{}
+	}
+
+	c.ops = append([]registry.PluginOp{
+		registry.OpPut(registry.WithStrKey(key), registry.WithValue(value)),
+	}, extra...)
+	return c, nil
+}
+
+// decode returns the value to write and the index ops of the record
+func (im *importer) decode(ctx context.Context, rec *model.ImportRecord,
+	domainProject, serviceId string) ([]byte, []registry.PluginOp, error) {
+	switch rec.Type {
+	case model.EXPORT_TYPE_SERVICE:
+		service := &pb.MicroService{}
+		if err := json.Unmarshal(rec.Value, service); err != nil {
+			return nil, nil, err
+		}
+		if service.ServiceId != serviceId {
+			return nil, nil, fmt.Errorf("service id '%s' does not match the key", service.ServiceId)
+		}
+		key := pb.MicroServiceToKey(domainProject, service)
+		indexKey := core.GenerateServiceIndexKey(key)
+		resp, err := backend.Registry().Do(ctx, registry.GET, registry.WithStrKey(indexKey))
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(resp.Kvs) > 0 && util.BytesToStringWithNoCopy(resp.Kvs[0].Value) != serviceId {
+			return nil, nil, errIndexConflict
+		}
+		ops := []registry.PluginOp{
+			registry.OpPut(registry.WithStrKey(indexKey), registry.WithStrValue(serviceId)),
+		}
+		if len(service.Alias) > 0 {
+			ops = append(ops, registry.OpPut(
+				registry.WithStrKey(core.GenerateServiceAliasKey(key)), registry.WithStrValue(serviceId)))
+		}
+		// the revisions exported are of the source registry
+		value, err := json.Marshal(service.WithoutRevision())
+		if err != nil {
+			return nil, nil, err
+		}
+		return value, ops, nil
+	case model.EXPORT_TYPE_SCHEMA:
+		var content string
+		if err := json.Unmarshal(rec.Value, &content); err != nil {
+			return nil, nil, err
+		}
+		return util.StringToBytesWithNoCopy(content), nil, nil
+	case model.EXPORT_TYPE_TAG:
+		tags := make(map[string]string)
+		if err := json.Unmarshal(rec.Value, &tags); err != nil {
+			return nil, nil, err
+		}
+		return rec.Value, nil, nil
+	case model.EXPORT_TYPE_RULE:
+		rule := &pb.ServiceRule{}
+		if err := json.Unmarshal(rec.Value, rule); err != nil {
+			return nil, nil, err
+		}
+		return rec.Value, []registry.PluginOp{
+			registry.OpPut(registry.WithStrKey(
+				core.GenerateRuleIndexKey(domainProject, serviceId, rule.Attribute, rule.Pattern)),
+				registry.WithStrValue(rule.RuleId)),
+		}, nil
+	case model.EXPORT_TYPE_DEPENDENCY:
+		dep := &pb.MicroServiceDependency{}
+		if err := json.Unmarshal(rec.Value, dep); err != nil {
+			return nil, nil, err
+		}
+		for _, key := range dep.Dependency {
+			domain, project := core.FromDomainProject(key.Tenant)
+			if d, ok := im.in.Mapping[domain]; ok {
+				key.Tenant = core.ToDomainProject(d, project)
+			}
+		}
+		value, err := json.Marshal(dep)
+		if err != nil {
+			return nil, nil, err
+		}
+		return value, nil, nil
+	}
+	return nil, nil, fmt.Errorf("unknown type '%s'", rec.Type)
+}
+
+func (im *importer) ensureDomainProject(ctx context.Context, domainProject string) error {
+	if _, ok := im.domainProjects[domainProject]; ok {
+		return nil
+	}
+	domain, project := core.FromDomainProject(domainProject)
+	if err := serviceUtil.NewDomainProject(ctx, domain, project); err != nil {
+		return err
+	}
+	im.domainProjects[domainProject] = struct{}{}
+	return nil
+}
+
+func (im *importer) count(t string, action importAction) {
+	c, ok := im.result.Counts[t]
+	if !ok {
+		c = &model.ImportCount{}
+		im.result.Counts[t] = c
+	}
+	switch action {
+	case importCreate:
+		c.Created++
+	case importOverwrite:
+		c.Overwritten++
+	case importSkip:
+		c.Skipped++
+	case importConflict:
+		c.Conflicts++
+	}
+}
+
+func (im *importer) addError(format string, args ...interface{}) {
+	if len(im.result.Errors) < MAX_IMPORT_ERRORS {
+		im.result.Errors = append(im.result.Errors, fmt.Sprintf(format, args...))
+	}
+}
+
+// Import loads the dump created by the export, the body is saved to a
+// temporary file before scanning
+func (service *AdminService) Import(ctx context.Context, in *model.ImportRequest, body io.Reader) (*model.ImportResponse, error) {
+	if !core.IsDefaultDomainProject(util.ParseDomainProject(ctx)) {
+		return &model.ImportResponse{
+			Response: pb.CreateResponse(scerr.ErrForbidden, "Required admin permission"),
+		}, nil
+	}
+
+	if len(in.Policy) == 0 {
+		in.Policy = model.IMPORT_POLICY_SKIP
+	}
+	if len(in.Format) == 0 {
+		in.Format = model.EXPORT_FORMAT_NDJSON
+	}
+	switch {
+	case in.Policy != model.IMPORT_POLICY_SKIP && in.Policy != model.IMPORT_POLICY_OVERWRITE &&
+		in.Policy != model.IMPORT_POLICY_FAIL:
+		return &model.ImportResponse{
+			Response: pb.CreateResponse(scerr.ErrInvalidParams, "Unsupported policy '"+in.Policy+"'"),
+		}, nil
+	case in.Format != model.EXPORT_FORMAT_JSON && in.Format != model.EXPORT_FORMAT_NDJSON:
+		return &model.ImportResponse{
+			Response: pb.CreateResponse(scerr.ErrInvalidParams, "Unsupported format '"+in.Format+"'"),
+		}, nil
+	}
+	for from, to := range in.Mapping {
+		if len(from) == 0 || len(to) == 0 || strings.Contains(to, core.SPLIT) {
+			return &model.ImportResponse{
+				Response: pb.CreateResponse(scerr.ErrInvalidParams, "Invalid mapping '"+from+"' to '"+to+"'"),
+			}, nil
+		}
+	}
+
+	f, err := ioutil.TempFile("", "sc-import-")
+	if err != nil {
+		log.Errorf(err, "create the import file failed")
+		return &model.ImportResponse{
+			Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
+		}, err
+	}
+	defer os.Remove(f.Name())
+	_, err = io.Copy(f, body)
+	f.Close()
+	if err != nil {
+		return &model.ImportResponse{
+			Response: pb.CreateResponse(scerr.ErrInvalidParams, err.Error()),
+		}, nil
+	}
+
+	im := newImporter(in)
+	if err := im.scan(ctx, f.Name(), false); err != nil {
+		return &model.ImportResponse{
+			Response: pb.CreateResponse(scerr.ErrInvalidParams, err.Error()),
+		}, nil
+	}
+	// the keys are reported instead if the request runs in dry run
+	if in.DryRun && !backend.IsDryRun(ctx) {
+		return &model.ImportResponse{
+			Response: pb.CreateResponse(pb.Response_SUCCESS, "Validate import successfully"),
+			Result:   im.result,
+		}, nil
+	}
+	if im.invalid > 0 || (in.Policy == model.IMPORT_POLICY_FAIL && im.conflicts > 0) {
+		return &model.ImportResponse{
+			Response: pb.CreateResponse(scerr.ErrInvalidParams, fmt.Sprintf(
+				"Import aborted, %d invalid and %d conflict records, %s",
+				im.invalid, im.conflicts, im.result.Errors[0])),
+		}, nil
+	}
+
+	if err := im.scan(ctx, f.Name(), true); err != nil {
+		log.Errorf(err, "import failed")
+		return &model.ImportResponse{
+			Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
+		}, err
+	}
+	log.Infof("import of domains %v with policy %s is done by %s",
+		im.result.Header.Domains, in.Policy, util.GetIPFromContext(ctx))
+
+	return &model.ImportResponse{
+		Response: pb.CreateResponse(pb.Response_SUCCESS, "Import successfully"),
+		Result:   im.result,
+	}, nil
+}
+
+// importReader reads the header and then the records of the dump, Next
+// returns io.EOF after the last record
+type importReader interface {
+	Header() (*model.ExportHeader, error)
+	Next() (*model.ImportRecord, error)
+}
+
+func newImportReader(format string, r io.Reader) importReader {
+	if format == model.EXPORT_FORMAT_JSON {
+		return &jsonImportReader{dec: json.NewDecoder(r)}
+	}
+	return &ndjsonImportReader{dec: json.NewDecoder(r)}
+}
+
+type ndjsonImportReader struct {
+	dec *json.Decoder
+}
+
+func (r *ndjsonImportReader) Header() (*model.ExportHeader, error) {
+	h := &model.ExportHeader{}
+	if err := r.dec.Decode(h); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+func (r *ndjsonImportReader) Next() (*model.ImportRecord, error) {
+	rec := &model.ImportRecord{}
+	if err := r.dec.Decode(rec); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+// jsonImportReader walks the document written by jsonExportWriter token
+// by token
+type jsonImportReader struct {
+	dec *json.Decoder
+}
+
+func (r *jsonImportReader) expect(want json.Token) error {
+	tok, err := r.dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != want {
+		return fmt.Errorf("unexpected token '%v', expect '%v'", tok, want)
+	}
+	return nil
+}
+
+func (r *jsonImportReader) Header() (*model.ExportHeader, error) {
+	if err := r.expect(json.Delim('{')); err != nil {
+		return nil, err
+	}
+	if err := r.expect("header"); err != nil {
+		return nil, err
+	}
+	h := &model.ExportHeader{}
+	if err := r.dec.Decode(h); err != nil {
+		return nil, err
+	}
+	if err := r.expect("records"); err != nil {
+		return nil, err
+	}
+	if err := r.expect(json.Delim('[')); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+func (r *jsonImportReader) Next() (*model.ImportRecord, error) {
+	if !r.dec.More() {
+		return nil, io.EOF
+	}
+	rec := &model.ImportRecord{}
+	if err := r.dec.Decode(rec); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
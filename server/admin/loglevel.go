@@ -0,0 +1,85 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package admin
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/admin/model"
+	"github.com/apache/servicecomb-service-center/server/core"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/reload"
+	"golang.org/x/net/context"
+)
+
+func init() {
+	reload.Register(reload.Option{
+		Key:      "loglevel",
+		Validate: log.CheckLevel,
+		Apply: func(level string) {
+			log.SetLevel(level)
+			core.ServerInfo.Config.LogLevel = log.GetLevel()
+		},
+	})
+}
+
+func (service *AdminService) GetLogLevel(ctx context.Context) (*model.LogLevelResponse, error) {
+	if !core.IsDefaultDomainProject(util.ParseDomainProject(ctx)) {
+		return &model.LogLevelResponse{
+			Response: pb.CreateResponse(scerr.ErrForbidden, "Required admin permission"),
+		}, nil
+	}
+	return &model.LogLevelResponse{
+		Response: pb.CreateResponse(pb.Response_SUCCESS, "Get log level successfully"),
+		Level:    log.GetLevel(),
+		Modules:  log.ModuleLevels(),
+	}, nil
+}
+
+// SetLogLevel changes the global and the modules log level at runtime,
+// the changes are lost after restart
+func (service *AdminService) SetLogLevel(ctx context.Context, in *model.LogLevelRequest) (*model.LogLevelResponse, error) {
+	if !core.IsDefaultDomainProject(util.ParseDomainProject(ctx)) {
+		return &model.LogLevelResponse{
+			Response: pb.CreateResponse(scerr.ErrForbidden, "Required admin permission"),
+		}, nil
+	}
+
+	if len(in.Level) > 0 {
+		if err := log.SetLevel(in.Level); err != nil {
+			return &model.LogLevelResponse{
+				Response: pb.CreateResponse(scerr.ErrInvalidParams, err.Error()),
+			}, nil
+		}
+	}
+	for module, level := range in.Modules {
+		if err := log.SetModuleLevel(module, level); err != nil {
+			return &model.LogLevelResponse{
+				Response: pb.CreateResponse(scerr.ErrInvalidParams, "module["+module+"] "+err.Error()),
+			}, nil
+		}
+	}
+	log.Warnf("log level is changed to %s, modules: %v, by %s",
+		log.GetLevel(), log.ModuleLevels(), util.GetIPFromContext(ctx))
+
+	return &model.LogLevelResponse{
+		Response: pb.CreateResponse(pb.Response_SUCCESS, "Set log level successfully"),
+		Level:    log.GetLevel(),
+		Modules:  log.ModuleLevels(),
+	}, nil
+}
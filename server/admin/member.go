@@ -0,0 +1,236 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package admin
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/admin/model"
+	"github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/core/backend"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/health"
+	"github.com/apache/servicecomb-service-center/server/metric"
+	"github.com/apache/servicecomb-service-center/server/peer"
+	serviceUtil "github.com/apache/servicecomb-service-center/server/service/util"
+	"github.com/apache/servicecomb-service-center/version"
+	"golang.org/x/net/context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	HEALTH_UNKNOWN = "UNKNOWN"
+
+	metricRequestRate = "http_query_per_seconds"
+	metricRenewTotal  = "lease_renew_total"
+	metricInstances   = "db_instance_total"
+)
+
+var (
+	startTime = time.Now()
+	heartbeat = &heartbeatReporter{}
+)
+
+func init() {
+	metric.RegisterReporter("member", heartbeat)
+}
+
+// heartbeatReporter calculates the heartbeat rate by the lease renewals
+// gathered in the last metrics period, the first period has no rate
+type heartbeatReporter struct {
+	last    float64
+	started bool
+	rate    atomic.Value
+}
+
+func (r *heartbeatReporter) Report() {
+	total := metric.Gatherer.Records.Summary(metricRenewTotal)
+	if r.started && total >= r.last {
+		r.rate.Store((total - r.last) / metric.Period.Seconds())
+	}
+	r.last, r.started = total, true
+}
+
+func (r *heartbeatReporter) Rate() float64 {
+	rate, _ := r.rate.Load().(float64)
+	return rate
+}
+
+// SelfStatus returns the status of this instance, the members API calls
+// it on each peer
+func (service *AdminService) SelfStatus(ctx context.Context) (*model.MemberResponse, error) {
+	if !core.IsDefaultDomainProject(util.ParseDomainProject(ctx)) {
+		return &model.MemberResponse{
+			Response: pb.CreateResponse(scerr.ErrForbidden, "Required admin permission"),
+		}, nil
+	}
+
+	return &model.MemberResponse{
+		Response: pb.CreateResponse(pb.Response_SUCCESS, "Get self status successfully"),
+		Member:   selfStatus(ctx),
+	}, nil
+}
+
+func selfStatus(ctx context.Context) *model.MemberStatus {
+	return &model.MemberStatus{
+		InstanceId:    core.Instance.InstanceId,
+		Version:       version.Ver().Version,
+		HostName:      core.Instance.HostName,
+		Endpoints:     core.Instance.Endpoints,
+		Status:        core.Instance.Status,
+		StartAt:       startTime.UTC().Format(time.RFC3339),
+		Uptime:        int64(time.Since(startTime) / time.Second),
+		CacheRevision: backend.Revision(),
+		Health:        health.DeepCheck(ctx).Status,
+		Metrics: &model.MemberMetrics{
+			RequestRate:   metric.Gatherer.Records.Summary(metricRequestRate),
+			HeartbeatRate: heartbeat.Rate(),
+			Instances:     int64(metric.Gatherer.Records.Summary(metricInstances)),
+		},
+		Self: true,
+	}
+}
+
+// Members lists the service center instances of all the versions
+// registered in the default domain project with their status
+func (service *AdminService) Members(ctx context.Context) (*model.MembersResponse, error) {
+	if !core.IsDefaultDomainProject(util.ParseDomainProject(ctx)) {
+		return &model.MembersResponse{
+			Response: pb.CreateResponse(scerr.ErrForbidden, "Required admin permission"),
+		}, nil
+	}
+
+	members, err := listMembers(ctx)
+	if err != nil {
+		log.Errorf(err, "list the members of service center failed")
+		return &model.MembersResponse{
+			Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
+		}, err
+	}
+
+	var wg sync.WaitGroup
+	for _, m := range members {
+		if m.InstanceId == core.Instance.InstanceId {
+			*m = *selfStatus(ctx)
+			continue
+		}
+		wg.Add(1)
+		go func(m *model.MemberStatus) {
+			defer wg.Done()
+			fillPeerStatus(ctx, m)
+		}(m)
+	}
+	wg.Wait()
+
+	return &model.MembersResponse{
+		Response: pb.CreateResponse(pb.Response_SUCCESS, "List members successfully"),
+		Members:  members,
+		Summary:  summarize(members),
+	}, nil
+}
+
+func listMembers(ctx context.Context) ([]*model.MemberStatus, error) {
+	cacheCtx := util.SetContext(util.CloneContext(ctx), serviceUtil.CTX_CACHEONLY, "1")
+	services, err := serviceUtil.GetServicesByDomainProject(cacheCtx, core.REGISTRY_DOMAIN_PROJECT)
+	if err != nil {
+		return nil, err
+	}
+	var members []*model.MemberStatus
+	for _, service := range services {
+		if service.AppId != core.Service.AppId || service.ServiceName != core.Service.ServiceName ||
+			service.Environment != core.Service.Environment {
+			continue
+		}
+		instances, err := serviceUtil.GetAllInstancesOfOneService(cacheCtx,
+			core.REGISTRY_DOMAIN_PROJECT, service.ServiceId)
+		if err != nil {
+			return nil, err
+		}
+		for _, instance := range instances {
+			members = append(members, &model.MemberStatus{
+				InstanceId: instance.InstanceId,
+				Version:    service.Version,
+				HostName:   instance.HostName,
+				Endpoints:  instance.Endpoints,
+				Status:     instance.Status,
+				Health:     HEALTH_UNKNOWN,
+			})
+		}
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].HostName < members[j].HostName
+	})
+	return members, nil
+}
+
+// fillPeerStatus asks the peer for the status reported by itself, the
+// health is UNKNOWN if the peer is unreachable
+func fillPeerStatus(ctx context.Context, m *model.MemberStatus) {
+	endpoint := peer.RestEndpoint(m.Endpoints)
+	if len(endpoint) == 0 {
+		m.Error = "no rest endpoint"
+		return
+	}
+	client, err := peer.GetTransport().Client(endpoint)
+	if err != nil {
+		m.Error = err.Error()
+		return
+	}
+	status, scErr := client.GetMemberStatus(ctx)
+	if scErr != nil {
+		m.Error = scErr.Error()
+		return
+	}
+	m.Version = status.Version
+	m.StartAt = status.StartAt
+	m.Uptime = status.Uptime
+	m.CacheRevision = status.CacheRevision
+	m.Health = status.Health
+	m.Metrics = status.Metrics
+}
+
+func summarize(members []*model.MemberStatus) *model.MembersSummary {
+	s := &model.MembersSummary{
+		Total:    len(members),
+		Versions: make(map[string]int),
+		Health:   make(map[string]int),
+	}
+	var min, max int64
+	for _, m := range members {
+		s.Versions[m.Version]++
+		s.Health[m.Health]++
+		if m.Health == health.STATUS_UP {
+			s.Healthy++
+		}
+		if m.Health == HEALTH_UNKNOWN {
+			continue
+		}
+		if min == 0 || m.CacheRevision < min {
+			min = m.CacheRevision
+		}
+		if m.CacheRevision > max {
+			max = m.CacheRevision
+		}
+	}
+	s.RevisionSpread = max - min
+	s.Converged = s.Total > 0 && s.Healthy == s.Total && len(s.Versions) == 1
+	return s
+}
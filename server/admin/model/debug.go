@@ -0,0 +1,69 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package model
+
+import (
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+)
+
+type DebugRequest struct {
+	Enabled bool `json:"enabled"`
+	// BlockProfileRate and MutexProfileFraction are passed to the
+	// runtime when enabled, 0 keeps the current value
+	BlockProfileRate     int `json:"blockProfileRate,omitempty"`
+	MutexProfileFraction int `json:"mutexProfileFraction,omitempty"`
+}
+
+type DebugResponse struct {
+	Response *pb.Response `json:"response,omitempty"`
+	Enabled  bool         `json:"enabled"`
+}
+
+type RuntimeRequest struct {
+}
+
+type GCStats struct {
+	NumGC      int64    `json:"numGC"`
+	LastGC     string   `json:"lastGC,omitempty"`
+	PauseTotal string   `json:"pauseTotal"`
+	Pauses     []string `json:"pauses,omitempty"`
+}
+
+type MemStats struct {
+	Alloc        uint64 `json:"alloc"`
+	TotalAlloc   uint64 `json:"totalAlloc"`
+	Sys          uint64 `json:"sys"`
+	HeapAlloc    uint64 `json:"heapAlloc"`
+	HeapInuse    uint64 `json:"heapInuse"`
+	HeapIdle     uint64 `json:"heapIdle"`
+	HeapReleased uint64 `json:"heapReleased"`
+	HeapObjects  uint64 `json:"heapObjects"`
+	StackInuse   uint64 `json:"stackInuse"`
+	NextGC       uint64 `json:"nextGC"`
+}
+
+type RuntimeResponse struct {
+	Response     *pb.Response `json:"response,omitempty"`
+	GoVersion    string       `json:"goVersion"`
+	NumCPU       int          `json:"numCPU"`
+	GoMaxProcs   int          `json:"goMaxProcs"`
+	NumGoroutine int          `json:"numGoroutine"`
+	NumCgoCall   int64        `json:"numCgoCall"`
+	Memory       *MemStats    `json:"memory,omitempty"`
+	GC           *GCStats     `json:"gc,omitempty"`
+	Profiles     []string     `json:"profiles,omitempty"`
+}
@@ -0,0 +1,80 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package model
+
+import (
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+)
+
+const (
+	DOMAIN_QUOTA_SERVICE  = "service"
+	DOMAIN_QUOTA_INSTANCE = "instance"
+)
+
+type ResourceCounts struct {
+	Services  int64 `json:"services"`
+	Instances int64 `json:"instances"`
+}
+
+type Project struct {
+	Name   string         `json:"name"`
+	Counts ResourceCounts `json:"counts"`
+}
+
+type Domain struct {
+	Name string `json:"name"`
+	// Quotas are the max services and instances of the domain, the
+	// domains created on first use have no quotas
+	Quotas   map[string]int64 `json:"quotas,omitempty"`
+	CreateAt string           `json:"createAt,omitempty"`
+	Counts   ResourceCounts   `json:"counts"`
+	Projects []*Project       `json:"projects"`
+}
+
+type DomainRequest struct {
+	Name   string           `json:"name,omitempty"`
+	Quotas map[string]int64 `json:"quotas,omitempty"`
+	// Projects are created with the domain, default is 'default'
+	Projects []string `json:"projects,omitempty"`
+}
+
+type ProjectRequest struct {
+	Name string `json:"name"`
+}
+
+// DeleteRequest guards the cascade deletion, the Confirm must be the
+// name of the domain or project deleted
+type DeleteRequest struct {
+	Cascade bool
+	Confirm string
+}
+
+type DomainsResponse struct {
+	Response *pb.Response `json:"response,omitempty"`
+	Domains  []*Domain    `json:"domains"`
+}
+
+type DomainResponse struct {
+	Response *pb.Response `json:"response,omitempty"`
+	Domain   *Domain      `json:"domain,omitempty"`
+}
+
+type DeleteDomainResponse struct {
+	Response *pb.Response `json:"response,omitempty"`
+	// Deleted are the counts of the resources deleted in cascade
+	Deleted ResourceCounts `json:"deleted"`
+}
@@ -211,8 +211,13 @@ type Instance struct {
 	Value *pb.MicroServiceInstance `json:"value,omitempty"`
 }
 
+// DUMP_SOURCE_BACKEND dumps the resources from the backend instead of
+// the cache
+const DUMP_SOURCE_BACKEND = "backend"
+
 type DumpRequest struct {
 	Options []string
+	Source  string
 }
 
 type DumpResponse struct {
@@ -0,0 +1,95 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package model
+
+import (
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+)
+
+// EXPORT_VERSION is the version of the export format, increase it when
+// the layout of the records changes incompatibly
+const EXPORT_VERSION = "1"
+
+const (
+	EXPORT_FORMAT_JSON   = "json"
+	EXPORT_FORMAT_NDJSON = "ndjson"
+)
+
+const (
+	EXPORT_STATUS_RUNNING   = "RUNNING"
+	EXPORT_STATUS_SUCCEEDED = "SUCCEEDED"
+	EXPORT_STATUS_FAILED    = "FAILED"
+)
+
+const (
+	EXPORT_TYPE_SERVICE    = "service"
+	EXPORT_TYPE_INSTANCE   = "instance"
+	EXPORT_TYPE_SCHEMA     = "schema"
+	EXPORT_TYPE_TAG        = "tag"
+	EXPORT_TYPE_RULE       = "rule"
+	EXPORT_TYPE_DEPENDENCY = "dependency"
+)
+
+type ExportRequest struct {
+	// Domains are the domains to export, empty means all
+	Domains []string `json:"domains,omitempty"`
+	// Format is json or ndjson, default is ndjson
+	Format string `json:"format,omitempty"`
+}
+
+type ExportJob struct {
+	Id      string   `json:"id"`
+	Status  string   `json:"status"`
+	Format  string   `json:"format"`
+	Domains []string `json:"domains,omitempty"`
+	// Counts is the number of records exported per type
+	Counts   map[string]int64 `json:"counts,omitempty"`
+	Size     int64            `json:"size"`
+	Error    string           `json:"error,omitempty"`
+	CreateAt string           `json:"createAt"`
+	FinishAt string           `json:"finishAt,omitempty"`
+}
+
+// ExportHeader is the first line of the ndjson file or the head fields
+// of the json document
+type ExportHeader struct {
+	Version       string   `json:"version"`
+	ServerVersion string   `json:"serverVersion"`
+	Revision      int64    `json:"revision"`
+	Domains       []string `json:"domains"`
+	CreateAt      string   `json:"createAt"`
+}
+
+// ExportRecord is a line of the ndjson file or an element of the records
+// array of the json document
+type ExportRecord struct {
+	Type          string      `json:"type"`
+	DomainProject string      `json:"domainProject"`
+	Key           string      `json:"key"`
+	Rev           int64       `json:"rev"`
+	Value         interface{} `json:"value,omitempty"`
+}
+
+type ExportResponse struct {
+	Response *pb.Response `json:"response,omitempty"`
+	Job      *ExportJob   `json:"job,omitempty"`
+}
+
+type ExportsResponse struct {
+	Response *pb.Response `json:"response,omitempty"`
+	Jobs     []*ExportJob `json:"jobs,omitempty"`
+}
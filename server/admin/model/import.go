@@ -0,0 +1,73 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package model
+
+import (
+	"encoding/json"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+)
+
+const (
+	IMPORT_POLICY_SKIP      = "skip"
+	IMPORT_POLICY_OVERWRITE = "overwrite"
+	IMPORT_POLICY_FAIL      = "fail"
+)
+
+type ImportRequest struct {
+	// Policy is the way to handle the existing keys, skip, overwrite or
+	// fail, default is skip
+	Policy string `json:"policy,omitempty"`
+	// Format is json or ndjson, default is ndjson
+	Format string `json:"format,omitempty"`
+	// DryRun validates the dump and reports the changes without writing
+	DryRun bool `json:"dryRun,omitempty"`
+	// Mapping renames the domains of the dump to the target domains
+	Mapping map[string]string `json:"mapping,omitempty"`
+}
+
+// ImportRecord is the ExportRecord read from the dump, the value is
+// decoded by type
+type ImportRecord struct {
+	Type          string          `json:"type"`
+	DomainProject string          `json:"domainProject"`
+	Key           string          `json:"key"`
+	Rev           int64           `json:"rev"`
+	Value         json.RawMessage `json:"value,omitempty"`
+}
+
+type ImportCount struct {
+	Created     int64 `json:"created"`
+	Overwritten int64 `json:"overwritten"`
+	Skipped     int64 `json:"skipped"`
+	Conflicts   int64 `json:"conflicts"`
+}
+
+type ImportResult struct {
+	DryRun bool          `json:"dryRun"`
+	Policy string        `json:"policy"`
+	Header *ExportHeader `json:"header,omitempty"`
+	// Counts is the changes per type, the conflicts are the existing
+	// keys with the fail policy or the services whose name and version
+	// belong to another service
+	Counts map[string]*ImportCount `json:"counts,omitempty"`
+	Errors []string                `json:"errors,omitempty"`
+}
+
+type ImportResponse struct {
+	Response *pb.Response  `json:"response,omitempty"`
+	Result   *ImportResult `json:"result,omitempty"`
+}
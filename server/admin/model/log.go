@@ -0,0 +1,35 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package model
+
+import (
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+)
+
+type LogLevelRequest struct {
+	// Level is the global log level, empty keeps the current level
+	Level string `json:"level,omitempty"`
+	// Modules maps the package path pattern like 'notification' to the
+	// level of its logs, the empty level removes the module
+	Modules map[string]string `json:"modules,omitempty"`
+}
+
+type LogLevelResponse struct {
+	Response *pb.Response      `json:"response,omitempty"`
+	Level    string            `json:"level"`
+	Modules  map[string]string `json:"modules,omitempty"`
+}
@@ -0,0 +1,78 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package model
+
+import (
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+)
+
+// MemberStatus is the status of a service center instance, the version,
+// the host and the endpoints come from the registry, the others are
+// reported by the member itself
+type MemberStatus struct {
+	InstanceId string   `json:"instanceId"`
+	Version    string   `json:"version"`
+	HostName   string   `json:"hostName"`
+	Endpoints  []string `json:"endpoints,omitempty"`
+	// Status is the instance status in the registry
+	Status string `json:"status"`
+	// StartAt is the time when the process starts, Uptime is in seconds
+	StartAt       string `json:"startAt,omitempty"`
+	Uptime        int64  `json:"uptime"`
+	CacheRevision int64  `json:"cacheRevision"`
+	// Health is UP, DEGRADED or DOWN of the deep health check, UNKNOWN if
+	// the member is unreachable
+	Health  string         `json:"health"`
+	Metrics *MemberMetrics `json:"metrics,omitempty"`
+	Error   string         `json:"error,omitempty"`
+	Self    bool           `json:"self,omitempty"`
+}
+
+// MemberMetrics are gathered by the member in the last metrics period,
+// the rates are per second
+type MemberMetrics struct {
+	RequestRate   float64 `json:"requestRate"`
+	HeartbeatRate float64 `json:"heartbeatRate"`
+	// Instances is the number of the instances in the cache of the member
+	Instances int64 `json:"instances"`
+}
+
+// MembersSummary is the aggregate for the dashboards, it tells whether
+// a rolling upgrade finishes
+type MembersSummary struct {
+	Total    int            `json:"total"`
+	Healthy  int            `json:"healthy"`
+	Versions map[string]int `json:"versions"`
+	Health   map[string]int `json:"health"`
+	// RevisionSpread is the difference between the max and the min cache
+	// revision of the reachable members
+	RevisionSpread int64 `json:"revisionSpread"`
+	// Converged is true if all the members are healthy and run the same
+	// version
+	Converged bool `json:"converged"`
+}
+
+type MembersResponse struct {
+	Response *pb.Response    `json:"response,omitempty"`
+	Members  []*MemberStatus `json:"members,omitempty"`
+	Summary  *MembersSummary `json:"summary,omitempty"`
+}
+
+type MemberResponse struct {
+	Response *pb.Response  `json:"response,omitempty"`
+	Member   *MemberStatus `json:"member,omitempty"`
+}
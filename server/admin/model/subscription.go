@@ -0,0 +1,40 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	nf "github.com/apache/servicecomb-service-center/server/service/notification"
+)
+
+type SubscriptionsRequest struct {
+	// Type is the notify type, INSTANCE or RESOURCE, empty means all
+	Type  string `json:"type,omitempty"`
+	Group string `json:"group,omitempty"`
+}
+
+type SubscriptionsResponse struct {
+	Response      *pb.Response       `json:"response,omitempty"`
+	Subscriptions []*nf.Subscription `json:"subscriptions,omitempty"`
+}
+
+type TerminateSubscriptionRequest struct {
+	Id string `json:"id,omitempty"`
+}
+
+type TerminateSubscriptionResponse struct {
+	Response *pb.Response `json:"response,omitempty"`
+}
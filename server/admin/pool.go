@@ -0,0 +1,124 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package admin
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/admin/model"
+	"github.com/apache/servicecomb-service-center/server/core"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/metric"
+	"github.com/apache/servicecomb-service-center/server/reload"
+	"github.com/astaxie/beego"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/net/context"
+	"time"
+)
+
+var (
+	poolTaskWait = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metric.FamilyName,
+			Subsystem: "gopool",
+			Name:      "task_wait_seconds",
+			Help:      "Histogram of the time the tasks waiting for the free workers",
+			Buckets:   prometheus.ExponentialBuckets(0.0005, 4, 8),
+		}, []string{"instance", "pool"})
+
+	poolTaskExec = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metric.FamilyName,
+			Subsystem: "gopool",
+			Name:      "task_exec_seconds",
+			Help:      "Histogram of the time the tasks executing",
+			Buckets:   prometheus.ExponentialBuckets(0.001, 4, 8),
+		}, []string{"instance", "pool"})
+
+	poolWorkersDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metric.FamilyName, "gopool", "workers"),
+		"Gauge of the running workers of the pool", []string{"instance", "pool"}, nil)
+	poolMaxWorkersDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metric.FamilyName, "gopool", "max_workers"),
+		"Gauge of the max workers of the pool", []string{"instance", "pool"}, nil)
+	poolQueuedDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metric.FamilyName, "gopool", "queued_tasks"),
+		"Gauge of the tasks waiting in the queue of the pool", []string{"instance", "pool"}, nil)
+	poolPanicsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metric.FamilyName, "gopool", "panics_total"),
+		"Counter of the panics recovered in the tasks of the pool", []string{"instance", "pool"}, nil)
+)
+
+func init() {
+	prometheus.MustRegister(poolTaskWait, poolTaskExec, poolCollector{})
+	gopool.SetTaskObserver(func(pool string, wait, exec time.Duration) {
+		instance := metric.InstanceName()
+		poolTaskWait.WithLabelValues(instance, pool).Observe(wait.Seconds())
+		poolTaskExec.WithLabelValues(instance, pool).Observe(exec.Seconds())
+	})
+
+	resizeDefaultPool()
+	reload.Register(reload.Option{Key: "gopool_max_workers", Validate: reload.Int(1),
+		Apply: func(string) { resizeDefaultPool() }})
+}
+
+// resizeDefaultPool applies 'gopool_max_workers' to the default pool,
+// which runs the heartbeats, notifications and the other short tasks
+func resizeDefaultPool() {
+	max := beego.AppConfig.DefaultInt("gopool_max_workers", gopool.GlobalConfig.Concurrent)
+	if max <= 0 {
+		max = gopool.GlobalConfig.Concurrent
+	}
+	gopool.Default().Resize(max)
+}
+
+// poolCollector reports the utilization of the named pools when the
+// metrics are scraped
+type poolCollector struct {
+}
+
+func (c poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- poolWorkersDesc
+	ch <- poolMaxWorkersDesc
+	ch <- poolQueuedDesc
+	ch <- poolPanicsDesc
+}
+
+func (c poolCollector) Collect(ch chan<- prometheus.Metric) {
+	instance := metric.InstanceName()
+	for _, s := range gopool.Stats() {
+		ch <- prometheus.MustNewConstMetric(poolWorkersDesc, prometheus.GaugeValue, float64(s.Workers), instance, s.Name)
+		ch <- prometheus.MustNewConstMetric(poolMaxWorkersDesc, prometheus.GaugeValue, float64(s.MaxWorkers), instance, s.Name)
+		ch <- prometheus.MustNewConstMetric(poolQueuedDesc, prometheus.GaugeValue, float64(s.Queued), instance, s.Name)
+		ch <- prometheus.MustNewConstMetric(poolPanicsDesc, prometheus.CounterValue, float64(s.Panics), instance, s.Name)
+	}
+}
+
+func (service *AdminService) Pools(ctx context.Context, in *model.PoolsRequest) (*model.PoolsResponse, error) {
+	domainProject := util.ParseDomainProject(ctx)
+	if !core.IsDefaultDomainProject(domainProject) {
+		return &model.PoolsResponse{
+			Response: pb.CreateResponse(scerr.ErrForbidden, "Required admin permission"),
+		}, nil
+	}
+
+	return &model.PoolsResponse{
+		Response: pb.CreateResponse(pb.Response_SUCCESS, "List pools successfully"),
+		Pools:    gopool.Stats(),
+	}, nil
+}
@@ -0,0 +1,69 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package admin
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/admin/model"
+	"github.com/apache/servicecomb-service-center/server/core"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/reload"
+	"golang.org/x/net/context"
+)
+
+// ReloadConfig reads the config file again and applies the changed values
+// of the reloadable keys, the reload is rejected if any value is invalid
+func (service *AdminService) ReloadConfig(ctx context.Context) (*model.ConfigReloadResponse, error) {
+	if !core.IsDefaultDomainProject(util.ParseDomainProject(ctx)) {
+		return &model.ConfigReloadResponse{
+			Response: pb.CreateResponse(scerr.ErrForbidden, "Required admin permission"),
+		}, nil
+	}
+
+	changes, err := reload.Reload(util.GetIPFromContext(ctx))
+	switch err.(type) {
+	case nil:
+	case reload.InvalidError:
+		return &model.ConfigReloadResponse{
+			Response: pb.CreateResponse(scerr.ErrInvalidParams, err.Error()),
+		}, nil
+	default:
+		return &model.ConfigReloadResponse{
+			Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
+		}, err
+	}
+	return &model.ConfigReloadResponse{
+		Response: pb.CreateResponse(pb.Response_SUCCESS, "Reload config successfully"),
+		Changes:  changes,
+	}, nil
+}
+
+// GetConfigChanges returns the reloadable keys and the latest changes
+// since startup
+func (service *AdminService) GetConfigChanges(ctx context.Context) (*model.ConfigChangesResponse, error) {
+	if !core.IsDefaultDomainProject(util.ParseDomainProject(ctx)) {
+		return &model.ConfigChangesResponse{
+			Response: pb.CreateResponse(scerr.ErrForbidden, "Required admin permission"),
+		}, nil
+	}
+	return &model.ConfigChangesResponse{
+		Response: pb.CreateResponse(pb.Response_SUCCESS, "Get config changes successfully"),
+		Keys:     reload.Keys(),
+		Changes:  reload.History(),
+	}, nil
+}
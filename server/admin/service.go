@@ -18,6 +18,7 @@ package admin
 
 import (
 	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/pkg/util"
 	"github.com/apache/servicecomb-service-center/server/admin/model"
 	"github.com/apache/servicecomb-service-center/server/core"
@@ -26,6 +27,7 @@ import (
 	scerr "github.com/apache/servicecomb-service-center/server/error"
 	"github.com/apache/servicecomb-service-center/server/plugin/pkg/discovery"
 	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	nf "github.com/apache/servicecomb-service-center/server/service/notification"
 	"github.com/apache/servicecomb-service-center/version"
 	"github.com/astaxie/beego"
 	"golang.org/x/net/context"
@@ -69,7 +71,16 @@ func (service *AdminService) Dump(ctx context.Context, in *model.DumpRequest) (*
 		}, nil
 	}
 
-	service.dumpAll(ctx, &cache)
+	if in.Source == model.DUMP_SOURCE_BACKEND {
+		if err := service.dumpBackend(ctx, &cache); err != nil {
+			log.Errorf(err, "dump the backend failed")
+			return &model.DumpResponse{
+				Response: pb.CreateResponse(scerr.ErrUnavailableBackend, err.Error()),
+			}, err
+		}
+	} else {
+		service.dumpAll(ctx, &cache)
+	}
 
 	return &model.DumpResponse{
 		Response:     pb.CreateResponse(pb.Response_SUCCESS, "Admin dump successfully"),
@@ -94,6 +105,41 @@ func (service *AdminService) dumpAll(ctx context.Context, cache *model.Cache) {
 		Done()
 }
 
+// dumpBackend reads the same resources as dumpAll but from the backend,
+// the difference between the two dumps is the inconsistency of the cache
+func (service *AdminService) dumpBackend(ctx context.Context, cache *model.Cache) error {
+	for _, r := range []struct {
+		Adaptor discovery.Adaptor
+		Prefix  string
+		Setter  model.Setter
+	}{
+		{backend.Store().Service(), core.GetServiceRootKey(""), &cache.Microservices},
+		{backend.Store().ServiceIndex(), core.GetServiceIndexRootKey(""), &cache.Indexes},
+		{backend.Store().ServiceAlias(), core.GetServiceAliasRootKey(""), &cache.Aliases},
+		{backend.Store().ServiceTag(), core.GetServiceTagRootKey(""), &cache.Tags},
+		{backend.Store().RuleIndex(), core.GetServiceRuleIndexRootKey(""), &cache.RuleIndexes},
+		{backend.Store().Rule(), core.GetServiceRuleRootKey(""), &cache.Rules},
+		{backend.Store().DependencyRule(), core.GetServiceDependencyRuleRootKey(""), &cache.DependencyRules},
+		{backend.Store().SchemaSummary(), core.GetServiceSchemaSummaryRootKey(""), &cache.Summaries},
+		{backend.Store().Instance(), core.GetInstanceRootKey(""), &cache.Instances},
+	} {
+		resp, err := r.Adaptor.Search(ctx,
+			registry.WithStrKey(r.Prefix), registry.WithPrefix(), registry.WithNoCache())
+		if err != nil {
+			return err
+		}
+		for _, kv := range resp.Kvs {
+			r.Setter.SetValue(&model.KV{
+				Key:         util.BytesToStringWithNoCopy(kv.Key),
+				Rev:         kv.ModRevision,
+				Value:       kv.Value,
+				ClusterName: kv.ClusterName,
+			})
+		}
+	}
+	return nil
+}
+
 func setValue(e discovery.Adaptor, setter model.Setter) {
 	e.Cache().ForEach(func(k string, kv *discovery.KeyValue) (next bool) {
 		setter.SetValue(&model.KV{
@@ -111,3 +157,86 @@ func (service *AdminService) Clusters(ctx context.Context, in *model.ClustersReq
 		Clusters: registry.Configuration().Clusters,
 	}, nil
 }
+
+func (service *AdminService) Laggards(ctx context.Context, in *model.LaggardsRequest) (*model.LaggardsResponse, error) {
+	domainProject := util.ParseDomainProject(ctx)
+	if !core.IsDefaultDomainProject(domainProject) {
+		return &model.LaggardsResponse{
+			Response: pb.CreateResponse(scerr.ErrForbidden, "Required admin permission"),
+		}, nil
+	}
+
+	return &model.LaggardsResponse{
+		Response: pb.CreateResponse(pb.Response_SUCCESS, "List laggards successfully"),
+		Laggards: nf.GetNotifyService().Laggards(),
+	}, nil
+}
+
+func (service *AdminService) Sources(ctx context.Context, in *model.SourcesRequest) (*model.SourcesResponse, error) {
+	domainProject := util.ParseDomainProject(ctx)
+	if !core.IsDefaultDomainProject(domainProject) {
+		return &model.SourcesResponse{
+			Response: pb.CreateResponse(scerr.ErrForbidden, "Required admin permission"),
+		}, nil
+	}
+
+	return &model.SourcesResponse{
+		Response: pb.CreateResponse(pb.Response_SUCCESS, "List sources successfully"),
+		Sources:  discovery.Sources(),
+	}, nil
+}
+
+func (service *AdminService) Subscriptions(ctx context.Context, in *model.SubscriptionsRequest) (*model.SubscriptionsResponse, error) {
+	domainProject := util.ParseDomainProject(ctx)
+	if !core.IsDefaultDomainProject(domainProject) {
+		return &model.SubscriptionsResponse{
+			Response: pb.CreateResponse(scerr.ErrForbidden, "Required admin permission"),
+		}, nil
+	}
+
+	return &model.SubscriptionsResponse{
+		Response:      pb.CreateResponse(pb.Response_SUCCESS, "List subscriptions successfully"),
+		Subscriptions: nf.GetNotifyService().Subscriptions(in.Type, in.Group),
+	}, nil
+}
+
+func (service *AdminService) TerminateSubscription(ctx context.Context, in *model.TerminateSubscriptionRequest) (*model.TerminateSubscriptionResponse, error) {
+	domainProject := util.ParseDomainProject(ctx)
+	if !core.IsDefaultDomainProject(domainProject) {
+		return &model.TerminateSubscriptionResponse{
+			Response: pb.CreateResponse(scerr.ErrForbidden, "Required admin permission"),
+		}, nil
+	}
+
+	if !nf.GetNotifyService().Terminate(in.Id) {
+		return &model.TerminateSubscriptionResponse{
+			Response: pb.CreateResponse(scerr.ErrInvalidParams, "Subscription does not exist"),
+		}, nil
+	}
+	log.Infof("subscription[%s] is terminated", in.Id)
+
+	return &model.TerminateSubscriptionResponse{
+		Response: pb.CreateResponse(pb.Response_SUCCESS, "Terminate subscription successfully"),
+	}, nil
+}
+
+func (service *AdminService) ReceivePeerEvents(ctx context.Context, in *pb.PeerEventsRequest) (*pb.PeerEventsResponse, error) {
+	domainProject := util.ParseDomainProject(ctx)
+	if !core.IsDefaultDomainProject(domainProject) {
+		return &pb.PeerEventsResponse{
+			Response: pb.CreateResponse(scerr.ErrForbidden, "Required admin permission"),
+		}, nil
+	}
+
+	bus := nf.GetPeerBus()
+	if !bus.Enabled {
+		return &pb.PeerEventsResponse{
+			Response: pb.CreateResponse(scerr.ErrForbidden, "Peer bus is disabled"),
+		}, nil
+	}
+	bus.Receive(in.Events)
+
+	return &pb.PeerEventsResponse{
+		Response: pb.CreateResponse(pb.Response_SUCCESS, "Receive peer events successfully"),
+	}, nil
+}
@@ -17,14 +17,23 @@
 package admin_test
 
 import (
+	"bufio"
+	"encoding/json"
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
 	"github.com/apache/servicecomb-service-center/pkg/util"
 	"github.com/apache/servicecomb-service-center/server/admin"
 	"github.com/apache/servicecomb-service-center/server/admin/model"
+	"github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/core/backend"
 	pb "github.com/apache/servicecomb-service-center/server/core/proto"
 	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	serviceUtil "github.com/apache/servicecomb-service-center/server/service/util"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"golang.org/x/net/context"
+	"strings"
+	"time"
 )
 
 var _ = Describe("'Admin' service", func() {
@@ -36,6 +45,14 @@ var _ = Describe("'Admin' service", func() {
 				Expect(resp.Response.Code).To(Equal(pb.Response_SUCCESS))
 			})
 		})
+		Context("when get all from the backend", func() {
+			It("should be passed", func() {
+				resp, err := admin.AdminServiceAPI.Dump(getContext(),
+					&model.DumpRequest{Source: model.DUMP_SOURCE_BACKEND})
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(pb.Response_SUCCESS))
+			})
+		})
 		Context("when get by domain project", func() {
 			It("should be passed", func() {
 				resp, err := admin.AdminServiceAPI.Dump(
@@ -46,4 +63,439 @@ var _ = Describe("'Admin' service", func() {
 			})
 		})
 	})
+	Describe("execute 'laggards' operation", func() {
+		Context("when get all", func() {
+			It("should be passed", func() {
+				resp, err := admin.AdminServiceAPI.Laggards(getContext(), &model.LaggardsRequest{})
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(pb.Response_SUCCESS))
+			})
+		})
+		Context("when get by domain project", func() {
+			It("should be passed", func() {
+				resp, err := admin.AdminServiceAPI.Laggards(
+					util.SetDomainProject(context.Background(), "x", "x"),
+					&model.LaggardsRequest{})
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(scerr.ErrForbidden))
+			})
+		})
+	})
+	Describe("execute 'pools' operation", func() {
+		Context("when get all", func() {
+			It("should be passed", func() {
+				resp, err := admin.AdminServiceAPI.Pools(getContext(), &model.PoolsRequest{})
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(pb.Response_SUCCESS))
+				names := make([]string, 0, len(resp.Pools))
+				for _, s := range resp.Pools {
+					names = append(names, s.Name)
+				}
+				Expect(names).To(ContainElement(gopool.DefaultPoolName))
+			})
+		})
+		Context("when get by domain project", func() {
+			It("should be passed", func() {
+				resp, err := admin.AdminServiceAPI.Pools(
+					util.SetDomainProject(context.Background(), "x", "x"),
+					&model.PoolsRequest{})
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(scerr.ErrForbidden))
+			})
+		})
+	})
+	Describe("execute 'sources' operation", func() {
+		Context("when get all", func() {
+			It("should be passed", func() {
+				resp, err := admin.AdminServiceAPI.Sources(getContext(), &model.SourcesRequest{})
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(pb.Response_SUCCESS))
+			})
+		})
+		Context("when get by domain project", func() {
+			It("should be passed", func() {
+				resp, err := admin.AdminServiceAPI.Sources(
+					util.SetDomainProject(context.Background(), "x", "x"),
+					&model.SourcesRequest{})
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(scerr.ErrForbidden))
+			})
+		})
+	})
+	Describe("execute 'subscriptions' operation", func() {
+		Context("when list all", func() {
+			It("should be passed", func() {
+				resp, err := admin.AdminServiceAPI.Subscriptions(getContext(), &model.SubscriptionsRequest{})
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(pb.Response_SUCCESS))
+			})
+		})
+		Context("when list by domain project", func() {
+			It("should be passed", func() {
+				resp, err := admin.AdminServiceAPI.Subscriptions(
+					util.SetDomainProject(context.Background(), "x", "x"),
+					&model.SubscriptionsRequest{})
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(scerr.ErrForbidden))
+			})
+		})
+		Context("when terminate a not exist subscription", func() {
+			It("should be failed", func() {
+				resp, err := admin.AdminServiceAPI.TerminateSubscription(getContext(),
+					&model.TerminateSubscriptionRequest{Id: "notexist"})
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(scerr.ErrInvalidParams))
+			})
+		})
+	})
+	Describe("execute 'debug' operation", func() {
+		Context("when disabled", func() {
+			It("should be forbidden", func() {
+				resp, err := admin.AdminServiceAPI.SetDebug(getContext(), &model.DebugRequest{Enabled: false})
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(pb.Response_SUCCESS))
+
+				rt, err := admin.AdminServiceAPI.Runtime(getContext(), &model.RuntimeRequest{})
+				Expect(err).To(BeNil())
+				Expect(rt.Response.Code).To(Equal(scerr.ErrForbidden))
+			})
+		})
+		Context("when enabled", func() {
+			It("should be passed", func() {
+				resp, err := admin.AdminServiceAPI.SetDebug(getContext(), &model.DebugRequest{Enabled: true})
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(pb.Response_SUCCESS))
+				Expect(admin.DebugEnabled()).To(BeTrue())
+
+				rt, err := admin.AdminServiceAPI.Runtime(getContext(), &model.RuntimeRequest{})
+				Expect(err).To(BeNil())
+				Expect(rt.Response.Code).To(Equal(pb.Response_SUCCESS))
+				Expect(rt.NumGoroutine > 0).To(BeTrue())
+
+				admin.AdminServiceAPI.SetDebug(getContext(), &model.DebugRequest{Enabled: false})
+			})
+		})
+		Context("when set by domain project", func() {
+			It("should be forbidden", func() {
+				resp, err := admin.AdminServiceAPI.SetDebug(
+					util.SetDomainProject(context.Background(), "x", "x"),
+					&model.DebugRequest{Enabled: true})
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(scerr.ErrForbidden))
+				Expect(admin.DebugEnabled()).To(BeFalse())
+			})
+		})
+	})
+	Describe("execute 'log level' operation", func() {
+		Context("when set by admin", func() {
+			It("should be passed", func() {
+				resp, err := admin.AdminServiceAPI.SetLogLevel(getContext(), &model.LogLevelRequest{
+					Modules: map[string]string{"notification": "DEBUG"},
+				})
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(pb.Response_SUCCESS))
+
+				get, err := admin.AdminServiceAPI.GetLogLevel(getContext())
+				Expect(err).To(BeNil())
+				Expect(get.Modules["notification"]).To(Equal("DEBUG"))
+
+				resp, err = admin.AdminServiceAPI.SetLogLevel(getContext(), &model.LogLevelRequest{
+					Modules: map[string]string{"notification": ""},
+				})
+				Expect(err).To(BeNil())
+				Expect(len(resp.Modules)).To(Equal(0))
+			})
+		})
+		Context("when set an invalid level", func() {
+			It("should be failed", func() {
+				resp, err := admin.AdminServiceAPI.SetLogLevel(getContext(), &model.LogLevelRequest{Level: "x"})
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(scerr.ErrInvalidParams))
+			})
+		})
+		Context("when set by domain project", func() {
+			It("should be forbidden", func() {
+				resp, err := admin.AdminServiceAPI.SetLogLevel(
+					util.SetDomainProject(context.Background(), "x", "x"),
+					&model.LogLevelRequest{Level: "DEBUG"})
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(scerr.ErrForbidden))
+			})
+		})
+	})
+	Describe("execute 'export' operation", func() {
+		Context("when export by domain project", func() {
+			It("should be forbidden", func() {
+				resp, err := admin.AdminServiceAPI.CreateExport(
+					util.SetDomainProject(context.Background(), "x", "x"),
+					&model.ExportRequest{})
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(scerr.ErrForbidden))
+			})
+		})
+		Context("when the request is invalid", func() {
+			It("should be failed", func() {
+				resp, err := admin.AdminServiceAPI.CreateExport(getContext(),
+					&model.ExportRequest{Format: "xml"})
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(scerr.ErrInvalidParams))
+
+				resp, err = admin.AdminServiceAPI.CreateExport(getContext(),
+					&model.ExportRequest{Domains: []string{"a/b"}})
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(scerr.ErrInvalidParams))
+
+				get, err := admin.AdminServiceAPI.GetExport(getContext(), "notexist")
+				Expect(err).To(BeNil())
+				Expect(get.Response.Code).To(Equal(scerr.ErrInvalidParams))
+			})
+		})
+		Context("when export the default domain", func() {
+			It("should be passed", func() {
+				resp, err := admin.AdminServiceAPI.CreateExport(getContext(),
+					&model.ExportRequest{Domains: []string{"default"}})
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(pb.Response_SUCCESS))
+				id := resp.Job.Id
+
+				var job *model.ExportJob
+				for i := 0; i < 50; i++ {
+					get, err := admin.AdminServiceAPI.GetExport(getContext(), id)
+					Expect(err).To(BeNil())
+					Expect(get.Response.Code).To(Equal(pb.Response_SUCCESS))
+					job = get.Job
+					if job.Status != model.EXPORT_STATUS_RUNNING {
+						break
+					}
+					time.Sleep(100 * time.Millisecond)
+				}
+				Expect(job.Status).To(Equal(model.EXPORT_STATUS_SUCCEEDED))
+
+				list, err := admin.AdminServiceAPI.ListExports(getContext())
+				Expect(err).To(BeNil())
+				Expect(len(list.Jobs) > 0).To(BeTrue())
+
+				f, job, r := admin.AdminServiceAPI.OpenExport(getContext(), id)
+				Expect(r).To(BeNil())
+				defer f.Close()
+				line, err := bufio.NewReader(f).ReadBytes('\n')
+				Expect(err).To(BeNil())
+				header := &model.ExportHeader{}
+				Expect(json.Unmarshal(line, header)).To(BeNil())
+				Expect(header.Version).To(Equal(model.EXPORT_VERSION))
+				Expect(header.Domains).To(Equal([]string{"default"}))
+			})
+		})
+	})
+	Describe("execute 'import' operation", func() {
+		dump := func(format string) string {
+			header := `{"version":"1","domains":["default"]}`
+			record := `{"type":"service","domainProject":"default/default",` +
+				`"key":"` + core.GenerateServiceKey("default/default", "import_test") + `",` +
+				`"value":{"serviceId":"import_test","appId":"import_test","serviceName":"import_test","version":"1.0.0"}}`
+			if format == model.EXPORT_FORMAT_JSON {
+				return `{"header":` + header + `,"records":[` + record + `]}`
+			}
+			return header + "\n" + record + "\n"
+		}
+		mapping := map[string]string{"default": "import_test"}
+
+		Context("when import by domain project", func() {
+			It("should be forbidden", func() {
+				resp, err := admin.AdminServiceAPI.Import(
+					util.SetDomainProject(context.Background(), "x", "x"),
+					&model.ImportRequest{}, strings.NewReader(dump("")))
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(scerr.ErrForbidden))
+			})
+		})
+		Context("when the request is invalid", func() {
+			It("should be failed", func() {
+				resp, err := admin.AdminServiceAPI.Import(getContext(),
+					&model.ImportRequest{Policy: "x"}, strings.NewReader(dump("")))
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(scerr.ErrInvalidParams))
+
+				resp, err = admin.AdminServiceAPI.Import(getContext(),
+					&model.ImportRequest{}, strings.NewReader(`{"version":"0"}`))
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(scerr.ErrInvalidParams))
+			})
+		})
+		Context("when import with domain mapping", func() {
+			It("should be passed", func() {
+				resp, err := admin.AdminServiceAPI.Import(getContext(),
+					&model.ImportRequest{DryRun: true, Format: model.EXPORT_FORMAT_JSON, Mapping: mapping},
+					strings.NewReader(dump(model.EXPORT_FORMAT_JSON)))
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(pb.Response_SUCCESS))
+				Expect(resp.Result.Counts[model.EXPORT_TYPE_SERVICE].Created).To(Equal(int64(1)))
+
+				resp, err = admin.AdminServiceAPI.Import(getContext(),
+					&model.ImportRequest{Mapping: mapping}, strings.NewReader(dump("")))
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(pb.Response_SUCCESS))
+
+				resp, err = admin.AdminServiceAPI.Import(getContext(),
+					&model.ImportRequest{Mapping: mapping}, strings.NewReader(dump("")))
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(pb.Response_SUCCESS))
+				Expect(resp.Result.Counts[model.EXPORT_TYPE_SERVICE].Skipped).To(Equal(int64(1)))
+
+				resp, err = admin.AdminServiceAPI.Import(getContext(),
+					&model.ImportRequest{Policy: model.IMPORT_POLICY_FAIL, Mapping: mapping},
+					strings.NewReader(dump("")))
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(scerr.ErrInvalidParams))
+			})
+		})
+	})
+	Describe("execute 'members' operation", func() {
+		Context("when get by domain project", func() {
+			It("should be forbidden", func() {
+				resp, err := admin.AdminServiceAPI.Members(
+					util.SetDomainProject(context.Background(), "x", "x"))
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(scerr.ErrForbidden))
+
+				self, err := admin.AdminServiceAPI.SelfStatus(
+					util.SetDomainProject(context.Background(), "x", "x"))
+				Expect(err).To(BeNil())
+				Expect(self.Response.Code).To(Equal(scerr.ErrForbidden))
+			})
+		})
+		Context("when get all", func() {
+			It("should be passed", func() {
+				resp, err := admin.AdminServiceAPI.Members(getContext())
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(pb.Response_SUCCESS))
+				Expect(resp.Summary.Total).To(Equal(len(resp.Members)))
+
+				self, err := admin.AdminServiceAPI.SelfStatus(getContext())
+				Expect(err).To(BeNil())
+				Expect(self.Response.Code).To(Equal(pb.Response_SUCCESS))
+				Expect(self.Member.Self).To(BeTrue())
+				Expect(len(self.Member.Health) > 0).To(BeTrue())
+				Expect(self.Member.Metrics).NotTo(BeNil())
+			})
+		})
+	})
+	Describe("execute 'config' operation", func() {
+		Context("when reload by domain project", func() {
+			It("should be forbidden", func() {
+				resp, err := admin.AdminServiceAPI.ReloadConfig(
+					util.SetDomainProject(context.Background(), "x", "x"))
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(scerr.ErrForbidden))
+
+				changes, err := admin.AdminServiceAPI.GetConfigChanges(
+					util.SetDomainProject(context.Background(), "x", "x"))
+				Expect(err).To(BeNil())
+				Expect(changes.Response.Code).To(Equal(scerr.ErrForbidden))
+			})
+		})
+		Context("when get the changes", func() {
+			It("should list the reloadable keys", func() {
+				resp, err := admin.AdminServiceAPI.GetConfigChanges(getContext())
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(pb.Response_SUCCESS))
+				Expect(resp.Keys).To(ContainElement("loglevel"))
+			})
+		})
+	})
+	Describe("execute 'domain' operation", func() {
+		Context("when operate by domain project", func() {
+			It("should be forbidden", func() {
+				resp, err := admin.AdminServiceAPI.ListDomains(
+					util.SetDomainProject(context.Background(), "x", "x"))
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(scerr.ErrForbidden))
+			})
+		})
+		Context("when create and delete the domain", func() {
+			It("should be guarded", func() {
+				name := "admin_domain_test"
+				resp, err := admin.AdminServiceAPI.CreateDomain(getContext(), &model.DomainRequest{
+					Name:   name,
+					Quotas: map[string]int64{model.DOMAIN_QUOTA_SERVICE: 10},
+				})
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(pb.Response_SUCCESS))
+
+				resp, err = admin.AdminServiceAPI.CreateDomain(getContext(), &model.DomainRequest{Name: name})
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(scerr.ErrInvalidParams))
+
+				resp, err = admin.AdminServiceAPI.CreateDomain(getContext(), &model.DomainRequest{
+					Name:   "admin_domain_test2",
+					Quotas: map[string]int64{"x": 1},
+				})
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(scerr.ErrInvalidParams))
+
+				resp, err = admin.AdminServiceAPI.CreateProject(getContext(), name, &model.ProjectRequest{Name: "p1"})
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(pb.Response_SUCCESS))
+
+				_, err = backend.Registry().Do(context.Background(), registry.PUT,
+					registry.WithStrKey(core.GenerateServiceKey(name+"/p1", "svc1")),
+					registry.WithStrValue(`{"serviceId":"svc1"}`))
+				Expect(err).To(BeNil())
+				_, err = backend.Registry().Do(context.Background(), registry.PUT,
+					registry.WithStrKey(core.GenerateInstanceKey(name+"/p1", "svc1", "inst1")),
+					registry.WithStrValue(`{"serviceId":"svc1","instanceId":"inst1"}`))
+				Expect(err).To(BeNil())
+				err = backend.BatchCommit(context.Background(), serviceUtil.TouchRevisionOps(name+"/p1"))
+				Expect(err).To(BeNil())
+
+				resp, err = admin.AdminServiceAPI.GetDomain(getContext(), name)
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(pb.Response_SUCCESS))
+				Expect(resp.Domain.Quotas[model.DOMAIN_QUOTA_SERVICE]).To(Equal(int64(10)))
+				Expect(len(resp.Domain.Projects)).To(Equal(2))
+				Expect(resp.Domain.Counts.Services).To(Equal(int64(1)))
+
+				del, err := admin.AdminServiceAPI.DeleteDomain(getContext(), name, &model.DeleteRequest{})
+				Expect(err).To(BeNil())
+				Expect(del.Response.Code).To(Equal(scerr.ErrInvalidParams))
+
+				del, err = admin.AdminServiceAPI.DeleteDomain(getContext(), name,
+					&model.DeleteRequest{Cascade: true, Confirm: "x"})
+				Expect(err).To(BeNil())
+				Expect(del.Response.Code).To(Equal(scerr.ErrInvalidParams))
+
+				del, err = admin.AdminServiceAPI.DeleteDomain(getContext(), name,
+					&model.DeleteRequest{Cascade: true, Confirm: name})
+				Expect(err).To(BeNil())
+				Expect(del.Response.Code).To(Equal(pb.Response_SUCCESS))
+				Expect(del.Deleted.Services).To(Equal(int64(1)))
+				Expect(del.Deleted.Instances).To(Equal(int64(1)))
+
+				// the instances deleted are not reported as expired
+				marker, err := backend.Registry().Do(context.Background(), registry.GET,
+					registry.WithStrKey(core.GenerateRevokedLeaseKey(name+"/p1", "svc1", "inst1")),
+					registry.WithCountOnly())
+				Expect(err).To(BeNil())
+				Expect(marker.Count).To(Equal(int64(1)))
+				projects, err := backend.Registry().Do(context.Background(), registry.GET,
+					registry.WithStrKey(core.GetProjectRootKey(name)+core.SPLIT),
+					registry.WithPrefix(), registry.WithCountOnly())
+				Expect(err).To(BeNil())
+				Expect(projects.Count).To(Equal(int64(0)))
+				revisions, err := backend.Registry().Do(context.Background(), registry.GET,
+					registry.WithStrKey(core.GenerateDomainRevisionKey(name)),
+					registry.WithPrefix(), registry.WithCountOnly())
+				Expect(err).To(BeNil())
+				Expect(revisions.Count).To(Equal(int64(0)))
+
+				resp, err = admin.AdminServiceAPI.GetDomain(getContext(), name)
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(scerr.ErrInvalidParams))
+
+				del, err = admin.AdminServiceAPI.DeleteDomain(getContext(), core.REGISTRY_DOMAIN, &model.DeleteRequest{})
+				Expect(err).To(BeNil())
+				Expect(del.Response.Code).To(Equal(scerr.ErrInvalidParams))
+			})
+		})
+	})
 })
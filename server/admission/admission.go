@@ -0,0 +1,275 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package admission
+
+import (
+	"golang.org/x/net/context"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Class is the priority of the requests, the lower value the higher priority
+type Class int
+
+const (
+	// ClassHigh is the heartbeats and the registrations, which keep the
+	// instances alive and are never shed without waiting
+	ClassHigh Class = iota
+	ClassNormal
+	// ClassLow is the expensive reads, they are shed first when the
+	// server is saturated
+	ClassLow
+)
+
+// ClassExempt is never limited, they are the long lived watches and the
+// admin APIs operating the overloaded server
+const ClassExempt Class = -1
+
+var classNames = []string{"high", "normal", "low"}
+
+func (c Class) String() string {
+	if c == ClassExempt {
+		return "exempt"
+	}
+	if c < ClassHigh || c > ClassLow {
+		return "unknown"
+	}
+	return classNames[c]
+}
+
+var highRoutes = map[string]struct{}{
+	// heartbeats
+	http.MethodPut + " /v4/:project/registry/microservices/:serviceId/instances/:instanceId/heartbeat":   {},
+	http.MethodPut + " /v4/:project/registry/heartbeats":                                                 {},
+	http.MethodPut + " /v4.1/:project/registry/microservices/:serviceId/instances/:instanceId/heartbeat": {},
+	http.MethodPut + " /v4.1/:project/registry/heartbeats":                                               {},
+	http.MethodPut + " /registry/v3/microservices/:serviceId/instances/:instanceId/heartbeat":            {},
+	http.MethodPut + " /registry/v3/heartbeats":                                                          {},
+	http.MethodPut + " /v4/:project/registry/agents/heartbeat":                                           {},
+	// registrations
+	http.MethodPost + " /v4/:project/registry/microservices":                                      {},
+	http.MethodPost + " /v4.1/:project/registry/microservices":                                    {},
+	http.MethodPost + " /registry/v3/microservices":                                               {},
+	http.MethodPost + " /v4/:project/registry/microservices/:serviceId/instances":                 {},
+	http.MethodPost + " /v4.1/:project/registry/microservices/:serviceId/instances":               {},
+	http.MethodPost + " /registry/v3/microservices/:serviceId/instances":                          {},
+	http.MethodDelete + " /v4/:project/registry/microservices/:serviceId/instances/:instanceId":   {},
+	http.MethodDelete + " /v4.1/:project/registry/microservices/:serviceId/instances/:instanceId": {},
+	http.MethodDelete + " /registry/v3/microservices/:serviceId/instances/:instanceId":            {},
+}
+
+var lowRoutes = map[string]struct{}{
+	// batch find
+	http.MethodPost + " /v4/:project/registry/instances":   {},
+	http.MethodPost + " /v4.1/:project/registry/instances": {},
+	// full dumps
+	http.MethodGet + " /v4/:project/admin/dump":       {},
+	http.MethodPost + " /v4/:project/admin/dump/jobs": {},
+	// the details of all the services
+	http.MethodGet + " /v4/:project/govern/microservices": {},
+	http.MethodGet + " /v4/:project/govern/relations":     {},
+	http.MethodGet + " /registry/v3/govern/services":      {},
+	http.MethodGet + " /registry/v3/govern/relation":      {},
+}
+
+// Classify returns the class of the request of the method and route pattern
+func Classify(method, pattern string) Class {
+	key := method + " " + pattern
+	if _, ok := highRoutes[key]; ok {
+		return ClassHigh
+	}
+	if _, ok := lowRoutes[key]; ok {
+		return ClassLow
+	}
+	if strings.Contains(pattern, "/watcher") || strings.Contains(pattern, "/listwatcher") ||
+		strings.HasPrefix(pattern, "/v4/:project/admin/") {
+		return ClassExempt
+	}
+	return ClassNormal
+}
+
+// limiter bounds the concurrent requests of a class, the limit 0 means
+// unlimited
+type limiter struct {
+	lock     sync.Mutex
+	limit    int
+	inflight int
+	// released is closed and renewed when a slot is released
+	released chan struct{}
+}
+
+func newLimiter(limit int) *limiter {
+	return &limiter{limit: limit, released: make(chan struct{})}
+}
+
+func (l *limiter) tryAcquire() (ok bool, released <-chan struct{}) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.limit > 0 && l.inflight >= l.limit {
+		return false, l.released
+	}
+	l.inflight++
+	return true, nil
+}
+
+// acquire waits for a free slot until the timeout, the timeout less than
+// 0 means waiting until the ctx is done
+func (l *limiter) acquire(ctx context.Context, timeout time.Duration) bool {
+	ok, released := l.tryAcquire()
+	if ok || timeout == 0 {
+		return ok
+	}
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	for {
+		select {
+		case <-released:
+			if ok, released = l.tryAcquire(); ok {
+				return true
+			}
+		case <-expired:
+			return false
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+// forceAcquire takes a slot regardless of the limit
+func (l *limiter) forceAcquire() {
+	l.lock.Lock()
+	l.inflight++
+	l.lock.Unlock()
+}
+
+func (l *limiter) release() {
+	l.lock.Lock()
+	l.inflight--
+	l.broadcast()
+	l.lock.Unlock()
+}
+
+func (l *limiter) broadcast() {
+	close(l.released)
+	l.released = make(chan struct{})
+}
+
+func (l *limiter) saturated() bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.limit > 0 && l.inflight >= l.limit
+}
+
+func (l *limiter) setLimit(limit int) {
+	l.lock.Lock()
+	l.limit = limit
+	l.broadcast()
+	l.lock.Unlock()
+}
+
+func (l *limiter) Inflight() int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.inflight
+}
+
+// Controller admits the requests by their classes, each class has its own
+// concurrency limit, and the low priority requests are shed as soon as
+// any class of higher priority is saturated
+type Controller struct {
+	// wait is how long the normal requests wait for a free slot, the high
+	// ones wait until the client gives up, the low ones never wait
+	wait int64
+
+	limiters [ClassLow + 1]*limiter
+}
+
+// Admit returns false if the request should be shed, otherwise Release
+// must be called when the request is done
+func (c *Controller) Admit(ctx context.Context, class Class) bool {
+	switch class {
+	case ClassExempt:
+		return true
+	case ClassHigh:
+		return c.limiters[class].acquire(ctx, -1)
+	case ClassLow:
+		if c.limiters[ClassHigh].saturated() || c.limiters[ClassNormal].saturated() {
+			return false
+		}
+		return c.limiters[class].acquire(ctx, 0)
+	default:
+		return c.limiters[ClassNormal].acquire(ctx, time.Duration(atomic.LoadInt64(&c.wait)))
+	}
+}
+
+func (c *Controller) Release(class Class) {
+	if class == ClassExempt {
+		return
+	}
+	c.limiterOf(class).release()
+}
+
+// Suspend releases the slot of the admitted request while it is blocked
+// for long, e.g. the long-polling finds, the returned resume takes the
+// slot back regardless of the limit, so the Release at the end is still
+// balanced
+func (c *Controller) Suspend(class Class) (resume func()) {
+	if class == ClassExempt {
+		return func() {}
+	}
+	l := c.limiterOf(class)
+	l.release()
+	return l.forceAcquire
+}
+
+func (c *Controller) limiterOf(class Class) *limiter {
+	switch class {
+	case ClassHigh, ClassLow:
+		return c.limiters[class]
+	default:
+		return c.limiters[ClassNormal]
+	}
+}
+
+// SetLimit changes the concurrency limit of the class, the waiting
+// requests are admitted at once if the limit is raised
+func (c *Controller) SetLimit(class Class, limit int) {
+	c.limiters[class].setLimit(limit)
+}
+
+func (c *Controller) SetWait(wait time.Duration) {
+	atomic.StoreInt64(&c.wait, int64(wait))
+}
+
+func (c *Controller) Inflight(class Class) int {
+	return c.limiters[class].Inflight()
+}
+
+func NewController(high, normal, low int, wait time.Duration) *Controller {
+	c := &Controller{wait: int64(wait)}
+	c.limiters[ClassHigh] = newLimiter(high)
+	c.limiters[ClassNormal] = newLimiter(normal)
+	c.limiters[ClassLow] = newLimiter(low)
+	return c
+}
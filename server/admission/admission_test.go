@@ -0,0 +1,147 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package admission
+
+import (
+	"golang.org/x/net/context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestClassify(t *testing.T) {
+	cases := []struct {
+		method  string
+		pattern string
+		class   Class
+	}{
+		{http.MethodPut, "/v4/:project/registry/heartbeats", ClassHigh},
+		{http.MethodPost, "/v4/:project/registry/microservices/:serviceId/instances", ClassHigh},
+		{http.MethodGet, "/v4/:project/registry/microservices/:serviceId/instances", ClassNormal},
+		{http.MethodPost, "/v4/:project/registry/instances", ClassLow},
+		{http.MethodGet, "/v4/:project/admin/dump", ClassLow},
+		{http.MethodGet, "/v4/:project/admin/pools", ClassExempt},
+		{http.MethodGet, "/v4/:project/registry/microservices/:serviceId/watcher", ClassExempt},
+	}
+	for _, c := range cases {
+		if class := Classify(c.method, c.pattern); class != c.class {
+			t.Fatalf("TestClassify %s %s failed, %s", c.method, c.pattern, class)
+		}
+	}
+}
+
+func TestController_Admit(t *testing.T) {
+	ctx := context.Background()
+	c := NewController(1, 1, 1, 10*time.Millisecond)
+
+	if !c.Admit(ctx, ClassExempt) {
+		t.Fatalf("TestController_Admit exempt failed")
+	}
+
+	// normal waits and gives up
+	if !c.Admit(ctx, ClassNormal) {
+		t.Fatalf("TestController_Admit normal failed")
+	}
+	start := time.Now()
+	if c.Admit(ctx, ClassNormal) {
+		t.Fatalf("TestController_Admit saturated normal failed")
+	}
+	if time.Since(start) < 10*time.Millisecond {
+		t.Fatalf("TestController_Admit normal did not wait")
+	}
+
+	// low is shed if a higher class is saturated
+	if c.Admit(ctx, ClassLow) {
+		t.Fatalf("TestController_Admit low failed")
+	}
+	c.Release(ClassNormal)
+	if !c.Admit(ctx, ClassLow) {
+		t.Fatalf("TestController_Admit low failed")
+	}
+	if c.Admit(ctx, ClassLow) {
+		t.Fatalf("TestController_Admit saturated low failed")
+	}
+	c.Release(ClassLow)
+
+	// high waits until released
+	if !c.Admit(ctx, ClassHigh) {
+		t.Fatalf("TestController_Admit high failed")
+	}
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		c.Release(ClassHigh)
+	}()
+	if !c.Admit(ctx, ClassHigh) {
+		t.Fatalf("TestController_Admit waiting high failed")
+	}
+	if c.Inflight(ClassHigh) != 1 {
+		t.Fatalf("TestController_Admit inflight failed")
+	}
+
+	// high gives up with the client
+	cctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if c.Admit(cctx, ClassHigh) {
+		t.Fatalf("TestController_Admit canceled high failed")
+	}
+
+	// raising the limit admits the waiting requests
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		c.SetLimit(ClassHigh, 2)
+	}()
+	if !c.Admit(ctx, ClassHigh) {
+		t.Fatalf("TestController_Admit raised high failed")
+	}
+
+	// unlimited
+	c.SetLimit(ClassNormal, 0)
+	for j := 0; j < 10; j++ {
+		if !c.Admit(ctx, ClassNormal) {
+			t.Fatalf("TestController_Admit unlimited failed")
+		}
+	}
+}
+
+func TestController_Suspend(t *testing.T) {
+	ctx := context.Background()
+	c := NewController(0, 1, 0, 0)
+
+	if !c.Admit(ctx, ClassNormal) {
+		t.Fatalf("TestController_Suspend failed")
+	}
+	resume := c.Suspend(ClassNormal)
+	if c.Inflight(ClassNormal) != 0 {
+		t.Fatalf("TestController_Suspend inflight failed")
+	}
+	// the suspended request does not hold the slot
+	if !c.Admit(ctx, ClassNormal) {
+		t.Fatalf("TestController_Suspend admit failed")
+	}
+	// and takes it back regardless of the limit
+	resume()
+	if c.Inflight(ClassNormal) != 2 {
+		t.Fatalf("TestController_Suspend resume failed")
+	}
+	c.Release(ClassNormal)
+	c.Release(ClassNormal)
+	if c.Inflight(ClassNormal) != 0 {
+		t.Fatalf("TestController_Suspend release failed")
+	}
+
+	c.Suspend(ClassExempt)()
+}
@@ -0,0 +1,78 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package admission
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/chain"
+	roa "github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/server/reload"
+	"github.com/astaxie/beego"
+	"time"
+)
+
+const (
+	DEFAULT_WAIT        = time.Second
+	DEFAULT_RETRY_AFTER = time.Second
+)
+
+var ctrl *Controller
+
+func init() {
+	cfg := LoadConfig()
+	ctrl = NewController(cfg.HighConcurrency, cfg.NormalConcurrency, cfg.LowConcurrency, cfg.Wait)
+	chain.RegisterHandler(roa.SERVER_CHAIN_NAME, &AdmissionHandler{})
+
+	for _, key := range []string{"admission_high_concurrency", "admission_normal_concurrency",
+		"admission_low_concurrency", "admission_wait"} {
+		validate := reload.Int(0)
+		if key == "admission_wait" {
+			validate = reload.Duration(0)
+		}
+		reload.Register(reload.Option{Key: key, Validate: validate, Apply: func(string) { apply(LoadConfig()) }})
+	}
+}
+
+// Config is the concurrency limits of the classes, the limit 0 means
+// unlimited, so the admission is disabled by default
+type Config struct {
+	HighConcurrency   int
+	NormalConcurrency int
+	LowConcurrency    int
+	// Wait is how long the normal requests wait for a free slot
+	Wait time.Duration
+}
+
+func LoadConfig() Config {
+	c := Config{
+		HighConcurrency:   beego.AppConfig.DefaultInt("admission_high_concurrency", 0),
+		NormalConcurrency: beego.AppConfig.DefaultInt("admission_normal_concurrency", 0),
+		LowConcurrency:    beego.AppConfig.DefaultInt("admission_low_concurrency", 0),
+		Wait:              DEFAULT_WAIT,
+	}
+	d, err := time.ParseDuration(beego.AppConfig.DefaultString("admission_wait", ""))
+	if err == nil && d >= 0 {
+		c.Wait = d
+	}
+	return c
+}
+
+func apply(cfg Config) {
+	ctrl.SetLimit(ClassHigh, cfg.HighConcurrency)
+	ctrl.SetLimit(ClassNormal, cfg.NormalConcurrency)
+	ctrl.SetLimit(ClassLow, cfg.LowConcurrency)
+	ctrl.SetWait(cfg.Wait)
+}
@@ -0,0 +1,110 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package admission
+
+import (
+	"fmt"
+	"github.com/apache/servicecomb-service-center/pkg/chain"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/metric"
+	"github.com/apache/servicecomb-service-center/server/rest/controller"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/net/context"
+	"net/http"
+	"strconv"
+)
+
+var (
+	shedRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metric.FamilyName,
+			Subsystem: "admission",
+			Name:      "shed_total",
+			Help:      "Counter of the requests shed by the admission control",
+		}, []string{"instance", "class"})
+
+	inflightDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metric.FamilyName, "admission", "inflight"),
+		"Gauge of the requests admitted and not done", []string{"instance", "class"}, nil)
+)
+
+func init() {
+	prometheus.MustRegister(shedRequests, inflightCollector{})
+}
+
+type inflightCollector struct {
+}
+
+func (c inflightCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- inflightDesc
+}
+
+func (c inflightCollector) Collect(ch chan<- prometheus.Metric) {
+	instance := metric.InstanceName()
+	for class := ClassHigh; class <= ClassLow; class++ {
+		ch <- prometheus.MustNewConstMetric(inflightDesc, prometheus.GaugeValue,
+			float64(ctrl.Inflight(class)), instance, class.String())
+	}
+}
+
+// CTX_ADMISSION_CLASS is the Class of the admitted request
+const CTX_ADMISSION_CLASS = "_admission_class"
+
+// Suspend releases the admission slot of the request while it is blocked
+// for long, the blocked requests do not starve the others of their class
+func Suspend(ctx context.Context) (resume func()) {
+	class, ok := ctx.Value(CTX_ADMISSION_CLASS).(Class)
+	if !ok {
+		return func() {}
+	}
+	return ctrl.Suspend(class)
+}
+
+// AdmissionHandler sheds the requests of the saturated classes with the
+// retriable error and the Retry-After header
+type AdmissionHandler struct {
+}
+
+func (h *AdmissionHandler) Handle(i *chain.Invocation) {
+	r := i.Context().Value(rest.CTX_REQUEST).(*http.Request)
+	pattern, _ := i.Context().Value(rest.CTX_MATCH_PATTERN).(string)
+	class := Classify(r.Method, pattern)
+	if class == ClassExempt {
+		i.Next()
+		return
+	}
+
+	if ctrl.Admit(r.Context(), class) {
+		util.SetRequestContext(r, CTX_ADMISSION_CLASS, class)
+		i.Next(chain.WithFunc(func(chain.Result) {
+			ctrl.Release(class)
+		}))
+		return
+	}
+
+	shedRequests.WithLabelValues(metric.InstanceName(), class.String()).Inc()
+	log.WithContext(r.Context()).Warnf("shed %s priority request %s %s", class, r.Method, r.RequestURI)
+
+	w := i.Context().Value(rest.CTX_RESPONSE).(http.ResponseWriter)
+	w.Header().Set(rest.HEADER_RETRY_AFTER, strconv.Itoa(int(DEFAULT_RETRY_AFTER.Seconds())))
+	controller.WriteError(w, scerr.ErrOverloaded, fmt.Sprintf("%s priority request is shed", class))
+
+	i.Fail(nil)
+}
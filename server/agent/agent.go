@@ -0,0 +1,242 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package agent
+
+import (
+	"crypto/subtle"
+	"errors"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	serviceUtil "github.com/apache/servicecomb-service-center/server/service/util"
+	"golang.org/x/net/context"
+	"golang.org/x/time/rate"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	HEADER_AGENT_ID = "X-Agent-Id"
+
+	RESULT_OK       = "ok"
+	RESULT_FAILED   = "failed"
+	RESULT_LIMITED  = "limited"
+	RESULT_REJECTED = "rejected"
+)
+
+var (
+	ErrUnauthorized = errors.New("platform agent unauthorized")
+	ErrRateLimited  = errors.New("platform agent heartbeat rate limited")
+	ErrNotPlatform  = errors.New("instance health check mode is not " + pb.CHECK_BY_PLATFORM)
+	ErrNotExists    = errors.New("instance does not exist")
+)
+
+// AgentStats is the heartbeat statistics of one platform agent
+type AgentStats struct {
+	AgentId       string `json:"agentId"`
+	Connections   int    `json:"connections"`
+	Heartbeats    int64  `json:"heartbeats"`
+	Failures      int64  `json:"failures"`
+	Limited       int64  `json:"limited"`
+	LastHeartbeat string `json:"lastHeartbeat,omitempty"`
+}
+
+type agentStatsSlice []AgentStats
+
+func (s agentStatsSlice) Len() int           { return len(s) }
+func (s agentStatsSlice) Less(i, j int) bool { return s[i].AgentId < s[j].AgentId }
+func (s agentStatsSlice) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+type Agent struct {
+	Id      string
+	limiter *rate.Limiter
+	mux     sync.Mutex
+	stats   AgentStats
+}
+
+func (a *Agent) Stats() AgentStats {
+	a.mux.Lock()
+	s := a.stats
+	a.mux.Unlock()
+	return s
+}
+
+func (a *Agent) record(result string) {
+	a.mux.Lock()
+	switch result {
+	case RESULT_OK:
+		a.stats.Heartbeats++
+		a.stats.LastHeartbeat = strconv.FormatInt(time.Now().Unix(), 10)
+	case RESULT_LIMITED:
+		a.stats.Limited++
+	default:
+		a.stats.Failures++
+	}
+	a.mux.Unlock()
+	ReportHeartbeat(a.Id, result)
+}
+
+// Manager authenticates the platform agents and keeps alive the
+// instances they report, each agent has its own rate limiter so that
+// one busy agent can not starve the others or the app heartbeats
+type Manager struct {
+	Cfg    Config
+	mux    sync.RWMutex
+	agents map[string]*Agent
+	// GetInstance and KeepAlive are replaceable for testing
+	GetInstance func(ctx context.Context, domainProject, serviceId, instanceId string) (*pb.MicroServiceInstance, error)
+	KeepAlive   func(ctx context.Context, domainProject, serviceId, instanceId string) error
+}
+
+// Authenticate returns the agent id of the request, the agent presents
+// the id in header and a bearer token when the tokens are configured,
+// otherwise the SPIFFE ID verified by the auth plugin is used
+func (m *Manager) Authenticate(r *http.Request) (string, error) {
+	if len(m.Cfg.Tokens) == 0 {
+		id, _ := util.FromContext(r.Context(), util.CtxSpiffeId).(string)
+		if len(id) == 0 {
+			return "", ErrUnauthorized
+		}
+		return id, nil
+	}
+	id := r.Header.Get(HEADER_AGENT_ID)
+	expect, ok := m.Cfg.Tokens[id]
+	if !ok {
+		return "", ErrUnauthorized
+	}
+	token := r.URL.Query().Get("token")
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		token = strings.TrimSpace(auth[len("Bearer "):])
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(expect)) != 1 {
+		return "", ErrUnauthorized
+	}
+	return id, nil
+}
+
+// Connect returns the agent of id and counts the stream
+func (m *Manager) Connect(id string) *Agent {
+	m.mux.Lock()
+	a, ok := m.agents[id]
+	if !ok {
+		a = &Agent{
+			Id:      id,
+			limiter: rate.NewLimiter(rate.Limit(m.Cfg.Rate), m.Cfg.Burst),
+			stats:   AgentStats{AgentId: id},
+		}
+		m.agents[id] = a
+	}
+	m.mux.Unlock()
+
+	a.mux.Lock()
+	a.stats.Connections++
+	a.mux.Unlock()
+	return a
+}
+
+// SetRate changes the rate limit of all the agents
+func (m *Manager) SetRate(r float64) {
+	m.mux.Lock()
+	m.Cfg.Rate = r
+	for _, a := range m.agents {
+		a.limiter.SetLimit(rate.Limit(r))
+	}
+	m.mux.Unlock()
+}
+
+func (m *Manager) Disconnect(a *Agent) {
+	a.mux.Lock()
+	a.stats.Connections--
+	a.mux.Unlock()
+}
+
+func (m *Manager) Agents() []AgentStats {
+	m.mux.RLock()
+	l := make([]AgentStats, 0, len(m.agents))
+	for _, a := range m.agents {
+		l = append(l, a.Stats())
+	}
+	m.mux.RUnlock()
+	sort.Sort(agentStatsSlice(l))
+	return l
+}
+
+// Heartbeat keeps alive the instances reported by the agent, only the
+// instances with CHECK_BY_PLATFORM mode are accepted, the elements
+// exceeding the agent rate are rejected with ErrRateLimited
+func (m *Manager) Heartbeat(ctx context.Context, a *Agent, domainProject string,
+	in *pb.HeartbeatSetRequest) []*pb.InstanceHbRst {
+	results := make([]*pb.InstanceHbRst, 0, len(in.Instances))
+	for _, e := range in.Instances {
+		if e == nil {
+			continue
+		}
+		rst := &pb.InstanceHbRst{ServiceId: e.ServiceId, InstanceId: e.InstanceId}
+		result := RESULT_OK
+		if err := m.heartbeat(ctx, a, domainProject, e); err != nil {
+			rst.ErrMessage = err.Error()
+			switch err {
+			case ErrRateLimited:
+				result = RESULT_LIMITED
+			case ErrNotExists, ErrNotPlatform:
+				result = RESULT_REJECTED
+			default:
+				result = RESULT_FAILED
+				log.Errorf(err, "agent[%s] heartbeat instance[%s/%s] failed",
+					a.Id, e.ServiceId, e.InstanceId)
+			}
+		}
+		a.record(result)
+		results = append(results, rst)
+	}
+	return results
+}
+
+func (m *Manager) heartbeat(ctx context.Context, a *Agent, domainProject string, e *pb.HeartbeatSetElement) error {
+	if !a.limiter.Allow() {
+		return ErrRateLimited
+	}
+	inst, err := m.GetInstance(ctx, domainProject, e.ServiceId, e.InstanceId)
+	if err != nil {
+		return err
+	}
+	if inst == nil {
+		return ErrNotExists
+	}
+	if inst.HealthCheck == nil || inst.HealthCheck.Mode != pb.CHECK_BY_PLATFORM {
+		return ErrNotPlatform
+	}
+	return m.KeepAlive(ctx, domainProject, e.ServiceId, e.InstanceId)
+}
+
+func keepAlive(ctx context.Context, domainProject, serviceId, instanceId string) error {
+	_, _, err, _ := serviceUtil.HeartbeatUtil(ctx, domainProject, serviceId, instanceId)
+	return err
+}
+
+func NewManager(cfg Config) *Manager {
+	return &Manager{
+		Cfg:         cfg,
+		agents:      make(map[string]*Agent),
+		GetInstance: serviceUtil.GetInstance,
+		KeepAlive:   keepAlive,
+	}
+}
@@ -0,0 +1,113 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package agent
+
+import (
+	"errors"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"golang.org/x/net/context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseTokens(t *testing.T) {
+	m := ParseTokens("a:1, b : 2,c,:3,d:")
+	if len(m) != 2 || m["a"] != "1" || m["b"] != "2" {
+		t.Fatalf("TestParseTokens failed, %v", m)
+	}
+}
+
+func TestManager_Authenticate(t *testing.T) {
+	m := NewManager(Config{Tokens: map[string]string{"a": "secret"}})
+	r := httptest.NewRequest(http.MethodGet, "/v4/default/registry/agents/heartbeat", nil)
+	if _, err := m.Authenticate(r); err != ErrUnauthorized {
+		t.Fatalf("TestManager_Authenticate failed, %v", err)
+	}
+	r.Header.Set(HEADER_AGENT_ID, "a")
+	r.Header.Set("Authorization", "Bearer wrong")
+	if _, err := m.Authenticate(r); err != ErrUnauthorized {
+		t.Fatalf("TestManager_Authenticate failed, %v", err)
+	}
+	r.Header.Set("Authorization", "Bearer secret")
+	if id, err := m.Authenticate(r); err != nil || id != "a" {
+		t.Fatalf("TestManager_Authenticate failed, %s %v", id, err)
+	}
+
+	m = NewManager(Config{})
+	r = httptest.NewRequest(http.MethodGet, "/v4/default/registry/agents/heartbeat", nil)
+	if _, err := m.Authenticate(r); err != ErrUnauthorized {
+		t.Fatalf("TestManager_Authenticate failed, %v", err)
+	}
+	util.SetRequestContext(r, util.CtxSpiffeId, "spiffe://td/ns/a/sa/agent")
+	if id, err := m.Authenticate(r); err != nil || id != "spiffe://td/ns/a/sa/agent" {
+		t.Fatalf("TestManager_Authenticate failed, %s %v", id, err)
+	}
+}
+
+func TestManager_Heartbeat(t *testing.T) {
+	m := NewManager(Config{Rate: 1, Burst: 3})
+	m.GetInstance = func(ctx context.Context, domainProject, serviceId, instanceId string) (*pb.MicroServiceInstance, error) {
+		switch instanceId {
+		case "platform", "broken":
+			return &pb.MicroServiceInstance{HealthCheck: &pb.HealthCheck{Mode: pb.CHECK_BY_PLATFORM}}, nil
+		case "push":
+			return &pb.MicroServiceInstance{HealthCheck: &pb.HealthCheck{Mode: pb.CHECK_BY_HEARTBEAT}}, nil
+		}
+		return nil, nil
+	}
+	m.KeepAlive = func(ctx context.Context, domainProject, serviceId, instanceId string) error {
+		if instanceId == "broken" {
+			return errors.New("lease not exist")
+		}
+		return nil
+	}
+
+	a := m.Connect("a")
+	results := m.Heartbeat(context.Background(), a, "default/default", &pb.HeartbeatSetRequest{
+		Instances: []*pb.HeartbeatSetElement{
+			{ServiceId: "s", InstanceId: "platform"},
+			{ServiceId: "s", InstanceId: "push"},
+			{ServiceId: "s", InstanceId: "broken"},
+			{ServiceId: "s", InstanceId: "platform"},
+		},
+	})
+	if len(results) != 4 || len(results[0].ErrMessage) != 0 ||
+		results[1].ErrMessage != ErrNotPlatform.Error() ||
+		results[2].ErrMessage != "lease not exist" ||
+		results[3].ErrMessage != ErrRateLimited.Error() {
+		t.Fatalf("TestManager_Heartbeat failed, %v", results)
+	}
+
+	// another agent has its own limiter
+	b := m.Connect("b")
+	results = m.Heartbeat(context.Background(), b, "default/default", &pb.HeartbeatSetRequest{
+		Instances: []*pb.HeartbeatSetElement{{ServiceId: "s", InstanceId: "none"}},
+	})
+	if len(results) != 1 || results[0].ErrMessage != ErrNotExists.Error() {
+		t.Fatalf("TestManager_Heartbeat failed, %v", results)
+	}
+	m.Disconnect(b)
+
+	stats := m.Agents()
+	if len(stats) != 2 || stats[0].AgentId != "a" || stats[0].Connections != 1 ||
+		stats[0].Heartbeats != 1 || stats[0].Failures != 2 || stats[0].Limited != 1 ||
+		stats[1].Connections != 0 || stats[1].Failures != 1 {
+		t.Fatalf("TestManager_Heartbeat failed, %v", stats)
+	}
+}
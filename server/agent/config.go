@@ -0,0 +1,104 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package agent
+
+import (
+	roa "github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/server/reload"
+	"github.com/astaxie/beego"
+	"strings"
+	"time"
+)
+
+const (
+	DEFAULT_RATE         = 100
+	DEFAULT_BURST        = 1000
+	DEFAULT_IDLE_TIMEOUT = 2 * time.Minute
+)
+
+var (
+	cfg Config
+	mgr *Manager
+)
+
+func init() {
+	cfg = LoadConfig()
+	if !cfg.Enabled {
+		return
+	}
+	mgr = NewManager(cfg)
+	roa.RegisterServant(&AgentController{})
+	reload.Register(reload.Option{
+		Key:      "platform_agent_rate",
+		Validate: reload.Float(0),
+		Apply: func(string) {
+			mgr.SetRate(LoadConfig().Rate)
+		},
+	})
+}
+
+type Config struct {
+	Enabled bool
+	// Tokens maps the agent id to the shared token the agent presents,
+	// the SPIFFE ID of the client certificate identifies the agent
+	// when no token is configured
+	Tokens map[string]string
+	// Rate is the heartbeats per second allowed for each agent
+	Rate float64
+	// Burst is the max heartbeats of one agent at a moment
+	Burst int
+	// IdleTimeout closes the stream which reports nothing in time
+	IdleTimeout time.Duration
+}
+
+func LoadConfig() Config {
+	c := Config{
+		Enabled:     beego.AppConfig.DefaultInt("platform_agent", 0) != 0,
+		Tokens:      ParseTokens(beego.AppConfig.DefaultString("platform_agent_tokens", "")),
+		Rate:        beego.AppConfig.DefaultFloat("platform_agent_rate", DEFAULT_RATE),
+		Burst:       beego.AppConfig.DefaultInt("platform_agent_burst", DEFAULT_BURST),
+		IdleTimeout: DEFAULT_IDLE_TIMEOUT,
+	}
+	if c.Rate <= 0 {
+		c.Rate = DEFAULT_RATE
+	}
+	if c.Burst <= 0 {
+		c.Burst = DEFAULT_BURST
+	}
+	d, err := time.ParseDuration(beego.AppConfig.DefaultString("platform_agent_idle_timeout", ""))
+	if err == nil && d >= time.Second {
+		c.IdleTimeout = d
+	}
+	return c
+}
+
+// ParseTokens parses the config like 'agent1:token1,agent2:token2'
+func ParseTokens(s string) map[string]string {
+	m := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		i := strings.Index(pair, ":")
+		if i <= 0 {
+			continue
+		}
+		id, token := strings.TrimSpace(pair[:i]), strings.TrimSpace(pair[i+1:])
+		if len(id) == 0 || len(token) == 0 {
+			continue
+		}
+		m[id] = token
+	}
+	return m
+}
@@ -0,0 +1,113 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package agent
+
+import (
+	"encoding/json"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/core"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/rest/controller"
+	"github.com/gorilla/websocket"
+	"net/http"
+	"time"
+)
+
+type HeartbeatResponse struct {
+	Instances []*pb.InstanceHbRst `json:"instances"`
+	Error     string              `json:"error,omitempty"`
+}
+
+type GetAgentsResponse struct {
+	Agents []AgentStats `json:"agents"`
+}
+
+// AgentController serves the platform agents which report the liveness
+// of many CHECK_BY_PLATFORM instances in one authenticated stream
+type AgentController struct {
+}
+
+func (ctrl *AgentController) URLPatterns() []rest.Route {
+	return []rest.Route{
+		{rest.HTTP_METHOD_GET, "/v4/:project/registry/agents/heartbeat", ctrl.Heartbeat},
+		{rest.HTTP_METHOD_GET, "/v4/:project/registry/agents", ctrl.GetAgents},
+	}
+}
+
+func (ctrl *AgentController) Heartbeat(w http.ResponseWriter, r *http.Request) {
+	id, err := mgr.Authenticate(r)
+	if err != nil {
+		controller.WriteError(w, scerr.ErrUnauthorized, err.Error())
+		return
+	}
+
+	upgrader := websocket.Upgrader{
+		CheckOrigin: func(r *http.Request) bool {
+			return true
+		},
+	}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Errorf(err, "agent[%s] upgrade failed", id)
+		return
+	}
+	defer conn.Close()
+
+	a := mgr.Connect(id)
+	defer mgr.Disconnect(a)
+	log.Infof("platform agent[%s] connected, remote: %s", id, util.GetIPFromContext(r.Context()))
+
+	ctx := r.Context()
+	domainProject := util.ParseDomainProject(ctx)
+	for {
+		conn.SetReadDeadline(time.Now().Add(mgr.Cfg.IdleTimeout))
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			log.Warnf("platform agent[%s] disconnected, %s", id, err.Error())
+			return
+		}
+
+		resp := &HeartbeatResponse{}
+		in := &pb.HeartbeatSetRequest{}
+		if err := json.Unmarshal(message, in); err != nil {
+			resp.Error = err.Error()
+		} else {
+			resp.Instances = mgr.Heartbeat(ctx, a, domainProject, in)
+		}
+
+		data, err := json.Marshal(resp)
+		if err != nil {
+			log.Errorf(err, "agent[%s] marshal heartbeat response failed", id)
+			return
+		}
+		if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+			log.Errorf(err, "agent[%s] write heartbeat response failed", id)
+			return
+		}
+	}
+}
+
+func (ctrl *AgentController) GetAgents(w http.ResponseWriter, r *http.Request) {
+	if !core.IsDefaultDomainProject(util.ParseDomainProject(r.Context())) {
+		controller.WriteError(w, scerr.ErrForbidden, "Required admin permission")
+		return
+	}
+	controller.WriteResponse(w, nil, &GetAgentsResponse{Agents: mgr.Agents()})
+}
@@ -27,6 +27,7 @@ import (
 	rs "github.com/apache/servicecomb-service-center/server/rest"
 	"github.com/apache/servicecomb-service-center/server/rpc"
 	"github.com/apache/servicecomb-service-center/server/service"
+	nf "github.com/apache/servicecomb-service-center/server/service/notification"
 	"golang.org/x/net/context"
 	"net"
 	"strconv"
@@ -206,9 +207,10 @@ func (s *APIServer) Stop() {
 	}
 	s.isClose = true
 
-	if !s.forked && core.ServerInfo.Config.SelfRegister {
-		backend.RegistryEngine().Stop()
-	}
+	// stop accepting the new watchers and disconnect the existing ones,
+	// then drain the in-flight requests before unregistering, so the
+	// clients never see this instance stop serving while still registered
+	nf.GetNotifyService().Drain()
 
 	if s.restSrv != nil {
 		s.restSrv.Shutdown()
@@ -218,6 +220,10 @@ func (s *APIServer) Stop() {
 		s.rpcSrv.GracefulStop()
 	}
 
+	if !s.forked && core.ServerInfo.Config.SelfRegister {
+		backend.RegistryEngine().Stop()
+	}
+
 	close(s.err)
 
 	s.goroutine.Close(true)
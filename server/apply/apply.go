@@ -0,0 +1,221 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package apply
+
+import (
+	"fmt"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/core"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	serviceUtil "github.com/apache/servicecomb-service-center/server/service/util"
+	"golang.org/x/net/context"
+	"sync"
+)
+
+// the applies are serialized, so the plan is not changed by another
+// apply before it is applied
+var lock sync.Mutex
+
+// Apply diffs the document against the current state of the domain
+// project, and converges it by the change plan. The applied changes are
+// rolled back if any change fails, except the pruned services
+func Apply(ctx context.Context, in *ApplyRequest) (*ApplyResponse, error) {
+	if err := validate(in); err != nil {
+		return &ApplyResponse{
+			Response: pb.CreateResponse(scerr.ErrInvalidParams, err.Error()),
+		}, nil
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	changes, err := plan(ctx, in)
+	if err != nil {
+		log.Errorf(err, "plan the declarative document failed")
+		return &ApplyResponse{
+			Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
+		}, err
+	}
+	for _, c := range changes {
+		if err := c.validate(); err != nil {
+			return &ApplyResponse{
+				Response: pb.CreateResponse(scerr.ErrInvalidParams,
+					fmt.Sprintf("Invalid %s %s of service %s, %s", c.Resource, c.Target, c.Service, err.Error())),
+			}, nil
+		}
+	}
+
+	resp := &ApplyResponse{Plan: changes}
+	if in.DryRun || len(changes) == 0 {
+		fillServiceIds(changes)
+		resp.Response = pb.CreateResponse(pb.Response_SUCCESS, "Plan the document successfully.")
+		return resp, nil
+	}
+
+	if failed := Execute(ctx, core.ServiceAPI, changes); failed != nil {
+		fillServiceIds(changes)
+		log.Errorf(nil, "apply the declarative document failed, %s %s %s of service %s, %s",
+			failed.Action, failed.Resource, failed.Target, failed.Service, failed.Error)
+		return &ApplyResponse{
+			Response: pb.CreateResponse(scerr.ErrInternal,
+				fmt.Sprintf("Failed to %s %s %s of service %s, the applied changes are rolled back, %s",
+					failed.Action, failed.Resource, failed.Target, failed.Service, failed.Error)),
+		}, nil
+	}
+	fillServiceIds(changes)
+	log.Infof("apply the declarative document successfully, %d changes, operator %s",
+		len(changes), util.GetIPFromContext(ctx))
+	resp.Applied = true
+	resp.Response = pb.CreateResponse(pb.Response_SUCCESS, "Apply the document successfully.")
+	return resp, nil
+}
+
+func validate(in *ApplyRequest) error {
+	keys := make(map[string]struct{}, len(in.Services))
+	for _, spec := range in.Services {
+		if spec == nil || spec.Service == nil {
+			return fmt.Errorf("the service of the spec is required")
+		}
+		key := serviceKeyOf(spec.Service)
+		if _, ok := keys[key]; ok {
+			return fmt.Errorf("duplicate service %s", key)
+		}
+		keys[key] = struct{}{}
+	}
+	return nil
+}
+
+func plan(ctx context.Context, in *ApplyRequest) ([]*Change, error) {
+	domainProject := util.ParseDomainProject(ctx)
+	var changes []*Change
+	keys := make(map[string]struct{}, len(in.Services))
+	for _, spec := range in.Services {
+		keys[serviceKeyOf(spec.Service)] = struct{}{}
+		current, err := Load(ctx, domainProject, spec)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, Plan(spec, current)...)
+	}
+
+	if in.Prune {
+		services, err := serviceUtil.GetAllServiceUtil(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range services {
+			if _, ok := keys[serviceKeyOf(s)]; ok {
+				continue
+			}
+			if core.IsShared(pb.MicroServiceToKey(domainProject, s)) {
+				continue
+			}
+			changes = append(changes, Prune(s))
+		}
+	}
+	Sort(changes)
+	return changes, nil
+}
+
+// Load returns the current state of the resources managed by the spec,
+// it returns nil if the service does not exist
+func Load(ctx context.Context, domainProject string, spec *ServiceSpec) (*State, error) {
+	serviceId, err := serviceUtil.GetServiceId(ctx, &pb.MicroServiceKey{
+		Tenant:      domainProject,
+		Environment: spec.Service.Environment,
+		AppId:       spec.Service.AppId,
+		ServiceName: spec.Service.ServiceName,
+		Version:     spec.Service.Version,
+	})
+	if err != nil || len(serviceId) == 0 {
+		return nil, err
+	}
+	service, err := serviceUtil.GetService(ctx, domainProject, serviceId)
+	if err != nil || service == nil {
+		return nil, err
+	}
+
+	state := &State{Service: service}
+	if spec.Schemas != nil {
+		resp, err := core.ServiceAPI.GetAllSchemaInfo(ctx, &pb.GetAllSchemaRequest{ServiceId: serviceId, WithSchema: true})
+		if err := check(resp.GetResponse(), err); err != nil {
+			return nil, err
+		}
+		state.Schemas = resp.Schemas
+	}
+	if spec.Tags != nil {
+		if state.Tags, err = serviceUtil.GetTagsUtils(ctx, domainProject, serviceId); err != nil {
+			return nil, err
+		}
+	}
+	if spec.Rules != nil {
+		if state.Rules, err = serviceUtil.GetRulesUtil(ctx, domainProject, serviceId); err != nil {
+			return nil, err
+		}
+	}
+	if spec.Providers != nil {
+		key := core.GenerateConsumerDependencyRuleKey(domainProject, pb.MicroServiceToKey(domainProject, service))
+		dependency, err := serviceUtil.TransferToMicroServiceDependency(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		state.Providers = dependency.Dependency
+	}
+	return state, nil
+}
+
+// Execute applies the changes in order, it returns the failed change
+// after rolling back the applied ones
+func Execute(ctx context.Context, api pb.ServiceCtrlServer, changes []*Change) *Change {
+	for i, c := range changes {
+		err := c.do(ctx, api)
+		if err == nil {
+			c.Status = STATUS_APPLIED
+			continue
+		}
+		c.Status, c.Error = STATUS_FAILED, err.Error()
+		for _, skipped := range changes[i+1:] {
+			skipped.Status = STATUS_SKIPPED
+		}
+		rollback(ctx, api, changes[:i])
+		return c
+	}
+	return nil
+}
+
+func rollback(ctx context.Context, api pb.ServiceCtrlServer, applied []*Change) {
+	for i := len(applied) - 1; i >= 0; i-- {
+		c := applied[i]
+		if c.undo == nil {
+			continue
+		}
+		if err := c.undo(ctx, api); err != nil {
+			log.Errorf(err, "roll back %s %s %s of service %s failed", c.Action, c.Resource, c.Target, c.Service)
+			c.Error = "roll back failed, " + err.Error()
+			continue
+		}
+		c.Status = STATUS_ROLLED_BACK
+	}
+}
+
+func fillServiceIds(changes []*Change) {
+	for _, c := range changes {
+		c.ServiceId = c.ref.id
+	}
+}
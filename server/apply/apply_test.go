@@ -0,0 +1,187 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package apply
+
+import (
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"golang.org/x/net/context"
+	"testing"
+)
+
+func newSpec() *ServiceSpec {
+	return &ServiceSpec{
+		Service: &pb.MicroService{AppId: "app", ServiceName: "order", Version: "1.0.0",
+			Properties: map[string]string{"a": "1"}},
+		Schemas: []*pb.Schema{{SchemaId: "s1", Schema: "s1", Summary: "v2"}, {SchemaId: "s3", Schema: "s3"}},
+		Tags:    map[string]string{"k1": "v1", "k2": "new"},
+		Rules: []*pb.AddOrUpdateServiceRule{
+			{RuleType: "WHITE", Attribute: "ServiceName", Pattern: "a.*", Description: "new"},
+			{RuleType: "WHITE", Attribute: "ServiceName", Pattern: "c.*"},
+		},
+		Providers: []*pb.MicroServiceKey{{ServiceName: "pay", Version: "1.0.0+"}},
+	}
+}
+
+func newState() *State {
+	return &State{
+		Service: &pb.MicroService{ServiceId: "id1", AppId: "app", ServiceName: "order", Version: "1.0.0",
+			Properties: map[string]string{"a": "0"}},
+		Schemas: []*pb.Schema{{SchemaId: "s1", Schema: "s1", Summary: "v1"}, {SchemaId: "s2", Schema: "s2"}},
+		Tags:    map[string]string{"k2": "old", "k3": "v3"},
+		Rules: []*pb.ServiceRule{
+			{RuleId: "r1", RuleType: "WHITE", Attribute: "ServiceName", Pattern: "a.*"},
+			{RuleId: "r2", RuleType: "WHITE", Attribute: "ServiceName", Pattern: "b.*"},
+		},
+		Providers: []*pb.MicroServiceKey{{AppId: "app", ServiceName: "pay", Version: "1.0.0+"}},
+	}
+}
+
+func summary(changes []*Change) []string {
+	var s []string
+	for _, c := range changes {
+		s = append(s, c.Action+" "+c.Resource+" "+c.Target)
+	}
+	return s
+}
+
+func TestPlan(t *testing.T) {
+	changes := Plan(newSpec(), newState())
+	Sort(changes)
+	expected := []string{
+		"update service properties",
+		"update schema s1",
+		"create schema s3",
+		"delete schema s2",
+		"create tag k1",
+		"update tag k2",
+		"delete tag k3",
+		"delete rule WHITE/ServiceName/b.*",
+		"update rule WHITE/ServiceName/a.*",
+		"create rule WHITE/ServiceName/c.*",
+	}
+	s := summary(changes)
+	if len(s) != len(expected) {
+		t.Fatalf("TestPlan failed, %v", s)
+	}
+	for i := range s {
+		if s[i] != expected[i] {
+			t.Fatalf("TestPlan failed, %v", s)
+		}
+	}
+
+	// the same state has no change, and the omitted resources are not managed
+	spec := &ServiceSpec{Service: newState().Service, Tags: newState().Tags, Providers: []*pb.MicroServiceKey{}}
+	changes = Plan(spec, &State{Service: spec.Service, Tags: spec.Tags})
+	if len(changes) != 0 {
+		t.Fatalf("TestPlan failed, %v", summary(changes))
+	}
+
+	changes = Plan(newSpec(), nil)
+	Sort(changes)
+	s = summary(changes)
+	if len(s) != 8 || s[0] != "create service " || s[7] != "update dependencies " {
+		t.Fatalf("TestPlan failed, %v", s)
+	}
+	for _, c := range changes {
+		if c.Service != "/app/order/1.0.0" || c.ref != changes[0].ref {
+			t.Fatalf("TestPlan failed, %v", c)
+		}
+	}
+}
+
+type mockAPI struct {
+	pb.ServiceCtrlServer
+	calls    []string
+	failTags bool
+}
+
+func (a *mockAPI) Create(ctx context.Context, in *pb.CreateServiceRequest) (*pb.CreateServiceResponse, error) {
+	a.calls = append(a.calls, "create "+in.Service.ServiceName)
+	return &pb.CreateServiceResponse{Response: pb.CreateResponse(pb.Response_SUCCESS, ""), ServiceId: "new"}, nil
+}
+
+func (a *mockAPI) Delete(ctx context.Context, in *pb.DeleteServiceRequest) (*pb.DeleteServiceResponse, error) {
+	a.calls = append(a.calls, "delete "+in.ServiceId)
+	return &pb.DeleteServiceResponse{Response: pb.CreateResponse(pb.Response_SUCCESS, "")}, nil
+}
+
+func (a *mockAPI) ModifySchema(ctx context.Context, in *pb.ModifySchemaRequest) (*pb.ModifySchemaResponse, error) {
+	a.calls = append(a.calls, "modify schema "+in.ServiceId+"/"+in.SchemaId)
+	return &pb.ModifySchemaResponse{Response: pb.CreateResponse(pb.Response_SUCCESS, "")}, nil
+}
+
+func (a *mockAPI) DeleteSchema(ctx context.Context, in *pb.DeleteSchemaRequest) (*pb.DeleteSchemaResponse, error) {
+	a.calls = append(a.calls, "delete schema "+in.ServiceId+"/"+in.SchemaId)
+	return &pb.DeleteSchemaResponse{Response: pb.CreateResponse(pb.Response_SUCCESS, "")}, nil
+}
+
+func (a *mockAPI) AddTags(ctx context.Context, in *pb.AddServiceTagsRequest) (*pb.AddServiceTagsResponse, error) {
+	if a.failTags {
+		return &pb.AddServiceTagsResponse{Response: pb.CreateResponse(scerr.ErrNotEnoughQuota, "quota")}, nil
+	}
+	a.calls = append(a.calls, "add tags "+in.ServiceId)
+	return &pb.AddServiceTagsResponse{Response: pb.CreateResponse(pb.Response_SUCCESS, "")}, nil
+}
+
+func TestExecute(t *testing.T) {
+	spec := &ServiceSpec{
+		Service: &pb.MicroService{AppId: "app", ServiceName: "order", Version: "1.0.0"},
+		Schemas: []*pb.Schema{{SchemaId: "s1", Schema: "s1"}},
+		Tags:    map[string]string{"k": "v"},
+	}
+	changes := Plan(spec, nil)
+	Sort(changes)
+	api := &mockAPI{}
+	if failed := Execute(context.Background(), api, changes); failed != nil {
+		t.Fatalf("TestExecute failed, %v", failed)
+	}
+	if len(api.calls) != 3 || api.calls[1] != "modify schema new/s1" || api.calls[2] != "add tags new" {
+		t.Fatalf("TestExecute failed, %v", api.calls)
+	}
+	for _, c := range changes {
+		if c.Status != STATUS_APPLIED {
+			t.Fatalf("TestExecute failed, %v", c)
+		}
+	}
+
+	changes = append(Plan(spec, nil), Prune(&pb.MicroService{ServiceId: "old", ServiceName: "old"}))
+	Sort(changes)
+	api = &mockAPI{failTags: true}
+	failed := Execute(context.Background(), api, changes)
+	if failed == nil || failed.Resource != RESOURCE_TAG || failed.Status != STATUS_FAILED {
+		t.Fatalf("TestExecute failed, %v", failed)
+	}
+	// roll back the schema and the service in reverse order
+	expected := []string{"create order", "modify schema new/s1", "delete schema new/s1", "delete new"}
+	if len(api.calls) != len(expected) {
+		t.Fatalf("TestExecute failed, %v", api.calls)
+	}
+	for i := range expected {
+		if api.calls[i] != expected[i] {
+			t.Fatalf("TestExecute failed, %v", api.calls)
+		}
+	}
+	if changes[0].Status != STATUS_ROLLED_BACK || changes[1].Status != STATUS_ROLLED_BACK ||
+		changes[3].Status != STATUS_SKIPPED {
+		t.Fatalf("TestExecute failed, %v", summary(changes))
+	}
+
+	if err := validate(&ApplyRequest{Services: []*ServiceSpec{spec, spec}}); err == nil {
+		t.Fatalf("TestExecute failed")
+	}
+}
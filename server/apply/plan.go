@@ -0,0 +1,467 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package apply
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/service"
+	"golang.org/x/net/context"
+	"reflect"
+	"sort"
+)
+
+// the changes are applied in the order of the phases, the services are
+// created before their resources and the dependencies, and the pruned
+// services are deleted at last
+const (
+	phaseCreateService = iota
+	phaseUpdateService
+	phaseSchema
+	phaseTag
+	phaseDeleteRule
+	phaseUpdateRule
+	phaseAddRule
+	phaseDependencies
+	phasePrune
+)
+
+func serviceKeyOf(s *pb.MicroService) string {
+	return util.StringJoin([]string{s.Environment, s.AppId, s.ServiceName, s.Version}, "/")
+}
+
+func ruleKeyOf(ruleType, attribute, pattern string) string {
+	return util.StringJoin([]string{ruleType, attribute, pattern}, "/")
+}
+
+// check returns the error of the api response
+func check(resp *pb.Response, err error) error {
+	if err != nil {
+		return err
+	}
+	if resp != nil && resp.Code != pb.Response_SUCCESS {
+		return scerr.NewError(resp.Code, resp.Message)
+	}
+	return nil
+}
+
+// Plan returns the changes converging the current state to the spec, the
+// current state is nil if the service does not exist
+func Plan(spec *ServiceSpec, current *State) []*Change {
+	ref := &serviceRef{}
+	key := serviceKeyOf(spec.Service)
+	var changes []*Change
+	add := func(c *Change) {
+		c.Service, c.ref, c.Status = key, ref, STATUS_PLANNED
+		changes = append(changes, c)
+	}
+
+	if current == nil {
+		add(createService(spec.Service))
+		current = &State{Tags: map[string]string{}}
+	} else {
+		ref.id = current.Service.ServiceId
+		if spec.Service.Properties != nil && !equalMap(spec.Service.Properties, current.Service.Properties) {
+			add(updateProperties(spec.Service.Properties, current.Service.Properties))
+		}
+	}
+
+	if spec.Schemas != nil {
+		for _, c := range planSchemas(spec.Schemas, current.Schemas) {
+			add(c)
+		}
+	}
+	if spec.Tags != nil {
+		for _, c := range planTags(spec.Tags, current.Tags) {
+			add(c)
+		}
+	}
+	if spec.Rules != nil {
+		for _, c := range planRules(spec.Rules, current.Rules) {
+			add(c)
+		}
+	}
+	if spec.Providers != nil && !equalProviders(spec.Service, spec.Providers, current.Providers) {
+		add(updateDependencies(spec.Service, spec.Providers, current.Providers))
+	}
+	return changes
+}
+
+// Prune returns the change deleting the service not in the document, it
+// can not be reverted
+func Prune(s *pb.MicroService) *Change {
+	ref := &serviceRef{id: s.ServiceId}
+	req := func(id string) *pb.DeleteServiceRequest { return &pb.DeleteServiceRequest{ServiceId: id} }
+	return &Change{
+		Action: ACTION_DELETE, Resource: RESOURCE_SERVICE,
+		Service: serviceKeyOf(s), Status: STATUS_PLANNED,
+		phase: phasePrune, ref: ref,
+		validate: func() error { return service.Validate(req(ref.Id())) },
+		do: func(ctx context.Context, api pb.ServiceCtrlServer) error {
+			resp, err := api.Delete(ctx, req(ref.id))
+			return check(resp.GetResponse(), err)
+		},
+	}
+}
+
+// Sort sorts the changes by the phases, and keeps the order in a phase
+func Sort(changes []*Change) {
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].phase < changes[j].phase })
+}
+
+func createService(s *pb.MicroService) *Change {
+	c := &Change{Action: ACTION_CREATE, Resource: RESOURCE_SERVICE, phase: phaseCreateService}
+	req := func() *pb.CreateServiceRequest {
+		svc := *s
+		return &pb.CreateServiceRequest{Service: &svc}
+	}
+	c.validate = func() error { return service.Validate(req()) }
+	c.do = func(ctx context.Context, api pb.ServiceCtrlServer) error {
+		resp, err := api.Create(ctx, req())
+		if err := check(resp.GetResponse(), err); err != nil {
+			return err
+		}
+		c.ref.id = resp.ServiceId
+		return nil
+	}
+	c.undo = func(ctx context.Context, api pb.ServiceCtrlServer) error {
+		resp, err := api.Delete(ctx, &pb.DeleteServiceRequest{ServiceId: c.ref.id, Force: true})
+		return check(resp.GetResponse(), err)
+	}
+	return c
+}
+
+func updateProperties(properties, old map[string]string) *Change {
+	c := &Change{Action: ACTION_UPDATE, Resource: RESOURCE_SERVICE, Target: "properties", phase: phaseUpdateService}
+	req := func(id string, p map[string]string) *pb.UpdateServicePropsRequest {
+		return &pb.UpdateServicePropsRequest{ServiceId: id, Properties: p}
+	}
+	c.validate = func() error { return service.Validate(req(c.ref.Id(), properties)) }
+	c.do = func(ctx context.Context, api pb.ServiceCtrlServer) error {
+		resp, err := api.UpdateProperties(ctx, req(c.ref.id, properties))
+		return check(resp.GetResponse(), err)
+	}
+	c.undo = func(ctx context.Context, api pb.ServiceCtrlServer) error {
+		resp, err := api.UpdateProperties(ctx, req(c.ref.id, old))
+		return check(resp.GetResponse(), err)
+	}
+	return c
+}
+
+func planSchemas(schemas, old []*pb.Schema) (changes []*Change) {
+	olds := make(map[string]*pb.Schema, len(old))
+	for _, schema := range old {
+		olds[schema.SchemaId] = schema
+	}
+	news := make(map[string]struct{}, len(schemas))
+	for _, schema := range schemas {
+		news[schema.SchemaId] = struct{}{}
+		o, ok := olds[schema.SchemaId]
+		switch {
+		case !ok:
+			changes = append(changes, putSchema(ACTION_CREATE, schema, nil))
+		case o.Schema != schema.Schema || o.Summary != schema.Summary:
+			changes = append(changes, putSchema(ACTION_UPDATE, schema, o))
+		}
+	}
+	for _, schema := range old {
+		if _, ok := news[schema.SchemaId]; !ok {
+			changes = append(changes, deleteSchema(schema))
+		}
+	}
+	return
+}
+
+func modifySchema(id string, schema *pb.Schema) *pb.ModifySchemaRequest {
+	return &pb.ModifySchemaRequest{ServiceId: id, SchemaId: schema.SchemaId, Schema: schema.Schema, Summary: schema.Summary}
+}
+
+func putSchema(action string, schema, old *pb.Schema) *Change {
+	c := &Change{Action: action, Resource: RESOURCE_SCHEMA, Target: schema.SchemaId, phase: phaseSchema}
+	c.validate = func() error { return service.Validate(modifySchema(c.ref.Id(), schema)) }
+	c.do = func(ctx context.Context, api pb.ServiceCtrlServer) error {
+		resp, err := api.ModifySchema(ctx, modifySchema(c.ref.id, schema))
+		return check(resp.GetResponse(), err)
+	}
+	c.undo = func(ctx context.Context, api pb.ServiceCtrlServer) error {
+		if old != nil {
+			resp, err := api.ModifySchema(ctx, modifySchema(c.ref.id, old))
+			return check(resp.GetResponse(), err)
+		}
+		resp, err := api.DeleteSchema(ctx, &pb.DeleteSchemaRequest{ServiceId: c.ref.id, SchemaId: schema.SchemaId})
+		return check(resp.GetResponse(), err)
+	}
+	return c
+}
+
+func deleteSchema(old *pb.Schema) *Change {
+	c := &Change{Action: ACTION_DELETE, Resource: RESOURCE_SCHEMA, Target: old.SchemaId, phase: phaseSchema}
+	req := func(id string) *pb.DeleteSchemaRequest {
+		return &pb.DeleteSchemaRequest{ServiceId: id, SchemaId: old.SchemaId}
+	}
+	c.validate = func() error { return service.Validate(req(c.ref.Id())) }
+	c.do = func(ctx context.Context, api pb.ServiceCtrlServer) error {
+		resp, err := api.DeleteSchema(ctx, req(c.ref.id))
+		return check(resp.GetResponse(), err)
+	}
+	c.undo = func(ctx context.Context, api pb.ServiceCtrlServer) error {
+		resp, err := api.ModifySchema(ctx, modifySchema(c.ref.id, old))
+		return check(resp.GetResponse(), err)
+	}
+	return c
+}
+
+func planTags(tags, old map[string]string) (changes []*Change) {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		o, ok := old[k]
+		switch {
+		case !ok:
+			changes = append(changes, addTag(k, tags[k]))
+		case o != tags[k]:
+			changes = append(changes, updateTag(k, tags[k], o))
+		}
+	}
+	keys = keys[:0]
+	for k := range old {
+		if _, ok := tags[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		changes = append(changes, deleteTag(k, old[k]))
+	}
+	return
+}
+
+func addTagRequest(id, k, v string) *pb.AddServiceTagsRequest {
+	return &pb.AddServiceTagsRequest{ServiceId: id, Tags: map[string]string{k: v}}
+}
+
+func deleteTagRequest(id, k string) *pb.DeleteServiceTagsRequest {
+	return &pb.DeleteServiceTagsRequest{ServiceId: id, Keys: []string{k}}
+}
+
+func updateTagRequest(id, k, v string) *pb.UpdateServiceTagRequest {
+	return &pb.UpdateServiceTagRequest{ServiceId: id, Key: k, Value: v}
+}
+
+func addTag(k, v string) *Change {
+	c := &Change{Action: ACTION_CREATE, Resource: RESOURCE_TAG, Target: k, phase: phaseTag}
+	c.validate = func() error { return service.Validate(addTagRequest(c.ref.Id(), k, v)) }
+	c.do = func(ctx context.Context, api pb.ServiceCtrlServer) error {
+		resp, err := api.AddTags(ctx, addTagRequest(c.ref.id, k, v))
+		return check(resp.GetResponse(), err)
+	}
+	c.undo = func(ctx context.Context, api pb.ServiceCtrlServer) error {
+		resp, err := api.DeleteTags(ctx, deleteTagRequest(c.ref.id, k))
+		return check(resp.GetResponse(), err)
+	}
+	return c
+}
+
+func updateTag(k, v, old string) *Change {
+	c := &Change{Action: ACTION_UPDATE, Resource: RESOURCE_TAG, Target: k, phase: phaseTag}
+	c.validate = func() error { return service.Validate(updateTagRequest(c.ref.Id(), k, v)) }
+	c.do = func(ctx context.Context, api pb.ServiceCtrlServer) error {
+		resp, err := api.UpdateTag(ctx, updateTagRequest(c.ref.id, k, v))
+		return check(resp.GetResponse(), err)
+	}
+	c.undo = func(ctx context.Context, api pb.ServiceCtrlServer) error {
+		resp, err := api.UpdateTag(ctx, updateTagRequest(c.ref.id, k, old))
+		return check(resp.GetResponse(), err)
+	}
+	return c
+}
+
+func deleteTag(k, old string) *Change {
+	c := &Change{Action: ACTION_DELETE, Resource: RESOURCE_TAG, Target: k, phase: phaseTag}
+	c.validate = func() error { return service.Validate(deleteTagRequest(c.ref.Id(), k)) }
+	c.do = func(ctx context.Context, api pb.ServiceCtrlServer) error {
+		resp, err := api.DeleteTags(ctx, deleteTagRequest(c.ref.id, k))
+		return check(resp.GetResponse(), err)
+	}
+	c.undo = func(ctx context.Context, api pb.ServiceCtrlServer) error {
+		resp, err := api.AddTags(ctx, addTagRequest(c.ref.id, k, old))
+		return check(resp.GetResponse(), err)
+	}
+	return c
+}
+
+// planRules identifies the rules by the type, attribute and pattern, the
+// deletions are applied before the additions, so the rule type can be
+// switched
+func planRules(rules []*pb.AddOrUpdateServiceRule, old []*pb.ServiceRule) (changes []*Change) {
+	olds := make(map[string]*pb.ServiceRule, len(old))
+	for _, rule := range old {
+		olds[ruleKeyOf(rule.RuleType, rule.Attribute, rule.Pattern)] = rule
+	}
+	news := make(map[string]struct{}, len(rules))
+	for _, rule := range rules {
+		key := ruleKeyOf(rule.RuleType, rule.Attribute, rule.Pattern)
+		news[key] = struct{}{}
+		o, ok := olds[key]
+		switch {
+		case !ok:
+			changes = append(changes, addRule(rule))
+		case o.Description != rule.Description:
+			changes = append(changes, updateRule(rule, o))
+		}
+	}
+	for _, rule := range old {
+		if _, ok := news[ruleKeyOf(rule.RuleType, rule.Attribute, rule.Pattern)]; !ok {
+			changes = append(changes, deleteRule(rule))
+		}
+	}
+	return
+}
+
+func toRule(rule *pb.ServiceRule) *pb.AddOrUpdateServiceRule {
+	return &pb.AddOrUpdateServiceRule{
+		RuleType:    rule.RuleType,
+		Attribute:   rule.Attribute,
+		Pattern:     rule.Pattern,
+		Description: rule.Description,
+	}
+}
+
+func addRuleRequest(id string, rule *pb.AddOrUpdateServiceRule) *pb.AddServiceRulesRequest {
+	return &pb.AddServiceRulesRequest{ServiceId: id, Rules: []*pb.AddOrUpdateServiceRule{rule}}
+}
+
+func addRule(rule *pb.AddOrUpdateServiceRule) *Change {
+	c := &Change{Action: ACTION_CREATE, Resource: RESOURCE_RULE, phase: phaseAddRule,
+		Target: ruleKeyOf(rule.RuleType, rule.Attribute, rule.Pattern)}
+	var ruleIds []string
+	c.validate = func() error { return service.Validate(addRuleRequest(c.ref.Id(), rule)) }
+	c.do = func(ctx context.Context, api pb.ServiceCtrlServer) error {
+		resp, err := api.AddRule(ctx, addRuleRequest(c.ref.id, rule))
+		if err := check(resp.GetResponse(), err); err != nil {
+			return err
+		}
+		ruleIds = resp.RuleIds
+		return nil
+	}
+	c.undo = func(ctx context.Context, api pb.ServiceCtrlServer) error {
+		resp, err := api.DeleteRule(ctx, &pb.DeleteServiceRulesRequest{ServiceId: c.ref.id, RuleIds: ruleIds})
+		return check(resp.GetResponse(), err)
+	}
+	return c
+}
+
+func updateRule(rule *pb.AddOrUpdateServiceRule, old *pb.ServiceRule) *Change {
+	c := &Change{Action: ACTION_UPDATE, Resource: RESOURCE_RULE, phase: phaseUpdateRule,
+		Target: ruleKeyOf(rule.RuleType, rule.Attribute, rule.Pattern)}
+	req := func(id string, r *pb.AddOrUpdateServiceRule) *pb.UpdateServiceRuleRequest {
+		return &pb.UpdateServiceRuleRequest{ServiceId: id, RuleId: old.RuleId, Rule: r}
+	}
+	c.validate = func() error { return service.Validate(req(c.ref.Id(), rule)) }
+	c.do = func(ctx context.Context, api pb.ServiceCtrlServer) error {
+		resp, err := api.UpdateRule(ctx, req(c.ref.id, rule))
+		return check(resp.GetResponse(), err)
+	}
+	c.undo = func(ctx context.Context, api pb.ServiceCtrlServer) error {
+		resp, err := api.UpdateRule(ctx, req(c.ref.id, toRule(old)))
+		return check(resp.GetResponse(), err)
+	}
+	return c
+}
+
+func deleteRule(old *pb.ServiceRule) *Change {
+	c := &Change{Action: ACTION_DELETE, Resource: RESOURCE_RULE, phase: phaseDeleteRule,
+		Target: ruleKeyOf(old.RuleType, old.Attribute, old.Pattern)}
+	req := func(id string) *pb.DeleteServiceRulesRequest {
+		return &pb.DeleteServiceRulesRequest{ServiceId: id, RuleIds: []string{old.RuleId}}
+	}
+	c.validate = func() error { return service.Validate(req(c.ref.Id())) }
+	c.do = func(ctx context.Context, api pb.ServiceCtrlServer) error {
+		resp, err := api.DeleteRule(ctx, req(c.ref.id))
+		return check(resp.GetResponse(), err)
+	}
+	c.undo = func(ctx context.Context, api pb.ServiceCtrlServer) error {
+		resp, err := api.AddRule(ctx, addRuleRequest(c.ref.id, toRule(old)))
+		return check(resp.GetResponse(), err)
+	}
+	return c
+}
+
+func dependenciesRequest(consumer *pb.MicroService, providers []*pb.MicroServiceKey) *pb.CreateDependenciesRequest {
+	return &pb.CreateDependenciesRequest{
+		Dependencies: []*pb.ConsumerDependency{{
+			Consumer: &pb.MicroServiceKey{
+				Environment: consumer.Environment,
+				AppId:       consumer.AppId,
+				ServiceName: consumer.ServiceName,
+				Version:     consumer.Version,
+			},
+			Providers: providers,
+		}},
+	}
+}
+
+// updateDependencies overrides the provider rules of the consumer, the
+// dependencies are handled asynchronously by the dependency queue
+func updateDependencies(consumer *pb.MicroService, providers, old []*pb.MicroServiceKey) *Change {
+	c := &Change{Action: ACTION_UPDATE, Resource: RESOURCE_DEPENDENCIES, phase: phaseDependencies}
+	c.validate = func() error { return service.Validate(dependenciesRequest(consumer, providers)) }
+	c.do = func(ctx context.Context, api pb.ServiceCtrlServer) error {
+		resp, err := api.CreateDependenciesForMicroServices(ctx, dependenciesRequest(consumer, providers))
+		return check(resp.GetResponse(), err)
+	}
+	c.undo = func(ctx context.Context, api pb.ServiceCtrlServer) error {
+		resp, err := api.CreateDependenciesForMicroServices(ctx, dependenciesRequest(consumer, old))
+		return check(resp.GetResponse(), err)
+	}
+	return c
+}
+
+func equalMap(a, b map[string]string) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func providerKeyOf(consumer *pb.MicroService, p *pb.MicroServiceKey) string {
+	appId := p.AppId
+	if len(appId) == 0 {
+		appId = consumer.AppId
+	}
+	return util.StringJoin([]string{p.Environment, appId, p.ServiceName, p.Version}, "/")
+}
+
+func equalProviders(consumer *pb.MicroService, a, b []*pb.MicroServiceKey) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	set := make(map[string]struct{}, len(a))
+	for _, p := range a {
+		set[providerKeyOf(consumer, p)] = struct{}{}
+	}
+	for _, p := range b {
+		if _, ok := set[providerKeyOf(consumer, p)]; !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,112 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package apply
+
+import (
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"golang.org/x/net/context"
+)
+
+const (
+	ACTION_CREATE = "create"
+	ACTION_UPDATE = "update"
+	ACTION_DELETE = "delete"
+
+	RESOURCE_SERVICE      = "service"
+	RESOURCE_SCHEMA       = "schema"
+	RESOURCE_TAG          = "tag"
+	RESOURCE_RULE         = "rule"
+	RESOURCE_DEPENDENCIES = "dependencies"
+
+	STATUS_PLANNED     = "planned"
+	STATUS_APPLIED     = "applied"
+	STATUS_FAILED      = "failed"
+	STATUS_ROLLED_BACK = "rolledBack"
+	STATUS_SKIPPED     = "skipped"
+)
+
+// ApplyRequest is the declarative document of the domain project
+type ApplyRequest struct {
+	Services []*ServiceSpec `json:"services"`
+	// Prune deletes the services of the domain project not in the document
+	Prune bool `json:"prune,omitempty"`
+	// DryRun returns the change plan without applying it
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// ServiceSpec is the desired state of a service, the omitted resources
+// are not managed, and the empty ones delete all the existing
+type ServiceSpec struct {
+	Service   *pb.MicroService             `json:"service"`
+	Schemas   []*pb.Schema                 `json:"schemas,omitempty"`
+	Tags      map[string]string            `json:"tags,omitempty"`
+	Rules     []*pb.AddOrUpdateServiceRule `json:"rules,omitempty"`
+	Providers []*pb.MicroServiceKey        `json:"providers,omitempty"`
+}
+
+type ApplyResponse struct {
+	Response *pb.Response `json:"response,omitempty"`
+	Plan     []*Change    `json:"plan"`
+	Applied  bool         `json:"applied"`
+}
+
+// State is the current state of a service
+type State struct {
+	Service   *pb.MicroService
+	Schemas   []*pb.Schema
+	Tags      map[string]string
+	Rules     []*pb.ServiceRule
+	Providers []*pb.MicroServiceKey
+}
+
+// Change is a step of the change plan
+type Change struct {
+	Action    string `json:"action"`
+	Resource  string `json:"resource"`
+	Service   string `json:"service"`
+	ServiceId string `json:"serviceId,omitempty"`
+	// Target is the schema id, tag key or rule of the change
+	Target string `json:"target,omitempty"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+
+	phase int
+	ref   *serviceRef
+	// validate checks the request before any change is applied
+	validate func() error
+	do       func(ctx context.Context, api pb.ServiceCtrlServer) error
+	// undo reverts the applied change when the later one fails, it is
+	// nil if the change can not be reverted
+	undo func(ctx context.Context, api pb.ServiceCtrlServer) error
+}
+
+// serviceRef is the service id shared by the changes of a service, it is
+// set after the service is created
+type serviceRef struct {
+	id string
+}
+
+// placeholder is the service id to validate the requests of the services
+// not created yet
+const placeholder = "placeholder"
+
+func (r *serviceRef) Id() string {
+	if len(r.id) == 0 {
+		return placeholder
+	}
+	return r.id
+}
@@ -19,6 +19,7 @@ package bootstrap
 // rest
 import _ "github.com/apache/servicecomb-service-center/server/rest/controller/v3"
 import _ "github.com/apache/servicecomb-service-center/server/rest/controller/v4"
+import _ "github.com/apache/servicecomb-service-center/server/rest/controller/v41"
 
 // registry
 import _ "github.com/apache/servicecomb-service-center/server/plugin/pkg/registry/buildin"
@@ -40,6 +41,7 @@ import _ "github.com/apache/servicecomb-service-center/server/plugin/pkg/quota/u
 
 // auth
 import _ "github.com/apache/servicecomb-service-center/server/plugin/pkg/auth/buildin"
+import _ "github.com/apache/servicecomb-service-center/server/plugin/pkg/auth/spiffe"
 
 // uuid
 import _ "github.com/apache/servicecomb-service-center/server/plugin/pkg/uuid/buildin"
@@ -51,6 +53,10 @@ import _ "github.com/apache/servicecomb-service-center/server/plugin/pkg/tracing
 // tls
 import _ "github.com/apache/servicecomb-service-center/server/plugin/pkg/tls/buildin"
 
+// validator
+import _ "github.com/apache/servicecomb-service-center/server/plugin/pkg/validator/buildin"
+import _ "github.com/apache/servicecomb-service-center/server/plugin/pkg/validator/rules"
+
 // module 'govern'
 import _ "github.com/apache/servicecomb-service-center/server/govern"
 
@@ -60,16 +66,103 @@ import _ "github.com/apache/servicecomb-service-center/server/broker"
 // module 'admin'
 import _ "github.com/apache/servicecomb-service-center/server/admin"
 
+// module 'consul'
+import _ "github.com/apache/servicecomb-service-center/server/consul"
+
+// module 'istio'
+import _ "github.com/apache/servicecomb-service-center/server/istio"
+
+// module 'simple'
+import _ "github.com/apache/servicecomb-service-center/server/simple"
+
+// module 'upstream'
+import _ "github.com/apache/servicecomb-service-center/server/upstream"
+
 // metrics
 import _ "github.com/apache/servicecomb-service-center/server/metric"
 
+// event sink
+import _ "github.com/apache/servicecomb-service-center/server/sink/nats"
+import _ "github.com/apache/servicecomb-service-center/server/sink/mqtt"
+
+// cross-replica event forwarding
+import _ "github.com/apache/servicecomb-service-center/server/peer"
+
+// multi-datacenter sync
+import _ "github.com/apache/servicecomb-service-center/server/syncer"
+
+// registry sync
+import _ "github.com/apache/servicecomb-service-center/server/nacos"
+import _ "github.com/apache/servicecomb-service-center/server/zookeeper"
+
+// dubbo registry
+import _ "github.com/apache/servicecomb-service-center/server/dubbo"
+
+// eureka and consul importer
+import _ "github.com/apache/servicecomb-service-center/server/importer"
+
+// api gateway schema push
+import _ "github.com/apache/servicecomb-service-center/server/gateway"
+
+// declarative apply
+import _ "github.com/apache/servicecomb-service-center/server/apply"
+
+// platform agent heartbeat
+import _ "github.com/apache/servicecomb-service-center/server/agent"
+
+// per-tenant api usage accounting
+import _ "github.com/apache/servicecomb-service-center/server/usage"
+
+// tenant capacity reports
+import _ "github.com/apache/servicecomb-service-center/server/capacity"
+
+// orphaned resources gc
+import _ "github.com/apache/servicecomb-service-center/server/gc"
+
+// quota usage reconciliation
+import _ "github.com/apache/servicecomb-service-center/server/reconcile"
+
+// referential integrity checks
+import _ "github.com/apache/servicecomb-service-center/server/integrity"
+
+// named locks for the clients
+import _ "github.com/apache/servicecomb-service-center/server/lock"
+
+// feature flags
+import _ "github.com/apache/servicecomb-service-center/server/feature"
+
+// maintenance mode
+import _ "github.com/apache/servicecomb-service-center/server/maintenance"
+
+// priority admission under overload
+import _ "github.com/apache/servicecomb-service-center/server/admission"
+
+// dry run of the destructive APIs
+import _ "github.com/apache/servicecomb-service-center/server/dryrun"
+
+// grpc health checking
+import _ "github.com/apache/servicecomb-service-center/server/health"
+
+// dns server
+import _ "github.com/apache/servicecomb-service-center/server/dns"
+
+// delta cache synchronization between replicas
+import _ "github.com/apache/servicecomb-service-center/server/feed"
+
+// the openapi document of all the routes
+import _ "github.com/apache/servicecomb-service-center/server/openapi"
+
 import (
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/server/handler/auth"
 	"github.com/apache/servicecomb-service-center/server/handler/cache"
 	"github.com/apache/servicecomb-service-center/server/handler/context"
+	"github.com/apache/servicecomb-service-center/server/handler/deprecation"
+	"github.com/apache/servicecomb-service-center/server/handler/fencing"
 	"github.com/apache/servicecomb-service-center/server/handler/maxbody"
 	"github.com/apache/servicecomb-service-center/server/handler/metric"
+	"github.com/apache/servicecomb-service-center/server/handler/negotiation"
+	"github.com/apache/servicecomb-service-center/server/handler/problem"
 	"github.com/apache/servicecomb-service-center/server/handler/tracing"
 	"github.com/apache/servicecomb-service-center/server/interceptor"
 	"github.com/apache/servicecomb-service-center/server/interceptor/access"
@@ -84,10 +177,14 @@ func init() {
 	interceptor.RegisterInterceptFunc(cors.Intercept)
 
 	// handle requests after routing.
+	problem.RegisterHandlers()
+	negotiation.RegisterHandlers()
+	deprecation.RegisterHandlers()
 	maxbody.RegisterHandlers()
 	metric.RegisterHandlers()
 	tracing.RegisterHandlers()
 	auth.RegisterHandlers()
 	context.RegisterHandlers()
+	fencing.RegisterHandlers()
 	cache.RegisterHandlers()
 }
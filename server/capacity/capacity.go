@@ -0,0 +1,296 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package capacity
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/core/backend"
+	"github.com/apache/servicecomb-service-center/server/metric"
+	"github.com/apache/servicecomb-service-center/server/mux"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	nf "github.com/apache/servicecomb-service-center/server/service/notification"
+	"golang.org/x/net/context"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	STAMP_FORMAT = "20060102150405"
+
+	capacityKey  = "capacity"
+	resourcesKey = "resources"
+	watchersKey  = "watchers"
+)
+
+var ErrNotLeader = errors.New("the capacity snapshot is taking by another service center")
+
+// resourceRoots are the root keys of all the resources stored per domain
+// project, the size of them is the storage of the domain project
+var resourceRoots = []func(string) string{
+	core.GetServiceRootKey,
+	core.GetServiceIndexRootKey,
+	core.GetServiceAliasRootKey,
+	core.GetServiceRuleRootKey,
+	core.GetServiceRuleIndexRootKey,
+	core.GetServiceTagRootKey,
+	core.GetServiceSchemaRootKey,
+	core.GetServiceSchemaSummaryRootKey,
+	core.GetInstanceRootKey,
+	core.GetInstanceLeaseRootKey,
+	core.GetServiceDependencyRuleRootKey,
+	core.GetServiceDependencyQueueRootKey,
+	core.GetServiceDependencyRootKey,
+}
+
+// Resources is the resource counts of a domain project at a moment
+type Resources struct {
+	Services  int64 `json:"services"`
+	Instances int64 `json:"instances"`
+	Schemas   int64 `json:"schemas"`
+	// StorageBytes is the size of the keys and values in the registry
+	StorageBytes int64 `json:"storageBytes"`
+}
+
+// Snapshot is the capacity of a domain project at a moment, the watch
+// connections are summed from all the service centers
+type Snapshot struct {
+	Timestamp     string `json:"timestamp"`
+	DomainProject string `json:"domainProject"`
+	Resources
+	WatchConnections int64 `json:"watchConnections"`
+}
+
+// '/cse-sr/capacity/resources/{stamp}/{domain}/{project}'
+// '/cse-sr/capacity/watchers/{stamp}/{node}'
+func getRootKey(kind string) string {
+	return util.StringJoin([]string{core.GetRootKey(), capacityKey, kind}, core.SPLIT)
+}
+
+func getResourcesKey(stamp, domainProject string) string {
+	return util.StringJoin([]string{getRootKey(resourcesKey), stamp, domainProject}, core.SPLIT)
+}
+
+func getWatchersKey(stamp, node string) string {
+	return util.StringJoin([]string{getRootKey(watchersKey), stamp, node}, core.SPLIT)
+}
+
+// Reporter takes the capacity snapshots periodically, the resources are
+// counted by the capacity job on the leader, and every service center
+// reports the watch connections of its own
+type Reporter struct {
+	Cfg Config
+	// Node identifies the service center in the cluster
+	Node string
+}
+
+func (r *Reporter) Run(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+		return
+	case <-backend.Registry().Ready():
+	}
+	log.Infof("capacity report is enabled, snapshot once every %s", r.Cfg.Interval)
+	for {
+		next := time.Now().Truncate(r.Cfg.Interval).Add(r.Cfg.Interval)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Until(next)):
+			stamp := next.UTC().Format(STAMP_FORMAT)
+			if err := r.ReportWatchers(ctx, stamp); err != nil {
+				log.Errorf(err, "report the watch connections at %s failed", stamp)
+			}
+		}
+	}
+}
+
+func (r *Reporter) ReportWatchers(ctx context.Context, stamp string) error {
+	data, err := json.Marshal(nf.GetNotifyService().WatchConnections())
+	if err != nil {
+		return err
+	}
+	_, err = backend.Registry().Do(ctx, registry.PUT,
+		registry.WithStrKey(getWatchersKey(stamp, r.Node)), registry.WithValue(data))
+	return err
+}
+
+// TrySnapshot counts the resources if no other service center is doing it
+func (r *Reporter) TrySnapshot(ctx context.Context, stamp string) error {
+	lock, err := mux.Try(mux.CAPACITY_LOCK)
+	if lock == nil {
+		log.Debugf("can not take the capacity snapshot by this service center instance now, %v", err)
+		return ErrNotLeader
+	}
+	defer lock.Unlock()
+
+	counts, err := Count(ctx)
+	if err != nil {
+		return err
+	}
+	var ops []registry.PluginOp
+	for domainProject, res := range counts {
+		data, err := json.Marshal(res)
+		if err != nil {
+			return err
+		}
+		ops = append(ops, registry.OpPut(
+			registry.WithStrKey(getResourcesKey(stamp, domainProject)), registry.WithValue(data)))
+	}
+	if err := backend.BatchCommit(ctx, ops); err != nil {
+		return err
+	}
+	log.Infof("capacity snapshot at %s is taken, %d domain projects", stamp, len(counts))
+	return nil
+}
+
+// Count scans the registry and returns the resources of each domain project
+func Count(ctx context.Context) (map[string]*Resources, error) {
+	counts := make(map[string]*Resources)
+	for _, rootKey := range resourceRoots {
+		root := rootKey("")
+		resp, err := backend.Registry().Do(ctx, registry.GET,
+			registry.WithStrKey(root), registry.WithPrefix())
+		if err != nil {
+			return nil, err
+		}
+		for _, kv := range resp.Kvs {
+			arr := strings.SplitN(strings.TrimPrefix(util.BytesToStringWithNoCopy(kv.Key), root), core.SPLIT, 3)
+			if len(arr) < 3 {
+				continue
+			}
+			domainProject := arr[0] + core.SPLIT + arr[1]
+			res, ok := counts[domainProject]
+			if !ok {
+				res = &Resources{}
+				counts[domainProject] = res
+			}
+			res.StorageBytes += int64(len(kv.Key) + len(kv.Value))
+			switch root {
+			case core.GetServiceRootKey(""):
+				res.Services++
+			case core.GetInstanceRootKey(""):
+				res.Instances++
+			case core.GetServiceSchemaRootKey(""):
+				res.Schemas++
+			}
+		}
+	}
+	return counts, nil
+}
+
+// Query returns the snapshots between from and to, filtered by the
+// domain project prefix like 'domain/' or 'domain/project' if not empty
+func Query(ctx context.Context, prefix string, from, to time.Time) ([]*Snapshot, error) {
+	start, end := from.UTC().Format(STAMP_FORMAT), to.UTC().Add(time.Second).Format(STAMP_FORMAT)
+	snapshots := make(map[string]*Snapshot)
+	get := func(stamp, domainProject string) *Snapshot {
+		key := stamp + core.SPLIT + domainProject
+		s, ok := snapshots[key]
+		if !ok {
+			t, _ := time.Parse(STAMP_FORMAT, stamp)
+			s = &Snapshot{Timestamp: t.Format(time.RFC3339), DomainProject: domainProject}
+			snapshots[key] = s
+		}
+		return s
+	}
+	match := func(domainProject string) bool {
+		return len(prefix) == 0 || domainProject == prefix ||
+			(strings.HasSuffix(prefix, core.SPLIT) && strings.HasPrefix(domainProject, prefix))
+	}
+
+	kvs, err := rangeOf(ctx, resourcesKey, start, end)
+	if err != nil {
+		return nil, err
+	}
+	for _, kv := range kvs {
+		arr := strings.SplitN(kv.key, core.SPLIT, 2)
+		if len(arr) != 2 || !match(arr[1]) {
+			continue
+		}
+		if err := json.Unmarshal(kv.value, &get(arr[0], arr[1]).Resources); err != nil {
+			log.Errorf(err, "unmarshal capacity snapshot '%s' failed", kv.key)
+		}
+	}
+
+	kvs, err = rangeOf(ctx, watchersKey, start, end)
+	if err != nil {
+		return nil, err
+	}
+	for _, kv := range kvs {
+		arr := strings.SplitN(kv.key, core.SPLIT, 2)
+		if len(arr) != 2 {
+			continue
+		}
+		watchers := make(map[string]int64)
+		if err := json.Unmarshal(kv.value, &watchers); err != nil {
+			log.Errorf(err, "unmarshal capacity watchers '%s' failed", kv.key)
+			continue
+		}
+		for domainProject, n := range watchers {
+			if match(domainProject) {
+				get(arr[0], domainProject).WatchConnections += n
+			}
+		}
+	}
+
+	list := make([]*Snapshot, 0, len(snapshots))
+	for _, s := range snapshots {
+		list = append(list, s)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Timestamp != list[j].Timestamp {
+			return list[i].Timestamp < list[j].Timestamp
+		}
+		return list[i].DomainProject < list[j].DomainProject
+	})
+	return list, nil
+}
+
+type rawKV struct {
+	// key is the part after the kind root key
+	key   string
+	value []byte
+}
+
+func rangeOf(ctx context.Context, kind, start, end string) ([]rawKV, error) {
+	root := getRootKey(kind) + core.SPLIT
+	resp, err := backend.Registry().Do(ctx, registry.GET,
+		registry.WithStrKey(root+start), registry.WithStrEndKey(root+end))
+	if err != nil {
+		return nil, err
+	}
+	kvs := make([]rawKV, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		kvs = append(kvs, rawKV{
+			key:   strings.TrimPrefix(util.BytesToStringWithNoCopy(kv.Key), root),
+			value: kv.Value,
+		})
+	}
+	return kvs, nil
+}
+
+func NewReporter(cfg Config) *Reporter {
+	return &Reporter{
+		Cfg:  cfg,
+		Node: metric.InstanceName(),
+	}
+}
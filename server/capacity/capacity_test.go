@@ -0,0 +1,66 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package capacity
+
+import (
+	"github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/core/backend"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	"golang.org/x/net/context"
+	"testing"
+	"time"
+)
+
+func TestReporter_TrySnapshot(t *testing.T) {
+	ctx := context.Background()
+	domainProject := "capacity_test/capacity_test"
+	_, err := backend.Registry().Do(ctx, registry.PUT,
+		registry.WithStrKey(core.GenerateServiceKey(domainProject, "svc1")),
+		registry.WithStrValue(`{"serviceId":"svc1"}`))
+	if err != nil {
+		t.Fatalf("TestReporter_TrySnapshot failed, %v", err)
+	}
+
+	counts, err := Count(ctx)
+	if err != nil {
+		t.Fatalf("TestReporter_TrySnapshot failed, %v", err)
+	}
+	res, ok := counts[domainProject]
+	if !ok || res.Services != 1 || res.StorageBytes == 0 {
+		t.Fatalf("TestReporter_TrySnapshot failed, %v", res)
+	}
+
+	r := NewReporter(Config{Interval: time.Hour, RetentionDays: 1})
+	now := time.Now().Truncate(time.Second)
+	stamp := now.UTC().Format(STAMP_FORMAT)
+	if err := r.TrySnapshot(ctx, stamp); err != nil {
+		t.Fatalf("TestReporter_TrySnapshot failed, %v", err)
+	}
+
+	snapshots, err := Query(ctx, "capacity_test/", now.Add(-time.Minute), now)
+	if err != nil || len(snapshots) != 1 {
+		t.Fatalf("TestReporter_TrySnapshot failed, %v, %v", snapshots, err)
+	}
+	if s := snapshots[0]; s.DomainProject != domainProject || s.Services != 1 {
+		t.Fatalf("TestReporter_TrySnapshot failed, %v", s)
+	}
+
+	snapshots, err = Query(ctx, "other/", now.Add(-time.Minute), now)
+	if err != nil || len(snapshots) != 0 {
+		t.Fatalf("TestReporter_TrySnapshot failed, %v, %v", snapshots, err)
+	}
+}
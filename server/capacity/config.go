@@ -0,0 +1,88 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package capacity
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	roa "github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/server/job"
+	"github.com/apache/servicecomb-service-center/server/retention"
+	"github.com/astaxie/beego"
+	"golang.org/x/net/context"
+	"time"
+)
+
+const (
+	DEFAULT_INTERVAL       = time.Hour
+	DEFAULT_RETENTION_DAYS = 90
+	// MAX_QUERY_RANGE is the max time range of one report query
+	MAX_QUERY_RANGE = 31 * 24 * time.Hour
+)
+
+var (
+	cfg      Config
+	reporter *Reporter
+)
+
+func init() {
+	cfg = LoadConfig()
+	if !cfg.Enabled {
+		return
+	}
+	reporter = NewReporter(cfg)
+	roa.RegisterServant(&CapacityController{})
+	gopool.Go(reporter.Run)
+	job.Register(job.Job{
+		Name:     "capacity",
+		Interval: cfg.Interval,
+		Align:    true,
+		Run: func(ctx context.Context) error {
+			return reporter.TrySnapshot(ctx, time.Now().Truncate(cfg.Interval).UTC().Format(STAMP_FORMAT))
+		},
+	})
+	retention.Register(retention.Policy{
+		Name:      "capacity",
+		Roots:     []string{getRootKey(resourcesKey), getRootKey(watchersKey)},
+		Format:    STAMP_FORMAT,
+		Retention: time.Duration(cfg.RetentionDays) * 24 * time.Hour,
+	})
+}
+
+type Config struct {
+	Enabled bool
+	// Interval is how often the snapshots are taken, the snapshots of
+	// all the service centers are aligned to it
+	Interval time.Duration
+	// RetentionDays is how long the snapshots are kept
+	RetentionDays int
+}
+
+func LoadConfig() Config {
+	c := Config{
+		Enabled:       beego.AppConfig.DefaultInt("capacity_report", 0) != 0,
+		Interval:      DEFAULT_INTERVAL,
+		RetentionDays: beego.AppConfig.DefaultInt("capacity_retention_days", DEFAULT_RETENTION_DAYS),
+	}
+	d, err := time.ParseDuration(beego.AppConfig.DefaultString("capacity_report_interval", ""))
+	if err == nil && d >= time.Minute {
+		c.Interval = d
+	}
+	if c.RetentionDays <= 0 {
+		c.RetentionDays = DEFAULT_RETENTION_DAYS
+	}
+	return c
+}
@@ -0,0 +1,89 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package capacity
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/core"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/rest/controller"
+	"net/http"
+	"time"
+)
+
+type GetCapacityResponse struct {
+	Snapshots []*Snapshot `json:"snapshots"`
+}
+
+// CapacityController serves the capacity snapshots of the tenants
+type CapacityController struct {
+}
+
+func (ctrl *CapacityController) URLPatterns() []rest.Route {
+	return []rest.Route{
+		{rest.HTTP_METHOD_GET, "/v4/:project/capacity", ctrl.GetCapacity},
+	}
+}
+
+// GetCapacity returns the snapshots between 'from' and 'to' in RFC3339,
+// default is the last 24 hours. The tenant gets the snapshots of its own
+// domain project, the admin gets all or the ones of the 'domain'
+func (ctrl *CapacityController) GetCapacity(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+	prefix := util.ParseDomainProject(ctx)
+	if core.IsDefaultDomainProject(prefix) {
+		prefix = ""
+		if domain := query.Get("domain"); len(domain) > 0 {
+			prefix = domain + core.SPLIT
+		}
+	}
+
+	now := time.Now()
+	to, err := parseTime(query.Get("to"), now)
+	if err != nil {
+		controller.WriteError(w, scerr.ErrInvalidParams, "parameter to must be in RFC3339")
+		return
+	}
+	from, err := parseTime(query.Get("from"), to.Add(-24*time.Hour))
+	if err != nil {
+		controller.WriteError(w, scerr.ErrInvalidParams, "parameter from must be in RFC3339")
+		return
+	}
+	if from.After(to) || to.Sub(from) > MAX_QUERY_RANGE {
+		controller.WriteError(w, scerr.ErrInvalidParams,
+			"parameter from must be before to, and in "+MAX_QUERY_RANGE.String())
+		return
+	}
+
+	snapshots, err := Query(ctx, prefix, from, to)
+	if err != nil {
+		log.Errorf(err, "query the capacity of '%s' failed", prefix)
+		controller.WriteError(w, scerr.ErrUnavailableBackend, err.Error())
+		return
+	}
+	controller.WriteResponse(w, nil, &GetCapacityResponse{Snapshots: snapshots})
+}
+
+func parseTime(s string, def time.Time) (time.Time, error) {
+	if len(s) == 0 {
+		return def, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
@@ -0,0 +1,57 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package consul
+
+import (
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"testing"
+)
+
+func TestToServiceEntry(t *testing.T) {
+	service := &pb.MicroService{ServiceId: "s", AppId: "a", ServiceName: "n", Version: "1.0.0"}
+	instance := &pb.MicroServiceInstance{
+		InstanceId: "i",
+		HostName:   "h",
+		Status:     pb.MSI_UP,
+		Endpoints:  []string{"rest://127.0.0.1:8080/?sslEnabled=true", "highway://127.0.0.1:8081"},
+		Properties: map[string]string{"k": "v"},
+	}
+	entry := toServiceEntry(service, instance)
+	if entry.Node.Node != "h" || entry.Node.Address != "127.0.0.1" {
+		t.Fatalf("TestToServiceEntry failed, %v", entry.Node)
+	}
+	s := entry.Service
+	if s.ID != "i" || s.Service != "n" || s.Address != "127.0.0.1" || s.Port != 8080 ||
+		s.Meta["k"] != "v" || s.Meta["scheme"] != "rest" || s.Meta["secure"] != "true" ||
+		!hasTags(s.Tags, []string{"app=a", "version=1.0.0"}) {
+		t.Fatalf("TestToServiceEntry failed, %v", s)
+	}
+	if len(entry.Checks) != 1 || entry.Checks[0].Status != HEALTH_PASSING {
+		t.Fatalf("TestToServiceEntry failed, %v", entry.Checks)
+	}
+
+	instance.Status = pb.MSI_OUTOFSERVICE
+	instance.Endpoints = nil
+	instance.HostName = ""
+	entry = toServiceEntry(service, instance)
+	if entry.Checks[0].Status != HEALTH_CRITICAL || entry.Service.Port != 0 {
+		t.Fatalf("TestToServiceEntry failed, %v", entry)
+	}
+	if hasTags(entry.Service.Tags, []string{"app=b"}) {
+		t.Fatalf("TestToServiceEntry failed")
+	}
+}
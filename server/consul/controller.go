@@ -0,0 +1,115 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package consul
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/core"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/rest/controller"
+	"golang.org/x/net/context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ConsulController serves the read-only Consul compatible API, the domain
+// is specified by the 'X-Domain-Name' header and the project by the 'ns'
+// query, both default to 'default'. The blocking queries are supported by
+// the 'index' and 'wait' query, the index is the revision of the registry
+type ConsulController struct {
+}
+
+func (ctrl *ConsulController) URLPatterns() []rest.Route {
+	return []rest.Route{
+		{rest.HTTP_METHOD_GET, "/v1/agent/self", ctrl.AgentSelf},
+		{rest.HTTP_METHOD_GET, "/v1/catalog/services", ctrl.CatalogServices},
+		{rest.HTTP_METHOD_GET, "/v1/health/service/:name", ctrl.HealthService},
+	}
+}
+
+func consulContext(r *http.Request) context.Context {
+	domain := r.Header.Get("X-Domain-Name")
+	if len(domain) == 0 {
+		domain = core.REGISTRY_DOMAIN
+	}
+	project := r.URL.Query().Get("ns")
+	if len(project) == 0 {
+		project = core.REGISTRY_PROJECT
+	}
+	return util.SetDomainProject(r.Context(), domain, project)
+}
+
+// wait blocks the request if the 'index' query is specified, and sets
+// the response index header
+func wait(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	index, _ := strconv.ParseInt(query.Get("index"), 10, 64)
+	timeout, err := time.ParseDuration(query.Get("wait"))
+	if err != nil || timeout <= 0 {
+		timeout = DEFAULT_WAIT
+	}
+	if timeout > MAX_WAIT {
+		timeout = MAX_WAIT
+	}
+	rev := WaitIndex(r.Context(), index, timeout)
+	if rev <= 0 {
+		// the index must be greater than 0
+		rev = 1
+	}
+	w.Header().Set("X-Consul-Index", strconv.FormatInt(rev, 10))
+	w.Header().Set("X-Consul-KnownLeader", "true")
+	w.Header().Set("X-Consul-LastContact", "0")
+}
+
+func (ctrl *ConsulController) AgentSelf(w http.ResponseWriter, r *http.Request) {
+	controller.WriteResponse(w, nil, &AgentSelf{
+		Config: map[string]interface{}{
+			"Datacenter": Datacenter(),
+			"NodeName":   core.Instance.HostName,
+			"NodeID":     core.Instance.InstanceId,
+		},
+	})
+}
+
+func (ctrl *ConsulController) CatalogServices(w http.ResponseWriter, r *http.Request) {
+	wait(w, r)
+	ctx := consulContext(r)
+	catalog, err := Catalog(ctx, util.ParseDomainProject(ctx))
+	if err != nil {
+		controller.WriteError(w, scerr.ErrInternal, err.Error())
+		return
+	}
+	controller.WriteResponse(w, nil, catalog)
+}
+
+func (ctrl *ConsulController) HealthService(w http.ResponseWriter, r *http.Request) {
+	wait(w, r)
+	ctx := consulContext(r)
+	query := r.URL.Query()
+	_, passing := query["passing"]
+	if v := query.Get("passing"); len(v) > 0 {
+		passing, _ = strconv.ParseBool(v)
+	}
+	entries, err := HealthService(ctx, util.ParseDomainProject(ctx), query.Get(":name"), passing, query["tag"])
+	if err != nil {
+		controller.WriteError(w, scerr.ErrInternal, err.Error())
+		return
+	}
+	controller.WriteResponse(w, nil, entries)
+}
@@ -0,0 +1,215 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package consul
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/core/backend"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	serviceUtil "github.com/apache/servicecomb-service-center/server/service/util"
+	"golang.org/x/net/context"
+	"net/url"
+	"time"
+)
+
+const (
+	HEALTH_PASSING  = "passing"
+	HEALTH_WARNING  = "warning"
+	HEALTH_CRITICAL = "critical"
+
+	DEFAULT_WAIT = 5 * time.Minute
+	MAX_WAIT     = 10 * time.Minute
+	// the interval to check the revision in blocking queries
+	pollInterval = 500 * time.Millisecond
+)
+
+// Datacenter returns the cluster name of service center
+func Datacenter() string {
+	return registry.Configuration().ClusterName
+}
+
+func cacheOnly(ctx context.Context) context.Context {
+	return util.SetContext(ctx, serviceUtil.CTX_CACHEONLY, "1")
+}
+
+// Catalog returns the service names with the tags of all their versions
+func Catalog(ctx context.Context, domainProject string) (map[string][]string, error) {
+	services, err := serviceUtil.GetServicesByDomainProject(cacheOnly(ctx), domainProject)
+	if err != nil {
+		return nil, err
+	}
+	catalog := make(map[string][]string, len(services))
+	for _, service := range services {
+		tags, ok := catalog[service.ServiceName]
+		if !ok {
+			tags = []string{}
+		}
+		for _, tag := range serviceTags(service) {
+			if !hasTag(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+		catalog[service.ServiceName] = tags
+	}
+	return catalog, nil
+}
+
+// HealthService returns the instances of the services named name, if
+// passing is true, only returns the UP instances. The instances must
+// have all the tags
+func HealthService(ctx context.Context, domainProject, name string, passing bool, tags []string) ([]*ServiceEntry, error) {
+	ctx = cacheOnly(ctx)
+	services, err := serviceUtil.GetServicesByDomainProject(ctx, domainProject)
+	if err != nil {
+		return nil, err
+	}
+	entries := []*ServiceEntry{}
+	for _, service := range services {
+		if service.ServiceName != name || !hasTags(serviceTags(service), tags) {
+			continue
+		}
+		instances, err := serviceUtil.GetAllInstancesOfOneService(ctx, domainProject, service.ServiceId)
+		if err != nil {
+			return nil, err
+		}
+		for _, instance := range instances {
+			entry := toServiceEntry(service, instance)
+			if passing && entry.Checks[0].Status != HEALTH_PASSING {
+				continue
+			}
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// WaitIndex blocks until the revision is greater than index or the wait
+// time elapses, returns the current revision
+func WaitIndex(ctx context.Context, index int64, wait time.Duration) int64 {
+	rev := backend.Revision()
+	if index <= 0 || rev > index {
+		return rev
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return backend.Revision()
+		case <-timer.C:
+			return backend.Revision()
+		case <-ticker.C:
+			if rev = backend.Revision(); rev > index {
+				return rev
+			}
+		}
+	}
+}
+
+func serviceTags(service *pb.MicroService) []string {
+	tags := []string{"app=" + service.AppId, "version=" + service.Version}
+	if len(service.Environment) > 0 {
+		tags = append(tags, "env="+service.Environment)
+	}
+	return tags
+}
+
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func hasTags(tags, wanted []string) bool {
+	for _, tag := range wanted {
+		if !hasTag(tags, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+func checkStatus(status string) string {
+	switch status {
+	case pb.MSI_UP:
+		return HEALTH_PASSING
+	case pb.MSI_STARTING:
+		return HEALTH_WARNING
+	default:
+		return HEALTH_CRITICAL
+	}
+}
+
+func toServiceEntry(service *pb.MicroService, instance *pb.MicroServiceInstance) *ServiceEntry {
+	meta := make(map[string]string, len(instance.Properties)+4)
+	for k, v := range instance.Properties {
+		meta[k] = v
+	}
+	meta["app"] = service.AppId
+	meta["version"] = service.Version
+	meta["serviceId"] = service.ServiceId
+
+	var address string
+	var port int
+	if len(instance.Endpoints) > 0 {
+		// the consul service has only one address
+		if u, err := url.Parse(instance.Endpoints[0]); err == nil {
+			ipPort := util.ParseIpPort(u.Host)
+			address, port = ipPort.IP, int(ipPort.Port)
+			meta["scheme"] = u.Scheme
+			if u.Query().Get("sslEnabled") == "true" {
+				meta["secure"] = "true"
+			}
+		}
+	}
+	node := instance.HostName
+	if len(node) == 0 {
+		node = address
+	}
+	return &ServiceEntry{
+		Node: &Node{
+			Node:       node,
+			Address:    address,
+			Datacenter: Datacenter(),
+		},
+		Service: &AgentService{
+			ID:      instance.InstanceId,
+			Service: service.ServiceName,
+			Tags:    serviceTags(service),
+			Address: address,
+			Port:    port,
+			Meta:    meta,
+		},
+		Checks: []*HealthCheck{
+			{
+				Node:        node,
+				CheckID:     "service:" + instance.InstanceId,
+				Name:        "Service '" + service.ServiceName + "' check",
+				Status:      checkStatus(instance.Status),
+				Output:      instance.Status,
+				ServiceID:   instance.InstanceId,
+				ServiceName: service.ServiceName,
+			},
+		},
+	}
+}
@@ -0,0 +1,58 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package consul
+
+// the read-only subset of the Consul HTTP API v1 types
+
+type Node struct {
+	ID         string `json:"ID"`
+	Node       string `json:"Node"`
+	Address    string `json:"Address"`
+	Datacenter string `json:"Datacenter"`
+}
+
+type AgentService struct {
+	ID      string            `json:"ID"`
+	Service string            `json:"Service"`
+	Tags    []string          `json:"Tags"`
+	Address string            `json:"Address"`
+	Port    int               `json:"Port"`
+	Meta    map[string]string `json:"Meta"`
+}
+
+type HealthCheck struct {
+	Node        string `json:"Node"`
+	CheckID     string `json:"CheckID"`
+	Name        string `json:"Name"`
+	Status      string `json:"Status"`
+	Output      string `json:"Output"`
+	ServiceID   string `json:"ServiceID"`
+	ServiceName string `json:"ServiceName"`
+}
+
+// ServiceEntry is the element of '/v1/health/service/:name' response
+type ServiceEntry struct {
+	Node    *Node          `json:"Node"`
+	Service *AgentService  `json:"Service"`
+	Checks  []*HealthCheck `json:"Checks"`
+}
+
+// AgentSelf is the response of '/v1/agent/self', the service discovery
+// clients read the datacenter from it
+type AgentSelf struct {
+	Config map[string]interface{} `json:"Config"`
+}
@@ -29,6 +29,8 @@ const (
 	selfPreservationPercentage = 0.8
 	selfPreservationMaxTTL     = 10 * 60 // 10min
 	selfPreservationInitCount  = 5
+
+	DEFAULT_REVISION_LAG_INTERVAL = 30 * time.Second
 )
 
 var (
@@ -46,6 +48,7 @@ var (
 	SCHEMA_SUMMARY   discovery.Type
 	INSTANCE         discovery.Type
 	LEASE            discovery.Type
+	REVOKED_LEASE    discovery.Type
 )
 
 func registerInnerTypes() {
@@ -92,4 +95,7 @@ func registerInnerTypes() {
 	PROJECT = Store().MustInstall(NewAddOn("PROJECT",
 		discovery.Configure().WithPrefix(core.GetProjectRootKey("")).
 			WithInitSize(100).WithParser(pb.StringParser)))
+	REVOKED_LEASE = Store().MustInstall(NewAddOn("REVOKED_LEASE",
+		discovery.Configure().WithPrefix(core.GetRevokedLeaseRootKey("")).
+			WithInitSize(100).WithParser(pb.StringParser)))
 }
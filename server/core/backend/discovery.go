@@ -22,11 +22,13 @@ import (
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/pkg/task"
 	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/core"
 	"github.com/apache/servicecomb-service-center/server/plugin"
 	"github.com/apache/servicecomb-service-center/server/plugin/pkg/discovery"
 	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
 	"golang.org/x/net/context"
 	"sync"
+	"time"
 )
 
 var store = &KvStore{}
@@ -84,6 +86,7 @@ func (s *KvStore) getOrCreateAdaptor(t discovery.Type) discovery.Adaptor {
 
 func (s *KvStore) Run() {
 	s.goroutine.Do(s.store)
+	s.goroutine.Do(s.reportRevisionLag)
 	s.taskService.Run()
 }
 
@@ -102,6 +105,44 @@ func (s *KvStore) store(ctx context.Context) {
 	log.Debugf("all adaptors are ready")
 }
 
+// reportRevisionLag compares the revision of the last event cached with
+// the registry periodically, the lag keeps growing when the cache stops
+// receiving events silently
+func (s *KvStore) reportRevisionLag(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+		return
+	case <-s.ready:
+	}
+
+	timer := time.NewTimer(DEFAULT_REVISION_LAG_INTERVAL)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			if rev, err := s.registryRevision(ctx); err == nil {
+				ReportRevisionLag(rev - s.rev)
+			}
+			timer.Reset(DEFAULT_REVISION_LAG_INTERVAL)
+		}
+	}
+}
+
+func (s *KvStore) registryRevision(ctx context.Context) (int64, error) {
+	ctx, cancel := registry.WithTimeout(ctx)
+	defer cancel()
+	resp, err := Registry().Do(ctx, registry.GET,
+		registry.WithStrKey(core.GetRootKey()),
+		registry.WithCountOnly())
+	if err != nil {
+		log.Errorf(err, "get the registry revision failed")
+		return 0, err
+	}
+	return resp.Revision, nil
+}
+
 func (s *KvStore) closed() bool {
 	return s.isClose
 }
@@ -174,6 +215,7 @@ func (s *KvStore) DependencyRule() discovery.Adaptor            { return s.Adapt
 func (s *KvStore) DependencyQueue() discovery.Adaptor           { return s.Adaptors(DEPENDENCY_QUEUE) }
 func (s *KvStore) Domain() discovery.Adaptor                    { return s.Adaptors(DOMAIN) }
 func (s *KvStore) Project() discovery.Adaptor                   { return s.Adaptors(PROJECT) }
+func (s *KvStore) RevokedLease() discovery.Adaptor              { return s.Adaptors(REVOKED_LEASE) }
 
 func (s *KvStore) KeepAlive(ctx context.Context, opts ...registry.PluginOpOption) (int64, error) {
 	op := registry.OpPut(opts...)
@@ -0,0 +1,209 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package backend
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	"golang.org/x/net/context"
+	"sort"
+	"sync"
+)
+
+const CTX_DRYRUN = "_dry_run"
+
+const (
+	CHANGE_PUT    = "put"
+	CHANGE_DELETE = "delete"
+)
+
+// Change is a key which would be written or deleted by the request
+type Change struct {
+	Action string `json:"action"`
+	Key    string `json:"key"`
+}
+
+// DryRun records the changes of the request instead of writing them, the
+// registry engine checks it in the context of every write, so the reads
+// see the registry as it is and the writes do nothing
+type DryRun struct {
+	lock    sync.Mutex
+	changes map[string]string
+}
+
+func (dr *DryRun) add(action, key string) {
+	dr.lock.Lock()
+	dr.changes[key] = action
+	dr.lock.Unlock()
+}
+
+// Changes returns the changes recorded, sorted by key
+func (dr *DryRun) Changes() []Change {
+	dr.lock.Lock()
+	changes := make([]Change, 0, len(dr.changes))
+	for key, action := range dr.changes {
+		changes = append(changes, Change{Action: action, Key: key})
+	}
+	dr.lock.Unlock()
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+	return changes
+}
+
+func NewDryRun() *DryRun {
+	return &DryRun{changes: make(map[string]string)}
+}
+
+func WithDryRun(ctx context.Context, dr *DryRun) context.Context {
+	return util.SetContext(ctx, CTX_DRYRUN, dr)
+}
+
+func DryRunFromContext(ctx context.Context) *DryRun {
+	dr, _ := ctx.Value(CTX_DRYRUN).(*DryRun)
+	return dr
+}
+
+// IsDryRun tells the callers keeping the state out of the registry to
+// skip changing it
+func IsDryRun(ctx context.Context) bool {
+	return DryRunFromContext(ctx) != nil
+}
+
+// record expands the op to the keys it affects, a delete of the keys not
+// existing is not recorded
+func (s *registryEngine) record(ctx context.Context, dr *DryRun, op registry.PluginOp) (int64, error) {
+	switch op.Action {
+	case registry.Put:
+		dr.add(CHANGE_PUT, util.BytesToStringWithNoCopy(op.Key))
+		return 1, nil
+	case registry.Delete:
+		opts := []registry.PluginOpOption{registry.GET, registry.WithKey(op.Key), registry.WithKeyOnly()}
+		if op.Prefix {
+			opts = append(opts, registry.WithPrefix())
+		}
+		if len(op.EndKey) > 0 {
+			opts = append(opts, registry.WithEndKey(op.EndKey))
+		}
+		resp, err := s.Registry.Do(ctx, opts...)
+		if err != nil {
+			return 0, err
+		}
+		for _, kv := range resp.Kvs {
+			dr.add(CHANGE_DELETE, string(kv.Key))
+		}
+		return int64(len(resp.Kvs)), nil
+	}
+	return 0, nil
+}
+
+func (s *registryEngine) Do(ctx context.Context, opts ...registry.PluginOpOption) (*registry.PluginResponse, error) {
+	dr := DryRunFromContext(ctx)
+	op := registry.OptionsToOp(opts...)
+	if op.Action == registry.Get {
+		return s.Registry.Do(ctx, opts...)
+	}
+	if dr == nil {
+		if f := FenceFromContext(ctx); f != nil {
+			return s.fencedDo(ctx, f, op)
+		}
+		return s.Registry.Do(ctx, opts...)
+	}
+	n, err := s.record(ctx, dr, op)
+	if err != nil {
+		return nil, err
+	}
+	return &registry.PluginResponse{Succeeded: true, Count: n}, nil
+}
+
+func (s *registryEngine) PutNoOverride(ctx context.Context, opts ...registry.PluginOpOption) (bool, error) {
+	dr := DryRunFromContext(ctx)
+	op := registry.OptionsToOp(opts...)
+	if dr == nil {
+		if f := FenceFromContext(ctx); f != nil {
+			resp, err := s.fencedTxn(ctx, f, []registry.PluginOp{op},
+				[]registry.CompareOp{registry.OpCmp(registry.CmpVer(op.Key), registry.CMP_EQUAL, 0)}, nil)
+			if err != nil {
+				return false, err
+			}
+			return resp.Succeeded, nil
+		}
+		return s.Registry.PutNoOverride(ctx, opts...)
+	}
+	resp, err := s.Registry.Do(ctx, registry.GET, registry.WithKey(op.Key), registry.WithCountOnly())
+	if err != nil {
+		return false, err
+	}
+	if resp.Count > 0 {
+		return false, nil
+	}
+	dr.add(CHANGE_PUT, util.BytesToStringWithNoCopy(op.Key))
+	return true, nil
+}
+
+func (s *registryEngine) Txn(ctx context.Context, ops []registry.PluginOp) (*registry.PluginResponse, error) {
+	return s.TxnWithCmp(ctx, ops, nil, nil)
+}
+
+// TxnWithCmp evaluates the compares by an empty transaction in dry run,
+// then records the ops of the branch the transaction would take
+func (s *registryEngine) TxnWithCmp(ctx context.Context, success []registry.PluginOp,
+	cmp []registry.CompareOp, fail []registry.PluginOp) (*registry.PluginResponse, error) {
+	dr := DryRunFromContext(ctx)
+	if dr == nil {
+		if f := FenceFromContext(ctx); f != nil {
+			return s.fencedTxn(ctx, f, success, cmp, fail)
+		}
+		return s.Registry.TxnWithCmp(ctx, success, cmp, fail)
+	}
+	resp := &registry.PluginResponse{Succeeded: true}
+	if len(cmp) > 0 {
+		var err error
+		resp, err = s.Registry.TxnWithCmp(ctx, nil, cmp, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+	ops := success
+	if !resp.Succeeded {
+		ops = fail
+	}
+	for _, op := range ops {
+		if _, err := s.record(ctx, dr, op); err != nil {
+			return nil, err
+		}
+	}
+	return &registry.PluginResponse{Succeeded: resp.Succeeded, Revision: resp.Revision}, nil
+}
+
+// LeaseGrant returns a zero lease in dry run, the keys put with it are
+// recorded only
+func (s *registryEngine) LeaseGrant(ctx context.Context, TTL int64) (int64, error) {
+	if IsDryRun(ctx) {
+		return 0, nil
+	}
+	return s.Registry.LeaseGrant(ctx, TTL)
+}
+
+// LeaseRevoke does nothing in dry run, the keys attached to the lease are
+// recorded by the deletes of the caller
+func (s *registryEngine) LeaseRevoke(ctx context.Context, leaseID int64) error {
+	if IsDryRun(ctx) {
+		return nil
+	}
+	return s.Registry.LeaseRevoke(ctx, leaseID)
+}
@@ -0,0 +1,213 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package backend
+
+import (
+	"errors"
+	errorsEx "github.com/apache/servicecomb-service-center/pkg/errors"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	"golang.org/x/net/context"
+	"sync/atomic"
+)
+
+const (
+	CTX_FENCE = "_fence"
+	// fencedTxnRetries is the times to retry the fenced transaction if its
+	// compares change between the transaction and its fail branch
+	fencedTxnRetries = 3
+)
+
+var (
+	ErrStaleFence        = errors.New("the fencing token is stale")
+	ErrFencedTxnConflict = errorsEx.RaiseError(errors.New("the compares of the fenced transaction change concurrently"))
+)
+
+// Fence makes the writes of the request conditional on the lock key, the
+// registry engine adds the compare of the lock to every write, so the
+// writes of a holder are rejected once its lock is expired and acquired
+// again, even if the holder does not know it yet
+type Fence struct {
+	Key string
+	// Token is the create revision of the lock key when the holder
+	// acquires it
+	Token int64
+
+	stale int32
+}
+
+func (f *Fence) Cmp() registry.CompareOp {
+	return registry.OpCmp(registry.CmpStrCreateRev(f.Key), registry.CMP_EQUAL, f.Token)
+}
+
+// Stale returns true if any write of the request is rejected by the fence
+func (f *Fence) Stale() bool {
+	return atomic.LoadInt32(&f.stale) != 0
+}
+
+func (f *Fence) setStale() {
+	atomic.StoreInt32(&f.stale, 1)
+}
+
+func NewFence(key string, token int64) *Fence {
+	return &Fence{Key: key, Token: token}
+}
+
+func WithFence(ctx context.Context, f *Fence) context.Context {
+	return util.SetContext(ctx, CTX_FENCE, f)
+}
+
+func FenceFromContext(ctx context.Context) *Fence {
+	f, _ := ctx.Value(CTX_FENCE).(*Fence)
+	return f
+}
+
+// CheckFence returns ErrStaleFence if the lock is not held by the token
+func CheckFence(ctx context.Context, f *Fence) error {
+	resp, err := Registry().Do(ctx, registry.GET, registry.WithStrKey(f.Key), registry.WithKeyOnly())
+	if err != nil {
+		return err
+	}
+	if len(resp.Kvs) == 0 || resp.Kvs[0].CreateRevision != f.Token {
+		f.setStale()
+		return ErrStaleFence
+	}
+	return nil
+}
+
+// fencedDo runs the write with the compare of the fence, the response is
+// the same as the one of the registry Do
+func (s *registryEngine) fencedDo(ctx context.Context, f *Fence, op registry.PluginOp) (*registry.PluginResponse, error) {
+	var ops []registry.PluginOp
+	switch {
+	case op.Action == registry.Delete:
+		// the txn does not return the deleted kvs, read them first
+		ops = append(ops, rangeOf(op))
+	case op.PrevKV:
+		ops = append(ops, rangeOf(op))
+	}
+	ops = append(ops, op)
+	resp, err := s.Registry.TxnWithCmp(ctx, ops, []registry.CompareOp{f.Cmp()}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Succeeded {
+		f.setStale()
+		return nil, ErrStaleFence
+	}
+	if !op.PrevKV {
+		resp.Kvs = nil
+	}
+	if op.Action != registry.Delete {
+		resp.Count = 0
+	}
+	return resp, nil
+}
+
+// rangeOf returns the read of the keys the write changes
+func rangeOf(op registry.PluginOp) registry.PluginOp {
+	opts := []registry.PluginOpOption{registry.WithKey(op.Key)}
+	if op.Prefix {
+		opts = append(opts, registry.WithPrefix())
+	}
+	if len(op.EndKey) > 0 {
+		opts = append(opts, registry.WithEndKey(op.EndKey))
+	}
+	if !op.PrevKV {
+		opts = append(opts, registry.WithCountOnly())
+	}
+	return registry.OpGet(opts...)
+}
+
+// fencedTxn runs the transaction with the compare of the fence, returns
+// ErrStaleFence if the lock is not held by the token. The fail ops never
+// run with the stale fence: etcd can not nest the transactions, so they
+// run in another transaction guarded by the fence and by the compare
+// which failed, and the whole transaction is retried if it succeeds again
+func (s *registryEngine) fencedTxn(ctx context.Context, f *Fence, success []registry.PluginOp,
+	cmp []registry.CompareOp, fail []registry.PluginOp) (*registry.PluginResponse, error) {
+	fenceOps := []registry.PluginOp{registry.OpGet(registry.WithStrKey(f.Key), registry.WithKeyOnly())}
+	for i := 0; i < fencedTxnRetries; i++ {
+		cmps := make([]registry.CompareOp, 0, len(cmp)+1)
+		cmps = append(append(cmps, cmp...), f.Cmp())
+		resp, err := s.Registry.TxnWithCmp(ctx, success, cmps, fenceOps)
+		if err != nil || resp.Succeeded {
+			return resp, err
+		}
+		if !f.heldIn(resp) {
+			f.setStale()
+			return nil, ErrStaleFence
+		}
+		if len(fail) == 0 {
+			return &registry.PluginResponse{Revision: resp.Revision}, nil
+		}
+
+		// any compare holding the negation means the caller's compares fail
+		for _, c := range cmp {
+			for _, not := range negate(c) {
+				resp, err = s.Registry.TxnWithCmp(ctx, fail, []registry.CompareOp{f.Cmp(), not}, fenceOps)
+				if err != nil || resp.Succeeded {
+					if resp != nil {
+						resp.Succeeded = false
+					}
+					return resp, err
+				}
+				if !f.heldIn(resp) {
+					f.setStale()
+					return nil, ErrStaleFence
+				}
+			}
+		}
+	}
+	return nil, ErrFencedTxnConflict
+}
+
+// heldIn returns whether the lock key read in the response is held by
+// the token
+func (f *Fence) heldIn(resp *registry.PluginResponse) bool {
+	for _, kv := range resp.Kvs {
+		if util.BytesToStringWithNoCopy(kv.Key) == f.Key {
+			return kv.CreateRevision == f.Token
+		}
+	}
+	return false
+}
+
+// negate returns the compares any of which holds if the compare fails,
+// the value compare of an absent key always fails
+func negate(c registry.CompareOp) []registry.CompareOp {
+	with := func(result registry.CompareResult) registry.CompareOp {
+		n := c
+		n.Result = result
+		return n
+	}
+	var cmps []registry.CompareOp
+	switch c.Result {
+	case registry.CMP_EQUAL:
+		cmps = append(cmps, with(registry.CMP_NOT_EQUAL))
+	case registry.CMP_NOT_EQUAL:
+		cmps = append(cmps, with(registry.CMP_EQUAL))
+	case registry.CMP_GREATER:
+		cmps = append(cmps, with(registry.CMP_LESS), with(registry.CMP_EQUAL))
+	case registry.CMP_LESS:
+		cmps = append(cmps, with(registry.CMP_GREATER), with(registry.CMP_EQUAL))
+	}
+	if c.Type == registry.CMP_VALUE {
+		cmps = append(cmps, registry.OpCmp(registry.CmpVer(c.Key), registry.CMP_EQUAL, 0))
+	}
+	return cmps
+}
@@ -0,0 +1,146 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package backend
+
+import (
+	errorsEx "github.com/apache/servicecomb-service-center/pkg/errors"
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	"golang.org/x/net/context"
+	"sync"
+	"time"
+)
+
+type leaseRenewal struct {
+	leaseID int64
+	ttl     int64
+	err     error
+	done    chan struct{}
+}
+
+// LeaseBatcher coalesces the lease renewals in a short window into one
+// batch, so that the heartbeats of many instances in the same second
+// cost a few requests to the backend
+type LeaseBatcher struct {
+	Renewer registry.LeaseBatchRenewer
+	Window  time.Duration
+	Size    int
+
+	lock    sync.Mutex
+	pending []*leaseRenewal
+	timer   *time.Timer
+}
+
+// Renew waits for the renewal of the batch including the lease
+func (b *LeaseBatcher) Renew(ctx context.Context, leaseID int64) (int64, error) {
+	r := &leaseRenewal{leaseID: leaseID, done: make(chan struct{})}
+
+	b.lock.Lock()
+	b.pending = append(b.pending, r)
+	switch {
+	case len(b.pending) >= b.Size:
+		if b.timer != nil {
+			b.timer.Stop()
+		}
+		batch := b.pending
+		b.pending = nil
+		gopool.Go(func(_ context.Context) {
+			b.flush(batch)
+		})
+	case len(b.pending) == 1:
+		b.timer = time.AfterFunc(b.Window, b.flushPending)
+	}
+	b.lock.Unlock()
+
+	select {
+	case <-r.done:
+		return r.ttl, r.err
+	case <-ctx.Done():
+		return 0, errorsEx.RaiseError(ctx.Err())
+	}
+}
+
+func (b *LeaseBatcher) flushPending() {
+	b.lock.Lock()
+	batch := b.pending
+	b.pending = nil
+	b.lock.Unlock()
+	b.flush(batch)
+}
+
+func (b *LeaseBatcher) flush(batch []*leaseRenewal) {
+	if len(batch) == 0 {
+		return
+	}
+	// the same lease may be renewed by several heartbeats
+	index := make(map[int64]int, len(batch))
+	leaseIDs := make([]int64, 0, len(batch))
+	for _, r := range batch {
+		if _, ok := index[r.leaseID]; !ok {
+			index[r.leaseID] = len(leaseIDs)
+			leaseIDs = append(leaseIDs, r.leaseID)
+		}
+	}
+
+	TTLs, errs, err := b.Renewer.LeaseRenewBatch(context.Background(), leaseIDs)
+	ReportLeaseBatch(len(leaseIDs), err)
+	for _, r := range batch {
+		if err != nil {
+			r.err = err
+		} else {
+			i := index[r.leaseID]
+			r.ttl, r.err = TTLs[i], errs[i]
+		}
+		close(r.done)
+	}
+}
+
+func NewLeaseBatcher(renewer registry.LeaseBatchRenewer, window time.Duration, size int) *LeaseBatcher {
+	return &LeaseBatcher{
+		Renewer: renewer,
+		Window:  window,
+		Size:    size,
+	}
+}
+
+// LeaseRenew renews the lease in batches if the batching is configured
+func (s *registryEngine) LeaseRenew(ctx context.Context, leaseID int64) (int64, error) {
+	if s.batcher != nil {
+		return s.batcher.Renew(ctx, leaseID)
+	}
+	return s.Registry.LeaseRenew(ctx, leaseID)
+}
+
+// withLeaseBatcher returns the batcher if the registry plugin renews the
+// leases in batches and the batching is configured
+func withLeaseBatcher(instance, r registry.Registry) *LeaseBatcher {
+	cfg := registry.Configuration()
+	if cfg.LeaseBatchWindow <= 0 {
+		return nil
+	}
+	if _, ok := instance.(registry.LeaseBatchRenewer); !ok {
+		log.Warnf("the registry does not renew the leases in batches, ignore the batch window %s",
+			cfg.LeaseBatchWindow)
+		return nil
+	}
+	renewer, ok := r.(registry.LeaseBatchRenewer)
+	if !ok {
+		return nil
+	}
+	return NewLeaseBatcher(renewer, cfg.LeaseBatchWindow, cfg.LeaseBatchSize)
+}
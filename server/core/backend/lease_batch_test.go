@@ -0,0 +1,96 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package backend
+
+import (
+	"errors"
+	"golang.org/x/net/context"
+	"sync"
+	"testing"
+	"time"
+)
+
+type mockBatchRenewer struct {
+	lock    sync.Mutex
+	batches [][]int64
+	err     error
+}
+
+func (r *mockBatchRenewer) LeaseRenewBatch(ctx context.Context, leaseIDs []int64) ([]int64, []error, error) {
+	r.lock.Lock()
+	r.batches = append(r.batches, leaseIDs)
+	r.lock.Unlock()
+	if r.err != nil {
+		return nil, nil, r.err
+	}
+	TTLs, errs := make([]int64, len(leaseIDs)), make([]error, len(leaseIDs))
+	for i, leaseID := range leaseIDs {
+		if leaseID < 0 {
+			errs[i] = errors.New("lease not found")
+			continue
+		}
+		TTLs[i] = leaseID * 10
+	}
+	return TTLs, errs, nil
+}
+
+func renewAll(b *LeaseBatcher, leaseIDs ...int64) ([]int64, []error) {
+	var wg sync.WaitGroup
+	TTLs, errs := make([]int64, len(leaseIDs)), make([]error, len(leaseIDs))
+	for i, leaseID := range leaseIDs {
+		wg.Add(1)
+		go func(i int, leaseID int64) {
+			defer wg.Done()
+			TTLs[i], errs[i] = b.Renew(context.Background(), leaseID)
+		}(i, leaseID)
+	}
+	wg.Wait()
+	return TTLs, errs
+}
+
+func TestLeaseBatcher_Renew(t *testing.T) {
+	r := &mockBatchRenewer{}
+	b := NewLeaseBatcher(r, 50*time.Millisecond, 100)
+
+	TTLs, errs := renewAll(b, 1, 2, 2, -1)
+	if len(r.batches) != 1 || len(r.batches[0]) != 3 {
+		t.Fatalf("TestLeaseBatcher_Renew failed, %v", r.batches)
+	}
+	if TTLs[0] != 10 || TTLs[1] != 20 || TTLs[2] != 20 || errs[3] == nil {
+		t.Fatalf("TestLeaseBatcher_Renew failed, %v, %v", TTLs, errs)
+	}
+
+	// flush at once if the batch is full
+	r.batches = nil
+	b.Size, b.Window = 2, time.Hour
+	if TTLs, errs = renewAll(b, 1, 2); errs[0] != nil || errs[1] != nil || len(r.batches) != 1 {
+		t.Fatalf("TestLeaseBatcher_Renew failed, %v, %v", errs, r.batches)
+	}
+
+	r.err = errors.New("network error")
+	b.Window = 10 * time.Millisecond
+	if _, errs = renewAll(b, 1); errs[0] != r.err {
+		t.Fatalf("TestLeaseBatcher_Renew failed, %v", errs)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	b.Window = time.Hour
+	if _, err := b.Renew(ctx, 1); err == nil {
+		t.Fatalf("TestLeaseBatcher_Renew failed")
+	}
+}
@@ -19,6 +19,7 @@ package backend
 import (
 	"fmt"
 	errorsEx "github.com/apache/servicecomb-service-center/pkg/errors"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
 	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry/buildin"
 	"golang.org/x/net/context"
 	"testing"
@@ -60,3 +61,26 @@ func TestLeaseTask_Do(t *testing.T) {
 		t.Fatalf("TestLeaseTask_Do failed")
 	}
 }
+
+func TestLeaseTask_Do_BreakerOpen(t *testing.T) {
+	now := time.Now().UTC()
+	c := &mockRegistry{}
+	breaker := registry.NewCircuitBreaker(1, time.Minute)
+	breaker.Done(false, context.DeadlineExceeded)
+	if breaker.State() != registry.BREAKER_OPEN {
+		t.Fatalf("TestLeaseTask_Do_BreakerOpen failed, %s", breaker.State())
+	}
+	lt := &LeaseTask{
+		Client:   &registry.BreakerRegistry{Registry: c, Breaker: breaker},
+		key:      ToLeaseAsyncTaskKey("/a"),
+		LeaseID:  1,
+		recvSec:  now.Unix(),
+		recvNsec: int64(now.Nanosecond()),
+	}
+
+	// the heartbeat is not regarded as lease not found
+	err := lt.Do(context.Background())
+	if err != nil || lt.Err() != nil {
+		t.Fatalf("TestLeaseTask_Do_BreakerOpen failed, %v, %v", err, lt.Err())
+	}
+}
@@ -18,9 +18,15 @@ package backend
 
 import (
 	"github.com/apache/servicecomb-service-center/server/metric"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
+const (
+	LEASE_BATCH_SUCCESS = "SUCCESS"
+	LEASE_BATCH_FAILURE = "FAILURE"
+)
+
 var (
 	scCounter = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
@@ -29,13 +35,90 @@ var (
 			Name:      "sc_total",
 			Help:      "Counter of the Service Center instance",
 		}, []string{"instance"})
+
+	revisionLagGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metric.FamilyName,
+			Subsystem: "db",
+			Name:      "cache_revision_lag",
+			Help:      "Revisions of the local cache behind the backend store",
+		}, []string{"instance"})
+
+	breakerStateGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metric.FamilyName,
+			Subsystem: "db",
+			Name:      "breaker_state",
+			Help:      "State of the registry circuit breaker, 0 closed, 1 open, 2 half-open",
+		}, []string{"instance"})
+
+	breakerRejectCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metric.FamilyName,
+			Subsystem: "db",
+			Name:      "breaker_rejected_total",
+			Help:      "Counter of the requests to the registry rejected by the breaker or the concurrency limit",
+		}, []string{"instance", "reason"})
+
+	leaseBatchHistogram = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metric.FamilyName,
+			Subsystem: "db",
+			Name:      "lease_batch_size",
+			Help:      "Histogram of the leases renewed in one batch",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 8),
+		}, []string{"instance", "status"})
 )
 
 func init() {
-	prometheus.MustRegister(scCounter)
+	prometheus.MustRegister(scCounter, revisionLagGauge, breakerStateGauge, breakerRejectCounter,
+		leaseBatchHistogram)
 }
 
 func ReportScInstance() {
 	instance := metric.InstanceName()
 	scCounter.WithLabelValues(instance).Add(1)
 }
+
+func ReportRevisionLag(lag int64) {
+	instance := metric.InstanceName()
+	if len(instance) == 0 {
+		return
+	}
+	if lag < 0 {
+		lag = 0
+	}
+	revisionLagGauge.WithLabelValues(instance).Set(float64(lag))
+}
+
+func ReportBreakerState(state registry.BreakerState) {
+	instance := metric.InstanceName()
+	if len(instance) == 0 {
+		return
+	}
+	breakerStateGauge.WithLabelValues(instance).Set(float64(state))
+}
+
+func ReportBreakerReject(err error) {
+	instance := metric.InstanceName()
+	if len(instance) == 0 {
+		return
+	}
+	reason := "open"
+	if err == registry.ErrTooManyRequests {
+		reason = "limit"
+	}
+	breakerRejectCounter.WithLabelValues(instance, reason).Inc()
+}
+
+func ReportLeaseBatch(size int, err error) {
+	instance := metric.InstanceName()
+	if len(instance) == 0 {
+		return
+	}
+	status := LEASE_BATCH_SUCCESS
+	if err != nil {
+		status = LEASE_BATCH_FAILURE
+	}
+	leaseBatchHistogram.WithLabelValues(instance, status).Observe(float64(size))
+}
@@ -52,12 +52,30 @@ func NewEngine() (*registryEngine, error) {
 		return nil, err
 	case <-instance.Ready():
 	}
+	r := withBreaker(instance)
 	return &registryEngine{
-		Registry:  instance,
+		Registry:  r,
+		batcher:   withLeaseBatcher(instance, r),
 		goroutine: gopool.New(context.Background()),
 	}, nil
 }
 
+// withBreaker guards the registry with the circuit breaker and the
+// concurrency limit if they are configured
+func withBreaker(instance registry.Registry) registry.Registry {
+	r := registry.NewBreakerRegistry(instance, registry.Configuration())
+	if br, ok := r.(*registry.BreakerRegistry); ok {
+		if br.Breaker != nil {
+			br.Breaker.OnStateChange = func(from, to registry.BreakerState) {
+				log.Warnf("the circuit breaker of the registry turns %s from %s", to, from)
+				ReportBreakerState(to)
+			}
+		}
+		br.OnReject = ReportBreakerReject
+	}
+	return r
+}
+
 func Registry() registry.Registry {
 	return RegistryEngine()
 }
@@ -113,6 +131,7 @@ func BatchCommitWithCmp(ctx context.Context, opts []registry.PluginOp,
 
 type registryEngine struct {
 	registry.Registry
+	batcher   *LeaseBatcher
 	goroutine *gopool.Pool
 }
 
@@ -130,8 +149,17 @@ func (s *registryEngine) Start() error {
 func (s *registryEngine) Stop() {
 	s.goroutine.Close(true)
 
-	ctx, _ := context.WithTimeout(context.Background(), 3*time.Second)
-	s.unregisterInstance(ctx)
+	// retry until timed out, otherwise the instance is left in the
+	// registry until the lease expires
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	for s.unregisterInstance(ctx) != nil {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
 }
 
 func (s *registryEngine) selfRegister(ctx context.Context) error {
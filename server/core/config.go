@@ -18,6 +18,7 @@ package core
 
 import (
 	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/pagination"
 	"github.com/apache/servicecomb-service-center/pkg/plugin"
 	"github.com/apache/servicecomb-service-center/pkg/util"
 	pb "github.com/apache/servicecomb-service-center/server/core/proto"
@@ -40,6 +41,8 @@ func Configure() {
 
 	plugin.SetPluginDir(ServerInfo.Config.PluginsDir)
 
+	pagination.SetKey([]byte(beego.AppConfig.String("pagination_secret")))
+
 	initLogger()
 
 	version.Ver().Log()
@@ -64,6 +67,13 @@ func newInfo() pb.ServerInformation {
 			ReadTimeout:       beego.AppConfig.DefaultString("read_timeout", "60s"),
 			IdleTimeout:       beego.AppConfig.DefaultString("idle_timeout", "60s"),
 			WriteTimeout:      beego.AppConfig.DefaultString("write_timeout", "60s"),
+			GraceTimeout:      beego.AppConfig.DefaultString("grace_timeout", "10s"),
+			KeepAliveTimeout:  beego.AppConfig.DefaultString("keepalive_timeout", "1m"),
+
+			EnableHTTP2:          beego.AppConfig.DefaultInt("enable_http2", 1) != 0,
+			EnableH2C:            beego.AppConfig.DefaultInt("enable_h2c", 0) != 0,
+			MaxConcurrentStreams: beego.AppConfig.DefaultInt64("http2_max_concurrent_streams", 250),
+			MaxConnections:       beego.AppConfig.DefaultInt64("max_connections", 0),
 
 			LimitTTLUnit:     beego.AppConfig.DefaultString("limit_ttl", "s"),
 			LimitConnections: int64(beego.AppConfig.DefaultInt("limit_conns", 0)),
@@ -92,6 +102,13 @@ func newInfo() pb.ServerInformation {
 			EnablePProf:  beego.AppConfig.DefaultInt("enable_pprof", 0) != 0,
 			EnableCache:  beego.AppConfig.DefaultInt("enable_cache", 1) != 0,
 			SelfRegister: beego.AppConfig.DefaultInt("self_register", 1) != 0,
+
+			EnableWatchCompression: beego.AppConfig.DefaultInt("watch_compression", 1) != 0,
+
+			RecordSpiffeId: beego.AppConfig.DefaultInt("spiffe_record_instance", 0) != 0,
+
+			InstanceConflictPolicy: beego.AppConfig.DefaultString("instance_conflict_policy",
+				pb.INSTANCE_CONFLICT_REUSE),
 		},
 	}
 }
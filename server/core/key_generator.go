@@ -42,6 +42,10 @@ const (
 	REGISTRY_DEPS_RULE_KEY      = "dep-rules"
 	REGISTRY_DEPS_QUEUE_KEY     = "dep-queue"
 	REGISTRY_METRICS_KEY        = "metrics"
+	REGISTRY_DATA_VERSION_KEY   = "data-version"
+	REGISTRY_CLIENT_LOCK_KEY    = "client-locks"
+	REGISTRY_REVOKED_LEASE_KEY  = "revoked-leases"
+	REGISTRY_REVISION_KEY       = "resource-revisions"
 	DEPS_QUEUE_UUID             = "0"
 	DEPS_CONSUMER               = "c"
 	DEPS_PROVIDER               = "p"
@@ -51,210 +55,161 @@ func GetRootKey() string {
 	return SPLIT + REGISTRY_ROOT_KEY
 }
 
+// the pre-computed prefixes of the keys, the keys are generated by
+// joinKey in one allocation, see BenchmarkGenerateInstanceKey
+var (
+	serviceRootPrefix              = rootPrefix(REGISTRY_SERVICE_KEY, REGISTRY_FILE)
+	serviceIndexRootPrefix         = rootPrefix(REGISTRY_SERVICE_KEY, REGISTRY_INDEX)
+	serviceAliasRootPrefix         = rootPrefix(REGISTRY_SERVICE_KEY, REGISTRY_ALIAS_KEY)
+	serviceRuleRootPrefix          = rootPrefix(REGISTRY_SERVICE_KEY, REGISTRY_RULE_KEY)
+	serviceRuleIndexRootPrefix     = rootPrefix(REGISTRY_SERVICE_KEY, REGISTRY_RULE_INDEX_KEY)
+	serviceTagRootPrefix           = rootPrefix(REGISTRY_SERVICE_KEY, REGISTRY_TAG_KEY)
+	serviceSchemaRootPrefix        = rootPrefix(REGISTRY_SERVICE_KEY, REGISTRY_SCHEMA_KEY)
+	serviceSchemaSummaryRootPrefix = rootPrefix(REGISTRY_SERVICE_KEY, REGISTRY_SCHEMA_SUMMARY_KEY)
+	serviceDepsRuleRootPrefix      = rootPrefix(REGISTRY_SERVICE_KEY, REGISTRY_DEPS_RULE_KEY)
+	serviceDepsQueueRootPrefix     = rootPrefix(REGISTRY_SERVICE_KEY, REGISTRY_DEPS_QUEUE_KEY)
+	serviceDepsRootPrefix          = rootPrefix(REGISTRY_SERVICE_KEY, REGISTRY_DEPENDENCY_KEY)
+	instanceRootPrefix             = rootPrefix(REGISTRY_INSTANCE_KEY, REGISTRY_FILE)
+	instanceLeaseRootPrefix        = rootPrefix(REGISTRY_INSTANCE_KEY, REGISTRY_LEASE_KEY)
+	domainRootPrefix               = rootPrefix(REGISTRY_DOMAIN_KEY)
+	projectRootPrefix              = rootPrefix(REGISTRY_PROJECT_KEY)
+	metricsRootPrefix              = rootPrefix(REGISTRY_METRICS_KEY)
+	clientLockRootPrefix           = rootPrefix(REGISTRY_CLIENT_LOCK_KEY)
+	revokedLeaseRootPrefix         = rootPrefix(REGISTRY_REVOKED_LEASE_KEY)
+	resourceRevisionRootPrefix     = rootPrefix(REGISTRY_REVISION_KEY)
+)
+
+func rootPrefix(paths ...string) []byte {
+	return []byte(joinKey([]byte(GetRootKey()), paths...))
+}
+
+// joinKey appends the paths to the prefix by SPLIT
+func joinKey(prefix []byte, paths ...string) string {
+	n := len(prefix) + len(SPLIT)*len(paths)
+	for _, path := range paths {
+		n += len(path)
+	}
+	b := make([]byte, n)
+	l := copy(b, prefix)
+	for _, path := range paths {
+		l += copy(b[l:], SPLIT)
+		l += copy(b[l:], path)
+	}
+	return util.BytesToStringWithNoCopy(b)
+}
+
 func GetServiceRootKey(domainProject string) string {
-	return util.StringJoin([]string{
-		GetRootKey(),
-		REGISTRY_SERVICE_KEY,
-		REGISTRY_FILE,
-		domainProject,
-	}, SPLIT)
+	return joinKey(serviceRootPrefix, domainProject)
 }
 
 func GetServiceIndexRootKey(domainProject string) string {
-	return util.StringJoin([]string{
-		GetRootKey(),
-		REGISTRY_SERVICE_KEY,
-		REGISTRY_INDEX,
-		domainProject,
-	}, SPLIT)
+	return joinKey(serviceIndexRootPrefix, domainProject)
 }
 
 func GetServiceAliasRootKey(domainProject string) string {
-	return util.StringJoin([]string{
-		GetRootKey(),
-		REGISTRY_SERVICE_KEY,
-		REGISTRY_ALIAS_KEY,
-		domainProject,
-	}, SPLIT)
+	return joinKey(serviceAliasRootPrefix, domainProject)
 }
 
 func GetServiceAppKey(domainProject, env, appId string) string {
-	return util.StringJoin([]string{
-		GetServiceIndexRootKey(domainProject),
-		env,
-		appId,
-	}, SPLIT)
+	return joinKey(serviceIndexRootPrefix, domainProject, env, appId)
 }
 
 func GetServiceRuleRootKey(domainProject string) string {
-	return util.StringJoin([]string{
-		GetRootKey(),
-		REGISTRY_SERVICE_KEY,
-		REGISTRY_RULE_KEY,
-		domainProject,
-	}, SPLIT)
+	return joinKey(serviceRuleRootPrefix, domainProject)
 }
 
 func GetServiceRuleIndexRootKey(domainProject string) string {
-	return util.StringJoin([]string{
-		GetRootKey(),
-		REGISTRY_SERVICE_KEY,
-		REGISTRY_RULE_INDEX_KEY,
-		domainProject,
-	}, SPLIT)
+	return joinKey(serviceRuleIndexRootPrefix, domainProject)
 }
 
 func GetServiceTagRootKey(domainProject string) string {
-	return util.StringJoin([]string{
-		GetRootKey(),
-		REGISTRY_SERVICE_KEY,
-		REGISTRY_TAG_KEY,
-		domainProject,
-	}, SPLIT)
+	return joinKey(serviceTagRootPrefix, domainProject)
 }
 
 func GetServiceSchemaRootKey(domainProject string) string {
-	return util.StringJoin([]string{
-		GetRootKey(),
-		REGISTRY_SERVICE_KEY,
-		REGISTRY_SCHEMA_KEY,
-		domainProject,
-	}, SPLIT)
+	return joinKey(serviceSchemaRootPrefix, domainProject)
 }
 
 func GetInstanceRootKey(domainProject string) string {
-	return util.StringJoin([]string{
-		GetRootKey(),
-		REGISTRY_INSTANCE_KEY,
-		REGISTRY_FILE,
-		domainProject,
-	}, SPLIT)
+	return joinKey(instanceRootPrefix, domainProject)
 }
 
 func GetInstanceLeaseRootKey(domainProject string) string {
-	return util.StringJoin([]string{
-		GetRootKey(),
-		REGISTRY_INSTANCE_KEY,
-		REGISTRY_LEASE_KEY,
-		domainProject,
-	}, SPLIT)
+	return joinKey(instanceLeaseRootPrefix, domainProject)
 }
 
 func GenerateServiceKey(domainProject string, serviceId string) string {
-	return util.StringJoin([]string{
-		GetServiceRootKey(domainProject),
-		serviceId,
-	}, SPLIT)
+	return joinKey(serviceRootPrefix, domainProject, serviceId)
 }
 
 func GenerateRuleIndexKey(domainProject string, serviceId string, attr string, pattern string) string {
-	return util.StringJoin([]string{
-		GetServiceRuleIndexRootKey(domainProject),
-		serviceId,
-		attr,
-		pattern,
-	}, SPLIT)
+	return joinKey(serviceRuleIndexRootPrefix, domainProject, serviceId, attr, pattern)
 }
 
 func GenerateServiceIndexKey(key *pb.MicroServiceKey) string {
-	return util.StringJoin([]string{
-		GetServiceIndexRootKey(key.Tenant),
+	return joinKey(serviceIndexRootPrefix,
+		key.Tenant,
 		key.Environment,
 		key.AppId,
 		key.ServiceName,
-		key.Version,
-	}, SPLIT)
+		key.Version)
 }
 
 func GenerateServiceAliasKey(key *pb.MicroServiceKey) string {
-	return util.StringJoin([]string{
-		GetServiceAliasRootKey(key.Tenant),
+	return joinKey(serviceAliasRootPrefix,
+		key.Tenant,
 		key.Environment,
 		key.AppId,
 		key.Alias,
-		key.Version,
-	}, SPLIT)
+		key.Version)
 }
 
 func GenerateServiceRuleKey(domainProject string, serviceId string, ruleId string) string {
-	return util.StringJoin([]string{
-		GetServiceRuleRootKey(domainProject),
-		serviceId,
-		ruleId,
-	}, SPLIT)
+	return joinKey(serviceRuleRootPrefix, domainProject, serviceId, ruleId)
 }
 
 func GenerateServiceTagKey(domainProject string, serviceId string) string {
-	return util.StringJoin([]string{
-		GetServiceTagRootKey(domainProject),
-		serviceId,
-	}, SPLIT)
+	return joinKey(serviceTagRootPrefix, domainProject, serviceId)
 }
 
 func GenerateServiceSchemaKey(domainProject string, serviceId string, schemaId string) string {
-	return util.StringJoin([]string{
-		GetServiceSchemaRootKey(domainProject),
-		serviceId,
-		schemaId,
-	}, SPLIT)
+	return joinKey(serviceSchemaRootPrefix, domainProject, serviceId, schemaId)
 }
 
 func GenerateServiceSchemaSummaryKey(domainProject string, serviceId string, schemaId string) string {
-	return util.StringJoin([]string{
-		GetServiceSchemaSummaryRootKey(domainProject),
-		serviceId,
-		schemaId,
-	}, SPLIT)
+	return joinKey(serviceSchemaSummaryRootPrefix, domainProject, serviceId, schemaId)
 }
 
 func GetServiceSchemaSummaryRootKey(domainProject string) string {
-	return util.StringJoin([]string{
-		GetRootKey(),
-		REGISTRY_SERVICE_KEY,
-		REGISTRY_SCHEMA_SUMMARY_KEY,
-		domainProject,
-	}, SPLIT)
+	return joinKey(serviceSchemaSummaryRootPrefix, domainProject)
 }
 
 func GenerateInstanceKey(domainProject string, serviceId string, instanceId string) string {
-	return util.StringJoin([]string{
-		GetInstanceRootKey(domainProject),
-		serviceId,
-		instanceId,
-	}, SPLIT)
+	return joinKey(instanceRootPrefix, domainProject, serviceId, instanceId)
 }
 
 func GenerateInstanceLeaseKey(domainProject string, serviceId string, instanceId string) string {
-	return util.StringJoin([]string{
-		GetInstanceLeaseRootKey(domainProject),
-		serviceId,
-		instanceId,
-	}, SPLIT)
+	return joinKey(instanceLeaseRootPrefix, domainProject, serviceId, instanceId)
 }
 
 func GenerateServiceDependencyRuleKey(serviceType string, domainProject string, in *pb.MicroServiceKey) string {
 	if in == nil {
-		return util.StringJoin([]string{
-			GetServiceDependencyRuleRootKey(domainProject),
-			serviceType,
-		}, SPLIT)
+		return joinKey(serviceDepsRuleRootPrefix, domainProject, serviceType)
 	}
 	if in.ServiceName == "*" {
-		return util.StringJoin([]string{
-			GetServiceDependencyRuleRootKey(domainProject),
+		return joinKey(serviceDepsRuleRootPrefix,
+			domainProject,
 			serviceType,
 			in.Environment,
-			in.ServiceName,
-		}, SPLIT)
+			in.ServiceName)
 	}
-	return util.StringJoin([]string{
-		GetServiceDependencyRuleRootKey(domainProject),
+	return joinKey(serviceDepsRuleRootPrefix,
+		domainProject,
 		serviceType,
 		in.Environment,
 		in.AppId,
 		in.ServiceName,
-		in.Version,
-	}, SPLIT)
+		in.Version)
 }
 
 func GenerateConsumerDependencyRuleKey(domainProject string, in *pb.MicroServiceKey) string {
@@ -266,52 +221,27 @@ func GenerateProviderDependencyRuleKey(domainProject string, in *pb.MicroService
 }
 
 func GetServiceDependencyRuleRootKey(domainProject string) string {
-	return util.StringJoin([]string{
-		GetRootKey(),
-		REGISTRY_SERVICE_KEY,
-		REGISTRY_DEPS_RULE_KEY,
-		domainProject,
-	}, SPLIT)
+	return joinKey(serviceDepsRuleRootPrefix, domainProject)
 }
 
 func GetServiceDependencyQueueRootKey(domainProject string) string {
-	return util.StringJoin([]string{
-		GetRootKey(),
-		REGISTRY_SERVICE_KEY,
-		REGISTRY_DEPS_QUEUE_KEY,
-		domainProject,
-	}, SPLIT)
+	return joinKey(serviceDepsQueueRootPrefix, domainProject)
 }
 
 func GenerateConsumerDependencyQueueKey(domainProject, consumerId, uuid string) string {
-	return util.StringJoin([]string{
-		GetServiceDependencyQueueRootKey(domainProject),
-		consumerId,
-		uuid,
-	}, SPLIT)
+	return joinKey(serviceDepsQueueRootPrefix, domainProject, consumerId, uuid)
 }
 
 func GetServiceDependencyRootKey(domainProject string) string {
-	return util.StringJoin([]string{
-		GetRootKey(),
-		REGISTRY_SERVICE_KEY,
-		REGISTRY_DEPENDENCY_KEY,
-		domainProject,
-	}, SPLIT)
+	return joinKey(serviceDepsRootPrefix, domainProject)
 }
 
 func GetDomainRootKey() string {
-	return util.StringJoin([]string{
-		GetRootKey(),
-		REGISTRY_DOMAIN_KEY,
-	}, SPLIT)
+	return string(domainRootPrefix)
 }
 
 func GenerateDomainKey(domain string) string {
-	return util.StringJoin([]string{
-		GetDomainRootKey(),
-		domain,
-	}, SPLIT)
+	return joinKey(domainRootPrefix, domain)
 }
 
 func GetServerInfoKey() string {
@@ -321,33 +251,52 @@ func GetServerInfoKey() string {
 	}, SPLIT)
 }
 
-func GetMetricsRootKey() string {
+func GetDataVersionKey() string {
 	return util.StringJoin([]string{
 		GetRootKey(),
-		REGISTRY_METRICS_KEY,
+		REGISTRY_DATA_VERSION_KEY,
 	}, SPLIT)
 }
 
+func GetMetricsRootKey() string {
+	return string(metricsRootPrefix)
+}
+
 func GenerateMetricsKey(name, utc, domain string) string {
-	return util.StringJoin([]string{
-		GetMetricsRootKey(),
-		name,
-		utc,
-		domain,
-	}, SPLIT)
+	return joinKey(metricsRootPrefix, name, utc, domain)
 }
 
 func GetProjectRootKey(domain string) string {
-	return util.StringJoin([]string{
-		GetRootKey(),
-		REGISTRY_PROJECT_KEY,
-		domain,
-	}, SPLIT)
+	return joinKey(projectRootPrefix, domain)
 }
 
 func GenerateProjectKey(domain, project string) string {
-	return util.StringJoin([]string{
-		GetProjectRootKey(domain),
-		project,
-	}, SPLIT)
+	return joinKey(projectRootPrefix, domain, project)
+}
+
+func GetClientLockRootKey(domainProject string) string {
+	return joinKey(clientLockRootPrefix, domainProject)
+}
+
+func GenerateClientLockKey(domainProject string, name string) string {
+	return joinKey(clientLockRootPrefix, domainProject, name)
+}
+
+func GetRevokedLeaseRootKey(domainProject string) string {
+	return joinKey(revokedLeaseRootPrefix, domainProject)
+}
+
+func GenerateRevokedLeaseKey(domainProject string, serviceId string, instanceId string) string {
+	return joinKey(revokedLeaseRootPrefix, domainProject, serviceId, instanceId)
+}
+
+// GenerateDomainRevisionKey returns the key modified by every creation of
+// the resources in the domain, its mod revision stands for the domain
+func GenerateDomainRevisionKey(domain string) string {
+	return joinKey(resourceRevisionRootPrefix, domain)
+}
+
+// GenerateProjectRevisionKey is GenerateDomainRevisionKey of the project
+func GenerateProjectRevisionKey(domain, project string) string {
+	return joinKey(resourceRevisionRootPrefix, domain, project)
 }
@@ -69,3 +69,22 @@ func TestGenerateDependencyRuleKey(t *testing.T) {
 		t.Fatalf("TestGenerateDependencyRuleKey failed")
 	}
 }
+
+func TestGenerateInstanceKey(t *testing.T) {
+	if k := GenerateInstanceKey("a/b", "c", ""); k != "/cse-sr/inst/files/a/b/c/" {
+		t.Fatalf("TestGenerateInstanceKey failed, %s", k)
+	}
+	if k := GenerateInstanceLeaseKey("a/b", "c", "d"); k != "/cse-sr/inst/leases/a/b/c/d" {
+		t.Fatalf("TestGenerateInstanceKey failed, %s", k)
+	}
+	if k := GetDomainRootKey(); k != "/cse-sr/domains" {
+		t.Fatalf("TestGenerateInstanceKey failed, %s", k)
+	}
+}
+
+func BenchmarkGenerateInstanceKey(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		GenerateInstanceLeaseKey("default/default", "serviceId", "instanceId")
+	}
+}
@@ -0,0 +1,103 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proto
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CloudEvents 1.0 structured encoding of the registry events.
+//
+// The attributes follow these conventions:
+//
+//	specversion:     "1.0"
+//	id:              "{revision}-{serviceId}-{instanceId}", or
+//	                 "{revision}-{appId}-{serviceName}-{version}" for EXPIRE
+//	source:          "/v4/{project}/registry/microservices/{serviceId}", or
+//	                 "/v4/{project}/registry/microservices" for EXPIRE
+//	type:            "org.apache.servicecomb.servicecenter.instance.{action}",
+//	                 action is lower case, e.g. create, update, delete, expire
+//	subject:         "{instanceId}", or "{appId}/{serviceName}/{version}" for EXPIRE
+//	time:            the time when the event is encoded, in RFC3339
+//	datacontenttype: "application/json"
+//	data:            the WatchInstanceResponse without the response field
+//
+// The extension attributes:
+//
+//	domain:   the tenant domain of the event
+//	revision: the registry revision of the event, a client can resume
+//	          the watching from it
+const (
+	CLOUDEVENTS_SPEC_VERSION = "1.0"
+	CLOUDEVENTS_TYPE_PREFIX  = "org.apache.servicecomb.servicecenter."
+
+	CONTENT_TYPE_CLOUDEVENTS       = "application/cloudevents+json"
+	CONTENT_TYPE_CLOUDEVENTS_BATCH = "application/cloudevents-batch+json"
+)
+
+type CloudEvent struct {
+	SpecVersion     string      `json:"specversion"`
+	Id              string      `json:"id"`
+	Source          string      `json:"source"`
+	Type            string      `json:"type"`
+	Subject         string      `json:"subject,omitempty"`
+	Time            string      `json:"time,omitempty"`
+	DataContentType string      `json:"datacontenttype,omitempty"`
+	Data            interface{} `json:"data,omitempty"`
+
+	Domain   string `json:"domain,omitempty"`
+	Revision int64  `json:"revision,omitempty"`
+}
+
+// NewInstanceCloudEvent returns the CloudEvent of the instance event
+func NewInstanceCloudEvent(domainProject string, rev int64, resp *WatchInstanceResponse) *CloudEvent {
+	domain, project := domainProject, ""
+	if i := strings.Index(domainProject, "/"); i >= 0 {
+		domain, project = domainProject[:i], domainProject[i+1:]
+	}
+
+	revision := strconv.FormatInt(rev, 10)
+	evt := &CloudEvent{
+		SpecVersion:     CLOUDEVENTS_SPEC_VERSION,
+		Type:            CLOUDEVENTS_TYPE_PREFIX + "instance." + strings.ToLower(resp.Action),
+		Time:            time.Now().UTC().Format(time.RFC3339),
+		DataContentType: "application/json",
+		Data: &WatchInstanceResponse{
+			Action:   resp.Action,
+			Key:      resp.Key,
+			Instance: resp.Instance,
+		},
+		Domain:   domain,
+		Revision: rev,
+	}
+	source := "/v4/" + project + "/registry/microservices"
+	if resp.Action == string(EVT_EXPIRE) || resp.Instance == nil {
+		evt.Source = source
+		if resp.Key != nil {
+			evt.Subject = resp.Key.AppId + "/" + resp.Key.ServiceName + "/" + resp.Key.Version
+			evt.Id = strings.Join([]string{revision, resp.Key.AppId, resp.Key.ServiceName, resp.Key.Version}, "-")
+		} else {
+			evt.Id = revision
+		}
+		return evt
+	}
+	evt.Source = source + "/" + resp.Instance.ServiceId
+	evt.Subject = resp.Instance.InstanceId
+	evt.Id = strings.Join([]string{revision, resp.Instance.ServiceId, resp.Instance.InstanceId}, "-")
+	return evt
+}
@@ -0,0 +1,48 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package proto
+
+import (
+	"testing"
+)
+
+func TestNewInstanceCloudEvent(t *testing.T) {
+	evt := NewInstanceCloudEvent("d/p", 1, &WatchInstanceResponse{
+		Response: CreateResponse(Response_SUCCESS, "ok"),
+		Action:   string(EVT_CREATE),
+		Key:      &MicroServiceKey{AppId: "a", ServiceName: "s", Version: "1"},
+		Instance: &MicroServiceInstance{ServiceId: "sid", InstanceId: "iid"},
+	})
+	if evt.SpecVersion != CLOUDEVENTS_SPEC_VERSION || evt.Id != "1-sid-iid" ||
+		evt.Source != "/v4/p/registry/microservices/sid" || evt.Subject != "iid" ||
+		evt.Type != CLOUDEVENTS_TYPE_PREFIX+"instance.create" ||
+		evt.Domain != "d" || evt.Revision != 1 {
+		t.Fatalf("NewInstanceCloudEvent failed, %v", evt)
+	}
+	if data := evt.Data.(*WatchInstanceResponse); data.Response != nil || data.Instance.InstanceId != "iid" {
+		t.Fatalf("NewInstanceCloudEvent failed, %v", data)
+	}
+
+	evt = NewInstanceCloudEvent("d/p", 2, &WatchInstanceResponse{
+		Action: string(EVT_EXPIRE),
+		Key:    &MicroServiceKey{AppId: "a", ServiceName: "s", Version: "1"},
+	})
+	if evt.Id != "2-a-s-1" || evt.Source != "/v4/p/registry/microservices" ||
+		evt.Subject != "a/s/1" || evt.Type != CLOUDEVENTS_TYPE_PREFIX+"instance.expire" {
+		t.Fatalf("NewInstanceCloudEvent failed, %v", evt)
+	}
+}
@@ -42,6 +42,16 @@ const (
 	EXISTENCE_SCHEMA string = "schema"
 
 	PROP_ALLOW_CROSS_APP = "allowCrossApp"
+	PROP_SPIFFE_ID       = "spiffeId"
+	// PROP_INSTANCE_CONFLICT_POLICY is the service property overrides the
+	// instance_conflict_policy config
+	PROP_INSTANCE_CONFLICT_POLICY = "instanceConflictPolicy"
+
+	// the policies when an instance registers with the id of an existing
+	// instance
+	INSTANCE_CONFLICT_REUSE   = "reuse"
+	INSTANCE_CONFLICT_REPLACE = "replace"
+	INSTANCE_CONFLICT_REJECT  = "reject"
 
 	Response_SUCCESS int32 = 0
 
@@ -0,0 +1,128 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package proto
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// FieldMask selects the fields of the objects by the json names, the
+// fields not selected are omitted from the response, a nil mask selects
+// all the fields
+type FieldMask struct {
+	typ     reflect.Type
+	indexes []int
+}
+
+// Instance returns a copy of the instance with the selected fields only,
+// the instance may be shared by the cache and must not be changed
+func (m *FieldMask) Instance(in *MicroServiceInstance) *MicroServiceInstance {
+	if m == nil || in == nil {
+		return in
+	}
+	return m.apply(in).(*MicroServiceInstance)
+}
+
+func (m *FieldMask) Instances(ins []*MicroServiceInstance) []*MicroServiceInstance {
+	if m == nil || len(ins) == 0 {
+		return ins
+	}
+	masked := make([]*MicroServiceInstance, 0, len(ins))
+	for _, in := range ins {
+		masked = append(masked, m.Instance(in))
+	}
+	return masked
+}
+
+func (m *FieldMask) Service(in *MicroService) *MicroService {
+	if m == nil || in == nil {
+		return in
+	}
+	return m.apply(in).(*MicroService)
+}
+
+func (m *FieldMask) Services(ins []*MicroService) []*MicroService {
+	if m == nil || len(ins) == 0 {
+		return ins
+	}
+	masked := make([]*MicroService, 0, len(ins))
+	for _, in := range ins {
+		masked = append(masked, m.Service(in))
+	}
+	return masked
+}
+
+func (m *FieldMask) apply(v interface{}) interface{} {
+	src := reflect.ValueOf(v).Elem()
+	dst := reflect.New(m.typ)
+	for _, i := range m.indexes {
+		dst.Elem().Field(i).Set(src.Field(i))
+	}
+	return dst.Interface()
+}
+
+// NewInstanceFieldMask parses the comma separated json names of the
+// instance fields, the instanceId and serviceId are always selected
+func NewInstanceFieldMask(fields string) (*FieldMask, error) {
+	return newFieldMask(reflect.TypeOf(MicroServiceInstance{}), fields, "instanceId", "serviceId")
+}
+
+// NewServiceFieldMask parses the comma separated json names of the
+// microservice fields, the serviceId is always selected
+func NewServiceFieldMask(fields string) (*FieldMask, error) {
+	return newFieldMask(reflect.TypeOf(MicroService{}), fields, "serviceId")
+}
+
+func newFieldMask(t reflect.Type, fields string, keeps ...string) (*FieldMask, error) {
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	selected := make(map[string]bool, len(keeps))
+	for _, name := range keeps {
+		selected[name] = true
+	}
+	for _, name := range strings.Split(fields, ",") {
+		if name = strings.TrimSpace(name); len(name) > 0 {
+			selected[name] = true
+		}
+	}
+	m := &FieldMask{typ: t}
+	for i := 0; i < t.NumField(); i++ {
+		name := jsonName(t.Field(i))
+		if name != "-" && selected[name] {
+			m.indexes = append(m.indexes, i)
+			delete(selected, name)
+		}
+	}
+	for name := range selected {
+		return nil, fmt.Errorf("unknown field '%s'", name)
+	}
+	return m, nil
+}
+
+func jsonName(f reflect.StructField) string {
+	tag := f.Tag.Get("json")
+	if i := strings.Index(tag, ","); i >= 0 {
+		tag = tag[:i]
+	}
+	if len(tag) == 0 {
+		return f.Name
+	}
+	return tag
+}
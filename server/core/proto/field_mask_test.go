@@ -0,0 +1,62 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package proto
+
+import (
+	"testing"
+)
+
+func TestFieldMask(t *testing.T) {
+	m, err := NewInstanceFieldMask("")
+	if err != nil || m != nil {
+		t.Fatalf("TestFieldMask failed, %v %v", m, err)
+	}
+	in := &MicroServiceInstance{InstanceId: "1", ServiceId: "2", Endpoints: []string{"rest://127.0.0.1:8080"},
+		Status: "UP", Properties: map[string]string{"a": "b"}, HealthCheck: &HealthCheck{Mode: "push"}}
+	if m.Instance(in) != in {
+		t.Fatalf("TestFieldMask failed")
+	}
+
+	m, err = NewInstanceFieldMask("endpoints, status")
+	if err != nil {
+		t.Fatalf("TestFieldMask failed, %v", err)
+	}
+	out := m.Instances([]*MicroServiceInstance{in})
+	if len(out) != 1 || out[0] == in || out[0].InstanceId != "1" || out[0].ServiceId != "2" ||
+		len(out[0].Endpoints) != 1 || out[0].Status != "UP" || out[0].Properties != nil || out[0].HealthCheck != nil {
+		t.Fatalf("TestFieldMask failed, %v", out[0])
+	}
+	if in.Properties == nil || in.HealthCheck == nil {
+		t.Fatalf("TestFieldMask failed, the source is changed")
+	}
+
+	s, err := NewServiceFieldMask("serviceName,version")
+	if err != nil {
+		t.Fatalf("TestFieldMask failed, %v", err)
+	}
+	svc := s.Service(&MicroService{ServiceId: "1", ServiceName: "a", Version: "1.0.0", Schemas: []string{"x"}})
+	if svc.ServiceId != "1" || svc.ServiceName != "a" || svc.Version != "1.0.0" || svc.Schemas != nil {
+		t.Fatalf("TestFieldMask failed, %v", svc)
+	}
+
+	if _, err = NewInstanceFieldMask("endpoints,unknown"); err == nil {
+		t.Fatalf("TestFieldMask failed")
+	}
+	if _, err = NewServiceFieldMask("endpoints"); err == nil {
+		t.Fatalf("TestFieldMask failed")
+	}
+}
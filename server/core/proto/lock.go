@@ -0,0 +1,46 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package proto
+
+// Lock is the named lock of a domain project, the holder is empty if the
+// lock is free
+type Lock struct {
+	Name   string `json:"name"`
+	Holder string `json:"holder,omitempty"`
+	// TTL is the seconds the lock is kept without renewal
+	TTL int64 `json:"ttl,omitempty"`
+	// Token increases every time the lock is acquired, the holder passes
+	// it to the guarded resource to reject the requests of the stale
+	// holders
+	Token      int64  `json:"token,omitempty"`
+	AcquiredAt string `json:"acquiredAt,omitempty"`
+	// Session is the secret of the acquisition, it is only returned to the
+	// holder and required to renew or release the lock
+	Session string `json:"session,omitempty"`
+}
+
+type AcquireLockRequest struct {
+	Holder string `json:"holder"`
+	// TTL is the default ttl if omitted
+	TTL int64 `json:"ttl,omitempty"`
+	// Session renews the lock if it is still held by the session
+	Session string `json:"session,omitempty"`
+}
+
+type RenewLockRequest struct {
+	Session string `json:"session"`
+}
@@ -16,7 +16,6 @@
 package proto
 
 import (
-	"encoding/json"
 	"github.com/apache/servicecomb-service-center/pkg/util"
 )
 
@@ -52,11 +51,11 @@ var (
 	MapUnmarshal ParseValueFunc = func(src []byte, dist interface{}) error {
 		d := dist.(*interface{})
 		m := (*d).(map[string]string)
-		return json.Unmarshal(src, &m)
+		return util.JsonUnmarshal(src, &m)
 	}
 	JsonUnmarshal ParseValueFunc = func(src []byte, dist interface{}) error {
 		d := dist.(*interface{})
-		return json.Unmarshal(src, *d)
+		return util.JsonUnmarshal(src, *d)
 	}
 )
 
@@ -17,6 +17,7 @@
 package proto
 
 import (
+	"encoding/json"
 	"testing"
 )
 
@@ -98,3 +99,27 @@ func TestParseValueTypeFunc(t *testing.T) {
 		t.Fatalf("DependencyRuleParser.Unmarshal failed, %s", v)
 	}
 }
+
+var benchInstance = []byte(`{"instanceId":"8cde54a46aa011e8b9aa286ed488fc6b","serviceId":"8cdd2a716aa011e8b9aa286ed488fc6b",` +
+	`"endpoints":["rest://127.0.0.1:8080","highway://127.0.0.1:7070"],"hostName":"localhost","status":"UP",` +
+	`"properties":{"engineID":"default","engineName":"default"},"healthCheck":{"mode":"push","interval":30,"times":3},` +
+	`"timestamp":"1530252962","dataCenterInfo":{"name":"dc","region":"region","availableZone":"az"},` +
+	`"modTimestamp":"1530252962","version":"1.0.0"}`)
+
+func BenchmarkInstanceParser_Unmarshal(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := InstanceParser.Unmarshal(benchInstance); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkInstanceParser_StdUnmarshal(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := json.Unmarshal(benchInstance, new(MicroServiceInstance)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proto
+
+import (
+	"strconv"
+	"strings"
+)
+
+// PeerEvent is the instance event forwarded between the replicas of
+// service center, Subscribers are the consumers computed by the replica
+// which observed the event first
+type PeerEvent struct {
+	DomainProject string                `protobuf:"bytes,1,opt,name=domainProject" json:"domainProject"`
+	Revision      int64                 `protobuf:"varint,2,opt,name=revision" json:"revision"`
+	Action        string                `protobuf:"bytes,3,opt,name=action" json:"action"`
+	Key           *MicroServiceKey      `protobuf:"bytes,4,opt,name=key" json:"key,omitempty"`
+	Instance      *MicroServiceInstance `protobuf:"bytes,5,opt,name=instance" json:"instance,omitempty"`
+	Subscribers   []string              `protobuf:"bytes,6,rep,name=subscribers" json:"subscribers,omitempty"`
+	// Origin and Seq are the replica forwarding the event and the sequence
+	// of the events it forwards, the receivers find the missing events by
+	// the gaps of the sequences
+	Origin string `protobuf:"bytes,7,opt,name=origin" json:"origin,omitempty"`
+	Seq    int64  `protobuf:"varint,8,opt,name=seq" json:"seq,omitempty"`
+}
+
+// Id returns the identity of the event, the same event observed by
+// different replicas has the same id
+func (m *PeerEvent) Id() string {
+	s := []string{strconv.FormatInt(m.Revision, 10), m.DomainProject, m.Action}
+	if m.Instance != nil {
+		s = append(s, m.Instance.ServiceId, m.Instance.InstanceId)
+	} else if m.Key != nil {
+		s = append(s, m.Key.AppId, m.Key.ServiceName, m.Key.Version)
+	}
+	if m.Action == string(EVT_EXPIRE) {
+		// the expire events of the same revision are published for
+		// different consumers
+		s = append(s, m.Subscribers...)
+	}
+	return strings.Join(s, "/")
+}
+
+type PeerEventsRequest struct {
+	Events []*PeerEvent `protobuf:"bytes,1,rep,name=events" json:"events,omitempty"`
+}
+
+type PeerEventsResponse struct {
+	Response *Response `protobuf:"bytes,1,opt,name=response" json:"response,omitempty"`
+}
@@ -0,0 +1,32 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package proto
+
+// RegisterServiceAndInstanceRequest registers the service if it is absent
+// and its instance in one transaction
+type RegisterServiceAndInstanceRequest struct {
+	Service  *MicroService         `protobuf:"bytes,1,opt,name=service" json:"service,omitempty"`
+	Instance *MicroServiceInstance `protobuf:"bytes,2,opt,name=instance" json:"instance,omitempty"`
+}
+
+type RegisterServiceAndInstanceResponse struct {
+	Response   *Response `protobuf:"bytes,1,opt,name=response" json:"response,omitempty"`
+	ServiceId  string    `protobuf:"bytes,2,opt,name=serviceId" json:"serviceId,omitempty"`
+	InstanceId string    `protobuf:"bytes,3,opt,name=instanceId" json:"instanceId,omitempty"`
+	// ConflictPolicy is the policy applied if the instance exists
+	ConflictPolicy string `protobuf:"bytes,4,opt,name=conflictPolicy" json:"conflictPolicy,omitempty"`
+}
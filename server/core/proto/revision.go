@@ -0,0 +1,47 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package proto
+
+// Revisioned is the resource stamped with the backend revisions when it is
+// read, the revisions order the modifications of the resources instead of
+// the wall-clock timestamps which may skew between the service centers
+type Revisioned interface {
+	SetRevision(createRevision, modRevision int64)
+}
+
+func (m *MicroService) SetRevision(createRevision, modRevision int64) {
+	m.CreateRevision, m.ModRevision = createRevision, modRevision
+}
+
+func (m *MicroServiceInstance) SetRevision(createRevision, modRevision int64) {
+	m.CreateRevision, m.ModRevision = createRevision, modRevision
+}
+
+// WithoutRevision returns a copy of the service to store, the revisions
+// are stamped when read and never stored with the resources
+func (m *MicroService) WithoutRevision() *MicroService {
+	c := *m
+	c.CreateRevision, c.ModRevision = 0, 0
+	return &c
+}
+
+// WithoutRevision returns a copy of the instance to store
+func (m *MicroServiceInstance) WithoutRevision() *MicroServiceInstance {
+	c := *m
+	c.CreateRevision, c.ModRevision = 0, 0
+	return &c
+}
@@ -0,0 +1,45 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package proto
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestWithoutRevision(t *testing.T) {
+	service := &MicroService{ServiceId: "a"}
+	service.SetRevision(1, 2)
+	data, err := json.Marshal(service.WithoutRevision())
+	if err != nil || strings.Contains(string(data), "Revision") {
+		t.Fatalf("TestWithoutRevision failed, %s, %v", data, err)
+	}
+	if service.CreateRevision != 1 || service.ModRevision != 2 {
+		t.Fatalf("TestWithoutRevision failed, the service is changed, %v", service)
+	}
+
+	instance := &MicroServiceInstance{InstanceId: "b"}
+	instance.SetRevision(3, 4)
+	data, err = json.Marshal(instance.WithoutRevision())
+	if err != nil || strings.Contains(string(data), "Revision") {
+		t.Fatalf("TestWithoutRevision failed, %s, %v", data, err)
+	}
+	if instance.CreateRevision != 3 || instance.ModRevision != 4 {
+		t.Fatalf("TestWithoutRevision failed, the instance is changed, %v", instance)
+	}
+}
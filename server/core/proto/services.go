@@ -19,15 +19,25 @@ package proto
 import (
 	"github.com/gorilla/websocket"
 	"golang.org/x/net/context"
+	"net/http"
 )
 
 type ServiceInstanceCtrlServerEx interface {
 	ServiceInstanceCtrlServer
+	ServiceInstanceStreamCtrlServer
 
 	BatchFind(ctx context.Context, in *BatchFindInstancesRequest) (*BatchFindInstancesResponse, error)
+	RegisterServiceAndInstance(ctx context.Context, in *RegisterServiceAndInstanceRequest) (*RegisterServiceAndInstanceResponse, error)
 
 	WebSocketWatch(ctx context.Context, in *WatchInstanceRequest, conn *websocket.Conn)
 	WebSocketListAndWatch(ctx context.Context, in *WatchInstanceRequest, conn *websocket.Conn)
+	WebSocketResourceWatch(ctx context.Context, in *WatchResourceRequest, conn *websocket.Conn)
+	GetWatchEvents(ctx context.Context, in *GetWatchEventsRequest) (*GetWatchEventsResponse, error)
+	// the Server-Sent Events watch returns an error if the stream is not established
+	SSEWatch(ctx context.Context, in *WatchInstanceRequest, w http.ResponseWriter) error
+	SSEListAndWatch(ctx context.Context, in *WatchInstanceRequest, w http.ResponseWriter) error
+	// the newline delimited JSON stream returns an error if no line is written
+	NDJSONGetInstances(ctx context.Context, in *GetInstancesRequest, w http.ResponseWriter) error
 
 	ClusterHealth(ctx context.Context) (*GetInstancesResponse, error)
 }
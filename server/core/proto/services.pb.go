@@ -560,6 +560,11 @@ type MicroService struct {
 	Environment  string             `protobuf:"bytes,16,opt,name=environment" json:"environment,omitempty"`
 	RegisterBy   string             `protobuf:"bytes,17,opt,name=registerBy" json:"registerBy,omitempty"`
 	Framework    *FrameWorkProperty `protobuf:"bytes,18,opt,name=framework" json:"framework,omitempty"`
+	// CreateRevision and ModRevision are stamped from the backend revisions
+	// when the service is read, they order the modifications of the services
+	// while Timestamp and ModTimestamp are informational only
+	CreateRevision int64 `protobuf:"varint,19,opt,name=createRevision" json:"createRevision,omitempty"`
+	ModRevision    int64 `protobuf:"varint,20,opt,name=modRevision" json:"modRevision,omitempty"`
 }
 
 func (m *MicroService) Reset()                    { *m = MicroService{} }
@@ -693,6 +698,20 @@ func (m *MicroService) GetFramework() *FrameWorkProperty {
 	return nil
 }
 
+func (m *MicroService) GetCreateRevision() int64 {
+	if m != nil {
+		return m.CreateRevision
+	}
+	return 0
+}
+
+func (m *MicroService) GetModRevision() int64 {
+	if m != nil {
+		return m.ModRevision
+	}
+	return 0
+}
+
 type FrameWorkProperty struct {
 	Name    string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
 	Version string `protobuf:"bytes,2,opt,name=version" json:"version,omitempty"`
@@ -1593,6 +1612,11 @@ type MicroServiceInstance struct {
 	DataCenterInfo *DataCenterInfo   `protobuf:"bytes,9,opt,name=dataCenterInfo" json:"dataCenterInfo,omitempty"`
 	ModTimestamp   string            `protobuf:"bytes,10,opt,name=modTimestamp" json:"modTimestamp,omitempty"`
 	Version        string            `protobuf:"bytes,11,opt,name=version" json:"version,omitempty"`
+	// CreateRevision and ModRevision are stamped from the backend revisions
+	// when the instance is read, they order the modifications of the instances
+	// while Timestamp and ModTimestamp are informational only
+	CreateRevision int64 `protobuf:"varint,12,opt,name=createRevision" json:"createRevision,omitempty"`
+	ModRevision    int64 `protobuf:"varint,13,opt,name=modRevision" json:"modRevision,omitempty"`
 }
 
 func (m *MicroServiceInstance) Reset()                    { *m = MicroServiceInstance{} }
@@ -1677,6 +1701,20 @@ func (m *MicroServiceInstance) GetVersion() string {
 	return ""
 }
 
+func (m *MicroServiceInstance) GetCreateRevision() int64 {
+	if m != nil {
+		return m.CreateRevision
+	}
+	return 0
+}
+
+func (m *MicroServiceInstance) GetModRevision() int64 {
+	if m != nil {
+		return m.ModRevision
+	}
+	return 0
+}
+
 type DataCenterInfo struct {
 	Name          string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
 	Region        string `protobuf:"bytes,2,opt,name=region" json:"region,omitempty"`
@@ -1750,8 +1788,9 @@ func (m *RegisterInstanceRequest) GetInstance() *MicroServiceInstance {
 }
 
 type RegisterInstanceResponse struct {
-	Response   *Response `protobuf:"bytes,1,opt,name=response" json:"response,omitempty"`
-	InstanceId string    `protobuf:"bytes,2,opt,name=instanceId" json:"instanceId,omitempty"`
+	Response       *Response `protobuf:"bytes,1,opt,name=response" json:"response,omitempty"`
+	InstanceId     string    `protobuf:"bytes,2,opt,name=instanceId" json:"instanceId,omitempty"`
+	ConflictPolicy string    `protobuf:"bytes,3,opt,name=conflictPolicy" json:"conflictPolicy,omitempty"`
 }
 
 func (m *RegisterInstanceResponse) Reset()                    { *m = RegisterInstanceResponse{} }
@@ -1773,6 +1812,13 @@ func (m *RegisterInstanceResponse) GetInstanceId() string {
 	return ""
 }
 
+func (m *RegisterInstanceResponse) GetConflictPolicy() string {
+	if m != nil {
+		return m.ConflictPolicy
+	}
+	return ""
+}
+
 type UnregisterInstanceRequest struct {
 	ServiceId  string `protobuf:"bytes,1,opt,name=serviceId" json:"serviceId,omitempty"`
 	InstanceId string `protobuf:"bytes,2,opt,name=instanceId" json:"instanceId,omitempty"`
@@ -1860,6 +1906,7 @@ type FindInstancesRequest struct {
 	VersionRule       string   `protobuf:"bytes,4,opt,name=versionRule" json:"versionRule,omitempty"`
 	Tags              []string `protobuf:"bytes,5,rep,name=tags" json:"tags,omitempty"`
 	Environment       string   `protobuf:"bytes,6,opt,name=environment" json:"environment,omitempty"`
+	Rev               string   `protobuf:"bytes,7,opt,name=rev" json:"rev,omitempty"`
 }
 
 func (m *FindInstancesRequest) Reset()                    { *m = FindInstancesRequest{} }
@@ -1909,9 +1956,17 @@ func (m *FindInstancesRequest) GetEnvironment() string {
 	return ""
 }
 
+func (m *FindInstancesRequest) GetRev() string {
+	if m != nil {
+		return m.Rev
+	}
+	return ""
+}
+
 type FindInstancesResponse struct {
 	Response  *Response               `protobuf:"bytes,1,opt,name=response" json:"response,omitempty"`
 	Instances []*MicroServiceInstance `protobuf:"bytes,2,rep,name=instances" json:"instances,omitempty"`
+	Rev       string                  `protobuf:"bytes,3,opt,name=rev" json:"rev,omitempty"`
 }
 
 func (m *FindInstancesResponse) Reset()                    { *m = FindInstancesResponse{} }
@@ -1933,6 +1988,13 @@ func (m *FindInstancesResponse) GetInstances() []*MicroServiceInstance {
 	return nil
 }
 
+func (m *FindInstancesResponse) GetRev() string {
+	if m != nil {
+		return m.Rev
+	}
+	return ""
+}
+
 type GetOneInstanceRequest struct {
 	ConsumerServiceId  string   `protobuf:"bytes,1,opt,name=consumerServiceId" json:"consumerServiceId,omitempty"`
 	ProviderServiceId  string   `protobuf:"bytes,2,opt,name=providerServiceId" json:"providerServiceId,omitempty"`
@@ -1976,6 +2038,7 @@ func (m *GetOneInstanceRequest) GetTags() []string {
 type GetOneInstanceResponse struct {
 	Response *Response             `protobuf:"bytes,1,opt,name=response" json:"response,omitempty"`
 	Instance *MicroServiceInstance `protobuf:"bytes,2,opt,name=instance" json:"instance,omitempty"`
+	Rev      string                `protobuf:"bytes,3,opt,name=rev" json:"rev,omitempty"`
 }
 
 func (m *GetOneInstanceResponse) Reset()                    { *m = GetOneInstanceResponse{} }
@@ -1997,6 +2060,13 @@ func (m *GetOneInstanceResponse) GetInstance() *MicroServiceInstance {
 	return nil
 }
 
+func (m *GetOneInstanceResponse) GetRev() string {
+	if m != nil {
+		return m.Rev
+	}
+	return ""
+}
+
 type GetInstancesRequest struct {
 	ConsumerServiceId string   `protobuf:"bytes,1,opt,name=consumerServiceId" json:"consumerServiceId,omitempty"`
 	ProviderServiceId string   `protobuf:"bytes,2,opt,name=providerServiceId" json:"providerServiceId,omitempty"`
@@ -2057,6 +2127,7 @@ type UpdateInstanceStatusRequest struct {
 	ServiceId  string `protobuf:"bytes,1,opt,name=serviceId" json:"serviceId,omitempty"`
 	InstanceId string `protobuf:"bytes,2,opt,name=instanceId" json:"instanceId,omitempty"`
 	Status     string `protobuf:"bytes,3,opt,name=status" json:"status,omitempty"`
+	Rev        string `protobuf:"bytes,4,opt,name=rev" json:"rev,omitempty"`
 }
 
 func (m *UpdateInstanceStatusRequest) Reset()                    { *m = UpdateInstanceStatusRequest{} }
@@ -2085,8 +2156,16 @@ func (m *UpdateInstanceStatusRequest) GetStatus() string {
 	return ""
 }
 
+func (m *UpdateInstanceStatusRequest) GetRev() string {
+	if m != nil {
+		return m.Rev
+	}
+	return ""
+}
+
 type UpdateInstanceStatusResponse struct {
 	Response *Response `protobuf:"bytes,1,opt,name=response" json:"response,omitempty"`
+	Rev      string    `protobuf:"bytes,2,opt,name=rev" json:"rev,omitempty"`
 }
 
 func (m *UpdateInstanceStatusResponse) Reset()                    { *m = UpdateInstanceStatusResponse{} }
@@ -2101,10 +2180,18 @@ func (m *UpdateInstanceStatusResponse) GetResponse() *Response {
 	return nil
 }
 
+func (m *UpdateInstanceStatusResponse) GetRev() string {
+	if m != nil {
+		return m.Rev
+	}
+	return ""
+}
+
 type UpdateInstancePropsRequest struct {
 	ServiceId  string            `protobuf:"bytes,1,opt,name=serviceId" json:"serviceId,omitempty"`
 	InstanceId string            `protobuf:"bytes,2,opt,name=instanceId" json:"instanceId,omitempty"`
 	Properties map[string]string `protobuf:"bytes,3,rep,name=properties" json:"properties,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Rev        string            `protobuf:"bytes,4,opt,name=rev" json:"rev,omitempty"`
 }
 
 func (m *UpdateInstancePropsRequest) Reset()                    { *m = UpdateInstancePropsRequest{} }
@@ -2133,8 +2220,16 @@ func (m *UpdateInstancePropsRequest) GetProperties() map[string]string {
 	return nil
 }
 
+func (m *UpdateInstancePropsRequest) GetRev() string {
+	if m != nil {
+		return m.Rev
+	}
+	return ""
+}
+
 type UpdateInstancePropsResponse struct {
 	Response *Response `protobuf:"bytes,1,opt,name=response" json:"response,omitempty"`
+	Rev      string    `protobuf:"bytes,2,opt,name=rev" json:"rev,omitempty"`
 }
 
 func (m *UpdateInstancePropsResponse) Reset()                    { *m = UpdateInstancePropsResponse{} }
@@ -2149,6 +2244,13 @@ func (m *UpdateInstancePropsResponse) GetResponse() *Response {
 	return nil
 }
 
+func (m *UpdateInstancePropsResponse) GetRev() string {
+	if m != nil {
+		return m.Rev
+	}
+	return ""
+}
+
 type WatchInstanceRequest struct {
 	SelfServiceId string `protobuf:"bytes,1,opt,name=selfServiceId" json:"selfServiceId,omitempty"`
 }
@@ -4118,6 +4220,97 @@ var _ServiceInstanceCtrl_serviceDesc = grpc.ServiceDesc{
 	Metadata: "services.proto",
 }
 
+// Client API for ServiceInstanceStreamCtrl service
+
+type ServiceInstanceStreamCtrlClient interface {
+	GetInstancesStream(ctx context.Context, in *GetInstancesRequest, opts ...grpc.CallOption) (ServiceInstanceStreamCtrl_GetInstancesStreamClient, error)
+}
+
+type serviceInstanceStreamCtrlClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewServiceInstanceStreamCtrlClient(cc *grpc.ClientConn) ServiceInstanceStreamCtrlClient {
+	return &serviceInstanceStreamCtrlClient{cc}
+}
+
+func (c *serviceInstanceStreamCtrlClient) GetInstancesStream(ctx context.Context, in *GetInstancesRequest, opts ...grpc.CallOption) (ServiceInstanceStreamCtrl_GetInstancesStreamClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_ServiceInstanceStreamCtrl_serviceDesc.Streams[0], c.cc, "/proto.ServiceInstanceStreamCtrl/getInstancesStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &serviceInstanceStreamCtrlGetInstancesStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ServiceInstanceStreamCtrl_GetInstancesStreamClient interface {
+	Recv() (*GetInstancesResponse, error)
+	grpc.ClientStream
+}
+
+type serviceInstanceStreamCtrlGetInstancesStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *serviceInstanceStreamCtrlGetInstancesStreamClient) Recv() (*GetInstancesResponse, error) {
+	m := new(GetInstancesResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Server API for ServiceInstanceStreamCtrl service
+
+type ServiceInstanceStreamCtrlServer interface {
+	GetInstancesStream(*GetInstancesRequest, ServiceInstanceStreamCtrl_GetInstancesStreamServer) error
+}
+
+func RegisterServiceInstanceStreamCtrlServer(s *grpc.Server, srv ServiceInstanceStreamCtrlServer) {
+	s.RegisterService(&_ServiceInstanceStreamCtrl_serviceDesc, srv)
+}
+
+func _ServiceInstanceStreamCtrl_GetInstancesStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetInstancesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ServiceInstanceStreamCtrlServer).GetInstancesStream(m, &serviceInstanceStreamCtrlGetInstancesStreamServer{stream})
+}
+
+type ServiceInstanceStreamCtrl_GetInstancesStreamServer interface {
+	Send(*GetInstancesResponse) error
+	grpc.ServerStream
+}
+
+type serviceInstanceStreamCtrlGetInstancesStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *serviceInstanceStreamCtrlGetInstancesStreamServer) Send(m *GetInstancesResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _ServiceInstanceStreamCtrl_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "proto.ServiceInstanceStreamCtrl",
+	HandlerType: (*ServiceInstanceStreamCtrlServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "getInstancesStream",
+			Handler:       _ServiceInstanceStreamCtrl_GetInstancesStream_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "services.proto",
+}
+
 // Client API for GovernServiceCtrl service
 
 type GovernServiceCtrlClient interface {
@@ -4251,212 +4444,219 @@ var _GovernServiceCtrl_serviceDesc = grpc.ServiceDesc{
 func init() { proto1.RegisterFile("services.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 3309 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x3b, 0x4b, 0x6f, 0xdc, 0xc8,
-	0xd1, 0xe0, 0x68, 0x46, 0x33, 0x53, 0x7a, 0x58, 0x6a, 0x4b, 0x16, 0x4d, 0xbf, 0xe4, 0xde, 0xfd,
-	0xbe, 0x35, 0xb0, 0xfb, 0x69, 0xd7, 0xda, 0xf7, 0xc3, 0x1f, 0x56, 0x96, 0x6c, 0x59, 0x96, 0x1d,
-	0x7b, 0x39, 0xde, 0x35, 0xb2, 0x41, 0x90, 0xa5, 0x66, 0x5a, 0x23, 0xc6, 0x1c, 0x92, 0x21, 0x39,
-	0xf2, 0x0e, 0x10, 0x6c, 0x10, 0x2c, 0x02, 0xe4, 0x92, 0x1c, 0x72, 0x09, 0x90, 0xe4, 0x94, 0x5b,
-	0xf2, 0x07, 0x72, 0x08, 0x82, 0xdc, 0x03, 0xe4, 0x16, 0x20, 0xa7, 0xfd, 0x07, 0x39, 0xe7, 0x1c,
-	0xf4, 0x83, 0x64, 0x37, 0xd9, 0x33, 0x1a, 0x51, 0xde, 0x9c, 0x86, 0xdd, 0x5d, 0x5d, 0x5d, 0x5d,
-	0x5d, 0x55, 0x5d, 0x8f, 0x1e, 0x58, 0x8c, 0x49, 0x74, 0xec, 0x76, 0x49, 0xbc, 0x11, 0x46, 0x41,
-	0x12, 0xa0, 0x06, 0xfb, 0xc1, 0xdf, 0x87, 0x95, 0x87, 0x41, 0xcf, 0x3d, 0x1c, 0x75, 0xba, 0x47,
-	0x64, 0xe0, 0xc4, 0x36, 0xf9, 0xd1, 0x90, 0xc4, 0x09, 0xba, 0x0c, 0x6d, 0x31, 0x61, 0xaf, 0x67,
-	0x1a, 0xeb, 0xc6, 0x8d, 0xb6, 0x9d, 0x77, 0xa0, 0x57, 0xa0, 0x19, 0x73, 0x78, 0xb3, 0xb6, 0x3e,
-	0x73, 0x63, 0x6e, 0x73, 0x81, 0x63, 0xdd, 0xe0, 0x58, 0xec, 0x74, 0x14, 0x7f, 0x06, 0xb3, 0xbc,
-	0x0b, 0x59, 0xd0, 0xe2, 0x9d, 0x19, 0xbe, 0xac, 0x8d, 0x4c, 0x68, 0xc6, 0xc3, 0xc1, 0xc0, 0x89,
-	0x46, 0x66, 0x8d, 0x0d, 0xa5, 0x4d, 0x74, 0x01, 0x66, 0x39, 0x94, 0x39, 0xc3, 0x06, 0x44, 0x0b,
-	0xef, 0xc0, 0x6a, 0x81, 0xec, 0x38, 0x0c, 0xfc, 0x98, 0xa0, 0x57, 0xa1, 0x15, 0x89, 0x6f, 0xb6,
-	0xcc, 0xdc, 0xe6, 0x39, 0x41, 0x5a, 0x0a, 0x62, 0x67, 0x00, 0xf8, 0x11, 0x9c, 0xbf, 0x47, 0x9c,
-	0x28, 0x39, 0x20, 0x4e, 0xd2, 0x21, 0x49, 0xba, 0xf7, 0xf7, 0xa0, 0xed, 0xfa, 0x71, 0xe2, 0xf8,
-	0x5d, 0x12, 0x9b, 0x06, 0xdb, 0x9f, 0x25, 0x90, 0xc8, 0xe0, 0x77, 0x3c, 0x32, 0x20, 0x7e, 0x62,
-	0xe7, 0xc0, 0xb8, 0xa3, 0x22, 0x14, 0x10, 0x27, 0x30, 0xf3, 0x2a, 0x40, 0x8a, 0x61, 0xaf, 0x27,
-	0x18, 0x20, 0xf5, 0xe0, 0xe7, 0xb0, 0xa2, 0x52, 0x59, 0x61, 0xab, 0x68, 0x53, 0xde, 0x13, 0x3f,
-	0xb3, 0x15, 0x01, 0xbd, 0x27, 0xfa, 0xef, 0x1d, 0xd8, 0xb1, 0xb2, 0x9b, 0x01, 0x2c, 0x28, 0x63,
-	0x67, 0xdb, 0x07, 0x1d, 0x27, 0x51, 0xf4, 0x90, 0xc4, 0xb1, 0xd3, 0x27, 0xe2, 0x3c, 0xa5, 0x1e,
-	0xbc, 0x0d, 0xed, 0x4e, 0xd2, 0xe1, 0xe8, 0xd0, 0x0a, 0x34, 0xba, 0xc1, 0xd0, 0x4f, 0xd8, 0x32,
-	0x33, 0x36, 0x6f, 0xa0, 0x75, 0x98, 0x0b, 0x7c, 0xcf, 0xf5, 0xc9, 0x36, 0x1b, 0xab, 0xb1, 0x31,
-	0xb9, 0x0b, 0xdf, 0x03, 0xe8, 0x24, 0x29, 0xd5, 0x63, 0xb0, 0xbc, 0x0c, 0x0b, 0xec, 0xe3, 0xf6,
-	0x68, 0x27, 0x18, 0x38, 0xae, 0x2f, 0xf0, 0xa8, 0x9d, 0xf8, 0x0a, 0x34, 0x3a, 0xc9, 0x56, 0x18,
-	0xea, 0x91, 0xe0, 0x5f, 0x18, 0x74, 0x25, 0x27, 0x71, 0xe3, 0xc4, 0xed, 0xc6, 0xe8, 0x35, 0x68,
-	0xa5, 0x0a, 0x26, 0x0e, 0x63, 0x29, 0x55, 0x89, 0x74, 0x4f, 0x76, 0x06, 0x81, 0x5e, 0x57, 0x4f,
-	0x83, 0x82, 0x2f, 0x67, 0xe0, 0x29, 0xf5, 0xd2, 0x51, 0xa0, 0x75, 0xa8, 0x3b, 0x61, 0x18, 0x33,
-	0xae, 0xcd, 0x6d, 0xce, 0x67, 0xb0, 0x5b, 0x61, 0x68, 0xb3, 0x11, 0xfc, 0x73, 0x03, 0x2e, 0xec,
-	0x92, 0x74, 0xad, 0x78, 0xcf, 0x3f, 0x0c, 0x52, 0x79, 0x36, 0xa1, 0x19, 0x84, 0x89, 0x1b, 0xf8,
-	0x5c, 0x9a, 0xdb, 0x76, 0xda, 0xa4, 0x5b, 0x73, 0xc2, 0x30, 0x3b, 0x2d, 0xde, 0xa0, 0x5c, 0x16,
-	0x94, 0x7e, 0xc7, 0x19, 0xa4, 0x27, 0x25, 0x77, 0x51, 0x41, 0x60, 0x5c, 0x78, 0xe4, 0x7b, 0x23,
-	0xb3, 0xbe, 0x6e, 0xdc, 0x68, 0xd9, 0x79, 0x07, 0xfe, 0x8b, 0x01, 0x6b, 0x25, 0x52, 0xaa, 0x08,
-	0xed, 0x6d, 0x58, 0x76, 0x3c, 0x2f, 0xc5, 0xb3, 0x43, 0x12, 0xc7, 0xf5, 0x0a, 0xc2, 0x2b, 0x06,
-	0xf9, 0x98, 0x5d, 0x06, 0x47, 0x37, 0x01, 0xe2, 0xec, 0x98, 0x04, 0xff, 0x72, 0x5e, 0xa7, 0x03,
-	0xb6, 0x04, 0x84, 0xff, 0x6e, 0xc0, 0xb9, 0x87, 0x6e, 0x37, 0x0a, 0x04, 0xaa, 0x7d, 0xc2, 0x0c,
-	0x51, 0x42, 0x7c, 0x47, 0x48, 0x41, 0xdb, 0x16, 0x2d, 0xca, 0xdb, 0x30, 0x0a, 0x7e, 0x48, 0xba,
-	0x49, 0x6a, 0xba, 0x44, 0x33, 0xe7, 0xed, 0xcc, 0x04, 0xde, 0xd6, 0xcb, 0xbc, 0x35, 0xa1, 0x79,
-	0x4c, 0xa2, 0xd8, 0x0d, 0x7c, 0xb3, 0xc1, 0x31, 0x8a, 0x26, 0x9d, 0x4b, 0xfc, 0x63, 0x37, 0x0a,
-	0x7c, 0x6a, 0x55, 0xcc, 0x59, 0x3e, 0x57, 0xea, 0x62, 0x6b, 0x7a, 0xae, 0x13, 0x9b, 0x4d, 0xb1,
-	0x26, 0x6d, 0xe0, 0xdf, 0xcf, 0xc2, 0xbc, 0xbc, 0x9f, 0x13, 0xf4, 0xb8, 0xaa, 0x50, 0x48, 0x84,
-	0xd7, 0x4b, 0x84, 0xf7, 0x48, 0xdc, 0x8d, 0x5c, 0x26, 0x76, 0x62, 0x5b, 0x72, 0x17, 0x5d, 0xd3,
-	0x23, 0xc7, 0xc4, 0x13, 0x9b, 0xe2, 0x0d, 0x76, 0x2f, 0x88, 0x6b, 0xa6, 0xc9, 0x05, 0x57, 0x34,
-	0xd1, 0x0d, 0x68, 0x84, 0x4e, 0x72, 0x14, 0x9b, 0xc0, 0xa4, 0x01, 0xa9, 0xd2, 0xf0, 0xd8, 0x49,
-	0x8e, 0x6c, 0x0e, 0xc0, 0x6e, 0x90, 0xc4, 0x49, 0x86, 0xb1, 0xd9, 0x12, 0x37, 0x08, 0x6b, 0xa1,
-	0x6d, 0x80, 0x30, 0x0a, 0x42, 0x12, 0x25, 0x2e, 0x89, 0xcd, 0x36, 0x43, 0xf3, 0x92, 0x40, 0x23,
-	0x33, 0x6b, 0xe3, 0x71, 0x06, 0x75, 0xc7, 0x4f, 0xa2, 0x91, 0x2d, 0x4d, 0xa3, 0x8c, 0x4c, 0xdc,
-	0x01, 0x89, 0x13, 0x67, 0x10, 0x9a, 0x73, 0x9c, 0x91, 0x59, 0x07, 0x7a, 0x0b, 0xda, 0x61, 0x14,
-	0x1c, 0xbb, 0x3d, 0x12, 0xc5, 0xe6, 0x3c, 0x5b, 0xe1, 0x82, 0x66, 0x85, 0x7d, 0x32, 0xb2, 0x73,
-	0xc0, 0xfc, 0x0c, 0x17, 0xa4, 0x33, 0xa4, 0xe4, 0x3e, 0xb8, 0xdd, 0x49, 0x22, 0x27, 0x21, 0xfd,
-	0x91, 0xb9, 0x38, 0x9e, 0xdc, 0x1c, 0x4a, 0x90, 0x9b, 0x77, 0x20, 0x0c, 0xf3, 0x83, 0xa0, 0xf7,
-	0x24, 0xa3, 0xf8, 0x1c, 0x5b, 0x41, 0xe9, 0x2b, 0x0a, 0xd9, 0x52, 0x59, 0xc8, 0xae, 0x02, 0x44,
-	0xa4, 0xef, 0xc6, 0x09, 0x89, 0x6e, 0x8f, 0xcc, 0x65, 0x6e, 0xc7, 0xf3, 0x1e, 0xf4, 0x0e, 0xb4,
-	0x0f, 0x23, 0x67, 0x40, 0x9e, 0x07, 0xd1, 0x33, 0x13, 0x31, 0x85, 0x33, 0x05, 0xa5, 0x77, 0x69,
-	0xff, 0xd3, 0x20, 0x7a, 0x26, 0x98, 0x3a, 0xb2, 0x73, 0x50, 0xeb, 0x16, 0x9c, 0x2b, 0xf0, 0x1a,
-	0x2d, 0xc1, 0xcc, 0x33, 0x32, 0x12, 0x22, 0x4a, 0x3f, 0x29, 0x77, 0x8e, 0x1d, 0x6f, 0x48, 0x52,
-	0xe1, 0x64, 0x8d, 0x0f, 0x6a, 0xef, 0x19, 0x74, 0x7a, 0x61, 0xef, 0xa7, 0x99, 0x8e, 0xb7, 0x60,
-	0xb9, 0x44, 0x1d, 0x42, 0x50, 0xf7, 0xa9, 0xb4, 0x73, 0x0c, 0xec, 0x5b, 0x16, 0xf3, 0x9a, 0x22,
-	0xe6, 0xf8, 0x1b, 0x03, 0xe6, 0x52, 0x5b, 0x3f, 0xf4, 0x08, 0x15, 0xbd, 0x68, 0xe8, 0xe5, 0x3a,
-	0x26, 0x5a, 0xd4, 0x15, 0xa2, 0x5f, 0x4f, 0x46, 0x61, 0x4a, 0x47, 0xd6, 0xa6, 0x12, 0xe5, 0x24,
-	0x49, 0xe4, 0x1e, 0x0c, 0x93, 0x54, 0xc9, 0xf2, 0x0e, 0x66, 0x6d, 0x9c, 0x24, 0x21, 0x51, 0xa6,
-	0x62, 0xa2, 0x39, 0x85, 0x8a, 0x29, 0xb2, 0x3a, 0x5b, 0x94, 0xd5, 0xa2, 0x68, 0x34, 0xcb, 0xa2,
-	0x81, 0x7f, 0x69, 0xc0, 0x85, 0xad, 0x5e, 0xef, 0x51, 0xf4, 0x69, 0xd8, 0x73, 0x12, 0x22, 0x6f,
-	0x55, 0xde, 0x92, 0x31, 0x69, 0x4b, 0xb5, 0x09, 0x5b, 0x9a, 0x99, 0xb8, 0xa5, 0x7a, 0x69, 0x4b,
-	0xf8, 0x77, 0x39, 0xc3, 0xa9, 0xca, 0xd3, 0xe3, 0xa2, 0x4a, 0x9f, 0x1e, 0x17, 0xfd, 0x46, 0x1f,
-	0x41, 0x4b, 0x28, 0xec, 0x48, 0x5c, 0x1d, 0xeb, 0x65, 0x63, 0x91, 0x2a, 0xb9, 0xd0, 0x99, 0x6c,
-	0x86, 0xf5, 0x21, 0x2c, 0x28, 0x43, 0xa7, 0x12, 0xa9, 0xf7, 0xa0, 0x95, 0xdd, 0x7b, 0x08, 0xea,
-	0xdd, 0xa0, 0xc7, 0x99, 0xd3, 0xb0, 0xd9, 0x37, 0xdd, 0xfa, 0x40, 0x78, 0x43, 0x42, 0x92, 0x44,
-	0x13, 0xff, 0xd3, 0x80, 0xf3, 0xbb, 0x24, 0xb9, 0xf3, 0x25, 0xd5, 0x29, 0xea, 0x0a, 0x88, 0x9b,
-	0x1c, 0x41, 0x3d, 0xc9, 0x59, 0xcc, 0xbe, 0xbf, 0x05, 0x73, 0xad, 0x5c, 0x0f, 0x8d, 0xe2, 0xf5,
-	0x20, 0x3b, 0xf2, 0xb3, 0x05, 0x47, 0xbe, 0x60, 0x3c, 0x9a, 0x25, 0xe3, 0x81, 0x7f, 0x6d, 0xc0,
-	0x8a, 0xba, 0xb3, 0x2a, 0x8e, 0x81, 0x42, 0x61, 0x6d, 0x12, 0x85, 0x33, 0xe3, 0x43, 0x8d, 0xba,
-	0x12, 0x6a, 0xe0, 0x3f, 0xd4, 0x60, 0x65, 0x3b, 0x22, 0x92, 0x60, 0x0b, 0xa6, 0xff, 0x1f, 0x34,
-	0x05, 0x6e, 0x41, 0xd8, 0x79, 0x8d, 0xdd, 0xb5, 0x53, 0x18, 0xf4, 0x26, 0x34, 0xa8, 0xe8, 0xa7,
-	0x5e, 0xf6, 0x15, 0x01, 0xac, 0x57, 0x1c, 0x9b, 0xc3, 0xa2, 0xf7, 0xa1, 0x9e, 0x38, 0x7d, 0xea,
-	0x9f, 0xd0, 0x39, 0xff, 0x23, 0xe6, 0xe8, 0xc8, 0xd9, 0x78, 0xe2, 0xf4, 0xc5, 0x4d, 0xc4, 0xa6,
-	0xa0, 0xf7, 0x65, 0x5f, 0xb2, 0xce, 0xe6, 0x5f, 0xd2, 0x10, 0xa8, 0xf1, 0x2a, 0xad, 0x77, 0xa1,
-	0x9d, 0x61, 0x3b, 0x95, 0x64, 0x1f, 0xc0, 0x6a, 0x81, 0xb6, 0x17, 0x7e, 0x8a, 0xf8, 0x3e, 0xac,
-	0xec, 0x10, 0x8f, 0x94, 0x8e, 0xe3, 0x44, 0xe7, 0xe5, 0x30, 0x88, 0xba, 0x9c, 0xe6, 0x96, 0xcd,
-	0x1b, 0x34, 0x5c, 0x2c, 0xe0, 0xaa, 0x12, 0x2e, 0xde, 0x84, 0xe5, 0xdc, 0xad, 0x9d, 0x8a, 0x1c,
-	0x1c, 0x02, 0x92, 0xa7, 0x54, 0xe1, 0x92, 0x24, 0x7e, 0xb5, 0x93, 0xc5, 0x0f, 0xaf, 0xc8, 0x2b,
-	0xa6, 0xe1, 0x3c, 0x8e, 0x99, 0x3d, 0xc9, 0x7b, 0xab, 0x10, 0xf2, 0xba, 0x14, 0xe2, 0x70, 0xd9,
-	0xd6, 0x52, 0x92, 0x01, 0xe1, 0xbf, 0x19, 0x70, 0x51, 0x91, 0x78, 0x6a, 0x4a, 0xa7, 0xcc, 0x30,
-	0x3c, 0x56, 0xdc, 0x33, 0xbe, 0xdc, 0x1b, 0x62, 0xb9, 0xb1, 0x38, 0x27, 0xf9, 0x6a, 0x67, 0x74,
-	0x2f, 0xf0, 0x1e, 0x58, 0xba, 0x75, 0xab, 0xc8, 0xd1, 0x3b, 0x72, 0xa4, 0x46, 0xad, 0x40, 0x3c,
-	0xad, 0x30, 0xad, 0x95, 0xe6, 0x55, 0x39, 0xc8, 0x1b, 0xaa, 0x85, 0x2a, 0x38, 0xcf, 0x92, 0x59,
-	0xc2, 0x5f, 0x1b, 0x60, 0x96, 0x6d, 0xd6, 0x54, 0x07, 0x98, 0x3b, 0x3f, 0x35, 0xc5, 0xf9, 0xb9,
-	0x09, 0x75, 0xfa, 0x25, 0x22, 0xb1, 0x13, 0xac, 0x23, 0x03, 0xc5, 0xf7, 0x0a, 0x62, 0xc4, 0x89,
-	0xa8, 0xc2, 0xf9, 0x67, 0xcc, 0x81, 0x39, 0x35, 0xe7, 0x2b, 0xd9, 0x74, 0xfc, 0x05, 0xac, 0x95,
-	0x16, 0xab, 0x72, 0x5c, 0x26, 0x34, 0x6d, 0xc6, 0x3b, 0xbe, 0x7c, 0xdb, 0x4e, 0x9b, 0xb8, 0x03,
-	0x17, 0x55, 0xb3, 0x36, 0xfd, 0x8e, 0x4c, 0x68, 0x46, 0x2a, 0x52, 0xd1, 0xa4, 0x82, 0xae, 0x43,
-	0x5a, 0x85, 0xdd, 0x6f, 0xc3, 0x6a, 0x2e, 0xb0, 0xf4, 0xa6, 0x99, 0x4e, 0xce, 0xff, 0xac, 0xa4,
-	0x32, 0xf8, 0xbc, 0x2a, 0x8c, 0xfb, 0x50, 0x5c, 0xaa, 0xfc, 0xd0, 0x5e, 0x11, 0x80, 0x7a, 0xcc,
-	0xc5, 0x6b, 0xb5, 0xfa, 0xdd, 0xf8, 0x03, 0x58, 0x53, 0x44, 0xe2, 0x89, 0xd3, 0x9f, 0xee, 0x48,
-	0xc4, 0x22, 0x35, 0xcd, 0x22, 0x33, 0xd2, 0x22, 0x78, 0xb7, 0xa0, 0x93, 0x6c, 0x81, 0x2a, 0xc7,
-	0xf3, 0x47, 0x03, 0x56, 0x73, 0x09, 0x9d, 0xfa, 0x7c, 0xd0, 0x07, 0x0a, 0x5f, 0xff, 0x37, 0x57,
-	0x86, 0x32, 0xa6, 0x17, 0xc7, 0xd6, 0x3b, 0xb2, 0xea, 0x56, 0x96, 0x09, 0xfc, 0x00, 0x4c, 0x45,
-	0xba, 0xa7, 0xdf, 0x35, 0x82, 0xfa, 0x33, 0x32, 0x4a, 0xd5, 0x85, 0x7d, 0x53, 0xcb, 0xa4, 0xc1,
-	0x56, 0x85, 0xae, 0x11, 0xcc, 0xdd, 0x23, 0x8e, 0x97, 0x1c, 0x6d, 0x1f, 0x91, 0xee, 0x33, 0xba,
-	0xd8, 0x20, 0x0d, 0x17, 0xda, 0x36, 0xfb, 0x66, 0xd1, 0x4d, 0x10, 0xf1, 0x3c, 0x53, 0xc3, 0x66,
-	0xdf, 0xd4, 0xd5, 0x75, 0xfd, 0x84, 0x44, 0xc7, 0x8e, 0xc7, 0x84, 0xa4, 0x61, 0x67, 0x6d, 0xca,
-	0x4b, 0x16, 0xdf, 0x31, 0x47, 0xb7, 0x61, 0xf3, 0x06, 0xe5, 0xf9, 0x30, 0xf2, 0x84, 0x5b, 0x4f,
-	0x3f, 0xf1, 0xd7, 0x75, 0x58, 0xd1, 0x79, 0x8a, 0x85, 0x84, 0xae, 0x51, 0x4a, 0xe8, 0x4e, 0xf6,
-	0xc2, 0x2f, 0x43, 0x9b, 0xf8, 0xbd, 0x30, 0x70, 0xfd, 0x84, 0xfb, 0xb5, 0x6d, 0x3b, 0xef, 0xa0,
-	0x84, 0x1f, 0x05, 0x71, 0x22, 0x25, 0xc1, 0xb2, 0xb6, 0x94, 0xb2, 0x69, 0x28, 0x29, 0x9b, 0x7d,
-	0xc5, 0x27, 0x98, 0x65, 0xd2, 0xf7, 0xea, 0x04, 0x57, 0x77, 0x62, 0xea, 0xe6, 0x2d, 0x98, 0x3b,
-	0xca, 0x19, 0xce, 0x42, 0x95, 0xfc, 0x2a, 0x94, 0x8e, 0xc2, 0x96, 0xc1, 0xd4, 0x20, 0xba, 0x55,
-	0x0c, 0xa2, 0x6f, 0xc1, 0x62, 0xcf, 0x49, 0x9c, 0x6d, 0x42, 0x8f, 0x60, 0xcf, 0x3f, 0x0c, 0xcc,
-	0x36, 0x43, 0xbb, 0x2a, 0xd0, 0xee, 0x28, 0x83, 0x76, 0x01, 0xb8, 0x14, 0x83, 0x83, 0x26, 0x3d,
-	0x23, 0x45, 0x6d, 0x73, 0x4a, 0xd4, 0x76, 0x56, 0xff, 0xe6, 0x00, 0x16, 0x55, 0xf2, 0xb4, 0xc9,
-	0x0f, 0x7a, 0xab, 0x93, 0x7e, 0x9e, 0xfb, 0x10, 0x2d, 0xf4, 0x32, 0x2c, 0x38, 0xc7, 0x8e, 0xeb,
-	0x39, 0x07, 0x1e, 0xf9, 0x3c, 0xf0, 0x53, 0x8b, 0xa5, 0x76, 0xe2, 0xa7, 0xb0, 0xa6, 0x3b, 0xa7,
-	0x7d, 0x32, 0x3a, 0x9b, 0xac, 0x61, 0x1b, 0xd6, 0x6c, 0x91, 0x80, 0xca, 0xe2, 0x1c, 0xa1, 0xd4,
-	0xef, 0x52, 0x0d, 0xe1, 0x5d, 0x42, 0x0b, 0x27, 0x46, 0x47, 0x19, 0x30, 0xee, 0x83, 0x59, 0xc6,
-	0x59, 0xe5, 0x1a, 0x3a, 0xa9, 0xbe, 0xf3, 0x5d, 0xb8, 0xf8, 0xa9, 0x1f, 0x8d, 0x21, 0xff, 0x6c,
-	0xa5, 0x23, 0xea, 0xb4, 0x6a, 0x50, 0x57, 0x31, 0x50, 0x8f, 0x61, 0x29, 0xab, 0x42, 0xbd, 0x18,
-	0xe2, 0x3e, 0x86, 0x65, 0x09, 0x63, 0x15, 0x9a, 0xfe, 0x61, 0xc0, 0xca, 0x5d, 0xd7, 0xef, 0xa5,
-	0x3b, 0xcb, 0x2c, 0xf9, 0x6b, 0xb0, 0xdc, 0x0d, 0xfc, 0x78, 0x38, 0x20, 0x51, 0xa7, 0x40, 0x60,
-	0x79, 0xa0, 0x72, 0x06, 0x65, 0x1d, 0xe6, 0x84, 0xf2, 0x51, 0x1f, 0x29, 0x4d, 0x50, 0x49, 0x5d,
-	0x2c, 0x5f, 0x43, 0x6f, 0xca, 0x06, 0xbf, 0x33, 0x58, 0xbc, 0x7e, 0x62, 0x16, 0x1f, 0xff, 0x04,
-	0x56, 0x0b, 0xbb, 0xaa, 0x22, 0x76, 0xef, 0x97, 0x2b, 0x7e, 0x53, 0xe6, 0x05, 0xf0, 0x9f, 0x0c,
-	0xe6, 0xb8, 0x3d, 0xf2, 0x49, 0x51, 0x1c, 0x4f, 0xc7, 0xd8, 0xd7, 0x60, 0x39, 0xcd, 0x6b, 0x77,
-	0x0a, 0xca, 0x5b, 0x1e, 0x40, 0x1b, 0x80, 0xd2, 0xce, 0xbd, 0x5c, 0x6e, 0x38, 0xdf, 0x35, 0x23,
-	0x19, 0x73, 0xeb, 0x39, 0x73, 0xf1, 0x57, 0xcc, 0x73, 0x54, 0x08, 0xaf, 0xc2, 0x3b, 0xd9, 0x68,
-	0xd4, 0x4e, 0x63, 0x34, 0x7e, 0xc6, 0x13, 0x77, 0x67, 0x14, 0xc8, 0xd3, 0xf1, 0x0d, 0x49, 0xb9,
-	0xa3, 0x9c, 0x0f, 0x2b, 0x2a, 0x19, 0xff, 0x65, 0x09, 0x8a, 0xe1, 0x12, 0xf7, 0x51, 0xd3, 0xc1,
-	0x0e, 0xbb, 0xc2, 0x5f, 0x88, 0xe1, 0x90, 0xfc, 0x83, 0x19, 0xd9, 0x3f, 0xc0, 0xfb, 0x70, 0x59,
-	0xbf, 0x68, 0x15, 0xdb, 0xf2, 0x2f, 0x23, 0x0d, 0xf8, 0x53, 0x6c, 0xa7, 0xc8, 0x5e, 0x9c, 0xb4,
-	0x83, 0x4f, 0x14, 0x4f, 0x86, 0x27, 0xfd, 0x6e, 0x2a, 0xd9, 0x0d, 0xdd, 0xa2, 0xdf, 0x66, 0x7a,
-	0xe3, 0x7e, 0xf1, 0xc0, 0xce, 0x90, 0xdf, 0xf8, 0x08, 0x56, 0x9e, 0x3a, 0x49, 0xf7, 0xa8, 0x68,
-	0x3c, 0x5e, 0x86, 0x85, 0x98, 0x78, 0x87, 0x45, 0x05, 0x50, 0x3b, 0xf1, 0x5f, 0x0d, 0x58, 0x2d,
-	0x4c, 0xaf, 0x22, 0xbc, 0x17, 0x60, 0xd6, 0xe9, 0x26, 0x92, 0xa7, 0xc2, 0x5b, 0xe8, 0x06, 0x67,
-	0x0a, 0x4f, 0x3f, 0x8c, 0x2b, 0xc7, 0x31, 0x66, 0xc9, 0x46, 0xa0, 0x7e, 0x1a, 0x23, 0xf0, 0x00,
-	0x96, 0x68, 0x90, 0xc9, 0x9f, 0xc2, 0x4c, 0x25, 0x2f, 0x72, 0xc6, 0xba, 0xa6, 0x66, 0xac, 0x71,
-	0x87, 0x59, 0x94, 0x2d, 0xcf, 0x3b, 0x0d, 0xc2, 0xab, 0x00, 0xcf, 0xdd, 0xe4, 0x88, 0x4f, 0x11,
-	0xb9, 0x50, 0xa9, 0x07, 0x7f, 0xc5, 0x53, 0x99, 0x02, 0x63, 0x45, 0xfe, 0xc6, 0x39, 0xf6, 0xec,
-	0x65, 0x0e, 0x3b, 0x64, 0xf6, 0xd5, 0x11, 0x69, 0x76, 0xe1, 0x09, 0x2a, 0x9d, 0xd8, 0x63, 0xf6,
-	0x49, 0xda, 0x54, 0x15, 0x12, 0xa6, 0x7e, 0x85, 0xf4, 0x08, 0xce, 0x8b, 0x30, 0xed, 0x05, 0x9d,
-	0xc9, 0x76, 0x96, 0x9b, 0xae, 0x4e, 0x3e, 0xfe, 0xa9, 0x01, 0xe7, 0xe5, 0x47, 0x4c, 0x67, 0x26,
-	0x6b, 0xdc, 0x6b, 0xa9, 0x09, 0x45, 0x8f, 0x6d, 0xf5, 0xf9, 0x57, 0xb5, 0x8d, 0x3c, 0x65, 0xa1,
-	0xf9, 0x0e, 0x09, 0x89, 0xdf, 0x23, 0x7e, 0xd7, 0xcd, 0xaf, 0xbd, 0x5b, 0x30, 0xdf, 0x93, 0xba,
-	0xc5, 0x63, 0xaa, 0x8b, 0x69, 0x79, 0x43, 0x5c, 0x7c, 0xd9, 0xcc, 0x91, 0xad, 0x80, 0xe3, 0xbb,
-	0x2c, 0x83, 0xa6, 0x22, 0xae, 0x42, 0xe0, 0xe7, 0x70, 0x91, 0x97, 0x2b, 0xbe, 0x05, 0x1a, 0x7f,
-	0x63, 0x00, 0x2a, 0x03, 0xa1, 0x4d, 0x68, 0xa5, 0xf7, 0xba, 0xa0, 0x6f, 0x9c, 0xad, 0xc9, 0xe0,
-	0xd4, 0xf7, 0x02, 0xb5, 0x69, 0xdf, 0x0b, 0x58, 0xd0, 0x0a, 0x8e, 0x49, 0x14, 0xb9, 0x3d, 0xee,
-	0xa4, 0xb6, 0xec, 0xac, 0x4d, 0xfd, 0x7f, 0xdd, 0xc6, 0xab, 0xf0, 0xd0, 0x67, 0x9e, 0x95, 0x8e,
-	0x81, 0x27, 0x5a, 0xa2, 0xd8, 0x19, 0x10, 0xe9, 0xa5, 0x55, 0xcb, 0x96, 0x7a, 0xa8, 0xcc, 0xfa,
-	0x41, 0x87, 0x78, 0x87, 0x82, 0x78, 0xd1, 0xc2, 0x3f, 0x06, 0x6b, 0x97, 0x24, 0xdb, 0x81, 0x7f,
-	0x66, 0xd2, 0xd1, 0xcd, 0x32, 0x5f, 0xb5, 0x95, 0x8b, 0x1c, 0x4a, 0xac, 0xfe, 0x38, 0x0a, 0x5e,
-	0xc8, 0xea, 0xe9, 0x09, 0x4f, 0x5e, 0x3d, 0x83, 0xc2, 0xbf, 0xaa, 0xc3, 0x82, 0xf2, 0xb0, 0x09,
-	0xbd, 0x0b, 0xf3, 0x03, 0x09, 0x78, 0x52, 0x21, 0x52, 0x01, 0x3c, 0x83, 0x0f, 0x87, 0x5e, 0x87,
-	0x39, 0x61, 0x59, 0xfc, 0xc3, 0x20, 0xf5, 0x52, 0x0a, 0x26, 0x56, 0x86, 0xc8, 0xeb, 0x0a, 0xf5,
-	0x13, 0xea, 0x0a, 0xea, 0x89, 0x34, 0xa6, 0x39, 0x11, 0x95, 0x8d, 0xb3, 0xd3, 0xb0, 0x11, 0x6d,
-	0x0a, 0xc7, 0xb8, 0xc9, 0xa0, 0xaf, 0xea, 0x5e, 0x8c, 0x95, 0xaa, 0xa9, 0x9b, 0xb0, 0x22, 0xf3,
-	0xef, 0x33, 0x1e, 0xcc, 0xc5, 0x66, 0x8b, 0x39, 0xd7, 0xda, 0x31, 0xf4, 0x2a, 0x34, 0xd9, 0xeb,
-	0xb1, 0x6e, 0x2c, 0xf2, 0x3d, 0x9a, 0xf7, 0x65, 0x29, 0x44, 0xf5, 0x04, 0xe8, 0x73, 0x30, 0xf3,
-	0xd4, 0xb5, 0x78, 0xef, 0x56, 0x49, 0x20, 0x37, 0x8a, 0x05, 0x45, 0xfd, 0x5b, 0xba, 0xac, 0xa2,
-	0x78, 0x1f, 0xd0, 0x0e, 0xf1, 0x0a, 0x15, 0x45, 0xa6, 0xd7, 0xa9, 0x92, 0xa7, 0xef, 0x0a, 0xa5,
-	0x9e, 0x31, 0x85, 0x58, 0x5b, 0xc5, 0x15, 0x87, 0x2c, 0xd3, 0xa4, 0xbe, 0x0c, 0x35, 0x8a, 0x2f,
-	0x43, 0x4f, 0x48, 0xfe, 0x8c, 0xd8, 0xed, 0x7e, 0xb6, 0xda, 0xe6, 0xdb, 0xa5, 0xda, 0x66, 0x7a,
-	0x01, 0x94, 0xc9, 0x95, 0x2a, 0x9c, 0x9b, 0xb0, 0x48, 0xdd, 0x98, 0x30, 0x8f, 0x0b, 0x0a, 0xd1,
-	0xbd, 0x51, 0x8e, 0xee, 0x3f, 0x83, 0x73, 0xd9, 0x9c, 0xaa, 0x8e, 0x6d, 0x18, 0xe6, 0x85, 0x1b,
-	0xd1, 0xda, 0xfc, 0x66, 0x31, 0x7b, 0x0c, 0xb3, 0x9d, 0x44, 0x1e, 0xfa, 0x18, 0x1a, 0xe4, 0x4b,
-	0x37, 0x4e, 0x90, 0x95, 0x17, 0x3e, 0x8a, 0x0f, 0x4a, 0xac, 0x4b, 0xda, 0x31, 0xb1, 0xd2, 0x36,
-	0xcc, 0x76, 0xd9, 0xed, 0x81, 0x2e, 0x4d, 0x78, 0x90, 0x60, 0x5d, 0xd6, 0x0f, 0xe6, 0x48, 0x7a,
-	0xcc, 0x55, 0xca, 0x90, 0xe8, 0xaa, 0xfa, 0x19, 0x12, 0x7d, 0x99, 0xfe, 0x16, 0xcc, 0xf6, 0x59,
-	0x58, 0x8f, 0xcc, 0x52, 0x15, 0x27, 0xc5, 0x70, 0x51, 0x33, 0x22, 0xa6, 0xef, 0xc0, 0x5c, 0x3f,
-	0xaf, 0x7e, 0xa3, 0x32, 0x64, 0x7a, 0x7c, 0x96, 0xa5, 0x1b, 0x12, 0x58, 0x9e, 0xc2, 0xd2, 0x90,
-	0x85, 0x48, 0x79, 0x9c, 0x85, 0xd6, 0x4f, 0x2a, 0x49, 0x5b, 0xd7, 0x27, 0x40, 0x08, 0xc4, 0xf7,
-	0xa0, 0xe9, 0xf4, 0x7a, 0x2c, 0x61, 0x74, 0xa5, 0x54, 0x4c, 0x91, 0x6b, 0x7a, 0xd6, 0xd5, 0x71,
-	0xc3, 0x39, 0xa6, 0x3e, 0x49, 0x14, 0x4c, 0xfa, 0x4a, 0x73, 0x86, 0x69, 0x5c, 0x41, 0xf9, 0x11,
-	0x00, 0xdf, 0x2c, 0x43, 0x76, 0x4d, 0xb7, 0x09, 0xa9, 0x16, 0x6c, 0xad, 0x8f, 0x07, 0x10, 0x08,
-	0x3f, 0x01, 0xe0, 0x72, 0xc0, 0x10, 0xae, 0x6b, 0x8f, 0x5b, 0x26, 0xf0, 0xfa, 0x04, 0x08, 0x81,
-	0xf2, 0x2e, 0xe3, 0x1b, 0xb5, 0xa6, 0xe8, 0xf2, 0xa4, 0x22, 0x94, 0x75, 0x65, 0xcc, 0x68, 0x8e,
-	0xa7, 0x4f, 0x12, 0x05, 0x8f, 0xb6, 0x6c, 0x69, 0x5d, 0x99, 0x58, 0x42, 0x44, 0x0f, 0xa0, 0xcd,
-	0x79, 0xf6, 0xc4, 0xe9, 0xa3, 0xab, 0x3a, 0x8e, 0xe4, 0xb5, 0x40, 0xeb, 0xda, 0xd8, 0xf1, 0xfc,
-	0x04, 0x38, 0xc3, 0x18, 0x61, 0xd7, 0x74, 0xec, 0x90, 0x69, 0x5b, 0x1f, 0x0f, 0x90, 0xbd, 0xa6,
-	0x5e, 0xe8, 0xa7, 0x31, 0x1f, 0x33, 0xbb, 0x6b, 0xd2, 0x76, 0xe4, 0x08, 0xc4, 0x32, 0xcb, 0x03,
-	0x02, 0xc7, 0x3e, 0x2c, 0xf5, 0xa5, 0xb8, 0x8d, 0xa1, 0x91, 0x74, 0xa6, 0x18, 0xa5, 0xca, 0xf6,
-	0xa5, 0x1c, 0xec, 0xed, 0x52, 0xcf, 0x3b, 0x8f, 0xa2, 0x32, 0x44, 0x9a, 0x58, 0xcd, 0xba, 0xa4,
-	0x1d, 0xcb, 0x11, 0x0d, 0xa4, 0x28, 0x26, 0x43, 0xa4, 0x89, 0xae, 0x32, 0x44, 0xda, 0xb0, 0xe7,
-	0x3e, 0x2c, 0xc8, 0x88, 0x62, 0xa4, 0x83, 0x8e, 0x8b, 0x36, 0x4b, 0xff, 0x4f, 0x94, 0x2f, 0xe0,
-	0x9a, 0xa3, 0x06, 0x2f, 0x77, 0x83, 0x48, 0x76, 0x55, 0x62, 0x59, 0xdb, 0x35, 0x8e, 0xb5, 0xac,
-	0xed, 0x5a, 0x37, 0xd4, 0x05, 0xdc, 0x2d, 0x79, 0xf7, 0xa5, 0x45, 0xd6, 0x15, 0xf3, 0xac, 0x5b,
-	0xe7, 0xfa, 0x04, 0x08, 0xb1, 0xd4, 0xf7, 0x60, 0xad, 0xcf, 0xfc, 0x61, 0xe6, 0x8d, 0xc9, 0x20,
-	0xb2, 0xa1, 0x99, 0x84, 0x7c, 0x82, 0x3b, 0xcd, 0x91, 0x97, 0x82, 0xa8, 0xd3, 0x21, 0x1f, 0x17,
-	0x29, 0xec, 0xc2, 0x62, 0x4f, 0x56, 0x89, 0xdc, 0xfc, 0x97, 0x9d, 0x1a, 0xcb, 0xd2, 0x0d, 0x71,
-	0x44, 0x9b, 0xbf, 0x9d, 0x85, 0xf3, 0x05, 0x6f, 0x99, 0xdd, 0xb3, 0xfb, 0xf4, 0xf2, 0xe6, 0x15,
-	0x96, 0x4c, 0xe9, 0xc7, 0x14, 0xa3, 0x32, 0xa5, 0x1f, 0x5b, 0x58, 0xfa, 0x04, 0x60, 0x98, 0x15,
-	0x6c, 0xf2, 0xdb, 0x65, 0x5c, 0x79, 0x28, 0xbf, 0x5d, 0xc6, 0x57, 0x79, 0xfe, 0x1f, 0xda, 0x47,
-	0x69, 0x99, 0x25, 0x53, 0xf9, 0x62, 0x29, 0x27, 0x53, 0xf9, 0x72, 0x45, 0x66, 0x0b, 0xea, 0x87,
-	0xae, 0xdf, 0xcb, 0x54, 0x41, 0x57, 0x70, 0xc9, 0x54, 0x41, 0x5f, 0xb7, 0xd8, 0x85, 0xf9, 0xbe,
-	0x94, 0x8d, 0x96, 0x2d, 0x46, 0x09, 0xd3, 0x25, 0xed, 0x98, 0x40, 0xf4, 0x10, 0x16, 0xfb, 0x4a,
-	0x7a, 0x5f, 0x36, 0xd8, 0xe5, 0x72, 0x85, 0x6c, 0xb0, 0x75, 0x35, 0x81, 0xa7, 0x30, 0xcf, 0x0d,
-	0x36, 0x4f, 0x14, 0x23, 0xac, 0x4d, 0xc1, 0x2a, 0xa9, 0x6b, 0xeb, 0xa5, 0x89, 0x30, 0x02, 0xb1,
-	0x03, 0xe6, 0xb0, 0x94, 0x4d, 0x15, 0x2e, 0xc3, 0xf5, 0x13, 0xf3, 0xbc, 0x16, 0x9e, 0x04, 0x92,
-	0xf9, 0x34, 0x8d, 0xe7, 0x4e, 0xd2, 0x3d, 0xca, 0xce, 0x45, 0x97, 0x72, 0xcd, 0xce, 0x45, 0x9b,
-	0x50, 0x7d, 0xc3, 0xa0, 0x27, 0x73, 0x24, 0xfd, 0xb7, 0x0c, 0xe9, 0xfe, 0xe7, 0x56, 0x3c, 0x19,
-	0xdd, 0x9f, 0xd1, 0x36, 0xff, 0x6d, 0xc0, 0xf2, 0x6e, 0x70, 0x4c, 0x22, 0x5f, 0xf6, 0x41, 0x1f,
-	0xb2, 0xeb, 0x42, 0x0d, 0x65, 0xc7, 0x7b, 0x70, 0xd7, 0x4a, 0x23, 0x85, 0x30, 0xe7, 0x31, 0x9c,
-	0xeb, 0xab, 0xff, 0x2b, 0xd2, 0xb8, 0x39, 0xf2, 0x5f, 0x9f, 0x34, 0x6e, 0x8e, 0xfa, 0x77, 0xa4,
-	0x8f, 0x19, 0xc6, 0xad, 0x30, 0xf4, 0xdc, 0xae, 0xc3, 0xff, 0x13, 0xb5, 0x2a, 0x5d, 0x59, 0xb9,
-	0x63, 0x6f, 0x5d, 0x28, 0x76, 0x73, 0x0c, 0xb7, 0xdf, 0x80, 0x57, 0xba, 0xc1, 0x60, 0xe3, 0x68,
-	0xe8, 0x3c, 0x27, 0xee, 0x46, 0xe8, 0x38, 0xf1, 0x46, 0x37, 0x26, 0x1b, 0x22, 0x44, 0xe0, 0x5a,
-	0x19, 0x8d, 0x36, 0x9c, 0xd0, 0xfd, 0x9c, 0xff, 0xe5, 0xf2, 0x60, 0x96, 0xfd, 0xbc, 0xf9, 0x9f,
-	0x00, 0x00, 0x00, 0xff, 0xff, 0x3b, 0x0a, 0x35, 0x24, 0x92, 0x39, 0x00, 0x00,
+	// 3410 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x3b, 0x5b, 0x6f, 0xdc, 0xc6,
+	0xd5, 0xe0, 0x6a, 0x57, 0xd2, 0x1e, 0x5d, 0x2c, 0x8d, 0x56, 0x16, 0x45, 0xcb, 0xb2, 0xcc, 0xe4,
+	0x4b, 0x0c, 0x24, 0x9f, 0x62, 0x2b, 0xf7, 0x8b, 0x3f, 0xc4, 0x96, 0x6c, 0xd9, 0xb1, 0xfd, 0xd9,
+	0xe1, 0x3a, 0x31, 0x9a, 0x36, 0x68, 0xe8, 0xdd, 0xd1, 0x8a, 0x35, 0x97, 0x64, 0x49, 0xae, 0xdc,
+	0x05, 0x8a, 0x00, 0x45, 0x51, 0xa0, 0x2f, 0xbd, 0xbe, 0x14, 0x68, 0xfb, 0x07, 0xda, 0x3f, 0xd0,
+	0x87, 0xa2, 0xe8, 0x7b, 0x81, 0x3c, 0xf7, 0x29, 0xaf, 0x7d, 0xea, 0x73, 0x1f, 0x8b, 0x62, 0x6e,
+	0xe4, 0x0c, 0x39, 0xbb, 0x5a, 0x51, 0x4a, 0x9f, 0x96, 0x33, 0x73, 0xe6, 0xcc, 0x99, 0x73, 0xce,
+	0x9c, 0x39, 0x97, 0x59, 0x58, 0x4c, 0x70, 0x7c, 0xe4, 0x75, 0x70, 0xb2, 0x1d, 0xc5, 0x61, 0x1a,
+	0xa2, 0x06, 0xfd, 0xb1, 0x3f, 0x87, 0xd6, 0x83, 0xb0, 0xeb, 0x1d, 0x0c, 0xdb, 0x9d, 0x43, 0xdc,
+	0x77, 0x13, 0x07, 0x7f, 0x7f, 0x80, 0x93, 0x14, 0x6d, 0x40, 0x93, 0x4f, 0xb8, 0xdb, 0x35, 0x8d,
+	0x2d, 0xe3, 0x4a, 0xd3, 0xc9, 0x3b, 0xd0, 0xcb, 0x30, 0x93, 0x30, 0x78, 0xb3, 0xb6, 0x35, 0x75,
+	0x65, 0x6e, 0x67, 0x81, 0x61, 0xdd, 0x66, 0x58, 0x1c, 0x31, 0x6a, 0x7f, 0x0a, 0xd3, 0xac, 0x0b,
+	0x59, 0x30, 0xcb, 0x3a, 0x33, 0x7c, 0x59, 0x1b, 0x99, 0x30, 0x93, 0x0c, 0xfa, 0x7d, 0x37, 0x1e,
+	0x9a, 0x35, 0x3a, 0x24, 0x9a, 0xe8, 0x3c, 0x4c, 0x33, 0x28, 0x73, 0x8a, 0x0e, 0xf0, 0x96, 0xbd,
+	0x07, 0xab, 0x05, 0xb2, 0x93, 0x28, 0x0c, 0x12, 0x8c, 0x5e, 0x81, 0xd9, 0x98, 0x7f, 0xd3, 0x65,
+	0xe6, 0x76, 0xce, 0x71, 0xd2, 0x04, 0x88, 0x93, 0x01, 0xd8, 0x0f, 0x61, 0xe5, 0x0e, 0x76, 0xe3,
+	0xf4, 0x29, 0x76, 0xd3, 0x36, 0x4e, 0xc5, 0xde, 0xdf, 0x81, 0xa6, 0x17, 0x24, 0xa9, 0x1b, 0x74,
+	0x70, 0x62, 0x1a, 0x74, 0x7f, 0x16, 0x47, 0x22, 0x83, 0xdf, 0xf2, 0x71, 0x1f, 0x07, 0xa9, 0x93,
+	0x03, 0xdb, 0x6d, 0x58, 0xd1, 0x40, 0x1c, 0xc3, 0xcc, 0x4d, 0x00, 0x81, 0xe1, 0x6e, 0x97, 0x33,
+	0x40, 0xea, 0xb1, 0x9f, 0x43, 0x4b, 0xa5, 0xb2, 0xc2, 0x56, 0xd1, 0x8e, 0xbc, 0x27, 0x26, 0xb3,
+	0x16, 0x87, 0xbe, 0xcb, 0xfb, 0xef, 0x3c, 0x75, 0x12, 0x65, 0x37, 0x7d, 0x58, 0x50, 0xc6, 0x4e,
+	0xb7, 0x0f, 0x32, 0x8e, 0xe3, 0xf8, 0x01, 0x4e, 0x12, 0xb7, 0x87, 0xb9, 0x3c, 0xa5, 0x1e, 0x7b,
+	0x17, 0x9a, 0xed, 0xb4, 0xcd, 0xd0, 0xa1, 0x16, 0x34, 0x3a, 0xe1, 0x20, 0x48, 0xe9, 0x32, 0x53,
+	0x0e, 0x6b, 0xa0, 0x2d, 0x98, 0x0b, 0x03, 0xdf, 0x0b, 0xf0, 0x2e, 0x1d, 0xab, 0xd1, 0x31, 0xb9,
+	0xcb, 0xbe, 0x03, 0xd0, 0x4e, 0x05, 0xd5, 0x23, 0xb0, 0xbc, 0x08, 0x0b, 0xf4, 0xe3, 0xe6, 0x70,
+	0x2f, 0xec, 0xbb, 0x5e, 0xc0, 0xf1, 0xa8, 0x9d, 0xf6, 0x45, 0x68, 0xb4, 0xd3, 0x1b, 0x51, 0xa4,
+	0x47, 0x62, 0xff, 0xcc, 0x20, 0x2b, 0xb9, 0xa9, 0x97, 0xa4, 0x5e, 0x27, 0x41, 0xaf, 0xc2, 0xac,
+	0x38, 0x60, 0x5c, 0x18, 0x4b, 0xe2, 0x48, 0x88, 0x3d, 0x39, 0x19, 0x04, 0x7a, 0x4d, 0x95, 0x06,
+	0x01, 0x5f, 0xce, 0xc0, 0x05, 0xf5, 0x92, 0x28, 0xd0, 0x16, 0xd4, 0xdd, 0x28, 0x4a, 0x28, 0xd7,
+	0xe6, 0x76, 0xe6, 0x33, 0xd8, 0x1b, 0x51, 0xe4, 0xd0, 0x11, 0xfb, 0xa7, 0x06, 0x9c, 0xdf, 0xc7,
+	0x62, 0xad, 0xe4, 0x6e, 0x70, 0x10, 0x0a, 0x7d, 0x36, 0x61, 0x26, 0x8c, 0x52, 0x2f, 0x0c, 0x98,
+	0x36, 0x37, 0x1d, 0xd1, 0x24, 0x5b, 0x73, 0xa3, 0x28, 0x93, 0x16, 0x6b, 0x10, 0x2e, 0x73, 0x4a,
+	0xff, 0xdf, 0xed, 0x0b, 0x49, 0xc9, 0x5d, 0x44, 0x11, 0x28, 0x17, 0x1e, 0x06, 0xfe, 0xd0, 0xac,
+	0x6f, 0x19, 0x57, 0x66, 0x9d, 0xbc, 0xc3, 0xfe, 0x8b, 0x01, 0x6b, 0x25, 0x52, 0xaa, 0x28, 0xed,
+	0x4d, 0x58, 0x76, 0x7d, 0x5f, 0xe0, 0xd9, 0xc3, 0xa9, 0xeb, 0xf9, 0x05, 0xe5, 0xe5, 0x83, 0x6c,
+	0xcc, 0x29, 0x83, 0xa3, 0x6b, 0x00, 0x49, 0x26, 0x26, 0x73, 0xaa, 0xc0, 0x6b, 0x31, 0xe0, 0x48,
+	0x40, 0xf6, 0x57, 0x06, 0x9c, 0x7b, 0xe0, 0x75, 0xe2, 0x90, 0xa3, 0xba, 0x87, 0xa9, 0x21, 0x4a,
+	0x71, 0xe0, 0x72, 0x2d, 0x68, 0x3a, 0xbc, 0x45, 0x78, 0x1b, 0xc5, 0xe1, 0xf7, 0x70, 0x27, 0x15,
+	0xa6, 0x8b, 0x37, 0x73, 0xde, 0x4e, 0x8d, 0xe1, 0x6d, 0xbd, 0xcc, 0x5b, 0x13, 0x66, 0x8e, 0x70,
+	0x9c, 0x78, 0x61, 0x60, 0x36, 0x18, 0x46, 0xde, 0x24, 0x73, 0x71, 0x70, 0xe4, 0xc5, 0x61, 0x40,
+	0xac, 0x8a, 0x39, 0xcd, 0xe6, 0x4a, 0x5d, 0x74, 0x4d, 0xdf, 0x73, 0x13, 0x73, 0x86, 0xaf, 0x49,
+	0x1a, 0xf6, 0x3f, 0xa7, 0x61, 0x5e, 0xde, 0xcf, 0x31, 0xe7, 0xb8, 0xaa, 0x52, 0x48, 0x84, 0xd7,
+	0x4b, 0x84, 0x77, 0x71, 0xd2, 0x89, 0xbd, 0x28, 0xcd, 0xb7, 0x25, 0x77, 0x91, 0x35, 0x7d, 0x7c,
+	0x84, 0x7d, 0xbe, 0x29, 0xd6, 0x20, 0x18, 0xc5, 0x35, 0x33, 0xc3, 0x14, 0x97, 0x37, 0xd1, 0x15,
+	0x68, 0x44, 0x6e, 0x7a, 0x98, 0x98, 0x40, 0xb5, 0x01, 0xa9, 0xda, 0xf0, 0xc8, 0x4d, 0x0f, 0x1d,
+	0x06, 0x40, 0x6f, 0x90, 0xd4, 0x4d, 0x07, 0x89, 0x39, 0xcb, 0x6f, 0x10, 0xda, 0x42, 0xbb, 0x00,
+	0x51, 0x1c, 0x46, 0x38, 0x4e, 0x3d, 0x9c, 0x98, 0x4d, 0x8a, 0xe6, 0x05, 0x8e, 0x46, 0x66, 0xd6,
+	0xf6, 0xa3, 0x0c, 0xea, 0x56, 0x90, 0xc6, 0x43, 0x47, 0x9a, 0x46, 0x18, 0x99, 0x7a, 0x7d, 0x9c,
+	0xa4, 0x6e, 0x3f, 0x32, 0xe7, 0x18, 0x23, 0xb3, 0x0e, 0xf4, 0x06, 0x34, 0xa3, 0x38, 0x3c, 0xf2,
+	0xba, 0x38, 0x4e, 0xcc, 0x79, 0xba, 0xc2, 0x79, 0xcd, 0x0a, 0xf7, 0xf0, 0xd0, 0xc9, 0x01, 0x73,
+	0x19, 0x2e, 0x48, 0x32, 0x24, 0xe4, 0xde, 0xbf, 0xd9, 0x4e, 0x63, 0x37, 0xc5, 0xbd, 0xa1, 0xb9,
+	0x38, 0x9a, 0xdc, 0x1c, 0x8a, 0x93, 0x9b, 0x77, 0x20, 0x1b, 0xe6, 0xfb, 0x61, 0xf7, 0x71, 0x46,
+	0xf1, 0x39, 0xba, 0x82, 0xd2, 0x57, 0x54, 0xb2, 0xa5, 0xb2, 0x92, 0x6d, 0x02, 0xc4, 0xb8, 0xe7,
+	0x25, 0x29, 0x8e, 0x6f, 0x0e, 0xcd, 0x65, 0x0a, 0x20, 0xf5, 0xa0, 0xb7, 0xa0, 0x79, 0x10, 0xbb,
+	0x7d, 0xfc, 0x3c, 0x8c, 0x9f, 0x99, 0x88, 0x1e, 0x38, 0x93, 0x53, 0x7a, 0x9b, 0xf4, 0x3f, 0x09,
+	0xe3, 0x67, 0x9c, 0xa9, 0x43, 0x27, 0x07, 0x45, 0x2f, 0xc1, 0x62, 0x27, 0xc6, 0x6e, 0x8a, 0x1d,
+	0x7c, 0xe4, 0x51, 0x35, 0x5a, 0xa1, 0x06, 0xb7, 0xd0, 0x4b, 0x28, 0xec, 0x87, 0xdd, 0x0c, 0xa8,
+	0xc5, 0x2e, 0x01, 0xa9, 0xcb, 0xba, 0x0e, 0xe7, 0x0a, 0x52, 0x43, 0x4b, 0x30, 0xf5, 0x0c, 0x0f,
+	0xb9, 0xb2, 0x93, 0x4f, 0xc2, 0xe7, 0x23, 0xd7, 0x1f, 0x60, 0xa1, 0xe6, 0xb4, 0xf1, 0x5e, 0xed,
+	0x1d, 0x83, 0x4c, 0x2f, 0x70, 0xf1, 0x24, 0xd3, 0xed, 0x1b, 0xb0, 0x5c, 0xda, 0x27, 0x42, 0x50,
+	0x0f, 0xc8, 0xb9, 0x61, 0x18, 0xe8, 0xb7, 0x7c, 0x60, 0x6a, 0xca, 0x81, 0xb1, 0xbf, 0x36, 0x60,
+	0x4e, 0xdc, 0x1a, 0x03, 0x1f, 0x13, 0x25, 0x8e, 0x07, 0x7e, 0x7e, 0x5a, 0x79, 0x8b, 0x38, 0x55,
+	0xe4, 0xeb, 0xf1, 0x30, 0x12, 0x74, 0x64, 0x6d, 0xa2, 0x9b, 0x6e, 0x9a, 0xc6, 0xde, 0xd3, 0x41,
+	0x2a, 0x8e, 0x6b, 0xde, 0x41, 0xed, 0x96, 0x9b, 0xa6, 0x38, 0xce, 0x0e, 0x2b, 0x6f, 0x4e, 0x70,
+	0x58, 0x15, 0xad, 0x9f, 0x2e, 0x6a, 0x7d, 0x51, 0xc9, 0x66, 0xca, 0x4a, 0x66, 0xff, 0xdc, 0x80,
+	0xf3, 0x37, 0xba, 0xdd, 0x87, 0xf1, 0x27, 0x51, 0xd7, 0x4d, 0xb1, 0xbc, 0x55, 0x79, 0x4b, 0xc6,
+	0xb8, 0x2d, 0xd5, 0xc6, 0x6c, 0x69, 0x6a, 0xec, 0x96, 0xea, 0xa5, 0x2d, 0xd9, 0xbf, 0xcf, 0x19,
+	0x4e, 0x8c, 0x07, 0x11, 0x17, 0x31, 0x1f, 0x42, 0x5c, 0xe4, 0x1b, 0x7d, 0x00, 0xb3, 0xfc, 0xe8,
+	0x0f, 0xf9, 0x25, 0xb4, 0x55, 0x36, 0x3b, 0xc2, 0x5c, 0xf0, 0xd3, 0x97, 0xcd, 0xb0, 0xde, 0x87,
+	0x05, 0x65, 0xe8, 0x44, 0x2a, 0xf5, 0x0e, 0xcc, 0x66, 0x37, 0x28, 0x82, 0x7a, 0x27, 0xec, 0x32,
+	0xe6, 0x34, 0x1c, 0xfa, 0x4d, 0xb6, 0xde, 0xe7, 0x7e, 0x15, 0xd7, 0x24, 0xde, 0xb4, 0xff, 0x6e,
+	0xc0, 0xca, 0x3e, 0x4e, 0x6f, 0xfd, 0x80, 0x9c, 0x4e, 0xe2, 0x54, 0x70, 0x9f, 0x00, 0x41, 0x3d,
+	0xcd, 0x59, 0x4c, 0xbf, 0xbf, 0x01, 0xc3, 0xaf, 0x5c, 0x34, 0x8d, 0xe2, 0x45, 0x23, 0x87, 0x04,
+	0xd3, 0x85, 0x90, 0xa0, 0x60, 0x86, 0x66, 0x4a, 0x66, 0xc8, 0xfe, 0x8d, 0x01, 0x2d, 0x75, 0x67,
+	0x55, 0x5c, 0x0c, 0x85, 0xc2, 0xda, 0x38, 0x0a, 0xa7, 0x46, 0x07, 0x2d, 0x75, 0x25, 0x68, 0xb1,
+	0xff, 0x50, 0x83, 0xd6, 0x2e, 0xb5, 0x59, 0x42, 0xb1, 0x39, 0xd3, 0xff, 0x17, 0x66, 0x38, 0x6e,
+	0x4e, 0xd8, 0x8a, 0xc6, 0x82, 0x3b, 0x02, 0x06, 0xbd, 0x0e, 0x0d, 0xa2, 0xfa, 0xc2, 0x5f, 0xbf,
+	0xc8, 0x81, 0xf5, 0x07, 0xc7, 0x61, 0xb0, 0xe8, 0x5d, 0xa8, 0xa7, 0x6e, 0x8f, 0x78, 0x3a, 0x64,
+	0xce, 0xff, 0xf0, 0x39, 0x3a, 0x72, 0xb6, 0x1f, 0xbb, 0x3d, 0x7e, 0xa7, 0xd1, 0x29, 0xe8, 0x5d,
+	0xd9, 0x2b, 0xad, 0xd3, 0xf9, 0x17, 0x34, 0x04, 0x6a, 0xfc, 0x53, 0xeb, 0x6d, 0x68, 0x66, 0xd8,
+	0x4e, 0xa4, 0xd9, 0x4f, 0x61, 0xb5, 0x40, 0xdb, 0x99, 0x4b, 0xd1, 0xfe, 0x08, 0x5a, 0x7b, 0xd8,
+	0xc7, 0x25, 0x71, 0x1c, 0xeb, 0x06, 0x1d, 0x84, 0x71, 0x87, 0xd1, 0x3c, 0xeb, 0xb0, 0x06, 0x09,
+	0x3c, 0x0b, 0xb8, 0xaa, 0x04, 0x9e, 0xd7, 0x60, 0x39, 0x77, 0x90, 0x27, 0x22, 0xc7, 0x8e, 0x00,
+	0xc9, 0x53, 0xaa, 0x70, 0x49, 0x52, 0xbf, 0xda, 0xf1, 0xea, 0x67, 0xb7, 0xe4, 0x15, 0x45, 0x62,
+	0xc0, 0x4e, 0x60, 0x45, 0xe9, 0xad, 0x42, 0xc8, 0x6b, 0x52, 0xb0, 0xc4, 0x74, 0x5b, 0x4b, 0x49,
+	0x06, 0x64, 0xff, 0xcd, 0x80, 0x75, 0x45, 0xe3, 0x89, 0x29, 0x9d, 0x30, 0x57, 0xf1, 0x48, 0x71,
+	0xf4, 0xd8, 0x72, 0x57, 0xf9, 0x72, 0x23, 0x71, 0x8e, 0xf3, 0xfa, 0x4e, 0xe9, 0x5e, 0xd8, 0x77,
+	0xc1, 0xd2, 0xad, 0x5b, 0x45, 0x8f, 0xde, 0x92, 0x63, 0x3e, 0x62, 0x05, 0x92, 0x49, 0x95, 0x69,
+	0xad, 0x34, 0xaf, 0x8a, 0x20, 0xaf, 0xa8, 0x16, 0xaa, 0xe0, 0x86, 0x4b, 0x66, 0xc9, 0xfe, 0xb1,
+	0x01, 0x66, 0xd9, 0x66, 0x4d, 0x24, 0xc0, 0xdc, 0xf9, 0xa9, 0x29, 0xce, 0xcf, 0x35, 0xa8, 0x93,
+	0x2f, 0x1e, 0xd3, 0x1d, 0x63, 0x1d, 0x29, 0xa8, 0x7d, 0x07, 0xd6, 0xcb, 0x43, 0x95, 0x38, 0xff,
+	0x8c, 0x3a, 0x30, 0x27, 0xe6, 0x7c, 0x25, 0x9b, 0x6e, 0x7f, 0x01, 0x6b, 0xa5, 0xc5, 0xaa, 0x88,
+	0xcb, 0x84, 0x19, 0x87, 0xf2, 0x8e, 0x2d, 0xdf, 0x74, 0x44, 0xd3, 0x6e, 0xc3, 0xba, 0x6a, 0xd6,
+	0x26, 0xdf, 0x91, 0x09, 0x33, 0xb1, 0x8a, 0x94, 0x37, 0x89, 0xa2, 0xeb, 0x90, 0x56, 0x61, 0xf7,
+	0x9b, 0xb0, 0x9a, 0x2b, 0x2c, 0xb9, 0x69, 0x26, 0xd3, 0xf3, 0x3f, 0x2b, 0x49, 0x11, 0x36, 0xaf,
+	0x0a, 0xe3, 0xde, 0xe7, 0x97, 0x2a, 0x13, 0xda, 0xcb, 0x1c, 0x50, 0x8f, 0xb9, 0x78, 0xad, 0x56,
+	0xbf, 0x1b, 0xbf, 0x0b, 0x6b, 0x8a, 0x4a, 0x3c, 0x76, 0x7b, 0x93, 0x89, 0x84, 0x2f, 0x52, 0xd3,
+	0x2c, 0x32, 0x25, 0x2d, 0x62, 0xef, 0x83, 0x59, 0x5e, 0xa0, 0x8a, 0x78, 0xfe, 0x68, 0xc0, 0x6a,
+	0xae, 0xa1, 0x13, 0xcb, 0x07, 0xbd, 0xa7, 0xf0, 0xf5, 0xa5, 0xfc, 0x30, 0x94, 0x31, 0x9d, 0x1d,
+	0x5b, 0x6f, 0xc9, 0x47, 0xb7, 0xb2, 0x4e, 0xd8, 0xf7, 0xc1, 0x54, 0xb4, 0x7b, 0xf2, 0x5d, 0x23,
+	0xa8, 0x3f, 0xc3, 0x43, 0x71, 0x5c, 0xe8, 0x37, 0xb1, 0x4c, 0x1a, 0x6c, 0x55, 0xe8, 0x1a, 0xc2,
+	0xdc, 0x1d, 0xec, 0xfa, 0xe9, 0xe1, 0xee, 0x21, 0xee, 0x3c, 0x23, 0x8b, 0xf5, 0x45, 0xb8, 0xd0,
+	0x74, 0xe8, 0x37, 0xe9, 0x8b, 0xc2, 0x98, 0x65, 0xac, 0x1a, 0x0e, 0xfd, 0x26, 0xae, 0xae, 0x17,
+	0xa4, 0x38, 0x3e, 0x72, 0x7d, 0xaa, 0x24, 0x0d, 0x27, 0x6b, 0x13, 0x5e, 0xd2, 0xf8, 0x8e, 0x3a,
+	0xba, 0x0d, 0x87, 0x35, 0x08, 0xcf, 0x07, 0xb1, 0xcf, 0xdd, 0x7a, 0xf2, 0x69, 0x7f, 0x55, 0x87,
+	0x96, 0xce, 0x53, 0x2c, 0xa4, 0x86, 0x8d, 0x52, 0x6a, 0x78, 0xbc, 0x17, 0xbe, 0x01, 0x4d, 0x1c,
+	0x74, 0xa3, 0xd0, 0x0b, 0x52, 0xe6, 0xd7, 0x36, 0x9d, 0xbc, 0x83, 0x10, 0x7e, 0x18, 0x26, 0xa9,
+	0x94, 0x4e, 0xcb, 0xda, 0x52, 0xf2, 0xa7, 0xa1, 0x24, 0x7f, 0xee, 0x29, 0x3e, 0xc1, 0x34, 0xd5,
+	0xbe, 0x57, 0xc6, 0xb8, 0xba, 0x63, 0x93, 0x40, 0x6f, 0xc0, 0xdc, 0x61, 0xce, 0x70, 0x1a, 0xaa,
+	0xe4, 0x57, 0xa1, 0x24, 0x0a, 0x47, 0x06, 0x53, 0x83, 0xe8, 0xd9, 0x62, 0x10, 0x7d, 0x1d, 0x16,
+	0xbb, 0x6e, 0xea, 0xee, 0x62, 0x22, 0x02, 0x92, 0x40, 0x35, 0x9b, 0x14, 0xed, 0x2a, 0x47, 0xbb,
+	0xa7, 0x0c, 0x3a, 0x05, 0xe0, 0x52, 0x0c, 0x0e, 0x9a, 0x44, 0x8f, 0x14, 0xb5, 0xcd, 0xa9, 0x51,
+	0x5b, 0x39, 0x11, 0x33, 0x3f, 0x49, 0x22, 0x66, 0xe1, 0xac, 0x13, 0x31, 0xf6, 0x53, 0x58, 0x54,
+	0x37, 0xaa, 0x4d, 0xa3, 0x10, 0xff, 0x00, 0xf7, 0xf2, 0x2c, 0x0a, 0x6f, 0x91, 0x34, 0xbf, 0x7b,
+	0xe4, 0x7a, 0xbe, 0xfb, 0xd4, 0xc7, 0x9f, 0x85, 0x81, 0xb0, 0x7d, 0x6a, 0xa7, 0xfd, 0x04, 0xd6,
+	0x74, 0x12, 0x27, 0x39, 0xdf, 0x53, 0x69, 0xad, 0xed, 0xc0, 0x9a, 0xc3, 0x93, 0x62, 0x02, 0xa9,
+	0x30, 0x0f, 0x6f, 0x93, 0xb3, 0xc6, 0xba, 0xf8, 0x79, 0x1e, 0x1b, 0x67, 0x65, 0xc0, 0xf6, 0x2f,
+	0x0c, 0x30, 0xcb, 0x48, 0xab, 0xdc, 0x68, 0xc7, 0x15, 0x6b, 0x88, 0x0e, 0x84, 0xc1, 0x81, 0xef,
+	0x75, 0xd2, 0x47, 0xa1, 0xef, 0x75, 0x86, 0x9c, 0x7b, 0x85, 0x5e, 0xfb, 0x5b, 0xb0, 0xfe, 0x49,
+	0x10, 0x8f, 0xd8, 0xe7, 0xe9, 0xea, 0x5e, 0xc4, 0x4f, 0xd6, 0xa0, 0xae, 0x62, 0x13, 0x1f, 0xc1,
+	0x52, 0x56, 0x42, 0x3b, 0x1b, 0xe2, 0x3e, 0x84, 0x65, 0x09, 0x63, 0x15, 0x9a, 0xfe, 0x61, 0x40,
+	0xeb, 0xb6, 0x17, 0x74, 0xc5, 0xce, 0xb2, 0xcb, 0xe3, 0x55, 0x58, 0xee, 0x84, 0x41, 0x32, 0xe8,
+	0xe3, 0xb8, 0x5d, 0x20, 0xb0, 0x3c, 0x50, 0x39, 0x69, 0xb3, 0x05, 0x73, 0xfc, 0xbc, 0x13, 0xb7,
+	0x4c, 0xe4, 0xc4, 0xa4, 0x2e, 0x84, 0xf8, 0xe5, 0xdc, 0x60, 0xd7, 0x14, 0xf9, 0x9e, 0xa0, 0x04,
+	0xb1, 0x04, 0x53, 0x31, 0x3e, 0xe2, 0x09, 0x1b, 0xf2, 0x69, 0xff, 0xca, 0x80, 0xd5, 0xc2, 0x46,
+	0xab, 0x68, 0xec, 0xbb, 0xe5, 0x0a, 0xe6, 0x84, 0xd9, 0x09, 0x41, 0xd3, 0x54, 0x4e, 0xd3, 0x9f,
+	0x0c, 0xea, 0x50, 0x3e, 0x0c, 0x70, 0x51, 0x67, 0x4f, 0xc6, 0xfd, 0x57, 0x61, 0x59, 0x64, 0xee,
+	0xdb, 0x05, 0x53, 0x50, 0x1e, 0x40, 0xdb, 0x80, 0x44, 0xe7, 0xdd, 0x5c, 0xb9, 0x18, 0x59, 0x9a,
+	0x91, 0x4c, 0x02, 0xf5, 0x5c, 0x02, 0xf6, 0x2f, 0x99, 0x4b, 0xab, 0x50, 0x5e, 0x85, 0x9d, 0xb2,
+	0x0d, 0xaa, 0x9d, 0xc0, 0x06, 0x69, 0x98, 0xf9, 0x13, 0x96, 0x63, 0x3c, 0xa5, 0x22, 0x9f, 0x8c,
+	0x95, 0x48, 0x4a, 0x73, 0x09, 0xd6, 0x7c, 0x09, 0x2d, 0x95, 0x8c, 0xff, 0xae, 0x9a, 0x11, 0x3e,
+	0x5c, 0x60, 0xfe, 0xb4, 0x18, 0x6d, 0x53, 0x77, 0xe3, 0x4c, 0x2c, 0x8e, 0xe4, 0xcb, 0x4c, 0x29,
+	0xbe, 0x0c, 0x97, 0x47, 0x3d, 0x97, 0xc7, 0xe7, 0xb0, 0xa1, 0x27, 0xa3, 0x0a, 0x3f, 0x38, 0xfa,
+	0x5a, 0x8e, 0xfe, 0xdf, 0x06, 0x58, 0x2a, 0xfe, 0x13, 0x64, 0x63, 0x8e, 0xdb, 0xe5, 0xc7, 0x8a,
+	0x67, 0xc6, 0x92, 0x98, 0xd7, 0x94, 0x6c, 0x8d, 0x6e, 0xd1, 0xb1, 0xfe, 0x59, 0x89, 0x41, 0xa7,
+	0x75, 0x4b, 0xbe, 0x53, 0x14, 0x73, 0xf5, 0x0c, 0x8e, 0x86, 0xbd, 0x1f, 0x40, 0xeb, 0x89, 0x9b,
+	0x76, 0x0e, 0x8b, 0x86, 0xe9, 0x45, 0x58, 0x48, 0xb0, 0x7f, 0x50, 0x3c, 0x49, 0x6a, 0xa7, 0xfd,
+	0x57, 0x03, 0x56, 0x0b, 0xd3, 0xab, 0x90, 0x75, 0x1e, 0xa6, 0xdd, 0x4e, 0x2a, 0xf9, 0x54, 0xac,
+	0x85, 0xae, 0x30, 0x36, 0xb1, 0x94, 0xcb, 0xa8, 0x62, 0x26, 0x65, 0x9f, 0x6c, 0x5f, 0xea, 0x27,
+	0xf1, 0x71, 0xee, 0xc3, 0x12, 0x09, 0xac, 0xd9, 0x43, 0xa2, 0x89, 0x74, 0x4a, 0xce, 0xd2, 0xd7,
+	0xd4, 0x2c, 0x3d, 0x79, 0x91, 0xb3, 0x8f, 0xd3, 0x1b, 0xbe, 0x7f, 0x12, 0x84, 0x9b, 0x00, 0xcf,
+	0xbd, 0xf4, 0x90, 0x4d, 0xe1, 0xf9, 0x5f, 0xa9, 0xc7, 0xfe, 0x92, 0xa5, 0x6f, 0x39, 0xc6, 0x8a,
+	0xfc, 0x4d, 0x72, 0xec, 0xd9, 0xbb, 0x26, 0x2a, 0x64, 0xfa, 0xd5, 0xe6, 0xa5, 0x05, 0xee, 0xb3,
+	0x2a, 0x9d, 0xb6, 0x0f, 0x2d, 0x75, 0x53, 0x55, 0x48, 0x98, 0xf8, 0x0d, 0xd7, 0x43, 0x58, 0xe1,
+	0xa1, 0xe9, 0x19, 0xc9, 0x64, 0x37, 0xcb, 0xc7, 0x57, 0x27, 0xdf, 0xfe, 0x91, 0x01, 0x2b, 0xf2,
+	0x13, 0xb0, 0x53, 0x93, 0x35, 0xea, 0xad, 0xd9, 0x98, 0x42, 0xcf, 0xae, 0xfa, 0x78, 0xae, 0xda,
+	0x46, 0x9e, 0xd0, 0x74, 0xc4, 0x1e, 0x8e, 0x70, 0xd0, 0xc5, 0x41, 0xc7, 0xcb, 0xef, 0xcf, 0xeb,
+	0x30, 0xdf, 0x95, 0xba, 0xf9, 0x53, 0xb4, 0x75, 0x51, 0xd2, 0xe1, 0x37, 0x68, 0x36, 0x73, 0xe8,
+	0x28, 0xe0, 0xf6, 0x6d, 0x9a, 0x35, 0x54, 0x11, 0x57, 0x21, 0xf0, 0x33, 0x58, 0x67, 0x25, 0x9a,
+	0x6f, 0x80, 0xc6, 0xdf, 0x1a, 0x80, 0xca, 0x40, 0x68, 0x07, 0x66, 0x85, 0x83, 0x60, 0x1a, 0x63,
+	0x6d, 0x4d, 0x06, 0xa7, 0xbe, 0xb6, 0xa8, 0x4d, 0xfa, 0xda, 0xc2, 0x82, 0xd9, 0xf0, 0x08, 0xc7,
+	0xb1, 0xd7, 0x65, 0x5e, 0xf2, 0xac, 0x93, 0xb5, 0x49, 0x00, 0xa2, 0xdb, 0x78, 0x15, 0x1e, 0x06,
+	0xd4, 0x69, 0xd3, 0x31, 0xf0, 0x58, 0x4b, 0x94, 0xb8, 0x7d, 0x2c, 0xbd, 0x53, 0x9b, 0x75, 0xa4,
+	0x1e, 0xa2, 0xb3, 0x41, 0xd8, 0xc6, 0xfe, 0x01, 0x27, 0x9e, 0xb7, 0xec, 0x1f, 0x82, 0xb5, 0x8f,
+	0xd3, 0xdd, 0x30, 0x38, 0x35, 0xe9, 0xe8, 0x5a, 0x99, 0xaf, 0xda, 0x6a, 0x4d, 0x0e, 0xc5, 0x57,
+	0x7f, 0x14, 0x87, 0x67, 0xb2, 0xba, 0x90, 0xf0, 0xf8, 0xd5, 0x33, 0x28, 0xfb, 0xd7, 0x75, 0x58,
+	0x50, 0x9e, 0x85, 0xa1, 0xb7, 0x61, 0xbe, 0x2f, 0x01, 0x8f, 0x2b, 0xbe, 0x2a, 0x80, 0xa7, 0x89,
+	0x39, 0x5e, 0x83, 0x39, 0x6e, 0x59, 0x82, 0x83, 0x50, 0x78, 0x32, 0x05, 0x13, 0x2b, 0x43, 0xe4,
+	0xb5, 0x94, 0xfa, 0x31, 0xb5, 0x14, 0x55, 0x22, 0x8d, 0x49, 0x24, 0xa2, 0xb2, 0x71, 0x7a, 0x12,
+	0x36, 0xa2, 0x1d, 0xee, 0x61, 0xcf, 0x50, 0xe8, 0x4d, 0xdd, 0x7b, 0xbb, 0x52, 0x05, 0x79, 0x07,
+	0x5a, 0x32, 0xff, 0x3e, 0x65, 0xd1, 0x24, 0x79, 0x7a, 0x45, 0xbc, 0x74, 0xed, 0x18, 0x7a, 0x05,
+	0x66, 0xe8, 0xdb, 0xbb, 0x4e, 0x62, 0x36, 0x47, 0xbd, 0xce, 0x13, 0x10, 0xd5, 0x93, 0xbe, 0xcf,
+	0xc1, 0xcc, 0xd3, 0xf5, 0x8c, 0xfa, 0x6a, 0x0a, 0xb9, 0x5d, 0x2c, 0xa2, 0xea, 0x5f, 0x22, 0x0a,
+	0x20, 0xfb, 0x23, 0x40, 0x7b, 0xd8, 0x2f, 0x54, 0x51, 0xe9, 0xb9, 0x16, 0x87, 0x5c, 0xbc, 0xca,
+	0x94, 0x7a, 0x46, 0x14, 0x9f, 0x1d, 0x15, 0x57, 0x12, 0xd1, 0x9c, 0x98, 0xfa, 0xae, 0xd6, 0x28,
+	0xbe, 0xab, 0x3d, 0x26, 0x4d, 0x35, 0x84, 0x15, 0x19, 0x67, 0x25, 0x9e, 0xbc, 0x59, 0xaa, 0xe7,
+	0x8a, 0x0b, 0xa0, 0x4c, 0xae, 0x54, 0xd5, 0xdd, 0x81, 0x45, 0xe2, 0xc6, 0x44, 0x79, 0xec, 0x50,
+	0x48, 0x2f, 0x18, 0xe5, 0x57, 0x1f, 0x9f, 0xc2, 0xb9, 0x6c, 0x4e, 0x55, 0xc7, 0x96, 0x64, 0x48,
+	0x44, 0xf6, 0x9d, 0xb7, 0x76, 0xbe, 0x5e, 0xcc, 0x1e, 0x00, 0xed, 0xa6, 0xb1, 0x8f, 0x3e, 0x84,
+	0x06, 0x26, 0x2f, 0x4b, 0x90, 0x95, 0x17, 0x7b, 0x8a, 0x8f, 0x68, 0xac, 0x0b, 0xda, 0x31, 0xbe,
+	0xd2, 0x2e, 0x4c, 0xb3, 0x7c, 0x29, 0xba, 0x30, 0xe6, 0x11, 0x86, 0xb5, 0xa1, 0x1f, 0xcc, 0x91,
+	0x74, 0xa9, 0xab, 0x94, 0x21, 0xd1, 0xbd, 0x64, 0xb0, 0x36, 0xf4, 0x83, 0x1c, 0xc9, 0x75, 0x98,
+	0xee, 0xd1, 0x8c, 0x01, 0x32, 0x4b, 0x95, 0x2b, 0x81, 0x61, 0x5d, 0x33, 0xc2, 0xa7, 0xef, 0xc1,
+	0x5c, 0x2f, 0xeb, 0x4d, 0x50, 0x19, 0x52, 0x88, 0xcf, 0xb2, 0x74, 0x43, 0x1c, 0xcb, 0x13, 0x58,
+	0x1a, 0xd0, 0xa0, 0x29, 0x8f, 0xbc, 0xd0, 0xd6, 0x71, 0x65, 0x78, 0xeb, 0xf2, 0x18, 0x08, 0x8e,
+	0xf8, 0x0e, 0xcc, 0xb8, 0xdd, 0x2e, 0xcd, 0x58, 0x5d, 0x2c, 0x15, 0x90, 0xe4, 0x3a, 0xa6, 0xb5,
+	0x39, 0x6a, 0x38, 0xc7, 0xd4, 0xc3, 0xa9, 0x82, 0x49, 0x5f, 0x5d, 0xb7, 0x36, 0x47, 0x0d, 0x73,
+	0x4c, 0x0f, 0x01, 0xd8, 0x66, 0x29, 0xb2, 0x4b, 0xba, 0x4d, 0x48, 0xf5, 0x6f, 0x6b, 0x6b, 0x34,
+	0x00, 0x47, 0xf8, 0x31, 0x00, 0xd3, 0x03, 0x8a, 0x70, 0x4b, 0x2b, 0x6e, 0x99, 0xc0, 0xcb, 0x63,
+	0x20, 0x38, 0xca, 0xdb, 0x94, 0x6f, 0xc4, 0x9a, 0xa2, 0x8d, 0x71, 0x85, 0x37, 0xeb, 0xe2, 0x88,
+	0xd1, 0x1c, 0x4f, 0x0f, 0xa7, 0x0a, 0x1e, 0x6d, 0xa9, 0xd6, 0xba, 0x38, 0xb6, 0x6c, 0x8a, 0xee,
+	0x43, 0x93, 0xf1, 0xec, 0xb1, 0xdb, 0x43, 0x9b, 0x3a, 0x8e, 0xe4, 0xf5, 0x4f, 0xeb, 0xd2, 0xc8,
+	0xf1, 0x5c, 0x02, 0x8c, 0x61, 0x94, 0xb0, 0x4b, 0x3a, 0x76, 0xc8, 0xb4, 0x6d, 0x8d, 0x06, 0xc8,
+	0xde, 0xa2, 0x2f, 0xf4, 0x44, 0xcc, 0x47, 0xcd, 0xee, 0x9a, 0xb4, 0x1d, 0x39, 0x02, 0xb1, 0xcc,
+	0xf2, 0x00, 0xc7, 0x71, 0x0f, 0x96, 0x7a, 0x52, 0xdc, 0x46, 0xd1, 0x48, 0x67, 0xa6, 0x18, 0xa5,
+	0x5a, 0x17, 0xb4, 0x63, 0x1c, 0xd9, 0x3e, 0xf1, 0xbc, 0xf3, 0x28, 0x0a, 0x59, 0xea, 0x16, 0xb4,
+	0x88, 0xb4, 0x61, 0xd7, 0x3e, 0x2d, 0x16, 0x65, 0x51, 0x4c, 0x86, 0x48, 0x13, 0x5d, 0x59, 0x17,
+	0xb4, 0x63, 0x1c, 0xd1, 0x47, 0xb0, 0x20, 0x23, 0x4a, 0x90, 0x0e, 0x3a, 0x29, 0xda, 0x2c, 0xfd,
+	0xff, 0x78, 0xbe, 0x80, 0x4b, 0xae, 0x1a, 0xbc, 0xdc, 0x0e, 0x63, 0xd9, 0x55, 0x49, 0xe4, 0xd3,
+	0xae, 0x71, 0xac, 0xad, 0xcd, 0x51, 0xc3, 0x7c, 0x05, 0x0f, 0xec, 0x4e, 0xc9, 0xbb, 0x2f, 0x2d,
+	0xb2, 0xa5, 0x98, 0x67, 0xdd, 0x3a, 0x97, 0xc7, 0x40, 0xf0, 0xa5, 0xbe, 0x0d, 0x6b, 0x3d, 0xea,
+	0x0f, 0x53, 0x6f, 0x4c, 0x06, 0x91, 0x0d, 0xcd, 0x38, 0xe4, 0x63, 0xdc, 0x69, 0x86, 0xbc, 0x14,
+	0x44, 0x9d, 0x0c, 0xf9, 0xa8, 0x48, 0x61, 0x1f, 0x16, 0xbb, 0xf2, 0x91, 0x48, 0x90, 0xee, 0x66,
+	0x2f, 0x98, 0x7f, 0x8d, 0x3f, 0xb1, 0xf3, 0xbb, 0x69, 0x58, 0x29, 0x78, 0xcb, 0xf4, 0x9e, 0xbd,
+	0x07, 0xb3, 0xa2, 0xc4, 0x93, 0x1d, 0xfa, 0x11, 0x65, 0x33, 0xeb, 0xd2, 0xc8, 0xf1, 0xdc, 0x4a,
+	0x0e, 0xb2, 0x8a, 0x51, 0x7e, 0xbb, 0x8c, 0xaa, 0x4f, 0x59, 0x97, 0xc7, 0x40, 0x70, 0x94, 0xff,
+	0x07, 0xcd, 0x43, 0x51, 0xe7, 0xc9, 0x8e, 0x7c, 0xb1, 0x96, 0x64, 0x99, 0xe5, 0x01, 0x3e, 0xff,
+	0x06, 0xd4, 0x0f, 0xbc, 0xa0, 0x9b, 0x1d, 0x05, 0x5d, 0xc5, 0xc7, 0xda, 0xd0, 0x0f, 0xe6, 0xe7,
+	0xb3, 0x27, 0xa5, 0xb5, 0x65, 0x8b, 0x51, 0xc2, 0x74, 0x41, 0x3b, 0xc6, 0x11, 0x3d, 0x80, 0xc5,
+	0x9e, 0x52, 0x39, 0x90, 0x0d, 0x76, 0xb9, 0x14, 0x62, 0x5d, 0x1c, 0x31, 0x9a, 0xdd, 0xe8, 0xf3,
+	0xcc, 0x60, 0xb3, 0xf4, 0x32, 0xb2, 0xb5, 0x69, 0x5a, 0x25, 0x05, 0x6e, 0xbd, 0x30, 0x16, 0x86,
+	0x23, 0x76, 0xc1, 0x1c, 0x94, 0xf2, 0xab, 0xdc, 0x65, 0xb8, 0x7c, 0x6c, 0x2e, 0xd8, 0xb2, 0xc7,
+	0x81, 0x64, 0x3e, 0x4d, 0xe3, 0x39, 0xc9, 0x92, 0x66, 0x72, 0xd1, 0xa5, 0x5c, 0xad, 0x0d, 0xfd,
+	0x20, 0xc3, 0x71, 0xd5, 0x20, 0x92, 0x39, 0x94, 0xfe, 0x99, 0x87, 0x74, 0xff, 0x12, 0x2c, 0x4a,
+	0x46, 0xf7, 0x57, 0xbe, 0x1d, 0x1f, 0xd6, 0x0b, 0x87, 0xa3, 0x9d, 0xc6, 0xd8, 0xed, 0xd3, 0x23,
+	0xf2, 0x10, 0x90, 0x2c, 0x7f, 0x36, 0x52, 0x59, 0x0b, 0xae, 0x1a, 0x3b, 0xff, 0x32, 0x60, 0x79,
+	0x3f, 0x3c, 0xc2, 0x71, 0x20, 0x7b, 0xbc, 0x0f, 0xe8, 0xe5, 0xa4, 0x06, 0xce, 0xa3, 0xfd, 0xc5,
+	0x4b, 0xa5, 0x91, 0x42, 0x50, 0xf5, 0x08, 0xce, 0xf5, 0xd4, 0xff, 0x80, 0x69, 0x9c, 0x2a, 0xf9,
+	0x6f, 0x6a, 0xd6, 0xe6, 0xa8, 0x61, 0x8e, 0xf1, 0x43, 0x8a, 0xf1, 0x46, 0x14, 0xf9, 0x5e, 0xc7,
+	0x65, 0xff, 0x5f, 0x5b, 0x95, 0x2e, 0xc8, 0x3c, 0x8c, 0xb0, 0xce, 0x17, 0xbb, 0x19, 0x86, 0x9b,
+	0x57, 0xe1, 0xe5, 0x4e, 0xd8, 0xdf, 0x3e, 0x1c, 0xb8, 0xcf, 0xb1, 0xb7, 0x1d, 0xb9, 0x6e, 0xb2,
+	0xdd, 0x49, 0xf0, 0x36, 0x0f, 0x48, 0x98, 0x0d, 0x88, 0x87, 0xdb, 0x6e, 0xe4, 0x7d, 0xc6, 0xfe,
+	0x1e, 0xfb, 0x74, 0x9a, 0xfe, 0xbc, 0xfe, 0x9f, 0x01, 0x00, 0x1f, 0xc2, 0x90, 0x7a, 0x3e, 0x3b,
+	0x00, 0x00,
 }
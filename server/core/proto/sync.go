@@ -0,0 +1,39 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proto
+
+// SyncRecord is the change of an instance replicated to the service
+// centers in the other datacenters, Deleted marks the tombstone
+type SyncRecord struct {
+	Seq           int64                 `protobuf:"varint,1,opt,name=seq" json:"seq"`
+	Revision      int64                 `protobuf:"varint,2,opt,name=revision" json:"revision"`
+	DomainProject string                `protobuf:"bytes,3,opt,name=domainProject" json:"domainProject"`
+	Service       *MicroServiceKey      `protobuf:"bytes,4,opt,name=service" json:"service,omitempty"`
+	Instance      *MicroServiceInstance `protobuf:"bytes,5,opt,name=instance" json:"instance,omitempty"`
+	Deleted       bool                  `protobuf:"varint,6,opt,name=deleted" json:"deleted,omitempty"`
+}
+
+// SyncChangesResponse returns the records after the requested sequence
+// of the epoch, if Full is true, the records are the snapshot of all the
+// instances and replace the ones received before
+type SyncChangesResponse struct {
+	Response   *Response     `protobuf:"bytes,1,opt,name=response" json:"response,omitempty"`
+	Datacenter string        `protobuf:"bytes,2,opt,name=datacenter" json:"datacenter"`
+	Epoch      string        `protobuf:"bytes,3,opt,name=epoch" json:"epoch"`
+	Seq        int64         `protobuf:"varint,4,opt,name=seq" json:"seq"`
+	Full       bool          `protobuf:"varint,5,opt,name=full" json:"full,omitempty"`
+	Records    []*SyncRecord `protobuf:"bytes,6,rep,name=records" json:"records,omitempty"`
+}
@@ -33,6 +33,15 @@ type ServerConfig struct {
 	ReadTimeout       string `json:"readTimeout"`
 	IdleTimeout       string `json:"idleTimeout"`
 	WriteTimeout      string `json:"writeTimeout"`
+	GraceTimeout      string `json:"graceTimeout"`
+	KeepAliveTimeout  string `json:"keepAliveTimeout"`
+
+	// EnableHTTP2 serves HTTP/2 over TLS, and over cleartext (h2c) if
+	// EnableH2C is set too and TLS is disabled
+	EnableHTTP2          bool  `json:"enableHttp2"`
+	EnableH2C            bool  `json:"enableH2c"`
+	MaxConcurrentStreams int64 `json:"maxConcurrentStreams"`
+	MaxConnections       int64 `json:"maxConnections"`
 
 	LimitTTLUnit     string `json:"limitTTLUnit"`
 	LimitConnections int64  `json:"limitConnections"`
@@ -50,6 +59,8 @@ type ServerConfig struct {
 	EnablePProf bool `json:"enablePProf"`
 	EnableCache bool `json:"enableCache"`
 
+	EnableWatchCompression bool `json:"enableWatchCompression"`
+
 	LogRotateSize  int64  `json:"-"`
 	LogBackupCount int64  `json:"-"`
 	LogFilePath    string `json:"-"`
@@ -61,6 +72,15 @@ type ServerConfig struct {
 	Plugins    util.JSONObject `json:"plugins"`
 
 	SelfRegister bool `json:"selfRegister"`
+
+	// RecordSpiffeId records the SPIFFE ID of the caller on the
+	// registered instances
+	RecordSpiffeId bool `json:"recordSpiffeId"`
+
+	// InstanceConflictPolicy is the policy when an instance registers with
+	// the id of an existing instance, the services override it by the
+	// PROP_INSTANCE_CONFLICT_POLICY property
+	InstanceConflictPolicy string `json:"instanceConflictPolicy"`
 }
 
 type ServerInformation struct {
@@ -0,0 +1,45 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proto
+
+// WatchEvent is the instance event retained in the replay buffer
+type WatchEvent struct {
+	Revision  int64                 `protobuf:"varint,1,opt,name=revision" json:"revision"`
+	Timestamp string                `protobuf:"bytes,2,opt,name=timestamp" json:"timestamp,omitempty"`
+	Action    string                `protobuf:"bytes,3,opt,name=action" json:"action,omitempty"`
+	Key       *MicroServiceKey      `protobuf:"bytes,4,opt,name=key" json:"key,omitempty"`
+	Instance  *MicroServiceInstance `protobuf:"bytes,5,opt,name=instance" json:"instance,omitempty"`
+}
+
+func (m *WatchEvent) ToResponse() *WatchInstanceResponse {
+	return &WatchInstanceResponse{
+		Action:   m.Action,
+		Key:      m.Key,
+		Instance: m.Instance,
+	}
+}
+
+type GetWatchEventsRequest struct {
+	SelfServiceId string `protobuf:"bytes,1,opt,name=selfServiceId" json:"selfServiceId,omitempty"`
+	Since         int64  `protobuf:"varint,2,opt,name=since" json:"since,omitempty"`
+}
+
+type GetWatchEventsResponse struct {
+	Response *Response `protobuf:"bytes,1,opt,name=response" json:"response,omitempty"`
+	// Revision is the latest revision of events, use it as 'since' in the next polling
+	Revision int64         `protobuf:"varint,2,opt,name=revision" json:"revision"`
+	Events   []*WatchEvent `protobuf:"bytes,3,rep,name=events" json:"events,omitempty"`
+}
@@ -0,0 +1,51 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proto
+
+// the resources can be watched besides the instances
+const (
+	WATCH_RESOURCE_SCHEMA     = "schema"
+	WATCH_RESOURCE_TAG        = "tag"
+	WATCH_RESOURCE_RULE       = "rule"
+	WATCH_RESOURCE_DEPENDENCY = "dependency"
+)
+
+var WatchResources = []string{
+	WATCH_RESOURCE_SCHEMA,
+	WATCH_RESOURCE_TAG,
+	WATCH_RESOURCE_RULE,
+	WATCH_RESOURCE_DEPENDENCY,
+}
+
+type WatchResourceRequest struct {
+	SelfServiceId string   `protobuf:"bytes,1,opt,name=selfServiceId" json:"selfServiceId,omitempty"`
+	ProviderIds   []string `protobuf:"bytes,2,rep,name=providerIds" json:"providerIds,omitempty"`
+	// Resources is the resources to watch, empty means all
+	Resources []string `protobuf:"bytes,3,rep,name=resources" json:"resources,omitempty"`
+}
+
+// WatchResourceResponse is the change event of the provider's resource,
+// Value is the schema summary, the tags, the rule or the consumer keys
+// of the dependency rule
+type WatchResourceResponse struct {
+	Action    string `protobuf:"bytes,1,opt,name=action" json:"action,omitempty"`
+	Resource  string `protobuf:"bytes,2,opt,name=resource" json:"resource,omitempty"`
+	ServiceId string `protobuf:"bytes,3,opt,name=serviceId" json:"serviceId,omitempty"`
+	// Id is the schemaId or the ruleId
+	Id       string      `protobuf:"bytes,4,opt,name=id" json:"id,omitempty"`
+	Revision int64       `protobuf:"varint,5,opt,name=revision" json:"revision,omitempty"`
+	Value    interface{} `json:"value,omitempty"`
+}
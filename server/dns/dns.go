@@ -0,0 +1,60 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package dns
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	"github.com/apache/servicecomb-service-center/server/core"
+	"github.com/astaxie/beego"
+	"strings"
+)
+
+const (
+	DEFAULT_DOMAIN = "sc"
+	// the label between the app id and the domain, e.g.
+	// 'myservice.myapp.service.sc'
+	SERVICE_LABEL = "service"
+	// the label of the names encoded from the ip addresses, e.g.
+	// '10-0-0-1.addr.sc'
+	ADDR_LABEL = "addr"
+)
+
+func init() {
+	cfg := LoadConfig()
+	if len(cfg.ListenAddr) == 0 {
+		return
+	}
+	gopool.Go(NewServer(cfg).Run)
+}
+
+type Config struct {
+	// ListenAddr is the udp and tcp address to serve, disabled if empty
+	ListenAddr string
+	// Domain is the top level domain of the records
+	Domain string
+	// DomainProject is the domain project of service center to resolve
+	DomainProject string
+}
+
+func LoadConfig() Config {
+	return Config{
+		ListenAddr: beego.AppConfig.DefaultString("dns_listen_addr", ""),
+		Domain: strings.ToLower(strings.Trim(
+			beego.AppConfig.DefaultString("dns_domain", DEFAULT_DOMAIN), ".")),
+		DomainProject: beego.AppConfig.DefaultString("dns_domain_project", core.REGISTRY_DOMAIN_PROJECT),
+	}
+}
@@ -0,0 +1,150 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package dns
+
+import (
+	"fmt"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"golang.org/x/net/context"
+	"golang.org/x/net/dns/dnsmessage"
+	"net"
+	"testing"
+)
+
+func query(t *testing.T, s *Server, name string, qtype dnsmessage.Type, maxSize int) *dnsmessage.Message {
+	n, err := dnsmessage.NewName(name)
+	if err != nil {
+		t.Fatalf("TestServer_Handle failed, %v", err)
+	}
+	req := dnsmessage.Message{
+		Header: dnsmessage.Header{ID: 1, RecursionDesired: true},
+		Questions: []dnsmessage.Question{
+			{Name: n, Type: qtype, Class: dnsmessage.ClassINET},
+		},
+	}
+	b, err := req.Pack()
+	if err != nil {
+		t.Fatalf("TestServer_Handle failed, %v", err)
+	}
+	b, err = s.Handle(context.Background(), b, maxSize)
+	if err != nil {
+		t.Fatalf("TestServer_Handle failed, %v", err)
+	}
+	var resp dnsmessage.Message
+	if err := resp.Unpack(b); err != nil {
+		t.Fatalf("TestServer_Handle failed, %v", err)
+	}
+	if resp.ID != 1 || !resp.Response || !resp.Authoritative {
+		t.Fatalf("TestServer_Handle failed, %v", resp.Header)
+	}
+	return &resp
+}
+
+func TestServer_Handle(t *testing.T) {
+	s := &Server{
+		Cfg: Config{Domain: "sc"},
+		Lookup: func(ctx context.Context, app, service string) ([]*Endpoint, error) {
+			switch {
+			case app == "myapp" && service == "myservice":
+				return []*Endpoint{
+					{IP: net.ParseIP("10.0.0.1"), Host: "10.0.0.1", Port: 8080, TTL: 120},
+					{IP: net.ParseIP("fe80::1"), Host: "fe80::1", Port: 8081, TTL: 60},
+					{Host: "svc.local", Port: 8082, TTL: 30},
+				}, nil
+			case app == "err":
+				return nil, fmt.Errorf("error")
+			default:
+				return nil, nil
+			}
+		},
+	}
+
+	resp := query(t, s, "MyService.myapp.service.sc.", dnsmessage.TypeA, maxUDPSize)
+	if resp.RCode != dnsmessage.RCodeSuccess || len(resp.Answers) != 1 {
+		t.Fatalf("TestServer_Handle failed, %v", resp)
+	}
+	a := resp.Answers[0]
+	if a.Header.TTL != 120 || net.IP(a.Body.(*dnsmessage.AResource).A[:]).String() != "10.0.0.1" {
+		t.Fatalf("TestServer_Handle failed, %v", a)
+	}
+
+	resp = query(t, s, "myservice.myapp.service.sc.", dnsmessage.TypeAAAA, maxUDPSize)
+	if resp.RCode != dnsmessage.RCodeSuccess || len(resp.Answers) != 1 || resp.Answers[0].Header.TTL != 60 {
+		t.Fatalf("TestServer_Handle failed, %v", resp)
+	}
+
+	resp = query(t, s, "myservice.myapp.service.sc.", dnsmessage.TypeSRV, maxUDPSize)
+	if resp.RCode != dnsmessage.RCodeSuccess || len(resp.Answers) != 3 || len(resp.Additionals) != 2 {
+		t.Fatalf("TestServer_Handle failed, %v", resp)
+	}
+	srv := resp.Answers[0].Body.(*dnsmessage.SRVResource)
+	if srv.Port != 8080 || srv.Target.String() != "10-0-0-1.addr.sc." {
+		t.Fatalf("TestServer_Handle failed, %v", srv)
+	}
+	srv = resp.Answers[2].Body.(*dnsmessage.SRVResource)
+	if srv.Port != 8082 || srv.Target.String() != "svc.local." {
+		t.Fatalf("TestServer_Handle failed, %v", srv)
+	}
+
+	resp = query(t, s, "10-0-0-1.addr.sc.", dnsmessage.TypeA, maxUDPSize)
+	if resp.RCode != dnsmessage.RCodeSuccess || len(resp.Answers) != 1 {
+		t.Fatalf("TestServer_Handle failed, %v", resp)
+	}
+	resp = query(t, s, "fe80--1.addr.sc.", dnsmessage.TypeAAAA, maxUDPSize)
+	if resp.RCode != dnsmessage.RCodeSuccess || len(resp.Answers) != 1 {
+		t.Fatalf("TestServer_Handle failed, %v", resp)
+	}
+
+	resp = query(t, s, "none.myapp.service.sc.", dnsmessage.TypeA, maxUDPSize)
+	if resp.RCode != dnsmessage.RCodeNameError {
+		t.Fatalf("TestServer_Handle failed, %v", resp)
+	}
+	resp = query(t, s, "myservice.err.service.sc.", dnsmessage.TypeA, maxUDPSize)
+	if resp.RCode != dnsmessage.RCodeServerFailure {
+		t.Fatalf("TestServer_Handle failed, %v", resp)
+	}
+	resp = query(t, s, "example.com.", dnsmessage.TypeA, maxUDPSize)
+	if resp.RCode != dnsmessage.RCodeRefused {
+		t.Fatalf("TestServer_Handle failed, %v", resp)
+	}
+
+	resp = query(t, s, "myservice.myapp.service.sc.", dnsmessage.TypeSRV, 64)
+	if !resp.Truncated || len(resp.Answers) != 0 {
+		t.Fatalf("TestServer_Handle failed, %v", resp)
+	}
+}
+
+func TestToEndpoint(t *testing.T) {
+	ep := toEndpoint(&pb.MicroServiceInstance{
+		Endpoints:   []string{"rest://10.0.0.1:8080?sslEnabled=true"},
+		HealthCheck: &pb.HealthCheck{Mode: pb.CHECK_BY_HEARTBEAT, Interval: 30, Times: 3},
+	})
+	if ep == nil || ep.IP.String() != "10.0.0.1" || ep.Port != 8080 || ep.TTL != 120 {
+		t.Fatalf("TestToEndpoint failed, %v", ep)
+	}
+	ep = toEndpoint(&pb.MicroServiceInstance{Endpoints: []string{"rest://[::1]:8080"}})
+	if ep == nil || ep.IP.String() != "::1" || ep.TTL != DEFAULT_TTL {
+		t.Fatalf("TestToEndpoint failed, %v", ep)
+	}
+	ep = toEndpoint(&pb.MicroServiceInstance{Endpoints: []string{"rest://svc.local:8080"}})
+	if ep == nil || ep.IP != nil || ep.Host != "svc.local" {
+		t.Fatalf("TestToEndpoint failed, %v", ep)
+	}
+	if ep = toEndpoint(&pb.MicroServiceInstance{}); ep != nil {
+		t.Fatalf("TestToEndpoint failed, %v", ep)
+	}
+}
@@ -0,0 +1,107 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package dns
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	serviceUtil "github.com/apache/servicecomb-service-center/server/service/util"
+	"golang.org/x/net/context"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// the ttl of the records if the instance has no health check
+const DEFAULT_TTL = 30
+
+// Endpoint is the address of an instance resolved
+type Endpoint struct {
+	// IP is nil if the instance endpoint is a host name
+	IP   net.IP
+	Host string
+	Port uint16
+	// TTL is the lease of the instance in seconds
+	TTL uint32
+}
+
+// Lookup returns the endpoints of the UP instances of the service
+type Lookup func(ctx context.Context, app, service string) ([]*Endpoint, error)
+
+// CacheLookup resolves the services in domainProject from the registry cache
+func CacheLookup(domainProject string) Lookup {
+	return func(ctx context.Context, app, service string) ([]*Endpoint, error) {
+		ctx = util.SetContext(ctx, serviceUtil.CTX_CACHEONLY, "1")
+		services, err := serviceUtil.GetServicesByDomainProject(ctx, domainProject)
+		if err != nil {
+			return nil, err
+		}
+		var endpoints []*Endpoint
+		for _, s := range services {
+			// the dns names are case insensitive
+			if !strings.EqualFold(s.AppId, app) || !strings.EqualFold(s.ServiceName, service) {
+				continue
+			}
+			instances, err := serviceUtil.GetAllInstancesOfOneService(ctx, domainProject, s.ServiceId)
+			if err != nil {
+				return nil, err
+			}
+			for _, instance := range instances {
+				if instance.Status != pb.MSI_UP {
+					continue
+				}
+				if ep := toEndpoint(instance); ep != nil {
+					endpoints = append(endpoints, ep)
+				}
+			}
+		}
+		return endpoints, nil
+	}
+}
+
+// toEndpoint returns nil if the instance has no valid endpoint
+func toEndpoint(instance *pb.MicroServiceInstance) *Endpoint {
+	if len(instance.Endpoints) == 0 {
+		return nil
+	}
+	u, err := url.Parse(instance.Endpoints[0])
+	if err != nil {
+		return nil
+	}
+	ipPort := util.ParseIpPort(u.Host)
+	if ipPort.Port == 0 {
+		return nil
+	}
+	host := strings.Trim(ipPort.IP, "[]")
+	return &Endpoint{
+		IP:   net.ParseIP(host),
+		Host: host,
+		Port: ipPort.Port,
+		TTL:  leaseTTL(instance.HealthCheck),
+	}
+}
+
+func leaseTTL(hc *pb.HealthCheck) uint32 {
+	if hc == nil || hc.Interval <= 0 {
+		return DEFAULT_TTL
+	}
+	times := hc.Times
+	if times < 0 {
+		times = 0
+	}
+	return uint32(hc.Interval * (times + 1))
+}
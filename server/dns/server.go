@@ -0,0 +1,300 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package dns
+
+import (
+	"encoding/binary"
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"golang.org/x/net/context"
+	"golang.org/x/net/dns/dnsmessage"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	// the max size of the udp messages without EDNS
+	maxUDPSize = 512
+	maxTCPSize = 65535
+	tcpTimeout = 10 * time.Second
+)
+
+// Server serves the A, AAAA and SRV records of the UP instances in
+// service center, the names are formatted as
+// '{service}.{app}.service.{domain}'
+type Server struct {
+	Cfg    Config
+	Lookup Lookup
+}
+
+func (s *Server) Run(ctx context.Context) {
+	pc, err := net.ListenPacket("udp", s.Cfg.ListenAddr)
+	if err != nil {
+		log.Errorf(err, "dns server listen udp %s failed", s.Cfg.ListenAddr)
+		return
+	}
+	ls, err := net.Listen("tcp", s.Cfg.ListenAddr)
+	if err != nil {
+		pc.Close()
+		log.Errorf(err, "dns server listen tcp %s failed", s.Cfg.ListenAddr)
+		return
+	}
+	log.Infof("dns server listen on %s, domain: %s", s.Cfg.ListenAddr, s.Cfg.Domain)
+
+	gopool.Go(func(ctx context.Context) {
+		s.serveUDP(ctx, pc)
+	})
+	gopool.Go(func(ctx context.Context) {
+		s.serveTCP(ctx, ls)
+	})
+
+	<-ctx.Done()
+	pc.Close()
+	ls.Close()
+}
+
+func (s *Server) serveUDP(ctx context.Context, pc net.PacketConn) {
+	buf := make([]byte, maxUDPSize)
+	for {
+		n, addr, err := pc.ReadFrom(buf)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Errorf(err, "dns server read udp failed")
+			}
+			return
+		}
+		resp, err := s.Handle(ctx, buf[:n], maxUDPSize)
+		if err != nil {
+			log.Debugf("dns server handle the query from %s failed, %v", addr, err)
+			continue
+		}
+		if _, err := pc.WriteTo(resp, addr); err != nil {
+			log.Errorf(err, "dns server write the response to %s failed", addr)
+		}
+	}
+}
+
+func (s *Server) serveTCP(ctx context.Context, ls net.Listener) {
+	for {
+		conn, err := ls.Accept()
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Errorf(err, "dns server accept tcp failed")
+			}
+			return
+		}
+		gopool.Go(func(ctx context.Context) {
+			s.handleConn(ctx, conn)
+		})
+	}
+}
+
+func (s *Server) handleConn(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+	var l [2]byte
+	for {
+		conn.SetDeadline(time.Now().Add(tcpTimeout))
+		if _, err := io.ReadFull(conn, l[:]); err != nil {
+			return
+		}
+		req := make([]byte, binary.BigEndian.Uint16(l[:]))
+		if _, err := io.ReadFull(conn, req); err != nil {
+			return
+		}
+		resp, err := s.Handle(ctx, req, maxTCPSize)
+		if err != nil {
+			log.Debugf("dns server handle the query from %s failed, %v", conn.RemoteAddr(), err)
+			return
+		}
+		binary.BigEndian.PutUint16(l[:], uint16(len(resp)))
+		if _, err := conn.Write(append(l[:], resp...)); err != nil {
+			return
+		}
+	}
+}
+
+// Handle answers the query req, the response is truncated if it is
+// larger than maxSize
+func (s *Server) Handle(ctx context.Context, req []byte, maxSize int) ([]byte, error) {
+	var p dnsmessage.Parser
+	h, err := p.Start(req)
+	if err != nil {
+		return nil, err
+	}
+	q, err := p.Question()
+	if err != nil {
+		return nil, err
+	}
+
+	header := dnsmessage.Header{
+		ID:               h.ID,
+		Response:         true,
+		OpCode:           h.OpCode,
+		Authoritative:    true,
+		RecursionDesired: h.RecursionDesired,
+	}
+	if h.Response || h.OpCode != 0 {
+		header.RCode = dnsmessage.RCodeNotImplemented
+		return build(header, q, nil, nil)
+	}
+
+	answers, additionals, rcode := s.resolve(ctx, q)
+	header.RCode = rcode
+	resp, err := build(header, q, answers, additionals)
+	if err != nil || len(resp) <= maxSize {
+		return resp, err
+	}
+	header.Truncated = true
+	return build(header, q, nil, nil)
+}
+
+func (s *Server) resolve(ctx context.Context, q dnsmessage.Question) (answers, additionals []dnsmessage.Resource, rcode dnsmessage.RCode) {
+	name := strings.ToLower(strings.TrimSuffix(q.Name.String(), "."))
+	suffix := "." + s.Cfg.Domain
+	if !strings.HasSuffix(name, suffix) {
+		return nil, nil, dnsmessage.RCodeRefused
+	}
+	labels := strings.Split(strings.TrimSuffix(name, suffix), ".")
+	n := len(labels)
+
+	switch {
+	case n == 2 && labels[1] == ADDR_LABEL:
+		ip := decodeAddr(labels[0])
+		if ip == nil {
+			return nil, nil, dnsmessage.RCodeNameError
+		}
+		if rr, ok := addrResource(q.Name, q.Type, ip, DEFAULT_TTL); ok {
+			answers = append(answers, rr)
+		}
+		return answers, nil, dnsmessage.RCodeSuccess
+	case n >= 3 && labels[n-1] == SERVICE_LABEL:
+		app, service := labels[n-2], strings.Join(labels[:n-2], ".")
+		endpoints, err := s.Lookup(ctx, app, service)
+		if err != nil {
+			log.Errorf(err, "dns server lookup %s failed", name)
+			return nil, nil, dnsmessage.RCodeServerFailure
+		}
+		if len(endpoints) == 0 {
+			return nil, nil, dnsmessage.RCodeNameError
+		}
+		for _, ep := range endpoints {
+			switch q.Type {
+			case dnsmessage.TypeSRV:
+				target := s.target(ep)
+				answers = append(answers, dnsmessage.Resource{
+					Header: resourceHeader(q.Name, dnsmessage.TypeSRV, ep.TTL),
+					Body: &dnsmessage.SRVResource{
+						Priority: 1,
+						Weight:   1,
+						Port:     ep.Port,
+						Target:   target,
+					},
+				})
+				if ep.IP == nil {
+					continue
+				}
+				t := dnsmessage.TypeA
+				if ep.IP.To4() == nil {
+					t = dnsmessage.TypeAAAA
+				}
+				if rr, ok := addrResource(target, t, ep.IP, ep.TTL); ok {
+					additionals = append(additionals, rr)
+				}
+			default:
+				if ep.IP == nil {
+					continue
+				}
+				if rr, ok := addrResource(q.Name, q.Type, ep.IP, ep.TTL); ok {
+					answers = append(answers, rr)
+				}
+			}
+		}
+		return answers, additionals, dnsmessage.RCodeSuccess
+	default:
+		return nil, nil, dnsmessage.RCodeNameError
+	}
+}
+
+// target returns the name resolved to the endpoint
+func (s *Server) target(ep *Endpoint) dnsmessage.Name {
+	host := ep.Host
+	if ep.IP != nil {
+		host = encodeAddr(ep.IP) + "." + ADDR_LABEL + "." + s.Cfg.Domain
+	}
+	name, _ := dnsmessage.NewName(host + ".")
+	return name
+}
+
+// encodeAddr converts the ip to a dns label, e.g. '10.0.0.1' to
+// '10-0-0-1' and 'fe80::1' to 'fe80--1'
+func encodeAddr(ip net.IP) string {
+	if ip4 := ip.To4(); ip4 != nil {
+		return strings.Replace(ip4.String(), ".", "-", -1)
+	}
+	return strings.Replace(ip.String(), ":", "-", -1)
+}
+
+func decodeAddr(label string) net.IP {
+	if ip := net.ParseIP(strings.Replace(label, "-", ".", -1)); ip != nil {
+		return ip
+	}
+	return net.ParseIP(strings.Replace(label, "-", ":", -1))
+}
+
+func resourceHeader(name dnsmessage.Name, t dnsmessage.Type, ttl uint32) dnsmessage.ResourceHeader {
+	return dnsmessage.ResourceHeader{
+		Name:  name,
+		Type:  t,
+		Class: dnsmessage.ClassINET,
+		TTL:   ttl,
+	}
+}
+
+// addrResource returns false if the type of the ip does not match t
+func addrResource(name dnsmessage.Name, t dnsmessage.Type, ip net.IP, ttl uint32) (dnsmessage.Resource, bool) {
+	switch ip4 := ip.To4(); {
+	case t == dnsmessage.TypeA && ip4 != nil:
+		rr := &dnsmessage.AResource{}
+		copy(rr.A[:], ip4)
+		return dnsmessage.Resource{Header: resourceHeader(name, t, ttl), Body: rr}, true
+	case t == dnsmessage.TypeAAAA && ip4 == nil:
+		rr := &dnsmessage.AAAAResource{}
+		copy(rr.AAAA[:], ip.To16())
+		return dnsmessage.Resource{Header: resourceHeader(name, t, ttl), Body: rr}, true
+	default:
+		return dnsmessage.Resource{}, false
+	}
+}
+
+func build(h dnsmessage.Header, q dnsmessage.Question, answers, additionals []dnsmessage.Resource) ([]byte, error) {
+	msg := dnsmessage.Message{
+		Header:      h,
+		Questions:   []dnsmessage.Question{q},
+		Answers:     answers,
+		Additionals: additionals,
+	}
+	return msg.Pack()
+}
+
+func NewServer(cfg Config) *Server {
+	return &Server{
+		Cfg:    cfg,
+		Lookup: CacheLookup(cfg.DomainProject),
+	}
+}
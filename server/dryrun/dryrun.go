@@ -0,0 +1,60 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package dryrun
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/chain"
+	roa "github.com/apache/servicecomb-service-center/pkg/rest"
+	"sync"
+)
+
+const QUERY_DRYRUN = "dryRun"
+
+var (
+	lock      sync.RWMutex
+	supported = map[string]struct{}{}
+)
+
+func init() {
+	Support(roa.HTTP_METHOD_DELETE, "/v4/:project/registry/microservices")
+	Support(roa.HTTP_METHOD_DELETE, "/v4/:project/registry/microservices/:serviceId")
+	Support(roa.HTTP_METHOD_DELETE, "/v4.1/:project/registry/microservices")
+	Support(roa.HTTP_METHOD_DELETE, "/v4.1/:project/registry/microservices/:serviceId")
+	Support(roa.HTTP_METHOD_POST, "/v4/:project/registry/microservices/:serviceId/rules")
+	Support(roa.HTTP_METHOD_DELETE, "/v4/:project/admin/domains/:domain")
+	Support(roa.HTTP_METHOD_DELETE, "/v4/:project/admin/domains/:domain/projects/:name")
+	Support(roa.HTTP_METHOD_POST, "/v4/:project/admin/dump/import")
+	Support(roa.HTTP_METHOD_POST, "/v4/:project/admin/import")
+	Support(roa.HTTP_METHOD_POST, "/v4/:project/admin/gc/orphans")
+	chain.RegisterHandler(roa.SERVER_CHAIN_NAME, &DryRunHandler{})
+}
+
+// Support enables the dry run of the API, the API must keep all the state
+// it changes in the registry, or skip changing the other state if
+// backend.IsDryRun
+func Support(method, pattern string) {
+	lock.Lock()
+	supported[method+" "+pattern] = struct{}{}
+	lock.Unlock()
+}
+
+func Supported(method, pattern string) bool {
+	lock.RLock()
+	_, ok := supported[method+" "+pattern]
+	lock.RUnlock()
+	return ok
+}
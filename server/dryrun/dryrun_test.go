@@ -0,0 +1,84 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package dryrun
+
+import (
+	"github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/core/backend"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	"golang.org/x/net/context"
+	"testing"
+)
+
+func TestDryRun(t *testing.T) {
+	ctx := context.Background()
+	root := core.GenerateServiceTagKey("dryrun_test/dryrun_test", "")
+	for _, id := range []string{"a", "b"} {
+		_, err := backend.Registry().Do(ctx, registry.PUT,
+			registry.WithStrKey(root+id), registry.WithStrValue(`{}`))
+		if err != nil {
+			t.Fatalf("TestDryRun failed, %v", err)
+		}
+	}
+
+	dr := backend.NewDryRun()
+	dctx := backend.WithDryRun(ctx, dr)
+	err := backend.BatchCommit(dctx, []registry.PluginOp{
+		registry.OpDel(registry.WithStrKey(root), registry.WithPrefix()),
+		registry.OpPut(registry.WithStrKey(root+"c"), registry.WithStrValue(`{}`)),
+	})
+	if err != nil {
+		t.Fatalf("TestDryRun failed, %v", err)
+	}
+	ok, err := backend.Registry().PutNoOverride(dctx, registry.WithStrKey(root+"a"), registry.WithStrValue(`{}`))
+	if err != nil || ok {
+		t.Fatalf("TestDryRun failed, %v, %v", ok, err)
+	}
+
+	changes := dr.Changes()
+	expected := []backend.Change{
+		{Action: backend.CHANGE_DELETE, Key: root + "a"},
+		{Action: backend.CHANGE_DELETE, Key: root + "b"},
+		{Action: backend.CHANGE_PUT, Key: root + "c"},
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("TestDryRun failed, %v", changes)
+	}
+	for i := range expected {
+		if changes[i] != expected[i] {
+			t.Fatalf("TestDryRun failed, %v", changes)
+		}
+	}
+
+	resp, err := backend.Registry().Do(ctx, registry.GET,
+		registry.WithStrKey(root), registry.WithPrefix(), registry.WithCountOnly())
+	if err != nil || resp.Count != 2 {
+		t.Fatalf("TestDryRun failed, the registry is changed, %v", err)
+	}
+}
+
+func TestSupported(t *testing.T) {
+	if !Supported("DELETE", "/v4/:project/admin/domains/:domain") {
+		t.Fatalf("TestSupported failed")
+	}
+	if !Supported("POST", "/v4/:project/registry/microservices/:serviceId/rules") {
+		t.Fatalf("TestSupported failed")
+	}
+	if Supported("PUT", "/v4/:project/admin/maintenance") {
+		t.Fatalf("TestSupported failed")
+	}
+}
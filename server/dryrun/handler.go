@@ -0,0 +1,93 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package dryrun
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/apache/servicecomb-service-center/pkg/chain"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/core/backend"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/rest/controller"
+	"net/http"
+)
+
+// Response wraps the response of the API in dry run, the changes are the
+// keys would be written or deleted
+type Response struct {
+	DryRun  bool             `json:"dryRun"`
+	Changes []backend.Change `json:"changes"`
+	Result  json.RawMessage  `json:"result,omitempty"`
+}
+
+// bufferWriter holds the response of the API until the changes are known
+type bufferWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferWriter) WriteHeader(status int) {
+	w.status = status
+}
+
+func (w *bufferWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+// DryRunHandler runs the API with '?dryRun=true' against the registry
+// without writing it, and responds the changes with the API response
+type DryRunHandler struct {
+}
+
+func (h *DryRunHandler) Handle(i *chain.Invocation) {
+	r := i.Context().Value(rest.CTX_REQUEST).(*http.Request)
+	if r.Method == rest.HTTP_METHOD_GET || r.URL.Query().Get(QUERY_DRYRUN) != "true" {
+		i.Next()
+		return
+	}
+
+	w := i.Context().Value(rest.CTX_RESPONSE).(http.ResponseWriter)
+	pattern, _ := i.Context().Value(rest.CTX_MATCH_PATTERN).(string)
+	if !Supported(r.Method, pattern) {
+		controller.WriteError(w, scerr.ErrInvalidParams, "Dry run is not supported by the API")
+		i.Fail(nil)
+		return
+	}
+
+	dr := backend.NewDryRun()
+	util.SetRequestContext(r, backend.CTX_DRYRUN, dr)
+	bw := &bufferWriter{ResponseWriter: w, status: http.StatusOK}
+	i.WithContext(rest.CTX_RESPONSE, bw)
+	i.Next(chain.WithFunc(func(ret chain.Result) {
+		if !ret.OK || bw.status != http.StatusOK {
+			w.WriteHeader(bw.status)
+			w.Write(bw.body.Bytes())
+			return
+		}
+		resp := &Response{DryRun: true, Changes: dr.Changes()}
+		if body := bytes.TrimSpace(bw.body.Bytes()); json.Valid(body) {
+			resp.Result = body
+		}
+		log.Infof("dry run %s %s by %s, %d changes", r.Method, r.URL.Path,
+			util.GetIPFromContext(r.Context()), len(resp.Changes))
+		controller.WriteResponse(w, nil, resp)
+	}))
+}
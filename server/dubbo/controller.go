@@ -0,0 +1,179 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package dubbo
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/rest/controller"
+	nf "github.com/apache/servicecomb-service-center/server/service/notification"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DubboController serves the dubbo registry semantics, the providers
+// register and unregister their urls, the consumers lookup the urls of
+// the subscribed categories, or subscribe them by the text/event-stream
+// and get the full urls notified whenever the providers change
+type DubboController struct {
+}
+
+func (ctrl *DubboController) URLPatterns() []rest.Route {
+	return []rest.Route{
+		{rest.HTTP_METHOD_POST, "/v4/:project/dubbo/registry/register", ctrl.Register},
+		{rest.HTTP_METHOD_POST, "/v4/:project/dubbo/registry/unregister", ctrl.Unregister},
+		{rest.HTTP_METHOD_GET, "/v4/:project/dubbo/registry/lookup", ctrl.Lookup},
+		{rest.HTTP_METHOD_GET, "/v4/:project/dubbo/registry/subscribe", ctrl.Subscribe},
+	}
+}
+
+func readURLRequest(w http.ResponseWriter, r *http.Request) *URLRequest {
+	message, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Error("read body failed", err)
+		controller.WriteError(w, scerr.ErrInvalidParams, err.Error())
+		return nil
+	}
+	request := &URLRequest{}
+	err = json.Unmarshal(message, request)
+	if err != nil {
+		log.Error("Unmarshal error", err)
+		controller.WriteError(w, scerr.ErrInvalidParams, err.Error())
+		return nil
+	}
+	return request
+}
+
+func (ctrl *DubboController) Register(w http.ResponseWriter, r *http.Request) {
+	request := readURLRequest(w, r)
+	if request == nil {
+		return
+	}
+	resp, err := Register(r.Context(), request)
+	if err != nil {
+		controller.WriteError(w, scerr.ErrInternal, err.Error())
+		return
+	}
+	respInternal := resp.Response
+	resp.Response = nil
+	controller.WriteResponse(w, respInternal, resp)
+}
+
+func (ctrl *DubboController) Unregister(w http.ResponseWriter, r *http.Request) {
+	request := readURLRequest(w, r)
+	if request == nil {
+		return
+	}
+	resp, err := Unregister(r.Context(), request)
+	if err != nil {
+		controller.WriteError(w, scerr.ErrInternal, err.Error())
+		return
+	}
+	controller.WriteResponse(w, resp, nil)
+}
+
+func (ctrl *DubboController) Lookup(w http.ResponseWriter, r *http.Request) {
+	resp, err := Lookup(r.Context(), &URLRequest{URL: r.URL.Query().Get("url")})
+	if err != nil {
+		controller.WriteError(w, scerr.ErrInternal, err.Error())
+		return
+	}
+	respInternal := resp.Response
+	resp.Response = nil
+	controller.WriteResponse(w, respInternal, resp)
+}
+
+// Subscribe streams the urls of the subscribed categories as the
+// text/event-stream, the first event is sent at once
+func (ctrl *DubboController) Subscribe(w http.ResponseWriter, r *http.Request) {
+	consumer, err := parseURL(r.URL.Query().Get("url"))
+	if err != nil {
+		controller.WriteError(w, scerr.ErrInvalidParams, err.Error())
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		controller.WriteError(w, scerr.ErrInternal, "streaming is not supported")
+		return
+	}
+
+	ctx := r.Context()
+	domainProject := util.ParseDomainProject(ctx)
+	iface := interfaceOf(consumer)
+	ch := notifier.Subscribe(domainProject, iface)
+	defer notifier.Unsubscribe(domainProject, iface, ch)
+
+	w.Header().Set("Content-Type", nf.SSE_CONTENT_TYPE)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	write := func(message string) error {
+		if _, err := w.Write(util.StringToBytesWithNoCopy(message)); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	}
+
+	var last string
+	notify := func() error {
+		urls, err := lookup(ctx, domainProject, consumer)
+		if err != nil {
+			log.Errorf(err, "dubbo subscriber lookup %s failed", iface)
+			return write(fmt.Sprintf("event: error\ndata: %s\n\n", err.Error()))
+		}
+		// notify the full urls only if they change
+		current := strings.Join(urls, "\n")
+		if current == last {
+			return nil
+		}
+		last = current
+		data, err := json.Marshal(&LookupResponse{URLs: urls})
+		if err != nil {
+			return err
+		}
+		return write(fmt.Sprintf("data: %s\n\n", util.BytesToStringWithNoCopy(data)))
+	}
+
+	if err := notify(); err != nil {
+		return
+	}
+	ticker := time.NewTicker(nf.RESTKeepalive().PingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err = write(": ping\n\n")
+		case <-ch:
+			err = notify()
+		}
+		if err != nil {
+			log.Errorf(err, "dubbo subscriber of %s is closed", iface)
+			return
+		}
+	}
+}
@@ -0,0 +1,67 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package dubbo
+
+import (
+	roa "github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/discovery"
+	"github.com/astaxie/beego"
+	"time"
+)
+
+const (
+	DEFAULT_HEARTBEAT_INTERVAL = 30 * time.Second
+	DEFAULT_HEARTBEAT_TIMES    = 3
+)
+
+var (
+	cfg      Config
+	notifier = NewNotifier()
+)
+
+func init() {
+	cfg = LoadConfig()
+	if !cfg.Enabled {
+		return
+	}
+	discovery.AddEventHandler(NewInstanceEventHandler(notifier))
+	roa.RegisterServant(&DubboController{})
+}
+
+type Config struct {
+	Enabled bool
+	// HeartbeatInterval and HeartbeatTimes are the health check of the
+	// providers registered by the dubbo registry api
+	HeartbeatInterval time.Duration
+	HeartbeatTimes    int
+}
+
+func LoadConfig() Config {
+	c := Config{
+		Enabled:           beego.AppConfig.DefaultInt("dubbo_registry", 0) != 0,
+		HeartbeatInterval: DEFAULT_HEARTBEAT_INTERVAL,
+		HeartbeatTimes:    beego.AppConfig.DefaultInt("dubbo_heartbeat_times", DEFAULT_HEARTBEAT_TIMES),
+	}
+	d, err := time.ParseDuration(beego.AppConfig.DefaultString("dubbo_heartbeat_interval", ""))
+	if err == nil && d >= time.Second {
+		c.HeartbeatInterval = d
+	}
+	if c.HeartbeatTimes <= 0 {
+		c.HeartbeatTimes = DEFAULT_HEARTBEAT_TIMES
+	}
+	return c
+}
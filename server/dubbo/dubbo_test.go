@@ -0,0 +1,135 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package dubbo
+
+import (
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestParseURL(t *testing.T) {
+	u, err := parseURL("dubbo://10.0.0.1:20880/com.foo.DemoService?application=demo&version=1.0.0&side=provider")
+	if err != nil || interfaceOf(u) != "com.foo.DemoService" || applicationOf(u) != "demo" ||
+		versionOf(u) != "1.0.0" || endpointOf(u) != "dubbo://10.0.0.1:20880" || !isProvider(u) {
+		t.Fatalf("TestParseURL failed, %v, %v", u, err)
+	}
+
+	u, err = parseURL("consumer://10.0.0.2/x?interface=com.foo.DemoService&version=1.0-SNAPSHOT&side=consumer")
+	if err != nil || interfaceOf(u) != "com.foo.DemoService" || applicationOf(u) != "default" ||
+		versionOf(u) != pb.VERSION || isProvider(u) {
+		t.Fatalf("TestParseURL failed, %v, %v", u, err)
+	}
+	if c := categoriesOf(u); len(c) != 1 || c[0] != CATEGORY_PROVIDERS {
+		t.Fatalf("TestParseURL failed, %v", c)
+	}
+
+	if _, err = parseURL("dubbo://10.0.0.1:20880"); err == nil {
+		t.Fatalf("TestParseURL failed")
+	}
+}
+
+func TestInstanceIdOf(t *testing.T) {
+	a, _ := parseURL("dubbo://10.0.0.1:20880/com.foo.DemoService?group=a&timestamp=1")
+	b, _ := parseURL("dubbo://10.0.0.1:20880/com.foo.DemoService?group=a&timestamp=2")
+	c, _ := parseURL("dubbo://10.0.0.1:20880/com.foo.DemoService?group=b")
+	if instanceIdOf(a) != instanceIdOf(b) || instanceIdOf(a) == instanceIdOf(c) || len(instanceIdOf(a)) > 64 {
+		t.Fatalf("TestInstanceIdOf failed")
+	}
+}
+
+func TestIsMatch(t *testing.T) {
+	provider, _ := url.Parse("dubbo://10.0.0.1:20880/com.foo.DemoService?version=1.0.0&group=g1")
+	cases := []struct {
+		consumer string
+		match    bool
+	}{
+		{"consumer://10.0.0.2/com.foo.DemoService?version=1.0.0&group=g1", true},
+		{"consumer://10.0.0.2/com.foo.DemoService?version=*&group=*", true},
+		{"consumer://10.0.0.2/com.foo.DemoService?version=1.0.0&group=g0,g1", true},
+		{"consumer://10.0.0.2/com.foo.DemoService?version=2.0.0&group=g1", false},
+		{"consumer://10.0.0.2/com.foo.DemoService?version=1.0.0", false},
+		{"consumer://10.0.0.2/com.foo.OtherService?version=*&group=*", false},
+	}
+	for _, c := range cases {
+		consumer, _ := url.Parse(c.consumer)
+		if isMatch(consumer, provider) != c.match {
+			t.Fatalf("TestIsMatch failed, %s", c.consumer)
+		}
+	}
+
+	provider, _ = url.Parse("dubbo://10.0.0.1:20880/com.foo.DemoService?enabled=false")
+	consumer, _ := url.Parse("consumer://10.0.0.2/com.foo.DemoService")
+	if isMatch(consumer, provider) {
+		t.Fatalf("TestIsMatch failed")
+	}
+}
+
+func TestEmptyURL(t *testing.T) {
+	consumer, _ := url.Parse("consumer://10.0.0.2/com.foo.DemoService?category=providers,routers&version=1.0.0")
+	s := emptyURL(consumer, CATEGORY_ROUTERS)
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme != PROTOCOL_EMPTY || interfaceOf(u) != "com.foo.DemoService" ||
+		u.Query().Get(PARAM_CATEGORY) != CATEGORY_ROUTERS || u.Query().Get(PARAM_VERSION) != "1.0.0" {
+		t.Fatalf("TestEmptyURL failed, %s", s)
+	}
+}
+
+func TestToProviderURLs(t *testing.T) {
+	service := &pb.MicroService{ServiceId: "s", AppId: "a", ServiceName: "com.foo.DemoService", Version: "1.0.0"}
+	instance := &pb.MicroServiceInstance{
+		InstanceId: "i",
+		Endpoints:  []string{"rest://127.0.0.1:8080", "highway://127.0.0.1:8081", "rest://"},
+	}
+	urls := toProviderURLs(service, instance)
+	if len(urls) != 2 || urls[0].Host != "127.0.0.1:8080" || urls[1].Scheme != "highway" ||
+		interfaceOf(urls[0]) != "com.foo.DemoService" || urls[0].Query().Get(PARAM_VERSION) != "1.0.0" ||
+		urls[0].Query().Get(PARAM_INSTANCE_ID) != "i" {
+		t.Fatalf("TestToProviderURLs failed, %v", urls)
+	}
+
+	raw := "dubbo://10.0.0.1:20880/com.foo.DemoService?methods=a,b&version=1.0.0"
+	instance.Properties = map[string]string{PROP_DUBBO_URL: raw}
+	urls = toProviderURLs(service, instance)
+	if len(urls) != 1 || !strings.Contains(urls[0].String(), "methods=a,b") {
+		t.Fatalf("TestToProviderURLs failed, %v", urls)
+	}
+}
+
+func TestNotifier(t *testing.T) {
+	n := NewNotifier()
+	ch := n.Subscribe("default/default", "com.foo.DemoService")
+	n.Notify("default/default", "com.foo.DemoService")
+	// coalesced
+	n.Notify("default/default", "com.foo.DemoService")
+	n.Notify("default/default", "com.foo.OtherService")
+	select {
+	case <-ch:
+	default:
+		t.Fatalf("TestNotifier failed")
+	}
+	select {
+	case <-ch:
+		t.Fatalf("TestNotifier failed")
+	default:
+	}
+	n.Unsubscribe("default/default", "com.foo.DemoService", ch)
+	if len(n.subscribers) != 0 {
+		t.Fatalf("TestNotifier failed, %v", n.subscribers)
+	}
+}
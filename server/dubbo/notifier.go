@@ -0,0 +1,103 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package dubbo
+
+import (
+	apt "github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/core/backend"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/discovery"
+	serviceUtil "github.com/apache/servicecomb-service-center/server/service/util"
+	"golang.org/x/net/context"
+	"sync"
+)
+
+// Notifier signals the subscribers of the interface when the instances of
+// the services named by the interface change
+type Notifier struct {
+	mux sync.RWMutex
+	// key is '{domainProject}/{interface}'
+	subscribers map[string]map[chan struct{}]struct{}
+}
+
+func (n *Notifier) Subscribe(domainProject, iface string) chan struct{} {
+	ch := make(chan struct{}, 1)
+	key := domainProject + "/" + iface
+	n.mux.Lock()
+	subs, ok := n.subscribers[key]
+	if !ok {
+		subs = make(map[chan struct{}]struct{})
+		n.subscribers[key] = subs
+	}
+	subs[ch] = struct{}{}
+	n.mux.Unlock()
+	return ch
+}
+
+func (n *Notifier) Unsubscribe(domainProject, iface string, ch chan struct{}) {
+	key := domainProject + "/" + iface
+	n.mux.Lock()
+	delete(n.subscribers[key], ch)
+	if len(n.subscribers[key]) == 0 {
+		delete(n.subscribers, key)
+	}
+	n.mux.Unlock()
+}
+
+// Notify signals the subscribers without blocking, the signals are
+// coalesced if the subscriber is busy
+func (n *Notifier) Notify(domainProject, iface string) {
+	n.mux.RLock()
+	for ch := range n.subscribers[domainProject+"/"+iface] {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+	n.mux.RUnlock()
+}
+
+func NewNotifier() *Notifier {
+	return &Notifier{subscribers: make(map[string]map[chan struct{}]struct{})}
+}
+
+// InstanceEventHandler notifies the subscribers of the interface named by
+// the service of the instance
+type InstanceEventHandler struct {
+	Notifier *Notifier
+}
+
+func (h *InstanceEventHandler) Type() discovery.Type {
+	return backend.INSTANCE
+}
+
+func (h *InstanceEventHandler) OnEvent(evt discovery.KvEvent) {
+	if _, ok := evt.KV.Value.(*pb.MicroServiceInstance); !ok {
+		return
+	}
+	providerId, _, domainProject := apt.GetInfoFromInstKV(evt.KV.Key)
+	ctx := context.WithValue(context.Background(), serviceUtil.CTX_CACHEONLY, "1")
+	ms, _ := serviceUtil.GetService(ctx, domainProject, providerId)
+	if ms == nil {
+		return
+	}
+	h.Notifier.Notify(domainProject, ms.ServiceName)
+}
+
+func NewInstanceEventHandler(n *Notifier) *InstanceEventHandler {
+	return &InstanceEventHandler{Notifier: n}
+}
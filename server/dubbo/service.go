@@ -0,0 +1,222 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package dubbo
+
+import (
+	"errors"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/core"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	serviceUtil "github.com/apache/servicecomb-service-center/server/service/util"
+	"golang.org/x/net/context"
+	"net/url"
+	"sort"
+)
+
+var errInvalidURL = errors.New("invalid dubbo url")
+
+// Register registers the provider url as an instance of the service
+// '{application}/{interface}/{version}', the service is created if it does
+// not exist. The instance must be kept alive by the instance heartbeat
+// api. The consumer urls are accepted but not registered
+func Register(ctx context.Context, in *URLRequest) (*RegisterResponse, error) {
+	u, err := parseURL(in.URL)
+	if err != nil {
+		return &RegisterResponse{
+			Response: pb.CreateResponse(scerr.ErrInvalidParams, err.Error()),
+		}, nil
+	}
+	if !isProvider(u) {
+		return &RegisterResponse{
+			Response: pb.CreateResponse(pb.Response_SUCCESS, "Consumer url is ignored."),
+		}, nil
+	}
+
+	serviceId, resp, err := ensureService(ctx, u)
+	if err != nil || resp != nil {
+		return &RegisterResponse{Response: resp}, err
+	}
+
+	instResp, err := core.InstanceAPI.Register(ctx, &pb.RegisterInstanceRequest{
+		Instance: &pb.MicroServiceInstance{
+			InstanceId: instanceIdOf(u),
+			ServiceId:  serviceId,
+			Endpoints:  []string{endpointOf(u)},
+			HostName:   u.Hostname(),
+			Status:     pb.MSI_UP,
+			Properties: map[string]string{
+				PROP_ORIGIN:    ORIGIN_DUBBO,
+				PROP_DUBBO_URL: u.String(),
+			},
+			HealthCheck: &pb.HealthCheck{
+				Mode:     pb.CHECK_BY_HEARTBEAT,
+				Interval: int32(cfg.HeartbeatInterval.Seconds()),
+				Times:    int32(cfg.HeartbeatTimes),
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if instResp.Response.Code != pb.Response_SUCCESS {
+		return &RegisterResponse{Response: instResp.Response}, nil
+	}
+	return &RegisterResponse{
+		Response:   instResp.Response,
+		ServiceId:  serviceId,
+		InstanceId: instResp.InstanceId,
+	}, nil
+}
+
+// ensureService returns the response if the service can not be created
+func ensureService(ctx context.Context, u *url.URL) (string, *pb.Response, error) {
+	key := &pb.MicroServiceKey{
+		Tenant:      util.ParseDomainProject(ctx),
+		AppId:       applicationOf(u),
+		ServiceName: interfaceOf(u),
+		Version:     versionOf(u),
+	}
+	serviceId, err := serviceUtil.GetServiceId(ctx, key)
+	if err != nil || len(serviceId) > 0 {
+		return serviceId, nil, err
+	}
+	resp, err := core.ServiceAPI.Create(ctx, &pb.CreateServiceRequest{
+		Service: &pb.MicroService{
+			AppId:       key.AppId,
+			ServiceName: key.ServiceName,
+			Version:     key.Version,
+			Properties:  map[string]string{PROP_ORIGIN: ORIGIN_DUBBO},
+		},
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	if resp.Response.Code != pb.Response_SUCCESS {
+		return "", resp.Response, nil
+	}
+	return resp.ServiceId, nil, nil
+}
+
+// Unregister unregisters the instance of the provider url, it is
+// successful if the instance does not exist
+func Unregister(ctx context.Context, in *URLRequest) (*pb.Response, error) {
+	u, err := parseURL(in.URL)
+	if err != nil {
+		return pb.CreateResponse(scerr.ErrInvalidParams, err.Error()), nil
+	}
+	if !isProvider(u) {
+		return pb.CreateResponse(pb.Response_SUCCESS, "Consumer url is ignored."), nil
+	}
+
+	serviceId, err := serviceUtil.GetServiceId(ctx, &pb.MicroServiceKey{
+		Tenant:      util.ParseDomainProject(ctx),
+		AppId:       applicationOf(u),
+		ServiceName: interfaceOf(u),
+		Version:     versionOf(u),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(serviceId) == 0 {
+		return pb.CreateResponse(pb.Response_SUCCESS, "Service does not exist."), nil
+	}
+	resp, err := core.InstanceAPI.Unregister(ctx, &pb.UnregisterInstanceRequest{
+		ServiceId:  serviceId,
+		InstanceId: instanceIdOf(u),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Response.Code == scerr.ErrInstanceNotExists {
+		return pb.CreateResponse(pb.Response_SUCCESS, "Instance does not exist."), nil
+	}
+	return resp.Response, nil
+}
+
+// Lookup returns the urls of the categories subscribed by the consumer
+// url, the providers are the UP instances of the services named by the
+// interface, which match the version and group of the consumer
+func Lookup(ctx context.Context, in *URLRequest) (*LookupResponse, error) {
+	consumer, err := parseURL(in.URL)
+	if err != nil {
+		return &LookupResponse{
+			Response: pb.CreateResponse(scerr.ErrInvalidParams, err.Error()),
+		}, nil
+	}
+	urls, err := lookup(ctx, util.ParseDomainProject(ctx), consumer)
+	if err != nil {
+		return nil, err
+	}
+	return &LookupResponse{
+		Response: pb.CreateResponse(pb.Response_SUCCESS, "Lookup successfully."),
+		URLs:     urls,
+	}, nil
+}
+
+func lookup(ctx context.Context, domainProject string, consumer *url.URL) ([]string, error) {
+	var urls []string
+	for _, category := range categoriesOf(consumer) {
+		var found []string
+		if category == CATEGORY_PROVIDERS {
+			providers, err := lookupProviders(ctx, domainProject, consumer)
+			if err != nil {
+				return nil, err
+			}
+			found = providers
+		}
+		// the configurators and routers are not supported
+		if len(found) == 0 {
+			found = []string{emptyURL(consumer, category)}
+		}
+		urls = append(urls, found...)
+	}
+	return urls, nil
+}
+
+func lookupProviders(ctx context.Context, domainProject string, consumer *url.URL) ([]string, error) {
+	ctx = util.SetContext(ctx, serviceUtil.CTX_CACHEONLY, "1")
+	services, err := serviceUtil.GetServicesByDomainProject(ctx, domainProject)
+	if err != nil {
+		return nil, err
+	}
+	iface := interfaceOf(consumer)
+	var providers []string
+	for _, service := range services {
+		if service.ServiceName != iface {
+			continue
+		}
+		instances, err := serviceUtil.GetAllInstancesOfOneService(ctx, domainProject, service.ServiceId)
+		if err != nil {
+			log.Errorf(err, "get service[%s] instances failed", service.ServiceId)
+			return nil, err
+		}
+		for _, instance := range instances {
+			if instance.Status != pb.MSI_UP {
+				continue
+			}
+			for _, provider := range toProviderURLs(service, instance) {
+				if isMatch(consumer, provider) {
+					providers = append(providers, provider.String())
+				}
+			}
+		}
+	}
+	sort.Strings(providers)
+	return providers, nil
+}
@@ -0,0 +1,40 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package dubbo
+
+import (
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+)
+
+// URLRequest is the request of register and unregister
+type URLRequest struct {
+	URL string `json:"url"`
+}
+
+type RegisterResponse struct {
+	Response   *pb.Response `json:"response,omitempty"`
+	ServiceId  string       `json:"serviceId,omitempty"`
+	InstanceId string       `json:"instanceId,omitempty"`
+}
+
+// LookupResponse is the response of lookup and the data of the events
+// notified to the subscribers, it contains the urls of all the subscribed
+// categories, the empty category is the 'empty://' url
+type LookupResponse struct {
+	Response *pb.Response `json:"response,omitempty"`
+	URLs     []string     `json:"urls"`
+}
@@ -0,0 +1,201 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package dubbo
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	serviceUtil "github.com/apache/servicecomb-service-center/server/service/util"
+	"net/url"
+	"strings"
+)
+
+const (
+	// PROP_DUBBO_URL is the original url of the provider registered by
+	// the dubbo registry api, it is returned as is to the consumers
+	PROP_DUBBO_URL = "dubbo.url"
+	PROP_ORIGIN    = "origin"
+	ORIGIN_DUBBO   = "dubbo"
+
+	PARAM_APPLICATION = "application"
+	PARAM_INTERFACE   = "interface"
+	PARAM_VERSION     = "version"
+	PARAM_GROUP       = "group"
+	PARAM_CATEGORY    = "category"
+	PARAM_SIDE        = "side"
+	PARAM_ENABLED     = "enabled"
+	PARAM_SERVICE_ID  = "sc.serviceId"
+	PARAM_INSTANCE_ID = "sc.instanceId"
+
+	SIDE_PROVIDER = "provider"
+	SIDE_CONSUMER = "consumer"
+
+	CATEGORY_PROVIDERS     = "providers"
+	CATEGORY_CONFIGURATORS = "configurators"
+	CATEGORY_ROUTERS       = "routers"
+
+	PROTOCOL_EMPTY = "empty"
+	ANY_VALUE      = "*"
+)
+
+var versionRegex = serviceUtil.NewVersionRegexp(false)
+
+// parseURL parses the dubbo url, e.g.
+// 'dubbo://10.0.0.1:20880/com.foo.DemoService?version=1.0.0&side=provider'
+func parseURL(s string) (*url.URL, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(u.Scheme) == 0 || len(interfaceOf(u)) == 0 {
+		return nil, errInvalidURL
+	}
+	return u, nil
+}
+
+// interfaceOf returns the 'interface' parameter or the path of the url
+func interfaceOf(u *url.URL) string {
+	if iface := u.Query().Get(PARAM_INTERFACE); len(iface) > 0 {
+		return iface
+	}
+	return strings.Trim(u.Path, "/")
+}
+
+// versionOf returns the dubbo version if it is a valid service version
+func versionOf(u *url.URL) string {
+	if v := u.Query().Get(PARAM_VERSION); versionRegex.MatchString(v) {
+		return v
+	}
+	return pb.VERSION
+}
+
+func applicationOf(u *url.URL) string {
+	if app := u.Query().Get(PARAM_APPLICATION); len(app) > 0 {
+		return app
+	}
+	return "default"
+}
+
+// categoriesOf returns the categories subscribed by the consumer url,
+// default is providers
+func categoriesOf(u *url.URL) []string {
+	category := u.Query().Get(PARAM_CATEGORY)
+	if len(category) == 0 {
+		return []string{CATEGORY_PROVIDERS}
+	}
+	return strings.Split(category, ",")
+}
+
+func isProvider(u *url.URL) bool {
+	side := u.Query().Get(PARAM_SIDE)
+	return len(side) == 0 || side == SIDE_PROVIDER
+}
+
+// endpointOf returns '{protocol}://{ip}:{port}'
+func endpointOf(u *url.URL) string {
+	return u.Scheme + "://" + u.Host
+}
+
+// instanceIdOf returns the id of the instance registered by the provider
+// url, the providers of different groups can be exported on the same
+// endpoint
+func instanceIdOf(u *url.URL) string {
+	sum := sha1.Sum([]byte(endpointOf(u) + "?" + PARAM_GROUP + "=" + u.Query().Get(PARAM_GROUP)))
+	return hex.EncodeToString(sum[:])
+}
+
+// isItemMatch matches the value to the pattern, the pattern may be '*' or
+// a comma separated list
+func isItemMatch(pattern, value string) bool {
+	if pattern == ANY_VALUE {
+		return true
+	}
+	if pattern == value {
+		return true
+	}
+	if !strings.Contains(pattern, ",") {
+		return false
+	}
+	for _, p := range strings.Split(pattern, ",") {
+		if strings.TrimSpace(p) == value {
+			return true
+		}
+	}
+	return false
+}
+
+// isMatch is the same as the dubbo UrlUtils.isMatch, the interface must
+// be equal, and the version and group of the provider must match the
+// ones of the consumer
+func isMatch(consumer, provider *url.URL) bool {
+	if interfaceOf(consumer) != interfaceOf(provider) {
+		return false
+	}
+	cq, pq := consumer.Query(), provider.Query()
+	if pq.Get(PARAM_ENABLED) == "false" && cq.Get(PARAM_ENABLED) != ANY_VALUE {
+		return false
+	}
+	return isItemMatch(cq.Get(PARAM_VERSION), pq.Get(PARAM_VERSION)) &&
+		isItemMatch(cq.Get(PARAM_GROUP), pq.Get(PARAM_GROUP))
+}
+
+// emptyURL tells the consumer there is nothing in the category
+func emptyURL(consumer *url.URL, category string) string {
+	params := consumer.Query()
+	params.Set(PARAM_CATEGORY, category)
+	u := &url.URL{
+		Scheme:   PROTOCOL_EMPTY,
+		Host:     consumer.Host,
+		Path:     "/" + interfaceOf(consumer),
+		RawQuery: params.Encode(),
+	}
+	return u.String()
+}
+
+// toProviderURLs returns the original url of the provider registered by
+// the dubbo registry api, or converts every endpoint of the instance to a
+// provider url of the interface named by the service name
+func toProviderURLs(service *pb.MicroService, instance *pb.MicroServiceInstance) []*url.URL {
+	if s := instance.Properties[PROP_DUBBO_URL]; len(s) > 0 {
+		if u, err := parseURL(s); err == nil {
+			return []*url.URL{u}
+		}
+	}
+	var urls []*url.URL
+	for _, ep := range instance.Endpoints {
+		u, err := url.Parse(ep)
+		if err != nil || len(u.Scheme) == 0 || util.ParseIpPort(u.Host).Port == 0 {
+			continue
+		}
+		params := url.Values{}
+		params.Set(PARAM_APPLICATION, service.AppId)
+		params.Set(PARAM_INTERFACE, service.ServiceName)
+		params.Set(PARAM_VERSION, service.Version)
+		params.Set(PARAM_SIDE, SIDE_PROVIDER)
+		params.Set(PARAM_SERVICE_ID, service.ServiceId)
+		params.Set(PARAM_INSTANCE_ID, instance.InstanceId)
+		urls = append(urls, &url.URL{
+			Scheme:   u.Scheme,
+			Host:     u.Host,
+			Path:     "/" + service.ServiceName,
+			RawQuery: params.Encode(),
+		})
+	}
+	return urls
+}
@@ -55,6 +55,18 @@ var errors = map[int32]string{
 	ErrEndpointAlreadyExists: "Endpoint is already belong to other service",
 
 	ErrForbidden: "Forbidden",
+
+	ErrRevisionExpired:  "Revision is out of the retained range",
+	ErrRevisionConflict: "Revision of the resource has changed",
+	ErrLockHeld:         "Lock is held by another holder",
+	ErrLockNotHeld:      "Lock is not held by the holder",
+
+	ErrInstanceAlreadyExists: "Instance already exists",
+
+	ErrMaintenance: "Service center is under maintenance",
+	ErrOverloaded:  "Service center is overloaded",
+
+	ErrHeartbeatThrottled: "Heartbeat set is partially throttled",
 }
 
 const (
@@ -94,6 +106,18 @@ const (
 	ErrUnavailableQuota int32 = 500101
 
 	ErrForbidden int32 = 403001
+
+	ErrRevisionExpired  int32 = 410001
+	ErrRevisionConflict int32 = 409001
+	ErrLockHeld         int32 = 409002
+	ErrLockNotHeld      int32 = 409003
+
+	ErrInstanceAlreadyExists int32 = 409004
+
+	ErrMaintenance int32 = 503001
+	ErrOverloaded  int32 = 503002
+
+	ErrHeartbeatThrottled int32 = 429001
 )
 
 type Error struct {
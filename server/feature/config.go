@@ -0,0 +1,96 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package feature
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	roa "github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/server/reload"
+	"github.com/astaxie/beego"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const DEFAULT_SYNC_INTERVAL = 30 * time.Second
+
+var (
+	cfg      Config
+	features *Features
+)
+
+func init() {
+	cfg = LoadConfig()
+	features = NewFeatures(cfg)
+	roa.RegisterServant(&FeatureController{})
+	reload.Register(reload.Option{
+		Key: "features",
+		Apply: func(string) {
+			features.SetConfigured(LoadConfig().Flags)
+		},
+	})
+	gopool.Go(features.Run)
+}
+
+type Config struct {
+	// Flags are the flags in the config file, the flags set at runtime
+	// take precedence over them
+	Flags map[string]*Flag
+	// SyncInterval is how often the runtime flags are read from the
+	// registry, the flags set on the other instances take effect in it
+	SyncInterval time.Duration
+}
+
+func LoadConfig() Config {
+	c := Config{
+		Flags:        ParseFlags(beego.AppConfig.DefaultString("features", "")),
+		SyncInterval: DEFAULT_SYNC_INTERVAL,
+	}
+	d, err := time.ParseDuration(beego.AppConfig.DefaultString("feature_sync_interval", ""))
+	if err == nil && d >= time.Second {
+		c.SyncInterval = d
+	}
+	return c
+}
+
+// ParseFlags parses the config like 'a,!b,c:20', 'a' is enabled, 'b' is
+// disabled and 'c' is enabled for 20 percent of the domains
+func ParseFlags(s string) map[string]*Flag {
+	flags := make(map[string]*Flag)
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if len(item) == 0 {
+			continue
+		}
+		f := &Flag{Enabled: true, Source: SOURCE_CONFIG}
+		if item[0] == '!' {
+			f.Enabled, item = false, item[1:]
+		}
+		if i := strings.Index(item, ":"); i >= 0 {
+			rollout, err := strconv.Atoi(item[i+1:])
+			if err != nil || rollout <= 0 || rollout > 100 {
+				log.Warnf("ignore the feature config '%s', the rollout must be in (0, 100]", item)
+				continue
+			}
+			f.Rollout, item = rollout, item[:i]
+		}
+		f.Name = item
+		flags[f.Name] = f
+	}
+	return flags
+}
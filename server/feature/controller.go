@@ -0,0 +1,114 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package feature
+
+import (
+	"encoding/json"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/core"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/rest/controller"
+	"io/ioutil"
+	"net/http"
+)
+
+type GetFeaturesResponse struct {
+	Features []*Flag `json:"features"`
+}
+
+// FeatureController serves the feature flags to the admin
+type FeatureController struct {
+}
+
+func (ctrl *FeatureController) URLPatterns() []rest.Route {
+	return []rest.Route{
+		{rest.HTTP_METHOD_GET, "/v4/:project/admin/features", ctrl.List},
+		{rest.HTTP_METHOD_GET, "/v4/:project/admin/features/:name", ctrl.Get},
+		{rest.HTTP_METHOD_PUT, "/v4/:project/admin/features/:name", ctrl.Set},
+		{rest.HTTP_METHOD_DELETE, "/v4/:project/admin/features/:name", ctrl.Reset},
+	}
+}
+
+func (ctrl *FeatureController) List(w http.ResponseWriter, r *http.Request) {
+	if !core.IsDefaultDomainProject(util.ParseDomainProject(r.Context())) {
+		controller.WriteError(w, scerr.ErrForbidden, "Required admin permission")
+		return
+	}
+	controller.WriteResponse(w, nil, &GetFeaturesResponse{Features: features.List()})
+}
+
+func (ctrl *FeatureController) Get(w http.ResponseWriter, r *http.Request) {
+	if !core.IsDefaultDomainProject(util.ParseDomainProject(r.Context())) {
+		controller.WriteError(w, scerr.ErrForbidden, "Required admin permission")
+		return
+	}
+	f, ok := features.Get(r.URL.Query().Get(":name"))
+	if !ok {
+		controller.WriteError(w, scerr.ErrInvalidParams, ErrUndefined.Error())
+		return
+	}
+	controller.WriteResponse(w, nil, f)
+}
+
+// Set enables or disables the flag for all the service centers, the body
+// is the flag without name
+func (ctrl *FeatureController) Set(w http.ResponseWriter, r *http.Request) {
+	if !core.IsDefaultDomainProject(util.ParseDomainProject(r.Context())) {
+		controller.WriteError(w, scerr.ErrForbidden, "Required admin permission")
+		return
+	}
+	message, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Error("read body failed", err)
+		controller.WriteError(w, scerr.ErrInvalidParams, err.Error())
+		return
+	}
+	f := &Flag{}
+	if err := json.Unmarshal(message, f); err != nil {
+		log.Error("Unmarshal error", err)
+		controller.WriteError(w, scerr.ErrInvalidParams, err.Error())
+		return
+	}
+	f.Name = r.URL.Query().Get(":name")
+	switch err := features.Set(r.Context(), f); err {
+	case nil:
+		f, _ = features.Get(f.Name)
+		controller.WriteResponse(w, nil, f)
+	case ErrUndefined, ErrInvalidRollout:
+		controller.WriteError(w, scerr.ErrInvalidParams, err.Error())
+	default:
+		log.Errorf(err, "set feature %s failed", f.Name)
+		controller.WriteError(w, scerr.ErrUnavailableBackend, err.Error())
+	}
+}
+
+// Reset removes the flag set at runtime
+func (ctrl *FeatureController) Reset(w http.ResponseWriter, r *http.Request) {
+	if !core.IsDefaultDomainProject(util.ParseDomainProject(r.Context())) {
+		controller.WriteError(w, scerr.ErrForbidden, "Required admin permission")
+		return
+	}
+	name := r.URL.Query().Get(":name")
+	if err := features.Reset(r.Context(), name); err != nil {
+		log.Errorf(err, "reset feature %s failed", name)
+		controller.WriteError(w, scerr.ErrUnavailableBackend, err.Error())
+		return
+	}
+	controller.WriteResponse(w, nil, nil)
+}
@@ -0,0 +1,305 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package feature
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/core/backend"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	"golang.org/x/net/context"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	SOURCE_DEFAULT = "default"
+	SOURCE_CONFIG  = "config"
+	SOURCE_RUNTIME = "runtime"
+
+	featureKey = "features"
+)
+
+var (
+	ErrUndefined      = errors.New("feature is not defined")
+	ErrInvalidRollout = errors.New("rollout must be in [0, 100]")
+)
+
+// Flag is the switch of an experimental behavior
+type Flag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Enabled     bool   `json:"enabled"`
+	// Rollout is the percent of the domains the flag is enabled for,
+	// 0 means all the domains
+	Rollout int `json:"rollout,omitempty"`
+	// Domains are enabled regardless of the Enabled and Rollout
+	Domains []string `json:"domains,omitempty"`
+	// Source is where the flag is from, default, config or runtime
+	Source   string `json:"source"`
+	UpdateAt string `json:"updateAt,omitempty"`
+	Operator string `json:"operator,omitempty"`
+}
+
+// EnabledFor returns whether the flag is enabled for the domain, a domain
+// is always in or out of the rollout of a flag
+func (f *Flag) EnabledFor(domain string) bool {
+	for _, d := range f.Domains {
+		if d == domain {
+			return true
+		}
+	}
+	if !f.Enabled {
+		return false
+	}
+	if f.Rollout <= 0 || f.Rollout >= 100 {
+		return true
+	}
+	return bucket(f.Name, domain) < f.Rollout
+}
+
+// bucket maps the domain to [0, 100) stably, the buckets of a domain
+// differ between the flags so that the same domains do not always try
+// the experiments first
+func bucket(name, domain string) int {
+	h := fnv.New32a()
+	h.Write([]byte(name + core.SPLIT + domain))
+	return int(h.Sum32() % 100)
+}
+
+func (f *Flag) clone() *Flag {
+	c := *f
+	c.Domains = append([]string(nil), f.Domains...)
+	return &c
+}
+
+// '/cse-sr/features/{name}'
+func getRootKey() string {
+	return util.StringJoin([]string{core.GetRootKey(), featureKey}, core.SPLIT)
+}
+
+func getFeatureKey(name string) string {
+	return util.StringJoin([]string{getRootKey(), name}, core.SPLIT)
+}
+
+// Features keeps the flags from the code, config and registry, the flags
+// set at runtime are stored in the registry and shared by all the
+// service centers
+type Features struct {
+	Cfg Config
+
+	lock    sync.RWMutex
+	defined map[string]*Flag
+	config  map[string]*Flag
+	runtime map[string]*Flag
+}
+
+// Define declares a flag and its default, only the defined flags can
+// be set at runtime
+func (fs *Features) Define(name, description string, enabled bool) {
+	fs.lock.Lock()
+	fs.defined[name] = &Flag{Name: name, Description: description, Enabled: enabled, Source: SOURCE_DEFAULT}
+	fs.lock.Unlock()
+}
+
+func (fs *Features) SetConfigured(flags map[string]*Flag) {
+	fs.lock.Lock()
+	fs.config = flags
+	fs.lock.Unlock()
+}
+
+// Get returns the effective flag, the runtime one takes precedence
+// over the configured one, then the default one
+func (fs *Features) Get(name string) (*Flag, bool) {
+	fs.lock.RLock()
+	defer fs.lock.RUnlock()
+	return fs.get(name)
+}
+
+func (fs *Features) lookup(name string) (*Flag, bool) {
+	if f, ok := fs.runtime[name]; ok {
+		return f, true
+	}
+	if f, ok := fs.config[name]; ok {
+		return f, true
+	}
+	f, ok := fs.defined[name]
+	return f, ok
+}
+
+func (fs *Features) get(name string) (*Flag, bool) {
+	f, ok := fs.lookup(name)
+	if !ok {
+		return nil, false
+	}
+	f = f.clone()
+	if d, ok := fs.defined[name]; ok {
+		f.Description = d.Description
+	}
+	return f, true
+}
+
+func (fs *Features) EnabledFor(name, domain string) bool {
+	fs.lock.RLock()
+	defer fs.lock.RUnlock()
+	f, ok := fs.lookup(name)
+	return ok && f.EnabledFor(domain)
+}
+
+// List returns the flags defined or configured
+func (fs *Features) List() []*Flag {
+	fs.lock.RLock()
+	defer fs.lock.RUnlock()
+	names := make(map[string]struct{})
+	for _, m := range []map[string]*Flag{fs.defined, fs.config, fs.runtime} {
+		for name := range m {
+			names[name] = struct{}{}
+		}
+	}
+	flags := make([]*Flag, 0, len(names))
+	for name := range names {
+		f, _ := fs.get(name)
+		flags = append(flags, f)
+	}
+	sort.Sort(flagSlice(flags))
+	return flags
+}
+
+// Set stores the flag in the registry, it takes effect on this instance
+// at once and on the others after they sync
+func (fs *Features) Set(ctx context.Context, f *Flag) error {
+	fs.lock.RLock()
+	_, ok := fs.defined[f.Name]
+	fs.lock.RUnlock()
+	if !ok {
+		return ErrUndefined
+	}
+	if f.Rollout < 0 || f.Rollout > 100 {
+		return ErrInvalidRollout
+	}
+	f = f.clone()
+	f.Description = ""
+	f.Source = SOURCE_RUNTIME
+	f.UpdateAt = time.Now().UTC().Format(time.RFC3339)
+	f.Operator = util.GetIPFromContext(ctx)
+	data, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+	_, err = backend.Registry().Do(ctx, registry.PUT,
+		registry.WithStrKey(getFeatureKey(f.Name)), registry.WithValue(data))
+	if err != nil {
+		return err
+	}
+	fs.lock.Lock()
+	fs.runtime[f.Name] = f
+	fs.lock.Unlock()
+	log.Warnf("audit: feature %s is set to enabled: %v, rollout: %d%%, domains: %v by %s",
+		f.Name, f.Enabled, f.Rollout, f.Domains, f.Operator)
+	return nil
+}
+
+// Reset removes the runtime flag, the configured or default one
+// takes effect again
+func (fs *Features) Reset(ctx context.Context, name string) error {
+	_, err := backend.Registry().Do(ctx, registry.DEL, registry.WithStrKey(getFeatureKey(name)))
+	if err != nil {
+		return err
+	}
+	fs.lock.Lock()
+	delete(fs.runtime, name)
+	fs.lock.Unlock()
+	log.Warnf("audit: feature %s is reset by %s", name, util.GetIPFromContext(ctx))
+	return nil
+}
+
+// Sync reads the runtime flags from the registry
+func (fs *Features) Sync(ctx context.Context) error {
+	resp, err := backend.Registry().Do(ctx, registry.GET,
+		registry.WithStrKey(getRootKey()+core.SPLIT), registry.WithPrefix())
+	if err != nil {
+		return err
+	}
+	flags := make(map[string]*Flag, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		f := &Flag{}
+		if err := json.Unmarshal(kv.Value, f); err != nil {
+			log.Errorf(err, "unmarshal feature '%s' failed", kv.Key)
+			continue
+		}
+		f.Name = strings.TrimPrefix(string(kv.Key), getRootKey()+core.SPLIT)
+		flags[f.Name] = f
+	}
+	fs.lock.Lock()
+	fs.runtime = flags
+	fs.lock.Unlock()
+	return nil
+}
+
+func (fs *Features) Run(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+		return
+	case <-backend.Registry().Ready():
+	}
+	for {
+		if err := fs.Sync(ctx); err != nil {
+			log.Errorf(err, "sync the features failed")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(fs.Cfg.SyncInterval):
+		}
+	}
+}
+
+func NewFeatures(cfg Config) *Features {
+	return &Features{
+		Cfg:     cfg,
+		defined: make(map[string]*Flag),
+		config:  cfg.Flags,
+		runtime: make(map[string]*Flag),
+	}
+}
+
+type flagSlice []*Flag
+
+func (s flagSlice) Len() int           { return len(s) }
+func (s flagSlice) Less(i, j int) bool { return s[i].Name < s[j].Name }
+func (s flagSlice) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// Define declares a flag of an experimental behavior, it should be called
+// in the init of the package owning the behavior
+func Define(name, description string, enabled bool) {
+	features.Define(name, description, enabled)
+}
+
+// Enabled returns whether the flag is enabled for the domain of the request
+func Enabled(ctx context.Context, name string) bool {
+	return features.EnabledFor(name, util.ParseDomain(ctx))
+}
+
+func EnabledFor(name, domain string) bool {
+	return features.EnabledFor(name, domain)
+}
@@ -0,0 +1,100 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package feature
+
+import (
+	"fmt"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"golang.org/x/net/context"
+	"testing"
+)
+
+func TestParseFlags(t *testing.T) {
+	flags := ParseFlags("a, !b,c:20,d:0,e:101,")
+	if len(flags) != 3 {
+		t.Fatalf("TestParseFlags failed, %v", flags)
+	}
+	if f := flags["a"]; !f.Enabled || f.Rollout != 0 || f.Source != SOURCE_CONFIG {
+		t.Fatalf("TestParseFlags failed, %v", f)
+	}
+	if f := flags["b"]; f.Enabled {
+		t.Fatalf("TestParseFlags failed, %v", f)
+	}
+	if f := flags["c"]; !f.Enabled || f.Rollout != 20 {
+		t.Fatalf("TestParseFlags failed, %v", f)
+	}
+}
+
+func TestFlag_EnabledFor(t *testing.T) {
+	f := &Flag{Name: "x", Domains: []string{"d1"}}
+	if !f.EnabledFor("d1") || f.EnabledFor("d2") {
+		t.Fatalf("TestFlag_EnabledFor failed")
+	}
+
+	f = &Flag{Name: "x", Enabled: true, Rollout: 30}
+	n := 0
+	for i := 0; i < 1000; i++ {
+		domain := fmt.Sprintf("domain%d", i)
+		enabled := f.EnabledFor(domain)
+		if enabled != f.EnabledFor(domain) {
+			t.Fatalf("TestFlag_EnabledFor failed, %s is not stable", domain)
+		}
+		if enabled {
+			n++
+		}
+	}
+	if n < 200 || n > 400 {
+		t.Fatalf("TestFlag_EnabledFor failed, %d of 1000 domains enabled", n)
+	}
+}
+
+func TestFeatures_Get(t *testing.T) {
+	fs := NewFeatures(Config{Flags: ParseFlags("a")})
+	fs.Define("a", "flag a", false)
+	fs.Define("b", "flag b", false)
+
+	f, ok := fs.Get("a")
+	if !ok || !f.Enabled || f.Source != SOURCE_CONFIG || f.Description != "flag a" {
+		t.Fatalf("TestFeatures_Get failed, %v", f)
+	}
+	if fs.EnabledFor("b", "d") || fs.EnabledFor("c", "d") {
+		t.Fatalf("TestFeatures_Get failed")
+	}
+
+	fs.runtime["a"] = &Flag{Name: "a", Source: SOURCE_RUNTIME}
+	if fs.EnabledFor("a", "d") {
+		t.Fatalf("TestFeatures_Get failed, the runtime flag should take precedence")
+	}
+	if l := fs.List(); len(l) != 2 || l[0].Name != "a" || l[1].Name != "b" {
+		t.Fatalf("TestFeatures_Get failed, %v", l)
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	Define("test_enabled", "flag of test", false)
+	features.SetConfigured(map[string]*Flag{
+		"test_enabled": {Name: "test_enabled", Domains: []string{"d"}, Source: SOURCE_CONFIG},
+	})
+	defer features.SetConfigured(nil)
+
+	if !Enabled(util.SetDomain(context.Background(), "d"), "test_enabled") {
+		t.Fatalf("TestEnabled failed, the flag should be enabled for the domain of the request")
+	}
+	if Enabled(util.SetDomain(context.Background(), "e"), "test_enabled") || Enabled(context.Background(), "test_enabled") {
+		t.Fatalf("TestEnabled failed, the flag should be disabled for the other domains")
+	}
+}
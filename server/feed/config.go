@@ -0,0 +1,70 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package feed
+
+import (
+	roa "github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/job"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/discovery/etcd"
+	"github.com/astaxie/beego"
+	"time"
+)
+
+const DEFAULT_BUFFER_SIZE = 1000
+
+var (
+	cfg       Config
+	deltaFeed *DeltaFeed
+)
+
+func init() {
+	cfg = LoadConfig()
+	if !cfg.Enabled {
+		return
+	}
+	deltaFeed = NewDeltaFeed(cfg)
+	etcd.SetFeed(deltaFeed)
+	roa.RegisterServant(&FeedController{})
+}
+
+type Config struct {
+	Enabled bool
+	// ElectionTTL is how long the leader keeps the leadership after it
+	// stops renewing, the others watch the backend directly meanwhile
+	ElectionTTL time.Duration
+	// BufferSize is the number of the latest changes buffered for each
+	// prefix, the ones subscribing with an older revision are rejected
+	BufferSize int
+}
+
+func LoadConfig() Config {
+	c := Config{
+		Enabled:     beego.AppConfig.DefaultInt("cache_delta_feed", 0) != 0,
+		ElectionTTL: job.LoadConfig().ElectionTTL,
+		BufferSize:  beego.AppConfig.DefaultInt("cache_delta_feed_buffer", DEFAULT_BUFFER_SIZE),
+	}
+	if c.BufferSize <= 0 {
+		c.BufferSize = DEFAULT_BUFFER_SIZE
+	}
+	return c
+}
+
+func getLeaderKey() string {
+	return util.StringJoin([]string{core.GetRootKey(), "election", "feed"}, core.SPLIT)
+}
@@ -0,0 +1,102 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package feed
+
+import (
+	"encoding/json"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/core"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	"github.com/apache/servicecomb-service-center/server/rest/controller"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// FeedController streams the changes watched by the leader to the other
+// service centers
+type FeedController struct {
+}
+
+func (ctrl *FeedController) URLPatterns() []rest.Route {
+	return []rest.Route{
+		{rest.HTTP_METHOD_GET, "/v4/:project/admin/feed", ctrl.Subscribe},
+	}
+}
+
+// Subscribe streams the changes of the prefix after the rev as ndjson,
+// until the subscriber leaves or the leadership is lost
+func (ctrl *FeedController) Subscribe(w http.ResponseWriter, r *http.Request) {
+	if !core.IsDefaultDomainProject(util.ParseDomainProject(r.Context())) {
+		controller.WriteError(w, scerr.ErrForbidden, "Required admin permission")
+		return
+	}
+	query := r.URL.Query()
+	prefix := query.Get("prefix")
+	rev, err := strconv.ParseInt(query.Get("rev"), 10, 64)
+	if len(prefix) == 0 || err != nil || rev <= 0 {
+		controller.WriteError(w, scerr.ErrInvalidParams, "Invalid prefix or rev")
+		return
+	}
+	s, backlog, err := deltaFeed.Serve(prefix, rev)
+	if err != nil {
+		controller.WriteError(w, scerr.ErrUnavailableBackend, err.Error())
+		return
+	}
+	defer deltaFeed.Unsubscribe(prefix, s)
+
+	w.Header().Set(rest.HEADER_CONTENT_TYPE, rest.CONTENT_TYPE_NDJSON)
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+	encoder := json.NewEncoder(w)
+	send := func(resp *registry.PluginResponse) bool {
+		if err := encoder.Encode(resp); err != nil {
+			return false
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return true
+	}
+	if flusher != nil {
+		flusher.Flush()
+	}
+	for _, resp := range backlog {
+		if !send(resp) {
+			return
+		}
+	}
+
+	ticker := time.NewTicker(deltaFeed.Cfg.ElectionTTL / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			if !deltaFeed.IsLeader() {
+				return
+			}
+		case resp, ok := <-s.ch:
+			if !ok || !send(resp) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,200 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package feed
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/client/sc"
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/job"
+	"github.com/apache/servicecomb-service-center/server/peer"
+	mgr "github.com/apache/servicecomb-service-center/server/plugin"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/discovery/etcd"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	"golang.org/x/net/context"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// DeltaFeed elects one service center to watch the backend, the leader
+// streams the changes to the others instead of them all watching the
+// whole backend, the others watch the backend directly if the stream
+// fails
+type DeltaFeed struct {
+	Cfg Config
+
+	elector *job.Elector
+	hubs    map[string]*hub
+	clients map[string]*sc.SCClient
+	once    sync.Once
+	lock    sync.RWMutex
+}
+
+// Subscribe implements etcd.Feed, it streams the changes from the leader
+func (f *DeltaFeed) Subscribe(ctx context.Context, prefix string, rev int64, fn func(*registry.PluginResponse)) error {
+	f.once.Do(f.start)
+	e := f.getElector()
+	if rev == 0 || e == nil || e.IsLeader() {
+		return etcd.ErrNoFeed
+	}
+	leader, err := e.Leader(ctx)
+	if err != nil || len(leader) == 0 || leader == e.Id {
+		return etcd.ErrNoFeed
+	}
+	client, err := f.client(leader)
+	if err != nil {
+		return err
+	}
+	if err := client.SubscribeFeed(ctx, prefix, rev, func(resp *registry.PluginResponse) {
+		if resp.Revision > rev {
+			fn(resp)
+		}
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Begin implements etcd.Feed, the leader starts buffering the changes
+func (f *DeltaFeed) Begin(prefix string, rev int64) {
+	f.once.Do(f.start)
+	if e := f.getElector(); e == nil || !e.IsLeader() {
+		f.close(prefix)
+		return
+	}
+	f.hub(prefix).begin(rev)
+}
+
+// Publish implements etcd.Feed, the leader fans out the changes
+func (f *DeltaFeed) Publish(prefix string, resp *registry.PluginResponse) {
+	f.lock.RLock()
+	h, ok := f.hubs[prefix]
+	f.lock.RUnlock()
+	if ok {
+		h.publish(resp)
+	}
+}
+
+// Serve returns the subscriber receiving the changes of the prefix after
+// rev, and the buffered changes after rev, it is called by the leader
+func (f *DeltaFeed) Serve(prefix string, rev int64) (*subscriber, []*registry.PluginResponse, error) {
+	f.lock.RLock()
+	h, ok := f.hubs[prefix]
+	f.lock.RUnlock()
+	if !ok || !f.IsLeader() {
+		return nil, nil, etcd.ErrNoFeed
+	}
+	return h.subscribe(rev)
+}
+
+func (f *DeltaFeed) Unsubscribe(prefix string, s *subscriber) {
+	f.lock.RLock()
+	h, ok := f.hubs[prefix]
+	f.lock.RUnlock()
+	if ok {
+		h.unsubscribe(s)
+	}
+}
+
+func (f *DeltaFeed) IsLeader() bool {
+	e := f.getElector()
+	return e != nil && e.IsLeader()
+}
+
+func (f *DeltaFeed) hub(prefix string) *hub {
+	f.lock.RLock()
+	h, ok := f.hubs[prefix]
+	f.lock.RUnlock()
+	if ok {
+		return h
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if h, ok = f.hubs[prefix]; !ok {
+		h = newHub(f.Cfg.BufferSize)
+		f.hubs[prefix] = h
+	}
+	return h
+}
+
+func (f *DeltaFeed) close(prefix string) {
+	f.lock.RLock()
+	h, ok := f.hubs[prefix]
+	f.lock.RUnlock()
+	if ok {
+		h.close()
+	}
+}
+
+func (f *DeltaFeed) getElector() *job.Elector {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	return f.elector
+}
+
+// start campaigns for the leadership once the rest endpoint is listened,
+// the endpoint is the id of the candidate for the others to connect
+func (f *DeltaFeed) start() {
+	gopool.Go(func(ctx context.Context) {
+		for {
+			if endpoint := peer.RestEndpoint(core.Instance.Endpoints); len(endpoint) > 0 {
+				e := job.NewElector(getLeaderKey(), endpoint, f.Cfg.ElectionTTL)
+				f.lock.Lock()
+				f.elector = e
+				f.lock.Unlock()
+				log.Infof("%s campaigns for the leader of the delta feed", endpoint)
+				e.Run(ctx)
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+		}
+	})
+}
+
+func (f *DeltaFeed) client(endpoint string) (*sc.SCClient, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if client, ok := f.clients[endpoint]; ok {
+		return client, nil
+	}
+	client, err := sc.NewSCClient(sc.Config{Name: endpoint, Endpoints: []string{endpoint}})
+	if err != nil {
+		return nil, err
+	}
+	// the stream lasts until the watch times out
+	client.Timeout = 0
+	if u, _ := url.Parse(endpoint); u.Scheme == "https" {
+		if client.TLS, err = mgr.Plugins().TLS().ClientConfig(); err != nil {
+			return nil, err
+		}
+	}
+	f.clients = map[string]*sc.SCClient{endpoint: client}
+	return client, nil
+}
+
+func NewDeltaFeed(cfg Config) *DeltaFeed {
+	return &DeltaFeed{
+		Cfg:  cfg,
+		hubs: make(map[string]*hub),
+	}
+}
@@ -0,0 +1,135 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package feed
+
+import (
+	"errors"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	"sync"
+)
+
+// ErrBehind means the subscriber's revision is older than the changes
+// buffered by the hub, it should watch the backend directly
+var ErrBehind = errors.New("the revision is older than the buffered changes")
+
+// subscriber receives the changes published after it subscribes, the
+// channel is closed if it is too slow or the hub is reset
+type subscriber struct {
+	ch chan *registry.PluginResponse
+}
+
+// hub buffers the latest changes of one prefix watched from the backend,
+// and fans them out to the subscribers
+type hub struct {
+	// from is the revision since which the changes are all buffered
+	from int64
+	// rev is the revision of the last change watched
+	rev  int64
+	buf  []*registry.PluginResponse
+	subs map[*subscriber]struct{}
+	size int
+	lock sync.Mutex
+}
+
+// begin is called before the leader watches the backend after rev, the
+// buffered changes are dropped if the watch does not continue the last
+func (h *hub) begin(rev int64) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.rev != 0 && h.rev == rev {
+		return
+	}
+	h.reset()
+	h.from, h.rev = rev, rev
+}
+
+func (h *hub) publish(resp *registry.PluginResponse) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.rev == 0 {
+		return
+	}
+	h.buf = append(h.buf, resp)
+	if len(h.buf) > h.size {
+		h.from = h.buf[0].Revision
+		h.buf[0] = nil
+		h.buf = h.buf[1:]
+	}
+	h.rev = resp.Revision
+	for s := range h.subs {
+		select {
+		case s.ch <- resp:
+		default:
+			// drop the slow subscriber, it will fall back to the backend
+			delete(h.subs, s)
+			close(s.ch)
+		}
+	}
+}
+
+// subscribe returns the subscriber receiving the changes after rev, and
+// the buffered changes after rev
+func (h *hub) subscribe(rev int64) (*subscriber, []*registry.PluginResponse, error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.rev == 0 || rev < h.from {
+		return nil, nil, ErrBehind
+	}
+	var backlog []*registry.PluginResponse
+	for i, resp := range h.buf {
+		if resp.Revision > rev {
+			backlog = append(backlog, h.buf[i:]...)
+			break
+		}
+	}
+	s := &subscriber{ch: make(chan *registry.PluginResponse, h.size)}
+	h.subs[s] = struct{}{}
+	return s, backlog, nil
+}
+
+func (h *hub) unsubscribe(s *subscriber) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if _, ok := h.subs[s]; ok {
+		delete(h.subs, s)
+		close(s.ch)
+	}
+}
+
+// close drops the changes and the subscribers, it is called when the
+// service center is no longer the leader
+func (h *hub) close() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.reset()
+	h.from, h.rev = 0, 0
+}
+
+func (h *hub) reset() {
+	for s := range h.subs {
+		delete(h.subs, s)
+		close(s.ch)
+	}
+	h.buf = nil
+}
+
+func newHub(size int) *hub {
+	return &hub{
+		size: size,
+		subs: make(map[*subscriber]struct{}),
+	}
+}
@@ -0,0 +1,101 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package feed
+
+import (
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	"testing"
+)
+
+func changes(rev int64) *registry.PluginResponse {
+	return &registry.PluginResponse{Action: registry.Put, Revision: rev}
+}
+
+func TestHub(t *testing.T) {
+	h := newHub(3)
+	if _, _, err := h.subscribe(1); err != ErrBehind {
+		t.Fatalf("TestHub failed, %v", err)
+	}
+
+	h.begin(10)
+	h.publish(changes(11))
+	h.publish(changes(12))
+	if _, _, err := h.subscribe(9); err != ErrBehind {
+		t.Fatalf("TestHub failed, %v", err)
+	}
+	s, backlog, err := h.subscribe(11)
+	if err != nil || len(backlog) != 1 || backlog[0].Revision != 12 {
+		t.Fatalf("TestHub failed, %v, %v", backlog, err)
+	}
+	h.publish(changes(13))
+	if resp := <-s.ch; resp.Revision != 13 {
+		t.Fatalf("TestHub failed, %v", resp)
+	}
+
+	// the oldest changes are dropped
+	h.publish(changes(14))
+	if _, _, err := h.subscribe(10); err != ErrBehind {
+		t.Fatalf("TestHub failed, %v", err)
+	}
+	_, backlog, err = h.subscribe(11)
+	if err != nil || len(backlog) != 3 || backlog[0].Revision != 12 {
+		t.Fatalf("TestHub failed, %v, %v", backlog, err)
+	}
+
+	// continue the last watch
+	h.begin(14)
+	if _, backlog, err = h.subscribe(12); err != nil || len(backlog) != 2 {
+		t.Fatalf("TestHub failed, %v, %v", backlog, err)
+	}
+	// the watch restarts after a re-list
+	h.begin(20)
+	for resp := range s.ch {
+		if resp.Revision != 14 {
+			t.Fatalf("TestHub failed, %v", resp)
+		}
+	}
+	if _, _, err := h.subscribe(14); err != ErrBehind {
+		t.Fatalf("TestHub failed, %v", err)
+	}
+	if _, backlog, err = h.subscribe(20); err != nil || len(backlog) != 0 {
+		t.Fatalf("TestHub failed, %v, %v", backlog, err)
+	}
+
+	h.close()
+	if _, _, err := h.subscribe(20); err != ErrBehind {
+		t.Fatalf("TestHub failed, %v", err)
+	}
+}
+
+func TestHub_SlowSubscriber(t *testing.T) {
+	h := newHub(1)
+	h.begin(1)
+	s, _, err := h.subscribe(1)
+	if err != nil {
+		t.Fatalf("TestHub_SlowSubscriber failed, %v", err)
+	}
+	h.publish(changes(2))
+	h.publish(changes(3))
+	if resp := <-s.ch; resp.Revision != 2 {
+		t.Fatalf("TestHub_SlowSubscriber failed, %v", resp)
+	}
+	if _, ok := <-s.ch; ok {
+		t.Fatalf("TestHub_SlowSubscriber failed")
+	}
+	// unsubscribe a dropped subscriber
+	h.unsubscribe(s)
+}
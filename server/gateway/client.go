@@ -0,0 +1,200 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package gateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	TYPE_KONG   = "kong"
+	TYPE_APISIX = "apisix"
+	TYPE_EDGE   = "edge"
+)
+
+// Client pushes the documents to the gateway by its admin api
+type Client interface {
+	Push(d *Document) error
+	Delete(d *Document) error
+}
+
+func NewClient(cfg Config) (Client, error) {
+	c := &httpClient{
+		Address:  strings.TrimRight(cfg.Address, "/"),
+		AdminKey: cfg.AdminKey,
+		client:   &http.Client{Timeout: cfg.Timeout},
+	}
+	switch cfg.Type {
+	case TYPE_KONG:
+		c.keyHeader = "Kong-Admin-Token"
+		return &KongClient{c: c, Upstream: cfg.Upstream}, nil
+	case TYPE_APISIX:
+		c.keyHeader = "X-API-KEY"
+		return &APISIXClient{c: c, Upstream: cfg.Upstream}, nil
+	case TYPE_EDGE:
+		c.keyHeader = "Authorization"
+		return &EdgeClient{c: c}, nil
+	default:
+		return nil, fmt.Errorf("unsupported gateway type '%s'", cfg.Type)
+	}
+}
+
+type httpClient struct {
+	Address   string
+	AdminKey  string
+	keyHeader string
+	client    *http.Client
+}
+
+// do returns nil if the response is 2xx, or 404 of the DELETE request
+func (c *httpClient) do(method, path, contentType string, body []byte) error {
+	req, err := http.NewRequest(method, c.Address+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if len(contentType) > 0 {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if len(c.AdminKey) > 0 {
+		req.Header.Set(c.keyHeader, c.AdminKey)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	b, _ := ioutil.ReadAll(resp.Body)
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusNotFound && method == http.MethodDelete:
+		return nil
+	default:
+		return fmt.Errorf("%s %s failed, %d %s", method, path, resp.StatusCode, b)
+	}
+}
+
+func (c *httpClient) doJSON(method, path string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.do(method, path, "application/json", b)
+}
+
+// KongClient creates a kong service to the upstream, and a route of the
+// service matching the paths of the document
+type KongClient struct {
+	c        *httpClient
+	Upstream string
+}
+
+func (k *KongClient) Push(d *Document) error {
+	paths, err := d.Paths()
+	if err != nil {
+		return err
+	}
+	name := url.PathEscape(d.Name())
+	err = k.c.doJSON(http.MethodPut, "/services/"+name, map[string]interface{}{
+		"name": d.Name(),
+		"url":  Upstream(k.Upstream, d),
+	})
+	if err != nil {
+		return err
+	}
+	return k.c.doJSON(http.MethodPut, "/services/"+name+"/routes/"+name, map[string]interface{}{
+		"name":       d.Name(),
+		"paths":      paths,
+		"strip_path": false,
+	})
+}
+
+func (k *KongClient) Delete(d *Document) error {
+	name := url.PathEscape(d.Name())
+	if err := k.c.do(http.MethodDelete, "/services/"+name+"/routes/"+name, "", nil); err != nil {
+		return err
+	}
+	return k.c.do(http.MethodDelete, "/services/"+name, "", nil)
+}
+
+// APISIXClient creates an apisix route matching the paths of the
+// document, with the upstream node
+type APISIXClient struct {
+	c        *httpClient
+	Upstream string
+}
+
+func (a *APISIXClient) Push(d *Document) error {
+	paths, err := d.Paths()
+	if err != nil {
+		return err
+	}
+	uris := make([]string, 0, len(paths))
+	for _, p := range paths {
+		uris = append(uris, strings.TrimRight(p, "/")+"/*")
+	}
+	u, err := url.Parse(Upstream(a.Upstream, d))
+	if err != nil {
+		return err
+	}
+	node := u.Host
+	if len(u.Port()) == 0 {
+		port := "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+		node = net.JoinHostPort(u.Hostname(), port)
+	}
+	return a.c.doJSON(http.MethodPut, "/apisix/admin/routes/"+url.PathEscape(d.Name()), map[string]interface{}{
+		"name": d.Name(),
+		"uris": uris,
+		"upstream": map[string]interface{}{
+			"type":   "roundrobin",
+			"scheme": u.Scheme,
+			"nodes":  map[string]int{node: 1},
+		},
+	})
+}
+
+func (a *APISIXClient) Delete(d *Document) error {
+	return a.c.do(http.MethodDelete, "/apisix/admin/routes/"+url.PathEscape(d.Name()), "", nil)
+}
+
+// EdgeClient puts the raw document to '{address}/{app}/{service}/{schemaId}',
+// the servicecomb edge service loads the contracts from it
+type EdgeClient struct {
+	c *httpClient
+}
+
+func (e *EdgeClient) path(d *Document) string {
+	return "/" + url.PathEscape(d.AppId) + "/" + url.PathEscape(d.ServiceName) + "/" + url.PathEscape(d.SchemaId)
+}
+
+func (e *EdgeClient) Push(d *Document) error {
+	return e.c.do(http.MethodPut, e.path(d), "application/yaml", d.Content)
+}
+
+func (e *EdgeClient) Delete(d *Document) error {
+	return e.c.do(http.MethodDelete, e.path(d), "", nil)
+}
@@ -0,0 +1,62 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package gateway
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/rest/controller"
+	"net/http"
+)
+
+type StatusesResponse struct {
+	Statuses []*Status `json:"statuses"`
+}
+
+// GatewayController shows the push statuses of the schemas, and pushes
+// a schema manually, e.g. the schema registered without summary or the
+// push failed after the max retry times
+type GatewayController struct {
+}
+
+func (ctrl *GatewayController) URLPatterns() []rest.Route {
+	return []rest.Route{
+		{rest.HTTP_METHOD_GET, "/v4/:project/gateway/schemas", ctrl.Statuses},
+		{rest.HTTP_METHOD_POST, "/v4/:project/gateway/schemas/:serviceId/:schemaId/push", ctrl.Push},
+	}
+}
+
+func (ctrl *GatewayController) Statuses(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	statuses := pusher.Statuses(util.ParseDomainProject(ctx), r.URL.Query().Get("serviceId"))
+	controller.WriteResponse(w, nil, &StatusesResponse{Statuses: statuses})
+}
+
+func (ctrl *GatewayController) Push(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+	serviceId, schemaId := query.Get(":serviceId"), query.Get(":schemaId")
+	doc, err := LoadDocument(ctx, util.ParseDomainProject(ctx), serviceId, schemaId)
+	if err != nil {
+		controller.WriteError(w, scerr.ErrInvalidParams, err.Error())
+		return
+	}
+	pusher.Push(doc)
+	controller.WriteResponse(w, pb.CreateResponse(pb.Response_SUCCESS, "Push the schema in background."), nil)
+}
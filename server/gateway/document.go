@@ -0,0 +1,102 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package gateway
+
+import (
+	"encoding/json"
+	"github.com/ghodss/yaml"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var nameRegex = regexp.MustCompile(`[^a-zA-Z0-9_.-]`)
+
+// Document is the schema pushed to the gateway
+type Document struct {
+	DomainProject string
+	ServiceId     string
+	SchemaId      string
+	AppId         string
+	ServiceName   string
+	Version       string
+	// Content is the swagger or openapi document in yaml or json
+	Content []byte
+}
+
+// Name returns the name of the gateway objects of the schema
+func (d *Document) Name() string {
+	return nameRegex.ReplaceAllString(d.AppId+"."+d.ServiceName+"."+d.SchemaId, "_")
+}
+
+// openapi contains the fields to route of swagger 2.0 and openapi 3.0
+type openapi struct {
+	BasePath string `json:"basePath"`
+	Servers  []struct {
+		URL string `json:"url"`
+	} `json:"servers"`
+	Paths map[string]json.RawMessage `json:"paths"`
+}
+
+// Paths returns the prefixes of the operation paths, they are the base
+// path of the document, or the paths truncated before the first template
+func (d *Document) Paths() ([]string, error) {
+	b, err := yaml.YAMLToJSON(d.Content)
+	if err != nil {
+		return nil, err
+	}
+	doc := &openapi{}
+	if err := json.Unmarshal(b, doc); err != nil {
+		return nil, err
+	}
+
+	basePath := doc.BasePath
+	if len(basePath) == 0 && len(doc.Servers) > 0 {
+		if u, err := url.Parse(doc.Servers[0].URL); err == nil {
+			basePath = u.Path
+		}
+	}
+	basePath = "/" + strings.Trim(basePath, "/")
+	if basePath != "/" {
+		return []string{basePath}, nil
+	}
+
+	set := make(map[string]struct{}, len(doc.Paths))
+	for p := range doc.Paths {
+		if i := strings.Index(p, "{"); i >= 0 {
+			p = p[:i]
+		}
+		p = "/" + strings.Trim(p, "/")
+		set[p] = struct{}{}
+	}
+	paths := make([]string, 0, len(set))
+	for p := range set {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// Upstream returns the upstream url rendered by the template
+func Upstream(template string, d *Document) string {
+	return strings.NewReplacer(
+		"{app}", d.AppId,
+		"{service}", d.ServiceName,
+		"{version}", d.Version,
+	).Replace(template)
+}
@@ -0,0 +1,161 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package gateway
+
+import (
+	"errors"
+	"fmt"
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	roa "github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/core/backend"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/discovery"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	serviceUtil "github.com/apache/servicecomb-service-center/server/service/util"
+	"github.com/astaxie/beego"
+	"golang.org/x/net/context"
+	"strings"
+	"time"
+)
+
+const (
+	DEFAULT_UPSTREAM       = "http://{service}"
+	DEFAULT_RETRY_TIMES    = 3
+	DEFAULT_RETRY_INTERVAL = 10 * time.Second
+	DEFAULT_TIMEOUT        = 10 * time.Second
+
+	queueSize = 1000
+)
+
+var (
+	cfg    Config
+	pusher *Pusher
+
+	errQueueFull = errors.New("push queue is full")
+)
+
+func init() {
+	cfg = LoadConfig()
+	if !cfg.Enabled {
+		return
+	}
+	client, err := NewClient(cfg)
+	if err != nil {
+		log.Errorf(err, "gateway push is disabled")
+		return
+	}
+	pusher = NewPusher(client, cfg.RetryTimes, cfg.RetryInterval)
+	discovery.AddEventHandler(&SchemaEventHandler{Pusher: pusher})
+	roa.RegisterServant(&GatewayController{})
+	gopool.Go(pusher.Run)
+}
+
+type Config struct {
+	Enabled bool
+	// Type is the gateway type, kong, apisix or edge
+	Type string
+	// Address is the admin api address of the gateway
+	Address  string
+	AdminKey string
+	// Upstream is the url template of the routes, the '{app}', '{service}'
+	// and '{version}' are replaced by the service of the schema
+	Upstream      string
+	RetryTimes    int
+	RetryInterval time.Duration
+	Timeout       time.Duration
+}
+
+func LoadConfig() Config {
+	c := Config{
+		Enabled:       beego.AppConfig.DefaultInt("gateway_push", 0) != 0,
+		Type:          strings.ToLower(beego.AppConfig.DefaultString("gateway_type", TYPE_KONG)),
+		Address:       beego.AppConfig.String("gateway_address"),
+		AdminKey:      beego.AppConfig.String("gateway_admin_key"),
+		Upstream:      beego.AppConfig.DefaultString("gateway_upstream", DEFAULT_UPSTREAM),
+		RetryTimes:    beego.AppConfig.DefaultInt("gateway_retry_times", DEFAULT_RETRY_TIMES),
+		RetryInterval: DEFAULT_RETRY_INTERVAL,
+		Timeout:       DEFAULT_TIMEOUT,
+	}
+	d, err := time.ParseDuration(beego.AppConfig.DefaultString("gateway_retry_interval", ""))
+	if err == nil && d > 0 {
+		c.RetryInterval = d
+	}
+	if c.RetryTimes < 0 {
+		c.RetryTimes = 0
+	}
+	if len(c.Address) == 0 {
+		c.Enabled = false
+	}
+	return c
+}
+
+// SchemaEventHandler pushes the schemas on the schema summary events, the
+// summary is written with the schema content in the same transaction
+type SchemaEventHandler struct {
+	Pusher *Pusher
+}
+
+func (h *SchemaEventHandler) Type() discovery.Type {
+	return backend.SCHEMA_SUMMARY
+}
+
+func (h *SchemaEventHandler) OnEvent(evt discovery.KvEvent) {
+	domainProject, serviceId, schemaId := core.GetInfoFromSchemaSummaryKV(evt.KV.Key)
+	switch evt.Type {
+	case pb.EVT_CREATE, pb.EVT_UPDATE:
+		gopool.Go(func(ctx context.Context) {
+			doc, err := LoadDocument(ctx, domainProject, serviceId, schemaId)
+			if err != nil {
+				log.Errorf(err, "load the schema[%s/%s] failed", serviceId, schemaId)
+				return
+			}
+			h.Pusher.Push(doc)
+		})
+	case pb.EVT_DELETE:
+		h.Pusher.Delete(domainProject, serviceId, schemaId)
+	}
+}
+
+// LoadDocument reads the schema and its service from the registry
+func LoadDocument(ctx context.Context, domainProject, serviceId, schemaId string) (*Document, error) {
+	service, err := serviceUtil.GetService(ctx, domainProject, serviceId)
+	if err != nil {
+		return nil, err
+	}
+	if service == nil {
+		return nil, fmt.Errorf("service[%s] does not exist", serviceId)
+	}
+	key := core.GenerateServiceSchemaKey(domainProject, serviceId, schemaId)
+	resp, err := backend.Store().Schema().Search(ctx, registry.WithStrKey(key))
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, fmt.Errorf("schema[%s/%s] does not exist", serviceId, schemaId)
+	}
+	return &Document{
+		DomainProject: domainProject,
+		ServiceId:     serviceId,
+		SchemaId:      schemaId,
+		AppId:         service.AppId,
+		ServiceName:   service.ServiceName,
+		Version:       service.Version,
+		Content:       resp.Kvs[0].Value.([]byte),
+	}, nil
+}
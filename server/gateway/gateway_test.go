@@ -0,0 +1,229 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package gateway
+
+import (
+	"encoding/json"
+	"errors"
+	"golang.org/x/net/context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+const swagger = `
+swagger: "2.0"
+info:
+  title: order
+basePath: /order/
+paths:
+  /orders/{id}:
+    get: {}
+`
+
+const openapi3 = `{"openapi":"3.0.0","servers":[{"url":"http://localhost:8080"}],
+"paths":{"/orders/{id}":{},"/orders":{},"/carts/items/{id}/detail":{}}}`
+
+func newDocument(content string) *Document {
+	return &Document{
+		DomainProject: "default/default",
+		ServiceId:     "s1",
+		SchemaId:      "order.Endpoint",
+		AppId:         "app",
+		ServiceName:   "order",
+		Version:       "1.0.0",
+		Content:       []byte(content),
+	}
+}
+
+func TestDocument_Paths(t *testing.T) {
+	d := newDocument(swagger)
+	paths, err := d.Paths()
+	if err != nil || !reflect.DeepEqual(paths, []string{"/order"}) {
+		t.Fatalf("TestDocument_Paths failed, %v, %v", paths, err)
+	}
+	paths, err = newDocument(openapi3).Paths()
+	if err != nil || !reflect.DeepEqual(paths, []string{"/carts/items", "/orders"}) {
+		t.Fatalf("TestDocument_Paths failed, %v, %v", paths, err)
+	}
+	if _, err := newDocument("{").Paths(); err == nil {
+		t.Fatalf("TestDocument_Paths failed")
+	}
+	if d.Name() != "app.order.order.Endpoint" {
+		t.Fatalf("TestDocument_Paths failed, %s", d.Name())
+	}
+	if u := Upstream("http://{service}.{app}:8080/{version}", d); u != "http://order.app:8080/1.0.0" {
+		t.Fatalf("TestDocument_Paths failed, %s", u)
+	}
+}
+
+type request struct {
+	Method string
+	Path   string
+	Key    string
+	Body   map[string]interface{}
+}
+
+func newGateway(t *testing.T, header string) (*httptest.Server, *[]request) {
+	var requests []request
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := request{Method: r.Method, Path: r.URL.Path, Key: r.Header.Get(header)}
+		b, _ := ioutil.ReadAll(r.Body)
+		if len(b) > 0 && r.Header.Get("Content-Type") == "application/json" {
+			if err := json.Unmarshal(b, &req.Body); err != nil {
+				t.Fatalf("newGateway failed, %v", err)
+			}
+		}
+		requests = append(requests, req)
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})), &requests
+}
+
+func TestKongClient(t *testing.T) {
+	server, requests := newGateway(t, "Kong-Admin-Token")
+	defer server.Close()
+	c, _ := NewClient(Config{Type: TYPE_KONG, Address: server.URL + "/", AdminKey: "key",
+		Upstream: DEFAULT_UPSTREAM, Timeout: time.Second})
+	d := newDocument(swagger)
+	if err := c.Push(d); err != nil {
+		t.Fatalf("TestKongClient failed, %v", err)
+	}
+	if err := c.Delete(d); err != nil {
+		t.Fatalf("TestKongClient failed, %v", err)
+	}
+	if len(*requests) != 4 {
+		t.Fatalf("TestKongClient failed, %v", *requests)
+	}
+	svc, route := (*requests)[0], (*requests)[1]
+	if svc.Method != http.MethodPut || svc.Path != "/services/app.order.order.Endpoint" ||
+		svc.Key != "key" || svc.Body["url"] != "http://order" {
+		t.Fatalf("TestKongClient failed, %v", svc)
+	}
+	if route.Path != "/services/app.order.order.Endpoint/routes/app.order.order.Endpoint" ||
+		!reflect.DeepEqual(route.Body["paths"], []interface{}{"/order"}) {
+		t.Fatalf("TestKongClient failed, %v", route)
+	}
+	if (*requests)[2].Method != http.MethodDelete || (*requests)[3].Path != "/services/app.order.order.Endpoint" {
+		t.Fatalf("TestKongClient failed, %v", *requests)
+	}
+}
+
+func TestAPISIXClient(t *testing.T) {
+	server, requests := newGateway(t, "X-API-KEY")
+	defer server.Close()
+	c, _ := NewClient(Config{Type: TYPE_APISIX, Address: server.URL, AdminKey: "key",
+		Upstream: "https://{service}.svc", Timeout: time.Second})
+	if err := c.Push(newDocument(openapi3)); err != nil {
+		t.Fatalf("TestAPISIXClient failed, %v", err)
+	}
+	route := (*requests)[0]
+	if route.Path != "/apisix/admin/routes/app.order.order.Endpoint" || route.Key != "key" ||
+		!reflect.DeepEqual(route.Body["uris"], []interface{}{"/carts/items/*", "/orders/*"}) {
+		t.Fatalf("TestAPISIXClient failed, %v", route)
+	}
+	upstream := route.Body["upstream"].(map[string]interface{})
+	if upstream["scheme"] != "https" ||
+		!reflect.DeepEqual(upstream["nodes"], map[string]interface{}{"order.svc:443": float64(1)}) {
+		t.Fatalf("TestAPISIXClient failed, %v", upstream)
+	}
+
+	if _, err := NewClient(Config{Type: "unknown"}); err == nil {
+		t.Fatalf("TestAPISIXClient failed")
+	}
+}
+
+func TestEdgeClient(t *testing.T) {
+	server, requests := newGateway(t, "Authorization")
+	defer server.Close()
+	c, _ := NewClient(Config{Type: TYPE_EDGE, Address: server.URL, Timeout: time.Second})
+	if err := c.Push(newDocument(swagger)); err != nil {
+		t.Fatalf("TestEdgeClient failed, %v", err)
+	}
+	if (*requests)[0].Path != "/app/order/order.Endpoint" || (*requests)[0].Method != http.MethodPut {
+		t.Fatalf("TestEdgeClient failed, %v", *requests)
+	}
+}
+
+type mockClient struct {
+	mux      sync.Mutex
+	failures int
+	pushed   int
+	deleted  int
+}
+
+func (c *mockClient) Push(d *Document) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if c.failures > 0 {
+		c.failures--
+		return errors.New("unavailable")
+	}
+	c.pushed++
+	return nil
+}
+
+func (c *mockClient) Delete(d *Document) error {
+	c.mux.Lock()
+	c.deleted++
+	c.mux.Unlock()
+	return nil
+}
+
+func TestPusher(t *testing.T) {
+	client := &mockClient{failures: 2}
+	p := NewPusher(client, 1, time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the schema never pushed is not deleted
+	p.Delete("default/default", "s1", "order.Endpoint")
+	if len(p.Statuses("default/default", "")) != 0 {
+		t.Fatalf("TestPusher failed")
+	}
+
+	d := newDocument(swagger)
+	p.Push(d)
+	p.do(ctx, <-p.tasks)
+	statuses := p.Statuses("default/default", "s1")
+	if len(statuses) != 1 || statuses[0].State != STATE_FAILED || statuses[0].Attempts != 2 ||
+		len(statuses[0].Error) == 0 {
+		t.Fatalf("TestPusher failed, %v", statuses[0])
+	}
+
+	p.Push(d)
+	p.do(ctx, <-p.tasks)
+	statuses = p.Statuses("default/default", "")
+	if statuses[0].State != STATE_PUSHED || statuses[0].Attempts != 1 || client.pushed != 1 {
+		t.Fatalf("TestPusher failed, %v", statuses[0])
+	}
+	if len(p.Statuses("other/default", "")) != 0 || len(p.Statuses("default/default", "s2")) != 0 {
+		t.Fatalf("TestPusher failed")
+	}
+
+	p.Delete("default/default", "s1", "order.Endpoint")
+	p.do(ctx, <-p.tasks)
+	statuses = p.Statuses("default/default", "")
+	if statuses[0].State != STATE_DELETED || client.deleted != 1 {
+		t.Fatalf("TestPusher failed, %v", statuses[0])
+	}
+}
@@ -0,0 +1,200 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package gateway
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"golang.org/x/net/context"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	STATE_PENDING = "pending"
+	STATE_PUSHED  = "pushed"
+	STATE_DELETED = "deleted"
+	STATE_FAILED  = "failed"
+)
+
+// Status is the push status of a schema
+type Status struct {
+	ServiceId   string    `json:"serviceId"`
+	SchemaId    string    `json:"schemaId"`
+	ServiceName string    `json:"serviceName,omitempty"`
+	Name        string    `json:"name,omitempty"`
+	State       string    `json:"state"`
+	Attempts    int       `json:"attempts"`
+	Error       string    `json:"error,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+
+	domainProject string
+}
+
+type task struct {
+	doc    *Document
+	delete bool
+}
+
+// Pusher pushes the documents to the gateway in order, the failed
+// pushes are retried in the interval until the max retry times
+type Pusher struct {
+	Client        Client
+	RetryTimes    int
+	RetryInterval time.Duration
+
+	tasks    chan *task
+	mux      sync.RWMutex
+	statuses map[string]*Status
+	// docs are the last documents pushed, the delete events need their
+	// names after the services are deleted
+	docs map[string]*Document
+}
+
+func statusKey(domainProject, serviceId, schemaId string) string {
+	return domainProject + "/" + serviceId + "/" + schemaId
+}
+
+func (p *Pusher) Push(doc *Document) {
+	p.enqueue(&task{doc: doc})
+}
+
+// Delete removes the schema from the gateway, it is ignored if the
+// schema was never pushed
+func (p *Pusher) Delete(domainProject, serviceId, schemaId string) {
+	p.mux.RLock()
+	doc, ok := p.docs[statusKey(domainProject, serviceId, schemaId)]
+	p.mux.RUnlock()
+	if !ok {
+		return
+	}
+	p.enqueue(&task{doc: doc, delete: true})
+}
+
+func (p *Pusher) enqueue(t *task) {
+	p.setStatus(t.doc, STATE_PENDING, 0, nil)
+	select {
+	case p.tasks <- t:
+	default:
+		log.Warnf("gateway push queue is full, drop the schema[%s/%s]", t.doc.ServiceId, t.doc.SchemaId)
+		p.setStatus(t.doc, STATE_FAILED, 0, errQueueFull)
+	}
+}
+
+func (p *Pusher) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case t := <-p.tasks:
+			p.do(ctx, t)
+		}
+	}
+}
+
+func (p *Pusher) do(ctx context.Context, t *task) {
+	var err error
+	for i := 1; i <= p.RetryTimes+1; i++ {
+		if t.delete {
+			err = p.Client.Delete(t.doc)
+		} else {
+			err = p.Client.Push(t.doc)
+		}
+		if err == nil {
+			state := STATE_PUSHED
+			if t.delete {
+				state = STATE_DELETED
+			}
+			p.setStatus(t.doc, state, i, nil)
+			log.Infof("%s the schema[%s/%s] to gateway as '%s'", state, t.doc.ServiceId, t.doc.SchemaId, t.doc.Name())
+			return
+		}
+		log.Errorf(err, "push the schema[%s/%s] to gateway failed, attempts %d",
+			t.doc.ServiceId, t.doc.SchemaId, i)
+		p.setStatus(t.doc, STATE_FAILED, i, err)
+		if i > p.RetryTimes {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(p.RetryInterval):
+		}
+	}
+}
+
+func (p *Pusher) setStatus(doc *Document, state string, attempts int, err error) {
+	key := statusKey(doc.DomainProject, doc.ServiceId, doc.SchemaId)
+	status := &Status{
+		ServiceId:     doc.ServiceId,
+		SchemaId:      doc.SchemaId,
+		ServiceName:   doc.ServiceName,
+		Name:          doc.Name(),
+		State:         state,
+		Attempts:      attempts,
+		Timestamp:     time.Now(),
+		domainProject: doc.DomainProject,
+	}
+	if err != nil {
+		status.Error = err.Error()
+	}
+	p.mux.Lock()
+	p.statuses[key] = status
+	switch state {
+	case STATE_PUSHED:
+		p.docs[key] = doc
+	case STATE_DELETED:
+		delete(p.docs, key)
+	}
+	p.mux.Unlock()
+}
+
+// Statuses returns the push statuses of the domain project, filtered by
+// the service id if it is not empty
+func (p *Pusher) Statuses(domainProject, serviceId string) []*Status {
+	p.mux.RLock()
+	statuses := make([]*Status, 0, len(p.statuses))
+	for _, status := range p.statuses {
+		if status.domainProject != domainProject {
+			continue
+		}
+		if len(serviceId) > 0 && status.ServiceId != serviceId {
+			continue
+		}
+		s := *status
+		statuses = append(statuses, &s)
+	}
+	p.mux.RUnlock()
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].ServiceId != statuses[j].ServiceId {
+			return statuses[i].ServiceId < statuses[j].ServiceId
+		}
+		return statuses[i].SchemaId < statuses[j].SchemaId
+	})
+	return statuses
+}
+
+func NewPusher(client Client, retryTimes int, retryInterval time.Duration) *Pusher {
+	return &Pusher{
+		Client:        client,
+		RetryTimes:    retryTimes,
+		RetryInterval: retryInterval,
+		tasks:         make(chan *task, queueSize),
+		statuses:      make(map[string]*Status),
+		docs:          make(map[string]*Document),
+	}
+}
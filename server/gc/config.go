@@ -0,0 +1,71 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package gc
+
+import (
+	roa "github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/server/job"
+	"github.com/astaxie/beego"
+	"golang.org/x/net/context"
+	"time"
+)
+
+const DEFAULT_INTERVAL = time.Hour
+
+var (
+	cfg       Config
+	collector *Collector
+)
+
+func init() {
+	cfg = LoadConfig()
+	if !cfg.Enabled {
+		return
+	}
+	collector = NewCollector(cfg)
+	roa.RegisterServant(&GCController{})
+	job.Register(job.Job{
+		Name:     "gc",
+		Interval: cfg.Interval,
+		Run: func(ctx context.Context) error {
+			_, err := collector.TryCollect(ctx)
+			return err
+		},
+	})
+}
+
+type Config struct {
+	Enabled bool
+	// Interval is how often the orphans are scanned
+	Interval time.Duration
+	// Delete removes the orphans found by two successive scans, the
+	// orphans are only reported if false
+	Delete bool
+}
+
+func LoadConfig() Config {
+	c := Config{
+		Enabled:  beego.AppConfig.DefaultInt("gc_orphans", 0) != 0,
+		Interval: DEFAULT_INTERVAL,
+		Delete:   beego.AppConfig.DefaultInt("gc_orphans_delete", 0) != 0,
+	}
+	d, err := time.ParseDuration(beego.AppConfig.DefaultString("gc_interval", ""))
+	if err == nil && d >= time.Minute {
+		c.Interval = d
+	}
+	return c
+}
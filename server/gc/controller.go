@@ -0,0 +1,71 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package gc
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/core"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/rest/controller"
+	"net/http"
+)
+
+// GCController serves the orphans reports to the admin
+type GCController struct {
+}
+
+func (ctrl *GCController) URLPatterns() []rest.Route {
+	return []rest.Route{
+		{rest.HTTP_METHOD_GET, "/v4/:project/admin/gc/orphans", ctrl.GetReport},
+		{rest.HTTP_METHOD_POST, "/v4/:project/admin/gc/orphans", ctrl.Collect},
+	}
+}
+
+// GetReport returns the report of the last round run by this instance
+func (ctrl *GCController) GetReport(w http.ResponseWriter, r *http.Request) {
+	if !core.IsDefaultDomainProject(util.ParseDomainProject(r.Context())) {
+		controller.WriteError(w, scerr.ErrForbidden, "Required admin permission")
+		return
+	}
+	report := collector.Last()
+	if report == nil {
+		report = &Report{Counts: map[string]int{}}
+	}
+	controller.WriteResponse(w, nil, report)
+}
+
+// Collect runs a round at once, the orphans are deleted as the scheduled
+// round does
+func (ctrl *GCController) Collect(w http.ResponseWriter, r *http.Request) {
+	if !core.IsDefaultDomainProject(util.ParseDomainProject(r.Context())) {
+		controller.WriteError(w, scerr.ErrForbidden, "Required admin permission")
+		return
+	}
+	report, err := collector.TryCollect(r.Context())
+	switch {
+	case err == ErrNotLeader:
+		controller.WriteError(w, scerr.ErrForbidden, err.Error())
+	case err != nil:
+		log.Errorf(err, "orphans gc failed")
+		controller.WriteError(w, scerr.ErrUnavailableBackend, err.Error())
+	default:
+		log.Infof("orphans gc is run by %s", util.GetIPFromContext(r.Context()))
+		controller.WriteResponse(w, nil, report)
+	}
+}
@@ -0,0 +1,333 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package gc
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/core/backend"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"github.com/apache/servicecomb-service-center/server/mux"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	"github.com/coreos/etcd/mvcc/mvccpb"
+	"golang.org/x/net/context"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	TYPE_TAG             = "tag"
+	TYPE_RULE            = "rule"
+	TYPE_RULE_INDEX      = "ruleIndex"
+	TYPE_SCHEMA          = "schema"
+	TYPE_SCHEMA_SUMMARY  = "schemaSummary"
+	TYPE_DEPENDENCY_RULE = "dependencyRule"
+)
+
+// MAX_REPORT_ORPHANS limits the orphans listed in the report, the counts
+// are always complete
+const MAX_REPORT_ORPHANS = 1000
+
+var ErrNotLeader = errors.New("the gc is running by another service center instance")
+
+var ResourceTypes = []string{TYPE_TAG, TYPE_RULE, TYPE_RULE_INDEX, TYPE_SCHEMA,
+	TYPE_SCHEMA_SUMMARY, TYPE_DEPENDENCY_RULE}
+
+// serviceResources are the resources keyed by
+// {root}/{domain}/{project}/{serviceId}[/...]
+var serviceResources = []struct {
+	Type    string
+	RootKey func(domainProject string) string
+}{
+	{TYPE_TAG, core.GetServiceTagRootKey},
+	{TYPE_RULE, core.GetServiceRuleRootKey},
+	{TYPE_RULE_INDEX, core.GetServiceRuleIndexRootKey},
+	{TYPE_SCHEMA, core.GetServiceSchemaRootKey},
+	{TYPE_SCHEMA_SUMMARY, core.GetServiceSchemaSummaryRootKey},
+}
+
+type Orphan struct {
+	Type string `json:"type"`
+	Key  string `json:"key"`
+	Rev  int64  `json:"rev"`
+	// Parent is the service id or the service key the resource belongs to
+	Parent  string `json:"parent"`
+	Deleted bool   `json:"deleted,omitempty"`
+	// cmps make sure the parent does not come back and the resource is
+	// not changed since the scan when deleting
+	cmps []registry.CompareOp
+}
+
+type Report struct {
+	Revision int64          `json:"revision"`
+	StartAt  string         `json:"startAt"`
+	FinishAt string         `json:"finishAt"`
+	Counts   map[string]int `json:"counts"`
+	Orphans  []*Orphan      `json:"orphans,omitempty"`
+	Error    string         `json:"error,omitempty"`
+}
+
+// Collector finds the resources whose parent service no longer exists,
+// the orphan is deleted only if it is found unchanged by two successive
+// scans, so the resources written during a service creation are safe
+type Collector struct {
+	Cfg Config
+
+	lock     sync.RWMutex
+	last     *Report
+	suspects map[string]int64
+}
+
+// TryCollect runs a round if no other instance is running the gc
+func (c *Collector) TryCollect(ctx context.Context) (*Report, error) {
+	lock, err := mux.Try(mux.GC_LOCK)
+	if lock == nil {
+		log.Debugf("can not run the orphans gc by this service center instance now, %v", err)
+		return nil, ErrNotLeader
+	}
+	defer lock.Unlock()
+	return c.Collect(ctx)
+}
+
+func (c *Collector) Collect(ctx context.Context) (*Report, error) {
+	report := &Report{
+		StartAt: time.Now().UTC().Format(time.RFC3339),
+		Counts:  make(map[string]int, len(ResourceTypes)),
+	}
+	orphans, rev, err := Scan(ctx)
+	if err != nil {
+		report.Error = err.Error()
+		report.FinishAt = time.Now().UTC().Format(time.RFC3339)
+		c.lock.Lock()
+		c.last = report
+		c.lock.Unlock()
+		return report, err
+	}
+	report.Revision = rev
+
+	c.lock.RLock()
+	last := c.suspects
+	c.lock.RUnlock()
+
+	suspects := make(map[string]int64, len(orphans))
+	for _, o := range orphans {
+		report.Counts[o.Type]++
+		if r, ok := last[o.Key]; ok && r == o.Rev && c.Cfg.Delete {
+			o.Deleted = remove(ctx, o)
+		}
+		if !o.Deleted {
+			suspects[o.Key] = o.Rev
+		}
+	}
+	if len(orphans) > MAX_REPORT_ORPHANS {
+		orphans = orphans[:MAX_REPORT_ORPHANS]
+	}
+	report.Orphans = orphans
+	report.FinishAt = time.Now().UTC().Format(time.RFC3339)
+	if backend.IsDryRun(ctx) {
+		// the orphans reported deleted are the ones the round would delete
+		return report, nil
+	}
+
+	c.lock.Lock()
+	c.suspects, c.last = suspects, report
+	c.lock.Unlock()
+
+	ReportOrphans(report.Counts)
+	if len(suspects) > 0 {
+		log.Warnf("found orphaned resources %v at revision %d", report.Counts, rev)
+	}
+	return report, nil
+}
+
+// Last returns the report of the last round, nil if it never runs
+func (c *Collector) Last() *Report {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.last
+}
+
+// remove deletes the orphan and writes the audit log
+func remove(ctx context.Context, o *Orphan) bool {
+	resp, err := backend.Registry().TxnWithCmp(ctx,
+		[]registry.PluginOp{registry.OpDel(registry.WithStrKey(o.Key))},
+		append(o.cmps, registry.OpCmp(registry.CmpStrModRev(o.Key), registry.CMP_EQUAL, o.Rev)),
+		nil)
+	if err != nil {
+		log.Errorf(err, "gc delete orphaned %s %s failed", o.Type, o.Key)
+		return false
+	}
+	if !resp.Succeeded {
+		log.Warnf("gc skip orphaned %s %s, it is changed or the parent %s comes back",
+			o.Type, o.Key, o.Parent)
+		return false
+	}
+	if backend.IsDryRun(ctx) {
+		return true
+	}
+	log.Warnf("audit: gc deleted orphaned %s %s, parent %s does not exist, mod revision %d",
+		o.Type, o.Key, o.Parent, o.Rev)
+	ReportDeleted(o.Type)
+	return true
+}
+
+// Scan lists the orphans at the same revision, the revision is returned
+func Scan(ctx context.Context) ([]*Orphan, int64, error) {
+	serviceRoot := core.GetServiceRootKey("")
+	resp, err := list(ctx, serviceRoot, 0, true)
+	if err != nil {
+		return nil, 0, err
+	}
+	rev := resp.Revision
+	services := keySet(resp.Kvs, serviceRoot)
+
+	indexRoot := core.GetServiceIndexRootKey("")
+	resp, err = list(ctx, indexRoot, rev, true)
+	if err != nil {
+		return nil, 0, err
+	}
+	indexes := keySet(resp.Kvs, indexRoot)
+
+	var orphans []*Orphan
+	for _, res := range serviceResources {
+		root := res.RootKey("")
+		resp, err := list(ctx, root, rev, true)
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, kv := range resp.Kvs {
+			key := util.BytesToStringWithNoCopy(kv.Key)
+			arr := strings.SplitN(key[len(root):], core.SPLIT, 4)
+			if len(arr) < 3 {
+				continue
+			}
+			parent := strings.Join(arr[:3], core.SPLIT)
+			if _, ok := services[parent]; ok {
+				continue
+			}
+			orphans = append(orphans, &Orphan{
+				Type:   res.Type,
+				Key:    key,
+				Rev:    kv.ModRevision,
+				Parent: arr[2],
+				cmps:   notExist(serviceRoot + parent),
+			})
+		}
+	}
+
+	depOrphans, err := scanDependencyRules(ctx, rev, indexRoot, indexes)
+	if err != nil {
+		return nil, 0, err
+	}
+	return append(orphans, depOrphans...), rev, nil
+}
+
+// scanDependencyRules finds the consumer rules of the services not
+// existing, and the provider rules none of whose consumers exist
+func scanDependencyRules(ctx context.Context, rev int64, indexRoot string,
+	indexes map[string]struct{}) ([]*Orphan, error) {
+	root := core.GetServiceDependencyRuleRootKey("")
+	resp, err := list(ctx, root, rev, false)
+	if err != nil {
+		return nil, err
+	}
+	var orphans []*Orphan
+	for _, kv := range resp.Kvs {
+		key := util.BytesToStringWithNoCopy(kv.Key)
+		arr := strings.Split(key[len(root):], core.SPLIT)
+		if len(arr) != 7 {
+			// the wildcard rules
+			continue
+		}
+		domainProject := strings.Join(arr[:2], core.SPLIT)
+		switch arr[2] {
+		case core.DEPS_CONSUMER:
+			parent := strings.Join(append([]string{domainProject}, arr[3:]...), core.SPLIT)
+			if _, ok := indexes[parent]; ok {
+				continue
+			}
+			orphans = append(orphans, &Orphan{
+				Type:   TYPE_DEPENDENCY_RULE,
+				Key:    key,
+				Rev:    kv.ModRevision,
+				Parent: parent,
+				cmps:   notExist(indexRoot + parent),
+			})
+		case core.DEPS_PROVIDER:
+			dep := &pb.MicroServiceDependency{}
+			if err := json.Unmarshal(kv.Value, dep); err != nil {
+				log.Errorf(err, "gc unmarshal the dependency rule %s failed", key)
+				continue
+			}
+			exist := false
+			for _, consumer := range dep.Dependency {
+				if _, ok := indexes[core.GenerateServiceIndexKey(consumer)[len(indexRoot):]]; ok {
+					exist = true
+					break
+				}
+			}
+			if exist {
+				continue
+			}
+			orphans = append(orphans, &Orphan{
+				Type:   TYPE_DEPENDENCY_RULE,
+				Key:    key,
+				Rev:    kv.ModRevision,
+				Parent: "consumers of " + strings.Join(arr[3:], core.SPLIT),
+			})
+		}
+	}
+	return orphans, nil
+}
+
+func list(ctx context.Context, prefix string, rev int64, keyOnly bool) (*registry.PluginResponse, error) {
+	opts := []registry.PluginOpOption{
+		registry.GET,
+		registry.WithStrKey(prefix),
+		registry.WithPrefix(),
+		registry.WithRev(rev),
+	}
+	if keyOnly {
+		opts = append(opts, registry.WithKeyOnly())
+	}
+	return backend.Registry().Do(ctx, opts...)
+}
+
+func keySet(kvs []*mvccpb.KeyValue, root string) map[string]struct{} {
+	set := make(map[string]struct{}, len(kvs))
+	for _, kv := range kvs {
+		set[util.BytesToStringWithNoCopy(kv.Key)[len(root):]] = struct{}{}
+	}
+	return set
+}
+
+func notExist(key string) []registry.CompareOp {
+	return []registry.CompareOp{
+		registry.OpCmp(registry.CmpStrVer(key), registry.CMP_EQUAL, 0),
+	}
+}
+
+func NewCollector(cfg Config) *Collector {
+	return &Collector{
+		Cfg:      cfg,
+		suspects: make(map[string]int64),
+	}
+}
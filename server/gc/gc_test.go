@@ -0,0 +1,100 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package gc
+
+import (
+	"github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/core/backend"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	"golang.org/x/net/context"
+	"testing"
+)
+
+func TestCollector_Collect(t *testing.T) {
+	ctx := context.Background()
+	key := core.GenerateServiceTagKey("gc_test/gc_test", "gc_orphan")
+	_, err := backend.Registry().Do(ctx, registry.PUT,
+		registry.WithStrKey(key), registry.WithStrValue(`{"a":"b"}`))
+	if err != nil {
+		t.Fatalf("TestCollector_Collect failed, %v", err)
+	}
+
+	exist := func() bool {
+		resp, err := backend.Registry().Do(ctx, registry.GET,
+			registry.WithStrKey(key), registry.WithCountOnly())
+		if err != nil {
+			t.Fatalf("TestCollector_Collect failed, %v", err)
+		}
+		return resp.Count > 0
+	}
+	found := func(report *Report) bool {
+		for _, o := range report.Orphans {
+			if o.Key == key {
+				return true
+			}
+		}
+		return false
+	}
+
+	c := NewCollector(Config{Delete: true})
+	report, err := c.Collect(ctx)
+	if err != nil || !found(report) || report.Counts[TYPE_TAG] == 0 {
+		t.Fatalf("TestCollector_Collect failed, %v, %v", report, err)
+	}
+	// found by the first scan only
+	if !exist() {
+		t.Fatalf("TestCollector_Collect failed")
+	}
+
+	// deleted by the second scan
+	report, err = c.Collect(ctx)
+	if err != nil || !found(report) || exist() {
+		t.Fatalf("TestCollector_Collect failed, %v, %v", report, err)
+	}
+	if c.Last() != report {
+		t.Fatalf("TestCollector_Collect failed")
+	}
+
+	report, err = c.Collect(ctx)
+	if err != nil || found(report) {
+		t.Fatalf("TestCollector_Collect failed, %v, %v", report, err)
+	}
+}
+
+func TestCollector_ReportOnly(t *testing.T) {
+	ctx := context.Background()
+	key := core.GenerateServiceTagKey("gc_test/gc_test", "gc_report_only")
+	_, err := backend.Registry().Do(ctx, registry.PUT,
+		registry.WithStrKey(key), registry.WithStrValue(`{"a":"b"}`))
+	if err != nil {
+		t.Fatalf("TestCollector_ReportOnly failed, %v", err)
+	}
+	defer backend.Registry().Do(ctx, registry.DEL, registry.WithStrKey(key))
+
+	c := NewCollector(Config{})
+	c.Collect(ctx)
+	report, err := c.Collect(ctx)
+	if err != nil {
+		t.Fatalf("TestCollector_ReportOnly failed, %v", err)
+	}
+	for _, o := range report.Orphans {
+		if o.Key == key && !o.Deleted {
+			return
+		}
+	}
+	t.Fatalf("TestCollector_ReportOnly failed, %v", report)
+}
@@ -0,0 +1,56 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package gc
+
+import (
+	"github.com/apache/servicecomb-service-center/server/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	orphansGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metric.FamilyName,
+			Subsystem: "gc",
+			Name:      "orphans",
+			Help:      "Orphaned resources found by the last scan",
+		}, []string{"instance", "type"})
+
+	deletedCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metric.FamilyName,
+			Subsystem: "gc",
+			Name:      "deleted_total",
+			Help:      "Counter of the orphaned resources deleted",
+		}, []string{"instance", "type"})
+)
+
+func init() {
+	prometheus.MustRegister(orphansGauge, deletedCounter)
+}
+
+func ReportOrphans(counts map[string]int) {
+	instance := metric.InstanceName()
+	for _, t := range ResourceTypes {
+		orphansGauge.WithLabelValues(instance, t).Set(float64(counts[t]))
+	}
+}
+
+func ReportDeleted(t string) {
+	instance := metric.InstanceName()
+	deletedCounter.WithLabelValues(instance, t).Inc()
+}
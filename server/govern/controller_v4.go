@@ -21,11 +21,16 @@ import (
 
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
 	"github.com/apache/servicecomb-service-center/server/core"
 	pb "github.com/apache/servicecomb-service-center/server/core/proto"
 	scerr "github.com/apache/servicecomb-service-center/server/error"
 	"github.com/apache/servicecomb-service-center/server/rest/controller"
+	"github.com/apache/servicecomb-service-center/server/service/lease"
+	"golang.org/x/net/context"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // GovernService 治理相关接口服务
@@ -40,6 +45,12 @@ func (governService *GovernServiceControllerV4) URLPatterns() []rest.Route {
 		{rest.HTTP_METHOD_GET, "/v4/:project/govern/relations", governService.GetGraph},
 		{rest.HTTP_METHOD_GET, "/v4/:project/govern/microservices", governService.GetAllServicesInfo},
 		{rest.HTTP_METHOD_GET, "/v4/:project/govern/apps", governService.GetAllApplications},
+		{rest.HTTP_METHOD_GET, "/v4/:project/govern/prometheus/targets", governService.GetPrometheusTargets},
+		{rest.HTTP_METHOD_GET, "/v4/:project/govern/top/services", governService.GetTopServices},
+		{rest.HTTP_METHOD_GET, "/v4/:project/govern/top/providers", governService.GetTopProviders},
+		{rest.HTTP_METHOD_GET, "/v4/:project/govern/top/heartbeats", governService.GetTopHeartbeats},
+		{rest.HTTP_METHOD_GET, "/v4/:project/govern/top/domains", governService.GetTopDomains},
+		{rest.HTTP_METHOD_GET, "/v4/:project/govern/leases/expired", governService.GetExpiredInstances},
 	}
 }
 
@@ -148,3 +159,98 @@ func (governService *GovernServiceControllerV4) GetAllApplications(w http.Respon
 	resp.Response = nil
 	controller.WriteResponse(w, respInternal, resp)
 }
+
+// GetPrometheusTargets serves the prometheus http_sd_config targets
+func (governService *GovernServiceControllerV4) GetPrometheusTargets(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+	filter := &TargetFilter{
+		AppId:       query.Get("appId"),
+		ServiceName: query.Get("serviceName"),
+		Environment: query.Get("env"),
+		Status:      query.Get("status"),
+	}
+	if tags := query.Get("tags"); len(tags) > 0 {
+		filter.Tags = strings.Split(tags, ",")
+	}
+	groups, err := PrometheusTargets(ctx, util.ParseDomainProject(ctx), filter)
+	if err != nil {
+		controller.WriteError(w, scerr.ErrInternal, err.Error())
+		return
+	}
+	controller.WriteResponse(w, nil, groups)
+}
+
+// topParam returns the n of the top n query, default is DEFAULT_TOP_N
+func topParam(r *http.Request) (int, bool) {
+	v := r.URL.Query().Get("n")
+	if len(v) == 0 {
+		return DEFAULT_TOP_N, true
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 || n > MAX_TOP_N {
+		return 0, false
+	}
+	return n, true
+}
+
+func writeTop(w http.ResponseWriter, r *http.Request,
+	f func(ctx context.Context, domainProject string, n int) (*TopResponse, error)) {
+	n, ok := topParam(r)
+	if !ok {
+		controller.WriteError(w, scerr.ErrInvalidParams, "parameter n must be 1 to "+strconv.Itoa(MAX_TOP_N))
+		return
+	}
+	ctx := r.Context()
+	resp, err := f(ctx, util.ParseDomainProject(ctx), n)
+	if err != nil {
+		log.Errorf(err, "get top %d failed", n)
+		controller.WriteError(w, scerr.ErrInternal, err.Error())
+		return
+	}
+	controller.WriteResponse(w, nil, resp)
+}
+
+// GetTopServices returns the services with most instances
+func (governService *GovernServiceControllerV4) GetTopServices(w http.ResponseWriter, r *http.Request) {
+	writeTop(w, r, TopServicesByInstances)
+}
+
+// GetTopProviders returns the providers with most consumers
+func (governService *GovernServiceControllerV4) GetTopProviders(w http.ResponseWriter, r *http.Request) {
+	writeTop(w, r, TopProvidersByConsumers)
+}
+
+// GetTopHeartbeats returns the services with the highest heartbeat rates
+func (governService *GovernServiceControllerV4) GetTopHeartbeats(w http.ResponseWriter, r *http.Request) {
+	writeTop(w, r, TopHeartbeatRates)
+}
+
+// GetTopDomains returns the domains by resource usage, it requires admin
+// permission
+func (governService *GovernServiceControllerV4) GetTopDomains(w http.ResponseWriter, r *http.Request) {
+	if !core.IsDefaultDomainProject(util.ParseDomainProject(r.Context())) {
+		controller.WriteError(w, scerr.ErrForbidden, "Required admin permission")
+		return
+	}
+	writeTop(w, r, func(ctx context.Context, _ string, n int) (*TopResponse, error) {
+		return TopDomains(ctx, n)
+	})
+}
+
+// GetExpiredInstances returns the instances removed by the lease
+// expiration in the last 'minutes', default is 10
+func (governService *GovernServiceControllerV4) GetExpiredInstances(w http.ResponseWriter, r *http.Request) {
+	minutes := DEFAULT_EXPIRED_MINUTES
+	max := int(lease.DEFAULT_RETENTION / time.Minute)
+	if v := r.URL.Query().Get("minutes"); len(v) > 0 {
+		m, err := strconv.Atoi(v)
+		if err != nil || m <= 0 || m > max {
+			controller.WriteError(w, scerr.ErrInvalidParams, "parameter minutes must be 1 to "+strconv.Itoa(max))
+			return
+		}
+		minutes = m
+	}
+	instances := lease.Expired(util.ParseDomainProject(r.Context()), time.Duration(minutes)*time.Minute)
+	controller.WriteResponse(w, nil, &ExpiredInstancesResponse{Instances: instances})
+}
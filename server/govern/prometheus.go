@@ -0,0 +1,149 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package govern
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	serviceUtil "github.com/apache/servicecomb-service-center/server/service/util"
+	"golang.org/x/net/context"
+	"net/url"
+	"strings"
+)
+
+// the prefix of the meta labels of the prometheus targets
+const promLabelPrefix = "__meta_servicecomb_"
+
+// TargetGroup is the item of the prometheus http_sd_config response
+type TargetGroup struct {
+	Targets []string          `json:"targets"`
+	Labels  map[string]string `json:"labels"`
+}
+
+// TargetFilter selects the services of the targets, the empty fields
+// match all. Tags are the 'key' or 'key=value' the services must have
+type TargetFilter struct {
+	AppId       string
+	ServiceName string
+	Environment string
+	Status      string
+	Tags        []string
+}
+
+func (f *TargetFilter) matchService(service *pb.MicroService) bool {
+	return (len(f.AppId) == 0 || f.AppId == service.AppId) &&
+		(len(f.ServiceName) == 0 || f.ServiceName == service.ServiceName) &&
+		(len(f.Environment) == 0 || f.Environment == service.Environment)
+}
+
+func (f *TargetFilter) matchTags(tags map[string]string) bool {
+	for _, tag := range f.Tags {
+		kv := strings.SplitN(tag, "=", 2)
+		v, ok := tags[kv[0]]
+		if !ok || (len(kv) == 2 && v != kv[1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// PrometheusTargets returns one target group per instance in the
+// domainProject, the labels are derived from the metadata of the
+// service, instance and the service tags
+func PrometheusTargets(ctx context.Context, domainProject string, filter *TargetFilter) ([]*TargetGroup, error) {
+	ctx = util.SetContext(ctx, serviceUtil.CTX_CACHEONLY, "1")
+	services, err := serviceUtil.GetServicesByDomainProject(ctx, domainProject)
+	if err != nil {
+		return nil, err
+	}
+	groups := []*TargetGroup{}
+	for _, service := range services {
+		if !filter.matchService(service) {
+			continue
+		}
+		tags, err := serviceUtil.GetTagsUtils(ctx, domainProject, service.ServiceId)
+		if err != nil {
+			return nil, err
+		}
+		if !filter.matchTags(tags) {
+			continue
+		}
+		instances, err := serviceUtil.GetAllInstancesOfOneService(ctx, domainProject, service.ServiceId)
+		if err != nil {
+			return nil, err
+		}
+		for _, instance := range instances {
+			if len(filter.Status) > 0 && filter.Status != instance.Status {
+				continue
+			}
+			if group := toTargetGroup(service, instance, tags); group != nil {
+				groups = append(groups, group)
+			}
+		}
+	}
+	return groups, nil
+}
+
+// toTargetGroup returns nil if the instance has no valid endpoint
+func toTargetGroup(service *pb.MicroService, instance *pb.MicroServiceInstance, tags map[string]string) *TargetGroup {
+	// prometheus scrapes the same metrics from every endpoint, so only
+	// the first valid one is the target
+	var endpoint *url.URL
+	for _, ep := range instance.Endpoints {
+		if u, err := url.Parse(ep); err == nil && len(u.Host) > 0 {
+			endpoint = u
+			break
+		}
+	}
+	if endpoint == nil {
+		return nil
+	}
+	scheme := "http"
+	if endpoint.Query().Get("sslEnabled") == "true" {
+		scheme = "https"
+	}
+
+	labels := map[string]string{
+		"__scheme__":                          scheme,
+		promLabelPrefix + "service_id":        service.ServiceId,
+		promLabelPrefix + "app_id":            service.AppId,
+		promLabelPrefix + "service_name":      service.ServiceName,
+		promLabelPrefix + "version":           service.Version,
+		promLabelPrefix + "environment":       service.Environment,
+		promLabelPrefix + "instance_id":       instance.InstanceId,
+		promLabelPrefix + "hostname":          instance.HostName,
+		promLabelPrefix + "status":            instance.Status,
+		promLabelPrefix + "endpoint_protocol": endpoint.Scheme,
+	}
+	for k, v := range tags {
+		labels[promLabelPrefix+"tag_"+promLabelName(k)] = v
+	}
+	for k, v := range instance.Properties {
+		labels[promLabelPrefix+"property_"+promLabelName(k)] = v
+	}
+	return &TargetGroup{Targets: []string{endpoint.Host}, Labels: labels}
+}
+
+// promLabelName replaces the chars not matching [a-zA-Z0-9_] with '_'
+func promLabelName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, name)
+}
@@ -212,6 +212,100 @@ var _ = Describe("'Govern' service", func() {
 		})
 	})
 
+	Describe("execute 'prometheus targets' operation", func() {
+		Context("when request is valid", func() {
+			It("should be passed", func() {
+				groups, err := govern.PrometheusTargets(getContext(), "default/default", &govern.TargetFilter{})
+				Expect(err).To(BeNil())
+				Expect(groups).ToNot(BeNil())
+
+				By("filter by a not exist tag")
+				groups, err = govern.PrometheusTargets(getContext(), "default/default", &govern.TargetFilter{
+					Tags: []string{"not_exist_tag=1"},
+				})
+				Expect(err).To(BeNil())
+				Expect(len(groups)).To(Equal(0))
+
+				svr := httptest.NewServer(&mockGovernHandler{func(w http.ResponseWriter, r *http.Request) {
+					ctrl := &govern.GovernServiceControllerV4{}
+					ctrl.GetPrometheusTargets(w, r.WithContext(getContext()))
+				}})
+				defer svr.Close()
+
+				resp, err := http.Get(svr.URL + "?tags=not_exist_tag")
+				Expect(err).To(BeNil())
+				body, err := ioutil.ReadAll(resp.Body)
+				resp.Body.Close()
+				Expect(err).To(BeNil())
+				Expect(resp.StatusCode).To(Equal(http.StatusOK))
+				Expect(string(body)).To(Equal("[]\n"))
+			})
+		})
+	})
+
+	Describe("execute 'top' operation", func() {
+		Context("when request is valid", func() {
+			It("should be passed", func() {
+				resp, err := govern.TopServicesByInstances(getContext(), "default/default", 1)
+				Expect(err).To(BeNil())
+				Expect(len(resp.Items) <= 1).To(BeTrue())
+				for _, item := range resp.Items {
+					Expect(item.Instances > 0).To(BeTrue())
+				}
+
+				resp, err = govern.TopHeartbeatRates(getContext(), "default/default", govern.DEFAULT_TOP_N)
+				Expect(err).To(BeNil())
+				Expect(resp.Total >= len(resp.Items)).To(BeTrue())
+
+				resp, err = govern.TopProvidersByConsumers(getContext(), "default/default", govern.DEFAULT_TOP_N)
+				Expect(err).To(BeNil())
+				Expect(resp.Total >= len(resp.Items)).To(BeTrue())
+
+				resp, err = govern.TopDomains(getContext(), govern.DEFAULT_TOP_N)
+				Expect(err).To(BeNil())
+				for _, item := range resp.Items {
+					Expect(len(item.DomainProject) > 0).To(BeTrue())
+				}
+			})
+		})
+		Context("when n is invalid", func() {
+			It("should be failed", func() {
+				svr := httptest.NewServer(&mockGovernHandler{func(w http.ResponseWriter, r *http.Request) {
+					ctrl := &govern.GovernServiceControllerV4{}
+					ctrl.GetTopServices(w, r.WithContext(getContext()))
+				}})
+				defer svr.Close()
+
+				resp, err := http.Get(svr.URL + "?n=0")
+				Expect(err).To(BeNil())
+				resp.Body.Close()
+				Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+
+	Describe("execute 'expired leases' operation", func() {
+		Context("when request is valid", func() {
+			It("should be passed", func() {
+				svr := httptest.NewServer(&mockGovernHandler{func(w http.ResponseWriter, r *http.Request) {
+					ctrl := &govern.GovernServiceControllerV4{}
+					ctrl.GetExpiredInstances(w, r.WithContext(getContext()))
+				}})
+				defer svr.Close()
+
+				resp, err := http.Get(svr.URL + "?minutes=5")
+				Expect(err).To(BeNil())
+				resp.Body.Close()
+				Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+				resp, err = http.Get(svr.URL + "?minutes=0")
+				Expect(err).To(BeNil())
+				resp.Body.Close()
+				Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+
 	Describe("execute all operations", func() {
 		Context("when request is valid", func() {
 			It("should be passed", func() {
@@ -0,0 +1,220 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package govern
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	apt "github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/core/backend"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	serviceUtil "github.com/apache/servicecomb-service-center/server/service/util"
+	"golang.org/x/net/context"
+	"sort"
+)
+
+const (
+	DEFAULT_TOP_N = 10
+	MAX_TOP_N     = 100
+)
+
+// TopItem is the aggregated resource usage of a service or a domain
+type TopItem struct {
+	DomainProject string `json:"domainProject,omitempty"`
+	ServiceId     string `json:"serviceId,omitempty"`
+	AppId         string `json:"appId,omitempty"`
+	ServiceName   string `json:"serviceName,omitempty"`
+	Version       string `json:"version,omitempty"`
+	Services      int64  `json:"services,omitempty"`
+	Instances     int64  `json:"instances,omitempty"`
+	Consumers     int64  `json:"consumers,omitempty"`
+	// HeartbeatRate is the heartbeats per second expected by the
+	// health check settings of the instances
+	HeartbeatRate float64 `json:"heartbeatRate,omitempty"`
+}
+
+type TopResponse struct {
+	// Total is the count of the items before truncated
+	Total int        `json:"total"`
+	Items []*TopItem `json:"items"`
+}
+
+func newServiceItem(service *pb.MicroService) *TopItem {
+	return &TopItem{
+		ServiceId:   service.ServiceId,
+		AppId:       service.AppId,
+		ServiceName: service.ServiceName,
+		Version:     service.Version,
+	}
+}
+
+// topN sorts the items with non zero value in descending order and
+// returns the first n ones
+func topN(items []*TopItem, n int, value func(*TopItem) float64) *TopResponse {
+	l := make([]*TopItem, 0, len(items))
+	for _, item := range items {
+		if value(item) > 0 {
+			l = append(l, item)
+		}
+	}
+	sort.SliceStable(l, func(i, j int) bool {
+		if vi, vj := value(l[i]), value(l[j]); vi != vj {
+			return vi > vj
+		}
+		if l[i].Services != l[j].Services {
+			return l[i].Services > l[j].Services
+		}
+		return l[i].DomainProject+l[i].ServiceId < l[j].DomainProject+l[j].ServiceId
+	})
+	resp := &TopResponse{Total: len(l), Items: l}
+	if n < len(l) {
+		resp.Items = l[:n]
+	}
+	return resp
+}
+
+// heartbeatRate returns the heartbeats per second of the instance
+func heartbeatRate(instance *pb.MicroServiceInstance) float64 {
+	hc := instance.HealthCheck
+	if hc == nil || hc.Mode != pb.CHECK_BY_HEARTBEAT || hc.Interval <= 0 {
+		return 0
+	}
+	return 1 / float64(hc.Interval)
+}
+
+func instancesByService(ctx context.Context, domainProject string) (map[string][]*pb.MicroServiceInstance, error) {
+	opts := append(serviceUtil.FromContext(ctx),
+		registry.WithStrKey(apt.GetInstanceRootKey(domainProject)+"/"),
+		registry.WithPrefix())
+	resp, err := backend.Store().Instance().Search(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string][]*pb.MicroServiceInstance)
+	for _, kv := range resp.Kvs {
+		serviceId, _, _ := apt.GetInfoFromInstKV(kv.Key)
+		m[serviceId] = append(m[serviceId], kv.Value.(*pb.MicroServiceInstance))
+	}
+	return m, nil
+}
+
+// serviceItems returns the items of the services with the instances
+// count and the heartbeat rate
+func serviceItems(ctx context.Context, domainProject string) ([]*TopItem, error) {
+	ctx = util.SetContext(ctx, serviceUtil.CTX_CACHEONLY, "1")
+	services, err := serviceUtil.GetServicesByDomainProject(ctx, domainProject)
+	if err != nil {
+		return nil, err
+	}
+	instances, err := instancesByService(ctx, domainProject)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]*TopItem, 0, len(services))
+	for _, service := range services {
+		item := newServiceItem(service)
+		for _, instance := range instances[service.ServiceId] {
+			item.Instances++
+			item.HeartbeatRate += heartbeatRate(instance)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// TopServicesByInstances returns the services with most instances
+func TopServicesByInstances(ctx context.Context, domainProject string, n int) (*TopResponse, error) {
+	items, err := serviceItems(ctx, domainProject)
+	if err != nil {
+		return nil, err
+	}
+	return topN(items, n, func(item *TopItem) float64 { return float64(item.Instances) }), nil
+}
+
+// TopHeartbeatRates returns the services receiving most heartbeats
+func TopHeartbeatRates(ctx context.Context, domainProject string, n int) (*TopResponse, error) {
+	items, err := serviceItems(ctx, domainProject)
+	if err != nil {
+		return nil, err
+	}
+	return topN(items, n, func(item *TopItem) float64 { return item.HeartbeatRate }), nil
+}
+
+// TopProvidersByConsumers returns the providers with most consumers
+func TopProvidersByConsumers(ctx context.Context, domainProject string, n int) (*TopResponse, error) {
+	ctx = util.SetContext(ctx, serviceUtil.CTX_CACHEONLY, "1")
+	services, err := serviceUtil.GetServicesByDomainProject(ctx, domainProject)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]*TopItem, 0, len(services))
+	for _, service := range services {
+		consumerIds, err := serviceUtil.GetConsumerIds(ctx, domainProject, service)
+		if err != nil {
+			return nil, err
+		}
+		item := newServiceItem(service)
+		item.Consumers = int64(len(consumerIds))
+		items = append(items, item)
+	}
+	return topN(items, n, func(item *TopItem) float64 { return float64(item.Consumers) }), nil
+}
+
+// TopDomains returns the domain projects with most instances, it counts
+// the keys of all the domains in cache
+func TopDomains(ctx context.Context, n int) (*TopResponse, error) {
+	ctx = util.SetContext(ctx, serviceUtil.CTX_CACHEONLY, "1")
+	opts := append(serviceUtil.FromContext(ctx), registry.WithPrefix(), registry.WithKeyOnly())
+
+	respSvc, err := backend.Store().Service().Search(ctx,
+		append(opts, registry.WithStrKey(apt.GetServiceRootKey("")))...)
+	if err != nil {
+		return nil, err
+	}
+	respIns, err := backend.Store().Instance().Search(ctx,
+		append(opts, registry.WithStrKey(apt.GetInstanceRootKey("")))...)
+	if err != nil {
+		return nil, err
+	}
+
+	domains := make(map[string]*TopItem)
+	itemOf := func(domainProject string) *TopItem {
+		item, ok := domains[domainProject]
+		if !ok {
+			item = &TopItem{DomainProject: domainProject}
+			domains[domainProject] = item
+		}
+		return item
+	}
+	for _, kv := range respSvc.Kvs {
+		_, domainProject := apt.GetInfoFromSvcKV(kv.Key)
+		itemOf(domainProject).Services++
+	}
+	for _, kv := range respIns.Kvs {
+		_, _, domainProject := apt.GetInfoFromInstKV(kv.Key)
+		itemOf(domainProject).Instances++
+	}
+
+	items := make([]*TopItem, 0, len(domains))
+	for _, item := range domains {
+		items = append(items, item)
+	}
+	// the domains are ordered by the count of services and instances
+	return topN(items, n, func(item *TopItem) float64 {
+		return float64(item.Instances + item.Services)
+	}), nil
+}
@@ -37,7 +37,7 @@ func (h *AuthRequest) Handle(i *chain.Invocation) {
 		return
 	}
 
-	log.Errorf(err, "authenticate request failed, %s %s", r.Method, r.RequestURI)
+	log.WithContext(r.Context()).Errorf(err, "authenticate request failed, %s %s", r.Method, r.RequestURI)
 
 	w := i.Context().Value(rest.CTX_RESPONSE).(http.ResponseWriter)
 	controller.WriteError(w, scerr.ErrUnauthorized, err.Error())
@@ -17,27 +17,94 @@
 package cache
 
 import (
+	"fmt"
 	"github.com/apache/servicecomb-service-center/pkg/chain"
 	"github.com/apache/servicecomb-service-center/pkg/rest"
 	"github.com/apache/servicecomb-service-center/pkg/util"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/metric"
+	"github.com/apache/servicecomb-service-center/server/rest/controller"
 	serviceUtil "github.com/apache/servicecomb-service-center/server/service/util"
+	"github.com/prometheus/client_golang/prometheus"
 	"net/http"
+	"net/url"
 )
 
+const (
+	QUERY_CONSISTENCY = "consistency"
+
+	// CONSISTENCY_LINEARIZABLE reads from etcd by quorum, it is for the
+	// critical callers which can not accept the stale cache
+	CONSISTENCY_LINEARIZABLE = "linearizable"
+	// CONSISTENCY_CACHE reads from the local cache only
+	CONSISTENCY_CACHE = "cache"
+)
+
+var linearizableReads = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: metric.FamilyName,
+		Subsystem: "http",
+		Name:      "linearizable_reads_total",
+		Help:      "Counter of the read requests with the linearizable consistency",
+	}, []string{"method", "instance", "api", "domain"})
+
+func init() {
+	prometheus.MustRegister(linearizableReads)
+}
+
 type CacheResponse struct {
 }
 
 func (l *CacheResponse) Handle(i *chain.Invocation) {
-	defer i.Next()
-
 	r := i.Context().Value(rest.CTX_REQUEST).(*http.Request)
 	query := r.URL.Query()
 
+	consistency, err := parseConsistency(query)
+	if err != nil {
+		w := i.Context().Value(rest.CTX_RESPONSE).(http.ResponseWriter)
+		controller.WriteError(w, scerr.ErrInvalidParams, err.Error())
+		i.Fail(nil)
+		return
+	}
+
+	if consistency == CONSISTENCY_LINEARIZABLE && r.Method == http.MethodGet {
+		pattern, _ := i.Context().Value(rest.CTX_MATCH_PATTERN).(string)
+		linearizableReads.WithLabelValues(r.Method, metric.InstanceName(), pattern,
+			util.ParseDomain(r.Context())).Inc()
+	}
+
+	withCacheContext(i, r, query, consistency)
+	i.Next()
+}
+
+// parseConsistency returns the consistency level of the request, it is
+// empty if the request does not specify it
+func parseConsistency(query url.Values) (string, error) {
+	switch v := query.Get(QUERY_CONSISTENCY); v {
+	case "", CONSISTENCY_LINEARIZABLE, CONSISTENCY_CACHE:
+		return v, nil
+	default:
+		return "", fmt.Errorf("invalid %s '%s', must be '%s' or '%s'",
+			QUERY_CONSISTENCY, v, CONSISTENCY_LINEARIZABLE, CONSISTENCY_CACHE)
+	}
+}
+
+func withCacheContext(i *chain.Invocation, r *http.Request, query url.Values, consistency string) {
 	global := util.StringTRUE(query.Get(serviceUtil.CTX_GLOBAL))
 	if global && r.Method == http.MethodGet {
 		i.WithContext(serviceUtil.CTX_GLOBAL, "1")
 	}
 
+	// the consistency level takes precedence over noCache and cacheOnly
+	switch consistency {
+	case CONSISTENCY_LINEARIZABLE:
+		i.WithContext(serviceUtil.CTX_NOCACHE, "1")
+		return
+	case CONSISTENCY_CACHE:
+		i.WithContext(serviceUtil.CTX_CACHEONLY, "1")
+		return
+	}
+
 	noCache := util.StringTRUE(query.Get(serviceUtil.CTX_NOCACHE))
 	if noCache {
 		i.WithContext(serviceUtil.CTX_NOCACHE, "1")
@@ -55,6 +122,17 @@ func (l *CacheResponse) Handle(i *chain.Invocation) {
 		i.WithContext(serviceUtil.CTX_REQUEST_REVISION, rev)
 		return
 	}
+
+	if r.Method != http.MethodGet {
+		return
+	}
+	// the first entity tag is taken as the revision the client holds
+	for _, rev := range rest.ParseIfNoneMatch(r.Header.Get(rest.HEADER_IF_NONE_MATCH)) {
+		if rev != rest.ETAG_ANY {
+			i.WithContext(serviceUtil.CTX_REQUEST_REVISION, rev)
+			return
+		}
+	}
 }
 
 func RegisterHandlers() {
@@ -29,7 +29,7 @@ type v4Context struct {
 }
 
 func (v *v4Context) IsMatch(r *http.Request) bool {
-	return strings.Index(r.RequestURI, "/v4/") == 0
+	return strings.Index(r.RequestURI, "/v4/") == 0 || strings.Index(r.RequestURI, "/v4.1/") == 0
 }
 
 func (v *v4Context) Do(r *http.Request) error {
@@ -0,0 +1,149 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package deprecation
+
+import (
+	"fmt"
+	"github.com/apache/servicecomb-service-center/pkg/chain"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/metric"
+	"github.com/astaxie/beego"
+	"github.com/prometheus/client_golang/prometheus"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	HEADER_DEPRECATION = "Deprecation"
+	HEADER_SUNSET      = "Sunset"
+	HEADER_LINK        = "Link"
+
+	DATE_FORMAT = "2006-01-02"
+)
+
+var deprecatedRequests = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: metric.FamilyName,
+		Subsystem: "http",
+		Name:      "deprecated_total",
+		Help:      "Counter of requests to the deprecated APIs",
+	}, []string{"method", "instance", "api", "domain"})
+
+func init() {
+	prometheus.MustRegister(deprecatedRequests)
+}
+
+// Entry deprecates the routes of the pattern prefix, the routes of all
+// methods are deprecated if the method is empty
+type Entry struct {
+	Method string
+	Prefix string
+	// Sunset is when the routes are removed, zero if it is not planned
+	Sunset time.Time
+}
+
+func (e *Entry) Match(method, pattern string) bool {
+	return (len(e.Method) == 0 || e.Method == method) && strings.HasPrefix(pattern, e.Prefix)
+}
+
+// ParseTable parses the comma separated entries '[METHOD ]prefix[=YYYY-MM-DD]',
+// e.g. '/registry/v3/=2020-12-31,DELETE /v4/:project/registry/microservices/:serviceId'
+func ParseTable(s string) ([]*Entry, error) {
+	var table []*Entry
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if len(item) == 0 {
+			continue
+		}
+		e := &Entry{}
+		if i := strings.Index(item, "="); i >= 0 {
+			sunset, err := time.Parse(DATE_FORMAT, strings.TrimSpace(item[i+1:]))
+			if err != nil {
+				return nil, fmt.Errorf("invalid sunset date of '%s'", item)
+			}
+			e.Sunset, item = sunset, strings.TrimSpace(item[:i])
+		}
+		if i := strings.Index(item, " "); i >= 0 {
+			e.Method, item = strings.ToUpper(item[:i]), strings.TrimSpace(item[i+1:])
+		}
+		if len(item) == 0 || item[0] != '/' {
+			return nil, fmt.Errorf("invalid prefix of '%s'", item)
+		}
+		e.Prefix = item
+		table = append(table, e)
+	}
+	return table, nil
+}
+
+// DeprecationHandler attaches the Deprecation and Sunset headers to the
+// responses of the deprecated routes and counts the requests of them
+type DeprecationHandler struct {
+	Table []*Entry
+	// Link is the document of the deprecations and the migrations
+	Link string
+}
+
+func (h *DeprecationHandler) match(method, pattern string) *Entry {
+	for _, e := range h.Table {
+		if e.Match(method, pattern) {
+			return e
+		}
+	}
+	return nil
+}
+
+func (h *DeprecationHandler) Handle(i *chain.Invocation) {
+	r := i.Context().Value(rest.CTX_REQUEST).(*http.Request)
+	pattern, _ := i.Context().Value(rest.CTX_MATCH_PATTERN).(string)
+	e := h.match(r.Method, pattern)
+	if e == nil {
+		i.Next()
+		return
+	}
+
+	header := i.Context().Value(rest.CTX_RESPONSE).(http.ResponseWriter).Header()
+	header.Set(HEADER_DEPRECATION, "true")
+	if !e.Sunset.IsZero() {
+		header.Set(HEADER_SUNSET, e.Sunset.UTC().Format(http.TimeFormat))
+	}
+	if len(h.Link) > 0 {
+		header.Add(HEADER_LINK, "<"+h.Link+">; rel=\"deprecation\"")
+	}
+
+	i.Next(chain.WithAsyncFunc(func(ret chain.Result) {
+		deprecatedRequests.WithLabelValues(r.Method, metric.InstanceName(), pattern,
+			util.ParseDomain(r.Context())).Inc()
+	}))
+}
+
+func RegisterHandlers() {
+	table, err := ParseTable(beego.AppConfig.String("api_deprecations"))
+	if err != nil {
+		log.Errorf(err, "invalid api_deprecations, ignore it")
+		return
+	}
+	if len(table) == 0 {
+		return
+	}
+	chain.RegisterHandler(rest.SERVER_CHAIN_NAME, &DeprecationHandler{
+		Table: table,
+		Link:  beego.AppConfig.String("api_deprecation_link"),
+	})
+}
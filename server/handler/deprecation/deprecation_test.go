@@ -0,0 +1,51 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package deprecation
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTable(t *testing.T) {
+	table, err := ParseTable(" /registry/v3/=2020-12-31, delete /v4/:project/registry/microservices/:serviceId ,")
+	if err != nil || len(table) != 2 {
+		t.Fatalf("TestParseTable failed, %v, %v", table, err)
+	}
+	if table[0].Method != "" || table[0].Prefix != "/registry/v3/" ||
+		!table[0].Sunset.Equal(time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("TestParseTable failed, %v", table[0])
+	}
+	if table[1].Method != "DELETE" || table[1].Prefix != "/v4/:project/registry/microservices/:serviceId" ||
+		!table[1].Sunset.IsZero() {
+		t.Fatalf("TestParseTable failed, %v", table[1])
+	}
+
+	if !table[0].Match("GET", "/registry/v3/microservices") || table[0].Match("GET", "/v4/:project/registry/microservices") {
+		t.Fatalf("TestParseTable failed")
+	}
+	if !table[1].Match("DELETE", "/v4/:project/registry/microservices/:serviceId") ||
+		table[1].Match("GET", "/v4/:project/registry/microservices/:serviceId") {
+		t.Fatalf("TestParseTable failed")
+	}
+
+	for _, s := range []string{"/registry/v3/=2020-13-01", "registry/v3/", "GET "} {
+		if _, err := ParseTable(s); err == nil {
+			t.Fatalf("TestParseTable failed, %s", s)
+		}
+	}
+}
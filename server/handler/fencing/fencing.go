@@ -0,0 +1,111 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fencing
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/apache/servicecomb-service-center/pkg/chain"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/core/backend"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/rest/controller"
+	"net/http"
+	"strconv"
+)
+
+const (
+	HEADER_LOCK_NAME     = "X-Lock-Name"
+	HEADER_FENCING_TOKEN = "X-Fencing-Token"
+)
+
+// bufferWriter holds the response of the API until the fence is known
+// not stale
+type bufferWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferWriter) WriteHeader(status int) {
+	w.status = status
+}
+
+func (w *bufferWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+// FencingHandler makes the writes of the request carrying the lock name
+// and the fencing token conditional on the lock, the request is rejected
+// if the lock is not held by the token any longer
+type FencingHandler struct {
+}
+
+func (h *FencingHandler) Handle(i *chain.Invocation) {
+	r := i.Context().Value(rest.CTX_REQUEST).(*http.Request)
+	name := r.Header.Get(HEADER_LOCK_NAME)
+	if r.Method == http.MethodGet || len(name) == 0 {
+		i.Next()
+		return
+	}
+
+	w := i.Context().Value(rest.CTX_RESPONSE).(http.ResponseWriter)
+	token, err := strconv.ParseInt(r.Header.Get(HEADER_FENCING_TOKEN), 10, 64)
+	if err != nil || token <= 0 {
+		controller.WriteError(w, scerr.ErrInvalidParams, "invalid "+HEADER_FENCING_TOKEN)
+		i.Fail(nil)
+		return
+	}
+
+	f := backend.NewFence(core.GenerateClientLockKey(util.ParseDomainProject(r.Context()), name), token)
+	// reject the stale holder at once, the fence in the writes covers the
+	// lock lost during the request
+	if err := backend.CheckFence(r.Context(), f); err != nil {
+		writeError(w, name, token, err)
+		i.Fail(nil)
+		return
+	}
+
+	util.SetRequestContext(r, backend.CTX_FENCE, f)
+	bw := &bufferWriter{ResponseWriter: w, status: http.StatusOK}
+	i.WithContext(rest.CTX_RESPONSE, bw)
+	i.Next(chain.WithFunc(func(ret chain.Result) {
+		if f.Stale() {
+			writeError(w, name, token, backend.ErrStaleFence)
+			return
+		}
+		w.WriteHeader(bw.status)
+		w.Write(bw.body.Bytes())
+	}))
+}
+
+func writeError(w http.ResponseWriter, name string, token int64, err error) {
+	if err != backend.ErrStaleFence {
+		log.Errorf(err, "check the fencing token %d of lock %s failed", token, name)
+		controller.WriteError(w, scerr.ErrUnavailableBackend, err.Error())
+		return
+	}
+	log.Warnf("reject the write of the stale holder of lock %s, fencing token %d", name, token)
+	controller.WriteError(w, scerr.ErrLockNotHeld, fmt.Sprintf("fencing token %d of lock '%s' is stale", token, name))
+}
+
+func RegisterHandlers() {
+	chain.RegisterHandler(rest.SERVER_CHAIN_NAME, &FencingHandler{})
+}
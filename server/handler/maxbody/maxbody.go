@@ -17,10 +17,16 @@
 package maxbody
 
 import (
+	"compress/gzip"
 	"github.com/apache/servicecomb-service-center/pkg/chain"
+	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/pkg/rest"
 	"github.com/apache/servicecomb-service-center/server/core"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/rest/controller"
+	"io"
 	"net/http"
+	"strings"
 )
 
 const (
@@ -30,17 +36,50 @@ const (
 )
 
 var resourcesMap = map[string]int64{
-	"/registry/v3/microservices":          microserviceSize,
-	"/v4/:project/registry/microservices": microserviceSize,
+	"/registry/v3/microservices":            microserviceSize,
+	"/v4/:project/registry/microservices":   microserviceSize,
+	"/v4.1/:project/registry/microservices": microserviceSize,
 
-	"/registry/v3/microservices/:serviceId/instances":          instanceSize,
-	"/v4/:project/registry/microservices/:serviceId/instances": instanceSize,
+	"/registry/v3/microservices/:serviceId/instances":            instanceSize,
+	"/v4/:project/registry/microservices/:serviceId/instances":   instanceSize,
+	"/v4.1/:project/registry/microservices/:serviceId/instances": instanceSize,
 
-	"/registry/v3/microservices/:serviceId/properties":          propertiesSize,
-	"/v4/:project/registry/microservices/:serviceId/properties": propertiesSize,
+	"/v4/:project/registry/registration": microserviceSize + instanceSize,
 
-	"/registry/v3/microservices/:serviceId/instances/:instanceId/properties":          propertiesSize,
-	"/v4/:project/registry/microservices/:serviceId/instances/:instanceId/properties": propertiesSize,
+	"/registry/v3/microservices/:serviceId/properties":            propertiesSize,
+	"/v4/:project/registry/microservices/:serviceId/properties":   propertiesSize,
+	"/v4.1/:project/registry/microservices/:serviceId/properties": propertiesSize,
+
+	"/registry/v3/microservices/:serviceId/instances/:instanceId/properties":            propertiesSize,
+	"/v4/:project/registry/microservices/:serviceId/instances/:instanceId/properties":   propertiesSize,
+	"/v4.1/:project/registry/microservices/:serviceId/instances/:instanceId/properties": propertiesSize,
+}
+
+// the schema documents can be uploaded in gzip, the decompressed body is
+// limited to the max body bytes as well
+var gzipResources = map[string]struct{}{
+	"/registry/v3/microservices/:serviceId/schemas/:schemaId":            {},
+	"/v4/:project/registry/microservices/:serviceId/schemas/:schemaId":   {},
+	"/v4.1/:project/registry/microservices/:serviceId/schemas/:schemaId": {},
+
+	"/registry/v3/microservices/:serviceId/schemas":            {},
+	"/v4/:project/registry/microservices/:serviceId/schemas":   {},
+	"/v4.1/:project/registry/microservices/:serviceId/schemas": {},
+}
+
+type gzipBody struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+func (b *gzipBody) Close() error {
+	b.Reader.Close()
+	return b.body.Close()
+}
+
+// isGzip returns true if the request body is gzip compressed
+func isGzip(r *http.Request) bool {
+	return strings.EqualFold(strings.TrimSpace(r.Header.Get(rest.HEADER_CONTENT_ENCODING)), rest.ENCODING_GZIP)
 }
 
 type MaxBodyHandler struct {
@@ -62,6 +101,20 @@ func (c *MaxBodyHandler) Handle(i *chain.Invocation) {
 
 	r.Body = http.MaxBytesReader(w, r.Body, v)
 
+	if _, ok := gzipResources[pattern]; ok && isGzip(r) {
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			log.WithContext(r.Context()).Errorf(err, "decompress request body failed, %s %s", r.Method, r.RequestURI)
+			controller.WriteError(w, scerr.ErrInvalidParams, "invalid gzip body")
+			i.Fail(nil)
+			return
+		}
+		// limit the decompressed body to prevent the zip bombs
+		r.Body = http.MaxBytesReader(w, &gzipBody{Reader: gz, body: r.Body}, v)
+		r.Header.Del(rest.HEADER_CONTENT_ENCODING)
+		r.ContentLength = -1
+	}
+
 	i.Next()
 }
 
@@ -0,0 +1,95 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package maxbody
+
+import (
+	"bytes"
+	"compress/gzip"
+	"github.com/apache/servicecomb-service-center/pkg/chain"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/server/core"
+	"golang.org/x/net/context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const schemaPattern = "/v4/:project/registry/microservices/:serviceId/schemas/:schemaId"
+
+type readHandler struct {
+	data []byte
+	err  error
+}
+
+func (h *readHandler) Handle(i *chain.Invocation) {
+	r := i.Context().Value(rest.CTX_REQUEST).(*http.Request)
+	h.data, h.err = ioutil.ReadAll(r.Body)
+	i.Next()
+}
+
+func compress(t *testing.T, data []byte) []byte {
+	buf := bytes.NewBuffer(nil)
+	gz := gzip.NewWriter(buf)
+	if _, err := gz.Write(data); err != nil {
+		t.Fatalf("compress failed, %v", err)
+	}
+	gz.Close()
+	return buf.Bytes()
+}
+
+func handle(body []byte) (*httptest.ResponseRecorder, *readHandler, bool) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPut, "/v4/default/registry/microservices/a/schemas/b", bytes.NewReader(body))
+	r.Header.Set(rest.HEADER_CONTENT_ENCODING, rest.ENCODING_GZIP)
+
+	next := &readHandler{}
+	ok := false
+	inv := chain.NewInvocation(context.Background(), chain.NewChain("_maxbody_test_",
+		[]chain.Handler{&MaxBodyHandler{}, next}))
+	inv.WithContext(rest.CTX_REQUEST, r).
+		WithContext(rest.CTX_RESPONSE, http.ResponseWriter(w)).
+		WithContext(rest.CTX_MATCH_PATTERN, schemaPattern)
+	inv.Invoke(func(ret chain.Result) { ok = ret.OK })
+	return w, next, ok
+}
+
+func TestMaxBodyHandler_Gzip(t *testing.T) {
+	defer func(n int64) { core.ServerInfo.Config.MaxBodyBytes = n }(core.ServerInfo.Config.MaxBodyBytes)
+	core.ServerInfo.Config.MaxBodyBytes = 1024
+
+	schema := []byte(`{"schema":"swagger: '2.0'"}`)
+	_, next, ok := handle(compress(t, schema))
+	if !ok || next.err != nil || !bytes.Equal(next.data, schema) {
+		t.Fatalf("TestMaxBodyHandler_Gzip failed, the valid body: %s, %v", next.data, next.err)
+	}
+
+	w, next, ok := handle([]byte("not a gzip stream"))
+	if ok || w.Code != http.StatusBadRequest || next.data != nil {
+		t.Fatalf("TestMaxBodyHandler_Gzip failed, the corrupt body: %d", w.Code)
+	}
+
+	// the small body decompressed over the limit is a zip bomb
+	bomb := compress(t, make([]byte, 64*1024))
+	if int64(len(bomb)) >= core.ServerInfo.Config.MaxBodyBytes {
+		t.Fatalf("TestMaxBodyHandler_Gzip failed, the compressed size %d", len(bomb))
+	}
+	_, next, _ = handle(bomb)
+	if next.err == nil || int64(len(next.data)) > core.ServerInfo.Config.MaxBodyBytes {
+		t.Fatalf("TestMaxBodyHandler_Gzip failed, the zip bomb is read %d bytes", len(next.data))
+	}
+}
@@ -37,7 +37,9 @@ func (h *MetricsHandler) Handle(i *chain.Invocation) {
 		w, r := i.Context().Value(rest.CTX_RESPONSE).(http.ResponseWriter),
 			i.Context().Value(rest.CTX_REQUEST).(*http.Request)
 		svr.ReportRequestCompleted(w, r, start)
-		log.LogNilOrWarnf(start, "%s %s", r.Method, r.RequestURI)
+		if cost := time.Since(start); cost >= time.Second {
+			log.WithContext(r.Context()).Warnf("[%s]%s %s", cost, r.Method, r.RequestURI)
+		}
 	}))
 }
 
@@ -0,0 +1,99 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package negotiation
+
+import (
+	"bytes"
+	"github.com/apache/servicecomb-service-center/pkg/chain"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/ghodss/yaml"
+	"net/http"
+	"strings"
+)
+
+// IsYAMLAccepted returns true if the client accepts the yaml responses
+func IsYAMLAccepted(r *http.Request) bool {
+	return strings.Contains(r.Header.Get(rest.HEADER_ACCEPT), "yaml")
+}
+
+// IsYAMLContent returns true if the request body is yaml
+func IsYAMLContent(r *http.Request) bool {
+	return strings.Contains(r.Header.Get(rest.HEADER_CONTENT_TYPE), "yaml")
+}
+
+// yamlWriter buffers the successful json responses and writes them in
+// yaml, the errors and the other content types are written as they are
+type yamlWriter struct {
+	http.ResponseWriter
+	status int
+	buf    bytes.Buffer
+}
+
+func (w *yamlWriter) WriteHeader(status int) {
+	if status < http.StatusOK || status >= http.StatusMultipleChoices ||
+		!strings.HasPrefix(w.Header().Get(rest.HEADER_CONTENT_TYPE), rest.ACCEPT_JSON) {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	w.status = status
+}
+
+func (w *yamlWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		return w.ResponseWriter.Write(b)
+	}
+	return w.buf.Write(b)
+}
+
+func (w *yamlWriter) flush() {
+	if w.status == 0 {
+		return
+	}
+	data, err := yaml.JSONToYAML(w.buf.Bytes())
+	if err != nil {
+		log.Errorf(err, "convert the response to yaml failed")
+		data = w.buf.Bytes()
+	} else {
+		w.Header().Set(rest.HEADER_CONTENT_TYPE, rest.CONTENT_TYPE_YAML)
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	w.ResponseWriter.Write(data)
+	w.status = 0
+}
+
+// YAMLHandler writes the json responses of the read APIs in yaml if the
+// client accepts it
+type YAMLHandler struct {
+}
+
+func (h *YAMLHandler) Handle(i *chain.Invocation) {
+	r := i.Context().Value(rest.CTX_REQUEST).(*http.Request)
+	if r.Method != http.MethodGet || !IsYAMLAccepted(r) {
+		i.Next()
+		return
+	}
+	yw := &yamlWriter{ResponseWriter: i.Context().Value(rest.CTX_RESPONSE).(http.ResponseWriter)}
+	i.WithContext(rest.CTX_RESPONSE, yw)
+	i.Next(chain.WithFunc(func(ret chain.Result) {
+		yw.flush()
+	}))
+}
+
+func RegisterHandlers() {
+	chain.RegisterHandler(rest.SERVER_CHAIN_NAME, &YAMLHandler{})
+}
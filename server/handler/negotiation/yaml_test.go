@@ -0,0 +1,55 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package negotiation
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/rest/controller"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestYAMLWriter(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/v4/default/registry/microservices", nil)
+	if IsYAMLAccepted(r) {
+		t.Fatalf("TestYAMLWriter failed")
+	}
+	r.Header.Set(rest.HEADER_ACCEPT, rest.CONTENT_TYPE_YAML)
+	if !IsYAMLAccepted(r) {
+		t.Fatalf("TestYAMLWriter failed")
+	}
+
+	rr := httptest.NewRecorder()
+	yw := &yamlWriter{ResponseWriter: rr}
+	controller.WriteResponse(yw, nil, map[string]interface{}{"services": []string{"a", "b"}})
+	yw.flush()
+	if rr.Code != http.StatusOK || rr.Header().Get(rest.HEADER_CONTENT_TYPE) != rest.CONTENT_TYPE_YAML ||
+		rr.Body.String() != "services:\n- a\n- b\n" {
+		t.Fatalf("TestYAMLWriter failed, %d %s", rr.Code, rr.Body.String())
+	}
+
+	// the errors are written as they are
+	rr = httptest.NewRecorder()
+	yw = &yamlWriter{ResponseWriter: rr}
+	controller.WriteError(yw, scerr.ErrServiceNotExists, "not found")
+	yw.flush()
+	if rr.Code != http.StatusBadRequest || rr.Header().Get(rest.HEADER_CONTENT_TYPE) != rest.CONTENT_TYPE_JSON {
+		t.Fatalf("TestYAMLWriter failed, %d", rr.Code)
+	}
+}
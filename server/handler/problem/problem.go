@@ -0,0 +1,133 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package problem
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/apache/servicecomb-service-center/pkg/chain"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/rest/controller/v41"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// TYPE_PREFIX is the prefix of the problem type, the type of each error
+// code never changes
+const TYPE_PREFIX = "urn:servicecomb:service-center:error:"
+
+// Problem is the RFC 7807 error payload, the errorCode and requestId are
+// the extension members
+type Problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	Instance  string `json:"instance,omitempty"`
+	Code      int32  `json:"errorCode"`
+	RequestId string `json:"requestId,omitempty"`
+}
+
+// TypeOf returns the type URI of the error code
+func TypeOf(code int32) string {
+	return TYPE_PREFIX + strconv.Itoa(int(code))
+}
+
+// IsAccepted returns true if the client accepts the problem+json errors
+func IsAccepted(r *http.Request) bool {
+	return strings.Contains(r.Header.Get(rest.HEADER_ACCEPT), rest.CONTENT_TYPE_PROBLEM)
+}
+
+// problemWriter converts the error payload written by controller.WriteError
+// to the problem+json one, the other responses are written as they are
+type problemWriter struct {
+	http.ResponseWriter
+	r      *http.Request
+	status int
+}
+
+func (w *problemWriter) WriteHeader(status int) {
+	if status < http.StatusBadRequest {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	w.status = status
+}
+
+func (w *problemWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		return w.ResponseWriter.Write(b)
+	}
+	// the error payload is written at once by controller.WriteError
+	w.convert(b)
+	return len(b), nil
+}
+
+func (w *problemWriter) convert(b []byte) {
+	status := w.status
+	w.status = 0
+	old := &scerr.Error{}
+	if err := json.Unmarshal(b, old); err != nil || old.Code == 0 {
+		// not written by controller.WriteError
+		old = &scerr.Error{Code: int32(status) * 1000, Message: http.StatusText(status),
+			Detail: string(bytes.TrimSpace(b))}
+	}
+	data, _ := json.Marshal(&Problem{
+		Type:      TypeOf(old.Code),
+		Title:     old.Message,
+		Status:    status,
+		Detail:    old.Detail,
+		Instance:  w.r.URL.Path,
+		Code:      old.Code,
+		RequestId: w.Header().Get(rest.HEADER_REQUEST_ID),
+	})
+	w.Header().Set(rest.HEADER_RESPONSE_STATUS, strconv.Itoa(status))
+	w.Header().Set(rest.HEADER_ERROR_CODE, strconv.Itoa(int(old.Code)))
+	w.Header().Set(rest.HEADER_CONTENT_TYPE, rest.CONTENT_TYPE_PROBLEM)
+	w.ResponseWriter.WriteHeader(status)
+	fmt.Fprintln(w.ResponseWriter, util.BytesToStringWithNoCopy(data))
+}
+
+// ProblemHandler writes the errors in problem+json if the client accepts
+// it, the v4.1 APIs have their own error payload
+type ProblemHandler struct {
+}
+
+func (h *ProblemHandler) Handle(i *chain.Invocation) {
+	r := i.Context().Value(rest.CTX_REQUEST).(*http.Request)
+	pattern, _ := i.Context().Value(rest.CTX_MATCH_PATTERN).(string)
+	if !IsAccepted(r) || strings.HasPrefix(pattern, v41.PREFIX) {
+		i.Next()
+		return
+	}
+	pw := &problemWriter{ResponseWriter: i.Context().Value(rest.CTX_RESPONSE).(http.ResponseWriter), r: r}
+	i.WithContext(rest.CTX_RESPONSE, pw)
+	i.Next(chain.WithFunc(func(ret chain.Result) {
+		if pw.status != 0 {
+			// the status is written without a body
+			pw.convert(nil)
+		}
+	}))
+}
+
+func RegisterHandlers() {
+	chain.RegisterHandler(rest.SERVER_CHAIN_NAME, &ProblemHandler{})
+}
@@ -0,0 +1,66 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package problem
+
+import (
+	"encoding/json"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/rest/controller"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestProblemWriter(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/v4/default/registry/microservices/x", nil)
+	if IsAccepted(r) {
+		t.Fatalf("TestProblemWriter failed")
+	}
+	r.Header.Set(rest.HEADER_ACCEPT, rest.CONTENT_TYPE_PROBLEM+", application/json")
+	if !IsAccepted(r) {
+		t.Fatalf("TestProblemWriter failed")
+	}
+
+	rr := httptest.NewRecorder()
+	rr.Header().Set(rest.HEADER_REQUEST_ID, "id")
+	controller.WriteError(&problemWriter{ResponseWriter: rr, r: r}, scerr.ErrServiceNotExists, "not found x")
+	p := &Problem{}
+	if err := json.Unmarshal(rr.Body.Bytes(), p); err != nil {
+		t.Fatalf("TestProblemWriter failed, %v", err)
+	}
+	if rr.Code != http.StatusBadRequest || rr.Header().Get(rest.HEADER_CONTENT_TYPE) != rest.CONTENT_TYPE_PROBLEM ||
+		p.Type != TYPE_PREFIX+"400012" || p.Status != http.StatusBadRequest || p.Code != scerr.ErrServiceNotExists ||
+		p.Detail != "not found x" || p.Instance != "/v4/default/registry/microservices/x" || p.RequestId != "id" {
+		t.Fatalf("TestProblemWriter failed, %d %v", rr.Code, p)
+	}
+
+	// not an error
+	rr = httptest.NewRecorder()
+	controller.WriteResponse(&problemWriter{ResponseWriter: rr, r: r}, nil, map[string]string{"a": "b"})
+	if rr.Code != http.StatusOK || rr.Header().Get(rest.HEADER_CONTENT_TYPE) != rest.CONTENT_TYPE_JSON {
+		t.Fatalf("TestProblemWriter failed, %d", rr.Code)
+	}
+
+	// not written by controller.WriteError
+	rr = httptest.NewRecorder()
+	pw := &problemWriter{ResponseWriter: rr, r: r}
+	http.Error(pw, "bad", http.StatusBadRequest)
+	if err := json.Unmarshal(rr.Body.Bytes(), p); err != nil || p.Code != 400000 || p.Detail != "bad" {
+		t.Fatalf("TestProblemWriter failed, %v %v", p, err)
+	}
+}
@@ -0,0 +1,272 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package health
+
+import (
+	"fmt"
+	"github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/core/backend"
+	"github.com/apache/servicecomb-service-center/server/plugin"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	"github.com/apache/servicecomb-service-center/server/reload"
+	nf "github.com/apache/servicecomb-service-center/server/service/notification"
+	"github.com/astaxie/beego"
+	"golang.org/x/net/context"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+const (
+	STATUS_UP       = "UP"
+	STATUS_DEGRADED = "DEGRADED"
+	STATUS_DOWN     = "DOWN"
+
+	COMPONENT_CACHE        = "cache"
+	COMPONENT_NOTIFICATION = "notification"
+	COMPONENT_PLUGINS      = "plugins"
+	COMPONENT_DISK         = "disk"
+
+	DEFAULT_LATENCY_THRESHOLD   = 500 * time.Millisecond
+	DEFAULT_CACHE_LAG_THRESHOLD = 1000
+	DEFAULT_DISK_FREE_THRESHOLD = 10
+)
+
+var deepConfig = LoadDeepConfig()
+
+func init() {
+	apply := func(string) {
+		deepConfig = LoadDeepConfig()
+	}
+	reload.Register(reload.Option{Key: "health_latency_threshold", Validate: reload.Duration(0), Apply: apply})
+	reload.Register(reload.Option{Key: "health_cache_lag_threshold", Validate: reload.Int(1), Apply: apply})
+	reload.Register(reload.Option{Key: "health_disk_free_threshold", Validate: reload.Float(0), Apply: apply})
+}
+
+// DeepConfig is the thresholds over which the components are DEGRADED
+type DeepConfig struct {
+	// RegistryLatency is the max latency of a quorum read
+	RegistryLatency time.Duration
+	// CacheLag is the max revisions the cache falls behind the registry
+	CacheLag int64
+	// DiskFreePercent is the min free space percent of the log disk
+	DiskFreePercent float64
+}
+
+// LoadDeepConfig reads 'health_latency_threshold', 'health_cache_lag_threshold'
+// and 'health_disk_free_threshold'
+func LoadDeepConfig() DeepConfig {
+	c := DeepConfig{
+		RegistryLatency: DEFAULT_LATENCY_THRESHOLD,
+		CacheLag:        beego.AppConfig.DefaultInt64("health_cache_lag_threshold", DEFAULT_CACHE_LAG_THRESHOLD),
+		DiskFreePercent: beego.AppConfig.DefaultFloat("health_disk_free_threshold", DEFAULT_DISK_FREE_THRESHOLD),
+	}
+	d, err := time.ParseDuration(beego.AppConfig.DefaultString("health_latency_threshold", ""))
+	if err == nil && d > 0 {
+		c.RegistryLatency = d
+	}
+	if c.CacheLag <= 0 {
+		c.CacheLag = DEFAULT_CACHE_LAG_THRESHOLD
+	}
+	return c
+}
+
+// ComponentHealth is the status of a subsystem, the details are the
+// measured values for alerting
+type ComponentHealth struct {
+	Name    string                 `json:"name"`
+	Status  string                 `json:"status"`
+	Message string                 `json:"message,omitempty"`
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+type DeepHealth struct {
+	// Status is the worst status of the components
+	Status     string             `json:"status"`
+	Components []*ComponentHealth `json:"components"`
+	Timestamp  string             `json:"timestamp"`
+}
+
+func newComponent(name string) *ComponentHealth {
+	return &ComponentHealth{Name: name, Status: STATUS_UP, Details: make(map[string]interface{})}
+}
+
+func (c *ComponentHealth) degrade(status, format string, args ...interface{}) {
+	if statusLevel(status) > statusLevel(c.Status) {
+		c.Status = status
+	}
+	if len(c.Message) > 0 {
+		c.Message += "; "
+	}
+	c.Message += fmt.Sprintf(format, args...)
+}
+
+func statusLevel(status string) int {
+	switch status {
+	case STATUS_DOWN:
+		return 2
+	case STATUS_DEGRADED:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// DeepCheck checks every subsystem of the service center, it costs a
+// quorum read of the registry
+func DeepCheck(ctx context.Context) *DeepHealth {
+	reg, rev := checkRegistry(ctx, deepConfig)
+	components := []*ComponentHealth{
+		reg,
+		checkCache(deepConfig, rev),
+		checkNotification(),
+		checkPlugins(ctx),
+		checkDisk(deepConfig),
+	}
+	h := &DeepHealth{
+		Status:     STATUS_UP,
+		Components: components,
+		Timestamp:  time.Now().UTC().Format(time.RFC3339),
+	}
+	for _, c := range components {
+		if statusLevel(c.Status) > statusLevel(h.Status) {
+			h.Status = c.Status
+		}
+	}
+	return h
+}
+
+// checkRegistry returns the registry health and the current revision,
+// the read is linearizable so it succeeds only if the quorum is available
+func checkRegistry(ctx context.Context, cfg DeepConfig) (*ComponentHealth, int64) {
+	c := newComponent(COMPONENT_REGISTRY)
+	ctx, cancel := registry.WithTimeout(ctx)
+	defer cancel()
+
+	start := time.Now()
+	resp, err := backend.Registry().Do(ctx, registry.GET,
+		registry.WithStrKey(core.GetRootKey()),
+		registry.WithCountOnly())
+	latency := time.Since(start)
+	c.Details["latencyMs"] = latency.Nanoseconds() / int64(time.Millisecond)
+	if err != nil {
+		c.degrade(STATUS_DOWN, "quorum read failed: %s", err.Error())
+		return c, 0
+	}
+	c.Details["revision"] = resp.Revision
+	if latency > cfg.RegistryLatency {
+		c.degrade(STATUS_DEGRADED, "latency %s exceeds %s", latency, cfg.RegistryLatency)
+	}
+	return c, resp.Revision
+}
+
+// checkCache compares the revision of the last event cached with the
+// registry, the lag is unknown if the registry is down
+func checkCache(cfg DeepConfig, registryRev int64) *ComponentHealth {
+	c := newComponent(COMPONENT_CACHE)
+	select {
+	case <-backend.Store().Ready():
+	default:
+		c.degrade(STATUS_DOWN, "cache is not ready")
+		return c
+	}
+	rev := backend.Revision()
+	c.Details["revision"] = rev
+	if registryRev <= 0 {
+		return c
+	}
+	lag := registryRev - rev
+	if lag < 0 {
+		lag = 0
+	}
+	c.Details["lag"] = lag
+	if lag > cfg.CacheLag {
+		c.degrade(STATUS_DEGRADED, "cache lags %d revisions behind the registry", lag)
+	}
+	return c
+}
+
+func checkNotification() *ComponentHealth {
+	c := newComponent(COMPONENT_NOTIFICATION)
+	svc := nf.GetNotifyService()
+	if svc.Closed() {
+		c.degrade(STATUS_DOWN, "notify service is closed")
+		return c
+	}
+	laggards := svc.Laggards()
+	var pending int
+	var dropped int64
+	for _, l := range laggards {
+		pending += l.Pending
+		dropped += l.Dropped
+	}
+	c.Details["laggards"] = len(laggards)
+	c.Details["pending"] = pending
+	c.Details["dropped"] = dropped
+	if len(laggards) > 0 {
+		c.degrade(STATUS_DEGRADED, "%d subscribers are lagging", len(laggards))
+	}
+	return c
+}
+
+func checkPlugins(ctx context.Context) *ComponentHealth {
+	c := newComponent(COMPONENT_PLUGINS)
+	health := plugin.Plugins().Health(ctx)
+	names := make([]string, 0, len(health))
+	for name := range health {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := health[name]; err != nil {
+			c.Details[name] = err.Error()
+			c.degrade(STATUS_DEGRADED, "plugin %s is unhealthy", name)
+			continue
+		}
+		c.Details[name] = STATUS_UP
+	}
+	return c
+}
+
+// checkDisk checks the free space of the disk the logs written to
+func checkDisk(cfg DeepConfig) *ComponentHealth {
+	c := newComponent(COMPONENT_DISK)
+	file := os.ExpandEnv(core.ServerInfo.Config.LogFilePath)
+	if len(file) == 0 {
+		c.Message = "logs are written to stdout"
+		return c
+	}
+	dir := filepath.Dir(file)
+	c.Details["path"] = dir
+	free, total, err := diskUsage(dir)
+	if err != nil {
+		c.degrade(STATUS_DEGRADED, "stat disk failed: %s", err.Error())
+		return c
+	}
+	c.Details["freeBytes"] = free
+	c.Details["totalBytes"] = total
+	if total == 0 {
+		return c
+	}
+	percent := float64(free) * 100 / float64(total)
+	c.Details["freePercent"] = percent
+	if percent < cfg.DiskFreePercent {
+		c.degrade(STATUS_DEGRADED, "free space %.1f%% is less than %.1f%%", percent, cfg.DiskFreePercent)
+	}
+	return c
+}
@@ -0,0 +1,49 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package health
+
+import (
+	"testing"
+)
+
+func TestComponentHealth_Degrade(t *testing.T) {
+	c := newComponent(COMPONENT_CACHE)
+	c.degrade(STATUS_DEGRADED, "lag %d", 1)
+	if c.Status != STATUS_DEGRADED || c.Message != "lag 1" {
+		t.Fatalf("TestComponentHealth_Degrade failed, %v", c)
+	}
+	c.degrade(STATUS_DOWN, "down")
+	c.degrade(STATUS_DEGRADED, "slow")
+	if c.Status != STATUS_DOWN || c.Message != "lag 1; down; slow" {
+		t.Fatalf("TestComponentHealth_Degrade failed, %v", c)
+	}
+}
+
+func TestDiskUsage(t *testing.T) {
+	free, total, err := diskUsage(".")
+	if err != nil {
+		t.Skipf("TestDiskUsage skipped, %v", err)
+	}
+	if total == 0 || free > total {
+		t.Fatalf("TestDiskUsage failed, %d/%d", free, total)
+	}
+
+	c := checkDisk(DeepConfig{DiskFreePercent: 101})
+	if _, ok := c.Details["path"]; ok && c.Status != STATUS_DEGRADED {
+		t.Fatalf("TestDiskUsage failed, %v", c)
+	}
+}
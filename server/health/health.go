@@ -0,0 +1,142 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package health
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rpc"
+	"github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/core/backend"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	"github.com/astaxie/beego"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"time"
+)
+
+const (
+	DEFAULT_CHECK_INTERVAL = 10 * time.Second
+
+	// the components reported besides the overall status and the
+	// registered grpc services
+	COMPONENT_REGISTRY  = "registry"
+	COMPONENT_DISCOVERY = "discovery"
+)
+
+var healthServer = NewServer()
+
+func init() {
+	healthServer.SetServingStatus("", HealthCheckResponse_NOT_SERVING)
+
+	rpc.RegisterService(func(s *grpc.Server) {
+		RegisterHealthServer(s, healthServer)
+
+		checker := &Checker{Server: healthServer, GRPCServer: s, Interval: LoadConfig()}
+		gopool.Go(checker.Run)
+	})
+}
+
+// LoadConfig reads 'rpc_health_check_interval'
+func LoadConfig() time.Duration {
+	d, err := time.ParseDuration(beego.AppConfig.DefaultString("rpc_health_check_interval", ""))
+	if err != nil || d <= 0 {
+		return DEFAULT_CHECK_INTERVAL
+	}
+	return d
+}
+
+// GetServer returns the health server registered on the grpc listener
+func GetServer() *Server {
+	return healthServer
+}
+
+// Checker refreshes the statuses of the components periodically, the
+// overall status and the status of every grpc service registered are
+// SERVING only if all the components are serving
+type Checker struct {
+	Server     *Server
+	GRPCServer *grpc.Server
+	Interval   time.Duration
+}
+
+func (c *Checker) Run(ctx context.Context) {
+	for {
+		c.Check(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(c.Interval):
+		}
+	}
+}
+
+func (c *Checker) Check(ctx context.Context) {
+	statuses := map[string]HealthCheckResponse_ServingStatus{
+		COMPONENT_DISCOVERY: toStatus(c.discoveryReady()),
+		COMPONENT_REGISTRY:  toStatus(c.registryAvailable(ctx)),
+	}
+	overall := HealthCheckResponse_SERVING
+	for name, status := range statuses {
+		c.Server.SetServingStatus(name, status)
+		if status != HealthCheckResponse_SERVING {
+			overall = HealthCheckResponse_NOT_SERVING
+		}
+	}
+
+	c.Server.SetServingStatus("", overall)
+
+	if c.GRPCServer == nil {
+		return
+	}
+	for name := range c.GRPCServer.GetServiceInfo() {
+		if name == _Health_serviceDesc.ServiceName {
+			continue
+		}
+		c.Server.SetServingStatus(name, overall)
+	}
+}
+
+func (c *Checker) discoveryReady() bool {
+	select {
+	case <-backend.Store().Ready():
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *Checker) registryAvailable(ctx context.Context) bool {
+	ctx, cancel := registry.WithTimeout(ctx)
+	defer cancel()
+	_, err := backend.Registry().Do(ctx, registry.GET,
+		registry.WithStrKey(core.GetRootKey()),
+		registry.WithCountOnly())
+	if err != nil {
+		log.Errorf(err, "health check registry failed")
+		return false
+	}
+	return true
+}
+
+func toStatus(ok bool) HealthCheckResponse_ServingStatus {
+	if ok {
+		return HealthCheckResponse_SERVING
+	}
+	return HealthCheckResponse_NOT_SERVING
+}
@@ -0,0 +1,92 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package health
+
+import (
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"testing"
+	"time"
+)
+
+type mockWatchServer struct {
+	grpc.ServerStream
+	ctx context.Context
+	ch  chan HealthCheckResponse_ServingStatus
+}
+
+func (s *mockWatchServer) Context() context.Context {
+	return s.ctx
+}
+
+func (s *mockWatchServer) Send(m *HealthCheckResponse) error {
+	s.ch <- m.Status
+	return nil
+}
+
+func TestServer_Check(t *testing.T) {
+	s := NewServer()
+	_, err := s.Check(context.Background(), &HealthCheckRequest{})
+	if err == nil {
+		t.Fatalf("TestServer_Check failed")
+	}
+
+	s.SetServingStatus("", HealthCheckResponse_SERVING)
+	resp, err := s.Check(context.Background(), &HealthCheckRequest{})
+	if err != nil || resp.Status != HealthCheckResponse_SERVING {
+		t.Fatalf("TestServer_Check failed, %v, %v", resp, err)
+	}
+}
+
+func TestServer_Watch(t *testing.T) {
+	s := NewServer()
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &mockWatchServer{ctx: ctx, ch: make(chan HealthCheckResponse_ServingStatus, 10)}
+	done := make(chan error)
+	go func() {
+		done <- s.Watch(&HealthCheckRequest{Service: COMPONENT_REGISTRY}, stream)
+	}()
+
+	expect := func(status HealthCheckResponse_ServingStatus) {
+		select {
+		case st := <-stream.ch:
+			if st != status {
+				t.Fatalf("TestServer_Watch failed, %v", st)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("TestServer_Watch failed, timed out waiting for %v", status)
+		}
+	}
+	expect(HealthCheckResponse_SERVICE_UNKNOWN)
+
+	s.SetServingStatus(COMPONENT_REGISTRY, HealthCheckResponse_NOT_SERVING)
+	expect(HealthCheckResponse_NOT_SERVING)
+	// no changes
+	s.SetServingStatus(COMPONENT_REGISTRY, HealthCheckResponse_NOT_SERVING)
+	s.SetServingStatus(COMPONENT_REGISTRY, HealthCheckResponse_SERVING)
+	expect(HealthCheckResponse_SERVING)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("TestServer_Watch failed, watch is not stopped")
+	}
+	if len(s.watchers) != 0 {
+		t.Fatalf("TestServer_Watch failed, %v", s.watchers)
+	}
+}
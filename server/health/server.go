@@ -0,0 +1,113 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package health
+
+import (
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"sync"
+)
+
+// Server implements the grpc health service, the status of each service
+// name is set by SetServingStatus, the empty name is the overall status
+type Server struct {
+	mux      sync.RWMutex
+	statuses map[string]HealthCheckResponse_ServingStatus
+	watchers map[string]map[chan HealthCheckResponse_ServingStatus]struct{}
+}
+
+func (s *Server) Check(ctx context.Context, in *HealthCheckRequest) (*HealthCheckResponse, error) {
+	s.mux.RLock()
+	status, ok := s.statuses[in.Service]
+	s.mux.RUnlock()
+	if !ok {
+		return nil, grpc.Errorf(codes.NotFound, "unknown service %s", in.Service)
+	}
+	return &HealthCheckResponse{Status: status}, nil
+}
+
+// Watch sends the current status at once, then sends the new one when it
+// changes, the intermediate statuses may be skipped if the client is slow
+func (s *Server) Watch(in *HealthCheckRequest, stream Health_WatchServer) error {
+	ch := make(chan HealthCheckResponse_ServingStatus, 1)
+
+	s.mux.Lock()
+	status, ok := s.statuses[in.Service]
+	if !ok {
+		status = HealthCheckResponse_SERVICE_UNKNOWN
+	}
+	ch <- status
+	ws, ok := s.watchers[in.Service]
+	if !ok {
+		ws = make(map[chan HealthCheckResponse_ServingStatus]struct{})
+		s.watchers[in.Service] = ws
+	}
+	ws[ch] = struct{}{}
+	s.mux.Unlock()
+
+	defer func() {
+		s.mux.Lock()
+		delete(s.watchers[in.Service], ch)
+		if len(s.watchers[in.Service]) == 0 {
+			delete(s.watchers, in.Service)
+		}
+		s.mux.Unlock()
+	}()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case status := <-ch:
+			if err := stream.Send(&HealthCheckResponse{Status: status}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *Server) SetServingStatus(service string, status HealthCheckResponse_ServingStatus) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if old, ok := s.statuses[service]; ok && old == status {
+		return
+	}
+	s.statuses[service] = status
+	for ch := range s.watchers[service] {
+		// keep the latest status only
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- status
+	}
+}
+
+func (s *Server) ServingStatus(service string) (HealthCheckResponse_ServingStatus, bool) {
+	s.mux.RLock()
+	status, ok := s.statuses[service]
+	s.mux.RUnlock()
+	return status, ok
+}
+
+func NewServer() *Server {
+	return &Server{
+		statuses: make(map[string]HealthCheckResponse_ServingStatus),
+		watchers: make(map[string]map[chan HealthCheckResponse_ServingStatus]struct{}),
+	}
+}
@@ -0,0 +1,70 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package importer
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	roa "github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/astaxie/beego"
+	"time"
+)
+
+const (
+	DEFAULT_LEASE_TTL          = 30 * time.Minute
+	DEFAULT_HEARTBEAT_INTERVAL = 30 * time.Second
+)
+
+var (
+	cfg Config
+	imp *Importer
+)
+
+func init() {
+	cfg = LoadConfig()
+	if !cfg.Enabled {
+		return
+	}
+	imp = &Importer{Cfg: cfg, Keeper: NewKeeper(cfg.HeartbeatInterval)}
+	roa.RegisterServant(&ImportController{})
+	gopool.Go(imp.Keeper.Run)
+}
+
+type Config struct {
+	Enabled bool
+	// LeaseTTL is how long the imported instances are kept alive
+	// if the real clients never re-register
+	LeaseTTL time.Duration
+	// HeartbeatInterval is the interval of the synthetic heartbeats
+	HeartbeatInterval time.Duration
+}
+
+func LoadConfig() Config {
+	c := Config{
+		Enabled:           beego.AppConfig.DefaultInt("importer", 0) != 0,
+		LeaseTTL:          DEFAULT_LEASE_TTL,
+		HeartbeatInterval: DEFAULT_HEARTBEAT_INTERVAL,
+	}
+	d, err := time.ParseDuration(beego.AppConfig.DefaultString("import_lease_ttl", ""))
+	if err == nil && d > 0 {
+		c.LeaseTTL = d
+	}
+	d, err = time.ParseDuration(beego.AppConfig.DefaultString("import_heartbeat_interval", ""))
+	if err == nil && d >= time.Second {
+		c.HeartbeatInterval = d
+	}
+	return c
+}
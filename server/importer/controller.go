@@ -0,0 +1,62 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package importer
+
+import (
+	"encoding/json"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/rest/controller"
+	"io/ioutil"
+	"net/http"
+)
+
+// ImportController imports the services and instances from the snapshot
+// of an existing eureka or consul registry, it requires admin permission
+type ImportController struct {
+}
+
+func (ctrl *ImportController) URLPatterns() []rest.Route {
+	return []rest.Route{
+		{rest.HTTP_METHOD_POST, "/v4/:project/admin/import", ctrl.Import},
+	}
+}
+
+func (ctrl *ImportController) Import(w http.ResponseWriter, r *http.Request) {
+	message, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Error("read body failed", err)
+		controller.WriteError(w, scerr.ErrInvalidParams, err.Error())
+		return
+	}
+	request := &ImportRequest{}
+	err = json.Unmarshal(message, request)
+	if err != nil {
+		log.Error("Unmarshal error", err)
+		controller.WriteError(w, scerr.ErrInvalidParams, err.Error())
+		return
+	}
+	resp, err := imp.Import(r.Context(), request)
+	if err != nil {
+		controller.WriteError(w, scerr.ErrInternal, err.Error())
+		return
+	}
+	respInternal := resp.Response
+	resp.Response = nil
+	controller.WriteResponse(w, respInternal, resp)
+}
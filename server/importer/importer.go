@@ -0,0 +1,188 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package importer
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/core/backend"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	serviceUtil "github.com/apache/servicecomb-service-center/server/service/util"
+	"golang.org/x/net/context"
+	"time"
+)
+
+const (
+	// PROP_ORIGIN tags the imported instances with the source registry
+	PROP_ORIGIN = "origin"
+	// PROP_IMPORT_ID is the id of the instance in the source registry
+	PROP_IMPORT_ID = "import.id"
+
+	maxHostNameLength = 64
+)
+
+// Importer bulk-creates the services and instances read from the snapshot
+// of the source registry, and hands the instances over to the keeper,
+// which keeps them alive until the real clients re-register
+type Importer struct {
+	Cfg    Config
+	Keeper *Keeper
+}
+
+func (i *Importer) Import(ctx context.Context, in *ImportRequest) (*ImportResponse, error) {
+	domainProject := util.ParseDomainProject(ctx)
+	if !core.IsDefaultDomainProject(domainProject) {
+		return &ImportResponse{
+			Response: pb.CreateResponse(scerr.ErrForbidden, "Required admin permission"),
+		}, nil
+	}
+
+	snapshot := []byte(in.Snapshot)
+	if len(snapshot) == 0 {
+		if len(in.Address) == 0 {
+			return &ImportResponse{
+				Response: pb.CreateResponse(scerr.ErrInvalidParams, "Snapshot or address is required."),
+			}, nil
+		}
+		b, err := Fetch(ctx, in.Source, in.Address)
+		if err != nil {
+			log.Errorf(err, "fetch %s registry snapshot from %s failed", in.Source, in.Address)
+			return &ImportResponse{
+				Response: pb.CreateResponse(scerr.ErrInvalidParams, err.Error()),
+			}, nil
+		}
+		snapshot = b
+	}
+	records, err := Parse(in.Source, snapshot)
+	if err != nil {
+		return &ImportResponse{
+			Response: pb.CreateResponse(scerr.ErrInvalidParams, err.Error()),
+		}, nil
+	}
+
+	appId := in.AppId
+	if len(appId) == 0 {
+		appId = "default"
+	}
+	resp := &ImportResponse{}
+	services := make(map[string]string)
+	for _, record := range records {
+		key := record.ServiceName + "/" + record.Version
+		serviceId, ok := services[key]
+		if !ok {
+			serviceId, err = i.ensureService(ctx, in.Source, appId, record)
+			if err != nil {
+				resp.Errors = append(resp.Errors, fmt.Sprintf("create service %s failed, %s", key, err.Error()))
+				continue
+			}
+			services[key] = serviceId
+			resp.Services++
+		}
+
+		instanceId, err := i.register(ctx, in.Source, serviceId, record)
+		if err != nil {
+			resp.Errors = append(resp.Errors, fmt.Sprintf("register instance %s failed, %s", record.Id, err.Error()))
+			continue
+		}
+		resp.Instances++
+		if backend.IsDryRun(ctx) {
+			continue
+		}
+		i.Keeper.Add(&Lease{
+			DomainProject: domainProject,
+			ServiceId:     serviceId,
+			InstanceId:    instanceId,
+			Endpoints:     record.Endpoints,
+			Deadline:      time.Now().Add(i.Cfg.LeaseTTL),
+		})
+	}
+	log.Infof("import %d services and %d instances from %s, %d errors",
+		resp.Services, resp.Instances, in.Source, len(resp.Errors))
+	resp.Response = pb.CreateResponse(pb.Response_SUCCESS, "Import successfully.")
+	return resp, nil
+}
+
+func (i *Importer) ensureService(ctx context.Context, source, appId string, record *Record) (string, error) {
+	serviceId, err := serviceUtil.GetServiceId(ctx, &pb.MicroServiceKey{
+		Tenant:      util.ParseDomainProject(ctx),
+		AppId:       appId,
+		ServiceName: record.ServiceName,
+		Version:     record.Version,
+	})
+	if err != nil || len(serviceId) > 0 {
+		return serviceId, err
+	}
+	resp, err := core.ServiceAPI.Create(ctx, &pb.CreateServiceRequest{
+		Service: &pb.MicroService{
+			AppId:       appId,
+			ServiceName: record.ServiceName,
+			Version:     record.Version,
+			Properties:  map[string]string{PROP_ORIGIN: source},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	if resp.Response.Code != pb.Response_SUCCESS {
+		return "", fmt.Errorf(resp.Response.Message)
+	}
+	return resp.ServiceId, nil
+}
+
+// register uses the instance id generated from the source id, so the
+// instances imported again are not duplicated
+func (i *Importer) register(ctx context.Context, source, serviceId string, record *Record) (string, error) {
+	properties := record.Properties
+	properties[PROP_ORIGIN] = source
+	properties[PROP_IMPORT_ID] = record.Id
+	hostName := record.HostName
+	if len(hostName) > maxHostNameLength {
+		hostName = hostName[:maxHostNameLength]
+	}
+	resp, err := core.InstanceAPI.Register(ctx, &pb.RegisterInstanceRequest{
+		Instance: &pb.MicroServiceInstance{
+			InstanceId: instanceIdOf(source, record.Id),
+			ServiceId:  serviceId,
+			Endpoints:  record.Endpoints,
+			HostName:   hostName,
+			Status:     record.Status,
+			Properties: properties,
+			HealthCheck: &pb.HealthCheck{
+				Mode:     pb.CHECK_BY_HEARTBEAT,
+				Interval: int32(i.Cfg.HeartbeatInterval.Seconds()),
+				Times:    3,
+			},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	if resp.Response.Code != pb.Response_SUCCESS {
+		return "", fmt.Errorf(resp.Response.Message)
+	}
+	return resp.InstanceId, nil
+}
+
+func instanceIdOf(source, id string) string {
+	sum := sha1.Sum([]byte(source + "/" + id))
+	return hex.EncodeToString(sum[:])
+}
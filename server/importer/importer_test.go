@@ -0,0 +1,101 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package importer
+
+import (
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"testing"
+)
+
+func TestParseEureka(t *testing.T) {
+	snapshot := `{"applications":{"application":[
+{"name":"ORDER","instance":{"instanceId":"o1","hostName":"","ipAddr":"10.0.0.1","status":"UP",
+  "port":{"$":8080,"@enabled":"true"},"securePort":{"$":443,"@enabled":"false"},"metadata":{"version":"1.2.0"}}},
+{"name":"PAY","instance":[
+  {"instanceId":"p1","hostName":"pay-1","ipAddr":"10.0.0.2","status":"OUT_OF_SERVICE",
+   "port":{"$":8080,"@enabled":false},"securePort":{"$":8443,"@enabled":true}},
+  {"instanceId":"p2","hostName":"pay-2","ipAddr":"10.0.0.3","status":"UP",
+   "port":{"$":8080,"@enabled":false},"securePort":{"$":8443,"@enabled":false}}]}
+]}}`
+	records, err := Parse(SOURCE_EUREKA, []byte(snapshot))
+	if err != nil || len(records) != 2 {
+		t.Fatalf("TestParseEureka failed, %v, %v", records, err)
+	}
+	r := records[0]
+	if r.Id != "o1" || r.ServiceName != "ORDER" || r.Version != "1.2.0" || r.HostName != "10.0.0.1" ||
+		r.Status != pb.MSI_UP || len(r.Endpoints) != 1 || r.Endpoints[0] != "rest://10.0.0.1:8080" {
+		t.Fatalf("TestParseEureka failed, %v", r)
+	}
+	r = records[1]
+	if r.Id != "p1" || r.Version != pb.VERSION || r.Status != pb.MSI_OUTOFSERVICE ||
+		len(r.Endpoints) != 1 || r.Endpoints[0] != "rest://10.0.0.2:8443?sslEnabled=true" {
+		t.Fatalf("TestParseEureka failed, %v", r)
+	}
+}
+
+func TestParseConsul(t *testing.T) {
+	snapshot := `{"web":[
+{"Node":{"Node":"node-1","Address":"10.0.0.1"},
+ "Service":{"ID":"web-1","Service":"web","Tags":["version=2.0.0","http"],"Port":80},
+ "Checks":[{"Status":"passing"}]},
+{"Node":{"Node":"node-2","Address":"10.0.0.2"},
+ "Service":{"ID":"web-2","Service":"web","Address":"192.168.0.2","Port":80},
+ "Checks":[{"Status":"critical"}]},
+{"Node":{"Node":"node-3","Address":"10.0.0.3"},
+ "Service":{"ID":"web-3","Service":"web"}}
+]}`
+	records, err := Parse(SOURCE_CONSUL, []byte(snapshot))
+	if err != nil || len(records) != 2 {
+		t.Fatalf("TestParseConsul failed, %v, %v", records, err)
+	}
+	r := records[0]
+	if r.Id != "node-1/web-1" || r.Version != "2.0.0" || r.HostName != "node-1" || r.Status != pb.MSI_UP ||
+		r.Endpoints[0] != "rest://10.0.0.1:80" || r.Properties["tags"] != "version=2.0.0,http" {
+		t.Fatalf("TestParseConsul failed, %v", r)
+	}
+	r = records[1]
+	if r.Version != pb.VERSION || r.Status != pb.MSI_DOWN || r.Endpoints[0] != "rest://192.168.0.2:80" {
+		t.Fatalf("TestParseConsul failed, %v", r)
+	}
+
+	_, err = Parse("zookeeper", []byte(snapshot))
+	if err == nil {
+		t.Fatalf("TestParseConsul failed")
+	}
+}
+
+func TestKeeper(t *testing.T) {
+	k := NewKeeper(DEFAULT_HEARTBEAT_INTERVAL)
+	k.Add(&Lease{InstanceId: "a"})
+	k.Add(&Lease{InstanceId: "b"})
+	k.Add(&Lease{InstanceId: "a"})
+	if len(k.Leases()) != 2 {
+		t.Fatalf("TestKeeper failed")
+	}
+	k.Remove("a")
+	if len(k.Leases()) != 1 {
+		t.Fatalf("TestKeeper failed")
+	}
+	if !overlap([]string{"rest://a:1", "rest://b:1"}, []string{"rest://b:1"}) ||
+		overlap([]string{"rest://a:1"}, []string{"rest://b:1"}) {
+		t.Fatalf("TestKeeper failed")
+	}
+	if instanceIdOf(SOURCE_EUREKA, "o1") == instanceIdOf(SOURCE_CONSUL, "o1") ||
+		len(instanceIdOf(SOURCE_EUREKA, "o1")) > 64 {
+		t.Fatalf("TestKeeper failed")
+	}
+}
@@ -0,0 +1,167 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package importer
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/core"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	serviceUtil "github.com/apache/servicecomb-service-center/server/service/util"
+	"golang.org/x/net/context"
+	"sync"
+	"time"
+)
+
+// Lease is the synthetic lease of an imported instance
+type Lease struct {
+	DomainProject string
+	ServiceId     string
+	InstanceId    string
+	Endpoints     []string
+	Deadline      time.Time
+}
+
+// Keeper sends the heartbeats of the imported instances until the real
+// clients re-register with the same endpoints, or the leases expire
+type Keeper struct {
+	Interval time.Duration
+
+	mux    sync.Mutex
+	leases map[string]*Lease
+}
+
+func (k *Keeper) Add(lease *Lease) {
+	k.mux.Lock()
+	k.leases[lease.InstanceId] = lease
+	k.mux.Unlock()
+}
+
+func (k *Keeper) Remove(instanceId string) {
+	k.mux.Lock()
+	delete(k.leases, instanceId)
+	k.mux.Unlock()
+}
+
+func (k *Keeper) Leases() []*Lease {
+	k.mux.Lock()
+	leases := make([]*Lease, 0, len(k.leases))
+	for _, lease := range k.leases {
+		leases = append(leases, lease)
+	}
+	k.mux.Unlock()
+	return leases
+}
+
+func (k *Keeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(k.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, lease := range k.Leases() {
+				k.keep(ctx, lease)
+			}
+		}
+	}
+}
+
+func (k *Keeper) keep(ctx context.Context, lease *Lease) {
+	domain, project := core.FromDomainProject(lease.DomainProject)
+	ctx = util.SetDomainProject(ctx, domain, project)
+
+	if time.Now().After(lease.Deadline) {
+		log.Infof("the lease of imported instance[%s/%s] expired", lease.ServiceId, lease.InstanceId)
+		k.Remove(lease.InstanceId)
+		return
+	}
+
+	replaced, err := isReplaced(ctx, lease)
+	if err != nil {
+		log.Errorf(err, "check the imported instance[%s/%s] replaced failed", lease.ServiceId, lease.InstanceId)
+		return
+	}
+	if replaced {
+		log.Infof("imported instance[%s/%s] is replaced by the re-registered one",
+			lease.ServiceId, lease.InstanceId)
+		k.Remove(lease.InstanceId)
+		_, err := core.InstanceAPI.Unregister(ctx, &pb.UnregisterInstanceRequest{
+			ServiceId:  lease.ServiceId,
+			InstanceId: lease.InstanceId,
+		})
+		if err != nil {
+			log.Errorf(err, "unregister imported instance[%s/%s] failed", lease.ServiceId, lease.InstanceId)
+		}
+		return
+	}
+
+	resp, err := core.InstanceAPI.Heartbeat(ctx, &pb.HeartbeatRequest{
+		ServiceId:  lease.ServiceId,
+		InstanceId: lease.InstanceId,
+	})
+	if err != nil {
+		log.Errorf(err, "heartbeat imported instance[%s/%s] failed", lease.ServiceId, lease.InstanceId)
+		return
+	}
+	if resp.Response.Code != pb.Response_SUCCESS {
+		// the instance is unregistered by others
+		log.Warnf("heartbeat imported instance[%s/%s] failed, %s",
+			lease.ServiceId, lease.InstanceId, resp.Response.Message)
+		k.Remove(lease.InstanceId)
+	}
+}
+
+// isReplaced returns true if any instance not imported of the service has
+// the endpoint of the imported one
+func isReplaced(ctx context.Context, lease *Lease) (bool, error) {
+	instances, err := serviceUtil.GetAllInstancesOfOneService(ctx, lease.DomainProject, lease.ServiceId)
+	if err != nil {
+		return false, err
+	}
+	for _, instance := range instances {
+		if instance.InstanceId == lease.InstanceId {
+			continue
+		}
+		if _, ok := instance.Properties[PROP_ORIGIN]; ok {
+			continue
+		}
+		if overlap(instance.Endpoints, lease.Endpoints) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func overlap(a, b []string) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func NewKeeper(interval time.Duration) *Keeper {
+	return &Keeper{
+		Interval: interval,
+		leases:   make(map[string]*Lease),
+	}
+}
@@ -0,0 +1,270 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/apache/servicecomb-service-center/server/consul"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	serviceUtil "github.com/apache/servicecomb-service-center/server/service/util"
+	"golang.org/x/net/context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SOURCE_EUREKA = "eureka"
+	SOURCE_CONSUL = "consul"
+
+	// the version of the imported instances is read from the metadata, or
+	// the 'version={version}' tag of consul
+	META_VERSION = "version"
+
+	fetchTimeout = 30 * time.Second
+)
+
+var versionRegex = serviceUtil.NewVersionRegexp(false)
+
+func versionOf(v string) string {
+	if versionRegex.MatchString(v) {
+		return v
+	}
+	return pb.VERSION
+}
+
+// Parse converts the snapshot of the source registry to the records
+func Parse(source string, snapshot []byte) ([]*Record, error) {
+	switch source {
+	case SOURCE_EUREKA:
+		return ParseEureka(snapshot)
+	case SOURCE_CONSUL:
+		return ParseConsul(snapshot)
+	default:
+		return nil, fmt.Errorf("unsupported source '%s'", source)
+	}
+}
+
+func ParseEureka(snapshot []byte) ([]*Record, error) {
+	apps := &EurekaApps{}
+	if err := json.Unmarshal(snapshot, apps); err != nil {
+		return nil, err
+	}
+	var records []*Record
+	for _, app := range apps.Applications.Application {
+		var instances []*EurekaInstance
+		raw := bytes.TrimSpace(app.Instance)
+		switch {
+		case len(raw) == 0:
+		case raw[0] == '[':
+			if err := json.Unmarshal(raw, &instances); err != nil {
+				return nil, err
+			}
+		default:
+			instance := &EurekaInstance{}
+			if err := json.Unmarshal(raw, instance); err != nil {
+				return nil, err
+			}
+			instances = append(instances, instance)
+		}
+		for _, instance := range instances {
+			if record := eurekaRecord(app.Name, instance); record != nil {
+				records = append(records, record)
+			}
+		}
+	}
+	return records, nil
+}
+
+// eurekaRecord returns nil if the instance has no enabled port
+func eurekaRecord(name string, instance *EurekaInstance) *Record {
+	var endpoints []string
+	if instance.Port.IsEnabled() && instance.Port.Port > 0 {
+		endpoints = append(endpoints, "rest://"+net.JoinHostPort(instance.IpAddr, strconv.Itoa(instance.Port.Port)))
+	}
+	if instance.SecurePort.IsEnabled() && instance.SecurePort.Port > 0 {
+		endpoints = append(endpoints, "rest://"+
+			net.JoinHostPort(instance.IpAddr, strconv.Itoa(instance.SecurePort.Port))+"?sslEnabled=true")
+	}
+	if len(endpoints) == 0 {
+		return nil
+	}
+	id := instance.InstanceId
+	if len(id) == 0 {
+		id = name + "/" + endpoints[0]
+	}
+	hostName := instance.HostName
+	if len(hostName) == 0 {
+		hostName = instance.IpAddr
+	}
+	return &Record{
+		Id:          id,
+		ServiceName: name,
+		Version:     versionOf(instance.Metadata[META_VERSION]),
+		HostName:    hostName,
+		Endpoints:   endpoints,
+		Status:      eurekaStatus(instance.Status),
+		Properties:  copyProperties(instance.Metadata),
+	}
+}
+
+func eurekaStatus(status string) string {
+	switch status {
+	case "UP":
+		return pb.MSI_UP
+	case "STARTING":
+		return pb.MSI_STARTING
+	case "OUT_OF_SERVICE":
+		return pb.MSI_OUTOFSERVICE
+	default:
+		return pb.MSI_DOWN
+	}
+}
+
+func ParseConsul(snapshot []byte) ([]*Record, error) {
+	services := make(map[string][]*consul.ServiceEntry)
+	if err := json.Unmarshal(snapshot, &services); err != nil {
+		return nil, err
+	}
+	var records []*Record
+	for name, entries := range services {
+		for _, entry := range entries {
+			if record := consulRecord(name, entry); record != nil {
+				records = append(records, record)
+			}
+		}
+	}
+	return records, nil
+}
+
+// consulRecord returns nil if the service has no port
+func consulRecord(name string, entry *consul.ServiceEntry) *Record {
+	if entry.Service == nil || entry.Service.Port == 0 {
+		return nil
+	}
+	address := entry.Service.Address
+	var node string
+	if entry.Node != nil {
+		node = entry.Node.Node
+		if len(address) == 0 {
+			address = entry.Node.Address
+		}
+	}
+	if len(address) == 0 {
+		return nil
+	}
+	hostName := node
+	if len(hostName) == 0 {
+		hostName = address
+	}
+
+	properties := copyProperties(entry.Service.Meta)
+	version := properties[META_VERSION]
+	for _, tag := range entry.Service.Tags {
+		if strings.HasPrefix(tag, META_VERSION+"=") && len(version) == 0 {
+			version = strings.TrimPrefix(tag, META_VERSION+"=")
+		}
+	}
+	if len(entry.Service.Tags) > 0 {
+		properties["tags"] = strings.Join(entry.Service.Tags, ",")
+	}
+
+	status := pb.MSI_UP
+	for _, check := range entry.Checks {
+		if check.Status == consul.HEALTH_CRITICAL {
+			status = pb.MSI_DOWN
+		}
+	}
+	return &Record{
+		Id:          node + "/" + entry.Service.ID,
+		ServiceName: name,
+		Version:     versionOf(version),
+		HostName:    hostName,
+		Endpoints:   []string{"rest://" + net.JoinHostPort(address, strconv.Itoa(entry.Service.Port))},
+		Status:      status,
+		Properties:  properties,
+	}
+}
+
+func copyProperties(m map[string]string) map[string]string {
+	properties := make(map[string]string, len(m)+2)
+	for k, v := range m {
+		properties[k] = v
+	}
+	return properties
+}
+
+// Fetch reads the snapshot from the source registry
+func Fetch(ctx context.Context, source, address string) ([]byte, error) {
+	address = strings.TrimRight(address, "/")
+	switch source {
+	case SOURCE_EUREKA:
+		return get(ctx, address+"/apps")
+	case SOURCE_CONSUL:
+		return fetchConsul(ctx, address)
+	default:
+		return nil, fmt.Errorf("unsupported source '%s'", source)
+	}
+}
+
+func fetchConsul(ctx context.Context, address string) ([]byte, error) {
+	b, err := get(ctx, address+"/v1/catalog/services")
+	if err != nil {
+		return nil, err
+	}
+	catalog := make(map[string][]string)
+	if err := json.Unmarshal(b, &catalog); err != nil {
+		return nil, err
+	}
+	services := make(map[string]json.RawMessage, len(catalog))
+	for name := range catalog {
+		b, err := get(ctx, address+"/v1/health/service/"+url.PathEscape(name))
+		if err != nil {
+			return nil, err
+		}
+		services[name] = b
+	}
+	return json.Marshal(services)
+}
+
+func get(ctx context.Context, u string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	client := &http.Client{Timeout: fetchTimeout}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("get %s failed, %d %s", u, resp.StatusCode, b)
+	}
+	return b, nil
+}
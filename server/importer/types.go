@@ -0,0 +1,94 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package importer
+
+import (
+	"encoding/json"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+)
+
+// ImportRequest imports the registry snapshot in the body, or fetches it
+// from the address if the snapshot is empty. The eureka snapshot is the
+// response of '{address}/apps', the consul snapshot is the map of the
+// service names to the responses of '/v1/health/service/:name'
+type ImportRequest struct {
+	Source   string          `json:"source"`
+	Address  string          `json:"address,omitempty"`
+	Snapshot json.RawMessage `json:"snapshot,omitempty"`
+	// AppId is the app id of the services imported, default is 'default'
+	AppId string `json:"appId,omitempty"`
+}
+
+type ImportResponse struct {
+	Response  *pb.Response `json:"response,omitempty"`
+	Services  int          `json:"services"`
+	Instances int          `json:"instances"`
+	Errors    []string     `json:"errors,omitempty"`
+}
+
+// Record is an instance read from the snapshot
+type Record struct {
+	// Id is the unique id of the instance in the source registry
+	Id          string
+	ServiceName string
+	Version     string
+	HostName    string
+	Endpoints   []string
+	Status      string
+	Properties  map[string]string
+}
+
+// the eureka '/apps' response types
+
+type EurekaApps struct {
+	Applications struct {
+		Application []*EurekaApplication `json:"application"`
+	} `json:"applications"`
+}
+
+type EurekaApplication struct {
+	Name string `json:"name"`
+	// the instance is an object if the application has only one instance
+	Instance json.RawMessage `json:"instance"`
+}
+
+type EurekaInstance struct {
+	InstanceId string            `json:"instanceId"`
+	HostName   string            `json:"hostName"`
+	App        string            `json:"app"`
+	IpAddr     string            `json:"ipAddr"`
+	Status     string            `json:"status"`
+	Port       EurekaPort        `json:"port"`
+	SecurePort EurekaPort        `json:"securePort"`
+	Metadata   map[string]string `json:"metadata"`
+}
+
+type EurekaPort struct {
+	Port    int         `json:"$"`
+	Enabled interface{} `json:"@enabled"`
+}
+
+// IsEnabled returns true if '@enabled' is true or "true"
+func (p EurekaPort) IsEnabled() bool {
+	switch v := p.Enabled.(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	}
+	return false
+}
@@ -0,0 +1,284 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package integrity
+
+import (
+	"encoding/json"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/core/backend"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	"github.com/coreos/etcd/mvcc/mvccpb"
+	"golang.org/x/net/context"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// TYPE_INSTANCE_SERVICE is the instance whose service does not exist
+	TYPE_INSTANCE_SERVICE = "instanceService"
+	// TYPE_INSTANCE_LEASE is the instance without the lease key
+	TYPE_INSTANCE_LEASE = "instanceLease"
+	// TYPE_INDEX is the index entry pointing to the service not existing
+	TYPE_INDEX = "index"
+	// TYPE_ALIAS is the alias entry pointing to the service not existing
+	TYPE_ALIAS = "alias"
+	// TYPE_DEPENDENCY_RULE is the dependency rule referencing the consumer
+	// not existing
+	TYPE_DEPENDENCY_RULE = "dependencyRule"
+)
+
+// MAX_REPORT_VIOLATIONS limits the violations listed in the report, the
+// counts are always complete
+const MAX_REPORT_VIOLATIONS = 1000
+
+var ViolationTypes = []string{TYPE_INSTANCE_SERVICE, TYPE_INSTANCE_LEASE, TYPE_INDEX,
+	TYPE_ALIAS, TYPE_DEPENDENCY_RULE}
+
+type Violation struct {
+	Type string `json:"type"`
+	Key  string `json:"key"`
+	// Ref is the key or the service id the resource references
+	Ref string `json:"ref"`
+}
+
+type Report struct {
+	Revision   int64          `json:"revision"`
+	StartAt    string         `json:"startAt"`
+	FinishAt   string         `json:"finishAt"`
+	Counts     map[string]int `json:"counts"`
+	Violations []*Violation   `json:"violations,omitempty"`
+	Error      string         `json:"error,omitempty"`
+}
+
+// Snapshot is the resources read at the same revision
+type Snapshot struct {
+	Revision     int64
+	Services     []*mvccpb.KeyValue
+	Indexes      []*mvccpb.KeyValue
+	Aliases      []*mvccpb.KeyValue
+	Instances    []*mvccpb.KeyValue
+	Leases       []*mvccpb.KeyValue
+	Dependencies []*mvccpb.KeyValue
+}
+
+// Checker verifies the references between the resources, the violations
+// are reported only, they are never repaired by the checker
+type Checker struct {
+	lock sync.RWMutex
+	last *Report
+}
+
+func (c *Checker) Check(ctx context.Context) (*Report, error) {
+	report := &Report{
+		StartAt: time.Now().UTC().Format(time.RFC3339),
+		Counts:  make(map[string]int, len(ViolationTypes)),
+	}
+	s, err := Load(ctx)
+	if err != nil {
+		report.Error = err.Error()
+	} else {
+		report.Revision = s.Revision
+		violations := Verify(s)
+		for _, v := range violations {
+			report.Counts[v.Type]++
+		}
+		if len(violations) > MAX_REPORT_VIOLATIONS {
+			violations = violations[:MAX_REPORT_VIOLATIONS]
+		}
+		report.Violations = violations
+	}
+	report.FinishAt = time.Now().UTC().Format(time.RFC3339)
+
+	c.lock.Lock()
+	c.last = report
+	c.lock.Unlock()
+
+	if err != nil {
+		return report, err
+	}
+	ReportViolations(report.Counts)
+	if len(report.Violations) > 0 {
+		log.Warnf("found integrity violations %v at revision %d", report.Counts, report.Revision)
+	}
+	return report, nil
+}
+
+// Last returns the report of the last check, nil if it never runs
+func (c *Checker) Last() *Report {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.last
+}
+
+// Load reads the resources at the same revision
+func Load(ctx context.Context) (*Snapshot, error) {
+	resp, err := list(ctx, core.GetServiceRootKey(""), 0, true)
+	if err != nil {
+		return nil, err
+	}
+	s := &Snapshot{Revision: resp.Revision, Services: resp.Kvs}
+	for _, res := range []struct {
+		Root    string
+		KeyOnly bool
+		Kvs     *[]*mvccpb.KeyValue
+	}{
+		{core.GetServiceIndexRootKey(""), false, &s.Indexes},
+		{core.GetServiceAliasRootKey(""), false, &s.Aliases},
+		{core.GetInstanceRootKey(""), true, &s.Instances},
+		{core.GetInstanceLeaseRootKey(""), true, &s.Leases},
+		{core.GetServiceDependencyRuleRootKey(""), false, &s.Dependencies},
+	} {
+		resp, err := list(ctx, res.Root, s.Revision, res.KeyOnly)
+		if err != nil {
+			return nil, err
+		}
+		*res.Kvs = resp.Kvs
+	}
+	return s, nil
+}
+
+// Verify returns the violations of the snapshot:
+// every instance has the service and the lease key, every index and alias
+// entry points to an existing service, and the consumers referenced by the
+// dependency rules exist
+func Verify(s *Snapshot) []*Violation {
+	serviceRoot := core.GetServiceRootKey("")
+	services := keySet(s.Services, serviceRoot)
+	leases := keySet(s.Leases, core.GetInstanceLeaseRootKey(""))
+
+	var violations []*Violation
+	instanceRoot := core.GetInstanceRootKey("")
+	for _, kv := range s.Instances {
+		key := util.BytesToStringWithNoCopy(kv.Key)
+		arr := strings.Split(key[len(instanceRoot):], core.SPLIT)
+		if len(arr) != 4 {
+			continue
+		}
+		service := strings.Join(arr[:3], core.SPLIT)
+		if _, ok := services[service]; !ok {
+			violations = append(violations, &Violation{
+				Type: TYPE_INSTANCE_SERVICE,
+				Key:  key,
+				Ref:  serviceRoot + service,
+			})
+		}
+		if _, ok := leases[key[len(instanceRoot):]]; !ok {
+			violations = append(violations, &Violation{
+				Type: TYPE_INSTANCE_LEASE,
+				Key:  key,
+				Ref:  core.GetInstanceLeaseRootKey("") + key[len(instanceRoot):],
+			})
+		}
+	}
+
+	violations = append(violations, verifyIndexes(TYPE_INDEX, s.Indexes, services)...)
+	violations = append(violations, verifyIndexes(TYPE_ALIAS, s.Aliases, services)...)
+	return append(violations, verifyDependencyRules(s, keySet(s.Indexes, core.GetServiceIndexRootKey("")))...)
+}
+
+// verifyIndexes finds the index entries whose service id does not exist
+func verifyIndexes(t string, kvs []*mvccpb.KeyValue, services map[string]struct{}) []*Violation {
+	var violations []*Violation
+	for _, kv := range kvs {
+		key := core.GetInfoFromSvcIndexKV(kv.Key)
+		if key == nil {
+			continue
+		}
+		serviceId := util.BytesToStringWithNoCopy(kv.Value)
+		if _, ok := services[key.Tenant+core.SPLIT+serviceId]; ok {
+			continue
+		}
+		violations = append(violations, &Violation{
+			Type: t,
+			Key:  util.BytesToStringWithNoCopy(kv.Key),
+			Ref:  serviceId,
+		})
+	}
+	return violations
+}
+
+// verifyDependencyRules finds the consumer rules of the services not
+// existing, and the consumers not existing in the provider rules, the
+// providers are not verified as they may be the version rules
+func verifyDependencyRules(s *Snapshot, indexes map[string]struct{}) []*Violation {
+	indexRoot := core.GetServiceIndexRootKey("")
+	exist := func(in *pb.MicroServiceKey) bool {
+		_, ok := indexes[core.GenerateServiceIndexKey(in)[len(indexRoot):]]
+		return ok
+	}
+
+	var violations []*Violation
+	for _, kv := range s.Dependencies {
+		t, in := core.GetInfoFromDependencyRuleKV(kv.Key)
+		if in == nil || in.ServiceName == "*" {
+			// the wildcard rules
+			continue
+		}
+		key := util.BytesToStringWithNoCopy(kv.Key)
+		switch t {
+		case core.DEPS_CONSUMER:
+			if !exist(in) {
+				violations = append(violations, &Violation{
+					Type: TYPE_DEPENDENCY_RULE,
+					Key:  key,
+					Ref:  core.GenerateServiceIndexKey(in),
+				})
+			}
+		case core.DEPS_PROVIDER:
+			dep := &pb.MicroServiceDependency{}
+			if err := json.Unmarshal(kv.Value, dep); err != nil {
+				log.Errorf(err, "unmarshal the dependency rule %s failed", key)
+				continue
+			}
+			for _, consumer := range dep.Dependency {
+				if !exist(consumer) {
+					violations = append(violations, &Violation{
+						Type: TYPE_DEPENDENCY_RULE,
+						Key:  key,
+						Ref:  core.GenerateServiceIndexKey(consumer),
+					})
+				}
+			}
+		}
+	}
+	return violations
+}
+
+func list(ctx context.Context, prefix string, rev int64, keyOnly bool) (*registry.PluginResponse, error) {
+	opts := []registry.PluginOpOption{
+		registry.GET,
+		registry.WithStrKey(prefix),
+		registry.WithPrefix(),
+		registry.WithRev(rev),
+	}
+	if keyOnly {
+		opts = append(opts, registry.WithKeyOnly())
+	}
+	return backend.Registry().Do(ctx, opts...)
+}
+
+func keySet(kvs []*mvccpb.KeyValue, root string) map[string]struct{} {
+	set := make(map[string]struct{}, len(kvs))
+	for _, kv := range kvs {
+		set[util.BytesToStringWithNoCopy(kv.Key)[len(root):]] = struct{}{}
+	}
+	return set
+}
@@ -0,0 +1,84 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package integrity
+
+import (
+	"encoding/json"
+	"github.com/apache/servicecomb-service-center/server/core"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"github.com/coreos/etcd/mvcc/mvccpb"
+	"testing"
+)
+
+func kv(key, value string) *mvccpb.KeyValue {
+	return &mvccpb.KeyValue{Key: []byte(key), Value: []byte(value)}
+}
+
+func TestVerify(t *testing.T) {
+	dp := "integrity_test/integrity_test"
+	consumer := &pb.MicroServiceKey{Tenant: dp, AppId: "a", ServiceName: "c", Version: "1.0.0"}
+	missing := &pb.MicroServiceKey{Tenant: dp, AppId: "a", ServiceName: "m", Version: "1.0.0"}
+	provider := &pb.MicroServiceKey{Tenant: dp, AppId: "a", ServiceName: "p", Version: "1.0.0"}
+	deps, _ := json.Marshal(&pb.MicroServiceDependency{
+		Dependency: []*pb.MicroServiceKey{consumer, missing},
+	})
+
+	s := &Snapshot{
+		Services: []*mvccpb.KeyValue{kv(core.GenerateServiceKey(dp, "s1"), "")},
+		Indexes: []*mvccpb.KeyValue{
+			kv(core.GenerateServiceIndexKey(consumer), "s1"),
+			kv(core.GenerateServiceIndexKey(provider), "s2"),
+		},
+		Aliases: []*mvccpb.KeyValue{kv(core.GenerateServiceAliasKey(consumer), "s1")},
+		Instances: []*mvccpb.KeyValue{
+			kv(core.GenerateInstanceKey(dp, "s1", "i1"), ""),
+			kv(core.GenerateInstanceKey(dp, "s1", "i2"), ""),
+			kv(core.GenerateInstanceKey(dp, "s2", "i3"), ""),
+		},
+		Leases: []*mvccpb.KeyValue{
+			kv(core.GenerateInstanceLeaseKey(dp, "s1", "i1"), "1"),
+			kv(core.GenerateInstanceLeaseKey(dp, "s2", "i3"), "1"),
+		},
+		Dependencies: []*mvccpb.KeyValue{
+			kv(core.GenerateConsumerDependencyRuleKey(dp, consumer), ""),
+			kv(core.GenerateConsumerDependencyRuleKey(dp, missing), ""),
+			kv(core.GenerateProviderDependencyRuleKey(dp, provider), string(deps)),
+			kv(core.GenerateConsumerDependencyRuleKey(dp, &pb.MicroServiceKey{ServiceName: "*"}), ""),
+		},
+	}
+
+	expected := map[string]*Violation{
+		TYPE_INSTANCE_SERVICE: {Key: core.GenerateInstanceKey(dp, "s2", "i3"), Ref: core.GenerateServiceKey(dp, "s2")},
+		TYPE_INSTANCE_LEASE:   {Key: core.GenerateInstanceKey(dp, "s1", "i2"), Ref: core.GenerateInstanceLeaseKey(dp, "s1", "i2")},
+		TYPE_INDEX:            {Key: core.GenerateServiceIndexKey(provider), Ref: "s2"},
+	}
+	violations := Verify(s)
+	counts := make(map[string]int)
+	for _, v := range violations {
+		counts[v.Type]++
+		if e, ok := expected[v.Type]; ok && (e.Key != v.Key || e.Ref != v.Ref) {
+			t.Fatalf("TestVerify failed, %v", v)
+		}
+		if v.Type == TYPE_DEPENDENCY_RULE && v.Ref != core.GenerateServiceIndexKey(missing) {
+			t.Fatalf("TestVerify failed, %v", v)
+		}
+	}
+	if len(counts) != 4 || counts[TYPE_INSTANCE_SERVICE] != 1 || counts[TYPE_INSTANCE_LEASE] != 1 ||
+		counts[TYPE_INDEX] != 1 || counts[TYPE_ALIAS] != 0 || counts[TYPE_DEPENDENCY_RULE] != 2 {
+		t.Fatalf("TestVerify failed, %v", counts)
+	}
+}
@@ -0,0 +1,41 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package integrity
+
+import (
+	"github.com/apache/servicecomb-service-center/server/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var violationsGauge = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: metric.FamilyName,
+		Subsystem: "integrity",
+		Name:      "violations",
+		Help:      "Integrity violations found by the last check",
+	}, []string{"instance", "type"})
+
+func init() {
+	prometheus.MustRegister(violationsGauge)
+}
+
+func ReportViolations(counts map[string]int) {
+	instance := metric.InstanceName()
+	for _, t := range ViolationTypes {
+		violationsGauge.WithLabelValues(instance, t).Set(float64(counts[t]))
+	}
+}
@@ -36,6 +36,13 @@ func init() {
 func Intercept(w http.ResponseWriter, r *http.Request) error {
 	w.Header().Add(rest.HEADER_SERVER, serverName)
 
+	id := r.Header.Get(rest.HEADER_REQUEST_ID)
+	if !util.IsValidRequestId(id) {
+		id = util.GenerateUuid()
+	}
+	w.Header().Set(rest.HEADER_REQUEST_ID, id)
+	util.SetRequestContext(r, util.CtxRequestId, id)
+
 	if !validate.IsRequestURI(r.RequestURI) {
 		err := fmt.Errorf("Invalid Request URI %s", r.RequestURI)
 		w.WriteHeader(http.StatusBadRequest)
@@ -0,0 +1,92 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cors
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/astaxie/beego"
+	"strings"
+)
+
+const (
+	DEFAULT_ALLOWED_METHODS = "GET,POST,PUT,DELETE,UPDATE"
+	DEFAULT_ALLOWED_HEADERS = "Origin,Accept,Content-Type,X-Domain-Name,X-ConsumerId"
+)
+
+type Config struct {
+	// AllowedOrigins are the origins the browsers can send the cross
+	// domain requests from, '*' or empty means all
+	AllowedOrigins []string
+	AllowedMethods []string
+	AllowedHeaders []string
+	// ExposedHeaders are the response headers the scripts can read,
+	// e.g. X-Resource-Revision
+	ExposedHeaders   []string
+	AllowCredentials bool
+	// MaxAge is the seconds the preflight responses can be cached
+	MaxAge int
+}
+
+func LoadConfig() Config {
+	c := Config{
+		AllowedOrigins:   ParseList(beego.AppConfig.DefaultString("cors_allowed_origins", "")),
+		AllowedMethods:   ParseList(beego.AppConfig.DefaultString("cors_allowed_methods", DEFAULT_ALLOWED_METHODS)),
+		AllowedHeaders:   ParseList(beego.AppConfig.DefaultString("cors_allowed_headers", DEFAULT_ALLOWED_HEADERS)),
+		ExposedHeaders:   ParseList(beego.AppConfig.DefaultString("cors_exposed_headers", "")),
+		AllowCredentials: beego.AppConfig.DefaultInt("cors_allow_credentials", 0) != 0,
+		MaxAge:           beego.AppConfig.DefaultInt("cors_max_age", 0),
+	}
+	if len(c.AllowedMethods) == 0 {
+		c.AllowedMethods = ParseList(DEFAULT_ALLOWED_METHODS)
+	}
+	if len(c.AllowedHeaders) == 0 {
+		c.AllowedHeaders = ParseList(DEFAULT_ALLOWED_HEADERS)
+	}
+	if c.MaxAge < 0 {
+		c.MaxAge = 0
+	}
+	if c.AllowCredentials && c.allowAllOrigins() {
+		log.Warnf("cors_allow_credentials is disabled, the credentials are allowed only for the origins listed in cors_allowed_origins")
+		c.AllowCredentials = false
+	}
+	return c
+}
+
+// allowAllOrigins returns whether any site can send the cross domain
+// requests, the credentials of the users must not be sent by them
+func (c Config) allowAllOrigins() bool {
+	if len(c.AllowedOrigins) == 0 {
+		return true
+	}
+	for _, o := range c.AllowedOrigins {
+		if o == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseList splits the comma separated values and drops the empty ones
+func ParseList(s string) []string {
+	var l []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); len(v) > 0 {
+			l = append(l, v)
+		}
+	}
+	return l
+}
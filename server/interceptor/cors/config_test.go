@@ -0,0 +1,96 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cors
+
+import (
+	"github.com/astaxie/beego"
+	"github.com/rs/cors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestParseList(t *testing.T) {
+	for _, c := range []struct {
+		s string
+		l []string
+	}{
+		{"", nil},
+		{" , ", nil},
+		{"GET", []string{"GET"}},
+		{"GET, POST ,,PUT", []string{"GET", "POST", "PUT"}},
+	} {
+		if l := ParseList(c.s); !reflect.DeepEqual(l, c.l) {
+			t.Fatalf("TestParseList failed, %q: %v", c.s, l)
+		}
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	defer beego.AppConfig.Set("cors_allow_credentials", "0")
+	defer beego.AppConfig.Set("cors_allowed_origins", "")
+	beego.AppConfig.Set("cors_allow_credentials", "1")
+	for _, c := range []struct {
+		origins     string
+		credentials bool
+	}{
+		{"", false},
+		{"*", false},
+		{"https://a.com,*", false},
+		{"https://a.com", true},
+	} {
+		beego.AppConfig.Set("cors_allowed_origins", c.origins)
+		if cfg := LoadConfig(); cfg.AllowCredentials != c.credentials {
+			t.Fatalf("TestLoadConfig failed, %q: %v", c.origins, cfg.AllowCredentials)
+		}
+	}
+}
+
+func TestIntercept(t *testing.T) {
+	defer func(c *cors.Cors) { CORS = c }(CORS)
+
+	request := func(method, origin string) (*httptest.ResponseRecorder, error) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(method, "/v4/default/registry/microservices", nil)
+		r.Header.Set("Origin", origin)
+		if method == http.MethodOptions {
+			r.Header.Set("Access-Control-Request-Method", http.MethodGet)
+		}
+		return w, Intercept(w, r)
+	}
+
+	CORS = New(Config{
+		AllowedOrigins:   []string{"https://a.com"},
+		AllowedMethods:   ParseList(DEFAULT_ALLOWED_METHODS),
+		AllowedHeaders:   ParseList(DEFAULT_ALLOWED_HEADERS),
+		AllowCredentials: true,
+	})
+	w, err := request(http.MethodGet, "https://a.com")
+	if err != nil || w.Header().Get("Access-Control-Allow-Origin") != "https://a.com" ||
+		w.Header().Get("Access-Control-Allow-Credentials") != "true" {
+		t.Fatalf("TestIntercept failed, the allowed origin: %v, %v", w.Header(), err)
+	}
+	w, err = request(http.MethodGet, "https://b.com")
+	if err != nil || len(w.Header().Get("Access-Control-Allow-Origin")) > 0 ||
+		len(w.Header().Get("Access-Control-Allow-Credentials")) > 0 {
+		t.Fatalf("TestIntercept failed, the rejected origin: %v, %v", w.Header(), err)
+	}
+	if _, err = request(http.MethodOptions, "https://a.com"); err == nil {
+		t.Fatalf("TestIntercept failed, the preflight request should not be served")
+	}
+}
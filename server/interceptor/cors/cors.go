@@ -26,9 +26,17 @@ import (
 var CORS *cors.Cors
 
 func init() {
-	CORS = cors.New(cors.Options{
-		AllowedHeaders: []string{"Origin", "Accept", "Content-Type", "X-Domain-Name", "X-ConsumerId"},
-		AllowedMethods: []string{"GET", "POST", "PUT", "DELETE", "UPDATE"},
+	CORS = New(LoadConfig())
+}
+
+func New(c Config) *cors.Cors {
+	return cors.New(cors.Options{
+		AllowedOrigins:   c.AllowedOrigins,
+		AllowedMethods:   c.AllowedMethods,
+		AllowedHeaders:   c.AllowedHeaders,
+		ExposedHeaders:   c.ExposedHeaders,
+		AllowCredentials: c.AllowCredentials,
+		MaxAge:           c.MaxAge,
 	})
 }
 
@@ -0,0 +1,73 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package istio
+
+import (
+	"encoding/json"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/rest/controller"
+	"github.com/ghodss/yaml"
+	"net/http"
+	"strconv"
+)
+
+const CONTENT_TYPE_YAML = "application/yaml"
+
+// IstioController exports the services as the istio manifests, the
+// response is yaml if the 'format' query is 'yaml', otherwise json
+type IstioController struct {
+}
+
+func (ctrl *IstioController) URLPatterns() []rest.Route {
+	return []rest.Route{
+		{rest.HTTP_METHOD_GET, "/v4/:project/istio/serviceentries", ctrl.ServiceEntries},
+	}
+}
+
+func (ctrl *IstioController) ServiceEntries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+	exporter := &Exporter{Cfg: cfg}
+	list, err := exporter.Export(ctx, util.ParseDomainProject(ctx), &Filter{
+		AppId:       query.Get("appId"),
+		ServiceName: query.Get("serviceName"),
+		Environment: query.Get("env"),
+	})
+	if err != nil {
+		controller.WriteError(w, scerr.ErrInternal, err.Error())
+		return
+	}
+	if query.Get("format") != "yaml" {
+		controller.WriteResponse(w, nil, list)
+		return
+	}
+
+	b, err := json.Marshal(list)
+	if err == nil {
+		b, err = yaml.JSONToYAML(b)
+	}
+	if err != nil {
+		controller.WriteError(w, scerr.ErrInternal, err.Error())
+		return
+	}
+	w.Header().Set(rest.HEADER_RESPONSE_STATUS, strconv.Itoa(http.StatusOK))
+	w.Header().Set(rest.HEADER_CONTENT_TYPE, CONTENT_TYPE_YAML)
+	w.WriteHeader(http.StatusOK)
+	w.Write(b)
+}
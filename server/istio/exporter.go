@@ -0,0 +1,241 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package istio
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	serviceUtil "github.com/apache/servicecomb-service-center/server/service/util"
+	"golang.org/x/net/context"
+	"net/url"
+	"strings"
+)
+
+const (
+	LABEL_SERVICE_ID = "servicecomb.io/service-id"
+	LABEL_APP        = "servicecomb.io/app"
+	LABEL_SERVICE    = "servicecomb.io/service"
+	LABEL_VERSION    = "servicecomb.io/version"
+
+	LOCATION_MESH_INTERNAL = "MESH_INTERNAL"
+	RESOLUTION_STATIC      = "STATIC"
+
+	// the max length of the k8s resource names and label values
+	maxNameLength  = 253
+	maxLabelLength = 63
+)
+
+// Filter selects the services to export, the empty fields match all
+type Filter struct {
+	AppId       string
+	ServiceName string
+	Environment string
+}
+
+func (f *Filter) match(service *pb.MicroService) bool {
+	return (len(f.AppId) == 0 || f.AppId == service.AppId) &&
+		(len(f.ServiceName) == 0 || f.ServiceName == service.ServiceName) &&
+		(len(f.Environment) == 0 || f.Environment == service.Environment)
+}
+
+// Exporter renders the services in service center as the istio
+// ServiceEntry and the UP instances as the WorkloadEntry selected by it
+type Exporter struct {
+	Cfg Config
+}
+
+func (e *Exporter) Export(ctx context.Context, domainProject string, filter *Filter) (*List, error) {
+	ctx = util.SetContext(ctx, serviceUtil.CTX_CACHEONLY, "1")
+	services, err := serviceUtil.GetServicesByDomainProject(ctx, domainProject)
+	if err != nil {
+		return nil, err
+	}
+	list := &List{ApiVersion: API_VERSION_LIST, Kind: KIND_LIST, Items: []interface{}{}}
+	for _, service := range services {
+		if !filter.match(service) {
+			continue
+		}
+		instances, err := serviceUtil.GetAllInstancesOfOneService(ctx, domainProject, service.ServiceId)
+		if err != nil {
+			return nil, err
+		}
+		list.Items = append(list.Items, e.manifests(service, instances)...)
+	}
+	return list, nil
+}
+
+// manifests returns nothing if the service has no UP instance with a
+// valid endpoint, istio requires the ports of the service entries
+func (e *Exporter) manifests(service *pb.MicroService, instances []*pb.MicroServiceInstance) []interface{} {
+	labels := serviceLabels(service)
+	entry := &ServiceEntry{
+		ApiVersion: API_VERSION_NETWORKING,
+		Kind:       KIND_SERVICE_ENTRY,
+		Metadata: ObjectMeta{
+			Name:      resourceName(service.AppId, service.ServiceName, service.Version),
+			Namespace: e.Cfg.Namespace,
+			Labels:    labels,
+		},
+		Spec: ServiceEntrySpec{
+			Hosts:      []string{e.host(service)},
+			Location:   LOCATION_MESH_INTERNAL,
+			Resolution: RESOLUTION_STATIC,
+			WorkloadSelector: &WorkloadSelector{
+				Labels: map[string]string{LABEL_SERVICE_ID: labels[LABEL_SERVICE_ID]},
+			},
+		},
+	}
+
+	var workloads []interface{}
+	ports := make(map[string]*Port)
+	for _, instance := range instances {
+		if instance.Status != pb.MSI_UP {
+			continue
+		}
+		workload := e.workloadEntry(service, instance, labels)
+		if workload == nil {
+			continue
+		}
+		for name, number := range workload.Spec.Ports {
+			if _, ok := ports[name]; ok {
+				continue
+			}
+			port := &Port{Name: name, Number: number, Protocol: protocolOf(name)}
+			ports[name] = port
+			entry.Spec.Ports = append(entry.Spec.Ports, port)
+		}
+		workloads = append(workloads, workload)
+	}
+	if len(workloads) == 0 {
+		return nil
+	}
+	return append([]interface{}{entry}, workloads...)
+}
+
+// workloadEntry returns nil if the instance has no valid endpoint
+func (e *Exporter) workloadEntry(service *pb.MicroService, instance *pb.MicroServiceInstance, labels map[string]string) *WorkloadEntry {
+	var address string
+	ports := make(map[string]uint32)
+	for _, ep := range instance.Endpoints {
+		u, err := url.Parse(ep)
+		if err != nil {
+			continue
+		}
+		ipPort := util.ParseIpPort(u.Host)
+		if ipPort.Port == 0 {
+			continue
+		}
+		host := strings.Trim(ipPort.IP, "[]")
+		if len(address) == 0 {
+			address = host
+		}
+		if host != address {
+			// the workload entry has only one address
+			continue
+		}
+		name := portName(u)
+		if _, ok := ports[name]; !ok {
+			ports[name] = uint32(ipPort.Port)
+		}
+	}
+	if len(address) == 0 {
+		return nil
+	}
+	return &WorkloadEntry{
+		ApiVersion: API_VERSION_NETWORKING,
+		Kind:       KIND_WORKLOAD_ENTRY,
+		Metadata: ObjectMeta{
+			Name:      resourceName(service.ServiceName, instance.InstanceId),
+			Namespace: e.Cfg.Namespace,
+			Labels:    labels,
+		},
+		Spec: WorkloadEntrySpec{
+			Address: address,
+			Ports:   ports,
+			Labels:  labels,
+		},
+	}
+}
+
+// host returns '{service}.{app}.{suffix}'
+func (e *Exporter) host(service *pb.MicroService) string {
+	return sanitize(service.ServiceName, maxLabelLength) + "." +
+		sanitize(service.AppId, maxLabelLength) + "." + e.Cfg.HostSuffix
+}
+
+func serviceLabels(service *pb.MicroService) map[string]string {
+	return map[string]string{
+		LABEL_SERVICE_ID: sanitize(service.ServiceId, maxLabelLength),
+		LABEL_APP:        sanitize(service.AppId, maxLabelLength),
+		LABEL_SERVICE:    sanitize(service.ServiceName, maxLabelLength),
+		LABEL_VERSION:    sanitize(service.Version, maxLabelLength),
+	}
+}
+
+// portName returns the endpoint protocol, suffixed by '-tls' if the
+// endpoint is secure
+func portName(u *url.URL) string {
+	name := sanitize(u.Scheme, maxLabelLength)
+	if u.Query().Get("sslEnabled") == "true" {
+		name += "-tls"
+	}
+	return name
+}
+
+func protocolOf(portName string) string {
+	secure := strings.HasSuffix(portName, "-tls")
+	switch strings.TrimSuffix(portName, "-tls") {
+	case "rest", "http":
+		if secure {
+			return "HTTPS"
+		}
+		return "HTTP"
+	case "grpc":
+		if secure {
+			return "TLS"
+		}
+		return "GRPC"
+	default:
+		if secure {
+			return "TLS"
+		}
+		return "TCP"
+	}
+}
+
+func resourceName(parts ...string) string {
+	return sanitize(strings.Join(parts, "-"), maxNameLength)
+}
+
+// sanitize converts s to a valid dns label or k8s label value, the
+// lower case alphanumeric characters and '-', at most max characters
+func sanitize(s string, max int) string {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r - 'A' + 'a'
+		default:
+			return '-'
+		}
+	}, s)
+	if len(s) > max {
+		s = s[:max]
+	}
+	return strings.Trim(s, "-")
+}
@@ -0,0 +1,83 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package istio
+
+import (
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"testing"
+)
+
+func TestExporter_Manifests(t *testing.T) {
+	e := &Exporter{Cfg: Config{Namespace: "ns", HostSuffix: DEFAULT_HOST_SUFFIX}}
+	service := &pb.MicroService{ServiceId: "sid", AppId: "MyApp", ServiceName: "my_service", Version: "1.0.0"}
+	instances := []*pb.MicroServiceInstance{
+		{
+			InstanceId: "i1",
+			Status:     pb.MSI_UP,
+			Endpoints:  []string{"rest://10.0.0.1:8080?sslEnabled=true", "highway://10.0.0.1:7070"},
+		},
+		{InstanceId: "i2", Status: pb.MSI_DOWN, Endpoints: []string{"rest://10.0.0.2:8080"}},
+		{InstanceId: "i3", Status: pb.MSI_UP},
+	}
+
+	items := e.manifests(service, instances)
+	if len(items) != 2 {
+		t.Fatalf("TestExporter_Manifests failed, %v", items)
+	}
+	entry := items[0].(*ServiceEntry)
+	if entry.Metadata.Name != "myapp-my-service-1-0-0" || entry.Metadata.Namespace != "ns" ||
+		entry.Spec.Hosts[0] != "my-service.myapp.service.sc" ||
+		entry.Spec.WorkloadSelector.Labels[LABEL_SERVICE_ID] != "sid" {
+		t.Fatalf("TestExporter_Manifests failed, %v", entry)
+	}
+	if len(entry.Spec.Ports) != 2 {
+		t.Fatalf("TestExporter_Manifests failed, %v", entry.Spec.Ports)
+	}
+	for _, port := range entry.Spec.Ports {
+		switch port.Name {
+		case "rest-tls":
+			if port.Number != 8080 || port.Protocol != "HTTPS" {
+				t.Fatalf("TestExporter_Manifests failed, %v", port)
+			}
+		case "highway":
+			if port.Number != 7070 || port.Protocol != "TCP" {
+				t.Fatalf("TestExporter_Manifests failed, %v", port)
+			}
+		default:
+			t.Fatalf("TestExporter_Manifests failed, %v", port)
+		}
+	}
+	workload := items[1].(*WorkloadEntry)
+	if workload.Metadata.Name != "my-service-i1" || workload.Spec.Address != "10.0.0.1" ||
+		workload.Spec.Labels[LABEL_SERVICE_ID] != "sid" {
+		t.Fatalf("TestExporter_Manifests failed, %v", workload)
+	}
+
+	if items = e.manifests(service, instances[1:]); len(items) != 0 {
+		t.Fatalf("TestExporter_Manifests failed, %v", items)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	service := &pb.MicroService{AppId: "a", ServiceName: "s", Environment: "production"}
+	if !(&Filter{}).match(service) || !(&Filter{AppId: "a", ServiceName: "s"}).match(service) {
+		t.Fatalf("TestFilter failed")
+	}
+	if (&Filter{AppId: "b"}).match(service) || (&Filter{Environment: "development"}).match(service) {
+		t.Fatalf("TestFilter failed")
+	}
+}
@@ -0,0 +1,60 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package istio
+
+import (
+	roa "github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/astaxie/beego"
+	"strings"
+)
+
+const (
+	DEFAULT_NAMESPACE   = "default"
+	DEFAULT_HOST_SUFFIX = "service.sc"
+)
+
+var cfg Config
+
+func init() {
+	cfg = LoadConfig()
+	if !cfg.Enabled {
+		return
+	}
+	registerREST()
+}
+
+func registerREST() {
+	roa.RegisterServant(&IstioController{})
+}
+
+type Config struct {
+	Enabled bool
+	// Namespace is the k8s namespace of the exported manifests
+	Namespace string
+	// HostSuffix is appended to '{service}.{app}' as the host of the
+	// service entries
+	HostSuffix string
+}
+
+func LoadConfig() Config {
+	return Config{
+		Enabled:   beego.AppConfig.DefaultInt("istio_export", 0) != 0,
+		Namespace: beego.AppConfig.DefaultString("istio_namespace", DEFAULT_NAMESPACE),
+		HostSuffix: strings.ToLower(strings.Trim(
+			beego.AppConfig.DefaultString("istio_host_suffix", DEFAULT_HOST_SUFFIX), ".")),
+	}
+}
@@ -0,0 +1,77 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package istio
+
+const (
+	API_VERSION_LIST       = "v1"
+	API_VERSION_NETWORKING = "networking.istio.io/v1alpha3"
+
+	KIND_LIST           = "List"
+	KIND_SERVICE_ENTRY  = "ServiceEntry"
+	KIND_WORKLOAD_ENTRY = "WorkloadEntry"
+)
+
+// List is the k8s list of the manifests
+type List struct {
+	ApiVersion string        `json:"apiVersion"`
+	Kind       string        `json:"kind"`
+	Items      []interface{} `json:"items"`
+}
+
+type ObjectMeta struct {
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+}
+
+type ServiceEntry struct {
+	ApiVersion string           `json:"apiVersion"`
+	Kind       string           `json:"kind"`
+	Metadata   ObjectMeta       `json:"metadata"`
+	Spec       ServiceEntrySpec `json:"spec"`
+}
+
+type ServiceEntrySpec struct {
+	Hosts            []string          `json:"hosts"`
+	Ports            []*Port           `json:"ports"`
+	Location         string            `json:"location"`
+	Resolution       string            `json:"resolution"`
+	WorkloadSelector *WorkloadSelector `json:"workloadSelector,omitempty"`
+}
+
+type Port struct {
+	Number   uint32 `json:"number"`
+	Protocol string `json:"protocol"`
+	Name     string `json:"name"`
+}
+
+type WorkloadSelector struct {
+	Labels map[string]string `json:"labels"`
+}
+
+type WorkloadEntry struct {
+	ApiVersion string            `json:"apiVersion"`
+	Kind       string            `json:"kind"`
+	Metadata   ObjectMeta        `json:"metadata"`
+	Spec       WorkloadEntrySpec `json:"spec"`
+}
+
+type WorkloadEntrySpec struct {
+	Address string            `json:"address"`
+	Ports   map[string]uint32 `json:"ports,omitempty"`
+	Labels  map[string]string `json:"labels,omitempty"`
+}
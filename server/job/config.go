@@ -0,0 +1,54 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package job
+
+import (
+	roa "github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/server/metric"
+	"github.com/astaxie/beego"
+	"time"
+)
+
+const DEFAULT_ELECTION_TTL = 30 * time.Second
+
+var (
+	cfg       Config
+	scheduler *Scheduler
+)
+
+func init() {
+	cfg = LoadConfig()
+	scheduler = NewScheduler(NewElector(getLeaderKey(), metric.InstanceName(), cfg.ElectionTTL))
+	roa.RegisterServant(&JobController{})
+}
+
+type Config struct {
+	// ElectionTTL is how long the leader keeps the leadership after it
+	// stops renewing, e.g. it crashes
+	ElectionTTL time.Duration
+}
+
+func LoadConfig() Config {
+	c := Config{
+		ElectionTTL: DEFAULT_ELECTION_TTL,
+	}
+	d, err := time.ParseDuration(beego.AppConfig.DefaultString("job_election_ttl", ""))
+	if err == nil && d >= 3*time.Second {
+		c.ElectionTTL = d
+	}
+	return c
+}
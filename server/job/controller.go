@@ -0,0 +1,70 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package job
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/core"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/rest/controller"
+	"net/http"
+)
+
+type ListJobsResponse struct {
+	Jobs []*Status `json:"jobs"`
+}
+
+// JobController serves the status of the background jobs to the admin
+type JobController struct {
+}
+
+func (ctrl *JobController) URLPatterns() []rest.Route {
+	return []rest.Route{
+		{rest.HTTP_METHOD_GET, "/v4/:project/admin/jobs", ctrl.ListJobs},
+		{rest.HTTP_METHOD_GET, "/v4/:project/admin/jobs/:name", ctrl.GetJob},
+	}
+}
+
+func (ctrl *JobController) ListJobs(w http.ResponseWriter, r *http.Request) {
+	if !core.IsDefaultDomainProject(util.ParseDomainProject(r.Context())) {
+		controller.WriteError(w, scerr.ErrForbidden, "Required admin permission")
+		return
+	}
+	statuses, err := scheduler.Statuses(r.Context())
+	if err != nil {
+		controller.WriteError(w, scerr.ErrUnavailableBackend, err.Error())
+		return
+	}
+	controller.WriteResponse(w, nil, &ListJobsResponse{Jobs: statuses})
+}
+
+func (ctrl *JobController) GetJob(w http.ResponseWriter, r *http.Request) {
+	if !core.IsDefaultDomainProject(util.ParseDomainProject(r.Context())) {
+		controller.WriteError(w, scerr.ErrForbidden, "Required admin permission")
+		return
+	}
+	status, err := scheduler.Status(r.Context(), r.URL.Query().Get(":name"))
+	switch {
+	case err == ErrNotExist:
+		controller.WriteError(w, scerr.ErrInvalidParams, err.Error())
+	case err != nil:
+		controller.WriteError(w, scerr.ErrUnavailableBackend, err.Error())
+	default:
+		controller.WriteResponse(w, nil, status)
+	}
+}
@@ -0,0 +1,140 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package job
+
+import (
+	errorsEx "github.com/apache/servicecomb-service-center/pkg/errors"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/server/core/backend"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	"golang.org/x/net/context"
+	"sync"
+	"time"
+)
+
+// Elector campaigns for the leadership with a key bound to a lease, the
+// leader keeps renewing the lease, and the others take over after the
+// lease expires
+type Elector struct {
+	Key string
+	// Id identifies the candidate in the cluster
+	Id  string
+	TTL time.Duration
+
+	lock    sync.RWMutex
+	leaseID int64
+	// renewedAt is the last time the lease was granted or renewed
+	renewedAt time.Time
+}
+
+func NewElector(key, id string, ttl time.Duration) *Elector {
+	return &Elector{
+		Key: key,
+		Id:  id,
+		TTL: ttl,
+	}
+}
+
+func (e *Elector) IsLeader() bool {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+	return e.leaseID != 0
+}
+
+// Leader returns the id of the current leader, empty if no leader
+func (e *Elector) Leader(ctx context.Context) (string, error) {
+	resp, err := backend.Registry().Do(ctx, registry.GET, registry.WithStrKey(e.Key))
+	if err != nil || len(resp.Kvs) == 0 {
+		return "", err
+	}
+	return string(resp.Kvs[0].Value), nil
+}
+
+func (e *Elector) Run(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+		return
+	case <-backend.Registry().Ready():
+	}
+	for {
+		e.Campaign(ctx)
+		select {
+		case <-ctx.Done():
+			// resign to let the others take over at once
+			rctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			e.Resign(rctx)
+			cancel()
+			return
+		case <-time.After(e.TTL / 3):
+		}
+	}
+}
+
+// Campaign renews the lease if it is the leader, otherwise tries to be
+// the leader, returns true if it is the leader. The leadership is given
+// up if the lease is not found, or the renewals keep failing until the
+// lease may expire, the lease is revoked then before campaigning again
+func (e *Elector) Campaign(ctx context.Context) bool {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if e.leaseID != 0 {
+		_, err := backend.Registry().LeaseRenew(ctx, e.leaseID)
+		if err == nil {
+			e.renewedAt = time.Now()
+			return true
+		}
+		if _, ok := err.(errorsEx.InternalError); ok {
+			if time.Since(e.renewedAt) < e.TTL {
+				log.Errorf(err, "%s renew the leadership of %s failed, retry later", e.Id, e.Key)
+				return true
+			}
+			if err := backend.Registry().LeaseRevoke(ctx, e.leaseID); err != nil {
+				log.Errorf(err, "%s revoke the lease[%d] of %s failed", e.Id, e.leaseID, e.Key)
+			}
+		}
+		log.Errorf(err, "%s lost the leadership of %s", e.Id, e.Key)
+		e.leaseID = 0
+	}
+
+	leaseID, err := backend.Registry().LeaseGrant(ctx, int64(e.TTL/time.Second))
+	if err != nil {
+		log.Errorf(err, "%s campaign for %s failed", e.Id, e.Key)
+		return false
+	}
+	ok, err := backend.Registry().PutNoOverride(ctx,
+		registry.WithStrKey(e.Key), registry.WithStrValue(e.Id), registry.WithLease(leaseID))
+	if err != nil || !ok {
+		backend.Registry().LeaseRevoke(ctx, leaseID)
+		return false
+	}
+	e.leaseID, e.renewedAt = leaseID, time.Now()
+	log.Warnf("%s is elected as the leader of %s", e.Id, e.Key)
+	return true
+}
+
+func (e *Elector) Resign(ctx context.Context) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if e.leaseID == 0 {
+		return
+	}
+	if err := backend.Registry().LeaseRevoke(ctx, e.leaseID); err != nil {
+		log.Errorf(err, "%s resign the leadership of %s failed", e.Id, e.Key)
+	}
+	e.leaseID = 0
+	log.Warnf("%s resigned the leadership of %s", e.Id, e.Key)
+}
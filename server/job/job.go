@@ -0,0 +1,257 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package job
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/core/backend"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	"golang.org/x/net/context"
+	"sort"
+	"sync"
+	"time"
+)
+
+var ErrNotExist = errors.New("the job does not exist")
+
+// Job is the background task run by the leader only, so it runs exactly
+// once across the cluster in each interval
+type Job struct {
+	Name     string
+	Interval time.Duration
+	// Align runs the job at the multiples of the interval, e.g. on the
+	// hour, otherwise one interval after the last tick
+	Align bool
+	Run   func(ctx context.Context) error
+}
+
+func (j *Job) next(now time.Time) time.Time {
+	if j.Align {
+		return now.Truncate(j.Interval).Add(j.Interval)
+	}
+	return now.Add(j.Interval)
+}
+
+// Status is the last run of the job, it is saved in the backend by the
+// leader, so it is the same whichever service center is asked
+type Status struct {
+	Name     string `json:"name"`
+	Interval string `json:"interval"`
+	// Leader is the service center running the jobs now
+	Leader       string `json:"leader,omitempty"`
+	Runs         int64  `json:"runs"`
+	Failures     int64  `json:"failures"`
+	LastStartAt  string `json:"lastStartAt,omitempty"`
+	LastFinishAt string `json:"lastFinishAt,omitempty"`
+	LastDuration string `json:"lastDuration,omitempty"`
+	LastError    string `json:"lastError,omitempty"`
+	// RunBy is the service center ran the last
+	RunBy string `json:"runBy,omitempty"`
+}
+
+type Scheduler struct {
+	Elector *Elector
+
+	lock sync.RWMutex
+	jobs map[string]*Job
+	once sync.Once
+}
+
+func NewScheduler(e *Elector) *Scheduler {
+	return &Scheduler{
+		Elector: e,
+		jobs:    make(map[string]*Job),
+	}
+}
+
+// Register schedules the job, and starts the campaign when the first job
+// is registered
+func (s *Scheduler) Register(j Job) error {
+	if len(j.Name) == 0 || j.Interval <= 0 || j.Run == nil {
+		return fmt.Errorf("invalid job '%s'", j.Name)
+	}
+	s.lock.Lock()
+	if _, ok := s.jobs[j.Name]; ok {
+		s.lock.Unlock()
+		return fmt.Errorf("job '%s' is already registered", j.Name)
+	}
+	s.jobs[j.Name] = &j
+	s.lock.Unlock()
+
+	log.Infof("job %s is registered, run once every %s", j.Name, j.Interval)
+	s.once.Do(func() {
+		gopool.Go(s.Elector.Run)
+	})
+	gopool.Go(func(ctx context.Context) {
+		s.schedule(ctx, &j)
+	})
+	return nil
+}
+
+func (s *Scheduler) Jobs() []Job {
+	s.lock.RLock()
+	jobs := make([]Job, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		jobs = append(jobs, *j)
+	}
+	s.lock.RUnlock()
+	sort.Slice(jobs, func(i, k int) bool {
+		return jobs[i].Name < jobs[k].Name
+	})
+	return jobs
+}
+
+func (s *Scheduler) schedule(ctx context.Context, j *Job) {
+	select {
+	case <-ctx.Done():
+		return
+	case <-backend.Registry().Ready():
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Until(j.next(time.Now()))):
+			if !s.Elector.IsLeader() {
+				log.Debugf("skip job %s, %s is not the leader", j.Name, s.Elector.Id)
+				continue
+			}
+			s.Execute(ctx, j)
+		}
+	}
+}
+
+// Execute runs the job and saves the status
+func (s *Scheduler) Execute(ctx context.Context, j *Job) error {
+	start := time.Now()
+	err := j.Run(ctx)
+	if err != nil {
+		log.Errorf(err, "job %s failed", j.Name)
+	}
+
+	status, lerr := load(ctx, j.Name)
+	if lerr != nil {
+		log.Errorf(lerr, "load the status of job %s failed", j.Name)
+		status = &Status{}
+	}
+	status.Runs++
+	status.LastError = ""
+	if err != nil {
+		status.Failures++
+		status.LastError = err.Error()
+	}
+	status.LastStartAt = start.UTC().Format(time.RFC3339)
+	status.LastFinishAt = time.Now().UTC().Format(time.RFC3339)
+	status.LastDuration = time.Since(start).String()
+	status.RunBy = s.Elector.Id
+	if serr := save(ctx, j.Name, status); serr != nil {
+		log.Errorf(serr, "save the status of job %s failed", j.Name)
+	}
+	return err
+}
+
+func (s *Scheduler) Status(ctx context.Context, name string) (*Status, error) {
+	s.lock.RLock()
+	j, ok := s.jobs[name]
+	s.lock.RUnlock()
+	if !ok {
+		return nil, ErrNotExist
+	}
+	leader, err := s.Elector.Leader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.status(ctx, j, leader)
+}
+
+func (s *Scheduler) Statuses(ctx context.Context) ([]*Status, error) {
+	leader, err := s.Elector.Leader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	jobs := s.Jobs()
+	statuses := make([]*Status, 0, len(jobs))
+	for i := range jobs {
+		status, err := s.status(ctx, &jobs[i], leader)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func (s *Scheduler) status(ctx context.Context, j *Job, leader string) (*Status, error) {
+	status, err := load(ctx, j.Name)
+	if err != nil {
+		return nil, err
+	}
+	status.Name = j.Name
+	status.Interval = j.Interval.String()
+	status.Leader = leader
+	return status, nil
+}
+
+func load(ctx context.Context, name string) (*Status, error) {
+	status := &Status{}
+	resp, err := backend.Registry().Do(ctx, registry.GET, registry.WithStrKey(getStatusKey(name)))
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Kvs) == 0 {
+		return status, nil
+	}
+	if err := json.Unmarshal(resp.Kvs[0].Value, status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+func save(ctx context.Context, name string, status *Status) error {
+	data, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	_, err = backend.Registry().Do(ctx, registry.PUT,
+		registry.WithStrKey(getStatusKey(name)), registry.WithValue(data))
+	return err
+}
+
+func getLeaderKey() string {
+	return util.StringJoin([]string{core.GetRootKey(), "election", "jobs"}, core.SPLIT)
+}
+
+func getStatusKey(name string) string {
+	return util.StringJoin([]string{core.GetRootKey(), "jobs", name}, core.SPLIT)
+}
+
+// Register schedules the job run by the leader, it is called in init()
+func Register(j Job) error {
+	return scheduler.Register(j)
+}
+
+// IsLeader returns true if this service center is the leader running the
+// jobs, the leader is campaigned once a job is registered
+func IsLeader() bool {
+	return scheduler.Elector.IsLeader()
+}
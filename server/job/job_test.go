@@ -0,0 +1,96 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package job
+
+import (
+	"errors"
+	"github.com/apache/servicecomb-service-center/server/core/backend"
+	"golang.org/x/net/context"
+	"testing"
+	"time"
+)
+
+func TestElector_Campaign(t *testing.T) {
+	ctx := context.Background()
+	a := NewElector("/cse-sr/election/job_test", "a", 3*time.Second)
+	b := NewElector("/cse-sr/election/job_test", "b", 3*time.Second)
+	if !a.Campaign(ctx) || !a.IsLeader() {
+		t.Fatalf("TestElector_Campaign failed")
+	}
+	if b.Campaign(ctx) || b.IsLeader() {
+		t.Fatalf("TestElector_Campaign failed")
+	}
+	if leader, err := b.Leader(ctx); err != nil || leader != "a" {
+		t.Fatalf("TestElector_Campaign failed, %s, %v", leader, err)
+	}
+	if !a.Campaign(ctx) {
+		t.Fatalf("TestElector_Campaign failed")
+	}
+
+	// lease not found
+	leaseID := a.leaseID
+	if err := backend.Registry().LeaseRevoke(ctx, leaseID); err != nil {
+		t.Fatalf("TestElector_Campaign failed, %s", err)
+	}
+	if !a.Campaign(ctx) || a.leaseID == leaseID {
+		t.Fatalf("TestElector_Campaign failed, the leadership is not regained")
+	}
+
+	a.Resign(ctx)
+	if a.IsLeader() || !b.Campaign(ctx) {
+		t.Fatalf("TestElector_Campaign failed")
+	}
+	b.Resign(ctx)
+}
+
+func TestScheduler_Execute(t *testing.T) {
+	ctx := context.Background()
+	s := NewScheduler(NewElector("/cse-sr/election/job_test", "a", 3*time.Second))
+	if err := s.Register(Job{Name: "job_test"}); err == nil {
+		t.Fatalf("TestScheduler_Execute failed")
+	}
+
+	fail := errors.New("failed")
+	j := &Job{Name: "job_test", Interval: time.Hour, Run: func(context.Context) error { return fail }}
+	if err := s.Execute(ctx, j); err != fail {
+		t.Fatalf("TestScheduler_Execute failed, %v", err)
+	}
+	j.Run = func(context.Context) error { return nil }
+	if err := s.Execute(ctx, j); err != nil {
+		t.Fatalf("TestScheduler_Execute failed, %v", err)
+	}
+	status, err := s.status(ctx, j, "a")
+	if err != nil || status.Runs < 2 || status.Failures < 1 || len(status.LastError) != 0 ||
+		status.RunBy != "a" || status.Interval != "1h0m0s" {
+		t.Fatalf("TestScheduler_Execute failed, %v, %v", status, err)
+	}
+	if _, err := s.Status(ctx, "notexist"); err != ErrNotExist {
+		t.Fatalf("TestScheduler_Execute failed, %v", err)
+	}
+}
+
+func TestJob_next(t *testing.T) {
+	now := time.Date(2026, 10, 17, 10, 20, 0, 0, time.UTC)
+	j := Job{Interval: time.Hour}
+	if next := j.next(now); !next.Equal(now.Add(time.Hour)) {
+		t.Fatalf("TestJob_next failed, %s", next)
+	}
+	j.Align = true
+	if next := j.next(now); !next.Equal(time.Date(2026, 10, 17, 11, 0, 0, 0, time.UTC)) {
+		t.Fatalf("TestJob_next failed, %s", next)
+	}
+}
@@ -0,0 +1,47 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package lock
+
+import (
+	roa "github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/astaxie/beego"
+)
+
+const DEFAULT_QUOTA = 100
+
+var (
+	cfg   Config
+	locks *Locks
+)
+
+func init() {
+	cfg = LoadConfig()
+	locks = NewLocks(cfg)
+	roa.RegisterServant(&LockController{})
+}
+
+type Config struct {
+	// Quota is the max number of the locks held in a domain project,
+	// 0 is unlimited
+	Quota int64
+}
+
+func LoadConfig() Config {
+	return Config{
+		Quota: beego.AppConfig.DefaultInt64("client_lock_quota", DEFAULT_QUOTA),
+	}
+}
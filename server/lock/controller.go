@@ -0,0 +1,116 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package lock
+
+import (
+	"encoding/json"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/rest/controller"
+	"io/ioutil"
+	"net/http"
+)
+
+// LockController serves the named locks to the clients, the locks of
+// each domain project are isolated
+type LockController struct {
+}
+
+func (ctrl *LockController) URLPatterns() []rest.Route {
+	return []rest.Route{
+		{rest.HTTP_METHOD_GET, "/v4/:project/registry/locks/:name", ctrl.Get},
+		{rest.HTTP_METHOD_POST, "/v4/:project/registry/locks/:name", ctrl.Acquire},
+		{rest.HTTP_METHOD_PUT, "/v4/:project/registry/locks/:name", ctrl.Renew},
+		{rest.HTTP_METHOD_DELETE, "/v4/:project/registry/locks/:name", ctrl.Release},
+	}
+}
+
+// Get returns the holder of the lock, the followers of a leader election
+// find the leader by it
+func (ctrl *LockController) Get(w http.ResponseWriter, r *http.Request) {
+	lock, err := locks.Get(r.Context(), util.ParseDomainProject(r.Context()), r.URL.Query().Get(":name"))
+	if err != nil {
+		writeError(w, "get lock failed", err)
+		return
+	}
+	controller.WriteResponse(w, nil, lock)
+}
+
+func (ctrl *LockController) Acquire(w http.ResponseWriter, r *http.Request) {
+	request := &pb.AcquireLockRequest{}
+	if !readRequest(w, r, request) {
+		return
+	}
+	lock, err := locks.Acquire(r.Context(), util.ParseDomainProject(r.Context()), r.URL.Query().Get(":name"), request)
+	if err != nil {
+		writeError(w, "acquire lock failed", err)
+		return
+	}
+	controller.WriteResponse(w, nil, lock)
+}
+
+// Renew should be called in a third of the ttl, the lock is lost once the
+// ttl expires
+func (ctrl *LockController) Renew(w http.ResponseWriter, r *http.Request) {
+	request := &pb.RenewLockRequest{}
+	if !readRequest(w, r, request) {
+		return
+	}
+	lock, err := locks.Renew(r.Context(), util.ParseDomainProject(r.Context()), r.URL.Query().Get(":name"), request)
+	if err != nil {
+		writeError(w, "renew lock failed", err)
+		return
+	}
+	controller.WriteResponse(w, nil, lock)
+}
+
+func (ctrl *LockController) Release(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	err := locks.Release(r.Context(), util.ParseDomainProject(r.Context()), query.Get(":name"), query.Get("session"))
+	if err != nil {
+		writeError(w, "release lock failed", err)
+		return
+	}
+	controller.WriteResponse(w, nil, nil)
+}
+
+func readRequest(w http.ResponseWriter, r *http.Request, request interface{}) bool {
+	message, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Error("read body failed", err)
+		controller.WriteError(w, scerr.ErrInvalidParams, err.Error())
+		return false
+	}
+	if err := json.Unmarshal(message, request); err != nil {
+		log.Error("Unmarshal error", err)
+		controller.WriteError(w, scerr.ErrInvalidParams, err.Error())
+		return false
+	}
+	return true
+}
+
+func writeError(w http.ResponseWriter, msg string, err error) {
+	if e, ok := err.(*scerr.Error); ok {
+		controller.WriteError(w, e.Code, e.Detail)
+		return
+	}
+	log.Errorf(err, msg)
+	controller.WriteError(w, scerr.ErrUnavailableBackend, err.Error())
+}
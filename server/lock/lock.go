@@ -0,0 +1,304 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package lock
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	errorsEx "github.com/apache/servicecomb-service-center/pkg/errors"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/core/backend"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	"golang.org/x/net/context"
+	"regexp"
+	"time"
+)
+
+const (
+	MIN_TTL     = 5
+	MAX_TTL     = 600
+	DEFAULT_TTL = 30
+
+	// SESSION_SIZE is the random bytes of the session
+	SESSION_SIZE = 16
+)
+
+var (
+	nameRegex   = regexp.MustCompile(`^[a-zA-Z0-9_.\-]{1,128}$`)
+	holderRegex = regexp.MustCompile(`^\S{1,128}$`)
+)
+
+// record is the value of the lock key, the key is bound to the lease so
+// it is removed once the holder stops renewing. The holder is public, the
+// session is the random secret of the acquisition
+type record struct {
+	Holder     string `json:"holder"`
+	Session    string `json:"session"`
+	TTL        int64  `json:"ttl"`
+	LeaseID    int64  `json:"leaseId"`
+	AcquiredAt string `json:"acquiredAt"`
+}
+
+// Locks serves the locks to the clients, every acquisition grants a new
+// lease, so renewing or revoking the lease of a stale acquisition never
+// affects the current holder
+type Locks struct {
+	Cfg Config
+}
+
+func (l *Locks) Get(ctx context.Context, domainProject, name string) (*pb.Lock, error) {
+	if err := checkName(name); err != nil {
+		return nil, err
+	}
+	rec, token, err := l.get(ctx, domainProject, name)
+	if err != nil {
+		return nil, err
+	}
+	return toLock(name, rec, token), nil
+}
+
+// Acquire takes the free lock, or renews the lock held by the session of
+// the request
+func (l *Locks) Acquire(ctx context.Context, domainProject, name string, in *pb.AcquireLockRequest) (*pb.Lock, error) {
+	if err := checkName(name); err != nil {
+		return nil, err
+	}
+	if err := checkHolder(in.Holder); err != nil {
+		return nil, err
+	}
+	ttl := in.TTL
+	if ttl == 0 {
+		ttl = DEFAULT_TTL
+	}
+	if ttl < MIN_TTL || ttl > MAX_TTL {
+		return nil, scerr.NewErrorf(scerr.ErrInvalidParams, "ttl must be in [%d, %d]", MIN_TTL, MAX_TTL)
+	}
+
+	rec, token, err := l.get(ctx, domainProject, name)
+	if err != nil {
+		return nil, err
+	}
+	if rec != nil {
+		if rec.Holder != in.Holder || !rec.heldBy(in.Session) {
+			return nil, scerr.NewErrorf(scerr.ErrLockHeld, "lock '%s' is held by '%s'", name, rec.Holder)
+		}
+		return l.renew(ctx, name, rec, token)
+	}
+
+	if err := l.checkQuota(ctx, domainProject); err != nil {
+		return nil, err
+	}
+
+	session, err := newSession()
+	if err != nil {
+		return nil, err
+	}
+	leaseID, err := backend.Registry().LeaseGrant(ctx, ttl)
+	if err != nil {
+		return nil, err
+	}
+	rec = &record{
+		Holder:     in.Holder,
+		Session:    session,
+		TTL:        ttl,
+		LeaseID:    leaseID,
+		AcquiredAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		backend.Registry().LeaseRevoke(ctx, leaseID)
+		return nil, err
+	}
+	key := core.GenerateClientLockKey(domainProject, name)
+	resp, err := backend.Registry().TxnWithCmp(ctx,
+		[]registry.PluginOp{registry.OpPut(registry.WithStrKey(key), registry.WithValue(data),
+			registry.WithLease(leaseID))},
+		[]registry.CompareOp{registry.OpCmp(registry.CmpStrCreateRev(key), registry.CMP_EQUAL, 0)},
+		[]registry.PluginOp{registry.OpGet(registry.WithStrKey(key))})
+	if err != nil {
+		backend.Registry().LeaseRevoke(ctx, leaseID)
+		return nil, err
+	}
+	if !resp.Succeeded {
+		backend.Registry().LeaseRevoke(ctx, leaseID)
+		holder := ""
+		if len(resp.Kvs) > 0 {
+			if held, err := parseRecord(resp.Kvs[0].Value); err == nil {
+				holder = held.Holder
+			}
+		}
+		return nil, scerr.NewErrorf(scerr.ErrLockHeld, "lock '%s' is held by '%s'", name, holder)
+	}
+	log.Infof("lock %s/%s is acquired by %s, ttl %ds", domainProject, name, in.Holder, ttl)
+	lock := toLock(name, rec, resp.Revision)
+	lock.Session = rec.Session
+	return lock, nil
+}
+
+// Renew extends the ttl of the lock held by the session
+func (l *Locks) Renew(ctx context.Context, domainProject, name string, in *pb.RenewLockRequest) (*pb.Lock, error) {
+	if err := checkName(name); err != nil {
+		return nil, err
+	}
+	rec, token, err := l.held(ctx, domainProject, name, in.Session)
+	if err != nil {
+		return nil, err
+	}
+	return l.renew(ctx, name, rec, token)
+}
+
+// Release frees the lock held by the session, releasing the free lock
+// succeeds
+func (l *Locks) Release(ctx context.Context, domainProject, name, session string) error {
+	if err := checkName(name); err != nil {
+		return err
+	}
+	rec, _, err := l.get(ctx, domainProject, name)
+	if err != nil || rec == nil {
+		return err
+	}
+	if !rec.heldBy(session) {
+		return scerr.NewErrorf(scerr.ErrLockNotHeld, "lock '%s' is held by '%s'", name, rec.Holder)
+	}
+	// the key is deleted with the lease
+	if err := backend.Registry().LeaseRevoke(ctx, rec.LeaseID); err != nil {
+		return err
+	}
+	log.Infof("lock %s/%s is released by %s", domainProject, name, rec.Holder)
+	return nil
+}
+
+func (l *Locks) renew(ctx context.Context, name string, rec *record, token int64) (*pb.Lock, error) {
+	ttl, err := backend.Registry().LeaseRenew(ctx, rec.LeaseID)
+	if err != nil {
+		if _, ok := err.(errorsEx.InternalError); !ok {
+			// it means lease not found if err is not the InternalError type
+			return nil, scerr.NewErrorf(scerr.ErrLockNotHeld, "lock '%s' is expired", name)
+		}
+		return nil, err
+	}
+	lock := toLock(name, rec, token)
+	lock.TTL, lock.Session = ttl, rec.Session
+	return lock, nil
+}
+
+// held returns the lock record if it is held by the session
+func (l *Locks) held(ctx context.Context, domainProject, name, session string) (*record, int64, error) {
+	rec, token, err := l.get(ctx, domainProject, name)
+	if err != nil {
+		return nil, 0, err
+	}
+	if rec == nil {
+		return nil, 0, scerr.NewErrorf(scerr.ErrLockNotHeld, "lock '%s' is free", name)
+	}
+	if !rec.heldBy(session) {
+		return nil, 0, scerr.NewErrorf(scerr.ErrLockNotHeld, "lock '%s' is held by '%s'", name, rec.Holder)
+	}
+	return rec, token, nil
+}
+
+// get returns the record and the create revision of the lock, the record
+// is nil if the lock is free
+func (l *Locks) get(ctx context.Context, domainProject, name string) (*record, int64, error) {
+	resp, err := backend.Registry().Do(ctx, registry.GET,
+		registry.WithStrKey(core.GenerateClientLockKey(domainProject, name)))
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, 0, nil
+	}
+	rec, err := parseRecord(resp.Kvs[0].Value)
+	if err != nil {
+		return nil, 0, err
+	}
+	return rec, resp.Kvs[0].CreateRevision, nil
+}
+
+// checkQuota limits the locks of the domain project, the concurrent
+// acquisitions may exceed it slightly
+func (l *Locks) checkQuota(ctx context.Context, domainProject string) error {
+	if l.Cfg.Quota <= 0 {
+		return nil
+	}
+	resp, err := backend.Registry().Do(ctx, registry.GET,
+		registry.WithStrKey(core.GetClientLockRootKey(domainProject)+core.SPLIT),
+		registry.WithPrefix(), registry.WithCountOnly())
+	if err != nil {
+		return err
+	}
+	if resp.Count >= l.Cfg.Quota {
+		return scerr.NewErrorf(scerr.ErrNotEnoughQuota, "no more than %d locks in a project", l.Cfg.Quota)
+	}
+	return nil
+}
+
+func parseRecord(data []byte) (*record, error) {
+	rec := &record{}
+	if err := json.Unmarshal(data, rec); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+// heldBy compares the session in constant time, the empty one never holds
+// the lock
+func (rec *record) heldBy(session string) bool {
+	return len(session) > 0 &&
+		subtle.ConstantTimeCompare(util.StringToBytesWithNoCopy(rec.Session), util.StringToBytesWithNoCopy(session)) == 1
+}
+
+func newSession() (string, error) {
+	b := make([]byte, SESSION_SIZE)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// toLock returns the public view of the lock, the session is never in it
+func toLock(name string, rec *record, token int64) *pb.Lock {
+	lock := &pb.Lock{Name: name}
+	if rec != nil {
+		lock.Holder, lock.TTL, lock.Token, lock.AcquiredAt = rec.Holder, rec.TTL, token, rec.AcquiredAt
+	}
+	return lock
+}
+
+func checkName(name string) error {
+	if !nameRegex.MatchString(name) {
+		return scerr.NewErrorf(scerr.ErrInvalidParams, "lock name must match %s", nameRegex)
+	}
+	return nil
+}
+
+func checkHolder(holder string) error {
+	if !holderRegex.MatchString(holder) {
+		return scerr.NewError(scerr.ErrInvalidParams, "holder must be 1 to 128 non-space characters")
+	}
+	return nil
+}
+
+func NewLocks(cfg Config) *Locks {
+	return &Locks{Cfg: cfg}
+}
@@ -0,0 +1,186 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package lock
+
+import (
+	"github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/core/backend"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	"golang.org/x/net/context"
+	"strings"
+	"testing"
+)
+
+func TestLocks_Validate(t *testing.T) {
+	l := NewLocks(Config{})
+	ctx := context.Background()
+	for _, c := range []struct {
+		Name string
+		In   *pb.AcquireLockRequest
+	}{
+		{"", &pb.AcquireLockRequest{Holder: "h"}},
+		{"a/b", &pb.AcquireLockRequest{Holder: "h"}},
+		{strings.Repeat("a", 129), &pb.AcquireLockRequest{Holder: "h"}},
+		{"a", &pb.AcquireLockRequest{}},
+		{"a", &pb.AcquireLockRequest{Holder: "h 1"}},
+		{"a", &pb.AcquireLockRequest{Holder: "h", TTL: MIN_TTL - 1}},
+		{"a", &pb.AcquireLockRequest{Holder: "h", TTL: MAX_TTL + 1}},
+	} {
+		_, err := l.Acquire(ctx, "default/default", c.Name, c.In)
+		if e, ok := err.(*scerr.Error); !ok || e.Code != scerr.ErrInvalidParams {
+			t.Fatalf("TestLocks_Validate failed, %s %v: %v", c.Name, c.In, err)
+		}
+	}
+	if _, err := l.Get(ctx, "default/default", "a/b"); err == nil {
+		t.Fatalf("TestLocks_Validate failed")
+	}
+}
+
+func TestLocks_Fence(t *testing.T) {
+	l := NewLocks(Config{})
+	ctx, dp := context.Background(), "lock_test/lock_test"
+	lock, err := l.Acquire(ctx, dp, "fence", &pb.AcquireLockRequest{Holder: "a"})
+	if err != nil || lock.Holder != "a" || lock.Token <= 0 {
+		t.Fatalf("TestLocks_Fence failed, %v, %v", lock, err)
+	}
+	if _, err := l.Acquire(ctx, dp, "fence", &pb.AcquireLockRequest{Holder: "b"}); err == nil {
+		t.Fatalf("TestLocks_Fence failed")
+	}
+
+	key := core.GenerateServiceTagKey(dp, "fence")
+	fctx := backend.WithFence(ctx, backend.NewFence(core.GenerateClientLockKey(dp, "fence"), lock.Token))
+	if _, err := backend.Registry().Do(fctx, registry.PUT,
+		registry.WithStrKey(key), registry.WithStrValue(`{}`)); err != nil {
+		t.Fatalf("TestLocks_Fence failed, %v", err)
+	}
+
+	if err := l.Release(ctx, dp, "fence", lock.Session); err != nil {
+		t.Fatalf("TestLocks_Fence failed, %v", err)
+	}
+	next, err := l.Acquire(ctx, dp, "fence", &pb.AcquireLockRequest{Holder: "b"})
+	if err != nil || next.Token <= lock.Token {
+		t.Fatalf("TestLocks_Fence failed, %v, %v", next, err)
+	}
+	defer l.Release(ctx, dp, "fence", next.Session)
+
+	// the writes of the stale holder are rejected
+	if _, err := backend.Registry().Do(fctx, registry.DEL, registry.WithStrKey(key)); err != backend.ErrStaleFence {
+		t.Fatalf("TestLocks_Fence failed, %v", err)
+	}
+	if _, err := backend.Registry().TxnWithCmp(fctx, []registry.PluginOp{
+		registry.OpDel(registry.WithStrKey(key))}, nil, nil); err != backend.ErrStaleFence {
+		t.Fatalf("TestLocks_Fence failed, %v", err)
+	}
+	if !backend.FenceFromContext(fctx).Stale() {
+		t.Fatalf("TestLocks_Fence failed")
+	}
+	backend.Registry().Do(ctx, registry.DEL, registry.WithStrKey(key))
+}
+
+func TestLocks_FenceFailBranch(t *testing.T) {
+	l := NewLocks(Config{})
+	ctx, dp := context.Background(), "lock_test/lock_test"
+	lock, err := l.Acquire(ctx, dp, "fail", &pb.AcquireLockRequest{Holder: "a"})
+	if err != nil {
+		t.Fatalf("TestLocks_FenceFailBranch failed, %v", err)
+	}
+	fctx := backend.WithFence(ctx, backend.NewFence(core.GenerateClientLockKey(dp, "fail"), lock.Token))
+
+	key := core.GenerateServiceTagKey(dp, "fail")
+	defer backend.Registry().Do(ctx, registry.DEL, registry.WithStrKey(key))
+	failBranch := func(ctx context.Context, value string) (*registry.PluginResponse, error) {
+		return backend.Registry().TxnWithCmp(ctx, nil,
+			[]registry.CompareOp{registry.OpCmp(registry.CmpStrVal(key), registry.CMP_EQUAL, value)},
+			[]registry.PluginOp{registry.OpPut(registry.WithStrKey(key), registry.WithStrValue(value))})
+	}
+
+	// the fail branch runs with the valid fence, also when the key is absent
+	resp, err := failBranch(fctx, "1")
+	if err != nil || resp.Succeeded {
+		t.Fatalf("TestLocks_FenceFailBranch failed, %v, %v", resp, err)
+	}
+	resp, err = failBranch(fctx, "1")
+	if err != nil || !resp.Succeeded {
+		t.Fatalf("TestLocks_FenceFailBranch failed, %v, %v", resp, err)
+	}
+
+	// the fenced delete returns the count like the unfenced one
+	other := core.GenerateServiceTagKey(dp, "fail_other")
+	backend.Registry().Do(ctx, registry.PUT, registry.WithStrKey(other), registry.WithStrValue("1"))
+	resp, err = backend.Registry().Do(fctx, registry.DEL, registry.WithStrKey(other))
+	if err != nil || resp.Count != 1 {
+		t.Fatalf("TestLocks_FenceFailBranch failed, %v, %v", resp, err)
+	}
+
+	if err := l.Release(ctx, dp, "fail", lock.Session); err != nil {
+		t.Fatalf("TestLocks_FenceFailBranch failed, %v", err)
+	}
+	next, err := l.Acquire(ctx, dp, "fail", &pb.AcquireLockRequest{Holder: "b"})
+	if err != nil {
+		t.Fatalf("TestLocks_FenceFailBranch failed, %v", err)
+	}
+	defer l.Release(ctx, dp, "fail", next.Session)
+
+	// the stale fence never runs the fail branch
+	if _, err := failBranch(fctx, "2"); err != backend.ErrStaleFence {
+		t.Fatalf("TestLocks_FenceFailBranch failed, %v", err)
+	}
+	resp, err = backend.Registry().Do(ctx, registry.GET, registry.WithStrKey(key))
+	if err != nil || len(resp.Kvs) != 1 || string(resp.Kvs[0].Value) != "1" {
+		t.Fatalf("TestLocks_FenceFailBranch failed, the key is changed, %v, %v", resp, err)
+	}
+}
+
+func TestLocks_Session(t *testing.T) {
+	l := NewLocks(Config{})
+	ctx, dp := context.Background(), "lock_test/lock_test"
+	lock, err := l.Acquire(ctx, dp, "session", &pb.AcquireLockRequest{Holder: "a"})
+	if err != nil || len(lock.Session) != 2*SESSION_SIZE {
+		t.Fatalf("TestLocks_Session failed, %v, %v", lock, err)
+	}
+	defer l.Release(ctx, dp, "session", lock.Session)
+
+	// the session is never returned to the others
+	got, err := l.Get(ctx, dp, "session")
+	if err != nil || got.Holder != "a" || len(got.Session) != 0 {
+		t.Fatalf("TestLocks_Session failed, %v, %v", got, err)
+	}
+
+	// the holder is not a credential
+	if _, err := l.Acquire(ctx, dp, "session", &pb.AcquireLockRequest{Holder: "a"}); err == nil {
+		t.Fatalf("TestLocks_Session failed, acquired by the holder only")
+	}
+	for _, session := range []string{"", "a", strings.Repeat("0", 2*SESSION_SIZE)} {
+		if _, err := l.Renew(ctx, dp, "session", &pb.RenewLockRequest{Session: session}); err == nil {
+			t.Fatalf("TestLocks_Session failed, renewed by session '%s'", session)
+		}
+		if err := l.Release(ctx, dp, "session", session); err == nil {
+			t.Fatalf("TestLocks_Session failed, released by session '%s'", session)
+		}
+	}
+
+	renewed, err := l.Renew(ctx, dp, "session", &pb.RenewLockRequest{Session: lock.Session})
+	if err != nil || renewed.Session != lock.Session || renewed.Token != lock.Token {
+		t.Fatalf("TestLocks_Session failed, %v, %v", renewed, err)
+	}
+	renewed, err = l.Acquire(ctx, dp, "session", &pb.AcquireLockRequest{Holder: "a", Session: lock.Session})
+	if err != nil || renewed.Token != lock.Token {
+		t.Fatalf("TestLocks_Session failed, %v, %v", renewed, err)
+	}
+}
@@ -0,0 +1,67 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package maintenance
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/chain"
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	roa "github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/astaxie/beego"
+	"time"
+)
+
+const (
+	DEFAULT_SYNC_INTERVAL = 10 * time.Second
+	DEFAULT_RETRY_AFTER   = time.Minute
+)
+
+var (
+	cfg  Config
+	mode *Mode
+)
+
+func init() {
+	cfg = LoadConfig()
+	mode = NewMode(cfg)
+	chain.RegisterHandler(roa.SERVER_CHAIN_NAME, &MaintenanceHandler{})
+	roa.RegisterServant(&MaintenanceController{})
+	gopool.Go(mode.Run)
+}
+
+type Config struct {
+	// AllowReads and AllowHeartbeats are the defaults when the
+	// maintenance starts without them
+	AllowReads      bool
+	AllowHeartbeats bool
+	// SyncInterval is how often the maintenance status is read from
+	// the registry, the status set on the other instances takes effect
+	// in it
+	SyncInterval time.Duration
+}
+
+func LoadConfig() Config {
+	c := Config{
+		AllowReads:      beego.AppConfig.DefaultInt("maintenance_allow_reads", 1) != 0,
+		AllowHeartbeats: beego.AppConfig.DefaultInt("maintenance_allow_heartbeats", 1) != 0,
+		SyncInterval:    DEFAULT_SYNC_INTERVAL,
+	}
+	d, err := time.ParseDuration(beego.AppConfig.DefaultString("maintenance_sync_interval", ""))
+	if err == nil && d >= time.Second {
+		c.SyncInterval = d
+	}
+	return c
+}
@@ -0,0 +1,130 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package maintenance
+
+import (
+	"encoding/json"
+	"github.com/apache/servicecomb-service-center/pkg/chain"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/core"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/rest/controller"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const HEADER_RETRY_AFTER = "Retry-After"
+
+// MaintenanceHandler rejects the requests not allowed in maintenance with
+// the retriable error and the Retry-After header
+type MaintenanceHandler struct {
+}
+
+func (h *MaintenanceHandler) Handle(i *chain.Invocation) {
+	r := i.Context().Value(rest.CTX_REQUEST).(*http.Request)
+	pattern, _ := i.Context().Value(rest.CTX_MATCH_PATTERN).(string)
+	if mode.Allow(r.Method, pattern) {
+		i.Next()
+		return
+	}
+
+	w := i.Context().Value(rest.CTX_RESPONSE).(http.ResponseWriter)
+	w.Header().Set(HEADER_RETRY_AFTER, strconv.Itoa(int(mode.RetryAfter(time.Now()).Seconds())))
+	controller.WriteError(w, scerr.ErrMaintenance, mode.Detail())
+
+	i.Fail(nil)
+}
+
+// CheckRPC returns the retriable error if the grpc method is not allowed
+// in maintenance, and how long the clients should wait before retrying
+func CheckRPC(method string) (time.Duration, error) {
+	if mode.AllowRPC(method) {
+		return 0, nil
+	}
+	return mode.RetryAfter(time.Now()), scerr.NewError(scerr.ErrMaintenance, mode.Detail())
+}
+
+// MaintenanceController toggles the maintenance mode
+type MaintenanceController struct {
+}
+
+func (ctrl *MaintenanceController) URLPatterns() []rest.Route {
+	return []rest.Route{
+		{rest.HTTP_METHOD_GET, "/v4/:project/admin/maintenance", ctrl.Get},
+		{rest.HTTP_METHOD_PUT, "/v4/:project/admin/maintenance", ctrl.Start},
+		{rest.HTTP_METHOD_DELETE, "/v4/:project/admin/maintenance", ctrl.Stop},
+	}
+}
+
+func (ctrl *MaintenanceController) Get(w http.ResponseWriter, r *http.Request) {
+	if !core.IsDefaultDomainProject(util.ParseDomainProject(r.Context())) {
+		controller.WriteError(w, scerr.ErrForbidden, "Required admin permission")
+		return
+	}
+	status := mode.Status()
+	controller.WriteResponse(w, nil, &status)
+}
+
+// Start enters the maintenance mode of all the service centers, it should
+// be called before the registry maintenance starts
+func (ctrl *MaintenanceController) Start(w http.ResponseWriter, r *http.Request) {
+	if !core.IsDefaultDomainProject(util.ParseDomainProject(r.Context())) {
+		controller.WriteError(w, scerr.ErrForbidden, "Required admin permission")
+		return
+	}
+	message, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Error("read body failed", err)
+		controller.WriteError(w, scerr.ErrInvalidParams, err.Error())
+		return
+	}
+	request := &StartRequest{}
+	if len(message) > 0 {
+		if err := json.Unmarshal(message, request); err != nil {
+			log.Error("Unmarshal error", err)
+			controller.WriteError(w, scerr.ErrInvalidParams, err.Error())
+			return
+		}
+	}
+	status, err := mode.Start(r.Context(), request)
+	switch err {
+	case nil:
+		controller.WriteResponse(w, nil, &status)
+	case ErrInvalidDuration:
+		controller.WriteError(w, scerr.ErrInvalidParams, err.Error())
+	default:
+		log.Errorf(err, "start maintenance mode failed")
+		controller.WriteError(w, scerr.ErrUnavailableBackend, err.Error())
+	}
+}
+
+func (ctrl *MaintenanceController) Stop(w http.ResponseWriter, r *http.Request) {
+	if !core.IsDefaultDomainProject(util.ParseDomainProject(r.Context())) {
+		controller.WriteError(w, scerr.ErrForbidden, "Required admin permission")
+		return
+	}
+	if err := mode.Stop(r.Context()); err != nil {
+		log.Errorf(err, "stop maintenance mode failed")
+		controller.WriteError(w, scerr.ErrUnavailableBackend, err.Error())
+		return
+	}
+	controller.WriteResponse(w, nil, nil)
+}
@@ -0,0 +1,282 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package maintenance
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/core/backend"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	"golang.org/x/net/context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const maintenanceKey = "maintenance"
+
+var ErrInvalidDuration = errors.New("duration must be a positive duration like '30m'")
+
+// heartbeats are the routes keep working if heartbeats are allowed, the
+// instances would be evicted by lease expiry otherwise
+var heartbeats = map[string]struct{}{
+	"/v4/:project/registry/microservices/:serviceId/instances/:instanceId/heartbeat":   {},
+	"/v4/:project/registry/heartbeats":                                                 {},
+	"/v4.1/:project/registry/microservices/:serviceId/instances/:instanceId/heartbeat": {},
+	"/v4.1/:project/registry/heartbeats":                                               {},
+	"/registry/v3/microservices/:serviceId/instances/:instanceId/heartbeat":            {},
+	"/registry/v3/heartbeats":                                                          {},
+	"/v4/:project/registry/agents/heartbeat":                                           {},
+}
+
+// methodHeartbeats are the heartbeats sharing the route with the other
+// methods, the renewals of the locks keep the holders
+var methodHeartbeats = map[string]struct{}{
+	http.MethodPut + " /v4/:project/registry/locks/:name": {},
+}
+
+// readPosts are the routes of the POST method but read only, they keep
+// working if reads are allowed
+var readPosts = map[string]struct{}{
+	"/v4/:project/registry/instances": {},
+}
+
+// rpcHeartbeats and rpcReads are the grpc methods of the heartbeats and
+// the reads, the other grpc methods are mutating
+var (
+	rpcHeartbeats = map[string]struct{}{
+		"heartbeat":    {},
+		"heartbeatSet": {},
+	}
+	rpcReads = map[string]struct{}{
+		"exist":                   {},
+		"getOne":                  {},
+		"getServices":             {},
+		"getRule":                 {},
+		"getTags":                 {},
+		"getSchemaInfo":           {},
+		"getAllSchemaInfo":        {},
+		"getProviderDependencies": {},
+		"getConsumerDependencies": {},
+		"find":                    {},
+		"getInstances":            {},
+		"getInstancesStream":      {},
+		"getOneInstance":          {},
+		"getServiceDetail":        {},
+		"getServicesInfo":         {},
+		"getApplications":         {},
+	}
+)
+
+// Status is the maintenance mode shared by all the service centers
+type Status struct {
+	Enabled         bool   `json:"enabled"`
+	Reason          string `json:"reason,omitempty"`
+	AllowReads      bool   `json:"allowReads"`
+	AllowHeartbeats bool   `json:"allowHeartbeats"`
+	StartAt         string `json:"startAt,omitempty"`
+	// EndAt is the estimated end time, the clients retry after it
+	EndAt    string `json:"endAt,omitempty"`
+	Operator string `json:"operator,omitempty"`
+}
+
+type StartRequest struct {
+	Reason string `json:"reason,omitempty"`
+	// Duration is the estimated duration like '30m', optional
+	Duration string `json:"duration,omitempty"`
+	// AllowReads and AllowHeartbeats are the config defaults if omitted
+	AllowReads      *bool `json:"allowReads,omitempty"`
+	AllowHeartbeats *bool `json:"allowHeartbeats,omitempty"`
+}
+
+// '/cse-sr/maintenance'
+func getMaintenanceKey() string {
+	return util.StringJoin([]string{core.GetRootKey(), maintenanceKey}, core.SPLIT)
+}
+
+// Mode rejects the mutating APIs during maintenance, the status is stored
+// in the registry before the maintenance starts, and this instance keeps
+// the last status read if the registry is unavailable in maintenance
+type Mode struct {
+	Cfg Config
+
+	lock   sync.RWMutex
+	status Status
+}
+
+func (m *Mode) Status() Status {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.status
+}
+
+func (m *Mode) set(status Status) {
+	m.lock.Lock()
+	m.status = status
+	m.lock.Unlock()
+}
+
+// Allow returns whether the request of the method and route pattern
+// can be served in the current mode, the admin APIs are always allowed
+func (m *Mode) Allow(method, pattern string) bool {
+	status := m.Status()
+	if !status.Enabled || strings.HasPrefix(pattern, "/v4/:project/admin/") {
+		return true
+	}
+	if _, ok := heartbeats[pattern]; ok {
+		return status.AllowHeartbeats
+	}
+	if _, ok := methodHeartbeats[method+" "+pattern]; ok {
+		return status.AllowHeartbeats
+	}
+	if method == http.MethodGet {
+		return status.AllowReads
+	}
+	if _, ok := readPosts[pattern]; ok && method == http.MethodPost {
+		return status.AllowReads
+	}
+	return false
+}
+
+// AllowRPC returns whether the grpc method like 'find' can be served in
+// the current mode
+func (m *Mode) AllowRPC(method string) bool {
+	status := m.Status()
+	if !status.Enabled {
+		return true
+	}
+	if _, ok := rpcHeartbeats[method]; ok {
+		return status.AllowHeartbeats
+	}
+	if _, ok := rpcReads[method]; ok {
+		return status.AllowReads
+	}
+	return false
+}
+
+// Detail returns the reason and the estimated end time of the maintenance
+func (m *Mode) Detail() string {
+	status := m.Status()
+	detail := status.Reason
+	if len(status.EndAt) > 0 {
+		detail += ", estimated end at " + status.EndAt
+	}
+	return detail
+}
+
+// RetryAfter returns how long the clients should wait before retrying
+func (m *Mode) RetryAfter(now time.Time) time.Duration {
+	end, err := time.Parse(time.RFC3339, m.Status().EndAt)
+	if err != nil || !end.After(now) {
+		return DEFAULT_RETRY_AFTER
+	}
+	return end.Sub(now)
+}
+
+func (m *Mode) Start(ctx context.Context, in *StartRequest) (Status, error) {
+	now := time.Now().UTC()
+	status := Status{
+		Enabled:         true,
+		Reason:          in.Reason,
+		AllowReads:      m.Cfg.AllowReads,
+		AllowHeartbeats: m.Cfg.AllowHeartbeats,
+		StartAt:         now.Format(time.RFC3339),
+		Operator:        util.GetIPFromContext(ctx),
+	}
+	if len(in.Duration) > 0 {
+		d, err := time.ParseDuration(in.Duration)
+		if err != nil || d <= 0 {
+			return status, ErrInvalidDuration
+		}
+		status.EndAt = now.Add(d).Format(time.RFC3339)
+	}
+	if in.AllowReads != nil {
+		status.AllowReads = *in.AllowReads
+	}
+	if in.AllowHeartbeats != nil {
+		status.AllowHeartbeats = *in.AllowHeartbeats
+	}
+
+	data, err := json.Marshal(status)
+	if err != nil {
+		return status, err
+	}
+	_, err = backend.Registry().Do(ctx, registry.PUT,
+		registry.WithStrKey(getMaintenanceKey()), registry.WithValue(data))
+	if err != nil {
+		return status, err
+	}
+	m.set(status)
+	log.Warnf("audit: maintenance mode is started by %s, reason: %s, end at: %s, reads: %v, heartbeats: %v",
+		status.Operator, status.Reason, status.EndAt, status.AllowReads, status.AllowHeartbeats)
+	return status, nil
+}
+
+func (m *Mode) Stop(ctx context.Context) error {
+	_, err := backend.Registry().Do(ctx, registry.DEL, registry.WithStrKey(getMaintenanceKey()))
+	if err != nil {
+		return err
+	}
+	m.set(Status{})
+	log.Warnf("audit: maintenance mode is stopped by %s", util.GetIPFromContext(ctx))
+	return nil
+}
+
+// Sync reads the status from the registry
+func (m *Mode) Sync(ctx context.Context) error {
+	resp, err := backend.Registry().Do(ctx, registry.GET, registry.WithStrKey(getMaintenanceKey()))
+	if err != nil {
+		return err
+	}
+	status := Status{}
+	if len(resp.Kvs) > 0 {
+		if err := json.Unmarshal(resp.Kvs[0].Value, &status); err != nil {
+			return err
+		}
+	}
+	if status.Enabled != m.Status().Enabled {
+		log.Warnf("maintenance mode is changed to %v, reason: %s", status.Enabled, status.Reason)
+	}
+	m.set(status)
+	return nil
+}
+
+func (m *Mode) Run(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+		return
+	case <-backend.Registry().Ready():
+	}
+	for {
+		if err := m.Sync(ctx); err != nil {
+			log.Errorf(err, "sync the maintenance mode failed")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(m.Cfg.SyncInterval):
+		}
+	}
+}
+
+func NewMode(cfg Config) *Mode {
+	return &Mode{Cfg: cfg}
+}
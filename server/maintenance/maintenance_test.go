@@ -0,0 +1,94 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package maintenance
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestMode_Allow(t *testing.T) {
+	m := NewMode(Config{})
+	if !m.Allow(http.MethodPost, "/v4/:project/registry/microservices") {
+		t.Fatalf("TestMode_Allow failed, all allowed if not in maintenance")
+	}
+
+	m.set(Status{Enabled: true, AllowReads: true})
+	cases := []struct {
+		method  string
+		pattern string
+		allow   bool
+	}{
+		{http.MethodGet, "/v4/:project/registry/microservices", true},
+		{http.MethodPost, "/v4/:project/registry/microservices", false},
+		{http.MethodPost, "/v4/:project/registry/instances", true},
+		{http.MethodPost, "/v4/:project/registry/microservices/:serviceId/instances", false},
+		{http.MethodDelete, "/v4/:project/registry/microservices/:serviceId", false},
+		{http.MethodPut, "/v4/:project/registry/heartbeats", false},
+		{http.MethodPut, "/v4/:project/registry/locks/:name", false},
+		{http.MethodGet, "/v4/:project/registry/locks/:name", true},
+		{http.MethodDelete, "/v4/:project/admin/maintenance", true},
+	}
+	for i, c := range cases {
+		if m.Allow(c.method, c.pattern) != c.allow {
+			t.Fatalf("TestMode_Allow case %d failed", i)
+		}
+	}
+
+	m.set(Status{Enabled: true, AllowHeartbeats: true})
+	if !m.Allow(http.MethodPut, "/v4/:project/registry/heartbeats") ||
+		!m.Allow(http.MethodPut, "/v4/:project/registry/locks/:name") ||
+		m.Allow(http.MethodPost, "/v4/:project/registry/locks/:name") ||
+		m.Allow(http.MethodGet, "/v4/:project/registry/microservices") ||
+		m.Allow(http.MethodPost, "/v4/:project/registry/instances") {
+		t.Fatalf("TestMode_Allow failed")
+	}
+}
+
+func TestMode_AllowRPC(t *testing.T) {
+	m := NewMode(Config{})
+	if !m.AllowRPC("create") {
+		t.Fatalf("TestMode_AllowRPC failed, all allowed if not in maintenance")
+	}
+
+	m.set(Status{Enabled: true, AllowReads: true})
+	if !m.AllowRPC("find") || !m.AllowRPC("getServices") ||
+		m.AllowRPC("register") || m.AllowRPC("heartbeat") {
+		t.Fatalf("TestMode_AllowRPC failed")
+	}
+
+	m.set(Status{Enabled: true, AllowHeartbeats: true, Reason: "upgrade"})
+	if !m.AllowRPC("heartbeatSet") || m.AllowRPC("find") || m.AllowRPC("unregister") {
+		t.Fatalf("TestMode_AllowRPC failed")
+	}
+	if d := m.Detail(); d != "upgrade" {
+		t.Fatalf("TestMode_AllowRPC failed, %s", d)
+	}
+}
+
+func TestMode_RetryAfter(t *testing.T) {
+	now := time.Now()
+	m := NewMode(Config{})
+	if m.RetryAfter(now) != DEFAULT_RETRY_AFTER {
+		t.Fatalf("TestMode_RetryAfter failed")
+	}
+	m.set(Status{Enabled: true, EndAt: now.Add(time.Hour).UTC().Format(time.RFC3339)})
+	if d := m.RetryAfter(now); d <= 59*time.Minute || d > time.Hour {
+		t.Fatalf("TestMode_RetryAfter failed, %v", d)
+	}
+}
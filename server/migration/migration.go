@@ -0,0 +1,188 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package migration
+
+import (
+	"fmt"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/core/backend"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	"golang.org/x/net/context"
+	"strconv"
+	"sync"
+)
+
+const (
+	// BASE_VERSION is the data version written before the version key
+	// was introduced
+	BASE_VERSION = 1
+	// DATA_VERSION is the data version expected by this binary, bump it
+	// and register a step whenever the key or value format changes
+	DATA_VERSION = 1
+)
+
+// Step upgrades the data from Version-1 to Version
+type Step struct {
+	Version     int
+	Description string
+	Migrate     func(ctx context.Context) error
+}
+
+// Migrator compares the data version stored in the backend with the one
+// expected by the binary, and runs the steps in between
+type Migrator struct {
+	Key         string
+	Target      int
+	AutoMigrate bool
+
+	lock  sync.RWMutex
+	steps map[int]Step
+}
+
+func NewMigrator(key string, target int, autoMigrate bool) *Migrator {
+	return &Migrator{
+		Key:         key,
+		Target:      target,
+		AutoMigrate: autoMigrate,
+		steps:       make(map[int]Step),
+	}
+}
+
+func (m *Migrator) Register(step Step) error {
+	if step.Version <= BASE_VERSION || step.Version > m.Target || step.Migrate == nil {
+		return fmt.Errorf("invalid migration step %d, expect (%d, %d]", step.Version, BASE_VERSION, m.Target)
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if _, ok := m.steps[step.Version]; ok {
+		return fmt.Errorf("migration step %d is already registered", step.Version)
+	}
+	m.steps[step.Version] = step
+	return nil
+}
+
+// Plan returns the steps to upgrade the data from version 'from' to the
+// target, or an error if the data can not be served by this binary
+func (m *Migrator) Plan(from int) ([]Step, error) {
+	if from > m.Target {
+		return nil, fmt.Errorf("the data version %d is newer than %d expected by this service center, "+
+			"upgrade the binary instead of rolling back", from, m.Target)
+	}
+	if from == m.Target {
+		return nil, nil
+	}
+	if !m.AutoMigrate {
+		return nil, fmt.Errorf("the data version %d is older than %d expected by this service center, "+
+			"set data_auto_migrate = 1 to migrate it", from, m.Target)
+	}
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	steps := make([]Step, 0, m.Target-from)
+	for v := from + 1; v <= m.Target; v++ {
+		step, ok := m.steps[v]
+		if !ok {
+			return nil, fmt.Errorf("migration step %d is missing, can not migrate the data version %d to %d",
+				v, from, m.Target)
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// Version returns the data version in the backend, the fresh backend is
+// treated as the target version
+func (m *Migrator) Version(ctx context.Context) (int, error) {
+	v, _, err := m.version(ctx)
+	return v, err
+}
+
+func (m *Migrator) version(ctx context.Context) (v int, stored bool, err error) {
+	resp, err := backend.Registry().Do(ctx, registry.GET, registry.WithStrKey(m.Key))
+	if err != nil {
+		return
+	}
+	if len(resp.Kvs) > 0 {
+		v, err = strconv.Atoi(string(resp.Kvs[0].Value))
+		if err != nil {
+			err = fmt.Errorf("invalid data version '%s'", resp.Kvs[0].Value)
+		}
+		stored = true
+		return
+	}
+
+	resp, err = backend.Registry().Do(ctx, registry.GET,
+		registry.WithStrKey(core.GetServiceRootKey("")), registry.WithPrefix(), registry.WithCountOnly())
+	if err != nil {
+		return
+	}
+	if resp.Count == 0 {
+		v = m.Target
+		return
+	}
+	v = BASE_VERSION
+	return
+}
+
+func (m *Migrator) setVersion(ctx context.Context, v int) error {
+	_, err := backend.Registry().Do(ctx, registry.PUT,
+		registry.WithStrKey(m.Key), registry.WithStrValue(strconv.Itoa(v)))
+	return err
+}
+
+// Run migrates the data to the target version step by step, the version
+// is saved after each step, so an interrupted migration resumes from the
+// failed step. The caller should hold the global lock
+func (m *Migrator) Run(ctx context.Context) error {
+	from, stored, err := m.version(ctx)
+	if err != nil {
+		return err
+	}
+	steps, err := m.Plan(from)
+	if err != nil {
+		return err
+	}
+	for _, step := range steps {
+		log.Warnf("migrate the data version %d to %d: %s", step.Version-1, step.Version, step.Description)
+		if err := step.Migrate(ctx); err != nil {
+			return fmt.Errorf("migrate the data version %d to %d failed, %s", step.Version-1, step.Version, err)
+		}
+		if err := m.setVersion(ctx, step.Version); err != nil {
+			return err
+		}
+	}
+	if len(steps) > 0 {
+		log.Warnf("the data version is migrated from %d to %d", from, m.Target)
+		return nil
+	}
+	if stored {
+		return nil
+	}
+	// the fresh or the pre-versioning backend
+	log.Infof("initialize the data version %d", m.Target)
+	return m.setVersion(ctx, m.Target)
+}
+
+// Register registers the step of the data format change, it must be
+// called in init()
+func Register(step Step) error {
+	return migrator.Register(step)
+}
+
+func Run(ctx context.Context) error {
+	return migrator.Run(ctx)
+}
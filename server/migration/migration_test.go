@@ -0,0 +1,85 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package migration
+
+import (
+	"github.com/apache/servicecomb-service-center/server/core/backend"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	"golang.org/x/net/context"
+	"testing"
+)
+
+func TestMigrator_Plan(t *testing.T) {
+	m := NewMigrator("/cse-sr/migration_test", 3, true)
+	if err := m.Register(Step{Version: 1, Migrate: func(context.Context) error { return nil }}); err == nil {
+		t.Fatalf("TestMigrator_Plan failed")
+	}
+	if err := m.Register(Step{Version: 2, Migrate: func(context.Context) error { return nil }}); err != nil {
+		t.Fatalf("TestMigrator_Plan failed, %v", err)
+	}
+	if err := m.Register(Step{Version: 2, Migrate: func(context.Context) error { return nil }}); err == nil {
+		t.Fatalf("TestMigrator_Plan failed")
+	}
+
+	if _, err := m.Plan(4); err == nil {
+		t.Fatalf("TestMigrator_Plan failed")
+	}
+	if steps, err := m.Plan(3); err != nil || len(steps) != 0 {
+		t.Fatalf("TestMigrator_Plan failed, %v", err)
+	}
+	if _, err := m.Plan(1); err == nil {
+		t.Fatalf("TestMigrator_Plan failed")
+	}
+	if err := m.Register(Step{Version: 3, Migrate: func(context.Context) error { return nil }}); err != nil {
+		t.Fatalf("TestMigrator_Plan failed, %v", err)
+	}
+	if steps, err := m.Plan(1); err != nil || len(steps) != 2 || steps[0].Version != 2 {
+		t.Fatalf("TestMigrator_Plan failed, %v", err)
+	}
+
+	m.AutoMigrate = false
+	if _, err := m.Plan(1); err == nil {
+		t.Fatalf("TestMigrator_Plan failed")
+	}
+}
+
+func TestMigrator_Run(t *testing.T) {
+	ctx := context.Background()
+	m := NewMigrator("/cse-sr/migration_test", 2, true)
+	migrated := 0
+	m.Register(Step{Version: 2, Migrate: func(context.Context) error {
+		migrated++
+		return nil
+	}})
+
+	m.setVersion(ctx, 1)
+	if err := m.Run(ctx); err != nil || migrated != 1 {
+		t.Fatalf("TestMigrator_Run failed, %v", err)
+	}
+	if v, err := m.Version(ctx); err != nil || v != 2 {
+		t.Fatalf("TestMigrator_Run failed, %d, %v", v, err)
+	}
+	if err := m.Run(ctx); err != nil || migrated != 1 {
+		t.Fatalf("TestMigrator_Run failed, %v", err)
+	}
+
+	m.setVersion(ctx, 3)
+	if err := m.Run(ctx); err == nil {
+		t.Fatalf("TestMigrator_Run failed")
+	}
+	backend.Registry().Do(ctx, registry.DEL, registry.WithStrKey(m.Key))
+}
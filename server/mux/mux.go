@@ -33,8 +33,14 @@ func (m *MuxType) String() (s string) {
 }
 
 const (
-	GLOBAL_LOCK    MuxType = "/cse-sr/lock/global"
-	DEP_QUEUE_LOCK MuxType = "/cse-sr/lock/dep-queue"
+	GLOBAL_LOCK     MuxType = "/cse-sr/lock/global"
+	DEP_QUEUE_LOCK  MuxType = "/cse-sr/lock/dep-queue"
+	NACOS_SYNC_LOCK MuxType = "/cse-sr/lock/nacos-sync"
+	ZK_SYNC_LOCK    MuxType = "/cse-sr/lock/zk-sync"
+	GC_LOCK         MuxType = "/cse-sr/lock/gc"
+	CAPACITY_LOCK   MuxType = "/cse-sr/lock/capacity"
+	RETENTION_LOCK  MuxType = "/cse-sr/lock/retention"
+	DOMAIN_LOCK     MuxType = "/cse-sr/lock/domain"
 )
 
 func Lock(t MuxType) (*etcdsync.DLock, error) {
@@ -0,0 +1,89 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package nacos
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/client/nacos"
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	"github.com/apache/servicecomb-service-center/server/core"
+	"github.com/astaxie/beego"
+	"strings"
+	"time"
+)
+
+const (
+	DIRECTION_BOTH       = "both"
+	DIRECTION_TO_NACOS   = "to_nacos"
+	DIRECTION_FROM_NACOS = "from_nacos"
+
+	DEFAULT_SYNC_INTERVAL = 30 * time.Second
+)
+
+func init() {
+	cfg := LoadConfig()
+	if !cfg.Enabled {
+		return
+	}
+	gopool.Go(NewSyncer(cfg).Run)
+}
+
+type Config struct {
+	nacos.Config
+	Enabled bool
+	// DomainProject is the domain project of service center to sync
+	DomainProject string
+	// App is the app id of the services synced from nacos
+	App       string
+	Direction string
+	Interval  time.Duration
+}
+
+func (cfg Config) toNacos() bool {
+	return cfg.Direction != DIRECTION_FROM_NACOS
+}
+
+func (cfg Config) fromNacos() bool {
+	return cfg.Direction != DIRECTION_TO_NACOS
+}
+
+func LoadConfig() Config {
+	cfg := Config{
+		Config: nacos.Config{
+			Addr:      beego.AppConfig.DefaultString("nacos_addr", "http://127.0.0.1:8848"),
+			Namespace: beego.AppConfig.DefaultString("nacos_namespace", ""),
+			Group:     beego.AppConfig.DefaultString("nacos_group", nacos.DEFAULT_GROUP),
+			User:      beego.AppConfig.DefaultString("nacos_user", ""),
+			Password:  beego.AppConfig.DefaultString("nacos_password", ""),
+		},
+		Enabled:       beego.AppConfig.DefaultInt("nacos_sync", 0) != 0,
+		DomainProject: beego.AppConfig.DefaultString("nacos_domain_project", core.REGISTRY_DOMAIN_PROJECT),
+		App:           beego.AppConfig.DefaultString("nacos_app", "default"),
+		Direction: strings.ToLower(
+			beego.AppConfig.DefaultString("nacos_sync_direction", DIRECTION_BOTH)),
+	}
+	switch cfg.Direction {
+	case DIRECTION_TO_NACOS, DIRECTION_FROM_NACOS:
+	default:
+		cfg.Direction = DIRECTION_BOTH
+	}
+	interval, err := time.ParseDuration(beego.AppConfig.DefaultString("nacos_sync_interval", ""))
+	if err != nil || interval <= 0 {
+		interval = DEFAULT_SYNC_INTERVAL
+	}
+	cfg.Interval = interval
+	return cfg
+}
@@ -0,0 +1,72 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package nacos
+
+import (
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"testing"
+)
+
+func TestToNacosInstance(t *testing.T) {
+	service := &pb.MicroService{ServiceId: "s1", AppId: "a", ServiceName: "svc", Version: "1.0.0"}
+	instance := &pb.MicroServiceInstance{
+		InstanceId: "i1",
+		Endpoints:  []string{"rest://127.0.0.1:8080?sslEnabled=false"},
+		Status:     pb.MSI_UP,
+		Properties: map[string]string{"k": "v", PROP_ORIGIN: "x"},
+	}
+	n := toNacosInstance(service, instance)
+	if n == nil || n.Addr() != "127.0.0.1:8080" || !n.Healthy || !n.Enabled {
+		t.Fatalf("TestToNacosInstance failed, %v", n)
+	}
+	if n.Metadata[PROP_ORIGIN] != ORIGIN_SC || n.Metadata["k"] != "v" ||
+		n.Metadata[META_VERSION] != "1.0.0" || n.Metadata[META_INSTANCE_ID] != "i1" {
+		t.Fatalf("TestToNacosInstance failed, %v", n.Metadata)
+	}
+
+	instance.Status = pb.MSI_DOWN
+	if n = toNacosInstance(service, instance); n.Healthy {
+		t.Fatalf("TestToNacosInstance failed, %v", n)
+	}
+
+	instance.Endpoints = []string{"rest://127.0.0.1"}
+	if n = toNacosInstance(service, instance); n != nil {
+		t.Fatalf("TestToNacosInstance failed, %v", n)
+	}
+	instance.Endpoints = nil
+	if n = toNacosInstance(service, instance); n != nil {
+		t.Fatalf("TestToNacosInstance failed, %v", n)
+	}
+}
+
+func TestConfig(t *testing.T) {
+	cfg := LoadConfig()
+	if cfg.Enabled || cfg.Direction != DIRECTION_BOTH || cfg.Interval != DEFAULT_SYNC_INTERVAL {
+		t.Fatalf("TestConfig failed, %v", cfg)
+	}
+	if !cfg.toNacos() || !cfg.fromNacos() {
+		t.Fatalf("TestConfig failed")
+	}
+	cfg.Direction = DIRECTION_TO_NACOS
+	if !cfg.toNacos() || cfg.fromNacos() {
+		t.Fatalf("TestConfig failed")
+	}
+	cfg.Direction = DIRECTION_FROM_NACOS
+	if cfg.toNacos() || !cfg.fromNacos() {
+		t.Fatalf("TestConfig failed")
+	}
+}
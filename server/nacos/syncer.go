@@ -0,0 +1,371 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package nacos
+
+import (
+	"fmt"
+	"github.com/apache/servicecomb-service-center/pkg/client/nacos"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/core/backend"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"github.com/apache/servicecomb-service-center/server/mux"
+	serviceUtil "github.com/apache/servicecomb-service-center/server/service/util"
+	"golang.org/x/net/context"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// PROP_ORIGIN tags the mirrored instances with the registry they come
+	// from, the mirrored instances are never synced back
+	PROP_ORIGIN  = "origin"
+	ORIGIN_SC    = "servicecenter"
+	ORIGIN_NACOS = "nacos"
+	// PROP_NACOS_KEY is the '{service}/{ip}:{port}' of the nacos instance
+	// mirrored to service center
+	PROP_NACOS_KEY = "nacos.key"
+
+	META_APP         = "app"
+	META_VERSION     = "version"
+	META_SERVICE_ID  = "sc.serviceId"
+	META_INSTANCE_ID = "sc.instanceId"
+)
+
+type localInstance struct {
+	Service  *pb.MicroService
+	Instance *pb.MicroServiceInstance
+}
+
+// Syncer mirrors the instances between service center and nacos in
+// both directions by comparing the full state periodically, only one
+// service center instance syncs at a time
+type Syncer struct {
+	Cfg    Config
+	client *nacos.Client
+}
+
+func (s *Syncer) Run(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+		return
+	case <-backend.Store().Ready():
+	}
+	log.Infof("start syncing with nacos[%s], direction: %s, interval: %s",
+		s.Cfg.Addr, s.Cfg.Direction, s.Cfg.Interval)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.Cfg.Interval):
+			s.trySync(ctx)
+		}
+	}
+}
+
+func (s *Syncer) trySync(ctx context.Context) {
+	lock, err := mux.Try(mux.NACOS_SYNC_LOCK)
+	if lock == nil {
+		log.Debugf("can not sync with nacos by this service center instance now, %v", err)
+		return
+	}
+	defer lock.Unlock()
+
+	if err := s.Sync(ctx); err != nil {
+		log.Errorf(err, "sync with nacos[%s] failed", s.Cfg.Addr)
+	}
+}
+
+// Sync runs one round of the synchronization
+func (s *Syncer) Sync(ctx context.Context) error {
+	domain, project := core.FromDomainProject(s.Cfg.DomainProject)
+	ctx = util.SetDomainProject(ctx, domain, project)
+
+	local, err := s.listLocal(ctx)
+	if err != nil {
+		return err
+	}
+	remote, err := s.listRemote(ctx)
+	if err != nil {
+		return err
+	}
+	if s.Cfg.toNacos() {
+		s.syncToNacos(ctx, local, remote)
+	}
+	if s.Cfg.fromNacos() {
+		s.syncFromNacos(ctx, local, remote)
+	}
+	return nil
+}
+
+func (s *Syncer) listLocal(ctx context.Context) ([]*localInstance, error) {
+	cacheCtx := util.SetContext(ctx, serviceUtil.CTX_CACHEONLY, "1")
+	services, err := serviceUtil.GetServicesByDomainProject(cacheCtx, s.Cfg.DomainProject)
+	if err != nil {
+		return nil, err
+	}
+	var local []*localInstance
+	for _, service := range services {
+		instances, err := serviceUtil.GetAllInstancesOfOneService(cacheCtx, s.Cfg.DomainProject, service.ServiceId)
+		if err != nil {
+			return nil, err
+		}
+		for _, instance := range instances {
+			local = append(local, &localInstance{Service: service, Instance: instance})
+		}
+	}
+	return local, nil
+}
+
+func (s *Syncer) listRemote(ctx context.Context) (map[string][]*nacos.Instance, error) {
+	services, err := s.client.ListServices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	remote := make(map[string][]*nacos.Instance, len(services))
+	for _, service := range services {
+		instances, err := s.client.ListInstances(ctx, service)
+		if err != nil {
+			return nil, err
+		}
+		remote[service] = instances
+	}
+	return remote, nil
+}
+
+func (s *Syncer) syncToNacos(ctx context.Context, local []*localInstance, remote map[string][]*nacos.Instance) {
+	desired := make(map[string]map[string]*nacos.Instance)
+	for _, l := range local {
+		if l.Instance.Properties[PROP_ORIGIN] == ORIGIN_NACOS {
+			continue
+		}
+		instance := toNacosInstance(l.Service, l.Instance)
+		if instance == nil {
+			continue
+		}
+		name := l.Service.ServiceName
+		if _, ok := desired[name]; !ok {
+			desired[name] = make(map[string]*nacos.Instance)
+		}
+		desired[name][instance.Addr()] = instance
+	}
+
+	for name, instances := range desired {
+		existing := make(map[string]*nacos.Instance, len(remote[name]))
+		for _, instance := range remote[name] {
+			existing[instance.Addr()] = instance
+		}
+		for addr, instance := range instances {
+			if old, ok := existing[addr]; ok {
+				if old.Metadata[PROP_ORIGIN] != ORIGIN_SC {
+					// do not override the instance registered to nacos directly
+					continue
+				}
+				if old.Healthy == instance.Healthy &&
+					old.Metadata[META_INSTANCE_ID] == instance.Metadata[META_INSTANCE_ID] {
+					continue
+				}
+			}
+			if err := s.client.RegisterInstance(ctx, name, instance); err != nil {
+				log.Errorf(err, "mirror instance[%s] to nacos service[%s] failed",
+					instance.Metadata[META_INSTANCE_ID], name)
+				continue
+			}
+			log.Infof("mirror instance[%s] to nacos service[%s] %s",
+				instance.Metadata[META_INSTANCE_ID], name, addr)
+		}
+	}
+
+	for name, instances := range remote {
+		for _, instance := range instances {
+			if instance.Metadata[PROP_ORIGIN] != ORIGIN_SC {
+				continue
+			}
+			if _, ok := desired[name][instance.Addr()]; ok {
+				continue
+			}
+			if err := s.client.DeregisterInstance(ctx, name, instance); err != nil {
+				log.Errorf(err, "remove the mirror instance %s from nacos service[%s] failed",
+					instance.Addr(), name)
+				continue
+			}
+			log.Infof("remove the mirror instance %s from nacos service[%s]", instance.Addr(), name)
+		}
+	}
+}
+
+func (s *Syncer) syncFromNacos(ctx context.Context, local []*localInstance, remote map[string][]*nacos.Instance) {
+	mirrored := make(map[string]*localInstance)
+	for _, l := range local {
+		if l.Instance.Properties[PROP_ORIGIN] == ORIGIN_NACOS {
+			mirrored[l.Instance.Properties[PROP_NACOS_KEY]] = l
+		}
+	}
+
+	desired := make(map[string]struct{})
+	for name, instances := range remote {
+		for _, instance := range instances {
+			if instance.Metadata[PROP_ORIGIN] == ORIGIN_SC || !instance.Enabled || !instance.Healthy {
+				continue
+			}
+			key := name + "/" + instance.Addr()
+			desired[key] = struct{}{}
+			if l, ok := mirrored[key]; ok {
+				s.heartbeat(ctx, l)
+				continue
+			}
+			if err := s.register(ctx, name, key, instance); err != nil {
+				log.Errorf(err, "mirror nacos instance[%s] to service center failed", key)
+				continue
+			}
+			log.Infof("mirror nacos instance[%s] to service center", key)
+		}
+	}
+
+	for key, l := range mirrored {
+		if _, ok := desired[key]; ok {
+			continue
+		}
+		resp, err := core.InstanceAPI.Unregister(ctx, &pb.UnregisterInstanceRequest{
+			ServiceId:  l.Instance.ServiceId,
+			InstanceId: l.Instance.InstanceId,
+		})
+		if err != nil || resp.Response.Code != pb.Response_SUCCESS {
+			log.Errorf(err, "remove the mirror instance[%s] of nacos instance[%s] failed",
+				l.Instance.InstanceId, key)
+			continue
+		}
+		log.Infof("remove the mirror instance[%s] of nacos instance[%s]", l.Instance.InstanceId, key)
+	}
+}
+
+func (s *Syncer) heartbeat(ctx context.Context, l *localInstance) {
+	resp, err := core.InstanceAPI.Heartbeat(ctx, &pb.HeartbeatRequest{
+		ServiceId:  l.Instance.ServiceId,
+		InstanceId: l.Instance.InstanceId,
+	})
+	if err != nil || resp.Response.Code != pb.Response_SUCCESS {
+		log.Errorf(err, "update the mirror instance[%s/%s] heartbeat failed",
+			l.Instance.ServiceId, l.Instance.InstanceId)
+	}
+}
+
+func (s *Syncer) ensureService(ctx context.Context, name, version string) (string, error) {
+	serviceId, err := serviceUtil.GetServiceId(ctx, &pb.MicroServiceKey{
+		Tenant:      s.Cfg.DomainProject,
+		AppId:       s.Cfg.App,
+		ServiceName: name,
+		Version:     version,
+	})
+	if err != nil || len(serviceId) > 0 {
+		return serviceId, err
+	}
+	resp, err := core.ServiceAPI.Create(ctx, &pb.CreateServiceRequest{
+		Service: &pb.MicroService{
+			AppId:       s.Cfg.App,
+			ServiceName: name,
+			Version:     version,
+			Properties:  map[string]string{PROP_ORIGIN: ORIGIN_NACOS},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	if resp.Response.Code != pb.Response_SUCCESS {
+		return "", fmt.Errorf(resp.Response.Message)
+	}
+	return resp.ServiceId, nil
+}
+
+func (s *Syncer) register(ctx context.Context, name, key string, instance *nacos.Instance) error {
+	version := instance.Metadata[META_VERSION]
+	if len(version) == 0 {
+		version = pb.VERSION
+	}
+	serviceId, err := s.ensureService(ctx, name, version)
+	if err != nil {
+		return err
+	}
+	properties := make(map[string]string, len(instance.Metadata)+2)
+	for k, v := range instance.Metadata {
+		properties[k] = v
+	}
+	properties[PROP_ORIGIN] = ORIGIN_NACOS
+	properties[PROP_NACOS_KEY] = key
+	resp, err := core.InstanceAPI.Register(ctx, &pb.RegisterInstanceRequest{
+		Instance: &pb.MicroServiceInstance{
+			ServiceId:  serviceId,
+			Endpoints:  []string{"rest://" + instance.Addr()},
+			HostName:   instance.Ip,
+			Status:     pb.MSI_UP,
+			Properties: properties,
+			HealthCheck: &pb.HealthCheck{
+				Mode:     pb.CHECK_BY_HEARTBEAT,
+				Interval: int32(s.Cfg.Interval.Seconds()),
+				Times:    3,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if resp.Response.Code != pb.Response_SUCCESS {
+		return fmt.Errorf(resp.Response.Message)
+	}
+	return nil
+}
+
+// toNacosInstance returns nil if the instance has no valid endpoint
+func toNacosInstance(service *pb.MicroService, instance *pb.MicroServiceInstance) *nacos.Instance {
+	if len(instance.Endpoints) == 0 {
+		return nil
+	}
+	u, err := url.Parse(instance.Endpoints[0])
+	if err != nil {
+		return nil
+	}
+	ipPort := util.ParseIpPort(u.Host)
+	if ipPort.Port == 0 {
+		return nil
+	}
+	metadata := make(map[string]string, len(instance.Properties)+5)
+	for k, v := range instance.Properties {
+		metadata[k] = v
+	}
+	metadata[PROP_ORIGIN] = ORIGIN_SC
+	metadata[META_APP] = service.AppId
+	metadata[META_VERSION] = service.Version
+	metadata[META_SERVICE_ID] = service.ServiceId
+	metadata[META_INSTANCE_ID] = instance.InstanceId
+	return &nacos.Instance{
+		Ip:       strings.Trim(ipPort.IP, "[]"),
+		Port:     int(ipPort.Port),
+		Weight:   1,
+		Healthy:  instance.Status == pb.MSI_UP,
+		Enabled:  true,
+		Metadata: metadata,
+	}
+}
+
+func NewSyncer(cfg Config) *Syncer {
+	return &Syncer{
+		Cfg:    cfg,
+		client: nacos.NewClient(cfg.Config),
+	}
+}
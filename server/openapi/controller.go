@@ -0,0 +1,63 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package openapi
+
+import (
+	"encoding/json"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/rest/controller"
+	"github.com/apache/servicecomb-service-center/version"
+	"net/http"
+	"sync"
+)
+
+const OPENAPI_PATH = "/openapi.json"
+
+func init() {
+	rest.RegisterServant(&OpenAPIController{})
+}
+
+// OpenAPIController serves the OpenAPI document of all the routes, the
+// document is generated once at the first request, all the servants are
+// registered before serving
+type OpenAPIController struct {
+	once sync.Once
+	data []byte
+	err  error
+}
+
+func (ctrl *OpenAPIController) URLPatterns() []rest.Route {
+	return []rest.Route{
+		{rest.HTTP_METHOD_GET, OPENAPI_PATH, ctrl.Get},
+	}
+}
+
+func (ctrl *OpenAPIController) Get(w http.ResponseWriter, r *http.Request) {
+	ctrl.once.Do(func() {
+		ctrl.data, ctrl.err = json.Marshal(Generate(rest.Routes(), version.Ver().Version))
+		if ctrl.err != nil {
+			log.Errorf(ctrl.err, "generate the openapi document failed")
+		}
+	})
+	if ctrl.err != nil {
+		controller.WriteError(w, scerr.ErrInternal, ctrl.err.Error())
+		return
+	}
+	controller.WriteJsonBytes(w, nil, ctrl.data)
+}
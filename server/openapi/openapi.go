@@ -0,0 +1,250 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package openapi
+
+import (
+	"fmt"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"strings"
+)
+
+const (
+	OPENAPI_VERSION = "3.0.0"
+	CONTENT_JSON    = "application/json"
+
+	errorRef      = "#/components/schemas/Error"
+	errorRespRef  = "#/components/responses/Error"
+	domainNameRef = "#/components/parameters/DomainName"
+	tokenScheme   = "token"
+)
+
+// Document is the subset of the OpenAPI 3.0 document the routes can be
+// described with
+type Document struct {
+	OpenAPI    string                `json:"openapi"`
+	Info       Info                  `json:"info"`
+	Paths      map[string]PathItem   `json:"paths"`
+	Components Components            `json:"components"`
+	Security   []map[string][]string `json:"security,omitempty"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// PathItem is the operations of a path by the lower case method
+type PathItem map[string]*Operation
+
+type Operation struct {
+	Tags        []string             `json:"tags,omitempty"`
+	OperationId string               `json:"operationId"`
+	Parameters  []*Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+type Parameter struct {
+	Ref         string  `json:"$ref,omitempty"`
+	Name        string  `json:"name,omitempty"`
+	In          string  `json:"in,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema,omitempty"`
+}
+
+type RequestBody struct {
+	Required bool                  `json:"required,omitempty"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+type Response struct {
+	Ref         string                `json:"$ref,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Schema struct {
+	Ref        string             `json:"$ref,omitempty"`
+	Type       string             `json:"type,omitempty"`
+	Format     string             `json:"format,omitempty"`
+	Default    interface{}        `json:"default,omitempty"`
+	Properties map[string]*Schema `json:"properties,omitempty"`
+	Required   []string           `json:"required,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type        string `json:"type"`
+	In          string `json:"in"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	Responses       map[string]*Response       `json:"responses"`
+	Parameters      map[string]*Parameter      `json:"parameters"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes"`
+}
+
+// Generate describes the routes, so the document is always the same as
+// the routes served
+func Generate(routes []rest.URLPattern, version string) *Document {
+	doc := &Document{
+		OpenAPI: OPENAPI_VERSION,
+		Info: Info{
+			Title:       "Service Center API",
+			Description: "The document is generated from the routes of the service center.",
+			Version:     version,
+		},
+		Paths:      make(map[string]PathItem),
+		Components: newComponents(),
+		Security:   []map[string][]string{{tokenScheme: {}}},
+	}
+	ids := make(map[string]int)
+	for _, route := range routes {
+		path, params := ToPath(route.Path)
+		item, ok := doc.Paths[path]
+		if !ok {
+			item = make(PathItem)
+			doc.Paths[path] = item
+		}
+		item[strings.ToLower(route.Method)] = newOperation(route, params, ids)
+	}
+	return doc
+}
+
+func newOperation(route rest.URLPattern, params []string, ids map[string]int) *Operation {
+	pkg, typ, fun := SplitFuncName(route.Func)
+	op := &Operation{
+		OperationId: pkg + fun,
+		Responses: map[string]*Response{
+			"200": {
+				Description: "Successful",
+				Content:     map[string]*MediaType{CONTENT_JSON: {Schema: &Schema{Type: "object"}}},
+			},
+			"400": {Ref: errorRespRef},
+			"401": {Ref: errorRespRef},
+			"403": {Ref: errorRespRef},
+			"500": {Ref: errorRespRef},
+		},
+	}
+	// the handlers of the different routes may have the same name
+	if n := ids[op.OperationId]; n > 0 {
+		ids[op.OperationId] = n + 1
+		op.OperationId = fmt.Sprintf("%s%d", op.OperationId, n+1)
+	} else {
+		ids[op.OperationId] = 1
+	}
+	if len(typ) > 0 {
+		op.Tags = []string{pkg + "." + typ}
+	}
+	for _, name := range params {
+		op.Parameters = append(op.Parameters, &Parameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "string"},
+		})
+	}
+	if strings.HasPrefix(route.Path, "/v4/") || strings.HasPrefix(route.Path, "/registry/v3/") {
+		op.Parameters = append(op.Parameters, &Parameter{Ref: domainNameRef})
+	}
+	switch route.Method {
+	case rest.HTTP_METHOD_POST, rest.HTTP_METHOD_PUT:
+		op.RequestBody = &RequestBody{
+			Content: map[string]*MediaType{CONTENT_JSON: {Schema: &Schema{Type: "object"}}},
+		}
+	}
+	return op
+}
+
+func newComponents() Components {
+	return Components{
+		Schemas: map[string]*Schema{
+			"Error": {
+				Type: "object",
+				Properties: map[string]*Schema{
+					"errorCode":    {Type: "string"},
+					"errorMessage": {Type: "string"},
+					"detail":       {Type: "string"},
+				},
+				Required: []string{"errorCode", "errorMessage"},
+			},
+		},
+		Responses: map[string]*Response{
+			"Error": {
+				Description: "The request failed, see the errorCode for the reason",
+				Content:     map[string]*MediaType{CONTENT_JSON: {Schema: &Schema{Ref: errorRef}}},
+			},
+		},
+		Parameters: map[string]*Parameter{
+			"DomainName": {
+				Name:        "X-Domain-Name",
+				In:          "header",
+				Description: "the domain of the request",
+				Schema:      &Schema{Type: "string", Default: "default"},
+			},
+		},
+		SecuritySchemes: map[string]*SecurityScheme{
+			tokenScheme: {
+				Type:        "apiKey",
+				In:          "header",
+				Name:        "X-Auth-Token",
+				Description: "the token is required if the auth plugin is enabled",
+			},
+		},
+	}
+}
+
+// ToPath converts the route pattern to the OpenAPI path, ':name' is
+// converted to '{name}'
+func ToPath(pattern string) (path string, params []string) {
+	segments := strings.Split(pattern, "/")
+	for i, s := range segments {
+		if strings.HasPrefix(s, ":") && len(s) > 1 {
+			params = append(params, s[1:])
+			segments[i] = "{" + s[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// SplitFuncName splits the full name of the callback function, e.g.
+// 'github.com/x/v4.(*MicroServiceService).Register-fm' to 'v4',
+// 'MicroServiceService' and 'Register'
+func SplitFuncName(name string) (pkg, typ, fun string) {
+	name = strings.TrimSuffix(name, "-fm")
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	arr := strings.Split(name, ".")
+	switch len(arr) {
+	case 0, 1:
+		return "", "", name
+	case 2:
+		return arr[0], "", arr[1]
+	default:
+		typ = strings.TrimSuffix(strings.TrimPrefix(arr[1], "(*"), ")")
+		return arr[0], typ, arr[len(arr)-1]
+	}
+}
@@ -0,0 +1,73 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package openapi
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"testing"
+)
+
+func TestSplitFuncName(t *testing.T) {
+	pkg, typ, fun := SplitFuncName("github.com/apache/servicecomb-service-center/server/rest/controller/v4.(*MicroServiceService).Register-fm")
+	if pkg != "v4" || typ != "MicroServiceService" || fun != "Register" {
+		t.Fatalf("TestSplitFuncName failed, %s %s %s", pkg, typ, fun)
+	}
+	pkg, typ, fun = SplitFuncName("github.com/a/b.Handle")
+	if pkg != "b" || typ != "" || fun != "Handle" {
+		t.Fatalf("TestSplitFuncName failed, %s %s %s", pkg, typ, fun)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	path, params := ToPath("/v4/:project/registry/microservices/:serviceId/instances")
+	if path != "/v4/{project}/registry/microservices/{serviceId}/instances" || len(params) != 2 || params[1] != "serviceId" {
+		t.Fatalf("TestGenerate failed, %s %v", path, params)
+	}
+
+	doc := Generate([]rest.URLPattern{
+		{Method: rest.HTTP_METHOD_GET, Path: "/v4/:project/registry/microservices/:serviceId", Func: "x/v4.(*MicroServiceService).GetServiceOne-fm"},
+		{Method: rest.HTTP_METHOD_DELETE, Path: "/v4/:project/registry/microservices/:serviceId", Func: "x/v4.(*MicroServiceService).Unregister-fm"},
+		{Method: rest.HTTP_METHOD_POST, Path: "/v4/:project/registry/microservices", Func: "x/v4.(*MicroServiceService).Register-fm"},
+		{Method: rest.HTTP_METHOD_POST, Path: "/v4/:project/registry/microservices/:serviceId/instances", Func: "x/v4.(*MicroServiceInstanceService).Register-fm"},
+		{Method: rest.HTTP_METHOD_GET, Path: "/version", Func: "x/v3.(*MainService).GetVersion-fm"},
+	}, "1.0.0")
+	if doc.OpenAPI != OPENAPI_VERSION || doc.Info.Version != "1.0.0" || len(doc.Paths) != 4 {
+		t.Fatalf("TestGenerate failed, %v", doc)
+	}
+	item := doc.Paths["/v4/{project}/registry/microservices/{serviceId}"]
+	if len(item) != 2 || item["get"] == nil || item["delete"] == nil {
+		t.Fatalf("TestGenerate failed, %v", item)
+	}
+	op := item["get"]
+	if op.OperationId != "v4GetServiceOne" || op.Tags[0] != "v4.MicroServiceService" || op.RequestBody != nil ||
+		len(op.Parameters) != 3 || op.Parameters[2].Ref != domainNameRef || op.Responses["500"].Ref != errorRespRef {
+		t.Fatalf("TestGenerate failed, %v", op)
+	}
+	op = doc.Paths["/v4/{project}/registry/microservices"]["post"]
+	if op.OperationId != "v4Register" || op.RequestBody == nil {
+		t.Fatalf("TestGenerate failed, %v", op)
+	}
+	// the operation id is unique
+	op = doc.Paths["/v4/{project}/registry/microservices/{serviceId}/instances"]["post"]
+	if op.OperationId != "v4Register2" {
+		t.Fatalf("TestGenerate failed, %v", op)
+	}
+	op = doc.Paths["/version"]["get"]
+	if len(op.Parameters) != 0 {
+		t.Fatalf("TestGenerate failed, %v", op)
+	}
+}
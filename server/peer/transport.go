@@ -0,0 +1,153 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package peer
+
+import (
+	"crypto/tls"
+	"github.com/apache/servicecomb-service-center/pkg/client/sc"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/server/core"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	mgr "github.com/apache/servicecomb-service-center/server/plugin"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	nf "github.com/apache/servicecomb-service-center/server/service/notification"
+	serviceUtil "github.com/apache/servicecomb-service-center/server/service/util"
+	"golang.org/x/net/context"
+	"net/url"
+	"sync"
+)
+
+var transport = NewTransport()
+
+func init() {
+	nf.GetPeerBus().SetTransport(transport)
+}
+
+// GetTransport returns the transport of the peer bus, its clients are
+// shared with the other callers of the peers
+func GetTransport() *Transport {
+	return transport
+}
+
+// Transport forwards the events to the other instances of service center
+// registered in the default domain project
+type Transport struct {
+	// clients caches the client of each peer by the endpoint
+	clients   map[string]*sc.SCClient
+	clientTLS *tls.Config
+	lock      sync.Mutex
+}
+
+func (t *Transport) Broadcast(ctx context.Context, events []*pb.PeerEvent) (err error) {
+	for _, client := range t.peers(ctx) {
+		if e := client.PostPeerEvents(ctx, events); e != nil {
+			log.Errorf(e, "forward %d events to peer%v failed", len(events), client.Cfg.Endpoints)
+			err = e
+		}
+	}
+	return
+}
+
+func (t *Transport) peers(ctx context.Context) (clients []*sc.SCClient) {
+	ctx = context.WithValue(ctx, serviceUtil.CTX_CACHEONLY, "1")
+	instances, err := serviceUtil.GetAllInstancesOfOneService(ctx, core.REGISTRY_DOMAIN_PROJECT, core.Service.ServiceId)
+	if err != nil {
+		log.Errorf(err, "get the peers of service center failed")
+		return
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	actives := make(map[string]*sc.SCClient, len(instances))
+	for _, instance := range instances {
+		if instance.InstanceId == core.Instance.InstanceId {
+			continue
+		}
+		endpoint := RestEndpoint(instance.Endpoints)
+		if len(endpoint) == 0 {
+			continue
+		}
+		client, ok := t.clients[endpoint]
+		if !ok {
+			if client, err = t.newClient(endpoint); err != nil {
+				log.Errorf(err, "new peer[%s] client failed", endpoint)
+				continue
+			}
+		}
+		actives[endpoint] = client
+		clients = append(clients, client)
+	}
+	t.clients = actives
+	return
+}
+
+func (t *Transport) newClient(endpoint string) (*sc.SCClient, error) {
+	client, err := sc.NewSCClient(sc.Config{Name: endpoint, Endpoints: []string{endpoint}})
+	if err != nil {
+		return nil, err
+	}
+	client.Timeout = registry.Configuration().RequestTimeOut
+	if u, _ := url.Parse(endpoint); u.Scheme == "https" {
+		if t.clientTLS == nil {
+			if t.clientTLS, err = mgr.Plugins().TLS().ClientConfig(); err != nil {
+				return nil, err
+			}
+		}
+		client.TLS = t.clientTLS
+	}
+	return client, nil
+}
+
+// Client returns the client of the peer endpoint, the client is cached
+// until the peer leaves
+func (t *Transport) Client(endpoint string) (*sc.SCClient, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if client, ok := t.clients[endpoint]; ok {
+		return client, nil
+	}
+	client, err := t.newClient(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if t.clients == nil {
+		t.clients = make(map[string]*sc.SCClient)
+	}
+	t.clients[endpoint] = client
+	return client, nil
+}
+
+// RestEndpoint converts the 'rest://ip:port/?sslEnabled=true' endpoint
+// to the http(s) address
+func RestEndpoint(endpoints []string) string {
+	for _, endpoint := range endpoints {
+		u, err := url.Parse(endpoint)
+		if err != nil || u.Scheme != "rest" {
+			continue
+		}
+		scheme := "http"
+		if u.Query().Get("sslEnabled") == "true" {
+			scheme = "https"
+		}
+		return scheme + "://" + u.Host
+	}
+	return ""
+}
+
+func NewTransport() *Transport {
+	return &Transport{}
+}
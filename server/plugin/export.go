@@ -25,6 +25,7 @@ import (
 	"github.com/apache/servicecomb-service-center/server/plugin/pkg/tls"
 	"github.com/apache/servicecomb-service-center/server/plugin/pkg/tracing"
 	"github.com/apache/servicecomb-service-center/server/plugin/pkg/uuid"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/validator"
 )
 
 const (
@@ -37,6 +38,7 @@ const (
 	TRACING
 	TLS
 	DISCOVERY
+	VALIDATOR
 	typeEnd
 )
 
@@ -50,6 +52,7 @@ var pluginNames = map[PluginName]string{
 	TRACING:   "trace",
 	DISCOVERY: "discovery",
 	TLS:       "ssl",
+	VALIDATOR: "validator",
 }
 
 func (pm *PluginManager) Discovery() discovery.AdaptorRepository {
@@ -67,3 +70,6 @@ func (pm *PluginManager) Cipher() security.Cipher      { return pm.Instance(CIPH
 func (pm *PluginManager) Quota() quota.QuotaManager    { return pm.Instance(QUOTA).(quota.QuotaManager) }
 func (pm *PluginManager) Tracing() (v tracing.Tracing) { return pm.Instance(TRACING).(tracing.Tracing) }
 func (pm *PluginManager) TLS() tls.TLS                 { return pm.Instance(TLS).(tls.TLS) }
+func (pm *PluginManager) Validator() validator.Validator {
+	return pm.Instance(VALIDATOR).(validator.Validator)
+}
@@ -0,0 +1,54 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package plugin
+
+import (
+	"errors"
+	"golang.org/x/net/context"
+)
+
+var ErrPluginNotLoaded = errors.New("plugin is not loaded")
+
+// HealthChecker is implemented by the plugin instance which can check
+// the health of its backing service
+type HealthChecker interface {
+	CheckHealth(ctx context.Context) error
+}
+
+// Health returns the health of the plugins by name, nil means healthy,
+// the plugin kinds without any implementation registered are skipped
+func (pm *PluginManager) Health(ctx context.Context) map[string]error {
+	m := make(map[string]error, len(pm.instances))
+	for pn, wi := range pm.instances {
+		if _, ok := pm.plugins[pn]; !ok {
+			continue
+		}
+		wi.lock.RLock()
+		instance := wi.instance
+		wi.lock.RUnlock()
+
+		switch i := instance.(type) {
+		case nil:
+			m[pn.String()] = ErrPluginNotLoaded
+		case HealthChecker:
+			m[pn.String()] = i.CheckHealth(ctx)
+		default:
+			m[pn.String()] = nil
+		}
+	}
+	return m
+}
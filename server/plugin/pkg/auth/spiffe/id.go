@@ -0,0 +1,122 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package spiffe
+
+import (
+	"crypto/x509"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const SCHEME = "spiffe"
+
+var (
+	oidSubjectAltName = asn1.ObjectIdentifier{2, 5, 29, 17}
+
+	ErrNoSVID = errors.New("no SPIFFE ID presented in the client certificate")
+)
+
+// ID is the SPIFFE ID 'spiffe://{trust domain}/{path}'
+type ID struct {
+	TrustDomain string
+	Path        string
+}
+
+func (id ID) String() string {
+	return SCHEME + "://" + id.TrustDomain + id.Path
+}
+
+// Segments returns the non-empty segments of the path
+func (id ID) Segments() []string {
+	var segments []string
+	for _, s := range strings.Split(id.Path, "/") {
+		if len(s) > 0 {
+			segments = append(segments, s)
+		}
+	}
+	return segments
+}
+
+func ParseID(s string) (*ID, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case strings.ToLower(u.Scheme) != SCHEME:
+		return nil, fmt.Errorf("invalid SPIFFE ID '%s', scheme must be '%s'", s, SCHEME)
+	case len(u.Host) == 0:
+		return nil, fmt.Errorf("invalid SPIFFE ID '%s', trust domain is empty", s)
+	case u.User != nil || len(u.Port()) > 0:
+		return nil, fmt.Errorf("invalid SPIFFE ID '%s', trust domain must not contain user info or port", s)
+	case len(u.RawQuery) > 0 || len(u.Fragment) > 0:
+		return nil, fmt.Errorf("invalid SPIFFE ID '%s', query and fragment are not allowed", s)
+	}
+	return &ID{TrustDomain: strings.ToLower(u.Host), Path: u.Path}, nil
+}
+
+// FromCertificate returns the SPIFFE ID in the URI SAN of the SVID, the SVID
+// must contain exactly one URI SAN
+func FromCertificate(cert *x509.Certificate) (*ID, error) {
+	uris, err := uriSANs(cert)
+	if err != nil {
+		return nil, err
+	}
+	switch len(uris) {
+	case 0:
+		return nil, ErrNoSVID
+	case 1:
+		return ParseID(uris[0])
+	default:
+		return nil, fmt.Errorf("the SVID contains %d URI SANs", len(uris))
+	}
+}
+
+// uriSANs parses the subject alternative name extension directly, the
+// x509.Certificate of go1.9 does not expose the URIs
+func uriSANs(cert *x509.Certificate) ([]string, error) {
+	var uris []string
+	for _, ext := range cert.Extensions {
+		if !ext.Id.Equal(oidSubjectAltName) {
+			continue
+		}
+		var seq asn1.RawValue
+		rest, err := asn1.Unmarshal(ext.Value, &seq)
+		if err != nil {
+			return nil, err
+		}
+		if len(rest) > 0 || !seq.IsCompound || seq.Tag != asn1.TagSequence || seq.Class != asn1.ClassUniversal {
+			return nil, errors.New("invalid subject alternative name extension")
+		}
+		rest = seq.Bytes
+		for len(rest) > 0 {
+			var v asn1.RawValue
+			rest, err = asn1.Unmarshal(rest, &v)
+			if err != nil {
+				return nil, err
+			}
+			// uniformResourceIdentifier [6] IA5String
+			if v.Class == asn1.ClassContextSpecific && v.Tag == 6 {
+				uris = append(uris, string(v.Bytes))
+			}
+		}
+	}
+	return uris, nil
+}
@@ -0,0 +1,83 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package spiffe
+
+import (
+	"fmt"
+	"github.com/apache/servicecomb-service-center/server/core"
+	"strings"
+)
+
+// Identity is the caller identity mapped from the SPIFFE ID
+type Identity struct {
+	Domain  string
+	Project string
+	Service string
+}
+
+// Mapper maps the trust domains to the domains, and the paths to the
+// projects and the services. The path '/ns/{project}/sa/{service}' is
+// the convention of the k8s workloads, otherwise the path is
+// '/{project}/{service}' or '/{service}' in the default project
+type Mapper struct {
+	// Domains is the trust domain to domain map, empty means any trust
+	// domain is accepted as the domain of the same name
+	Domains map[string]string
+}
+
+func (m *Mapper) Map(id *ID) (*Identity, error) {
+	domain := id.TrustDomain
+	if len(m.Domains) > 0 {
+		d, ok := m.Domains[id.TrustDomain]
+		if !ok {
+			return nil, fmt.Errorf("trust domain '%s' is not allowed", id.TrustDomain)
+		}
+		domain = d
+	}
+
+	identity := &Identity{Domain: domain, Project: core.REGISTRY_PROJECT}
+	segments := id.Segments()
+	switch {
+	case len(segments) == 4 && segments[0] == "ns" && segments[2] == "sa":
+		identity.Project, identity.Service = segments[1], segments[3]
+	case len(segments) == 2:
+		identity.Project, identity.Service = segments[0], segments[1]
+	case len(segments) == 1:
+		identity.Service = segments[0]
+	default:
+		return nil, fmt.Errorf("can not map the path of SPIFFE ID '%s'", id)
+	}
+	return identity, nil
+}
+
+// ParseDomains parses the '{trust domain}[={domain}]' list separated by comma
+func ParseDomains(s string) map[string]string {
+	domains := make(map[string]string)
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if len(item) == 0 {
+			continue
+		}
+		kv := strings.SplitN(item, "=", 2)
+		trustDomain, domain := strings.ToLower(strings.TrimSpace(kv[0])), strings.TrimSpace(kv[0])
+		if len(kv) == 2 && len(strings.TrimSpace(kv[1])) > 0 {
+			domain = strings.TrimSpace(kv[1])
+		}
+		domains[trustDomain] = domain
+	}
+	return domains
+}
@@ -0,0 +1,76 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package spiffe
+
+import (
+	"errors"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	mgr "github.com/apache/servicecomb-service-center/server/plugin"
+	"github.com/astaxie/beego"
+	"net/http"
+)
+
+func init() {
+	mgr.RegisterPlugin(mgr.Plugin{mgr.AUTH, "spiffe", New})
+}
+
+func New() mgr.PluginInstance {
+	return &SpiffeAuth{
+		Required: beego.AppConfig.DefaultInt("spiffe_required", 1) != 0,
+		Mapper:   &Mapper{Domains: ParseDomains(beego.AppConfig.String("spiffe_trust_domains"))},
+	}
+}
+
+// SpiffeAuth accepts the SPIFFE ID in the client certificate of mTLS as
+// the caller identity, the domain and project of the request are mapped
+// from the SPIFFE ID instead of the request headers
+type SpiffeAuth struct {
+	// Required rejects the requests without SVID, otherwise they are
+	// identified by the request headers as usual
+	Required bool
+	Mapper   *Mapper
+}
+
+func (a *SpiffeAuth) Identify(r *http.Request) error {
+	id, err := FromRequest(r)
+	if err == ErrNoSVID && !a.Required {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	identity, err := a.Mapper.Map(id)
+	if err != nil {
+		return err
+	}
+	util.SetRequestContext(r, util.CtxDomain, identity.Domain)
+	util.SetRequestContext(r, util.CtxProject, identity.Project)
+	util.SetRequestContext(r, util.CtxServiceIdentity, identity.Service)
+	util.SetRequestContext(r, util.CtxSpiffeId, id.String())
+	return nil
+}
+
+// FromRequest returns the SPIFFE ID of the verified client certificate
+func FromRequest(r *http.Request) (*ID, error) {
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		return nil, ErrNoSVID
+	}
+	if len(r.TLS.VerifiedChains) == 0 {
+		return nil, errors.New("the client certificate is not verified")
+	}
+	return FromCertificate(r.TLS.PeerCertificates[0])
+}
@@ -0,0 +1,161 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package spiffe
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func newCertificate(t *testing.T, uris ...string) *x509.Certificate {
+	var names []asn1.RawValue
+	for _, uri := range uris {
+		names = append(names, asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 6, Bytes: []byte(uri)})
+	}
+	san, err := asn1.Marshal(names)
+	if err != nil {
+		t.Fatalf("newCertificate failed, %v", err)
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("newCertificate failed, %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:    big.NewInt(1),
+		Subject:         pkix.Name{CommonName: "test"},
+		NotBefore:       time.Now().Add(-time.Hour),
+		NotAfter:        time.Now().Add(time.Hour),
+		ExtraExtensions: []pkix.Extension{{Id: oidSubjectAltName, Value: san}},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("newCertificate failed, %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("newCertificate failed, %v", err)
+	}
+	return cert
+}
+
+func TestParseID(t *testing.T) {
+	id, err := ParseID("spiffe://Example.org/ns/prod/sa/order")
+	if err != nil || id.TrustDomain != "example.org" || id.Path != "/ns/prod/sa/order" ||
+		id.String() != "spiffe://example.org/ns/prod/sa/order" {
+		t.Fatalf("TestParseID failed, %v, %v", id, err)
+	}
+	for _, s := range []string{
+		"http://example.org/a",
+		"spiffe:///a",
+		"spiffe://user@example.org/a",
+		"spiffe://example.org:8080/a",
+		"spiffe://example.org/a?b=c",
+		"spiffe://example.org/a#b",
+	} {
+		if _, err := ParseID(s); err == nil {
+			t.Fatalf("TestParseID %s failed", s)
+		}
+	}
+}
+
+func TestFromCertificate(t *testing.T) {
+	id, err := FromCertificate(newCertificate(t, "spiffe://example.org/order"))
+	if err != nil || id.String() != "spiffe://example.org/order" {
+		t.Fatalf("TestFromCertificate failed, %v, %v", id, err)
+	}
+	_, err = FromCertificate(newCertificate(t))
+	if err != ErrNoSVID {
+		t.Fatalf("TestFromCertificate failed, %v", err)
+	}
+	_, err = FromCertificate(newCertificate(t, "spiffe://example.org/a", "spiffe://example.org/b"))
+	if err == nil {
+		t.Fatalf("TestFromCertificate failed")
+	}
+}
+
+func TestMapper(t *testing.T) {
+	domains := ParseDomains(" Example.org=default, corp.com ,")
+	if len(domains) != 2 || domains["example.org"] != "default" || domains["corp.com"] != "corp.com" {
+		t.Fatalf("TestMapper failed, %v", domains)
+	}
+	m := &Mapper{Domains: domains}
+	cases := []struct {
+		id       string
+		identity Identity
+	}{
+		{"spiffe://example.org/ns/prod/sa/order", Identity{"default", "prod", "order"}},
+		{"spiffe://example.org/prod/order", Identity{"default", "prod", "order"}},
+		{"spiffe://corp.com/order", Identity{"corp.com", "default", "order"}},
+	}
+	for _, c := range cases {
+		id, _ := ParseID(c.id)
+		identity, err := m.Map(id)
+		if err != nil || *identity != c.identity {
+			t.Fatalf("TestMapper %s failed, %v, %v", c.id, identity, err)
+		}
+	}
+	for _, s := range []string{"spiffe://other.org/order", "spiffe://example.org/", "spiffe://example.org/a/b/c"} {
+		id, _ := ParseID(s)
+		if _, err := m.Map(id); err == nil {
+			t.Fatalf("TestMapper %s failed", s)
+		}
+	}
+
+	id, _ := ParseID("spiffe://other.org/order")
+	identity, err := (&Mapper{}).Map(id)
+	if err != nil || identity.Domain != "other.org" {
+		t.Fatalf("TestMapper failed, %v, %v", identity, err)
+	}
+}
+
+func TestSpiffeAuth_Identify(t *testing.T) {
+	a := &SpiffeAuth{Required: true, Mapper: &Mapper{}}
+	r, _ := http.NewRequest(http.MethodGet, "/v4/default/registry/microservices", nil)
+	if err := a.Identify(r); err != ErrNoSVID {
+		t.Fatalf("TestSpiffeAuth_Identify failed, %v", err)
+	}
+	a.Required = false
+	if err := a.Identify(r); err != nil || len(util.ParseDomain(r.Context())) > 0 {
+		t.Fatalf("TestSpiffeAuth_Identify failed, %v", err)
+	}
+
+	cert := newCertificate(t, "spiffe://example.org/ns/prod/sa/order")
+	r.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+	if err := a.Identify(r); err == nil {
+		t.Fatalf("TestSpiffeAuth_Identify failed, the unverified certificate is accepted")
+	}
+	r.TLS.VerifiedChains = [][]*x509.Certificate{{cert}}
+	if err := a.Identify(r); err != nil {
+		t.Fatalf("TestSpiffeAuth_Identify failed, %v", err)
+	}
+	ctx := r.Context()
+	if util.ParseDomainProject(ctx) != "example.org/prod" ||
+		util.FromContext(ctx, util.CtxServiceIdentity) != "order" ||
+		util.FromContext(ctx, util.CtxSpiffeId) != "spiffe://example.org/ns/prod/sa/order" {
+		t.Fatalf("TestSpiffeAuth_Identify failed, %s", util.ParseDomainProject(ctx))
+	}
+}
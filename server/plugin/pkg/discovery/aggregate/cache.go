@@ -16,7 +16,6 @@
 package aggregate
 
 import (
-	"github.com/apache/servicecomb-service-center/pkg/util"
 	"github.com/apache/servicecomb-service-center/server/plugin/pkg/discovery"
 )
 
@@ -69,7 +68,7 @@ func (c Cache) GetPrefix(prefix string, arr *[]*discovery.KeyValue) (s int) {
 func (c Cache) append(tmp []*discovery.KeyValue, arr *[]*discovery.KeyValue,
 	exists map[string]struct{}) (s int) {
 	for _, kv := range tmp {
-		key := util.BytesToStringWithNoCopy(kv.Key)
+		key := identity(kv)
 		if _, ok := exists[key]; ok {
 			continue
 		}
@@ -86,10 +85,11 @@ func (c Cache) ForEach(iter func(k string, v *discovery.KeyValue) (next bool)) {
 	exists := make(map[string]struct{})
 	for _, cache := range c {
 		cache.ForEach(func(k string, v *discovery.KeyValue) bool {
-			if _, ok := exists[k]; ok {
+			id := identity(v)
+			if _, ok := exists[id]; ok {
 				return true
 			}
-			exists[k] = struct{}{}
+			exists[id] = struct{}{}
 			return iter(k, v)
 		})
 	}
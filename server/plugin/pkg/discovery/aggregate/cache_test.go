@@ -0,0 +1,51 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package aggregate
+
+import (
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/discovery"
+	"testing"
+)
+
+func TestCache_GetPrefix(t *testing.T) {
+	local, remote := discovery.NewKvCache("local", discovery.Configure()), discovery.NewKvCache("remote", discovery.Configure())
+	local.Put("/inst/svc/1", &discovery.KeyValue{Key: []byte("/inst/svc/1"),
+		Value: &pb.MicroServiceInstance{InstanceId: "1", Endpoints: []string{"rest://a:80", "rest://b:80"}}})
+	remote.Put("/inst/svc/1", &discovery.KeyValue{Key: []byte("/inst/svc/1"),
+		Value: &pb.MicroServiceInstance{InstanceId: "1", Endpoints: []string{"rest://a:80", "rest://b:80"}}})
+	remote.Put("/inst/svc/2", &discovery.KeyValue{Key: []byte("/inst/svc/2"),
+		Value: &pb.MicroServiceInstance{InstanceId: "2", Endpoints: []string{"rest://b:80", "rest://a:80"}}})
+	remote.Put("/inst/svc/3", &discovery.KeyValue{Key: []byte("/inst/svc/3"),
+		Value: &pb.MicroServiceInstance{InstanceId: "3", Endpoints: []string{"rest://c:80"}}})
+	remote.Put("/inst/other/4", &discovery.KeyValue{Key: []byte("/inst/other/4"),
+		Value: &pb.MicroServiceInstance{InstanceId: "4", Endpoints: []string{"rest://a:80", "rest://b:80"}}})
+
+	var arr []*discovery.KeyValue
+	c := Cache{local, remote}
+	if n := c.GetPrefix("/inst/", &arr); n != 3 || len(arr) != 3 {
+		t.Fatalf("TestCache_GetPrefix failed, %v", arr)
+	}
+	if arr[0].Value.(*pb.MicroServiceInstance).InstanceId != "1" {
+		t.Fatalf("TestCache_GetPrefix failed, %v", arr[0])
+	}
+	for _, kv := range arr {
+		if kv.Value.(*pb.MicroServiceInstance).InstanceId == "2" {
+			t.Fatalf("TestCache_GetPrefix failed, %v", kv)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package aggregate
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/discovery"
+	"sort"
+	"strings"
+)
+
+// identity returns the de-duplication key of the kv. Instances found in
+// more than one source are the same one if they belong to the same
+// service and listen on the same endpoints, even if their instance ids are
+// different, so the one found first, in order of aggregate_mode, wins.
+func identity(kv *discovery.KeyValue) string {
+	key := util.BytesToStringWithNoCopy(kv.Key)
+	inst, ok := kv.Value.(*pb.MicroServiceInstance)
+	if !ok || len(inst.Endpoints) == 0 {
+		return key
+	}
+	i := strings.LastIndex(key, "/")
+	if i < 0 {
+		return key
+	}
+	endpoints := make([]string, len(inst.Endpoints))
+	copy(endpoints, inst.Endpoints)
+	sort.Strings(endpoints)
+	return key[:i+1] + strings.Join(endpoints, ",")
+}
@@ -16,7 +16,6 @@
 package aggregate
 
 import (
-	"github.com/apache/servicecomb-service-center/pkg/util"
 	"github.com/apache/servicecomb-service-center/server/plugin/pkg/discovery"
 	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
 	"golang.org/x/net/context"
@@ -36,14 +35,17 @@ func (i *AdaptorsIndexer) Search(ctx context.Context, opts ...registry.PluginOpO
 		if err != nil {
 			continue
 		}
+		var dup int64
 		for _, kv := range resp.Kvs {
-			key := util.BytesToStringWithNoCopy(kv.Key)
-			if _, ok := exists[key]; !ok {
-				exists[key] = struct{}{}
-				response.Kvs = append(response.Kvs, kv)
+			key := identity(kv)
+			if _, ok := exists[key]; ok {
+				dup++
+				continue
 			}
+			exists[key] = struct{}{}
+			response.Kvs = append(response.Kvs, kv)
 		}
-		response.Count += resp.Count
+		response.Count += resp.Count - dup
 	}
 	return &response, nil
 }
@@ -20,14 +20,50 @@ import (
 	"github.com/apache/servicecomb-service-center/pkg/util"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// LockStats is the lock contention statistics of the cache
+type LockStats struct {
+	// Acquires is the number of the locks acquired
+	Acquires int64
+	// Wait is the total time spent in waiting for the locks
+	Wait time.Duration
+}
+
+type kvShard struct {
+	store    map[string]map[string]*KeyValue
+	rwMux    sync.RWMutex
+	acquires int64
+	wait     int64
+}
+
+func (s *kvShard) rlock() {
+	start := time.Now()
+	s.rwMux.RLock()
+	s.observe(start)
+}
+
+func (s *kvShard) lock() {
+	start := time.Now()
+	s.rwMux.Lock()
+	s.observe(start)
+}
+
+func (s *kvShard) observe(start time.Time) {
+	atomic.AddInt64(&s.acquires, 1)
+	atomic.AddInt64(&s.wait, int64(time.Since(start)))
+}
+
+// KvCache is the local cache of one resource type, the keys are sharded
+// by domain project, and by service within one domain project, so that
+// the lookups of the different domains do not contend for the same lock
 type KvCache struct {
 	Cfg         *Config
 	name        string
-	store       map[string]map[string]*KeyValue
-	rwMux       sync.RWMutex
+	shards      []*kvShard
+	width       uint32
 	lastRefresh time.Time
 	lastMaxSize int
 }
@@ -37,62 +73,122 @@ func (c *KvCache) Name() string {
 }
 
 func (c *KvCache) Size() (l int) {
-	c.rwMux.RLock()
-	l = int(util.Sizeof(c.store))
-	c.rwMux.RUnlock()
+	for _, s := range c.shards {
+		s.rlock()
+		l += int(util.Sizeof(s.store))
+		s.rwMux.RUnlock()
+	}
 	return
 }
 
 func (c *KvCache) Get(key string) (v *KeyValue) {
-	c.rwMux.RLock()
+	s := c.shardOf(key)
+	s.rlock()
 	prefix := c.prefix(key)
-	if p, ok := c.store[prefix]; ok {
+	if p, ok := s.store[prefix]; ok {
 		v, _ = p[key]
 	}
-	c.rwMux.RUnlock()
+	s.rwMux.RUnlock()
 	return
 }
 
 func (c *KvCache) GetAll(arr *[]*KeyValue) (count int) {
-	c.rwMux.RLock()
-	count = c.getPrefixKey(arr, c.Cfg.Key)
-	c.rwMux.RUnlock()
-	return
+	return c.GetPrefix(c.Cfg.Key, arr)
 }
 
+// GetPrefix returns the values under the prefix, the prefix spanning
+// several shards is not a consistent snapshot of them
 func (c *KvCache) GetPrefix(prefix string, arr *[]*KeyValue) (count int) {
-	c.rwMux.RLock()
-	count = c.getPrefixKey(arr, prefix)
-	c.rwMux.RUnlock()
+	c.forEachShardOf(prefix, func(s *kvShard) bool {
+		s.rlock()
+		count += c.getPrefixKey(s.store, arr, prefix)
+		s.rwMux.RUnlock()
+		return true
+	})
 	return
 }
 
 func (c *KvCache) Put(key string, v *KeyValue) {
-	c.rwMux.Lock()
-	c.addPrefixKey(key, v)
-	c.rwMux.Unlock()
+	s := c.shardOf(key)
+	s.lock()
+	c.addPrefixKey(s.store, key, v)
+	s.rwMux.Unlock()
 }
 
 func (c *KvCache) Remove(key string) {
-	c.rwMux.Lock()
-	c.deletePrefixKey(key)
-	c.rwMux.Unlock()
+	s := c.shardOf(key)
+	s.lock()
+	c.deletePrefixKey(s.store, key)
+	s.rwMux.Unlock()
 }
 
 func (c *KvCache) ForEach(iter func(k string, v *KeyValue) (next bool)) {
-	c.rwMux.RLock()
+	for _, s := range c.shards {
+		if !c.forEach(s, iter) {
+			return
+		}
+	}
+}
+
+func (c *KvCache) forEach(s *kvShard, iter func(k string, v *KeyValue) (next bool)) (next bool) {
+	next = true
+	s.rlock()
 loopParent:
-	for _, p := range c.store {
+	for _, p := range s.store {
 		for k, v := range p {
 			if v == nil {
 				continue loopParent
 			}
 			if !iter(k, v) {
+				next = false
 				break loopParent
 			}
 		}
 	}
-	c.rwMux.RUnlock()
+	s.rwMux.RUnlock()
+	return
+}
+
+// LockStats returns the lock contention statistics summed over the shards
+func (c *KvCache) LockStats() (stats LockStats) {
+	for _, s := range c.shards {
+		stats.Acquires += atomic.LoadInt64(&s.acquires)
+		stats.Wait += time.Duration(atomic.LoadInt64(&s.wait))
+	}
+	return
+}
+
+// shardOf returns the shard of the key
+func (c *KvCache) shardOf(key string) *kvShard {
+	domainProject, serviceId, _ := splitShardKey(strings.TrimPrefix(key, c.Cfg.Key))
+	return c.shards[c.index(domainProject, serviceId)]
+}
+
+// forEachShardOf calls f with the shards may contain the keys under the
+// prefix, a domain project prefix only spans the shards of its services
+func (c *KvCache) forEachShardOf(prefix string, f func(s *kvShard) bool) {
+	domainProject, serviceId, depth := splitShardKey(strings.TrimPrefix(prefix, c.Cfg.Key))
+	switch {
+	case depth < 2:
+		for _, s := range c.shards {
+			if !f(s) {
+				return
+			}
+		}
+	case depth == 2 && len(serviceId) == 0:
+		base := hashString(domainProject)
+		for i := uint32(0); i < c.width; i++ {
+			if !f(c.shards[(base+i)%uint32(len(c.shards))]) {
+				return
+			}
+		}
+	default:
+		f(c.shards[c.index(domainProject, serviceId)])
+	}
+}
+
+func (c *KvCache) index(domainProject, serviceId string) uint32 {
+	return (hashString(domainProject) + hashString(serviceId)%c.width) % uint32(len(c.shards))
 }
 
 func (c *KvCache) prefix(key string) string {
@@ -102,8 +198,8 @@ func (c *KvCache) prefix(key string) string {
 	return key[:strings.LastIndex(key[:len(key)-1], "/")+1]
 }
 
-func (c *KvCache) getPrefixKey(arr *[]*KeyValue, prefix string) (count int) {
-	keysRef, ok := c.store[prefix]
+func (c *KvCache) getPrefixKey(store map[string]map[string]*KeyValue, arr *[]*KeyValue, prefix string) (count int) {
+	keysRef, ok := store[prefix]
 	if !ok {
 		return 0
 	}
@@ -111,7 +207,7 @@ func (c *KvCache) getPrefixKey(arr *[]*KeyValue, prefix string) (count int) {
 	// TODO support sort option
 	if arr == nil {
 		for key := range keysRef {
-			if n := c.getPrefixKey(nil, key); n > 0 {
+			if n := c.getPrefixKey(store, nil, key); n > 0 {
 				count += n
 				continue
 			}
@@ -121,7 +217,7 @@ func (c *KvCache) getPrefixKey(arr *[]*KeyValue, prefix string) (count int) {
 	}
 
 	for key, val := range keysRef {
-		if n := c.getPrefixKey(arr, key); n > 0 {
+		if n := c.getPrefixKey(store, arr, key); n > 0 {
 			count += n
 			continue
 		}
@@ -131,7 +227,7 @@ func (c *KvCache) getPrefixKey(arr *[]*KeyValue, prefix string) (count int) {
 	return
 }
 
-func (c *KvCache) addPrefixKey(key string, val *KeyValue) {
+func (c *KvCache) addPrefixKey(store map[string]map[string]*KeyValue, key string, val *KeyValue) {
 	if len(c.Cfg.Key) >= len(key) {
 		return
 	}
@@ -139,11 +235,11 @@ func (c *KvCache) addPrefixKey(key string, val *KeyValue) {
 	if len(prefix) == 0 {
 		return
 	}
-	keys, ok := c.store[prefix]
+	keys, ok := store[prefix]
 	if !ok {
 		// build parent index key and new child nodes
 		keys = make(map[string]*KeyValue)
-		c.store[prefix] = keys
+		store[prefix] = keys
 	} else if _, ok := keys[key]; ok {
 		if val != nil {
 			// override the value
@@ -153,12 +249,12 @@ func (c *KvCache) addPrefixKey(key string, val *KeyValue) {
 	}
 
 	keys[key], key = val, prefix
-	c.addPrefixKey(key, nil)
+	c.addPrefixKey(store, key, nil)
 }
 
-func (c *KvCache) deletePrefixKey(key string) {
+func (c *KvCache) deletePrefixKey(store map[string]map[string]*KeyValue, key string) {
 	prefix := c.prefix(key)
-	m, ok := c.store[prefix]
+	m, ok := store[prefix]
 	if !ok {
 		return
 	}
@@ -166,16 +262,59 @@ func (c *KvCache) deletePrefixKey(key string) {
 
 	// remove parent which has no child
 	if len(m) == 0 {
-		delete(c.store, prefix)
-		c.deletePrefixKey(prefix)
+		delete(store, prefix)
+		c.deletePrefixKey(store, prefix)
 	}
 }
 
+// splitShardKey splits the key relative to the resource prefix into
+// the domain project and the service segments, depth is the number of
+// the segments terminated by '/'
+func splitShardKey(key string) (domainProject, serviceId string, depth int) {
+	i := strings.IndexByte(key, '/')
+	if i < 0 {
+		return key, "", 0
+	}
+	j := strings.IndexByte(key[i+1:], '/')
+	if j < 0 {
+		return key, "", 1
+	}
+	domainProject, key = key[:i+1+j], key[i+2+j:]
+	k := strings.IndexByte(key, '/')
+	if k < 0 {
+		return domainProject, key, 2
+	}
+	return domainProject, key[:k], 3
+}
+
+// hashString is the FNV-1a hash of s
+func hashString(s string) uint32 {
+	h := uint32(2166136261)
+	for i := 0; i < len(s); i++ {
+		h ^= uint32(s[i])
+		h *= 16777619
+	}
+	return h
+}
+
 func NewKvCache(name string, cfg *Config) *KvCache {
-	return &KvCache{
+	n := cfg.Shards
+	if n <= 0 {
+		n = 1
+	}
+	width := DEFAULT_DOMAIN_SHARDS
+	if width > n {
+		width = n
+	}
+	c := &KvCache{
 		Cfg:         cfg,
 		name:        name,
-		store:       make(map[string]map[string]*KeyValue),
+		shards:      make([]*kvShard, n),
+		width:       uint32(width),
 		lastRefresh: time.Now(),
 	}
+	for i := range c.shards {
+		c.shards[i] = &kvShard{store: make(map[string]map[string]*KeyValue)}
+	}
+	return c
 }
@@ -17,6 +17,7 @@
 package discovery
 
 import (
+	"fmt"
 	"testing"
 )
 
@@ -159,3 +160,85 @@ func BenchmarkKvCache_GetAll2(b *testing.B) {
 	b.ReportAllocs()
 	// 1000000	      2784 ns/op	     173 B/op	       0 allocs/op
 }
+
+func TestKvCache_Shards(t *testing.T) {
+	c := NewKvCache("test", Configure().WithPrefix("/r/").WithShards(8))
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 10; j++ {
+			c.Put(fmt.Sprintf("/r/d%d/p/s%d/i1", i, j), &KeyValue{Version: int64(j)})
+			c.Put(fmt.Sprintf("/r/d%d/p/s%d/i2", i, j), &KeyValue{Version: int64(j)})
+		}
+	}
+
+	if l := c.GetAll(nil); l != 80 {
+		t.Fatalf("TestKvCache_Shards GetAll() failed, %d", l)
+	}
+	if l := c.GetPrefix("/r/d1/", nil); l != 20 {
+		t.Fatalf("TestKvCache_Shards GetPrefix() failed, %d", l)
+	}
+	var arr []*KeyValue
+	if l := c.GetPrefix("/r/d1/p/", &arr); l != 20 || len(arr) != 20 {
+		t.Fatalf("TestKvCache_Shards GetPrefix() failed, %d, %v", l, arr)
+	}
+	arr = arr[:0]
+	if l := c.GetPrefix("/r/d1/p/s3/", &arr); l != 2 || arr[0].Version != 3 {
+		t.Fatalf("TestKvCache_Shards GetPrefix() failed, %d, %v", l, arr)
+	}
+	if kv := c.Get("/r/d2/p/s5/i2"); kv == nil || kv.Version != 5 {
+		t.Fatalf("TestKvCache_Shards Get() failed, %v", kv)
+	}
+
+	l := 0
+	c.ForEach(func(k string, v *KeyValue) (next bool) {
+		l++
+		return true
+	})
+	if l != 80 {
+		t.Fatalf("TestKvCache_Shards ForEach() failed, %d", l)
+	}
+
+	for j := 0; j < 10; j++ {
+		c.Remove(fmt.Sprintf("/r/d1/p/s%d/i1", j))
+	}
+	if l := c.GetPrefix("/r/d1/p/", nil); l != 10 {
+		t.Fatalf("TestKvCache_Shards Remove() failed, %d", l)
+	}
+
+	if stats := c.LockStats(); stats.Acquires == 0 {
+		t.Fatalf("TestKvCache_Shards LockStats() failed, %v", stats)
+	}
+}
+
+func benchmarkKvCacheParallel(b *testing.B, shards int) {
+	c := NewKvCache("test", Configure().WithPrefix("/r/").WithShards(shards))
+	for i := 0; i < 100; i++ {
+		for j := 0; j < 10; j++ {
+			c.Put(fmt.Sprintf("/r/d%d/p/s%d/i1", i, j), &KeyValue{Version: 1})
+		}
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		var arr []*KeyValue
+		i := 0
+		for pb.Next() {
+			key := fmt.Sprintf("/r/d%d/p/s%d/", i%100, i%10)
+			if i%10 == 0 {
+				c.Put(key+"i2", &KeyValue{Version: 2})
+			}
+			arr = arr[:0]
+			c.GetPrefix(key, &arr)
+			i++
+		}
+	})
+	b.ReportAllocs()
+	stats := c.LockStats()
+	b.Logf("lock acquires: %d, wait: %s", stats.Acquires, stats.Wait)
+}
+
+func BenchmarkKvCache_Parallel1(b *testing.B) {
+	benchmarkKvCacheParallel(b, 1)
+}
+
+func BenchmarkKvCache_Parallel16(b *testing.B) {
+	benchmarkKvCacheParallel(b, DEFAULT_CACHE_SHARDS)
+}
@@ -23,4 +23,8 @@ import (
 const (
 	DEFAULT_TIMEOUT         = 30 * time.Second
 	DEFAULT_CACHE_INIT_SIZE = 100
+	DEFAULT_CACHE_SHARDS    = 16
+	// DEFAULT_DOMAIN_SHARDS is the number of the shards that the services
+	// of one domain project are hashed to
+	DEFAULT_DOMAIN_SHARDS = 4
 )
@@ -26,6 +26,7 @@ type Config struct {
 	// Key is the prefix to unique specify resource type
 	Key          string
 	InitSize     int
+	Shards       int
 	Timeout      time.Duration
 	Period       time.Duration
 	DeferHandler DeferHandler
@@ -48,6 +49,13 @@ func (cfg *Config) WithInitSize(size int) *Config {
 	return cfg
 }
 
+// WithShards sets the number of the shards of the local cache,
+// keys are hashed to shards by domain project and service
+func (cfg *Config) WithShards(n int) *Config {
+	cfg.Shards = n
+	return cfg
+}
+
 func (cfg *Config) WithTimeout(ot time.Duration) *Config {
 	cfg.Timeout = ot
 	return cfg
@@ -91,6 +99,7 @@ func Configure() *Config {
 		Timeout:  DEFAULT_TIMEOUT,
 		Period:   time.Second,
 		InitSize: DEFAULT_CACHE_INIT_SIZE,
+		Shards:   DEFAULT_CACHE_SHARDS,
 		Parser:   pb.BytesParser,
 	}
 }
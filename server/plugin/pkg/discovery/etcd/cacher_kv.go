@@ -65,8 +65,13 @@ func (c *KvCacher) needList() bool {
 }
 
 func (c *KvCacher) doList(cfg ListWatchConfig) error {
+	resource, begin := c.cache.Name(), time.Now()
+	ReportRebuildStart(resource)
+
 	resp, err := c.lw.List(cfg)
 	if err != nil {
+		log.Errorf(err, "rebuild cache %s failed, prefix: %s", resource, c.Cfg.Key)
+		ReportRebuildFinish(resource, 0, 0, time.Since(begin), err)
 		return err
 	}
 
@@ -82,6 +87,17 @@ func (c *KvCacher) doList(cfg ListWatchConfig) error {
 	log.LogDebugOrWarnf(start, "finish to cache key %s, %d items, rev: %d",
 		c.Cfg.Key, len(kvs), c.lw.Revision())
 
+	// the changes found by the re-list of a ready cache are the ones
+	// the watcher missed, the cache served stale data before
+	missed := 0
+	if c.IsReady() {
+		missed = len(evts)
+	}
+	if missed > 0 {
+		log.Warnf("rebuild cache %s found %d changes missed by watching, prefix: %s",
+			resource, missed, c.Cfg.Key)
+	}
+	ReportRebuildFinish(resource, len(kvs), missed, time.Since(begin), nil)
 	return nil
 }
 
@@ -117,13 +133,43 @@ func (c *KvCacher) ListAndWatch(ctx context.Context) error {
 
 	util.SafeCloseChan(c.ready)
 
-	return c.doWatch(cfg)
+	if err := c.doWatch(cfg); err != registry.ErrCompacted {
+		return err
+	}
+	return c.resync(cfg)
+}
+
+// resync re-lists the backend without backing off after the revision
+// watched is compacted, and then watches from the revision listed
+func (c *KvCacher) resync(cfg ListWatchConfig) (err error) {
+	resource := c.cache.Name()
+	log.Warnf("the revision watched by cache %s is compacted, resync it, prefix: %s",
+		resource, c.Cfg.Key)
+	ReportCompacted(resource)
+
+	for i := 0; i < maxResyncRetries; i++ {
+		if err = c.doList(cfg); err == nil {
+			c.reListCount = 0
+			return c.doWatch(cfg)
+		}
+		select {
+		case <-cfg.Context.Done():
+			return err
+		case <-time.After(minWaitInterval):
+		}
+	}
+	log.Errorf(err, "resync cache %s failed after %d retries, prefix: %s",
+		resource, maxResyncRetries, c.Cfg.Key)
+	return err
 }
 
 func (c *KvCacher) handleWatcher(watcher Watcher) error {
 	defer watcher.Stop()
 	for resp := range watcher.EventBus() {
 		if resp == nil {
+			if c.lw.Compacted() {
+				return registry.ErrCompacted
+			}
 			return errors.New("handle watcher error")
 		}
 
@@ -457,9 +503,7 @@ func (c *KvCacher) IsReady() bool {
 }
 
 func (c *KvCacher) reportMetrics(ctx context.Context) {
-	if !core.ServerInfo.Config.EnablePProf {
-		return
-	}
+	var last discovery.LockStats
 	timer := time.NewTimer(DEFAULT_METRICS_INTERVAL)
 	defer timer.Stop()
 	for {
@@ -467,7 +511,17 @@ func (c *KvCacher) reportMetrics(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-timer.C:
-			ReportCacheSize(c.cache.Name(), "raw", c.cache.Size())
+			if core.ServerInfo.Config.EnablePProf {
+				ReportCacheSize(c.cache.Name(), "raw", c.cache.Size())
+			}
+			if kc, ok := c.cache.(*discovery.KvCache); ok {
+				stats := kc.LockStats()
+				ReportCacheLock(c.cache.Name(), discovery.LockStats{
+					Acquires: stats.Acquires - last.Acquires,
+					Wait:     stats.Wait - last.Wait,
+				})
+				last = stats
+			}
 			timer.Reset(DEFAULT_METRICS_INTERVAL)
 		}
 	}
@@ -367,6 +367,65 @@ func TestNewKvCacher(t *testing.T) {
 	<-lw.Bus
 }
 
+func TestKvCacher_Resync(t *testing.T) {
+	w := &mockWatcher{}
+	lw := &mockListWatch{
+		Bus: make(chan *registry.PluginResponse, 100),
+	}
+	w.lw = lw
+	lw.Watcher = w
+
+	var evt discovery.KvEvent
+	cr := &KvCacher{
+		Cfg: discovery.Configure().
+			WithEventFunc(func(e discovery.KvEvent) {
+				evt = e
+			}),
+		ready:     make(chan struct{}),
+		lw:        lw,
+		goroutine: gopool.New(context.Background()),
+		cache:     &mockCache{},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	data := &mvccpb.KeyValue{Key: []byte("ka"), Value: []byte("va"), Version: 1, ModRevision: 2}
+	lw.ListResponse = &registry.PluginResponse{Revision: 3, Kvs: []*mvccpb.KeyValue{data}}
+	lw.Bus <- nil
+	cr.refresh(ctx)
+	if !cr.IsReady() || cr.cache.Get("ka") == nil {
+		t.Fatalf("TestKvCacher_Resync failed")
+	}
+
+	// case: the revision watched is compacted, re-list and watch again
+	added := &mvccpb.KeyValue{Key: []byte("kb"), Value: []byte("vb"), Version: 1, ModRevision: 4}
+	lw.ListResponse = &registry.PluginResponse{Revision: 5, Kvs: []*mvccpb.KeyValue{data, added}}
+	lw.Compact = true
+	lw.Bus <- nil
+	lw.Bus <- nil
+	evt.KV = nil
+	err := cr.ListAndWatch(ctx)
+	if err == nil || err == registry.ErrCompacted || lw.Rev != 5 {
+		t.Fatalf("TestKvCacher_Resync failed, %v", err)
+	}
+	if evt.Type != pb.EVT_CREATE || evt.Revision != 5 || string(evt.KV.Key) != "kb" {
+		t.Fatalf("TestKvCacher_Resync failed, %v", evt)
+	}
+	if kv := cr.cache.Get("kb"); kv == nil || kv.ModRevision != 4 {
+		t.Fatalf("TestKvCacher_Resync failed")
+	}
+
+	// case: re-list failed
+	lw.ListResponse = nil
+	lw.Compact = true
+	lw.Bus <- nil
+	err = cr.ListAndWatch(ctx)
+	if err == nil || err == registry.ErrCompacted {
+		t.Fatalf("TestKvCacher_Resync failed, %v", err)
+	}
+}
+
 func BenchmarkFilter(b *testing.B) {
 	inst := &pb.MicroServiceInstance{
 		HealthCheck: &pb.HealthCheck{
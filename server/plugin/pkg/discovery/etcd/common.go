@@ -30,6 +30,9 @@ const (
 	minWaitInterval = 1 * time.Second
 	eventBlockSize  = 1000
 	eventBusSize    = 1000
+	// the times to re-list the backend immediately after the revision
+	// watched is compacted, the cacher backs off if all of them fail
+	maxResyncRetries = 3
 )
 
 var closedCh = make(chan struct{})
@@ -47,5 +50,8 @@ func FromEtcdKeyValue(dist *discovery.KeyValue, src *mvccpb.KeyValue, parser pb.
 		return
 	}
 	dist.Value, err = parser.Unmarshal(src.Value)
+	if r, ok := dist.Value.(pb.Revisioned); ok && err == nil {
+		r.SetRevision(src.CreateRevision, src.ModRevision)
+	}
 	return
 }
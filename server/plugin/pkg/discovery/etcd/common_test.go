@@ -0,0 +1,60 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package etcd
+
+import (
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/discovery"
+	"github.com/coreos/etcd/mvcc/mvccpb"
+	"testing"
+)
+
+func TestFromEtcdKeyValue(t *testing.T) {
+	src := &mvccpb.KeyValue{
+		Key:            []byte("/a"),
+		Value:          []byte(`{"instanceId":"a","modTimestamp":"1","createRevision":1,"modRevision":1}`),
+		CreateRevision: 3,
+		ModRevision:    5,
+	}
+
+	// case: the backend revisions are stamped on the instance
+	kv := discovery.NewKeyValue()
+	if err := FromEtcdKeyValue(kv, src, pb.InstanceParser); err != nil {
+		t.Fatalf("TestFromEtcdKeyValue failed, %v", err)
+	}
+	inst := kv.Value.(*pb.MicroServiceInstance)
+	if inst.CreateRevision != 3 || inst.ModRevision != 5 || inst.ModTimestamp != "1" {
+		t.Fatalf("TestFromEtcdKeyValue failed, %v", inst)
+	}
+
+	// case: the backend revisions are stamped on the service
+	src.Value = []byte(`{"serviceId":"a"}`)
+	kv = discovery.NewKeyValue()
+	if err := FromEtcdKeyValue(kv, src, pb.ServiceParser); err != nil {
+		t.Fatalf("TestFromEtcdKeyValue failed, %v", err)
+	}
+	ms := kv.Value.(*pb.MicroService)
+	if ms.CreateRevision != 3 || ms.ModRevision != 5 {
+		t.Fatalf("TestFromEtcdKeyValue failed, %v", ms)
+	}
+
+	// case: the other values are not changed
+	kv = discovery.NewKeyValue()
+	if err := FromEtcdKeyValue(kv, src, pb.StringParser); err != nil || kv.Value.(string) != string(src.Value) {
+		t.Fatalf("TestFromEtcdKeyValue failed, %v, %v", err, kv.Value)
+	}
+}
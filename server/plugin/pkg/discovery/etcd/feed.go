@@ -0,0 +1,61 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package etcd
+
+import (
+	"errors"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	"golang.org/x/net/context"
+)
+
+// ErrNoFeed means there is no delta feed to subscribe, the changes
+// should be watched from the backend directly
+var ErrNoFeed = errors.New("no delta feed available")
+
+var feed Feed = noFeed{}
+
+// Feed fans out the changes watched from the backend by one service
+// center to the others, so that only one of them watches the backend
+type Feed interface {
+	// Subscribe calls f with the changes of the prefix after rev until
+	// ctx is done, returns ErrNoFeed if the changes should be watched
+	// from the backend directly
+	Subscribe(ctx context.Context, prefix string, rev int64, f func(*registry.PluginResponse)) error
+	// Begin is called before watching the changes of the prefix after
+	// rev from the backend
+	Begin(prefix string, rev int64)
+	// Publish is called with the changes watched from the backend
+	Publish(prefix string, resp *registry.PluginResponse)
+}
+
+type noFeed struct {
+}
+
+func (noFeed) Subscribe(_ context.Context, _ string, _ int64, _ func(*registry.PluginResponse)) error {
+	return ErrNoFeed
+}
+
+func (noFeed) Begin(_ string, _ int64) {
+}
+
+func (noFeed) Publish(_ string, _ *registry.PluginResponse) {
+}
+
+// SetFeed replaces the feed, it is called in init()
+func SetFeed(f Feed) {
+	feed = f
+}
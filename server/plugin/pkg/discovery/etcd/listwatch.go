@@ -39,4 +39,7 @@ type ListWatch interface {
 	//
 	DoWatch(ctx context.Context, f func(*registry.PluginResponse)) error
 	Revision() int64
+	// Compacted returns true if the last watch failed because the revision
+	// to watch from has been compacted
+	Compacted() bool
 }
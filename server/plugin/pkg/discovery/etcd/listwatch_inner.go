@@ -27,7 +27,8 @@ type innerListWatch struct {
 	Client registry.Registry
 	Prefix string
 
-	rev int64
+	rev       int64
+	compacted bool
 }
 
 func (lw *innerListWatch) List(op ListWatchConfig) (*registry.PluginResponse, error) {
@@ -49,12 +50,35 @@ func (lw *innerListWatch) setRevision(rev int64) {
 	lw.rev = rev
 }
 
+func (lw *innerListWatch) Compacted() bool {
+	return lw.compacted
+}
+
 func (lw *innerListWatch) Watch(op ListWatchConfig) Watcher {
 	return newInnerWatcher(lw, op)
 }
 
 func (lw *innerListWatch) DoWatch(ctx context.Context, f func(*registry.PluginResponse)) error {
+	err := feed.Subscribe(ctx, lw.Prefix, lw.Revision(), func(resp *registry.PluginResponse) {
+		if len(resp.Kvs) == 0 {
+			return
+		}
+		lw.setRevision(resp.Revision)
+		f(resp)
+	})
+	switch {
+	case err == nil || ctx.Err() != nil:
+		return nil
+	case err != ErrNoFeed:
+		log.Errorf(err, "subscribe the delta feed of prefix %s failed, watch it directly, rev: %d",
+			lw.Prefix, lw.Revision())
+	}
+	return lw.doWatchBackend(ctx, f)
+}
+
+func (lw *innerListWatch) doWatchBackend(ctx context.Context, f func(*registry.PluginResponse)) error {
 	rev := lw.Revision()
+	feed.Begin(lw.Prefix, rev)
 	opts := append(
 		registry.WatchPrefixOpOptions(lw.Prefix),
 		registry.WithRev(rev+1),
@@ -65,15 +89,22 @@ func (lw *innerListWatch) DoWatch(ctx context.Context, f func(*registry.PluginRe
 				}
 
 				lw.setRevision(resp.Revision)
+				feed.Publish(lw.Prefix, resp)
 
 				f(resp)
 				return nil
 			}))
 
 	err := lw.Client.Watch(ctx, opts...)
-	if err != nil { // compact可能会导致watch失败 or message body size lager than 4MB
+	lw.compacted = err == registry.ErrCompacted
+	switch {
+	case lw.compacted:
+		log.Warnf("watch prefix %s failed, start rev: %d+1->%d->0, the revision is compacted",
+			lw.Prefix, rev, lw.Revision())
+	case err != nil: // message body size lager than 4MB
 		log.Errorf(err, "watch prefix %s failed, start rev: %d+1->%d->0", lw.Prefix, rev, lw.Revision())
-
+	}
+	if err != nil {
 		lw.setRevision(0)
 		f(nil)
 	}
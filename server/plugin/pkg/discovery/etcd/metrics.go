@@ -18,7 +18,14 @@ package etcd
 
 import (
 	"github.com/apache/servicecomb-service-center/server/metric"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/discovery"
 	"github.com/prometheus/client_golang/prometheus"
+	"time"
+)
+
+const (
+	REBUILD_SUCCESS = "SUCCESS"
+	REBUILD_FAILURE = "FAILURE"
 )
 
 var (
@@ -29,10 +36,76 @@ var (
 			Name:      "cache_size_bytes",
 			Help:      "Local cache size summary of backend store",
 		}, []string{"instance", "resource", "type"})
+
+	rebuildCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metric.FamilyName,
+			Subsystem: "local",
+			Name:      "cache_rebuild_total",
+			Help:      "Counter of the local cache rebuilds by listing the backend store",
+		}, []string{"instance", "resource", "result"})
+
+	rebuildingGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metric.FamilyName,
+			Subsystem: "local",
+			Name:      "cache_rebuilding",
+			Help:      "Whether the local cache is rebuilding, 1 means in progress",
+		}, []string{"instance", "resource"})
+
+	rebuildItemsGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metric.FamilyName,
+			Subsystem: "local",
+			Name:      "cache_rebuild_items",
+			Help:      "Items loaded by the last local cache rebuild",
+		}, []string{"instance", "resource"})
+
+	rebuildDurationGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metric.FamilyName,
+			Subsystem: "local",
+			Name:      "cache_rebuild_duration_seconds",
+			Help:      "Duration of the last local cache rebuild",
+		}, []string{"instance", "resource"})
+
+	lockAcquiresCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metric.FamilyName,
+			Subsystem: "local",
+			Name:      "cache_lock_acquires_total",
+			Help:      "Counter of the locks acquired by accessing the local cache",
+		}, []string{"instance", "resource"})
+
+	lockWaitCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metric.FamilyName,
+			Subsystem: "local",
+			Name:      "cache_lock_wait_seconds_total",
+			Help:      "Total time spent in waiting for the locks of the local cache",
+		}, []string{"instance", "resource"})
+
+	missedEventsCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metric.FamilyName,
+			Subsystem: "local",
+			Name:      "cache_missed_events_total",
+			Help:      "Counter of the changes missed by watching and found by the rebuilds",
+		}, []string{"instance", "resource"})
+
+	compactedCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metric.FamilyName,
+			Subsystem: "local",
+			Name:      "cache_compacted_total",
+			Help:      "Counter of the local cache resyncs since the revisions watched are compacted",
+		}, []string{"instance", "resource"})
 )
 
 func init() {
-	prometheus.MustRegister(cacheSizeGauge)
+	prometheus.MustRegister(cacheSizeGauge, rebuildCounter, rebuildingGauge,
+		rebuildItemsGauge, rebuildDurationGauge, missedEventsCounter,
+		lockAcquiresCounter, lockWaitCounter, compactedCounter)
 }
 
 func ReportCacheSize(resource, t string, s int) {
@@ -45,3 +118,55 @@ func ReportCacheSize(resource, t string, s int) {
 
 	cacheSizeGauge.WithLabelValues(instance, resource, t).Set(float64(s))
 }
+
+// ReportCacheLock reports the increments of the lock contention statistics
+func ReportCacheLock(resource string, delta discovery.LockStats) {
+	instance := metric.InstanceName()
+	if len(instance) == 0 || len(resource) == 0 {
+		return
+	}
+
+	lockAcquiresCounter.WithLabelValues(instance, resource).Add(float64(delta.Acquires))
+	lockWaitCounter.WithLabelValues(instance, resource).Add(delta.Wait.Seconds())
+}
+
+func ReportRebuildStart(resource string) {
+	instance := metric.InstanceName()
+	if len(instance) == 0 || len(resource) == 0 {
+		return
+	}
+
+	rebuildingGauge.WithLabelValues(instance, resource).Set(1)
+}
+
+// ReportRebuildFinish reports the result of a rebuild, items and missed
+// are only meaningful when the rebuild succeeds
+func ReportRebuildFinish(resource string, items, missed int, d time.Duration, err error) {
+	instance := metric.InstanceName()
+	if len(instance) == 0 || len(resource) == 0 {
+		return
+	}
+
+	rebuildingGauge.WithLabelValues(instance, resource).Set(0)
+	if err != nil {
+		rebuildCounter.WithLabelValues(instance, resource, REBUILD_FAILURE).Inc()
+		return
+	}
+	rebuildCounter.WithLabelValues(instance, resource, REBUILD_SUCCESS).Inc()
+	rebuildItemsGauge.WithLabelValues(instance, resource).Set(float64(items))
+	rebuildDurationGauge.WithLabelValues(instance, resource).Set(d.Seconds())
+	if missed > 0 {
+		missedEventsCounter.WithLabelValues(instance, resource).Add(float64(missed))
+	}
+}
+
+// ReportCompacted reports the local cache is resynced since the revision
+// watched is compacted
+func ReportCompacted(resource string) {
+	instance := metric.InstanceName()
+	if len(instance) == 0 || len(resource) == 0 {
+		return
+	}
+
+	compactedCounter.WithLabelValues(instance, resource).Inc()
+}
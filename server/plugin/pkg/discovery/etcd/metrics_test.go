@@ -17,9 +17,11 @@
 package etcd
 
 import (
+	"errors"
 	"github.com/apache/servicecomb-service-center/server/metric"
 	"github.com/astaxie/beego"
 	"testing"
+	"time"
 )
 
 func init() {
@@ -42,3 +44,53 @@ func TestReportCacheSize(t *testing.T) {
 		t.Fatalf("TestReportCacheSize failed")
 	}
 }
+
+func TestReportRebuild(t *testing.T) {
+	ReportRebuildStart("a")
+	err := metric.Gatherer.Collect()
+	if err != nil {
+		t.Fatalf("TestReportRebuild failed, %v", err)
+	}
+	if metric.Gatherer.Records.Summary("local_cache_rebuilding") != 1 {
+		t.Fatalf("TestReportRebuild failed")
+	}
+
+	ReportRebuildFinish("a", 10, 2, time.Second, nil)
+	metric.Gatherer.Collect()
+	if metric.Gatherer.Records.Summary("local_cache_rebuilding") != 0 ||
+		metric.Gatherer.Records.Summary("local_cache_rebuild_items") != 10 ||
+		metric.Gatherer.Records.Summary("local_cache_rebuild_duration_seconds") != 1 ||
+		metric.Gatherer.Records.Summary("local_cache_missed_events_total") != 2 ||
+		metric.Gatherer.Records.Summary("local_cache_rebuild_total") != 1 {
+		t.Fatalf("TestReportRebuild failed")
+	}
+
+	ReportRebuildStart("a")
+	ReportRebuildFinish("a", 0, 0, time.Second, errors.New("error"))
+	metric.Gatherer.Collect()
+	if metric.Gatherer.Records.Summary("local_cache_rebuilding") != 0 ||
+		metric.Gatherer.Records.Summary("local_cache_rebuild_items") != 10 ||
+		metric.Gatherer.Records.Summary("local_cache_rebuild_total") != 2 {
+		t.Fatalf("TestReportRebuild failed")
+	}
+}
+
+func TestReportCompacted(t *testing.T) {
+	metric.Gatherer.Collect()
+	old := metric.Gatherer.Records.Summary("local_cache_compacted_total")
+
+	ReportCompacted("a")
+	err := metric.Gatherer.Collect()
+	if err != nil {
+		t.Fatalf("TestReportCompacted failed, %v", err)
+	}
+	if metric.Gatherer.Records.Summary("local_cache_compacted_total") != old+1 {
+		t.Fatalf("TestReportCompacted failed")
+	}
+
+	ReportCompacted("")
+	metric.Gatherer.Collect()
+	if metric.Gatherer.Records.Summary("local_cache_compacted_total") != old+1 {
+		t.Fatalf("TestReportCompacted failed")
+	}
+}
@@ -39,6 +39,7 @@ type mockListWatch struct {
 	Bus          chan *registry.PluginResponse
 	Watcher      Watcher
 	Rev          int64
+	Compact      bool
 }
 
 func (lw *mockListWatch) List(op ListWatchConfig) (*registry.PluginResponse, error) {
@@ -65,6 +66,11 @@ func (lw *mockListWatch) Watch(op ListWatchConfig) Watcher {
 func (lw *mockListWatch) Revision() int64 {
 	return lw.Rev
 }
+func (lw *mockListWatch) Compacted() bool {
+	c := lw.Compact
+	lw.Compact = false
+	return c
+}
 
 func TestInnerWatcher_EventBus(t *testing.T) {
 	w := newInnerWatcher(&mockListWatch{}, ListWatchConfig{Timeout: time.Second, Context: context.Background()})
@@ -33,6 +33,7 @@ import (
 
 const (
 	Name                 = "Kubernetes"
+	SourceType           = "k8s"
 	TypeService  K8sType = "Service"
 	TypeEndpoint K8sType = "Endpoints"
 	TypeNode     K8sType = "Node"
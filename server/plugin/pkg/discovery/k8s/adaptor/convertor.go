@@ -117,5 +117,8 @@ func AsKeyValue(key string, v interface{}, resourceVersion string) *discovery.Ke
 	kv.Key = util.StringToBytesWithNoCopy(key)
 	kv.Value = v
 	kv.ModRevision = rev
+	if r, ok := v.(pb.Revisioned); ok {
+		r.SetRevision(kv.CreateRevision, kv.ModRevision)
+	}
 	return kv
 }
@@ -20,6 +20,7 @@ import (
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/pkg/util"
 	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/discovery"
 	"golang.org/x/net/context"
 	"k8s.io/api/core/v1"
 	"k8s.io/client-go/informers"
@@ -63,6 +64,7 @@ func (c *K8sClient) init() {
 
 	// if KUBERNETES_CONFIG_PATH is unset, then service center must be deployed in the same k8s cluster
 	c.kubeClient, err = createKubeClient(os.Getenv("KUBERNETES_CONFIG_PATH"))
+	discovery.ReportSource(Name, SourceType, err)
 	if err != nil {
 		log.Errorf(err, "create kube client failed")
 		return
@@ -233,6 +235,7 @@ func (c *K8sClient) Run() {
 				return
 			case <-time.After(minWaitInterval):
 				util.SafeCloseChan(c.ready)
+				discovery.ReportSource(Name, SourceType, nil)
 			}
 		})
 }
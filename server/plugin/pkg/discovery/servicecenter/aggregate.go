@@ -53,6 +53,7 @@ func (c *SCClientAggregate) GetScCache(ctx context.Context) (*model.Cache, map[s
 	errs := make(map[string]error)
 	for _, client := range *c {
 		cache, err := client.GetScCache(ctx)
+		discovery.ReportSource(client.Cfg.Name, SourceType, err)
 		if err != nil {
 			errs[client.Cfg.Name] = err
 			continue
@@ -87,6 +88,9 @@ func (c *SCClientAggregate) cacheAppend(name string, setter model.Setter, getter
 func (c *SCClientAggregate) GetSchemasByServiceId(ctx context.Context, domainProject, serviceId string) (*discovery.Response, *scerr.Error) {
 	var response discovery.Response
 	for _, client := range *c {
+		if !discovery.IsSourceHealthy(client.Cfg.Name) {
+			continue
+		}
 		schemas, err := client.GetSchemasByServiceId(ctx, domainProject, serviceId)
 		if err != nil && err.InternalError() {
 			log.Errorf(err, "get schema by serviceId[%s/%s] failed", domainProject, serviceId)
@@ -112,6 +116,9 @@ func (c *SCClientAggregate) GetSchemasByServiceId(ctx context.Context, domainPro
 func (c *SCClientAggregate) GetSchemaBySchemaId(ctx context.Context, domainProject, serviceId, schemaId string) (*discovery.Response, *scerr.Error) {
 	var response discovery.Response
 	for _, client := range *c {
+		if !discovery.IsSourceHealthy(client.Cfg.Name) {
+			continue
+		}
 		schema, err := client.GetSchemaBySchemaId(ctx, domainProject, serviceId, schemaId)
 		if err != nil && err.InternalError() {
 			log.Errorf(err, "get schema by serviceId[%s/%s] failed", domainProject, serviceId)
@@ -133,8 +140,14 @@ func (c *SCClientAggregate) GetSchemaBySchemaId(ctx context.Context, domainProje
 }
 
 func (c *SCClientAggregate) GetInstancesByServiceId(ctx context.Context, domainProject, providerId, consumerId string) (*discovery.Response, *scerr.Error) {
-	var response discovery.Response
+	var (
+		response discovery.Response
+		exists   = make(map[string]struct{})
+	)
 	for _, client := range *c {
+		if !discovery.IsSourceHealthy(client.Cfg.Name) {
+			continue
+		}
 		insts, err := client.GetInstancesByServiceId(ctx, domainProject, providerId, consumerId)
 		if err != nil && err.InternalError() {
 			log.Errorf(err, "consumer[%s] get provider[%s/%s] instances failed", consumerId, domainProject, providerId)
@@ -143,8 +156,12 @@ func (c *SCClientAggregate) GetInstancesByServiceId(ctx context.Context, domainP
 		if insts == nil {
 			continue
 		}
-		response.Count = int64(len(insts))
 		for _, instance := range insts {
+			// the same instance may be registered in more than one cluster
+			if _, ok := exists[instance.InstanceId]; ok {
+				continue
+			}
+			exists[instance.InstanceId] = struct{}{}
 			response.Kvs = append(response.Kvs, &discovery.KeyValue{
 				Key:         []byte(core.GenerateInstanceKey(domainProject, providerId, instance.InstanceId)),
 				Value:       instance,
@@ -153,12 +170,16 @@ func (c *SCClientAggregate) GetInstancesByServiceId(ctx context.Context, domainP
 			})
 		}
 	}
+	response.Count = int64(len(response.Kvs))
 	return &response, nil
 }
 
 func (c *SCClientAggregate) GetInstanceByInstanceId(ctx context.Context, domainProject, providerId, instanceId, consumerId string) (*discovery.Response, *scerr.Error) {
 	var response discovery.Response
 	for _, client := range *c {
+		if !discovery.IsSourceHealthy(client.Cfg.Name) {
+			continue
+		}
 		instance, err := client.GetInstanceByInstanceId(ctx, domainProject, providerId, instanceId, consumerId)
 		if err != nil && err.InternalError() {
 			log.Errorf(err, "consumer[%s] get provider[%s/%s] instances failed", consumerId, domainProject, providerId)
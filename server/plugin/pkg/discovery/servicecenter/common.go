@@ -19,6 +19,9 @@ import "time"
 
 const (
 	minWaitInterval = 5 * time.Second
+	// SourceType is the type name of remote service center clusters
+	// reported to the aggregator
+	SourceType = "servicecenter"
 )
 
 var (
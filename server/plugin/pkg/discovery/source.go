@@ -0,0 +1,86 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package discovery
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// the number of continuous failures before a source is marked unhealthy
+const SourceUnhealthyThreshold = 3
+
+// SourceStatus is the health of one upstream registry, e.g. a remote
+// service center cluster or kubernetes, which the aggregator reads from
+type SourceStatus struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Healthy     bool   `json:"healthy"`
+	Failures    int    `json:"failures,omitempty"`
+	Error       string `json:"error,omitempty"`
+	LastCheck   int64  `json:"lastCheck,omitempty"`
+	LastSuccess int64  `json:"lastSuccess,omitempty"`
+}
+
+var (
+	sources    = make(map[string]*SourceStatus)
+	sourcesMux sync.RWMutex
+)
+
+// ReportSource records the result of the latest access to the source
+func ReportSource(name, t string, err error) {
+	now := time.Now().Unix()
+
+	sourcesMux.Lock()
+	s, ok := sources[name]
+	if !ok {
+		s = &SourceStatus{Name: name, Type: t, Healthy: true}
+		sources[name] = s
+	}
+	s.LastCheck = now
+	if err == nil {
+		s.Healthy, s.Failures, s.Error, s.LastSuccess = true, 0, "", now
+	} else {
+		s.Failures++
+		s.Error = err.Error()
+		s.Healthy = s.Failures < SourceUnhealthyThreshold
+	}
+	sourcesMux.Unlock()
+}
+
+// IsSourceHealthy returns false only if the source is reported unhealthy
+func IsSourceHealthy(name string) bool {
+	sourcesMux.RLock()
+	s, ok := sources[name]
+	healthy := !ok || s.Healthy
+	sourcesMux.RUnlock()
+	return healthy
+}
+
+// Sources returns the copies of all the reported sources sorted by name
+func Sources() []*SourceStatus {
+	sourcesMux.RLock()
+	l := make([]*SourceStatus, 0, len(sources))
+	for _, s := range sources {
+		cp := *s
+		l = append(l, &cp)
+	}
+	sourcesMux.RUnlock()
+	sort.Slice(l, func(i, j int) bool { return l[i].Name < l[j].Name })
+	return l
+}
@@ -0,0 +1,54 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package discovery
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestReportSource(t *testing.T) {
+	if !IsSourceHealthy("TestReportSource") {
+		t.Fatalf("TestReportSource failed")
+	}
+	err := errors.New("unavailable")
+	for i := 0; i < SourceUnhealthyThreshold-1; i++ {
+		ReportSource("TestReportSource", "test", err)
+	}
+	if !IsSourceHealthy("TestReportSource") {
+		t.Fatalf("TestReportSource failed")
+	}
+	ReportSource("TestReportSource", "test", err)
+	if IsSourceHealthy("TestReportSource") {
+		t.Fatalf("TestReportSource failed")
+	}
+
+	var found *SourceStatus
+	for _, s := range Sources() {
+		if s.Name == "TestReportSource" {
+			found = s
+		}
+	}
+	if found == nil || found.Healthy || found.Failures != SourceUnhealthyThreshold ||
+		found.Error != err.Error() || found.LastSuccess != 0 {
+		t.Fatalf("TestReportSource failed, %v", found)
+	}
+
+	ReportSource("TestReportSource", "test", nil)
+	if !IsSourceHealthy("TestReportSource") {
+		t.Fatalf("TestReportSource failed")
+	}
+}
@@ -0,0 +1,196 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package buildin
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/server/metric"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/quota"
+	"github.com/astaxie/beego"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/net/context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultAlertThresholds = "80,90"
+	alertWebhookTimeout    = 10 * time.Second
+)
+
+var (
+	alertCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metric.FamilyName,
+			Subsystem: "quota",
+			Name:      "alerts_total",
+			Help:      "Counter of the quota usage reaching the alert thresholds",
+		}, []string{"instance", "domain", "type", "threshold"})
+
+	alerter = NewAlerter(LoadAlertConfig())
+)
+
+func init() {
+	prometheus.MustRegister(alertCounter)
+}
+
+// AlertConfig decides when to warn that the quota is running out
+type AlertConfig struct {
+	// Thresholds are the ascending percents of the quota
+	Thresholds []int
+	// Webhook is the url the alerts posted to, optional
+	Webhook string
+}
+
+// LoadAlertConfig reads 'quota_alert_thresholds' and 'quota_alert_webhook'
+func LoadAlertConfig() AlertConfig {
+	return AlertConfig{
+		Thresholds: ParseThresholds(beego.AppConfig.DefaultString("quota_alert_thresholds", defaultAlertThresholds)),
+		Webhook:    beego.AppConfig.DefaultString("quota_alert_webhook", ""),
+	}
+}
+
+// ParseThresholds parses the comma separated percents, the values not in
+// range (0, 100] are ignored
+func ParseThresholds(s string) []int {
+	var thresholds []int
+	exists := make(map[int]struct{})
+	for _, v := range strings.Split(s, ",") {
+		t, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil || t <= 0 || t > 100 {
+			continue
+		}
+		if _, ok := exists[t]; ok {
+			continue
+		}
+		exists[t] = struct{}{}
+		thresholds = append(thresholds, t)
+	}
+	sort.Ints(thresholds)
+	return thresholds
+}
+
+// QuotaAlert is the body posted to the webhook
+type QuotaAlert struct {
+	Domain    string `json:"domain"`
+	ServiceId string `json:"serviceId,omitempty"`
+	Type      string `json:"type"`
+	Threshold int    `json:"threshold"`
+	Used      int64  `json:"used"`
+	Limit     int64  `json:"limit"`
+	Timestamp string `json:"timestamp"`
+}
+
+type Alerter struct {
+	Cfg AlertConfig
+
+	client *rest.URLClient
+}
+
+// Crossed returns the highest threshold crossed when the usage grows
+// from 'from' to 'to', 0 means none
+func (a *Alerter) Crossed(from, to, limit int64) int {
+	if limit <= 0 || to <= from {
+		return 0
+	}
+	crossed := 0
+	for _, t := range a.Cfg.Thresholds {
+		line := int64(t) * limit
+		if from*100 < line && to*100 >= line {
+			crossed = t
+		}
+	}
+	return crossed
+}
+
+// Check warns once the apply makes the usage cross a threshold, it
+// never blocks the apply
+func (a *Alerter) Check(ctx context.Context, res *quota.ApplyQuotaResource, used, limit int64) {
+	threshold := a.Crossed(used, used+res.QuotaSize, limit)
+	if threshold == 0 {
+		return
+	}
+	alert := &QuotaAlert{
+		Domain:    res.DomainProject,
+		ServiceId: res.ServiceId,
+		Type:      res.QuotaType.String(),
+		Threshold: threshold,
+		Used:      used + res.QuotaSize,
+		Limit:     limit,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+	log.Warnf("%s quota of '%s' reaches %d%%, used %d, max num is %d",
+		alert.Type, alert.Domain, threshold, alert.Used, limit)
+	alertCounter.WithLabelValues(metric.InstanceName(), alert.Domain, alert.Type,
+		strconv.Itoa(threshold)).Inc()
+
+	if len(a.Cfg.Webhook) == 0 || a.client == nil {
+		return
+	}
+	gopool.Go(func(_ context.Context) {
+		if err := a.notify(alert); err != nil {
+			log.Errorf(err, "post %s quota alert of '%s' to webhook failed", alert.Type, alert.Domain)
+		}
+	})
+}
+
+func (a *Alerter) notify(alert *QuotaAlert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), alertWebhookTimeout)
+	defer cancel()
+	headers := http.Header{}
+	headers.Set(rest.HEADER_CONTENT_TYPE, rest.CONTENT_TYPE_JSON)
+	resp, err := a.client.HttpDoWithContext(ctx, rest.HTTP_METHOD_POST, a.Cfg.Webhook, headers, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("webhook responds %s", resp.Status)
+	}
+	return nil
+}
+
+func NewAlerter(cfg AlertConfig) *Alerter {
+	a := &Alerter{Cfg: cfg}
+	if len(cfg.Webhook) == 0 {
+		return a
+	}
+	client, err := rest.GetURLClient(rest.URLClientOption{
+		Compressed:     true,
+		RequestTimeout: alertWebhookTimeout,
+	})
+	if err != nil {
+		log.Errorf(err, "create quota alert webhook client failed")
+		return a
+	}
+	a.client = client
+	return a
+}
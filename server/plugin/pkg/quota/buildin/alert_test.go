@@ -0,0 +1,88 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package buildin
+
+import (
+	"encoding/json"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/quota"
+	"golang.org/x/net/context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseThresholds(t *testing.T) {
+	ts := ParseThresholds("90, 80,x,0,101,80,100")
+	if !reflect.DeepEqual(ts, []int{80, 90, 100}) {
+		t.Fatalf("TestParseThresholds failed, %v", ts)
+	}
+	if ts := ParseThresholds(""); len(ts) != 0 {
+		t.Fatalf("TestParseThresholds failed, %v", ts)
+	}
+}
+
+func TestAlerter_Crossed(t *testing.T) {
+	a := NewAlerter(AlertConfig{Thresholds: []int{80, 90}})
+	cases := []struct {
+		from, to, limit int64
+		expect          int
+	}{
+		{0, 1, 10, 0},
+		{7, 8, 10, 80},
+		{8, 9, 10, 90},
+		{7, 10, 10, 90},
+		{9, 10, 10, 0},
+		{8, 8, 10, 0},
+		{0, 1, 0, 0},
+	}
+	for _, c := range cases {
+		if th := a.Crossed(c.from, c.to, c.limit); th != c.expect {
+			t.Fatalf("TestAlerter_Crossed failed, %v: %d", c, th)
+		}
+	}
+}
+
+func TestAlerter_Check(t *testing.T) {
+	ch := make(chan *QuotaAlert, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		alert := &QuotaAlert{}
+		json.NewDecoder(r.Body).Decode(alert)
+		ch <- alert
+	}))
+	defer server.Close()
+
+	a := NewAlerter(AlertConfig{Thresholds: []int{80}, Webhook: server.URL})
+	res := quota.NewApplyQuotaResource(quota.MicroServiceQuotaType, "default/default", "", 1)
+	a.Check(context.Background(), res, 7, 10)
+	select {
+	case alert := <-ch:
+		if alert.Domain != "default/default" || alert.Threshold != 80 || alert.Used != 8 || alert.Limit != 10 {
+			t.Fatalf("TestAlerter_Check failed, %v", alert)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("TestAlerter_Check failed, webhook is not called")
+	}
+
+	a.Check(context.Background(), res, 8, 10)
+	select {
+	case alert := <-ch:
+		t.Fatalf("TestAlerter_Check failed, %v", alert)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
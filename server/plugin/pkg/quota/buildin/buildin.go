@@ -28,6 +28,7 @@ func init() {
 }
 
 func New() mgr.PluginInstance {
+	loadQuotas()
 	InitConfigs()
 	log.Infof("quota init, service: %d, instance: %d, schema: %d/service, tag: %d/service, rule: %d/service",
 		quota.DefaultServiceQuota, quota.DefaultInstanceQuota,
@@ -45,6 +46,9 @@ func (q *BuildInQuota) Apply4Quotas(ctx context.Context, res *quota.ApplyQuotaRe
 		return df(ctx, res)
 	}
 
+	if ret := DomainQuotaCheck(ctx, res); ret != nil {
+		return ret
+	}
 	return CommonQuotaCheck(ctx, res, resourceQuota(res.QuotaType), resourceLimitHandler)
 }
 
@@ -56,3 +60,13 @@ func (q *BuildInQuota) RemandQuotas(ctx context.Context, quotaType quota.Resourc
 		return
 	}
 }
+
+// ReconcileUsage corrects the usage reported to the quota center, the
+// buildin quotas count the resources and need no reconciliation
+func (q *BuildInQuota) ReconcileUsage(ctx context.Context, usages []*quota.Usage) error {
+	df, ok := mgr.DynamicPluginFunc(mgr.QUOTA, "ReconcileUsage").(func(context.Context, []*quota.Usage) error)
+	if ok {
+		return df(ctx, usages)
+	}
+	return nil
+}
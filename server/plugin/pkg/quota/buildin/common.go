@@ -54,6 +54,7 @@ func CommonQuotaCheck(ctx context.Context, res *quota.ApplyQuotaResource,
 		log.Errorf(nil, mes)
 		return quota.NewApplyQuotaResult(nil, scerr.NewError(scerr.ErrNotEnoughQuota, mes))
 	}
+	alerter.Check(ctx, res, curNum, limitQuota)
 	return quota.NewApplyQuotaResult(nil, nil)
 }
 
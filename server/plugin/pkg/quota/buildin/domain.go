@@ -0,0 +1,79 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package buildin
+
+import (
+	"fmt"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/core/backend"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/discovery"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/quota"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	serviceUtil "github.com/apache/servicecomb-service-center/server/service/util"
+	"golang.org/x/net/context"
+	"strings"
+)
+
+// DomainQuotaCheck checks the quotas of the domain created explicitly,
+// the services and instances of all the projects in the domain are
+// counted, nil means no quota of the domain is exceeded
+func DomainQuotaCheck(ctx context.Context, res *quota.ApplyQuotaResource) *quota.ApplyQuotaResult {
+	var (
+		name    string
+		key     string
+		indexer discovery.Indexer
+	)
+	domain := res.DomainProject
+	if i := strings.Index(domain, core.SPLIT); i >= 0 {
+		domain = domain[:i]
+	}
+	switch res.QuotaType {
+	case quota.MicroServiceQuotaType:
+		name, key, indexer = "service", core.GetServiceRootKey(domain), backend.Store().Service()
+	case quota.MicroServiceInstanceQuotaType:
+		name, key, indexer = "instance", core.GetInstanceRootKey(domain), backend.Store().Instance()
+	default:
+		return nil
+	}
+
+	info, err := serviceUtil.GetDomainInfo(ctx, domain)
+	if err != nil {
+		log.Errorf(err, "%s quota check of domain '%s' failed", res.QuotaType, domain)
+		return quota.NewApplyQuotaResult(nil, scerr.NewError(scerr.ErrInternal, err.Error()))
+	}
+	if info == nil || info.Quotas[name] <= 0 {
+		return nil
+	}
+	limit := info.Quotas[name]
+	resp, err := indexer.Search(ctx,
+		registry.WithStrKey(key+core.SPLIT),
+		registry.WithPrefix(),
+		registry.WithCountOnly())
+	if err != nil {
+		log.Errorf(err, "%s quota check of domain '%s' failed", res.QuotaType, domain)
+		return quota.NewApplyQuotaResult(nil, scerr.NewError(scerr.ErrInternal, err.Error()))
+	}
+	if resp.Count+res.QuotaSize > limit {
+		mes := fmt.Sprintf("no quota of domain '%s' to create %s, max num is %d, curNum is %d, apply num is %d",
+			domain, res.QuotaType, limit, resp.Count, res.QuotaSize)
+		log.Errorf(nil, mes)
+		return quota.NewApplyQuotaResult(nil, scerr.NewError(scerr.ErrNotEnoughQuota, mes))
+	}
+	return nil
+}
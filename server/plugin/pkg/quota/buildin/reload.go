@@ -0,0 +1,73 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package buildin
+
+import (
+	"errors"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/quota"
+	"github.com/apache/servicecomb-service-center/server/reload"
+	"github.com/astaxie/beego"
+)
+
+var errInvalidThresholds = errors.New("has no threshold in range (0, 100]")
+
+// quotaOptions maps the config keys to the default quotas, the values
+// from env are used if the keys are not set
+var quotaOptions = map[string]*int{
+	"quota_service":  &quota.DefaultServiceQuota,
+	"quota_instance": &quota.DefaultInstanceQuota,
+	"quota_schema":   &quota.DefaultSchemaQuota,
+	"quota_tag":      &quota.DefaultTagQuota,
+	"quota_rule":     &quota.DefaultRuleQuota,
+}
+
+// loadQuotas reads the default quotas from config and makes them and
+// the alert config reloadable, the reloaded quotas apply to the next
+// quota checks
+func loadQuotas() {
+	for key, value := range quotaOptions {
+		key, value, def := key, value, *value
+		load := func(string) {
+			*value = beego.AppConfig.DefaultInt(key, def)
+			if *value <= 0 {
+				*value = def
+			}
+		}
+		load("")
+		reload.Register(reload.Option{
+			Key:      key,
+			Validate: reload.Int(1),
+			Apply: func(v string) {
+				load(v)
+				InitConfigs()
+			},
+		})
+	}
+
+	apply := func(string) {
+		alerter = NewAlerter(LoadAlertConfig())
+	}
+	reload.Register(reload.Option{Key: "quota_alert_thresholds", Validate: validateThresholds, Apply: apply})
+	reload.Register(reload.Option{Key: "quota_alert_webhook", Apply: apply})
+}
+
+func validateThresholds(s string) error {
+	if len(s) > 0 && len(ParseThresholds(s)) == 0 {
+		return errInvalidThresholds
+	}
+	return nil
+}
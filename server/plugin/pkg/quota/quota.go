@@ -54,6 +54,9 @@ type ApplyQuotaResult struct {
 	reporter QuotaReporter
 }
 
+// ReportUsedQuota reports the usage after the resources are created, the
+// usage missed if it fails or the server crashes before it is corrected
+// by the UsageReconciler only
 func (r *ApplyQuotaResult) ReportUsedQuota(ctx context.Context) error {
 	if r == nil || r.reporter == nil {
 		return nil
@@ -101,6 +104,21 @@ type QuotaReporter interface {
 	Close(ctx context.Context)
 }
 
+// Usage is the number of the resources of a type in a domain project
+type Usage struct {
+	DomainProject string
+	QuotaType     ResourceType
+	Used          int64
+}
+
+// UsageReconciler corrects the usage by the numbers of the resources
+// counted periodically, the usage is reported out of the transactions
+// creating the resources and it is the only correction of the drifts,
+// the domain projects absent in the usages have no services and instances
+type UsageReconciler interface {
+	ReconcileUsage(ctx context.Context, usages []*Usage) error
+}
+
 type ResourceType int
 
 func (r ResourceType) String() string {
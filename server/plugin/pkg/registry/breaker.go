@@ -0,0 +1,312 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package registry
+
+import (
+	errorsEx "github.com/apache/servicecomb-service-center/pkg/errors"
+	"golang.org/x/net/context"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	BREAKER_CLOSED BreakerState = iota
+	BREAKER_OPEN
+	BREAKER_HALF_OPEN
+)
+
+// the rejections are the internal errors, so the callers, e.g. the lease
+// renewal, regard them as transient rather than the key or lease not found
+var (
+	ErrCircuitOpen     = errorsEx.RaiseError("the circuit breaker of the registry is open")
+	ErrTooManyRequests = errorsEx.RaiseError("too many concurrent requests to the registry")
+)
+
+type BreakerState int
+
+func (s BreakerState) String() string {
+	switch s {
+	case BREAKER_CLOSED:
+		return "closed"
+	case BREAKER_OPEN:
+		return "open"
+	case BREAKER_HALF_OPEN:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// IsTimeout tells whether the request timed out waiting for the backend
+func IsTimeout(err error) bool {
+	if err == nil {
+		return false
+	}
+	return err == context.DeadlineExceeded ||
+		strings.Contains(err.Error(), context.DeadlineExceeded.Error())
+}
+
+// CircuitBreaker opens after the consecutive timeouts of the backend, the
+// requests fail fast while it is open, it lets one probe through after
+// the cooldown, and closes if the probe succeeds
+type CircuitBreaker struct {
+	// Failures is the consecutive timeouts to open the breaker
+	Failures int
+	// Cooldown is how long the breaker keeps open before probing
+	Cooldown      time.Duration
+	OnStateChange func(from, to BreakerState)
+
+	lock     sync.Mutex
+	state    BreakerState
+	timeouts int
+	openedAt time.Time
+	probing  bool
+}
+
+func (b *CircuitBreaker) State() BreakerState {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.state
+}
+
+// Allow returns ErrCircuitOpen if the request should fail fast, probe is
+// true if the request is the one probing the backend
+func (b *CircuitBreaker) Allow() (probe bool, err error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	switch b.state {
+	case BREAKER_OPEN:
+		if time.Since(b.openedAt) < b.Cooldown {
+			return false, ErrCircuitOpen
+		}
+		b.setState(BREAKER_HALF_OPEN)
+	case BREAKER_HALF_OPEN:
+		if b.probing {
+			return false, ErrCircuitOpen
+		}
+	default:
+		return false, nil
+	}
+	b.probing = true
+	return true, nil
+}
+
+// Done records the result of the request allowed
+func (b *CircuitBreaker) Done(probe bool, err error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if probe {
+		b.probing = false
+	}
+	if err == context.Canceled {
+		// tells nothing about the backend
+		return
+	}
+	timeout := IsTimeout(err)
+	switch b.state {
+	case BREAKER_CLOSED:
+		if !timeout {
+			b.timeouts = 0
+			return
+		}
+		b.timeouts++
+		if b.timeouts >= b.Failures {
+			b.open()
+		}
+	case BREAKER_HALF_OPEN:
+		if !probe {
+			return
+		}
+		if timeout {
+			b.open()
+			return
+		}
+		b.timeouts = 0
+		b.setState(BREAKER_CLOSED)
+	}
+}
+
+func (b *CircuitBreaker) open() {
+	b.openedAt = time.Now()
+	b.setState(BREAKER_OPEN)
+}
+
+func (b *CircuitBreaker) setState(state BreakerState) {
+	from := b.state
+	b.state = state
+	if b.OnStateChange != nil && from != state {
+		b.OnStateChange(from, state)
+	}
+}
+
+func NewCircuitBreaker(failures int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		Failures: failures,
+		Cooldown: cooldown,
+	}
+}
+
+// BreakerRegistry guards the requests to the registry with the circuit
+// breaker, and limits the concurrent requests, so that a slow backend
+// does not pile up the goroutines waiting on it, the watches are not
+// guarded
+type BreakerRegistry struct {
+	Registry
+	// Breaker is nil if the breaker is disabled
+	Breaker *CircuitBreaker
+	// Wait is how long a request waits for a free slot at most
+	Wait     time.Duration
+	OnReject func(err error)
+
+	slots chan struct{}
+}
+
+func (r *BreakerRegistry) do(ctx context.Context, f func() error) error {
+	probe := false
+	if r.Breaker != nil {
+		var err error
+		if probe, err = r.Breaker.Allow(); err != nil {
+			r.reject(err)
+			return err
+		}
+	}
+	if r.slots != nil {
+		timer := time.NewTimer(r.Wait)
+		select {
+		case r.slots <- struct{}{}:
+			timer.Stop()
+			defer func() { <-r.slots }()
+		case <-timer.C:
+			if r.Breaker != nil {
+				r.Breaker.Done(probe, context.Canceled)
+			}
+			r.reject(ErrTooManyRequests)
+			return ErrTooManyRequests
+		case <-ctx.Done():
+			timer.Stop()
+			if r.Breaker != nil {
+				r.Breaker.Done(probe, context.Canceled)
+			}
+			return ctx.Err()
+		}
+	}
+	err := f()
+	if r.Breaker != nil {
+		r.Breaker.Done(probe, err)
+	}
+	return err
+}
+
+func (r *BreakerRegistry) reject(err error) {
+	if r.OnReject != nil {
+		r.OnReject(err)
+	}
+}
+
+func (r *BreakerRegistry) PutNoOverride(ctx context.Context, opts ...PluginOpOption) (ok bool, err error) {
+	err = r.do(ctx, func() (err error) {
+		ok, err = r.Registry.PutNoOverride(ctx, opts...)
+		return
+	})
+	return
+}
+
+func (r *BreakerRegistry) Do(ctx context.Context, opts ...PluginOpOption) (resp *PluginResponse, err error) {
+	err = r.do(ctx, func() (err error) {
+		resp, err = r.Registry.Do(ctx, opts...)
+		return
+	})
+	return
+}
+
+func (r *BreakerRegistry) Txn(ctx context.Context, ops []PluginOp) (resp *PluginResponse, err error) {
+	err = r.do(ctx, func() (err error) {
+		resp, err = r.Registry.Txn(ctx, ops)
+		return
+	})
+	return
+}
+
+func (r *BreakerRegistry) TxnWithCmp(ctx context.Context, success []PluginOp, cmp []CompareOp, fail []PluginOp) (resp *PluginResponse, err error) {
+	err = r.do(ctx, func() (err error) {
+		resp, err = r.Registry.TxnWithCmp(ctx, success, cmp, fail)
+		return
+	})
+	return
+}
+
+func (r *BreakerRegistry) LeaseGrant(ctx context.Context, TTL int64) (leaseID int64, err error) {
+	err = r.do(ctx, func() (err error) {
+		leaseID, err = r.Registry.LeaseGrant(ctx, TTL)
+		return
+	})
+	return
+}
+
+func (r *BreakerRegistry) LeaseRenew(ctx context.Context, leaseID int64) (TTL int64, err error) {
+	err = r.do(ctx, func() (err error) {
+		TTL, err = r.Registry.LeaseRenew(ctx, leaseID)
+		return
+	})
+	return
+}
+
+// LeaseRenewBatch guards the batch renewals, ErrNotSupported is
+// returned if the registry does not renew the leases in batches
+func (r *BreakerRegistry) LeaseRenewBatch(ctx context.Context, leaseIDs []int64) (TTLs []int64, errs []error, err error) {
+	renewer, ok := r.Registry.(LeaseBatchRenewer)
+	if !ok {
+		return nil, nil, ErrNotSupported
+	}
+	err = r.do(ctx, func() (err error) {
+		TTLs, errs, err = renewer.LeaseRenewBatch(ctx, leaseIDs)
+		return
+	})
+	return
+}
+
+func (r *BreakerRegistry) LeaseRevoke(ctx context.Context, leaseID int64) error {
+	return r.do(ctx, func() error {
+		return r.Registry.LeaseRevoke(ctx, leaseID)
+	})
+}
+
+func (r *BreakerRegistry) Compact(ctx context.Context, reserve int64) error {
+	return r.do(ctx, func() error {
+		return r.Registry.Compact(ctx, reserve)
+	})
+}
+
+// NewBreakerRegistry guards the registry as configured, returns the
+// registry itself if neither the breaker nor the limit is configured
+func NewBreakerRegistry(r Registry, cfg *Config) Registry {
+	if cfg.BreakerFailures <= 0 && cfg.MaxConcurrency <= 0 {
+		return r
+	}
+	br := &BreakerRegistry{
+		Registry: r,
+		Wait:     cfg.RequestTimeOut,
+	}
+	if cfg.BreakerFailures > 0 {
+		br.Breaker = NewCircuitBreaker(cfg.BreakerFailures, cfg.BreakerCooldown)
+	}
+	if cfg.MaxConcurrency > 0 {
+		br.slots = make(chan struct{}, cfg.MaxConcurrency)
+	}
+	return br
+}
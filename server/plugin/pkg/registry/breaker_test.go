@@ -0,0 +1,142 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package registry
+
+import (
+	"errors"
+	"golang.org/x/net/context"
+	"testing"
+	"time"
+)
+
+type slowRegistry struct {
+	Registry
+	err   error
+	calls int
+}
+
+func (r *slowRegistry) Do(ctx context.Context, opts ...PluginOpOption) (*PluginResponse, error) {
+	r.calls++
+	if r.err != nil {
+		return nil, r.err
+	}
+	return &PluginResponse{Succeeded: true}, nil
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	var changes []BreakerState
+	b := NewCircuitBreaker(2, 50*time.Millisecond)
+	b.OnStateChange = func(_, to BreakerState) {
+		changes = append(changes, to)
+	}
+
+	probe, err := b.Allow()
+	if probe || err != nil {
+		t.Fatalf("TestCircuitBreaker failed, %v, %v", probe, err)
+	}
+	b.Done(probe, context.DeadlineExceeded)
+	b.Done(false, errors.New("key not found"))
+	b.Done(false, context.DeadlineExceeded)
+	if b.State() != BREAKER_CLOSED {
+		t.Fatalf("TestCircuitBreaker failed, %s", b.State())
+	}
+	b.Done(false, errors.New("rpc error: code = DeadlineExceeded desc = context deadline exceeded"))
+	if b.State() != BREAKER_OPEN {
+		t.Fatalf("TestCircuitBreaker failed, %s", b.State())
+	}
+	if _, err := b.Allow(); err != ErrCircuitOpen {
+		t.Fatalf("TestCircuitBreaker failed, %v", err)
+	}
+
+	// probe after the cooldown
+	time.Sleep(60 * time.Millisecond)
+	probe, err = b.Allow()
+	if !probe || err != nil || b.State() != BREAKER_HALF_OPEN {
+		t.Fatalf("TestCircuitBreaker failed, %v, %v, %s", probe, err, b.State())
+	}
+	if _, err := b.Allow(); err != ErrCircuitOpen {
+		t.Fatalf("TestCircuitBreaker failed, %v", err)
+	}
+	b.Done(probe, context.DeadlineExceeded)
+	if b.State() != BREAKER_OPEN {
+		t.Fatalf("TestCircuitBreaker failed, %s", b.State())
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	probe, _ = b.Allow()
+	// the requests allowed before opening do not close it
+	b.Done(false, nil)
+	if b.State() != BREAKER_HALF_OPEN {
+		t.Fatalf("TestCircuitBreaker failed, %s", b.State())
+	}
+	b.Done(probe, nil)
+	if b.State() != BREAKER_CLOSED {
+		t.Fatalf("TestCircuitBreaker failed, %s", b.State())
+	}
+
+	expected := []BreakerState{BREAKER_OPEN, BREAKER_HALF_OPEN, BREAKER_OPEN, BREAKER_HALF_OPEN, BREAKER_CLOSED}
+	if len(changes) != len(expected) {
+		t.Fatalf("TestCircuitBreaker failed, %v", changes)
+	}
+	for i := range expected {
+		if changes[i] != expected[i] {
+			t.Fatalf("TestCircuitBreaker failed, %v", changes)
+		}
+	}
+}
+
+func TestBreakerRegistry(t *testing.T) {
+	if r := NewBreakerRegistry(&slowRegistry{}, &Config{}); r == nil {
+		t.Fatalf("TestBreakerRegistry failed")
+	} else if _, ok := r.(*BreakerRegistry); ok {
+		t.Fatalf("TestBreakerRegistry failed")
+	}
+
+	inner := &slowRegistry{err: context.DeadlineExceeded}
+	r := NewBreakerRegistry(inner, &Config{
+		BreakerFailures: 1,
+		BreakerCooldown: time.Minute,
+		MaxConcurrency:  1,
+		RequestTimeOut:  10 * time.Millisecond,
+	}).(*BreakerRegistry)
+	var rejects []error
+	r.OnReject = func(err error) {
+		rejects = append(rejects, err)
+	}
+
+	if _, err := r.Do(context.Background()); err != context.DeadlineExceeded {
+		t.Fatalf("TestBreakerRegistry failed, %v", err)
+	}
+	if _, err := r.Do(context.Background()); err != ErrCircuitOpen || inner.calls != 1 {
+		t.Fatalf("TestBreakerRegistry failed, %v, %d", err, inner.calls)
+	}
+
+	// all slots are taken
+	r.Breaker = nil
+	r.slots <- struct{}{}
+	if _, err := r.Do(context.Background()); err != ErrTooManyRequests {
+		t.Fatalf("TestBreakerRegistry failed, %v", err)
+	}
+	<-r.slots
+	inner.err = nil
+	if resp, err := r.Do(context.Background()); err != nil || !resp.Succeeded {
+		t.Fatalf("TestBreakerRegistry failed, %v, %v", resp, err)
+	}
+	if len(rejects) != 2 || rejects[0] != ErrCircuitOpen || rejects[1] != ErrTooManyRequests {
+		t.Fatalf("TestBreakerRegistry failed, %v", rejects)
+	}
+}
@@ -58,6 +58,10 @@ const (
 	// the timeout dial to etcd
 	defaultDialTimeout    = 10 * time.Second
 	defaultRequestTimeout = 30 * time.Second
+	// the time the circuit breaker keeps open before probing
+	defaultBreakerCooldown = 10 * time.Second
+	// the max leases renewed in one batch
+	defaultLeaseBatchSize = 128
 
 	DefaultClusterName = "default"
 )
@@ -65,3 +69,22 @@ const (
 func WithTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
 	return context.WithTimeout(ctx, defaultRegistryConfig.RequestTimeOut)
 }
+
+func WithReadTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return withTimeout(ctx, defaultRegistryConfig.ReadTimeout)
+}
+
+func WithWriteTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return withTimeout(ctx, defaultRegistryConfig.WriteTimeout)
+}
+
+func WithLeaseTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return withTimeout(ctx, defaultRegistryConfig.LeaseTimeout)
+}
+
+func withTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if d <= 0 {
+		return WithTimeout(ctx)
+	}
+	return context.WithTimeout(ctx, d)
+}
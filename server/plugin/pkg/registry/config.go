@@ -38,6 +38,23 @@ type Config struct {
 	DialTimeout      time.Duration `json:"connectTimeout"`
 	RequestTimeOut   time.Duration `json:"registryTimeout"`
 	AutoSyncInterval time.Duration `json:"autoSyncInterval"`
+	// the timeouts of the reads, writes and lease operations, they are
+	// the RequestTimeOut if not configured
+	ReadTimeout  time.Duration `json:"readTimeout"`
+	WriteTimeout time.Duration `json:"writeTimeout"`
+	LeaseTimeout time.Duration `json:"leaseTimeout"`
+	// BreakerFailures is the consecutive timeouts to open the circuit
+	// breaker, 0 disables the breaker
+	BreakerFailures int           `json:"breakerFailures"`
+	BreakerCooldown time.Duration `json:"breakerCooldown"`
+	// MaxConcurrency limits the concurrent requests, 0 is unlimited
+	MaxConcurrency int `json:"maxConcurrency"`
+	// Connections is the number of the connections to the backend
+	Connections int `json:"connections"`
+	// the lease renewals in the window are sent in one batch of the size
+	// at most, 0 window disables the batching
+	LeaseBatchWindow time.Duration `json:"leaseBatchWindow"`
+	LeaseBatchSize   int           `json:"leaseBatchSize"`
 }
 
 func (c *Config) InitClusters() {
@@ -106,6 +123,34 @@ func Configuration() *Config {
 		if err != nil {
 			log.Errorf(err, "auto_sync_interval is invalid")
 		}
+		defaultRegistryConfig.ReadTimeout = parseTimeout("registry_read_timeout", defaultRegistryConfig.RequestTimeOut)
+		defaultRegistryConfig.WriteTimeout = parseTimeout("registry_write_timeout", defaultRegistryConfig.RequestTimeOut)
+		defaultRegistryConfig.LeaseTimeout = parseTimeout("registry_lease_timeout", defaultRegistryConfig.RequestTimeOut)
+		defaultRegistryConfig.BreakerFailures = beego.AppConfig.DefaultInt("registry_breaker_failures", 0)
+		defaultRegistryConfig.BreakerCooldown = parseTimeout("registry_breaker_cooldown", defaultBreakerCooldown)
+		defaultRegistryConfig.MaxConcurrency = beego.AppConfig.DefaultInt("registry_max_concurrency", 0)
+		defaultRegistryConfig.Connections = beego.AppConfig.DefaultInt("registry_connections", 1)
+		if defaultRegistryConfig.Connections < 1 {
+			defaultRegistryConfig.Connections = 1
+		}
+		defaultRegistryConfig.LeaseBatchWindow = parseTimeout("registry_lease_batch_window", 0)
+		defaultRegistryConfig.LeaseBatchSize = beego.AppConfig.DefaultInt("registry_lease_batch_size", defaultLeaseBatchSize)
+		if defaultRegistryConfig.LeaseBatchSize < 1 {
+			defaultRegistryConfig.LeaseBatchSize = defaultLeaseBatchSize
+		}
 	})
 	return &defaultRegistryConfig
 }
+
+func parseTimeout(key string, def time.Duration) time.Duration {
+	v := beego.AppConfig.String(key)
+	if len(v) == 0 {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		log.Errorf(err, "%s is invalid, use default time %s", key, def)
+		return def
+	}
+	return d
+}
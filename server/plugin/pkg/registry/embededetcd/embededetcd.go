@@ -292,6 +292,9 @@ func (s *EtcdEmbed) Do(ctx context.Context, opts ...registry.PluginOpOption) (*r
 		resp = &registry.PluginResponse{
 			Revision: etcdResp.Header.Revision,
 		}
+		if etcdResp.PrevKv != nil {
+			resp.Kvs = []*mvccpb.KeyValue{etcdResp.PrevKv}
+		}
 	case registry.Delete:
 		var etcdResp *etcdserverpb.DeleteRangeResponse
 		etcdResp, err = s.Embed.Server.DeleteRange(otCtx, s.toDeleteRequest(op))
@@ -299,6 +302,8 @@ func (s *EtcdEmbed) Do(ctx context.Context, opts ...registry.PluginOpOption) (*r
 			break
 		}
 		resp = &registry.PluginResponse{
+			Kvs:      etcdResp.PrevKvs,
+			Count:    etcdResp.Deleted,
 			Revision: etcdResp.Header.Revision,
 		}
 	}
@@ -428,6 +433,12 @@ func (s *EtcdEmbed) Watch(ctx context.Context, opts ...registry.PluginOpOption)
 					err = errors.New("channel is closed")
 					return
 				}
+				if resp.CompactRevision != 0 {
+					log.Warnf("watch key %s failed, the revision is compacted to %d",
+						key, resp.CompactRevision)
+					err = registry.ErrCompacted
+					return
+				}
 
 				err = dispatch(resp.Events, op.WatchCallback)
 				if err != nil {
@@ -26,6 +26,7 @@ import (
 	"github.com/apache/servicecomb-service-center/pkg/util"
 	mgr "github.com/apache/servicecomb-service-center/server/plugin"
 	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/tracing"
 	"github.com/coreos/etcd/clientv3"
 	"github.com/coreos/etcd/etcdserver/api/v3rpc/rpctypes"
 	"github.com/coreos/etcd/etcdserver/etcdserverpb"
@@ -35,6 +36,7 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -59,7 +61,13 @@ type EtcdClient struct {
 	DialTimeout      time.Duration
 	TLSConfig        *tls.Config
 	AutoSyncInterval time.Duration
+	// Connections is the number of the connections to the backend, the
+	// kv requests are balanced over them
+	Connections int
 
+	// conns are the connections besides the Client
+	conns     []*clientv3.Client
+	next      uint32
 	err       chan error
 	ready     chan struct{}
 	goroutine *gopool.Pool
@@ -89,12 +97,21 @@ func (c *EtcdClient) Initialize() (err error) {
 	if c.AutoSyncInterval == 0 {
 		c.AutoSyncInterval = registry.Configuration().AutoSyncInterval
 	}
+	if c.Connections == 0 {
+		c.Connections = registry.Configuration().Connections
+	}
 
 	c.Client, err = c.newClient()
 	if err != nil {
 		log.Errorf(err, "get etcd client %v failed.", c.Endpoints)
 		return
 	}
+	c.conns, err = c.newConns()
+	if err != nil {
+		log.Errorf(err, "get %d etcd connections %v failed.", c.Connections, c.Endpoints)
+		c.Client.Close()
+		return
+	}
 
 	c.HealthCheck()
 
@@ -158,6 +175,41 @@ epLoop:
 	return client, nil
 }
 
+// newConns returns the connections besides the Client
+func (c *EtcdClient) newConns() ([]*clientv3.Client, error) {
+	var conns []*clientv3.Client
+	for i := 1; i < c.Connections; i++ {
+		client, err := c.newClient()
+		if err != nil {
+			closeConns(conns)
+			return nil, err
+		}
+		conns = append(conns, client)
+	}
+	return conns, nil
+}
+
+func closeConns(conns []*clientv3.Client) {
+	for _, client := range conns {
+		if err := client.Close(); err != nil {
+			log.Errorf(err, "failed to close the etcd connection")
+		}
+	}
+}
+
+// kv returns the connection to send the kv request, round robin
+func (c *EtcdClient) kv() *clientv3.Client {
+	conns := c.conns
+	if len(conns) == 0 {
+		return c.Client
+	}
+	i := atomic.AddUint32(&c.next, 1) % uint32(len(conns)+1)
+	if i == 0 {
+		return c.Client
+	}
+	return conns[i-1]
+}
+
 func (c *EtcdClient) Err() <-chan error {
 	return c.err
 }
@@ -172,6 +224,7 @@ func (c *EtcdClient) Close() {
 	if c.Client != nil {
 		c.Client.Close()
 	}
+	closeConns(c.conns)
 	log.Debugf("etcd client stopped")
 }
 
@@ -351,13 +404,14 @@ func (c *EtcdClient) PutNoOverride(ctx context.Context, opts ...registry.PluginO
 }
 
 func (c *EtcdClient) paging(ctx context.Context, op registry.PluginOp) (*clientv3.GetResponse, error) {
+	kv := c.kv()
 	var etcdResp *clientv3.GetResponse
 	key := util.BytesToStringWithNoCopy(op.Key)
 
 	start := time.Now()
 	tempOp := op
 	tempOp.CountOnly = true
-	countResp, err := c.Client.Get(ctx, key, c.toGetRequest(tempOp)...)
+	countResp, err := kv.Get(ctx, key, c.toGetRequest(tempOp)...)
 	if err != nil {
 		return nil, err
 	}
@@ -412,7 +466,7 @@ func (c *EtcdClient) paging(ctx context.Context, op registry.PluginOp) (*clientv
 			start = 1
 		}
 		ops := append(baseOps, clientv3.WithLimit(int64(limit)))
-		recordResp, err := c.Client.Get(ctx, nextKey, ops...)
+		recordResp, err := kv.Get(ctx, nextKey, ops...)
 		if err != nil {
 			return nil, err
 		}
@@ -457,9 +511,21 @@ func (c *EtcdClient) Do(ctx context.Context, opts ...registry.PluginOpOption) (*
 	op := registry.OptionsToOp(opts...)
 
 	span := TracingBegin(ctx, "etcd:do", op)
-	defer TracingEnd(span, err)
+	TracingTags(span, opTags(op))
+	defer func() {
+		TracingTags(span, respTags(resp))
+		TracingEnd(span, err)
+	}()
 
-	otCtx, cancel := registry.WithTimeout(ctx)
+	var (
+		otCtx  context.Context
+		cancel context.CancelFunc
+	)
+	if op.Action == registry.Get {
+		otCtx, cancel = registry.WithReadTimeout(ctx)
+	} else {
+		otCtx, cancel = registry.WithWriteTimeout(ctx)
+	}
 	defer cancel()
 
 	switch op.Action {
@@ -475,7 +541,7 @@ func (c *EtcdClient) Do(ctx context.Context, opts ...registry.PluginOpOption) (*
 		}
 
 		if etcdResp == nil {
-			etcdResp, err = c.Client.Get(otCtx, key, c.toGetRequest(op)...)
+			etcdResp, err = c.kv().Get(otCtx, key, c.toGetRequest(op)...)
 			if err != nil {
 				break
 			}
@@ -492,20 +558,25 @@ func (c *EtcdClient) Do(ctx context.Context, opts ...registry.PluginOpOption) (*
 			value = util.BytesToStringWithNoCopy(op.Value)
 		}
 		var etcdResp *clientv3.PutResponse
-		etcdResp, err = c.Client.Put(otCtx, util.BytesToStringWithNoCopy(op.Key), value, c.toPutRequest(op)...)
+		etcdResp, err = c.kv().Put(otCtx, util.BytesToStringWithNoCopy(op.Key), value, c.toPutRequest(op)...)
 		if err != nil {
 			break
 		}
 		resp = &registry.PluginResponse{
 			Revision: etcdResp.Header.Revision,
 		}
+		if etcdResp.PrevKv != nil {
+			resp.Kvs = []*mvccpb.KeyValue{etcdResp.PrevKv}
+		}
 	case registry.Delete:
 		var etcdResp *clientv3.DeleteResponse
-		etcdResp, err = c.Client.Delete(otCtx, util.BytesToStringWithNoCopy(op.Key), c.toDeleteRequest(op)...)
+		etcdResp, err = c.kv().Delete(otCtx, util.BytesToStringWithNoCopy(op.Key), c.toDeleteRequest(op)...)
 		if err != nil {
 			break
 		}
 		resp = &registry.PluginResponse{
+			Kvs:      etcdResp.PrevKvs,
+			Count:    etcdResp.Deleted,
 			Revision: etcdResp.Header.Revision,
 		}
 	}
@@ -532,9 +603,12 @@ func (c *EtcdClient) Txn(ctx context.Context, opts []registry.PluginOp) (*regist
 }
 
 func (c *EtcdClient) TxnWithCmp(ctx context.Context, success []registry.PluginOp, cmps []registry.CompareOp, fail []registry.PluginOp) (*registry.PluginResponse, error) {
-	var err error
+	var (
+		err        error
+		pluginResp *registry.PluginResponse
+	)
 
-	otCtx, cancel := registry.WithTimeout(ctx)
+	otCtx, cancel := registry.WithWriteTimeout(ctx)
 	defer cancel()
 
 	start := time.Now()
@@ -550,9 +624,16 @@ func (c *EtcdClient) TxnWithCmp(ctx context.Context, success []registry.PluginOp
 	}
 
 	span := TracingBegin(ctx, "etcd:txn", traceOps[0])
-	defer TracingEnd(span, err)
+	tags := opTags(traceOps[0])
+	tags["etcd.ops"] = len(traceOps)
+	tags["etcd.cmps"] = len(cmps)
+	TracingTags(span, tags)
+	defer func() {
+		TracingTags(span, respTags(pluginResp))
+		TracingEnd(span, err)
+	}()
 
-	kvc := clientv3.NewKV(c.Client)
+	kvc := clientv3.NewKV(c.kv())
 	txn := kvc.Txn(otCtx)
 	if len(etcdCmps) > 0 {
 		txn.If(etcdCmps...)
@@ -577,27 +658,30 @@ func (c *EtcdClient) TxnWithCmp(ctx context.Context, success []registry.PluginOp
 		}
 	}
 
-	return &registry.PluginResponse{
+	pluginResp = &registry.PluginResponse{
 		Succeeded: resp.Succeeded,
 		Revision:  resp.Header.Revision,
 		Kvs:       rangeResponse.Kvs,
 		Count:     rangeResponse.Count,
-	}, nil
+	}
+	return pluginResp, nil
 }
 
 func (c *EtcdClient) LeaseGrant(ctx context.Context, TTL int64) (int64, error) {
 	var err error
 	span := TracingBegin(ctx, "etcd:grant",
 		registry.PluginOp{Action: registry.Put, Key: util.StringToBytesWithNoCopy(strconv.FormatInt(TTL, 10))})
-	defer TracingEnd(span, err)
+	TracingTags(span, tracing.Tags{"etcd.ttl": TTL})
+	defer func() { TracingEnd(span, err) }()
 
-	otCtx, cancel := registry.WithTimeout(ctx)
+	otCtx, cancel := registry.WithLeaseTimeout(ctx)
 	defer cancel()
 	start := time.Now()
 	etcdResp, err := c.Client.Grant(otCtx, TTL)
 	if err != nil {
 		return 0, err
 	}
+	TracingTags(span, tracing.Tags{"etcd.lease_id": int64(etcdResp.ID)})
 	log.LogNilOrWarnf(start, "registry client grant lease %ds", TTL)
 	return int64(etcdResp.ID), nil
 }
@@ -606,9 +690,10 @@ func (c *EtcdClient) LeaseRenew(ctx context.Context, leaseID int64) (int64, erro
 	var err error
 	span := TracingBegin(ctx, "etcd:keepalive",
 		registry.PluginOp{Action: registry.Put, Key: util.StringToBytesWithNoCopy(strconv.FormatInt(leaseID, 10))})
-	defer TracingEnd(span, err)
+	TracingTags(span, tracing.Tags{"etcd.lease_id": leaseID})
+	defer func() { TracingEnd(span, err) }()
 
-	otCtx, cancel := registry.WithTimeout(ctx)
+	otCtx, cancel := registry.WithLeaseTimeout(ctx)
 	defer cancel()
 	start := time.Now()
 	etcdResp, err := c.Client.KeepAliveOnce(otCtx, clientv3.LeaseID(leaseID))
@@ -622,13 +707,56 @@ func (c *EtcdClient) LeaseRenew(ctx context.Context, leaseID int64) (int64, erro
 	return etcdResp.TTL, nil
 }
 
+// LeaseRenewBatch renews the leases by one keepalive stream, all the
+// requests are sent at once and the responses are matched by lease id
+func (c *EtcdClient) LeaseRenewBatch(ctx context.Context, leaseIDs []int64) ([]int64, []error, error) {
+	var err error
+	span := TracingBegin(ctx, "etcd:keepalive_batch",
+		registry.PluginOp{Action: registry.Put, Key: util.StringToBytesWithNoCopy(strconv.Itoa(len(leaseIDs)))})
+	TracingTags(span, tracing.Tags{"etcd.leases": len(leaseIDs)})
+	defer func() { TracingEnd(span, err) }()
+
+	otCtx, cancel := registry.WithLeaseTimeout(ctx)
+	defer cancel()
+	start := time.Now()
+	stream, err := etcdserverpb.NewLeaseClient(c.Client.ActiveConnection()).LeaseKeepAlive(otCtx)
+	if err != nil {
+		return nil, nil, errorsEx.RaiseError(err)
+	}
+	defer stream.CloseSend()
+	for _, leaseID := range leaseIDs {
+		if err = stream.Send(&etcdserverpb.LeaseKeepAliveRequest{ID: leaseID}); err != nil {
+			return nil, nil, errorsEx.RaiseError(err)
+		}
+	}
+	received := make(map[int64]int64, len(leaseIDs))
+	for len(received) < len(leaseIDs) {
+		var resp *etcdserverpb.LeaseKeepAliveResponse
+		if resp, err = stream.Recv(); err != nil {
+			return nil, nil, errorsEx.RaiseError(err)
+		}
+		received[resp.ID] = resp.TTL
+	}
+
+	TTLs, errs := make([]int64, len(leaseIDs)), make([]error, len(leaseIDs))
+	for i, leaseID := range leaseIDs {
+		// the TTL is 0 if the lease is not found
+		if TTLs[i] = received[leaseID]; TTLs[i] <= 0 {
+			errs[i] = rpctypes.ErrGRPCLeaseNotFound
+		}
+	}
+	log.LogNilOrWarnf(start, "registry client renew %d leases", len(leaseIDs))
+	return TTLs, errs, nil
+}
+
 func (c *EtcdClient) LeaseRevoke(ctx context.Context, leaseID int64) error {
 	var err error
 	span := TracingBegin(ctx, "etcd:revoke",
 		registry.PluginOp{Action: registry.Delete, Key: util.StringToBytesWithNoCopy(strconv.FormatInt(leaseID, 10))})
-	defer TracingEnd(span, err)
+	TracingTags(span, tracing.Tags{"etcd.lease_id": leaseID})
+	defer func() { TracingEnd(span, err) }()
 
-	otCtx, cancel := registry.WithTimeout(ctx)
+	otCtx, cancel := registry.WithLeaseTimeout(ctx)
 	defer cancel()
 	start := time.Now()
 	_, err = c.Client.Revoke(otCtx, clientv3.LeaseID(leaseID))
@@ -672,6 +800,11 @@ func (c *EtcdClient) Watch(ctx context.Context, opts ...registry.PluginOpOption)
 				}
 				// cause a rpc ResourceExhausted error if watch response body larger then 4MB
 				if err = resp.Err(); err != nil {
+					if resp.CompactRevision != 0 {
+						log.Warnf("watch key %s failed, the revision is compacted to %d",
+							key, resp.CompactRevision)
+						err = registry.ErrCompacted
+					}
 					return
 				}
 
@@ -733,10 +866,19 @@ func (c *EtcdClient) ReOpen() error {
 			c.Endpoints)
 		return cerr
 	}
+	conns, cerr := c.newConns()
+	if cerr != nil {
+		log.Errorf(cerr, "create %d new connections to etcd %v failed",
+			c.Connections, c.Endpoints)
+		client.Close()
+		return cerr
+	}
 	c.Client, client = client, c.Client
+	c.conns, conns = conns, c.conns
 	if cerr = client.Close(); cerr != nil {
 		log.Errorf(cerr, "failed to close the unavailable etcd client")
 	}
+	closeConns(conns)
 	client = nil
 	return nil
 }
@@ -17,13 +17,18 @@
 package etcd
 
 import (
+	"github.com/apache/servicecomb-service-center/pkg/util"
 	"github.com/apache/servicecomb-service-center/server/plugin"
 	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
 	"github.com/apache/servicecomb-service-center/server/plugin/pkg/tracing"
+	"github.com/coreos/etcd/mvcc/mvccpb"
 	"golang.org/x/net/context"
 	"net/http"
 )
 
+// the levels of the key tagged, e.g. '/cse-sr/inst/files'
+const keyPrefixLevel = 3
+
 func TracingBegin(ctx context.Context, operationName string, op registry.PluginOp) tracing.Span {
 	r := &tracing.RegistryRequest{
 		Ctx:      ctx,
@@ -40,3 +45,55 @@ func TracingEnd(span tracing.Span, err error) {
 	}
 	plugin.Plugins().Tracing().ClientEnd(span, http.StatusOK, "")
 }
+
+// TracingTags attaches the tags to the span if the tracing supports
+func TracingTags(span tracing.Span, tags tracing.Tags) {
+	if span == nil || len(tags) == 0 {
+		return
+	}
+	if t, ok := plugin.Plugins().Tracing().(tracing.SpanTagger); ok {
+		t.SetTags(span, tags)
+	}
+}
+
+// keyPrefix returns the key prefix which tells the resource type
+func keyPrefix(key []byte) string {
+	k := util.BytesToStringWithNoCopy(key)
+	level := 0
+	for i := 1; i < len(k); i++ {
+		if k[i] != '/' {
+			continue
+		}
+		if level++; level == keyPrefixLevel {
+			return k[:i]
+		}
+	}
+	return k
+}
+
+func opTags(op registry.PluginOp) tracing.Tags {
+	return tracing.Tags{
+		"etcd.key_prefix": keyPrefix(op.Key),
+		"etcd.prefix":     op.Prefix,
+	}
+}
+
+func kvsSize(kvs []*mvccpb.KeyValue) (size int) {
+	for _, kv := range kvs {
+		size += len(kv.Key) + len(kv.Value)
+	}
+	return
+}
+
+func respTags(resp *registry.PluginResponse) tracing.Tags {
+	if resp == nil {
+		return nil
+	}
+	return tracing.Tags{
+		"etcd.count":     resp.Count,
+		"etcd.kvs":       len(resp.Kvs),
+		"etcd.kvs_bytes": kvsSize(resp.Kvs),
+		"etcd.revision":  resp.Revision,
+		"etcd.succeeded": resp.Succeeded,
+	}
+}
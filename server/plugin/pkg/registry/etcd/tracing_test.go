@@ -0,0 +1,53 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package etcd
+
+import (
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	"github.com/coreos/etcd/mvcc/mvccpb"
+	"testing"
+)
+
+func TestKeyPrefix(t *testing.T) {
+	cases := map[string]string{
+		"":                           "",
+		"/":                          "/",
+		"/cse-sr":                    "/cse-sr",
+		"/cse-sr/inst/files":         "/cse-sr/inst/files",
+		"/cse-sr/inst/files/":        "/cse-sr/inst/files",
+		"/cse-sr/inst/files/a/b/c/d": "/cse-sr/inst/files",
+	}
+	for key, expect := range cases {
+		if p := keyPrefix([]byte(key)); p != expect {
+			t.Fatalf("TestKeyPrefix failed, %s: %s", key, p)
+		}
+	}
+}
+
+func TestRespTags(t *testing.T) {
+	if respTags(nil) != nil {
+		t.Fatalf("TestRespTags failed")
+	}
+	tags := respTags(&registry.PluginResponse{
+		Kvs:      []*mvccpb.KeyValue{{Key: []byte("a"), Value: []byte("bc")}},
+		Count:    1,
+		Revision: 2,
+	})
+	if tags["etcd.kvs"] != 1 || tags["etcd.kvs_bytes"] != 3 || tags["etcd.revision"] != int64(2) {
+		t.Fatalf("TestRespTags failed, %v", tags)
+	}
+}
@@ -17,9 +17,16 @@
 package registry
 
 import (
+	"errors"
 	"golang.org/x/net/context"
 )
 
+var ErrNotSupported = errors.New("the operation is not supported by the registry")
+
+// ErrCompacted means the revision to watch from has been compacted by the
+// registry, the watcher should re-list and watch from the current revision
+var ErrCompacted = errors.New("the required revision has been compacted")
+
 type Registry interface {
 	Err() <-chan error
 	Ready() <-chan struct{}
@@ -39,3 +46,10 @@ type Registry interface {
 	Compact(ctx context.Context, reserve int64) error
 	Close()
 }
+
+// LeaseBatchRenewer is the registry renewing the leases in batches, err
+// is not nil if the whole batch fails, otherwise the TTLs and the errors
+// are in the order of the lease ids, the ids must be unique
+type LeaseBatchRenewer interface {
+	LeaseRenewBatch(ctx context.Context, leaseIDs []int64) (TTLs []int64, errs []error, err error)
+}
@@ -70,6 +70,7 @@ func (zp *Zipkin) ServerBegin(operationName string, itf tracing.Request) tracing
 		span.SetTag("protocol", "HTTP")
 		span.SetTag(zipkincore.HTTP_PATH, r.URL.Path)
 		span.SetTag(zipkincore.HTTP_HOST, r.URL.Host)
+		setRequestIdTag(span, ctx)
 	default:
 		// grpc?
 		return nil
@@ -138,6 +139,7 @@ func (zp *Zipkin) ClientBegin(operationName string, itf tracing.Request) tracing
 		span.SetTag("protocol", "gRPC")
 		span.SetTag(zipkincore.HTTP_PATH, u.Path)
 		span.SetTag(zipkincore.HTTP_HOST, u.Host)
+		setRequestIdTag(span, ctx)
 
 		carrier := opentracing.HTTPHeadersCarrier{}
 		if err := ZipkinTracer().Inject(
@@ -168,6 +170,23 @@ func (zp *Zipkin) ClientEnd(itf tracing.Span, code int, message string) {
 	span.Finish()
 }
 
+func (zp *Zipkin) SetTags(itf tracing.Span, tags tracing.Tags) {
+	span, ok := itf.(opentracing.Span)
+	if !ok {
+		return
+	}
+	for k, v := range tags {
+		span.SetTag(k, v)
+	}
+}
+
+// setRequestIdTag correlates the span with the log lines of the request
+func setRequestIdTag(span opentracing.Span, ctx context.Context) {
+	if id := util.ParseRequestId(ctx); len(id) > 0 {
+		span.SetTag("requestId", id)
+	}
+}
+
 func setResultTags(span opentracing.Span, code int, message string) {
 	if code >= http.StatusBadRequest {
 		span.SetTag("error", message)
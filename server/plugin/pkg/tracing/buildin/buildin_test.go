@@ -70,6 +70,8 @@ func TestZipkin_XBegin(t *testing.T) {
 		t.Fatalf("TestZipkin_XBegin failed")
 	}
 
+	zk.SetTags(span, tracing.Tags{"x": 1})
+	zk.SetTags(nil, tracing.Tags{"x": 1})
 	zk.ClientEnd(span, 0, "")
 	zk.ClientEnd(span, 400, "")
 }
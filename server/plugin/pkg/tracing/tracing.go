@@ -33,6 +33,15 @@ type Tracing interface {
 	ClientEnd(span Span, code int, message string)
 }
 
+// Tags are the attributes attached to the span
+type Tags map[string]interface{}
+
+// SpanTagger is implemented by the tracing which supports attaching
+// attributes to the span after it began
+type SpanTagger interface {
+	SetTags(span Span, tags Tags)
+}
+
 type RegistryRequest struct {
 	Ctx      context.Context
 	Endpoint string
@@ -0,0 +1,142 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package rules
+
+import (
+	"fmt"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	mgr "github.com/apache/servicecomb-service-center/server/plugin"
+	"github.com/astaxie/beego"
+	"regexp"
+	"strings"
+)
+
+func init() {
+	mgr.RegisterPlugin(mgr.Plugin{mgr.VALIDATOR, "rules", New})
+}
+
+func New() mgr.PluginInstance {
+	return &RulesValidator{
+		ServiceName:        compile("validator_service_name_pattern"),
+		AppId:              compile("validator_app_id_pattern"),
+		RequiredProperties: split(beego.AppConfig.String("validator_required_properties")),
+		ForbiddenSchemes:   split(beego.AppConfig.String("validator_forbidden_schemes")),
+	}
+}
+
+// RulesValidator checks the org-specific constraints configured in
+// app.conf on top of the buildin validators
+type RulesValidator struct {
+	// ServiceName and AppId are the naming conventions of the
+	// microservices, nil means no restriction
+	ServiceName *regexp.Regexp
+	AppId       *regexp.Regexp
+	// RequiredProperties are the property keys every microservice must have
+	RequiredProperties []string
+	// ForbiddenSchemes are the endpoint schemes the instances can not
+	// register, e.g. 'http' to allow TLS endpoints only
+	ForbiddenSchemes []string
+}
+
+func (v *RulesValidator) Validate(in interface{}) error {
+	switch t := in.(type) {
+	case *pb.CreateServiceRequest:
+		if t.Service == nil {
+			return nil
+		}
+		if err := v.validateService(t.Service); err != nil {
+			return err
+		}
+		for _, inst := range t.Instances {
+			if inst == nil {
+				continue
+			}
+			if err := v.validateEndpoints(inst.Endpoints); err != nil {
+				return err
+			}
+		}
+	case *pb.UpdateServicePropsRequest:
+		return v.validateProperties(t.Properties)
+	case *pb.RegisterInstanceRequest:
+		if t.Instance == nil {
+			return nil
+		}
+		return v.validateEndpoints(t.Instance.Endpoints)
+	}
+	return nil
+}
+
+func (v *RulesValidator) validateService(service *pb.MicroService) error {
+	if v.ServiceName != nil && !v.ServiceName.MatchString(service.ServiceName) {
+		return fmt.Errorf("serviceName '%s' does not match the pattern '%s'",
+			service.ServiceName, v.ServiceName)
+	}
+	if v.AppId != nil && !v.AppId.MatchString(service.AppId) {
+		return fmt.Errorf("appId '%s' does not match the pattern '%s'",
+			service.AppId, v.AppId)
+	}
+	return v.validateProperties(service.Properties)
+}
+
+func (v *RulesValidator) validateProperties(properties map[string]string) error {
+	for _, key := range v.RequiredProperties {
+		if _, ok := properties[key]; !ok {
+			return fmt.Errorf("property '%s' is required", key)
+		}
+	}
+	return nil
+}
+
+func (v *RulesValidator) validateEndpoints(endpoints []string) error {
+	for _, endpoint := range endpoints {
+		i := strings.Index(endpoint, "://")
+		if i < 0 {
+			continue
+		}
+		scheme := endpoint[:i]
+		for _, forbidden := range v.ForbiddenSchemes {
+			if strings.EqualFold(scheme, forbidden) {
+				return fmt.Errorf("endpoint '%s' uses the forbidden scheme '%s'", endpoint, scheme)
+			}
+		}
+	}
+	return nil
+}
+
+func compile(key string) *regexp.Regexp {
+	s := beego.AppConfig.String(key)
+	if len(s) == 0 {
+		return nil
+	}
+	re, err := regexp.Compile(s)
+	if err != nil {
+		log.Errorf(err, "invalid %s '%s', ignore it", key, s)
+		return nil
+	}
+	return re
+}
+
+func split(s string) []string {
+	var l []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); len(v) > 0 {
+			l = append(l, v)
+		}
+	}
+	return l
+}
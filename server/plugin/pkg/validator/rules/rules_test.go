@@ -0,0 +1,65 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package rules
+
+import (
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"regexp"
+	"testing"
+)
+
+func TestRulesValidator_Validate(t *testing.T) {
+	v := &RulesValidator{
+		ServiceName:        regexp.MustCompile(`^[a-z][a-z0-9-]*$`),
+		AppId:              regexp.MustCompile(`^org-`),
+		RequiredProperties: []string{"owner"},
+		ForbiddenSchemes:   []string{"http"},
+	}
+
+	service := &pb.MicroService{ServiceName: "order", AppId: "org-shop", Properties: map[string]string{"owner": "a"}}
+	if err := v.Validate(&pb.CreateServiceRequest{Service: service}); err != nil {
+		t.Fatalf("TestRulesValidator_Validate failed, %s", err)
+	}
+	service = &pb.MicroService{ServiceName: "Order", AppId: "org-shop", Properties: map[string]string{"owner": "a"}}
+	if err := v.Validate(&pb.CreateServiceRequest{Service: service}); err == nil {
+		t.Fatalf("TestRulesValidator_Validate failed")
+	}
+	service = &pb.MicroService{ServiceName: "order", AppId: "shop", Properties: map[string]string{"owner": "a"}}
+	if err := v.Validate(&pb.CreateServiceRequest{Service: service}); err == nil {
+		t.Fatalf("TestRulesValidator_Validate failed")
+	}
+	service = &pb.MicroService{ServiceName: "order", AppId: "org-shop"}
+	if err := v.Validate(&pb.CreateServiceRequest{Service: service}); err == nil {
+		t.Fatalf("TestRulesValidator_Validate failed")
+	}
+	if err := v.Validate(&pb.UpdateServicePropsRequest{Properties: map[string]string{}}); err == nil {
+		t.Fatalf("TestRulesValidator_Validate failed")
+	}
+
+	instance := &pb.MicroServiceInstance{Endpoints: []string{"rest://127.0.0.1:8080?sslEnabled=true"}}
+	if err := v.Validate(&pb.RegisterInstanceRequest{Instance: instance}); err != nil {
+		t.Fatalf("TestRulesValidator_Validate failed, %s", err)
+	}
+	instance = &pb.MicroServiceInstance{Endpoints: []string{"HTTP://127.0.0.1:8080"}}
+	if err := v.Validate(&pb.RegisterInstanceRequest{Instance: instance}); err == nil {
+		t.Fatalf("TestRulesValidator_Validate failed")
+	}
+
+	if err := v.Validate(&pb.GetServiceRequest{}); err != nil {
+		t.Fatalf("TestRulesValidator_Validate failed, %s", err)
+	}
+}
@@ -0,0 +1,52 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package reconcile
+
+import (
+	"github.com/apache/servicecomb-service-center/server/job"
+	"github.com/astaxie/beego"
+	"time"
+)
+
+var cfg Config
+
+func init() {
+	cfg = LoadConfig()
+	if cfg.Interval <= 0 {
+		return
+	}
+	job.Register(job.Job{
+		Name:     "quota_reconcile",
+		Interval: cfg.Interval,
+		Run:      Reconcile,
+	})
+}
+
+type Config struct {
+	// Interval is how often the usage of the quota manager is corrected
+	// by the numbers of the services and instances, 0 is disabled
+	Interval time.Duration
+}
+
+func LoadConfig() Config {
+	var c Config
+	d, err := time.ParseDuration(beego.AppConfig.DefaultString("quota_reconcile_interval", ""))
+	if err == nil && d >= time.Minute {
+		c.Interval = d
+	}
+	return c
+}
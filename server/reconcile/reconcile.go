@@ -0,0 +1,103 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package reconcile
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/core/backend"
+	"github.com/apache/servicecomb-service-center/server/plugin"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/discovery"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/quota"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	"golang.org/x/net/context"
+	"sort"
+)
+
+// Reconcile corrects the usage of the quota manager by the numbers of the
+// services and instances of each domain project, the usage reported out of
+// the registration transactions drifts if the reports fail
+func Reconcile(ctx context.Context) error {
+	r, ok := plugin.Plugins().Quota().(quota.UsageReconciler)
+	if !ok {
+		return nil
+	}
+	usages, err := CountUsages(ctx)
+	if err != nil {
+		return err
+	}
+	if err := r.ReconcileUsage(ctx, usages); err != nil {
+		log.Errorf(err, "reconcile %d quota usages failed", len(usages))
+		return err
+	}
+	log.Infof("reconciled %d quota usages", len(usages))
+	return nil
+}
+
+// CountUsages counts the services and instances of each domain project
+func CountUsages(ctx context.Context) ([]*quota.Usage, error) {
+	opts := []registry.PluginOpOption{registry.WithPrefix(), registry.WithKeyOnly()}
+	respSvc, err := backend.Store().Service().Search(ctx,
+		append(opts, registry.WithStrKey(core.GetServiceRootKey("")))...)
+	if err != nil {
+		return nil, err
+	}
+	respIns, err := backend.Store().Instance().Search(ctx,
+		append(opts, registry.WithStrKey(core.GetInstanceRootKey("")))...)
+	if err != nil {
+		return nil, err
+	}
+	return countUsages(respSvc.Kvs, respIns.Kvs), nil
+}
+
+// countUsages returns the service and instance usages of each domain
+// project ordered by the domain project, the instance usage is 0 if the
+// domain project has services only
+func countUsages(services, instances []*discovery.KeyValue) []*quota.Usage {
+	counts := make(map[string]*[2]int64)
+	countOf := func(domainProject string) *[2]int64 {
+		c, ok := counts[domainProject]
+		if !ok {
+			c = new([2]int64)
+			counts[domainProject] = c
+		}
+		return c
+	}
+	for _, kv := range services {
+		_, domainProject := core.GetInfoFromSvcKV(kv.Key)
+		countOf(domainProject)[0]++
+	}
+	for _, kv := range instances {
+		_, _, domainProject := core.GetInfoFromInstKV(kv.Key)
+		countOf(domainProject)[1]++
+	}
+
+	domainProjects := make([]string, 0, len(counts))
+	for domainProject := range counts {
+		domainProjects = append(domainProjects, domainProject)
+	}
+	sort.Strings(domainProjects)
+
+	usages := make([]*quota.Usage, 0, 2*len(domainProjects))
+	for _, domainProject := range domainProjects {
+		c := counts[domainProject]
+		usages = append(usages,
+			&quota.Usage{DomainProject: domainProject, QuotaType: quota.MicroServiceQuotaType, Used: c[0]},
+			&quota.Usage{DomainProject: domainProject, QuotaType: quota.MicroServiceInstanceQuotaType, Used: c[1]})
+	}
+	return usages
+}
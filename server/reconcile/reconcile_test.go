@@ -0,0 +1,59 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package reconcile
+
+import (
+	"github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/discovery"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/quota"
+	"testing"
+)
+
+func TestCountUsages(t *testing.T) {
+	kv := func(key string) *discovery.KeyValue {
+		return &discovery.KeyValue{Key: []byte(key)}
+	}
+	services := []*discovery.KeyValue{
+		kv(core.GenerateServiceKey("b/p", "s1")),
+		kv(core.GenerateServiceKey("a/p", "s1")),
+		kv(core.GenerateServiceKey("a/p", "s2")),
+	}
+	instances := []*discovery.KeyValue{
+		kv(core.GenerateInstanceKey("a/p", "s1", "i1")),
+		kv(core.GenerateInstanceKey("a/p", "s2", "i1")),
+		kv(core.GenerateInstanceKey("a/p", "s2", "i2")),
+	}
+	usages := countUsages(services, instances)
+	expected := []quota.Usage{
+		{DomainProject: "a/p", QuotaType: quota.MicroServiceQuotaType, Used: 2},
+		{DomainProject: "a/p", QuotaType: quota.MicroServiceInstanceQuotaType, Used: 3},
+		{DomainProject: "b/p", QuotaType: quota.MicroServiceQuotaType, Used: 1},
+		{DomainProject: "b/p", QuotaType: quota.MicroServiceInstanceQuotaType, Used: 0},
+	}
+	if len(usages) != len(expected) {
+		t.Fatalf("TestCountUsages failed, %v", usages)
+	}
+	for i, u := range usages {
+		if *u != expected[i] {
+			t.Fatalf("TestCountUsages failed, %d: %v", i, *u)
+		}
+	}
+
+	if len(countUsages(nil, nil)) != 0 {
+		t.Fatalf("TestCountUsages failed")
+	}
+}
@@ -0,0 +1,36 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package reload
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/grace"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/astaxie/beego"
+)
+
+const OPERATOR_SIGNAL = "SIGHUP"
+
+func init() {
+	if beego.AppConfig.DefaultInt("config_reload_signal", 0) == 0 {
+		return
+	}
+	grace.OnReload(func() {
+		if _, err := Reload(OPERATOR_SIGNAL); err != nil {
+			log.Errorf(err, "reload config on %s failed", OPERATOR_SIGNAL)
+		}
+	})
+}
@@ -0,0 +1,161 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package reload
+
+import (
+	"fmt"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/astaxie/beego"
+	"github.com/astaxie/beego/config"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const MAX_HISTORY = 100
+
+var (
+	lock    sync.Mutex
+	options = make(map[string]Option)
+	history []Change
+)
+
+// Option is a config key can be changed without restart
+type Option struct {
+	Key string
+	// Validate checks the new value, the empty value means the key is
+	// removed from the config file, nil accepts any value
+	Validate func(value string) error
+	// Apply makes the new value effective, it is called after the value
+	// is set to beego.AppConfig, so the owner can simply load its config
+	// again
+	Apply func(value string)
+}
+
+// Change is the audit record of a reloaded key
+type Change struct {
+	Key       string `json:"key"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Operator  string `json:"operator"`
+	Timestamp string `json:"timestamp"`
+}
+
+// InvalidError lists the keys whose new values are invalid
+type InvalidError []string
+
+func (e InvalidError) Error() string {
+	return "invalid config: " + strings.Join(e, "; ")
+}
+
+// Register makes the key reloadable, the later registered one
+// replaces the former
+func Register(opt Option) {
+	lock.Lock()
+	options[opt.Key] = opt
+	lock.Unlock()
+}
+
+// Keys returns the reloadable keys in order
+func Keys() []string {
+	lock.Lock()
+	defer lock.Unlock()
+	return sortedKeys()
+}
+
+// History returns the latest changes, the newest is the last
+func History() []Change {
+	lock.Lock()
+	changes := make([]Change, len(history))
+	copy(changes, history)
+	lock.Unlock()
+	return changes
+}
+
+// Reload reads the config file again and applies the changed values of
+// the reloadable keys, the other keys still require restart
+func Reload(operator string) ([]Change, error) {
+	c, err := config.NewConfig("ini", beego.AppConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	return Apply(c, operator)
+}
+
+// Apply applies the changed values in c, nothing is changed if any
+// value is invalid
+func Apply(c config.Configer, operator string) ([]Change, error) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	var (
+		changes []Change
+		invalid InvalidError
+		now     = time.Now().UTC().Format(time.RFC3339)
+	)
+	for _, key := range sortedKeys() {
+		from, to := lookup(beego.AppConfig, key), lookup(c, key)
+		if from == to {
+			continue
+		}
+		if v := options[key].Validate; v != nil {
+			if err := v(to); err != nil {
+				invalid = append(invalid, fmt.Sprintf("%s=%q %s", key, to, err.Error()))
+				continue
+			}
+		}
+		changes = append(changes, Change{Key: key, From: from, To: to, Operator: operator, Timestamp: now})
+	}
+	if len(invalid) > 0 {
+		log.Errorf(invalid, "config reload by %s is rejected", operator)
+		return nil, invalid
+	}
+
+	for _, change := range changes {
+		if err := beego.AppConfig.Set(change.Key, change.To); err != nil {
+			log.Errorf(err, "set config %s failed", change.Key)
+		}
+		if apply := options[change.Key].Apply; apply != nil {
+			apply(change.To)
+		}
+		log.Warnf("audit: config %s changed from %q to %q by %s", change.Key, change.From, change.To, operator)
+	}
+	history = append(history, changes...)
+	if n := len(history) - MAX_HISTORY; n > 0 {
+		history = append(history[:0:0], history[n:]...)
+	}
+	log.Infof("config reload by %s, %d key(s) changed", operator, len(changes))
+	return changes, nil
+}
+
+func sortedKeys() []string {
+	keys := make([]string, 0, len(options))
+	for key := range options {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// lookup reads the key of the run mode section first like beego does
+func lookup(c config.Configer, key string) string {
+	if v := c.String(beego.BConfig.RunMode + "::" + key); len(v) > 0 {
+		return v
+	}
+	return c.String(key)
+}
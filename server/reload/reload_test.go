@@ -0,0 +1,89 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package reload
+
+import (
+	"github.com/astaxie/beego"
+	"github.com/astaxie/beego/config"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	var applied []string
+	Register(Option{
+		Key:      "reload_test_size",
+		Validate: Int(1),
+		Apply: func(v string) {
+			applied = append(applied, v)
+		},
+	})
+
+	c, _ := config.NewConfigData("ini", []byte("reload_test_size = 10\nreload_test_other = 1\n"))
+	changes, err := Apply(c, "test")
+	if err != nil || len(changes) != 1 || changes[0].To != "10" || changes[0].Operator != "test" {
+		t.Fatalf("TestApply failed, %v, %v", changes, err)
+	}
+	if len(applied) != 1 || beego.AppConfig.String("reload_test_size") != "10" {
+		t.Fatalf("TestApply failed, %v", applied)
+	}
+	if beego.AppConfig.String("reload_test_other") != "" {
+		t.Fatalf("TestApply failed, the key not registered should not be reloaded")
+	}
+
+	// nothing changed
+	changes, err = Apply(c, "test")
+	if err != nil || len(changes) != 0 || len(applied) != 1 {
+		t.Fatalf("TestApply failed, %v, %v", changes, err)
+	}
+
+	// invalid value rejects the reload
+	c, _ = config.NewConfigData("ini", []byte("reload_test_size = 0\n"))
+	_, err = Apply(c, "test")
+	if _, ok := err.(InvalidError); !ok || len(applied) != 1 ||
+		beego.AppConfig.String("reload_test_size") != "10" {
+		t.Fatalf("TestApply failed, %v", err)
+	}
+
+	h := History()
+	if len(h) == 0 || h[len(h)-1].Key != "reload_test_size" || h[len(h)-1].From != "" {
+		t.Fatalf("TestApply failed, %v", h)
+	}
+}
+
+func TestValidators(t *testing.T) {
+	cases := []struct {
+		f     func(string) error
+		value string
+		valid bool
+	}{
+		{Int(1), "", true},
+		{Int(1), "1", true},
+		{Int(1), "0", false},
+		{Int(1), "a", false},
+		{Float(0), "0.5", true},
+		{Float(0), "0", false},
+		{Duration(0), "1s", true},
+		{Duration(0), "1", false},
+		{OneOf("drop", "disconnect"), "Drop", true},
+		{OneOf("drop", "disconnect"), "block", false},
+	}
+	for i, c := range cases {
+		if err := c.f(c.value); (err == nil) != c.valid {
+			t.Fatalf("TestValidators case %d failed, %v", i, err)
+		}
+	}
+}
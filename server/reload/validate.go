@@ -0,0 +1,91 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package reload
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Int returns the validator of the integers not less than min
+func Int(min int64) func(string) error {
+	return func(s string) error {
+		if len(s) == 0 {
+			return nil
+		}
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return errors.New("is not an integer")
+		}
+		if i < min {
+			return fmt.Errorf("is less than %d", min)
+		}
+		return nil
+	}
+}
+
+// Float returns the validator of the numbers greater than min
+func Float(min float64) func(string) error {
+	return func(s string) error {
+		if len(s) == 0 {
+			return nil
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return errors.New("is not a number")
+		}
+		if f <= min {
+			return fmt.Errorf("is not greater than %v", min)
+		}
+		return nil
+	}
+}
+
+// Duration returns the validator of the durations not less than min
+func Duration(min time.Duration) func(string) error {
+	return func(s string) error {
+		if len(s) == 0 {
+			return nil
+		}
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return errors.New("is not a duration")
+		}
+		if d < min {
+			return fmt.Errorf("is less than %s", min)
+		}
+		return nil
+	}
+}
+
+// OneOf returns the validator of the enum values, case insensitive
+func OneOf(values ...string) func(string) error {
+	return func(s string) error {
+		if len(s) == 0 {
+			return nil
+		}
+		for _, v := range values {
+			if strings.EqualFold(s, v) {
+				return nil
+			}
+		}
+		return fmt.Errorf("is not one of %v", values)
+	}
+}
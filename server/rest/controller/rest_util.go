@@ -17,7 +17,6 @@
 package controller
 
 import (
-	"encoding/json"
 	"fmt"
 	"github.com/apache/servicecomb-service-center/pkg/rest"
 	"github.com/apache/servicecomb-service-center/pkg/util"
@@ -30,6 +29,7 @@ import (
 func WriteError(w http.ResponseWriter, code int32, detail string) {
 	err := error.NewError(code, detail)
 	w.Header().Set(rest.HEADER_RESPONSE_STATUS, strconv.Itoa(err.StatusCode()))
+	w.Header().Set(rest.HEADER_ERROR_CODE, strconv.Itoa(int(code)))
 	w.Header().Set(rest.HEADER_CONTENT_TYPE, rest.CONTENT_TYPE_JSON)
 	w.WriteHeader(err.StatusCode())
 	fmt.Fprintln(w, util.BytesToStringWithNoCopy(err.Marshal()))
@@ -48,7 +48,7 @@ func WriteResponse(w http.ResponseWriter, resp *pb.Response, obj interface{}) {
 		return
 	}
 
-	objJson, err := json.Marshal(obj)
+	objJson, err := util.JsonMarshal(obj)
 	if err != nil {
 		WriteError(w, error.ErrInternal, err.Error())
 		return
@@ -59,6 +59,21 @@ func WriteResponse(w http.ResponseWriter, resp *pb.Response, obj interface{}) {
 	fmt.Fprintln(w, util.BytesToStringWithNoCopy(objJson))
 }
 
+// WriteNotModified sets the ETag of the resource revision and writes 304
+// if the client holds the latest revision, either by the requested revision
+// or the If-None-Match header
+func WriteNotModified(w http.ResponseWriter, r *http.Request, requestRev, rev string) bool {
+	if len(rev) == 0 {
+		return false
+	}
+	w.Header().Set(rest.HEADER_ETAG, rest.ETag(rev))
+	if requestRev != rev && !rest.MatchIfNoneMatch(r.Header.Get(rest.HEADER_IF_NONE_MATCH), rev) {
+		return false
+	}
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
 func WriteJsonBytes(w http.ResponseWriter, resp *pb.Response, json []byte) {
 	if resp.GetCode() == pb.Response_SUCCESS {
 		w.Header().Set(rest.HEADER_RESPONSE_STATUS, strconv.Itoa(http.StatusOK))
@@ -0,0 +1,147 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package controller
+
+import (
+	"encoding/json"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+var registerRequest = []byte(`{"instance":{"endpoints":["rest://127.0.0.1:8080","highway://127.0.0.1:7070"],` +
+	`"hostName":"localhost","status":"UP","properties":{"engineID":"default","engineName":"default"},` +
+	`"healthCheck":{"mode":"push","interval":30,"times":3},"version":"1.0.0"}}`)
+
+func findResponse(n int) *pb.FindInstancesResponse {
+	resp := &pb.FindInstancesResponse{}
+	for i := 0; i < n; i++ {
+		resp.Instances = append(resp.Instances, &pb.MicroServiceInstance{
+			InstanceId: "instance" + strconv.Itoa(i),
+			ServiceId:  "8cdd2a716aa011e8b9aa286ed488fc6b",
+			Endpoints:  []string{"rest://127.0.0.1:8080", "highway://127.0.0.1:7070"},
+			HostName:   "localhost",
+			Status:     pb.MSI_UP,
+			Properties: map[string]string{"engineID": "default", "engineName": "default"},
+			HealthCheck: &pb.HealthCheck{
+				Mode:     pb.CHECK_BY_HEARTBEAT,
+				Interval: 30,
+				Times:    3,
+			},
+			Timestamp:    "1530252962",
+			ModTimestamp: "1530252962",
+			Version:      "1.0.0",
+		})
+	}
+	return resp
+}
+
+func TestWriteResponse(t *testing.T) {
+	resp := findResponse(2)
+	w := httptest.NewRecorder()
+	WriteResponse(w, nil, resp)
+	if w.Code != 200 {
+		t.Fatalf("TestWriteResponse failed, code %d", w.Code)
+	}
+	var std pb.FindInstancesResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &std); err != nil {
+		t.Fatalf("TestWriteResponse failed, %s", err.Error())
+	}
+	if len(std.Instances) != 2 || std.Instances[1].InstanceId != "instance1" ||
+		std.Instances[0].HealthCheck.Interval != 30 {
+		t.Fatalf("TestWriteResponse failed, %s", w.Body.String())
+	}
+}
+
+func TestWriteNotModified(t *testing.T) {
+	cases := []struct {
+		requestRev  string
+		ifNoneMatch string
+		rev         string
+		notModified bool
+	}{
+		{"", "", "", false},
+		{"", "", "a", false},
+		{"a", "", "a", true},
+		{"b", "", "a", false},
+		{"", `"a"`, "a", true},
+		{"", `W/"a"`, "a", true},
+		{"", `"b", "a"`, "a", true},
+		{"", `"b"`, "a", false},
+		{"", `a`, "a", false},
+		{"", `*`, "a", true},
+		{"", `*`, "", false},
+	}
+	for i, c := range cases {
+		r, _ := http.NewRequest(http.MethodGet, "/", nil)
+		if len(c.ifNoneMatch) > 0 {
+			r.Header.Set(rest.HEADER_IF_NONE_MATCH, c.ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		if WriteNotModified(w, r, c.requestRev, c.rev) != c.notModified {
+			t.Fatalf("TestWriteNotModified case %d failed", i)
+		}
+		if c.notModified && w.Code != http.StatusNotModified {
+			t.Fatalf("TestWriteNotModified case %d failed, code %d", i, w.Code)
+		}
+		if etag := w.Header().Get(rest.HEADER_ETAG); len(c.rev) > 0 && etag != `"`+c.rev+`"` {
+			t.Fatalf("TestWriteNotModified case %d failed, etag %s", i, etag)
+		}
+	}
+}
+
+func BenchmarkRegister_Unmarshal(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := util.JsonUnmarshal(registerRequest, &pb.RegisterInstanceRequest{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkRegister_StdUnmarshal(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := json.Unmarshal(registerRequest, &pb.RegisterInstanceRequest{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFind_WriteResponse(b *testing.B) {
+	resp := findResponse(100)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		WriteResponse(httptest.NewRecorder(), nil, resp)
+	}
+}
+
+func BenchmarkFind_StdMarshal(b *testing.B) {
+	resp := findResponse(100)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := json.Marshal(resp); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
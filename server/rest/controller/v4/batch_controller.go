@@ -0,0 +1,269 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package v4
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/core"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/rest/controller"
+	"github.com/astaxie/beego"
+	"golang.org/x/net/context"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+const (
+	BATCH_REGISTER_INSTANCE          = "registerInstance"
+	BATCH_UPDATE_INSTANCE_PROPERTIES = "updateInstanceProperties"
+	BATCH_UPDATE_SERVICE_PROPERTIES  = "updateServiceProperties"
+	BATCH_ADD_TAGS                   = "addTags"
+	BATCH_HEARTBEAT                  = "heartbeat"
+
+	DEFAULT_BATCH_MAX_REQUESTS    = 100
+	DEFAULT_BATCH_MAX_CONCURRENCY = 10
+)
+
+// BatchRequest is one sub-request of the batch, the body is the same as
+// the one of the single API and the ids replace the path parameters
+type BatchRequest struct {
+	Id         string          `json:"id,omitempty"`
+	Action     string          `json:"action"`
+	ServiceId  string          `json:"serviceId,omitempty"`
+	InstanceId string          `json:"instanceId,omitempty"`
+	Body       json.RawMessage `json:"body,omitempty"`
+}
+
+// BatchResult is the result of the sub-request of the same index
+type BatchResult struct {
+	Id     string       `json:"id,omitempty"`
+	Action string       `json:"action"`
+	Status int          `json:"status"`
+	Error  *scerr.Error `json:"error,omitempty"`
+	Body   interface{}  `json:"body,omitempty"`
+}
+
+type BatchService struct {
+	// MaxRequests is the max sub-requests of one batch
+	MaxRequests int
+	// MaxConcurrency is the max sub-requests executed at the same time
+	MaxConcurrency int
+}
+
+func NewBatchService() *BatchService {
+	s := &BatchService{
+		MaxRequests:    beego.AppConfig.DefaultInt("batch_max_requests", DEFAULT_BATCH_MAX_REQUESTS),
+		MaxConcurrency: beego.AppConfig.DefaultInt("batch_max_concurrency", DEFAULT_BATCH_MAX_CONCURRENCY),
+	}
+	if s.MaxRequests <= 0 {
+		s.MaxRequests = DEFAULT_BATCH_MAX_REQUESTS
+	}
+	if s.MaxConcurrency <= 0 {
+		s.MaxConcurrency = DEFAULT_BATCH_MAX_CONCURRENCY
+	}
+	return s
+}
+
+func (this *BatchService) URLPatterns() []rest.Route {
+	return []rest.Route{
+		{rest.HTTP_METHOD_POST, "/v4/:project/registry/batch", this.Batch},
+	}
+}
+
+func (this *BatchService) Batch(w http.ResponseWriter, r *http.Request) {
+	message, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Error("read body failed", err)
+		controller.WriteError(w, scerr.ErrInvalidParams, err.Error())
+		return
+	}
+	var request struct {
+		Requests []*BatchRequest `json:"requests"`
+	}
+	err = json.Unmarshal(message, &request)
+	if err != nil {
+		log.Errorf(err, "Invalid json: %s", util.BytesToStringWithNoCopy(message))
+		controller.WriteError(w, scerr.ErrInvalidParams, "Unmarshal error")
+		return
+	}
+	if len(request.Requests) == 0 || len(request.Requests) > this.MaxRequests {
+		controller.WriteError(w, scerr.ErrInvalidParams,
+			fmt.Sprintf("the number of requests must be in [1, %d]", this.MaxRequests))
+		return
+	}
+
+	results := this.Execute(r.Context(), request.Requests)
+	controller.WriteResponse(w, nil, map[string]interface{}{"results": results})
+}
+
+// Execute runs the sub-requests with the bounded concurrency, the results
+// are in the order of the sub-requests
+func (this *BatchService) Execute(ctx context.Context, requests []*BatchRequest) []*BatchResult {
+	var wg sync.WaitGroup
+	results := make([]*BatchResult, len(requests))
+	sem := make(chan struct{}, this.MaxConcurrency)
+	for i, req := range requests {
+		sem <- struct{}{}
+		wg.Add(1)
+		i, req := i, req
+		gopool.Go(func(_ context.Context) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			results[i] = executeBatchRequest(ctx, req)
+		})
+	}
+	wg.Wait()
+	return results
+}
+
+func executeBatchRequest(ctx context.Context, req *BatchRequest) *BatchResult {
+	if req == nil {
+		return batchError(&BatchResult{}, scerr.NewError(scerr.ErrInvalidParams, "request is nil"))
+	}
+	result := &BatchResult{Id: req.Id, Action: req.Action}
+
+	var (
+		resp *pb.Response
+		body interface{}
+		err  error
+	)
+	switch req.Action {
+	case BATCH_REGISTER_INSTANCE:
+		in := &pb.RegisterInstanceRequest{}
+		if err := unmarshalBatchBody(req.Body, in); err != nil {
+			return batchError(result, err)
+		}
+		if in.GetInstance() != nil {
+			if err := mergeBatchId("serviceId", &in.Instance.ServiceId, req.ServiceId); err != nil {
+				return batchError(result, err)
+			}
+		}
+		var out *pb.RegisterInstanceResponse
+		out, err = core.InstanceAPI.Register(ctx, in)
+		resp = out.GetResponse()
+		if out != nil {
+			out.Response = nil
+			body = out
+		}
+	case BATCH_UPDATE_INSTANCE_PROPERTIES:
+		in := &pb.UpdateInstancePropsRequest{}
+		if err := unmarshalBatchBody(req.Body, in); err != nil {
+			return batchError(result, err)
+		}
+		if err := mergeBatchIds(req, &in.ServiceId, &in.InstanceId); err != nil {
+			return batchError(result, err)
+		}
+		var out *pb.UpdateInstancePropsResponse
+		out, err = core.InstanceAPI.UpdateInstanceProperties(ctx, in)
+		resp = out.GetResponse()
+	case BATCH_UPDATE_SERVICE_PROPERTIES:
+		in := &pb.UpdateServicePropsRequest{}
+		if err := unmarshalBatchBody(req.Body, in); err != nil {
+			return batchError(result, err)
+		}
+		if err := mergeBatchId("serviceId", &in.ServiceId, req.ServiceId); err != nil {
+			return batchError(result, err)
+		}
+		var out *pb.UpdateServicePropsResponse
+		out, err = core.ServiceAPI.UpdateProperties(ctx, in)
+		resp = out.GetResponse()
+	case BATCH_ADD_TAGS:
+		in := &pb.AddServiceTagsRequest{}
+		if err := unmarshalBatchBody(req.Body, in); err != nil {
+			return batchError(result, err)
+		}
+		if err := mergeBatchId("serviceId", &in.ServiceId, req.ServiceId); err != nil {
+			return batchError(result, err)
+		}
+		var out *pb.AddServiceTagsResponse
+		out, err = core.ServiceAPI.AddTags(ctx, in)
+		resp = out.GetResponse()
+	case BATCH_HEARTBEAT:
+		var out *pb.HeartbeatResponse
+		out, err = core.InstanceAPI.Heartbeat(ctx, &pb.HeartbeatRequest{
+			ServiceId:  req.ServiceId,
+			InstanceId: req.InstanceId,
+		})
+		resp = out.GetResponse()
+	default:
+		return batchError(result, scerr.NewError(scerr.ErrInvalidParams,
+			fmt.Sprintf("unknown action '%s'", req.Action)))
+	}
+
+	if err != nil {
+		log.Errorf(err, "batch request[%s] %s of service[%s] instance[%s] failed",
+			req.Id, req.Action, req.ServiceId, req.InstanceId)
+		if resp == nil || resp.GetCode() == pb.Response_SUCCESS {
+			return batchError(result, scerr.NewError(scerr.ErrInternal, err.Error()))
+		}
+	}
+	if resp == nil {
+		return batchError(result, scerr.NewError(scerr.ErrInternal, "no response"))
+	}
+	if resp.GetCode() != pb.Response_SUCCESS {
+		return batchError(result, scerr.NewError(resp.GetCode(), resp.GetMessage()))
+	}
+	result.Status = http.StatusOK
+	result.Body = body
+	return result
+}
+
+// mergeBatchId defaults the id in the body to the one of the sub-request,
+// they must be the same if both are set
+func mergeBatchId(name string, bodyId *string, id string) *scerr.Error {
+	switch {
+	case len(id) == 0:
+	case len(*bodyId) == 0:
+		*bodyId = id
+	case *bodyId != id:
+		return scerr.NewError(scerr.ErrInvalidParams,
+			fmt.Sprintf("%s '%s' in body does not match '%s'", name, *bodyId, id))
+	}
+	return nil
+}
+
+func mergeBatchIds(req *BatchRequest, serviceId, instanceId *string) *scerr.Error {
+	if err := mergeBatchId("serviceId", serviceId, req.ServiceId); err != nil {
+		return err
+	}
+	return mergeBatchId("instanceId", instanceId, req.InstanceId)
+}
+
+func unmarshalBatchBody(body json.RawMessage, v interface{}) *scerr.Error {
+	if len(body) == 0 {
+		return nil
+	}
+	if err := util.JsonUnmarshal(body, v); err != nil {
+		return scerr.NewError(scerr.ErrInvalidParams, "Unmarshal error")
+	}
+	return nil
+}
+
+func batchError(result *BatchResult, err *scerr.Error) *BatchResult {
+	result.Status = err.StatusCode()
+	result.Error = err
+	return result
+}
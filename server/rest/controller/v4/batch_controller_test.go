@@ -0,0 +1,142 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package v4
+
+import (
+	"errors"
+	"github.com/apache/servicecomb-service-center/server/core"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"golang.org/x/net/context"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type mockInstanceAPI struct {
+	pb.ServiceInstanceCtrlServerEx
+	running int32
+	max     int32
+}
+
+func (m *mockInstanceAPI) Heartbeat(ctx context.Context, in *pb.HeartbeatRequest) (*pb.HeartbeatResponse, error) {
+	n := atomic.AddInt32(&m.running, 1)
+	defer atomic.AddInt32(&m.running, -1)
+	for {
+		max := atomic.LoadInt32(&m.max)
+		if n <= max || atomic.CompareAndSwapInt32(&m.max, max, n) {
+			break
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	switch in.InstanceId {
+	case "not-exist":
+		return &pb.HeartbeatResponse{
+			Response: pb.CreateResponse(scerr.ErrInstanceNotExists, "Service instance does not exist."),
+		}, nil
+	case "error":
+		return &pb.HeartbeatResponse{
+			Response: pb.CreateResponse(scerr.ErrInternal, "Update lease failed."),
+		}, errors.New("update lease failed")
+	}
+	return &pb.HeartbeatResponse{
+		Response: pb.CreateResponse(pb.Response_SUCCESS, "Update service instance heartbeat successfully."),
+	}, nil
+}
+
+func (m *mockInstanceAPI) Register(ctx context.Context, in *pb.RegisterInstanceRequest) (*pb.RegisterInstanceResponse, error) {
+	return &pb.RegisterInstanceResponse{
+		Response:   pb.CreateResponse(pb.Response_SUCCESS, "Register service instance successfully."),
+		InstanceId: in.Instance.ServiceId + "-instance",
+	}, nil
+}
+
+func mockBatchAPI() (*mockInstanceAPI, func()) {
+	old := core.InstanceAPI
+	m := &mockInstanceAPI{}
+	core.InstanceAPI = m
+	return m, func() { core.InstanceAPI = old }
+}
+
+func TestBatchService_Execute(t *testing.T) {
+	_, restore := mockBatchAPI()
+	defer restore()
+
+	s := &BatchService{MaxRequests: 10, MaxConcurrency: 2}
+	results := s.Execute(context.Background(), []*BatchRequest{
+		{Id: "1", Action: BATCH_HEARTBEAT, ServiceId: "a", InstanceId: "ok"},
+		{Id: "2", Action: BATCH_HEARTBEAT, ServiceId: "a", InstanceId: "not-exist"},
+		{Id: "3", Action: BATCH_HEARTBEAT, ServiceId: "a", InstanceId: "error"},
+		{Id: "4", Action: "unknown"},
+		{Id: "5", Action: BATCH_REGISTER_INSTANCE, ServiceId: "a",
+			Body: []byte(`{"instance":{"hostName":"localhost"}}`)},
+		{Id: "6", Action: BATCH_REGISTER_INSTANCE, ServiceId: "a",
+			Body: []byte(`{"instance":{"serviceId":"b","hostName":"localhost"}}`)},
+	})
+	if len(results) != 6 {
+		t.Fatalf("TestBatchService_Execute failed, %d results", len(results))
+	}
+	for i, result := range results {
+		if result.Id != strconv.Itoa(i+1) {
+			t.Fatalf("TestBatchService_Execute failed, result %d is %s", i, result.Id)
+		}
+	}
+
+	if results[0].Status != http.StatusOK || results[0].Error != nil {
+		t.Fatalf("TestBatchService_Execute failed, %v", results[0])
+	}
+	if results[1].Error == nil || results[1].Error.Code != scerr.ErrInstanceNotExists {
+		t.Fatalf("TestBatchService_Execute failed, %v", results[1])
+	}
+	if results[2].Status != http.StatusInternalServerError ||
+		results[2].Error == nil || results[2].Error.Code != scerr.ErrInternal {
+		t.Fatalf("TestBatchService_Execute failed, %v", results[2])
+	}
+	if results[3].Error == nil || results[3].Error.Code != scerr.ErrInvalidParams {
+		t.Fatalf("TestBatchService_Execute failed, %v", results[3])
+	}
+	out, ok := results[4].Body.(*pb.RegisterInstanceResponse)
+	if results[4].Status != http.StatusOK || !ok || out.InstanceId != "a-instance" {
+		t.Fatalf("TestBatchService_Execute failed, %v", results[4])
+	}
+	if results[5].Error == nil || results[5].Error.Code != scerr.ErrInvalidParams {
+		t.Fatalf("TestBatchService_Execute failed, %v", results[5])
+	}
+}
+
+func TestBatchService_Concurrency(t *testing.T) {
+	m, restore := mockBatchAPI()
+	defer restore()
+
+	var requests []*BatchRequest
+	for i := 0; i < 20; i++ {
+		requests = append(requests, &BatchRequest{Action: BATCH_HEARTBEAT, ServiceId: "a", InstanceId: "ok"})
+	}
+	s := &BatchService{MaxRequests: 20, MaxConcurrency: 3}
+	results := s.Execute(context.Background(), requests)
+	for _, result := range results {
+		if result.Status != http.StatusOK {
+			t.Fatalf("TestBatchService_Concurrency failed, %v", result)
+		}
+	}
+	if max := atomic.LoadInt32(&m.max); max > 3 || max < 2 {
+		t.Fatalf("TestBatchService_Concurrency failed, max concurrency is %d", max)
+	}
+}
@@ -17,20 +17,39 @@
 package v4
 
 import (
-	"encoding/json"
+	"fmt"
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/pkg/rest"
 	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/admission"
 	"github.com/apache/servicecomb-service-center/server/core"
 	pb "github.com/apache/servicecomb-service-center/server/core/proto"
 	scerr "github.com/apache/servicecomb-service-center/server/error"
 	"github.com/apache/servicecomb-service-center/server/rest/controller"
+	"github.com/apache/servicecomb-service-center/server/service/cache"
 	serviceUtil "github.com/apache/servicecomb-service-center/server/service/util"
+	"github.com/astaxie/beego"
+	"golang.org/x/net/context"
 	"io/ioutil"
 	"net/http"
 	"strings"
+	"time"
 )
 
+const DEFAULT_FIND_MAX_WAIT = 30 * time.Second
+
+// maxFindWait caps the wait of the long-polling finds, it should be less
+// than the write timeout of the server
+var maxFindWait = loadMaxFindWait()
+
+func loadMaxFindWait() time.Duration {
+	d, err := time.ParseDuration(beego.AppConfig.DefaultString("find_max_wait", ""))
+	if err != nil || d <= 0 {
+		return DEFAULT_FIND_MAX_WAIT
+	}
+	return d
+}
+
 type MicroServiceInstanceService struct {
 	//
 }
@@ -47,6 +66,7 @@ func (this *MicroServiceInstanceService) URLPatterns() []rest.Route {
 		{rest.HTTP_METHOD_PUT, "/v4/:project/registry/microservices/:serviceId/instances/:instanceId/status", this.UpdateStatus},
 		{rest.HTTP_METHOD_PUT, "/v4/:project/registry/microservices/:serviceId/instances/:instanceId/heartbeat", this.Heartbeat},
 		{rest.HTTP_METHOD_PUT, "/v4/:project/registry/heartbeats", this.HeartbeatSet},
+		{rest.HTTP_METHOD_POST, "/v4/:project/registry/registration", this.RegisterServiceAndInstance},
 	}
 }
 func (this *MicroServiceInstanceService) RegisterInstance(w http.ResponseWriter, r *http.Request) {
@@ -58,7 +78,7 @@ func (this *MicroServiceInstanceService) RegisterInstance(w http.ResponseWriter,
 	}
 
 	request := &pb.RegisterInstanceRequest{}
-	err = json.Unmarshal(message, request)
+	err = util.JsonUnmarshal(message, request)
 	if err != nil {
 		log.Errorf(err, "Invalid json: %s", util.BytesToStringWithNoCopy(message))
 		controller.WriteError(w, scerr.ErrInvalidParams, "Unmarshal error")
@@ -74,6 +94,30 @@ func (this *MicroServiceInstanceService) RegisterInstance(w http.ResponseWriter,
 	controller.WriteResponse(w, respInternal, resp)
 }
 
+// RegisterServiceAndInstance creates the service if it is absent and
+// registers its instance in one request
+func (this *MicroServiceInstanceService) RegisterServiceAndInstance(w http.ResponseWriter, r *http.Request) {
+	message, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Error("read body failed", err)
+		controller.WriteError(w, scerr.ErrInvalidParams, err.Error())
+		return
+	}
+
+	request := &pb.RegisterServiceAndInstanceRequest{}
+	err = util.JsonUnmarshal(message, request)
+	if err != nil {
+		log.Errorf(err, "Invalid json: %s", util.BytesToStringWithNoCopy(message))
+		controller.WriteError(w, scerr.ErrInvalidParams, "Unmarshal error")
+		return
+	}
+
+	resp, err := core.InstanceAPI.RegisterServiceAndInstance(r.Context(), request)
+	respInternal := resp.Response
+	resp.Response = nil
+	controller.WriteResponse(w, respInternal, resp)
+}
+
 //TODO 什么样的服务允许更新服务心跳，只能是本服务才可以更新自己，如何屏蔽其他服务伪造的心跳更新？
 func (this *MicroServiceInstanceService) Heartbeat(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query()
@@ -94,7 +138,7 @@ func (this *MicroServiceInstanceService) HeartbeatSet(w http.ResponseWriter, r *
 	}
 
 	request := &pb.HeartbeatSetRequest{}
-	err = json.Unmarshal(message, request)
+	err = util.JsonUnmarshal(message, request)
 	if err != nil {
 		log.Errorf(err, "Invalid json: %s", util.BytesToStringWithNoCopy(message))
 		controller.WriteError(w, scerr.ErrInvalidParams, "Unmarshal error")
@@ -137,24 +181,110 @@ func (this *MicroServiceInstanceService) FindInstances(w http.ResponseWriter, r
 		Environment:       query.Get("env"),
 		Tags:              ids,
 	}
+	mask, err := pb.NewInstanceFieldMask(query.Get("fields"))
+	if err != nil {
+		controller.WriteError(w, scerr.ErrInvalidParams, err.Error())
+		return
+	}
+	wait, err := parseWait(query.Get("wait"))
+	if err != nil {
+		controller.WriteError(w, scerr.ErrInvalidParams, err.Error())
+		return
+	}
 
 	ctx := util.SetTargetDomainProject(r.Context(), r.Header.Get("X-Domain-Name"), query.Get(":project"))
+	if query.Get("remote") == "1" {
+		ctx = util.SetContext(ctx, serviceUtil.CTX_INCLUDE_REMOTE, "1")
+	}
 
+	if wait > 0 {
+		ctx = util.SetContext(ctx, serviceUtil.CTX_FIND_WAIT, "1")
+	}
 	resp, _ := core.InstanceAPI.Find(ctx, request)
-	respInternal := resp.Response
-	resp.Response = nil
 
 	iv, _ := ctx.Value(serviceUtil.CTX_REQUEST_REVISION).(string)
 	ov, _ := ctx.Value(serviceUtil.CTX_RESPONSE_REVISION).(string)
+	if wait > 0 && len(iv) > 0 && iv == ov && resp.Response.GetCode() == pb.Response_SUCCESS {
+		if changedResp := waitFindInstances(ctx, request, wait, iv); changedResp != nil {
+			resp = changedResp
+			ov, _ = ctx.Value(serviceUtil.CTX_RESPONSE_REVISION).(string)
+		}
+	}
+	if waiter := takeFindWaiter(ctx); waiter != nil {
+		cache.FindInstances.Stop(waiter)
+	}
+	respInternal := resp.Response
+	resp.Response = nil
+
 	w.Header().Set(serviceUtil.HEADER_REV, ov)
-	if len(iv) > 0 && iv == ov {
-		w.WriteHeader(http.StatusNotModified)
+	if controller.WriteNotModified(w, r, iv, ov) {
 		return
 	}
 
+	resp.Instances = mask.Instances(resp.Instances)
 	controller.WriteResponse(w, respInternal, resp)
 }
 
+// waitFindInstances holds the find until the instances of the provider
+// change from the revision rev or the wait expires, it returns nil if
+// nothing changed
+func waitFindInstances(ctx context.Context, request *pb.FindInstancesRequest,
+	wait time.Duration, rev string) *pb.FindInstancesResponse {
+	// do not hold the admission slot while waiting
+	resume := admission.Suspend(ctx)
+	defer resume()
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	for {
+		waiter := takeFindWaiter(ctx)
+		if waiter == nil {
+			return nil
+		}
+		changed := false
+		select {
+		case <-waiter.C:
+			changed = true
+		case <-timer.C:
+		case <-ctx.Done():
+		}
+		cache.FindInstances.Stop(waiter)
+		if !changed {
+			return nil
+		}
+
+		resp, _ := core.InstanceAPI.Find(ctx, request)
+		ov, _ := ctx.Value(serviceUtil.CTX_RESPONSE_REVISION).(string)
+		if resp.Response.GetCode() != pb.Response_SUCCESS || ov != rev {
+			return resp
+		}
+	}
+}
+
+// takeFindWaiter returns the waiter subscribed by the last find and
+// clears it from the ctx
+func takeFindWaiter(ctx context.Context) *cache.InstancesWaiter {
+	waiter, _ := ctx.Value(serviceUtil.CTX_FIND_WAITER).(*cache.InstancesWaiter)
+	if waiter != nil {
+		util.SetContext(ctx, serviceUtil.CTX_FIND_WAITER, nil)
+	}
+	return waiter
+}
+
+func parseWait(s string) (time.Duration, error) {
+	if len(s) == 0 {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid wait '%s'", s)
+	}
+	if d > maxFindWait {
+		d = maxFindWait
+	}
+	return d, nil
+}
+
 func (this *MicroServiceInstanceService) BatchFindInstances(w http.ResponseWriter, r *http.Request) {
 	message, err := ioutil.ReadAll(r.Body)
 	if err != nil {
@@ -164,7 +294,7 @@ func (this *MicroServiceInstanceService) BatchFindInstances(w http.ResponseWrite
 	}
 
 	request := &pb.BatchFindInstancesRequest{}
-	err = json.Unmarshal(message, request)
+	err = util.JsonUnmarshal(message, request)
 	if err != nil {
 		log.Errorf(err, "Invalid json: %s", util.BytesToStringWithNoCopy(message))
 		controller.WriteError(w, scerr.ErrInvalidParams, "Unmarshal error")
@@ -172,6 +302,9 @@ func (this *MicroServiceInstanceService) BatchFindInstances(w http.ResponseWrite
 	}
 	request.ConsumerServiceId = r.Header.Get("X-ConsumerId")
 	ctx := util.SetTargetDomainProject(r.Context(), r.Header.Get("X-Domain-Name"), r.URL.Query().Get(":project"))
+	if r.URL.Query().Get("remote") == "1" {
+		ctx = util.SetContext(ctx, serviceUtil.CTX_INCLUDE_REMOTE, "1")
+	}
 	resp, _ := core.InstanceAPI.BatchFind(ctx, request)
 	respInternal := resp.Response
 	resp.Response = nil
@@ -191,9 +324,17 @@ func (this *MicroServiceInstanceService) GetOneInstance(w http.ResponseWriter, r
 		ProviderInstanceId: query.Get(":instanceId"),
 		Tags:               ids,
 	}
+	mask, err := pb.NewInstanceFieldMask(query.Get("fields"))
+	if err != nil {
+		controller.WriteError(w, scerr.ErrInvalidParams, err.Error())
+		return
+	}
 	resp, _ := core.InstanceAPI.GetOneInstance(r.Context(), request)
 	respInternal := resp.Response
 	resp.Response = nil
+	resp.Instance = mask.Instance(resp.Instance)
+	writeInstanceETag(w, resp.Rev)
+	resp.Rev = ""
 	controller.WriteResponse(w, respInternal, resp)
 }
 
@@ -209,9 +350,25 @@ func (this *MicroServiceInstanceService) GetInstances(w http.ResponseWriter, r *
 		ProviderServiceId: query.Get(":serviceId"),
 		Tags:              ids,
 	}
+	mask, err := pb.NewInstanceFieldMask(query.Get("fields"))
+	if err != nil {
+		controller.WriteError(w, scerr.ErrInvalidParams, err.Error())
+		return
+	}
+	if strings.Contains(r.Header.Get(rest.HEADER_ACCEPT), rest.CONTENT_TYPE_NDJSON) {
+		ctx := r.Context()
+		if mask != nil {
+			ctx = util.SetContext(ctx, serviceUtil.CTX_FIELD_MASK, mask)
+		}
+		if err := core.InstanceAPI.NDJSONGetInstances(ctx, request, w); err != nil {
+			writeStreamError(w, err)
+		}
+		return
+	}
 	resp, _ := core.InstanceAPI.GetInstances(r.Context(), request)
 	respInternal := resp.Response
 	resp.Response = nil
+	resp.Instances = mask.Instances(resp.Instances)
 	controller.WriteResponse(w, respInternal, resp)
 }
 
@@ -223,7 +380,11 @@ func (this *MicroServiceInstanceService) UpdateStatus(w http.ResponseWriter, r *
 		InstanceId: query.Get(":instanceId"),
 		Status:     status,
 	}
+	if !parseIfMatch(w, r, &request.Rev) {
+		return
+	}
 	resp, _ := core.InstanceAPI.UpdateStatus(r.Context(), request)
+	writeInstanceETag(w, resp.Rev)
 	controller.WriteResponse(w, resp.Response, nil)
 }
 
@@ -239,12 +400,38 @@ func (this *MicroServiceInstanceService) UpdateMetadata(w http.ResponseWriter, r
 		ServiceId:  query.Get(":serviceId"),
 		InstanceId: query.Get(":instanceId"),
 	}
-	err = json.Unmarshal(message, request)
+	err = util.JsonUnmarshal(message, request)
 	if err != nil {
 		log.Errorf(err, "Invalid json: %s", util.BytesToStringWithNoCopy(message))
 		controller.WriteError(w, scerr.ErrInvalidParams, "Unmarshal error")
 		return
 	}
+	if !parseIfMatch(w, r, &request.Rev) {
+		return
+	}
 	resp, err := core.InstanceAPI.UpdateInstanceProperties(r.Context(), request)
+	writeInstanceETag(w, resp.Rev)
 	controller.WriteResponse(w, resp.Response, nil)
 }
+
+// parseIfMatch sets the revision the update is conditional on, the one
+// of the If-Match header overrides the one of the body
+func parseIfMatch(w http.ResponseWriter, r *http.Request, rev *string) bool {
+	ifMatch, ok := rest.ParseIfMatch(r.Header.Get(rest.HEADER_IF_MATCH))
+	if !ok {
+		controller.WriteError(w, scerr.ErrInvalidParams, "Invalid If-Match header")
+		return false
+	}
+	if len(ifMatch) > 0 {
+		*rev = ifMatch
+	}
+	return true
+}
+
+// writeInstanceETag sets the ETag of the instance mod revision, use it as
+// the If-Match of the conditional updates
+func writeInstanceETag(w http.ResponseWriter, rev string) {
+	if len(rev) > 0 {
+		w.Header().Set(rest.HEADER_ETAG, rest.ETag(rev))
+	}
+}
@@ -19,10 +19,17 @@ package v4
 import (
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
 	"github.com/apache/servicecomb-service-center/server/core"
 	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/rest/controller"
+	serviceUtil "github.com/apache/servicecomb-service-center/server/service/util"
 	"github.com/gorilla/websocket"
+	"golang.org/x/net/context"
 	"net/http"
+	"strconv"
+	"strings"
 )
 
 type WatchService struct {
@@ -33,6 +40,11 @@ func (this *WatchService) URLPatterns() []rest.Route {
 	return []rest.Route{
 		{rest.HTTP_METHOD_GET, "/v4/:project/registry/microservices/:serviceId/watcher", this.Watch},
 		{rest.HTTP_METHOD_GET, "/v4/:project/registry/microservices/:serviceId/listwatcher", this.ListAndWatch},
+		{rest.HTTP_METHOD_GET, "/v4/:project/registry/microservices/:serviceId/watcher/sse", this.SSEWatch},
+		{rest.HTTP_METHOD_GET, "/v4/:project/registry/microservices/:serviceId/listwatcher/sse", this.SSEListAndWatch},
+		{rest.HTTP_METHOD_GET, "/v4/:project/registry/microservices/:serviceId/resources/watcher", this.ResourceWatch},
+		{rest.HTTP_METHOD_GET, "/v4/:project/registry/microservices/:serviceId/events", this.GetEvents},
+		{rest.HTTP_METHOD_GET, "/v4/:project/registry/events", this.GetEvents},
 	}
 }
 
@@ -41,6 +53,8 @@ func upgrade(w http.ResponseWriter, r *http.Request) (*websocket.Conn, error) {
 		CheckOrigin: func(r *http.Request) bool {
 			return true
 		},
+		// negotiate permessage-deflate if the watcher supports
+		EnableCompression: core.ServerInfo.Config.EnableWatchCompression,
 		/*Error: func(w http.ResponseWriter, r *http.Request, status int, reason error) {
 
 		  },*/
@@ -53,6 +67,25 @@ func upgrade(w http.ResponseWriter, r *http.Request) (*websocket.Conn, error) {
 	return conn, err
 }
 
+// watchContext returns the request context with the revision to resume
+// from, whether the watcher receives the events in batch and the format
+// of the events
+func watchContext(r *http.Request) context.Context {
+	ctx := r.Context()
+	query := r.URL.Query()
+	if batch, _ := strconv.ParseBool(query.Get("batch")); batch {
+		ctx = util.SetContext(ctx, serviceUtil.CTX_WATCH_BATCH, true)
+	}
+	if format := query.Get("format"); len(format) > 0 {
+		ctx = util.SetContext(ctx, serviceUtil.CTX_EVENT_FORMAT, format)
+	}
+	since, err := strconv.ParseInt(query.Get("since"), 10, 64)
+	if err != nil || since <= 0 {
+		return ctx
+	}
+	return util.SetContext(ctx, serviceUtil.CTX_SINCE_REVISION, since)
+}
+
 func (this *WatchService) Watch(w http.ResponseWriter, r *http.Request) {
 	conn, err := upgrade(w, r)
 	if err != nil {
@@ -61,7 +94,7 @@ func (this *WatchService) Watch(w http.ResponseWriter, r *http.Request) {
 	defer conn.Close()
 
 	r.Method = "WATCH"
-	core.InstanceAPI.WebSocketWatch(r.Context(), &pb.WatchInstanceRequest{
+	core.InstanceAPI.WebSocketWatch(watchContext(r), &pb.WatchInstanceRequest{
 		SelfServiceId: r.URL.Query().Get(":serviceId"),
 	}, conn)
 }
@@ -74,7 +107,91 @@ func (this *WatchService) ListAndWatch(w http.ResponseWriter, r *http.Request) {
 	defer conn.Close()
 
 	r.Method = "WATCHLIST"
-	core.InstanceAPI.WebSocketListAndWatch(r.Context(), &pb.WatchInstanceRequest{
+	core.InstanceAPI.WebSocketListAndWatch(watchContext(r), &pb.WatchInstanceRequest{
 		SelfServiceId: r.URL.Query().Get(":serviceId"),
 	}, conn)
 }
+
+// splitQuery returns the non-empty values of the comma separated query
+func splitQuery(r *http.Request, key string) (values []string) {
+	for _, v := range strings.Split(r.URL.Query().Get(key), ",") {
+		if v = strings.TrimSpace(v); len(v) > 0 {
+			values = append(values, v)
+		}
+	}
+	return
+}
+
+func (this *WatchService) ResourceWatch(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrade(w, r)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	r.Method = "WATCH"
+	core.InstanceAPI.WebSocketResourceWatch(r.Context(), &pb.WatchResourceRequest{
+		SelfServiceId: r.URL.Query().Get(":serviceId"),
+		ProviderIds:   splitQuery(r, "providers"),
+		Resources:     splitQuery(r, "resources"),
+	}, conn)
+}
+
+// sseContext returns the watch context, the Last-Event-ID header sent by
+// the reconnecting client takes precedence over the since parameter
+func sseContext(r *http.Request) context.Context {
+	ctx := watchContext(r)
+	since, err := strconv.ParseInt(r.Header.Get("Last-Event-ID"), 10, 64)
+	if err != nil || since <= 0 {
+		return ctx
+	}
+	return util.SetContext(ctx, serviceUtil.CTX_SINCE_REVISION, since)
+}
+
+func writeStreamError(w http.ResponseWriter, err error) {
+	if e, ok := err.(*scerr.Error); ok {
+		controller.WriteError(w, e.Code, e.Detail)
+		return
+	}
+	controller.WriteError(w, scerr.ErrInternal, err.Error())
+}
+
+func (this *WatchService) SSEWatch(w http.ResponseWriter, r *http.Request) {
+	r.Method = "WATCH"
+	err := core.InstanceAPI.SSEWatch(sseContext(r), &pb.WatchInstanceRequest{
+		SelfServiceId: r.URL.Query().Get(":serviceId"),
+	}, w)
+	if err != nil {
+		writeStreamError(w, err)
+	}
+}
+
+func (this *WatchService) SSEListAndWatch(w http.ResponseWriter, r *http.Request) {
+	r.Method = "WATCHLIST"
+	err := core.InstanceAPI.SSEListAndWatch(sseContext(r), &pb.WatchInstanceRequest{
+		SelfServiceId: r.URL.Query().Get(":serviceId"),
+	}, w)
+	if err != nil {
+		writeStreamError(w, err)
+	}
+}
+
+func (this *WatchService) GetEvents(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var since int64
+	if v := query.Get("since"); len(v) > 0 {
+		var err error
+		since, err = strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			controller.WriteError(w, scerr.ErrInvalidParams, "Invalid since revision")
+			return
+		}
+	}
+	resp, _ := core.InstanceAPI.GetWatchEvents(r.Context(), &pb.GetWatchEventsRequest{
+		SelfServiceId: query.Get(":serviceId"),
+		Since:         since,
+	})
+	respInternal := resp.Response
+	resp.Response = nil
+	controller.WriteResponse(w, respInternal, resp)
+}
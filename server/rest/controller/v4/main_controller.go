@@ -21,9 +21,12 @@ import (
 	"github.com/apache/servicecomb-service-center/pkg/rest"
 	"github.com/apache/servicecomb-service-center/server/core"
 	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/health"
 	"github.com/apache/servicecomb-service-center/server/rest/controller"
 	"github.com/apache/servicecomb-service-center/version"
 	"net/http"
+	"strconv"
 	"sync"
 )
 
@@ -49,6 +52,7 @@ func (this *MainService) URLPatterns() []rest.Route {
 	return []rest.Route{
 		{rest.HTTP_METHOD_GET, "/v4/:project/registry/version", this.GetVersion},
 		{rest.HTTP_METHOD_GET, "/v4/:project/registry/health", this.ClusterHealth},
+		{rest.HTTP_METHOD_GET, "/v4/:project/registry/health/deep", this.DeepHealth},
 	}
 }
 
@@ -59,6 +63,25 @@ func (this *MainService) ClusterHealth(w http.ResponseWriter, r *http.Request) {
 	controller.WriteResponse(w, respInternal, resp)
 }
 
+// DeepHealth reports the status of each subsystem, responds 503 if any
+// of them is DOWN
+func (this *MainService) DeepHealth(w http.ResponseWriter, r *http.Request) {
+	h := health.DeepCheck(r.Context())
+	data, err := json.Marshal(h)
+	if err != nil {
+		controller.WriteError(w, scerr.ErrInternal, err.Error())
+		return
+	}
+	status := http.StatusOK
+	if h.Status == health.STATUS_DOWN {
+		status = http.StatusServiceUnavailable
+	}
+	w.Header().Set(rest.HEADER_RESPONSE_STATUS, strconv.Itoa(status))
+	w.Header().Set(rest.HEADER_CONTENT_TYPE, rest.CONTENT_TYPE_JSON)
+	w.WriteHeader(status)
+	w.Write(data)
+}
+
 func (this *MainService) GetVersion(w http.ResponseWriter, r *http.Request) {
 	parseVersionOnce.Do(func() {
 		result := Result{
@@ -17,7 +17,6 @@
 package v4
 
 import (
-	"encoding/json"
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/pkg/rest"
 	"github.com/apache/servicecomb-service-center/pkg/util"
@@ -55,7 +54,7 @@ func (this *MicroServiceService) Register(w http.ResponseWriter, r *http.Request
 		return
 	}
 	var request pb.CreateServiceRequest
-	err = json.Unmarshal(message, &request)
+	err = util.JsonUnmarshal(message, &request)
 	if err != nil {
 		log.Errorf(err, "Invalid json: %s", util.BytesToStringWithNoCopy(message))
 		controller.WriteError(w, scerr.ErrInvalidParams, err.Error())
@@ -77,7 +76,7 @@ func (this *MicroServiceService) Update(w http.ResponseWriter, r *http.Request)
 	request := &pb.UpdateServicePropsRequest{
 		ServiceId: r.URL.Query().Get(":serviceId"),
 	}
-	err = json.Unmarshal(message, request)
+	err = util.JsonUnmarshal(message, request)
 	if err != nil {
 		log.Errorf(err, "Invalid json: %s", util.BytesToStringWithNoCopy(message))
 		controller.WriteError(w, scerr.ErrInvalidParams, err.Error())
@@ -107,10 +106,16 @@ func (this *MicroServiceService) Unregister(w http.ResponseWriter, r *http.Reque
 }
 
 func (this *MicroServiceService) GetServices(w http.ResponseWriter, r *http.Request) {
+	mask, err := pb.NewServiceFieldMask(r.URL.Query().Get("fields"))
+	if err != nil {
+		controller.WriteError(w, scerr.ErrInvalidParams, err.Error())
+		return
+	}
 	request := &pb.GetServicesRequest{}
 	resp, _ := core.ServiceAPI.GetServices(r.Context(), request)
 	respInternal := resp.Response
 	resp.Response = nil
+	resp.Services = mask.Services(resp.Services)
 	controller.WriteResponse(w, respInternal, resp)
 }
 
@@ -134,12 +139,18 @@ func (this *MicroServiceService) GetExistence(w http.ResponseWriter, r *http.Req
 }
 
 func (this *MicroServiceService) GetServiceOne(w http.ResponseWriter, r *http.Request) {
+	mask, err := pb.NewServiceFieldMask(r.URL.Query().Get("fields"))
+	if err != nil {
+		controller.WriteError(w, scerr.ErrInvalidParams, err.Error())
+		return
+	}
 	request := &pb.GetServiceRequest{
 		ServiceId: r.URL.Query().Get(":serviceId"),
 	}
 	resp, _ := core.ServiceAPI.GetOne(r.Context(), request)
 	respInternal := resp.Response
 	resp.Response = nil
+	resp.Service = mask.Service(resp.Service)
 	controller.WriteResponse(w, respInternal, resp)
 }
 
@@ -153,7 +164,7 @@ func (this *MicroServiceService) UnregisterServices(w http.ResponseWriter, r *ht
 
 	request := &pb.DelServicesRequest{}
 
-	err = json.Unmarshal(message, request)
+	err = util.JsonUnmarshal(message, request)
 	if err != nil {
 		log.Errorf(err, "Invalid json: %s", util.BytesToStringWithNoCopy(message))
 		controller.WriteError(w, scerr.ErrInvalidParams, err.Error())
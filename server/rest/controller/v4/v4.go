@@ -33,4 +33,5 @@ func initRouter() {
 	roa.RegisterServant(&RuleService{})
 	roa.RegisterServant(&MicroServiceInstanceService{})
 	roa.RegisterServant(&WatchService{})
+	roa.RegisterServant(NewBatchService())
 }
@@ -0,0 +1,108 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package v41
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/apache/servicecomb-service-center/pkg/chain"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Error is the error payload of all the v4.1 APIs
+type Error struct {
+	Code    int32  `json:"code"`
+	Message string `json:"message"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+type ErrorResponse struct {
+	Error *Error `json:"error"`
+}
+
+func writeError(w http.ResponseWriter, status int, e *Error) {
+	data, _ := json.Marshal(&ErrorResponse{Error: e})
+	w.Header().Set(rest.HEADER_RESPONSE_STATUS, strconv.Itoa(status))
+	w.Header().Set(rest.HEADER_ERROR_CODE, strconv.Itoa(int(e.Code)))
+	w.Header().Set(rest.HEADER_CONTENT_TYPE, rest.CONTENT_TYPE_JSON)
+	w.WriteHeader(status)
+	fmt.Fprintln(w, util.BytesToStringWithNoCopy(data))
+}
+
+// errorWriter converts the error payload written by the v4 APIs to the
+// v4.1 one, the other responses are written as they are
+type errorWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *errorWriter) WriteHeader(status int) {
+	if status < http.StatusBadRequest {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	w.status = status
+}
+
+func (w *errorWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		return w.ResponseWriter.Write(b)
+	}
+	// the error payload is written at once by controller.WriteError
+	w.convert(b)
+	return len(b), nil
+}
+
+func (w *errorWriter) convert(b []byte) {
+	status := w.status
+	w.status = 0
+	old := &scerr.Error{}
+	if err := json.Unmarshal(b, old); err != nil || old.Code == 0 {
+		// not written by controller.WriteError
+		old = &scerr.Error{Code: int32(status) * 1000, Message: http.StatusText(status),
+			Detail: string(bytes.TrimSpace(b))}
+	}
+	writeError(w.ResponseWriter, status, &Error{Code: old.Code, Message: old.Message, Detail: old.Detail})
+}
+
+// ErrorHandler writes the errors of the v4.1 APIs in the v4.1 payload, so
+// the v4 APIs served in v4.1 and the handlers rejecting the requests do
+// not need to know the version
+type ErrorHandler struct {
+}
+
+func (h *ErrorHandler) Handle(i *chain.Invocation) {
+	pattern, _ := i.Context().Value(rest.CTX_MATCH_PATTERN).(string)
+	if !strings.HasPrefix(pattern, PREFIX) {
+		i.Next()
+		return
+	}
+	ew := &errorWriter{ResponseWriter: i.Context().Value(rest.CTX_RESPONSE).(http.ResponseWriter)}
+	i.WithContext(rest.CTX_RESPONSE, ew)
+	i.Next(chain.WithFunc(func(ret chain.Result) {
+		if ew.status != 0 {
+			// the status is written without a body
+			ew.convert(nil)
+		}
+	}))
+}
@@ -0,0 +1,218 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package v41
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"github.com/apache/servicecomb-service-center/pkg/pagination"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	DEFAULT_LIMIT = 100
+	MAX_LIMIT     = 1000
+
+	QUERY_LIMIT  = "limit"
+	QUERY_CURSOR = "cursor"
+	QUERY_SORT   = "sort"
+)
+
+var (
+	ErrInvalidLimit  = errors.New("limit must be an integer in [1, 1000]")
+	ErrInvalidCursor = errors.New("cursor is invalid or does not match the sort and filters")
+)
+
+// Entry is a resource in the list, the fields are the values it can be
+// filtered and sorted by
+type Entry struct {
+	Id     string
+	Fields map[string]string
+	Value  interface{}
+}
+
+// ListQuery is parsed from '?limit=&cursor=&sort=[-]field&field=v1,v2',
+// the filters match any of the comma separated values exactly
+type ListQuery struct {
+	Limit   int
+	Cursor  string
+	Sort    string
+	Desc    bool
+	Filters map[string][]string
+	// Scope is the tenant and the resource listed, the cursor is only
+	// valid for the same scope and filters
+	Scope string
+	// Revision is the current revision of the registry
+	Revision int64
+}
+
+// Page is the uniform list payload, pass the next cursor to get the next
+// page, it is empty on the last page
+type Page struct {
+	Items      []interface{} `json:"items"`
+	Total      int           `json:"total"`
+	NextCursor string        `json:"nextCursor,omitempty"`
+	// Revision is the revision of the registry when the page was listed,
+	// the pages are not a snapshot, the items may change between them and
+	// the clients compare the revisions of the pages to detect it
+	Revision int64 `json:"revision,omitempty"`
+}
+
+// ParseListQuery parses the query of the list, the fields are the ones
+// the entries can be filtered and sorted by, other params are ignored
+func ParseListQuery(query url.Values, fields []string) (*ListQuery, error) {
+	q := &ListQuery{
+		Limit:   DEFAULT_LIMIT,
+		Cursor:  query.Get(QUERY_CURSOR),
+		Filters: make(map[string][]string),
+	}
+	if s := query.Get(QUERY_LIMIT); len(s) > 0 {
+		limit, err := strconv.Atoi(s)
+		if err != nil || limit < 1 || limit > MAX_LIMIT {
+			return nil, ErrInvalidLimit
+		}
+		q.Limit = limit
+	}
+	if s := query.Get(QUERY_SORT); len(s) > 0 {
+		if s[0] == '-' {
+			q.Desc, s = true, s[1:]
+		}
+		if !contains(fields, s) {
+			return nil, errors.New("can not sort by '" + s + "', should be one of " + strings.Join(fields, ","))
+		}
+		q.Sort = s
+	}
+	for _, field := range fields {
+		if v := query.Get(field); len(v) > 0 {
+			q.Filters[field] = strings.Split(v, ",")
+		}
+	}
+	return q, nil
+}
+
+func contains(arr []string, s string) bool {
+	for _, v := range arr {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// less compares the numbers like the timestamps by the value
+func less(a, b string) bool {
+	if len(a) != len(b) {
+		if x, err := strconv.ParseInt(a, 10, 64); err == nil {
+			if y, err := strconv.ParseInt(b, 10, 64); err == nil {
+				return x < y
+			}
+		}
+	}
+	return a < b
+}
+
+func (q *ListQuery) sortKey() string {
+	if q.Desc {
+		return "-" + q.Sort
+	}
+	return q.Sort
+}
+
+// before tells whether the entry a is listed before b
+func (q *ListQuery) before(aValue, aId, bValue, bId string) bool {
+	if aValue == bValue {
+		return aId < bId
+	}
+	return less(aValue, bValue) != q.Desc
+}
+
+// scopeKey digests the scope and the filters the cursor is bound to
+func (q *ListQuery) scopeKey() string {
+	fields := make([]string, 0, len(q.Filters))
+	for field := range q.Filters {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	h := sha256.New()
+	h.Write([]byte(q.Scope))
+	for _, field := range fields {
+		values := append([]string(nil), q.Filters[field]...)
+		sort.Strings(values)
+		h.Write([]byte("\x00" + field + "=" + strings.Join(values, ",")))
+	}
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil)[:12])
+}
+
+func (q *ListQuery) match(e *Entry) bool {
+	for field, values := range q.Filters {
+		if !contains(values, e.Fields[field]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Paginate filters and sorts the entries, then returns the page after the
+// cursor, the entries with the same sort value are ordered by the id
+func Paginate(entries []*Entry, q *ListQuery) (*Page, error) {
+	matched := make([]*Entry, 0, len(entries))
+	for _, e := range entries {
+		if q.match(e) {
+			matched = append(matched, e)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		a, b := matched[i], matched[j]
+		return q.before(a.Fields[q.Sort], a.Id, b.Fields[q.Sort], b.Id)
+	})
+
+	start, scope := 0, q.scopeKey()
+	if len(q.Cursor) > 0 {
+		c, err := pagination.Decode(q.Cursor)
+		if err != nil || c.Sort != q.sortKey() || c.Scope != scope {
+			return nil, ErrInvalidCursor
+		}
+		start = sort.Search(len(matched), func(i int) bool {
+			e := matched[i]
+			return q.before(c.Value, c.Id, e.Fields[q.Sort], e.Id)
+		})
+	}
+	end := start + q.Limit
+	if end > len(matched) {
+		end = len(matched)
+	}
+
+	page := &Page{Items: make([]interface{}, 0, end-start), Total: len(matched), Revision: q.Revision}
+	for _, e := range matched[start:end] {
+		page.Items = append(page.Items, e.Value)
+	}
+	if end < len(matched) {
+		last := matched[end-1]
+		page.NextCursor = pagination.Encode(pagination.Token{
+			Scope: scope,
+			Sort:  q.sortKey(),
+			Value: last.Fields[q.Sort],
+			Id:    last.Id,
+		})
+	}
+	return page, nil
+}
@@ -0,0 +1,117 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package v41
+
+import (
+	"net/url"
+	"strconv"
+	"testing"
+)
+
+func testEntries() []*Entry {
+	var entries []*Entry
+	for i, status := range []string{"UP", "DOWN", "UP", "UP", "STARTING"} {
+		id := strconv.Itoa(i)
+		entries = append(entries, &Entry{
+			Id:     id,
+			Fields: map[string]string{"status": status, "timestamp": strconv.Itoa(100 - i*10)},
+			Value:  id,
+		})
+	}
+	return entries
+}
+
+func TestParseListQuery(t *testing.T) {
+	fields := []string{"status", "timestamp"}
+	q, err := ParseListQuery(url.Values{"sort": {"-timestamp"}, "status": {"UP,DOWN"}, "other": {"x"}}, fields)
+	if err != nil || q.Limit != DEFAULT_LIMIT || q.Sort != "timestamp" || !q.Desc ||
+		len(q.Filters) != 1 || len(q.Filters["status"]) != 2 {
+		t.Fatalf("TestParseListQuery failed, %v, %v", q, err)
+	}
+	for _, query := range []url.Values{
+		{"limit": {"0"}},
+		{"limit": {"1001"}},
+		{"limit": {"a"}},
+		{"sort": {"unknown"}},
+	} {
+		if _, err := ParseListQuery(query, fields); err == nil {
+			t.Fatalf("TestParseListQuery failed, %v", query)
+		}
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	fields := []string{"status", "timestamp"}
+	q, _ := ParseListQuery(url.Values{"limit": {"2"}, "status": {"UP"}, "sort": {"timestamp"}}, fields)
+	q.Scope, q.Revision = "default/default /v4.1/default/registry/microservices", 10
+	var ids []interface{}
+	for {
+		page, err := Paginate(testEntries(), q)
+		if err != nil {
+			t.Fatalf("TestPaginate failed, %v", err)
+		}
+		if page.Total != 3 || page.Revision != q.Revision {
+			t.Fatalf("TestPaginate failed, %v", page)
+		}
+		ids = append(ids, page.Items...)
+		if len(page.NextCursor) == 0 {
+			break
+		}
+		// the later pages are listed at the current revision
+		q.Cursor, q.Revision = page.NextCursor, q.Revision+1
+	}
+	// timestamps of 0, 2, 3 are 100, 80, 70
+	if len(ids) != 3 || ids[0] != "3" || ids[1] != "2" || ids[2] != "0" {
+		t.Fatalf("TestPaginate failed, %v", ids)
+	}
+
+	// the cursor of another scope or filters is invalid
+	next, _ := Paginate(testEntries(), &ListQuery{Limit: 2, Sort: "timestamp",
+		Filters: map[string][]string{"status": {"UP"}}, Scope: q.Scope})
+	for _, other := range []*ListQuery{
+		{Limit: 2, Sort: "timestamp", Filters: map[string][]string{"status": {"UP"}}, Scope: "other/default"},
+		{Limit: 2, Sort: "timestamp", Filters: map[string][]string{"status": {"UP", "DOWN"}}, Scope: q.Scope},
+		{Limit: 2, Sort: "timestamp", Scope: q.Scope},
+	} {
+		other.Cursor = next.NextCursor
+		if _, err := Paginate(testEntries(), other); err != ErrInvalidCursor {
+			t.Fatalf("TestPaginate failed, %v", err)
+		}
+	}
+	same := &ListQuery{Limit: 2, Sort: "timestamp", Filters: map[string][]string{"status": {"UP"}},
+		Scope: q.Scope, Cursor: next.NextCursor}
+	if _, err := Paginate(testEntries(), same); err != nil {
+		t.Fatalf("TestPaginate failed, %v", err)
+	}
+
+	// the cursor of another sort is invalid
+	desc, _ := ParseListQuery(url.Values{"sort": {"-timestamp"}}, fields)
+	desc.Scope, desc.Cursor = q.Scope, q.Cursor
+	if _, err := Paginate(testEntries(), desc); err != ErrInvalidCursor {
+		t.Fatalf("TestPaginate failed, %v", err)
+	}
+	desc.Cursor = "!"
+	if _, err := Paginate(testEntries(), desc); err != ErrInvalidCursor {
+		t.Fatalf("TestPaginate failed, %v", err)
+	}
+
+	desc.Cursor = ""
+	page, err := Paginate(testEntries(), desc)
+	if err != nil || len(page.Items) != 5 || page.Items[0] != "0" || len(page.NextCursor) != 0 {
+		t.Fatalf("TestPaginate failed, %v, %v", page, err)
+	}
+}
@@ -0,0 +1,247 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package v41
+
+import (
+	roa "github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/core/backend"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/rest/controller"
+	"github.com/apache/servicecomb-service-center/server/rest/controller/v4"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	V4_PREFIX       = "/v4/:project/registry/"
+	REGISTRY_PREFIX = PREFIX + ":project/registry/"
+)
+
+var (
+	serviceFields  = []string{"serviceId", "appId", "serviceName", "version", "environment", "status", "level", "alias", "timestamp", "modTimestamp", "createRevision", "modRevision"}
+	instanceFields = []string{"instanceId", "hostName", "status", "version", "timestamp", "modTimestamp", "createRevision", "modRevision"}
+	schemaFields   = []string{"schemaId", "summary"}
+	tagFields      = []string{"key", "value"}
+	ruleFields     = []string{"ruleId", "ruleType", "attribute", "pattern", "timestamp", "modTimestamp"}
+)
+
+// compatibles are the v4 APIs served in v4.1 with the v4.1 error payload
+var compatibles = []roa.ROAServantService{
+	&v4.MicroServiceService{},
+	&v4.MicroServiceInstanceService{},
+	&v4.SchemaService{},
+	&v4.TagService{},
+	&v4.RuleService{},
+}
+
+// Tag is the entry of the tags list
+type Tag struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// RegistryService serves the v4.1 registry APIs, the lists are paginated,
+// and the other APIs are the v4 ones, the errors of all of them are
+// written in the v4.1 payload by the ErrorHandler
+type RegistryService struct {
+}
+
+func (this *RegistryService) URLPatterns() []roa.Route {
+	routes := []roa.Route{
+		{roa.HTTP_METHOD_GET, REGISTRY_PREFIX + "microservices", this.ListServices},
+		{roa.HTTP_METHOD_GET, REGISTRY_PREFIX + "microservices/:serviceId/instances", this.ListInstances},
+		{roa.HTTP_METHOD_GET, REGISTRY_PREFIX + "microservices/:serviceId/schemas", this.ListSchemas},
+		{roa.HTTP_METHOD_GET, REGISTRY_PREFIX + "microservices/:serviceId/tags", this.ListTags},
+		{roa.HTTP_METHOD_GET, REGISTRY_PREFIX + "microservices/:serviceId/rules", this.ListRules},
+	}
+	exists := make(map[string]struct{}, len(routes))
+	for _, route := range routes {
+		exists[route.Method+" "+route.Path] = struct{}{}
+	}
+	for _, group := range compatibles {
+		for _, route := range group.URLPatterns() {
+			if !strings.HasPrefix(route.Path, V4_PREFIX) {
+				continue
+			}
+			route.Path = REGISTRY_PREFIX + route.Path[len(V4_PREFIX):]
+			if _, ok := exists[route.Method+" "+route.Path]; ok {
+				continue
+			}
+			routes = append(routes, route)
+		}
+	}
+	return routes
+}
+
+// writePage paginates the entries by the query of the request
+func writePage(w http.ResponseWriter, r *http.Request, fields []string, entries []*Entry) {
+	q, err := ParseListQuery(r.URL.Query(), fields)
+	if err != nil {
+		controller.WriteError(w, scerr.ErrInvalidParams, err.Error())
+		return
+	}
+	q.Scope = util.ParseDomainProject(r.Context()) + " " + r.URL.Path
+	q.Revision = backend.Revision()
+	page, err := Paginate(entries, q)
+	if err != nil {
+		controller.WriteError(w, scerr.ErrInvalidParams, err.Error())
+		return
+	}
+	controller.WriteResponse(w, nil, page)
+}
+
+func (this *RegistryService) ListServices(w http.ResponseWriter, r *http.Request) {
+	resp, _ := core.ServiceAPI.GetServices(r.Context(), &pb.GetServicesRequest{})
+	if resp.Response.GetCode() != pb.Response_SUCCESS {
+		controller.WriteResponse(w, resp.Response, nil)
+		return
+	}
+	entries := make([]*Entry, 0, len(resp.Services))
+	for _, s := range resp.Services {
+		entries = append(entries, &Entry{
+			Id: s.ServiceId,
+			Fields: map[string]string{
+				"serviceId":      s.ServiceId,
+				"appId":          s.AppId,
+				"serviceName":    s.ServiceName,
+				"version":        s.Version,
+				"environment":    s.Environment,
+				"status":         s.Status,
+				"level":          s.Level,
+				"alias":          s.Alias,
+				"timestamp":      s.Timestamp,
+				"modTimestamp":   s.ModTimestamp,
+				"createRevision": strconv.FormatInt(s.CreateRevision, 10),
+				"modRevision":    strconv.FormatInt(s.ModRevision, 10),
+			},
+			Value: s,
+		})
+	}
+	writePage(w, r, serviceFields, entries)
+}
+
+func (this *RegistryService) ListInstances(w http.ResponseWriter, r *http.Request) {
+	resp, _ := core.InstanceAPI.GetInstances(r.Context(), &pb.GetInstancesRequest{
+		ConsumerServiceId: r.Header.Get("X-ConsumerId"),
+		ProviderServiceId: r.URL.Query().Get(":serviceId"),
+	})
+	if resp.Response.GetCode() != pb.Response_SUCCESS {
+		controller.WriteResponse(w, resp.Response, nil)
+		return
+	}
+	entries := make([]*Entry, 0, len(resp.Instances))
+	for _, inst := range resp.Instances {
+		entries = append(entries, &Entry{
+			Id: inst.InstanceId,
+			Fields: map[string]string{
+				"instanceId":     inst.InstanceId,
+				"hostName":       inst.HostName,
+				"status":         inst.Status,
+				"version":        inst.Version,
+				"timestamp":      inst.Timestamp,
+				"modTimestamp":   inst.ModTimestamp,
+				"createRevision": strconv.FormatInt(inst.CreateRevision, 10),
+				"modRevision":    strconv.FormatInt(inst.ModRevision, 10),
+			},
+			Value: inst,
+		})
+	}
+	writePage(w, r, instanceFields, entries)
+}
+
+// ListSchemas lists the schema ids and summaries, the schema contents are
+// returned with '?withSchema=1'
+func (this *RegistryService) ListSchemas(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	withSchema := query.Get("withSchema")
+	if withSchema != "0" && withSchema != "1" && strings.TrimSpace(withSchema) != "" {
+		controller.WriteError(w, scerr.ErrInvalidParams, "parameter withSchema must be 1 or 0")
+		return
+	}
+	resp, _ := core.ServiceAPI.GetAllSchemaInfo(r.Context(), &pb.GetAllSchemaRequest{
+		ServiceId:  query.Get(":serviceId"),
+		WithSchema: withSchema == "1",
+	})
+	if resp.Response.GetCode() != pb.Response_SUCCESS {
+		controller.WriteResponse(w, resp.Response, nil)
+		return
+	}
+	entries := make([]*Entry, 0, len(resp.Schemas))
+	for _, schema := range resp.Schemas {
+		entries = append(entries, &Entry{
+			Id: schema.SchemaId,
+			Fields: map[string]string{
+				"schemaId": schema.SchemaId,
+				"summary":  schema.Summary,
+			},
+			Value: schema,
+		})
+	}
+	writePage(w, r, schemaFields, entries)
+}
+
+func (this *RegistryService) ListTags(w http.ResponseWriter, r *http.Request) {
+	resp, _ := core.ServiceAPI.GetTags(r.Context(), &pb.GetServiceTagsRequest{
+		ServiceId: r.URL.Query().Get(":serviceId"),
+	})
+	if resp.Response.GetCode() != pb.Response_SUCCESS {
+		controller.WriteResponse(w, resp.Response, nil)
+		return
+	}
+	entries := make([]*Entry, 0, len(resp.Tags))
+	for key, value := range resp.Tags {
+		entries = append(entries, &Entry{
+			Id: key,
+			Fields: map[string]string{
+				"key":   key,
+				"value": value,
+			},
+			Value: &Tag{Key: key, Value: value},
+		})
+	}
+	writePage(w, r, tagFields, entries)
+}
+
+func (this *RegistryService) ListRules(w http.ResponseWriter, r *http.Request) {
+	resp, _ := core.ServiceAPI.GetRule(r.Context(), &pb.GetServiceRulesRequest{
+		ServiceId: r.URL.Query().Get(":serviceId"),
+	})
+	if resp.Response.GetCode() != pb.Response_SUCCESS {
+		controller.WriteResponse(w, resp.Response, nil)
+		return
+	}
+	entries := make([]*Entry, 0, len(resp.Rules))
+	for _, rule := range resp.Rules {
+		entries = append(entries, &Entry{
+			Id: rule.RuleId,
+			Fields: map[string]string{
+				"ruleId":       rule.RuleId,
+				"ruleType":     rule.RuleType,
+				"attribute":    rule.Attribute,
+				"pattern":      rule.Pattern,
+				"timestamp":    rule.Timestamp,
+				"modTimestamp": rule.ModTimestamp,
+			},
+			Value: rule,
+		})
+	}
+	writePage(w, r, ruleFields, entries)
+}
@@ -53,6 +53,23 @@ var (
 			Objectives: prometheus.DefObjectives,
 		}, []string{"method", "instance", "api", "domain"})
 
+	reqLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metric.FamilyName,
+			Subsystem: "http",
+			Name:      "request_latency_seconds",
+			Help:      "HTTP request latency histogram of ROA handler",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "code", "instance", "api", "domain"})
+
+	failedRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metric.FamilyName,
+			Subsystem: "http",
+			Name:      "error_total",
+			Help:      "Counter of failed requests processed by ROA handler by error code",
+		}, []string{"method", "code", "instance", "api", "domain", "error"})
+
 	queryPerSeconds = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: metric.FamilyName,
@@ -63,28 +80,43 @@ var (
 )
 
 func init() {
-	prometheus.MustRegister(incomingRequests, successfulRequests, reqDurations, queryPerSeconds)
+	prometheus.MustRegister(incomingRequests, successfulRequests, reqDurations, reqLatency,
+		failedRequests, queryPerSeconds)
 
 	RegisterServerHandler("/metrics", prometheus.Handler())
 }
 
 func ReportRequestCompleted(w http.ResponseWriter, r *http.Request, start time.Time) {
 	instance := metric.InstanceName()
-	elapsed := float64(time.Since(start).Nanoseconds()) / float64(time.Microsecond)
+	duration := time.Since(start)
+	elapsed := float64(duration.Nanoseconds()) / float64(time.Microsecond)
 	route, _ := r.Context().Value(rest.CTX_MATCH_FUNC).(string)
 	domain := util.ParseDomain(r.Context())
 
+	success, code := codeOf(w.Header())
+
 	if strings.Index(r.Method, "WATCH") != 0 {
 		reqDurations.WithLabelValues(r.Method, instance, route, domain).Observe(elapsed)
+		reqLatency.WithLabelValues(r.Method, code, instance, route, domain).Observe(duration.Seconds())
 	}
 
-	success, code := codeOf(w.Header())
-
 	incomingRequests.WithLabelValues(r.Method, code, instance, route, domain).Inc()
 
 	if success {
 		successfulRequests.WithLabelValues(r.Method, code, instance, route, domain).Inc()
+		return
+	}
+
+	failedRequests.WithLabelValues(r.Method, code, instance, route, domain, errorCodeOf(w.Header())).Inc()
+}
+
+// errorCodeOf returns the service center error code written by
+// controller.WriteError, or the status code if it is not present
+func errorCodeOf(h http.Header) string {
+	if code := h.Get(rest.HEADER_ERROR_CODE); len(code) > 0 {
+		return code
 	}
+	return h.Get(rest.HEADER_RESPONSE_STATUS)
 }
 
 func codeOf(h http.Header) (bool, string) {
@@ -30,6 +30,10 @@ func LoadConfig() (srvCfg *rest.ServerConfig, err error) {
 	readTimeout, _ := time.ParseDuration(core.ServerInfo.Config.ReadTimeout)
 	idleTimeout, _ := time.ParseDuration(core.ServerInfo.Config.IdleTimeout)
 	writeTimeout, _ := time.ParseDuration(core.ServerInfo.Config.WriteTimeout)
+	graceTimeout, parseErr := time.ParseDuration(core.ServerInfo.Config.GraceTimeout)
+	if parseErr != nil {
+		graceTimeout = srvCfg.GraceTimeout
+	}
 	maxHeaderBytes := int(core.ServerInfo.Config.MaxHeaderBytes)
 	var tlsConfig *tls.Config
 	if core.ServerInfo.Config.SslEnabled {
@@ -42,8 +46,18 @@ func LoadConfig() (srvCfg *rest.ServerConfig, err error) {
 	srvCfg.ReadTimeout = readTimeout
 	srvCfg.IdleTimeout = idleTimeout
 	srvCfg.WriteTimeout = writeTimeout
+	srvCfg.GraceTimeout = graceTimeout
 	srvCfg.MaxHeaderBytes = maxHeaderBytes
 	srvCfg.TLSConfig = tlsConfig
+	if keepAliveTimeout, err := time.ParseDuration(core.ServerInfo.Config.KeepAliveTimeout); err == nil {
+		srvCfg.KeepAliveTimeout = keepAliveTimeout
+	}
+	srvCfg.EnableHTTP2 = core.ServerInfo.Config.EnableHTTP2
+	srvCfg.EnableH2C = core.ServerInfo.Config.EnableH2C
+	if streams := core.ServerInfo.Config.MaxConcurrentStreams; streams > 0 {
+		srvCfg.MaxConcurrentStreams = uint32(streams)
+	}
+	srvCfg.MaxConnections = int(core.ServerInfo.Config.MaxConnections)
 	srvCfg.Handler = DefaultServerMux
 	return
 }
@@ -0,0 +1,50 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package retention
+
+import (
+	"github.com/astaxie/beego"
+	"time"
+)
+
+const DEFAULT_PURGE_INTERVAL = time.Hour
+
+var (
+	cfg    Config
+	purger *Purger
+)
+
+func init() {
+	cfg = LoadConfig()
+	purger = NewPurger(cfg)
+}
+
+type Config struct {
+	// PurgeInterval is how often the expired records are purged
+	PurgeInterval time.Duration
+}
+
+func LoadConfig() Config {
+	c := Config{
+		PurgeInterval: DEFAULT_PURGE_INTERVAL,
+	}
+	d, err := time.ParseDuration(beego.AppConfig.DefaultString("retention_purge_interval", ""))
+	if err == nil && d >= time.Minute {
+		c.PurgeInterval = d
+	}
+	return c
+}
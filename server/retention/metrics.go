@@ -0,0 +1,50 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package retention
+
+import (
+	"github.com/apache/servicecomb-service-center/server/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	purgedCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metric.FamilyName,
+			Subsystem: "retention",
+			Name:      "purged_total",
+			Help:      "Counter of the expired records purged",
+		}, []string{"instance", "policy"})
+
+	lastPurgeGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metric.FamilyName,
+			Subsystem: "retention",
+			Name:      "last_purge_timestamp_seconds",
+			Help:      "Unix time of the last successful purge",
+		}, []string{"instance", "policy"})
+)
+
+func init() {
+	prometheus.MustRegister(purgedCounter, lastPurgeGauge)
+}
+
+func ReportPurged(policy string, n int64, now int64) {
+	instance := metric.InstanceName()
+	purgedCounter.WithLabelValues(instance, policy).Add(float64(n))
+	lastPurgeGauge.WithLabelValues(instance, policy).Set(float64(now))
+}
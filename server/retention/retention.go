@@ -0,0 +1,155 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package retention
+
+import (
+	"errors"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/core/backend"
+	"github.com/apache/servicecomb-service-center/server/job"
+	"github.com/apache/servicecomb-service-center/server/mux"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	"golang.org/x/net/context"
+	"sort"
+	"sync"
+	"time"
+)
+
+var ErrNotLeader = errors.New("the retention purge is running by another service center instance")
+
+// Policy describes a kind of historical records keyed by
+// {root}/{stamp}/..., the stamps are formatted in UTC so that the expired
+// records are always the range before the stamp of the expire time.
+// The usage rollups and the capacity snapshots are registered by their
+// owners, other records kept over time should register a policy as well
+// instead of deleting by themselves.
+type Policy struct {
+	Name  string
+	Roots []string
+	// Format is the time layout of the stamps
+	Format    string
+	Retention time.Duration
+}
+
+func (p *Policy) Expire(now time.Time) string {
+	return now.UTC().Add(-p.Retention).Format(p.Format)
+}
+
+type Purger struct {
+	Cfg Config
+
+	lock     sync.RWMutex
+	policies map[string]*Policy
+	once     sync.Once
+}
+
+func NewPurger(c Config) *Purger {
+	return &Purger{
+		Cfg:      c,
+		policies: make(map[string]*Policy),
+	}
+}
+
+// Register adds the policy and starts purging in the background when the
+// first policy is registered
+func (p *Purger) Register(policy Policy) {
+	if len(policy.Name) == 0 || len(policy.Roots) == 0 || len(policy.Format) == 0 || policy.Retention <= 0 {
+		log.Warnf("invalid retention policy %+v, ignore it", policy)
+		return
+	}
+	p.lock.Lock()
+	p.policies[policy.Name] = &policy
+	p.lock.Unlock()
+	log.Infof("retention policy %s is registered, keep %s", policy.Name, policy.Retention)
+	p.once.Do(func() {
+		job.Register(job.Job{
+			Name:     "retention",
+			Interval: p.Cfg.PurgeInterval,
+			Run: func(ctx context.Context) error {
+				_, err := p.TryPurge(ctx, time.Now())
+				return err
+			},
+		})
+	})
+}
+
+func (p *Purger) Policies() []Policy {
+	p.lock.RLock()
+	policies := make([]Policy, 0, len(p.policies))
+	for _, policy := range p.policies {
+		policies = append(policies, *policy)
+	}
+	p.lock.RUnlock()
+	sort.Slice(policies, func(i, j int) bool {
+		return policies[i].Name < policies[j].Name
+	})
+	return policies
+}
+
+// TryPurge purges the expired records of all the policies if no other
+// service center is doing it, returns the purged counts of each policy
+func (p *Purger) TryPurge(ctx context.Context, now time.Time) (map[string]int64, error) {
+	lock, err := mux.Try(mux.RETENTION_LOCK)
+	if lock == nil {
+		log.Debugf("can not purge the expired records by this service center instance now, %v", err)
+		return nil, ErrNotLeader
+	}
+	defer lock.Unlock()
+
+	counts := make(map[string]int64)
+	for _, policy := range p.Policies() {
+		n, err := Purge(ctx, policy, now)
+		if err != nil {
+			log.Errorf(err, "purge the expired %s records failed", policy.Name)
+			continue
+		}
+		counts[policy.Name] = n
+		ReportPurged(policy.Name, n, now.Unix())
+		if n > 0 {
+			log.Infof("%d %s records before %s are purged", n, policy.Name, policy.Expire(now))
+		}
+	}
+	return counts, nil
+}
+
+// Purge deletes the records of the policy older than the retention
+func Purge(ctx context.Context, policy Policy, now time.Time) (int64, error) {
+	expire := policy.Expire(now)
+	var purged int64
+	for _, root := range policy.Roots {
+		opts := []registry.PluginOpOption{
+			registry.WithStrKey(root + core.SPLIT),
+			registry.WithStrEndKey(util.StringJoin([]string{root, expire}, core.SPLIT)),
+		}
+		resp, err := backend.Registry().Do(ctx, append(opts, registry.DEL)...)
+		if err != nil {
+			return purged, err
+		}
+		purged += resp.Count
+	}
+	return purged, nil
+}
+
+func Register(policy Policy) {
+	purger.Register(policy)
+}
+
+func Policies() []Policy {
+	return purger.Policies()
+}
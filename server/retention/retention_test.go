@@ -0,0 +1,73 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package retention
+
+import (
+	"github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/core/backend"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	"golang.org/x/net/context"
+	"testing"
+	"time"
+)
+
+func TestPurge(t *testing.T) {
+	ctx := context.Background()
+	root := "/cse-sr/retention_test"
+	now := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	policy := Policy{Name: "test", Roots: []string{root}, Format: "20060102", Retention: 48 * time.Hour}
+	if expire := policy.Expire(now); expire != "20261015" {
+		t.Fatalf("TestPurge failed, %s", expire)
+	}
+
+	for _, date := range []string{"20261013", "20261014", "20261015", "20261016"} {
+		_, err := backend.Registry().Do(ctx, registry.PUT,
+			registry.WithStrKey(root+core.SPLIT+date+core.SPLIT+"a"), registry.WithStrValue("1"))
+		if err != nil {
+			t.Fatalf("TestPurge failed, %v", err)
+		}
+	}
+
+	n, err := Purge(ctx, policy, now)
+	if err != nil || n != 2 {
+		t.Fatalf("TestPurge failed, %d, %v", n, err)
+	}
+	resp, err := backend.Registry().Do(ctx, registry.GET,
+		registry.WithStrKey(root+core.SPLIT), registry.WithPrefix(), registry.WithCountOnly())
+	if err != nil || resp.Count != 2 {
+		t.Fatalf("TestPurge failed, %v", err)
+	}
+
+	n, err = Purge(ctx, policy, now)
+	if err != nil || n != 0 {
+		t.Fatalf("TestPurge failed, %d, %v", n, err)
+	}
+}
+
+func TestPurger_Register(t *testing.T) {
+	p := NewPurger(Config{PurgeInterval: time.Hour})
+	p.Register(Policy{Name: "invalid", Roots: []string{"/a"}, Format: "20060102"})
+	if len(p.Policies()) != 0 {
+		t.Fatalf("TestPurger_Register failed")
+	}
+	p.Register(Policy{Name: "b", Roots: []string{"/b"}, Format: "20060102", Retention: time.Hour})
+	p.Register(Policy{Name: "a", Roots: []string{"/a"}, Format: "20060102", Retention: time.Hour})
+	policies := p.Policies()
+	if len(policies) != 2 || policies[0].Name != "a" || policies[1].Name != "b" {
+		t.Fatalf("TestPurger_Register failed, %v", policies)
+	}
+}
@@ -0,0 +1,100 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package rpc
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/maintenance"
+	serviceUtil "github.com/apache/servicecomb-service-center/server/service/util"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"strconv"
+)
+
+// REVISION_KEY is the metadata key of the resource revision, the same as
+// the REST header X-Resource-Revision
+const REVISION_KEY = "x-resource-revision"
+
+// RETRY_AFTER_KEY is the metadata key of the seconds the clients should
+// wait before retrying, the same as the REST header Retry-After
+const RETRY_AFTER_KEY = "retry-after"
+
+// serverStream overrides the context of the stream
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// withRequestId accepts the request id in the client metadata or
+// generates one, and returns it in the response header
+func withRequestId(ctx context.Context) context.Context {
+	id := util.FromMetadata(ctx, util.CtxRequestId)
+	if !util.IsValidRequestId(id) {
+		id = util.GenerateUuid()
+	}
+	grpc.SetHeader(ctx, metadata.Pairs(util.CtxRequestId, id))
+	return util.SetRequestId(ctx, id)
+}
+
+// withRevision accepts the revision the client holds in the metadata, the
+// APIs like Find skip the unmodified resources of the same revision
+func withRevision(ctx context.Context) context.Context {
+	if rev := util.FromMetadata(ctx, REVISION_KEY); len(rev) > 0 {
+		return util.SetContext(ctx, serviceUtil.CTX_REQUEST_REVISION, rev)
+	}
+	return ctx
+}
+
+// setRevision returns the revision of the response in the header
+func setRevision(ctx context.Context) {
+	if rev, _ := ctx.Value(serviceUtil.CTX_RESPONSE_REVISION).(string); len(rev) > 0 {
+		grpc.SetHeader(ctx, metadata.Pairs(REVISION_KEY, rev))
+	}
+}
+
+// maintenanceHandler rejects the mutating methods in maintenance like the
+// REST handler, the heartbeats and reads are served if allowed
+func maintenanceHandler(handler grpc.UnaryHandler) grpc.UnaryHandler {
+	return func(ctx context.Context, req interface{}) (interface{}, error) {
+		retryAfter, err := maintenance.CheckRPC(util.ParseApi(ctx))
+		if err != nil {
+			grpc.SetHeader(ctx, metadata.Pairs(RETRY_AFTER_KEY, strconv.Itoa(int(retryAfter.Seconds()))))
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+	ctx = util.SetContext(withRevision(withRequestId(ctx)), util.CtxApi, apiOf(info.FullMethod))
+	resp, err := unaryMetricsInterceptor(ctx, req, info, maintenanceHandler(handler))
+	setRevision(ctx)
+	return resp, err
+}
+
+func streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+	ctx := util.SetContext(withRequestId(ss.Context()), util.CtxApi, apiOf(info.FullMethod))
+	ss = &serverStream{ServerStream: ss, ctx: ctx}
+	return streamMetricsInterceptor(srv, ss, info, handler)
+}
@@ -0,0 +1,43 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package rpc
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	serviceUtil "github.com/apache/servicecomb-service-center/server/service/util"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/metadata"
+	"testing"
+)
+
+func TestWithRevision(t *testing.T) {
+	ctx := withRevision(context.Background())
+	if rev, _ := ctx.Value(serviceUtil.CTX_REQUEST_REVISION).(string); len(rev) != 0 {
+		t.Fatalf("TestWithRevision failed, %s", rev)
+	}
+
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(REVISION_KEY, "abc"))
+	ctx = withRevision(ctx)
+	if rev, _ := ctx.Value(serviceUtil.CTX_REQUEST_REVISION).(string); rev != "abc" {
+		t.Fatalf("TestWithRevision failed, %s", rev)
+	}
+	// the response revision is set in the same context by the APIs
+	util.SetContext(ctx, serviceUtil.CTX_RESPONSE_REVISION, "def")
+	if rev, _ := ctx.Value(serviceUtil.CTX_RESPONSE_REVISION).(string); rev != "def" {
+		t.Fatalf("TestWithRevision failed, %s", rev)
+	}
+}
@@ -0,0 +1,127 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package rpc
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/metric"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	incomingRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metric.FamilyName,
+			Subsystem: "grpc",
+			Name:      "request_total",
+			Help:      "Counter of requests received into gRPC handler",
+		}, []string{"code", "instance", "api", "domain"})
+
+	failedRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metric.FamilyName,
+			Subsystem: "grpc",
+			Name:      "error_total",
+			Help:      "Counter of failed requests processed by gRPC handler by error code",
+		}, []string{"code", "instance", "api", "domain", "error"})
+
+	reqLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metric.FamilyName,
+			Subsystem: "grpc",
+			Name:      "request_latency_seconds",
+			Help:      "gRPC request latency histogram of unary handler",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"code", "instance", "api", "domain"})
+)
+
+func init() {
+	prometheus.MustRegister(incomingRequests, failedRequests, reqLatency)
+}
+
+type responseGetter interface {
+	GetResponse() *pb.Response
+}
+
+// apiOf returns the method name of the full method
+// like '/com.huawei.paas.cse.serviceregistry.api.ServiceCtrl/Create'
+func apiOf(fullMethod string) string {
+	return fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+}
+
+// codeOf returns the status code and the error code of the result, the
+// handlers report the errors in the response rather than the grpc error
+func codeOf(resp interface{}, err error) (code string, errCode string) {
+	if err != nil {
+		if e, ok := err.(*scerr.Error); ok {
+			return strconv.Itoa(e.StatusCode()), strconv.Itoa(int(e.Code))
+		}
+		c := grpc.Code(err).String()
+		return c, c
+	}
+	if r, ok := resp.(responseGetter); ok {
+		if rp := r.GetResponse(); rp != nil && rp.GetCode() != pb.Response_SUCCESS {
+			e := scerr.NewError(rp.GetCode(), "")
+			return strconv.Itoa(e.StatusCode()), strconv.Itoa(int(e.Code))
+		}
+	}
+	return "200", ""
+}
+
+func ReportRequestCompleted(ctx context.Context, fullMethod string, resp interface{}, err error, start time.Time) {
+	instance := metric.InstanceName()
+	api := apiOf(fullMethod)
+	domain := util.ParseDomain(ctx)
+	code, errCode := codeOf(resp, err)
+
+	reqLatency.WithLabelValues(code, instance, api, domain).Observe(time.Since(start).Seconds())
+	incomingRequests.WithLabelValues(code, instance, api, domain).Inc()
+	if len(errCode) > 0 {
+		failedRequests.WithLabelValues(code, instance, api, domain, errCode).Inc()
+	}
+}
+
+func unaryMetricsInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	ReportRequestCompleted(ctx, info.FullMethod, resp, err, start)
+	return resp, err
+}
+
+// streamMetricsInterceptor only counts the streams, the latency of the
+// long-lived watch streams is meaningless
+func streamMetricsInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+	err := handler(srv, ss)
+	code, errCode := codeOf(nil, err)
+	instance := metric.InstanceName()
+	api := apiOf(info.FullMethod)
+	domain := util.ParseDomain(ss.Context())
+	incomingRequests.WithLabelValues(code, instance, api, domain).Inc()
+	if len(errCode) > 0 {
+		failedRequests.WithLabelValues(code, instance, api, domain, errCode).Inc()
+	}
+	return err
+}
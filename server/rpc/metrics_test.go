@@ -0,0 +1,48 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package rpc
+
+import (
+	"errors"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"testing"
+)
+
+func TestCodeOf(t *testing.T) {
+	if api := apiOf("/com.huawei.paas.cse.serviceregistry.api.ServiceCtrl/Create"); api != "Create" {
+		t.Fatalf("TestCodeOf failed, %s", api)
+	}
+
+	code, errCode := codeOf(&pb.CreateServiceResponse{Response: pb.CreateResponse(pb.Response_SUCCESS, "")}, nil)
+	if code != "200" || len(errCode) != 0 {
+		t.Fatalf("TestCodeOf failed, %s %s", code, errCode)
+	}
+	code, errCode = codeOf(&pb.CreateServiceResponse{
+		Response: pb.CreateResponse(scerr.ErrServiceNotExists, "not exist")}, nil)
+	if code != "400" || errCode != "400012" {
+		t.Fatalf("TestCodeOf failed, %s %s", code, errCode)
+	}
+	code, errCode = codeOf(nil, scerr.NewError(scerr.ErrInternal, "internal"))
+	if code != "500" || errCode != "500003" {
+		t.Fatalf("TestCodeOf failed, %s %s", code, errCode)
+	}
+	code, errCode = codeOf(nil, errors.New("unknown"))
+	if code != "Unknown" || errCode != "Unknown" {
+		t.Fatalf("TestCodeOf failed, %s %s", code, errCode)
+	}
+}
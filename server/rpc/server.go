@@ -21,8 +21,10 @@ import (
 	"github.com/apache/servicecomb-service-center/pkg/rpc"
 	"github.com/apache/servicecomb-service-center/server/core"
 	"github.com/apache/servicecomb-service-center/server/plugin"
+	nf "github.com/apache/servicecomb-service-center/server/service/notification"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
 	"net"
 )
 
@@ -35,8 +37,29 @@ func (srv *Server) Serve() error {
 	return srv.Server.Serve(srv.Listener)
 }
 
+// keepaliveOptions pings the client every ping interval, and closes the
+// connection if the ping is not acked within the idle timeout
+func keepaliveOptions() []grpc.ServerOption {
+	cfg := nf.RPCKeepalive()
+	params := keepalive.ServerParameters{Time: cfg.PingInterval}
+	if cfg.IdleTimeout > 0 {
+		params.Timeout = cfg.IdleTimeout
+	}
+	return []grpc.ServerOption{
+		grpc.KeepaliveParams(params),
+		// permit the client pings at the same interval
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             cfg.PingInterval,
+			PermitWithoutStream: true,
+		}),
+	}
+}
+
 func NewServer(ipAddr string) (_ *Server, err error) {
 	var grpcSrv *grpc.Server
+	opts := append(keepaliveOptions(),
+		grpc.UnaryInterceptor(unaryInterceptor),
+		grpc.StreamInterceptor(streamInterceptor))
 	if core.ServerInfo.Config.SslEnabled {
 		tlsConfig, err := plugin.Plugins().TLS().ServerConfig()
 		if err != nil {
@@ -44,9 +67,9 @@ func NewServer(ipAddr string) (_ *Server, err error) {
 			return nil, err
 		}
 		creds := credentials.NewTLS(tlsConfig)
-		grpcSrv = grpc.NewServer(grpc.Creds(creds))
+		grpcSrv = grpc.NewServer(append(opts, grpc.Creds(creds))...)
 	} else {
-		grpcSrv = grpc.NewServer()
+		grpcSrv = grpc.NewServer(opts...)
 	}
 
 	rpc.RegisterGRpcServer(grpcSrv)
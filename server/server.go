@@ -23,6 +23,8 @@ import (
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/server/core"
 	"github.com/apache/servicecomb-service-center/server/core/backend"
+	"github.com/apache/servicecomb-service-center/server/job"
+	"github.com/apache/servicecomb-service-center/server/migration"
 	"github.com/apache/servicecomb-service-center/server/mux"
 	"github.com/apache/servicecomb-service-center/server/plugin"
 	nf "github.com/apache/servicecomb-service-center/server/service/notification"
@@ -98,6 +100,20 @@ func (s *ServiceCenterServer) loadOrUpgradeServerVersion() {
 	lock.Unlock()
 }
 
+func (s *ServiceCenterServer) migrateData() {
+	lock, err := mux.Lock(mux.GLOBAL_LOCK)
+	if err != nil {
+		log.Errorf(err, "wait for server ready failed")
+		os.Exit(1)
+	}
+	err = migration.Run(context.Background())
+	lock.Unlock()
+	if err != nil {
+		log.Errorf(err, "refuse to start, the data in the backend is incompatible")
+		os.Exit(1)
+	}
+}
+
 func (s *ServiceCenterServer) compactBackendService() {
 	delta := core.ServerInfo.Config.CompactIndexDelta
 	if delta <= 0 || len(core.ServerInfo.Config.CompactInterval) == 0 {
@@ -108,25 +124,14 @@ func (s *ServiceCenterServer) compactBackendService() {
 		log.Errorf(err, "invalid compact interval %s, reset to default interval 12h", core.ServerInfo.Config.CompactInterval)
 		interval = 12 * time.Hour
 	}
-	s.goroutine.Do(func(ctx context.Context) {
-		log.Infof("enabled the automatic compact mechanism, compact once every %s, reserve %d",
-			core.ServerInfo.Config.CompactInterval, delta)
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case <-time.After(interval):
-				lock, err := mux.Try(mux.GLOBAL_LOCK)
-				if lock == nil {
-					log.Errorf(err, "can not compact backend by this service center instance now")
-					continue
-				}
-
-				backend.Registry().Compact(ctx, delta)
-
-				lock.Unlock()
-			}
-		}
+	log.Infof("enabled the automatic compact mechanism, compact once every %s, reserve %d",
+		core.ServerInfo.Config.CompactInterval, delta)
+	job.Register(job.Job{
+		Name:     "compact",
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			return backend.Registry().Compact(ctx, delta)
+		},
 	})
 }
 
@@ -143,6 +148,9 @@ func (s *ServiceCenterServer) initialize() {
 	s.store.Run()
 	<-s.store.Ready()
 
+	// check the data version
+	s.migrateData()
+
 	if core.ServerInfo.Config.SelfRegister {
 		// check version
 		s.loadOrUpgradeServerVersion()
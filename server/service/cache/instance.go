@@ -18,10 +18,12 @@ package cache
 
 import (
 	"github.com/apache/servicecomb-service-center/pkg/cache"
+	"github.com/apache/servicecomb-service-center/pkg/util"
 	pb "github.com/apache/servicecomb-service-center/server/core/proto"
 	serviceUtil "github.com/apache/servicecomb-service-center/server/service/util"
 	"golang.org/x/net/context"
 	"math"
+	"sync"
 	"time"
 )
 
@@ -71,6 +73,9 @@ func (vi *VersionRuleCacheItem) Broken() {
 
 type FindInstancesCache struct {
 	*cache.Tree
+
+	lock    sync.Mutex
+	changes map[string]*InstancesWaiter
 }
 
 func (f *FindInstancesCache) Get(ctx context.Context, consumer *pb.MicroService, provider *pb.MicroServiceKey,
@@ -90,9 +95,71 @@ func (f *FindInstancesCache) Get(ctx context.Context, consumer *pb.MicroService,
 
 func (f *FindInstancesCache) Remove(provider *pb.MicroServiceKey) {
 	f.Tree.Remove(context.WithValue(context.Background(), CTX_FIND_PROVIDER, provider))
+	f.notify(provider)
 	if len(provider.Alias) > 0 {
 		copy := *provider
 		copy.ServiceName = copy.Alias
 		f.Tree.Remove(context.WithValue(context.Background(), CTX_FIND_PROVIDER, &copy))
+		f.notify(&copy)
+	}
+}
+
+// InstancesWaiter is the subscription to the changes of the cached
+// instances of one provider, C is closed when they are removed next time
+type InstancesWaiter struct {
+	C <-chan struct{}
+
+	key  string
+	ch   chan struct{}
+	refs int
+}
+
+// Changed subscribes the changes of the cached instances of the provider,
+// the long-polling finds wait on it and must call Stop when they are done
+func (f *FindInstancesCache) Changed(provider *pb.MicroServiceKey) *InstancesWaiter {
+	key := changeKey(provider)
+	f.lock.Lock()
+	if f.changes == nil {
+		f.changes = make(map[string]*InstancesWaiter)
+	}
+	w, ok := f.changes[key]
+	if !ok {
+		ch := make(chan struct{})
+		w = &InstancesWaiter{C: ch, key: key, ch: ch}
+		f.changes[key] = w
 	}
+	w.refs++
+	f.lock.Unlock()
+	return w
+}
+
+// Stop unsubscribes the waiter, the entry is removed with the last waiter
+// so the providers never changed do not leak
+func (f *FindInstancesCache) Stop(w *InstancesWaiter) {
+	f.lock.Lock()
+	w.refs--
+	if w.refs <= 0 && f.changes[w.key] == w {
+		delete(f.changes, w.key)
+	}
+	f.lock.Unlock()
+}
+
+func (f *FindInstancesCache) notify(provider *pb.MicroServiceKey) {
+	key := changeKey(provider)
+	f.lock.Lock()
+	if w, ok := f.changes[key]; ok {
+		delete(f.changes, key)
+		close(w.ch)
+	}
+	f.lock.Unlock()
+}
+
+// changeKey is the same as the name of the ServiceFilter node, the
+// providers of the different tenants and environments never share it
+func changeKey(provider *pb.MicroServiceKey) string {
+	return util.StringJoin([]string{
+		provider.Tenant,
+		provider.Environment,
+		provider.AppId,
+		provider.ServiceName}, "/")
 }
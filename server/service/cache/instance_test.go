@@ -0,0 +1,97 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cache
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/cache"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"testing"
+	"time"
+)
+
+func closed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestFindInstancesCache_Changed(t *testing.T) {
+	f := &FindInstancesCache{Tree: cache.NewTree(cache.Configure().WithTTL(time.Minute))}
+
+	provider := &pb.MicroServiceKey{Tenant: "a/a", Environment: "dev", AppId: "app", ServiceName: "svc"}
+	otherTenant := &pb.MicroServiceKey{Tenant: "b/b", Environment: "dev", AppId: "app", ServiceName: "svc"}
+	otherEnv := &pb.MicroServiceKey{Tenant: "a/a", Environment: "prod", AppId: "app", ServiceName: "svc"}
+
+	w1 := f.Changed(provider)
+	w2 := f.Changed(provider)
+	w3 := f.Changed(otherTenant)
+	w4 := f.Changed(otherEnv)
+	if w1 != w2 || w1 == w3 || w1 == w4 || w3 == w4 {
+		t.Fatalf("TestFindInstancesCache_Changed failed, the waiters are shared across tenants")
+	}
+
+	f.Remove(provider)
+	if !closed(w1.C) || closed(w3.C) || closed(w4.C) {
+		t.Fatalf("TestFindInstancesCache_Changed failed, notified the other tenants")
+	}
+	f.Stop(w1)
+	f.Stop(w2)
+
+	// the alias is notified too
+	alias := f.Changed(&pb.MicroServiceKey{Tenant: "a/a", Environment: "dev", AppId: "app", ServiceName: "alias"})
+	f.Remove(&pb.MicroServiceKey{Tenant: "a/a", Environment: "dev", AppId: "app", ServiceName: "svc", Alias: "alias"})
+	if !closed(alias.C) {
+		t.Fatalf("TestFindInstancesCache_Changed failed, alias is not notified")
+	}
+	f.Stop(alias)
+
+	// the entry is removed with the last waiter
+	f.Stop(w3)
+	f.Stop(w4)
+	if len(f.changes) != 0 {
+		t.Fatalf("TestFindInstancesCache_Changed failed, %d entries leaked", len(f.changes))
+	}
+}
+
+func TestFindInstancesCache_Stop(t *testing.T) {
+	f := &FindInstancesCache{Tree: cache.NewTree(cache.Configure().WithTTL(time.Minute))}
+	provider := &pb.MicroServiceKey{Tenant: "a/a", AppId: "app", ServiceName: "svc"}
+
+	w1 := f.Changed(provider)
+	w2 := f.Changed(provider)
+	f.Stop(w1)
+	if len(f.changes) != 1 {
+		t.Fatalf("TestFindInstancesCache_Stop failed, removed with a waiter left")
+	}
+	f.Stop(w2)
+	if len(f.changes) != 0 {
+		t.Fatalf("TestFindInstancesCache_Stop failed, the entry leaked")
+	}
+
+	// the stale waiter does not remove the renewed entry
+	w1 = f.Changed(provider)
+	f.Remove(provider)
+	w2 = f.Changed(provider)
+	f.Stop(w1)
+	if len(f.changes) != 1 || closed(w2.C) {
+		t.Fatalf("TestFindInstancesCache_Stop failed, the renewed entry is removed")
+	}
+	f.Stop(w2)
+}
@@ -18,14 +18,22 @@ package event
 
 import (
 	"github.com/apache/servicecomb-service-center/server/plugin/pkg/discovery"
+	nf "github.com/apache/servicecomb-service-center/server/service/notification"
 )
 
 func init() {
 	discovery.AddEventHandler(NewDomainEventHandler())
 	discovery.AddEventHandler(NewServiceEventHandler())
 	discovery.AddEventHandler(NewInstanceEventHandler())
+	discovery.AddEventHandler(NewLeaseEventHandler())
 	discovery.AddEventHandler(NewRuleEventHandler())
 	discovery.AddEventHandler(NewTagEventHandler())
 	discovery.AddEventHandler(NewDependencyEventHandler())
 	discovery.AddEventHandler(NewDependencyRuleEventHandler())
+	discovery.AddEventHandler(NewSchemaSummaryEventHandler())
+	discovery.AddEventHandler(NewTagResourceEventHandler())
+	discovery.AddEventHandler(NewRuleResourceEventHandler())
+	discovery.AddEventHandler(NewDependencyResourceEventHandler())
+
+	nf.GetPeerBus().Deliver = deliverPeerEvent
 }
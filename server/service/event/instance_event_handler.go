@@ -24,6 +24,7 @@ import (
 	pb "github.com/apache/servicecomb-service-center/server/core/proto"
 	"github.com/apache/servicecomb-service-center/server/plugin/pkg/discovery"
 	"github.com/apache/servicecomb-service-center/server/service/cache"
+	"github.com/apache/servicecomb-service-center/server/service/lease"
 	"github.com/apache/servicecomb-service-center/server/service/metrics"
 	nf "github.com/apache/servicecomb-service-center/server/service/notification"
 	serviceUtil "github.com/apache/servicecomb-service-center/server/service/util"
@@ -50,6 +51,9 @@ func (h *InstanceEventHandler) OnEvent(evt discovery.KvEvent) {
 		metrics.ReportInstances(1)
 	case pb.EVT_DELETE:
 		metrics.ReportInstances(-1)
+		if instance, ok := evt.KV.Value.(*pb.MicroServiceInstance); ok {
+			lease.OnInstanceDeleted(context.Background(), domainProject, instance)
+		}
 
 		splited := strings.Split(domainProject, "/")
 		if len(splited) == 2 && !apt.IsDefaultDomainProject(domainProject) {
@@ -79,6 +83,9 @@ func (h *InstanceEventHandler) OnEvent(evt discovery.KvEvent) {
 	log.Infof("caught [%s] service[%s][%s/%s/%s/%s] instance[%s] event",
 		action, providerId, ms.Environment, ms.AppId, ms.ServiceName, ms.Version, providerInstanceId)
 
+	PublishSinkEvent(domainProject, action, pb.MicroServiceToKey(domainProject, ms),
+		evt.KV.Value.(*pb.MicroServiceInstance), evt.Revision)
+
 	// 查询所有consumer
 	consumerIds, _, err := serviceUtil.GetAllConsumerIds(ctx, domainProject, ms)
 	if err != nil {
@@ -95,12 +102,31 @@ func NewInstanceEventHandler() *InstanceEventHandler {
 	return &InstanceEventHandler{}
 }
 
+// PublishInstanceEvent notifies the subscribers of the instance event, if
+// the peer bus is enabled, the event is also forwarded to the other replicas
+// and the subscribers are notified by whichever replica observed it first
 func PublishInstanceEvent(domainProject string, action pb.EventType, serviceKey *pb.MicroServiceKey, instance *pb.MicroServiceInstance, rev int64, subscribers []string) {
-	defer cache.FindInstances.Remove(serviceKey)
-
-	if len(subscribers) == 0 {
+	if bus := nf.GetPeerBus(); bus.Enabled {
+		bus.Publish(&pb.PeerEvent{
+			DomainProject: domainProject,
+			Revision:      rev,
+			Action:        string(action),
+			Key:           serviceKey,
+			Instance:      instance,
+			Subscribers:   subscribers,
+		})
 		return
 	}
+	publishInstanceEvent(domainProject, action, serviceKey, instance, rev, subscribers)
+}
+
+func deliverPeerEvent(evt *pb.PeerEvent) {
+	publishInstanceEvent(evt.DomainProject, pb.EventType(evt.Action), evt.Key, evt.Instance,
+		evt.Revision, evt.Subscribers)
+}
+
+func publishInstanceEvent(domainProject string, action pb.EventType, serviceKey *pb.MicroServiceKey, instance *pb.MicroServiceInstance, rev int64, subscribers []string) {
+	defer cache.FindInstances.Remove(serviceKey)
 
 	response := &pb.WatchInstanceResponse{
 		Response: pb.CreateResponse(pb.Response_SUCCESS, "Watch instance successfully."),
@@ -108,9 +134,30 @@ func PublishInstanceEvent(domainProject string, action pb.EventType, serviceKey
 		Key:      serviceKey,
 		Instance: instance,
 	}
+	nf.GetReplayBuffer().Append(domainProject, rev, response, subscribers)
+
+	if len(subscribers) == 0 {
+		return
+	}
+
 	for _, consumerId := range subscribers {
 		// TODO add超时怎么处理？
 		job := nf.NewWatchJob(consumerId, apt.GetInstanceRootKey(domainProject)+"/", rev, response)
 		nf.GetNotifyService().AddJob(job)
 	}
 }
+
+func PublishSinkEvent(domainProject string, action pb.EventType, serviceKey *pb.MicroServiceKey, instance *pb.MicroServiceInstance, rev int64) {
+	domain, _ := apt.FromDomainProject(domainProject)
+	if !nf.GetNotifyService().SinkRequired(domain) {
+		return
+	}
+	if err := nf.GetNotifyService().AddJob(nf.NewSinkJob(domainProject, rev, &pb.WatchInstanceResponse{
+		Action:   string(action),
+		Key:      serviceKey,
+		Instance: instance,
+	})); err != nil {
+		log.Errorf(err, "publish [%s] instance[%s/%s] event to sink failed",
+			action, instance.ServiceId, instance.InstanceId)
+	}
+}
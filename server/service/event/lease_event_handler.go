@@ -0,0 +1,45 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package event
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/core/backend"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/discovery"
+	serviceUtil "github.com/apache/servicecomb-service-center/server/service/util"
+)
+
+// LeaseEventHandler invalidates the lease ids cached by the heartbeats,
+// the re-registered instance is granted a new lease
+type LeaseEventHandler struct {
+}
+
+func (h *LeaseEventHandler) Type() discovery.Type {
+	return backend.LEASE
+}
+
+func (h *LeaseEventHandler) OnEvent(evt discovery.KvEvent) {
+	if evt.Type == pb.EVT_INIT {
+		return
+	}
+	serviceUtil.RemoveLeaseId(util.BytesToStringWithNoCopy(evt.KV.Key))
+}
+
+func NewLeaseEventHandler() *LeaseEventHandler {
+	return &LeaseEventHandler{}
+}
@@ -0,0 +1,97 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package event
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/core/backend"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/discovery"
+	nf "github.com/apache/servicecomb-service-center/server/service/notification"
+	serviceUtil "github.com/apache/servicecomb-service-center/server/service/util"
+	"golang.org/x/net/context"
+)
+
+// ResourceEventHandler publishes the changes of schema summaries, tags,
+// rules and dependency rules to the resource watchers
+type ResourceEventHandler struct {
+	t        discovery.Type
+	resource string
+}
+
+func (h *ResourceEventHandler) Type() discovery.Type {
+	return h.t
+}
+
+func (h *ResourceEventHandler) OnEvent(evt discovery.KvEvent) {
+	action := evt.Type
+	if action == pb.EVT_INIT {
+		return
+	}
+	if nf.GetNotifyService().Closed() {
+		return
+	}
+
+	var domainProject string
+	resp := &pb.WatchResourceResponse{
+		Action:   string(action),
+		Resource: h.resource,
+		Revision: evt.Revision,
+		Value:    evt.KV.Value,
+	}
+	switch h.resource {
+	case pb.WATCH_RESOURCE_SCHEMA:
+		domainProject, resp.ServiceId, resp.Id = core.GetInfoFromSchemaSummaryKV(evt.KV.Key)
+	case pb.WATCH_RESOURCE_TAG:
+		resp.ServiceId, domainProject = core.GetInfoFromTagKV(evt.KV.Key)
+	case pb.WATCH_RESOURCE_RULE:
+		resp.ServiceId, resp.Id, domainProject = core.GetInfoFromRuleKV(evt.KV.Key)
+	case pb.WATCH_RESOURCE_DEPENDENCY:
+		t, providerKey := core.GetInfoFromDependencyRuleKV(evt.KV.Key)
+		if t != core.DEPS_PROVIDER || providerKey == nil {
+			return
+		}
+		domainProject = providerKey.Tenant
+		ctx := context.WithValue(context.Background(), serviceUtil.CTX_CACHEONLY, "1")
+		resp.ServiceId, _ = serviceUtil.GetServiceId(ctx, providerKey)
+	}
+	if len(resp.ServiceId) == 0 {
+		return
+	}
+
+	log.Debugf("caught [%s] service[%s] %s event", action, resp.ServiceId, h.resource)
+	if err := nf.GetNotifyService().AddJob(nf.NewResourceJob(domainProject, resp)); err != nil {
+		log.Errorf(err, "publish [%s] service[%s] %s event failed", action, resp.ServiceId, h.resource)
+	}
+}
+
+func NewSchemaSummaryEventHandler() *ResourceEventHandler {
+	return &ResourceEventHandler{t: backend.SCHEMA_SUMMARY, resource: pb.WATCH_RESOURCE_SCHEMA}
+}
+
+func NewTagResourceEventHandler() *ResourceEventHandler {
+	return &ResourceEventHandler{t: backend.SERVICE_TAG, resource: pb.WATCH_RESOURCE_TAG}
+}
+
+func NewRuleResourceEventHandler() *ResourceEventHandler {
+	return &ResourceEventHandler{t: backend.RULE, resource: pb.WATCH_RESOURCE_RULE}
+}
+
+func NewDependencyResourceEventHandler() *ResourceEventHandler {
+	return &ResourceEventHandler{t: backend.DEPENDENCY_RULE, resource: pb.WATCH_RESOURCE_DEPENDENCY}
+}
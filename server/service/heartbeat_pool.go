@@ -0,0 +1,140 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package service
+
+import (
+	"errors"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	"github.com/apache/servicecomb-service-center/server/reload"
+	"github.com/astaxie/beego"
+	"golang.org/x/net/context"
+	"sync"
+	"time"
+)
+
+const (
+	defaultHeartbeatWorkersPerBackend = 100
+	defaultHeartbeatThrottleWait      = time.Second
+)
+
+// ErrHeartbeatThrottled is the error message of the heartbeat set
+// element not renewed as no worker is free in time, the client should
+// send it again later
+var ErrHeartbeatThrottled = errors.New("heartbeat throttled, retry later")
+
+var (
+	heartbeatPool     *HeartbeatPool
+	heartbeatPoolLock sync.RWMutex
+)
+
+func init() {
+	// the reloaded pool applies to the new requests, the running ones
+	// release the workers to the old pool
+	apply := func(string) {
+		SetHeartbeatPool(LoadHeartbeatPool())
+	}
+	reload.Register(reload.Option{Key: "heartbeat_workers_per_backend", Validate: reload.Int(1), Apply: apply})
+	reload.Register(reload.Option{Key: "heartbeat_throttle_wait", Validate: reload.Duration(0), Apply: apply})
+}
+
+// HeartbeatPool bounds the concurrent renewals of all the heartbeat set
+// requests, so a large batch can not exhaust the registry backend
+type HeartbeatPool struct {
+	workers chan struct{}
+	// Wait is the max time a heartbeat set request waits for the free
+	// workers, the elements still waiting after it are throttled
+	Wait time.Duration
+}
+
+func (p *HeartbeatPool) Size() int {
+	return cap(p.workers)
+}
+
+// Acquire takes a free worker, it blocks until the deadline and returns
+// false if no worker is free in time
+func (p *HeartbeatPool) Acquire(ctx context.Context, deadline time.Time) bool {
+	select {
+	case p.workers <- struct{}{}:
+		return true
+	default:
+	}
+	d := deadline.Sub(time.Now())
+	if d <= 0 {
+		return false
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case p.workers <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (p *HeartbeatPool) Release() {
+	<-p.workers
+}
+
+func NewHeartbeatPool(size int, wait time.Duration) *HeartbeatPool {
+	return &HeartbeatPool{workers: make(chan struct{}, size), Wait: wait}
+}
+
+// LoadHeartbeatPool creates the pool by 'heartbeat_workers_per_backend'
+// and 'heartbeat_throttle_wait', the size is relative to the number of
+// the registry backend endpoints
+func LoadHeartbeatPool() *HeartbeatPool {
+	perBackend := beego.AppConfig.DefaultInt("heartbeat_workers_per_backend", defaultHeartbeatWorkersPerBackend)
+	if perBackend <= 0 {
+		perBackend = defaultHeartbeatWorkersPerBackend
+	}
+	backends := len(registry.Configuration().RegistryAddresses())
+	if backends == 0 {
+		backends = 1
+	}
+	wait, err := time.ParseDuration(beego.AppConfig.DefaultString("heartbeat_throttle_wait", ""))
+	if err != nil || wait < 0 {
+		wait = defaultHeartbeatThrottleWait
+	}
+	return NewHeartbeatPool(perBackend*backends, wait)
+}
+
+// GetHeartbeatPool returns the pool, it is loaded on the first call as
+// the registry config is not ready in init
+func GetHeartbeatPool() *HeartbeatPool {
+	heartbeatPoolLock.RLock()
+	p := heartbeatPool
+	heartbeatPoolLock.RUnlock()
+	if p != nil {
+		return p
+	}
+
+	heartbeatPoolLock.Lock()
+	defer heartbeatPoolLock.Unlock()
+	if heartbeatPool == nil {
+		heartbeatPool = LoadHeartbeatPool()
+	}
+	return heartbeatPool
+}
+
+func SetHeartbeatPool(p *HeartbeatPool) {
+	heartbeatPoolLock.Lock()
+	heartbeatPool = p
+	heartbeatPoolLock.Unlock()
+}
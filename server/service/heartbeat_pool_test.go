@@ -0,0 +1,55 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package service_test
+
+import (
+	"github.com/apache/servicecomb-service-center/server/service"
+	"golang.org/x/net/context"
+	"testing"
+	"time"
+)
+
+func TestHeartbeatPool(t *testing.T) {
+	p := service.NewHeartbeatPool(1, 0)
+	deadline := time.Now().Add(10 * time.Millisecond)
+	if !p.Acquire(context.Background(), deadline) {
+		t.Fatalf("TestHeartbeatPool failed")
+	}
+	// full, wait until the deadline
+	if p.Acquire(context.Background(), deadline) || time.Now().Before(deadline) {
+		t.Fatalf("TestHeartbeatPool failed")
+	}
+	// the deadline is past, but the free worker is taken
+	p.Release()
+	if !p.Acquire(context.Background(), deadline) {
+		t.Fatalf("TestHeartbeatPool failed")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if p.Acquire(ctx, time.Now().Add(time.Minute)) {
+		t.Fatalf("TestHeartbeatPool failed")
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		p.Release()
+	}()
+	if !p.Acquire(context.Background(), time.Now().Add(time.Minute)) {
+		t.Fatalf("TestHeartbeatPool failed")
+	}
+}
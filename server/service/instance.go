@@ -17,7 +17,6 @@
 package service
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/apache/servicecomb-service-center/pkg/gopool"
@@ -31,6 +30,7 @@ import (
 	"github.com/apache/servicecomb-service-center/server/plugin/pkg/quota"
 	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
 	"github.com/apache/servicecomb-service-center/server/service/cache"
+	"github.com/apache/servicecomb-service-center/server/service/lease"
 	serviceUtil "github.com/apache/servicecomb-service-center/server/service/util"
 	"golang.org/x/net/context"
 	"math"
@@ -42,6 +42,70 @@ type InstanceService struct {
 }
 
 func (s *InstanceService) preProcessRegisterInstance(ctx context.Context, instance *pb.MicroServiceInstance) *scerr.Error {
+	if err := preProcessInstance(ctx, instance); err != nil {
+		return err
+	}
+
+	domainProject := util.ParseDomainProject(ctx)
+	service, err := serviceUtil.GetService(ctx, domainProject, instance.ServiceId)
+	if service == nil || err != nil {
+		return scerr.NewError(scerr.ErrServiceNotExists, "Invalid 'serviceId' in request body.")
+	}
+	if err := checkServiceIdentity(ctx, service.ServiceName); err != nil {
+		return err
+	}
+	instance.Version = service.Version
+	return nil
+}
+
+// checkServiceIdentity rejects the caller mapped to another service by
+// its SVID, the caller may only register or update its own service
+func checkServiceIdentity(ctx context.Context, serviceName string) *scerr.Error {
+	identity, ok := util.FromContext(ctx, util.CtxServiceIdentity).(string)
+	if !ok || len(identity) == 0 || identity == serviceName {
+		return nil
+	}
+	return scerr.NewErrorf(scerr.ErrPermissionDeny,
+		"The caller identified as service '%s' can not access service '%s'.", identity, serviceName)
+}
+
+// checkServiceIdentityOf is checkServiceIdentity of the service by id,
+// the service absent is left to the caller
+func checkServiceIdentityOf(ctx context.Context, domainProject, serviceId string) *scerr.Error {
+	if identity, ok := util.FromContext(ctx, util.CtxServiceIdentity).(string); !ok || len(identity) == 0 {
+		return nil
+	}
+	service, err := serviceUtil.GetService(ctx, domainProject, serviceId)
+	if err != nil {
+		return scerr.NewError(scerr.ErrInternal, err.Error())
+	}
+	if service == nil {
+		return nil
+	}
+	return checkServiceIdentity(ctx, service.ServiceName)
+}
+
+// recordSpiffeId returns the properties with the spiffe id overwritten by
+// the SVID of the caller, the one supplied by the client is dropped, and
+// the recorded one is kept if the caller has no SVID
+func recordSpiffeId(ctx context.Context, properties map[string]string, recorded string) map[string]string {
+	delete(properties, pb.PROP_SPIFFE_ID)
+	if spiffeId, ok := util.FromContext(ctx, util.CtxSpiffeId).(string); ok && len(spiffeId) > 0 &&
+		apt.ServerInfo.Config.RecordSpiffeId {
+		recorded = spiffeId
+	}
+	if len(recorded) == 0 {
+		return properties
+	}
+	if properties == nil {
+		properties = make(map[string]string)
+	}
+	properties[pb.PROP_SPIFFE_ID] = recorded
+	return properties
+}
+
+// preProcessInstance sets the default values of the instance to register
+func preProcessInstance(ctx context.Context, instance *pb.MicroServiceInstance) *scerr.Error {
 	if len(instance.Status) == 0 {
 		instance.Status = pb.MSI_UP
 	}
@@ -53,6 +117,8 @@ func (s *InstanceService) preProcessRegisterInstance(ctx context.Context, instan
 	instance.Timestamp = strconv.FormatInt(time.Now().Unix(), 10)
 	instance.ModTimestamp = instance.Timestamp
 
+	instance.Properties = recordSpiffeId(ctx, instance.Properties, "")
+
 	// 这里应该根据租约计时
 	renewalInterval := apt.REGISTRY_DEFAULT_LEASE_RENEWALINTERVAL
 	retryTimes := apt.REGISTRY_DEFAULT_LEASE_RETRYTIMES
@@ -76,21 +142,41 @@ func (s *InstanceService) preProcessRegisterInstance(ctx context.Context, instan
 			instance.HealthCheck.Times = retryTimes
 		}
 	}
+	return nil
+}
 
-	domainProject := util.ParseDomainProject(ctx)
-	service, err := serviceUtil.GetService(ctx, domainProject, instance.ServiceId)
-	if service == nil || err != nil {
-		return scerr.NewError(scerr.ErrServiceNotExists, "Invalid 'serviceId' in request body.")
+// registerInstanceOps returns the ops to put the instance and its lease
+func registerInstanceOps(domainProject string, instance *pb.MicroServiceInstance, data []byte, leaseID int64) []registry.PluginOp {
+	key := apt.GenerateInstanceKey(domainProject, instance.ServiceId, instance.InstanceId)
+	hbKey := apt.GenerateInstanceLeaseKey(domainProject, instance.ServiceId, instance.InstanceId)
+	return []registry.PluginOp{
+		registry.OpPut(registry.WithStrKey(key), registry.WithValue(data),
+			registry.WithLease(leaseID)),
+		registry.OpPut(registry.WithStrKey(hbKey), registry.WithStrValue(strconv.FormatInt(leaseID, 10)),
+			registry.WithLease(leaseID)),
+	}
+}
+
+// releaseReplacedLease releases the lease of the instance replaced, the
+// keys are attached to the new lease in the txn, so the old lease is only
+// uncached and revoked. The instance is not marked revoked, the new one
+// keeps the same id and its heartbeats must not be rejected
+func releaseReplacedLease(ctx context.Context, domainProject, serviceId, instanceId string, leaseID int64) {
+	serviceUtil.RemoveLeaseId(apt.GenerateInstanceLeaseKey(domainProject, serviceId, instanceId))
+	if leaseID <= 0 {
+		return
+	}
+	if err := backend.Registry().LeaseRevoke(ctx, leaseID); err != nil {
+		log.WithContext(ctx).Errorf(err, "revoke the replaced lease[%d] of instance[%s/%s] failed",
+			leaseID, serviceId, instanceId)
 	}
-	instance.Version = service.Version
-	return nil
 }
 
 func (s *InstanceService) Register(ctx context.Context, in *pb.RegisterInstanceRequest) (*pb.RegisterInstanceResponse, error) {
 	remoteIP := util.GetIPFromContext(ctx)
 
 	if err := Validate(in); err != nil {
-		log.Errorf(err, "register instance failed, invalid parameters, operator %s", remoteIP)
+		log.WithContext(ctx).Errorf(err, "register instance failed, invalid parameters, operator %s", remoteIP)
 		return &pb.RegisterInstanceResponse{
 			Response: pb.CreateResponse(scerr.ErrInvalidParams, err.Error()),
 		}, nil
@@ -99,10 +185,10 @@ func (s *InstanceService) Register(ctx context.Context, in *pb.RegisterInstanceR
 	instance := in.GetInstance()
 
 	//允许自定义id
-	//如果没填写 并且endpoints沒重復，則产生新的全局instance id
+	//如果没填写，則产生新的全局instance id，只按instance id检查是否已存在
 	oldInstanceId, checkErr := serviceUtil.InstanceExist(ctx, in.Instance)
 	if checkErr != nil {
-		log.Errorf(checkErr, "service[%s]'s instance existence check failed, endpoints %v, host '%s', operator %s",
+		log.WithContext(ctx).Errorf(checkErr, "service[%s]'s instance existence check failed, endpoints %v, host '%s', operator %s",
 			instance.ServiceId, instance.Endpoints, instance.HostName, remoteIP)
 		resp := pb.CreateResponseWithSCErr(checkErr)
 		if checkErr.InternalError() {
@@ -110,17 +196,51 @@ func (s *InstanceService) Register(ctx context.Context, in *pb.RegisterInstanceR
 		}
 		return &pb.RegisterInstanceResponse{Response: resp}, nil
 	}
+	var (
+		policy     string
+		oldLeaseID int64
+	)
 	if len(oldInstanceId) > 0 {
-		log.Infof("register instance successful, reuse instance[%s/%s], operator %s",
-			instance.ServiceId, oldInstanceId, remoteIP)
-		return &pb.RegisterInstanceResponse{
-			Response:   pb.CreateResponse(pb.Response_SUCCESS, "instance already exists"),
-			InstanceId: oldInstanceId,
-		}, nil
+		service, err := serviceUtil.GetService(ctx, util.ParseDomainProject(ctx), instance.ServiceId)
+		if err != nil {
+			log.WithContext(ctx).Errorf(err, "register instance failed, get service[%s] failed, operator %s",
+				instance.ServiceId, remoteIP)
+			return &pb.RegisterInstanceResponse{
+				Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
+			}, err
+		}
+		policy = serviceUtil.InstanceConflictPolicy(service)
+		switch policy {
+		case pb.INSTANCE_CONFLICT_REJECT:
+			log.WithContext(ctx).Warnf("register instance failed, instance[%s/%s] already exists, operator %s",
+				instance.ServiceId, oldInstanceId, remoteIP)
+			return &pb.RegisterInstanceResponse{
+				Response:       pb.CreateResponse(scerr.ErrInstanceAlreadyExists, "Instance already exists."),
+				ConflictPolicy: policy,
+			}, nil
+		case pb.INSTANCE_CONFLICT_REUSE:
+			log.WithContext(ctx).Infof("register instance successful, reuse instance[%s/%s], operator %s",
+				instance.ServiceId, oldInstanceId, remoteIP)
+			return &pb.RegisterInstanceResponse{
+				Response:       pb.CreateResponse(pb.Response_SUCCESS, "instance already exists"),
+				InstanceId:     oldInstanceId,
+				ConflictPolicy: policy,
+			}, nil
+		}
+		// replace the existing instance with the new metadata and lease
+		oldLeaseID, err = serviceUtil.GetLeaseId(util.SetContext(util.CloneContext(ctx), serviceUtil.CTX_NOCACHE, "1"),
+			util.ParseDomainProject(ctx), instance.ServiceId, oldInstanceId)
+		if err != nil {
+			log.WithContext(ctx).Errorf(err, "register instance failed, get the lease of instance[%s/%s] failed, operator %s",
+				instance.ServiceId, oldInstanceId, remoteIP)
+			return &pb.RegisterInstanceResponse{
+				Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
+			}, err
+		}
 	}
 
 	if err := s.preProcessRegisterInstance(ctx, instance); err != nil {
-		log.Errorf(err, "register service[%s]'s instance failed, endpoints %v, host '%s', operator %s",
+		log.WithContext(ctx).Errorf(err, "register service[%s]'s instance failed, endpoints %v, host '%s', operator %s",
 			instance.ServiceId, instance.Endpoints, instance.HostName, remoteIP)
 		return &pb.RegisterInstanceResponse{
 			Response: pb.CreateResponseWithSCErr(err),
@@ -135,14 +255,15 @@ func (s *InstanceService) Register(ctx context.Context, in *pb.RegisterInstanceR
 	domainProject := util.ParseDomainProject(ctx)
 
 	var reporter *quota.ApplyQuotaResult
-	if !apt.IsSCInstance(ctx) {
+	// the replacement does not increase the instances
+	if !apt.IsSCInstance(ctx) && len(policy) == 0 {
 		res := quota.NewApplyQuotaResource(quota.MicroServiceInstanceQuotaType,
 			domainProject, in.Instance.ServiceId, 1)
 		reporter = plugin.Plugins().Quota().Apply4Quotas(ctx, res)
 		defer reporter.Close(ctx)
 
 		if reporter.Err != nil {
-			log.Errorf(reporter.Err, "register instance failed, %s, operator %s",
+			log.WithContext(ctx).Errorf(reporter.Err, "register instance failed, %s, operator %s",
 				instanceFlag, remoteIP)
 			response := &pb.RegisterInstanceResponse{
 				Response: pb.CreateResponseWithSCErr(reporter.Err),
@@ -155,9 +276,9 @@ func (s *InstanceService) Register(ctx context.Context, in *pb.RegisterInstanceR
 	}
 
 	instanceId := instance.InstanceId
-	data, err := json.Marshal(instance)
+	data, err := util.JsonMarshal(instance.WithoutRevision())
 	if err != nil {
-		log.Errorf(err,
+		log.WithContext(ctx).Errorf(err,
 			"register instance failed, %s, instanceId %s, operator %s",
 			instanceFlag, instanceId, remoteIP)
 		return &pb.RegisterInstanceResponse{
@@ -166,31 +287,23 @@ func (s *InstanceService) Register(ctx context.Context, in *pb.RegisterInstanceR
 	}
 
 	leaseID, err := backend.Registry().LeaseGrant(ctx, ttl)
+	lease.ReportGrant(domainProject, instance.ServiceId, err)
 	if err != nil {
-		log.Errorf(err, "grant lease failed, %s, operator: %s", instanceFlag, remoteIP)
+		log.WithContext(ctx).Errorf(err, "grant lease failed, %s, operator: %s", instanceFlag, remoteIP)
 		return &pb.RegisterInstanceResponse{
 			Response: pb.CreateResponse(scerr.ErrUnavailableBackend, err.Error()),
 		}, err
 	}
 
-	// build the request options
-	key := apt.GenerateInstanceKey(domainProject, instance.ServiceId, instanceId)
-	hbKey := apt.GenerateInstanceLeaseKey(domainProject, instance.ServiceId, instanceId)
-
-	opts := []registry.PluginOp{
-		registry.OpPut(registry.WithStrKey(key), registry.WithValue(data),
-			registry.WithLease(leaseID)),
-		registry.OpPut(registry.WithStrKey(hbKey), registry.WithStrValue(fmt.Sprintf("%d", leaseID)),
-			registry.WithLease(leaseID)),
-	}
-
+	opts := append(registerInstanceOps(domainProject, instance, data, leaseID),
+		serviceUtil.TouchRevisionOps(domainProject)...)
 	resp, err := backend.Registry().TxnWithCmp(ctx, opts,
 		[]registry.CompareOp{registry.OpCmp(
 			registry.CmpVer(util.StringToBytesWithNoCopy(apt.GenerateServiceKey(domainProject, instance.ServiceId))),
 			registry.CMP_NOT_EQUAL, 0)},
 		nil)
 	if err != nil {
-		log.Errorf(err,
+		log.WithContext(ctx).Errorf(err,
 			"register instance failed, %s, instanceId %s, operator %s",
 			instanceFlag, instanceId, remoteIP)
 		return &pb.RegisterInstanceResponse{
@@ -198,7 +311,7 @@ func (s *InstanceService) Register(ctx context.Context, in *pb.RegisterInstanceR
 		}, err
 	}
 	if !resp.Succeeded {
-		log.Errorf(nil,
+		log.WithContext(ctx).Errorf(nil,
 			"register instance failed, %s, instanceId %s, operator %s: service does not exist",
 			instanceFlag, instanceId, remoteIP)
 		return &pb.RegisterInstanceResponse{
@@ -206,17 +319,22 @@ func (s *InstanceService) Register(ctx context.Context, in *pb.RegisterInstanceR
 		}, nil
 	}
 
+	if len(policy) > 0 {
+		releaseReplacedLease(ctx, domainProject, instance.ServiceId, instanceId, oldLeaseID)
+	}
+
 	if err := reporter.ReportUsedQuota(ctx); err != nil {
-		log.Errorf(err,
+		log.WithContext(ctx).Errorf(err,
 			"register instance failed, %s, instanceId %s, operator %s",
 			instanceFlag, instanceId, remoteIP)
 	}
 
-	log.Infof("register instance %s, instanceId %s, operator %s",
-		instanceFlag, instanceId, remoteIP)
+	log.WithContext(ctx).Infof("register instance %s, instanceId %s, conflict policy '%s', operator %s",
+		instanceFlag, instanceId, policy, remoteIP)
 	return &pb.RegisterInstanceResponse{
-		Response:   pb.CreateResponse(pb.Response_SUCCESS, "Register service instance successfully."),
-		InstanceId: instanceId,
+		Response:       pb.CreateResponse(pb.Response_SUCCESS, "Register service instance successfully."),
+		InstanceId:     instanceId,
+		ConflictPolicy: policy,
 	}, nil
 }
 
@@ -224,7 +342,7 @@ func (s *InstanceService) Unregister(ctx context.Context, in *pb.UnregisterInsta
 	remoteIP := util.GetIPFromContext(ctx)
 
 	if err := Validate(in); err != nil {
-		log.Errorf(err, "unregister instance failed, invalid parameters, operator %s", remoteIP)
+		log.WithContext(ctx).Errorf(err, "unregister instance failed, invalid parameters, operator %s", remoteIP)
 		return &pb.UnregisterInstanceResponse{
 			Response: pb.CreateResponse(scerr.ErrInvalidParams, err.Error()),
 		}, nil
@@ -238,13 +356,13 @@ func (s *InstanceService) Unregister(ctx context.Context, in *pb.UnregisterInsta
 
 	isExist, err := serviceUtil.InstanceExistById(ctx, domainProject, serviceId, instanceId)
 	if err != nil {
-		log.Errorf(err, "unregister instance failed, instance[%s], operator %s: query instance failed", instanceFlag, remoteIP)
+		log.WithContext(ctx).Errorf(err, "unregister instance failed, instance[%s], operator %s: query instance failed", instanceFlag, remoteIP)
 		return &pb.UnregisterInstanceResponse{
 			Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
 		}, err
 	}
 	if !isExist {
-		log.Errorf(nil, "unregister instance failed, instance[%s], operator %s: instance not exist", instanceFlag, remoteIP)
+		log.WithContext(ctx).Errorf(nil, "unregister instance failed, instance[%s], operator %s: instance not exist", instanceFlag, remoteIP)
 		return &pb.UnregisterInstanceResponse{
 			Response: pb.CreateResponse(scerr.ErrInstanceNotExists, "Service instance does not exist."),
 		}, nil
@@ -252,7 +370,7 @@ func (s *InstanceService) Unregister(ctx context.Context, in *pb.UnregisterInsta
 
 	err, isInnerErr := revokeInstance(ctx, domainProject, serviceId, instanceId)
 	if err != nil {
-		log.Errorf(nil, "unregister instance failed, instance[%s], operator %s: revoke instance failed", instanceFlag, remoteIP)
+		log.WithContext(ctx).Errorf(nil, "unregister instance failed, instance[%s], operator %s: revoke instance failed", instanceFlag, remoteIP)
 		if isInnerErr {
 			return &pb.UnregisterInstanceResponse{
 				Response: pb.CreateResponse(scerr.ErrUnavailableBackend, err.Error()),
@@ -263,7 +381,7 @@ func (s *InstanceService) Unregister(ctx context.Context, in *pb.UnregisterInsta
 		}, nil
 	}
 
-	log.Infof("unregister instance[%s], operator %s", instanceFlag, remoteIP)
+	log.WithContext(ctx).Infof("unregister instance[%s], operator %s", instanceFlag, remoteIP)
 	return &pb.UnregisterInstanceResponse{
 		Response: pb.CreateResponse(pb.Response_SUCCESS, "Unregister service instance successfully."),
 	}, nil
@@ -278,6 +396,7 @@ func revokeInstance(ctx context.Context, domainProject string, serviceId string,
 		return errors.New("instance's leaseId not exist."), false
 	}
 
+	lease.MarkRevoked(ctx, domainProject, serviceId, instanceId)
 	err = backend.Registry().LeaseRevoke(ctx, leaseID)
 	if err != nil {
 		return err, true
@@ -285,23 +404,21 @@ func revokeInstance(ctx context.Context, domainProject string, serviceId string,
 	return nil, false
 }
 
+// Heartbeat is the fast path of the lease renewal, the request is checked
+// by hand and the lease id is cached, the context is parsed only once
 func (s *InstanceService) Heartbeat(ctx context.Context, in *pb.HeartbeatRequest) (*pb.HeartbeatResponse, error) {
-	remoteIP := util.GetIPFromContext(ctx)
-
-	if err := Validate(in); err != nil {
-		log.Errorf(err, "heartbeat failed, invalid parameters, operator %s", remoteIP)
+	if err := validateHeartbeat(in); err != nil {
+		log.WithContext(ctx).Errorf(err, "heartbeat failed, invalid parameters, operator %s",
+			util.GetIPFromContext(ctx))
 		return &pb.HeartbeatResponse{
 			Response: pb.CreateResponse(scerr.ErrInvalidParams, err.Error()),
 		}, nil
 	}
 
-	domainProject := util.ParseDomainProject(ctx)
-	instanceFlag := util.StringJoin([]string{in.ServiceId, in.InstanceId}, "/")
-
-	_, ttl, err, isInnerErr := serviceUtil.HeartbeatUtil(ctx, domainProject, in.ServiceId, in.InstanceId)
+	ttl, err, isInnerErr := serviceUtil.HeartbeatFast(ctx, util.ParseDomainProject(ctx), in.ServiceId, in.InstanceId)
 	if err != nil {
-		log.Errorf(err, "heartbeat failed, instance[%s], internal error '%v'. operator %s",
-			instanceFlag, isInnerErr, remoteIP)
+		log.WithContext(ctx).Errorf(err, "heartbeat failed, instance[%s/%s], internal error '%v'. operator %s",
+			in.ServiceId, in.InstanceId, isInnerErr, util.GetIPFromContext(ctx))
 		if isInnerErr {
 			return &pb.HeartbeatResponse{
 				Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
@@ -313,10 +430,12 @@ func (s *InstanceService) Heartbeat(ctx context.Context, in *pb.HeartbeatRequest
 	}
 
 	if ttl == 0 {
-		log.Errorf(errors.New("connect backend timed out"),
-			"heartbeat successful, but renew instance[%s] failed. operator %s", instanceFlag, remoteIP)
+		log.WithContext(ctx).Errorf(errors.New("connect backend timed out"),
+			"heartbeat successful, but renew instance[%s/%s] failed. operator %s",
+			in.ServiceId, in.InstanceId, util.GetIPFromContext(ctx))
 	} else {
-		log.Infof("heartbeat successful, renew instance[%s] ttl to %d. operator %s", instanceFlag, ttl, remoteIP)
+		log.WithContext(ctx).Debugf("heartbeat successful, renew instance[%s/%s] ttl to %d. operator %s",
+			in.ServiceId, in.InstanceId, ttl, util.GetIPFromContext(ctx))
 	}
 	return &pb.HeartbeatResponse{
 		Response: pb.CreateResponse(pb.Response_SUCCESS, "Update service instance heartbeat successfully."),
@@ -325,7 +444,7 @@ func (s *InstanceService) Heartbeat(ctx context.Context, in *pb.HeartbeatRequest
 
 func (s *InstanceService) HeartbeatSet(ctx context.Context, in *pb.HeartbeatSetRequest) (*pb.HeartbeatSetResponse, error) {
 	if len(in.Instances) == 0 {
-		log.Errorf(nil, "heartbeats failed, invalid request. Body not contain Instances or is empty")
+		log.WithContext(ctx).Errorf(nil, "heartbeats failed, invalid request. Body not contain Instances or is empty")
 		return &pb.HeartbeatSetResponse{
 			Response: pb.CreateResponse(scerr.ErrInvalidParams, "Request format invalid."),
 		}, nil
@@ -336,40 +455,63 @@ func (s *InstanceService) HeartbeatSet(ctx context.Context, in *pb.HeartbeatSetR
 	existFlag := make(map[string]bool, heartBeatCount)
 	instancesHbRst := make(chan *pb.InstanceHbRst, heartBeatCount)
 	noMultiCounter := 0
+	// the elements wait for the free workers in the shared pool until
+	// the deadline, then the rest are throttled
+	pool := GetHeartbeatPool()
+	deadline := time.Now().Add(pool.Wait)
 	for _, heartbeatElement := range in.Instances {
 		if _, ok := existFlag[heartbeatElement.ServiceId+heartbeatElement.InstanceId]; ok {
-			log.Warnf("instance[%s/%s] is duplicate in heartbeat set", heartbeatElement.ServiceId, heartbeatElement.InstanceId)
+			log.WithContext(ctx).Warnf("instance[%s/%s] is duplicate in heartbeat set", heartbeatElement.ServiceId, heartbeatElement.InstanceId)
 			continue
 		} else {
 			existFlag[heartbeatElement.ServiceId+heartbeatElement.InstanceId] = true
 			noMultiCounter++
 		}
-		gopool.Go(getHeartbeatFunc(ctx, domainProject, instancesHbRst, heartbeatElement))
+		if !pool.Acquire(ctx, deadline) {
+			instancesHbRst <- &pb.InstanceHbRst{
+				ServiceId:  heartbeatElement.ServiceId,
+				InstanceId: heartbeatElement.InstanceId,
+				ErrMessage: ErrHeartbeatThrottled.Error(),
+			}
+			continue
+		}
+		gopool.Go(getHeartbeatFunc(ctx, pool, domainProject, instancesHbRst, heartbeatElement))
 	}
 	count := 0
 	successFlag := false
 	failFlag := false
+	throttled := 0
 	instanceHbRstArr := make([]*pb.InstanceHbRst, 0, heartBeatCount)
 	for heartbeat := range instancesHbRst {
 		count++
-		if len(heartbeat.ErrMessage) != 0 {
-			failFlag = true
-		} else {
+		switch heartbeat.ErrMessage {
+		case "":
 			successFlag = true
+		case ErrHeartbeatThrottled.Error():
+			throttled++
+		default:
+			failFlag = true
 		}
 		instanceHbRstArr = append(instanceHbRstArr, heartbeat)
 		if count == noMultiCounter {
 			close(instancesHbRst)
 		}
 	}
-	if !failFlag && successFlag {
-		log.Infof("batch update heartbeats[%s] successfully", count)
+	if !failFlag && throttled == 0 && successFlag {
+		log.WithContext(ctx).Infof("batch update heartbeats[%s] successfully", count)
 		return &pb.HeartbeatSetResponse{
 			Response:  pb.CreateResponse(pb.Response_SUCCESS, "Heartbeat set successfully."),
 			Instances: instanceHbRstArr,
 		}, nil
+	} else if !failFlag {
+		log.WithContext(ctx).Warnf("batch update heartbeats throttled, %d/%d instances are not renewed, pool size %d",
+			throttled, count, pool.Size())
+		return &pb.HeartbeatSetResponse{
+			Response:  pb.CreateResponse(scerr.ErrHeartbeatThrottled, "Heartbeat set partially throttled."),
+			Instances: instanceHbRstArr,
+		}, nil
 	} else {
-		log.Errorf(nil, "batch update heartbeats failed, %v", in.Instances)
+		log.WithContext(ctx).Errorf(nil, "batch update heartbeats failed, %v", in.Instances)
 		return &pb.HeartbeatSetResponse{
 			Response:  pb.CreateResponse(scerr.ErrInstanceNotExists, "Heartbeat set failed."),
 			Instances: instanceHbRstArr,
@@ -377,17 +519,18 @@ func (s *InstanceService) HeartbeatSet(ctx context.Context, in *pb.HeartbeatSetR
 	}
 }
 
-func getHeartbeatFunc(ctx context.Context, domainProject string, instancesHbRst chan<- *pb.InstanceHbRst, element *pb.HeartbeatSetElement) func(context.Context) {
+func getHeartbeatFunc(ctx context.Context, pool *HeartbeatPool, domainProject string, instancesHbRst chan<- *pb.InstanceHbRst, element *pb.HeartbeatSetElement) func(context.Context) {
 	return func(_ context.Context) {
+		defer pool.Release()
 		hbRst := &pb.InstanceHbRst{
 			ServiceId:  element.ServiceId,
 			InstanceId: element.InstanceId,
 			ErrMessage: "",
 		}
-		_, _, err, _ := serviceUtil.HeartbeatUtil(ctx, domainProject, element.ServiceId, element.InstanceId)
+		_, err, _ := serviceUtil.HeartbeatFast(ctx, domainProject, element.ServiceId, element.InstanceId)
 		if err != nil {
 			hbRst.ErrMessage = err.Error()
-			log.Errorf(err, "heartbeat set failed, %s/%s", element.ServiceId, element.InstanceId)
+			log.WithContext(ctx).Errorf(err, "heartbeat set failed, %s/%s", element.ServiceId, element.InstanceId)
 		}
 		instancesHbRst <- hbRst
 	}
@@ -395,7 +538,7 @@ func getHeartbeatFunc(ctx context.Context, domainProject string, instancesHbRst
 
 func (s *InstanceService) GetOneInstance(ctx context.Context, in *pb.GetOneInstanceRequest) (*pb.GetOneInstanceResponse, error) {
 	if err := Validate(in); err != nil {
-		log.Errorf(err, "get instance failed: invalid parameters")
+		log.WithContext(ctx).Errorf(err, "get instance failed: invalid parameters")
 		return &pb.GetOneInstanceResponse{
 			Response: pb.CreateResponse(scerr.ErrInvalidParams, err.Error()),
 		}, nil
@@ -407,7 +550,7 @@ func (s *InstanceService) GetOneInstance(ctx context.Context, in *pb.GetOneInsta
 	}
 
 	if checkErr := s.getInstancePreCheck(ctx, in.ProviderServiceId, in.ConsumerServiceId, in.Tags); checkErr != nil {
-		log.Errorf(checkErr, "%s failed: pre check failed", cpFunc())
+		log.WithContext(ctx).Errorf(checkErr, "%s failed: pre check failed", cpFunc())
 		resp := &pb.GetOneInstanceResponse{
 			Response: pb.CreateResponseWithSCErr(checkErr),
 		}
@@ -419,15 +562,15 @@ func (s *InstanceService) GetOneInstance(ctx context.Context, in *pb.GetOneInsta
 
 	serviceId := in.ProviderServiceId
 	instanceId := in.ProviderInstanceId
-	instance, err := serviceUtil.GetInstance(ctx, util.ParseTargetDomainProject(ctx), serviceId, instanceId)
+	instance, modRev, err := serviceUtil.GetInstanceWithRev(ctx, util.ParseTargetDomainProject(ctx), serviceId, instanceId)
 	if err != nil {
-		log.Errorf(err, "%s failed: get instance failed", cpFunc())
+		log.WithContext(ctx).Errorf(err, "%s failed: get instance failed", cpFunc())
 		return &pb.GetOneInstanceResponse{
 			Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
 		}, err
 	}
 	if instance == nil {
-		log.Errorf(nil, "%s failed: instance does not exist", cpFunc())
+		log.WithContext(ctx).Errorf(nil, "%s failed: instance does not exist", cpFunc())
 		return &pb.GetOneInstanceResponse{
 			Response: pb.CreateResponse(scerr.ErrInstanceNotExists, "Service instance does not exist."),
 		}, nil
@@ -436,6 +579,7 @@ func (s *InstanceService) GetOneInstance(ctx context.Context, in *pb.GetOneInsta
 	return &pb.GetOneInstanceResponse{
 		Response: pb.CreateResponse(pb.Response_SUCCESS, "Get instance successfully."),
 		Instance: instance,
+		Rev:      strconv.FormatInt(modRev, 10),
 	}, nil
 }
 
@@ -465,21 +609,24 @@ func (s *InstanceService) getInstancePreCheck(ctx context.Context, providerServi
 	return serviceUtil.Accessible(ctx, consumerServiceId, providerServiceId)
 }
 
-func (s *InstanceService) GetInstances(ctx context.Context, in *pb.GetInstancesRequest) (*pb.GetInstancesResponse, error) {
+// checkGetInstances validates the request of getting the instances of a
+// provider, shared by the buffered and the streaming variants
+func (s *InstanceService) checkGetInstances(ctx context.Context, in *pb.GetInstancesRequest) *scerr.Error {
 	if err := Validate(in); err != nil {
-		log.Errorf(err, "get instances failed: invalid parameters")
-		return &pb.GetInstancesResponse{
-			Response: pb.CreateResponse(scerr.ErrInvalidParams, err.Error()),
-		}, nil
+		log.WithContext(ctx).Errorf(err, "get instances failed: invalid parameters")
+		return scerr.NewError(scerr.ErrInvalidParams, err.Error())
 	}
 
-	cpFunc := func() string {
-		return fmt.Sprintf("consumer[%s] get provider[%s] instances",
+	if checkErr := s.getInstancePreCheck(ctx, in.ProviderServiceId, in.ConsumerServiceId, in.Tags); checkErr != nil {
+		log.WithContext(ctx).Errorf(checkErr, "consumer[%s] get provider[%s] instances failed: pre check failed",
 			in.ConsumerServiceId, in.ProviderServiceId)
+		return checkErr
 	}
+	return nil
+}
 
-	if checkErr := s.getInstancePreCheck(ctx, in.ProviderServiceId, in.ConsumerServiceId, in.Tags); checkErr != nil {
-		log.Errorf(checkErr, "%s failed: pre check failed", cpFunc())
+func (s *InstanceService) GetInstances(ctx context.Context, in *pb.GetInstancesRequest) (*pb.GetInstancesResponse, error) {
+	if checkErr := s.checkGetInstances(ctx, in); checkErr != nil {
 		resp := &pb.GetInstancesResponse{
 			Response: pb.CreateResponseWithSCErr(checkErr),
 		}
@@ -491,7 +638,8 @@ func (s *InstanceService) GetInstances(ctx context.Context, in *pb.GetInstancesR
 
 	instances, err := serviceUtil.GetAllInstancesOfOneService(ctx, util.ParseTargetDomainProject(ctx), in.ProviderServiceId)
 	if err != nil {
-		log.Errorf(err, "%s failed", cpFunc())
+		log.WithContext(ctx).Errorf(err, "consumer[%s] get provider[%s] instances failed",
+			in.ConsumerServiceId, in.ProviderServiceId)
 		return &pb.GetInstancesResponse{
 			Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
 		}, err
@@ -505,7 +653,7 @@ func (s *InstanceService) GetInstances(ctx context.Context, in *pb.GetInstancesR
 func (s *InstanceService) Find(ctx context.Context, in *pb.FindInstancesRequest) (*pb.FindInstancesResponse, error) {
 	err := Validate(in)
 	if err != nil {
-		log.Errorf(err, "find instance failed: invalid parameters")
+		log.WithContext(ctx).Errorf(err, "find instance failed: invalid parameters")
 		return &pb.FindInstancesResponse{
 			Response: pb.CreateResponse(scerr.ErrInvalidParams, err.Error()),
 		}, nil
@@ -517,14 +665,14 @@ func (s *InstanceService) Find(ctx context.Context, in *pb.FindInstancesRequest)
 	if len(in.ConsumerServiceId) > 0 {
 		service, err = serviceUtil.GetService(ctx, domainProject, in.ConsumerServiceId)
 		if err != nil {
-			log.Errorf(err, "get consumer failed, consumer[%s] find provider[%s/%s/%s/%s]",
+			log.WithContext(ctx).Errorf(err, "get consumer failed, consumer[%s] find provider[%s/%s/%s/%s]",
 				in.ConsumerServiceId, in.Environment, in.AppId, in.ServiceName, in.VersionRule)
 			return &pb.FindInstancesResponse{
 				Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
 			}, err
 		}
 		if service == nil {
-			log.Errorf(nil, "consumer does not exist, consumer[%s] find provider[%s/%s/%s/%s]",
+			log.WithContext(ctx).Errorf(nil, "consumer does not exist, consumer[%s] find provider[%s/%s/%s/%s]",
 				in.ConsumerServiceId, in.Environment, in.AppId, in.ServiceName, in.VersionRule)
 			return &pb.FindInstancesResponse{
 				Response: pb.CreateResponse(scerr.ErrServiceNotExists,
@@ -567,16 +715,31 @@ func (s *InstanceService) Find(ctx context.Context, in *pb.FindInstancesRequest)
 	// cache
 	var item *cache.VersionRuleCacheItem
 	rev, _ := ctx.Value(serviceUtil.CTX_REQUEST_REVISION).(string)
+	if len(rev) == 0 {
+		rev = in.Rev
+	}
+	includeRemote := serviceUtil.IncludeRemote(ctx)
+	requestRev := rev
+	if includeRemote {
+		rev, _ = serviceUtil.SplitRemoteRevision(rev)
+	}
+	if ctx.Value(serviceUtil.CTX_FIND_WAIT) == "1" {
+		// subscribe before the read, the changes in between are not missed
+		util.SetContext(ctx, serviceUtil.CTX_FIND_WAITER, cache.FindInstances.Changed(provider))
+	}
 	item, err = cache.FindInstances.Get(ctx, service, provider, in.Tags, rev)
 	if err != nil {
-		log.Errorf(err, "FindInstancesCache.Get failed, %s failed", findFlag())
+		log.WithContext(ctx).Errorf(err, "FindInstancesCache.Get failed, %s failed", findFlag())
 		return &pb.FindInstancesResponse{
 			Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
 		}, err
 	}
+	if item == nil && includeRemote {
+		return s.findWithRemote(ctx, provider, nil, requestRev), nil
+	}
 	if item == nil {
 		mes := fmt.Errorf("%s failed, provider does not exist.", findFlag())
-		log.Errorf(mes, "FindInstancesCache.Get failed")
+		log.WithContext(ctx).Errorf(mes, "FindInstancesCache.Get failed")
 		return &pb.FindInstancesResponse{
 			Response: pb.CreateResponse(scerr.ErrServiceNotExists, mes.Error()),
 		}, nil
@@ -591,35 +754,83 @@ func (s *InstanceService) Find(ctx context.Context, in *pb.FindInstancesRequest)
 			err = serviceUtil.AddServiceVersionRule(ctx, domainProject, service, provider)
 		} else {
 			mes := fmt.Errorf("%s failed, provider does not exist.", findFlag())
-			log.Errorf(mes, "AddServiceVersionRule failed")
+			log.WithContext(ctx).Errorf(mes, "AddServiceVersionRule failed")
 			return &pb.FindInstancesResponse{
 				Response: pb.CreateResponse(scerr.ErrServiceNotExists, mes.Error()),
 			}, nil
 		}
 		if err != nil {
-			log.Errorf(err, "AddServiceVersionRule failed, %s failed", findFlag())
+			log.WithContext(ctx).Errorf(err, "AddServiceVersionRule failed, %s failed", findFlag())
 			return &pb.FindInstancesResponse{
 				Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
 			}, err
 		}
 	}
 
+	if includeRemote {
+		return s.findWithRemote(ctx, provider, item, requestRev), nil
+	}
+
 	instances := item.Instances
 	if rev == item.Rev {
 		instances = nil // for gRPC
 	}
-	// TODO support gRPC output context
+	// the gRPC interceptor returns it in the response header as well
 	ctx = util.SetContext(ctx, serviceUtil.CTX_RESPONSE_REVISION, item.Rev)
 	return &pb.FindInstancesResponse{
 		Response:  pb.CreateResponse(pb.Response_SUCCESS, "Query service instances successfully."),
 		Instances: instances,
+		Rev:       item.Rev,
 	}, nil
 }
 
+// findWithRemote appends the instances of the provider in the other
+// datacenters, the response revision contains both the local and the
+// remote revision
+func (s *InstanceService) findWithRemote(ctx context.Context, provider *pb.MicroServiceKey, item *cache.VersionRuleCacheItem, requestRev string) *pb.FindInstancesResponse {
+	remotes, remoteRev := serviceUtil.FindRemoteInstances(ctx, provider)
+	if item == nil && len(remotes) == 0 {
+		return &pb.FindInstancesResponse{
+			Response: pb.CreateResponse(scerr.ErrServiceNotExists,
+				fmt.Sprintf("Provider[%s/%s/%s] does not exist.", provider.AppId, provider.ServiceName, provider.Version)),
+		}
+	}
+
+	var (
+		localRev  string
+		instances []*pb.MicroServiceInstance
+	)
+	locals := make(map[string]struct{})
+	if item != nil {
+		localRev = item.Rev
+		instances = append(instances, item.Instances...)
+		for _, instance := range item.Instances {
+			locals[instance.InstanceId] = struct{}{}
+		}
+	}
+	for _, instance := range remotes {
+		// the local registration wins if the instance is also synced
+		// from the other datacenter
+		if _, ok := locals[instance.InstanceId]; !ok {
+			instances = append(instances, instance)
+		}
+	}
+	rev := serviceUtil.JoinRemoteRevision(localRev, remoteRev)
+	if rev == requestRev {
+		instances = nil // for gRPC
+	}
+	util.SetContext(ctx, serviceUtil.CTX_RESPONSE_REVISION, rev)
+	return &pb.FindInstancesResponse{
+		Response:  pb.CreateResponse(pb.Response_SUCCESS, "Query service instances successfully."),
+		Instances: instances,
+		Rev:       rev,
+	}
+}
+
 func (s *InstanceService) BatchFind(ctx context.Context, in *pb.BatchFindInstancesRequest) (*pb.BatchFindInstancesResponse, error) {
 	err := Validate(in)
 	if err != nil {
-		log.Errorf(err, "batch find instance failed: invalid parameters")
+		log.WithContext(ctx).Errorf(err, "batch find instance failed: invalid parameters")
 		return &pb.BatchFindInstancesResponse{
 			Response: pb.CreateResponse(scerr.ErrInvalidParams, err.Error()),
 		}, nil
@@ -673,43 +884,68 @@ func (s *InstanceService) UpdateStatus(ctx context.Context, in *pb.UpdateInstanc
 	domainProject := util.ParseDomainProject(ctx)
 	updateStatusFlag := util.StringJoin([]string{in.ServiceId, in.InstanceId, in.Status}, "/")
 	if err := Validate(in); err != nil {
-		log.Errorf(nil, "update instance[%s] status failed", updateStatusFlag)
+		log.WithContext(ctx).Errorf(nil, "update instance[%s] status failed", updateStatusFlag)
 		return &pb.UpdateInstanceStatusResponse{
 			Response: pb.CreateResponse(scerr.ErrInvalidParams, err.Error()),
 		}, nil
 	}
 
-	instance, err := serviceUtil.GetInstance(ctx, domainProject, in.ServiceId, in.InstanceId)
+	ctx, rev, err := parseUpdateRev(ctx, in.Rev)
 	if err != nil {
-		log.Errorf(err, "update instance[%s] status failed", updateStatusFlag)
+		log.WithContext(ctx).Errorf(err, "update instance[%s] status failed", updateStatusFlag)
+		return &pb.UpdateInstanceStatusResponse{
+			Response: pb.CreateResponse(scerr.ErrInvalidParams, err.Error()),
+		}, nil
+	}
+
+	instance, modRev, err := serviceUtil.GetInstanceWithRev(ctx, domainProject, in.ServiceId, in.InstanceId)
+	if err != nil {
+		log.WithContext(ctx).Errorf(err, "update instance[%s] status failed", updateStatusFlag)
 		return &pb.UpdateInstanceStatusResponse{
 			Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
 		}, err
 	}
 	if instance == nil {
-		log.Errorf(nil, "update instance[%s] status failed, instance does not exist", updateStatusFlag)
+		log.WithContext(ctx).Errorf(nil, "update instance[%s] status failed, instance does not exist", updateStatusFlag)
 		return &pb.UpdateInstanceStatusResponse{
 			Response: pb.CreateResponse(scerr.ErrInstanceNotExists, "Service instance does not exist."),
 		}, nil
 	}
 
+	if rev > 0 && rev != modRev {
+		log.WithContext(ctx).Errorf(nil, "update instance[%s] status failed, revision is %d, not %d",
+			updateStatusFlag, modRev, rev)
+		return &pb.UpdateInstanceStatusResponse{
+			Response: pb.CreateResponse(scerr.ErrRevisionConflict, "Service instance revision has changed."),
+		}, nil
+	}
+
+	if err := checkServiceIdentityOf(ctx, domainProject, in.ServiceId); err != nil {
+		log.WithContext(ctx).Errorf(err, "update instance[%s] status failed", updateStatusFlag)
+		return &pb.UpdateInstanceStatusResponse{
+			Response: pb.CreateResponseWithSCErr(err),
+		}, nil
+	}
+
 	copyInstanceRef := *instance
 	copyInstanceRef.Status = in.Status
 
-	if err := serviceUtil.UpdateInstance(ctx, domainProject, &copyInstanceRef); err != nil {
-		log.Errorf(err, "update instance[%s] status failed", updateStatusFlag)
+	modRev, updateErr := serviceUtil.UpdateInstanceIfMatch(ctx, domainProject, &copyInstanceRef, rev)
+	if updateErr != nil {
+		log.WithContext(ctx).Errorf(updateErr, "update instance[%s] status failed", updateStatusFlag)
 		resp := &pb.UpdateInstanceStatusResponse{
-			Response: pb.CreateResponseWithSCErr(err),
+			Response: pb.CreateResponseWithSCErr(updateErr),
 		}
-		if err.InternalError() {
-			return resp, err
+		if updateErr.InternalError() {
+			return resp, updateErr
 		}
 		return resp, nil
 	}
 
-	log.Infof("update instance[%s] status successfully", updateStatusFlag)
+	log.WithContext(ctx).Infof("update instance[%s] status successfully", updateStatusFlag)
 	return &pb.UpdateInstanceStatusResponse{
 		Response: pb.CreateResponse(pb.Response_SUCCESS, "Update service instance status successfully."),
+		Rev:      strconv.FormatInt(modRev, 10),
 	}, nil
 }
 
@@ -717,46 +953,84 @@ func (s *InstanceService) UpdateInstanceProperties(ctx context.Context, in *pb.U
 	domainProject := util.ParseDomainProject(ctx)
 	instanceFlag := util.StringJoin([]string{in.ServiceId, in.InstanceId}, "/")
 	if err := Validate(in); err != nil {
-		log.Errorf(nil, "update instance[%s] properties failed", instanceFlag)
+		log.WithContext(ctx).Errorf(nil, "update instance[%s] properties failed", instanceFlag)
+		return &pb.UpdateInstancePropsResponse{
+			Response: pb.CreateResponse(scerr.ErrInvalidParams, err.Error()),
+		}, nil
+	}
+
+	ctx, rev, err := parseUpdateRev(ctx, in.Rev)
+	if err != nil {
+		log.WithContext(ctx).Errorf(err, "update instance[%s] properties failed", instanceFlag)
 		return &pb.UpdateInstancePropsResponse{
 			Response: pb.CreateResponse(scerr.ErrInvalidParams, err.Error()),
 		}, nil
 	}
 
-	instance, err := serviceUtil.GetInstance(ctx, domainProject, in.ServiceId, in.InstanceId)
+	instance, modRev, err := serviceUtil.GetInstanceWithRev(ctx, domainProject, in.ServiceId, in.InstanceId)
 	if err != nil {
-		log.Errorf(err, "update instance[%s] properties failed", instanceFlag)
+		log.WithContext(ctx).Errorf(err, "update instance[%s] properties failed", instanceFlag)
 		return &pb.UpdateInstancePropsResponse{
 			Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
 		}, err
 	}
 	if instance == nil {
-		log.Errorf(nil, "update instance[%s] properties failed, instance does not exist", instanceFlag)
+		log.WithContext(ctx).Errorf(nil, "update instance[%s] properties failed, instance does not exist", instanceFlag)
 		return &pb.UpdateInstancePropsResponse{
 			Response: pb.CreateResponse(scerr.ErrInstanceNotExists, "Service instance does not exist."),
 		}, nil
 	}
 
+	if rev > 0 && rev != modRev {
+		log.WithContext(ctx).Errorf(nil, "update instance[%s] properties failed, revision is %d, not %d",
+			instanceFlag, modRev, rev)
+		return &pb.UpdateInstancePropsResponse{
+			Response: pb.CreateResponse(scerr.ErrRevisionConflict, "Service instance revision has changed."),
+		}, nil
+	}
+
+	if err := checkServiceIdentityOf(ctx, domainProject, in.ServiceId); err != nil {
+		log.WithContext(ctx).Errorf(err, "update instance[%s] properties failed", instanceFlag)
+		return &pb.UpdateInstancePropsResponse{
+			Response: pb.CreateResponseWithSCErr(err),
+		}, nil
+	}
+
 	copyInstanceRef := *instance
-	copyInstanceRef.Properties = in.Properties
+	copyInstanceRef.Properties = recordSpiffeId(ctx, in.Properties, instance.Properties[pb.PROP_SPIFFE_ID])
 
-	if err := serviceUtil.UpdateInstance(ctx, domainProject, &copyInstanceRef); err != nil {
-		log.Errorf(err, "update instance[%s] properties failed", instanceFlag)
+	modRev, updateErr := serviceUtil.UpdateInstanceIfMatch(ctx, domainProject, &copyInstanceRef, rev)
+	if updateErr != nil {
+		log.WithContext(ctx).Errorf(updateErr, "update instance[%s] properties failed", instanceFlag)
 		resp := &pb.UpdateInstancePropsResponse{
-			Response: pb.CreateResponseWithSCErr(err),
+			Response: pb.CreateResponseWithSCErr(updateErr),
 		}
-		if err.InternalError() {
-			return resp, err
+		if updateErr.InternalError() {
+			return resp, updateErr
 		}
 		return resp, nil
 	}
 
-	log.Infof("update instance[%s] properties successfully", instanceFlag)
+	log.WithContext(ctx).Infof("update instance[%s] properties successfully", instanceFlag)
 	return &pb.UpdateInstancePropsResponse{
 		Response: pb.CreateResponse(pb.Response_SUCCESS, "Update service instance properties successfully."),
+		Rev:      strconv.FormatInt(modRev, 10),
 	}, nil
 }
 
+// parseUpdateRev parses the mod revision the update is conditional on,
+// the instance is read from the registry instead of the cache to compare
+func parseUpdateRev(ctx context.Context, s string) (context.Context, int64, error) {
+	if len(s) == 0 {
+		return ctx, 0, nil
+	}
+	rev, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || rev <= 0 {
+		return ctx, 0, fmt.Errorf("invalid revision '%s'", s)
+	}
+	return util.SetContext(util.CloneContext(ctx), serviceUtil.CTX_NOCACHE, "1"), rev, nil
+}
+
 func (s *InstanceService) ClusterHealth(ctx context.Context) (*pb.GetInstancesResponse, error) {
 	domainProject := apt.REGISTRY_DOMAIN_PROJECT
 	serviceId, err := serviceUtil.GetServiceId(ctx, &pb.MicroServiceKey{
@@ -768,14 +1042,14 @@ func (s *InstanceService) ClusterHealth(ctx context.Context) (*pb.GetInstancesRe
 	})
 
 	if err != nil {
-		log.Errorf(err, "health check failed: get service center[%s/%s/%s/%s]'s serviceId failed",
+		log.WithContext(ctx).Errorf(err, "health check failed: get service center[%s/%s/%s/%s]'s serviceId failed",
 			apt.Service.Environment, apt.Service.AppId, apt.Service.ServiceName, apt.Service.Version)
 		return &pb.GetInstancesResponse{
 			Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
 		}, err
 	}
 	if len(serviceId) == 0 {
-		log.Errorf(nil, "health check failed: service center[%s/%s/%s/%s]'s serviceId does not exist",
+		log.WithContext(ctx).Errorf(nil, "health check failed: service center[%s/%s/%s/%s]'s serviceId does not exist",
 			apt.Service.Environment, apt.Service.AppId, apt.Service.ServiceName, apt.Service.Version)
 		return &pb.GetInstancesResponse{
 			Response: pb.CreateResponse(scerr.ErrServiceNotExists, "ServiceCenter's serviceId not exist."),
@@ -784,7 +1058,7 @@ func (s *InstanceService) ClusterHealth(ctx context.Context) (*pb.GetInstancesRe
 
 	instances, err := serviceUtil.GetAllInstancesOfOneService(ctx, domainProject, serviceId)
 	if err != nil {
-		log.Errorf(err, "health check failed: get service center[%s][%s/%s/%s/%s]'s instances failed",
+		log.WithContext(ctx).Errorf(err, "health check failed: get service center[%s][%s/%s/%s/%s]'s instances failed",
 			serviceId, apt.Service.Environment, apt.Service.AppId, apt.Service.ServiceName, apt.Service.Version)
 		return &pb.GetInstancesResponse{
 			Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
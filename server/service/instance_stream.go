@@ -0,0 +1,119 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package service
+
+import (
+	"bytes"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	serviceUtil "github.com/apache/servicecomb-service-center/server/service/util"
+	"golang.org/x/net/context"
+	"net/http"
+)
+
+// the max number of instances sent in one chunk of the streams
+const instancesStreamChunk = 500
+
+func (s *InstanceService) GetInstancesStream(in *pb.GetInstancesRequest, stream pb.ServiceInstanceStreamCtrl_GetInstancesStreamServer) error {
+	ctx := stream.Context()
+	if checkErr := s.checkGetInstances(ctx, in); checkErr != nil {
+		return stream.Send(&pb.GetInstancesResponse{
+			Response: pb.CreateResponseWithSCErr(checkErr),
+		})
+	}
+
+	sent := false
+	err := serviceUtil.ForEachInstanceOfOneService(ctx, util.ParseTargetDomainProject(ctx), in.ProviderServiceId,
+		instancesStreamChunk, func(instances []*pb.MicroServiceInstance) error {
+			resp := &pb.GetInstancesResponse{Instances: instances}
+			if !sent {
+				resp.Response = pb.CreateResponse(pb.Response_SUCCESS, "Query service instances successfully.")
+				sent = true
+			}
+			return stream.Send(resp)
+		})
+	if err != nil {
+		log.WithContext(ctx).Errorf(err, "consumer[%s] stream provider[%s] instances failed",
+			in.ConsumerServiceId, in.ProviderServiceId)
+		if !sent {
+			stream.Send(&pb.GetInstancesResponse{
+				Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
+			})
+		}
+		return err
+	}
+	if !sent {
+		return stream.Send(&pb.GetInstancesResponse{
+			Response: pb.CreateResponse(pb.Response_SUCCESS, "Query service instances successfully."),
+		})
+	}
+	return nil
+}
+
+func (s *InstanceService) NDJSONGetInstances(ctx context.Context, in *pb.GetInstancesRequest, w http.ResponseWriter) error {
+	if checkErr := s.checkGetInstances(ctx, in); checkErr != nil {
+		return checkErr
+	}
+
+	flusher, _ := w.(http.Flusher)
+	started := false
+	start := func() {
+		w.Header().Set(rest.HEADER_CONTENT_TYPE, rest.CONTENT_TYPE_NDJSON)
+		w.WriteHeader(http.StatusOK)
+		started = true
+	}
+	mask, _ := ctx.Value(serviceUtil.CTX_FIELD_MASK).(*pb.FieldMask)
+	var buf bytes.Buffer
+	err := serviceUtil.ForEachInstanceOfOneService(ctx, util.ParseTargetDomainProject(ctx), in.ProviderServiceId,
+		instancesStreamChunk, func(instances []*pb.MicroServiceInstance) error {
+			buf.Reset()
+			for _, instance := range instances {
+				data, err := util.JsonMarshal(mask.Instance(instance))
+				if err != nil {
+					return err
+				}
+				buf.Write(data)
+				buf.WriteByte('\n')
+			}
+			if !started {
+				start()
+			}
+			if _, err := w.Write(buf.Bytes()); err != nil {
+				return err
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+			return nil
+		})
+	if err != nil {
+		log.WithContext(ctx).Errorf(err, "consumer[%s] stream provider[%s] instances failed",
+			in.ConsumerServiceId, in.ProviderServiceId)
+		if !started {
+			return scerr.NewError(scerr.ErrInternal, err.Error())
+		}
+		// the status is sent, the client sees a truncated stream
+		return nil
+	}
+	if !started {
+		start()
+	}
+	return nil
+}
@@ -19,12 +19,17 @@ package service_test
 import (
 	"github.com/apache/servicecomb-service-center/pkg/util"
 	"github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/core/backend"
 	pb "github.com/apache/servicecomb-service-center/server/core/proto"
 	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/service"
 	serviceUtil "github.com/apache/servicecomb-service-center/server/service/util"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
 	"math"
+	"net/http/httptest"
 	"os"
 	"strconv"
 	"strings"
@@ -35,6 +40,20 @@ var (
 	TOO_LONG_URL      = strings.Repeat("x", 513)
 )
 
+type grpcInstancesStreamServer struct {
+	grpc.ServerStream
+	chunks []*pb.GetInstancesResponse
+}
+
+func (x *grpcInstancesStreamServer) Send(m *pb.GetInstancesResponse) error {
+	x.chunks = append(x.chunks, m)
+	return nil
+}
+
+func (x *grpcInstancesStreamServer) Context() context.Context {
+	return getContext()
+}
+
 var _ = Describe("'Instance' service", func() {
 	Describe("execute 'register' operartion", func() {
 		var (
@@ -160,6 +179,149 @@ var _ = Describe("'Instance' service", func() {
 			})
 		})
 
+		Context("when register the instance with the spiffe id property", func() {
+			It("should be overwritten by the caller identity", func() {
+				resp, err := instanceResource.Register(getContext(), &pb.RegisterInstanceRequest{
+					Instance: &pb.MicroServiceInstance{
+						ServiceId:  serviceId1,
+						Endpoints:  []string{"spiffeInstance:127.0.0.1:8080"},
+						HostName:   "UT-HOST",
+						Properties: map[string]string{pb.PROP_SPIFFE_ID: "spiffe://forged/ns/default/sa/a"},
+					},
+				})
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(pb.Response_SUCCESS))
+				instanceId := resp.InstanceId
+
+				respGet, err := instanceResource.GetOneInstance(getContext(), &pb.GetOneInstanceRequest{
+					ConsumerServiceId:  serviceId1,
+					ProviderServiceId:  serviceId1,
+					ProviderInstanceId: instanceId,
+				})
+				Expect(err).To(BeNil())
+				Expect(respGet.Instance.Properties[pb.PROP_SPIFFE_ID]).To(Equal(""))
+
+				By("update properties")
+				respUpdate, err := instanceResource.UpdateInstanceProperties(getContext(), &pb.UpdateInstancePropsRequest{
+					ServiceId:  serviceId1,
+					InstanceId: instanceId,
+					Properties: map[string]string{pb.PROP_SPIFFE_ID: "spiffe://forged/ns/default/sa/a"},
+				})
+				Expect(err).To(BeNil())
+				Expect(respUpdate.Response.Code).To(Equal(pb.Response_SUCCESS))
+				respGet, err = instanceResource.GetOneInstance(getContext(), &pb.GetOneInstanceRequest{
+					ConsumerServiceId:  serviceId1,
+					ProviderServiceId:  serviceId1,
+					ProviderInstanceId: instanceId,
+				})
+				Expect(err).To(BeNil())
+				Expect(respGet.Instance.Properties[pb.PROP_SPIFFE_ID]).To(Equal(""))
+
+				By("the caller identified as another service")
+				ctx := util.SetContext(getContext(), util.CtxServiceIdentity, "other_service")
+				resp, err = instanceResource.Register(ctx, &pb.RegisterInstanceRequest{
+					Instance: &pb.MicroServiceInstance{
+						ServiceId: serviceId1,
+						Endpoints: []string{"spiffeInstance:127.0.0.1:8081"},
+						HostName:  "UT-HOST",
+					},
+				})
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(scerr.ErrPermissionDeny))
+				respUpdate, err = instanceResource.UpdateInstanceProperties(ctx, &pb.UpdateInstancePropsRequest{
+					ServiceId:  serviceId1,
+					InstanceId: instanceId,
+					Properties: map[string]string{"a": "b"},
+				})
+				Expect(err).To(BeNil())
+				Expect(respUpdate.Response.Code).To(Equal(scerr.ErrPermissionDeny))
+
+				By("the caller identified as the service")
+				ctx = util.SetContext(getContext(), util.CtxServiceIdentity, "create_instance_service")
+				respUpdate, err = instanceResource.UpdateInstanceProperties(ctx, &pb.UpdateInstancePropsRequest{
+					ServiceId:  serviceId1,
+					InstanceId: instanceId,
+					Properties: map[string]string{"a": "b"},
+				})
+				Expect(err).To(BeNil())
+				Expect(respUpdate.Response.Code).To(Equal(pb.Response_SUCCESS))
+			})
+		})
+
+		Context("when register the existing instance with the conflict policy", func() {
+			It("should apply the policy of the service", func() {
+				register := func(serviceId, hostName string) *pb.RegisterInstanceResponse {
+					resp, err := instanceResource.Register(getContext(), &pb.RegisterInstanceRequest{
+						Instance: &pb.MicroServiceInstance{
+							ServiceId:  serviceId,
+							InstanceId: "conflict_policy_instance",
+							Endpoints:  []string{"conflictPolicy:127.0.0.1:8080"},
+							HostName:   hostName,
+							Status:     pb.MSI_UP,
+						},
+					})
+					Expect(err).To(BeNil())
+					return resp
+				}
+
+				By("reuse by default")
+				resp := register(serviceId2, "UT-HOST")
+				Expect(resp.Response.Code).To(Equal(pb.Response_SUCCESS))
+				Expect(resp.ConflictPolicy).To(Equal(""))
+				resp = register(serviceId2, "UT-HOST-NEW")
+				Expect(resp.Response.Code).To(Equal(pb.Response_SUCCESS))
+				Expect(resp.ConflictPolicy).To(Equal(pb.INSTANCE_CONFLICT_REUSE))
+
+				By("replace")
+				respCreate, err := serviceResource.Create(getContext(), &pb.CreateServiceRequest{
+					Service: &pb.MicroService{
+						ServiceName: "conflict_policy_service",
+						AppId:       "create_instance",
+						Version:     "1.0.0",
+						Status:      pb.MS_UP,
+						Properties: map[string]string{
+							pb.PROP_INSTANCE_CONFLICT_POLICY: pb.INSTANCE_CONFLICT_REPLACE,
+						},
+					},
+				})
+				Expect(err).To(BeNil())
+				Expect(respCreate.Response.Code).To(Equal(pb.Response_SUCCESS))
+				serviceId := respCreate.ServiceId
+				Expect(register(serviceId, "UT-HOST").Response.Code).To(Equal(pb.Response_SUCCESS))
+				oldLeaseID, err := serviceUtil.GetLeaseId(getContext(), "default/default", serviceId, "conflict_policy_instance")
+				Expect(err).To(BeNil())
+				resp = register(serviceId, "UT-HOST-NEW")
+				Expect(resp.Response.Code).To(Equal(pb.Response_SUCCESS))
+				Expect(resp.ConflictPolicy).To(Equal(pb.INSTANCE_CONFLICT_REPLACE))
+				newLeaseID, err := serviceUtil.GetLeaseId(getContext(), "default/default", serviceId, resp.InstanceId)
+				Expect(err).To(BeNil())
+				Expect(newLeaseID).NotTo(Equal(oldLeaseID))
+				// the replaced lease is revoked rather than left to expire
+				_, err = backend.Registry().LeaseRenew(context.Background(), oldLeaseID)
+				Expect(err).NotTo(BeNil())
+				respGet, err := instanceResource.GetOneInstance(getContext(), &pb.GetOneInstanceRequest{
+					ConsumerServiceId:  serviceId,
+					ProviderServiceId:  serviceId,
+					ProviderInstanceId: resp.InstanceId,
+				})
+				Expect(err).To(BeNil())
+				Expect(respGet.Instance.HostName).To(Equal("UT-HOST-NEW"))
+
+				By("reject")
+				respUpdate, err := serviceResource.UpdateProperties(getContext(), &pb.UpdateServicePropsRequest{
+					ServiceId: serviceId,
+					Properties: map[string]string{
+						pb.PROP_INSTANCE_CONFLICT_POLICY: pb.INSTANCE_CONFLICT_REJECT,
+					},
+				})
+				Expect(err).To(BeNil())
+				Expect(respUpdate.Response.Code).To(Equal(pb.Response_SUCCESS))
+				resp = register(serviceId, "UT-HOST")
+				Expect(resp.Response.Code).To(Equal(scerr.ErrInstanceAlreadyExists))
+				Expect(resp.ConflictPolicy).To(Equal(pb.INSTANCE_CONFLICT_REJECT))
+			})
+		})
+
 		Context("when register invalid instance", func() {
 			It("should be failed", func() {
 				By("endpoints are empty")
@@ -393,6 +555,160 @@ var _ = Describe("'Instance' service", func() {
 		})
 	})
 
+	Describe("execute 'register service and instance' operartion", func() {
+		Context("when the service is absent or exists", func() {
+			It("should be passed", func() {
+				By("create the service and the instance")
+				resp, err := instanceResource.RegisterServiceAndInstance(getContext(), &pb.RegisterServiceAndInstanceRequest{
+					Service: &pb.MicroService{
+						AppId:       "register_service_and_instance",
+						ServiceName: "register_service_and_instance",
+						Version:     "1.0.0",
+						Level:       "FRONT",
+						Status:      pb.MS_UP,
+					},
+					Instance: &pb.MicroServiceInstance{
+						Endpoints: []string{"registerServiceAndInstance:127.0.0.1:8080"},
+						HostName:  "UT-HOST",
+						Status:    pb.MSI_UP,
+					},
+				})
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(pb.Response_SUCCESS))
+				Expect(resp.ServiceId).ToNot(BeEmpty())
+				Expect(resp.InstanceId).ToNot(BeEmpty())
+				serviceId := resp.ServiceId
+
+				respGet, err := instanceResource.GetOneInstance(getContext(), &pb.GetOneInstanceRequest{
+					ConsumerServiceId:  serviceId,
+					ProviderServiceId:  serviceId,
+					ProviderInstanceId: resp.InstanceId,
+				})
+				Expect(err).To(BeNil())
+				Expect(respGet.Response.Code).To(Equal(pb.Response_SUCCESS))
+				Expect(respGet.Instance.Version).To(Equal("1.0.0"))
+
+				By("register another instance of the existing service")
+				resp, err = instanceResource.RegisterServiceAndInstance(getContext(), &pb.RegisterServiceAndInstanceRequest{
+					Service: &pb.MicroService{
+						AppId:       "register_service_and_instance",
+						ServiceName: "register_service_and_instance",
+						Version:     "1.0.0",
+						Level:       "FRONT",
+						Status:      pb.MS_UP,
+					},
+					Instance: &pb.MicroServiceInstance{
+						Endpoints: []string{"registerServiceAndInstance:127.0.0.2:8080"},
+						HostName:  "UT-HOST",
+						Status:    pb.MSI_UP,
+					},
+				})
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(pb.Response_SUCCESS))
+				Expect(resp.ServiceId).To(Equal(serviceId))
+
+				By("the service id conflicts")
+				resp, err = instanceResource.RegisterServiceAndInstance(getContext(), &pb.RegisterServiceAndInstanceRequest{
+					Service: &pb.MicroService{
+						ServiceId:   "register_service_and_instance_conflict",
+						AppId:       "register_service_and_instance",
+						ServiceName: "register_service_and_instance",
+						Version:     "1.0.0",
+						Level:       "FRONT",
+						Status:      pb.MS_UP,
+					},
+					Instance: &pb.MicroServiceInstance{
+						Endpoints: []string{"registerServiceAndInstance:127.0.0.3:8080"},
+						HostName:  "UT-HOST",
+						Status:    pb.MSI_UP,
+					},
+				})
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(scerr.ErrServiceAlreadyExists))
+
+				By("the instance is absent")
+				resp, err = instanceResource.RegisterServiceAndInstance(getContext(), &pb.RegisterServiceAndInstanceRequest{
+					Service: &pb.MicroService{
+						AppId:       "register_service_and_instance",
+						ServiceName: "register_service_and_instance",
+						Version:     "1.0.0",
+					},
+				})
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(scerr.ErrInvalidParams))
+			})
+		})
+
+		Context("when the service is created by others before the transaction", func() {
+			It("should decide the conflict again", func() {
+				var createdServiceId string
+				restore := service.SetBeforeRegisterTxn(func(ctx context.Context, attempt int) {
+					if attempt > 0 {
+						return
+					}
+					respCreate, err := serviceResource.Create(getContext(), &pb.CreateServiceRequest{
+						Service: &pb.MicroService{
+							AppId:       "register_service_and_instance_flip",
+							ServiceName: "register_service_and_instance_flip",
+							Version:     "1.0.0",
+							Level:       "FRONT",
+							Status:      pb.MS_UP,
+							Properties: map[string]string{
+								pb.PROP_INSTANCE_CONFLICT_POLICY: pb.INSTANCE_CONFLICT_REJECT,
+							},
+						},
+					})
+					Expect(err).To(BeNil())
+					Expect(respCreate.Response.Code).To(Equal(pb.Response_SUCCESS))
+					createdServiceId = respCreate.ServiceId
+
+					respRegister, err := instanceResource.Register(getContext(), &pb.RegisterInstanceRequest{
+						Instance: &pb.MicroServiceInstance{
+							ServiceId:  createdServiceId,
+							InstanceId: "register_service_and_instance_flip",
+							Endpoints:  []string{"registerServiceAndInstanceFlip:127.0.0.1:8080"},
+							HostName:   "UT-HOST",
+							Status:     pb.MSI_UP,
+						},
+					})
+					Expect(err).To(BeNil())
+					Expect(respRegister.Response.Code).To(Equal(pb.Response_SUCCESS))
+				})
+				defer restore()
+
+				By("the creation fails and the retry finds the instance of the created service")
+				resp, err := instanceResource.RegisterServiceAndInstance(getContext(), &pb.RegisterServiceAndInstanceRequest{
+					Service: &pb.MicroService{
+						AppId:       "register_service_and_instance_flip",
+						ServiceName: "register_service_and_instance_flip",
+						Version:     "1.0.0",
+						Level:       "FRONT",
+						Status:      pb.MS_UP,
+					},
+					Instance: &pb.MicroServiceInstance{
+						InstanceId: "register_service_and_instance_flip",
+						Endpoints:  []string{"registerServiceAndInstanceFlip:127.0.0.2:8080"},
+						HostName:   "UT-HOST",
+						Status:     pb.MSI_UP,
+					},
+				})
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(scerr.ErrInstanceAlreadyExists))
+				Expect(resp.ServiceId).To(Equal(createdServiceId))
+				Expect(resp.ConflictPolicy).To(Equal(pb.INSTANCE_CONFLICT_REJECT))
+
+				respGet, err := instanceResource.GetOneInstance(getContext(), &pb.GetOneInstanceRequest{
+					ConsumerServiceId:  createdServiceId,
+					ProviderServiceId:  createdServiceId,
+					ProviderInstanceId: "register_service_and_instance_flip",
+				})
+				Expect(err).To(BeNil())
+				Expect(respGet.Response.Code).To(Equal(pb.Response_SUCCESS))
+				Expect(respGet.Instance.Endpoints).To(Equal([]string{"registerServiceAndInstanceFlip:127.0.0.1:8080"}))
+			})
+		})
+	})
+
 	Describe("execute 'heartbeat' operartion", func() {
 		var (
 			serviceId   string
@@ -453,6 +769,14 @@ var _ = Describe("'Instance' service", func() {
 				Expect(err).To(BeNil())
 				Expect(resp.Response.Code).To(Equal(pb.Response_SUCCESS))
 
+				By("renew by the cached lease id")
+				resp, err = instanceResource.Heartbeat(getContext(), &pb.HeartbeatRequest{
+					ServiceId:  serviceId,
+					InstanceId: instanceId1,
+				})
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(pb.Response_SUCCESS))
+
 				By("serviceId/instanceId is invalid")
 				resp, err = instanceResource.Heartbeat(getContext(), &pb.HeartbeatRequest{
 					ServiceId:  "",
@@ -460,6 +784,12 @@ var _ = Describe("'Instance' service", func() {
 				})
 				Expect(err).To(BeNil())
 				Expect(resp.Response.Code).To(Equal(scerr.ErrInvalidParams))
+				resp, err = instanceResource.Heartbeat(getContext(), &pb.HeartbeatRequest{
+					ServiceId:  "a b",
+					InstanceId: instanceId1,
+				})
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(scerr.ErrInvalidParams))
 				resp, err = instanceResource.Heartbeat(getContext(), &pb.HeartbeatRequest{
 					ServiceId:  TOO_LONG_SERVICEID,
 					InstanceId: instanceId1,
@@ -544,6 +874,27 @@ var _ = Describe("'Instance' service", func() {
 					},
 				})
 				Expect(resp.Response.Code).ToNot(Equal(pb.Response_SUCCESS))
+
+				By("no worker is free")
+				old := service.GetHeartbeatPool()
+				service.SetHeartbeatPool(service.NewHeartbeatPool(0, 0))
+				resp, err = instanceResource.HeartbeatSet(getContext(), &pb.HeartbeatSetRequest{
+					Instances: []*pb.HeartbeatSetElement{
+						{
+							ServiceId:  serviceId,
+							InstanceId: instanceId1,
+						},
+						{
+							ServiceId:  serviceId,
+							InstanceId: instanceId2,
+						},
+					},
+				})
+				service.SetHeartbeatPool(old)
+				Expect(err).To(BeNil())
+				Expect(resp.Response.Code).To(Equal(scerr.ErrHeartbeatThrottled))
+				Expect(len(resp.Instances)).To(Equal(2))
+				Expect(resp.Instances[0].ErrMessage).To(Equal(service.ErrHeartbeatThrottled.Error()))
 			})
 		})
 	})
@@ -699,6 +1050,44 @@ var _ = Describe("'Instance' service", func() {
 
 				Expect(err).To(BeNil())
 				Expect(respUpdateStatus.Response.Code).ToNot(Equal(pb.Response_SUCCESS))
+
+				By("update status if the revision is not changed")
+				respGet, err := instanceResource.GetOneInstance(getContext(), &pb.GetOneInstanceRequest{
+					ConsumerServiceId:  serviceId,
+					ProviderServiceId:  serviceId,
+					ProviderInstanceId: instanceId,
+				})
+				Expect(err).To(BeNil())
+				Expect(respGet.Response.Code).To(Equal(pb.Response_SUCCESS))
+				Expect(respGet.Rev).ToNot(BeEmpty())
+
+				respUpdateStatus, err = instanceResource.UpdateStatus(getContext(), &pb.UpdateInstanceStatusRequest{
+					ServiceId:  serviceId,
+					InstanceId: instanceId,
+					Status:     pb.MSI_DOWN,
+					Rev:        respGet.Rev,
+				})
+				Expect(err).To(BeNil())
+				Expect(respUpdateStatus.Response.Code).To(Equal(pb.Response_SUCCESS))
+				Expect(respUpdateStatus.Rev).ToNot(Equal(respGet.Rev))
+
+				respUpdateStatus, err = instanceResource.UpdateStatus(getContext(), &pb.UpdateInstanceStatusRequest{
+					ServiceId:  serviceId,
+					InstanceId: instanceId,
+					Status:     pb.MSI_UP,
+					Rev:        respGet.Rev,
+				})
+				Expect(err).To(BeNil())
+				Expect(respUpdateStatus.Response.Code).To(Equal(scerr.ErrRevisionConflict))
+
+				respUpdateStatus, err = instanceResource.UpdateStatus(getContext(), &pb.UpdateInstanceStatusRequest{
+					ServiceId:  serviceId,
+					InstanceId: instanceId,
+					Status:     pb.MSI_UP,
+					Rev:        "x",
+				})
+				Expect(err).To(BeNil())
+				Expect(respUpdateStatus.Response.Code).To(Equal(scerr.ErrInvalidParams))
 			})
 		})
 
@@ -1995,6 +2384,56 @@ var _ = Describe("'Instance' service", func() {
 				Expect(resp.Response.Code).To(Equal(pb.Response_SUCCESS))
 			})
 		})
+
+		Context("when get instances by stream", func() {
+			It("should be passed", func() {
+				By("grpc stream")
+				stream := &grpcInstancesStreamServer{}
+				err := instanceResource.GetInstancesStream(&pb.GetInstancesRequest{
+					ConsumerServiceId: serviceId1,
+					ProviderServiceId: serviceId2,
+				}, stream)
+				Expect(err).To(BeNil())
+				Expect(len(stream.chunks)).To(Equal(1))
+				Expect(stream.chunks[0].Response.Code).To(Equal(pb.Response_SUCCESS))
+				Expect(len(stream.chunks[0].Instances)).To(Equal(1))
+				Expect(stream.chunks[0].Instances[0].InstanceId).To(Equal(instanceId2))
+
+				By("ndjson stream")
+				w := httptest.NewRecorder()
+				err = instanceResource.NDJSONGetInstances(getContext(), &pb.GetInstancesRequest{
+					ConsumerServiceId: serviceId1,
+					ProviderServiceId: serviceId2,
+				}, w)
+				Expect(err).To(BeNil())
+				lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+				Expect(len(lines)).To(Equal(1))
+				instance := &pb.MicroServiceInstance{}
+				Expect(util.JsonUnmarshal([]byte(lines[0]), instance)).To(BeNil())
+				Expect(instance.InstanceId).To(Equal(instanceId2))
+			})
+
+			It("should be failed", func() {
+				By("grpc stream")
+				stream := &grpcInstancesStreamServer{}
+				err := instanceResource.GetInstancesStream(&pb.GetInstancesRequest{
+					ConsumerServiceId: "noneservice",
+					ProviderServiceId: serviceId2,
+				}, stream)
+				Expect(err).To(BeNil())
+				Expect(len(stream.chunks)).To(Equal(1))
+				Expect(stream.chunks[0].Response.Code).ToNot(Equal(pb.Response_SUCCESS))
+
+				By("ndjson stream")
+				w := httptest.NewRecorder()
+				err = instanceResource.NDJSONGetInstances(getContext(), &pb.GetInstancesRequest{
+					ConsumerServiceId: "noneservice",
+					ProviderServiceId: serviceId2,
+				}, w)
+				Expect(err).NotTo(BeNil())
+				Expect(w.Body.Len()).To(Equal(0))
+			})
+		})
 	})
 
 	Describe("execute 'unregister' operartion", func() {
@@ -17,11 +17,15 @@
 package service
 
 import (
+	"errors"
+	"fmt"
 	"github.com/apache/servicecomb-service-center/pkg/util"
 	"github.com/apache/servicecomb-service-center/pkg/validate"
 	pb "github.com/apache/servicecomb-service-center/server/core/proto"
 	"math"
 	"regexp"
+	"strings"
+	"unicode/utf8"
 )
 
 var (
@@ -84,6 +88,35 @@ func HeartbeatReqValidator() *validate.Validator {
 	})
 }
 
+// validateHeartbeat is the HeartbeatReqValidator written by hand, the
+// heartbeat is the hottest request and skips the reflection of Validate
+func validateHeartbeat(in *pb.HeartbeatRequest) error {
+	if in == nil {
+		return errors.New("pointer is nil")
+	}
+	// the same as serviceIdRegex, \S excludes the ASCII white spaces only
+	if l := len(in.ServiceId); l == 0 || l > 64 && utf8.RuneCountInString(in.ServiceId) > 64 ||
+		strings.ContainsAny(in.ServiceId, "\t\n\f\r ") {
+		return heartbeatFieldError("ServiceId", in.ServiceId)
+	}
+	if l := len(in.InstanceId); l == 0 || l > 64 {
+		return heartbeatFieldError("InstanceId", in.InstanceId)
+	}
+	for i := 0; i < len(in.InstanceId); i++ {
+		switch c := in.InstanceId[i]; {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '_', c == '.', c == '-':
+		default:
+			return heartbeatFieldError("InstanceId", in.InstanceId)
+		}
+	}
+	return nil
+}
+
+func heartbeatFieldError(name, value string) error {
+	return fmt.Errorf("field 'HeartbeatRequest.%s' invalid value '%s' does not match rule: %s",
+		name, value, HeartbeatReqValidator().GetRule(name))
+}
+
 func UpdateInstanceReqValidator() *validate.Validator {
 	return updateInstanceReqValidator.Init(func(v *validate.Validator) {
 		v.AddRules(heartbeatReqValidator.GetRules())
@@ -0,0 +1,206 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package lease
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/core/backend"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"github.com/apache/servicecomb-service-center/server/job"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	"golang.org/x/net/context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// DEFAULT_RETENTION is how long the expired instances are kept
+	DEFAULT_RETENTION = time.Hour
+	// DEFAULT_MAX_EXPIRED is the max number of the expired instances kept
+	DEFAULT_MAX_EXPIRED = 1000
+	// revokeTimeout is how long the revoke marker is kept for the members
+	// of the cluster to receive the deletion event
+	revokeTimeout = time.Minute
+	// revokeCheckDelay is how long the deletion waits for the event of the
+	// revoke marker, the markers and the instances are watched separately
+	revokeCheckDelay = 2 * time.Second
+)
+
+var tracker = NewTracker(DEFAULT_RETENTION, DEFAULT_MAX_EXPIRED)
+
+// ExpiredInstance is the instance removed because its lease expired
+type ExpiredInstance struct {
+	ServiceId  string   `json:"serviceId"`
+	InstanceId string   `json:"instanceId"`
+	HostName   string   `json:"hostName,omitempty"`
+	Endpoints  []string `json:"endpoints,omitempty"`
+	Timestamp  string   `json:"timestamp"`
+
+	domainProject string
+	expiredAt     time.Time
+}
+
+// Tracker tells the lease expirations from the unregistrations. The
+// unregistration writes a revoke marker to the registry before revoking
+// the lease, so every member of the cluster regards the deletion of an
+// instance without the marker as expired
+type Tracker struct {
+	Retention time.Duration
+	Max       int
+	// Revoked returns true if the revoke marker of the instance exists
+	Revoked func(ctx context.Context, domainProject, serviceId, instanceId string) (bool, error)
+
+	lock    sync.Mutex
+	expired []*ExpiredInstance
+}
+
+// OnDeleted is called when the instance is deleted from the registry, it
+// returns true if the deletion is caused by the lease expiration
+func (t *Tracker) OnDeleted(ctx context.Context, domainProject string, instance *pb.MicroServiceInstance) bool {
+	revoked, err := t.Revoked(ctx, domainProject, instance.ServiceId, instance.InstanceId)
+	if err != nil {
+		log.Errorf(err, "check the revoke marker of instance[%s/%s] failed",
+			instance.ServiceId, instance.InstanceId)
+		return false
+	}
+	if revoked {
+		return false
+	}
+
+	now := time.Now()
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.expired = append(t.expired, &ExpiredInstance{
+		ServiceId:     instance.ServiceId,
+		InstanceId:    instance.InstanceId,
+		HostName:      instance.HostName,
+		Endpoints:     instance.Endpoints,
+		Timestamp:     now.UTC().Format(time.RFC3339),
+		domainProject: domainProject,
+		expiredAt:     now,
+	})
+	t.evict(now)
+	return true
+}
+func (t *Tracker) evict(now time.Time) {
+	i := 0
+	for ; i < len(t.expired); i++ {
+		if now.Sub(t.expired[i].expiredAt) <= t.Retention && len(t.expired)-i <= t.Max {
+			break
+		}
+	}
+	if i > 0 {
+		t.expired = append(t.expired[:0], t.expired[i:]...)
+	}
+}
+
+// Expired returns the instances of the domain project expired within
+// the duration, the latest first
+func (t *Tracker) Expired(domainProject string, within time.Duration) []*ExpiredInstance {
+	now := time.Now()
+	items := []*ExpiredInstance{}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.evict(now)
+	for i := len(t.expired) - 1; i >= 0; i-- {
+		e := t.expired[i]
+		if now.Sub(e.expiredAt) > within {
+			break
+		}
+		if e.domainProject == domainProject {
+			items = append(items, e)
+		}
+	}
+	return items
+}
+
+func NewTracker(retention time.Duration, max int) *Tracker {
+	return &Tracker{
+		Retention: retention,
+		Max:       max,
+		Revoked:   isRevoked,
+	}
+}
+
+// isRevoked looks up the revoke marker in the cache of the markers watched
+func isRevoked(ctx context.Context, domainProject, serviceId, instanceId string) (bool, error) {
+	resp, err := backend.Store().RevokedLease().Search(ctx,
+		registry.WithStrKey(core.GenerateRevokedLeaseKey(domainProject, serviceId, instanceId)),
+		registry.WithCountOnly())
+	if err != nil {
+		return false, err
+	}
+	return resp.Count > 0, nil
+}
+
+// MarkRevoked must be called before revoking the lease of the instance, the
+// marker is bound to a lease of revokeTimeout
+func MarkRevoked(ctx context.Context, domainProject, serviceId, instanceId string) {
+	leaseID, err := backend.Registry().LeaseGrant(ctx, int64(revokeTimeout.Seconds()))
+	if err == nil {
+		_, err = backend.Registry().Do(ctx, registry.PUT,
+			registry.WithStrKey(core.GenerateRevokedLeaseKey(domainProject, serviceId, instanceId)),
+			registry.WithStrValue(strconv.FormatInt(time.Now().Unix(), 10)),
+			registry.WithLease(leaseID))
+	}
+	if err != nil {
+		log.Errorf(err, "mark the lease of instance[%s/%s] revoked failed", serviceId, instanceId)
+	}
+}
+
+// MarkRevokedAll is MarkRevoked of the instance keys deleted, the markers
+// share one lease and are committed in batches, so they are put within
+// revokeCheckDelay after the deletion
+func MarkRevokedAll(ctx context.Context, instanceKeys [][]byte) {
+	if len(instanceKeys) == 0 {
+		return
+	}
+	leaseID, err := backend.Registry().LeaseGrant(ctx, int64(revokeTimeout.Seconds()))
+	if err == nil {
+		now := strconv.FormatInt(time.Now().Unix(), 10)
+		ops := make([]registry.PluginOp, 0, len(instanceKeys))
+		for _, key := range instanceKeys {
+			serviceId, instanceId, domainProject := core.GetInfoFromInstKV(key)
+			ops = append(ops, registry.OpPut(
+				registry.WithStrKey(core.GenerateRevokedLeaseKey(domainProject, serviceId, instanceId)),
+				registry.WithStrValue(now), registry.WithLease(leaseID)))
+		}
+		err = backend.BatchCommit(ctx, ops)
+	}
+	if err != nil {
+		log.Errorf(err, "mark the leases of %d instances revoked failed", len(instanceKeys))
+	}
+}
+
+// OnInstanceDeleted records the instance if its lease expired, only the
+// leader reports the expiration, so it is counted once in the cluster.
+// The marker is put before the lease revoked, but its event may arrive
+// later than the deletion, so the check is delayed
+func OnInstanceDeleted(ctx context.Context, domainProject string, instance *pb.MicroServiceInstance) {
+	time.AfterFunc(revokeCheckDelay, func() {
+		if tracker.OnDeleted(ctx, domainProject, instance) && job.IsLeader() {
+			ReportExpire(domainProject, instance.ServiceId)
+		}
+	})
+}
+
+func Expired(domainProject string, within time.Duration) []*ExpiredInstance {
+	return tracker.Expired(domainProject, within)
+}
@@ -0,0 +1,71 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package lease
+
+import (
+	"errors"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"golang.org/x/net/context"
+	"testing"
+	"time"
+)
+
+func TestTracker_OnDeleted(t *testing.T) {
+	tr := NewTracker(time.Hour, 2)
+	inst := func(id string) *pb.MicroServiceInstance {
+		return &pb.MicroServiceInstance{ServiceId: "svc", InstanceId: id}
+	}
+	// the markers written by any member of the cluster
+	markers := map[string]bool{"a/a/svc/1": true}
+	var checkErr error
+	tr.Revoked = func(_ context.Context, domainProject, serviceId, instanceId string) (bool, error) {
+		return markers[domainProject+"/"+serviceId+"/"+instanceId], checkErr
+	}
+	ctx := context.Background()
+
+	if tr.OnDeleted(ctx, "a/a", inst("1")) {
+		t.Fatalf("TestTracker_OnDeleted failed, revoked instance is regarded as expired")
+	}
+	checkErr = errors.New("error")
+	if tr.OnDeleted(ctx, "a/a", inst("4")) {
+		t.Fatalf("TestTracker_OnDeleted failed, regarded as expired if the marker is unknown")
+	}
+	checkErr = nil
+	if !tr.OnDeleted(ctx, "b/b", inst("2")) || !tr.OnDeleted(ctx, "a/a", inst("3")) {
+		t.Fatalf("TestTracker_OnDeleted failed")
+	}
+
+	// max is 2
+	items := tr.Expired("a/a", time.Minute)
+	if len(items) != 1 || items[0].InstanceId != "3" {
+		t.Fatalf("TestTracker_OnDeleted failed, %v", items)
+	}
+	items = tr.Expired("b/b", time.Minute)
+	if len(items) != 1 || items[0].InstanceId != "2" {
+		t.Fatalf("TestTracker_OnDeleted failed, %v", items)
+	}
+
+	tr.expired[0].expiredAt = time.Now().Add(-10 * time.Minute)
+	if items := tr.Expired("b/b", 5*time.Minute); len(items) != 0 {
+		t.Fatalf("TestTracker_OnDeleted failed, %v", items)
+	}
+
+	tr.Retention = time.Minute
+	if items := tr.Expired("b/b", time.Hour); len(items) != 0 || len(tr.expired) != 1 {
+		t.Fatalf("TestTracker_OnDeleted failed, %v", items)
+	}
+}
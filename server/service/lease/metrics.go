@@ -0,0 +1,79 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package lease
+
+import (
+	"github.com/apache/servicecomb-service-center/server/metric"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	RESULT_SUCCESS = "success"
+	RESULT_FAILURE = "failure"
+)
+
+var (
+	grantCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metric.FamilyName,
+			Subsystem: "lease",
+			Name:      "grant_total",
+			Help:      "Counter of the leases granted to the instances",
+		}, []string{"instance", "domain", "service", "result"})
+
+	renewCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metric.FamilyName,
+			Subsystem: "lease",
+			Name:      "renew_total",
+			Help:      "Counter of the leases renewed by the instance heartbeats",
+		}, []string{"instance", "domain", "service", "result"})
+
+	expireCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metric.FamilyName,
+			Subsystem: "lease",
+			Name:      "expire_total",
+			Help:      "Counter of the instances removed by the lease expiration",
+		}, []string{"instance", "domain", "service"})
+)
+
+func init() {
+	prometheus.MustRegister(grantCounter, renewCounter, expireCounter)
+}
+
+func resultOf(err error) string {
+	if err != nil {
+		return RESULT_FAILURE
+	}
+	return RESULT_SUCCESS
+}
+
+func ReportGrant(domainProject, serviceId string, err error) {
+	instance := metric.InstanceName()
+	grantCounter.WithLabelValues(instance, domainProject, serviceId, resultOf(err)).Inc()
+}
+
+func ReportRenew(domainProject, serviceId string, err error) {
+	instance := metric.InstanceName()
+	renewCounter.WithLabelValues(instance, domainProject, serviceId, resultOf(err)).Inc()
+}
+
+func ReportExpire(domainProject, serviceId string) {
+	instance := metric.InstanceName()
+	expireCounter.WithLabelValues(instance, domainProject, serviceId).Inc()
+}
@@ -17,7 +17,6 @@
 package service
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/apache/servicecomb-service-center/pkg/gopool"
@@ -49,7 +48,7 @@ const (
 
 func (s *MicroServiceService) Create(ctx context.Context, in *pb.CreateServiceRequest) (*pb.CreateServiceResponse, error) {
 	if in == nil || in.Service == nil {
-		log.Errorf(nil, "create micro-service failed: request body is empty")
+		log.WithContext(ctx).Errorf(nil, "create micro-service failed: request body is empty")
 		return &pb.CreateServiceResponse{
 			Response: pb.CreateResponse(scerr.ErrInvalidParams, "Request body is empty"),
 		}, nil
@@ -79,13 +78,21 @@ func (s *MicroServiceService) CreateServicePri(ctx context.Context, in *pb.Creat
 
 	err := Validate(in)
 	if err != nil {
-		log.Errorf(err, "create micro-service[%s] failed, operator: %s",
+		log.WithContext(ctx).Errorf(err, "create micro-service[%s] failed, operator: %s",
 			serviceFlag, remoteIP)
 		return &pb.CreateServiceResponse{
 			Response: pb.CreateResponse(scerr.ErrInvalidParams, err.Error()),
 		}, nil
 	}
 
+	if err := checkServiceIdentity(ctx, service.ServiceName); err != nil {
+		log.WithContext(ctx).Errorf(err, "create micro-service[%s] failed, operator: %s",
+			serviceFlag, remoteIP)
+		return &pb.CreateServiceResponse{
+			Response: pb.CreateResponseWithSCErr(err),
+		}, nil
+	}
+
 	domainProject := util.ParseDomainProject(ctx)
 
 	serviceKey := &pb.MicroServiceKey{
@@ -100,7 +107,7 @@ func (s *MicroServiceService) CreateServicePri(ctx context.Context, in *pb.Creat
 	defer reporter.Close(ctx)
 
 	if reporter != nil && reporter.Err != nil {
-		log.Errorf(reporter.Err, "create micro-service[%s] failed, operator: %s",
+		log.WithContext(ctx).Errorf(reporter.Err, "create micro-service[%s] failed, operator: %s",
 			serviceFlag, remoteIP)
 		resp := &pb.CreateServiceResponse{
 			Response: pb.CreateResponseWithSCErr(reporter.Err),
@@ -122,42 +129,20 @@ func (s *MicroServiceService) CreateServicePri(ctx context.Context, in *pb.Creat
 	service.Timestamp = strconv.FormatInt(time.Now().Unix(), 10)
 	service.ModTimestamp = service.Timestamp
 
-	data, err := json.Marshal(service)
+	data, err := util.JsonMarshal(service.WithoutRevision())
 	if err != nil {
-		log.Errorf(err, "create micro-service[%s] failed, json marshal service failed, operator: %s",
+		log.WithContext(ctx).Errorf(err, "create micro-service[%s] failed, json marshal service failed, operator: %s",
 			serviceFlag, remoteIP)
 		return &pb.CreateServiceResponse{
 			Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
 		}, err
 	}
 
-	key := apt.GenerateServiceKey(domainProject, service.ServiceId)
-	keyBytes := util.StringToBytesWithNoCopy(key)
-	indexBytes := util.StringToBytesWithNoCopy(index)
-	aliasBytes := util.StringToBytesWithNoCopy(apt.GenerateServiceAliasKey(serviceKey))
-
-	opts := []registry.PluginOp{
-		registry.OpPut(registry.WithKey(keyBytes), registry.WithValue(data)),
-		registry.OpPut(registry.WithKey(indexBytes), registry.WithStrValue(service.ServiceId)),
-	}
-	uniqueCmpOpts := []registry.CompareOp{
-		registry.OpCmp(registry.CmpVer(indexBytes), registry.CMP_EQUAL, 0),
-		registry.OpCmp(registry.CmpVer(keyBytes), registry.CMP_EQUAL, 0),
-	}
-	failOpts := []registry.PluginOp{
-		registry.OpGet(registry.WithKey(indexBytes)),
-	}
-
-	if len(serviceKey.Alias) > 0 {
-		opts = append(opts, registry.OpPut(registry.WithKey(aliasBytes), registry.WithStrValue(service.ServiceId)))
-		uniqueCmpOpts = append(uniqueCmpOpts,
-			registry.OpCmp(registry.CmpVer(aliasBytes), registry.CMP_EQUAL, 0))
-		failOpts = append(failOpts, registry.OpGet(registry.WithKey(aliasBytes)))
-	}
-
+	opts, uniqueCmpOpts, failOpts := createServiceOps(domainProject, serviceKey, service.ServiceId, data)
+	opts = append(opts, serviceUtil.TouchRevisionOps(domainProject)...)
 	resp, err := backend.Registry().TxnWithCmp(ctx, opts, uniqueCmpOpts, failOpts)
 	if err != nil {
-		log.Errorf(err, "create micro-service[%s] failed, operator: %s",
+		log.WithContext(ctx).Errorf(err, "create micro-service[%s] failed, operator: %s",
 			serviceFlag, remoteIP)
 		return &pb.CreateServiceResponse{
 			Response: pb.CreateResponse(scerr.ErrUnavailableBackend, err.Error()),
@@ -167,7 +152,7 @@ func (s *MicroServiceService) CreateServicePri(ctx context.Context, in *pb.Creat
 		if len(requestServiceId) != 0 {
 			if len(resp.Kvs) == 0 ||
 				requestServiceId != util.BytesToStringWithNoCopy(resp.Kvs[0].Value) {
-				log.Warnf("create micro-service[%s] failed, service already exists, operator: %s",
+				log.WithContext(ctx).Warnf("create micro-service[%s] failed, service already exists, operator: %s",
 					serviceFlag, remoteIP)
 				return &pb.CreateServiceResponse{
 					Response: pb.CreateResponse(scerr.ErrServiceAlreadyExists,
@@ -178,7 +163,7 @@ func (s *MicroServiceService) CreateServicePri(ctx context.Context, in *pb.Creat
 
 		if len(resp.Kvs) == 0 {
 			// internal error?
-			log.Errorf(nil, "create micro-service[%s] failed, unexpected txn response, operator: %s",
+			log.WithContext(ctx).Errorf(nil, "create micro-service[%s] failed, unexpected txn response, operator: %s",
 				serviceFlag, remoteIP)
 			return &pb.CreateServiceResponse{
 				Response: pb.CreateResponse(scerr.ErrInternal, "Unexpected txn response."),
@@ -186,7 +171,7 @@ func (s *MicroServiceService) CreateServicePri(ctx context.Context, in *pb.Creat
 		}
 
 		serviceIdInner := util.BytesToStringWithNoCopy(resp.Kvs[0].Value)
-		log.Warnf("create micro-service[%s][%s] failed, service already exists, operator: %s",
+		log.WithContext(ctx).Warnf("create micro-service[%s][%s] failed, service already exists, operator: %s",
 			serviceIdInner, serviceFlag, remoteIP)
 		return &pb.CreateServiceResponse{
 			Response:  pb.CreateResponse(pb.Response_SUCCESS, "register service successfully"),
@@ -195,10 +180,10 @@ func (s *MicroServiceService) CreateServicePri(ctx context.Context, in *pb.Creat
 	}
 
 	if err := reporter.ReportUsedQuota(ctx); err != nil {
-		log.Errorf(err, "report the used quota failed")
+		log.WithContext(ctx).Errorf(err, "report the used quota failed")
 	}
 
-	log.Infof("create micro-service[%s][%s] successfully, operator: %s",
+	log.WithContext(ctx).Infof("create micro-service[%s][%s] successfully, operator: %s",
 		service.ServiceId, serviceFlag, remoteIP)
 	return &pb.CreateServiceResponse{
 		Response:  pb.CreateResponse(pb.Response_SUCCESS, "Register service successfully."),
@@ -206,9 +191,39 @@ func (s *MicroServiceService) CreateServicePri(ctx context.Context, in *pb.Creat
 	}, nil
 }
 
+// createServiceOps returns the ops to create the service if the service
+// key, index and alias are absent, the fail ops get the existing index and
+// alias
+func createServiceOps(domainProject string, serviceKey *pb.MicroServiceKey, serviceId string, data []byte) (
+	opts []registry.PluginOp, uniqueCmpOpts []registry.CompareOp, failOpts []registry.PluginOp) {
+	keyBytes := util.StringToBytesWithNoCopy(apt.GenerateServiceKey(domainProject, serviceId))
+	indexBytes := util.StringToBytesWithNoCopy(apt.GenerateServiceIndexKey(serviceKey))
+	aliasBytes := util.StringToBytesWithNoCopy(apt.GenerateServiceAliasKey(serviceKey))
+
+	opts = []registry.PluginOp{
+		registry.OpPut(registry.WithKey(keyBytes), registry.WithValue(data)),
+		registry.OpPut(registry.WithKey(indexBytes), registry.WithStrValue(serviceId)),
+	}
+	uniqueCmpOpts = []registry.CompareOp{
+		registry.OpCmp(registry.CmpVer(indexBytes), registry.CMP_EQUAL, 0),
+		registry.OpCmp(registry.CmpVer(keyBytes), registry.CMP_EQUAL, 0),
+	}
+	failOpts = []registry.PluginOp{
+		registry.OpGet(registry.WithKey(indexBytes)),
+	}
+
+	if len(serviceKey.Alias) > 0 {
+		opts = append(opts, registry.OpPut(registry.WithKey(aliasBytes), registry.WithStrValue(serviceId)))
+		uniqueCmpOpts = append(uniqueCmpOpts,
+			registry.OpCmp(registry.CmpVer(aliasBytes), registry.CMP_EQUAL, 0))
+		failOpts = append(failOpts, registry.OpGet(registry.WithKey(aliasBytes)))
+	}
+	return
+}
+
 func checkQuota(ctx context.Context, domainProject string) *quota.ApplyQuotaResult {
 	if core.IsSCInstance(ctx) {
-		log.Debugf("register service-center, skip quota check")
+		log.WithContext(ctx).Debugf("register service-center, skip quota check")
 		return nil
 	}
 	res := quota.NewApplyQuotaResource(quota.MicroServiceQuotaType, domainProject, "", 1)
@@ -227,19 +242,19 @@ func (s *MicroServiceService) DeleteServicePri(ctx context.Context, serviceId st
 
 	if serviceId == apt.Service.ServiceId {
 		err := errors.New("not allow to delete service center")
-		log.Errorf(err, "%s micro-service[%s] failed, operator: %s", title, serviceId, remoteIP)
+		log.WithContext(ctx).Errorf(err, "%s micro-service[%s] failed, operator: %s", title, serviceId, remoteIP)
 		return pb.CreateResponse(scerr.ErrInvalidParams, err.Error()), nil
 	}
 
 	service, err := serviceUtil.GetService(ctx, domainProject, serviceId)
 	if err != nil {
-		log.Errorf(err, "%s micro-service[%s] failed, get service file failed, operator: %s",
+		log.WithContext(ctx).Errorf(err, "%s micro-service[%s] failed, get service file failed, operator: %s",
 			title, serviceId, remoteIP)
 		return pb.CreateResponse(scerr.ErrInternal, err.Error()), err
 	}
 
 	if service == nil {
-		log.Errorf(err, "%s micro-service[%s] failed, service does not exist, operator: %s",
+		log.WithContext(ctx).Errorf(err, "%s micro-service[%s] failed, service does not exist, operator: %s",
 			title, serviceId, remoteIP)
 		return pb.CreateResponse(scerr.ErrServiceNotExists, "Service does not exist."), nil
 	}
@@ -249,12 +264,12 @@ func (s *MicroServiceService) DeleteServicePri(ctx context.Context, serviceId st
 		dr := serviceUtil.NewProviderDependencyRelation(ctx, domainProject, service)
 		services, err := dr.GetDependencyConsumerIds()
 		if err != nil {
-			log.Errorf(err, "delete micro-service[%s] failed, get service dependency failed, operator: %s",
+			log.WithContext(ctx).Errorf(err, "delete micro-service[%s] failed, get service dependency failed, operator: %s",
 				serviceId, remoteIP)
 			return pb.CreateResponse(scerr.ErrInternal, err.Error()), err
 		}
 		if l := len(services); l > 1 || (l == 1 && services[0] != serviceId) {
-			log.Errorf(nil, "delete micro-service[%s] failed, other services[%d] depend on it, operator: %s",
+			log.WithContext(ctx).Errorf(nil, "delete micro-service[%s] failed, other services[%d] depend on it, operator: %s",
 				serviceId, l, remoteIP)
 			return pb.CreateResponse(scerr.ErrDependedOnConsumer, "Can not delete this service, other service rely it."), err
 		}
@@ -265,13 +280,13 @@ func (s *MicroServiceService) DeleteServicePri(ctx context.Context, serviceId st
 			registry.WithPrefix(),
 			registry.WithCountOnly())
 		if err != nil {
-			log.Errorf(err, "delete micro-service[%s] failed, get instances failed, operator: %s",
+			log.WithContext(ctx).Errorf(err, "delete micro-service[%s] failed, get instances failed, operator: %s",
 				serviceId, remoteIP)
 			return pb.CreateResponse(scerr.ErrUnavailableBackend, err.Error()), err
 		}
 
 		if rsp.Count > 0 {
-			log.Errorf(nil, "delete micro-service[%s] failed, service deployed instances[%s], operator: %s",
+			log.WithContext(ctx).Errorf(nil, "delete micro-service[%s] failed, service deployed instances[%s], operator: %s",
 				serviceId, rsp.Count, remoteIP)
 			return pb.CreateResponse(scerr.ErrDeployedInstance, "Can not delete the service deployed instance(s)."), err
 		}
@@ -295,7 +310,7 @@ func (s *MicroServiceService) DeleteServicePri(ctx context.Context, serviceId st
 	//删除依赖规则
 	optDeleteDep, err := serviceUtil.DeleteDependencyForDeleteService(domainProject, serviceId, serviceKey)
 	if err != nil {
-		log.Errorf(err, "%s micro-service[%s] failed, delete dependency failed, operator: %s",
+		log.WithContext(ctx).Errorf(err, "%s micro-service[%s] failed, delete dependency failed, operator: %s",
 			title, serviceId, remoteIP)
 		return pb.CreateResponse(scerr.ErrInternal, err.Error()), err
 	}
@@ -332,7 +347,7 @@ func (s *MicroServiceService) DeleteServicePri(ctx context.Context, serviceId st
 	//删除实例
 	err = serviceUtil.DeleteServiceAllInstances(ctx, serviceId)
 	if err != nil {
-		log.Errorf(err, "%s micro-service[%s] failed, revoke all instances failed, operator: %s",
+		log.WithContext(ctx).Errorf(err, "%s micro-service[%s] failed, revoke all instances failed, operator: %s",
 			title, serviceId, remoteIP)
 		return pb.CreateResponse(scerr.ErrUnavailableBackend, err.Error()), err
 	}
@@ -343,18 +358,18 @@ func (s *MicroServiceService) DeleteServicePri(ctx context.Context, serviceId st
 			registry.CMP_NOT_EQUAL, 0)},
 		nil)
 	if err != nil {
-		log.Errorf(err, "%s micro-service[%s] failed, operator: %s", title, serviceId, remoteIP)
+		log.WithContext(ctx).Errorf(err, "%s micro-service[%s] failed, operator: %s", title, serviceId, remoteIP)
 		return pb.CreateResponse(scerr.ErrUnavailableBackend, err.Error()), err
 	}
 	if !resp.Succeeded {
-		log.Errorf(err, "%s micro-service[%s] failed, service does not exist, operator: %s",
+		log.WithContext(ctx).Errorf(err, "%s micro-service[%s] failed, service does not exist, operator: %s",
 			title, serviceId, remoteIP)
 		return pb.CreateResponse(scerr.ErrServiceNotExists, "Service does not exist."), nil
 	}
 
 	serviceUtil.RemandServiceQuota(ctx)
 
-	log.Infof("%s micro-service[%s] successfully, operator: %s", title, serviceId, remoteIP)
+	log.WithContext(ctx).Infof("%s micro-service[%s] successfully, operator: %s", title, serviceId, remoteIP)
 	return pb.CreateResponse(pb.Response_SUCCESS, "Unregister service successfully."), nil
 }
 
@@ -362,7 +377,7 @@ func (s *MicroServiceService) Delete(ctx context.Context, in *pb.DeleteServiceRe
 	remoteIP := util.GetIPFromContext(ctx)
 	err := Validate(in)
 	if err != nil {
-		log.Errorf(err, "delete micro-service[%s] failed, operator: %s", in.ServiceId, remoteIP)
+		log.WithContext(ctx).Errorf(err, "delete micro-service[%s] failed, operator: %s", in.ServiceId, remoteIP)
 		return &pb.DeleteServiceResponse{
 			Response: pb.CreateResponse(scerr.ErrInvalidParams, err.Error()),
 		}, nil
@@ -379,7 +394,7 @@ func (s *MicroServiceService) DeleteServices(ctx context.Context, request *pb.De
 	remoteIP := util.GetIPFromContext(ctx)
 	// 合法性检查
 	if len(request.ServiceIds) == 0 {
-		log.Errorf(nil, "delete all micro-services failed, 'serviceIds' is empty, operator: %s", remoteIP)
+		log.WithContext(ctx).Errorf(nil, "delete all micro-services failed, 'serviceIds' is empty, operator: %s", remoteIP)
 		return &pb.DelServicesResponse{
 			Response: pb.CreateResponse(scerr.ErrInvalidParams, "'serviceIds' is empty"),
 			Services: nil,
@@ -393,7 +408,7 @@ func (s *MicroServiceService) DeleteServices(ctx context.Context, request *pb.De
 	for _, serviceId := range request.ServiceIds {
 		//ServiceId重复性检查
 		if _, ok := existFlag[serviceId]; ok {
-			log.Warnf("duplicate micro-service[%s] serviceId, operator: %s", serviceId, remoteIP)
+			log.WithContext(ctx).Warnf("duplicate micro-service[%s] serviceId, operator: %s", serviceId, remoteIP)
 			continue
 		} else {
 			existFlag[serviceId] = true
@@ -407,7 +422,7 @@ func (s *MicroServiceService) DeleteServices(ctx context.Context, request *pb.De
 		}
 		err := Validate(in)
 		if err != nil {
-			log.Errorf(err, "delete micro-service[%s] failed, operator: %s", in.ServiceId, remoteIP)
+			log.WithContext(ctx).Errorf(err, "delete micro-service[%s] failed, operator: %s", in.ServiceId, remoteIP)
 			serviceRespChan <- &pb.DelServicesRspInfo{
 				ServiceId:  serviceId,
 				ErrMessage: err.Error(),
@@ -435,7 +450,7 @@ func (s *MicroServiceService) DeleteServices(ctx context.Context, request *pb.De
 		}
 	}
 
-	log.Infof("Batch delete micro-services by serviceIds[%d]: %v, result code: %d, operator: %s",
+	log.WithContext(ctx).Infof("Batch delete micro-services by serviceIds[%d]: %v, result code: %d, operator: %s",
 		len(request.ServiceIds), request.ServiceIds, responseCode, remoteIP)
 
 	resp := &pb.DelServicesResponse{
@@ -469,7 +484,7 @@ func (s *MicroServiceService) getDeleteServiceFunc(ctx context.Context, serviceI
 func (s *MicroServiceService) GetOne(ctx context.Context, in *pb.GetServiceRequest) (*pb.GetServiceResponse, error) {
 	err := Validate(in)
 	if err != nil {
-		log.Errorf(err, "get micro-service[%s] failed", in.ServiceId)
+		log.WithContext(ctx).Errorf(err, "get micro-service[%s] failed", in.ServiceId)
 		return &pb.GetServiceResponse{
 			Response: pb.CreateResponse(scerr.ErrInvalidParams, err.Error()),
 		}, nil
@@ -478,13 +493,13 @@ func (s *MicroServiceService) GetOne(ctx context.Context, in *pb.GetServiceReque
 	service, err := serviceUtil.GetService(ctx, domainProject, in.ServiceId)
 
 	if err != nil {
-		log.Errorf(err, "get micro-service[%s] failed, get service file failed", in.ServiceId)
+		log.WithContext(ctx).Errorf(err, "get micro-service[%s] failed, get service file failed", in.ServiceId)
 		return &pb.GetServiceResponse{
 			Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
 		}, err
 	}
 	if service == nil {
-		log.Errorf(nil, "get micro-service[%s] failed, service does not exist", in.ServiceId)
+		log.WithContext(ctx).Errorf(nil, "get micro-service[%s] failed, service does not exist", in.ServiceId)
 		return &pb.GetServiceResponse{
 			Response: pb.CreateResponse(scerr.ErrServiceNotExists, "Service does not exist."),
 		}, nil
@@ -498,7 +513,7 @@ func (s *MicroServiceService) GetOne(ctx context.Context, in *pb.GetServiceReque
 func (s *MicroServiceService) GetServices(ctx context.Context, in *pb.GetServicesRequest) (*pb.GetServicesResponse, error) {
 	services, err := serviceUtil.GetAllServiceUtil(ctx)
 	if err != nil {
-		log.Errorf(err, "get all services by domain failed")
+		log.WithContext(ctx).Errorf(err, "get all services by domain failed")
 		return &pb.GetServicesResponse{
 			Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
 		}, err
@@ -514,7 +529,7 @@ func (s *MicroServiceService) UpdateProperties(ctx context.Context, in *pb.Updat
 	remoteIP := util.GetIPFromContext(ctx)
 	err := Validate(in)
 	if err != nil {
-		log.Errorf(err, "update service[%s] properties failed, operator: %s", in.ServiceId, remoteIP)
+		log.WithContext(ctx).Errorf(err, "update service[%s] properties failed, operator: %s", in.ServiceId, remoteIP)
 		return &pb.UpdateServicePropsResponse{
 			Response: pb.CreateResponse(scerr.ErrInvalidParams, err.Error()),
 		}, nil
@@ -525,27 +540,34 @@ func (s *MicroServiceService) UpdateProperties(ctx context.Context, in *pb.Updat
 	key := apt.GenerateServiceKey(domainProject, in.ServiceId)
 	service, err := serviceUtil.GetService(ctx, domainProject, in.ServiceId)
 	if err != nil {
-		log.Errorf(err, "update service[%s] properties failed, get service file failed, operator: %s",
+		log.WithContext(ctx).Errorf(err, "update service[%s] properties failed, get service file failed, operator: %s",
 			in.ServiceId, remoteIP)
 		return &pb.UpdateServicePropsResponse{
 			Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
 		}, err
 	}
 	if service == nil {
-		log.Errorf(nil, "update service[%s] properties failed, service does not exist, operator: %s",
+		log.WithContext(ctx).Errorf(nil, "update service[%s] properties failed, service does not exist, operator: %s",
 			in.ServiceId, remoteIP)
 		return &pb.UpdateServicePropsResponse{
 			Response: pb.CreateResponse(scerr.ErrServiceNotExists, "Service does not exist."),
 		}, nil
 	}
+	if err := checkServiceIdentity(ctx, service.ServiceName); err != nil {
+		log.WithContext(ctx).Errorf(err, "update service[%s] properties failed, operator: %s",
+			in.ServiceId, remoteIP)
+		return &pb.UpdateServicePropsResponse{
+			Response: pb.CreateResponseWithSCErr(err),
+		}, nil
+	}
 
 	copyServiceRef := *service
 	copyServiceRef.Properties = in.Properties
 	copyServiceRef.ModTimestamp = strconv.FormatInt(time.Now().Unix(), 10)
 
-	data, err := json.Marshal(copyServiceRef)
+	data, err := util.JsonMarshal(copyServiceRef.WithoutRevision())
 	if err != nil {
-		log.Errorf(err, "update service[%s] properties failed, json marshal service failed, operator: %s",
+		log.WithContext(ctx).Errorf(err, "update service[%s] properties failed, json marshal service failed, operator: %s",
 			in.ServiceId, remoteIP)
 		return &pb.UpdateServicePropsResponse{
 			Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
@@ -560,20 +582,20 @@ func (s *MicroServiceService) UpdateProperties(ctx context.Context, in *pb.Updat
 			registry.CMP_NOT_EQUAL, 0)},
 		nil)
 	if err != nil {
-		log.Errorf(err, "update service[%s] properties failed, operator: %s", in.ServiceId, remoteIP)
+		log.WithContext(ctx).Errorf(err, "update service[%s] properties failed, operator: %s", in.ServiceId, remoteIP)
 		return &pb.UpdateServicePropsResponse{
 			Response: pb.CreateResponse(scerr.ErrUnavailableBackend, err.Error()),
 		}, err
 	}
 	if !resp.Succeeded {
-		log.Errorf(err, "update service[%s] properties failed, service does not exist, operator: %s",
+		log.WithContext(ctx).Errorf(err, "update service[%s] properties failed, service does not exist, operator: %s",
 			in.ServiceId, remoteIP)
 		return &pb.UpdateServicePropsResponse{
 			Response: pb.CreateResponse(scerr.ErrServiceNotExists, "Service does not exist."),
 		}, nil
 	}
 
-	log.Infof("update service[%s] properties successfully, operator: %s", in.ServiceId, remoteIP)
+	log.WithContext(ctx).Infof("update service[%s] properties successfully, operator: %s", in.ServiceId, remoteIP)
 	return &pb.UpdateServicePropsResponse{
 		Response: pb.CreateResponse(pb.Response_SUCCESS, "update service successfully."),
 	}, nil
@@ -586,7 +608,7 @@ func (s *MicroServiceService) Exist(ctx context.Context, in *pb.GetExistenceRequ
 		err := ExistenceReqValidator().Validate(in)
 		serviceFlag := util.StringJoin([]string{in.Environment, in.AppId, in.ServiceName, in.Version}, "/")
 		if err != nil {
-			log.Errorf(err, "micro-service[%s] exist failed", serviceFlag)
+			log.WithContext(ctx).Errorf(err, "micro-service[%s] exist failed", serviceFlag)
 			return &pb.GetExistenceResponse{
 				Response: pb.CreateResponse(scerr.ErrInvalidParams, err.Error()),
 			}, nil
@@ -601,19 +623,19 @@ func (s *MicroServiceService) Exist(ctx context.Context, in *pb.GetExistenceRequ
 			Tenant:      domainProject,
 		})
 		if err != nil {
-			log.Errorf(err, "micro-service[%s] exist failed, find serviceIds failed", serviceFlag)
+			log.WithContext(ctx).Errorf(err, "micro-service[%s] exist failed, find serviceIds failed", serviceFlag)
 			return &pb.GetExistenceResponse{
 				Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
 			}, err
 		}
 		if !exist {
-			log.Infof("micro-service[%s] exist failed, service does not exist", serviceFlag)
+			log.WithContext(ctx).Infof("micro-service[%s] exist failed, service does not exist", serviceFlag)
 			return &pb.GetExistenceResponse{
 				Response: pb.CreateResponse(scerr.ErrServiceNotExists, serviceFlag+" does not exist."),
 			}, nil
 		}
 		if len(ids) == 0 {
-			log.Infof("micro-service[%s] exist failed, version mismatch", serviceFlag)
+			log.WithContext(ctx).Infof("micro-service[%s] exist failed, version mismatch", serviceFlag)
 			return &pb.GetExistenceResponse{
 				Response: pb.CreateResponse(scerr.ErrServiceVersionNotExists, serviceFlag+" version mismatch."),
 			}, nil
@@ -625,14 +647,14 @@ func (s *MicroServiceService) Exist(ctx context.Context, in *pb.GetExistenceRequ
 	case EXIST_TYPE_SCHEMA:
 		err := GetSchemaReqValidator().Validate(in)
 		if err != nil {
-			log.Errorf(err, "schema[%s/%s] exist failed", in.ServiceId, in.SchemaId)
+			log.WithContext(ctx).Errorf(err, "schema[%s/%s] exist failed", in.ServiceId, in.SchemaId)
 			return &pb.GetExistenceResponse{
 				Response: pb.CreateResponse(scerr.ErrInvalidParams, err.Error()),
 			}, nil
 		}
 
 		if !serviceUtil.ServiceExist(ctx, domainProject, in.ServiceId) {
-			log.Warnf("schema[%s/%s] exist failed, service does not exist", in.ServiceId, in.SchemaId)
+			log.WithContext(ctx).Warnf("schema[%s/%s] exist failed, service does not exist", in.ServiceId, in.SchemaId)
 			return &pb.GetExistenceResponse{
 				Response: pb.CreateResponse(scerr.ErrServiceNotExists, "service does not exist."),
 			}, nil
@@ -641,20 +663,20 @@ func (s *MicroServiceService) Exist(ctx context.Context, in *pb.GetExistenceRequ
 		key := apt.GenerateServiceSchemaKey(domainProject, in.ServiceId, in.SchemaId)
 		exist, err := serviceUtil.CheckSchemaInfoExist(ctx, key)
 		if err != nil {
-			log.Errorf(err, "schema[%s/%s] exist failed, get schema failed", in.ServiceId, in.SchemaId)
+			log.WithContext(ctx).Errorf(err, "schema[%s/%s] exist failed, get schema failed", in.ServiceId, in.SchemaId)
 			return &pb.GetExistenceResponse{
 				Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
 			}, err
 		}
 		if !exist {
-			log.Infof("schema[%s/%s] exist failed, schema does not exist", in.ServiceId, in.SchemaId)
+			log.WithContext(ctx).Infof("schema[%s/%s] exist failed, schema does not exist", in.ServiceId, in.SchemaId)
 			return &pb.GetExistenceResponse{
 				Response: pb.CreateResponse(scerr.ErrSchemaNotExists, "schema does not exist."),
 			}, nil
 		}
 		schemaSummary, err := getSchemaSummary(ctx, domainProject, in.ServiceId, in.SchemaId)
 		if err != nil {
-			log.Errorf(err, "schema[%s/%s] exist failed, get schema summary failed", in.ServiceId, in.SchemaId)
+			log.WithContext(ctx).Errorf(err, "schema[%s/%s] exist failed, get schema summary failed", in.ServiceId, in.SchemaId)
 			return &pb.GetExistenceResponse{
 				Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
 			}, err
@@ -665,7 +687,7 @@ func (s *MicroServiceService) Exist(ctx context.Context, in *pb.GetExistenceRequ
 			Summary:  schemaSummary,
 		}, nil
 	default:
-		log.Warnf("unexpected type '%s' for existence query.", in.Type)
+		log.WithContext(ctx).Warnf("unexpected type '%s' for existence query.", in.Type)
 		return &pb.GetExistenceResponse{
 			Response: pb.CreateResponse(scerr.ErrInvalidParams, "Only micro-service and schema can be used as type."),
 		}, nil
@@ -761,7 +783,7 @@ func (s *MicroServiceService) CreateServiceEx(ctx context.Context, in *pb.Create
 		result.Response.Code = pb.Response_SUCCESS
 	}
 
-	log.Infof("createServiceEx, serviceId: %s, result code: %s, operator: %s",
+	log.WithContext(ctx).Infof("createServiceEx, serviceId: %s, result code: %s, operator: %s",
 		result.ServiceId, result.Response.Message, util.GetIPFromContext(ctx))
 	return result, nil
 }
@@ -31,6 +31,8 @@ const (
 const (
 	NOTIFTY NotifyType = iota
 	INSTANCE
+	SINK
+	RESOURCE
 	typeEnd
 )
 
@@ -56,8 +58,11 @@ func (nt NotifyType) QueueSize() (s int) {
 var notifyTypeNames = []string{
 	NOTIFTY:  "NOTIFTY",
 	INSTANCE: "INSTANCE",
+	SINK:     "SINK",
+	RESOURCE: "RESOURCE",
 }
 
 var notifyTypeQueues = []int{
 	INSTANCE: 100 * 1000,
+	SINK:     100 * 1000,
 }
@@ -0,0 +1,39 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package notification
+
+import (
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+)
+
+const (
+	// EVENT_FORMAT_RAW encodes the event as WatchInstanceResponse
+	EVENT_FORMAT_RAW = "raw"
+	// EVENT_FORMAT_CLOUDEVENTS encodes the event in CloudEvents 1.0
+	// structured mode, see proto.CloudEvent
+	EVENT_FORMAT_CLOUDEVENTS = "cloudevents"
+)
+
+// EncodeEvent returns the object to marshal of the watch job in format
+func EncodeEvent(format, domainProject string, job *WatchJob) interface{} {
+	resp := job.Response
+	resp.Response = nil
+	if format == EVENT_FORMAT_CLOUDEVENTS {
+		return pb.NewInstanceCloudEvent(domainProject, job.Revision, resp)
+	}
+	return resp
+}
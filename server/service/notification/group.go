@@ -48,8 +48,13 @@ func (g *Group) AddSubscriber(subscriber Subscriber) Subscriber {
 	return g.subscribers.PutIfAbsent(subscriber.Id(), subscriber).(Subscriber)
 }
 
-func (g *Group) Remove(name string) {
+// Remove returns false if the subscriber does not exist
+func (g *Group) Remove(name string) bool {
+	if _, ok := g.subscribers.Get(name); !ok {
+		return false
+	}
 	g.subscribers.Remove(name)
+	return true
 }
 
 func (g *Group) Size() int {
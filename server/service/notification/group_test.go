@@ -46,7 +46,9 @@ func TestGroup_Add(t *testing.T) {
 	if g.Size() != 2 {
 		t.Fatalf("TestGroup_Add failed")
 	}
-	g.Remove(m.Id())
+	if !g.Remove(m.Id()) || g.Remove(m.Id()) {
+		t.Fatalf("TestGroup_Add failed")
+	}
 	if g.Size() != 1 {
 		t.Fatalf("TestGroup_Add failed")
 	}
@@ -0,0 +1,75 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package notification
+
+import (
+	"github.com/astaxie/beego"
+	"time"
+)
+
+const (
+	LISTENER_REST = "rest"
+	LISTENER_RPC  = "rpc"
+)
+
+var restKeepalive, rpcKeepalive KeepaliveConfig
+
+func init() {
+	restKeepalive = LoadKeepaliveConfig(LISTENER_REST)
+	rpcKeepalive = LoadKeepaliveConfig(LISTENER_RPC)
+}
+
+// KeepaliveConfig is the heartbeat config of the watch connections
+// accepted by a listener
+type KeepaliveConfig struct {
+	// PingInterval is the interval to ping the watcher
+	PingInterval time.Duration
+	// IdleTimeout closes the watcher if nothing is received from it
+	// within the timeout, 0 means never
+	IdleTimeout time.Duration
+}
+
+// LoadKeepaliveConfig reads '{listener}_watch_ping_interval' and
+// '{listener}_watch_idle_timeout'
+func LoadKeepaliveConfig(listener string) KeepaliveConfig {
+	cfg := KeepaliveConfig{PingInterval: DEFAULT_HEARTBEAT_INTERVAL}
+	interval, err := time.ParseDuration(beego.AppConfig.DefaultString(listener+"_watch_ping_interval", ""))
+	if err == nil && interval > 0 {
+		cfg.PingInterval = interval
+	}
+	idle, err := time.ParseDuration(beego.AppConfig.DefaultString(listener+"_watch_idle_timeout", ""))
+	if err == nil && idle > 0 {
+		cfg.IdleTimeout = idle
+	}
+	return cfg
+}
+
+// Deadline returns the read deadline of the watcher, zero means never
+func (cfg KeepaliveConfig) Deadline() time.Time {
+	if cfg.IdleTimeout <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(cfg.IdleTimeout)
+}
+
+func RESTKeepalive() KeepaliveConfig {
+	return restKeepalive
+}
+
+func RPCKeepalive() KeepaliveConfig {
+	return rpcKeepalive
+}
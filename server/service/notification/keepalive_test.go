@@ -0,0 +1,46 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package notification
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoadKeepaliveConfig(t *testing.T) {
+	cfg := LoadKeepaliveConfig("unknown")
+	if cfg.PingInterval != DEFAULT_HEARTBEAT_INTERVAL || cfg.IdleTimeout != 0 {
+		t.Fatalf("TestLoadKeepaliveConfig failed, %v", cfg)
+	}
+	if !cfg.Deadline().IsZero() {
+		t.Fatalf("TestLoadKeepaliveConfig failed")
+	}
+	cfg.IdleTimeout = time.Minute
+	if d := cfg.Deadline(); d.Before(time.Now().Add(59 * time.Second)) {
+		t.Fatalf("TestLoadKeepaliveConfig failed, %v", d)
+	}
+}
+
+func TestBaseSubscriber_Touch(t *testing.T) {
+	s := NewSubscriber(INSTANCE, "s", "g")
+	last := s.LastActivity()
+	time.Sleep(time.Millisecond)
+	s.Touch()
+	if !s.LastActivity().After(last) || len(s.Describe().LastActivity) == 0 {
+		t.Fatalf("TestBaseSubscriber_Touch failed")
+	}
+}
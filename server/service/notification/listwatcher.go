@@ -21,6 +21,7 @@ import (
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	pb "github.com/apache/servicecomb-service-center/server/core/proto"
 	"golang.org/x/net/context"
+	"sync/atomic"
 	"time"
 )
 
@@ -37,9 +38,20 @@ type ListWatcher struct {
 	ListRevision int64
 	ListFunc     func() (results []*pb.WatchInstanceResponse, rev int64)
 	listCh       chan struct{}
+	// Policy is applied if the Job queue is still full after Grace
+	Policy SlowConsumerPolicy
+	Grace  time.Duration
+
+	dropped int64
+	// laggingSince is the unix nano time when the Job queue became full
+	laggingSince int64
 }
 
 func (s *ListWatcher) SetError(err error) {
+	if s.Err() != nil {
+		// already removed
+		return
+	}
 	s.BaseSubscriber.SetError(err)
 	// 触发清理job
 	s.Service().AddJob(NewNotifyServiceHealthCheckJob(s))
@@ -61,6 +73,9 @@ func (w *ListWatcher) listAndPublishJobs(_ context.Context) {
 	results, rev := w.ListFunc()
 	w.ListRevision = rev
 	for _, response := range results {
+		if w.Err() != nil {
+			return
+		}
 		w.sendMessage(NewWatchJob(w.Group(), w.Subject(), w.ListRevision, response))
 	}
 }
@@ -79,14 +94,14 @@ func (w *ListWatcher) OnMessage(job NotifyJob) {
 	select {
 	case <-w.listCh:
 	default:
-		timer := time.NewTimer(w.Timeout())
+		timer := time.NewTimer(DEFAULT_ADD_JOB_TIMEOUT)
 		select {
 		case <-w.listCh:
 			timer.Stop()
 		case <-timer.C:
 			log.Errorf(nil,
 				"the %s listwatcher %s %s is not ready[over %s], send the event %v",
-				w.Type(), w.Group(), w.Subject(), w.Timeout(), job)
+				w.Type(), w.Group(), w.Subject(), DEFAULT_ADD_JOB_TIMEOUT, job)
 		}
 	}
 
@@ -100,35 +115,73 @@ func (w *ListWatcher) OnMessage(job NotifyJob) {
 
 func (w *ListWatcher) sendMessage(job *WatchJob) {
 	defer log.Recover()
+	defer ReportSubscriberLag(w.Id(), w.Subject(), w.Group(), len(w.Job))
 	select {
 	case w.Job <- job:
+		if len(w.Job)*2 < cap(w.Job) {
+			atomic.StoreInt64(&w.laggingSince, 0)
+		}
 	default:
+		atomic.CompareAndSwapInt64(&w.laggingSince, 0, time.Now().UnixNano())
 		timer := time.NewTimer(w.Timeout())
 		select {
 		case w.Job <- job:
 			timer.Stop()
 		case <-timer.C:
-			log.Errorf(nil,
-				"the %s watcher %s %s event queue is full[over %s], drop the event %v",
-				w.Type(), w.Group(), w.Subject(), w.Timeout(), job)
+			w.onSlow(job)
 		}
 	}
 }
 
+func (w *ListWatcher) onSlow(job *WatchJob) {
+	ReportSlowConsumer(string(w.Policy))
+	if w.Policy == SlowConsumerDisconnect {
+		log.Errorf(nil,
+			"the %s watcher %s %s event queue is full[over %s], disconnect it, last revision is %d",
+			w.Type(), w.Group(), w.Subject(), w.Timeout(), job.Revision)
+		w.SetError(ErrSlowConsumer)
+		return
+	}
+	atomic.AddInt64(&w.dropped, 1)
+	ReportDropped(w.Type(), dropReasonSlowConsumer)
+	log.Errorf(nil,
+		"the %s watcher %s %s event queue is full[over %s], drop the event %v",
+		w.Type(), w.Group(), w.Subject(), w.Timeout(), job)
+}
+
 func (w *ListWatcher) Timeout() time.Duration {
-	return DEFAULT_ADD_JOB_TIMEOUT
+	return w.Grace
+}
+
+func (w *ListWatcher) Lag() *Laggard {
+	l := &Laggard{
+		Id:       w.Id(),
+		Type:     w.Type().String(),
+		Subject:  w.Subject(),
+		Group:    w.Group(),
+		Policy:   string(w.Policy),
+		Pending:  len(w.Job),
+		Capacity: cap(w.Job),
+		Dropped:  atomic.LoadInt64(&w.dropped),
+	}
+	if since := atomic.LoadInt64(&w.laggingSince); since > 0 {
+		l.LaggingSince = time.Unix(0, since).Format(time.RFC3339)
+	}
+	return l
 }
 
 func (w *ListWatcher) Close() {
 	close(w.Job)
+	DeleteSubscriberLag(w.Id(), w.Subject(), w.Group())
 }
 
 func NewWatchJob(group, subject string, rev int64, response *pb.WatchInstanceResponse) *WatchJob {
 	return &WatchJob{
 		BaseNotifyJob: &BaseNotifyJob{
-			group:   group,
-			subject: subject,
-			nType:   INSTANCE,
+			group:    group,
+			subject:  subject,
+			nType:    INSTANCE,
+			createAt: time.Now(),
 		},
 		Revision: rev,
 		Response: response,
@@ -139,9 +192,11 @@ func NewListWatcher(group string, subject string,
 	listFunc func() (results []*pb.WatchInstanceResponse, rev int64)) *ListWatcher {
 	watcher := &ListWatcher{
 		BaseSubscriber: NewSubscriber(INSTANCE, subject, group),
-		Job:            make(chan *WatchJob, DEFAULT_MAX_QUEUE),
+		Job:            make(chan *WatchJob, subscriberConfig.QueueSize),
 		ListFunc:       listFunc,
 		listCh:         make(chan struct{}),
+		Policy:         subscriberConfig.Policy,
+		Grace:          subscriberConfig.Grace,
 	}
 	return watcher
 }
@@ -0,0 +1,214 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package notification
+
+import (
+	"github.com/apache/servicecomb-service-center/server/metric"
+	"github.com/prometheus/client_golang/prometheus"
+	"time"
+)
+
+const (
+	evictReasonSize = "size"
+	evictReasonAge  = "age"
+
+	dropReasonSlowConsumer = "slow_consumer"
+	dropReasonQueueFull    = "queue_full"
+
+	deliveryWebSocket = "websocket"
+	deliverySSE       = "sse"
+	deliveryGRPC      = "grpc"
+	deliverySink      = "sink"
+)
+
+var (
+	replayEvictedCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metric.FamilyName,
+			Subsystem: "notify",
+			Name:      "replay_evicted_total",
+			Help:      "Counter of events evicted from the replay buffer",
+		}, []string{"instance", "domain", "reason"})
+
+	replayMissedCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metric.FamilyName,
+			Subsystem: "notify",
+			Name:      "replay_missed_total",
+			Help:      "Counter of replay requests whose revision is out of the retained range",
+		}, []string{"instance", "domain"})
+
+	subscriberLagGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metric.FamilyName,
+			Subsystem: "notify",
+			Name:      "subscriber_pending_events",
+			Help:      "Gauge of events pending in the subscriber queue",
+		}, []string{"instance", "subject", "group", "id"})
+
+	slowConsumerCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metric.FamilyName,
+			Subsystem: "notify",
+			Name:      "slow_consumer_total",
+			Help:      "Counter of the slow consumer policy applied",
+		}, []string{"instance", "policy"})
+
+	peerEventCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metric.FamilyName,
+			Subsystem: "notify",
+			Name:      "peer_events_total",
+			Help:      "Counter of events accepted by the peer bus",
+		}, []string{"instance", "source"})
+
+	peerLateCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metric.FamilyName,
+			Subsystem: "notify",
+			Name:      "peer_late_total",
+			Help:      "Counter of events released by the peer bus out of revision order",
+		}, []string{"instance"})
+
+	peerGapCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metric.FamilyName,
+			Subsystem: "notify",
+			Name:      "peer_gaps_total",
+			Help:      "Counter of the gaps found in the event sequences forwarded by the peers",
+		}, []string{"instance"})
+
+	subscriberGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metric.FamilyName,
+			Subsystem: "notify",
+			Name:      "subscribers",
+			Help:      "Gauge of the active subscribers",
+		}, []string{"instance", "type"})
+
+	droppedCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metric.FamilyName,
+			Subsystem: "notify",
+			Name:      "dropped_events_total",
+			Help:      "Counter of events dropped before delivered to the subscribers",
+		}, []string{"instance", "type", "reason"})
+
+	deliveryLatency = prometheus.NewSummaryVec(
+		prometheus.SummaryOpts{
+			Namespace:  metric.FamilyName,
+			Subsystem:  "notify",
+			Name:       "delivery_latency_seconds",
+			Help:       "Latency summary from the event observed to delivered to the subscriber",
+			Objectives: prometheus.DefObjectives,
+		}, []string{"instance", "type", "protocol"})
+
+	sinkFailureCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metric.FamilyName,
+			Subsystem: "notify",
+			Name:      "sink_failures_total",
+			Help:      "Counter of events failed to publish to the event sink",
+		}, []string{"instance", "sink"})
+
+	replaySizeGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metric.FamilyName,
+			Subsystem: "notify",
+			Name:      "replay_events",
+			Help:      "Gauge of events retained in the replay buffer",
+		}, []string{"instance", "domain"})
+)
+
+func init() {
+	prometheus.MustRegister(replayEvictedCounter, replayMissedCounter, replaySizeGauge,
+		subscriberLagGauge, slowConsumerCounter, peerEventCounter, peerLateCounter,
+		peerGapCounter, subscriberGauge, droppedCounter, deliveryLatency, sinkFailureCounter)
+}
+
+func ReportReplayEvicted(domain, reason string, c int) {
+	if c == 0 {
+		return
+	}
+	instance := metric.InstanceName()
+	replayEvictedCounter.WithLabelValues(instance, domain, reason).Add(float64(c))
+}
+
+func ReportReplayMissed(domain string) {
+	instance := metric.InstanceName()
+	replayMissedCounter.WithLabelValues(instance, domain).Inc()
+}
+
+func ReportReplaySize(domain string, c int) {
+	instance := metric.InstanceName()
+	replaySizeGauge.WithLabelValues(instance, domain).Set(float64(c))
+}
+
+func ReportSubscriberLag(id, subject, group string, c int) {
+	instance := metric.InstanceName()
+	subscriberLagGauge.WithLabelValues(instance, subject, group, id).Set(float64(c))
+}
+
+func DeleteSubscriberLag(id, subject, group string) {
+	instance := metric.InstanceName()
+	subscriberLagGauge.DeleteLabelValues(instance, subject, group, id)
+}
+
+func ReportSlowConsumer(policy string) {
+	instance := metric.InstanceName()
+	slowConsumerCounter.WithLabelValues(instance, policy).Inc()
+}
+
+func ReportPeerEvent(source string) {
+	instance := metric.InstanceName()
+	peerEventCounter.WithLabelValues(instance, source).Inc()
+}
+
+func ReportPeerLate() {
+	instance := metric.InstanceName()
+	peerLateCounter.WithLabelValues(instance).Inc()
+}
+
+func ReportPeerGap() {
+	instance := metric.InstanceName()
+	peerGapCounter.WithLabelValues(instance).Inc()
+}
+
+func ReportSubscribers(t NotifyType, c float64) {
+	instance := metric.InstanceName()
+	subscriberGauge.WithLabelValues(instance, t.String()).Add(c)
+}
+
+func ReportDropped(t NotifyType, reason string) {
+	instance := metric.InstanceName()
+	droppedCounter.WithLabelValues(instance, t.String(), reason).Inc()
+}
+
+// ReportDelivery observes the latency since the event created, the jobs
+// not created from the events are skipped
+func ReportDelivery(t NotifyType, protocol string, createAt time.Time) {
+	if createAt.IsZero() {
+		return
+	}
+	instance := metric.InstanceName()
+	deliveryLatency.WithLabelValues(instance, t.String(), protocol).Observe(time.Since(createAt).Seconds())
+}
+
+func ReportSinkFailure(sink string) {
+	instance := metric.InstanceName()
+	sinkFailureCounter.WithLabelValues(instance, sink).Inc()
+}
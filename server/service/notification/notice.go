@@ -16,6 +16,8 @@
  */
 package notification
 
+import "time"
+
 type NotifyJob interface {
 	Type() NotifyType
 	Group() string
@@ -26,6 +28,9 @@ type BaseNotifyJob struct {
 	nType   NotifyType
 	subject string
 	group   string
+	// createAt is the time the event observed, it is zero if the job
+	// is not an event
+	createAt time.Time
 }
 
 func (s *BaseNotifyJob) Type() NotifyType {
@@ -39,3 +44,7 @@ func (s *BaseNotifyJob) Group() string {
 func (s *BaseNotifyJob) Subject() string {
 	return s.subject
 }
+
+func (s *BaseNotifyJob) CreateAt() time.Time {
+	return s.createAt
+}
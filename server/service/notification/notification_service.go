@@ -21,8 +21,10 @@ import (
 	"github.com/apache/servicecomb-service-center/pkg/gopool"
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/core/backend"
 	"golang.org/x/net/context"
 	"sync"
+	"sync/atomic"
 )
 
 var notifyService *NotifyService
@@ -30,7 +32,7 @@ var notifyService *NotifyService
 func init() {
 	notifyService = &NotifyService{
 		isClose:   true,
-		goroutine: gopool.New(context.Background()),
+		goroutine: gopool.New(context.Background(), gopool.Configure().Named("notification")),
 	}
 }
 
@@ -40,6 +42,9 @@ type NotifyService struct {
 	err        chan error
 	closeMux   sync.RWMutex
 	isClose    bool
+	draining   bool
+	// sinkCfg is the SinkConfig of the event sink started
+	sinkCfg atomic.Value
 }
 
 func (s *NotifyService) Err() <-chan error {
@@ -61,12 +66,17 @@ func (s *NotifyService) Start() {
 	}
 	s.closeMux.Lock()
 	s.isClose = false
+	s.draining = false
 	s.closeMux.Unlock()
 
 	s.init()
 	// 错误subscriber清理
 	s.AddSubscriber(NewNotifyServiceHealthChecker())
 
+	s.startEventSink()
+
+	GetReplayBuffer().Reset(backend.Revision())
+
 	log.Debugf("notify service is started")
 
 	s.processors.ForEach(func(item util.MapItem) (next bool) {
@@ -75,9 +85,34 @@ func (s *NotifyService) Start() {
 	})
 }
 
+func (s *NotifyService) startEventSink() {
+	cfg := LoadSinkConfig()
+	if len(cfg.Name) == 0 {
+		return
+	}
+	sink, err := NewEventSink(cfg)
+	if err != nil {
+		log.Errorf(err, "create event sink '%s' failed", cfg.Name)
+		return
+	}
+	s.AddSubscriber(NewSinkSubscriber(sink, cfg))
+	s.sinkCfg.Store(cfg)
+}
+
+// SinkRequired returns whether the events of the domain are published to
+// the event sink, the sink jobs are not scheduled otherwise
+func (s *NotifyService) SinkRequired(domain string) bool {
+	cfg, ok := s.sinkCfg.Load().(SinkConfig)
+	if !ok {
+		return false
+	}
+	_, ok = cfg.QoSOf(domain)
+	return ok
+}
+
 func (s *NotifyService) AddSubscriber(n Subscriber) error {
-	if s.Closed() {
-		return errors.New("server is shutting down")
+	if s.Closed() || (isWatcher(n) && s.Draining()) {
+		return ErrShuttingDown
 	}
 
 	itf, ok := s.processors.Get(n.Type())
@@ -88,6 +123,7 @@ func (s *NotifyService) AddSubscriber(n Subscriber) error {
 	n.OnAccept()
 
 	itf.(*Processor).AddSubscriber(n)
+	ReportSubscribers(n.Type(), 1)
 	return nil
 }
 
@@ -97,7 +133,9 @@ func (s *NotifyService) RemoveSubscriber(n Subscriber) {
 		return
 	}
 
-	itf.(*Processor).Remove(n)
+	if itf.(*Processor).Remove(n) {
+		ReportSubscribers(n.Type(), -1)
+	}
 	n.Close()
 }
 
@@ -129,6 +167,34 @@ func (s *NotifyService) Closed() (b bool) {
 	return
 }
 
+func (s *NotifyService) Draining() (b bool) {
+	s.closeMux.RLock()
+	b = s.draining
+	s.closeMux.RUnlock()
+	return
+}
+
+// Drain rejects the new watchers and disconnects the existing ones, then
+// the watchers will reconnect to the other service centers
+func (s *NotifyService) Drain() {
+	if s.Closed() || s.Draining() {
+		return
+	}
+	s.closeMux.Lock()
+	s.draining = true
+	s.closeMux.Unlock()
+
+	n := 0
+	s.walk(func(w Subscriber) bool {
+		if isWatcher(w) {
+			w.SetError(ErrShuttingDown)
+			n++
+		}
+		return true
+	})
+	log.Infof("notify service is draining, disconnect %d watcher(s)", n)
+}
+
 func (s *NotifyService) Stop() {
 	if s.Closed() {
 		return
@@ -70,7 +70,7 @@ func TestGetNotifyService(t *testing.T) {
 	if err != nil {
 		t.Fatalf("TestGetNotifyService failed")
 	}
-	j := &BaseNotifyJob{INSTANCE, "s", "g"}
+	j := &BaseNotifyJob{nType: INSTANCE, subject: "s", group: "g"}
 	err = notifyService.AddJob(j)
 	if err != nil {
 		t.Fatalf("TestGetNotifyService failed")
@@ -89,3 +89,29 @@ func TestGetNotifyService(t *testing.T) {
 		t.Fatalf("TestGetNotifyService failed")
 	}
 }
+
+func TestNotifyService_Drain(t *testing.T) {
+	s := &NotifyService{
+		isClose:   true,
+		goroutine: gopool.New(context.Background()),
+	}
+	s.Start()
+	defer s.Stop()
+
+	w := NewListWatcher("g", "s", nil)
+	if err := s.AddSubscriber(w); err != nil {
+		t.Fatalf("TestNotifyService_Drain failed, %s", err)
+	}
+
+	s.Drain()
+	if !s.Draining() || w.Err() != ErrShuttingDown {
+		t.Fatalf("TestNotifyService_Drain failed, %v", w.Err())
+	}
+	if err := s.AddSubscriber(NewListWatcher("g", "s", nil)); err != ErrShuttingDown {
+		t.Fatalf("TestNotifyService_Drain failed, %v", err)
+	}
+	// the internal subscribers are still accepted
+	if err := s.AddSubscriber(NewSubscriber(NOTIFTY, "s", "g")); err != nil {
+		t.Fatalf("TestNotifyService_Drain failed, %v", err)
+	}
+}
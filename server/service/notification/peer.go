@@ -0,0 +1,370 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package notification
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"github.com/astaxie/beego"
+	"golang.org/x/net/context"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	DEFAULT_PEER_HOLD        = 100 * time.Millisecond
+	DEFAULT_PEER_DEDUP_SIZE  = 10000
+	DEFAULT_PEER_OUTBOX_SIZE = 10000
+	DEFAULT_PEER_ORIGIN_TTL  = time.Minute
+
+	peerSourceLocal = "local"
+	peerSourcePeer  = "peer"
+
+	peerBroadcastRetries = 3
+)
+
+var peerBus *PeerBus
+
+func init() {
+	hold, err := time.ParseDuration(beego.AppConfig.DefaultString("peer_bus_hold", ""))
+	if err != nil || hold <= 0 {
+		hold = DEFAULT_PEER_HOLD
+	}
+	peerBus = NewPeerBus(beego.AppConfig.DefaultInt("peer_bus", 0) != 0, hold, DEFAULT_PEER_DEDUP_SIZE)
+}
+
+// PeerTransport forwards the events to the other replicas
+type PeerTransport interface {
+	// Broadcast returns an error if any replica failed to receive the
+	// events, the events are broadcast again
+	Broadcast(ctx context.Context, events []*pb.PeerEvent) error
+}
+
+// peerOrigin tracks the sequences of the events forwarded by one replica,
+// the events of the sequences after the one of baseRev are contiguous,
+// so the replica forwarded all the events it observed in the range of
+// baseRev and lastRev
+type peerOrigin struct {
+	baseRev int64
+	lastSeq int64
+	lastRev int64
+	// held keeps the revisions of the sequences after a gap
+	held     map[int64]int64
+	gapSince time.Time
+	updated  time.Time
+}
+
+func (o *peerOrigin) extend(seq, rev int64) {
+	for {
+		o.lastSeq = seq
+		if rev > o.lastRev {
+			o.lastRev = rev
+		}
+		next, ok := o.held[seq+1]
+		if !ok {
+			return
+		}
+		delete(o.held, seq+1)
+		seq, rev = seq+1, next
+	}
+}
+
+// restart drops the gap and starts the contiguous sequences from the
+// first one held
+func (o *peerOrigin) restart(now time.Time) {
+	var seq int64
+	for s := range o.held {
+		if seq == 0 || s < seq {
+			seq = s
+		}
+	}
+	rev := o.held[seq]
+	delete(o.held, seq)
+	o.baseRev, o.lastRev = rev, 0
+	o.extend(seq, rev)
+	o.gapSince = now
+}
+
+// PeerBus merges the instance events observed by the local etcd watch
+// and the events forwarded by the other replicas, so a watcher receives
+// the event from whichever replica observed it first. Each replica
+// forwards all the events it observes with the sequences of its own, an
+// event is delivered in revision order once the events before it are
+// complete, known by the local watch or by the contiguous sequences of
+// a replica. The replica with a gap not filled in Hold is not trusted
+// until the local watch catches up the events after the gap
+type PeerBus struct {
+	Enabled bool
+	Hold    time.Duration
+	// Deliver publishes the event to the local subscribers
+	Deliver func(evt *pb.PeerEvent)
+
+	transport PeerTransport
+	dedup     *dedupSet
+	pending   []*pb.PeerEvent
+	outbox    []*pb.PeerEvent
+	origin    string
+	seq       int64
+	origins   map[string]*peerOrigin
+	localRev  int64
+	released  int64
+	sending   bool
+	retries   int
+	lock      sync.Mutex
+	once      sync.Once
+}
+
+func (b *PeerBus) SetTransport(t PeerTransport) {
+	b.lock.Lock()
+	b.transport = t
+	b.lock.Unlock()
+}
+
+// Publish accepts the event observed by the local etcd watch
+func (b *PeerBus) Publish(evt *pb.PeerEvent) {
+	b.accept(evt, peerSourceLocal)
+}
+
+// Receive accepts the events forwarded by the other replicas
+func (b *PeerBus) Receive(events []*pb.PeerEvent) {
+	for _, evt := range events {
+		b.accept(evt, peerSourcePeer)
+	}
+}
+
+func (b *PeerBus) accept(evt *pb.PeerEvent, source string) {
+	b.once.Do(func() {
+		gopool.Go(b.loop)
+	})
+
+	now := time.Now()
+	b.lock.Lock()
+	if source == peerSourceLocal {
+		if evt.Revision > b.localRev {
+			b.localRev = evt.Revision
+		}
+		// forward all the events observed, even the ones received from
+		// the other replicas, to keep the sequences contiguous
+		b.seq++
+		evt.Origin, evt.Seq = b.origin, b.seq
+		b.outbox = append(b.outbox, evt)
+		if n := len(b.outbox) - DEFAULT_PEER_OUTBOX_SIZE; n > 0 {
+			b.outbox = b.outbox[n:]
+		}
+	} else {
+		b.track(evt, now)
+	}
+	if !b.dedup.Add(evt.Id()) {
+		b.lock.Unlock()
+		return
+	}
+	b.pending = append(b.pending, evt)
+	b.lock.Unlock()
+	ReportPeerEvent(source)
+}
+
+// track advances the contiguous sequences of the replica forwarding the
+// event, the sequences after a gap are held until it is filled
+func (b *PeerBus) track(evt *pb.PeerEvent, now time.Time) {
+	if len(evt.Origin) == 0 || evt.Origin == b.origin {
+		return
+	}
+	o, ok := b.origins[evt.Origin]
+	if !ok {
+		o = &peerOrigin{baseRev: evt.Revision, lastSeq: evt.Seq - 1, held: make(map[int64]int64)}
+		b.origins[evt.Origin] = o
+	}
+	o.updated = now
+	switch {
+	case evt.Seq <= o.lastSeq:
+		// broadcast again
+	case evt.Seq > o.lastSeq+1:
+		if len(o.held) == 0 {
+			o.gapSince = now
+			log.Warnf("peer[%s] event sequence %d is missing, received %d",
+				evt.Origin, o.lastSeq+1, evt.Seq)
+			ReportPeerGap()
+		}
+		o.held[evt.Seq] = evt.Revision
+	default:
+		o.extend(evt.Seq, evt.Revision)
+	}
+}
+
+// complete returns the revision the events before which are all received,
+// it is the revision of the local watch, extended by the replicas whose
+// contiguous sequences start before it
+func (b *PeerBus) complete() int64 {
+	rev := b.localRev
+	for extended := true; extended; {
+		extended = false
+		for _, o := range b.origins {
+			if o.baseRev <= rev && o.lastRev > rev {
+				rev, extended = o.lastRev, true
+			}
+		}
+	}
+	return rev
+}
+
+// expire restarts the sequences of the replicas after the gaps not filled
+// in Hold, and forgets the replicas not heard over DEFAULT_PEER_ORIGIN_TTL
+func (b *PeerBus) expire(now time.Time) {
+	for id, o := range b.origins {
+		if now.Sub(o.updated) >= DEFAULT_PEER_ORIGIN_TTL {
+			delete(b.origins, id)
+			continue
+		}
+		if len(o.held) > 0 && now.Sub(o.gapSince) >= b.Hold {
+			log.Warnf("peer[%s] event sequence %d is not received in %s, wait for the local watch",
+				id, o.lastSeq+1, b.Hold)
+			o.restart(now)
+		}
+	}
+}
+
+func (b *PeerBus) loop(ctx context.Context) {
+	interval := b.Hold / 2
+	if interval < 10*time.Millisecond {
+		interval = 10 * time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			b.broadcast(ctx)
+			b.release(now)
+		}
+	}
+}
+
+// broadcast forwards the local events, the events failed to forward are
+// broadcast again at most peerBroadcastRetries times, the replicas missing
+// them wait for their local watch
+func (b *PeerBus) broadcast(ctx context.Context) {
+	b.lock.Lock()
+	events, t := b.outbox, b.transport
+	if len(events) == 0 || t == nil || b.sending {
+		b.lock.Unlock()
+		return
+	}
+	b.outbox, b.sending = nil, true
+	b.lock.Unlock()
+
+	gopool.Go(func(_ context.Context) {
+		err := t.Broadcast(ctx, events)
+
+		b.lock.Lock()
+		defer b.lock.Unlock()
+		b.sending = false
+		if err == nil {
+			b.retries = 0
+			return
+		}
+		if b.retries >= peerBroadcastRetries {
+			log.Errorf(err, "forward %d peer events failed after %d retries", len(events), b.retries)
+			b.retries = 0
+			return
+		}
+		b.retries++
+		b.outbox = append(events, b.outbox...)
+		if n := len(b.outbox) - DEFAULT_PEER_OUTBOX_SIZE; n > 0 {
+			b.outbox = b.outbox[n:]
+		}
+	})
+}
+
+// release delivers the events of the complete revisions in revision order
+func (b *PeerBus) release(now time.Time) {
+	b.lock.Lock()
+	b.expire(now)
+	rev := b.complete()
+	sort.SliceStable(b.pending, func(i, j int) bool {
+		return b.pending[i].Revision < b.pending[j].Revision
+	})
+	i := sort.Search(len(b.pending), func(i int) bool {
+		return b.pending[i].Revision > rev
+	})
+	if i == 0 {
+		b.lock.Unlock()
+		return
+	}
+	events := b.pending[:i]
+	b.pending = append([]*pb.PeerEvent(nil), b.pending[i:]...)
+	released := b.released
+	if last := events[i-1].Revision; last > b.released {
+		b.released = last
+	}
+	b.lock.Unlock()
+
+	for _, evt := range events {
+		if evt.Revision < released {
+			log.Warnf("peer event[%s] revision %d is later than the released revision %d",
+				evt.Action, evt.Revision, released)
+			ReportPeerLate()
+		}
+		b.Deliver(evt)
+	}
+}
+
+func NewPeerBus(enabled bool, hold time.Duration, dedupSize int) *PeerBus {
+	return &PeerBus{
+		Enabled: enabled,
+		Hold:    hold,
+		dedup:   newDedupSet(dedupSize),
+		origin:  util.GenerateUuid(),
+		origins: make(map[string]*peerOrigin),
+	}
+}
+
+func GetPeerBus() *PeerBus {
+	return peerBus
+}
+
+// dedupSet is a bounded set, the oldest id is evicted if it is full
+type dedupSet struct {
+	ids  map[string]struct{}
+	ring []string
+	next int
+}
+
+// Add returns false if the id exists
+func (s *dedupSet) Add(id string) bool {
+	if _, ok := s.ids[id]; ok {
+		return false
+	}
+	if old := s.ring[s.next]; len(old) > 0 {
+		delete(s.ids, old)
+	}
+	s.ring[s.next] = id
+	s.next = (s.next + 1) % len(s.ring)
+	s.ids[id] = struct{}{}
+	return true
+}
+
+func newDedupSet(size int) *dedupSet {
+	return &dedupSet{
+		ids:  make(map[string]struct{}, size),
+		ring: make([]string, size),
+	}
+}
@@ -0,0 +1,162 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package notification
+
+import (
+	"errors"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"golang.org/x/net/context"
+	"testing"
+	"time"
+)
+
+type mockTransport struct {
+	events chan []*pb.PeerEvent
+	err    error
+}
+
+func (t *mockTransport) Broadcast(_ context.Context, events []*pb.PeerEvent) error {
+	t.events <- events
+	return t.err
+}
+
+func newPeerEvent(origin string, seq, rev int64) *pb.PeerEvent {
+	return &pb.PeerEvent{DomainProject: "a/b", Revision: rev, Action: string(pb.EVT_CREATE),
+		Instance: &pb.MicroServiceInstance{ServiceId: "s", InstanceId: "i"}, Origin: origin, Seq: seq}
+}
+
+func waitBroadcast(b *PeerBus) {
+	for {
+		b.lock.Lock()
+		sending := b.sending
+		b.lock.Unlock()
+		if !sending {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestPeerBus(t *testing.T) {
+	b := NewPeerBus(true, time.Hour, 100)
+	// release and broadcast manually
+	b.once.Do(func() {})
+
+	// dedup, but forward all the local events
+	b.Receive([]*pb.PeerEvent{newPeerEvent("x", 1, 3), newPeerEvent("x", 1, 3)})
+	b.Publish(newPeerEvent("", 0, 2))
+	b.Publish(newPeerEvent("", 0, 3))
+	if len(b.pending) != 2 || len(b.outbox) != 2 ||
+		b.outbox[0].Origin != b.origin || b.outbox[0].Seq != 1 || b.outbox[1].Seq != 2 {
+		t.Fatalf("TestPeerBus failed, %v, %v", b.pending, b.outbox)
+	}
+
+	// broadcast the local events
+	tr := &mockTransport{events: make(chan []*pb.PeerEvent, 1)}
+	b.SetTransport(tr)
+	b.broadcast(context.Background())
+	if events := <-tr.events; len(events) != 2 || events[0].Revision != 2 {
+		t.Fatalf("TestPeerBus failed, %v", events)
+	}
+	waitBroadcast(b)
+	if len(b.outbox) != 0 {
+		t.Fatalf("TestPeerBus failed, %v", b.outbox)
+	}
+
+	// broadcast again if failed
+	b.Publish(newPeerEvent("", 0, 4))
+	tr.err = errors.New("error")
+	for i := 0; i <= peerBroadcastRetries; i++ {
+		b.broadcast(context.Background())
+		if events := <-tr.events; len(events) != 1 || events[0].Seq != 3 {
+			t.Fatalf("TestPeerBus failed, %v", events)
+		}
+		waitBroadcast(b)
+	}
+	if len(b.outbox) != 0 {
+		t.Fatalf("TestPeerBus failed, %v", b.outbox)
+	}
+
+	// the bounded dedup set evicts the oldest id
+	b = NewPeerBus(true, time.Hour, 1)
+	b.once.Do(func() {})
+	b.Publish(newPeerEvent("", 0, 1))
+	b.Publish(newPeerEvent("", 0, 2))
+	if !b.dedup.Add(newPeerEvent("", 0, 1).Id()) {
+		t.Fatalf("TestPeerBus failed")
+	}
+}
+
+func TestPeerBus_Release(t *testing.T) {
+	var delivered []int64
+	b := NewPeerBus(true, time.Second, 100)
+	b.once.Do(func() {})
+	b.Deliver = func(evt *pb.PeerEvent) {
+		delivered = append(delivered, evt.Revision)
+	}
+	check := func(revs ...int64) {
+		if len(delivered) != len(revs) {
+			t.Fatalf("TestPeerBus_Release failed, %v", delivered)
+		}
+		for i, rev := range revs {
+			if delivered[i] != rev {
+				t.Fatalf("TestPeerBus_Release failed, %v", delivered)
+			}
+		}
+	}
+
+	// the revisions of the local watch are complete
+	b.Publish(newPeerEvent("", 0, 1))
+	b.Receive([]*pb.PeerEvent{newPeerEvent("x", 1, 1)})
+	b.release(time.Now())
+	check(1)
+
+	// the peer events out of order are delivered in revision order once
+	// the sequences are contiguous
+	b.Receive([]*pb.PeerEvent{newPeerEvent("x", 3, 4)})
+	b.release(time.Now())
+	check(1)
+	b.Receive([]*pb.PeerEvent{newPeerEvent("x", 2, 3)})
+	b.release(time.Now())
+	check(1, 3, 4)
+
+	// hold the events after a missing one until it is received
+	b.Receive([]*pb.PeerEvent{newPeerEvent("x", 5, 6)})
+	b.release(time.Now())
+	check(1, 3, 4)
+	b.Receive([]*pb.PeerEvent{newPeerEvent("x", 4, 5)})
+	b.release(time.Now())
+	check(1, 3, 4, 5, 6)
+
+	// wait for the local watch if the missing one is not received in time
+	b.Receive([]*pb.PeerEvent{newPeerEvent("x", 8, 9)})
+	b.release(time.Now().Add(2 * time.Second))
+	check(1, 3, 4, 5, 6)
+	for rev := int64(3); rev <= 8; rev++ {
+		b.Publish(newPeerEvent("", 0, rev))
+	}
+	b.release(time.Now())
+	check(1, 3, 4, 5, 6, 7, 8)
+	b.Publish(newPeerEvent("", 0, 9))
+	b.release(time.Now())
+	check(1, 3, 4, 5, 6, 7, 8, 9)
+
+	// trust the replica again after the local watch catches up
+	b.Receive([]*pb.PeerEvent{newPeerEvent("x", 9, 10)})
+	b.release(time.Now())
+	check(1, 3, 4, 5, 6, 7, 8, 9, 10)
+}
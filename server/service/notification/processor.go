@@ -53,19 +53,20 @@ func (p *Processor) AddSubscriber(n Subscriber) {
 	item.(*Subject).GetOrNewGroup(n.Group()).AddSubscriber(n)
 }
 
-func (p *Processor) Remove(n Subscriber) {
+// Remove returns false if the subscriber does not exist
+func (p *Processor) Remove(n Subscriber) bool {
 	itf, ok := p.subjects.Get(n.Subject())
 	if !ok {
-		return
+		return false
 	}
 
 	s := itf.(*Subject)
 	g := s.Groups(n.Group())
 	if g == nil {
-		return
+		return false
 	}
 
-	g.Remove(n.Id())
+	removed := g.Remove(n.Id())
 
 	if g.Size() == 0 {
 		s.Remove(g.Name())
@@ -73,6 +74,7 @@ func (p *Processor) Remove(n Subscriber) {
 	if s.Size() == 0 {
 		p.subjects.Remove(s.Name())
 	}
+	return removed
 }
 
 func (p *Processor) Clear() {
@@ -18,19 +18,38 @@ package notification
 
 import (
 	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	"github.com/astaxie/beego"
 	"golang.org/x/net/context"
 	"sync"
 	"time"
 )
 
+const (
+	DEFAULT_FLUSH_INTERVAL = 500 * time.Millisecond
+	DEFAULT_BATCH_SIZE     = 100
+)
+
 var publisher *Publisher
 
 func init() {
-	publisher = NewPublisher()
+	interval, err := time.ParseDuration(beego.AppConfig.DefaultString("watch_flush_interval", ""))
+	if err != nil || interval <= 0 {
+		interval = DEFAULT_FLUSH_INTERVAL
+	}
+	batchSize := beego.AppConfig.DefaultInt("watch_batch_size", DEFAULT_BATCH_SIZE)
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+	publisher = NewPublisher(interval, batchSize)
 	publisher.Run()
 }
 
 type Publisher struct {
+	// Interval is the period to flush the events to websockets
+	Interval time.Duration
+	// BatchSize is the max number of events flushed to a websocket per interval
+	BatchSize int
+
 	wss       []*WebSocket
 	lock      sync.Mutex
 	goroutine *gopool.Pool
@@ -52,7 +71,7 @@ func (wh *Publisher) dispatch(ws *WebSocket, payload interface{}) {
 
 func (wh *Publisher) loop(ctx context.Context) {
 	defer wh.Stop()
-	ticker := time.NewTicker(500 * time.Millisecond)
+	ticker := time.NewTicker(wh.Interval)
 	for {
 		select {
 		case <-ctx.Done():
@@ -96,8 +115,10 @@ func (wh *Publisher) Accept(ws *WebSocket) {
 	wh.lock.Unlock()
 }
 
-func NewPublisher() *Publisher {
+func NewPublisher(interval time.Duration, batchSize int) *Publisher {
 	return &Publisher{
+		Interval:  interval,
+		BatchSize: batchSize,
 		goroutine: gopool.New(context.Background()),
 	}
 }
@@ -0,0 +1,210 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package notification
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"github.com/astaxie/beego"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DEFAULT_REPLAY_SIZE = 1000
+	DEFAULT_REPLAY_AGE  = 5 * time.Minute
+)
+
+var replayBuffer *ReplayBuffer
+
+func init() {
+	size := beego.AppConfig.DefaultInt("event_replay_size", DEFAULT_REPLAY_SIZE)
+	age, err := time.ParseDuration(beego.AppConfig.DefaultString("event_replay_age", ""))
+	if err != nil || age <= 0 {
+		age = DEFAULT_REPLAY_AGE
+	}
+	replayBuffer = NewReplayBuffer(size, age)
+}
+
+type replayEvent struct {
+	*pb.WatchEvent
+	created     time.Time
+	subscribers map[string]struct{}
+}
+
+// replayRing is a bounded ring of the events of one domain project,
+// the events are appended in revision order
+type replayRing struct {
+	domain string
+	events []*replayEvent
+	head   int
+	count  int
+	// evictedRev is the max revision of the evicted events,
+	// replay since a revision less than it will miss events
+	evictedRev int64
+	lock       sync.RWMutex
+}
+
+func (r *replayRing) at(i int) *replayEvent {
+	return r.events[(r.head+i)%len(r.events)]
+}
+
+func (r *replayRing) pop() *replayEvent {
+	evt := r.events[r.head]
+	r.events[r.head] = nil
+	r.head = (r.head + 1) % len(r.events)
+	r.count--
+	if evt.Revision > r.evictedRev {
+		r.evictedRev = evt.Revision
+	}
+	return evt
+}
+
+func (r *replayRing) expire(age time.Duration) {
+	deadline := time.Now().Add(-age)
+	n := 0
+	for r.count > 0 && r.at(0).created.Before(deadline) {
+		r.pop()
+		n++
+	}
+	ReportReplayEvicted(r.domain, evictReasonAge, n)
+}
+
+func (r *replayRing) push(evt *replayEvent, age time.Duration) {
+	r.lock.Lock()
+	r.expire(age)
+	if r.count == len(r.events) {
+		r.pop()
+		ReportReplayEvicted(r.domain, evictReasonSize, 1)
+	}
+	r.events[(r.head+r.count)%len(r.events)] = evt
+	r.count++
+	c := r.count
+	r.lock.Unlock()
+	ReportReplaySize(r.domain, c)
+}
+
+// ReplayBuffer retains the recent instance events of each domain project,
+// it backs the watch resuming and the events polling API
+type ReplayBuffer struct {
+	Size int
+	Age  time.Duration
+
+	// floor is the revision when the buffer starts retaining
+	floor int64
+	rings *util.ConcurrentMap
+}
+
+// Reset clears all the retained events, the events whose revision
+// is less than or equal to rev can not be replayed any more
+func (b *ReplayBuffer) Reset(rev int64) {
+	b.rings.Clear()
+	b.floor = rev
+}
+
+func (b *ReplayBuffer) ring(domainProject string) *replayRing {
+	item, _ := b.rings.Fetch(domainProject, func() (interface{}, error) {
+		return &replayRing{
+			domain:     domainProject[:strings.Index(domainProject, "/")],
+			events:     make([]*replayEvent, b.Size),
+			evictedRev: b.floor,
+		}, nil
+	})
+	return item.(*replayRing)
+}
+
+// Append retains the event, subscribers are the consumers who
+// will be notified of the event
+func (b *ReplayBuffer) Append(domainProject string, rev int64,
+	response *pb.WatchInstanceResponse, subscribers []string) {
+	if b.Size <= 0 {
+		return
+	}
+	now := time.Now()
+	evt := &replayEvent{
+		WatchEvent: &pb.WatchEvent{
+			Revision:  rev,
+			Timestamp: strconv.FormatInt(now.Unix(), 10),
+			Action:    response.Action,
+			Key:       response.Key,
+			Instance:  response.Instance,
+		},
+		created:     now,
+		subscribers: util.ListToMap(subscribers),
+	}
+	b.ring(domainProject).push(evt, b.Age)
+}
+
+// Since returns the events whose revision is greater than rev, if subscriber
+// is not empty, only returns the events will be notified to it. The ok is
+// false if some events after rev are evicted
+func (b *ReplayBuffer) Since(domainProject string, rev int64, subscriber string) (events []*pb.WatchEvent, ok bool) {
+	item, ok := b.rings.Get(domainProject)
+	if !ok {
+		return nil, rev >= b.floor
+	}
+	r := item.(*replayRing)
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.expire(b.Age)
+	if rev < r.evictedRev {
+		ReportReplayMissed(r.domain)
+		return nil, false
+	}
+	for i := 0; i < r.count; i++ {
+		evt := r.at(i)
+		if evt.Revision <= rev {
+			continue
+		}
+		if len(subscriber) > 0 {
+			if _, ok := evt.subscribers[subscriber]; !ok {
+				continue
+			}
+		}
+		events = append(events, evt.WatchEvent)
+	}
+	return events, true
+}
+
+// Revision returns the latest revision of the retained events
+func (b *ReplayBuffer) Revision(domainProject string) int64 {
+	item, ok := b.rings.Get(domainProject)
+	if !ok {
+		return b.floor
+	}
+	r := item.(*replayRing)
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	if r.count == 0 {
+		return r.evictedRev
+	}
+	return r.at(r.count - 1).Revision
+}
+
+func NewReplayBuffer(size int, age time.Duration) *ReplayBuffer {
+	return &ReplayBuffer{
+		Size:  size,
+		Age:   age,
+		rings: util.NewConcurrentMap(0),
+	}
+}
+
+func GetReplayBuffer() *ReplayBuffer {
+	return replayBuffer
+}
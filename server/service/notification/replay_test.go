@@ -0,0 +1,72 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package notification
+
+import (
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"testing"
+	"time"
+)
+
+func TestReplayBuffer_Since(t *testing.T) {
+	b := NewReplayBuffer(2, time.Minute)
+	b.Reset(10)
+	if _, ok := b.Since("d/p", 9, ""); ok {
+		t.Fatalf("TestReplayBuffer_Since failed")
+	}
+	if evts, ok := b.Since("d/p", 10, ""); !ok || len(evts) != 0 {
+		t.Fatalf("TestReplayBuffer_Since failed")
+	}
+	if b.Revision("d/p") != 10 {
+		t.Fatalf("TestReplayBuffer_Since failed")
+	}
+
+	resp := &pb.WatchInstanceResponse{Action: string(pb.EVT_CREATE)}
+	b.Append("d/p", 11, resp, []string{"a"})
+	b.Append("d/p", 12, resp, []string{"b"})
+	evts, ok := b.Since("d/p", 10, "")
+	if !ok || len(evts) != 2 || evts[0].Revision != 11 || evts[1].Revision != 12 {
+		t.Fatalf("TestReplayBuffer_Since failed")
+	}
+	evts, ok = b.Since("d/p", 10, "b")
+	if !ok || len(evts) != 1 || evts[0].Revision != 12 {
+		t.Fatalf("TestReplayBuffer_Since failed")
+	}
+	if b.Revision("d/p") != 12 {
+		t.Fatalf("TestReplayBuffer_Since failed")
+	}
+
+	// evicted by size
+	b.Append("d/p", 13, resp, nil)
+	if _, ok := b.Since("d/p", 10, ""); ok {
+		t.Fatalf("TestReplayBuffer_Since failed")
+	}
+	evts, ok = b.Since("d/p", 11, "")
+	if !ok || len(evts) != 2 || evts[0].Revision != 12 {
+		t.Fatalf("TestReplayBuffer_Since failed")
+	}
+
+	// evicted by age
+	b.Age = time.Nanosecond
+	<-time.After(time.Millisecond)
+	if _, ok := b.Since("d/p", 12, ""); ok {
+		t.Fatalf("TestReplayBuffer_Since failed")
+	}
+	if evts, ok = b.Since("d/p", 13, ""); !ok || len(evts) != 0 {
+		t.Fatalf("TestReplayBuffer_Since failed")
+	}
+}
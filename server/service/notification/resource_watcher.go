@@ -0,0 +1,218 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package notification
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"github.com/gorilla/websocket"
+	"golang.org/x/net/context"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const NOTIFY_RESOURCE_SUBJECT = "__ResourceWatch__"
+
+// ResourceJob is the change event of the non-instance resources,
+// it is broadcast to the watchers of the same domain project
+type ResourceJob struct {
+	*BaseNotifyJob
+	Response *pb.WatchResourceResponse
+}
+
+func NewResourceJob(domainProject string, response *pb.WatchResourceResponse) *ResourceJob {
+	return &ResourceJob{
+		BaseNotifyJob: &BaseNotifyJob{
+			group:    domainProject,
+			subject:  NOTIFY_RESOURCE_SUBJECT,
+			nType:    RESOURCE,
+			createAt: time.Now(),
+		},
+		Response: response,
+	}
+}
+
+// ResourceWatcher subscribes the resource changes of the specified providers
+type ResourceWatcher struct {
+	*BaseSubscriber
+	Job       chan *ResourceJob
+	Providers map[string]struct{}
+	// Resources is the resources to watch, empty means all
+	Resources map[string]struct{}
+	Policy    SlowConsumerPolicy
+	Grace     time.Duration
+
+	dropped int64
+}
+
+func (w *ResourceWatcher) SetError(err error) {
+	if w.Err() != nil {
+		// already removed
+		return
+	}
+	w.BaseSubscriber.SetError(err)
+	w.Service().AddJob(NewNotifyServiceHealthCheckJob(w))
+}
+
+func (w *ResourceWatcher) OnMessage(job NotifyJob) {
+	if w.Err() != nil {
+		return
+	}
+	rJob, ok := job.(*ResourceJob)
+	if !ok {
+		return
+	}
+	if _, ok := w.Providers[rJob.Response.ServiceId]; !ok {
+		return
+	}
+	if _, ok := w.Resources[rJob.Response.Resource]; !ok && len(w.Resources) > 0 {
+		return
+	}
+
+	defer log.Recover()
+	select {
+	case w.Job <- rJob:
+	default:
+		timer := time.NewTimer(w.Grace)
+		select {
+		case w.Job <- rJob:
+			timer.Stop()
+		case <-timer.C:
+			ReportSlowConsumer(string(w.Policy))
+			if w.Policy == SlowConsumerDisconnect {
+				log.Errorf(nil, "the %s watcher %s event queue is full[over %s], disconnect it",
+					w.Type(), w.Group(), w.Grace)
+				w.SetError(ErrSlowConsumer)
+				return
+			}
+			atomic.AddInt64(&w.dropped, 1)
+			ReportDropped(w.Type(), dropReasonSlowConsumer)
+			log.Errorf(nil, "the %s watcher %s event queue is full[over %s], drop the event %v",
+				w.Type(), w.Group(), w.Grace, rJob.Response)
+		}
+	}
+}
+
+func (w *ResourceWatcher) Lag() *Laggard {
+	return &Laggard{
+		Id:       w.Id(),
+		Type:     w.Type().String(),
+		Subject:  w.Subject(),
+		Group:    w.Group(),
+		Policy:   string(w.Policy),
+		Pending:  len(w.Job),
+		Capacity: cap(w.Job),
+		Dropped:  atomic.LoadInt64(&w.dropped),
+	}
+}
+
+func (w *ResourceWatcher) Close() {
+	close(w.Job)
+}
+
+func NewResourceWatcher(domainProject string, providerIds, resources []string) *ResourceWatcher {
+	w := &ResourceWatcher{
+		BaseSubscriber: NewSubscriber(RESOURCE, NOTIFY_RESOURCE_SUBJECT, domainProject),
+		Job:            make(chan *ResourceJob, subscriberConfig.QueueSize),
+		Providers:      make(map[string]struct{}, len(providerIds)),
+		Resources:      make(map[string]struct{}, len(resources)),
+		Policy:         subscriberConfig.Policy,
+		Grace:          subscriberConfig.Grace,
+	}
+	for _, id := range providerIds {
+		w.Providers[id] = struct{}{}
+	}
+	for _, r := range resources {
+		w.Resources[r] = struct{}{}
+	}
+	w.SetFilter("providers", strings.Join(providerIds, ","))
+	w.SetFilter("resources", strings.Join(resources, ","))
+	return w
+}
+
+// DoWebSocketResourceWatch pushes the resource changes to the websocket
+// in json, it blocks until the connection closed
+func DoWebSocketResourceWatch(ctx context.Context, watcher *ResourceWatcher, conn *websocket.Conn) {
+	remoteAddr := conn.RemoteAddr().String()
+	watcher.SetRemote("websocket", remoteAddr)
+	if err := GetNotifyService().AddSubscriber(watcher); err != nil {
+		EstablishWebSocketError(conn, fmt.Errorf("notify service error, %s", err.Error()))
+		return
+	}
+	log.Debugf("start watching resources, watcher[%s], group: %s", remoteAddr, watcher.Group())
+
+	keepalive := RESTKeepalive()
+	active := func(string) error {
+		watcher.Touch()
+		return conn.SetReadDeadline(keepalive.Deadline())
+	}
+	active("")
+	conn.SetPongHandler(active)
+	go func() {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				// client close, conn broken or idle timeout
+				watcher.SetError(err)
+				return
+			}
+			active("")
+		}
+	}()
+
+	ticker := time.NewTicker(keepalive.PingInterval)
+	defer ticker.Stop()
+	for {
+		var err error
+		select {
+		case <-ctx.Done():
+			watcher.SetError(ctx.Err())
+			return
+		case <-ticker.C:
+			err = conn.WriteControl(websocket.PingMessage, []byte{}, time.Now().Add(DEFAULT_SEND_TIMEOUT))
+		case job := <-watcher.Job:
+			if job == nil {
+				err = watcher.Err()
+				if err == nil {
+					err = errors.New("server shutdown")
+				}
+				conn.WriteMessage(websocket.TextMessage,
+					util.StringToBytesWithNoCopy(fmt.Sprintf("watcher catch an err: %s", err.Error())))
+				return
+			}
+			log.Infof("event[%s] %s/%s is coming in, watcher[%s], group: %s", job.Response.Action,
+				job.Response.Resource, job.Response.ServiceId, remoteAddr, watcher.Group())
+			var data []byte
+			data, err = json.Marshal(job.Response)
+			if err == nil {
+				err = conn.WriteMessage(websocket.TextMessage, data)
+			}
+			if err == nil {
+				ReportDelivery(watcher.Type(), deliveryWebSocket, job.CreateAt())
+			}
+		}
+		if err != nil {
+			log.Errorf(err, "watcher[%s] catch an err, group: %s", remoteAddr, watcher.Group())
+			watcher.SetError(err)
+			return
+		}
+	}
+}
@@ -0,0 +1,57 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package notification
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"golang.org/x/net/context"
+	"testing"
+	"time"
+)
+
+func TestResourceWatcher_OnMessage(t *testing.T) {
+	s := &NotifyService{
+		isClose:   true,
+		goroutine: gopool.New(context.Background()),
+	}
+	s.Start()
+	defer s.Stop()
+
+	w := NewResourceWatcher("d/p", []string{"p1"}, []string{pb.WATCH_RESOURCE_SCHEMA})
+	if err := s.AddSubscriber(w); err != nil {
+		t.Fatalf("TestResourceWatcher_OnMessage failed, %s", err)
+	}
+
+	s.AddJob(NewResourceJob("d/p", &pb.WatchResourceResponse{
+		Resource: pb.WATCH_RESOURCE_SCHEMA, ServiceId: "p2", Revision: 1}))
+	s.AddJob(NewResourceJob("d/p", &pb.WatchResourceResponse{
+		Resource: pb.WATCH_RESOURCE_TAG, ServiceId: "p1", Revision: 2}))
+	s.AddJob(NewResourceJob("x/p", &pb.WatchResourceResponse{
+		Resource: pb.WATCH_RESOURCE_SCHEMA, ServiceId: "p1", Revision: 3}))
+	s.AddJob(NewResourceJob("d/p", &pb.WatchResourceResponse{
+		Resource: pb.WATCH_RESOURCE_SCHEMA, ServiceId: "p1", Revision: 4}))
+
+	select {
+	case job := <-w.Job:
+		if job.Response.Revision != 4 {
+			t.Fatalf("TestResourceWatcher_OnMessage failed, %v", job.Response)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("TestResourceWatcher_OnMessage failed")
+	}
+}
@@ -0,0 +1,269 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package notification
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"github.com/astaxie/beego"
+	"golang.org/x/net/context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	NOTIFY_SINK_SUBJECT = "__EventSink__"
+
+	domainAll = "*"
+)
+
+var (
+	sinkFuncs = make(map[string]NewSinkFunc)
+	sinkLock  sync.RWMutex
+)
+
+// EventSink publishes the instance events to the external message brokers
+type EventSink interface {
+	Name() string
+	// Publish is called in the sink's own goroutine, so it can be blocked
+	Publish(evt *SinkEvent, qos int) error
+	Close()
+}
+
+type NewSinkFunc func(cfg SinkConfig) (EventSink, error)
+
+// RegisterSink should be called in init() of the sink implement
+func RegisterSink(name string, f NewSinkFunc) {
+	sinkLock.Lock()
+	sinkFuncs[name] = f
+	sinkLock.Unlock()
+	log.Infof("register event sink '%s'", name)
+}
+
+type SinkConfig struct {
+	Name     string
+	Addr     string
+	User     string
+	Password string
+	Topic    string
+	// Format is the encoding of events, raw or cloudevents
+	Format string
+	// QoS is the default QoS level
+	QoS int
+	// Domains is the QoS level of each domain
+	Domains map[string]int
+}
+
+// QoSOf returns the QoS level of domain and false if the domain is not
+// required to publish to sink
+func (cfg *SinkConfig) QoSOf(domain string) (int, bool) {
+	if qos, ok := cfg.Domains[domain]; ok {
+		return qos, true
+	}
+	qos, ok := cfg.Domains[domainAll]
+	return qos, ok
+}
+
+// the format of domains is 'domain1:qos,domain2,...', empty means all domains
+func parseSinkDomains(domains string, defaultQoS int) map[string]int {
+	m := make(map[string]int)
+	for _, d := range strings.Split(domains, ",") {
+		d = strings.TrimSpace(d)
+		if len(d) == 0 {
+			continue
+		}
+		qos := defaultQoS
+		if i := strings.LastIndex(d, ":"); i > 0 {
+			if v, err := strconv.Atoi(d[i+1:]); err == nil {
+				qos = v
+			}
+			d = d[:i]
+		}
+		m[d] = qos
+	}
+	if len(m) == 0 {
+		m[domainAll] = defaultQoS
+	}
+	return m
+}
+
+func LoadSinkConfig() SinkConfig {
+	qos := beego.AppConfig.DefaultInt("event_sink_qos", 0)
+	return SinkConfig{
+		Name:     beego.AppConfig.DefaultString("event_sink", ""),
+		Addr:     beego.AppConfig.DefaultString("event_sink_addr", ""),
+		User:     beego.AppConfig.DefaultString("event_sink_user", ""),
+		Password: beego.AppConfig.DefaultString("event_sink_password", ""),
+		Topic:    beego.AppConfig.DefaultString("event_sink_topic", "servicecenter"),
+		Format:   beego.AppConfig.DefaultString("event_sink_format", EVENT_FORMAT_RAW),
+		QoS:      qos,
+		Domains:  parseSinkDomains(beego.AppConfig.DefaultString("event_sink_domains", ""), qos),
+	}
+}
+
+func NewEventSink(cfg SinkConfig) (EventSink, error) {
+	sinkLock.RLock()
+	f, ok := sinkFuncs[cfg.Name]
+	sinkLock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown event sink '%s'", cfg.Name)
+	}
+	return f(cfg)
+}
+
+// SinkEvent is the instance event published to sinks
+type SinkEvent struct {
+	DomainProject string                   `json:"-"`
+	Revision      int64                    `json:"revision"`
+	Action        string                   `json:"action"`
+	Key           *pb.MicroServiceKey      `json:"key,omitempty"`
+	Instance      *pb.MicroServiceInstance `json:"instance,omitempty"`
+}
+
+func (evt *SinkEvent) Domain() string {
+	return evt.DomainProject[:strings.Index(evt.DomainProject, "/")]
+}
+
+func (evt *SinkEvent) Project() string {
+	return evt.DomainProject[strings.Index(evt.DomainProject, "/")+1:]
+}
+
+func (evt *SinkEvent) Bytes() ([]byte, error) {
+	return json.Marshal(evt)
+}
+
+// Encode returns the bytes of the event in format
+func (evt *SinkEvent) Encode(format string) ([]byte, error) {
+	if format != EVENT_FORMAT_CLOUDEVENTS {
+		return evt.Bytes()
+	}
+	return json.Marshal(pb.NewInstanceCloudEvent(evt.DomainProject, evt.Revision,
+		&pb.WatchInstanceResponse{
+			Action:   evt.Action,
+			Key:      evt.Key,
+			Instance: evt.Instance,
+		}))
+}
+
+type SinkJob struct {
+	*BaseNotifyJob
+	Event *SinkEvent
+}
+
+func NewSinkJob(domainProject string, rev int64, response *pb.WatchInstanceResponse) *SinkJob {
+	return &SinkJob{
+		BaseNotifyJob: &BaseNotifyJob{
+			subject:  NOTIFY_SINK_SUBJECT,
+			nType:    SINK,
+			createAt: time.Now(),
+		},
+		Event: &SinkEvent{
+			DomainProject: domainProject,
+			Revision:      rev,
+			Action:        response.Action,
+			Key:           response.Key,
+			Instance:      response.Instance,
+		},
+	}
+}
+
+// SinkSubscriber subscribes all the sink jobs and publishes the
+// events of the required domains to the EventSink asynchronously, the
+// events of QoS 1 are retried until the sink acknowledges them, and the
+// later events wait in the bounded queue meanwhile
+type SinkSubscriber struct {
+	*BaseSubscriber
+	Cfg SinkConfig
+	// Backoff is the delay between the retries of the QoS 1 events
+	Backoff util.Backoff
+	sink    EventSink
+	events  chan *SinkJob
+}
+
+func (s *SinkSubscriber) OnAccept() {
+	log.Infof("accepted by notify service, event sink '%s'", s.sink.Name())
+	s.Service().goroutine.Do(s.loop)
+}
+
+func (s *SinkSubscriber) OnMessage(job NotifyJob) {
+	sJob, ok := job.(*SinkJob)
+	if !ok {
+		return
+	}
+	if _, ok := s.Cfg.QoSOf(sJob.Event.Domain()); !ok {
+		return
+	}
+	select {
+	case s.events <- sJob:
+	default:
+		ReportDropped(s.Type(), dropReasonQueueFull)
+		log.Errorf(nil, "event sink '%s' queue is full, drop the event[%s] revision %d",
+			s.sink.Name(), sJob.Event.Action, sJob.Event.Revision)
+	}
+}
+
+func (s *SinkSubscriber) loop(ctx context.Context) {
+	defer s.sink.Close()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job := <-s.events:
+			if s.publish(ctx, job.Event) {
+				ReportDelivery(s.Type(), deliverySink, job.CreateAt())
+			}
+		}
+	}
+}
+
+// publish returns true if the event is acknowledged by the sink, the QoS 0
+// events are published once, the QoS 1 ones until acknowledged or closed
+func (s *SinkSubscriber) publish(ctx context.Context, evt *SinkEvent) bool {
+	qos, _ := s.Cfg.QoSOf(evt.Domain())
+	for retries := 0; ; retries++ {
+		err := s.sink.Publish(evt, qos)
+		if err == nil {
+			return true
+		}
+		ReportSinkFailure(s.sink.Name())
+		log.Errorf(err, "event sink '%s' publish event[%s] revision %d failed, QoS %d, retries %d",
+			s.sink.Name(), evt.Action, evt.Revision, qos, retries)
+		if qos < 1 {
+			return false
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(s.Backoff.Delay(retries)):
+		}
+	}
+}
+
+func NewSinkSubscriber(sink EventSink, cfg SinkConfig) *SinkSubscriber {
+	return &SinkSubscriber{
+		BaseSubscriber: NewSubscriber(SINK, NOTIFY_SINK_SUBJECT, sink.Name()),
+		Cfg:            cfg,
+		Backoff:        util.GetBackoff(),
+		sink:           sink,
+		events:         make(chan *SinkJob, DEFAULT_MAX_QUEUE),
+	}
+}
@@ -0,0 +1,139 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package notification
+
+import (
+	"errors"
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"golang.org/x/net/context"
+	"testing"
+	"time"
+)
+
+type mockSink struct {
+	events chan *SinkEvent
+	// fails is the count of the publishes failing
+	fails int
+}
+
+func (s *mockSink) Name() string { return "mock" }
+func (s *mockSink) Publish(evt *SinkEvent, qos int) error {
+	if s.fails > 0 {
+		s.fails--
+		return errors.New("unavailable")
+	}
+	s.events <- evt
+	return nil
+}
+func (s *mockSink) Close() {}
+
+func TestParseSinkDomains(t *testing.T) {
+	cfg := SinkConfig{Domains: parseSinkDomains("", 1)}
+	if qos, ok := cfg.QoSOf("a"); !ok || qos != 1 {
+		t.Fatalf("TestParseSinkDomains failed")
+	}
+	cfg = SinkConfig{Domains: parseSinkDomains("a:0, b ,c:x", 1)}
+	if qos, ok := cfg.QoSOf("a"); !ok || qos != 0 {
+		t.Fatalf("TestParseSinkDomains failed")
+	}
+	if qos, ok := cfg.QoSOf("b"); !ok || qos != 1 {
+		t.Fatalf("TestParseSinkDomains failed")
+	}
+	if qos, ok := cfg.QoSOf("c"); !ok || qos != 1 {
+		t.Fatalf("TestParseSinkDomains failed")
+	}
+	if _, ok := cfg.QoSOf("d"); ok {
+		t.Fatalf("TestParseSinkDomains failed")
+	}
+}
+
+func TestSinkSubscriber_OnMessage(t *testing.T) {
+	RegisterSink("mock", func(cfg SinkConfig) (EventSink, error) {
+		return &mockSink{events: make(chan *SinkEvent, 1)}, nil
+	})
+	cfg := SinkConfig{Name: "mock", Domains: parseSinkDomains("a", 0)}
+	sink, err := NewEventSink(cfg)
+	if err != nil {
+		t.Fatalf("TestSinkSubscriber_OnMessage failed, %s", err)
+	}
+	if _, err := NewEventSink(SinkConfig{Name: "unknown"}); err == nil {
+		t.Fatalf("TestSinkSubscriber_OnMessage failed")
+	}
+
+	s := &NotifyService{
+		isClose:   true,
+		goroutine: gopool.New(context.Background()),
+	}
+	s.Start()
+	defer s.Stop()
+	if err := s.AddSubscriber(NewSinkSubscriber(sink, cfg)); err != nil {
+		t.Fatalf("TestSinkSubscriber_OnMessage failed, %s", err)
+	}
+
+	events := sink.(*mockSink).events
+	s.AddJob(NewSinkJob("b/p", 1, &pb.WatchInstanceResponse{Action: "CREATE"}))
+	s.AddJob(NewSinkJob("a/p", 2, &pb.WatchInstanceResponse{Action: "CREATE"}))
+	select {
+	case evt := <-events:
+		if evt.Revision != 2 || evt.Domain() != "a" || evt.Project() != "p" {
+			t.Fatalf("TestSinkSubscriber_OnMessage failed, %v", evt)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("TestSinkSubscriber_OnMessage failed")
+	}
+}
+
+func TestSinkSubscriber_Publish(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sink := &mockSink{events: make(chan *SinkEvent, 1), fails: 2}
+	cfg := SinkConfig{Name: "mock", Domains: parseSinkDomains("a:0,b:1", 0)}
+	s := NewSinkSubscriber(sink, cfg)
+	s.Backoff = &util.PowerBackoff{InitDelay: time.Millisecond, MaxDelay: time.Millisecond, Factor: 1}
+
+	evt := NewSinkJob("a/p", 1, &pb.WatchInstanceResponse{Action: "CREATE"}).Event
+	if s.publish(ctx, evt) || sink.fails != 1 {
+		t.Fatalf("TestSinkSubscriber_Publish failed, QoS 0 event retried")
+	}
+	sink.fails = 2
+	evt = NewSinkJob("b/p", 2, &pb.WatchInstanceResponse{Action: "CREATE"}).Event
+	if !s.publish(ctx, evt) || sink.fails != 0 {
+		t.Fatalf("TestSinkSubscriber_Publish failed, QoS 1 event not retried")
+	}
+	if e := <-sink.events; e.Revision != 2 {
+		t.Fatalf("TestSinkSubscriber_Publish failed, %v", e)
+	}
+
+	sink.fails = 1
+	cancel()
+	if s.publish(ctx, evt) {
+		t.Fatalf("TestSinkSubscriber_Publish failed, retried after closed")
+	}
+}
+
+func TestNotifyService_SinkRequired(t *testing.T) {
+	s := &NotifyService{}
+	if s.SinkRequired("a") {
+		t.Fatalf("TestNotifyService_SinkRequired failed")
+	}
+	s.sinkCfg.Store(SinkConfig{Name: "mock", Domains: parseSinkDomains("a", 0)})
+	if !s.SinkRequired("a") || s.SinkRequired("b") {
+		t.Fatalf("TestNotifyService_SinkRequired failed")
+	}
+}
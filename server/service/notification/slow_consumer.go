@@ -0,0 +1,127 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package notification
+
+import (
+	"errors"
+	"github.com/apache/servicecomb-service-center/server/reload"
+	"github.com/astaxie/beego"
+	"strings"
+	"time"
+)
+
+const (
+	// SlowConsumerDrop drops the event if the subscriber queue is still
+	// full after the grace timeout
+	SlowConsumerDrop SlowConsumerPolicy = "drop"
+	// SlowConsumerDisconnect disconnects the subscriber if the queue is
+	// still full after the grace timeout, the watcher can resume from
+	// the replay buffer by the revision it received
+	SlowConsumerDisconnect SlowConsumerPolicy = "disconnect"
+)
+
+var ErrSlowConsumer = errors.New("the subscriber is too slow to consume the events")
+
+var subscriberConfig = SubscriberConfig{
+	QueueSize: DEFAULT_MAX_QUEUE,
+	Policy:    SlowConsumerDrop,
+	Grace:     DEFAULT_ADD_JOB_TIMEOUT,
+}
+
+func init() {
+	subscriberConfig = LoadSubscriberConfig()
+
+	// the reloaded config applies to the new subscribers
+	apply := func(string) {
+		subscriberConfig = LoadSubscriberConfig()
+	}
+	reload.Register(reload.Option{Key: "watch_queue_size", Validate: reload.Int(1), Apply: apply})
+	reload.Register(reload.Option{Key: "watch_slow_policy",
+		Validate: reload.OneOf(string(SlowConsumerDrop), string(SlowConsumerDisconnect)), Apply: apply})
+	reload.Register(reload.Option{Key: "watch_slow_grace", Validate: reload.Duration(0), Apply: apply})
+}
+
+type SlowConsumerPolicy string
+
+// SubscriberConfig is the queue config of the watchers
+type SubscriberConfig struct {
+	QueueSize int
+	Policy    SlowConsumerPolicy
+	// Grace is the max time to wait for the subscriber queue to be
+	// available before the policy is applied
+	Grace time.Duration
+}
+
+func LoadSubscriberConfig() SubscriberConfig {
+	cfg := SubscriberConfig{
+		QueueSize: beego.AppConfig.DefaultInt("watch_queue_size", DEFAULT_MAX_QUEUE),
+		Policy: SlowConsumerPolicy(strings.ToLower(
+			beego.AppConfig.DefaultString("watch_slow_policy", string(SlowConsumerDrop)))),
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = DEFAULT_MAX_QUEUE
+	}
+	if cfg.Policy != SlowConsumerDisconnect {
+		cfg.Policy = SlowConsumerDrop
+	}
+	grace, err := time.ParseDuration(beego.AppConfig.DefaultString("watch_slow_grace", ""))
+	if err != nil || grace < 0 {
+		grace = DEFAULT_ADD_JOB_TIMEOUT
+	}
+	cfg.Grace = grace
+	return cfg
+}
+
+// Laggard is the lag status of a subscriber
+type Laggard struct {
+	Id       string `json:"id"`
+	Type     string `json:"type"`
+	Subject  string `json:"subject"`
+	Group    string `json:"group"`
+	Policy   string `json:"policy"`
+	Pending  int    `json:"pending"`
+	Capacity int    `json:"capacity"`
+	Dropped  int64  `json:"dropped"`
+	// LaggingSince is the time when the subscriber queue became full
+	LaggingSince string `json:"laggingSince,omitempty"`
+}
+
+// Lagger is the subscriber which can report the lag status
+type Lagger interface {
+	Lag() *Laggard
+}
+
+// IsLagging returns true if the queue is more than half full or
+// any event has been dropped
+func (l *Laggard) IsLagging() bool {
+	return l.Pending*2 >= l.Capacity || l.Dropped > 0
+}
+
+// Laggards returns the lagging subscribers of all notify types
+func (s *NotifyService) Laggards() (laggards []*Laggard) {
+	s.walk(func(n Subscriber) bool {
+		l, ok := n.(Lagger)
+		if !ok {
+			return true
+		}
+		if lag := l.Lag(); lag.IsLagging() {
+			laggards = append(laggards, lag)
+		}
+		return true
+	})
+	return
+}
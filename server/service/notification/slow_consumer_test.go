@@ -0,0 +1,65 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package notification
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"golang.org/x/net/context"
+	"testing"
+)
+
+func TestListWatcher_SlowConsumer(t *testing.T) {
+	s := &NotifyService{
+		isClose:   true,
+		goroutine: gopool.New(context.Background()),
+	}
+	s.Start()
+	defer s.Stop()
+
+	newWatcher := func(policy SlowConsumerPolicy) *ListWatcher {
+		w := NewListWatcher("g", "s", nil)
+		w.Job = make(chan *WatchJob, 1)
+		w.Policy = policy
+		w.Grace = 0
+		if err := s.AddSubscriber(w); err != nil {
+			t.Fatalf("TestListWatcher_SlowConsumer failed, %s", err)
+		}
+		return w
+	}
+	newJob := func(rev int64) *WatchJob {
+		return NewWatchJob("g", "s", rev, &pb.WatchInstanceResponse{})
+	}
+
+	w := newWatcher(SlowConsumerDrop)
+	w.OnMessage(newJob(1))
+	w.OnMessage(newJob(2))
+	lag := w.Lag()
+	if w.Err() != nil || lag.Dropped != 1 || lag.Pending != 1 || len(lag.LaggingSince) == 0 || !lag.IsLagging() {
+		t.Fatalf("TestListWatcher_SlowConsumer failed, %v", lag)
+	}
+	if laggards := s.Laggards(); len(laggards) != 1 || laggards[0].Id != w.Id() {
+		t.Fatalf("TestListWatcher_SlowConsumer failed, %v", laggards)
+	}
+
+	w = newWatcher(SlowConsumerDisconnect)
+	w.OnMessage(newJob(1))
+	w.OnMessage(newJob(2))
+	if w.Err() != ErrSlowConsumer || w.Lag().Dropped != 0 {
+		t.Fatalf("TestListWatcher_SlowConsumer failed, %v", w.Err())
+	}
+}
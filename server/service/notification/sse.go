@@ -0,0 +1,245 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package notification
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	apt "github.com/apache/servicecomb-service-center/server/core"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	serviceUtil "github.com/apache/servicecomb-service-center/server/service/util"
+	"golang.org/x/net/context"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"time"
+)
+
+const (
+	SSE_CONTENT_TYPE = "text/event-stream"
+	// the client reconnects after the retry milliseconds if the stream is broken
+	sseRetry = 3000
+)
+
+// ServerSentEvents pushes the watch jobs to the http client in the
+// text/event-stream format, the event id is the revision, so the
+// client can resume from the Last-Event-ID header after reconnecting
+type ServerSentEvents struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	w       http.ResponseWriter
+	flusher http.Flusher
+	watcher *ListWatcher
+	// format is the encoding of events, raw or cloudevents
+	format string
+	// conn is the hijacked connection, the stream is written to it in
+	// chunks, nil if the response writer does not support hijacking
+	conn  net.Conn
+	buf   *bufio.Writer
+	chunk io.WriteCloser
+}
+
+func (s *ServerSentEvents) Init() error {
+	s.w.Header().Set("Content-Type", SSE_CONTENT_TYPE)
+	s.w.Header().Set("Cache-Control", "no-cache")
+	s.w.Header().Set("Connection", "keep-alive")
+	// disable the proxy buffering, e.g. nginx
+	s.w.Header().Set("X-Accel-Buffering", "no")
+	if err := s.hijack(); err != nil {
+		log.Errorf(err, "establish sse watch failed: hijack connection failed.")
+		return err
+	}
+	if s.conn == nil {
+		s.w.WriteHeader(http.StatusOK)
+	}
+	if err := s.write(fmt.Sprintf("retry: %d\n\n", sseRetry)); err != nil {
+		log.Errorf(err, "establish sse watch failed: write message failed.")
+		return err
+	}
+
+	if err := GetNotifyService().AddSubscriber(s.watcher); err != nil {
+		err = fmt.Errorf("establish sse watch failed: notify service error, %s", err.Error())
+		log.Errorf(nil, err.Error())
+		s.writeError(err)
+		return err
+	}
+	log.Debugf("start watching instance status, sse watcher, subject: %s, group: %s",
+		s.watcher.Subject(), s.watcher.Group())
+	return nil
+}
+
+// hijack takes over the connection from the http server, otherwise the
+// WriteTimeout of the server cuts the stream, the response header is
+// written at once and the stream is closed with the connection.
+// The response writers not supporting hijacking, e.g. http2, are
+// streamed as they are
+func (s *ServerSentEvents) hijack() error {
+	hj, ok := s.w.(http.Hijacker)
+	if !ok {
+		return nil
+	}
+	conn, rw, err := hj.Hijack()
+	if err != nil {
+		return err
+	}
+	// clear the deadlines set by the http server
+	conn.SetDeadline(time.Time{})
+	s.conn, s.buf = conn, rw.Writer
+
+	header := s.w.Header()
+	header.Set("Connection", "close")
+	header.Set("Transfer-Encoding", "chunked")
+	s.buf.WriteString("HTTP/1.1 200 OK\r\n")
+	header.Write(s.buf)
+	s.buf.WriteString("\r\n")
+	s.chunk = httputil.NewChunkedWriter(s.buf)
+
+	// the server no longer cancels the context when the client closes
+	go func() {
+		io.Copy(ioutil.Discard, rw.Reader)
+		s.cancel()
+	}()
+	return nil
+}
+
+func (s *ServerSentEvents) write(message string) error {
+	if s.conn == nil {
+		if _, err := s.w.Write(util.StringToBytesWithNoCopy(message)); err != nil {
+			return err
+		}
+		s.flusher.Flush()
+		return nil
+	}
+	// a write blocked longer than the ping interval means the client is stuck
+	s.conn.SetWriteDeadline(time.Now().Add(RESTKeepalive().PingInterval))
+	if _, err := s.chunk.Write(util.StringToBytesWithNoCopy(message)); err != nil {
+		return err
+	}
+	return s.buf.Flush()
+}
+
+// Close ends the chunked stream and closes the hijacked connection
+func (s *ServerSentEvents) Close() {
+	s.cancel()
+	if s.conn == nil {
+		return
+	}
+	s.conn.SetWriteDeadline(time.Now().Add(RESTKeepalive().PingInterval))
+	s.chunk.Close()
+	s.buf.WriteString("\r\n")
+	s.buf.Flush()
+	s.conn.Close()
+}
+
+func (s *ServerSentEvents) writeError(err error) error {
+	return s.write(fmt.Sprintf("event: error\ndata: %s\n\n", err.Error()))
+}
+
+func (s *ServerSentEvents) writeJob(job *WatchJob) error {
+	resp := job.Response
+	log.Infof("event[%s] is coming in, sse watcher, subject: %s, group: %s",
+		resp.Action, s.watcher.Subject(), s.watcher.Group())
+
+	data, err := json.Marshal(EncodeEvent(s.format, util.ParseDomainProject(s.ctx), job))
+	if err != nil {
+		log.Errorf(err, "sse watcher marshal event failed, subject: %s, group: %s",
+			s.watcher.Subject(), s.watcher.Group())
+		return s.writeError(err)
+	}
+	return s.write(fmt.Sprintf("id: %d\ndata: %s\n\n", job.Revision, util.BytesToStringWithNoCopy(data)))
+}
+
+// Serve blocks until the client closes the stream or the watcher is removed
+func (s *ServerSentEvents) Serve() {
+	ticker := time.NewTicker(RESTKeepalive().PingInterval)
+	defer ticker.Stop()
+	for {
+		var err error
+		select {
+		case <-s.ctx.Done():
+			// client close
+			s.watcher.SetError(s.ctx.Err())
+			return
+		case <-ticker.C:
+			domainProject := util.ParseDomainProject(s.ctx)
+			if !serviceUtil.ServiceExist(s.ctx, domainProject, s.watcher.Group()) {
+				err = errors.New("Service does not exit.")
+				s.writeError(err)
+				break
+			}
+			// the comment line keeps the connection alive through proxies
+			err = s.write(": ping\n\n")
+		case job := <-s.watcher.Job:
+			if job == nil {
+				err = s.watcher.Err()
+				if err == nil {
+					err = errors.New("server shutdown")
+				}
+				s.writeError(err)
+				return
+			}
+			if err = s.writeJob(job); err == nil {
+				s.watcher.Touch()
+				ReportDelivery(s.watcher.Type(), deliverySSE, job.CreateAt())
+			}
+		}
+		if err != nil {
+			log.Errorf(err, "sse watcher catch an err, subject: %s, group: %s",
+				s.watcher.Subject(), s.watcher.Group())
+			s.watcher.SetError(err)
+			return
+		}
+	}
+}
+
+// DoServerSentEventsListAndWatch returns an error if the response writer
+// does not support streaming, otherwise it blocks until the stream closed
+func DoServerSentEventsListAndWatch(ctx context.Context, serviceId string,
+	f func() ([]*pb.WatchInstanceResponse, int64), w http.ResponseWriter) error {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return errors.New("streaming is not supported")
+	}
+	domainProject := util.ParseDomainProject(ctx)
+	format, _ := ctx.Value(serviceUtil.CTX_EVENT_FORMAT).(string)
+	watcher := NewListWatcher(serviceId, apt.GetInstanceRootKey(domainProject)+"/", f)
+	watcher.SetRemote("sse", util.GetIPFromContext(ctx))
+	watcher.SetFilter("format", format)
+	setSinceFilter(ctx, watcher.BaseSubscriber)
+	ctx, cancel := context.WithCancel(ctx)
+	s := &ServerSentEvents{
+		ctx:     ctx,
+		cancel:  cancel,
+		w:       w,
+		flusher: flusher,
+		watcher: watcher,
+		format:  format,
+	}
+	defer s.Close()
+	if err := s.Init(); err != nil {
+		// the response has been sent
+		return nil
+	}
+	s.Serve()
+	return nil
+}
@@ -0,0 +1,149 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package notification
+
+import (
+	"bufio"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	apt "github.com/apache/servicecomb-service-center/server/core"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"golang.org/x/net/context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type noFlushWriter struct {
+	http.ResponseWriter
+}
+
+func TestDoServerSentEventsListAndWatch(t *testing.T) {
+	GetNotifyService().Start()
+
+	ctx, cancel := context.WithCancel(util.SetDomainProject(context.Background(), "default", "default"))
+	if err := DoServerSentEventsListAndWatch(ctx, "g", nil, &noFlushWriter{httptest.NewRecorder()}); err == nil {
+		t.Fatalf("TestDoServerSentEventsListAndWatch failed")
+	}
+
+	w := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		err := DoServerSentEventsListAndWatch(ctx, "g", func() ([]*pb.WatchInstanceResponse, int64) {
+			return []*pb.WatchInstanceResponse{{
+				Action:   string(pb.EVT_INIT),
+				Key:      &pb.MicroServiceKey{},
+				Instance: &pb.MicroServiceInstance{},
+			}}, 1
+		}, w)
+		if err != nil {
+			t.Errorf("TestDoServerSentEventsListAndWatch failed, %s", err)
+		}
+		close(done)
+	}()
+
+	<-time.After(time.Second)
+	GetNotifyService().AddJob(NewWatchJob("g", apt.GetInstanceRootKey("default/default")+"/", 2,
+		&pb.WatchInstanceResponse{
+			Response: pb.CreateResponse(pb.Response_SUCCESS, "ok"),
+			Action:   string(pb.EVT_CREATE),
+			Key:      &pb.MicroServiceKey{},
+			Instance: &pb.MicroServiceInstance{},
+		}))
+	<-time.After(time.Second)
+	cancel()
+	<-done
+
+	body := w.Body.String()
+	if w.Header().Get("Content-Type") != SSE_CONTENT_TYPE ||
+		!strings.Contains(body, "retry: ") ||
+		!strings.Contains(body, "id: 1\ndata: {\"action\":\"INIT\"") ||
+		!strings.Contains(body, "id: 2\ndata: {\"action\":\"CREATE\"") {
+		t.Fatalf("TestDoServerSentEventsListAndWatch failed, %s", body)
+	}
+}
+
+func TestServerSentEvents_WriteTimeout(t *testing.T) {
+	GetNotifyService().Start()
+
+	done := make(chan struct{})
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := util.SetDomainProject(r.Context(), "default", "default")
+		DoServerSentEventsListAndWatch(ctx, "sse", func() ([]*pb.WatchInstanceResponse, int64) {
+			return []*pb.WatchInstanceResponse{{
+				Action:   string(pb.EVT_INIT),
+				Key:      &pb.MicroServiceKey{},
+				Instance: &pb.MicroServiceInstance{},
+			}}, 1
+		}, w)
+		close(done)
+	}))
+	srv.Config.WriteTimeout = 200 * time.Millisecond
+	srv.Start()
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("TestServerSentEvents_WriteTimeout failed, %s", err)
+	}
+	if resp.Header.Get("Content-Type") != SSE_CONTENT_TYPE {
+		t.Fatalf("TestServerSentEvents_WriteTimeout failed, %v", resp.Header)
+	}
+	lines := make(chan string, 100)
+	go func() {
+		r := bufio.NewReader(resp.Body)
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				close(lines)
+				return
+			}
+			lines <- line
+		}
+	}()
+
+	// keep the stream open past the write timeout
+	<-time.After(3 * srv.Config.WriteTimeout)
+	GetNotifyService().AddJob(NewWatchJob("sse", apt.GetInstanceRootKey("default/default")+"/", 2,
+		&pb.WatchInstanceResponse{
+			Response: pb.CreateResponse(pb.Response_SUCCESS, "ok"),
+			Action:   string(pb.EVT_CREATE),
+			Key:      &pb.MicroServiceKey{},
+			Instance: &pb.MicroServiceInstance{},
+		}))
+	timeout := time.After(3 * time.Second)
+	for received := false; !received; {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				t.Fatalf("TestServerSentEvents_WriteTimeout failed, stream closed")
+			}
+			received = strings.HasPrefix(line, "id: 2")
+		case <-timeout:
+			t.Fatalf("TestServerSentEvents_WriteTimeout failed, event not received")
+		}
+	}
+
+	// the watcher stops after the client closes
+	resp.Body.Close()
+	select {
+	case <-done:
+	case <-time.After(3 * time.Second):
+		t.Fatalf("TestServerSentEvents_WriteTimeout failed, stream not closed")
+	}
+}
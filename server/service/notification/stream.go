@@ -25,14 +25,14 @@ import (
 )
 
 func HandleWatchJob(watcher *ListWatcher, stream pb.ServiceInstanceCtrl_WatchServer) (err error) {
-	timer := time.NewTimer(DEFAULT_HEARTBEAT_INTERVAL)
+	// the grpc server pings the client in http2 frame, see server/rpc
+	interval := RPCKeepalive().PingInterval
+	timer := time.NewTimer(interval)
 	defer timer.Stop()
 	for {
 		select {
 		case <-timer.C:
-			timer.Reset(DEFAULT_HEARTBEAT_INTERVAL)
-
-			// TODO grpc 长连接心跳？
+			timer.Reset(interval)
 		case job := <-watcher.Job:
 			if job == nil {
 				err = errors.New("channel is closed")
@@ -52,7 +52,9 @@ func HandleWatchJob(watcher *ListWatcher, stream pb.ServiceInstanceCtrl_WatchSer
 				return
 			}
 
-			util.ResetTimer(timer, DEFAULT_HEARTBEAT_INTERVAL)
+			watcher.Touch()
+			ReportDelivery(watcher.Type(), deliveryGRPC, job.CreateAt())
+			util.ResetTimer(timer, interval)
 		}
 	}
 }
@@ -19,6 +19,8 @@ package notification
 import (
 	"errors"
 	"github.com/apache/servicecomb-service-center/pkg/util"
+	"sync/atomic"
+	"time"
 )
 
 type Subscriber interface {
@@ -45,6 +47,14 @@ type BaseSubscriber struct {
 	nType   NotifyType
 	service *NotifyService
 	err     error
+	created time.Time
+	// transport, remoteAddr and filters describe the watch client
+	transport  string
+	remoteAddr string
+	filters    map[string]string
+	// lastActivity is the unix nano time of the last activity
+	// of the watch client
+	lastActivity int64
 }
 
 func (s *BaseSubscriber) Id() string                    { return s.id }
@@ -61,11 +71,55 @@ func (s *BaseSubscriber) OnMessage(job NotifyJob) {
 	s.SetError(errors.New("do not call base notifier OnMessage method"))
 }
 
+// SetRemote sets the transport and address of the watch client,
+// it must be called before the subscriber is added
+func (s *BaseSubscriber) SetRemote(transport, remoteAddr string) {
+	s.transport, s.remoteAddr = transport, remoteAddr
+}
+
+// SetFilter records the watch parameter, it must be called before
+// the subscriber is added
+func (s *BaseSubscriber) SetFilter(key, value string) {
+	if len(value) == 0 {
+		return
+	}
+	if s.filters == nil {
+		s.filters = make(map[string]string)
+	}
+	s.filters[key] = value
+}
+
+// Touch records the activity of the watch client
+func (s *BaseSubscriber) Touch() {
+	atomic.StoreInt64(&s.lastActivity, time.Now().UnixNano())
+}
+
+func (s *BaseSubscriber) LastActivity() time.Time {
+	return time.Unix(0, atomic.LoadInt64(&s.lastActivity))
+}
+
+func (s *BaseSubscriber) Describe() *Subscription {
+	return &Subscription{
+		Id:           s.id,
+		Type:         s.nType.String(),
+		Subject:      s.subject,
+		Group:        s.group,
+		Transport:    s.transport,
+		RemoteAddr:   s.remoteAddr,
+		Since:        s.created.Format(time.RFC3339),
+		LastActivity: s.LastActivity().Format(time.RFC3339),
+		Filters:      s.filters,
+	}
+}
+
 func NewSubscriber(nType NotifyType, subject, group string) *BaseSubscriber {
+	now := time.Now()
 	return &BaseSubscriber{
-		id:      util.GenerateUuid(),
-		group:   group,
-		subject: subject,
-		nType:   nType,
+		id:           util.GenerateUuid(),
+		group:        group,
+		subject:      subject,
+		nType:        nType,
+		created:      now,
+		lastActivity: now.UnixNano(),
 	}
 }
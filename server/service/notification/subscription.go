@@ -0,0 +1,135 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package notification
+
+import (
+	"errors"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	apt "github.com/apache/servicecomb-service-center/server/core"
+	"strings"
+)
+
+var (
+	ErrTerminated   = errors.New("the subscription is terminated by the administrator")
+	ErrShuttingDown = errors.New("server is shutting down")
+)
+
+// Subscription is the description of an active subscriber
+type Subscription struct {
+	Id      string `json:"id"`
+	Type    string `json:"type"`
+	Subject string `json:"subject"`
+	Group   string `json:"group"`
+	// Transport is websocket or sse, empty means the internal subscriber
+	Transport  string `json:"transport,omitempty"`
+	RemoteAddr string `json:"remoteAddr,omitempty"`
+	// Since is the time when the subscriber is created
+	Since string `json:"since"`
+	// LastActivity is the time of the last message received from the
+	// websocket client including the pings and pongs, or the time of
+	// the last event sent to the sse and grpc client
+	LastActivity string            `json:"lastActivity"`
+	Filters      map[string]string `json:"filters,omitempty"`
+	Lag          *Laggard          `json:"lag,omitempty"`
+}
+
+// Describer is the subscriber which can describe itself
+type Describer interface {
+	Describe() *Subscription
+}
+
+// walk calls f with every subscriber until f returns false
+func (s *NotifyService) walk(f func(n Subscriber) bool) {
+	if s.Closed() {
+		return
+	}
+	next := true
+	s.processors.ForEach(func(item util.MapItem) bool {
+		item.Value.(*Processor).subjects.ForEach(func(item util.MapItem) bool {
+			item.Value.(*Subject).groups.ForEach(func(item util.MapItem) bool {
+				item.Value.(*Group).subscribers.ForEach(func(item util.MapItem) bool {
+					next = f(item.Value.(Subscriber))
+					return next
+				})
+				return next
+			})
+			return next
+		})
+		return next
+	})
+}
+
+// Subscriptions returns the active watchers, filtered by the notify
+// type and group if they are not empty
+func (s *NotifyService) Subscriptions(nType, group string) (subscriptions []*Subscription) {
+	s.walk(func(n Subscriber) bool {
+		if !isWatcher(n) || (len(nType) > 0 && n.Type().String() != nType) ||
+			(len(group) > 0 && n.Group() != group) {
+			return true
+		}
+		d, ok := n.(Describer)
+		if !ok {
+			return true
+		}
+		subscription := d.Describe()
+		if l, ok := n.(Lagger); ok {
+			subscription.Lag = l.Lag()
+		}
+		subscriptions = append(subscriptions, subscription)
+		return true
+	})
+	return
+}
+
+// WatchConnections returns the number of the watchers of each domain
+// project connected to this service center
+func (s *NotifyService) WatchConnections() map[string]int64 {
+	counts := make(map[string]int64)
+	root := apt.GetInstanceRootKey("")
+	s.walk(func(n Subscriber) bool {
+		switch n.Type() {
+		case INSTANCE:
+			// the subject is the instance root key of the domain project
+			if strings.HasPrefix(n.Subject(), root) {
+				counts[strings.TrimSuffix(strings.TrimPrefix(n.Subject(), root), "/")]++
+			}
+		case RESOURCE:
+			counts[n.Group()]++
+		}
+		return true
+	})
+	return counts
+}
+
+// Terminate disconnects the watcher, returns false if it does not exist
+func (s *NotifyService) Terminate(id string) (ok bool) {
+	s.walk(func(n Subscriber) bool {
+		if n.Id() != id || !isWatcher(n) {
+			return true
+		}
+		n.SetError(ErrTerminated)
+		ok = true
+		return false
+	})
+	return
+}
+
+// isWatcher returns false for the internal subscribers, e.g. the
+// health checker and the event sink
+func isWatcher(n Subscriber) bool {
+	return n.Type() == INSTANCE || n.Type() == RESOURCE
+}
@@ -0,0 +1,69 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package notification
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	"golang.org/x/net/context"
+	"testing"
+)
+
+func TestNotifyService_Subscriptions(t *testing.T) {
+	s := &NotifyService{
+		isClose:   true,
+		goroutine: gopool.New(context.Background()),
+	}
+	s.Start()
+	defer s.Stop()
+
+	w := NewListWatcher("g", "s", nil)
+	w.SetRemote("websocket", "127.0.0.1:12345")
+	w.SetFilter("format", "cloudevents")
+	w.SetFilter("since", "")
+	if err := s.AddSubscriber(w); err != nil {
+		t.Fatalf("TestNotifyService_Subscriptions failed, %s", err)
+	}
+	r := NewResourceWatcher("d/p", []string{"a"}, nil)
+	if err := s.AddSubscriber(r); err != nil {
+		t.Fatalf("TestNotifyService_Subscriptions failed, %s", err)
+	}
+
+	subscriptions := s.Subscriptions("", "")
+	if len(subscriptions) != 2 {
+		t.Fatalf("TestNotifyService_Subscriptions failed, %v", subscriptions)
+	}
+	subscriptions = s.Subscriptions(INSTANCE.String(), "g")
+	if len(subscriptions) != 1 {
+		t.Fatalf("TestNotifyService_Subscriptions failed, %v", subscriptions)
+	}
+	sub := subscriptions[0]
+	if sub.Id != w.Id() || sub.Transport != "websocket" || sub.RemoteAddr != "127.0.0.1:12345" ||
+		len(sub.Since) == 0 || len(sub.Filters) != 1 || sub.Lag == nil {
+		t.Fatalf("TestNotifyService_Subscriptions failed, %v", sub)
+	}
+	if subscriptions = s.Subscriptions(RESOURCE.String(), ""); len(subscriptions) != 1 ||
+		subscriptions[0].Filters["providers"] != "a" {
+		t.Fatalf("TestNotifyService_Subscriptions failed, %v", subscriptions)
+	}
+
+	if s.Terminate("notexist") {
+		t.Fatalf("TestNotifyService_Subscriptions failed")
+	}
+	if !s.Terminate(w.Id()) || w.Err() != ErrTerminated {
+		t.Fatalf("TestNotifyService_Subscriptions failed, %v", w.Err())
+	}
+}
@@ -18,6 +18,7 @@ package notification
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/pkg/util"
@@ -26,6 +27,7 @@ import (
 	serviceUtil "github.com/apache/servicecomb-service-center/server/service/util"
 	"github.com/gorilla/websocket"
 	"golang.org/x/net/context"
+	"strconv"
 	"time"
 )
 
@@ -38,10 +40,15 @@ type WebSocket struct {
 	needPingWatcher bool
 	free            chan struct{}
 	closed          chan struct{}
+	// batch is true if the watcher receives the events of one flush
+	// interval in a json array frame
+	batch bool
+	// format is the encoding of events, raw or cloudevents
+	format string
 }
 
 func (wh *WebSocket) Init() error {
-	wh.ticker = time.NewTicker(DEFAULT_HEARTBEAT_INTERVAL)
+	wh.ticker = time.NewTicker(RESTKeepalive().PingInterval)
 	wh.needPingWatcher = true
 	wh.free = make(chan struct{}, 1)
 	wh.closed = make(chan struct{})
@@ -92,8 +99,11 @@ func (wh *WebSocket) heartbeat(messageType int) error {
 
 func (wh *WebSocket) HandleWatchWebSocketControlMessage() {
 	remoteAddr := wh.conn.RemoteAddr().String()
+	// the watcher is closed if nothing is received within the idle timeout
+	wh.conn.SetReadDeadline(RESTKeepalive().Deadline())
 	// PING
 	wh.conn.SetPingHandler(func(message string) error {
+		wh.active()
 		if wh.needPingWatcher {
 			log.Infof("received 'Ping' message '%s' from watcher[%s], no longer send 'Ping' to it, subject: %s, group: %s",
 				message, remoteAddr, wh.watcher.Subject(), wh.watcher.Group())
@@ -103,6 +113,7 @@ func (wh *WebSocket) HandleWatchWebSocketControlMessage() {
 	})
 	// PONG
 	wh.conn.SetPongHandler(func(message string) error {
+		wh.active()
 		log.Debugf("received 'Pong' message '%s' from watcher[%s], subject: %s, group: %s",
 			message, remoteAddr, wh.watcher.Subject(), wh.watcher.Group())
 		return nil
@@ -117,13 +128,20 @@ func (wh *WebSocket) HandleWatchWebSocketControlMessage() {
 	for {
 		_, _, err := wh.conn.ReadMessage()
 		if err != nil {
-			// client close or conn broken
+			// client close, conn broken or idle timeout
 			wh.watcher.SetError(err)
 			return
 		}
+		wh.active()
 	}
 }
 
+// active records the activity of the watcher and extends the read deadline
+func (wh *WebSocket) active() {
+	wh.watcher.Touch()
+	wh.conn.SetReadDeadline(RESTKeepalive().Deadline())
+}
+
 func (wh *WebSocket) sendClose(code int, text string) error {
 	remoteAddr := wh.conn.RemoteAddr().String()
 	var message []byte
@@ -154,7 +172,7 @@ func (wh *WebSocket) Pick() interface{} {
 			if j == nil {
 				return fmt.Errorf("server shutdown")
 			}
-			return j
+			return wh.drain(j)
 		default:
 			// reset if idle
 			wh.SetReady()
@@ -165,6 +183,28 @@ func (wh *WebSocket) Pick() interface{} {
 	return nil
 }
 
+// drain picks the jobs queued up to the publisher's batch size,
+// returns the job itself if there is no more job
+func (wh *WebSocket) drain(j *WatchJob) interface{} {
+	jobs := []*WatchJob{j}
+loop:
+	for len(jobs) < publisher.BatchSize {
+		select {
+		case next := <-wh.watcher.Job:
+			if next == nil {
+				return fmt.Errorf("server shutdown")
+			}
+			jobs = append(jobs, next)
+		default:
+			break loop
+		}
+	}
+	if len(jobs) == 1 {
+		return j
+	}
+	return jobs
+}
+
 // HandleWatchWebSocketJob will be called if Pick() returns not nil
 func (wh *WebSocket) HandleWatchWebSocketJob(o interface{}) {
 	defer wh.SetReady()
@@ -179,6 +219,22 @@ func (wh *WebSocket) HandleWatchWebSocketJob(o interface{}) {
 			remoteAddr, wh.watcher.Subject(), wh.watcher.Group())
 
 		message = util.StringToBytesWithNoCopy(fmt.Sprintf("watcher catch an err: %s", err.Error()))
+		switch err {
+		case ErrSlowConsumer:
+			// the watcher should reconnect and resume from the revision it received
+			wh.write(message)
+			wh.sendClose(websocket.CloseTryAgainLater, err.Error())
+			return
+		case ErrTerminated:
+			wh.write(message)
+			wh.sendClose(websocket.ClosePolicyViolation, err.Error())
+			return
+		case ErrShuttingDown:
+			// the watcher should reconnect to the other service centers
+			wh.write(message)
+			wh.sendClose(websocket.CloseGoingAway, err.Error())
+			return
+		}
 	case time.Time:
 		domainProject := util.ParseDomainProject(wh.ctx)
 		if !serviceUtil.ServiceExist(wh.ctx, domainProject, wh.watcher.Group()) {
@@ -195,42 +251,117 @@ func (wh *WebSocket) HandleWatchWebSocketJob(o interface{}) {
 		wh.heartbeat(websocket.PingMessage)
 		return
 	case *WatchJob:
-		job := o.(*WatchJob)
-		resp := job.Response
-
-		providerFlag := fmt.Sprintf("%s/%s/%s", resp.Key.AppId, resp.Key.ServiceName, resp.Key.Version)
-		if resp.Action != string(pb.EVT_EXPIRE) {
-			providerFlag = fmt.Sprintf("%s/%s(%s)", resp.Instance.ServiceId, resp.Instance.InstanceId, providerFlag)
-		}
-		log.Infof("event[%s] is coming in, watcher[%s] watch %s, subject: %s, group: %s",
-			resp.Action, remoteAddr, providerFlag, wh.watcher.Subject(), wh.watcher.Group())
-
-		resp.Response = nil
-		data, err := json.Marshal(resp)
-		if err != nil {
-			log.Errorf(err, "watcher[%s] watch %s, subject: %s, group: %s",
-				remoteAddr, providerFlag, o, wh.watcher.Subject(), wh.watcher.Group())
-			message = util.StringToBytesWithNoCopy(fmt.Sprintf("marshal output file error, %s", err.Error()))
-			break
+		message = wh.marshal(o)
+	case []*WatchJob:
+		jobs := o.([]*WatchJob)
+		if !wh.batch {
+			for _, job := range jobs {
+				if wh.write(wh.marshal(job)) == nil {
+					ReportDelivery(wh.watcher.Type(), deliveryWebSocket, job.CreateAt())
+				}
+			}
+			return
 		}
-		message = data
+		message = wh.marshal(coalesce(jobs))
 	default:
 		log.Errorf(nil, "watcher[%s] unknown input %v, subject: %s, group: %s",
 			remoteAddr, o, wh.watcher.Subject(), wh.watcher.Group())
 		return
 	}
 
+	if wh.write(message) != nil {
+		return
+	}
+	switch o.(type) {
+	case *WatchJob:
+		ReportDelivery(wh.watcher.Type(), deliveryWebSocket, o.(*WatchJob).CreateAt())
+	case []*WatchJob:
+		for _, job := range o.([]*WatchJob) {
+			ReportDelivery(wh.watcher.Type(), deliveryWebSocket, job.CreateAt())
+		}
+	}
+}
+
+func (wh *WebSocket) logEvent(resp *pb.WatchInstanceResponse) string {
+	providerFlag := fmt.Sprintf("%s/%s/%s", resp.Key.AppId, resp.Key.ServiceName, resp.Key.Version)
+	if resp.Action != string(pb.EVT_EXPIRE) {
+		providerFlag = fmt.Sprintf("%s/%s(%s)", resp.Instance.ServiceId, resp.Instance.InstanceId, providerFlag)
+	}
+	log.Infof("event[%s] is coming in, watcher[%s] watch %s, subject: %s, group: %s",
+		resp.Action, wh.conn.RemoteAddr(), providerFlag, wh.watcher.Subject(), wh.watcher.Group())
+	return providerFlag
+}
+
+// marshal returns the message of one event or the json array of events
+func (wh *WebSocket) marshal(o interface{}) []byte {
+	var (
+		providerFlag string
+		v            interface{}
+	)
+	domainProject := util.ParseDomainProject(wh.ctx)
+	switch o.(type) {
+	case *WatchJob:
+		job := o.(*WatchJob)
+		providerFlag = wh.logEvent(job.Response)
+		v = EncodeEvent(wh.format, domainProject, job)
+	case []*WatchJob:
+		jobs := o.([]*WatchJob)
+		events := make([]interface{}, 0, len(jobs))
+		for _, job := range jobs {
+			wh.logEvent(job.Response)
+			events = append(events, EncodeEvent(wh.format, domainProject, job))
+		}
+		providerFlag = fmt.Sprintf("%d events", len(jobs))
+		v = events
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		log.Errorf(err, "watcher[%s] watch %s, subject: %s, group: %s",
+			wh.conn.RemoteAddr(), providerFlag, wh.watcher.Subject(), wh.watcher.Group())
+		return util.StringToBytesWithNoCopy(fmt.Sprintf("marshal output file error, %s", err.Error()))
+	}
+	return data
+}
+
+func (wh *WebSocket) write(message []byte) error {
 	select {
 	case <-wh.closed:
-		return
+		return errors.New("websocket is closed")
 	default:
 	}
 
 	err := wh.conn.WriteMessage(websocket.TextMessage, message)
 	if err != nil {
 		log.Errorf(err, "watcher[%s] catch an err, subject: %s, group: %s",
-			remoteAddr, wh.watcher.Subject(), wh.watcher.Group())
+			wh.conn.RemoteAddr(), wh.watcher.Subject(), wh.watcher.Group())
+	}
+	return err
+}
+
+// coalesce drops the events superseded by the later events of the same
+// instance, the expire events are coalesced by the service key,
+// the remaining events are kept in order
+func coalesce(jobs []*WatchJob) []*WatchJob {
+	last := make(map[string]int, len(jobs))
+	for i, job := range jobs {
+		last[eventKey(job.Response)] = i
+	}
+	news := make([]*WatchJob, 0, len(last))
+	for i, job := range jobs {
+		if last[eventKey(job.Response)] == i {
+			news = append(news, job)
+		}
+	}
+	return news
+}
+
+func eventKey(resp *pb.WatchInstanceResponse) string {
+	if resp.Action == string(pb.EVT_EXPIRE) {
+		return util.StringJoin([]string{resp.Action,
+			resp.Key.AppId, resp.Key.ServiceName, resp.Key.Version}, "/")
 	}
+	return util.StringJoin([]string{resp.Instance.ServiceId, resp.Instance.InstanceId}, "/")
 }
 
 func (wh *WebSocket) Ready() <-chan struct{} {
@@ -250,14 +381,29 @@ func (wh *WebSocket) Stop() {
 
 func DoWebSocketListAndWatch(ctx context.Context, serviceId string, f func() ([]*pb.WatchInstanceResponse, int64), conn *websocket.Conn) {
 	domainProject := util.ParseDomainProject(ctx)
+	batch, _ := ctx.Value(serviceUtil.CTX_WATCH_BATCH).(bool)
+	format, _ := ctx.Value(serviceUtil.CTX_EVENT_FORMAT).(string)
+	watcher := NewListWatcher(serviceId, apt.GetInstanceRootKey(domainProject)+"/", f)
+	watcher.SetRemote("websocket", conn.RemoteAddr().String())
+	watcher.SetFilter("batch", strconv.FormatBool(batch))
+	watcher.SetFilter("format", format)
+	setSinceFilter(ctx, watcher.BaseSubscriber)
 	socket := &WebSocket{
 		ctx:     ctx,
 		conn:    conn,
-		watcher: NewListWatcher(serviceId, apt.GetInstanceRootKey(domainProject)+"/", f),
+		watcher: watcher,
+		batch:   batch,
+		format:  format,
 	}
 	process(socket)
 }
 
+func setSinceFilter(ctx context.Context, s *BaseSubscriber) {
+	if since, ok := ctx.Value(serviceUtil.CTX_SINCE_REVISION).(int64); ok {
+		s.SetFilter("since", strconv.FormatInt(since, 10))
+	}
+}
+
 func process(socket *WebSocket) {
 	if err := socket.Init(); err != nil {
 		return
@@ -138,3 +138,29 @@ func TestDoWebSocketListAndWatch(t *testing.T) {
 
 	publisher.Stop()
 }
+
+func TestCoalesce(t *testing.T) {
+	newJob := func(action, instanceId string) *WatchJob {
+		return NewWatchJob("g", "s", 1, &proto.WatchInstanceResponse{
+			Action:   action,
+			Key:      &proto.MicroServiceKey{AppId: "a", ServiceName: "s", Version: "1"},
+			Instance: &proto.MicroServiceInstance{ServiceId: "s", InstanceId: instanceId},
+		})
+	}
+	jobs := coalesce([]*WatchJob{
+		newJob(string(proto.EVT_DELETE), "1"),
+		newJob(string(proto.EVT_CREATE), "2"),
+		newJob(string(proto.EVT_CREATE), "1"),
+		newJob(string(proto.EVT_EXPIRE), ""),
+		newJob(string(proto.EVT_UPDATE), "2"),
+		newJob(string(proto.EVT_EXPIRE), ""),
+	})
+	if len(jobs) != 3 {
+		t.Fatalf("TestCoalesce failed, %v", jobs)
+	}
+	if jobs[0].Response.Action != string(proto.EVT_CREATE) || jobs[0].Response.Instance.InstanceId != "1" ||
+		jobs[1].Response.Action != string(proto.EVT_UPDATE) || jobs[1].Response.Instance.InstanceId != "2" ||
+		jobs[2].Response.Action != string(proto.EVT_EXPIRE) {
+		t.Fatalf("TestCoalesce failed, %v", jobs)
+	}
+}
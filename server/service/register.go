@@ -0,0 +1,348 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package service
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	apt "github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/core/backend"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/plugin"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/quota"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/uuid"
+	"github.com/apache/servicecomb-service-center/server/service/lease"
+	serviceUtil "github.com/apache/servicecomb-service-center/server/service/util"
+	"golang.org/x/net/context"
+	"strconv"
+	"time"
+)
+
+// the times to retry the transaction if the service is created or deleted
+// by others between the read of the service and the transaction
+const registerTxnRetries = 3
+
+// beforeRegisterTxn is called before each transaction of the registration,
+// the tests change the registry in it to simulate the concurrent writers
+var beforeRegisterTxn = func(ctx context.Context, attempt int) {}
+
+// RegisterServiceAndInstance creates the service if it is absent and
+// registers its instance in one transaction
+func (s *InstanceService) RegisterServiceAndInstance(ctx context.Context, in *pb.RegisterServiceAndInstanceRequest) (*pb.RegisterServiceAndInstanceResponse, error) {
+	remoteIP := util.GetIPFromContext(ctx)
+	if in == nil || in.Service == nil || in.Instance == nil {
+		log.WithContext(ctx).Errorf(nil, "register service and instance failed: request body is empty, operator %s", remoteIP)
+		return &pb.RegisterServiceAndInstanceResponse{
+			Response: pb.CreateResponse(scerr.ErrInvalidParams, "Request body is empty"),
+		}, nil
+	}
+
+	service, instance := in.Service, in.Instance
+	serviceFlag := util.StringJoin([]string{
+		service.Environment, service.AppId, service.ServiceName, service.Version}, "/")
+
+	serviceUtil.SetServiceDefaultValue(service)
+	if err := Validate(&pb.CreateServiceRequest{Service: service}); err != nil {
+		log.WithContext(ctx).Errorf(err, "register service[%s] and instance failed, operator %s", serviceFlag, remoteIP)
+		return &pb.RegisterServiceAndInstanceResponse{
+			Response: pb.CreateResponse(scerr.ErrInvalidParams, err.Error()),
+		}, nil
+	}
+
+	if err := checkServiceIdentity(ctx, service.ServiceName); err != nil {
+		log.WithContext(ctx).Errorf(err, "register service[%s] and instance failed, operator %s", serviceFlag, remoteIP)
+		return &pb.RegisterServiceAndInstanceResponse{
+			Response: pb.CreateResponseWithSCErr(err),
+		}, nil
+	}
+
+	domainProject := util.ParseDomainProject(ctx)
+	serviceKey := &pb.MicroServiceKey{
+		Tenant:      domainProject,
+		Environment: service.Environment,
+		AppId:       service.AppId,
+		ServiceName: service.ServiceName,
+		Alias:       service.Alias,
+		Version:     service.Version,
+	}
+	requestServiceId, requestInstanceId := service.ServiceId, instance.InstanceId
+	// the service is read from the registry to compare in the transaction
+	ctx = util.SetContext(util.CloneContext(ctx), serviceUtil.CTX_NOCACHE, "1")
+
+	var (
+		serviceReporter, instanceReporter *quota.ApplyQuotaResult
+		leaseID, oldLeaseID               int64
+		prepared                          bool
+		// decided is the service the conflict, lease and quota are
+		// decided for
+		decided string
+		// committed is whether the lease granted is attached to the
+		// instance, it is revoked otherwise
+		committed bool
+		// policy is the conflict policy applied if the instance exists
+		policy string
+	)
+	defer func() {
+		serviceReporter.Close(ctx)
+		instanceReporter.Close(ctx)
+		if leaseID == 0 || committed {
+			return
+		}
+		if err := backend.Registry().LeaseRevoke(ctx, leaseID); err != nil {
+			log.WithContext(ctx).Errorf(err, "revoke the unused lease[%d] of service[%s] failed", leaseID, serviceFlag)
+		}
+	}()
+	for i := 0; i < registerTxnRetries; i++ {
+		serviceId, err := serviceUtil.GetServiceId(ctx, serviceKey)
+		if err != nil {
+			log.WithContext(ctx).Errorf(err, "register service[%s] and instance failed, operator %s", serviceFlag, remoteIP)
+			return &pb.RegisterServiceAndInstanceResponse{
+				Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
+			}, err
+		}
+		create := len(serviceId) == 0
+		var existing *pb.MicroService
+		if !create {
+			if len(requestServiceId) > 0 && requestServiceId != serviceId {
+				log.WithContext(ctx).Warnf("register service[%s] and instance failed, service already exists, operator %s",
+					serviceFlag, remoteIP)
+				return &pb.RegisterServiceAndInstanceResponse{
+					Response: pb.CreateResponse(scerr.ErrServiceAlreadyExists,
+						"ServiceId conflict or found the same service with different id."),
+				}, nil
+			}
+			existing, err = serviceUtil.GetService(ctx, domainProject, serviceId)
+			if err != nil {
+				log.WithContext(ctx).Errorf(err, "register service[%s] and instance failed, operator %s", serviceFlag, remoteIP)
+				return &pb.RegisterServiceAndInstanceResponse{
+					Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
+				}, err
+			}
+			if existing == nil {
+				// the service is being deleted, create it again
+				continue
+			}
+			instance.Version = existing.Version
+		} else {
+			serviceId = requestServiceId
+			if len(serviceId) == 0 {
+				serviceId = plugin.Plugins().UUID().GetServiceId(
+					util.SetContext(util.CloneContext(ctx), uuid.ContextKey, apt.GenerateServiceIndexKey(serviceKey)))
+			}
+			instance.Version = service.Version
+		}
+		instance.ServiceId = serviceId
+
+		if !prepared {
+			if err := Validate(&pb.RegisterInstanceRequest{Instance: instance}); err != nil {
+				log.WithContext(ctx).Errorf(err, "register service[%s] and instance failed, operator %s", serviceFlag, remoteIP)
+				return &pb.RegisterServiceAndInstanceResponse{
+					Response: pb.CreateResponse(scerr.ErrInvalidParams, err.Error()),
+				}, nil
+			}
+			if err := preProcessInstance(ctx, instance); err != nil {
+				log.WithContext(ctx).Errorf(err, "register service[%s] and instance failed, operator %s", serviceFlag, remoteIP)
+				return &pb.RegisterServiceAndInstanceResponse{
+					Response: pb.CreateResponseWithSCErr(err),
+				}, nil
+			}
+			prepared = true
+		}
+
+		// the conflict, lease and quota are decided by the service, decide
+		// them again if another one is read in the retry
+		if target := decisionOf(serviceId, create); target != decided {
+			decided, policy, oldLeaseID = target, "", 0
+			serviceReporter.Close(ctx)
+			instanceReporter.Close(ctx)
+			serviceReporter, instanceReporter = nil, nil
+
+			if !create && len(requestInstanceId) > 0 {
+				oldInstanceId, checkErr := serviceUtil.InstanceExist(ctx, instance)
+				if checkErr != nil {
+					log.WithContext(ctx).Errorf(checkErr, "register service[%s] and instance failed, operator %s",
+						serviceFlag, remoteIP)
+					resp := &pb.RegisterServiceAndInstanceResponse{Response: pb.CreateResponseWithSCErr(checkErr)}
+					if checkErr.InternalError() {
+						return resp, checkErr
+					}
+					return resp, nil
+				}
+				if len(oldInstanceId) > 0 {
+					policy = serviceUtil.InstanceConflictPolicy(existing)
+					switch policy {
+					case pb.INSTANCE_CONFLICT_REJECT:
+						log.WithContext(ctx).Warnf("register service[%s] and instance failed, instance[%s/%s] already exists, operator %s",
+							serviceFlag, serviceId, oldInstanceId, remoteIP)
+						return &pb.RegisterServiceAndInstanceResponse{
+							Response:       pb.CreateResponse(scerr.ErrInstanceAlreadyExists, "Instance already exists."),
+							ServiceId:      serviceId,
+							ConflictPolicy: policy,
+						}, nil
+					case pb.INSTANCE_CONFLICT_REUSE:
+						log.WithContext(ctx).Infof("register service[%s] and instance successful, reuse instance[%s/%s], operator %s",
+							serviceFlag, serviceId, oldInstanceId, remoteIP)
+						return &pb.RegisterServiceAndInstanceResponse{
+							Response:       pb.CreateResponse(pb.Response_SUCCESS, "instance already exists"),
+							ServiceId:      serviceId,
+							InstanceId:     oldInstanceId,
+							ConflictPolicy: policy,
+						}, nil
+					}
+					// replace the existing instance with the new metadata and lease
+					oldLeaseID, err = serviceUtil.GetLeaseId(ctx, domainProject, serviceId, oldInstanceId)
+					if err != nil {
+						log.WithContext(ctx).Errorf(err, "register service[%s] and instance failed, operator %s",
+							serviceFlag, remoteIP)
+						return &pb.RegisterServiceAndInstanceResponse{
+							Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
+						}, err
+					}
+				}
+			}
+
+			if create {
+				serviceReporter = checkQuota(ctx, domainProject)
+				if serviceReporter != nil && serviceReporter.Err != nil {
+					return registerQuotaFailed(ctx, serviceReporter.Err, serviceFlag, remoteIP)
+				}
+			}
+			// the replacement does not increase the instances
+			if !apt.IsSCInstance(ctx) && len(policy) == 0 {
+				res := quota.NewApplyQuotaResource(quota.MicroServiceInstanceQuotaType, domainProject, serviceId, 1)
+				instanceReporter = plugin.Plugins().Quota().Apply4Quotas(ctx, res)
+				if instanceReporter.Err != nil {
+					return registerQuotaFailed(ctx, instanceReporter.Err, serviceFlag, remoteIP)
+				}
+			}
+		}
+
+		if leaseID == 0 {
+			ttl := int64(instance.HealthCheck.Interval * (instance.HealthCheck.Times + 1))
+			leaseID, err = backend.Registry().LeaseGrant(ctx, ttl)
+			lease.ReportGrant(domainProject, serviceId, err)
+			if err != nil {
+				log.WithContext(ctx).Errorf(err, "register service[%s] and instance failed, grant lease failed, operator %s",
+					serviceFlag, remoteIP)
+				return &pb.RegisterServiceAndInstanceResponse{
+					Response: pb.CreateResponse(scerr.ErrUnavailableBackend, err.Error()),
+				}, err
+			}
+		}
+
+		instanceData, err := util.JsonMarshal(instance.WithoutRevision())
+		if err != nil {
+			log.WithContext(ctx).Errorf(err, "register service[%s] and instance failed, operator %s", serviceFlag, remoteIP)
+			return &pb.RegisterServiceAndInstanceResponse{
+				Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
+			}, err
+		}
+		opts := append(registerInstanceOps(domainProject, instance, instanceData, leaseID),
+			serviceUtil.TouchRevisionOps(domainProject)...)
+		var (
+			cmps    []registry.CompareOp
+			failOps []registry.PluginOp
+		)
+		if create {
+			service.ServiceId = serviceId
+			service.Timestamp = strconv.FormatInt(time.Now().Unix(), 10)
+			service.ModTimestamp = service.Timestamp
+			serviceData, err := util.JsonMarshal(service.WithoutRevision())
+			if err != nil {
+				log.WithContext(ctx).Errorf(err, "register service[%s] and instance failed, operator %s", serviceFlag, remoteIP)
+				return &pb.RegisterServiceAndInstanceResponse{
+					Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
+				}, err
+			}
+			var serviceOpts []registry.PluginOp
+			serviceOpts, cmps, failOps = createServiceOps(domainProject, serviceKey, serviceId, serviceData)
+			opts = append(serviceOpts, opts...)
+		} else {
+			cmps = []registry.CompareOp{registry.OpCmp(
+				registry.CmpVer(util.StringToBytesWithNoCopy(apt.GenerateServiceKey(domainProject, serviceId))),
+				registry.CMP_NOT_EQUAL, 0)}
+		}
+
+		beforeRegisterTxn(ctx, i)
+		resp, err := backend.Registry().TxnWithCmp(ctx, opts, cmps, failOps)
+		if err != nil {
+			log.WithContext(ctx).Errorf(err, "register service[%s] and instance failed, operator %s", serviceFlag, remoteIP)
+			return &pb.RegisterServiceAndInstanceResponse{
+				Response: pb.CreateResponse(scerr.ErrUnavailableBackend, err.Error()),
+			}, err
+		}
+		if !resp.Succeeded {
+			if create && len(requestServiceId) > 0 &&
+				(len(resp.Kvs) == 0 || requestServiceId != util.BytesToStringWithNoCopy(resp.Kvs[0].Value)) {
+				log.WithContext(ctx).Warnf("register service[%s] and instance failed, service already exists, operator %s",
+					serviceFlag, remoteIP)
+				return &pb.RegisterServiceAndInstanceResponse{
+					Response: pb.CreateResponse(scerr.ErrServiceAlreadyExists,
+						"ServiceId conflict or found the same service with different id."),
+				}, nil
+			}
+			log.WithContext(ctx).Warnf("register service[%s] and instance conflicted, the service is changed, retry %d",
+				serviceFlag, i+1)
+			continue
+		}
+		committed = true
+		if len(policy) > 0 {
+			releaseReplacedLease(ctx, domainProject, serviceId, instance.InstanceId, oldLeaseID)
+		}
+
+		if create {
+			if err := serviceReporter.ReportUsedQuota(ctx); err != nil {
+				log.WithContext(ctx).Errorf(err, "report the used quota failed")
+			}
+		}
+		if err := instanceReporter.ReportUsedQuota(ctx); err != nil {
+			log.WithContext(ctx).Errorf(err, "report the used quota failed")
+		}
+
+		log.WithContext(ctx).Infof("register service[%s][%s] and instance[%s] successfully, created %v, operator %s",
+			serviceId, serviceFlag, instance.InstanceId, create, remoteIP)
+		return &pb.RegisterServiceAndInstanceResponse{
+			Response:       pb.CreateResponse(pb.Response_SUCCESS, "Register service and instance successfully."),
+			ServiceId:      serviceId,
+			InstanceId:     instance.InstanceId,
+			ConflictPolicy: policy,
+		}, nil
+	}
+
+	log.WithContext(ctx).Errorf(nil, "register service[%s] and instance failed, the service is changed concurrently, operator %s",
+		serviceFlag, remoteIP)
+	return &pb.RegisterServiceAndInstanceResponse{
+		Response: pb.CreateResponse(scerr.ErrRevisionConflict, "The service is created or deleted concurrently."),
+	}, nil
+}
+
+func decisionOf(serviceId string, create bool) string {
+	return serviceId + "/" + strconv.FormatBool(create)
+}
+
+func registerQuotaFailed(ctx context.Context, err *scerr.Error, serviceFlag, remoteIP string) (*pb.RegisterServiceAndInstanceResponse, error) {
+	log.WithContext(ctx).Errorf(err, "register service[%s] and instance failed, operator %s", serviceFlag, remoteIP)
+	resp := &pb.RegisterServiceAndInstanceResponse{
+		Response: pb.CreateResponseWithSCErr(err),
+	}
+	if err.InternalError() {
+		return resp, err
+	}
+	return resp, nil
+}
@@ -37,7 +37,7 @@ func (s *MicroServiceService) AddRule(ctx context.Context, in *pb.AddServiceRule
 	remoteIP := util.GetIPFromContext(ctx)
 	err := Validate(in)
 	if err != nil {
-		log.Errorf(err, "add service[%s] rule failed, operator: %s", in.ServiceId, remoteIP)
+		log.WithContext(ctx).Errorf(err, "add service[%s] rule failed, operator: %s", in.ServiceId, remoteIP)
 		return &pb.AddServiceRulesResponse{
 			Response: pb.CreateResponse(scerr.ErrInvalidParams, err.Error()),
 		}, nil
@@ -47,7 +47,7 @@ func (s *MicroServiceService) AddRule(ctx context.Context, in *pb.AddServiceRule
 
 	// service id存在性校验
 	if !serviceUtil.ServiceExist(ctx, domainProject, in.ServiceId) {
-		log.Errorf(nil, "add service[%s] rule failed, service does not exist, operator: %s",
+		log.WithContext(ctx).Errorf(nil, "add service[%s] rule failed, service does not exist, operator: %s",
 			in.ServiceId, remoteIP)
 		return &pb.AddServiceRulesResponse{
 			Response: pb.CreateResponse(scerr.ErrInvalidParams, "Service does not exist."),
@@ -57,7 +57,7 @@ func (s *MicroServiceService) AddRule(ctx context.Context, in *pb.AddServiceRule
 	rst := plugin.Plugins().Quota().Apply4Quotas(ctx, res)
 	errQuota := rst.Err
 	if errQuota != nil {
-		log.Errorf(errQuota, "add service[%s] rule failed, operator: %s", in.ServiceId, remoteIP)
+		log.WithContext(ctx).Errorf(errQuota, "add service[%s] rule failed, operator: %s", in.ServiceId, remoteIP)
 		response := &pb.AddServiceRulesResponse{
 			Response: pb.CreateResponseWithSCErr(errQuota),
 		}
@@ -80,7 +80,7 @@ func (s *MicroServiceService) AddRule(ctx context.Context, in *pb.AddServiceRule
 		if len(ruleType) == 0 {
 			ruleType = rule.RuleType
 		} else if ruleType != rule.RuleType {
-			log.Errorf(nil, "add service[%s] rule failed, can not add different RuleType at the same time, operator: %s",
+			log.WithContext(ctx).Errorf(nil, "add service[%s] rule failed, can not add different RuleType at the same time, operator: %s",
 				in.ServiceId, remoteIP)
 			return &pb.AddServiceRulesResponse{
 				Response: pb.CreateResponse(scerr.ErrBlackAndWhiteRule, "Service can only contain one rule type, BLACK or WHITE."),
@@ -90,7 +90,7 @@ func (s *MicroServiceService) AddRule(ctx context.Context, in *pb.AddServiceRule
 
 		//同一服务，attribute和pattern确定一个rule
 		if serviceUtil.RuleExist(ctx, domainProject, in.ServiceId, rule.Attribute, rule.Pattern) {
-			log.Infof("service[%s] rule[%s/%s] already exists, operator: %s",
+			log.WithContext(ctx).Infof("service[%s] rule[%s/%s] already exists, operator: %s",
 				in.ServiceId, rule.Attribute, rule.Pattern, remoteIP)
 			continue
 		}
@@ -113,7 +113,7 @@ func (s *MicroServiceService) AddRule(ctx context.Context, in *pb.AddServiceRule
 
 		data, err := json.Marshal(ruleAdd)
 		if err != nil {
-			log.Errorf(err, "add service[%s] rule failed, marshal rule[%s/%s] failed, operator: %s",
+			log.WithContext(ctx).Errorf(err, "add service[%s] rule failed, marshal rule[%s/%s] failed, operator: %s",
 				in.ServiceId, ruleAdd.Attribute, ruleAdd.Pattern, remoteIP)
 			return &pb.AddServiceRulesResponse{
 				Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
@@ -124,7 +124,7 @@ func (s *MicroServiceService) AddRule(ctx context.Context, in *pb.AddServiceRule
 		opts = append(opts, registry.OpPut(registry.WithStrKey(indexKey), registry.WithStrValue(ruleAdd.RuleId)))
 	}
 	if len(opts) <= 0 {
-		log.Infof("add service[%s] rule successfully, no rules to add, operator: %s",
+		log.WithContext(ctx).Infof("add service[%s] rule successfully, no rules to add, operator: %s",
 			in.ServiceId, remoteIP)
 		return &pb.AddServiceRulesResponse{
 			Response: pb.CreateResponse(pb.Response_SUCCESS, "Service rules has been added."),
@@ -137,20 +137,20 @@ func (s *MicroServiceService) AddRule(ctx context.Context, in *pb.AddServiceRule
 			registry.CMP_NOT_EQUAL, 0)},
 		nil)
 	if err != nil {
-		log.Errorf(err, "add service[%s] rule failed, operator: %s", in.ServiceId, remoteIP)
+		log.WithContext(ctx).Errorf(err, "add service[%s] rule failed, operator: %s", in.ServiceId, remoteIP)
 		return &pb.AddServiceRulesResponse{
 			Response: pb.CreateResponse(scerr.ErrUnavailableBackend, err.Error()),
 		}, err
 	}
 	if !resp.Succeeded {
-		log.Errorf(nil, "add service[%s] rule failed, service does not exist, operator: %s",
+		log.WithContext(ctx).Errorf(nil, "add service[%s] rule failed, service does not exist, operator: %s",
 			in.ServiceId, remoteIP)
 		return &pb.AddServiceRulesResponse{
 			Response: pb.CreateResponse(scerr.ErrServiceNotExists, "Service does not exist."),
 		}, nil
 	}
 
-	log.Infof("add service[%s] rule %v successfully, operator: %s", in.ServiceId, ruleIds, remoteIP)
+	log.WithContext(ctx).Infof("add service[%s] rule %v successfully, operator: %s", in.ServiceId, ruleIds, remoteIP)
 	return &pb.AddServiceRulesResponse{
 		Response: pb.CreateResponse(pb.Response_SUCCESS, "Add service rules successfully."),
 		RuleIds:  ruleIds,
@@ -161,7 +161,7 @@ func (s *MicroServiceService) UpdateRule(ctx context.Context, in *pb.UpdateServi
 	remoteIP := util.GetIPFromContext(ctx)
 	err := Validate(in)
 	if err != nil {
-		log.Errorf(err, "update service rule[%s/%s] failed, operator: %s", in.ServiceId, in.RuleId, remoteIP)
+		log.WithContext(ctx).Errorf(err, "update service rule[%s/%s] failed, operator: %s", in.ServiceId, in.RuleId, remoteIP)
 		return &pb.UpdateServiceRuleResponse{
 			Response: pb.CreateResponse(scerr.ErrInvalidParams, err.Error()),
 		}, nil
@@ -171,7 +171,7 @@ func (s *MicroServiceService) UpdateRule(ctx context.Context, in *pb.UpdateServi
 
 	// service id存在性校验
 	if !serviceUtil.ServiceExist(ctx, domainProject, in.ServiceId) {
-		log.Errorf(nil, "update service rule[%s/%s] failed, service does not exist, operator: %s",
+		log.WithContext(ctx).Errorf(nil, "update service rule[%s/%s] failed, service does not exist, operator: %s",
 			in.ServiceId, in.RuleId, remoteIP)
 		return &pb.UpdateServiceRuleResponse{
 			Response: pb.CreateResponse(scerr.ErrServiceNotExists, "Service does not exist."),
@@ -181,14 +181,14 @@ func (s *MicroServiceService) UpdateRule(ctx context.Context, in *pb.UpdateServi
 	//是否能改变ruleType
 	ruleType, ruleNum, err := serviceUtil.GetServiceRuleType(ctx, domainProject, in.ServiceId)
 	if err != nil {
-		log.Errorf(err, "update service rule[%s/%s] failed, get rule type failed, operator: %s",
+		log.WithContext(ctx).Errorf(err, "update service rule[%s/%s] failed, get rule type failed, operator: %s",
 			in.ServiceId, in.RuleId, remoteIP)
 		return &pb.UpdateServiceRuleResponse{
 			Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
 		}, err
 	}
 	if ruleNum >= 1 && ruleType != in.Rule.RuleType {
-		log.Errorf(err, "update service rule[%s/%s] failed, can only exist one type, current type is %s, operator: %s",
+		log.WithContext(ctx).Errorf(err, "update service rule[%s/%s] failed, can only exist one type, current type is %s, operator: %s",
 			in.ServiceId, in.RuleId, ruleType, remoteIP)
 		return &pb.UpdateServiceRuleResponse{
 			Response: pb.CreateResponse(scerr.ErrModifyRuleNotAllow, "Exist multiple rules,can not change rule type. Rule type is "+ruleType),
@@ -197,14 +197,14 @@ func (s *MicroServiceService) UpdateRule(ctx context.Context, in *pb.UpdateServi
 
 	rule, err := serviceUtil.GetOneRule(ctx, domainProject, in.ServiceId, in.RuleId)
 	if err != nil {
-		log.Errorf(err, "update service rule[%s/%s] failed, query service rule failed, operator: %s",
+		log.WithContext(ctx).Errorf(err, "update service rule[%s/%s] failed, query service rule failed, operator: %s",
 			in.ServiceId, in.RuleId, remoteIP)
 		return &pb.UpdateServiceRuleResponse{
 			Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
 		}, err
 	}
 	if rule == nil {
-		log.Errorf(err, "update service rule[%s/%s] failed, service rule does not exist, operator: %s",
+		log.WithContext(ctx).Errorf(err, "update service rule[%s/%s] failed, service rule does not exist, operator: %s",
 			in.ServiceId, in.RuleId, remoteIP)
 		return &pb.UpdateServiceRuleResponse{
 			Response: pb.CreateResponse(scerr.ErrRuleNotExists, "This rule does not exist."),
@@ -230,7 +230,7 @@ func (s *MicroServiceService) UpdateRule(ctx context.Context, in *pb.UpdateServi
 	key := apt.GenerateServiceRuleKey(domainProject, in.ServiceId, in.RuleId)
 	data, err := json.Marshal(copyRuleRef)
 	if err != nil {
-		log.Errorf(err, "update service rule[%s/%s] failed, marshal service rule failed, operator: %s",
+		log.WithContext(ctx).Errorf(err, "update service rule[%s/%s] failed, marshal service rule failed, operator: %s",
 			in.ServiceId, in.RuleId, remoteIP)
 		return &pb.UpdateServiceRuleResponse{
 			Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
@@ -254,20 +254,20 @@ func (s *MicroServiceService) UpdateRule(ctx context.Context, in *pb.UpdateServi
 			registry.CMP_NOT_EQUAL, 0)},
 		nil)
 	if err != nil {
-		log.Errorf(err, "update service rule[%s/%s] failed, operator: %s", in.ServiceId, in.RuleId, remoteIP)
+		log.WithContext(ctx).Errorf(err, "update service rule[%s/%s] failed, operator: %s", in.ServiceId, in.RuleId, remoteIP)
 		return &pb.UpdateServiceRuleResponse{
 			Response: pb.CreateResponse(scerr.ErrUnavailableBackend, err.Error()),
 		}, err
 	}
 	if !resp.Succeeded {
-		log.Errorf(err, "update service rule[%s/%s] failed, service does not exist, operator: %s",
+		log.WithContext(ctx).Errorf(err, "update service rule[%s/%s] failed, service does not exist, operator: %s",
 			in.ServiceId, in.RuleId, remoteIP)
 		return &pb.UpdateServiceRuleResponse{
 			Response: pb.CreateResponse(scerr.ErrServiceNotExists, "Service does not exist."),
 		}, nil
 	}
 
-	log.Infof("update service rule[%s/%s] successfully, operator: %s", in.ServiceId, in.RuleId, remoteIP)
+	log.WithContext(ctx).Infof("update service rule[%s/%s] successfully, operator: %s", in.ServiceId, in.RuleId, remoteIP)
 	return &pb.UpdateServiceRuleResponse{
 		Response: pb.CreateResponse(pb.Response_SUCCESS, "Get service rules successfully."),
 	}, nil
@@ -276,7 +276,7 @@ func (s *MicroServiceService) UpdateRule(ctx context.Context, in *pb.UpdateServi
 func (s *MicroServiceService) GetRule(ctx context.Context, in *pb.GetServiceRulesRequest) (*pb.GetServiceRulesResponse, error) {
 	err := Validate(in)
 	if err != nil {
-		log.Errorf(err, "get service[%s] rule failed", in.ServiceId)
+		log.WithContext(ctx).Errorf(err, "get service[%s] rule failed", in.ServiceId)
 		return &pb.GetServiceRulesResponse{
 			Response: pb.CreateResponse(scerr.ErrInvalidParams, err.Error()),
 		}, nil
@@ -286,7 +286,7 @@ func (s *MicroServiceService) GetRule(ctx context.Context, in *pb.GetServiceRule
 
 	// service id存在性校验
 	if !serviceUtil.ServiceExist(ctx, domainProject, in.ServiceId) {
-		log.Errorf(nil, "get service[%s] rule failed, service does not exist", in.ServiceId)
+		log.WithContext(ctx).Errorf(nil, "get service[%s] rule failed, service does not exist", in.ServiceId)
 		return &pb.GetServiceRulesResponse{
 			Response: pb.CreateResponse(scerr.ErrServiceNotExists, "Service does not exist."),
 		}, nil
@@ -294,7 +294,7 @@ func (s *MicroServiceService) GetRule(ctx context.Context, in *pb.GetServiceRule
 
 	rules, err := serviceUtil.GetRulesUtil(ctx, domainProject, in.ServiceId)
 	if err != nil {
-		log.Errorf(err, "get service[%s] rule failed", in.ServiceId)
+		log.WithContext(ctx).Errorf(err, "get service[%s] rule failed", in.ServiceId)
 		return &pb.GetServiceRulesResponse{
 			Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
 		}, err
@@ -310,7 +310,7 @@ func (s *MicroServiceService) DeleteRule(ctx context.Context, in *pb.DeleteServi
 	remoteIP := util.GetIPFromContext(ctx)
 	err := Validate(in)
 	if err != nil {
-		log.Errorf(err, "delete service[%s] rules %v failed, operator: %s", in.ServiceId, in.RuleIds, remoteIP)
+		log.WithContext(ctx).Errorf(err, "delete service[%s] rules %v failed, operator: %s", in.ServiceId, in.RuleIds, remoteIP)
 		return &pb.DeleteServiceRulesResponse{
 			Response: pb.CreateResponse(scerr.ErrInvalidParams, err.Error()),
 		}, nil
@@ -320,7 +320,7 @@ func (s *MicroServiceService) DeleteRule(ctx context.Context, in *pb.DeleteServi
 
 	// service id存在性校验
 	if !serviceUtil.ServiceExist(ctx, domainProject, in.ServiceId) {
-		log.Errorf(nil, "delete service[%s] rules %v failed, service does not exist, operator: %s",
+		log.WithContext(ctx).Errorf(nil, "delete service[%s] rules %v failed, service does not exist, operator: %s",
 			in.ServiceId, in.RuleIds, remoteIP)
 		return &pb.DeleteServiceRulesResponse{
 			Response: pb.CreateResponse(scerr.ErrServiceNotExists, "Service does not exist."),
@@ -332,17 +332,17 @@ func (s *MicroServiceService) DeleteRule(ctx context.Context, in *pb.DeleteServi
 	indexKey := ""
 	for _, ruleId := range in.RuleIds {
 		key = apt.GenerateServiceRuleKey(domainProject, in.ServiceId, ruleId)
-		log.Debugf("start delete service rule file: %s", key)
+		log.WithContext(ctx).Debugf("start delete service rule file: %s", key)
 		data, err := serviceUtil.GetOneRule(ctx, domainProject, in.ServiceId, ruleId)
 		if err != nil {
-			log.Errorf(err, "delete service[%s] rules %v failed, get rule[%s] failed, operator: %s",
+			log.WithContext(ctx).Errorf(err, "delete service[%s] rules %v failed, get rule[%s] failed, operator: %s",
 				in.ServiceId, in.RuleIds, ruleId, remoteIP)
 			return &pb.DeleteServiceRulesResponse{
 				Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
 			}, err
 		}
 		if data == nil {
-			log.Errorf(nil, "delete service[%s] rules %v failed, rule[%s] does not exist, operator: %s",
+			log.WithContext(ctx).Errorf(nil, "delete service[%s] rules %v failed, rule[%s] does not exist, operator: %s",
 				in.ServiceId, in.RuleIds, ruleId, remoteIP)
 			return &pb.DeleteServiceRulesResponse{
 				Response: pb.CreateResponse(scerr.ErrRuleNotExists, "This rule does not exist."),
@@ -354,7 +354,7 @@ func (s *MicroServiceService) DeleteRule(ctx context.Context, in *pb.DeleteServi
 			registry.OpDel(registry.WithStrKey(indexKey)))
 	}
 	if len(opts) <= 0 {
-		log.Errorf(nil, "delete service[%s] rules %v failed, no rule has been deleted, operator: %s",
+		log.WithContext(ctx).Errorf(nil, "delete service[%s] rules %v failed, no rule has been deleted, operator: %s",
 			in.ServiceId, in.RuleIds, remoteIP)
 		return &pb.DeleteServiceRulesResponse{
 			Response: pb.CreateResponse(scerr.ErrRuleNotExists, "No service rule has been deleted."),
@@ -367,20 +367,20 @@ func (s *MicroServiceService) DeleteRule(ctx context.Context, in *pb.DeleteServi
 			registry.CMP_NOT_EQUAL, 0)},
 		nil)
 	if err != nil {
-		log.Errorf(err, "delete service[%s] rules %v failed, operator: %s", in.ServiceId, in.RuleIds, remoteIP)
+		log.WithContext(ctx).Errorf(err, "delete service[%s] rules %v failed, operator: %s", in.ServiceId, in.RuleIds, remoteIP)
 		return &pb.DeleteServiceRulesResponse{
 			Response: pb.CreateResponse(scerr.ErrUnavailableBackend, err.Error()),
 		}, err
 	}
 	if !resp.Succeeded {
-		log.Errorf(err, "delete service[%s] rules %v failed, service does not exist, operator: %s",
+		log.WithContext(ctx).Errorf(err, "delete service[%s] rules %v failed, service does not exist, operator: %s",
 			in.ServiceId, in.RuleIds, remoteIP)
 		return &pb.DeleteServiceRulesResponse{
 			Response: pb.CreateResponse(scerr.ErrServiceNotExists, "Service does not exist."),
 		}, nil
 	}
 
-	log.Infof("delete service[%s] rules %v successfully, operator: %s", in.ServiceId, in.RuleIds, remoteIP)
+	log.WithContext(ctx).Infof("delete service[%s] rules %v successfully, operator: %s", in.ServiceId, in.RuleIds, remoteIP)
 	return &pb.DeleteServiceRulesResponse{
 		Response: pb.CreateResponse(pb.Response_SUCCESS, "Delete service rules successfully."),
 	}, nil
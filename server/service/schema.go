@@ -36,7 +36,7 @@ import (
 func (s *MicroServiceService) GetSchemaInfo(ctx context.Context, in *pb.GetSchemaRequest) (*pb.GetSchemaResponse, error) {
 	err := Validate(in)
 	if err != nil {
-		log.Errorf(nil, "get schema[%s/%s] failed", in.ServiceId, in.SchemaId)
+		log.WithContext(ctx).Errorf(nil, "get schema[%s/%s] failed", in.ServiceId, in.SchemaId)
 		return &pb.GetSchemaResponse{
 			Response: pb.CreateResponse(scerr.ErrInvalidParams, err.Error()),
 		}, nil
@@ -45,7 +45,7 @@ func (s *MicroServiceService) GetSchemaInfo(ctx context.Context, in *pb.GetSchem
 	domainProject := util.ParseDomainProject(ctx)
 
 	if !serviceUtil.ServiceExist(ctx, domainProject, in.ServiceId) {
-		log.Errorf(nil, "get schema[%s/%s] failed, service does not exist", in.ServiceId, in.SchemaId)
+		log.WithContext(ctx).Errorf(nil, "get schema[%s/%s] failed, service does not exist", in.ServiceId, in.SchemaId)
 		return &pb.GetSchemaResponse{
 			Response: pb.CreateResponse(scerr.ErrServiceNotExists, "Service does not exist."),
 		}, nil
@@ -55,13 +55,13 @@ func (s *MicroServiceService) GetSchemaInfo(ctx context.Context, in *pb.GetSchem
 	opts := append(serviceUtil.FromContext(ctx), registry.WithStrKey(key))
 	resp, errDo := backend.Store().Schema().Search(ctx, opts...)
 	if errDo != nil {
-		log.Errorf(errDo, "get schema[%s/%s] failed", in.ServiceId, in.SchemaId)
+		log.WithContext(ctx).Errorf(errDo, "get schema[%s/%s] failed", in.ServiceId, in.SchemaId)
 		return &pb.GetSchemaResponse{
 			Response: pb.CreateResponse(scerr.ErrUnavailableBackend, errDo.Error()),
 		}, errDo
 	}
 	if resp.Count == 0 {
-		log.Errorf(errDo, "get schema[%s/%s] failed, schema does not exists", in.ServiceId, in.SchemaId)
+		log.WithContext(ctx).Errorf(errDo, "get schema[%s/%s] failed, schema does not exists", in.ServiceId, in.SchemaId)
 		return &pb.GetSchemaResponse{
 			Response: pb.CreateResponse(scerr.ErrSchemaNotExists, "Do not have this schema info."),
 		}, nil
@@ -69,7 +69,7 @@ func (s *MicroServiceService) GetSchemaInfo(ctx context.Context, in *pb.GetSchem
 
 	schemaSummary, err := getSchemaSummary(ctx, domainProject, in.ServiceId, in.SchemaId)
 	if err != nil {
-		log.Errorf(err, "get schema[%s/%s] failed, get schema summary failed", in.ServiceId, in.SchemaId)
+		log.WithContext(ctx).Errorf(err, "get schema[%s/%s] failed, get schema summary failed", in.ServiceId, in.SchemaId)
 		return &pb.GetSchemaResponse{
 			Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
 		}, err
@@ -85,7 +85,7 @@ func (s *MicroServiceService) GetSchemaInfo(ctx context.Context, in *pb.GetSchem
 func (s *MicroServiceService) GetAllSchemaInfo(ctx context.Context, in *pb.GetAllSchemaRequest) (*pb.GetAllSchemaResponse, error) {
 	err := Validate(in)
 	if err != nil {
-		log.Errorf(nil, "get service[%s] all schemas failed", in.ServiceId)
+		log.WithContext(ctx).Errorf(nil, "get service[%s] all schemas failed", in.ServiceId)
 		return &pb.GetAllSchemaResponse{
 			Response: pb.CreateResponse(scerr.ErrInvalidParams, err.Error()),
 		}, nil
@@ -95,13 +95,13 @@ func (s *MicroServiceService) GetAllSchemaInfo(ctx context.Context, in *pb.GetAl
 
 	service, err := serviceUtil.GetService(ctx, domainProject, in.ServiceId)
 	if err != nil {
-		log.Errorf(err, "get service[%s] all schemas failed, get service failed", in.ServiceId)
+		log.WithContext(ctx).Errorf(err, "get service[%s] all schemas failed, get service failed", in.ServiceId)
 		return &pb.GetAllSchemaResponse{
 			Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
 		}, err
 	}
 	if service == nil {
-		log.Errorf(nil, "get service[%s] all schemas failed, service does not exist", in.ServiceId)
+		log.WithContext(ctx).Errorf(nil, "get service[%s] all schemas failed, service does not exist", in.ServiceId)
 		return &pb.GetAllSchemaResponse{
 			Response: pb.CreateResponse(scerr.ErrServiceNotExists, "Service does not exist."),
 		}, nil
@@ -119,7 +119,7 @@ func (s *MicroServiceService) GetAllSchemaInfo(ctx context.Context, in *pb.GetAl
 	opts := append(serviceUtil.FromContext(ctx), registry.WithStrKey(key), registry.WithPrefix())
 	resp, errDo := backend.Store().SchemaSummary().Search(ctx, opts...)
 	if errDo != nil {
-		log.Errorf(errDo, "get service[%s] all schema summaries failed", in.ServiceId)
+		log.WithContext(ctx).Errorf(errDo, "get service[%s] all schema summaries failed", in.ServiceId)
 		return &pb.GetAllSchemaResponse{
 			Response: pb.CreateResponse(scerr.ErrUnavailableBackend, errDo.Error()),
 		}, errDo
@@ -131,7 +131,7 @@ func (s *MicroServiceService) GetAllSchemaInfo(ctx context.Context, in *pb.GetAl
 		opts := append(serviceUtil.FromContext(ctx), registry.WithStrKey(key), registry.WithPrefix())
 		respWithSchema, errDo = backend.Store().Schema().Search(ctx, opts...)
 		if errDo != nil {
-			log.Errorf(errDo, "get service[%s] all schemas failed", in.ServiceId)
+			log.WithContext(ctx).Errorf(errDo, "get service[%s] all schemas failed", in.ServiceId)
 			return &pb.GetAllSchemaResponse{
 				Response: pb.CreateResponse(scerr.ErrUnavailableBackend, errDo.Error()),
 			}, errDo
@@ -169,7 +169,7 @@ func (s *MicroServiceService) DeleteSchema(ctx context.Context, in *pb.DeleteSch
 	remoteIP := util.GetIPFromContext(ctx)
 	err := Validate(in)
 	if err != nil {
-		log.Errorf(err, "delete schema[%s/%s] failed, operator: %s", in.ServiceId, in.SchemaId, remoteIP)
+		log.WithContext(ctx).Errorf(err, "delete schema[%s/%s] failed, operator: %s", in.ServiceId, in.SchemaId, remoteIP)
 		return &pb.DeleteSchemaResponse{
 			Response: pb.CreateResponse(scerr.ErrInvalidParams, err.Error()),
 		}, nil
@@ -177,7 +177,7 @@ func (s *MicroServiceService) DeleteSchema(ctx context.Context, in *pb.DeleteSch
 	domainProject := util.ParseDomainProject(ctx)
 
 	if !serviceUtil.ServiceExist(ctx, domainProject, in.ServiceId) {
-		log.Errorf(nil, "delete schema[%s/%s] failed, service does not exist, operator: %s",
+		log.WithContext(ctx).Errorf(nil, "delete schema[%s/%s] failed, service does not exist, operator: %s",
 			in.ServiceId, in.SchemaId, remoteIP)
 		return &pb.DeleteSchemaResponse{
 			Response: pb.CreateResponse(scerr.ErrServiceNotExists, "Service does not exist."),
@@ -187,13 +187,13 @@ func (s *MicroServiceService) DeleteSchema(ctx context.Context, in *pb.DeleteSch
 	key := apt.GenerateServiceSchemaKey(domainProject, in.ServiceId, in.SchemaId)
 	exist, err := serviceUtil.CheckSchemaInfoExist(ctx, key)
 	if err != nil {
-		log.Errorf(err, "delete schema[%s/%s] failed, operator: %s", in.ServiceId, in.SchemaId, remoteIP)
+		log.WithContext(ctx).Errorf(err, "delete schema[%s/%s] failed, operator: %s", in.ServiceId, in.SchemaId, remoteIP)
 		return &pb.DeleteSchemaResponse{
 			Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
 		}, err
 	}
 	if !exist {
-		log.Errorf(nil, "delete schema[%s/%s] failed, schema does not exist, operator: %s",
+		log.WithContext(ctx).Errorf(nil, "delete schema[%s/%s] failed, schema does not exist, operator: %s",
 			in.ServiceId, in.SchemaId, remoteIP)
 		return &pb.DeleteSchemaResponse{
 			Response: pb.CreateResponse(scerr.ErrSchemaNotExists, "Schema info does not exist."),
@@ -211,20 +211,20 @@ func (s *MicroServiceService) DeleteSchema(ctx context.Context, in *pb.DeleteSch
 			registry.CMP_NOT_EQUAL, 0)},
 		nil)
 	if errDo != nil {
-		log.Errorf(errDo, "delete schema[%s/%s] failed, operator: %s", in.ServiceId, in.SchemaId, remoteIP)
+		log.WithContext(ctx).Errorf(errDo, "delete schema[%s/%s] failed, operator: %s", in.ServiceId, in.SchemaId, remoteIP)
 		return &pb.DeleteSchemaResponse{
 			Response: pb.CreateResponse(scerr.ErrUnavailableBackend, errDo.Error()),
 		}, errDo
 	}
 	if !resp.Succeeded {
-		log.Errorf(nil, "delete schema[%s/%s] failed, service does not exist, operator: %s",
+		log.WithContext(ctx).Errorf(nil, "delete schema[%s/%s] failed, service does not exist, operator: %s",
 			in.ServiceId, in.SchemaId, remoteIP)
 		return &pb.DeleteSchemaResponse{
 			Response: pb.CreateResponse(scerr.ErrServiceNotExists, "Service does not exist."),
 		}, nil
 	}
 
-	log.Infof("delete schema[%s/%s] info successfully, operator: %s", in.ServiceId, in.SchemaId, remoteIP)
+	log.WithContext(ctx).Infof("delete schema[%s/%s] info successfully, operator: %s", in.ServiceId, in.SchemaId, remoteIP)
 	return &pb.DeleteSchemaResponse{
 		Response: pb.CreateResponse(pb.Response_SUCCESS, "Delete schema info successfully."),
 	}, nil
@@ -234,7 +234,7 @@ func (s *MicroServiceService) ModifySchemas(ctx context.Context, in *pb.ModifySc
 	remoteIP := util.GetIPFromContext(ctx)
 	err := Validate(in)
 	if err != nil {
-		log.Errorf(err, "modify service[%s] schemas failed, operator: %s", in.ServiceId, remoteIP)
+		log.WithContext(ctx).Errorf(err, "modify service[%s] schemas failed, operator: %s", in.ServiceId, remoteIP)
 		return &pb.ModifySchemasResponse{
 			Response: pb.CreateResponse(scerr.ErrInvalidParams, "Invalid request."),
 		}, nil
@@ -245,13 +245,13 @@ func (s *MicroServiceService) ModifySchemas(ctx context.Context, in *pb.ModifySc
 
 	service, err := serviceUtil.GetService(ctx, domainProject, serviceId)
 	if err != nil {
-		log.Errorf(err, "modify service[%s] schemas failed, get service failed, operator: %s", serviceId, remoteIP)
+		log.WithContext(ctx).Errorf(err, "modify service[%s] schemas failed, get service failed, operator: %s", serviceId, remoteIP)
 		return &pb.ModifySchemasResponse{
 			Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
 		}, err
 	}
 	if service == nil {
-		log.Errorf(nil, "modify service[%s] schemas failed, service does not exist, operator: %s",
+		log.WithContext(ctx).Errorf(nil, "modify service[%s] schemas failed, service does not exist, operator: %s",
 			serviceId, remoteIP)
 		return &pb.ModifySchemasResponse{
 			Response: pb.CreateResponse(scerr.ErrServiceNotExists, "Service does not exist."),
@@ -260,7 +260,7 @@ func (s *MicroServiceService) ModifySchemas(ctx context.Context, in *pb.ModifySc
 
 	respErr := modifySchemas(ctx, domainProject, service, in.Schemas)
 	if respErr != nil {
-		log.Errorf(nil, "modify service[%s] schemas failed, operator: %s", serviceId, remoteIP)
+		log.WithContext(ctx).Errorf(nil, "modify service[%s] schemas failed, operator: %s", serviceId, remoteIP)
 		resp := &pb.ModifySchemasResponse{
 			Response: pb.CreateResponseWithSCErr(respErr),
 		}
@@ -333,7 +333,7 @@ func modifySchemas(ctx context.Context, domainProject string, service *pb.MicroS
 	serviceId := service.ServiceId
 	schemasFromDatabase, err := GetSchemasFromDatabase(ctx, domainProject, serviceId)
 	if err != nil {
-		log.Errorf(nil, "modify service[%s] schemas failed, get schemas failed, operator: %s",
+		log.WithContext(ctx).Errorf(nil, "modify service[%s] schemas failed, get schemas failed, operator: %s",
 			serviceId, remoteIP)
 		return scerr.NewError(scerr.ErrUnavailableBackend, err.Error())
 	}
@@ -347,14 +347,14 @@ func modifySchemas(ctx context.Context, domainProject string, service *pb.MicroS
 			rst := plugin.Plugins().Quota().Apply4Quotas(ctx, res)
 			errQuota := rst.Err
 			if errQuota != nil {
-				log.Errorf(errQuota, "modify service[%s] schemas failed, operator: %s", serviceId, remoteIP)
+				log.WithContext(ctx).Errorf(errQuota, "modify service[%s] schemas failed, operator: %s", serviceId, remoteIP)
 				return errQuota
 			}
 
 			service.Schemas = nonExistSchemaIds
 			opt, err := serviceUtil.UpdateService(domainProject, serviceId, service)
 			if err != nil {
-				log.Errorf(err, "modify service[%s] schemas failed, update service.Schemas failed, operator: %s",
+				log.WithContext(ctx).Errorf(err, "modify service[%s] schemas failed, update service.Schemas failed, operator: %s",
 					serviceId, remoteIP)
 				return scerr.NewError(scerr.ErrInternal, err.Error())
 			}
@@ -362,7 +362,7 @@ func modifySchemas(ctx context.Context, domainProject string, service *pb.MicroS
 		} else {
 			if len(nonExistSchemaIds) != 0 {
 				errInfo := fmt.Errorf("Non-existent schemaIds %v", nonExistSchemaIds)
-				log.Errorf(errInfo, "modify service[%s] schemas failed, operator: %s", serviceId, remoteIP)
+				log.WithContext(ctx).Errorf(errInfo, "modify service[%s] schemas failed, operator: %s", serviceId, remoteIP)
 				return scerr.NewError(scerr.ErrUndefinedSchemaId, errInfo.Error())
 			}
 			for _, needUpdateSchema := range needUpdateSchemas {
@@ -374,14 +374,14 @@ func modifySchemas(ctx context.Context, domainProject string, service *pb.MicroS
 					opts := schemaWithDatabaseOpera(registry.OpPut, domainProject, serviceId, needUpdateSchema)
 					pluginOps = append(pluginOps, opts...)
 				} else {
-					log.Warnf("schema[%s/%s] and it's summary already exist, skip to update, operator: %s",
+					log.WithContext(ctx).Warnf("schema[%s/%s] and it's summary already exist, skip to update, operator: %s",
 						serviceId, needUpdateSchema.SchemaId, remoteIP)
 				}
 			}
 		}
 
 		for _, schema := range needAddSchemas {
-			log.Infof("add new schema[%s/%s], operator: %s", serviceId, schema.SchemaId, remoteIP)
+			log.WithContext(ctx).Infof("add new schema[%s/%s], operator: %s", serviceId, schema.SchemaId, remoteIP)
 			opts := schemaWithDatabaseOpera(registry.OpPut, domainProject, service.ServiceId, schema)
 			pluginOps = append(pluginOps, opts...)
 		}
@@ -392,28 +392,28 @@ func modifySchemas(ctx context.Context, domainProject string, service *pb.MicroS
 			rst := plugin.Plugins().Quota().Apply4Quotas(ctx, res)
 			err := rst.Err
 			if err != nil {
-				log.Errorf(err, "modify service[%s] schemas failed, operator: %s", serviceId, remoteIP)
+				log.WithContext(ctx).Errorf(err, "modify service[%s] schemas failed, operator: %s", serviceId, remoteIP)
 				return err
 			}
 		}
 
 		var schemaIds []string
 		for _, schema := range needAddSchemas {
-			log.Infof("add new schema[%s/%s], operator: %s", serviceId, schema.SchemaId, remoteIP)
+			log.WithContext(ctx).Infof("add new schema[%s/%s], operator: %s", serviceId, schema.SchemaId, remoteIP)
 			opts := schemaWithDatabaseOpera(registry.OpPut, domainProject, service.ServiceId, schema)
 			pluginOps = append(pluginOps, opts...)
 			schemaIds = append(schemaIds, schema.SchemaId)
 		}
 
 		for _, schema := range needUpdateSchemas {
-			log.Infof("update schema[%s/%s], operator: %s", serviceId, schema.SchemaId, remoteIP)
+			log.WithContext(ctx).Infof("update schema[%s/%s], operator: %s", serviceId, schema.SchemaId, remoteIP)
 			opts := schemaWithDatabaseOpera(registry.OpPut, domainProject, serviceId, schema)
 			pluginOps = append(pluginOps, opts...)
 			schemaIds = append(schemaIds, schema.SchemaId)
 		}
 
 		for _, schema := range needDeleteSchemas {
-			log.Infof("delete non-existent schema[%s/%s], operator: %s", serviceId, schema.SchemaId, remoteIP)
+			log.WithContext(ctx).Infof("delete non-existent schema[%s/%s], operator: %s", serviceId, schema.SchemaId, remoteIP)
 			opts := schemaWithDatabaseOpera(registry.OpDel, domainProject, serviceId, schema)
 			pluginOps = append(pluginOps, opts...)
 		}
@@ -421,7 +421,7 @@ func modifySchemas(ctx context.Context, domainProject string, service *pb.MicroS
 		service.Schemas = schemaIds
 		opt, err := serviceUtil.UpdateService(domainProject, serviceId, service)
 		if err != nil {
-			log.Errorf(err, "modify service[%s] schemas failed, update service.Schemas failed, operator: %s",
+			log.WithContext(ctx).Errorf(err, "modify service[%s] schemas failed, update service.Schemas failed, operator: %s",
 				serviceId, remoteIP)
 			return scerr.NewError(scerr.ErrInternal, err.Error())
 		}
@@ -472,7 +472,7 @@ func GetSchemasFromDatabase(ctx context.Context, domainProject string, serviceId
 		registry.WithPrefix(),
 		registry.WithStrKey(key))
 	if err != nil {
-		log.Errorf(err, "get service[%s]'s schema failed", serviceId)
+		log.WithContext(ctx).Errorf(err, "get service[%s]'s schema failed", serviceId)
 		return nil, err
 	}
 	schemas := make([]*pb.Schema, 0, len(resp.Kvs))
@@ -514,7 +514,7 @@ func (s *MicroServiceService) ModifySchema(ctx context.Context, request *pb.Modi
 	}
 	err := s.modifySchema(ctx, serviceId, &schema)
 	if err != nil {
-		log.Errorf(err, "modify schema[%s/%s] failed, operator: %s", serviceId, schemaId, remoteIP)
+		log.WithContext(ctx).Errorf(err, "modify schema[%s/%s] failed, operator: %s", serviceId, schemaId, remoteIP)
 		resp := &pb.ModifySchemaResponse{
 			Response: pb.CreateResponseWithSCErr(err),
 		}
@@ -524,7 +524,7 @@ func (s *MicroServiceService) ModifySchema(ctx context.Context, request *pb.Modi
 		return resp, nil
 	}
 
-	log.Infof("modify schema[%s/%s] successfully, operator: %s", serviceId, schemaId, remoteIP)
+	log.WithContext(ctx).Infof("modify schema[%s/%s] successfully, operator: %s", serviceId, schemaId, remoteIP)
 	return &pb.ModifySchemaResponse{
 		Response: pb.CreateResponse(pb.Response_SUCCESS, "modify schema info success"),
 	}, nil
@@ -535,13 +535,13 @@ func (s *MicroServiceService) canModifySchema(ctx context.Context, domainProject
 	serviceId := in.ServiceId
 	schemaId := in.SchemaId
 	if len(schemaId) == 0 || len(serviceId) == 0 {
-		log.Errorf(nil, "update schema[%s/%s] failed, invalid params, operator: %s",
+		log.WithContext(ctx).Errorf(nil, "update schema[%s/%s] failed, invalid params, operator: %s",
 			serviceId, schemaId, remoteIP)
 		return scerr.NewError(scerr.ErrInvalidParams, "serviceId or schemaId is nil")
 	}
 	err := Validate(in)
 	if err != nil {
-		log.Errorf(err, "update schema[%s/%s] failed, operator: %s", serviceId, schemaId, remoteIP)
+		log.WithContext(ctx).Errorf(err, "update schema[%s/%s] failed, operator: %s", serviceId, schemaId, remoteIP)
 		return scerr.NewError(scerr.ErrInvalidParams, err.Error())
 	}
 
@@ -549,11 +549,11 @@ func (s *MicroServiceService) canModifySchema(ctx context.Context, domainProject
 	rst := plugin.Plugins().Quota().Apply4Quotas(ctx, res)
 	errQuota := rst.Err
 	if errQuota != nil {
-		log.Errorf(errQuota, "update schema[%s/%s] failed, operator: %s", serviceId, schemaId, remoteIP)
+		log.WithContext(ctx).Errorf(errQuota, "update schema[%s/%s] failed, operator: %s", serviceId, schemaId, remoteIP)
 		return errQuota
 	}
 	if len(in.Summary) == 0 {
-		log.Warnf("schema[%s/%s]'s summary is empty, operator: %s", serviceId, schemaId, remoteIP)
+		log.WithContext(ctx).Warnf("schema[%s/%s]'s summary is empty, operator: %s", serviceId, schemaId, remoteIP)
 	}
 	return nil
 }
@@ -565,12 +565,12 @@ func (s *MicroServiceService) modifySchema(ctx context.Context, serviceId string
 
 	service, err := serviceUtil.GetService(ctx, domainProject, serviceId)
 	if err != nil {
-		log.Errorf(err, "modify schema[%s/%s] failed, get service failed, operator: %s",
+		log.WithContext(ctx).Errorf(err, "modify schema[%s/%s] failed, get service failed, operator: %s",
 			serviceId, schemaId, remoteIP)
 		return scerr.NewError(scerr.ErrInternal, err.Error())
 	}
 	if service == nil {
-		log.Errorf(nil, "modify schema[%s/%s] failed, service does not exist, operator: %s",
+		log.WithContext(ctx).Errorf(nil, "modify schema[%s/%s] failed, service does not exist, operator: %s",
 			serviceId, schemaId, remoteIP)
 		return scerr.NewError(scerr.ErrServiceNotExists, "Service does not exist")
 	}
@@ -586,26 +586,26 @@ func (s *MicroServiceService) modifySchema(ctx context.Context, serviceId string
 		key := apt.GenerateServiceSchemaKey(domainProject, serviceId, schemaId)
 		respSchema, err := backend.Store().Schema().Search(ctx, registry.WithStrKey(key), registry.WithCountOnly())
 		if err != nil {
-			log.Errorf(err, "modify schema[%s/%s] failed, get schema summary failed, operator: %s",
+			log.WithContext(ctx).Errorf(err, "modify schema[%s/%s] failed, get schema summary failed, operator: %s",
 				serviceId, schemaId, remoteIP)
 			return scerr.NewError(scerr.ErrUnavailableBackend, err.Error())
 		}
 
 		if respSchema.Count != 0 {
 			if len(schema.Summary) == 0 {
-				log.Errorf(err, "%s mode, schema[%s/%s] already exists, can not be changed, operator: %s",
+				log.WithContext(ctx).Errorf(err, "%s mode, schema[%s/%s] already exists, can not be changed, operator: %s",
 					pb.ENV_PROD, serviceId, schemaId, remoteIP)
 				return scerr.NewError(scerr.ErrModifySchemaNotAllow, "schema already exist, can not be changed in "+pb.ENV_PROD)
 			}
 
 			exist, err := isExistSchemaSummary(ctx, domainProject, serviceId, schemaId)
 			if err != nil {
-				log.Errorf(err, "check schema[%s/%s] summary existence failed, operator: %s",
+				log.WithContext(ctx).Errorf(err, "check schema[%s/%s] summary existence failed, operator: %s",
 					serviceId, schemaId, remoteIP)
 				return scerr.NewError(scerr.ErrInternal, err.Error())
 			}
 			if exist {
-				log.Errorf(err, "%s mode, schema[%s/%s] already exist, can not be changed, operator: %s",
+				log.WithContext(ctx).Errorf(err, "%s mode, schema[%s/%s] already exist, can not be changed, operator: %s",
 					pb.ENV_PROD, serviceId, schemaId, remoteIP)
 				return scerr.NewError(scerr.ErrModifySchemaNotAllow, "schema already exist, can not be changed in "+pb.ENV_PROD)
 			}
@@ -615,7 +615,7 @@ func (s *MicroServiceService) modifySchema(ctx context.Context, serviceId string
 			service.Schemas = append(service.Schemas, schemaId)
 			opt, err := serviceUtil.UpdateService(domainProject, serviceId, service)
 			if err != nil {
-				log.Errorf(err, "modify schema[%s/%s] failed, update service.Schemas failed, operator: %s",
+				log.WithContext(ctx).Errorf(err, "modify schema[%s/%s] failed, update service.Schemas failed, operator: %s",
 					serviceId, schemaId, remoteIP)
 				return scerr.NewError(scerr.ErrInternal, err.Error())
 			}
@@ -626,7 +626,7 @@ func (s *MicroServiceService) modifySchema(ctx context.Context, serviceId string
 			service.Schemas = append(service.Schemas, schemaId)
 			opt, err := serviceUtil.UpdateService(domainProject, serviceId, service)
 			if err != nil {
-				log.Errorf(err, "modify schema[%s/%s] failed, update service.Schemas failed, operator: %s",
+				log.WithContext(ctx).Errorf(err, "modify schema[%s/%s] failed, update service.Schemas failed, operator: %s",
 					serviceId, schemaId, remoteIP)
 				return scerr.NewError(scerr.ErrInternal, err.Error())
 			}
@@ -691,7 +691,7 @@ func getSchemaSummary(ctx context.Context, domainProject string, serviceId strin
 		registry.WithStrKey(key),
 	)
 	if err != nil {
-		log.Errorf(err, "get schema[%s/%s] summary failed", serviceId, schemaId)
+		log.WithContext(ctx).Errorf(err, "get schema[%s/%s] summary failed", serviceId, schemaId)
 		return "", err
 	}
 	if len(resp.Kvs) == 0 {
@@ -38,6 +38,7 @@ func init() {
 func RegisterGrpcServices(s *grpc.Server) {
 	pb.RegisterServiceCtrlServer(s, serviceService)
 	pb.RegisterServiceInstanceCtrlServer(s, instanceService)
+	pb.RegisterServiceInstanceStreamCtrlServer(s, instanceService)
 }
 
 func AssembleResources() (pb.ServiceCtrlServer, pb.ServiceInstanceCtrlServerEx) {
@@ -35,7 +35,7 @@ func (s *MicroServiceService) AddTags(ctx context.Context, in *pb.AddServiceTags
 	remoteIP := util.GetIPFromContext(ctx)
 	err := Validate(in)
 	if err != nil {
-		log.Errorf(err, "add service[%s]'s tags %v failed, operator: %s", in.ServiceId, in.Tags, remoteIP)
+		log.WithContext(ctx).Errorf(err, "add service[%s]'s tags %v failed, operator: %s", in.ServiceId, in.Tags, remoteIP)
 		return &pb.AddServiceTagsResponse{
 			Response: pb.CreateResponse(scerr.ErrInvalidParams, err.Error()),
 		}, nil
@@ -44,7 +44,7 @@ func (s *MicroServiceService) AddTags(ctx context.Context, in *pb.AddServiceTags
 	domainProject := util.ParseDomainProject(ctx)
 	// service id存在性校验
 	if !serviceUtil.ServiceExist(ctx, domainProject, in.ServiceId) {
-		log.Errorf(nil, "add service[%s]'s tags %v failed, service does not exist, operator: %s",
+		log.WithContext(ctx).Errorf(nil, "add service[%s]'s tags %v failed, service does not exist, operator: %s",
 			in.ServiceId, in.Tags, remoteIP)
 		return &pb.AddServiceTagsResponse{
 			Response: pb.CreateResponse(scerr.ErrServiceNotExists, "Service does not exist."),
@@ -56,7 +56,7 @@ func (s *MicroServiceService) AddTags(ctx context.Context, in *pb.AddServiceTags
 	rst := plugin.Plugins().Quota().Apply4Quotas(ctx, res)
 	errQuota := rst.Err
 	if errQuota != nil {
-		log.Errorf(errQuota, "add service[%s]'s tags %v failed, operator: %s", in.ServiceId, addTags, remoteIP)
+		log.WithContext(ctx).Errorf(errQuota, "add service[%s]'s tags %v failed, operator: %s", in.ServiceId, addTags, remoteIP)
 		response := &pb.AddServiceTagsResponse{
 			Response: pb.CreateResponseWithSCErr(errQuota),
 		}
@@ -68,7 +68,7 @@ func (s *MicroServiceService) AddTags(ctx context.Context, in *pb.AddServiceTags
 
 	dataTags, err := serviceUtil.GetTagsUtils(ctx, domainProject, in.ServiceId)
 	if err != nil {
-		log.Errorf(err, "add service[%s]'s tags %v failed, get existed tag failed, operator: %s",
+		log.WithContext(ctx).Errorf(err, "add service[%s]'s tags %v failed, get existed tag failed, operator: %s",
 			in.ServiceId, addTags, remoteIP)
 		return &pb.AddServiceTagsResponse{
 			Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
@@ -84,7 +84,7 @@ func (s *MicroServiceService) AddTags(ctx context.Context, in *pb.AddServiceTags
 
 	checkErr := serviceUtil.AddTagIntoETCD(ctx, domainProject, in.ServiceId, dataTags)
 	if checkErr != nil {
-		log.Errorf(checkErr, "add service[%s]'s tags %v failed, operator: %s", in.ServiceId, in.Tags, remoteIP)
+		log.WithContext(ctx).Errorf(checkErr, "add service[%s]'s tags %v failed, operator: %s", in.ServiceId, in.Tags, remoteIP)
 		resp := &pb.AddServiceTagsResponse{
 			Response: pb.CreateResponseWithSCErr(checkErr),
 		}
@@ -94,7 +94,7 @@ func (s *MicroServiceService) AddTags(ctx context.Context, in *pb.AddServiceTags
 		return resp, nil
 	}
 
-	log.Infof("add service[%s]'s tags %v successfully, operator: %s", in.ServiceId, in.Tags, remoteIP)
+	log.WithContext(ctx).Infof("add service[%s]'s tags %v successfully, operator: %s", in.ServiceId, in.Tags, remoteIP)
 	return &pb.AddServiceTagsResponse{
 		Response: pb.CreateResponse(pb.Response_SUCCESS, "Add service tags successfully."),
 	}, nil
@@ -105,7 +105,7 @@ func (s *MicroServiceService) UpdateTag(ctx context.Context, in *pb.UpdateServic
 	tagFlag := util.StringJoin([]string{in.Key, in.Value}, "/")
 	err := Validate(in)
 	if err != nil {
-		log.Errorf(err, "update service[%s]'s tag[%s] failed, operator: %s", in.ServiceId, tagFlag, remoteIP)
+		log.WithContext(ctx).Errorf(err, "update service[%s]'s tag[%s] failed, operator: %s", in.ServiceId, tagFlag, remoteIP)
 		return &pb.UpdateServiceTagResponse{
 			Response: pb.CreateResponse(scerr.ErrInvalidParams, err.Error()),
 		}, nil
@@ -114,7 +114,7 @@ func (s *MicroServiceService) UpdateTag(ctx context.Context, in *pb.UpdateServic
 	domainProject := util.ParseDomainProject(ctx)
 
 	if !serviceUtil.ServiceExist(ctx, domainProject, in.ServiceId) {
-		log.Errorf(err, "update service[%s]'s tag[%s] failed, service does not exist, operator: %s",
+		log.WithContext(ctx).Errorf(err, "update service[%s]'s tag[%s] failed, service does not exist, operator: %s",
 			in.ServiceId, tagFlag, remoteIP)
 		return &pb.UpdateServiceTagResponse{
 			Response: pb.CreateResponse(scerr.ErrServiceNotExists, "Service does not exist."),
@@ -123,7 +123,7 @@ func (s *MicroServiceService) UpdateTag(ctx context.Context, in *pb.UpdateServic
 
 	tags, err := serviceUtil.GetTagsUtils(ctx, domainProject, in.ServiceId)
 	if err != nil {
-		log.Errorf(err, "update service[%s]'s tag[%s] failed, get tag failed, operator: %s",
+		log.WithContext(ctx).Errorf(err, "update service[%s]'s tag[%s] failed, get tag failed, operator: %s",
 			in.ServiceId, tagFlag, remoteIP)
 		return &pb.UpdateServiceTagResponse{
 			Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
@@ -131,7 +131,7 @@ func (s *MicroServiceService) UpdateTag(ctx context.Context, in *pb.UpdateServic
 	}
 	//check tag 是否存在
 	if _, ok := tags[in.Key]; !ok {
-		log.Errorf(nil, "update service[%s]'s tag[%s] failed, tag does not exist, operator: %s",
+		log.WithContext(ctx).Errorf(nil, "update service[%s]'s tag[%s] failed, tag does not exist, operator: %s",
 			in.ServiceId, tagFlag, remoteIP)
 		return &pb.UpdateServiceTagResponse{
 			Response: pb.CreateResponse(scerr.ErrTagNotExists, "Tag does not exist, please add one first."),
@@ -146,7 +146,7 @@ func (s *MicroServiceService) UpdateTag(ctx context.Context, in *pb.UpdateServic
 
 	checkErr := serviceUtil.AddTagIntoETCD(ctx, domainProject, in.ServiceId, copyTags)
 	if checkErr != nil {
-		log.Errorf(checkErr, "update service[%s]'s tag[%s] failed, operator: %s", in.ServiceId, tagFlag, remoteIP)
+		log.WithContext(ctx).Errorf(checkErr, "update service[%s]'s tag[%s] failed, operator: %s", in.ServiceId, tagFlag, remoteIP)
 		resp := &pb.UpdateServiceTagResponse{
 			Response: pb.CreateResponseWithSCErr(checkErr),
 		}
@@ -156,7 +156,7 @@ func (s *MicroServiceService) UpdateTag(ctx context.Context, in *pb.UpdateServic
 		return resp, nil
 	}
 
-	log.Infof("update service[%s]'s tag[%s] successfully, operator: %s", in.ServiceId, tagFlag, remoteIP)
+	log.WithContext(ctx).Infof("update service[%s]'s tag[%s] successfully, operator: %s", in.ServiceId, tagFlag, remoteIP)
 	return &pb.UpdateServiceTagResponse{
 		Response: pb.CreateResponse(pb.Response_SUCCESS, "Update service tag success."),
 	}, nil
@@ -166,7 +166,7 @@ func (s *MicroServiceService) DeleteTags(ctx context.Context, in *pb.DeleteServi
 	remoteIP := util.GetIPFromContext(ctx)
 	err := Validate(in)
 	if err != nil {
-		log.Errorf(err, "delete service[%s]'s tags %v failed, operator: %s", in.ServiceId, in.Keys, remoteIP)
+		log.WithContext(ctx).Errorf(err, "delete service[%s]'s tags %v failed, operator: %s", in.ServiceId, in.Keys, remoteIP)
 		return &pb.DeleteServiceTagsResponse{
 			Response: pb.CreateResponse(scerr.ErrInvalidParams, err.Error()),
 		}, nil
@@ -175,7 +175,7 @@ func (s *MicroServiceService) DeleteTags(ctx context.Context, in *pb.DeleteServi
 	domainProject := util.ParseDomainProject(ctx)
 
 	if !serviceUtil.ServiceExist(ctx, domainProject, in.ServiceId) {
-		log.Errorf(nil, "delete service[%s]'s tags %v failed, service does not exist, operator: %s",
+		log.WithContext(ctx).Errorf(nil, "delete service[%s]'s tags %v failed, service does not exist, operator: %s",
 			in.ServiceId, in.Keys, remoteIP)
 		return &pb.DeleteServiceTagsResponse{
 			Response: pb.CreateResponse(scerr.ErrServiceNotExists, "Service does not exist."),
@@ -184,7 +184,7 @@ func (s *MicroServiceService) DeleteTags(ctx context.Context, in *pb.DeleteServi
 
 	tags, err := serviceUtil.GetTagsUtils(ctx, domainProject, in.ServiceId)
 	if err != nil {
-		log.Errorf(err, "delete service[%s]'s tags %v failed, get service tags failed, operator: %s",
+		log.WithContext(ctx).Errorf(err, "delete service[%s]'s tags %v failed, get service tags failed, operator: %s",
 			in.ServiceId, in.Keys, remoteIP)
 		return &pb.DeleteServiceTagsResponse{
 			Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
@@ -197,7 +197,7 @@ func (s *MicroServiceService) DeleteTags(ctx context.Context, in *pb.DeleteServi
 	}
 	for _, key := range in.Keys {
 		if _, ok := copyTags[key]; !ok {
-			log.Errorf(nil, "delete service[%s]'s tags %v failed, tag[%s] does not exist, operator: %s",
+			log.WithContext(ctx).Errorf(nil, "delete service[%s]'s tags %v failed, tag[%s] does not exist, operator: %s",
 				in.ServiceId, in.Keys, key, remoteIP)
 			return &pb.DeleteServiceTagsResponse{
 				Response: pb.CreateResponse(scerr.ErrTagNotExists, "Delete tags failed for this key "+key+" does not exist."),
@@ -209,7 +209,7 @@ func (s *MicroServiceService) DeleteTags(ctx context.Context, in *pb.DeleteServi
 	// tags 可能size == 0
 	data, err := json.Marshal(copyTags)
 	if err != nil {
-		log.Errorf(err, "delete service[%s]'s tags %v failed, marshall service tags failed, operator: %s",
+		log.WithContext(ctx).Errorf(err, "delete service[%s]'s tags %v failed, marshall service tags failed, operator: %s",
 			in.ServiceId, in.Keys, remoteIP)
 		return &pb.DeleteServiceTagsResponse{
 			Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
@@ -225,21 +225,21 @@ func (s *MicroServiceService) DeleteTags(ctx context.Context, in *pb.DeleteServi
 			registry.CMP_NOT_EQUAL, 0)},
 		nil)
 	if err != nil {
-		log.Errorf(err, "delete service[%s]'s tags %v failed, operator: %s",
+		log.WithContext(ctx).Errorf(err, "delete service[%s]'s tags %v failed, operator: %s",
 			in.ServiceId, in.Keys, remoteIP)
 		return &pb.DeleteServiceTagsResponse{
 			Response: pb.CreateResponse(scerr.ErrUnavailableBackend, err.Error()),
 		}, err
 	}
 	if !resp.Succeeded {
-		log.Errorf(err, "delete service[%s]'s tags %v failed, service does not exist, operator: %s",
+		log.WithContext(ctx).Errorf(err, "delete service[%s]'s tags %v failed, service does not exist, operator: %s",
 			in.ServiceId, in.Keys, remoteIP)
 		return &pb.DeleteServiceTagsResponse{
 			Response: pb.CreateResponse(scerr.ErrServiceNotExists, "Service does not exist."),
 		}, nil
 	}
 
-	log.Infof("delete service[%s]'s tags %v successfully, operator: %s", in.ServiceId, in.Keys, remoteIP)
+	log.WithContext(ctx).Infof("delete service[%s]'s tags %v successfully, operator: %s", in.ServiceId, in.Keys, remoteIP)
 	return &pb.DeleteServiceTagsResponse{
 		Response: pb.CreateResponse(pb.Response_SUCCESS, "Delete service tags successfully."),
 	}, nil
@@ -248,7 +248,7 @@ func (s *MicroServiceService) DeleteTags(ctx context.Context, in *pb.DeleteServi
 func (s *MicroServiceService) GetTags(ctx context.Context, in *pb.GetServiceTagsRequest) (*pb.GetServiceTagsResponse, error) {
 	err := Validate(in)
 	if err != nil {
-		log.Errorf(err, "get service[%s]'s tags failed", in.ServiceId)
+		log.WithContext(ctx).Errorf(err, "get service[%s]'s tags failed", in.ServiceId)
 		return &pb.GetServiceTagsResponse{
 			Response: pb.CreateResponse(scerr.ErrInvalidParams, err.Error()),
 		}, nil
@@ -257,7 +257,7 @@ func (s *MicroServiceService) GetTags(ctx context.Context, in *pb.GetServiceTags
 	domainProject := util.ParseDomainProject(ctx)
 
 	if !serviceUtil.ServiceExist(ctx, domainProject, in.ServiceId) {
-		log.Errorf(err, "get service[%s]'s tags failed, service does not exist", in.ServiceId)
+		log.WithContext(ctx).Errorf(err, "get service[%s]'s tags failed, service does not exist", in.ServiceId)
 		return &pb.GetServiceTagsResponse{
 			Response: pb.CreateResponse(scerr.ErrServiceNotExists, "Service does not exist."),
 		}, nil
@@ -265,7 +265,7 @@ func (s *MicroServiceService) GetTags(ctx context.Context, in *pb.GetServiceTags
 
 	tags, err := serviceUtil.GetTagsUtils(ctx, domainProject, in.ServiceId)
 	if err != nil {
-		log.Errorf(err, "get service[%s]'s tags failed, get tags failed", in.ServiceId)
+		log.WithContext(ctx).Errorf(err, "get service[%s]'s tags failed, get tags failed", in.ServiceId)
 		return &pb.GetServiceTagsResponse{
 			Response: pb.CreateResponse(scerr.ErrInternal, err.Error()),
 		}, err
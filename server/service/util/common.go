@@ -23,4 +23,16 @@ const (
 	CTX_CACHEONLY         = "cacheOnly"
 	CTX_REQUEST_REVISION  = "requestRev"
 	CTX_RESPONSE_REVISION = "responseRev"
+	CTX_SINCE_REVISION    = "sinceRev"
+	CTX_WATCH_BATCH       = "watchBatch"
+	CTX_EVENT_FORMAT      = "eventFormat"
+	CTX_INCLUDE_REMOTE    = "includeRemote"
+	// CTX_FIELD_MASK is the *proto.FieldMask selects the fields of the
+	// streamed instances
+	CTX_FIELD_MASK = "fieldMask"
+	// CTX_FIND_WAIT is "1" if the find is long-polling, then the find
+	// sets CTX_FIND_WAITER to the *cache.InstancesWaiter subscribed before
+	// reading the cache, the caller must stop it
+	CTX_FIND_WAIT   = "findWait"
+	CTX_FIND_WAITER = "findWaiter"
 )
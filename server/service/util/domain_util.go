@@ -17,6 +17,7 @@
 package util
 
 import (
+	"encoding/json"
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/pkg/util"
 	apt "github.com/apache/servicecomb-service-center/server/core"
@@ -24,9 +25,20 @@ import (
 	"github.com/apache/servicecomb-service-center/server/plugin/pkg/discovery"
 	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
 	"golang.org/x/net/context"
+	"strconv"
 	"strings"
+	"time"
 )
 
+// DomainInfo is the value of the domain key, the domains created on
+// first use have no value
+type DomainInfo struct {
+	// Quotas maps 'service' and 'instance' to the max number of the
+	// resources in the domain, they are checked besides the global quotas
+	Quotas   map[string]int64 `json:"quotas,omitempty"`
+	CreateAt string           `json:"createAt,omitempty"`
+}
+
 func GetAllDomainRawData(ctx context.Context) ([]*discovery.KeyValue, error) {
 	opts := append(FromContext(ctx),
 		registry.WithStrKey(apt.GenerateDomainKey("")),
@@ -123,3 +135,45 @@ func NewDomainProject(ctx context.Context, domain, project string) error {
 	}
 	return err
 }
+
+// GetDomainInfo returns nil if the domain does not exist
+func GetDomainInfo(ctx context.Context, domain string) (*DomainInfo, error) {
+	opts := append(FromContext(ctx), registry.WithStrKey(apt.GenerateDomainKey(domain)))
+	rsp, err := backend.Store().Domain().Search(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if len(rsp.Kvs) == 0 {
+		return nil, nil
+	}
+	info := &DomainInfo{}
+	if v, ok := rsp.Kvs[0].Value.(string); ok && len(v) > 0 {
+		if err := json.Unmarshal([]byte(v), info); err != nil {
+			return nil, err
+		}
+	}
+	return info, nil
+}
+
+func PutDomainInfo(ctx context.Context, domain string, info *DomainInfo) error {
+	data, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+	_, err = backend.Registry().Do(ctx, registry.PUT,
+		registry.WithStrKey(apt.GenerateDomainKey(domain)), registry.WithValue(data))
+	return err
+}
+
+// TouchRevisionOps returns the ops to modify the revision keys of the
+// domain and the project, they are committed with every creation of the
+// resources, so the deletion of the domain or the project compares the
+// mod revisions of the keys instead of the prefixes
+func TouchRevisionOps(domainProject string) []registry.PluginOp {
+	domain, project := apt.FromDomainProject(domainProject)
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	return []registry.PluginOp{
+		registry.OpPut(registry.WithStrKey(apt.GenerateDomainRevisionKey(domain)), registry.WithStrValue(now)),
+		registry.OpPut(registry.WithStrKey(apt.GenerateProjectRevisionKey(domain, project)), registry.WithStrValue(now)),
+	}
+}
@@ -18,27 +18,88 @@ package util
 
 import (
 	"errors"
+	"github.com/apache/servicecomb-service-center/pkg/util"
 	apt "github.com/apache/servicecomb-service-center/server/core"
 	"github.com/apache/servicecomb-service-center/server/core/backend"
+	"github.com/apache/servicecomb-service-center/server/feature"
 	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	"github.com/apache/servicecomb-service-center/server/service/lease"
 	"golang.org/x/net/context"
 )
 
+// FEATURE_HEARTBEAT_FAST is the flag of renewing the leases by the cached
+// lease ids, disable it to search the lease store on every heartbeat
+const FEATURE_HEARTBEAT_FAST = "heartbeat_fast_path"
+
+// leaseIds caches the lease id of the instances by the lease key, the
+// heartbeats fill it and the lease events invalidate it
+var leaseIds = util.NewConcurrentMap(0)
+
+// keepAlive and heartbeat are replaced in the tests
+var (
+	keepAlive = keepAliveLeaseKey
+	heartbeat = HeartbeatUtil
+)
+
+func init() {
+	feature.Define(FEATURE_HEARTBEAT_FAST, "renew the instance leases by the cached lease ids", true)
+}
+
+// RemoveLeaseId is called when the lease key of an instance is changed
+func RemoveLeaseId(key string) {
+	leaseIds.Remove(key)
+}
+
+// HeartbeatFast renews the lease of the instance by the cached lease id,
+// the lease store is searched only if the id is not cached or the renewal
+// fails or the flag FEATURE_HEARTBEAT_FAST is disabled for the domain,
+// then it is the same as HeartbeatUtil
+func HeartbeatFast(ctx context.Context, domainProject string, serviceId string, instanceId string) (ttl int64, err error, isInnerErr bool) {
+	if !feature.Enabled(ctx, FEATURE_HEARTBEAT_FAST) {
+		_, ttl, err, isInnerErr = heartbeat(ctx, domainProject, serviceId, instanceId)
+		return
+	}
+
+	key := apt.GenerateInstanceLeaseKey(domainProject, serviceId, instanceId)
+	if v, ok := leaseIds.Get(key); ok {
+		ttl, err = keepAlive(ctx, key, v.(int64))
+		if err == nil {
+			lease.ReportRenew(domainProject, serviceId, nil)
+			return
+		}
+		// the instance may be registered again with a new lease
+		leaseIds.Remove(key)
+	}
+
+	var leaseID int64
+	leaseID, ttl, err, isInnerErr = heartbeat(ctx, domainProject, serviceId, instanceId)
+	if err == nil && leaseID != -1 {
+		leaseIds.PutIfAbsent(key, leaseID)
+	}
+	return
+}
+
 func HeartbeatUtil(ctx context.Context, domainProject string, serviceId string, instanceId string) (leaseID int64, ttl int64, err error, isInnerErr bool) {
 	leaseID, err = GetLeaseId(ctx, domainProject, serviceId, instanceId)
 	if err != nil {
+		lease.ReportRenew(domainProject, serviceId, err)
 		return leaseID, ttl, err, true
 	}
 	ttl, err = KeepAliveLease(ctx, domainProject, serviceId, instanceId, leaseID)
+	lease.ReportRenew(domainProject, serviceId, err)
 	return leaseID, ttl, err, false
 }
 
 func KeepAliveLease(ctx context.Context, domainProject, serviceId, instanceId string, leaseID int64) (ttl int64, err error) {
+	return keepAliveLeaseKey(ctx, apt.GenerateInstanceLeaseKey(domainProject, serviceId, instanceId), leaseID)
+}
+
+func keepAliveLeaseKey(ctx context.Context, key string, leaseID int64) (ttl int64, err error) {
 	if leaseID == -1 {
 		return ttl, errors.New("leaseId not exist, instance not exist.")
 	}
 	ttl, err = backend.Store().KeepAlive(ctx,
-		registry.WithStrKey(apt.GenerateInstanceLeaseKey(domainProject, serviceId, instanceId)),
+		registry.WithStrKey(key),
 		registry.WithLease(leaseID))
 	if err != nil {
 		return ttl, err
@@ -17,6 +17,9 @@
 package util
 
 import (
+	"errors"
+	apt "github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/feature"
 	"golang.org/x/net/context"
 	"testing"
 )
@@ -41,3 +44,64 @@ func TestKeepAliveLease(t *testing.T) {
 		t.Fatalf("KeepAliveLease failed")
 	}
 }
+
+func TestHeartbeatFast(t *testing.T) {
+	defer func(k func(context.Context, string, int64) (int64, error),
+		h func(context.Context, string, string, string) (int64, int64, error, bool)) {
+		keepAlive, heartbeat = k, h
+	}(keepAlive, heartbeat)
+
+	var (
+		renewed, lookups int
+		renewedId        int64
+		renewErr         error
+	)
+	keepAlive = func(_ context.Context, _ string, leaseID int64) (int64, error) {
+		renewed++
+		renewedId = leaseID
+		return 30, renewErr
+	}
+	heartbeat = func(_ context.Context, _, _, _ string) (int64, int64, error, bool) {
+		lookups++
+		return int64(lookups + 1), 60, nil, false
+	}
+	ctx := context.Background()
+	key := apt.GenerateInstanceLeaseKey("d/p", "s", "i")
+
+	// a cache hit renews the cached lease id without a lookup
+	leaseIds.Put(key, int64(1))
+	ttl, err, _ := HeartbeatFast(ctx, "d/p", "s", "i")
+	if err != nil || ttl != 30 || renewed != 1 || renewedId != 1 || lookups != 0 {
+		t.Fatalf("TestHeartbeatFast failed, ttl %d, renewed %d, id %d, lookups %d, %v",
+			ttl, renewed, renewedId, lookups, err)
+	}
+
+	// the removed id is looked up and cached again
+	RemoveLeaseId(key)
+	if _, ok := leaseIds.Get(key); ok {
+		t.Fatalf("TestHeartbeatFast failed, RemoveLeaseId should invalidate the entry")
+	}
+	ttl, err, _ = HeartbeatFast(ctx, "d/p", "s", "i")
+	if v, ok := leaseIds.Get(key); err != nil || ttl != 60 || lookups != 1 || renewed != 1 || !ok || v.(int64) != 2 {
+		t.Fatalf("TestHeartbeatFast failed, ttl %d, renewed %d, lookups %d, cached %v, %v",
+			ttl, renewed, lookups, v, err)
+	}
+
+	// a renewal failure falls back to the lookup and caches the new id
+	renewErr = errors.New("lease not found")
+	ttl, err, _ = HeartbeatFast(ctx, "d/p", "s", "i")
+	if v, ok := leaseIds.Get(key); err != nil || ttl != 60 || lookups != 2 || renewed != 2 || !ok || v.(int64) != 3 {
+		t.Fatalf("TestHeartbeatFast failed, ttl %d, renewed %d, lookups %d, cached %v, %v",
+			ttl, renewed, lookups, v, err)
+	}
+
+	// the cache is skipped if the flag is disabled
+	renewErr = nil
+	feature.Define(FEATURE_HEARTBEAT_FAST, "", false)
+	defer feature.Define(FEATURE_HEARTBEAT_FAST, "renew the instance leases by the cached lease ids", true)
+	ttl, err, _ = HeartbeatFast(ctx, "d/p", "s", "i")
+	if err != nil || ttl != 60 || lookups != 3 || renewed != 2 {
+		t.Fatalf("TestHeartbeatFast failed, ttl %d, renewed %d, lookups %d, %v", ttl, renewed, lookups, err)
+	}
+	RemoveLeaseId(key)
+}
@@ -18,8 +18,8 @@ package util
 
 import (
 	"crypto/sha1"
-	"encoding/json"
-	"fmt"
+	"encoding/hex"
+	"github.com/apache/servicecomb-service-center/pkg/buffer"
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/pkg/util"
 	apt "github.com/apache/servicecomb-service-center/server/core"
@@ -28,6 +28,7 @@ import (
 	scerr "github.com/apache/servicecomb-service-center/server/error"
 	"github.com/apache/servicecomb-service-center/server/plugin/pkg/discovery"
 	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	"github.com/apache/servicecomb-service-center/server/service/lease"
 	"golang.org/x/net/context"
 	"strconv"
 	"strings"
@@ -49,25 +50,42 @@ func GetLeaseId(ctx context.Context, domainProject string, serviceId string, ins
 }
 
 func GetInstance(ctx context.Context, domainProject string, serviceId string, instanceId string) (*pb.MicroServiceInstance, error) {
+	instance, _, err := GetInstanceWithRev(ctx, domainProject, serviceId, instanceId)
+	return instance, err
+}
+
+// GetInstanceWithRev returns the instance and its mod revision
+func GetInstanceWithRev(ctx context.Context, domainProject string, serviceId string, instanceId string) (*pb.MicroServiceInstance, int64, error) {
 	key := apt.GenerateInstanceKey(domainProject, serviceId, instanceId)
 	opts := append(FromContext(ctx), registry.WithStrKey(key))
 
 	resp, err := backend.Store().Instance().Search(ctx, opts...)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	if len(resp.Kvs) == 0 {
-		return nil, nil
+		return nil, 0, nil
 	}
 
-	return resp.Kvs[0].Value.(*pb.MicroServiceInstance), nil
+	return resp.Kvs[0].Value.(*pb.MicroServiceInstance), resp.Kvs[0].ModRevision, nil
 }
 
-func FormatRevision(revs, counts []int64) (s string) {
+// revisionBuffers is used by FormatRevision which is called on every
+// refresh of the find instances cache
+var revisionBuffers = buffer.NewPool(64)
+
+func FormatRevision(revs, counts []int64) string {
+	var num [20]byte
+	buf := revisionBuffers.Get()
 	for i, rev := range revs {
-		s += fmt.Sprintf("%d.%d,", rev, counts[i])
+		buf.Write(strconv.AppendInt(num[:0], rev, 10))
+		buf.WriteByte('.')
+		buf.Write(strconv.AppendInt(num[:0], counts[i], 10))
+		buf.WriteByte(',')
 	}
-	return fmt.Sprintf("%x", sha1.Sum(util.StringToBytesWithNoCopy(s)))
+	sum := sha1.Sum(buf.Bytes())
+	revisionBuffers.Put(buf)
+	return hex.EncodeToString(sum[:])
 }
 
 func GetAllInstancesOfOneService(ctx context.Context, domainProject string, serviceId string) ([]*pb.MicroServiceInstance, error) {
@@ -86,6 +104,42 @@ func GetAllInstancesOfOneService(ctx context.Context, domainProject string, serv
 	return instances, nil
 }
 
+// ForEachInstanceOfOneService calls f with the instances of the service in
+// chunks of at most n, it stops at the first error returned by f. The
+// instances are iterated in the cache, only the references are collected,
+// the registry is searched if the context requires no cache
+func ForEachInstanceOfOneService(ctx context.Context, domainProject string, serviceId string, n int,
+	f func(instances []*pb.MicroServiceInstance) error) error {
+	key := apt.GenerateInstanceKey(domainProject, serviceId, "")
+	var all []*discovery.KeyValue
+	if ctx.Value(CTX_NOCACHE) == "1" {
+		opts := append(FromContext(ctx), registry.WithStrKey(key), registry.WithPrefix())
+		resp, err := backend.Store().Instance().Search(ctx, opts...)
+		if err != nil {
+			log.Errorf(err, "get service[%s]'s instances failed", serviceId)
+			return err
+		}
+		all = resp.Kvs
+	} else {
+		backend.Store().Instance().Cache().GetPrefix(key, &all)
+	}
+
+	for i := 0; i < len(all); i += n {
+		kvs := all[i:]
+		if len(kvs) > n {
+			kvs = kvs[:n]
+		}
+		instances := make([]*pb.MicroServiceInstance, 0, len(kvs))
+		for _, kv := range kvs {
+			instances = append(instances, kv.Value.(*pb.MicroServiceInstance))
+		}
+		if err := f(instances); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func GetInstanceCountOfOneService(ctx context.Context, domainProject string, serviceId string) (int64, error) {
 	key := apt.GenerateInstanceKey(domainProject, serviceId, "")
 	opts := append(FromContext(ctx),
@@ -114,6 +168,9 @@ func InstanceExistById(ctx context.Context, domainProject string, serviceId stri
 	return true, nil
 }
 
+// InstanceExist returns the id of the existing instance the request
+// registers again, the instances are matched by the instance id only,
+// an instance without id is always new even if the endpoints are the same
 func InstanceExist(ctx context.Context, instance *pb.MicroServiceInstance) (string, *scerr.Error) {
 	domainProject := util.ParseDomainProject(ctx)
 	// check id index
@@ -129,6 +186,29 @@ func InstanceExist(ctx context.Context, instance *pb.MicroServiceInstance) (stri
 	return "", nil
 }
 
+// InstanceConflictPolicy returns the policy when an instance of the
+// service registers with the id of an existing instance, the invalid
+// values fall back to the config, then INSTANCE_CONFLICT_REUSE
+func InstanceConflictPolicy(service *pb.MicroService) string {
+	if service != nil {
+		if policy := service.Properties[pb.PROP_INSTANCE_CONFLICT_POLICY]; isInstanceConflictPolicy(policy) {
+			return policy
+		}
+	}
+	if policy := apt.ServerInfo.Config.InstanceConflictPolicy; isInstanceConflictPolicy(policy) {
+		return policy
+	}
+	return pb.INSTANCE_CONFLICT_REUSE
+}
+
+func isInstanceConflictPolicy(policy string) bool {
+	switch policy {
+	case pb.INSTANCE_CONFLICT_REUSE, pb.INSTANCE_CONFLICT_REPLACE, pb.INSTANCE_CONFLICT_REJECT:
+		return true
+	}
+	return false
+}
+
 type EndpointIndexValue struct {
 	serviceId  string
 	instanceId string
@@ -161,6 +241,10 @@ func DeleteServiceAllInstances(ctx context.Context, serviceId string) error {
 	}
 	for _, v := range resp.Kvs {
 		leaseID, _ := strconv.ParseInt(v.Value.(string), 10, 64)
+		_, instanceId, _ := apt.GetInfoFromInstKV(v.Key)
+		if !backend.IsDryRun(ctx) {
+			lease.MarkRevoked(ctx, domainProject, serviceId, instanceId)
+		}
 		backend.Registry().LeaseRevoke(ctx, leaseID)
 	}
 	return nil
@@ -239,38 +323,60 @@ func queryServiceInstancesKvs(ctx context.Context, serviceId string, rev int64)
 }
 
 func UpdateInstance(ctx context.Context, domainProject string, instance *pb.MicroServiceInstance) *scerr.Error {
+	_, err := UpdateInstanceIfMatch(ctx, domainProject, instance, 0)
+	return err
+}
+
+// UpdateInstanceIfMatch updates the instance if its mod revision is still
+// rev, or unconditionally if rev is 0, and returns the new mod revision
+func UpdateInstanceIfMatch(ctx context.Context, domainProject string, instance *pb.MicroServiceInstance, rev int64) (int64, *scerr.Error) {
 	leaseID, err := GetLeaseId(ctx, domainProject, instance.ServiceId, instance.InstanceId)
 	if err != nil {
-		return scerr.NewError(scerr.ErrInternal, err.Error())
+		return 0, scerr.NewError(scerr.ErrInternal, err.Error())
 	}
 	if leaseID == -1 {
-		return scerr.NewError(scerr.ErrInstanceNotExists, "Instance's leaseId not exist.")
+		return 0, scerr.NewError(scerr.ErrInstanceNotExists, "Instance's leaseId not exist.")
 	}
 
 	instance.ModTimestamp = strconv.FormatInt(time.Now().Unix(), 10)
-	data, err := json.Marshal(instance)
+	data, err := util.JsonMarshal(instance.WithoutRevision())
 	if err != nil {
-		return scerr.NewError(scerr.ErrInternal, err.Error())
+		return 0, scerr.NewError(scerr.ErrInternal, err.Error())
 	}
 
 	key := apt.GenerateInstanceKey(domainProject, instance.ServiceId, instance.InstanceId)
 
+	cmps := []registry.CompareOp{registry.OpCmp(
+		registry.CmpVer(util.StringToBytesWithNoCopy(apt.GenerateServiceKey(domainProject, instance.ServiceId))),
+		registry.CMP_NOT_EQUAL, 0)}
+	var fail []registry.PluginOp
+	if rev > 0 {
+		cmps = append(cmps, registry.OpCmp(registry.CmpStrModRev(key), registry.CMP_EQUAL, rev))
+		fail = append(fail, registry.OpGet(registry.WithStrKey(key)))
+	}
 	resp, err := backend.Registry().TxnWithCmp(ctx,
 		[]registry.PluginOp{registry.OpPut(
 			registry.WithStrKey(key),
 			registry.WithValue(data),
 			registry.WithLease(leaseID))},
-		[]registry.CompareOp{registry.OpCmp(
-			registry.CmpVer(util.StringToBytesWithNoCopy(apt.GenerateServiceKey(domainProject, instance.ServiceId))),
-			registry.CMP_NOT_EQUAL, 0)},
-		nil)
+		cmps,
+		fail)
 	if err != nil {
-		return scerr.NewError(scerr.ErrUnavailableBackend, err.Error())
+		return 0, scerr.NewError(scerr.ErrUnavailableBackend, err.Error())
 	}
 	if !resp.Succeeded {
-		return scerr.NewError(scerr.ErrServiceNotExists, "Service does not exist.")
+		if rev > 0 {
+			if len(resp.Kvs) == 0 {
+				return 0, scerr.NewError(scerr.ErrInstanceNotExists, "Service instance does not exist.")
+			}
+			if modRev := resp.Kvs[0].ModRevision; modRev != rev {
+				return 0, scerr.NewErrorf(scerr.ErrRevisionConflict,
+					"Instance revision is %d, not %d.", modRev, rev)
+			}
+		}
+		return 0, scerr.NewError(scerr.ErrServiceNotExists, "Service does not exist.")
 	}
-	return nil
+	return resp.Revision, nil
 }
 
 func AppendFindResponse(ctx context.Context, index int64, find *pb.FindInstancesResponse,
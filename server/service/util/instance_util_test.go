@@ -40,6 +40,14 @@ func TestFormatRevision(t *testing.T) {
 	}
 }
 
+func BenchmarkFormatRevision(b *testing.B) {
+	b.ReportAllocs()
+	revs, counts := []int64{100, 200}, []int64{10, 20}
+	for i := 0; i < b.N; i++ {
+		FormatRevision(revs, counts)
+	}
+}
+
 func TestGetLeaseId(t *testing.T) {
 	_, err := GetLeaseId(context.Background(), "", "", "")
 	if err != nil {
@@ -17,7 +17,6 @@
 package util
 
 import (
-	"encoding/json"
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/pkg/util"
 	apt "github.com/apache/servicecomb-service-center/server/core"
@@ -210,7 +209,7 @@ func RemandInstanceQuota(ctx context.Context) {
 func UpdateService(domainProject string, serviceId string, service *pb.MicroService) (opt registry.PluginOp, err error) {
 	opt = registry.PluginOp{}
 	key := apt.GenerateServiceKey(domainProject, serviceId)
-	data, err := json.Marshal(service)
+	data, err := util.JsonMarshal(service.WithoutRevision())
 	if err != nil {
 		log.Errorf(err, "marshal service file failed")
 		return
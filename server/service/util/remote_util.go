@@ -0,0 +1,67 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package util
+
+import (
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"golang.org/x/net/context"
+	"strings"
+)
+
+// the separator of the local and remote revisions in the response
+// revision of finding instances with the remote ones
+const remoteRevSeparator = "-"
+
+// RemoteFinder finds the instances of the provider registered in the
+// service centers of the other datacenters, the version of the provider
+// is a version rule. Rev changes when the result changes
+type RemoteFinder interface {
+	FindRemote(ctx context.Context, provider *pb.MicroServiceKey) (instances []*pb.MicroServiceInstance, rev string)
+}
+
+var remoteFinder RemoteFinder
+
+// SetRemoteFinder is called when the module initializes
+func SetRemoteFinder(f RemoteFinder) {
+	remoteFinder = f
+}
+
+func FindRemoteInstances(ctx context.Context, provider *pb.MicroServiceKey) ([]*pb.MicroServiceInstance, string) {
+	if remoteFinder == nil {
+		return nil, ""
+	}
+	return remoteFinder.FindRemote(ctx, provider)
+}
+
+// IncludeRemote returns true if the remote instances are requested
+func IncludeRemote(ctx context.Context) bool {
+	return ctx.Value(CTX_INCLUDE_REMOTE) == "1"
+}
+
+func JoinRemoteRevision(localRev, remoteRev string) string {
+	return localRev + remoteRevSeparator + remoteRev
+}
+
+// SplitRemoteRevision returns the local and remote revision joined by
+// JoinRemoteRevision
+func SplitRemoteRevision(rev string) (localRev, remoteRev string) {
+	i := strings.LastIndex(rev, remoteRevSeparator)
+	if i < 0 {
+		return rev, ""
+	}
+	return rev[:i], rev[i+1:]
+}
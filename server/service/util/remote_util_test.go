@@ -0,0 +1,58 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package util
+
+import (
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"golang.org/x/net/context"
+	"testing"
+)
+
+type mockRemoteFinder struct {
+}
+
+func (f *mockRemoteFinder) FindRemote(ctx context.Context, provider *pb.MicroServiceKey) ([]*pb.MicroServiceInstance, string) {
+	return []*pb.MicroServiceInstance{{InstanceId: "remote"}}, "1"
+}
+
+func TestRemoteRevision(t *testing.T) {
+	rev := JoinRemoteRevision("abc", "def")
+	if l, r := SplitRemoteRevision(rev); l != "abc" || r != "def" {
+		t.Fatalf("TestRemoteRevision failed, %s %s", l, r)
+	}
+	if l, r := SplitRemoteRevision(JoinRemoteRevision("", "def")); l != "" || r != "def" {
+		t.Fatalf("TestRemoteRevision failed, %s %s", l, r)
+	}
+	if l, r := SplitRemoteRevision("abc"); l != "abc" || r != "" {
+		t.Fatalf("TestRemoteRevision failed, %s %s", l, r)
+	}
+}
+
+func TestFindRemoteInstances(t *testing.T) {
+	defer SetRemoteFinder(nil)
+	if instances, rev := FindRemoteInstances(context.Background(), &pb.MicroServiceKey{}); instances != nil || rev != "" {
+		t.Fatalf("TestFindRemoteInstances failed, %v", instances)
+	}
+	SetRemoteFinder(&mockRemoteFinder{})
+	if instances, rev := FindRemoteInstances(context.Background(), &pb.MicroServiceKey{}); len(instances) != 1 || rev != "1" {
+		t.Fatalf("TestFindRemoteInstances failed, %v", instances)
+	}
+	if IncludeRemote(context.Background()) ||
+		!IncludeRemote(context.WithValue(context.Background(), CTX_INCLUDE_REMOTE, "1")) {
+		t.Fatalf("TestFindRemoteInstances failed")
+	}
+}
@@ -20,10 +20,20 @@ import (
 	"errors"
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"github.com/apache/servicecomb-service-center/server/plugin"
 	"reflect"
 )
 
+// Validate checks the request by the buildin validators, then by the
+// validator plugin for the constraints of the deployment
 func Validate(v interface{}) error {
+	if err := validateRequest(v); err != nil {
+		return err
+	}
+	return plugin.Plugins().Validator().Validate(v)
+}
+
+func validateRequest(v interface{}) error {
 	if v == nil {
 		return errors.New("data is nil")
 	}
@@ -18,14 +18,17 @@ package service
 
 import (
 	"errors"
+	"fmt"
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/pkg/util"
 	apt "github.com/apache/servicecomb-service-center/server/core"
 	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
 	nf "github.com/apache/servicecomb-service-center/server/service/notification"
 	serviceUtil "github.com/apache/servicecomb-service-center/server/service/util"
 	"github.com/gorilla/websocket"
 	"golang.org/x/net/context"
+	"net/http"
 )
 
 func (s *InstanceService) WatchPreOpera(ctx context.Context, in *pb.WatchInstanceRequest) error {
@@ -42,32 +45,165 @@ func (s *InstanceService) WatchPreOpera(ctx context.Context, in *pb.WatchInstanc
 func (s *InstanceService) Watch(in *pb.WatchInstanceRequest, stream pb.ServiceInstanceCtrl_WatchServer) error {
 	var err error
 	if err = s.WatchPreOpera(stream.Context(), in); err != nil {
-		log.Errorf(err, "service[%s] establish watch failed: invalid params", in.SelfServiceId)
+		log.WithContext(stream.Context()).Errorf(err, "service[%s] establish watch failed: invalid params", in.SelfServiceId)
 		return err
 	}
 	domainProject := util.ParseDomainProject(stream.Context())
 	watcher := nf.NewListWatcher(in.SelfServiceId, apt.GetInstanceRootKey(domainProject)+"/", nil)
+	watcher.SetRemote("grpc", util.GetIPFromContext(stream.Context()))
 	err = nf.GetNotifyService().AddSubscriber(watcher)
-	log.Infof("watcher[%s/%s] start watch instance status", watcher.Subject(), watcher.Group())
+	log.WithContext(stream.Context()).Infof("watcher[%s/%s] start watch instance status", watcher.Subject(), watcher.Group())
 	return nf.HandleWatchJob(watcher, stream)
 }
 
 func (s *InstanceService) WebSocketWatch(ctx context.Context, in *pb.WatchInstanceRequest, conn *websocket.Conn) {
-	log.Infof("new a web socket watch with service[%s]", in.SelfServiceId)
+	log.WithContext(ctx).Infof("new a web socket watch with service[%s]", in.SelfServiceId)
 	if err := s.WatchPreOpera(ctx, in); err != nil {
 		nf.EstablishWebSocketError(conn, err)
 		return
 	}
-	nf.DoWebSocketListAndWatch(ctx, in.SelfServiceId, nil, conn)
+	since, _ := ctx.Value(serviceUtil.CTX_SINCE_REVISION).(int64)
+	if since <= 0 {
+		nf.DoWebSocketListAndWatch(ctx, in.SelfServiceId, nil, conn)
+		return
+	}
+	if _, ok := nf.GetReplayBuffer().Since(util.ParseDomainProject(ctx), since, in.SelfServiceId); !ok {
+		nf.EstablishWebSocketError(conn, scerr.NewErrorf(scerr.ErrRevisionExpired,
+			"can not resume from revision %d, list and watch again", since))
+		return
+	}
+	nf.DoWebSocketListAndWatch(ctx, in.SelfServiceId, replayFunc(ctx, in.SelfServiceId, since), conn)
 }
 
 func (s *InstanceService) WebSocketListAndWatch(ctx context.Context, in *pb.WatchInstanceRequest, conn *websocket.Conn) {
-	log.Infof("new a web socket list and watch with service[%s]", in.SelfServiceId)
+	log.WithContext(ctx).Infof("new a web socket list and watch with service[%s]", in.SelfServiceId)
 	if err := s.WatchPreOpera(ctx, in); err != nil {
 		nf.EstablishWebSocketError(conn, err)
 		return
 	}
+	since, _ := ctx.Value(serviceUtil.CTX_SINCE_REVISION).(int64)
+	if since > 0 {
+		if _, ok := nf.GetReplayBuffer().Since(util.ParseDomainProject(ctx), since, in.SelfServiceId); ok {
+			nf.DoWebSocketListAndWatch(ctx, in.SelfServiceId, replayFunc(ctx, in.SelfServiceId, since), conn)
+			return
+		}
+		log.WithContext(ctx).Warnf("service[%s] can not resume from revision %d, list all instances", in.SelfServiceId, since)
+	}
 	nf.DoWebSocketListAndWatch(ctx, in.SelfServiceId, func() ([]*pb.WatchInstanceResponse, int64) {
 		return serviceUtil.QueryAllProvidersInstances(ctx, in.SelfServiceId)
 	}, conn)
 }
+
+func (s *InstanceService) SSEWatch(ctx context.Context, in *pb.WatchInstanceRequest, w http.ResponseWriter) error {
+	log.WithContext(ctx).Infof("new a sse watch with service[%s]", in.SelfServiceId)
+	if err := s.WatchPreOpera(ctx, in); err != nil {
+		return scerr.NewError(scerr.ErrInvalidParams, err.Error())
+	}
+	since, _ := ctx.Value(serviceUtil.CTX_SINCE_REVISION).(int64)
+	if since <= 0 {
+		return nf.DoServerSentEventsListAndWatch(ctx, in.SelfServiceId, nil, w)
+	}
+	if _, ok := nf.GetReplayBuffer().Since(util.ParseDomainProject(ctx), since, in.SelfServiceId); !ok {
+		return scerr.NewErrorf(scerr.ErrRevisionExpired,
+			"can not resume from revision %d, list and watch again", since)
+	}
+	return nf.DoServerSentEventsListAndWatch(ctx, in.SelfServiceId, replayFunc(ctx, in.SelfServiceId, since), w)
+}
+
+func (s *InstanceService) SSEListAndWatch(ctx context.Context, in *pb.WatchInstanceRequest, w http.ResponseWriter) error {
+	log.WithContext(ctx).Infof("new a sse list and watch with service[%s]", in.SelfServiceId)
+	if err := s.WatchPreOpera(ctx, in); err != nil {
+		return scerr.NewError(scerr.ErrInvalidParams, err.Error())
+	}
+	since, _ := ctx.Value(serviceUtil.CTX_SINCE_REVISION).(int64)
+	if since > 0 {
+		if _, ok := nf.GetReplayBuffer().Since(util.ParseDomainProject(ctx), since, in.SelfServiceId); ok {
+			return nf.DoServerSentEventsListAndWatch(ctx, in.SelfServiceId, replayFunc(ctx, in.SelfServiceId, since), w)
+		}
+		log.WithContext(ctx).Warnf("service[%s] can not resume from revision %d, list all instances", in.SelfServiceId, since)
+	}
+	return nf.DoServerSentEventsListAndWatch(ctx, in.SelfServiceId, func() ([]*pb.WatchInstanceResponse, int64) {
+		return serviceUtil.QueryAllProvidersInstances(ctx, in.SelfServiceId)
+	}, w)
+}
+
+func (s *InstanceService) WebSocketResourceWatch(ctx context.Context, in *pb.WatchResourceRequest, conn *websocket.Conn) {
+	log.WithContext(ctx).Infof("new a web socket resource watch with service[%s]", in.SelfServiceId)
+	if err := s.resourceWatchPreOpera(ctx, in); err != nil {
+		nf.EstablishWebSocketError(conn, err)
+		return
+	}
+	nf.DoWebSocketResourceWatch(ctx,
+		nf.NewResourceWatcher(util.ParseDomainProject(ctx), in.ProviderIds, in.Resources), conn)
+}
+
+func (s *InstanceService) resourceWatchPreOpera(ctx context.Context, in *pb.WatchResourceRequest) error {
+	if in == nil || len(in.SelfServiceId) == 0 || len(in.ProviderIds) == 0 {
+		return errors.New("Request format invalid.")
+	}
+	for _, r := range in.Resources {
+		if !util.SliceHave(pb.WatchResources, r) {
+			return fmt.Errorf("Unknown resource '%s'.", r)
+		}
+	}
+	domainProject := util.ParseDomainProject(ctx)
+	if !serviceUtil.ServiceExist(ctx, domainProject, in.SelfServiceId) {
+		return errors.New("Service does not exist.")
+	}
+	return nil
+}
+
+// replayFunc returns the list function of the watcher resuming from
+// revision since, it lists the retained events after since
+func replayFunc(ctx context.Context, serviceId string, since int64) func() ([]*pb.WatchInstanceResponse, int64) {
+	return func() ([]*pb.WatchInstanceResponse, int64) {
+		events, _ := nf.GetReplayBuffer().Since(util.ParseDomainProject(ctx), since, serviceId)
+		rev := since
+		results := make([]*pb.WatchInstanceResponse, 0, len(events))
+		for _, evt := range events {
+			results = append(results, evt.ToResponse())
+			rev = evt.Revision
+		}
+		return results, rev
+	}
+}
+
+func (s *InstanceService) GetWatchEvents(ctx context.Context, in *pb.GetWatchEventsRequest) (*pb.GetWatchEventsResponse, error) {
+	if in == nil || in.Since < 0 {
+		return &pb.GetWatchEventsResponse{
+			Response: pb.CreateResponse(scerr.ErrInvalidParams, "Request format invalid."),
+		}, nil
+	}
+	domainProject := util.ParseDomainProject(ctx)
+	if len(in.SelfServiceId) > 0 && !serviceUtil.ServiceExist(ctx, domainProject, in.SelfServiceId) {
+		return &pb.GetWatchEventsResponse{
+			Response: pb.CreateResponse(scerr.ErrServiceNotExists, "Service does not exist."),
+		}, nil
+	}
+
+	buffer := nf.GetReplayBuffer()
+	// get the latest revision first, so events after it will be got in the next polling
+	rev := buffer.Revision(domainProject)
+	events, ok := buffer.Since(domainProject, in.Since, in.SelfServiceId)
+	if !ok {
+		log.WithContext(ctx).Warnf("service[%s] get events failed, revision %d is out of the retained range",
+			in.SelfServiceId, in.Since)
+		return &pb.GetWatchEventsResponse{
+			Response: pb.CreateResponse(scerr.ErrRevisionExpired,
+				fmt.Sprintf("Revision %d is out of the retained range, list all instances again.", in.Since)),
+		}, nil
+	}
+	for _, evt := range events {
+		if evt.Revision > rev {
+			rev = evt.Revision
+		}
+	}
+	if rev < in.Since {
+		rev = in.Since
+	}
+	return &pb.GetWatchEventsResponse{
+		Response: pb.CreateResponse(pb.Response_SUCCESS, "Get watch events successfully."),
+		Revision: rev,
+		Events:   events,
+	}, nil
+}
@@ -0,0 +1,101 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package simple
+
+import (
+	"crypto/subtle"
+	"errors"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/core"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/rest/controller"
+	"golang.org/x/net/context"
+	"net/http"
+	"strings"
+)
+
+// SimpleController serves the spring cloud DiscoveryClient shaped api for
+// the lightweight clients and scripts. The domain is specified by the
+// 'X-Domain-Name' header and the project by the 'project' query, both
+// default to 'default'. If 'simple_api_token' is set, the requests must
+// carry it by the 'Authorization: Bearer {token}' header or 'token' query
+type SimpleController struct {
+}
+
+func (ctrl *SimpleController) URLPatterns() []rest.Route {
+	return []rest.Route{
+		{rest.HTTP_METHOD_GET, "/discovery/services", ctrl.Services},
+		{rest.HTTP_METHOD_GET, "/discovery/instances/:service", ctrl.Instances},
+	}
+}
+
+func simpleContext(r *http.Request) context.Context {
+	domain := r.Header.Get("X-Domain-Name")
+	if len(domain) == 0 {
+		domain = core.REGISTRY_DOMAIN
+	}
+	project := r.URL.Query().Get("project")
+	if len(project) == 0 {
+		project = core.REGISTRY_PROJECT
+	}
+	return util.SetDomainProject(r.Context(), domain, project)
+}
+
+func authenticate(r *http.Request) error {
+	if len(token) == 0 {
+		return nil
+	}
+	t := r.URL.Query().Get("token")
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		t = strings.TrimPrefix(auth, "Bearer ")
+	}
+	if subtle.ConstantTimeCompare([]byte(t), []byte(token)) != 1 {
+		return errors.New("invalid token")
+	}
+	return nil
+}
+
+func (ctrl *SimpleController) Services(w http.ResponseWriter, r *http.Request) {
+	if err := authenticate(r); err != nil {
+		controller.WriteError(w, scerr.ErrUnauthorized, err.Error())
+		return
+	}
+	ctx := simpleContext(r)
+	names, err := Services(ctx, util.ParseDomainProject(ctx), r.URL.Query().Get("app"))
+	if err != nil {
+		controller.WriteError(w, scerr.ErrInternal, err.Error())
+		return
+	}
+	controller.WriteResponse(w, nil, names)
+}
+
+func (ctrl *SimpleController) Instances(w http.ResponseWriter, r *http.Request) {
+	if err := authenticate(r); err != nil {
+		controller.WriteError(w, scerr.ErrUnauthorized, err.Error())
+		return
+	}
+	ctx := simpleContext(r)
+	query := r.URL.Query()
+	instances, err := Instances(ctx, util.ParseDomainProject(ctx),
+		query.Get("app"), query.Get(":service"), query.Get("scheme"))
+	if err != nil {
+		controller.WriteError(w, scerr.ErrInternal, err.Error())
+		return
+	}
+	controller.WriteResponse(w, nil, instances)
+}
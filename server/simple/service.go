@@ -0,0 +1,137 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package simple
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	serviceUtil "github.com/apache/servicecomb-service-center/server/service/util"
+	"golang.org/x/net/context"
+	"net/url"
+	"sort"
+	"strconv"
+)
+
+func cacheOnly(ctx context.Context) context.Context {
+	return util.SetContext(ctx, serviceUtil.CTX_CACHEONLY, "1")
+}
+
+// Services returns the sorted names of the services, if appId is not
+// empty, only returns the ones in the application
+func Services(ctx context.Context, domainProject, appId string) ([]string, error) {
+	services, err := serviceUtil.GetServicesByDomainProject(cacheOnly(ctx), domainProject)
+	if err != nil {
+		return nil, err
+	}
+	exists := make(map[string]struct{}, len(services))
+	names := []string{}
+	for _, service := range services {
+		if len(appId) > 0 && service.AppId != appId {
+			continue
+		}
+		if _, ok := exists[service.ServiceName]; ok {
+			continue
+		}
+		exists[service.ServiceName] = struct{}{}
+		names = append(names, service.ServiceName)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Instances returns the UP instances of all the versions of the services
+// named name, if appId is not empty, only returns the ones in the
+// application. The address of the instance is its first endpoint of the
+// scheme, or its first endpoint if scheme is empty
+func Instances(ctx context.Context, domainProject, appId, name, scheme string) ([]*ServiceInstance, error) {
+	ctx = cacheOnly(ctx)
+	services, err := serviceUtil.GetServicesByDomainProject(ctx, domainProject)
+	if err != nil {
+		return nil, err
+	}
+	instances := []*ServiceInstance{}
+	for _, service := range services {
+		if service.ServiceName != name || (len(appId) > 0 && service.AppId != appId) {
+			continue
+		}
+		insts, err := serviceUtil.GetAllInstancesOfOneService(ctx, domainProject, service.ServiceId)
+		if err != nil {
+			return nil, err
+		}
+		for _, inst := range insts {
+			if inst.Status != pb.MSI_UP {
+				continue
+			}
+			if instance := toServiceInstance(service, inst, scheme); instance != nil {
+				instances = append(instances, instance)
+			}
+		}
+	}
+	return instances, nil
+}
+
+// toServiceInstance returns nil if the instance has no endpoint of the
+// scheme
+func toServiceInstance(service *pb.MicroService, instance *pb.MicroServiceInstance, scheme string) *ServiceInstance {
+	var endpoint *url.URL
+	for _, ep := range instance.Endpoints {
+		u, err := url.Parse(ep)
+		if err != nil || len(u.Host) == 0 {
+			continue
+		}
+		if len(scheme) == 0 || u.Scheme == scheme {
+			endpoint = u
+			break
+		}
+	}
+	if endpoint == nil {
+		return nil
+	}
+
+	ipPort := util.ParseIpPort(endpoint.Host)
+	secure := endpoint.Query().Get("sslEnabled") == "true"
+	uriScheme := endpoint.Scheme
+	if uriScheme == "rest" {
+		// the rest endpoint is served by http
+		uriScheme = "http"
+		if secure {
+			uriScheme = "https"
+		}
+	}
+
+	meta := make(map[string]string, len(instance.Properties)+4)
+	for k, v := range instance.Properties {
+		meta[k] = v
+	}
+	meta["app"] = service.AppId
+	meta["version"] = service.Version
+	meta["serviceId"] = service.ServiceId
+	if len(instance.HostName) > 0 {
+		meta["hostName"] = instance.HostName
+	}
+
+	return &ServiceInstance{
+		InstanceId: instance.InstanceId,
+		ServiceId:  service.ServiceName,
+		Host:       ipPort.IP,
+		Port:       int(ipPort.Port),
+		Secure:     secure,
+		Scheme:     uriScheme,
+		Uri:        uriScheme + "://" + ipPort.IP + ":" + strconv.Itoa(int(ipPort.Port)),
+		Metadata:   meta,
+	}
+}
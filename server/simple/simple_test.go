@@ -0,0 +1,74 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package simple
+
+import (
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"net/http"
+	"testing"
+)
+
+func TestToServiceInstance(t *testing.T) {
+	service := &pb.MicroService{ServiceId: "s", AppId: "a", ServiceName: "n", Version: "1.0.0"}
+	instance := &pb.MicroServiceInstance{
+		InstanceId: "i",
+		HostName:   "h",
+		Status:     pb.MSI_UP,
+		Endpoints:  []string{"rest://127.0.0.1:8080/?sslEnabled=true", "highway://127.0.0.1:8081"},
+		Properties: map[string]string{"k": "v"},
+	}
+	si := toServiceInstance(service, instance, "")
+	if si == nil || si.InstanceId != "i" || si.ServiceId != "n" || si.Host != "127.0.0.1" || si.Port != 8080 ||
+		!si.Secure || si.Scheme != "https" || si.Uri != "https://127.0.0.1:8080" ||
+		si.Metadata["k"] != "v" || si.Metadata["app"] != "a" || si.Metadata["version"] != "1.0.0" {
+		t.Fatalf("TestToServiceInstance failed, %v", si)
+	}
+
+	si = toServiceInstance(service, instance, "highway")
+	if si == nil || si.Port != 8081 || si.Secure || si.Uri != "highway://127.0.0.1:8081" {
+		t.Fatalf("TestToServiceInstance failed, %v", si)
+	}
+
+	if si = toServiceInstance(service, instance, "grpc"); si != nil {
+		t.Fatalf("TestToServiceInstance failed, %v", si)
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	r, _ := http.NewRequest(http.MethodGet, "/discovery/services", nil)
+	if err := authenticate(r); err != nil {
+		t.Fatalf("TestAuthenticate failed, %v", err)
+	}
+
+	token = "secret"
+	defer func() { token = "" }()
+	if err := authenticate(r); err == nil {
+		t.Fatalf("TestAuthenticate failed")
+	}
+	r.Header.Set("Authorization", "Bearer secret")
+	if err := authenticate(r); err != nil {
+		t.Fatalf("TestAuthenticate failed, %v", err)
+	}
+	r, _ = http.NewRequest(http.MethodGet, "/discovery/services?token=secret", nil)
+	if err := authenticate(r); err != nil {
+		t.Fatalf("TestAuthenticate failed, %v", err)
+	}
+	r.Header.Set("Authorization", "Bearer other")
+	if err := authenticate(r); err == nil {
+		t.Fatalf("TestAuthenticate failed")
+	}
+}
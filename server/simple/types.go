@@ -0,0 +1,32 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package simple
+
+// the Spring Cloud DiscoveryClient shaped types
+
+// ServiceInstance is the element of '/discovery/instances/:service'
+// response, the same as the spring cloud ServiceInstance
+type ServiceInstance struct {
+	InstanceId string            `json:"instanceId"`
+	ServiceId  string            `json:"serviceId"`
+	Host       string            `json:"host"`
+	Port       int               `json:"port"`
+	Secure     bool              `json:"secure"`
+	Scheme     string            `json:"scheme"`
+	Uri        string            `json:"uri"`
+	Metadata   map[string]string `json:"metadata"`
+}
@@ -0,0 +1,96 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package mqtt
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/client/mqtt"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	nf "github.com/apache/servicecomb-service-center/server/service/notification"
+	"sync"
+)
+
+const name = "mqtt"
+
+func init() {
+	nf.RegisterSink(name, New)
+}
+
+// Sink publishes events to topic '{topic}/{domain}/{project}'
+type Sink struct {
+	Cfg      nf.SinkConfig
+	clientId string
+	client   *mqtt.Client
+	lock     sync.Mutex
+}
+
+func (s *Sink) Name() string {
+	return name
+}
+
+func (s *Sink) getClient() (*mqtt.Client, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.client != nil && s.client.Err() == nil {
+		return s.client, nil
+	}
+	if s.client != nil {
+		log.Warnf("mqtt connection broken, %s, reconnect to %s", s.client.Err(), s.Cfg.Addr)
+		s.client.Close()
+		s.client = nil
+	}
+	client, err := mqtt.NewMQTTClient(mqtt.Config{
+		Addr:     s.Cfg.Addr,
+		ClientId: s.clientId,
+		User:     s.Cfg.User,
+		Password: s.Cfg.Password,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.client = client
+	return client, nil
+}
+
+func (s *Sink) Publish(evt *nf.SinkEvent, qos int) error {
+	data, err := evt.Encode(s.Cfg.Format)
+	if err != nil {
+		return err
+	}
+	client, err := s.getClient()
+	if err != nil {
+		return err
+	}
+	return client.Publish(s.Cfg.Topic+"/"+evt.DomainProject, data, byte(qos))
+}
+
+func (s *Sink) Close() {
+	s.lock.Lock()
+	if s.client != nil {
+		s.client.Close()
+		s.client = nil
+	}
+	s.lock.Unlock()
+}
+
+func New(cfg nf.SinkConfig) (nf.EventSink, error) {
+	return &Sink{
+		Cfg: cfg,
+		// the client identifier must be unique in broker
+		clientId: "sc-" + util.GenerateUuid()[:16],
+	}, nil
+}
@@ -0,0 +1,99 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package nats
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/client/nats"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/server/core"
+	nf "github.com/apache/servicecomb-service-center/server/service/notification"
+	"sync"
+)
+
+const name = "nats"
+
+func init() {
+	nf.RegisterSink(name, New)
+}
+
+// Sink publishes events to subject '{topic}.{domain}.{project}',
+// QoS 1 means flushing after every publishing
+type Sink struct {
+	Cfg    nf.SinkConfig
+	client *nats.Client
+	lock   sync.Mutex
+}
+
+func (s *Sink) Name() string {
+	return name
+}
+
+func (s *Sink) getClient() (*nats.Client, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.client != nil && s.client.Err() == nil {
+		return s.client, nil
+	}
+	if s.client != nil {
+		log.Warnf("nats connection broken, %s, reconnect to %s", s.client.Err(), s.Cfg.Addr)
+		s.client.Close()
+		s.client = nil
+	}
+	client, err := nats.NewNATSClient(nats.Config{
+		Addr:     s.Cfg.Addr,
+		Name:     core.Service.ServiceName,
+		User:     s.Cfg.User,
+		Password: s.Cfg.Password,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.client = client
+	return client, nil
+}
+
+func (s *Sink) Publish(evt *nf.SinkEvent, qos int) error {
+	data, err := evt.Encode(s.Cfg.Format)
+	if err != nil {
+		return err
+	}
+	client, err := s.getClient()
+	if err != nil {
+		return err
+	}
+	subject := s.Cfg.Topic + "." + evt.Domain() + "." + evt.Project()
+	if err := client.Publish(subject, data); err != nil {
+		return err
+	}
+	if qos > 0 {
+		return client.Flush(client.Cfg.Timeout)
+	}
+	return nil
+}
+
+func (s *Sink) Close() {
+	s.lock.Lock()
+	if s.client != nil {
+		s.client.Close()
+		s.client = nil
+	}
+	s.lock.Unlock()
+}
+
+func New(cfg nf.SinkConfig) (nf.EventSink, error) {
+	return &Sink{Cfg: cfg}, nil
+}
@@ -0,0 +1,72 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package syncer
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/core"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/rest/controller"
+	"net/http"
+	"strconv"
+)
+
+// SyncerController serves the changes of the local instances to the
+// peer datacenters, requires the admin permission
+type SyncerController struct {
+}
+
+func (ctrl *SyncerController) URLPatterns() []rest.Route {
+	return []rest.Route{
+		{rest.HTTP_METHOD_GET, "/v4/:project/admin/syncer/changes", ctrl.Changes},
+		{rest.HTTP_METHOD_GET, "/v4/:project/admin/syncer/status", ctrl.Status},
+	}
+}
+
+func isAdmin(r *http.Request) bool {
+	return core.IsDefaultDomainProject(util.ParseDomainProject(r.Context()))
+}
+
+func (ctrl *SyncerController) Changes(w http.ResponseWriter, r *http.Request) {
+	if !isAdmin(r) {
+		controller.WriteError(w, scerr.ErrForbidden, "Required admin permission")
+		return
+	}
+	query := r.URL.Query()
+	since, err := strconv.ParseInt(query.Get("since"), 10, 64)
+	if err != nil && len(query.Get("since")) > 0 {
+		controller.WriteError(w, scerr.ErrInvalidParams, "Invalid since")
+		return
+	}
+	resp := journal.Changes(query.Get("epoch"), since)
+	respInternal := resp.Response
+	resp.Response = nil
+	controller.WriteResponse(w, respInternal, resp)
+}
+
+func (ctrl *SyncerController) Status(w http.ResponseWriter, r *http.Request) {
+	if !isAdmin(r) {
+		controller.WriteError(w, scerr.ErrForbidden, "Required admin permission")
+		return
+	}
+	controller.WriteResponse(w, nil, map[string]interface{}{
+		"datacenter": journal.Datacenter,
+		"epoch":      journal.Epoch(),
+		"peers":      store.Status(),
+	})
+}
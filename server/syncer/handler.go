@@ -0,0 +1,73 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package syncer
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	apt "github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/core/backend"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/discovery"
+	serviceUtil "github.com/apache/servicecomb-service-center/server/service/util"
+	"golang.org/x/net/context"
+)
+
+// InstanceEventHandler logs the changes of the local instances to the
+// journal
+type InstanceEventHandler struct {
+	Journal *Journal
+}
+
+func (h *InstanceEventHandler) Type() discovery.Type {
+	return backend.INSTANCE
+}
+
+func (h *InstanceEventHandler) OnEvent(evt discovery.KvEvent) {
+	instance, ok := evt.KV.Value.(*pb.MicroServiceInstance)
+	if !ok {
+		return
+	}
+	providerId, _, domainProject := apt.GetInfoFromInstKV(evt.KV.Key)
+	record := &pb.SyncRecord{
+		Revision:      evt.KV.ModRevision,
+		DomainProject: domainProject,
+		Instance:      instance,
+	}
+	if evt.Type == pb.EVT_DELETE {
+		// the tombstone is newer than any revision of the instance
+		record.Revision = evt.Revision
+		record.Deleted = true
+		h.Journal.Append(record)
+		return
+	}
+
+	ctx := context.WithValue(context.WithValue(context.Background(),
+		serviceUtil.CTX_CACHEONLY, "1"),
+		serviceUtil.CTX_GLOBAL, "1")
+	ms, err := serviceUtil.GetService(ctx, domainProject, providerId)
+	if ms == nil {
+		log.Errorf(err, "caught [%s] instance[%s/%s] event, get cached provider's file failed",
+			evt.Type, providerId, instance.InstanceId)
+		return
+	}
+	record.Service = pb.MicroServiceToKey(domainProject, ms)
+	h.Journal.Append(record)
+}
+
+func NewInstanceEventHandler(journal *Journal) *InstanceEventHandler {
+	return &InstanceEventHandler{Journal: journal}
+}
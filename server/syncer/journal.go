@@ -0,0 +1,127 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package syncer
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"sort"
+	"sync"
+)
+
+// the max records returned by one incremental pull
+const maxBatchSize = 1000
+
+// Journal is the change log of the local instances, the peers pull the
+// records after the sequence they received. The peers pull the snapshot
+// of all the instances if the records they need are trimmed or the
+// journal restarted with a new epoch
+type Journal struct {
+	Datacenter string
+
+	epoch   string
+	size    int
+	seq     int64
+	records []*pb.SyncRecord
+	// state is the latest record of each live instance
+	state map[string]*pb.SyncRecord
+	lock  sync.RWMutex
+}
+
+func (j *Journal) Epoch() string {
+	return j.epoch
+}
+
+// Append assigns the sequence to the record and logs it
+func (j *Journal) Append(r *pb.SyncRecord) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	j.seq++
+	r.Seq = j.seq
+	if r.Deleted {
+		delete(j.state, r.Instance.InstanceId)
+	} else {
+		j.state[r.Instance.InstanceId] = r
+	}
+	j.records = append(j.records, r)
+	if len(j.records) > j.size {
+		// trim a quarter at once to avoid copying on every append
+		keep := j.size * 3 / 4
+		if keep < 1 {
+			keep = 1
+		}
+		j.records = append([]*pb.SyncRecord(nil), j.records[len(j.records)-keep:]...)
+	}
+}
+
+// Changes returns the records after the since sequence of the epoch
+func (j *Journal) Changes(epoch string, since int64) *pb.SyncChangesResponse {
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+	resp := &pb.SyncChangesResponse{
+		Response:   pb.CreateResponse(pb.Response_SUCCESS, "Get sync changes successfully."),
+		Datacenter: j.Datacenter,
+		Epoch:      j.epoch,
+		Seq:        j.seq,
+	}
+	if !j.continuous(epoch, since) {
+		resp.Full = true
+		resp.Records = make([]*pb.SyncRecord, 0, len(j.state))
+		for _, r := range j.state {
+			resp.Records = append(resp.Records, r)
+		}
+		sort.Sort(bySeq(resp.Records))
+		return resp
+	}
+	i := sort.Search(len(j.records), func(i int) bool {
+		return j.records[i].Seq > since
+	})
+	records := j.records[i:]
+	if len(records) > maxBatchSize {
+		records = records[:maxBatchSize]
+		resp.Seq = records[len(records)-1].Seq
+	}
+	resp.Records = append([]*pb.SyncRecord(nil), records...)
+	return resp
+}
+
+// continuous returns true if the journal keeps all the records after
+// the since sequence
+func (j *Journal) continuous(epoch string, since int64) bool {
+	if epoch != j.epoch || since <= 0 || since > j.seq {
+		return false
+	}
+	if len(j.records) == 0 {
+		return since == j.seq
+	}
+	return j.records[0].Seq <= since+1
+}
+
+type bySeq []*pb.SyncRecord
+
+func (s bySeq) Len() int           { return len(s) }
+func (s bySeq) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s bySeq) Less(i, j int) bool { return s[i].Seq < s[j].Seq }
+
+func NewJournal(dc string, size int) *Journal {
+	return &Journal{
+		Datacenter: dc,
+		epoch:      util.GenerateUuid(),
+		size:       size,
+		state:      make(map[string]*pb.SyncRecord),
+	}
+}
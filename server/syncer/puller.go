@@ -0,0 +1,100 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package syncer
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/client/sc"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	mgr "github.com/apache/servicecomb-service-center/server/plugin"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	"golang.org/x/net/context"
+	"net/url"
+	"time"
+)
+
+// Peer is the service center cluster of another datacenter
+type Peer struct {
+	Datacenter string
+	Endpoint   string
+}
+
+// Puller pulls the changes from the peer periodically
+type Puller struct {
+	Peer     Peer
+	Store    *Store
+	Interval time.Duration
+	client   *sc.SCClient
+}
+
+func (p *Puller) Run(ctx context.Context) {
+	log.Infof("start pulling the instances of datacenter[%s] from %s", p.Peer.Datacenter, p.Peer.Endpoint)
+	for {
+		if err := p.Pull(ctx); err != nil {
+			log.Errorf(err, "pull the instances of datacenter[%s] from %s failed",
+				p.Peer.Datacenter, p.Peer.Endpoint)
+			p.Store.SetError(p.Peer.Datacenter, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(p.Interval):
+		}
+	}
+}
+
+// Pull applies the changes until the store catches up with the peer
+func (p *Puller) Pull(ctx context.Context) error {
+	if p.client == nil {
+		client, err := newClient(p.Peer.Endpoint)
+		if err != nil {
+			return err
+		}
+		p.client = client
+	}
+	for {
+		epoch, seq := p.Store.Cursor(p.Peer.Datacenter)
+		changes, err := p.client.GetSyncChanges(ctx, epoch, seq)
+		if err != nil {
+			return err
+		}
+		if n := p.Store.Apply(p.Peer.Datacenter, changes); n > 0 {
+			log.Infof("%d instances of datacenter[%s] changed, full: %v, seq: %d",
+				n, p.Peer.Datacenter, changes.Full, changes.Seq)
+		}
+		if changes.Full || len(changes.Records) < maxBatchSize {
+			return nil
+		}
+	}
+}
+
+func newClient(endpoint string) (*sc.SCClient, error) {
+	client, err := sc.NewSCClient(sc.Config{Name: endpoint, Endpoints: []string{endpoint}})
+	if err != nil {
+		return nil, err
+	}
+	client.Timeout = registry.Configuration().RequestTimeOut
+	if u, _ := url.Parse(endpoint); u.Scheme == "https" {
+		if client.TLS, err = mgr.Plugins().TLS().ClientConfig(); err != nil {
+			return nil, err
+		}
+	}
+	return client, nil
+}
+
+func NewPuller(peer Peer, store *Store, interval time.Duration) *Puller {
+	return &Puller{Peer: peer, Store: store, Interval: interval}
+}
@@ -0,0 +1,284 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package syncer
+
+import (
+	"crypto/sha1"
+	"fmt"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	serviceUtil "github.com/apache/servicecomb-service-center/server/service/util"
+	"golang.org/x/net/context"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// PROP_ORIGIN_DC marks the remote instances with their datacenter
+const PROP_ORIGIN_DC = "origin.dc"
+
+type tombstone struct {
+	Revision int64
+	Expire   time.Time
+}
+
+// PeerStatus is the sync status of a peer datacenter
+type PeerStatus struct {
+	Datacenter string    `json:"datacenter"`
+	Epoch      string    `json:"epoch"`
+	Seq        int64     `json:"seq"`
+	Instances  int       `json:"instances"`
+	Tombstones int       `json:"tombstones"`
+	LastSync   time.Time `json:"lastSync"`
+	Error      string    `json:"error,omitempty"`
+}
+
+type peerState struct {
+	PeerStatus
+	records    map[string]*pb.SyncRecord
+	tombstones map[string]tombstone
+}
+
+// apply returns true if the record changes the state. The record of the
+// larger revision wins, the tombstones prevent the stale records from
+// reviving the deleted instances
+func (p *peerState) apply(r *pb.SyncRecord, ttl time.Duration) bool {
+	if r.Instance == nil {
+		return false
+	}
+	id := r.Instance.InstanceId
+	if old, ok := p.records[id]; ok && old.Revision > r.Revision {
+		return false
+	}
+	if t, ok := p.tombstones[id]; ok && t.Revision >= r.Revision {
+		return false
+	}
+	if r.Deleted {
+		_, ok := p.records[id]
+		delete(p.records, id)
+		p.tombstones[id] = tombstone{Revision: r.Revision, Expire: time.Now().Add(ttl)}
+		return ok
+	}
+	p.records[id] = r
+	return true
+}
+
+func (p *peerState) gc() {
+	now := time.Now()
+	for id, t := range p.tombstones {
+		if now.After(t.Expire) {
+			delete(p.tombstones, id)
+		}
+	}
+}
+
+// Store keeps the instances pulled from the peer datacenters in memory,
+// they are returned by Find only if the remote instances are requested
+type Store struct {
+	TombstoneTTL time.Duration
+
+	peers map[string]*peerState
+	lock  sync.RWMutex
+}
+
+func (s *Store) peer(dc string) *peerState {
+	p, ok := s.peers[dc]
+	if !ok {
+		p = &peerState{
+			PeerStatus: PeerStatus{Datacenter: dc},
+			records:    make(map[string]*pb.SyncRecord),
+			tombstones: make(map[string]tombstone),
+		}
+		s.peers[dc] = p
+	}
+	return p
+}
+
+// Cursor returns the position of the records received from the peer
+func (s *Store) Cursor(dc string) (epoch string, seq int64) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	if p, ok := s.peers[dc]; ok {
+		return p.Epoch, p.Seq
+	}
+	return "", 0
+}
+
+// Apply merges the changes pulled from the peer, the instances absent
+// from a full snapshot are deleted
+func (s *Store) Apply(dc string, changes *pb.SyncChangesResponse) (changed int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	p := s.peer(dc)
+	p.gc()
+	if changes.Full {
+		live := make(map[string]struct{}, len(changes.Records))
+		for _, r := range changes.Records {
+			if r.Instance != nil {
+				live[r.Instance.InstanceId] = struct{}{}
+			}
+		}
+		for id := range p.records {
+			if _, ok := live[id]; !ok {
+				delete(p.records, id)
+				changed++
+			}
+		}
+	}
+	for _, r := range changes.Records {
+		if p.apply(r, s.TombstoneTTL) {
+			changed++
+		}
+	}
+	p.Epoch, p.Seq = changes.Epoch, changes.Seq
+	p.LastSync = time.Now()
+	p.Error = ""
+	p.Instances, p.Tombstones = len(p.records), len(p.tombstones)
+	return
+}
+
+func (s *Store) SetError(dc string, err error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.peer(dc).Error = err.Error()
+}
+
+func (s *Store) Status() []PeerStatus {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	status := make([]PeerStatus, 0, len(s.peers))
+	for _, p := range s.peers {
+		status = append(status, p.PeerStatus)
+	}
+	sort.Slice(status, func(i, j int) bool {
+		return status[i].Datacenter < status[j].Datacenter
+	})
+	return status
+}
+
+type remoteRecord struct {
+	Datacenter string
+	Record     *pb.SyncRecord
+}
+
+// FindRemote returns the instances of the provider in all the peer
+// datacenters. If the same instance is synced from more than one
+// datacenter, the latest modified one wins
+func (s *Store) FindRemote(ctx context.Context, provider *pb.MicroServiceKey) ([]*pb.MicroServiceInstance, string) {
+	s.lock.RLock()
+	matched := make(map[string]remoteRecord)
+	for dc, p := range s.peers {
+		for id, r := range p.records {
+			if !matchProvider(r, provider) {
+				continue
+			}
+			if old, ok := matched[id]; ok && !newer(dc, r, old) {
+				continue
+			}
+			matched[id] = remoteRecord{Datacenter: dc, Record: r}
+		}
+	}
+	s.lock.RUnlock()
+
+	records := make([]remoteRecord, 0, len(matched))
+	for _, r := range matched {
+		records = append(records, r)
+	}
+	if provider.Version == "latest" {
+		records = latest(records)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Datacenter != records[j].Datacenter {
+			return records[i].Datacenter < records[j].Datacenter
+		}
+		return records[i].Record.Instance.InstanceId < records[j].Record.Instance.InstanceId
+	})
+
+	instances := make([]*pb.MicroServiceInstance, 0, len(records))
+	var revs string
+	for _, r := range records {
+		instances = append(instances, withLocality(r.Datacenter, r.Record.Instance))
+		revs += r.Datacenter + "/" + r.Record.Instance.InstanceId + "/" +
+			strconv.FormatInt(r.Record.Revision, 10) + ","
+	}
+	return instances, fmt.Sprintf("%x", sha1.Sum([]byte(revs)))
+}
+
+func matchProvider(r *pb.SyncRecord, provider *pb.MicroServiceKey) bool {
+	key := r.Service
+	if key == nil || r.DomainProject != provider.Tenant {
+		return false
+	}
+	if key.Environment != provider.Environment || key.AppId != provider.AppId ||
+		(key.ServiceName != provider.ServiceName && key.Alias != provider.ServiceName) {
+		return false
+	}
+	return provider.Version == "latest" || serviceUtil.VersionMatchRule(key.Version, provider.Version)
+}
+
+// newer returns true if the record r of datacenter dc wins the old one
+func newer(dc string, r *pb.SyncRecord, old remoteRecord) bool {
+	t, _ := strconv.ParseInt(r.Instance.ModTimestamp, 10, 64)
+	oldT, _ := strconv.ParseInt(old.Record.Instance.ModTimestamp, 10, 64)
+	if t != oldT {
+		return t > oldT
+	}
+	return dc < old.Datacenter
+}
+
+// latest returns the records of the latest version
+func latest(records []remoteRecord) []remoteRecord {
+	var (
+		max    int64 = -1
+		result []remoteRecord
+	)
+	for _, r := range records {
+		v, err := serviceUtil.VersionToInt64(r.Record.Service.Version)
+		if err != nil {
+			continue
+		}
+		switch {
+		case v > max:
+			max, result = v, []remoteRecord{r}
+		case v == max:
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+// withLocality returns the copy of the instance marked with the origin
+// datacenter
+func withLocality(dc string, instance *pb.MicroServiceInstance) *pb.MicroServiceInstance {
+	copied := *instance
+	copied.Properties = make(map[string]string, len(instance.Properties)+1)
+	for k, v := range instance.Properties {
+		copied.Properties[k] = v
+	}
+	copied.Properties[PROP_ORIGIN_DC] = dc
+	if copied.DataCenterInfo == nil {
+		copied.DataCenterInfo = &pb.DataCenterInfo{Name: dc}
+	}
+	return &copied
+}
+
+func NewStore(tombstoneTTL time.Duration) *Store {
+	return &Store{
+		TombstoneTTL: tombstoneTTL,
+		peers:        make(map[string]*peerState),
+	}
+}
@@ -0,0 +1,109 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package syncer
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	roa "github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/discovery"
+	serviceUtil "github.com/apache/servicecomb-service-center/server/service/util"
+	"github.com/astaxie/beego"
+	"strings"
+	"time"
+)
+
+const (
+	DEFAULT_DATACENTER    = "default"
+	DEFAULT_INTERVAL      = 5 * time.Second
+	DEFAULT_JOURNAL_SIZE  = 10000
+	DEFAULT_TOMBSTONE_TTL = 10 * time.Minute
+)
+
+var (
+	journal *Journal
+	store   *Store
+)
+
+func init() {
+	cfg := LoadConfig()
+	if !cfg.Enabled {
+		return
+	}
+	journal = NewJournal(cfg.Datacenter, cfg.JournalSize)
+	store = NewStore(cfg.TombstoneTTL)
+
+	discovery.AddEventHandler(NewInstanceEventHandler(journal))
+	serviceUtil.SetRemoteFinder(store)
+	roa.RegisterServant(&SyncerController{})
+	for _, peer := range cfg.Peers {
+		gopool.Go(NewPuller(peer, store, cfg.Interval).Run)
+	}
+}
+
+type Config struct {
+	Enabled bool
+	// Datacenter is the name of the local datacenter
+	Datacenter   string
+	Peers        []Peer
+	Interval     time.Duration
+	JournalSize  int
+	TombstoneTTL time.Duration
+}
+
+// ParsePeers parses the comma separated '{datacenter}={endpoint}'
+func ParsePeers(s string) (peers []Peer) {
+	for _, item := range strings.Split(s, ",") {
+		kv := strings.SplitN(strings.TrimSpace(item), "=", 2)
+		if len(kv) != 2 || len(kv[0]) == 0 || len(kv[1]) == 0 {
+			if len(item) > 0 {
+				log.Warnf("invalid syncer peer '%s', the format is '{datacenter}={endpoint}'", item)
+			}
+			continue
+		}
+		peers = append(peers, Peer{Datacenter: kv[0], Endpoint: strings.TrimRight(kv[1], "/")})
+	}
+	return
+}
+
+func parseDuration(key string, def time.Duration) time.Duration {
+	d, err := time.ParseDuration(beego.AppConfig.DefaultString(key, ""))
+	if err != nil || d <= 0 {
+		return def
+	}
+	return d
+}
+
+func LoadConfig() Config {
+	cfg := Config{
+		Enabled:      beego.AppConfig.DefaultInt("syncer", 0) != 0,
+		Datacenter:   beego.AppConfig.DefaultString("syncer_datacenter", DEFAULT_DATACENTER),
+		Peers:        ParsePeers(beego.AppConfig.DefaultString("syncer_peers", "")),
+		Interval:     parseDuration("syncer_interval", DEFAULT_INTERVAL),
+		JournalSize:  beego.AppConfig.DefaultInt("syncer_journal_size", DEFAULT_JOURNAL_SIZE),
+		TombstoneTTL: parseDuration("syncer_tombstone_ttl", DEFAULT_TOMBSTONE_TTL),
+	}
+	if cfg.JournalSize <= 0 {
+		cfg.JournalSize = DEFAULT_JOURNAL_SIZE
+	}
+	for _, peer := range cfg.Peers {
+		if peer.Datacenter == cfg.Datacenter {
+			log.Warnf("syncer peer %s has the same datacenter name as the local one", peer.Endpoint)
+		}
+	}
+	return cfg
+}
@@ -0,0 +1,148 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package syncer
+
+import (
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"golang.org/x/net/context"
+	"testing"
+	"time"
+)
+
+func record(id, version string, rev int64, deleted bool) *pb.SyncRecord {
+	return &pb.SyncRecord{
+		Revision:      rev,
+		DomainProject: "default/default",
+		Service: &pb.MicroServiceKey{
+			Tenant:      "default/default",
+			AppId:       "app",
+			ServiceName: "svc",
+			Version:     version,
+		},
+		Instance: &pb.MicroServiceInstance{InstanceId: id, ServiceId: "s-" + version},
+		Deleted:  deleted,
+	}
+}
+
+func TestJournal_Changes(t *testing.T) {
+	j := NewJournal("dc1", 4)
+	resp := j.Changes("", 0)
+	if !resp.Full || len(resp.Records) != 0 || resp.Datacenter != "dc1" || resp.Epoch != j.Epoch() {
+		t.Fatalf("TestJournal_Changes failed, %v", resp)
+	}
+
+	j.Append(record("i1", "1.0.0", 10, false))
+	j.Append(record("i2", "1.0.0", 11, false))
+	resp = j.Changes(j.Epoch(), 1)
+	if resp.Full || len(resp.Records) != 1 || resp.Records[0].Instance.InstanceId != "i2" || resp.Seq != 2 {
+		t.Fatalf("TestJournal_Changes failed, %v", resp)
+	}
+	resp = j.Changes(j.Epoch(), 2)
+	if resp.Full || len(resp.Records) != 0 {
+		t.Fatalf("TestJournal_Changes failed, %v", resp)
+	}
+
+	j.Append(record("i1", "1.0.0", 12, true))
+	j.Append(record("i3", "1.0.0", 13, false))
+	j.Append(record("i4", "1.0.0", 14, false))
+	// trimmed
+	resp = j.Changes(j.Epoch(), 1)
+	if !resp.Full || len(resp.Records) != 3 || resp.Seq != 5 {
+		t.Fatalf("TestJournal_Changes failed, %v", resp)
+	}
+	for _, r := range resp.Records {
+		if r.Deleted || r.Instance.InstanceId == "i1" {
+			t.Fatalf("TestJournal_Changes failed, %v", r)
+		}
+	}
+	// epoch changed
+	resp = j.Changes("old", 4)
+	if !resp.Full {
+		t.Fatalf("TestJournal_Changes failed, %v", resp)
+	}
+	resp = j.Changes(j.Epoch(), 4)
+	if resp.Full || len(resp.Records) != 1 || resp.Records[0].Instance.InstanceId != "i4" {
+		t.Fatalf("TestJournal_Changes failed, %v", resp)
+	}
+}
+
+func TestStore_Apply(t *testing.T) {
+	s := NewStore(time.Minute)
+	provider := &pb.MicroServiceKey{Tenant: "default/default", AppId: "app", ServiceName: "svc", Version: "1.0.0+"}
+
+	n := s.Apply("dc2", &pb.SyncChangesResponse{
+		Epoch: "e1", Seq: 2, Full: true,
+		Records: []*pb.SyncRecord{record("i1", "1.0.0", 10, false), record("i2", "2.0.0", 11, false)},
+	})
+	if n != 2 {
+		t.Fatalf("TestStore_Apply failed, %d", n)
+	}
+	if epoch, seq := s.Cursor("dc2"); epoch != "e1" || seq != 2 {
+		t.Fatalf("TestStore_Apply failed, %s %d", epoch, seq)
+	}
+	instances, rev := s.FindRemote(context.Background(), provider)
+	if len(instances) != 2 || len(rev) == 0 {
+		t.Fatalf("TestStore_Apply failed, %v", instances)
+	}
+	if instances[0].Properties[PROP_ORIGIN_DC] != "dc2" || instances[0].DataCenterInfo.Name != "dc2" {
+		t.Fatalf("TestStore_Apply failed, %v", instances[0])
+	}
+
+	latest := *provider
+	latest.Version = "latest"
+	instances, _ = s.FindRemote(context.Background(), &latest)
+	if len(instances) != 1 || instances[0].InstanceId != "i2" {
+		t.Fatalf("TestStore_Apply failed, %v", instances)
+	}
+
+	// tombstone wins the stale record
+	s.Apply("dc2", &pb.SyncChangesResponse{Epoch: "e1", Seq: 3,
+		Records: []*pb.SyncRecord{record("i1", "1.0.0", 12, true)}})
+	s.Apply("dc2", &pb.SyncChangesResponse{Epoch: "e1", Seq: 4,
+		Records: []*pb.SyncRecord{record("i1", "1.0.0", 10, false)}})
+	instances, newRev := s.FindRemote(context.Background(), provider)
+	if len(instances) != 1 || instances[0].InstanceId != "i2" || newRev == rev {
+		t.Fatalf("TestStore_Apply failed, %v", instances)
+	}
+
+	// the newer record wins
+	s.Apply("dc2", &pb.SyncChangesResponse{Epoch: "e1", Seq: 5,
+		Records: []*pb.SyncRecord{record("i1", "1.0.0", 13, false)}})
+	if instances, _ = s.FindRemote(context.Background(), provider); len(instances) != 2 {
+		t.Fatalf("TestStore_Apply failed, %v", instances)
+	}
+
+	// the full snapshot removes the absent instances
+	s.Apply("dc2", &pb.SyncChangesResponse{Epoch: "e2", Seq: 1, Full: true,
+		Records: []*pb.SyncRecord{record("i2", "2.0.0", 11, false)}})
+	if instances, _ = s.FindRemote(context.Background(), provider); len(instances) != 1 {
+		t.Fatalf("TestStore_Apply failed, %v", instances)
+	}
+
+	status := s.Status()
+	if len(status) != 1 || status[0].Instances != 1 || status[0].Epoch != "e2" {
+		t.Fatalf("TestStore_Apply failed, %v", status)
+	}
+}
+
+func TestParsePeers(t *testing.T) {
+	peers := ParsePeers("dc2=http://127.0.0.1:30100/, bad ,dc3=https://127.0.0.2:30100")
+	if len(peers) != 2 || peers[0].Datacenter != "dc2" || peers[0].Endpoint != "http://127.0.0.1:30100" ||
+		peers[1].Endpoint != "https://127.0.0.2:30100" {
+		t.Fatalf("TestParsePeers failed, %v", peers)
+	}
+}
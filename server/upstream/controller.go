@@ -0,0 +1,69 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package upstream
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/rest/controller"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// UpstreamController renders the UP instances of the services as the
+// nginx upstream blocks or haproxy backend sections. The 'format' query
+// is nginx or haproxy, the 'service' query selects the services by
+// '{appId}/{serviceName}' or '{serviceName}' and can be repeated, the
+// 'scheme' query is the protocol of the endpoints to render
+type UpstreamController struct {
+}
+
+func (ctrl *UpstreamController) URLPatterns() []rest.Route {
+	return []rest.Route{
+		{rest.HTTP_METHOD_GET, "/v4/:project/upstream/config", ctrl.Config},
+	}
+}
+
+func (ctrl *UpstreamController) Config(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+	format := strings.ToLower(query.Get("format"))
+	if len(format) == 0 {
+		format = cfg.Format
+	}
+	if format != FORMAT_NGINX && format != FORMAT_HAPROXY {
+		controller.WriteError(w, scerr.ErrInvalidParams, "Invalid format, must be nginx or haproxy")
+		return
+	}
+	scheme := query.Get("scheme")
+	if len(scheme) == 0 {
+		scheme = cfg.Scheme
+	}
+
+	upstreams, err := Collect(ctx, util.ParseDomainProject(ctx),
+		ParseSelectors(strings.Join(query["service"], ",")), scheme)
+	if err != nil {
+		controller.WriteError(w, scerr.ErrInternal, err.Error())
+		return
+	}
+	w.Header().Set(rest.HEADER_RESPONSE_STATUS, strconv.Itoa(http.StatusOK))
+	w.Header().Set(rest.HEADER_CONTENT_TYPE, rest.CONTENT_TYPE_TEXT)
+	w.WriteHeader(http.StatusOK)
+	w.Write(Render(format, upstreams))
+}
@@ -0,0 +1,202 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package upstream
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	serviceUtil "github.com/apache/servicecomb-service-center/server/service/util"
+	"golang.org/x/net/context"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Selector selects the services by '{appId}/{serviceName}' or
+// '{serviceName}' of any application
+type Selector struct {
+	AppId       string
+	ServiceName string
+}
+
+func (s Selector) match(service *pb.MicroService) bool {
+	return (len(s.AppId) == 0 || s.AppId == service.AppId) && s.ServiceName == service.ServiceName
+}
+
+// ParseSelectors parses the comma separated selectors
+func ParseSelectors(s string) (selectors []Selector) {
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if len(item) == 0 {
+			continue
+		}
+		if i := strings.Index(item, "/"); i >= 0 {
+			selectors = append(selectors, Selector{AppId: item[:i], ServiceName: item[i+1:]})
+			continue
+		}
+		selectors = append(selectors, Selector{ServiceName: item})
+	}
+	return
+}
+
+func selected(selectors []Selector, service *pb.MicroService) bool {
+	if len(selectors) == 0 {
+		return true
+	}
+	for _, s := range selectors {
+		if s.match(service) {
+			return true
+		}
+	}
+	return false
+}
+
+// Upstream is the UP instances of all the versions of a service
+type Upstream struct {
+	Name    string
+	Servers []*Server
+}
+
+type Server struct {
+	Name    string
+	Address string
+}
+
+// Collect returns the upstreams of the selected services sorted by name,
+// each server is the first endpoint of the scheme of an UP instance
+func Collect(ctx context.Context, domainProject string, selectors []Selector, scheme string) ([]*Upstream, error) {
+	ctx = util.SetContext(ctx, serviceUtil.CTX_CACHEONLY, "1")
+	services, err := serviceUtil.GetServicesByDomainProject(ctx, domainProject)
+	if err != nil {
+		return nil, err
+	}
+	upstreams := make(map[string]*Upstream)
+	for _, service := range services {
+		if !selected(selectors, service) {
+			continue
+		}
+		name := upstreamName(service)
+		upstream, ok := upstreams[name]
+		if !ok {
+			upstream = &Upstream{Name: name}
+			upstreams[name] = upstream
+		}
+		instances, err := serviceUtil.GetAllInstancesOfOneService(ctx, domainProject, service.ServiceId)
+		if err != nil {
+			return nil, err
+		}
+		for _, instance := range instances {
+			if instance.Status != pb.MSI_UP {
+				continue
+			}
+			if address := addressOf(instance, scheme); len(address) > 0 {
+				upstream.Servers = append(upstream.Servers, &Server{
+					Name:    sanitize(instance.InstanceId),
+					Address: address,
+				})
+			}
+		}
+	}
+
+	list := make([]*Upstream, 0, len(upstreams))
+	for _, upstream := range upstreams {
+		sort.Slice(upstream.Servers, func(i, j int) bool {
+			return upstream.Servers[i].Address < upstream.Servers[j].Address
+		})
+		list = append(list, upstream)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// addressOf returns the '{ip}:{port}' of the first endpoint of the scheme
+func addressOf(instance *pb.MicroServiceInstance, scheme string) string {
+	for _, ep := range instance.Endpoints {
+		u, err := url.Parse(ep)
+		if err != nil || u.Scheme != scheme {
+			continue
+		}
+		ipPort := util.ParseIpPort(u.Host)
+		if ipPort.Port == 0 {
+			continue
+		}
+		return net.JoinHostPort(strings.Trim(ipPort.IP, "[]"), strconv.Itoa(int(ipPort.Port)))
+	}
+	return ""
+}
+
+// upstreamName returns '{appId}_{serviceName}'
+func upstreamName(service *pb.MicroService) string {
+	return sanitize(service.AppId + "_" + service.ServiceName)
+}
+
+// sanitize replaces the characters not allowed in the names of nginx
+// upstreams and haproxy backends
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9',
+			r == '-', r == '_', r == '.':
+			return r
+		default:
+			return '_'
+		}
+	}, s)
+}
+
+// Render renders the upstreams in the format, nginx or haproxy
+func Render(format string, upstreams []*Upstream) []byte {
+	if format == FORMAT_HAPROXY {
+		return RenderHAProxy(upstreams)
+	}
+	return RenderNginx(upstreams)
+}
+
+// RenderNginx renders the upstream blocks, the upstream without servers
+// has a down server, because nginx refuses the empty upstream
+func RenderNginx(upstreams []*Upstream) []byte {
+	var b bytes.Buffer
+	for _, upstream := range upstreams {
+		fmt.Fprintf(&b, "upstream %s {\n", upstream.Name)
+		if len(upstream.Servers) == 0 {
+			b.WriteString("    server 127.0.0.1:65535 down;\n")
+		}
+		for _, server := range upstream.Servers {
+			fmt.Fprintf(&b, "    server %s;\n", server.Address)
+		}
+		b.WriteString("}\n\n")
+	}
+	return b.Bytes()
+}
+
+// RenderHAProxy renders the backend sections with the server lines
+func RenderHAProxy(upstreams []*Upstream) []byte {
+	var b bytes.Buffer
+	for _, upstream := range upstreams {
+		fmt.Fprintf(&b, "backend %s\n", upstream.Name)
+		b.WriteString("    balance roundrobin\n")
+		for _, server := range upstream.Servers {
+			fmt.Fprintf(&b, "    server %s %s check\n", server.Name, server.Address)
+		}
+		b.WriteString("\n")
+	}
+	return b.Bytes()
+}
@@ -0,0 +1,95 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package upstream
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	roa "github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/discovery"
+	"github.com/astaxie/beego"
+	"strings"
+	"time"
+)
+
+const (
+	FORMAT_NGINX   = "nginx"
+	FORMAT_HAPROXY = "haproxy"
+
+	DEFAULT_SCHEME        = "rest"
+	DEFAULT_REFRESH_DELAY = time.Second
+)
+
+var cfg Config
+
+func init() {
+	cfg = LoadConfig()
+	if !cfg.Enabled {
+		return
+	}
+	roa.RegisterServant(&UpstreamController{})
+
+	if len(cfg.File) == 0 {
+		return
+	}
+	writer := NewWriter(cfg)
+	for _, h := range NewEventHandlers(writer) {
+		discovery.AddEventHandler(h)
+	}
+	gopool.Go(writer.Run)
+}
+
+type Config struct {
+	Enabled bool
+	// Format is the format of the file, nginx or haproxy
+	Format string
+	// Scheme is the protocol of the instance endpoints to render
+	Scheme string
+	// Selectors selects the services rendered to the file, the empty
+	// selects all
+	Selectors []Selector
+	// DomainProject is the domain project of the services in the file
+	DomainProject string
+	// File is the config file rewritten when the instances change, the
+	// writer is disabled if it is empty
+	File string
+	// ReloadCommand is executed by shell after the file is rewritten,
+	// e.g. 'nginx -s reload'
+	ReloadCommand string
+	// RefreshDelay merges the changes within the delay into one rewrite
+	RefreshDelay time.Duration
+}
+
+func LoadConfig() Config {
+	c := Config{
+		Enabled:       beego.AppConfig.DefaultInt("upstream_export", 0) != 0,
+		Format:        strings.ToLower(beego.AppConfig.DefaultString("upstream_format", FORMAT_NGINX)),
+		Scheme:        beego.AppConfig.DefaultString("upstream_scheme", DEFAULT_SCHEME),
+		Selectors:     ParseSelectors(beego.AppConfig.DefaultString("upstream_services", "")),
+		DomainProject: beego.AppConfig.DefaultString("upstream_domain_project", core.REGISTRY_DOMAIN_PROJECT),
+		File:          beego.AppConfig.DefaultString("upstream_file", ""),
+		ReloadCommand: beego.AppConfig.DefaultString("upstream_reload_cmd", ""),
+		RefreshDelay:  DEFAULT_REFRESH_DELAY,
+	}
+	if d, err := time.ParseDuration(beego.AppConfig.DefaultString("upstream_refresh_delay", "")); err == nil && d > 0 {
+		c.RefreshDelay = d
+	}
+	if c.Format != FORMAT_HAPROXY {
+		c.Format = FORMAT_NGINX
+	}
+	return c
+}
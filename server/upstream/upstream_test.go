@@ -0,0 +1,97 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package upstream
+
+import (
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseSelectors(t *testing.T) {
+	selectors := ParseSelectors(" app/a, b ,,")
+	if len(selectors) != 2 || selectors[0].AppId != "app" || selectors[0].ServiceName != "a" ||
+		selectors[1].AppId != "" || selectors[1].ServiceName != "b" {
+		t.Fatalf("TestParseSelectors failed, %v", selectors)
+	}
+	if !selected(selectors, &pb.MicroService{AppId: "x", ServiceName: "b"}) ||
+		selected(selectors, &pb.MicroService{AppId: "x", ServiceName: "a"}) ||
+		!selected(nil, &pb.MicroService{AppId: "x", ServiceName: "a"}) {
+		t.Fatalf("TestParseSelectors failed")
+	}
+}
+
+func TestAddressOf(t *testing.T) {
+	instance := &pb.MicroServiceInstance{
+		Endpoints: []string{"highway://10.0.0.1:7070", "rest://10.0.0.1:8080?sslEnabled=true", "rest://10.0.0.2:8080"},
+	}
+	if address := addressOf(instance, "rest"); address != "10.0.0.1:8080" {
+		t.Fatalf("TestAddressOf failed, %s", address)
+	}
+	if address := addressOf(instance, "grpc"); address != "" {
+		t.Fatalf("TestAddressOf failed, %s", address)
+	}
+	instance.Endpoints = []string{"rest://[::1]:8080"}
+	if address := addressOf(instance, "rest"); address != "[::1]:8080" {
+		t.Fatalf("TestAddressOf failed, %s", address)
+	}
+}
+
+func TestRender(t *testing.T) {
+	upstreams := []*Upstream{
+		{Name: "app_a", Servers: []*Server{{Name: "i1", Address: "10.0.0.1:8080"}, {Name: "i2", Address: "10.0.0.2:8080"}}},
+		{Name: "app_b"},
+	}
+	nginx := "upstream app_a {\n    server 10.0.0.1:8080;\n    server 10.0.0.2:8080;\n}\n\n" +
+		"upstream app_b {\n    server 127.0.0.1:65535 down;\n}\n\n"
+	if s := string(Render(FORMAT_NGINX, upstreams)); s != nginx {
+		t.Fatalf("TestRender failed, %s", s)
+	}
+	haproxy := "backend app_a\n    balance roundrobin\n    server i1 10.0.0.1:8080 check\n    server i2 10.0.0.2:8080 check\n\n" +
+		"backend app_b\n    balance roundrobin\n\n"
+	if s := string(Render(FORMAT_HAPROXY, upstreams)); s != haproxy {
+		t.Fatalf("TestRender failed, %s", s)
+	}
+	if s := upstreamName(&pb.MicroService{AppId: "my app", ServiceName: "svc/1"}); s != "my_app_svc_1" {
+		t.Fatalf("TestRender failed, %s", s)
+	}
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir, err := ioutil.TempDir("", "upstream")
+	if err != nil {
+		t.Fatalf("TestWriteFileAtomic failed, %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "upstream.conf")
+	for _, content := range []string{"a", "b"} {
+		if err := writeFileAtomic(path, []byte(content)); err != nil {
+			t.Fatalf("TestWriteFileAtomic failed, %v", err)
+		}
+		b, err := ioutil.ReadFile(path)
+		if err != nil || string(b) != content {
+			t.Fatalf("TestWriteFileAtomic failed, %s, %v", b, err)
+		}
+	}
+	files, _ := ioutil.ReadDir(dir)
+	if len(files) != 1 {
+		t.Fatalf("TestWriteFileAtomic failed, %d files", len(files))
+	}
+}
@@ -0,0 +1,153 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package upstream
+
+import (
+	"bytes"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/server/core/backend"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/discovery"
+	"golang.org/x/net/context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+)
+
+// Writer rewrites the config file when the services or instances change,
+// and executes the reload command if the content changes
+type Writer struct {
+	Cfg   Config
+	dirty chan struct{}
+	last  []byte
+}
+
+// Invalidate marks the file to be rewritten without blocking
+func (w *Writer) Invalidate() {
+	select {
+	case w.dirty <- struct{}{}:
+	default:
+	}
+}
+
+func (w *Writer) Run(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+		return
+	case <-backend.Store().Ready():
+	}
+	w.Invalidate()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.dirty:
+		}
+		// merge the changes within the delay
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(w.Cfg.RefreshDelay):
+		}
+		if err := w.Refresh(ctx); err != nil {
+			log.Errorf(err, "refresh upstream file %s failed", w.Cfg.File)
+		}
+	}
+}
+
+// Refresh rewrites the file if the content changes
+func (w *Writer) Refresh(ctx context.Context) error {
+	upstreams, err := Collect(ctx, w.Cfg.DomainProject, w.Cfg.Selectors, w.Cfg.Scheme)
+	if err != nil {
+		return err
+	}
+	data := Render(w.Cfg.Format, upstreams)
+	if w.last != nil && bytes.Equal(w.last, data) {
+		return nil
+	}
+	if err := writeFileAtomic(w.Cfg.File, data); err != nil {
+		return err
+	}
+	w.last = data
+	log.Infof("upstream file %s is rewritten, %d upstreams", w.Cfg.File, len(upstreams))
+
+	if len(w.Cfg.ReloadCommand) == 0 {
+		return nil
+	}
+	out, err := exec.Command("/bin/sh", "-c", w.Cfg.ReloadCommand).CombinedOutput()
+	if err != nil {
+		log.Errorf(err, "execute upstream reload command failed, %s", out)
+		return err
+	}
+	return nil
+}
+
+// writeFileAtomic writes the temp file in the same directory and renames
+// it, so the proxy never reads a partial file
+func writeFileAtomic(path string, data []byte) error {
+	dir, name := filepath.Split(path)
+	if len(dir) == 0 {
+		dir = "."
+	}
+	f, err := ioutil.TempFile(dir, "."+name+".tmp")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	if _, err = f.Write(data); err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Chmod(tmp, 0644)
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		os.Remove(tmp)
+	}
+	return err
+}
+
+func NewWriter(cfg Config) *Writer {
+	return &Writer{Cfg: cfg, dirty: make(chan struct{}, 1)}
+}
+
+// EventHandler invalidates the file when the services or instances change
+type EventHandler struct {
+	T      discovery.Type
+	Writer *Writer
+}
+
+func (h *EventHandler) Type() discovery.Type {
+	return h.T
+}
+
+func (h *EventHandler) OnEvent(evt discovery.KvEvent) {
+	h.Writer.Invalidate()
+}
+
+func NewEventHandlers(w *Writer) []*EventHandler {
+	return []*EventHandler{
+		{T: backend.SERVICE, Writer: w},
+		{T: backend.INSTANCE, Writer: w},
+	}
+}
@@ -0,0 +1,80 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package usage
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/chain"
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	roa "github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/server/retention"
+	"github.com/astaxie/beego"
+	"time"
+)
+
+const (
+	DEFAULT_FLUSH_INTERVAL = time.Minute
+	DEFAULT_RETENTION_DAYS = 90
+	// MAX_QUERY_DAYS is the max days of one usage query
+	MAX_QUERY_DAYS = 31
+)
+
+var (
+	cfg      Config
+	recorder *Recorder
+)
+
+func init() {
+	cfg = LoadConfig()
+	if !cfg.Enabled {
+		return
+	}
+	recorder = NewRecorder(cfg)
+	chain.RegisterHandler(roa.SERVER_CHAIN_NAME, &UsageHandler{})
+	roa.RegisterServant(&UsageController{})
+	gopool.Go(recorder.Run)
+	retention.Register(retention.Policy{
+		Name:      "usage",
+		Roots:     []string{getRootKey()},
+		Format:    DATE_FORMAT,
+		Retention: time.Duration(cfg.RetentionDays) * 24 * time.Hour,
+	})
+}
+
+type Config struct {
+	Enabled bool
+	// FlushInterval is how often the counts are added to the daily
+	// rollups in the registry
+	FlushInterval time.Duration
+	// RetentionDays is how long the daily rollups are kept
+	RetentionDays int
+}
+
+func LoadConfig() Config {
+	c := Config{
+		Enabled:       beego.AppConfig.DefaultInt("usage_accounting", 0) != 0,
+		FlushInterval: DEFAULT_FLUSH_INTERVAL,
+		RetentionDays: beego.AppConfig.DefaultInt("usage_retention_days", DEFAULT_RETENTION_DAYS),
+	}
+	d, err := time.ParseDuration(beego.AppConfig.DefaultString("usage_flush_interval", ""))
+	if err == nil && d >= time.Second {
+		c.FlushInterval = d
+	}
+	if c.RetentionDays <= 0 {
+		c.RetentionDays = DEFAULT_RETENTION_DAYS
+	}
+	return c
+}
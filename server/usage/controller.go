@@ -0,0 +1,94 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package usage
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/core"
+	scerr "github.com/apache/servicecomb-service-center/server/error"
+	"github.com/apache/servicecomb-service-center/server/rest/controller"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type GetUsageResponse struct {
+	DomainProject string   `json:"domainProject"`
+	Usages        []*Usage `json:"usages"`
+}
+
+// UsageController serves the daily API usage of the tenants
+type UsageController struct {
+}
+
+func (ctrl *UsageController) URLPatterns() []rest.Route {
+	return []rest.Route{
+		{rest.HTTP_METHOD_GET, "/v4/:project/usage", ctrl.GetUsage},
+	}
+}
+
+// GetUsage returns the usage between the dates 'from' and 'to' in format
+// yyyyMMdd, default is today, only the admin can query the usage of the
+// other domain by the parameter 'domain'
+func (ctrl *UsageController) GetUsage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+	domainProject := util.ParseDomainProject(ctx)
+	if domain := query.Get("domain"); len(domain) > 0 && domain != util.ParseDomain(ctx) {
+		if !core.IsDefaultDomainProject(domainProject) {
+			controller.WriteError(w, scerr.ErrForbidden, "Required admin permission")
+			return
+		}
+		domainProject = domain + core.SPLIT + query.Get(":project")
+	}
+
+	to, err := parseDate(query.Get("to"), time.Now())
+	if err != nil {
+		controller.WriteError(w, scerr.ErrInvalidParams, "parameter to must be yyyyMMdd")
+		return
+	}
+	from, err := parseDate(query.Get("from"), to)
+	if err != nil {
+		controller.WriteError(w, scerr.ErrInvalidParams, "parameter from must be yyyyMMdd")
+		return
+	}
+	if from.After(to) || to.Sub(from) >= MAX_QUERY_DAYS*24*time.Hour {
+		controller.WriteError(w, scerr.ErrInvalidParams,
+			"parameter from must be before to, and in "+strconv.Itoa(MAX_QUERY_DAYS)+" days")
+		return
+	}
+
+	usages, err := Query(ctx, domainProject, from, to)
+	if err != nil {
+		log.Errorf(err, "query the usage of '%s' failed", domainProject)
+		controller.WriteError(w, scerr.ErrUnavailableBackend, err.Error())
+		return
+	}
+	if usages == nil {
+		usages = []*Usage{}
+	}
+	controller.WriteResponse(w, nil, &GetUsageResponse{DomainProject: domainProject, Usages: usages})
+}
+
+func parseDate(s string, def time.Time) (time.Time, error) {
+	if len(s) == 0 {
+		return def.UTC().Truncate(24 * time.Hour), nil
+	}
+	return time.Parse(DATE_FORMAT, s)
+}
@@ -0,0 +1,51 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package usage
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/chain"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	svr "github.com/apache/servicecomb-service-center/server/rest"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// UsageHandler counts the REST API calls of each domain project, the
+// watch APIs are also accounted by the connected time
+type UsageHandler struct {
+}
+
+func (h *UsageHandler) Handle(i *chain.Invocation) {
+	i.Next(chain.WithAsyncFunc(func(ret chain.Result) {
+		r := i.Context().Value(rest.CTX_REQUEST).(*http.Request)
+		ctx := r.Context()
+		if len(util.ParseDomain(ctx)) == 0 || len(util.ParseProject(ctx)) == 0 {
+			// not authenticated
+			return
+		}
+		api, _ := i.Context().Value(rest.CTX_MATCH_FUNC).(string)
+		domainProject, now := util.ParseDomainProject(ctx), time.Now()
+		recorder.Add(domainProject, api, now)
+
+		start, ok := i.Context().Value(svr.CTX_START_TIMESTAMP).(time.Time)
+		if ok && strings.Index(r.Method, "WATCH") == 0 {
+			recorder.AddWatch(domainProject, now.Sub(start), now)
+		}
+	}))
+}
@@ -0,0 +1,220 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package usage
+
+import (
+	"encoding/json"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/core/backend"
+	"github.com/apache/servicecomb-service-center/server/metric"
+	"github.com/apache/servicecomb-service-center/server/plugin/pkg/registry"
+	"golang.org/x/net/context"
+	"sync"
+	"time"
+)
+
+const (
+	DATE_FORMAT = "20060102"
+
+	usageKey = "usage"
+)
+
+// Usage is the API calls of a domain project in one day
+type Usage struct {
+	Date string `json:"date"`
+	// Apis is the calls of each API
+	Apis map[string]int64 `json:"apis"`
+	// WatchMinutes is the total connected time of the watchers
+	WatchMinutes float64 `json:"watchMinutes"`
+}
+
+func (u *Usage) Merge(o *Usage) {
+	for api, c := range o.Apis {
+		u.Apis[api] += c
+	}
+	u.WatchMinutes += o.WatchMinutes
+}
+
+func NewUsage(date string) *Usage {
+	return &Usage{Date: date, Apis: make(map[string]int64)}
+}
+
+// the rollups are stored by date first, so the expired ones can be
+// deleted in a range, each service center writes its own key
+// '/cse-sr/usage/{date}/{domain}/{project}/{node}'
+func getRootKey() string {
+	return util.StringJoin([]string{core.GetRootKey(), usageKey}, core.SPLIT)
+}
+
+func getUsageKey(date, domainProject, node string) string {
+	return util.StringJoin([]string{getRootKey(), date, domainProject, node}, core.SPLIT)
+}
+
+// Recorder counts the API calls in memory and adds them to the daily
+// rollups in the registry periodically
+type Recorder struct {
+	Cfg Config
+	// Node identifies the service center in the cluster
+	Node string
+
+	lock sync.Mutex
+	// pending maps the key of rollup to the counts not flushed
+	pending map[string]*pendingUsage
+}
+
+type pendingUsage struct {
+	DomainProject string
+	*Usage
+}
+
+func (r *Recorder) get(domainProject string, now time.Time) *Usage {
+	date := now.UTC().Format(DATE_FORMAT)
+	key := getUsageKey(date, domainProject, r.Node)
+	p, ok := r.pending[key]
+	if !ok {
+		p = &pendingUsage{DomainProject: domainProject, Usage: NewUsage(date)}
+		r.pending[key] = p
+	}
+	return p.Usage
+}
+
+func (r *Recorder) Add(domainProject, api string, now time.Time) {
+	r.lock.Lock()
+	r.get(domainProject, now).Apis[api]++
+	r.lock.Unlock()
+}
+
+func (r *Recorder) AddWatch(domainProject string, d time.Duration, now time.Time) {
+	r.lock.Lock()
+	r.get(domainProject, now).WatchMinutes += d.Minutes()
+	r.lock.Unlock()
+}
+
+func (r *Recorder) swap() map[string]*pendingUsage {
+	r.lock.Lock()
+	pending := r.pending
+	r.pending = make(map[string]*pendingUsage)
+	r.lock.Unlock()
+	return pending
+}
+
+// restore puts back the counts failed to flush
+func (r *Recorder) restore(key string, p *pendingUsage) {
+	r.lock.Lock()
+	if exist, ok := r.pending[key]; ok {
+		exist.Merge(p.Usage)
+	} else {
+		r.pending[key] = p
+	}
+	r.lock.Unlock()
+}
+
+// Flush adds the pending counts to the rollups, the key is written by
+// this service center only, so there is no conflict to read and write
+func (r *Recorder) Flush(ctx context.Context) {
+	for key, p := range r.swap() {
+		if err := r.flush(ctx, key, p); err != nil {
+			log.Errorf(err, "flush the usage of '%s' on %s failed", p.DomainProject, p.Date)
+			r.restore(key, p)
+		}
+	}
+}
+
+func (r *Recorder) flush(ctx context.Context, key string, p *pendingUsage) error {
+	resp, err := backend.Registry().Do(ctx, registry.GET, registry.WithStrKey(key))
+	if err != nil {
+		return err
+	}
+	u := NewUsage(p.Date)
+	if len(resp.Kvs) > 0 {
+		if err := json.Unmarshal(resp.Kvs[0].Value, u); err != nil {
+			log.Errorf(err, "unmarshal usage '%s' failed, reset it", key)
+			u = NewUsage(p.Date)
+		}
+		if u.Apis == nil {
+			u.Apis = make(map[string]int64)
+		}
+	}
+	u.Merge(p.Usage)
+	data, err := json.Marshal(u)
+	if err != nil {
+		return err
+	}
+	_, err = backend.Registry().Do(ctx, registry.PUT,
+		registry.WithStrKey(key),
+		registry.WithValue(data))
+	return err
+}
+
+func (r *Recorder) Run(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+		return
+	case <-backend.Registry().Ready():
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			// flush the counts before shutting down
+			fctx, cancel := context.WithTimeout(context.Background(), DEFAULT_FLUSH_INTERVAL)
+			r.Flush(fctx)
+			cancel()
+			return
+		case <-time.After(r.Cfg.FlushInterval):
+			r.Flush(ctx)
+		}
+	}
+}
+
+// Query returns the daily usage of the domain project in the dates, the
+// counts of all the service centers are summed
+func Query(ctx context.Context, domainProject string, from, to time.Time) ([]*Usage, error) {
+	var usages []*Usage
+	for day := from.UTC(); !day.After(to.UTC()); day = day.AddDate(0, 0, 1) {
+		date := day.Format(DATE_FORMAT)
+		resp, err := backend.Registry().Do(ctx, registry.GET,
+			registry.WithStrKey(util.StringJoin([]string{getRootKey(), date, domainProject, ""}, core.SPLIT)),
+			registry.WithPrefix())
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.Kvs) == 0 {
+			continue
+		}
+		u := NewUsage(date)
+		for _, kv := range resp.Kvs {
+			node := NewUsage(date)
+			if err := json.Unmarshal(kv.Value, node); err != nil {
+				log.Errorf(err, "unmarshal usage '%s' failed", kv.Key)
+				continue
+			}
+			u.Merge(node)
+		}
+		usages = append(usages, u)
+	}
+	return usages, nil
+}
+
+func NewRecorder(cfg Config) *Recorder {
+	return &Recorder{
+		Cfg:     cfg,
+		Node:    metric.InstanceName(),
+		pending: make(map[string]*pendingUsage),
+	}
+}
@@ -0,0 +1,69 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package usage
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecorder_Add(t *testing.T) {
+	r := NewRecorder(Config{})
+	r.Node = "n"
+	now := time.Date(2019, 1, 2, 23, 0, 0, 0, time.UTC)
+	r.Add("a/p", "Register", now)
+	r.Add("a/p", "Register", now)
+	r.Add("a/p", "Find", now)
+	r.AddWatch("a/p", 90*time.Second, now)
+	r.Add("a/p", "Find", now.Add(2*time.Hour))
+	r.Add("b/p", "Find", now)
+
+	pending := r.swap()
+	if len(pending) != 3 || len(r.pending) != 0 {
+		t.Fatalf("TestRecorder_Add failed, %v", pending)
+	}
+	p, ok := pending["/cse-sr/usage/20190102/a/p/n"]
+	if !ok || p.DomainProject != "a/p" || p.Date != "20190102" {
+		t.Fatalf("TestRecorder_Add failed, %v", p)
+	}
+	if p.Apis["Register"] != 2 || p.Apis["Find"] != 1 || p.WatchMinutes != 1.5 {
+		t.Fatalf("TestRecorder_Add failed, %v", p.Usage)
+	}
+
+	// restore the counts failed to flush
+	r.Add("a/p", "Find", now)
+	r.restore("/cse-sr/usage/20190102/a/p/n", p)
+	p = r.pending["/cse-sr/usage/20190102/a/p/n"]
+	if p.Apis["Register"] != 2 || p.Apis["Find"] != 2 || p.WatchMinutes != 1.5 {
+		t.Fatalf("TestRecorder_Add failed, %v", p.Usage)
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	now := time.Date(2019, 1, 2, 23, 0, 0, 0, time.UTC)
+	d, err := parseDate("", now)
+	if err != nil || !d.Equal(time.Date(2019, 1, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("TestParseDate failed, %v, %v", d, err)
+	}
+	d, err = parseDate("20190101", now)
+	if err != nil || d.Format(DATE_FORMAT) != "20190101" {
+		t.Fatalf("TestParseDate failed, %v, %v", d, err)
+	}
+	if _, err = parseDate("2019-01-01", now); err == nil {
+		t.Fatalf("TestParseDate failed")
+	}
+}
@@ -0,0 +1,137 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package zookeeper
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	serviceUtil "github.com/apache/servicecomb-service-center/server/service/util"
+	"net/url"
+	"strings"
+)
+
+const (
+	// PROP_ORIGIN tags the mirrored providers with the registry they come
+	// from, the mirrored providers are never synced back
+	PROP_ORIGIN = "origin"
+	ORIGIN_SC   = "servicecenter"
+	ORIGIN_ZK   = "zookeeper"
+	// PROP_ZK_KEY is the '{interface}/{protocol}://{ip}:{port}' of the
+	// dubbo provider mirrored to service center
+	PROP_ZK_KEY = "zk.key"
+
+	PARAM_APPLICATION = "application"
+	PARAM_INTERFACE   = "interface"
+	PARAM_VERSION     = "version"
+	PARAM_SIDE        = "side"
+	PARAM_ANYHOST     = "anyhost"
+	PARAM_SERVICE_ID  = "sc.serviceId"
+	PARAM_INSTANCE_ID = "sc.instanceId"
+
+	SIDE_PROVIDER = "provider"
+)
+
+var versionRegex = serviceUtil.NewVersionRegexp(false)
+
+// provider is the dubbo provider registered under
+// '/{root}/{interface}/providers' as the url encoded node name
+type provider struct {
+	Interface string
+	URL       *url.URL
+}
+
+// Key returns '{interface}/{protocol}://{ip}:{port}'
+func (p *provider) Key() string {
+	return p.Interface + "/" + p.Endpoint()
+}
+
+func (p *provider) Endpoint() string {
+	return p.URL.Scheme + "://" + p.URL.Host
+}
+
+func (p *provider) Origin() string {
+	return p.URL.Query().Get(PROP_ORIGIN)
+}
+
+// Version returns the dubbo version if it is a valid service version
+func (p *provider) Version() string {
+	if v := p.URL.Query().Get(PARAM_VERSION); versionRegex.MatchString(v) {
+		return v
+	}
+	return pb.VERSION
+}
+
+// NodeName returns the url encoded node name
+func (p *provider) NodeName() string {
+	return url.QueryEscape(p.URL.String())
+}
+
+func providersPath(root, iface string) string {
+	return "/" + root + "/" + iface + "/providers"
+}
+
+// parseProvider returns nil if the node is not a valid provider url
+func parseProvider(iface, node string) *provider {
+	s, err := url.QueryUnescape(node)
+	if err != nil {
+		return nil
+	}
+	u, err := url.Parse(s)
+	if err != nil || len(u.Scheme) == 0 || util.ParseIpPort(u.Host).Port == 0 {
+		return nil
+	}
+	if side := u.Query().Get(PARAM_SIDE); len(side) > 0 && side != SIDE_PROVIDER {
+		return nil
+	}
+	return &provider{Interface: iface, URL: u}
+}
+
+// toProviders converts every endpoint of the instance to a provider of
+// the interface named by the service name
+func toProviders(service *pb.MicroService, instance *pb.MicroServiceInstance) []*provider {
+	var providers []*provider
+	for _, ep := range instance.Endpoints {
+		u, err := url.Parse(ep)
+		if err != nil || len(u.Scheme) == 0 || util.ParseIpPort(u.Host).Port == 0 {
+			continue
+		}
+		params := url.Values{}
+		params.Set(PARAM_ANYHOST, "false")
+		params.Set(PARAM_APPLICATION, service.AppId)
+		params.Set(PARAM_INTERFACE, service.ServiceName)
+		params.Set(PARAM_VERSION, service.Version)
+		params.Set(PARAM_SIDE, SIDE_PROVIDER)
+		params.Set(PROP_ORIGIN, ORIGIN_SC)
+		params.Set(PARAM_SERVICE_ID, service.ServiceId)
+		params.Set(PARAM_INSTANCE_ID, instance.InstanceId)
+		providers = append(providers, &provider{
+			Interface: service.ServiceName,
+			URL: &url.URL{
+				Scheme:   u.Scheme,
+				Host:     u.Host,
+				Path:     "/" + service.ServiceName,
+				RawQuery: params.Encode(),
+			},
+		})
+	}
+	return providers
+}
+
+// hostName returns the ip of the endpoint
+func hostName(p *provider) string {
+	return strings.Trim(util.ParseIpPort(p.URL.Host).IP, "[]")
+}
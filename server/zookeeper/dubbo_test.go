@@ -0,0 +1,68 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package zookeeper
+
+import (
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"net/url"
+	"testing"
+)
+
+func TestToProviders(t *testing.T) {
+	service := &pb.MicroService{ServiceId: "s1", AppId: "a", ServiceName: "com.foo.DemoService", Version: "1.0.0"}
+	instance := &pb.MicroServiceInstance{
+		InstanceId: "i1",
+		Endpoints:  []string{"rest://10.0.0.1:8080", "highway://10.0.0.1:7070", "rest://10.0.0.1"},
+	}
+	providers := toProviders(service, instance)
+	if len(providers) != 2 {
+		t.Fatalf("TestToProviders failed, %v", providers)
+	}
+	p := providers[0]
+	if p.Key() != "com.foo.DemoService/rest://10.0.0.1:8080" || p.Origin() != ORIGIN_SC ||
+		p.Version() != "1.0.0" || p.URL.Path != "/com.foo.DemoService" {
+		t.Fatalf("TestToProviders failed, %v", p.URL)
+	}
+
+	parsed := parseProvider(p.Interface, p.NodeName())
+	if parsed == nil || parsed.URL.String() != p.URL.String() || parsed.NodeName() != p.NodeName() {
+		t.Fatalf("TestToProviders failed, %v", parsed)
+	}
+}
+
+func TestParseProvider(t *testing.T) {
+	node := url.QueryEscape("dubbo://10.0.0.2:20880/com.foo.DemoService?application=demo&side=provider&version=2.0")
+	p := parseProvider("com.foo.DemoService", node)
+	if p == nil || p.Key() != "com.foo.DemoService/dubbo://10.0.0.2:20880" ||
+		p.Version() != "2.0" || len(p.Origin()) > 0 || hostName(p) != "10.0.0.2" {
+		t.Fatalf("TestParseProvider failed, %v", p)
+	}
+
+	node = url.QueryEscape("dubbo://10.0.0.2:20880/com.foo.DemoService?version=v1")
+	if p = parseProvider("com.foo.DemoService", node); p == nil || p.Version() != pb.VERSION {
+		t.Fatalf("TestParseProvider failed, %v", p)
+	}
+
+	node = url.QueryEscape("consumer://10.0.0.3/com.foo.DemoService?side=consumer")
+	if p = parseProvider("com.foo.DemoService", node); p != nil {
+		t.Fatalf("TestParseProvider failed, %v", p)
+	}
+	node = url.QueryEscape("dubbo://10.0.0.3:20880/com.foo.DemoService?side=consumer")
+	if p = parseProvider("com.foo.DemoService", node); p != nil {
+		t.Fatalf("TestParseProvider failed, %v", p)
+	}
+}
@@ -0,0 +1,344 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package zookeeper
+
+import (
+	"fmt"
+	"github.com/apache/servicecomb-service-center/pkg/client/zk"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/core/backend"
+	pb "github.com/apache/servicecomb-service-center/server/core/proto"
+	"github.com/apache/servicecomb-service-center/server/mux"
+	serviceUtil "github.com/apache/servicecomb-service-center/server/service/util"
+	"golang.org/x/net/context"
+	"time"
+)
+
+var acls = zk.WorldACL(zk.PermAll)
+
+type localInstance struct {
+	Service  *pb.MicroService
+	Instance *pb.MicroServiceInstance
+}
+
+// Syncer mirrors the UP instances of service center to zookeeper as
+// the ephemeral dubbo provider nodes, and the dubbo providers to service
+// center, by comparing the full state periodically. Only one service
+// center instance syncs at a time, the others close their sessions to
+// release the ephemeral nodes
+type Syncer struct {
+	Cfg    Config
+	client *zk.Client
+}
+
+func (s *Syncer) Run(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+		return
+	case <-backend.Store().Ready():
+	}
+	log.Infof("start syncing with zookeeper%v, direction: %s, interval: %s",
+		s.Cfg.Addrs, s.Cfg.Direction, s.Cfg.Interval)
+	for {
+		select {
+		case <-ctx.Done():
+			s.closeClient()
+			return
+		case <-time.After(s.Cfg.Interval):
+			s.trySync(ctx)
+		}
+	}
+}
+
+func (s *Syncer) getClient() (*zk.Client, error) {
+	if s.client != nil && s.client.Err() == nil {
+		return s.client, nil
+	}
+	if s.client != nil {
+		log.Warnf("zookeeper session broken, %s, reconnect to %v", s.client.Err(), s.Cfg.Addrs)
+		s.closeClient()
+	}
+	client, err := zk.NewClient(s.Cfg.Config)
+	if err != nil {
+		return nil, err
+	}
+	s.client = client
+	return client, nil
+}
+
+func (s *Syncer) closeClient() {
+	if s.client == nil {
+		return
+	}
+	s.client.Close()
+	s.client = nil
+}
+
+func (s *Syncer) trySync(ctx context.Context) {
+	lock, err := mux.Try(mux.ZK_SYNC_LOCK)
+	if lock == nil {
+		log.Debugf("can not sync with zookeeper by this service center instance now, %v", err)
+		s.closeClient()
+		return
+	}
+	defer lock.Unlock()
+
+	if err := s.Sync(ctx); err != nil {
+		log.Errorf(err, "sync with zookeeper%v failed", s.Cfg.Addrs)
+	}
+}
+
+// Sync runs one round of the synchronization
+func (s *Syncer) Sync(ctx context.Context) error {
+	domain, project := core.FromDomainProject(s.Cfg.DomainProject)
+	ctx = util.SetDomainProject(ctx, domain, project)
+
+	client, err := s.getClient()
+	if err != nil {
+		return err
+	}
+	local, err := s.listLocal(ctx)
+	if err != nil {
+		return err
+	}
+	remote, err := s.listRemote(client)
+	if err != nil {
+		return err
+	}
+	if s.Cfg.toZK() {
+		s.syncToZK(client, local, remote)
+	}
+	if s.Cfg.fromZK() {
+		s.syncFromZK(ctx, local, remote)
+	}
+	return nil
+}
+
+func (s *Syncer) listLocal(ctx context.Context) ([]*localInstance, error) {
+	cacheCtx := util.SetContext(ctx, serviceUtil.CTX_CACHEONLY, "1")
+	services, err := serviceUtil.GetServicesByDomainProject(cacheCtx, s.Cfg.DomainProject)
+	if err != nil {
+		return nil, err
+	}
+	var local []*localInstance
+	for _, service := range services {
+		instances, err := serviceUtil.GetAllInstancesOfOneService(cacheCtx, s.Cfg.DomainProject, service.ServiceId)
+		if err != nil {
+			return nil, err
+		}
+		for _, instance := range instances {
+			local = append(local, &localInstance{Service: service, Instance: instance})
+		}
+	}
+	return local, nil
+}
+
+// listRemote returns the providers of all the interfaces, indexed by
+// the node path
+func (s *Syncer) listRemote(client *zk.Client) (map[string]*provider, error) {
+	ifaces, err := client.Children("/" + s.Cfg.Root)
+	if err == zk.ErrNoNode {
+		return map[string]*provider{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	remote := make(map[string]*provider)
+	for _, iface := range ifaces {
+		dir := providersPath(s.Cfg.Root, iface)
+		nodes, err := client.Children(dir)
+		if err == zk.ErrNoNode {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, node := range nodes {
+			if p := parseProvider(iface, node); p != nil {
+				remote[dir+"/"+node] = p
+			}
+		}
+	}
+	return remote, nil
+}
+
+func (s *Syncer) syncToZK(client *zk.Client, local []*localInstance, remote map[string]*provider) {
+	desired := make(map[string]*provider)
+	for _, l := range local {
+		if l.Instance.Properties[PROP_ORIGIN] == ORIGIN_ZK || l.Instance.Status != pb.MSI_UP {
+			continue
+		}
+		for _, p := range toProviders(l.Service, l.Instance) {
+			desired[providersPath(s.Cfg.Root, p.Interface)+"/"+p.NodeName()] = p
+		}
+	}
+
+	for path, p := range desired {
+		if _, ok := remote[path]; ok {
+			continue
+		}
+		if err := client.CreateAll(providersPath(s.Cfg.Root, p.Interface), acls); err != nil {
+			log.Errorf(err, "create the providers node of interface[%s] failed", p.Interface)
+			continue
+		}
+		_, err := client.Create(path, nil, zk.FlagEphemeral, acls)
+		if err != nil && err != zk.ErrNodeExists {
+			log.Errorf(err, "mirror instance[%s] to zookeeper interface[%s] failed",
+				p.URL.Query().Get(PARAM_INSTANCE_ID), p.Interface)
+			continue
+		}
+		log.Infof("mirror instance[%s] to zookeeper interface[%s] %s",
+			p.URL.Query().Get(PARAM_INSTANCE_ID), p.Interface, p.Endpoint())
+	}
+
+	for path, p := range remote {
+		if p.Origin() != ORIGIN_SC {
+			continue
+		}
+		if _, ok := desired[path]; ok {
+			continue
+		}
+		if err := client.Delete(path, -1); err != nil && err != zk.ErrNoNode {
+			log.Errorf(err, "remove the mirror provider %s from zookeeper interface[%s] failed",
+				p.Endpoint(), p.Interface)
+			continue
+		}
+		log.Infof("remove the mirror provider %s from zookeeper interface[%s]", p.Endpoint(), p.Interface)
+	}
+}
+
+func (s *Syncer) syncFromZK(ctx context.Context, local []*localInstance, remote map[string]*provider) {
+	mirrored := make(map[string]*localInstance)
+	for _, l := range local {
+		if l.Instance.Properties[PROP_ORIGIN] == ORIGIN_ZK {
+			mirrored[l.Instance.Properties[PROP_ZK_KEY]] = l
+		}
+	}
+
+	desired := make(map[string]struct{})
+	for _, p := range remote {
+		if p.Origin() == ORIGIN_SC {
+			continue
+		}
+		key := p.Key()
+		if _, ok := desired[key]; ok {
+			// the same endpoint registered with different parameters
+			continue
+		}
+		desired[key] = struct{}{}
+		if l, ok := mirrored[key]; ok {
+			s.heartbeat(ctx, l)
+			continue
+		}
+		if err := s.register(ctx, p); err != nil {
+			log.Errorf(err, "mirror dubbo provider[%s] to service center failed", key)
+			continue
+		}
+		log.Infof("mirror dubbo provider[%s] to service center", key)
+	}
+
+	for key, l := range mirrored {
+		if _, ok := desired[key]; ok {
+			continue
+		}
+		resp, err := core.InstanceAPI.Unregister(ctx, &pb.UnregisterInstanceRequest{
+			ServiceId:  l.Instance.ServiceId,
+			InstanceId: l.Instance.InstanceId,
+		})
+		if err != nil || resp.Response.Code != pb.Response_SUCCESS {
+			log.Errorf(err, "remove the mirror instance[%s] of dubbo provider[%s] failed",
+				l.Instance.InstanceId, key)
+			continue
+		}
+		log.Infof("remove the mirror instance[%s] of dubbo provider[%s]", l.Instance.InstanceId, key)
+	}
+}
+
+func (s *Syncer) heartbeat(ctx context.Context, l *localInstance) {
+	resp, err := core.InstanceAPI.Heartbeat(ctx, &pb.HeartbeatRequest{
+		ServiceId:  l.Instance.ServiceId,
+		InstanceId: l.Instance.InstanceId,
+	})
+	if err != nil || resp.Response.Code != pb.Response_SUCCESS {
+		log.Errorf(err, "update the mirror instance[%s/%s] heartbeat failed",
+			l.Instance.ServiceId, l.Instance.InstanceId)
+	}
+}
+
+func (s *Syncer) ensureService(ctx context.Context, name, version string) (string, error) {
+	serviceId, err := serviceUtil.GetServiceId(ctx, &pb.MicroServiceKey{
+		Tenant:      s.Cfg.DomainProject,
+		AppId:       s.Cfg.App,
+		ServiceName: name,
+		Version:     version,
+	})
+	if err != nil || len(serviceId) > 0 {
+		return serviceId, err
+	}
+	resp, err := core.ServiceAPI.Create(ctx, &pb.CreateServiceRequest{
+		Service: &pb.MicroService{
+			AppId:       s.Cfg.App,
+			ServiceName: name,
+			Version:     version,
+			Properties:  map[string]string{PROP_ORIGIN: ORIGIN_ZK},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	if resp.Response.Code != pb.Response_SUCCESS {
+		return "", fmt.Errorf(resp.Response.Message)
+	}
+	return resp.ServiceId, nil
+}
+
+func (s *Syncer) register(ctx context.Context, p *provider) error {
+	serviceId, err := s.ensureService(ctx, p.Interface, p.Version())
+	if err != nil {
+		return err
+	}
+	resp, err := core.InstanceAPI.Register(ctx, &pb.RegisterInstanceRequest{
+		Instance: &pb.MicroServiceInstance{
+			ServiceId: serviceId,
+			Endpoints: []string{p.Endpoint()},
+			HostName:  hostName(p),
+			Status:    pb.MSI_UP,
+			Properties: map[string]string{
+				PROP_ORIGIN: ORIGIN_ZK,
+				PROP_ZK_KEY: p.Key(),
+			},
+			HealthCheck: &pb.HealthCheck{
+				Mode:     pb.CHECK_BY_HEARTBEAT,
+				Interval: int32(s.Cfg.Interval.Seconds()),
+				Times:    3,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if resp.Response.Code != pb.Response_SUCCESS {
+		return fmt.Errorf(resp.Response.Message)
+	}
+	return nil
+}
+
+func NewSyncer(cfg Config) *Syncer {
+	return &Syncer{Cfg: cfg}
+}
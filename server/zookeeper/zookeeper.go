@@ -0,0 +1,98 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package zookeeper
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/client/zk"
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	"github.com/apache/servicecomb-service-center/server/core"
+	"github.com/astaxie/beego"
+	"strings"
+	"time"
+)
+
+const (
+	DIRECTION_BOTH    = "both"
+	DIRECTION_TO_ZK   = "to_zk"
+	DIRECTION_FROM_ZK = "from_zk"
+
+	DEFAULT_ROOT          = "dubbo"
+	DEFAULT_SYNC_INTERVAL = 30 * time.Second
+)
+
+func init() {
+	cfg := LoadConfig()
+	if !cfg.Enabled {
+		return
+	}
+	gopool.Go(NewSyncer(cfg).Run)
+}
+
+type Config struct {
+	zk.Config
+	Enabled bool
+	// Root is the root node of the dubbo registry, the providers are
+	// registered under '/{root}/{interface}/providers'
+	Root string
+	// DomainProject is the domain project of service center to sync
+	DomainProject string
+	// App is the app id of the services synced from zookeeper
+	App       string
+	Direction string
+	Interval  time.Duration
+}
+
+func (cfg Config) toZK() bool {
+	return cfg.Direction != DIRECTION_FROM_ZK
+}
+
+func (cfg Config) fromZK() bool {
+	return cfg.Direction != DIRECTION_TO_ZK
+}
+
+func LoadConfig() Config {
+	cfg := Config{
+		Config: zk.Config{
+			Addrs:  strings.Split(beego.AppConfig.DefaultString("zk_addrs", "127.0.0.1:2181"), ","),
+			Digest: beego.AppConfig.DefaultString("zk_digest", ""),
+		},
+		Enabled:       beego.AppConfig.DefaultInt("zk_sync", 0) != 0,
+		Root:          strings.Trim(beego.AppConfig.DefaultString("zk_root", DEFAULT_ROOT), "/"),
+		DomainProject: beego.AppConfig.DefaultString("zk_domain_project", core.REGISTRY_DOMAIN_PROJECT),
+		App:           beego.AppConfig.DefaultString("zk_app", "default"),
+		Direction: strings.ToLower(
+			beego.AppConfig.DefaultString("zk_sync_direction", DIRECTION_BOTH)),
+	}
+	switch cfg.Direction {
+	case DIRECTION_TO_ZK, DIRECTION_FROM_ZK:
+	default:
+		cfg.Direction = DIRECTION_BOTH
+	}
+	if len(cfg.Root) == 0 {
+		cfg.Root = DEFAULT_ROOT
+	}
+	interval, err := time.ParseDuration(beego.AppConfig.DefaultString("zk_sync_interval", ""))
+	if err != nil || interval <= 0 {
+		interval = DEFAULT_SYNC_INTERVAL
+	}
+	cfg.Interval = interval
+	sessionTimeout, err := time.ParseDuration(beego.AppConfig.DefaultString("zk_session_timeout", ""))
+	if err == nil && sessionTimeout > 0 {
+		cfg.SessionTimeout = sessionTimeout
+	}
+	return cfg
+}